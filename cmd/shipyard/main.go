@@ -6,8 +6,11 @@ import (
 	"os"
 
 	"github.com/NatoNathan/shipyard/internal/commands"
+	"github.com/NatoNathan/shipyard/internal/config"
 	shipyarderrors "github.com/NatoNathan/shipyard/internal/errors"
+	"github.com/NatoNathan/shipyard/internal/i18n"
 	"github.com/NatoNathan/shipyard/internal/logger"
+	"github.com/NatoNathan/shipyard/internal/prompt"
 	"github.com/NatoNathan/shipyard/internal/ui"
 	"github.com/spf13/cobra"
 )
@@ -26,17 +29,45 @@ func main() {
 your fleet (packages), chart courses to new version ports, and maintain detailed
 ship's logs of your journey.`,
 		Version: version,
-		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 			// Configure logger based on flags
 			quiet, _ := cmd.Flags().GetBool("quiet")
 			verbose, _ := cmd.Flags().GetBool("verbose")
 
+			if accessible, _ := cmd.Flags().GetBool("accessible"); accessible {
+				prompt.SetAccessible(true)
+			}
+
+			if dir, _ := cmd.Flags().GetString("project-dir"); dir != "" {
+				commands.SetProjectDir(dir)
+			}
+
+			if locale, _ := cmd.Flags().GetString("locale"); locale != "" {
+				if err := i18n.SetLocale(locale); err != nil {
+					return err
+				}
+			}
+
 			log := logger.Get()
 			log.SetQuiet(quiet)
 
 			if verbose {
 				log.SetLevel(logger.LevelDebug)
 			}
+			if levelName, _ := cmd.Flags().GetString("log-level"); levelName != "" {
+				level, err := logger.ParseLevel(levelName)
+				if err != nil {
+					return err
+				}
+				log.SetLevel(level)
+			}
+			formatName, _ := cmd.Flags().GetString("log-format")
+			format, err := logger.ParseFormat(formatName)
+			if err != nil {
+				return err
+			}
+			log.SetFormat(format)
+			return nil
 		},
 		Run: func(cmd *cobra.Command, args []string) {
 			_ = cmd.Help()
@@ -51,6 +82,17 @@ ship's logs of your journey.`,
 	rootCmd.PersistentFlags().BoolP("json", "j", false, "output in JSON format")
 	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "suppress non-error output")
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().Bool("accessible", false, "use plain sequential prompts for screen readers (or set SHIPYARD_ACCESSIBLE=1)")
+	rootCmd.PersistentFlags().String("project-dir", "", "project root to work on instead of the nearest one above the working directory (or set SHIPYARD_PROJECT_DIR)")
+	rootCmd.PersistentFlags().String("locale", "", "language for messages, e.g. es (or set SHIPYARD_LOCALE)")
+	rootCmd.PersistentFlags().String("log-level", "", "log level written to stderr: debug, info, warn or error (default warn, debug with --verbose)")
+	rootCmd.PersistentFlags().String("log-format", "text", "log line format: text or json")
+	rootCmd.PersistentFlags().String("max-severity", "", "report every enabled rule at this level (warn or error)")
+
+	// Configs can require a minimum shipyard version
+	config.SetToolVersion(version)
+	// Configs on an older schema are upgraded on load with a note to migrate them
+	config.SetMigrationNotices(os.Stderr)
 
 	// Create version info for commands that need it
 	versionInfo := commands.VersionInfo{
@@ -65,16 +107,50 @@ ship's logs of your journey.`,
 	rootCmd.AddCommand(commands.NewVersionCommand())
 	rootCmd.AddCommand(commands.NewStatusCommand())
 	rootCmd.AddCommand(commands.NewReleaseNotesCommand())
+	rootCmd.AddCommand(commands.NewChangelogCommand())
+	rootCmd.AddCommand(commands.NewManifestCommand())
 	rootCmd.AddCommand(commands.NewReleaseCommand())
 	rootCmd.AddCommand(commands.NewCompletionCommand())
 	rootCmd.AddCommand(commands.NewUpgradeCommand(versionInfo))
 	rootCmd.AddCommand(commands.NewRemoveCommand())
+	rootCmd.AddCommand(commands.NewEditCommand())
 	rootCmd.AddCommand(commands.NewValidateCommand())
+	rootCmd.AddCommand(commands.NewCheckCommand())
+	rootCmd.AddCommand(commands.NewDueCommand())
+	rootCmd.AddCommand(commands.NewPreviewTemplateCommand())
 
-	configCmd := &cobra.Command{Use: "config {show}", Aliases: []string{"cfg"}, Short: "Review the ship's standing orders"}
+	configCmd := &cobra.Command{Use: "config {show|migrate}", Aliases: []string{"cfg"}, Short: "Review the ship's standing orders"}
 	configCmd.AddCommand(commands.NewConfigShowCommand())
+	configCmd.AddCommand(commands.NewConfigMigrateCommand())
 	rootCmd.AddCommand(configCmd)
 
+	consignmentCmd := &cobra.Command{Use: "consignment {squash}", Short: "Work with pending cargo"}
+	consignmentCmd.AddCommand(commands.NewConsignmentSquashCommand())
+	rootCmd.AddCommand(consignmentCmd)
+
+	importCmd := &cobra.Command{Use: "import {changesets|changelog}", Short: "Take on cargo from other manifests"}
+	importCmd.AddCommand(commands.NewImportChangesetsCommand())
+	importCmd.AddCommand(commands.NewImportChangelogCommand())
+	rootCmd.AddCommand(importCmd)
+
+	cacheCmd := &cobra.Command{Use: "cache {list|clear|refresh}", Short: "Tend the chart locker of remote templates"}
+	cacheCmd.AddCommand(commands.NewCacheListCommand())
+	cacheCmd.AddCommand(commands.NewCacheClearCommand())
+	cacheCmd.AddCommand(commands.NewCacheRefreshCommand())
+	rootCmd.AddCommand(cacheCmd)
+
+	channelCmd := &cobra.Command{Use: "channel {promote}", Short: "Work with release channels"}
+	channelCmd.AddCommand(commands.NewChannelPromoteCommand())
+	rootCmd.AddCommand(channelCmd)
+
+	historyCmd := &cobra.Command{Use: "history {show|annotate|config|compact|rename-package}", Short: "Consult the captain's log"}
+	historyCmd.AddCommand(commands.NewHistoryShowCommand())
+	historyCmd.AddCommand(commands.NewHistoryAnnotateCommand())
+	historyCmd.AddCommand(commands.NewHistoryConfigCommand())
+	historyCmd.AddCommand(commands.NewHistoryCompactCommand())
+	historyCmd.AddCommand(commands.NewHistoryRenamePackageCommand())
+	rootCmd.AddCommand(historyCmd)
+
 	if err := rootCmd.Execute(); err != nil {
 		var exitErr *shipyarderrors.ExitCodeError
 		if errors.As(err, &exitErr) {
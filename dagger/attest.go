@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"dagger/shipyard/internal/dagger"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Attestation formats and provenance identifiers
+const (
+	sbomMediaType       = "application/spdx+json"
+	provenanceMediaType = "application/vnd.in-toto+json"
+	inTotoStatementType = "https://in-toto.io/Statement/v1"
+	slsaPredicateType   = "https://slsa.dev/provenance/v1"
+	provenanceBuildType = "https://github.com/NatoNathan/shipyard/dagger/release@v1"
+	provenanceBuilderID = "https://github.com/NatoNathan/shipyard/dagger"
+	provenanceSourceURI = "git+https://github.com/NatoNathan/shipyard"
+)
+
+// assetKind distinguishes the per-platform files Package produces
+type assetKind int
+
+const (
+	archiveAsset assetKind = iota
+	sbomAsset
+)
+
+// releaseAsset is a per-platform file in the package artifacts
+type releaseAsset struct {
+	Name     string
+	Platform Platform
+	Kind     assetKind
+}
+
+// releaseAssets lists the per-platform files Package produces, in platform
+// order: each archive followed by the SBOM of its binary, unless SBOMs are skipped
+func releaseAssets(platforms []Platform, version string, withSBOM bool) []releaseAsset {
+	var assets []releaseAsset
+	for _, platform := range platforms {
+		assets = append(assets, releaseAsset{Name: archiveFilename(platform, version), Platform: platform, Kind: archiveAsset})
+		if withSBOM {
+			assets = append(assets, releaseAsset{Name: sbomFilename(platform, version), Platform: platform, Kind: sbomAsset})
+		}
+	}
+	return assets
+}
+
+// assetNames returns the sorted names of the assets
+func assetNames(assets []releaseAsset) []string {
+	names := make([]string, len(assets))
+	for i, asset := range assets {
+		names[i] = asset.Name
+	}
+	slices.Sort(names)
+	return names
+}
+
+// sbomFilename generates the SPDX SBOM filename for a platform binary
+func sbomFilename(platform Platform, version string) string {
+	return fmt.Sprintf("shipyard_%s_%s_%s.spdx.json", version, platform.OS, platform.archName())
+}
+
+// provenanceFilename generates the filename of the release provenance statement
+func provenanceFilename(version string) string {
+	return fmt.Sprintf("shipyard_%s.intoto.json", version)
+}
+
+// imageSBOMNames returns the SBOMs in a package artifacts listing that
+// describe a Docker image platform, in DockerPlatforms order
+func imageSBOMNames(entries []string, version string) []string {
+	var names []string
+	for _, platform := range DockerPlatforms {
+		if name := sbomFilename(platform, version); slices.Contains(entries, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// generateSBOM scans a platform binary with syft and returns an SPDX JSON SBOM
+func (m *Shipyard) generateSBOM(
+	buildArtifacts *dagger.Directory,
+	platform Platform,
+	version string,
+) *dagger.File {
+	binaryPath := fmt.Sprintf("/artifacts/%s/%s", platform.dirname(), platform.binaryName())
+	output := "/out/" + sbomFilename(platform, version)
+
+	return dag.Container().
+		From(SyftImage).
+		WithMountedDirectory("/artifacts", buildArtifacts).
+		WithDirectory("/out", dag.Directory()).
+		WithExec([]string{
+			"/syft", "scan", "file:" + binaryPath,
+			"--source-name", "shipyard",
+			"--source-version", version,
+			"--output", "spdx-json=" + output,
+		}).
+		File(output)
+}
+
+// provenancePredicate builds the SLSA v1 provenance predicate for a release build
+func provenancePredicate(version, commit string, startedOn time.Time) map[string]any {
+	return map[string]any{
+		"buildDefinition": map[string]any{
+			"buildType": provenanceBuildType,
+			"externalParameters": map[string]any{
+				"version": version,
+				"source":  provenanceSourceURI + "@" + commit,
+			},
+			"resolvedDependencies": []any{
+				map[string]any{
+					"uri":    provenanceSourceURI,
+					"digest": map[string]string{"gitCommit": commit},
+				},
+			},
+		},
+		"runDetails": map[string]any{
+			"builder":  map[string]any{"id": provenanceBuilderID},
+			"metadata": map[string]any{"startedOn": startedOn.UTC().Format(time.RFC3339)},
+		},
+	}
+}
+
+// provenanceSubject is a file a provenance statement vouches for
+type provenanceSubject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// provenanceStatement wraps a predicate in an in-toto statement whose subjects
+// are the given files (filename -> SHA256 hex), sorted by filename
+func provenanceStatement(predicate map[string]any, checksums map[string]string) (string, error) {
+	if len(checksums) == 0 {
+		return "", fmt.Errorf("no artifacts to attest")
+	}
+
+	subjects := make([]provenanceSubject, 0, len(checksums))
+	for name, sum := range checksums {
+		subjects = append(subjects, provenanceSubject{Name: name, Digest: map[string]string{"sha256": sum}})
+	}
+	sort.Slice(subjects, func(i, j int) bool { return subjects[i].Name < subjects[j].Name })
+
+	statement := map[string]any{
+		"_type":         inTotoStatementType,
+		"subject":       subjects,
+		"predicateType": slsaPredicateType,
+		"predicate":     predicate,
+	}
+	data, err := json.MarshalIndent(statement, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data) + "\n", nil
+}
+
+// withProvenance adds a provenance statement covering every file listed in
+// checksums.txt to the package artifacts
+func (m *Shipyard) withProvenance(
+	ctx context.Context,
+	artifacts *dagger.Directory,
+	version string,
+	commit string,
+) (*dagger.Directory, error) {
+	checksums, err := m.extractChecksums(ctx, artifacts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract checksums: %w", err)
+	}
+
+	statement, err := provenanceStatement(provenancePredicate(version, commit, time.Now()), checksums)
+	if err != nil {
+		return nil, err
+	}
+	return artifacts.WithNewFile(provenanceFilename(version), statement), nil
+}
+
+// imageAttestations collects the SBOMs of the image platforms found in the
+// package artifacts and a provenance predicate for the image
+func (m *Shipyard) imageAttestations(
+	ctx context.Context,
+	packageArtifacts *dagger.Directory,
+	version string,
+	commit string,
+) ([]attestation, error) {
+	entries, err := packageArtifacts.Entries(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list package artifacts: %w", err)
+	}
+
+	var attestations []attestation
+	for _, name := range imageSBOMNames(entries, version) {
+		attestations = append(attestations, attestation{
+			Name:      name,
+			Type:      "spdxjson",
+			MediaType: sbomMediaType,
+			Predicate: packageArtifacts.File(name),
+		})
+	}
+
+	predicate, err := json.MarshalIndent(provenancePredicate(version, commit, time.Now()), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	attestations = append(attestations, attestation{
+		Name:      "provenance.json",
+		Type:      "slsaprovenance1",
+		MediaType: provenanceMediaType,
+		Predicate: dag.Directory().WithNewFile("provenance.json", string(predicate)).File("provenance.json"),
+	})
+
+	return attestations, nil
+}
+
+// digestReference turns a published image reference (repo:tag@sha256:...)
+// into a digest reference (repo@sha256:...), so attestations bind to the
+// image content rather than a movable tag
+func digestReference(ref string) (string, error) {
+	name, digest, ok := strings.Cut(ref, "@")
+	if !ok || !strings.HasPrefix(digest, "sha256:") {
+		return "", fmt.Errorf("published reference %q has no digest", ref)
+	}
+	if colon := strings.LastIndex(name, ":"); colon > strings.LastIndex(name, "/") {
+		name = name[:colon]
+	}
+	return name + "@" + digest, nil
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReleaseAssets(t *testing.T) {
+	linux := Platform{OS: "linux", Arch: "amd64"}
+	windows := Platform{OS: "windows", Arch: "amd64"}
+
+	t.Run("archive then SBOM per platform", func(t *testing.T) {
+		got := releaseAssets([]Platform{linux, windows}, "v1.2.3", true)
+		want := []releaseAsset{
+			{Name: "shipyard_v1.2.3_linux_amd64.tar.gz", Platform: linux, Kind: archiveAsset},
+			{Name: "shipyard_v1.2.3_linux_amd64.spdx.json", Platform: linux, Kind: sbomAsset},
+			{Name: "shipyard_v1.2.3_windows_amd64.zip", Platform: windows, Kind: archiveAsset},
+			{Name: "shipyard_v1.2.3_windows_amd64.spdx.json", Platform: windows, Kind: sbomAsset},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("releaseAssets() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("SBOMs skipped", func(t *testing.T) {
+		got := releaseAssets([]Platform{linux, windows}, "v1.2.3", false)
+		for _, asset := range got {
+			if asset.Kind != archiveAsset {
+				t.Errorf("releaseAssets() included %s with SBOMs skipped", asset.Name)
+			}
+		}
+		if len(got) != 2 {
+			t.Errorf("releaseAssets() = %v, want 2 archives", got)
+		}
+	})
+
+	t.Run("every supported binary gets an SBOM", func(t *testing.T) {
+		names := assetNames(releaseAssets(SupportedPlatforms, "v1.2.3", true))
+		if len(names) != 2*len(SupportedPlatforms) {
+			t.Fatalf("assetNames() = %v, want %d assets", names, 2*len(SupportedPlatforms))
+		}
+		for _, platform := range SupportedPlatforms {
+			sbom := sbomFilename(platform, "v1.2.3")
+			found := false
+			for _, name := range names {
+				found = found || name == sbom
+			}
+			if !found {
+				t.Errorf("assets are missing %s", sbom)
+			}
+		}
+		for i := 1; i < len(names); i++ {
+			if names[i-1] > names[i] {
+				t.Errorf("assetNames() not sorted: %v", names)
+			}
+		}
+	})
+}
+
+func TestImageSBOMNames(t *testing.T) {
+	entries := []string{
+		"checksums.txt",
+		"shipyard_v1.2.3_linux_arm64.spdx.json",
+		"shipyard_v1.2.3_darwin_arm64.spdx.json",
+		"shipyard_v1.2.3_linux_amd64.spdx.json",
+		"shipyard_v1.2.3_linux_amd64.tar.gz",
+	}
+
+	got := imageSBOMNames(entries, "v1.2.3")
+	want := []string{"shipyard_v1.2.3_linux_amd64.spdx.json", "shipyard_v1.2.3_linux_arm64.spdx.json"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("imageSBOMNames() = %v, want %v", got, want)
+	}
+
+	if got := imageSBOMNames([]string{"shipyard_v1.2.3_linux_amd64.tar.gz"}, "v1.2.3"); len(got) != 0 {
+		t.Errorf("imageSBOMNames() without SBOMs = %v, want none", got)
+	}
+}
+
+func TestProvenanceStatement(t *testing.T) {
+	startedOn := time.Date(2026, 1, 30, 12, 0, 0, 0, time.UTC)
+	checksums := map[string]string{
+		"shipyard_v1.2.3_linux_amd64.tar.gz":    strings.Repeat("b", 64),
+		"shipyard_v1.2.3_linux_amd64.spdx.json": strings.Repeat("a", 64),
+	}
+
+	content, err := provenanceStatement(provenancePredicate("v1.2.3", "abc123", startedOn), checksums)
+	if err != nil {
+		t.Fatalf("provenanceStatement() unexpected error: %v", err)
+	}
+
+	var statement struct {
+		Type          string              `json:"_type"`
+		Subject       []provenanceSubject `json:"subject"`
+		PredicateType string              `json:"predicateType"`
+		Predicate     struct {
+			BuildDefinition struct {
+				ExternalParameters   map[string]string `json:"externalParameters"`
+				ResolvedDependencies []struct {
+					Digest map[string]string `json:"digest"`
+				} `json:"resolvedDependencies"`
+			} `json:"buildDefinition"`
+			RunDetails struct {
+				Metadata map[string]string `json:"metadata"`
+			} `json:"runDetails"`
+		} `json:"predicate"`
+	}
+	if err := json.Unmarshal([]byte(content), &statement); err != nil {
+		t.Fatalf("provenance is not valid JSON: %v\n%s", err, content)
+	}
+
+	if statement.Type != inTotoStatementType || statement.PredicateType != slsaPredicateType {
+		t.Errorf("statement types = %q, %q", statement.Type, statement.PredicateType)
+	}
+	wantSubjects := []provenanceSubject{
+		{Name: "shipyard_v1.2.3_linux_amd64.spdx.json", Digest: map[string]string{"sha256": strings.Repeat("a", 64)}},
+		{Name: "shipyard_v1.2.3_linux_amd64.tar.gz", Digest: map[string]string{"sha256": strings.Repeat("b", 64)}},
+	}
+	if !reflect.DeepEqual(statement.Subject, wantSubjects) {
+		t.Errorf("subjects = %v, want %v", statement.Subject, wantSubjects)
+	}
+
+	definition := statement.Predicate.BuildDefinition
+	if definition.ExternalParameters["version"] != "v1.2.3" {
+		t.Errorf("version parameter = %q", definition.ExternalParameters["version"])
+	}
+	if len(definition.ResolvedDependencies) != 1 || definition.ResolvedDependencies[0].Digest["gitCommit"] != "abc123" {
+		t.Errorf("resolved dependencies = %+v, want the source commit", definition.ResolvedDependencies)
+	}
+	if got := statement.Predicate.RunDetails.Metadata["startedOn"]; got != "2026-01-30T12:00:00Z" {
+		t.Errorf("startedOn = %q", got)
+	}
+
+	if _, err := provenanceStatement(provenancePredicate("v1.2.3", "abc123", startedOn), nil); err == nil {
+		t.Error("provenanceStatement() expected error without artifacts")
+	}
+}
+
+func TestDigestReference(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("c", 64)
+	tests := []struct {
+		ref     string
+		want    string
+		wantErr bool
+	}{
+		{ref: "ghcr.io/natonathan/shipyard:v1.2.3@" + digest, want: "ghcr.io/natonathan/shipyard@" + digest},
+		{ref: "registry:5000/shipyard:latest@" + digest, want: "registry:5000/shipyard@" + digest},
+		{ref: "registry:5000/shipyard@" + digest, want: "registry:5000/shipyard@" + digest},
+		{ref: "ghcr.io/natonathan/shipyard:v1.2.3", wantErr: true},
+		{ref: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := digestReference(tt.ref)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("digestReference(%q) expected error", tt.ref)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("digestReference(%q) unexpected error: %v", tt.ref, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("digestReference(%q) = %q, want %q", tt.ref, got, tt.want)
+		}
+	}
+}
@@ -4,10 +4,11 @@ import (
 	"context"
 	"dagger/shipyard/internal/dagger"
 	"fmt"
+	"strings"
 	"time"
 )
 
-// Build compiles the Shipyard binary for all supported platforms
+// Build compiles the Shipyard binary for the selected platforms
 func (m *Shipyard) Build(
 	ctx context.Context,
 	// Source code directory
@@ -16,7 +17,15 @@ func (m *Shipyard) Build(
 	version string,
 	// Git commit SHA
 	commit string,
-) *dagger.Directory {
+	// Platforms to build in os/arch[/variant] form (e.g., "linux/amd64", "linux/arm/v7"); defaults to all supported platforms
+	// +optional
+	platforms []string,
+) (*dagger.Directory, error) {
+	selected, err := selectPlatforms(platforms)
+	if err != nil {
+		return nil, err
+	}
+
 	buildInfo := BuildInfo{
 		Version: version,
 		Commit:  commit,
@@ -27,20 +36,68 @@ func (m *Shipyard) Build(
 	output := dag.Directory()
 
 	// Build for each platform (Dagger parallelizes automatically)
-	for _, platform := range SupportedPlatforms {
+	for _, platform := range selected {
 		binary := m.buildPlatform(ctx, source, platform, buildInfo)
 
 		// Place binary in platform-specific subdirectory
-		dirname := fmt.Sprintf("%s_%s", platform.OS, platform.Arch)
-		filename := "shipyard"
-		if platform.OS == "windows" {
-			filename += ".exe"
-		}
+		output = output.WithFile(fmt.Sprintf("%s/%s", platform.dirname(), platform.binaryName()), binary)
+	}
+
+	return output, nil
+}
+
+// BuildSingle compiles the Shipyard binary for a single platform
+func (m *Shipyard) BuildSingle(
+	ctx context.Context,
+	// Source code directory
+	source *dagger.Directory,
+	// Platform in os/arch[/variant] form (e.g., "linux/amd64", "linux/arm/v7")
+	platform string,
+	// Version string (e.g., "v1.2.3")
+	version string,
+	// Git commit SHA
+	// +default="dev"
+	commit string,
+) (*dagger.File, error) {
+	target, err := parsePlatform(platform)
+	if err != nil {
+		return nil, err
+	}
+
+	buildInfo := BuildInfo{
+		Version: version,
+		Commit:  commit,
+		Date:    time.Now().Format(time.RFC3339),
+	}
+
+	return m.buildPlatform(ctx, source, target, buildInfo), nil
+}
 
-		output = output.WithFile(fmt.Sprintf("%s/%s", dirname, filename), binary)
+// BinaryForHost compiles the Shipyard binary for the host running the Dagger engine.
+// On macOS the engine runs in a Linux VM, so pass --platform darwin/arm64 (or darwin/amd64)
+// to get a binary that runs outside the engine.
+func (m *Shipyard) BinaryForHost(
+	ctx context.Context,
+	// Source code directory
+	source *dagger.Directory,
+	// Version string (e.g., "v1.2.3")
+	// +default="dev"
+	version string,
+	// Platform override in os/arch form; defaults to the engine platform
+	// +optional
+	platform string,
+) (*dagger.File, error) {
+	if platform == "" {
+		enginePlatform, err := dag.DefaultPlatform(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to detect host platform: %w", err)
+		}
+		// Drop any variant suffix (e.g., "linux/arm64/v8")
+		parts := strings.SplitN(string(enginePlatform), "/", 3)
+		platform = strings.Join(parts[:min(len(parts), 2)], "/")
 	}
 
-	return output
+	return m.BuildSingle(ctx, source, platform, version, "dev")
 }
 
 // buildPlatform compiles a single platform binary
@@ -50,15 +107,23 @@ func (m *Shipyard) buildPlatform(
 	platform Platform,
 	buildInfo BuildInfo,
 ) *dagger.File {
+	// Cache volumes are keyed per platform so concurrent cross-compiles don't contend
+	cacheKey := fmt.Sprintf("%s-%s", platform.OS, platform.archName())
+
 	// Use Go 1.25 alpine image for building
 	builder := dag.Container().
 		From(GoImage).
+		WithMountedCache("/go/pkg/mod", dag.CacheVolume("go-mod-"+cacheKey)).
+		WithMountedCache("/root/.cache/go-build", dag.CacheVolume("go-build-"+cacheKey)).
 		WithMountedDirectory("/src", source).
 		WithWorkdir("/src").
 		// Set build environment
 		WithEnvVariable("GOOS", platform.OS).
 		WithEnvVariable("GOARCH", platform.Arch).
 		WithEnvVariable("CGO_ENABLED", "0")
+	if goarm := platform.goarm(); goarm != "" {
+		builder = builder.WithEnvVariable("GOARM", goarm)
+	}
 
 	// Build ldflags for version info
 	ldflags := fmt.Sprintf(
@@ -69,10 +134,7 @@ func (m *Shipyard) buildPlatform(
 	)
 
 	// Output binary name
-	outputName := "shipyard"
-	if platform.OS == "windows" {
-		outputName += ".exe"
-	}
+	outputName := platform.binaryName()
 
 	// Execute build
 	builder = builder.WithExec([]string{
@@ -92,6 +154,9 @@ func (m *Shipyard) BuildOnly(
 	source *dagger.Directory,
 	// Version string (e.g., "v1.2.3")
 	version string,
-) *dagger.Directory {
-	return m.Build(ctx, source, version, "dev")
+	// Platforms to build in os/arch form; defaults to all supported platforms
+	// +optional
+	platforms []string,
+) (*dagger.Directory, error) {
+	return m.Build(ctx, source, version, "dev", platforms)
 }
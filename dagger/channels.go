@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Distribution channels a release publishes to
+const (
+	ChannelGitHub   = "github"
+	ChannelHomebrew = "homebrew"
+	ChannelNPM      = "npm"
+	ChannelDocker   = "docker"
+)
+
+// ReleaseChannels lists every distribution channel in publishing order
+var ReleaseChannels = []string{ChannelGitHub, ChannelHomebrew, ChannelNPM, ChannelDocker}
+
+// Outcomes of publishing to a channel
+const (
+	StatusPublished = "published"
+	StatusSkipped   = "skipped"
+	StatusFailed    = "failed"
+)
+
+// parseChannels parses a comma-separated channel list. An empty list or
+// "all" selects every channel; explicit reports whether channels were named.
+// The result follows the order of ReleaseChannels and contains no duplicates.
+func parseChannels(list string) (channels []string, explicit bool, err error) {
+	list = strings.TrimSpace(list)
+	if list == "" || strings.EqualFold(list, "all") {
+		return append([]string{}, ReleaseChannels...), false, nil
+	}
+
+	selected := make(map[string]bool)
+	for _, name := range strings.Split(list, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if !isReleaseChannel(name) {
+			return nil, false, fmt.Errorf("unknown channel %q (supported: %s)", name, strings.Join(ReleaseChannels, ", "))
+		}
+		selected[name] = true
+	}
+	if len(selected) == 0 {
+		return nil, false, fmt.Errorf("no channels in %q", list)
+	}
+
+	for _, channel := range ReleaseChannels {
+		if selected[channel] {
+			channels = append(channels, channel)
+		}
+	}
+	return channels, true, nil
+}
+
+func isReleaseChannel(name string) bool {
+	for _, channel := range ReleaseChannels {
+		if channel == name {
+			return true
+		}
+	}
+	return false
+}
+
+// planChannels decides why each channel is skipped, keyed by channel; the
+// channels missing from the result are published. missingCredentials names
+// the credentials each channel lacks, if any. A channel that was not
+// requested is skipped, as is one requested only by default whose
+// credentials are missing. A channel named explicitly without its
+// credentials is an error, so a release never silently drops a channel it
+// was asked for.
+func planChannels(list string, missingCredentials map[string]string) (map[string]string, error) {
+	channels, explicit, err := parseChannels(list)
+	if err != nil {
+		return nil, err
+	}
+	requested := make(map[string]bool, len(channels))
+	for _, channel := range channels {
+		requested[channel] = true
+	}
+
+	skipped := make(map[string]string)
+	for _, channel := range ReleaseChannels {
+		missing := missingCredentials[channel]
+		switch {
+		case !requested[channel]:
+			skipped[channel] = "not requested"
+		case missing != "" && explicit:
+			return nil, fmt.Errorf("channel %s was requested but %s is not set", channel, missing)
+		case missing != "":
+			skipped[channel] = "no " + missing
+		}
+	}
+	if len(skipped) == len(ReleaseChannels) {
+		return nil, fmt.Errorf("no channel can be published; pass the credentials of at least one channel")
+	}
+	return skipped, nil
+}
+
+// ChannelResult is the outcome of publishing to one distribution channel
+type ChannelResult struct {
+	// Channel name: github, homebrew, npm or docker
+	Channel string
+	// published, skipped or failed
+	Status string
+	// Why the channel was skipped, or the error it failed with
+	Detail string
+}
+
+// ReleaseSummary reports what a release did on each distribution channel
+type ReleaseSummary struct {
+	Version string
+	// One result per channel, in publishing order
+	Channels []ChannelResult
+}
+
+// newReleaseSummary assembles the summary of a release from the channels
+// it skipped and the errors of the channels it published
+func newReleaseSummary(version string, skipped map[string]string, errs map[string]error) *ReleaseSummary {
+	summary := &ReleaseSummary{Version: version}
+	for _, channel := range ReleaseChannels {
+		result := ChannelResult{Channel: channel, Status: StatusPublished}
+		if reason, ok := skipped[channel]; ok {
+			result.Status, result.Detail = StatusSkipped, reason
+		} else if err := errs[channel]; err != nil {
+			result.Status, result.Detail = StatusFailed, err.Error()
+		}
+		summary.Channels = append(summary.Channels, result)
+	}
+	return summary
+}
+
+// Failed returns the channels that failed to publish
+func (s *ReleaseSummary) Failed() []string {
+	var failed []string
+	for _, result := range s.Channels {
+		if result.Status == StatusFailed {
+			failed = append(failed, result.Channel)
+		}
+	}
+	return failed
+}
+
+// String renders one line per channel for CI logs
+func (s *ReleaseSummary) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Release %s:\n", s.Version)
+	for _, result := range s.Channels {
+		fmt.Fprintf(&b, "  %-9s %s", result.Channel, result.Status)
+		if result.Detail != "" {
+			fmt.Fprintf(&b, " (%s)", result.Detail)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// err returns an error naming every failed channel with the full summary,
+// or nil when nothing failed
+func (s *ReleaseSummary) err() error {
+	failed := s.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+	return fmt.Errorf("release failed on %s\n%s", strings.Join(failed, ", "), s)
+}
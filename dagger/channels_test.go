@@ -0,0 +1,136 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseChannels(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		want         []string
+		wantExplicit bool
+		wantErr      string
+	}{
+		{name: "empty selects all", input: "", want: ReleaseChannels},
+		{name: "all selects all", input: "ALL", want: ReleaseChannels},
+		{name: "subset keeps publishing order", input: "docker,github", want: []string{"github", "docker"}, wantExplicit: true},
+		{name: "spaces, case and duplicates", input: " Docker , docker,,npm ", want: []string{"npm", "docker"}, wantExplicit: true},
+		{name: "unknown channel", input: "github,snap", wantErr: `unknown channel "snap" (supported: github, homebrew, npm, docker)`},
+		{name: "only separators", input: ",,", wantErr: "no channels"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			channels, explicit, err := parseChannels(tt.input)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseChannels(%q) error = %v, want %q", tt.input, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseChannels(%q) unexpected error: %v", tt.input, err)
+			}
+			if !reflect.DeepEqual(channels, tt.want) || explicit != tt.wantExplicit {
+				t.Errorf("parseChannels(%q) = %v, %v, want %v, %v", tt.input, channels, explicit, tt.want, tt.wantExplicit)
+			}
+		})
+	}
+}
+
+func TestPlanChannels(t *testing.T) {
+	noNPM := map[string]string{ChannelNPM: "npmOidcTokenUrl"}
+
+	tests := []struct {
+		name    string
+		list    string
+		missing map[string]string
+		want    map[string]string
+		wantErr string
+	}{
+		{name: "everything with credentials", want: map[string]string{}},
+		{name: "default skips channels without credentials", missing: noNPM, want: map[string]string{ChannelNPM: "no npmOidcTokenUrl"}},
+		{
+			name:    "fork release to GitHub and Docker",
+			list:    "github,docker",
+			missing: noNPM,
+			want:    map[string]string{ChannelHomebrew: "not requested", ChannelNPM: "not requested"},
+		},
+		{name: "requested channel without credentials", list: "github,npm", missing: noNPM, wantErr: "channel npm was requested but npmOidcTokenUrl is not set"},
+		{
+			name: "nothing publishable",
+			missing: map[string]string{
+				ChannelGitHub: "githubToken", ChannelHomebrew: "githubToken",
+				ChannelNPM: "npmOidcTokenUrl", ChannelDocker: "dockerToken or githubToken",
+			},
+			wantErr: "no channel can be published",
+		},
+		{name: "invalid list", list: "pypi", wantErr: `unknown channel "pypi"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			skipped, err := planChannels(tt.list, tt.missing)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("planChannels(%q) error = %v, want %q", tt.list, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("planChannels(%q) unexpected error: %v", tt.list, err)
+			}
+			if !reflect.DeepEqual(skipped, tt.want) {
+				t.Errorf("planChannels(%q) = %v, want %v", tt.list, skipped, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewReleaseSummary(t *testing.T) {
+	summary := newReleaseSummary("v1.2.3",
+		map[string]string{ChannelNPM: "not requested"},
+		map[string]error{ChannelDocker: errors.New("push denied")},
+	)
+
+	want := []ChannelResult{
+		{Channel: ChannelGitHub, Status: StatusPublished},
+		{Channel: ChannelHomebrew, Status: StatusPublished},
+		{Channel: ChannelNPM, Status: StatusSkipped, Detail: "not requested"},
+		{Channel: ChannelDocker, Status: StatusFailed, Detail: "push denied"},
+	}
+	if !reflect.DeepEqual(summary.Channels, want) {
+		t.Fatalf("summary channels = %+v, want %+v", summary.Channels, want)
+	}
+	if got := summary.Failed(); !reflect.DeepEqual(got, []string{ChannelDocker}) {
+		t.Errorf("Failed() = %v, want [docker]", got)
+	}
+
+	wantText := "Release v1.2.3:\n" +
+		"  github    published\n" +
+		"  homebrew  published\n" +
+		"  npm       skipped (not requested)\n" +
+		"  docker    failed (push denied)\n"
+	if summary.String() != wantText {
+		t.Errorf("String() =\n%s\nwant\n%s", summary.String(), wantText)
+	}
+
+	err := summary.err()
+	if err == nil || !strings.HasPrefix(err.Error(), "release failed on docker\n") || !strings.Contains(err.Error(), "npm       skipped") {
+		t.Errorf("err() = %v, want the failed channels and the summary", err)
+	}
+}
+
+func TestNewReleaseSummary_AllPublished(t *testing.T) {
+	summary := newReleaseSummary("v1.2.3", nil, nil)
+	if err := summary.err(); err != nil {
+		t.Errorf("err() = %v, want nil", err)
+	}
+	if len(summary.Failed()) != 0 {
+		t.Errorf("Failed() = %v, want none", summary.Failed())
+	}
+}
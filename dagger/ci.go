@@ -176,7 +176,9 @@ func (m *Shipyard) CI(
 	fmt.Println(securityOutput)
 
 	fmt.Println("🔨 Building...")
-	_ = m.BuildOnly(ctx, source, "dev")
+	if _, err := m.BuildOnly(ctx, source, "dev", nil); err != nil {
+		return fmt.Errorf("build stage failed: %w", err)
+	}
 
 	fmt.Println("✅ CI passed!")
 	return nil
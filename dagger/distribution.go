@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"strings"
+	"text/template"
+)
+
+// distributionTemplateFS holds the files published to package managers
+// alongside the binaries, rendered for each release
+//
+//go:embed templates
+var distributionTemplateFS embed.FS
+
+// distributionTemplateDir is the directory of the embedded templates
+const distributionTemplateDir = "templates"
+
+// Distribution templates, by their path under templates/. A fork replaces
+// any of them by putting a file at the same path in the Templates directory.
+const (
+	formulaTemplate        = "homebrew/shipyard.rb.tmpl"
+	npmPackageJSONTemplate = "npm/package.json.tmpl"
+	npmInstallTemplate     = "npm/install.js.tmpl"
+	npmReadmeTemplate      = "npm/README.md.tmpl"
+	npmBinWrapperTemplate  = "npm/bin/shipyard.tmpl"
+)
+
+// distributionMetadata describes shipyard the same way on every channel
+type distributionMetadata struct {
+	Name        string   // Display name
+	Command     string   // Binary name, also the archive prefix
+	Package     string   // npm package name
+	Description string   // One line, without a trailing period
+	Repository  string   // GitHub owner/name the release archives are downloaded from
+	Homepage    string   // Project URL
+	Author      string   // npm author
+	License     string   // SPDX identifier
+	Keywords    []string // npm keywords
+}
+
+// shipyardMetadata is the single source of the description, links and
+// names the Homebrew formula and npm package publish
+var shipyardMetadata = distributionMetadata{
+	Name:        "Shipyard",
+	Command:     "shipyard",
+	Package:     "shipyard-cli",
+	Description: "Semantic versioning and release management for monorepos and single-package repositories",
+	Repository:  "NatoNathan/shipyard",
+	Homepage:    "https://github.com/NatoNathan/shipyard",
+	Author:      "NatoNathan",
+	License:     "MIT",
+	Keywords:    []string{"shipyard", "cli", "semver", "versioning", "changelog", "release", "monorepo"},
+}
+
+// downloadURL returns the URL the archives of a release are downloaded from
+func (d distributionMetadata) downloadURL(version string) string {
+	return fmt.Sprintf("https://github.com/%s/releases/download/%s", d.Repository, version)
+}
+
+// formulaData is what the Homebrew formula template renders
+type formulaData struct {
+	distributionMetadata
+	Version  string // Without the leading v
+	Archives []formulaArchive
+}
+
+// formulaArchive is the archive the formula installs on one platform
+type formulaArchive struct {
+	OS     string // Homebrew's OS check: mac or linux
+	CPU    string // Homebrew's CPU check: intel or arm
+	URL    string
+	SHA256 string
+}
+
+// npmData is what the npm package templates render
+type npmData struct {
+	distributionMetadata
+	Version      string                       // Without the leading v
+	DownloadURL  string                       // Release URL the archives are under
+	Archives     map[string]map[string]string // Node.js platform -> arch -> archive filename
+	ReleaseNotes string                       // Markdown notes, headings demoted below the README's; empty without notes
+}
+
+// newFormulaData describes the archives the formula installs. Homebrew only
+// runs on 64-bit macOS and Linux, so the armv7 and Windows archives are left
+// out. Every archive needs a checksum.
+func newFormulaData(meta distributionMetadata, version string, checksums map[string]string) (formulaData, error) {
+	data := formulaData{distributionMetadata: meta, Version: strings.TrimPrefix(version, "v")}
+	homebrewOS := map[string]string{"darwin": "mac", "linux": "linux"}
+	homebrewCPU := map[string]string{"amd64": "intel", "arm64": "arm"}
+	for _, platform := range SupportedPlatforms {
+		brewOS, cpu := homebrewOS[platform.OS], homebrewCPU[platform.archName()]
+		if brewOS == "" || cpu == "" {
+			continue
+		}
+		name := archiveFilename(platform, version)
+		sum, ok := checksums[name]
+		if !ok {
+			return formulaData{}, fmt.Errorf("no checksum for %s", name)
+		}
+		data.Archives = append(data.Archives, formulaArchive{
+			OS:     brewOS,
+			CPU:    cpu,
+			URL:    meta.downloadURL(version) + "/" + name,
+			SHA256: sum,
+		})
+	}
+	return data, nil
+}
+
+// newNPMData describes the npm package of a release, with the release notes
+// for its README
+func newNPMData(meta distributionMetadata, version string, notes string) npmData {
+	nodePlatform := map[string]string{"darwin": "darwin", "linux": "linux", "windows": "win32"}
+	nodeArch := map[string]string{"amd64": "x64", "arm64": "arm64", "armv7": "arm"}
+	archives := make(map[string]map[string]string)
+	for _, platform := range SupportedPlatforms {
+		nodeOS, arch := nodePlatform[platform.OS], nodeArch[platform.archName()]
+		if nodeOS == "" || arch == "" {
+			continue
+		}
+		if archives[nodeOS] == nil {
+			archives[nodeOS] = make(map[string]string)
+		}
+		archives[nodeOS][arch] = archiveFilename(platform, version)
+	}
+
+	data := npmData{
+		distributionMetadata: meta,
+		Version:              strings.TrimPrefix(version, "v"),
+		DownloadURL:          meta.downloadURL(version),
+		Archives:             archives,
+	}
+	if notes = strings.TrimSpace(notes); notes != "" {
+		data.ReleaseNotes = demoteHeadings(notes, 2)
+	}
+	return data
+}
+
+// distributionTemplates renders the distribution templates, preferring the
+// overrides a fork supplies to the embedded ones
+type distributionTemplates struct {
+	overrides map[string]string // Template path -> contents replacing the embedded template
+}
+
+// newDistributionTemplates checks every override replaces an embedded
+// template, so a misplaced file is an error rather than silently ignored
+func newDistributionTemplates(overrides map[string]string) (distributionTemplates, error) {
+	for name := range overrides {
+		if _, err := fs.Stat(distributionTemplateFS, distributionTemplateDir+"/"+name); err != nil {
+			return distributionTemplates{}, fmt.Errorf("template override %s does not replace a distribution template", name)
+		}
+	}
+	return distributionTemplates{overrides: overrides}, nil
+}
+
+// render executes the template at name with data
+func (t distributionTemplates) render(name string, data any) (string, error) {
+	source, ok := t.overrides[name]
+	if !ok {
+		embedded, err := distributionTemplateFS.ReadFile(distributionTemplateDir + "/" + name)
+		if err != nil {
+			return "", fmt.Errorf("unknown distribution template %s: %w", name, err)
+		}
+		source = string(embedded)
+	}
+
+	tmpl, err := template.New(name).Option("missingkey=error").Funcs(template.FuncMap{
+		"json": func(v any) (string, error) {
+			encoded, err := json.Marshal(v)
+			return string(encoded), err
+		},
+	}).Parse(source)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template %s: %w", name, err)
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("failed to render template %s: %w", name, err)
+	}
+	return out.String(), nil
+}
+
+// renderFormula renders the Homebrew formula of a release
+func (t distributionTemplates) renderFormula(version string, checksums map[string]string) (string, error) {
+	data, err := newFormulaData(shipyardMetadata, version, checksums)
+	if err != nil {
+		return "", err
+	}
+	return t.render(formulaTemplate, data)
+}
+
+// renderNPMPackage renders the files of the npm wrapper package of a
+// release, by their path in the package
+func (t distributionTemplates) renderNPMPackage(version string, notes string) (map[string]string, error) {
+	data := newNPMData(shipyardMetadata, version, notes)
+	files := make(map[string]string)
+	for path, name := range map[string]string{
+		"package.json":        npmPackageJSONTemplate,
+		"install.js":          npmInstallTemplate,
+		"README.md":           npmReadmeTemplate,
+		"bin/" + data.Command: npmBinWrapperTemplate,
+	} {
+		rendered, err := t.render(name, data)
+		if err != nil {
+			return nil, err
+		}
+		files[path] = rendered
+	}
+	return files, nil
+}
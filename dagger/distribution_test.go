@@ -0,0 +1,212 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// fixtureChecksums returns a checksum for every release archive of version
+func fixtureChecksums(version string) map[string]string {
+	checksums := make(map[string]string)
+	for i, platform := range SupportedPlatforms {
+		checksums[archiveFilename(platform, version)] = strings.Repeat(string(rune('a'+i)), 64)
+	}
+	return checksums
+}
+
+func TestRenderFormula(t *testing.T) {
+	checksums := fixtureChecksums("v1.2.3")
+	formula, err := distributionTemplates{}.renderFormula("v1.2.3", checksums)
+	if err != nil {
+		t.Fatalf("renderFormula: %v", err)
+	}
+
+	for _, want := range []string{
+		`class Shipyard < Formula`,
+		`version "1.2.3"`,
+		`desc "` + shipyardMetadata.Description + `"`,
+		`assert_match "shipyard version 1.2.3", shell_output("#{bin}/shipyard --version")`,
+	} {
+		if !strings.Contains(formula, want) {
+			t.Errorf("formula is missing %q:\n%s", want, formula)
+		}
+	}
+	for _, platform := range SupportedPlatforms {
+		name := archiveFilename(platform, "v1.2.3")
+		homebrew := platform.OS != "windows" && platform.Arch != "arm"
+		if got := strings.Contains(formula, checksums[name]); got != homebrew {
+			t.Errorf("formula has checksum for %s = %v, want %v", name, got, homebrew)
+		}
+		url := "https://github.com/NatoNathan/shipyard/releases/download/v1.2.3/" + name
+		if got := strings.Contains(formula, url); got != homebrew {
+			t.Errorf("formula has URL for %s = %v, want %v", name, got, homebrew)
+		}
+	}
+	checkRubyBlocks(t, formula)
+}
+
+func TestRenderFormula_MissingChecksum(t *testing.T) {
+	checksums := fixtureChecksums("v1.2.3")
+	delete(checksums, "shipyard_v1.2.3_darwin_arm64.tar.gz")
+
+	_, err := distributionTemplates{}.renderFormula("v1.2.3", checksums)
+	if err == nil || !strings.Contains(err.Error(), "no checksum for shipyard_v1.2.3_darwin_arm64.tar.gz") {
+		t.Fatalf("renderFormula error = %v, want missing checksum", err)
+	}
+}
+
+// checkRubyBlocks checks every block the formula opens is closed, and runs
+// ruby's syntax check when ruby is installed
+func checkRubyBlocks(t *testing.T, formula string) {
+	t.Helper()
+	opener := regexp.MustCompile(`^\s*(class|def|if)\b|\bdo\s*$`)
+	depth := 0
+	for _, line := range strings.Split(formula, "\n") {
+		switch {
+		case opener.MatchString(line):
+			depth++
+		case strings.TrimSpace(line) == "end":
+			depth--
+		}
+		if depth < 0 {
+			t.Fatalf("unbalanced end in formula:\n%s", formula)
+		}
+	}
+	if depth != 0 {
+		t.Fatalf("formula leaves %d block(s) open:\n%s", depth, formula)
+	}
+
+	ruby, err := exec.LookPath("ruby")
+	if err != nil {
+		return
+	}
+	path := filepath.Join(t.TempDir(), "shipyard.rb")
+	if err := os.WriteFile(path, []byte(formula), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if output, err := exec.Command(ruby, "-c", path).CombinedOutput(); err != nil {
+		t.Fatalf("ruby -c: %v\n%s", err, output)
+	}
+}
+
+func TestRenderNPMPackage(t *testing.T) {
+	files, err := distributionTemplates{}.renderNPMPackage("v1.2.3", "## Features\n\n- Add dark mode\n")
+	if err != nil {
+		t.Fatalf("renderNPMPackage: %v", err)
+	}
+
+	var pkg struct {
+		Name        string            `json:"name"`
+		Version     string            `json:"version"`
+		Description string            `json:"description"`
+		Bin         map[string]string `json:"bin"`
+		Keywords    []string          `json:"keywords"`
+		License     string            `json:"license"`
+		Repository  struct {
+			URL string `json:"url"`
+		} `json:"repository"`
+	}
+	if err := json.Unmarshal([]byte(files["package.json"]), &pkg); err != nil {
+		t.Fatalf("package.json is not valid JSON: %v\n%s", err, files["package.json"])
+	}
+	if pkg.Name != "shipyard-cli" || pkg.Version != "1.2.3" || pkg.License != "MIT" {
+		t.Errorf("package.json = %+v", pkg)
+	}
+	if pkg.Description != shipyardMetadata.Description {
+		t.Errorf("description = %q, want %q", pkg.Description, shipyardMetadata.Description)
+	}
+	if pkg.Bin["shipyard"] != "bin/shipyard" {
+		t.Errorf("bin = %v", pkg.Bin)
+	}
+	if pkg.Repository.URL != "https://github.com/NatoNathan/shipyard.git" {
+		t.Errorf("repository url = %q", pkg.Repository.URL)
+	}
+	if _, ok := files["bin/shipyard"]; !ok {
+		t.Errorf("package has no bin wrapper: %v", files)
+	}
+
+	install := files["install.js"]
+	if !strings.Contains(install, `const downloadURL = "https://github.com/NatoNathan/shipyard/releases/download/v1.2.3";`) {
+		t.Errorf("install.js does not download from the release:\n%s", install)
+	}
+	archives := regexp.MustCompile(`(?m)^const archives = (.*);$`).FindStringSubmatch(install)
+	if archives == nil {
+		t.Fatalf("install.js has no archive map:\n%s", install)
+	}
+	var byPlatform map[string]map[string]string
+	if err := json.Unmarshal([]byte(archives[1]), &byPlatform); err != nil {
+		t.Fatalf("archive map is not valid JSON: %v", err)
+	}
+	for platform, want := range map[[2]string]string{
+		{"darwin", "arm64"}: "shipyard_v1.2.3_darwin_arm64.tar.gz",
+		{"linux", "arm"}:    "shipyard_v1.2.3_linux_armv7.tar.gz",
+		{"win32", "x64"}:    "shipyard_v1.2.3_windows_amd64.zip",
+	} {
+		if got := byPlatform[platform[0]][platform[1]]; got != want {
+			t.Errorf("archive for %s/%s = %q, want %q", platform[0], platform[1], got, want)
+		}
+	}
+
+	readme := files["README.md"]
+	for _, want := range []string{"# shipyard-cli", "Version: 1.2.3", "```bash\nnpm install -g shipyard-cli\n```", "## Release Notes\n\n#### Features\n\n- Add dark mode\n\n## Links"} {
+		if !strings.Contains(readme, want) {
+			t.Errorf("README is missing %q:\n%s", want, readme)
+		}
+	}
+}
+
+func TestRenderNPMPackage_WithoutNotes(t *testing.T) {
+	files, err := distributionTemplates{}.renderNPMPackage("v1.2.3", " \n")
+	if err != nil {
+		t.Fatalf("renderNPMPackage: %v", err)
+	}
+	if strings.Contains(files["README.md"], "Release Notes") {
+		t.Errorf("README has a release notes section without notes:\n%s", files["README.md"])
+	}
+	if !strings.Contains(files["README.md"], "```\n\n## Links") {
+		t.Errorf("README usage is not followed by the links:\n%s", files["README.md"])
+	}
+}
+
+func TestDistributionTemplates_Overrides(t *testing.T) {
+	templates, err := newDistributionTemplates(map[string]string{
+		formulaTemplate: "class Harbor < Formula\n  version {{ printf \"%q\" .Version }}\nend\n",
+	})
+	if err != nil {
+		t.Fatalf("newDistributionTemplates: %v", err)
+	}
+	formula, err := templates.renderFormula("v1.2.3", fixtureChecksums("v1.2.3"))
+	if err != nil {
+		t.Fatalf("renderFormula: %v", err)
+	}
+	if formula != "class Harbor < Formula\n  version \"1.2.3\"\nend\n" {
+		t.Errorf("formula = %q", formula)
+	}
+
+	// Templates that are not overridden keep the embedded version
+	files, err := templates.renderNPMPackage("v1.2.3", "")
+	if err != nil {
+		t.Fatalf("renderNPMPackage: %v", err)
+	}
+	if !strings.Contains(files["README.md"], "# shipyard-cli") {
+		t.Errorf("README = %q", files["README.md"])
+	}
+
+	if _, err := newDistributionTemplates(map[string]string{"homebrew/harbor.rb.tmpl": ""}); err == nil ||
+		!strings.Contains(err.Error(), "homebrew/harbor.rb.tmpl does not replace a distribution template") {
+		t.Errorf("unknown override error = %v", err)
+	}
+
+	broken, err := newDistributionTemplates(map[string]string{npmReadmeTemplate: "{{ .Missing }}"})
+	if err != nil {
+		t.Fatalf("newDistributionTemplates: %v", err)
+	}
+	if _, err := broken.renderNPMPackage("v1.2.3", ""); err == nil || !strings.Contains(err.Error(), npmReadmeTemplate) {
+		t.Errorf("render error = %v, want it to name %s", err, npmReadmeTemplate)
+	}
+}
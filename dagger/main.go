@@ -9,42 +9,85 @@ import (
 	"context"
 	"dagger/shipyard/internal/dagger"
 	"fmt"
+	"strings"
 	"sync"
 )
 
-type Shipyard struct{}
+type Shipyard struct {
+	// Directory of templates replacing the embedded Homebrew formula and npm
+	// package templates at the same paths, so forks can rebrand the packages
+	Templates *dagger.Directory
+}
+
+func New(
+	// Directory of templates replacing the embedded Homebrew formula and npm
+	// package templates at the same paths (e.g. homebrew/shipyard.rb.tmpl)
+	// +optional
+	templates *dagger.Directory,
+) *Shipyard {
+	return &Shipyard{Templates: templates}
+}
 
-// Release builds, packages, and publishes Shipyard to all distribution channels
+// Release builds, packages, and publishes Shipyard to the selected
+// distribution channels, and returns what happened on each channel
 func (m *Shipyard) Release(
 	ctx context.Context,
 	// Source code directory
 	source *dagger.Directory,
 	// Version string (e.g., "v1.2.3")
 	version string,
-	// GitHub token for releases and Docker registry
+	// Comma-separated channels to publish to: github, homebrew, npm, docker (default all).
+	// Channels left at the default are skipped when their credentials are missing;
+	// channels named here fail the release instead.
+	// +optional
+	channels string,
+	// GitHub token for releases, the Homebrew tap and the Docker registry
+	// +optional
 	githubToken *dagger.Secret,
 	// GitHub Actions OIDC token request URL for npm trusted publishing (ACTIONS_ID_TOKEN_REQUEST_URL)
+	// +optional
 	npmOidcTokenUrl string,
 	// GitHub Actions OIDC bearer token for npm trusted publishing (ACTIONS_ID_TOKEN_REQUEST_TOKEN)
+	// +optional
 	npmOidcToken *dagger.Secret,
 	// Docker registry (e.g., "ghcr.io/natonathan/shipyard")
 	// +default="ghcr.io/natonathan/shipyard"
 	dockerRegistry string,
 	// Docker registry username (GitHub actor for GHCR)
+	// +optional
 	dockerUsername string,
 	// Docker registry token (usually same as GitHub token for ghcr.io)
 	// +optional
 	dockerToken *dagger.Secret,
-) error {
+	// Cosign private key for signing Docker image attestations
+	// +optional
+	cosignKey *dagger.Secret,
+	// Password for the cosign private key
+	// +optional
+	cosignPassword *dagger.Secret,
+	// Skip signing and attaching Docker image attestations (for forks without a cosign key)
+	// +optional
+	skipAttestation bool,
+	// Skip generating SBOMs for the platform binaries
+	// +optional
+	skipSbom bool,
+) (*ReleaseSummary, error) {
 	// Use GitHub token for Docker if not provided
 	if dockerToken == nil {
 		dockerToken = githubToken
 	}
-	if npmOidcTokenUrl == "" {
-		return fmt.Errorf("npm OIDC token request URL is required")
+	skipped, err := planChannels(channels, missingCredentials(githubToken, npmOidcTokenUrl, npmOidcToken, dockerUsername, dockerToken))
+	if err != nil {
+		return nil, err
+	}
+	_, skipDocker := skipped[ChannelDocker]
+	if !skipDocker && cosignKey == nil && !skipAttestation {
+		return nil, fmt.Errorf("cosign key is required to attest Docker images; pass --skip-attestation to release without attestations")
 	}
-	if npmOidcToken == nil {
-		return fmt.Errorf("npm OIDC token request token is required")
+	for _, channel := range ReleaseChannels {
+		if reason, ok := skipped[channel]; ok {
+			fmt.Printf("⏭  Skipping %s: %s\n", channel, reason)
+		}
 	}
 
 	// Get commit SHA from git
@@ -56,79 +99,146 @@ func (m *Shipyard) Release(
 		Stdout(ctx)
 
 	if err != nil {
-		return fmt.Errorf("failed to get git commit: %w", err)
+		return nil, fmt.Errorf("failed to get git commit: %w", err)
 	}
+	commit = strings.TrimSpace(commit)
 
 	fmt.Printf("🚢 Building Shipyard %s (commit: %.7s)\n", version, commit)
 
 	// Stage 1: Build
 	fmt.Printf("\n📦 Stage 1: Building binaries...\n")
-	buildArtifacts := m.Build(ctx, source, version, commit)
+	buildArtifacts, err := m.Build(ctx, source, version, commit, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build failed: %w", err)
+	}
 
 	// Stage 2: Package
 	fmt.Printf("\n📦 Stage 2: Creating distribution packages...\n")
-	packageArtifacts := m.Package(ctx, buildArtifacts, version)
+	packageArtifacts, err := m.Package(ctx, buildArtifacts, version, nil, skipSbom)
+	if err != nil {
+		return nil, fmt.Errorf("packaging failed: %w", err)
+	}
+	packageArtifacts, err = m.withProvenance(ctx, packageArtifacts, version, commit)
+	if err != nil {
+		return nil, fmt.Errorf("provenance failed: %w", err)
+	}
 
 	// Stage 3: Publish (all in parallel)
-	fmt.Printf("\n🚀 Stage 3: Publishing to distribution channels...\n")
+	targets := releaseTargets{
+		GitHub:   &ghCLIReleaseTarget{repo: "NatoNathan/shipyard", token: githubToken},
+		Homebrew: &gitTapTarget{repo: "natonathan/homebrew-tap", token: githubToken},
+		NPM:      &oidcNPMTarget{tokenURL: npmOidcTokenUrl, token: npmOidcToken},
+		Docker:   &registryDockerTarget{repository: dockerRegistry, username: dockerUsername, token: dockerToken},
+		Skipped:  skipped,
+	}
+	if !skipAttestation && !skipDocker {
+		targets.Attestation = &cosignAttestationTarget{
+			registry: strings.Split(dockerRegistry, "/")[0],
+			username: dockerUsername,
+			token:    dockerToken,
+			key:      cosignKey,
+			password: cosignPassword,
+		}
+	}
+	summary := m.publishAll(ctx, source, buildArtifacts, packageArtifacts, version, commit, targets)
+	if err := summary.err(); err != nil {
+		return summary, err
+	}
 
-	var wg sync.WaitGroup
-	errors := make(chan error, 4)
+	fmt.Printf("\n✅ Release %s completed successfully!\n", version)
+	return summary, nil
+}
 
-	// Publish to GitHub
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		if err := m.PublishGitHub(ctx, source, packageArtifacts, version, commit, githubToken); err != nil {
-			errors <- fmt.Errorf("GitHub: %w", err)
-		}
-	}()
-
-	// Publish to Homebrew
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		if err := m.PublishHomebrew(ctx, packageArtifacts, version, githubToken); err != nil {
-			errors <- fmt.Errorf("Homebrew: %w", err)
-		}
-	}()
-
-	// Publish to npm
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		if err := m.PublishNPM(ctx, packageArtifacts, version, npmOidcTokenUrl, npmOidcToken); err != nil {
-			errors <- fmt.Errorf("npm: %w", err)
-		}
-	}()
-
-	// Publish to Docker
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		if err := m.PublishDocker(ctx, buildArtifacts, version, dockerRegistry, dockerUsername, dockerToken); err != nil {
-			errors <- fmt.Errorf("Docker: %w", err)
-		}
-	}()
+// missingCredentials names the credentials each channel lacks. The Homebrew
+// tap is pushed with the GitHub token, and Docker falls back to it.
+func missingCredentials(
+	githubToken *dagger.Secret,
+	npmOidcTokenUrl string,
+	npmOidcToken *dagger.Secret,
+	dockerUsername string,
+	dockerToken *dagger.Secret,
+) map[string]string {
+	missing := make(map[string]string)
+	if githubToken == nil {
+		missing[ChannelGitHub] = "githubToken"
+		missing[ChannelHomebrew] = "githubToken"
+	}
+	switch {
+	case npmOidcTokenUrl == "":
+		missing[ChannelNPM] = "npmOidcTokenUrl"
+	case npmOidcToken == nil:
+		missing[ChannelNPM] = "npmOidcToken"
+	}
+	switch {
+	case dockerToken == nil:
+		missing[ChannelDocker] = "dockerToken or githubToken"
+	case dockerUsername == "":
+		missing[ChannelDocker] = "dockerUsername"
+	}
+	return missing
+}
 
-	// Wait for all publishers
-	wg.Wait()
-	close(errors)
+// publishAll generates the release notes, publishes to every channel not in
+// targets.Skipped in parallel, and summarizes the outcome on each channel
+func (m *Shipyard) publishAll(
+	ctx context.Context,
+	source *dagger.Directory,
+	buildArtifacts *dagger.Directory,
+	packageArtifacts *dagger.Directory,
+	version string,
+	commit string,
+	targets releaseTargets,
+) *ReleaseSummary {
+	fmt.Printf("\n🚀 Stage 3: Publishing to distribution channels...\n")
+
+	// Every channel but Docker shows the notes, so generate them once up front
+	var notes releaseNotes
+	for _, channel := range []string{ChannelGitHub, ChannelHomebrew, ChannelNPM} {
+		if _, skip := targets.Skipped[channel]; !skip {
+			notes = m.releaseNotes(ctx, source, packageArtifacts, version, commit)
+			break
+		}
+	}
 
-	// Collect errors
-	var publishErrors []error
-	for err := range errors {
-		publishErrors = append(publishErrors, err)
+	publishers := map[string]func() error{
+		ChannelGitHub: func() error {
+			return m.publishGitHub(ctx, packageArtifacts, version, notes, targets.GitHub)
+		},
+		ChannelHomebrew: func() error {
+			return m.publishHomebrew(ctx, packageArtifacts, version, notes.Plain, targets.Homebrew)
+		},
+		ChannelNPM: func() error {
+			return m.publishNPM(ctx, version, notes.Markdown, targets.NPM)
+		},
+		ChannelDocker: func() error {
+			_, err := m.publishDocker(ctx, buildArtifacts, packageArtifacts, version, commit, targets.Docker, targets.Attestation)
+			return err
+		},
 	}
 
-	if len(publishErrors) > 0 {
-		fmt.Printf("\n❌ Release failed with %d error(s):\n", len(publishErrors))
-		for _, err := range publishErrors {
-			fmt.Printf("  - %v\n", err)
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs = make(map[string]error)
+	)
+	for _, channel := range ReleaseChannels {
+		if _, skip := targets.Skipped[channel]; skip {
+			continue
 		}
-		return fmt.Errorf("release failed")
+		publish := publishers[channel]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := publish(); err != nil {
+				mu.Lock()
+				errs[channel] = err
+				mu.Unlock()
+			}
+		}()
 	}
+	wg.Wait()
 
-	fmt.Printf("\n✅ Release %s completed successfully!\n", version)
-	return nil
+	summary := newReleaseSummary(version, targets.Skipped, errs)
+	fmt.Printf("\n📋 %s", summary)
+	return summary
 }
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// releaseNotes are the notes of a release, generated once and reused by
+// every channel that shows them
+type releaseNotes struct {
+	// GitHub release body and npm README section
+	Markdown string
+	// Homebrew tap commit message body
+	Plain string
+}
+
+// changelogExcerpt returns the body of the section of a Keep a Changelog
+// style CHANGELOG.md for version, without its heading. Section headings look
+// like "## [1.2.3] - 2026-06-27"; the version may be given with or without
+// its "v" prefix.
+func changelogExcerpt(changelog, version string) (string, error) {
+	want := strings.TrimPrefix(version, "v")
+
+	var section []string
+	found := false
+	for _, line := range strings.Split(strings.ReplaceAll(changelog, "\r\n", "\n"), "\n") {
+		if strings.HasPrefix(line, "# ") || strings.HasPrefix(line, "## ") {
+			if found {
+				break
+			}
+			found = changelogHeadingVersion(line) == want
+			continue
+		}
+		if found {
+			section = append(section, line)
+		}
+	}
+	if !found {
+		return "", fmt.Errorf("CHANGELOG.md has no section for %s", version)
+	}
+
+	excerpt := strings.TrimSpace(strings.Join(section, "\n"))
+	if excerpt == "" {
+		return "", fmt.Errorf("CHANGELOG.md section for %s is empty", version)
+	}
+	return excerpt, nil
+}
+
+// changelogHeadingVersion returns the version named by a changelog heading,
+// without brackets or a "v" prefix
+func changelogHeadingVersion(heading string) string {
+	fields := strings.Fields(strings.TrimLeft(heading, "#"))
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.TrimPrefix(strings.Trim(fields[0], "[]"), "v")
+}
+
+// homebrewCommitMessage is the tap commit message for version, with the
+// plain text notes as its body
+func homebrewCommitMessage(version, notes string) string {
+	subject := fmt.Sprintf("Update shipyard to %s", version)
+	if notes = strings.TrimSpace(notes); notes == "" {
+		return subject
+	}
+	return subject + "\n\n" + notes
+}
+
+// demoteHeadings nests markdown headings levels deeper, so notes can be
+// embedded under a heading of another document. Code fences are left alone.
+func demoteHeadings(markdown string, levels int) string {
+	lines := strings.Split(markdown, "\n")
+	inFence := false
+	for i, line := range lines {
+		if strings.HasPrefix(line, "```") {
+			inFence = !inFence
+			continue
+		}
+		if !inFence && strings.HasPrefix(line, "#") {
+			lines[i] = strings.Repeat("#", levels) + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
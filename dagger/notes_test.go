@@ -0,0 +1,76 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const testChangelog = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+## [0.7.1] - 2026-06-27
+**Package**: shipyard
+
+### Bug Fixes
+- Fix release pipeline
+
+## [0.7.0] - 2026-06-27
+**Package**: shipyard
+
+### Features
+- Enrich changelog template context
+
+## [0.6.9] - 2026-06-01
+`
+
+func TestChangelogExcerpt(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		want    string
+		wantErr string
+	}{
+		{name: "latest section", version: "v0.7.1", want: "**Package**: shipyard\n\n### Bug Fixes\n- Fix release pipeline"},
+		{name: "without v prefix", version: "0.7.0", want: "**Package**: shipyard\n\n### Features\n- Enrich changelog template context"},
+		{name: "missing version", version: "v1.0.0", wantErr: "CHANGELOG.md has no section for v1.0.0"},
+		{name: "empty section", version: "v0.6.9", wantErr: "CHANGELOG.md section for v0.6.9 is empty"},
+		{name: "prefix of another version", version: "v0.7", wantErr: "no section"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := changelogExcerpt(testChangelog, tt.version)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("changelogExcerpt(%q) error = %v, want %q", tt.version, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("changelogExcerpt(%q) unexpected error: %v", tt.version, err)
+			}
+			if got != tt.want {
+				t.Errorf("changelogExcerpt(%q) =\n%s\nwant\n%s", tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHomebrewCommitMessage(t *testing.T) {
+	if got := homebrewCommitMessage("v1.2.3", " \n"); got != "Update shipyard to v1.2.3" {
+		t.Errorf("without notes = %q", got)
+	}
+	want := "Update shipyard to v1.2.3\n\nChanges\n- Minor: Add dark mode"
+	if got := homebrewCommitMessage("v1.2.3", "Changes\n- Minor: Add dark mode\n"); got != want {
+		t.Errorf("with notes = %q, want %q", got, want)
+	}
+}
+
+func TestDemoteHeadings(t *testing.T) {
+	markdown := "# Release Notes\n\n## Install\n\n```sh\n# comment\n```"
+	want := "### Release Notes\n\n#### Install\n\n```sh\n# comment\n```"
+	if got := demoteHeadings(markdown, 2); got != want {
+		t.Errorf("demoteHeadings() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -6,34 +6,72 @@ import (
 	"fmt"
 )
 
-// Package creates distribution archives and checksums
+// Package creates distribution archives, SPDX SBOMs of the binaries, and
+// checksums for the platforms present in the build artifacts, or for the given subset
 func (m *Shipyard) Package(
 	ctx context.Context,
 	// Build artifacts directory
 	buildArtifacts *dagger.Directory,
 	// Version string (e.g., "v1.2.3")
 	version string,
-) *dagger.Directory {
+	// Platforms to package in os/arch[/variant] form; defaults to every platform in the build artifacts
+	// +optional
+	platforms []string,
+	// Skip generating an SBOM for each platform binary
+	// +optional
+	skipSbom bool,
+) (*dagger.Directory, error) {
+	var selected []Platform
+	if len(platforms) > 0 {
+		var err error
+		selected, err = selectPlatforms(platforms)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		entries, err := buildArtifacts.Entries(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list build artifacts: %w", err)
+		}
+		selected = platformsInArtifacts(entries)
+		if len(selected) == 0 {
+			return nil, fmt.Errorf("no platform binaries found in build artifacts")
+		}
+	}
+
+	assets := releaseAssets(selected, version, !skipSbom)
 	output := dag.Directory()
-	checksums := ""
+	checksums := make(map[string]string, len(assets))
 
-	// Create archive for each platform
-	for _, platform := range SupportedPlatforms {
-		archive := m.createArchive(ctx, buildArtifacts, platform, version)
-		filename := archiveFilename(platform, version)
+	// Create the archive and SBOM for each platform
+	for _, asset := range assets {
+		var file *dagger.File
+		switch asset.Kind {
+		case archiveAsset:
+			file = m.createArchive(ctx, buildArtifacts, asset.Platform, version)
+		case sbomAsset:
+			file = m.generateSBOM(buildArtifacts, asset.Platform, version)
+		}
 
-		// Add archive to output
-		output = output.WithFile(filename, archive)
+		// Add asset to output
+		output = output.WithFile(asset.Name, file)
 
 		// Calculate checksum
-		checksum, _ := calculateChecksum(ctx, archive, filename)
-		checksums += checksum + "\n"
+		checksum, err := calculateChecksum(ctx, file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to checksum %s: %w", asset.Name, err)
+		}
+		checksums[asset.Name] = checksum
 	}
 
 	// Add checksums file
-	output = output.WithNewFile("checksums.txt", checksums)
+	contents, err := formatChecksums(checksums)
+	if err != nil {
+		return nil, err
+	}
+	output = output.WithNewFile("checksums.txt", contents)
 
-	return output
+	return output, nil
 }
 
 // createArchive creates a tar.gz or zip archive for a platform
@@ -43,11 +81,8 @@ func (m *Shipyard) createArchive(
 	platform Platform,
 	version string,
 ) *dagger.File {
-	dirname := fmt.Sprintf("%s_%s", platform.OS, platform.Arch)
-	binaryName := "shipyard"
-	if platform.OS == "windows" {
-		binaryName += ".exe"
-	}
+	dirname := platform.dirname()
+	binaryName := platform.binaryName()
 
 	// Get the binary file
 	binaryPath := fmt.Sprintf("%s/%s", dirname, binaryName)
@@ -81,19 +116,20 @@ func (m *Shipyard) createArchive(
 	return archiver.File(filename)
 }
 
-// archiveFilename generates the archive filename for a platform
+// archiveFilename generates the archive filename for a platform, e.g.
+// shipyard_v1.2.3_linux_armv7.tar.gz or shipyard_v1.2.3_windows_arm64.zip
 func archiveFilename(platform Platform, version string) string {
 	ext := "tar.gz"
 	if platform.OS == "windows" {
 		ext = "zip"
 	}
-	return fmt.Sprintf("shipyard_%s_%s_%s.%s", version, platform.OS, platform.Arch, ext)
+	return fmt.Sprintf("shipyard_%s_%s_%s.%s", version, platform.OS, platform.archName(), ext)
 }
 
-// calculateChecksum computes SHA256 checksum for a file using sha256sum in a container
+// calculateChecksum computes the SHA256 checksum (hex) of a file using sha256sum in a container
 // Note: We use a container-based approach because Dagger's file.Contents() returns a string,
 // which can corrupt binary data. Using sha256sum ensures we hash the raw bytes correctly.
-func calculateChecksum(ctx context.Context, file *dagger.File, filename string) (string, error) {
+func calculateChecksum(ctx context.Context, file *dagger.File) (string, error) {
 	// Use sha256sum in a container to calculate checksum of binary file correctly
 	output, err := dag.Container().
 		From("alpine:latest").
@@ -106,14 +142,11 @@ func calculateChecksum(ctx context.Context, file *dagger.File, filename string)
 	}
 
 	// Parse the checksum from sha256sum output (format: "checksum  /file\n")
-	// Extract just the hash part (first 64 characters)
+	// Return just the hash part (first 64 characters)
 	if len(output) < 64 {
 		return "", fmt.Errorf("unexpected sha256sum output: %s", output)
 	}
-	checksumHex := output[:64]
-
-	// Format as "checksum  filename"
-	return fmt.Sprintf("%s  %s", checksumHex, filename), nil
+	return output[:64], nil
 }
 
 // PackageOnly is a convenience function for testing package stage in isolation
@@ -123,7 +156,13 @@ func (m *Shipyard) PackageOnly(
 	source *dagger.Directory,
 	// Version string (e.g., "v1.2.3")
 	version string,
-) *dagger.Directory {
-	buildArtifacts := m.BuildOnly(ctx, source, version)
-	return m.Package(ctx, buildArtifacts, version)
+	// Platforms to build and package in os/arch[/variant] form; defaults to all supported platforms
+	// +optional
+	platforms []string,
+) (*dagger.Directory, error) {
+	buildArtifacts, err := m.BuildOnly(ctx, source, version, platforms)
+	if err != nil {
+		return nil, err
+	}
+	return m.Package(ctx, buildArtifacts, version, platforms, false)
 }
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSelectPlatforms(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   []string
+		want    []string
+		wantErr string
+	}{
+		{name: "empty selects all", input: nil, want: []string{"linux/amd64", "linux/arm64", "linux/arm/v7", "darwin/amd64", "darwin/arm64", "windows/amd64", "windows/arm64"}},
+		{name: "subset keeps supported order", input: []string{"windows/amd64", "linux/amd64"}, want: []string{"linux/amd64", "windows/amd64"}},
+		{name: "arm variant", input: []string{"windows/arm64", "linux/arm/v7"}, want: []string{"linux/arm/v7", "windows/arm64"}},
+		{name: "arm without variant", input: []string{"linux/arm"}, wantErr: `unsupported platform "linux/arm"`},
+		{name: "duplicates are dropped", input: []string{"darwin/arm64", " darwin/arm64 "}, want: []string{"darwin/arm64"}},
+		{name: "unsupported platform", input: []string{"linux/amd64", "plan9/386"}, wantErr: `unsupported platform "plan9/386"`},
+		{name: "missing arch", input: []string{"linux"}, wantErr: "expected os/arch"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			platforms, err := selectPlatforms(tt.input)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("selectPlatforms(%v) error = %v, want %q", tt.input, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("selectPlatforms(%v) unexpected error: %v", tt.input, err)
+			}
+
+			got := make([]string, len(platforms))
+			for i, platform := range platforms {
+				got[i] = platform.String()
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("selectPlatforms(%v) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlatformsInArtifacts(t *testing.T) {
+	entries := []string{"windows_amd64/", "linux_amd64/", "README.md", "freebsd_amd64/"}
+
+	got := platformsInArtifacts(entries)
+	want := []Platform{{OS: "linux", Arch: "amd64"}, {OS: "windows", Arch: "amd64"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("platformsInArtifacts() = %v, want %v", got, want)
+	}
+
+	if got := platformsInArtifacts(nil); len(got) != 0 {
+		t.Errorf("platformsInArtifacts(nil) = %v, want none", got)
+	}
+}
+
+func TestArtifactNames(t *testing.T) {
+	tests := []struct {
+		platform Platform
+		dirname  string
+		archive  string
+		sbom     string
+	}{
+		{Platform{OS: "linux", Arch: "amd64"}, "linux_amd64", "shipyard_v1.2.3_linux_amd64.tar.gz", "shipyard_v1.2.3_linux_amd64.spdx.json"},
+		{Platform{OS: "linux", Arch: "arm", Variant: "v7"}, "linux_armv7", "shipyard_v1.2.3_linux_armv7.tar.gz", "shipyard_v1.2.3_linux_armv7.spdx.json"},
+		{Platform{OS: "windows", Arch: "arm64"}, "windows_arm64", "shipyard_v1.2.3_windows_arm64.zip", "shipyard_v1.2.3_windows_arm64.spdx.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.platform.String(), func(t *testing.T) {
+			if got := tt.platform.dirname(); got != tt.dirname {
+				t.Errorf("dirname() = %q, want %q", got, tt.dirname)
+			}
+			if got := archiveFilename(tt.platform, "v1.2.3"); got != tt.archive {
+				t.Errorf("archiveFilename() = %q, want %q", got, tt.archive)
+			}
+			if got := sbomFilename(tt.platform, "v1.2.3"); got != tt.sbom {
+				t.Errorf("sbomFilename() = %q, want %q", got, tt.sbom)
+			}
+		})
+	}
+}
+
+func TestPlatformGOARM(t *testing.T) {
+	if got := (Platform{OS: "linux", Arch: "arm", Variant: "v7"}).goarm(); got != "7" {
+		t.Errorf("goarm() for linux/arm/v7 = %q, want 7", got)
+	}
+	if got := (Platform{OS: "linux", Arch: "arm64"}).goarm(); got != "" {
+		t.Errorf("goarm() for linux/arm64 = %q, want none", got)
+	}
+}
+
+func TestFormatChecksums(t *testing.T) {
+	linux := Platform{OS: "linux", Arch: "amd64"}
+	windows := Platform{OS: "windows", Arch: "amd64"}
+	linuxSum := strings.Repeat("a", 64)
+	windowsSum := strings.Repeat("b", 64)
+
+	t.Run("partial set lists only built archives", func(t *testing.T) {
+		checksums := map[string]string{
+			archiveFilename(windows, "v1.2.3"): windowsSum,
+			archiveFilename(linux, "v1.2.3"):   linuxSum,
+		}
+
+		got, err := formatChecksums(checksums)
+		if err != nil {
+			t.Fatalf("formatChecksums() unexpected error: %v", err)
+		}
+
+		want := linuxSum + "  shipyard_v1.2.3_linux_amd64.tar.gz\n" +
+			windowsSum + "  shipyard_v1.2.3_windows_amd64.zip\n"
+		if got != want {
+			t.Errorf("formatChecksums() =\n%s\nwant\n%s", got, want)
+		}
+	})
+
+	t.Run("empty set fails", func(t *testing.T) {
+		if _, err := formatChecksums(nil); err == nil {
+			t.Error("formatChecksums(nil) expected error")
+		}
+	})
+
+	t.Run("malformed checksum fails", func(t *testing.T) {
+		checksums := map[string]string{archiveFilename(linux, "v1.2.3"): "abc"}
+		if _, err := formatChecksums(checksums); err == nil {
+			t.Error("formatChecksums() expected error for short checksum")
+		}
+	})
+}
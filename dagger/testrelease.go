@@ -0,0 +1,297 @@
+package main
+
+import (
+	"context"
+	"dagger/shipyard/internal/dagger"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// TestRelease runs the full Build → Package → Publish pipeline against disposable
+// targets: a local registry service for Docker, a directory in place of the GitHub
+// release, a directory in place of the Homebrew tap, and `npm publish --dry-run`.
+// Image attestations are attached unsigned with oras instead of cosign.
+// It asserts on the produced artifacts, SBOMs, provenance, pushed tags,
+// attestations, formula contents and release notes, and returns a summary report.
+func (m *Shipyard) TestRelease(
+	ctx context.Context,
+	// Source code directory
+	source *dagger.Directory,
+	// Version string (e.g., "v1.2.3")
+	// +optional
+	// +default="v0.0.0-test"
+	version string,
+) (string, error) {
+	commit, err := dag.Container().
+		From("alpine/git:latest").
+		WithMountedDirectory("/src", source).
+		WithWorkdir("/src").
+		WithExec([]string{"git", "rev-parse", "HEAD"}).
+		Stdout(ctx)
+	if err != nil {
+		// Sources without git metadata are fine for a test release
+		commit = "0000000"
+	}
+	commit = strings.TrimSpace(commit)
+
+	fmt.Printf("🧪 Test release of Shipyard %s (commit: %.7s)\n", version, commit)
+
+	fmt.Printf("\n📦 Stage 1: Building binaries...\n")
+	buildArtifacts, err := m.Build(ctx, source, version, commit, nil)
+	if err != nil {
+		return "", fmt.Errorf("build failed: %w", err)
+	}
+
+	fmt.Printf("\n📦 Stage 2: Creating distribution packages...\n")
+	packageArtifacts, err := m.Package(ctx, buildArtifacts, version, nil, false)
+	if err != nil {
+		return "", fmt.Errorf("packaging failed: %w", err)
+	}
+	packageArtifacts, err = m.withProvenance(ctx, packageArtifacts, version, commit)
+	if err != nil {
+		return "", fmt.Errorf("provenance failed: %w", err)
+	}
+
+	registry := dag.Container().
+		From("registry:2").
+		WithExposedPort(5000).
+		AsService()
+
+	github := &directoryReleaseTarget{output: dag.Directory()}
+	homebrew := &directoryTapTarget{output: dag.Directory()}
+	npm := &dryRunNPMTarget{output: dag.Directory()}
+	docker := &serviceDockerTarget{registry: registry, alias: "registry", image: "shipyard"}
+	attestor := &orasAttestationTarget{registry: registry, alias: "registry"}
+
+	targets := releaseTargets{GitHub: github, Homebrew: homebrew, NPM: npm, Docker: docker, Attestation: attestor}
+	if err := m.publishAll(ctx, source, buildArtifacts, packageArtifacts, version, commit, targets).err(); err != nil {
+		return "", err
+	}
+
+	fmt.Printf("\n🔎 Verifying published artifacts...\n")
+	var report strings.Builder
+
+	assets, err := verifyArtifacts(ctx, packageArtifacts, version)
+	if err != nil {
+		return "", fmt.Errorf("artifacts: %w", err)
+	}
+	fmt.Fprintf(&report, "Artifacts: %s\n", strings.Join(assets, ", "))
+
+	if err := verifyGitHubRelease(ctx, github.output, assets, version); err != nil {
+		return "", fmt.Errorf("GitHub: %w", err)
+	}
+	fmt.Fprintf(&report, "GitHub release: %d assets, provenance and notes\n", len(assets)+1)
+
+	tags, err := registryTags(ctx, registry, docker.image)
+	if err != nil {
+		return "", fmt.Errorf("Docker: %w", err)
+	}
+	expectedTags := generateDockerTags(version)
+	slices.Sort(expectedTags)
+	if !slices.Equal(tags, expectedTags) {
+		return "", fmt.Errorf("Docker: registry tags %v, expected %v", tags, expectedTags)
+	}
+	fmt.Fprintf(&report, "Docker tags: %s\n", strings.Join(tags, ", "))
+
+	if err := verifyImageAttestations(ctx, registry, fmt.Sprintf("%s:%s", docker.Repository(), version)); err != nil {
+		return "", fmt.Errorf("Docker: %w", err)
+	}
+	fmt.Fprintf(&report, "Docker attestations: %d SBOMs and provenance\n", len(DockerPlatforms))
+
+	checksums, err := m.extractChecksums(ctx, packageArtifacts)
+	if err != nil {
+		return "", fmt.Errorf("Homebrew: failed to extract checksums: %w", err)
+	}
+	if err := verifyFormula(ctx, homebrew.output, version, checksums); err != nil {
+		return "", fmt.Errorf("Homebrew: %w", err)
+	}
+	fmt.Fprintf(&report, "Homebrew formula: Formula/shipyard.rb, committed with notes\n")
+
+	if err := verifyNPMDryRun(ctx, npm.output, version); err != nil {
+		return "", fmt.Errorf("npm: %w", err)
+	}
+	fmt.Fprintf(&report, "npm: shipyard-cli@%s (dry run), README with notes\n", strings.TrimPrefix(version, "v"))
+
+	fmt.Printf("\n✅ Test release %s passed!\n", version)
+	return report.String(), nil
+}
+
+// verifyArtifacts checks that every platform archive, the SBOM of every
+// platform binary, and the checksums file exist. Returns the sorted asset names.
+func verifyArtifacts(ctx context.Context, artifacts *dagger.Directory, version string) ([]string, error) {
+	entries, err := artifacts.Entries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	assets := assetNames(releaseAssets(SupportedPlatforms, version, true))
+	for _, name := range assets {
+		if !slices.Contains(entries, name) {
+			return nil, fmt.Errorf("missing %s", name)
+		}
+	}
+
+	checksums, err := artifacts.File("checksums.txt").Contents(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("missing checksums.txt: %w", err)
+	}
+	lines := strings.Split(strings.TrimSpace(checksums), "\n")
+	if len(lines) != len(assets) {
+		return nil, fmt.Errorf("checksums.txt has %d entries, expected %d", len(lines), len(assets))
+	}
+
+	return assets, nil
+}
+
+// verifyGitHubRelease checks the directory release contains every asset, the
+// provenance statement covering them, and the notes
+func verifyGitHubRelease(ctx context.Context, release *dagger.Directory, assets []string, version string) error {
+	entries, err := release.Entries(ctx, dagger.DirectoryEntriesOpts{Path: version})
+	if err != nil {
+		return fmt.Errorf("release %s not created: %w", version, err)
+	}
+	expected := append([]string{"checksums.txt", "NOTES.md", provenanceFilename(version)}, assets...)
+	for _, name := range expected {
+		if !slices.Contains(entries, name) {
+			return fmt.Errorf("release is missing %s", name)
+		}
+	}
+
+	provenance, err := release.File(version + "/" + provenanceFilename(version)).Contents(ctx)
+	if err != nil {
+		return err
+	}
+	var statement struct {
+		Subject []provenanceSubject `json:"subject"`
+	}
+	if err := json.Unmarshal([]byte(provenance), &statement); err != nil {
+		return fmt.Errorf("invalid provenance: %w", err)
+	}
+	var subjects []string
+	for _, subject := range statement.Subject {
+		subjects = append(subjects, subject.Name)
+	}
+	if !slices.Equal(subjects, assets) {
+		return fmt.Errorf("provenance covers %v, expected %v", subjects, assets)
+	}
+
+	notes, err := release.File(version + "/NOTES.md").Contents(ctx)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(notes) == "" {
+		return fmt.Errorf("release notes are empty")
+	}
+	return nil
+}
+
+// registryTags lists the tags of an image in a registry service, sorted
+func registryTags(ctx context.Context, registry *dagger.Service, image string) ([]string, error) {
+	output, err := dag.Container().
+		From("alpine:latest").
+		WithServiceBinding("registry", registry).
+		WithExec([]string{"wget", "-qO-", fmt.Sprintf("http://registry:5000/v2/%s/tags/list", image)}).
+		Stdout(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list registry tags: %w", err)
+	}
+
+	var result struct {
+		Tags []string `json:"tags"`
+	}
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse registry tags: %w", err)
+	}
+	slices.Sort(result.Tags)
+	return result.Tags, nil
+}
+
+// verifyImageAttestations checks an image has an SBOM attached for every
+// image platform and a provenance attestation
+func verifyImageAttestations(ctx context.Context, registry *dagger.Service, imageRef string) error {
+	output, err := dag.Container().
+		From(OrasImage).
+		WithServiceBinding("registry", registry).
+		WithExec([]string{"oras", "discover", "--plain-http", "--format", "json", imageRef}).
+		Stdout(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list image referrers: %w", err)
+	}
+
+	var result struct {
+		Manifests []struct {
+			ArtifactType string `json:"artifactType"`
+		} `json:"manifests"`
+	}
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		return fmt.Errorf("failed to parse image referrers: %w", err)
+	}
+	counts := make(map[string]int)
+	for _, manifest := range result.Manifests {
+		counts[manifest.ArtifactType]++
+	}
+
+	if counts[sbomMediaType] != len(DockerPlatforms) {
+		return fmt.Errorf("image has %d SBOM attestations, expected %d", counts[sbomMediaType], len(DockerPlatforms))
+	}
+	if counts[provenanceMediaType] == 0 {
+		return fmt.Errorf("image has no provenance attestation")
+	}
+	return nil
+}
+
+// verifyFormula checks the formula references the version and every archive
+// checksum, and that its commit message carries the release notes
+func verifyFormula(ctx context.Context, tap *dagger.Directory, version string, checksums map[string]string) error {
+	formula, err := tap.File("Formula/shipyard.rb").Contents(ctx)
+	if err != nil {
+		return fmt.Errorf("formula not written: %w", err)
+	}
+
+	message, err := tap.File("COMMIT_MESSAGE").Contents(ctx)
+	if err != nil {
+		return fmt.Errorf("commit message not written: %w", err)
+	}
+	if !strings.HasPrefix(message, homebrewCommitMessage(version, "")+"\n\n") {
+		return fmt.Errorf("commit message %q has no release notes", message)
+	}
+
+	if !strings.Contains(formula, fmt.Sprintf(`version "%s"`, strings.TrimPrefix(version, "v"))) {
+		return fmt.Errorf("formula does not declare version %s", version)
+	}
+	for _, platform := range SupportedPlatforms {
+		if platform.OS == "windows" {
+			continue
+		}
+		name := archiveFilename(platform, version)
+		sum, ok := checksums[name]
+		if !ok || !strings.Contains(formula, sum) {
+			return fmt.Errorf("formula is missing checksum for %s", name)
+		}
+	}
+	return nil
+}
+
+// verifyNPMDryRun checks the npm dry run packaged the expected version with
+// the release notes in its README
+func verifyNPMDryRun(ctx context.Context, npm *dagger.Directory, version string) error {
+	report, err := npm.File("publish-dry-run.txt").Contents(ctx)
+	if err != nil {
+		return fmt.Errorf("dry run not recorded: %w", err)
+	}
+	expected := "shipyard-cli@" + strings.TrimPrefix(version, "v")
+	if !strings.Contains(report, expected) {
+		return fmt.Errorf("dry run output does not mention %s", expected)
+	}
+
+	readme, err := npm.File("package/README.md").Contents(ctx)
+	if err != nil {
+		return fmt.Errorf("README not packaged: %w", err)
+	}
+	if !strings.Contains(readme, "## Release Notes") {
+		return fmt.Errorf("README has no release notes")
+	}
+	return nil
+}
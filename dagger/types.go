@@ -1,18 +1,68 @@
 package main
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
 // Platform represents a target OS and architecture for building
 type Platform struct {
-	OS   string
-	Arch string
+	OS      string
+	Arch    string
+	Variant string // Architecture variant, e.g. "v7" for 32-bit ARM
+}
+
+// String returns the platform in os/arch[/variant] form (e.g., "linux/amd64", "linux/arm/v7")
+func (p Platform) String() string {
+	if p.Variant != "" {
+		return p.OS + "/" + p.Arch + "/" + p.Variant
+	}
+	return p.OS + "/" + p.Arch
+}
+
+// archName returns the architecture as it appears in artifact names, with
+// the variant appended (e.g., "armv7")
+func (p Platform) archName() string {
+	return p.Arch + p.Variant
+}
+
+// goarm returns the GOARM value for a 32-bit ARM variant, or "" for other platforms
+func (p Platform) goarm() string {
+	if p.Arch != "arm" {
+		return ""
+	}
+	return strings.TrimPrefix(p.Variant, "v")
+}
+
+// dirname returns the build artifact subdirectory for the platform
+func (p Platform) dirname() string {
+	return fmt.Sprintf("%s_%s", p.OS, p.archName())
+}
+
+// binaryName returns the binary filename for the platform
+func (p Platform) binaryName() string {
+	if p.OS == "windows" {
+		return "shipyard.exe"
+	}
+	return "shipyard"
 }
 
 // SupportedPlatforms lists all platforms to build for
 var SupportedPlatforms = []Platform{
 	{OS: "linux", Arch: "amd64"},
 	{OS: "linux", Arch: "arm64"},
+	{OS: "linux", Arch: "arm", Variant: "v7"},
 	{OS: "darwin", Arch: "amd64"},
 	{OS: "darwin", Arch: "arm64"},
 	{OS: "windows", Arch: "amd64"},
+	{OS: "windows", Arch: "arm64"},
+}
+
+// DockerPlatforms lists the platforms published as Docker images
+var DockerPlatforms = []Platform{
+	{OS: "linux", Arch: "amd64"},
+	{OS: "linux", Arch: "arm64"},
 }
 
 // BuildInfo contains version metadata to embed in binaries
@@ -28,4 +78,91 @@ const (
 	GolangCILintVersion = "v2.12.2"
 	GosecVersion        = "v2.27.1"
 	GovulncheckVersion  = "v1.3.0"
+	SyftImage           = "anchore/syft:v1.18.1"
+	OrasImage           = "ghcr.io/oras-project/oras:v1.2.2"
 )
+
+// parsePlatform parses an os/arch[/variant] string into a supported platform
+func parsePlatform(s string) (Platform, error) {
+	name := strings.TrimSpace(s)
+	if !strings.Contains(name, "/") {
+		return Platform{}, fmt.Errorf("invalid platform %q (expected os/arch, e.g. linux/amd64)", s)
+	}
+	for _, platform := range SupportedPlatforms {
+		if platform.String() == name {
+			return platform, nil
+		}
+	}
+	supported := make([]string, len(SupportedPlatforms))
+	for i, platform := range SupportedPlatforms {
+		supported[i] = platform.String()
+	}
+	return Platform{}, fmt.Errorf("unsupported platform %q (supported: %s)", s, strings.Join(supported, ", "))
+}
+
+// selectPlatforms resolves a platform list into supported platforms.
+// An empty list selects every supported platform. The result follows the
+// order of SupportedPlatforms and contains no duplicates.
+func selectPlatforms(names []string) ([]Platform, error) {
+	if len(names) == 0 {
+		return append([]Platform{}, SupportedPlatforms...), nil
+	}
+
+	selected := make(map[Platform]bool, len(names))
+	for _, name := range names {
+		platform, err := parsePlatform(name)
+		if err != nil {
+			return nil, err
+		}
+		selected[platform] = true
+	}
+
+	var platforms []Platform
+	for _, platform := range SupportedPlatforms {
+		if selected[platform] {
+			platforms = append(platforms, platform)
+		}
+	}
+	return platforms, nil
+}
+
+// platformsInArtifacts returns the supported platforms that have a binary in a
+// build artifacts directory, given the directory's entries (e.g., "linux_amd64/")
+func platformsInArtifacts(entries []string) []Platform {
+	present := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		present[strings.TrimSuffix(entry, "/")] = true
+	}
+
+	var platforms []Platform
+	for _, platform := range SupportedPlatforms {
+		if present[platform.dirname()] {
+			platforms = append(platforms, platform)
+		}
+	}
+	return platforms
+}
+
+// formatChecksums renders checksums.txt from asset filename -> SHA256 hex.
+// Lines are sorted by filename so partial and full artifact sets are stable.
+func formatChecksums(checksums map[string]string) (string, error) {
+	if len(checksums) == 0 {
+		return "", fmt.Errorf("no archives to checksum")
+	}
+
+	filenames := make([]string, 0, len(checksums))
+	for filename := range checksums {
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
+
+	var b strings.Builder
+	for _, filename := range filenames {
+		sum := checksums[filename]
+		if len(sum) != 64 {
+			return "", fmt.Errorf("invalid checksum for %s: %q", filename, sum)
+		}
+		fmt.Fprintf(&b, "%s  %s\n", sum, filename)
+	}
+	return b.String(), nil
+}
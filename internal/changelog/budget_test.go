@@ -0,0 +1,116 @@
+package changelog
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/consignment"
+	"github.com/NatoNathan/shipyard/pkg/semver"
+	"github.com/NatoNathan/shipyard/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// budgetFixtureConsignments returns n consignments for the core package
+func budgetFixtureConsignments(n int) []*consignment.Consignment {
+	consignments := make([]*consignment.Consignment, n)
+	for i := range consignments {
+		consignments[i] = &consignment.Consignment{
+			ID:         fmt.Sprintf("c%02d", i),
+			Timestamp:  time.Now(),
+			Packages:   []string{"core"},
+			ChangeType: types.ChangeTypePatch,
+			Summary:    fmt.Sprintf("Fix edge case number %02d in the request pipeline", i),
+		}
+	}
+	return consignments
+}
+
+func budgetFixtureBumps(names ...string) map[string]VersionBump {
+	bumps := make(map[string]VersionBump)
+	for _, name := range names {
+		bumps[name] = VersionBump{
+			Package:    name,
+			OldVersion: semver.Version{Major: 1},
+			NewVersion: semver.Version{Major: 1, Patch: 1},
+			ChangeType: "patch",
+		}
+	}
+	return bumps
+}
+
+func TestGenerateCommitMessage_TruncatesToBudget(t *testing.T) {
+	consignments := budgetFixtureConsignments(50)
+	bumps := budgetFixtureBumps("core")
+
+	generator := NewChangelogGenerator()
+	full, err := generator.GenerateCommitMessage(consignments, bumps, "builtin:detailed")
+	require.NoError(t, err)
+	assert.Contains(t, full, "edge case number 49")
+	assert.NotContains(t, full, "more changes")
+
+	generator.SetMaxMessageBytes(1024)
+	truncated, err := generator.GenerateCommitMessage(consignments, bumps, "builtin:detailed")
+	require.NoError(t, err)
+
+	assert.LessOrEqual(t, len(truncated), 1024)
+	assert.Contains(t, truncated, "edge case number 00")
+	assert.NotContains(t, truncated, "edge case number 49")
+	assert.Regexp(t, `… and \d+ more changes \(see CHANGELOG\.md\)`, truncated)
+
+	// Kept and omitted changes account for every consignment
+	kept := strings.Count(truncated, "edge case number")
+	var omitted int
+	_, err = fmt.Sscanf(truncated[strings.Index(truncated, "… and ")+len("… and "):], "%d", &omitted)
+	require.NoError(t, err)
+	assert.Equal(t, 50, kept+omitted)
+}
+
+func TestGenerateCommitMessage_BudgetTooSmall(t *testing.T) {
+	generator := NewChangelogGenerator()
+	generator.SetMaxMessageBytes(10)
+
+	// Nothing fits; the fully truncated message is returned for the caller to reject
+	message, err := generator.GenerateCommitMessage(budgetFixtureConsignments(50), budgetFixtureBumps("core"), "builtin:detailed")
+	require.NoError(t, err)
+	assert.Contains(t, message, "… and 50 more changes")
+	assert.Greater(t, len(message), 10)
+}
+
+func TestGenerateCommitMessage_DefaultSubjectLength(t *testing.T) {
+	generator := NewChangelogGenerator()
+
+	short, err := generator.GenerateCommitMessage(nil, budgetFixtureBumps("api", "core"), "builtin:default")
+	require.NoError(t, err)
+	assert.Equal(t, "chore: Bump 2 package(s) [api, core]", short)
+
+	var names []string
+	for i := 0; i < 12; i++ {
+		names = append(names, fmt.Sprintf("package-%02d", i))
+	}
+	long, err := generator.GenerateCommitMessage(nil, budgetFixtureBumps(names...), "builtin:default")
+	require.NoError(t, err)
+	assert.Equal(t, "chore: Bump 12 package(s)", long)
+	assert.LessOrEqual(t, len(long), 72)
+}
+
+func TestGeneratePackageTag_TruncatesAnnotationToBudget(t *testing.T) {
+	consignments := budgetFixtureConsignments(50)
+	version := semver.Version{Major: 1, Patch: 1}
+
+	generator := NewChangelogGenerator()
+	generator.SetMaxMessageBytes(512)
+
+	for _, source := range []string{"builtin:go-annotated", "builtin:detailed-annotated"} {
+		t.Run(source, func(t *testing.T) {
+			name, message, err := generator.GeneratePackageTag(consignments, "core", version, source)
+			require.NoError(t, err)
+			assert.Equal(t, "core/v1.0.1", name)
+			assert.LessOrEqual(t, len(message), 512)
+			assert.Contains(t, message, "edge case number 00")
+			assert.Regexp(t, `… and \d+ more changes \(see CHANGELOG\.md\)`, message)
+		})
+	}
+}
@@ -2,12 +2,14 @@ package changelog
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/NatoNathan/shipyard/internal/fileutil"
 
 	"github.com/NatoNathan/shipyard/internal/consignment"
+	"github.com/NatoNathan/shipyard/internal/git"
 	"github.com/NatoNathan/shipyard/internal/history"
 	"github.com/NatoNathan/shipyard/internal/template"
 	"github.com/NatoNathan/shipyard/pkg/semver"
@@ -29,7 +31,7 @@ func ParseTagOutput(output string) (name, message string, err error) {
 
 	// Single line = lightweight tag (name only)
 	if len(lines) == 1 {
-		return lines[0], "", nil
+		return validTagName(lines[0], "")
 	}
 
 	// Multi-line = annotated tag
@@ -47,6 +49,16 @@ func ParseTagOutput(output string) (name, message string, err error) {
 		message = strings.Join(lines[2:], "\n")
 	}
 
+	return validTagName(name, message)
+}
+
+// validTagName rejects rendered tag names git would refuse or that could
+// escape refs/tags, such as a summary containing "../" interpolated into the
+// name. Names are never silently cleaned; templates can use sanitizeRef.
+func validTagName(name, message string) (string, string, error) {
+	if err := git.ValidateRefName(name); err != nil {
+		return "", "", fmt.Errorf("tag template rendered an invalid tag name: %w", err)
+	}
 	return name, message, nil
 }
 
@@ -55,6 +67,14 @@ type ChangelogGenerator struct {
 	loader           *template.TemplateLoader
 	renderer         *template.TemplateRenderer
 	preserveExisting bool
+	renderOptions    template.RenderOptions
+	maxMessageBytes  int
+	packageTemplates map[string]string
+	ecosystems       map[string]string
+	audiences        map[string]string
+	sections         []history.Section
+	channel          string
+	now              func() time.Time
 }
 
 // PackageTag represents a generated tag with name and optional message
@@ -69,9 +89,16 @@ func NewChangelogGenerator() *ChangelogGenerator {
 		loader:           template.NewTemplateLoader(),
 		renderer:         template.NewTemplateRenderer(),
 		preserveExisting: false,
+		renderOptions:    template.DefaultRenderOptions(),
+		now:              time.Now,
 	}
 }
 
+// SetClock sets the clock used for release dates in rendered output
+func (g *ChangelogGenerator) SetClock(now func() time.Time) {
+	g.now = now
+}
+
 // SetBaseDir sets the base directory for resolving template paths
 func (g *ChangelogGenerator) SetBaseDir(dir string) {
 	g.loader.SetBaseDir(dir)
@@ -82,6 +109,86 @@ func (g *ChangelogGenerator) SetPreserveExisting(preserve bool) {
 	g.preserveExisting = preserve
 }
 
+// SetRenderOptions sets how consignment summaries are normalized before
+// rendering, the repository rendered templates link into and how remote
+// templates are fetched
+func (g *ChangelogGenerator) SetRenderOptions(opts template.RenderOptions) {
+	g.renderOptions = opts
+	g.renderer.SetRepository(opts.Repository)
+	g.loader.SetRemoteOptions(opts.Remote)
+}
+
+// SetMaxMessageBytes sets the size budget for commit messages and tag
+// annotations. Zero disables truncation.
+func (g *ChangelogGenerator) SetMaxMessageBytes(maxBytes int) {
+	g.maxMessageBytes = maxBytes
+}
+
+// SetPackageTemplate sets the changelog template GenerateAll uses for one
+// package in place of the template it is given
+func (g *ChangelogGenerator) SetPackageTemplate(packageName, templateSource string) {
+	if g.packageTemplates == nil {
+		g.packageTemplates = make(map[string]string)
+	}
+	g.packageTemplates[packageName] = templateSource
+}
+
+// SetPackageEcosystems sets every configured package's ecosystem, exposed to
+// templates as .Ecosystem. More than one package makes .IsMonorepo true.
+func (g *ChangelogGenerator) SetPackageEcosystems(ecosystems map[string]string) {
+	g.ecosystems = ecosystems
+}
+
+// SetChangeTypeAudiences sets the audience of each configured change type,
+// used to build .ChangesByAudience. Unlisted types are user-facing.
+func (g *ChangelogGenerator) SetChangeTypeAudiences(audiences map[string]string) {
+	g.audiences = audiences
+}
+
+// SetChangelogSections sets the changelog sections, in order, used to build
+// .Sections. nil keeps the default sections.
+func (g *ChangelogGenerator) SetChangelogSections(sections []history.Section) {
+	g.sections = sections
+}
+
+// SetChannel sets the release channel templates see as .Channel
+func (g *ChangelogGenerator) SetChannel(channel string) {
+	g.channel = channel
+}
+
+// changesBySection lists template consignments under the changelog sections
+func (g *ChangelogGenerator) changesBySection(consignments []templateConsignment) []history.SectionChanges[templateConsignment] {
+	return history.GroupBySection(consignments, func(c templateConsignment) string {
+		return c.ChangeType
+	}, g.sections)
+}
+
+// changesByAudience splits template consignments by audience
+func (g *ChangelogGenerator) changesByAudience(consignments []templateConsignment) history.AudienceGroups[templateConsignment] {
+	return history.GroupByAudience(consignments, func(c templateConsignment) string {
+		return history.ResolveAudience(c.ChangeType, c.Metadata, g.audiences)
+	})
+}
+
+// isMonorepo reports whether more than one package is configured
+func (g *ChangelogGenerator) isMonorepo() bool {
+	return len(g.ecosystems) > 1
+}
+
+// sharedEcosystem returns the ecosystem common to all packages, or "" when
+// they differ
+func (g *ChangelogGenerator) sharedEcosystem(packages []string) string {
+	shared := ""
+	for i, pkg := range packages {
+		ecosystem := g.ecosystems[pkg]
+		if i > 0 && ecosystem != shared {
+			return ""
+		}
+		shared = ecosystem
+	}
+	return shared
+}
+
 // GenerateForPackage generates a changelog for a single package
 func (g *ChangelogGenerator) GenerateForPackage(
 	consignments []*consignment.Consignment,
@@ -109,9 +216,11 @@ func (g *ChangelogGenerator) GenerateForPackageWithTemplate(
 
 	histConsignments := make([]history.Consignment, len(filtered))
 	for i, c := range filtered {
+		summary := c.SummaryFor(packageName)
 		histConsignments[i] = history.Consignment{
 			ID:         c.ID,
-			Summary:    c.Summary,
+			Summary:    template.NormalizeSummary(summary, g.renderOptions),
+			RawSummary: summary,
 			ChangeType: string(c.ChangeType),
 			Metadata:   c.Metadata,
 		}
@@ -120,13 +229,21 @@ func (g *ChangelogGenerator) GenerateForPackageWithTemplate(
 	entry := history.Entry{
 		Package:      packageName,
 		Version:      version.String(),
-		Timestamp:    time.Now(),
+		Timestamp:    g.now(),
 		Consignments: histConsignments,
+		Ecosystem:    g.ecosystems[packageName],
+		IsMonorepo:   g.isMonorepo(),
+		Channel:      g.channel,
+
+		ChangelogSections: g.sections,
 	}
 
 	ctx := template.ChangelogContext{
 		Package:       packageName,
 		LatestVersion: version.String(),
+		Ecosystem:     entry.Ecosystem,
+		IsMonorepo:    entry.IsMonorepo,
+		Channel:       g.channel,
 		Entries:       []history.Entry{entry},
 	}
 	if version.IsPreRelease() {
@@ -143,7 +260,8 @@ func (g *ChangelogGenerator) GenerateForPackageWithTemplate(
 	return result, nil
 }
 
-// GenerateAll generates changelogs for all packages
+// GenerateAll generates changelogs for all packages, using each package's
+// template from SetPackageTemplate and templateSource for the rest
 func (g *ChangelogGenerator) GenerateAll(
 	consignments []*consignment.Consignment,
 	versions map[string]semver.Version,
@@ -152,7 +270,11 @@ func (g *ChangelogGenerator) GenerateAll(
 	results := make(map[string]string)
 
 	for pkg, ver := range versions {
-		changelog, err := g.GenerateForPackage(consignments, pkg, ver, templateSource)
+		source := templateSource
+		if override, ok := g.packageTemplates[pkg]; ok {
+			source = override
+		}
+		changelog, err := g.GenerateForPackage(consignments, pkg, ver, source)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate changelog for %s: %w", pkg, err)
 		}
@@ -180,8 +302,8 @@ func (g *ChangelogGenerator) WriteChangelogToFile(
 	if g.preserveExisting {
 		existingContent, err := fileutil.ReadFile(outputPath)
 		if err == nil {
-			// Prepend new content before existing
-			newContent = newContent + "\n" + string(existingContent)
+			// Prepend new content before existing, keeping the file LF only
+			newContent = newContent + "\n" + fileutil.NormalizeNewlines(string(existingContent))
 		}
 		// If file doesn't exist, just use new content
 	}
@@ -228,8 +350,8 @@ func (g *ChangelogGenerator) GeneratePackageTagWithContext(
 	// Build single-package context (same as changelog generation)
 	context := g.buildSinglePackageContext(packageName, version, filtered)
 
-	// Render template
-	result, err := g.renderer.Render(inlineTemplate, context)
+	// Render template, truncating the consignment list if the annotation is over budget
+	result, err := g.renderWithinBudget(inlineTemplate, context, tagMessage)
 	if err != nil {
 		return "", "", fmt.Errorf("failed to render package tag: %w", err)
 	}
@@ -273,12 +395,13 @@ func (g *ChangelogGenerator) GenerateReleaseTagWithContext(
 ) (string, string, error) {
 	// Build multi-package context for release tag
 	type Package struct {
-		Name string
+		Name      string
+		Ecosystem string
 	}
 
 	packageStructs := make([]Package, len(packages))
 	for i, pkg := range packages {
-		packageStructs[i] = Package{Name: pkg}
+		packageStructs[i] = Package{Name: pkg, Ecosystem: g.ecosystems[pkg]}
 	}
 
 	// Convert semver.Version map to string map
@@ -287,34 +410,20 @@ func (g *ChangelogGenerator) GenerateReleaseTagWithContext(
 		versionStrings[pkg] = ver.String()
 	}
 
-	// Convert consignments to template-friendly format
-	type TemplateConsignment struct {
-		ID         string
-		Timestamp  time.Time
-		Packages   []string
-		ChangeType string
-		Summary    string
-		Metadata   map[string]interface{}
-	}
-
-	templateConsignments := make([]TemplateConsignment, len(consignments))
-	for i, c := range consignments {
-		templateConsignments[i] = TemplateConsignment{
-			ID:         c.ID,
-			Timestamp:  c.Timestamp,
-			Packages:   c.Packages,
-			ChangeType: string(c.ChangeType),
-			Summary:    c.Summary,
-			Metadata:   c.Metadata,
-		}
-	}
+	templateConsignments := g.templateConsignments(consignments)
 
 	context := map[string]interface{}{
 		"Packages":     packageStructs,
 		"Versions":     versionStrings,
 		"Consignments": templateConsignments,
-		"Date":         time.Now(),
+		"Date":         g.now(),
 		"Metadata":     aggregateMetadata(consignments),
+		"Ecosystem":    g.sharedEcosystem(packages),
+		"IsMonorepo":   g.isMonorepo(),
+		"Channel":      g.channel,
+
+		"ChangesByAudience": g.changesByAudience(templateConsignments),
+		"Sections":          g.changesBySection(templateConsignments),
 	}
 
 	result, err := g.renderer.Render(inlineTemplate, context)
@@ -360,41 +469,120 @@ func (g *ChangelogGenerator) buildSinglePackageContext(
 	version semver.Version,
 	consignments []*consignment.Consignment,
 ) map[string]interface{} {
-	// Convert consignments to template-friendly format
-	type TemplateConsignment struct {
-		ID         string
-		Timestamp  time.Time
-		Packages   []string
-		ChangeType string
-		Summary    string
-		Metadata   map[string]interface{}
-	}
+	templateConsignments := g.templateConsignments(consignments)
 
-	templateConsignments := make([]TemplateConsignment, len(consignments))
-	for i, c := range consignments {
-		templateConsignments[i] = TemplateConsignment{
-			ID:         c.ID,
-			Timestamp:  c.Timestamp,
-			Packages:   c.Packages,
-			ChangeType: string(c.ChangeType),
-			Summary:    c.Summary,
-			Metadata:   c.Metadata,
-		}
-	}
-
-	now := time.Now()
+	now := g.now()
 	context := map[string]interface{}{
 		"Package":      packageName,
 		"Version":      version.String(),
+		"VersionTag":   "v" + version.String(),
+		"VersionInfo":  version, // Parsed components: .Major .Minor .Patch .PreRelease .CalVer
 		"Consignments": templateConsignments,
+		"OmittedCount": 0,
 		"Date":         now,
 		"Timestamp":    now,
 		"Metadata":     aggregateMetadata(consignments),
+		"Ecosystem":    g.ecosystems[packageName],
+		"IsMonorepo":   g.isMonorepo(),
+		"Channel":      g.channel,
+
+		"ChangesByAudience": g.changesByAudience(templateConsignments),
+		"Sections":          g.changesBySection(templateConsignments),
 	}
 
 	return context
 }
 
+// renderWithinBudget renders a commit or tag template. When the budgeted part
+// of the output (as returned by measure) exceeds the configured byte limit,
+// trailing consignments are dropped until it fits and the number dropped is
+// exposed to the template as .OmittedCount. If nothing fits, the output with
+// every consignment dropped is returned and the caller's size check reports it.
+func (g *ChangelogGenerator) renderWithinBudget(
+	tmpl string,
+	context map[string]interface{},
+	measure func(output string) (string, error),
+) (string, error) {
+	all, _ := context["Consignments"].([]templateConsignment)
+
+	render := func(keep int) (string, bool, error) {
+		context["Consignments"] = all[:keep]
+		context["OmittedCount"] = len(all) - keep
+		context["ChangesByAudience"] = g.changesByAudience(all[:keep])
+		context["Sections"] = g.changesBySection(all[:keep])
+		output, err := g.renderer.Render(tmpl, context)
+		if err != nil {
+			return "", false, err
+		}
+		budgeted, err := measure(output)
+		if err != nil {
+			return "", false, err
+		}
+		return output, g.maxMessageBytes <= 0 || len(budgeted) <= g.maxMessageBytes, nil
+	}
+
+	output, fits, err := render(len(all))
+	if err != nil || fits || len(all) == 0 {
+		return output, err
+	}
+
+	// Binary search for the largest prefix of consignments that fits
+	best, _, err := render(0)
+	if err != nil {
+		return "", err
+	}
+	lo, hi := 1, len(all)-1
+	for lo <= hi {
+		mid := (lo + hi) / 2
+		candidate, fits, err := render(mid)
+		if err != nil {
+			return "", err
+		}
+		if fits {
+			best = candidate
+			lo = mid + 1
+		} else {
+			hi = mid - 1
+		}
+	}
+	return best, nil
+}
+
+// tagMessage returns the annotation part of rendered tag output
+func tagMessage(output string) (string, error) {
+	_, message, err := ParseTagOutput(output)
+	return message, err
+}
+
+// templateConsignment is the template-friendly view of a consignment. Summary is
+// normalized for single-line rendering; RawSummary keeps the original text.
+type templateConsignment struct {
+	ID         string
+	Timestamp  time.Time
+	Packages   []string
+	ChangeType string
+	Summary    string
+	RawSummary string
+	Metadata   map[string]interface{}
+}
+
+// templateConsignments converts consignments to their template-friendly form
+func (g *ChangelogGenerator) templateConsignments(consignments []*consignment.Consignment) []templateConsignment {
+	result := make([]templateConsignment, len(consignments))
+	for i, c := range consignments {
+		result[i] = templateConsignment{
+			ID:         c.ID,
+			Timestamp:  c.Timestamp,
+			Packages:   c.Packages,
+			ChangeType: string(c.ChangeType),
+			Summary:    template.NormalizeSummary(c.Summary, g.renderOptions),
+			RawSummary: c.Summary,
+			Metadata:   c.Metadata,
+		}
+	}
+	return result
+}
+
 // filterConsignmentsForPackage filters consignments to only those affecting the specified package
 func filterConsignmentsForPackage(consignments []*consignment.Consignment, packageName string) []*consignment.Consignment {
 	var filtered []*consignment.Consignment
@@ -441,10 +629,31 @@ func (g *ChangelogGenerator) GenerateReleaseNotes(
 		return "", fmt.Errorf("failed to load template: %w", err)
 	}
 
+	return g.renderReleaseNotes(consignments, packageName, version, templateSource, templateContent)
+}
+
+// GenerateReleaseNotesWithTemplate generates release notes for a single package using an inline template
+func (g *ChangelogGenerator) GenerateReleaseNotesWithTemplate(
+	consignments []*consignment.Consignment,
+	packageName string,
+	version semver.Version,
+	inlineTemplate string,
+) (string, error) {
+	return g.renderReleaseNotes(consignments, packageName, version, "template", inlineTemplate)
+}
+
+// renderReleaseNotes renders release notes, naming the template in errors
+func (g *ChangelogGenerator) renderReleaseNotes(
+	consignments []*consignment.Consignment,
+	packageName string,
+	version semver.Version,
+	name string,
+	templateContent string,
+) (string, error) {
 	filtered := filterConsignmentsForPackage(consignments, packageName)
 	context := g.buildSinglePackageContext(packageName, version, filtered)
 
-	result, err := g.renderer.Render(templateContent, context)
+	result, err := g.renderer.RenderWithName(name, templateContent, context)
 	if err != nil {
 		return "", fmt.Errorf("failed to render release notes: %w", err)
 	}
@@ -497,56 +706,61 @@ func (g *ChangelogGenerator) GenerateCommitMessage(
 		return "", fmt.Errorf("failed to load template: %w", err)
 	}
 
+	return g.GenerateCommitMessageWithTemplate(consignments, versionBumps, templateContent)
+}
+
+// GenerateCommitMessageWithTemplate generates a commit message using an inline template
+func (g *ChangelogGenerator) GenerateCommitMessageWithTemplate(
+	consignments []*consignment.Consignment,
+	versionBumps map[string]VersionBump,
+	inlineTemplate string,
+) (string, error) {
 	// Convert version bumps to package info for template
 	type PackageInfo struct {
 		Name       string
+		Ecosystem  string
 		OldVersion string
 		NewVersion string
 		ChangeType string
 	}
 
 	packages := make([]PackageInfo, 0, len(versionBumps))
+	names := make([]string, 0, len(versionBumps))
 	for name, bump := range versionBumps {
 		packages = append(packages, PackageInfo{
 			Name:       name,
+			Ecosystem:  g.ecosystems[name],
 			OldVersion: bump.OldVersion.String(),
 			NewVersion: bump.NewVersion.String(),
 			ChangeType: bump.ChangeType,
 		})
+		names = append(names, name)
 	}
+	sort.Slice(packages, func(i, j int) bool {
+		return packages[i].Name < packages[j].Name
+	})
 
-	// Convert consignments to template-friendly format
-	type TemplateConsignment struct {
-		ID         string
-		Timestamp  time.Time
-		Packages   []string
-		ChangeType string
-		Summary    string
-		Metadata   map[string]interface{}
-	}
-
-	templateConsignments := make([]TemplateConsignment, len(consignments))
-	for i, c := range consignments {
-		templateConsignments[i] = TemplateConsignment{
-			ID:         c.ID,
-			Timestamp:  c.Timestamp,
-			Packages:   c.Packages,
-			ChangeType: string(c.ChangeType),
-			Summary:    c.Summary,
-			Metadata:   c.Metadata,
-		}
-	}
+	templateConsignments := g.templateConsignments(consignments)
 
 	// Build context
 	context := map[string]interface{}{
 		"Packages":     packages,
 		"Consignments": templateConsignments,
-		"Date":         time.Now(),
+		"OmittedCount": 0,
+		"Date":         g.now(),
 		"Metadata":     aggregateMetadata(consignments),
+		"Ecosystem":    g.sharedEcosystem(names),
+		"IsMonorepo":   g.isMonorepo(),
+		"Channel":      g.channel,
+
+		"ChangesByAudience": g.changesByAudience(templateConsignments),
+		"Sections":          g.changesBySection(templateConsignments),
 	}
 
-	// Render template
-	result, err := g.renderer.Render(templateContent, context)
+	// Render template, truncating the consignment list if the message is over budget
+	result, err := g.renderWithinBudget(inlineTemplate, context, func(output string) (string, error) {
+		return output, nil
+	})
 	if err != nil {
 		return "", fmt.Errorf("failed to render commit message: %w", err)
 	}
@@ -3,6 +3,7 @@ package changelog
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -93,6 +94,26 @@ func TestGenerateChangelog_MultiplePackages(t *testing.T) {
 	assert.NotContains(t, apiChangelog, "Core feature")
 }
 
+func TestGenerateChangelog_PackageTemplates(t *testing.T) {
+	consignments := []*consignment.Consignment{
+		{ID: "c1", Timestamp: time.Now(), Packages: []string{"core"}, ChangeType: types.ChangeTypeMinor, Summary: "Core feature"},
+		{ID: "c2", Timestamp: time.Now(), Packages: []string{"charts"}, ChangeType: types.ChangeTypePatch, Summary: "Chart fix"},
+	}
+	versions := map[string]semver.Version{
+		"core":   {Major: 1, Minor: 1},
+		"charts": {Major: 0, Minor: 1, Patch: 1},
+	}
+
+	generator := NewChangelogGenerator()
+	generator.SetPackageTemplate("charts", "builtin:keepachangelog")
+	results, err := generator.GenerateAll(consignments, versions, "builtin:default")
+
+	require.NoError(t, err)
+	assert.NotContains(t, results["core"], "Keep a Changelog")
+	assert.Contains(t, results["charts"], "Keep a Changelog")
+	assert.Contains(t, results["charts"], "Chart fix")
+}
+
 func TestGenerateChangelog_PackageFiltering(t *testing.T) {
 	now := time.Now()
 
@@ -349,6 +370,26 @@ func TestGenerateChangelog_PreserveExisting(t *testing.T) {
 	assert.Contains(t, contentStr, "1.0.1")
 }
 
+func TestGenerateChangelog_PreserveExistingCRLF(t *testing.T) {
+	tmpDir := t.TempDir()
+	changelogPath := filepath.Join(tmpDir, "CHANGELOG.md")
+	existingContent := "\uFEFF# Changelog\r\n\r\n## [1.0.0] - 2026-01-15\r\n\r\n- Initial release\r\n"
+	require.NoError(t, os.WriteFile(changelogPath, []byte(existingContent), 0644))
+
+	consignments := []*consignment.Consignment{
+		{ID: "c1", Timestamp: time.Now(), Packages: []string{"core"}, ChangeType: types.ChangeTypePatch, Summary: "New fix"},
+	}
+
+	generator := NewChangelogGenerator()
+	generator.SetPreserveExisting(true)
+	require.NoError(t, generator.WriteChangelogToFile(consignments, "core", semver.Version{Major: 1, Minor: 0, Patch: 1}, "builtin:default", changelogPath))
+
+	content, err := os.ReadFile(changelogPath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "\r")
+	assert.Contains(t, string(content), "\n- Initial release\n")
+}
+
 func TestGeneratePackageTag(t *testing.T) {
 	version := semver.Version{Major: 1, Minor: 5, Patch: 2}
 
@@ -417,6 +458,17 @@ func TestGenerateReleaseTag_Date(t *testing.T) {
 	assert.Equal(t, "", message) // Lightweight tag
 }
 
+func TestGenerateReleaseTag_Clock(t *testing.T) {
+	versions := map[string]semver.Version{"core": {Major: 1, Minor: 5, Patch: 2}}
+
+	generator := NewChangelogGenerator()
+	generator.SetClock(func() time.Time { return time.Date(2024, 3, 15, 9, 30, 0, 0, time.UTC) })
+	tagName, _, err := generator.GenerateReleaseTag(nil, []string{"core"}, versions, "builtin:date")
+
+	require.NoError(t, err)
+	assert.Equal(t, "release-20240315-093000", tagName)
+}
+
 func TestGenerateReleaseTag_Versions(t *testing.T) {
 	versions := map[string]semver.Version{
 		"core": {Major: 1, Minor: 5, Patch: 2},
@@ -517,3 +569,136 @@ func TestGeneratePackageTag_CustomTemplate(t *testing.T) {
 	assert.Equal(t, "api/v2.0.0", tagName)
 	assert.Equal(t, "", message) // Lightweight tag
 }
+
+func TestGeneratePackageTag_VersionTag(t *testing.T) {
+	version := semver.Version{Major: 2, Minor: 1, Patch: 0}
+
+	generator := NewChangelogGenerator()
+	tagName, _, err := generator.GeneratePackageTagWithContext([]*consignment.Consignment{}, "api", version, `{{ .Package }}/{{ .VersionTag }}`)
+
+	require.NoError(t, err)
+	assert.Equal(t, "api/v2.1.0", tagName)
+}
+
+func TestGeneratePackageTag_Channel(t *testing.T) {
+	version := semver.Version{Major: 1, Minor: 3, Patch: 0, PreRelease: "beta.2"}
+
+	generator := NewChangelogGenerator()
+	generator.SetChannel("beta")
+	tagName, _, err := generator.GeneratePackageTagWithContext([]*consignment.Consignment{}, "api", version, `{{ .Channel }}/{{ .VersionTag }}`)
+
+	require.NoError(t, err)
+	assert.Equal(t, "beta/v1.3.0-beta.2", tagName)
+}
+
+// TestConditionalTemplateContext checks every generated context exposes
+// .Ecosystem, .IsMonorepo and the conditional template functions
+func TestConditionalTemplateContext(t *testing.T) {
+	consignments := []*consignment.Consignment{
+		{
+			ID:         "c1",
+			Timestamp:  time.Now(),
+			Packages:   []string{"web"},
+			ChangeType: types.ChangeTypeMajor,
+			Summary:    "Drop Node 18",
+			Metadata:   map[string]interface{}{"migration": "docs/v2.md"},
+		},
+	}
+	version := semver.Version{Major: 2, Minor: 0, Patch: 0}
+	conditional := `{{ if .Ecosystem | eqEcosystem "npm" }}npm{{ else }}other{{ end }}` +
+		` {{ .IsMonorepo }} {{ hasMetadata "migration" . }} {{ anyChangeOfType "major" . }}`
+
+	generator := NewChangelogGenerator()
+	generator.SetPackageEcosystems(map[string]string{"web": "npm", "api": "go"})
+
+	t.Run("changelog", func(t *testing.T) {
+		result, err := generator.GenerateForPackageWithTemplate(consignments, "web", version, conditional)
+		require.NoError(t, err)
+		assert.Equal(t, "npm true true true", result)
+	})
+
+	t.Run("package tag", func(t *testing.T) {
+		tagName, _, err := generator.GeneratePackageTagWithContext(consignments, "web", version,
+			`{{ if .IsMonorepo }}{{ .Package }}/{{ end }}{{ .VersionTag }}{{ if eqEcosystem "npm" .Ecosystem }}-npm{{ end }}`)
+		require.NoError(t, err)
+		assert.Equal(t, "web/v2.0.0-npm", tagName)
+	})
+
+	t.Run("release notes", func(t *testing.T) {
+		result, err := generator.GenerateReleaseNotes(consignments, "web", version, "builtin:default")
+		require.NoError(t, err)
+		assert.Contains(t, result, "npm install web@2.0.0")
+	})
+
+	t.Run("release tag", func(t *testing.T) {
+		versions := map[string]semver.Version{"web": version, "api": version}
+		tagName, _, err := generator.GenerateReleaseTagWithContext(consignments, []string{"api", "web"}, versions,
+			`release{{ range .Packages }}-{{ .Ecosystem }}{{ end }}-{{ .IsMonorepo }}-{{ anyChangeOfType "major" . }}`)
+		require.NoError(t, err)
+		assert.Equal(t, "release-go-npm-true-true", tagName)
+	})
+
+	t.Run("commit", func(t *testing.T) {
+		versionBumps := map[string]VersionBump{
+			"web": {Package: "web", OldVersion: semver.Version{Major: 1}, NewVersion: version, ChangeType: "major"},
+		}
+		result, err := generator.GenerateCommitMessage(consignments, versionBumps,
+			"{{ .Ecosystem }} {{ (index .Packages 0).Ecosystem }}\n{{ .IsMonorepo }} {{ hasMetadata \"migration\" . }}")
+		require.NoError(t, err)
+		assert.Equal(t, "npm npm\ntrue true", result)
+	})
+
+	t.Run("single package project", func(t *testing.T) {
+		single := NewChangelogGenerator()
+		single.SetPackageEcosystems(map[string]string{"web": "npm"})
+		result, err := single.GenerateForPackageWithTemplate(consignments, "web", version, `{{ .Ecosystem }} {{ .IsMonorepo }}`)
+		require.NoError(t, err)
+		assert.Equal(t, "npm false", result)
+	})
+}
+
+// TestChangesByAudienceContext tests that release notes, release tag and commit
+// contexts group a shipment's consignments by audience
+func TestChangesByAudienceContext(t *testing.T) {
+	now := time.Date(2026, 1, 30, 14, 30, 0, 0, time.UTC)
+	consignments := []*consignment.Consignment{
+		{ID: "c1", Timestamp: now, Packages: []string{"web"}, ChangeType: types.ChangeTypeMinor, Summary: "Add dark mode"},
+		{ID: "c2", Timestamp: now, Packages: []string{"api"}, ChangeType: types.ChangeTypePatch, Summary: "Bump CI runners"},
+		{ID: "c3", Timestamp: now, Packages: []string{"api"}, ChangeType: types.ChangeTypePatch, Summary: "Fix login redirect",
+			Metadata: map[string]interface{}{"audience": "user"}},
+		{ID: "c4", Timestamp: now, Packages: []string{"web"}, ChangeType: types.ChangeTypeMajor, Summary: "Rewrite build pipeline",
+			Metadata: map[string]interface{}{"audience": "internal"}},
+	}
+	version := semver.Version{Major: 2}
+	audiences := `user:{{ range .ChangesByAudience.User }} {{ .ID }}{{ end }}; internal:{{ range .ChangesByAudience.Internal }} {{ .ID }}{{ end }}`
+
+	generator := NewChangelogGenerator()
+	generator.SetChangeTypeAudiences(map[string]string{"patch": "internal"})
+
+	t.Run("release tag", func(t *testing.T) {
+		versions := map[string]semver.Version{"web": version, "api": version}
+		_, message, err := generator.GenerateReleaseTagWithContext(consignments, []string{"api", "web"}, versions, "release\n\n"+audiences)
+		require.NoError(t, err)
+		assert.Equal(t, "user: c1 c3; internal: c2 c4", message)
+	})
+
+	t.Run("commit", func(t *testing.T) {
+		versionBumps := map[string]VersionBump{
+			"web": {Package: "web", OldVersion: semver.Version{Major: 1}, NewVersion: version, ChangeType: "major"},
+			"api": {Package: "api", OldVersion: semver.Version{Major: 1}, NewVersion: version, ChangeType: "major"},
+		}
+		message, err := generator.GenerateCommitMessage(consignments, versionBumps, audiences+"\n")
+		require.NoError(t, err)
+		assert.Contains(t, message, "user: c1 c3; internal: c2 c4")
+	})
+
+	t.Run("release notes", func(t *testing.T) {
+		notes, err := generator.GenerateReleaseNotes(consignments, "api", version, "builtin:audience")
+		require.NoError(t, err)
+		user, internal, found := strings.Cut(notes, "## Internal Changes")
+		require.True(t, found, notes)
+		assert.Contains(t, user, "Fix login redirect")
+		assert.Contains(t, internal, "Bump CI runners")
+		assert.NotContains(t, notes, "Add dark mode", "only the package's changes")
+	})
+}
@@ -0,0 +1,85 @@
+package changelog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/consignment"
+	"github.com/NatoNathan/shipyard/internal/git"
+	"github.com/NatoNathan/shipyard/pkg/semver"
+	"github.com/NatoNathan/shipyard/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hostileSummaries are consignment summaries crafted to break out of a tag name
+var hostileSummaries = []string{
+	"../../../etc/passwd",
+	"Fix login\n\nrefs/heads/main",
+	"v1.0.0/../../HEAD",
+	"-d",
+	"@",
+	"a@{-1}",
+	"x.lock",
+	"tab\tand\x00nul",
+	"C:\\temp\\x",
+	"ünïcödé İ",
+}
+
+// tagFromSummary renders a package tag whose name interpolates a consignment summary
+func tagFromSummary(summary, tmpl string) (string, error) {
+	consignments := []*consignment.Consignment{{
+		ID:         "c1",
+		Timestamp:  time.Date(2026, 1, 30, 0, 0, 0, 0, time.UTC),
+		Packages:   []string{"core"},
+		ChangeType: types.ChangeTypePatch,
+		Summary:    summary,
+	}}
+	name, _, err := NewChangelogGenerator().GeneratePackageTagWithContext(consignments, "core", semver.Version{Major: 1}, tmpl)
+	return name, err
+}
+
+const (
+	rawSummaryTag       = `{{ .Package }}/{{ (index .Consignments 0).Summary }}`
+	sanitizedSummaryTag = `{{ .Package }}/{{ (index .Consignments 0).Summary | sanitizeRef }}`
+)
+
+func TestGeneratePackageTag_HostileSummary(t *testing.T) {
+	for _, summary := range hostileSummaries {
+		name, err := tagFromSummary(summary, rawSummaryTag)
+		if err == nil {
+			assert.NoError(t, git.ValidateRefName(name), "%q rendered %q", summary, name)
+		}
+
+		name, err = tagFromSummary(summary, sanitizedSummaryTag)
+		if err == nil {
+			assert.NoError(t, git.ValidateRefName(name), "%q rendered %q", summary, name)
+		}
+	}
+
+	name, err := tagFromSummary("../../../etc/passwd", rawSummaryTag)
+	require.Error(t, err)
+	assert.Empty(t, name)
+	assert.Contains(t, err.Error(), `"core/../../../etc/passwd"`)
+
+	name, err = tagFromSummary("../../../etc/passwd", sanitizedSummaryTag)
+	require.NoError(t, err)
+	assert.Equal(t, "core/etc/passwd", name)
+}
+
+func FuzzGeneratePackageTag(f *testing.F) {
+	for _, summary := range hostileSummaries {
+		f.Add(summary)
+	}
+	f.Fuzz(func(t *testing.T, summary string) {
+		for _, tmpl := range []string{rawSummaryTag, sanitizedSummaryTag} {
+			name, err := tagFromSummary(summary, tmpl)
+			if err != nil {
+				continue
+			}
+			if err := git.ValidateRefName(name); err != nil {
+				t.Fatalf("summary %q rendered invalid tag %q: %v", summary, name, err)
+			}
+		}
+	})
+}
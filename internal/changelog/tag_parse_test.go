@@ -93,3 +93,12 @@ v2.0.0`
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "blank line")
 }
+
+// TestParseTagOutput_InvalidName tests that names git would reject are refused, not cleaned
+func TestParseTagOutput_InvalidName(t *testing.T) {
+	for _, output := range []string{"v1.0.0/../../HEAD", "fix: login bug", "-v1", "v1.lock\n\nmessage"} {
+		_, _, err := ParseTagOutput(output)
+		require.Error(t, err, "%q", output)
+		assert.Contains(t, err.Error(), "invalid tag name")
+	}
+}
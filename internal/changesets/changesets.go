@@ -0,0 +1,126 @@
+// Package changesets reads pending changesets written by the changesets tool
+// (github.com/changesets/changesets) so they can be imported as consignments.
+package changesets
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/NatoNathan/shipyard/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultDir is where the changesets tool keeps pending changesets
+const DefaultDir = ".changeset"
+
+// bumpNone marks a package the changeset mentions without releasing it
+const bumpNone = "none"
+
+// Release is one package a changeset releases and its bump type
+type Release struct {
+	Package string
+	Type    types.ChangeType
+}
+
+// Changeset is a parsed changeset file
+type Changeset struct {
+	Name     string    // File name without the .md extension
+	Path     string    // Path of the file it was read from
+	Releases []Release // Sorted by package name; "none" bumps are dropped
+	Summary  string    // Markdown body
+}
+
+// Parse parses a changeset: YAML frontmatter mapping package names to
+// patch, minor, major or none, followed by a markdown summary
+func Parse(name string, content []byte) (*Changeset, error) {
+	text := strings.ReplaceAll(string(content), "\r\n", "\n")
+	if !strings.HasPrefix(text, "---\n") {
+		return nil, fmt.Errorf("changeset %s: missing frontmatter", name)
+	}
+	frontmatter, body, found := strings.Cut(text[len("---\n"):], "\n---")
+	if !found {
+		// An empty changeset has nothing between the delimiters
+		if !strings.HasPrefix(text[len("---\n"):], "---") {
+			return nil, fmt.Errorf("changeset %s: unterminated frontmatter", name)
+		}
+		frontmatter, body = "", text[len("---\n---"):]
+	}
+
+	var bumps map[string]string
+	if err := yaml.Unmarshal([]byte(frontmatter), &bumps); err != nil {
+		return nil, fmt.Errorf("changeset %s: invalid frontmatter: %w", name, err)
+	}
+
+	cs := &Changeset{Name: name, Summary: strings.TrimSpace(body)}
+	for pkg, bump := range bumps {
+		bump = strings.ToLower(strings.TrimSpace(bump))
+		if bump == bumpNone {
+			continue
+		}
+		changeType := types.ChangeType(bump)
+		if err := changeType.Validate(); err != nil {
+			return nil, fmt.Errorf("changeset %s: package %s: invalid bump type %q", name, pkg, bump)
+		}
+		cs.Releases = append(cs.Releases, Release{Package: pkg, Type: changeType})
+	}
+	sort.Slice(cs.Releases, func(i, j int) bool {
+		return cs.Releases[i].Package < cs.Releases[j].Package
+	})
+	return cs, nil
+}
+
+// ReadDir reads every changeset in dir, sorted by file name. The README.md
+// the changesets tool keeps there and files other than markdown are ignored.
+func ReadDir(dir string) ([]*Changeset, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read changesets directory: %w", err)
+	}
+
+	var changesets []*Changeset
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) != ".md" || strings.EqualFold(name, "README.md") {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read changeset %s: %w", name, err)
+		}
+		cs, err := Parse(strings.TrimSuffix(name, ".md"), content)
+		if err != nil {
+			return nil, err
+		}
+		cs.Path = path
+		changesets = append(changesets, cs)
+	}
+	return changesets, nil
+}
+
+// Group is the packages a changeset releases with the same bump type
+type Group struct {
+	Type     types.ChangeType
+	Packages []string
+}
+
+// Groups groups the releases of the packages keep accepts by bump type, from
+// major down, since a consignment has one change type for all its packages
+func (c *Changeset) Groups(keep func(pkg string) bool) []Group {
+	var groups []Group
+	for _, changeType := range []types.ChangeType{types.ChangeTypeMajor, types.ChangeTypeMinor, types.ChangeTypePatch} {
+		group := Group{Type: changeType}
+		for _, r := range c.Releases {
+			if r.Type == changeType && keep(r.Package) {
+				group.Packages = append(group.Packages, r.Package)
+			}
+		}
+		if len(group.Packages) > 0 {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
@@ -0,0 +1,90 @@
+package changesets
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/NatoNathan/shipyard/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "rewrite testdata/*.golden")
+
+// render prints a changeset's releases, groups and summary for golden files
+func render(cs *Changeset) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "name: %s\n", cs.Name)
+	for _, r := range cs.Releases {
+		fmt.Fprintf(&b, "release: %s %s\n", r.Package, r.Type)
+	}
+	for _, g := range cs.Groups(func(string) bool { return true }) {
+		fmt.Fprintf(&b, "consignment: %s [%s]\n", g.Type, strings.Join(g.Packages, ", "))
+	}
+	fmt.Fprintf(&b, "summary:\n%s\n", cs.Summary)
+	return b.String()
+}
+
+func TestReadDir_Golden(t *testing.T) {
+	pending, err := ReadDir("testdata")
+	require.NoError(t, err)
+
+	var names []string
+	for _, cs := range pending {
+		names = append(names, cs.Name)
+		assert.Equal(t, filepath.Join("testdata", cs.Name+".md"), cs.Path)
+
+		t.Run(cs.Name, func(t *testing.T) {
+			golden := filepath.Join("testdata", cs.Name+".golden")
+			got := render(cs)
+			if *update {
+				require.NoError(t, os.WriteFile(golden, []byte(got), 0644))
+			}
+			want, err := os.ReadFile(golden)
+			require.NoError(t, err)
+			assert.Equal(t, string(want), got)
+		})
+	}
+
+	// README.md, config.json and the golden files are not changesets
+	assert.Equal(t, []string{"brave-dogs-dance", "empty-changeset", "quiet-owls-rest", "wild-ties-sing", "windows-line-endings"}, names)
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "no frontmatter", content: "Just a note\n", wantErr: "missing frontmatter"},
+		{name: "unterminated", content: "---\n\"web\": patch\n\nSummary\n", wantErr: "unterminated frontmatter"},
+		{name: "invalid bump", content: "---\n\"web\": huge\n---\n\nSummary\n", wantErr: `package web: invalid bump type "huge"`},
+		{name: "invalid YAML", content: "---\n- web\n---\n\nSummary\n", wantErr: "invalid frontmatter"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse("example", []byte(tt.content))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			assert.Contains(t, err.Error(), "changeset example")
+		})
+	}
+}
+
+func TestGroups_Filter(t *testing.T) {
+	cs, err := Parse("example", []byte("---\n\"a\": patch\n\"b\": minor\n\"c\": patch\n---\n\nSummary\n"))
+	require.NoError(t, err)
+
+	groups := cs.Groups(func(pkg string) bool { return pkg != "c" })
+	assert.Equal(t, []Group{
+		{Type: types.ChangeTypeMinor, Packages: []string{"b"}},
+		{Type: types.ChangeTypePatch, Packages: []string{"a"}},
+	}, groups)
+
+	assert.Empty(t, cs.Groups(func(string) bool { return false }))
+}
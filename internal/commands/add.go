@@ -2,8 +2,10 @@ package commands
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -12,6 +14,7 @@ import (
 	"github.com/NatoNathan/shipyard/internal/consignment"
 	"github.com/NatoNathan/shipyard/internal/errors"
 	"github.com/NatoNathan/shipyard/internal/git"
+	"github.com/NatoNathan/shipyard/internal/i18n"
 	"github.com/NatoNathan/shipyard/internal/metadata"
 	"github.com/NatoNathan/shipyard/internal/prompt"
 	"github.com/NatoNathan/shipyard/internal/ui"
@@ -25,10 +28,24 @@ type AddOptions struct {
 	Packages  []string
 	Type      string
 	Summary   string
+	Body      string // Optional description appended below the summary
 	Metadata  map[string]string
 	Timestamp time.Time // For testing
 	JSON      bool      // Output in JSON format
 	Quiet     bool      // Suppress output
+	AckMajor  bool      // Acknowledge the package is already queued for a major bump
+	AckYanked bool      // Acknowledge the package's latest release was yanked
+
+	// MaxSeverity overrides the level of every enabled rule (--max-severity)
+	MaxSeverity string
+
+	// PackageSummaries words the change for individual packages; the others
+	// use Summary
+	PackageSummaries map[string]string
+
+	// Confirm asks the user to proceed past a release boundary notice.
+	// Nil in non-interactive mode, where the Ack flags are required instead.
+	Confirm func(message string) (bool, error)
 }
 
 // runAdd executes the add command logic
@@ -57,7 +74,12 @@ func runAdd(projectPath string, options AddOptions) error {
 
 	// Validate summary
 	if strings.TrimSpace(options.Summary) == "" {
-		return errors.NewValidationError("summary", "summary cannot be empty")
+		return errors.NewValidationError("summary", i18n.T("add.summary_empty"))
+	}
+	for pkg := range options.PackageSummaries {
+		if !slices.Contains(options.Packages, pkg) {
+			return errors.NewValidationError("summary", i18n.T("add.package_summaries_invalid", pkg))
+		}
 	}
 
 	// Validate metadata against config if metadata validation is configured
@@ -65,6 +87,16 @@ func runAdd(projectPath string, options AddOptions) error {
 		return err
 	}
 
+	// Enforce metadata keys the changelog requires for the affected packages
+	if missing := missingRequiredMetadata(cfg, options.Packages, options.Metadata); len(missing) > 0 {
+		return errors.NewValidationError("metadata", i18n.T("add.metadata_missing", strings.Join(missing, ", ")))
+	}
+
+	// Warn before filing against a pending major or yanked release
+	if err := checkReleaseBoundaries(projectPath, cfg, options); err != nil {
+		return err
+	}
+
 	// Convert and parse metadata
 	metadataMap, err := convertMetadata(cfg, options.Metadata)
 	if err != nil {
@@ -79,27 +111,34 @@ func runAdd(projectPath string, options AddOptions) error {
 		timestamp = options.Timestamp
 	}
 
-	id, err := consignment.GenerateID(timestamp)
+	// Get consignments directory from config
+	consignmentsPath := cfg.Consignments.Path
+	if consignmentsPath == "" {
+		consignmentsPath = ".shipyard/consignments"
+	}
+	consignmentsDir := filepath.Join(projectPath, consignmentsPath)
+
+	id, err := consignment.NewIDGenerator(cfg.Consignments.IDStyle, consignmentsDir).Generate(timestamp, options.Summary)
 	if err != nil {
 		return fmt.Errorf("failed to generate consignment ID: %w", err)
 	}
+	sequence, err := consignment.NextSequence(consignmentsDir)
+	if err != nil {
+		return fmt.Errorf("failed to number consignment: %w", err)
+	}
 
 	// Create consignment
 	cons := &consignment.Consignment{
 		ID:         id,
 		Timestamp:  timestamp,
+		Sequence:   sequence,
 		Packages:   options.Packages,
 		ChangeType: types.ChangeType(options.Type),
-		Summary:    options.Summary,
+		Summary:    composeSummary(options.Summary, options.Body),
 		Metadata:   metadataMap,
-	}
 
-	// Get consignments directory from config
-	consignmentsPath := cfg.Consignments.Path
-	if consignmentsPath == "" {
-		consignmentsPath = ".shipyard/consignments"
+		PackageSummaries: options.PackageSummaries,
 	}
-	consignmentsDir := filepath.Join(projectPath, consignmentsPath)
 
 	// Write consignment file
 	if err := consignment.WriteConsignment(cons, consignmentsDir); err != nil {
@@ -108,6 +147,7 @@ func runAdd(projectPath string, options AddOptions) error {
 
 	// Output based on format flags
 	filename := fmt.Sprintf("%s.md", id)
+	relPath := filepath.ToSlash(filepath.Join(consignmentsPath, filename))
 
 	if options.JSON {
 		// JSON output
@@ -115,6 +155,7 @@ func runAdd(projectPath string, options AddOptions) error {
 			"success":  true,
 			"id":       id,
 			"filename": filename,
+			"path":     relPath,
 			"packages": options.Packages,
 			"type":     options.Type,
 			"summary":  options.Summary,
@@ -125,11 +166,12 @@ func runAdd(projectPath string, options AddOptions) error {
 	if !options.Quiet {
 		// Success message with styled output
 		fmt.Println()
-		fmt.Println(ui.SuccessMessage(fmt.Sprintf("Created consignment: %s", filename)))
+		fmt.Println(ui.SuccessMessage(i18n.T("add.created", filename)))
 		fmt.Println()
-		fmt.Println(ui.KeyValue("Packages", strings.Join(options.Packages, ", ")))
-		fmt.Println(ui.KeyValue("Type", options.Type))
-		fmt.Println(ui.KeyValue("Summary", truncateSummary(options.Summary, 60)))
+		fmt.Println(ui.KeyValue(i18n.T("label.path"), relPath))
+		fmt.Println(ui.KeyValue(i18n.T("label.packages"), strings.Join(options.Packages, ", ")))
+		fmt.Println(ui.KeyValue(i18n.T("label.type"), options.Type))
+		fmt.Println(ui.KeyValue(i18n.T("label.summary"), truncateSummary(options.Summary, 60)))
 		fmt.Println()
 	}
 
@@ -139,7 +181,7 @@ func runAdd(projectPath string, options AddOptions) error {
 // validatePackages checks that all package names exist in the configuration
 func validatePackages(cfg *config.Config, packages []string) error {
 	if len(packages) == 0 {
-		return errors.NewValidationError("packages", "at least one package is required")
+		return errors.NewValidationError("packages", i18n.T("add.packages_required"))
 	}
 
 	// Build map of valid package names
@@ -163,9 +205,9 @@ func validatePackages(cfg *config.Config, packages []string) error {
 			availablePackages = append(availablePackages, pkg.Name)
 		}
 
-		return fmt.Errorf("invalid package reference: %s\n\nAvailable packages:\n  - %s",
+		return fmt.Errorf("%s", i18n.T("add.packages_invalid",
 			strings.Join(invalidPackages, ", "),
-			strings.Join(availablePackages, "\n  - "))
+			strings.Join(availablePackages, "\n  - ")))
 	}
 
 	return nil
@@ -181,7 +223,7 @@ func validateChangeType(changeType string) error {
 	}
 
 	return errors.NewValidationError("changeType",
-		fmt.Sprintf("invalid change type: %s (valid: %s)", changeType, strings.Join(validTypes, ", ")))
+		i18n.T("add.type_invalid", changeType, strings.Join(validTypes, ", ")))
 }
 
 // convertMetadata converts string map to interface map with proper type parsing
@@ -213,6 +255,54 @@ func convertMetadata(cfg *config.Config, input map[string]string) (map[string]in
 	return result, nil
 }
 
+// composeSummary joins a summary line and an optional body into the
+// consignment's markdown body, separated by a blank line
+func composeSummary(summary, body string) string {
+	summary = strings.TrimSpace(summary)
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return summary
+	}
+	return summary + "\n\n" + body
+}
+
+// parseChangeText splits text read from stdin into a summary and body. The
+// output of `gh pr view` yields the PR title and description; otherwise the
+// first non-empty line is the summary and the rest is the body.
+func parseChangeText(text string) (string, string) {
+	if title, body, ok := splitPRView(text); ok {
+		return title, body
+	}
+
+	text = strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n"))
+	summary, body, _ := strings.Cut(text, "\n")
+	summary = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(summary), "#"))
+	return summary, strings.TrimSpace(body)
+}
+
+// splitPRView recognizes the non-TTY output of `gh pr view`: "key:\tvalue"
+// header lines, a "--" separator, then the PR description
+func splitPRView(text string) (string, string, bool) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	title := ""
+	for i, line := range lines {
+		if line == "--" {
+			if title == "" {
+				return "", "", false
+			}
+			return title, strings.TrimSpace(strings.Join(lines[i+1:], "\n")), true
+		}
+		key, value, ok := strings.Cut(line, ":\t")
+		if !ok {
+			return "", "", false
+		}
+		if key == "title" {
+			title = strings.TrimSpace(value)
+		}
+	}
+	return "", "", false
+}
+
 // truncateSummary truncates a summary to the specified length
 func truncateSummary(summary string, maxLen int) string {
 	// Get first line only
@@ -230,9 +320,43 @@ func truncateSummary(summary string, maxLen int) string {
 	return firstLine[:maxLen-3] + "..."
 }
 
+// missingRequiredMetadata returns the changelog-required metadata keys that
+// have no value for a consignment affecting the given packages
+func missingRequiredMetadata(cfg *config.Config, packages []string, input map[string]string) []string {
+	var missing []string
+	for _, key := range cfg.RequiredMetadataFor(packages) {
+		if strings.TrimSpace(input[key]) == "" {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
+
+// metadataPromptFields returns the metadata fields to prompt for: configured
+// fields, with changelog-required keys marked required and undeclared
+// required keys added as plain string fields
+func metadataPromptFields(cfg *config.Config, packages []string) []config.MetadataField {
+	required := cfg.RequiredMetadataFor(packages)
+	fields := make([]config.MetadataField, 0, len(cfg.Metadata.Fields)+len(required))
+	declared := make(map[string]bool)
+	for _, field := range cfg.Metadata.Fields {
+		declared[field.Name] = true
+		if slices.Contains(required, field.Name) {
+			field.Required = true
+		}
+		fields = append(fields, field)
+	}
+	for _, key := range required {
+		if !declared[key] {
+			fields = append(fields, config.MetadataField{Name: key, Type: "string", Required: true})
+		}
+	}
+	return fields
+}
+
 // promptForMetadata prompts for metadata fields interactively using huh
-func promptForMetadata(cfg *config.Config, existingMetadata map[string]string) (map[string]string, error) {
-	if len(cfg.Metadata.Fields) == 0 {
+func promptForMetadata(fields []config.MetadataField, existingMetadata map[string]string) (map[string]string, error) {
+	if len(fields) == 0 {
 		return existingMetadata, nil
 	}
 
@@ -244,7 +368,7 @@ func promptForMetadata(cfg *config.Config, existingMetadata map[string]string) (
 	}
 
 	// Prompt for each field
-	for _, field := range cfg.Metadata.Fields {
+	for _, field := range fields {
 		// Skip if already provided via flag
 		if _, exists := existingMetadata[field.Name]; exists {
 			continue
@@ -275,6 +399,15 @@ func promptForMetadata(cfg *config.Config, existingMetadata map[string]string) (
 	return result, nil
 }
 
+// runForm runs a huh form, using huh's accessible mode when prompts are accessible
+func runForm(form *huh.Form) error {
+	if prompt.Accessible() {
+		in, out := prompt.AccessibleIO()
+		form = form.WithAccessible(true).WithInput(in).WithOutput(out)
+	}
+	return form.Run()
+}
+
 // promptForSelect creates a select prompt for enum fields
 func promptForSelect(field config.MetadataField) (string, error) {
 	if field.Default == "" && !field.Required {
@@ -297,7 +430,7 @@ func promptForSelect(field config.MetadataField) (string, error) {
 		),
 	)
 
-	if err := form.Run(); err != nil {
+	if err := runForm(form); err != nil {
 		return "", err
 	}
 
@@ -314,11 +447,11 @@ func promptForInt(field config.MetadataField) (string, error) {
 	if field.Min != nil || field.Max != nil {
 		rangeInfo := ""
 		if field.Min != nil && field.Max != nil {
-			rangeInfo = fmt.Sprintf(" (range: %d-%d)", *field.Min, *field.Max)
+			rangeInfo = " " + i18n.T("add.field_range", *field.Min, *field.Max)
 		} else if field.Min != nil {
-			rangeInfo = fmt.Sprintf(" (min: %d)", *field.Min)
+			rangeInfo = " " + i18n.T("add.field_min", *field.Min)
 		} else {
-			rangeInfo = fmt.Sprintf(" (max: %d)", *field.Max)
+			rangeInfo = " " + i18n.T("add.field_max", *field.Max)
 		}
 		if description != "" {
 			description += rangeInfo
@@ -339,15 +472,15 @@ func promptForInt(field config.MetadataField) (string, error) {
 			// Parse integer
 			intVal, err := strconv.Atoi(strings.TrimSpace(s))
 			if err != nil {
-				return fmt.Errorf("invalid integer")
+				return fmt.Errorf("%s", i18n.T("add.field_not_integer"))
 			}
 
 			// Validate range
 			if field.Min != nil && intVal < *field.Min {
-				return fmt.Errorf("below minimum %d", *field.Min)
+				return fmt.Errorf("%s", i18n.T("add.field_below_min", *field.Min))
 			}
 			if field.Max != nil && intVal > *field.Max {
-				return fmt.Errorf("above maximum %d", *field.Max)
+				return fmt.Errorf("%s", i18n.T("add.field_above_max", *field.Max))
 			}
 
 			return nil
@@ -361,7 +494,7 @@ func promptForInt(field config.MetadataField) (string, error) {
 	}
 
 	form := huh.NewForm(huh.NewGroup(input))
-	if err := form.Run(); err != nil {
+	if err := runForm(form); err != nil {
 		return "", err
 	}
 
@@ -376,7 +509,7 @@ func promptForString(field config.MetadataField) (string, error) {
 
 	// Add pattern info to description
 	if field.Pattern != "" {
-		patternInfo := fmt.Sprintf(" (pattern: %s)", field.Pattern)
+		patternInfo := " " + i18n.T("add.field_pattern", field.Pattern)
 		if description != "" {
 			description += patternInfo
 		} else {
@@ -411,27 +544,60 @@ func promptForString(field config.MetadataField) (string, error) {
 	}
 
 	form := huh.NewForm(huh.NewGroup(input))
-	if err := form.Run(); err != nil {
+	if err := runForm(form); err != nil {
 		return "", err
 	}
 
 	return value, nil
 }
 
+// promptForPackageSummaries asks whether to word the change differently for
+// each package and, if so, for each package's summary. Packages left empty
+// use the shared summary.
+func promptForPackageSummaries(packages []string) (map[string]string, error) {
+	perPackage, err := prompt.PromptConfirm(i18n.T("add.package_summaries_confirm"), false)
+	if err != nil || !perPackage {
+		return nil, err
+	}
+
+	summaries := make(map[string]string)
+	for _, pkg := range packages {
+		summary, err := prompt.PromptTextInput(i18n.T("add.package_summary", pkg), "")
+		if err != nil {
+			return nil, err
+		}
+		if summary = strings.TrimSpace(summary); summary != "" {
+			summaries[pkg] = summary
+		}
+	}
+	return summaries, nil
+}
+
 // NewAddCommand returns the add command
 func NewAddCommand() *cobra.Command {
 	var (
-		packages []string
-		typeName string
-		summary  string
-		metadata []string
+		packages  []string
+		typeName  string
+		summary   string
+		body      string
+		bodyFile  string
+		useStdin  bool
+		metadata  []string
+		meta      []string
+		ackMajor  bool
+		ackYanked bool
+
+		fromCommits bool
+		squash      bool
+		dryRun      bool
+		deps        bool
 	)
 
 	cmd := &cobra.Command{
-		Use:                   "add [-p package]... [-t {patch|minor|major}] [-s summary] [-m key=value]...",
+		Use:                   "add [-p package]... [-t {patch|minor|major}] [-s summary] [--body text | --body-file path | --stdin] [-m key=value]...",
 		DisableFlagsInUseLine: true,
 		Aliases:               []string{"consign", "log"},
-		Short:   "Log cargo in the ship's manifest",
+		Short:                 "Log cargo in the ship's manifest",
 		Long: `Record new cargo in your ship's manifest. Each consignment documents what's
 being shipped (changes), which vessels carry it (packages), and how it affects
 the voyage (patch/minor/major). Interactive mode guides you through manifest
@@ -442,16 +608,38 @@ creation, or use flags to log cargo directly.`,
   # Non-interactive mode
   shipyard add --package core --type minor --summary "Added new feature"
 
+  # With a longer description
+  shipyard add --package core --type minor --summary "Added new feature" \
+    --body-file notes.md
+
+  # Summary and description from a pull request
+  gh pr view 42 | shipyard add --package core --type minor --stdin
+
   # Multiple packages
   shipyard add --package core --package api --type major --summary "Breaking change"
 
   # With metadata
   shipyard add --package core --type patch --summary "Fixed bug" \
-    --metadata author=dev@example.com --metadata issue=JIRA-123`,
+    --metadata author=dev@example.com --metadata issue=JIRA-123
+
+  # Link the change to its pull request
+  shipyard add --package core --type patch --summary "Fixed bug" --meta pr=42
+
+  # Acknowledge that core is already queued for a major release
+  shipyard add --package core --type minor --summary "Added option" --ack-major
+
+  # Preview consignments for the conventional commits since the last release
+  shipyard add --from-commits --dry-run
+
+  # Log them as a single consignment
+  shipyard add --from-commits --squash
+
+  # Preview consignments for the dependency updates since the last release
+  shipyard add --deps --dry-run`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			projectPath, err := os.Getwd()
+			projectPath, err := projectRoot()
 			if err != nil {
-				return fmt.Errorf("failed to get current directory: %w", err)
+				return err
 			}
 
 			// Extract global flags
@@ -459,14 +647,78 @@ creation, or use flags to log cargo directly.`,
 
 			// Parse metadata flags
 			metadataMap := make(map[string]string)
-			for _, m := range metadata {
+			for _, m := range append(metadata, meta...) {
 				parts := strings.SplitN(m, "=", 2)
 				if len(parts) != 2 {
-					return errors.NewValidationError("metadata", fmt.Sprintf("invalid metadata format: %s (expected key=value)", m))
+					return errors.NewValidationError("metadata", i18n.T("add.metadata_invalid", m))
 				}
 				metadataMap[parts[0]] = parts[1]
 			}
 
+			if deps {
+				var conflicts []string
+				for _, name := range []string{"type", "summary", "body", "body-file", "stdin", "ack-major", "ack-yanked", "squash"} {
+					if cmd.Flags().Changed(name) {
+						conflicts = append(conflicts, "--"+name)
+					}
+				}
+				if len(conflicts) > 0 {
+					return errors.NewValidationError("deps", i18n.T("add.deps_conflict", strings.Join(conflicts, ", ")))
+				}
+				return runAddDeps(projectPath, AddDepsOptions{
+					Packages: packages,
+					Metadata: metadataMap,
+					DryRun:   dryRun,
+					JSON:     globalFlags.JSON,
+					Quiet:    globalFlags.Quiet,
+				})
+			}
+
+			if fromCommits {
+				var conflicts []string
+				for _, name := range []string{"type", "summary", "body", "body-file", "stdin", "ack-major", "ack-yanked"} {
+					if cmd.Flags().Changed(name) {
+						conflicts = append(conflicts, "--"+name)
+					}
+				}
+				if len(conflicts) > 0 {
+					return errors.NewValidationError("from-commits", i18n.T("add.commits_conflict", strings.Join(conflicts, ", ")))
+				}
+				return runAddFromCommits(projectPath, AddFromCommitsOptions{
+					Packages: packages,
+					Metadata: metadataMap,
+					Squash:   squash,
+					DryRun:   dryRun,
+					JSON:     globalFlags.JSON,
+					Quiet:    globalFlags.Quiet,
+				})
+			}
+			if squash || dryRun {
+				return errors.NewValidationError("from-commits", i18n.T("add.commits_squash_only"))
+			}
+
+			// Read the description from a file or the summary and description from stdin
+			if bodyFile != "" {
+				data, err := os.ReadFile(bodyFile)
+				if err != nil {
+					return fmt.Errorf("failed to read body file: %w", err)
+				}
+				body = string(data)
+			}
+			if useStdin {
+				data, err := io.ReadAll(cmd.InOrStdin())
+				if err != nil {
+					return fmt.Errorf("failed to read stdin: %w", err)
+				}
+				if summary == "" {
+					summary, body = parseChangeText(string(data))
+				} else if _, prBody, ok := splitPRView(string(data)); ok {
+					body = prBody
+				} else {
+					body = string(data)
+				}
+			}
+
 			// Auto-select package for single-package repos
 			if len(packages) == 0 {
 				cfg, loadErr := config.LoadFromDir(projectPath)
@@ -475,37 +727,82 @@ creation, or use flags to log cargo directly.`,
 				}
 			}
 
+			// Stdin is consumed, so nothing can be prompted for
+			if useStdin {
+				var missing []string
+				if len(packages) == 0 {
+					missing = append(missing, "--package")
+				}
+				if typeName == "" {
+					missing = append(missing, "--type")
+				}
+				if summary == "" {
+					missing = append(missing, i18n.T("add.stdin_summary"))
+				}
+				if len(missing) > 0 {
+					return errors.NewValidationError("stdin", i18n.T("add.stdin_missing", strings.Join(missing, ", ")))
+				}
+			}
+
 			// Check if we have all required flags for non-interactive mode
 			if len(packages) > 0 && typeName != "" && summary != "" {
 				// Non-interactive mode
 				return runAdd(projectPath, AddOptions{
-					Packages: packages,
-					Type:     typeName,
-					Summary:  summary,
-					Metadata: metadataMap,
-					JSON:     globalFlags.JSON,
-					Quiet:    globalFlags.Quiet,
+					Packages:  packages,
+					Type:      typeName,
+					Summary:   summary,
+					Body:      body,
+					Metadata:  metadataMap,
+					JSON:      globalFlags.JSON,
+					Quiet:     globalFlags.Quiet,
+					AckMajor:  ackMajor,
+					AckYanked: ackYanked,
+
+					MaxSeverity: globalFlags.MaxSeverity,
 				})
 			}
 
 			// Interactive mode: prompt for missing fields
-			return runInteractiveAdd(projectPath, packages, typeName, summary, metadataMap, globalFlags)
+			return runInteractiveAdd(projectPath, packages, typeName, summary, metadataMap, AddOptions{
+				Body:      body,
+				JSON:      globalFlags.JSON,
+				Quiet:     globalFlags.Quiet,
+				AckMajor:  ackMajor,
+				AckYanked: ackYanked,
+
+				MaxSeverity: globalFlags.MaxSeverity,
+			})
 		},
 	}
 
 	cmd.Flags().StringSliceVarP(&packages, "package", "p", nil, "package name(s) affected by this change")
 	cmd.Flags().StringVarP(&typeName, "type", "t", "", "change type: patch, minor, or major")
 	cmd.Flags().StringVarP(&summary, "summary", "s", "", "summary of the change")
+	cmd.Flags().StringVar(&body, "body", "", "description of the change, written below the summary")
+	cmd.Flags().StringVar(&bodyFile, "body-file", "", "read the description of the change from a file")
+	cmd.Flags().BoolVar(&useStdin, "stdin", false, "read the summary and description from stdin (first line is the summary)")
 	cmd.Flags().StringSliceVarP(&metadata, "metadata", "m", nil, "metadata in key=value format (can be repeated)")
+	cmd.Flags().StringSliceVar(&meta, "meta", nil, "alias for --metadata")
+	cmd.Flags().BoolVar(&ackMajor, "ack-major", false, "acknowledge the package is already queued for a major bump")
+	cmd.Flags().BoolVar(&ackYanked, "ack-yanked", false, "acknowledge the package's latest release was yanked")
+
+	cmd.Flags().BoolVar(&fromCommits, "from-commits", false, "create consignments from conventional commits since each package's last release tag")
+	cmd.Flags().BoolVar(&squash, "squash", false, "with --from-commits, create one consignment for all commits")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "with --from-commits or --deps, show the consignments without writing them")
+	cmd.Flags().BoolVar(&deps, "deps", false, "create a consignment per package for the dependency updates since its last release tag")
 
-	// Register package name completion
+	cmd.MarkFlagsMutuallyExclusive("body", "body-file", "stdin")
+	cmd.MarkFlagsMutuallyExclusive("from-commits", "deps")
+
+	// Register package name and change type completion
 	RegisterPackageCompletions(cmd, "package")
+	RegisterChangeTypeCompletions(cmd, "type")
 
 	return cmd
 }
 
 // runInteractiveAdd runs the add command in interactive mode
-func runInteractiveAdd(projectPath string, packages []string, typeName, summary string, metadata map[string]string, globalFlags GlobalFlags) error {
+func runInteractiveAdd(projectPath string, packages []string, typeName, summary string, metadata map[string]string, options AddOptions) error {
 	// Load config to get available packages
 	cfg, err := config.LoadFromDir(projectPath)
 	if err != nil {
@@ -550,19 +847,29 @@ func runInteractiveAdd(projectPath string, packages []string, typeName, summary
 		}
 	}
 
+	// Offer to word the change for each package when it spans several
+	var packageSummaries map[string]string
+	if len(packages) > 1 {
+		packageSummaries, err = promptForPackageSummaries(packages)
+		if err != nil {
+			return fmt.Errorf("failed to get package summaries: %w", err)
+		}
+	}
+
 	// Prompt for metadata fields if configured
-	metadata, err = promptForMetadata(cfg, metadata)
+	metadata, err = promptForMetadata(metadataPromptFields(cfg, packages), metadata)
 	if err != nil {
 		return fmt.Errorf("failed to collect metadata: %w", err)
 	}
 
 	// Run the add command
-	return runAdd(projectPath, AddOptions{
-		Packages: packages,
-		Type:     string(changeType),
-		Summary:  summary,
-		Metadata: metadata,
-		JSON:     globalFlags.JSON,
-		Quiet:    globalFlags.Quiet,
-	})
+	options.Packages = packages
+	options.Type = string(changeType)
+	options.Summary = summary
+	options.Metadata = metadata
+	options.PackageSummaries = packageSummaries
+	options.Confirm = func(message string) (bool, error) {
+		return prompt.PromptConfirm(message, true)
+	}
+	return runAdd(projectPath, options)
 }
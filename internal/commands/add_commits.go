@@ -0,0 +1,373 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/changelog"
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/consignment"
+	"github.com/NatoNathan/shipyard/internal/conventional"
+	"github.com/NatoNathan/shipyard/internal/errors"
+	"github.com/NatoNathan/shipyard/internal/git"
+	"github.com/NatoNathan/shipyard/internal/i18n"
+	"github.com/NatoNathan/shipyard/internal/release"
+	"github.com/NatoNathan/shipyard/internal/ui"
+	"github.com/NatoNathan/shipyard/pkg/semver"
+	"github.com/NatoNathan/shipyard/pkg/types"
+)
+
+// Metadata keys recording the commits a consignment was created from, so a
+// later scan skips them
+const (
+	commitMetadataKey  = "commit"
+	commitsMetadataKey = "commits"
+)
+
+// AddFromCommitsOptions holds the options for add --from-commits
+type AddFromCommitsOptions struct {
+	Packages []string          // Only attribute commits to these packages; all by default
+	Metadata map[string]string // Added to every consignment
+	Squash   bool              // One consignment for all commits instead of one per commit
+	DryRun   bool              // Report the consignments without writing them
+	JSON     bool
+	Quiet    bool
+}
+
+// AddFromCommitsOutput is the JSON output structure for add --from-commits
+type AddFromCommitsOutput struct {
+	DryRun       bool                `json:"dryRun,omitempty"`
+	Consignments []CommitConsignment `json:"consignments"`
+	Skipped      []SkippedCommit     `json:"skipped,omitempty"`
+}
+
+// CommitConsignment is a consignment created from one or more commits
+type CommitConsignment struct {
+	ID       string           `json:"id,omitempty"`
+	Commits  []string         `json:"commits"`
+	Packages []string         `json:"packages"`
+	Type     types.ChangeType `json:"type"`
+	Summary  string           `json:"summary"`
+
+	body      string
+	timestamp time.Time
+}
+
+// SkippedCommit is a commit touching a package that produced no consignment
+type SkippedCommit struct {
+	Commit  string `json:"commit"`
+	Subject string `json:"subject"`
+	Reason  string `json:"reason"`
+}
+
+// runAddFromCommits creates consignments from the conventional commits made
+// since each package's last release tag
+func runAddFromCommits(projectPath string, opts AddFromCommitsOptions) (err error) {
+	isGitRepo, err := git.IsRepository(projectPath)
+	if err != nil || !isGitRepo {
+		return errors.NewGitError("not a git repository", nil)
+	}
+
+	cfg, err := config.LoadFromDir(projectPath)
+	if err != nil {
+		return errors.NewConfigError("failed to load configuration", err)
+	}
+
+	scope := cfg.Packages
+	if len(opts.Packages) > 0 {
+		if err := validatePackages(cfg, opts.Packages); err != nil {
+			return err
+		}
+		scope = nil
+		for _, pkg := range cfg.Packages {
+			if slices.Contains(opts.Packages, pkg.Name) {
+				scope = append(scope, pkg)
+			}
+		}
+	}
+
+	commits, packagesByCommit, err := commitsSinceRelease(projectPath, cfg, scope)
+	if err != nil {
+		return err
+	}
+
+	consignmentsPath := cfg.Consignments.Path
+	if consignmentsPath == "" {
+		consignmentsPath = ".shipyard/consignments"
+	}
+	consignmentsDir := filepath.Join(projectPath, consignmentsPath)
+	recorded, err := recordedCommits(consignmentsDir)
+	if err != nil {
+		return err
+	}
+
+	output := AddFromCommitsOutput{DryRun: opts.DryRun, Consignments: []CommitConsignment{}}
+	for _, commit := range commits {
+		if recorded[commit.Hash] {
+			continue
+		}
+		subject, _, _ := strings.Cut(strings.TrimSpace(commit.Message), "\n")
+		parsed, ok := conventional.Parse(commit.Message)
+		if !ok {
+			output.Skipped = append(output.Skipped, SkippedCommit{Commit: commit.Hash, Subject: subject, Reason: i18n.T("add.commits_not_conventional")})
+			continue
+		}
+		changeType, ok := parsed.ChangeType()
+		if !ok {
+			output.Skipped = append(output.Skipped, SkippedCommit{Commit: commit.Hash, Subject: subject, Reason: i18n.T("add.commits_no_release", parsed.Type)})
+			continue
+		}
+		output.Consignments = append(output.Consignments, CommitConsignment{
+			Commits:   []string{commit.Hash},
+			Packages:  packagesByCommit[commit.Hash],
+			Type:      changeType,
+			Summary:   parsed.Description,
+			body:      parsed.Body,
+			timestamp: commit.When.UTC(),
+		})
+	}
+	if opts.Squash && len(output.Consignments) > 1 {
+		output.Consignments = []CommitConsignment{squashCommitConsignments(output.Consignments)}
+	}
+
+	for _, cons := range output.Consignments {
+		if missing := missingRequiredMetadata(cfg, cons.Packages, opts.Metadata); len(missing) > 0 {
+			return errors.NewValidationError("metadata", i18n.T("add.metadata_missing", strings.Join(missing, ", ")))
+		}
+	}
+	metadataMap, err := convertMetadata(cfg, opts.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to convert metadata: %w", err)
+	}
+
+	if !opts.DryRun {
+		tx := release.NewFileTransaction()
+		defer func() {
+			if err != nil {
+				if rollbackErr := tx.Rollback(); rollbackErr != nil {
+					err = fmt.Errorf("%w; additionally failed to roll back: %v", err, rollbackErr)
+				}
+			}
+		}()
+
+		ids := consignment.NewIDGenerator(cfg.Consignments.IDStyle, consignmentsDir)
+		sequence, err := consignment.NextSequence(consignmentsDir)
+		if err != nil {
+			return fmt.Errorf("failed to number consignments: %w", err)
+		}
+		for i := range output.Consignments {
+			cons := &output.Consignments[i]
+			id, err := ids.Generate(cons.timestamp, cons.Summary)
+			if err != nil {
+				return fmt.Errorf("failed to generate consignment ID: %w", err)
+			}
+			if err := tx.Backup(filepath.Join(consignmentsDir, id+".md")); err != nil {
+				return err
+			}
+			if err := consignment.WriteConsignment(&consignment.Consignment{
+				ID:         id,
+				Timestamp:  cons.timestamp,
+				Sequence:   sequence + i,
+				Packages:   cons.Packages,
+				ChangeType: cons.Type,
+				Summary:    composeSummary(cons.Summary, cons.body),
+				Metadata:   commitMetadata(metadataMap, cons.Commits),
+			}, consignmentsDir); err != nil {
+				return fmt.Errorf("failed to write consignment: %w", err)
+			}
+			cons.ID = id
+		}
+	}
+
+	if opts.JSON {
+		return PrintJSON(os.Stdout, output)
+	}
+	if opts.Quiet {
+		return nil
+	}
+
+	fmt.Println()
+	for _, cons := range output.Consignments {
+		hashes := make([]string, len(cons.Commits))
+		for i, hash := range cons.Commits {
+			hashes[i] = shortHash(hash)
+		}
+		fmt.Println(ui.KeyValue(strings.Join(hashes, ", "),
+			fmt.Sprintf("%s [%s] %s", cons.Type, strings.Join(cons.Packages, ", "), truncateSummary(cons.Summary, 60))))
+	}
+	for _, skipped := range output.Skipped {
+		fmt.Println(ui.Dimmed(i18n.T("add.commits_skipped", shortHash(skipped.Commit), truncateSummary(skipped.Subject, 60), skipped.Reason)))
+	}
+	switch {
+	case len(output.Consignments) == 0:
+		fmt.Println(ui.InfoMessage(i18n.T("add.commits_none")))
+	case opts.DryRun:
+		fmt.Println(ui.InfoMessage(i18n.T("add.commits_dry_run", len(output.Consignments))))
+	default:
+		fmt.Println(ui.SuccessMessage(i18n.T("add.commits_created", len(output.Consignments))))
+	}
+	fmt.Println()
+	return nil
+}
+
+// commitsSinceRelease returns the commits since the last release tag of any
+// package in scope, oldest first, and the packages each one touches. A
+// package's last release is the tag its tag template renders for its current
+// version; without that tag its whole history counts. Files belong to the
+// package with the most specific path, so a root package does not claim
+// files of packages nested inside it.
+func commitsSinceRelease(projectPath string, cfg *config.Config, scope []config.Package) ([]git.LogEntry, map[string][]string, error) {
+	versions, err := release.ReadAllCurrentVersions(projectPath, cfg, release.SettingsFor(projectPath, cfg))
+	if err != nil {
+		return nil, nil, err
+	}
+	generator := changelog.NewChangelogGenerator()
+	generator.SetBaseDir(projectPath)
+
+	byTag := make(map[string][]git.LogEntry)
+	packagesByCommit := make(map[string][]string)
+	entries := make(map[string]git.LogEntry)
+	for _, pkg := range scope {
+		since, err := lastReleaseTag(projectPath, cfg, generator, pkg, versions[pkg.Name])
+		if err != nil {
+			return nil, nil, err
+		}
+
+		commits, cached := byTag[since]
+		if !cached {
+			if commits, err = git.CommitsSince(projectPath, since); err != nil {
+				return nil, nil, err
+			}
+			byTag[since] = commits
+		}
+
+		for _, commit := range commits {
+			for _, file := range commit.Files {
+				if owner, ok := cfg.PackageForPath(file); ok && owner == pkg.Name {
+					packagesByCommit[commit.Hash] = append(packagesByCommit[commit.Hash], pkg.Name)
+					entries[commit.Hash] = commit
+					break
+				}
+			}
+		}
+	}
+
+	// Scans overlap; take each commit once, longest scan first so commits
+	// made within the same second keep their history order
+	scans := make([][]git.LogEntry, 0, len(byTag))
+	for _, commits := range byTag {
+		scans = append(scans, commits)
+	}
+	sort.SliceStable(scans, func(i, j int) bool { return len(scans[i]) > len(scans[j]) })
+	var commits []git.LogEntry
+	for _, scan := range scans {
+		for _, commit := range scan {
+			if _, ok := entries[commit.Hash]; ok {
+				commits = append(commits, commit)
+				delete(entries, commit.Hash)
+			}
+		}
+	}
+	sort.SliceStable(commits, func(i, j int) bool { return commits[i].When.Before(commits[j].When) })
+	for _, commit := range commits {
+		sort.Strings(packagesByCommit[commit.Hash])
+	}
+	return commits, packagesByCommit, nil
+}
+
+// lastReleaseTag returns the tag the package's tag template renders for its
+// current version, or "" when that tag does not exist
+func lastReleaseTag(projectPath string, cfg *config.Config, generator *changelog.ChangelogGenerator, pkg config.Package, current semver.Version) (string, error) {
+	tagName, _, err := release.PackageTag(generator, cfg, pkg, nil, current)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate tag for package %s: %w", pkg.Name, err)
+	}
+	exists, err := git.VerifyTagExists(projectPath, tagName)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return "", nil
+	}
+	return tagName, nil
+}
+
+// squashCommitConsignments merges consignments into one covering all their
+// packages at the highest change type, listing each commit's description
+func squashCommitConsignments(consignments []CommitConsignment) CommitConsignment {
+	squashed := CommitConsignment{Type: types.ChangeTypePatch}
+	packages := make(map[string]bool)
+	var lines []string
+	for _, cons := range consignments {
+		squashed.Commits = append(squashed.Commits, cons.Commits...)
+		for _, pkg := range cons.Packages {
+			packages[pkg] = true
+		}
+		if cons.Type.Priority() > squashed.Type.Priority() {
+			squashed.Type = cons.Type
+		}
+		if cons.timestamp.After(squashed.timestamp) {
+			squashed.timestamp = cons.timestamp
+		}
+		lines = append(lines, "- "+cons.Summary)
+	}
+	for pkg := range packages {
+		squashed.Packages = append(squashed.Packages, pkg)
+	}
+	sort.Strings(squashed.Packages)
+	squashed.Summary = strings.Join(lines, "\n")
+	return squashed
+}
+
+// commitMetadata adds the source commits to a consignment's metadata
+func commitMetadata(metadata map[string]interface{}, commits []string) map[string]interface{} {
+	result := make(map[string]interface{}, len(metadata)+1)
+	for k, v := range metadata {
+		result[k] = v
+	}
+	if len(commits) == 1 {
+		result[commitMetadataKey] = commits[0]
+		return result
+	}
+	list := make([]interface{}, len(commits))
+	for i, hash := range commits {
+		list[i] = hash
+	}
+	result[commitsMetadataKey] = list
+	return result
+}
+
+// recordedCommits returns the commits pending consignments were created from
+func recordedCommits(consignmentsDir string) (map[string]bool, error) {
+	pending, err := readAllConsignments(consignmentsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read consignments: %w", err)
+	}
+	recorded := make(map[string]bool)
+	for _, cons := range pending {
+		if hash, ok := cons.Metadata[commitMetadataKey].(string); ok {
+			recorded[hash] = true
+		}
+		if hashes, ok := cons.Metadata[commitsMetadataKey].([]interface{}); ok {
+			for _, hash := range hashes {
+				if s, ok := hash.(string); ok {
+					recorded[s] = true
+				}
+			}
+		}
+	}
+	return recorded, nil
+}
+
+// shortHash abbreviates a commit hash for display
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
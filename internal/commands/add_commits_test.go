@@ -0,0 +1,178 @@
+package commands
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/consignment"
+	"github.com/NatoNathan/shipyard/internal/git"
+	"github.com/NatoNathan/shipyard/pkg/types"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupCommitsRepo creates a project with core and api packages, releases
+// core 1.0.0 and then makes a mix of conventional and other commits. It
+// returns the project path and the hashes of the commits by subject.
+func setupCommitsRepo(t *testing.T) (string, map[string]string) {
+	t.Helper()
+	tempDir := t.TempDir()
+	repo, err := gogit.PlainInit(tempDir, false)
+	require.NoError(t, err)
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+
+	when := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	hashes := make(map[string]string)
+	commit := func(message string, files map[string]string) {
+		for name, content := range files {
+			path := filepath.Join(tempDir, name)
+			require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+			require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+			_, err := wt.Add(name)
+			require.NoError(t, err)
+		}
+		hash, err := wt.Commit(message, &gogit.CommitOptions{
+			Author: &object.Signature{Name: "Test", Email: "test@example.com", When: when},
+		})
+		require.NoError(t, err)
+		hashes[message] = hash.String()
+		when = when.Add(time.Hour)
+	}
+
+	commit("chore: initial import", map[string]string{
+		".shipyard/shipyard.yaml": `packages:
+  - name: core
+    path: ./core
+    ecosystem: npm
+  - name: api
+    path: ./api
+    ecosystem: npm
+templates:
+  tagName:
+    source: builtin:npm
+consignments:
+  path: .shipyard/consignments
+history:
+  path: .shipyard/history.json
+`,
+		"core/package.json": `{"name": "core", "version": "1.0.0"}`,
+		"api/package.json":  `{"name": "api", "version": "0.3.0"}`,
+		"core/index.js":     "export {}\n",
+		"api/server.js":     "listen()\n",
+	})
+	commit("feat(core): add legacy export", map[string]string{"core/index.js": "export const legacy = 1\n"})
+	require.NoError(t, git.CreateLightweightTag(tempDir, "core@1.0.0"))
+
+	commit("feat(core): add CSV export", map[string]string{"core/index.js": "export const csv = 1\n"})
+	commit("fix: handle empty pages\n\nThe list endpoint returned null.", map[string]string{"api/server.js": "listen({ empty: [] })\n"})
+	commit("refactor!: rename options", map[string]string{
+		"core/index.js": "export const csv = 2\n",
+		"api/server.js": "listen({ pages: [] })\n",
+	})
+	commit("docs: update readme", map[string]string{"README.md": "# Project\n"})
+	commit("Update dependencies", map[string]string{"api/package.json": `{"name": "api", "version": "0.3.0", "dependencies": {}}`})
+	commit("chore(core): lint", map[string]string{"core/index.js": "export const csv = 2;\n"})
+
+	return tempDir, hashes
+}
+
+func readCommitConsignments(t *testing.T, projectPath string) []*consignment.Consignment {
+	t.Helper()
+	all, err := consignment.ReadAllConsignments(filepath.Join(projectPath, ".shipyard", "consignments"))
+	require.NoError(t, err)
+	sort.Slice(all, func(i, j int) bool { return all[i].Timestamp.Before(all[j].Timestamp) })
+	return all
+}
+
+func TestAddFromCommits(t *testing.T) {
+	tempDir, hashes := setupCommitsRepo(t)
+
+	output := captureOutput(func() {
+		require.NoError(t, runAddFromCommits(tempDir, AddFromCommitsOptions{}))
+	})
+	assert.Contains(t, output, "Created 3 consignment(s) from commits")
+	assert.Contains(t, output, "not a conventional commit")
+
+	created := readCommitConsignments(t, tempDir)
+	require.Len(t, created, 3, "only feat, fix and breaking commits after each package's last release")
+
+	assert.Equal(t, []string{"core"}, created[0].Packages)
+	assert.Equal(t, types.ChangeTypeMinor, created[0].ChangeType)
+	assert.Equal(t, "add CSV export", created[0].Summary)
+	assert.Equal(t, hashes["feat(core): add CSV export"], created[0].Metadata["commit"])
+
+	assert.Equal(t, []string{"api"}, created[1].Packages)
+	assert.Equal(t, types.ChangeTypePatch, created[1].ChangeType)
+	assert.Equal(t, "handle empty pages\n\nThe list endpoint returned null.", created[1].Summary)
+	assert.True(t, time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC).Equal(created[1].Timestamp), "dated by the commit")
+
+	assert.Equal(t, []string{"api", "core"}, created[2].Packages, "attributed by the files it touches")
+	assert.Equal(t, types.ChangeTypeMajor, created[2].ChangeType)
+
+	// A second scan skips commits that already have a consignment
+	output = captureOutput(func() {
+		require.NoError(t, runAddFromCommits(tempDir, AddFromCommitsOptions{}))
+	})
+	assert.Contains(t, output, "No new conventional commits to log")
+	assert.Len(t, readCommitConsignments(t, tempDir), 3)
+}
+
+func TestAddFromCommits_UnreleasedPackageScansAllHistory(t *testing.T) {
+	tempDir, hashes := setupCommitsRepo(t)
+
+	var output AddFromCommitsOutput
+	stdout := captureOutput(func() {
+		require.NoError(t, runAddFromCommits(tempDir, AddFromCommitsOptions{Packages: []string{"api"}, DryRun: true, JSON: true}))
+	})
+	require.NoError(t, json.Unmarshal([]byte(stdout), &output))
+
+	require.Len(t, output.Consignments, 2)
+	assert.Equal(t, []string{"api"}, output.Consignments[0].Packages)
+	assert.Equal(t, []string{"api"}, output.Consignments[1].Packages, "core is out of scope")
+
+	// api has no core@-style release tag, so its initial commit is scanned too
+	var skipped []string
+	for _, s := range output.Skipped {
+		skipped = append(skipped, s.Commit)
+	}
+	assert.Equal(t, []string{hashes["chore: initial import"], hashes["Update dependencies"]}, skipped)
+	assert.NotContains(t, skipped, hashes["feat(core): add legacy export"])
+}
+
+func TestAddFromCommits_Squash(t *testing.T) {
+	tempDir, hashes := setupCommitsRepo(t)
+
+	captureOutput(func() {
+		require.NoError(t, runAddFromCommits(tempDir, AddFromCommitsOptions{Squash: true}))
+	})
+
+	created := readCommitConsignments(t, tempDir)
+	require.Len(t, created, 1)
+	assert.Equal(t, []string{"api", "core"}, created[0].Packages)
+	assert.Equal(t, types.ChangeTypeMajor, created[0].ChangeType, "the highest change type wins")
+	assert.Equal(t, "- add CSV export\n- handle empty pages\n- rename options", created[0].Summary)
+	assert.Equal(t, []interface{}{
+		hashes["feat(core): add CSV export"],
+		hashes["fix: handle empty pages\n\nThe list endpoint returned null."],
+		hashes["refactor!: rename options"],
+	}, created[0].Metadata["commits"])
+}
+
+func TestAddFromCommits_DryRun(t *testing.T) {
+	tempDir, _ := setupCommitsRepo(t)
+
+	output := captureOutput(func() {
+		require.NoError(t, runAddFromCommits(tempDir, AddFromCommitsOptions{DryRun: true}))
+	})
+	assert.Contains(t, output, "Would create 3 consignment(s)")
+	assert.Contains(t, output, "minor [core] add CSV export")
+	assert.Contains(t, output, "major [api, core] rename options")
+	assert.NoDirExists(t, filepath.Join(tempDir, ".shipyard", "consignments"))
+}
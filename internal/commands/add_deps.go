@@ -0,0 +1,317 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/changelog"
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/consignment"
+	"github.com/NatoNathan/shipyard/internal/ecosystem"
+	"github.com/NatoNathan/shipyard/internal/errors"
+	"github.com/NatoNathan/shipyard/internal/git"
+	"github.com/NatoNathan/shipyard/internal/i18n"
+	"github.com/NatoNathan/shipyard/internal/release"
+	"github.com/NatoNathan/shipyard/internal/ui"
+	"github.com/NatoNathan/shipyard/pkg/types"
+)
+
+// dependenciesMetadataKey records the dependency versions a consignment was
+// created for, as name@version, so a later scan skips them
+const dependenciesMetadataKey = "dependencies"
+
+// AddDepsOptions holds the options for add --deps
+type AddDepsOptions struct {
+	Packages  []string          // Only scan these packages; all by default
+	Metadata  map[string]string // Added to every consignment
+	DryRun    bool              // Report the consignments without writing them
+	Timestamp time.Time         // For testing
+	JSON      bool
+	Quiet     bool
+}
+
+// AddDepsOutput is the JSON output structure for add --deps
+type AddDepsOutput struct {
+	DryRun       bool                    `json:"dryRun,omitempty"`
+	Consignments []DependencyConsignment `json:"consignments"`
+	Skipped      []SkippedPackage        `json:"skipped,omitempty"`
+}
+
+// DependencyConsignment is a consignment logging a package's dependency
+// updates since its last release
+type DependencyConsignment struct {
+	ID       string                       `json:"id,omitempty"`
+	Package  string                       `json:"package"`
+	Since    string                       `json:"since"` // Release tag the manifest is compared with
+	Manifest string                       `json:"manifest"`
+	Type     types.ChangeType             `json:"type"`
+	Summary  string                       `json:"summary"`
+	Updates  []ecosystem.DependencyUpdate `json:"updates"`
+}
+
+// SkippedPackage is a package whose dependencies could not be compared
+type SkippedPackage struct {
+	Package string `json:"package"`
+	Reason  string `json:"reason"`
+}
+
+// runAddDeps creates a patch consignment per package listing the
+// dependencies its manifest changed since the package's last release tag.
+// Updates matching dependencyUpdates.changeTypes escalate the change type.
+func runAddDeps(projectPath string, opts AddDepsOptions) (err error) {
+	isGitRepo, err := git.IsRepository(projectPath)
+	if err != nil || !isGitRepo {
+		return errors.NewGitError("not a git repository", nil)
+	}
+
+	cfg, err := config.LoadFromDir(projectPath)
+	if err != nil {
+		return errors.NewConfigError("failed to load configuration", err)
+	}
+
+	scope := cfg.Packages
+	if len(opts.Packages) > 0 {
+		if err := validatePackages(cfg, opts.Packages); err != nil {
+			return err
+		}
+		scope = nil
+		for _, pkg := range cfg.Packages {
+			if slices.Contains(opts.Packages, pkg.Name) {
+				scope = append(scope, pkg)
+			}
+		}
+	}
+
+	consignmentsPath := cfg.Consignments.Path
+	if consignmentsPath == "" {
+		consignmentsPath = ".shipyard/consignments"
+	}
+	consignmentsDir := filepath.Join(projectPath, consignmentsPath)
+	pending, err := readAllConsignments(consignmentsDir)
+	if err != nil {
+		return fmt.Errorf("failed to read consignments: %w", err)
+	}
+
+	versions, err := release.ReadAllCurrentVersions(projectPath, cfg, release.SettingsFor(projectPath, cfg))
+	if err != nil {
+		return err
+	}
+	generator := changelog.NewChangelogGenerator()
+	generator.SetBaseDir(projectPath)
+
+	output := AddDepsOutput{DryRun: opts.DryRun, Consignments: []DependencyConsignment{}}
+	changedSince := make(map[string][]string)
+	for _, pkg := range scope {
+		manifestName, ok := ecosystem.DependencyManifest(pkg.Ecosystem)
+		if !ok {
+			output.Skipped = append(output.Skipped, SkippedPackage{Package: pkg.Name, Reason: i18n.T("add.deps_unsupported", pkg.Ecosystem)})
+			continue
+		}
+		manifest := path.Join(strings.TrimPrefix(path.Clean(filepath.ToSlash(pkg.Path)), "./"), manifestName)
+
+		since, err := lastReleaseTag(projectPath, cfg, generator, pkg, versions[pkg.Name])
+		if err != nil {
+			return err
+		}
+		if since == "" {
+			output.Skipped = append(output.Skipped, SkippedPackage{Package: pkg.Name, Reason: i18n.T("add.deps_no_tag")})
+			continue
+		}
+
+		// Only manifests the diff since the tag touches need parsing
+		changed, cached := changedSince[since]
+		if !cached {
+			if changed, err = git.ChangedSince(projectPath, since); err != nil {
+				return err
+			}
+			changedSince[since] = changed
+		}
+		if !slices.Contains(changed, manifest) {
+			continue
+		}
+
+		updates, err := manifestUpdates(projectPath, pkg.Ecosystem, manifest, since)
+		if err != nil {
+			return fmt.Errorf("%s: %w", pkg.Name, err)
+		}
+		recorded := recordedDependencies(pending, pkg.Name)
+		updates = slices.DeleteFunc(updates, func(u ecosystem.DependencyUpdate) bool {
+			return recorded[dependencyKey(u)]
+		})
+		if len(updates) == 0 {
+			continue
+		}
+
+		cons := DependencyConsignment{
+			Package:  pkg.Name,
+			Since:    since,
+			Manifest: manifest,
+			Type:     types.ChangeTypePatch,
+			Summary:  dependencySummary(updates),
+			Updates:  updates,
+		}
+		for _, update := range updates {
+			if changeType := cfg.DependencyUpdates.ChangeTypeFor(update.Name); changeType.Priority() > cons.Type.Priority() {
+				cons.Type = changeType
+			}
+		}
+		output.Consignments = append(output.Consignments, cons)
+	}
+
+	for _, cons := range output.Consignments {
+		if missing := missingRequiredMetadata(cfg, []string{cons.Package}, opts.Metadata); len(missing) > 0 {
+			return errors.NewValidationError("metadata", i18n.T("add.metadata_missing", strings.Join(missing, ", ")))
+		}
+	}
+	metadataMap, err := convertMetadata(cfg, opts.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to convert metadata: %w", err)
+	}
+
+	if !opts.DryRun {
+		tx := release.NewFileTransaction()
+		defer func() {
+			if err != nil {
+				if rollbackErr := tx.Rollback(); rollbackErr != nil {
+					err = fmt.Errorf("%w; additionally failed to roll back: %v", err, rollbackErr)
+				}
+			}
+		}()
+
+		timestamp := opts.Timestamp
+		if timestamp.IsZero() {
+			timestamp = time.Now().UTC()
+		}
+		ids := consignment.NewIDGenerator(cfg.Consignments.IDStyle, consignmentsDir)
+		sequence, err := consignment.NextSequence(consignmentsDir)
+		if err != nil {
+			return fmt.Errorf("failed to number consignments: %w", err)
+		}
+		for i := range output.Consignments {
+			cons := &output.Consignments[i]
+			id, err := ids.Generate(timestamp, cons.Summary)
+			if err != nil {
+				return fmt.Errorf("failed to generate consignment ID: %w", err)
+			}
+			if err := tx.Backup(filepath.Join(consignmentsDir, id+".md")); err != nil {
+				return err
+			}
+			if err := consignment.WriteConsignment(&consignment.Consignment{
+				ID:         id,
+				Timestamp:  timestamp,
+				Sequence:   sequence + i,
+				Packages:   []string{cons.Package},
+				ChangeType: cons.Type,
+				Summary:    cons.Summary,
+				Metadata:   dependencyMetadata(metadataMap, cons.Updates),
+			}, consignmentsDir); err != nil {
+				return fmt.Errorf("failed to write consignment: %w", err)
+			}
+			cons.ID = id
+		}
+	}
+
+	if opts.JSON {
+		return PrintJSON(os.Stdout, output)
+	}
+	if opts.Quiet {
+		return nil
+	}
+
+	fmt.Println()
+	for _, cons := range output.Consignments {
+		fmt.Println(ui.KeyValue(cons.Package, fmt.Sprintf("%s %s", cons.Type, i18n.T("add.deps_since", cons.Manifest, cons.Since))))
+		for _, update := range cons.Updates {
+			fmt.Println("  - " + update.String())
+		}
+	}
+	for _, skipped := range output.Skipped {
+		fmt.Println(ui.Dimmed(i18n.T("add.deps_skipped", skipped.Package, skipped.Reason)))
+	}
+	switch {
+	case len(output.Consignments) == 0:
+		fmt.Println(ui.InfoMessage(i18n.T("add.deps_none")))
+	case opts.DryRun:
+		fmt.Println(ui.InfoMessage(i18n.T("add.commits_dry_run", len(output.Consignments))))
+	default:
+		fmt.Println(ui.SuccessMessage(i18n.T("add.deps_created", len(output.Consignments))))
+	}
+	fmt.Println()
+	return nil
+}
+
+// manifestUpdates compares a manifest as released at tag with HEAD
+func manifestUpdates(projectPath, eco, manifest, tag string) ([]ecosystem.DependencyUpdate, error) {
+	read := func(rev string) (map[string]string, error) {
+		content, ok, err := git.ReadFileAtRevision(projectPath, rev, manifest)
+		if err != nil || !ok {
+			return map[string]string{}, err
+		}
+		return ecosystem.ParseDependencies(eco, content)
+	}
+	before, err := read(tag)
+	if err != nil {
+		return nil, err
+	}
+	after, err := read("HEAD")
+	if err != nil {
+		return nil, err
+	}
+	return ecosystem.DiffDependencies(before, after), nil
+}
+
+// dependencySummary describes the updates: the update itself when there is
+// one, otherwise a count followed by a list of them
+func dependencySummary(updates []ecosystem.DependencyUpdate) string {
+	if len(updates) == 1 {
+		return updates[0].String()
+	}
+	lines := make([]string, len(updates))
+	for i, update := range updates {
+		lines[i] = "- " + update.String()
+	}
+	return composeSummary(fmt.Sprintf("Update %d dependencies", len(updates)), strings.Join(lines, "\n"))
+}
+
+// dependencyKey identifies an update in consignment metadata
+func dependencyKey(update ecosystem.DependencyUpdate) string {
+	return update.Name + "@" + update.To
+}
+
+// dependencyMetadata adds the logged dependency versions to a consignment's
+// metadata
+func dependencyMetadata(metadata map[string]interface{}, updates []ecosystem.DependencyUpdate) map[string]interface{} {
+	result := make(map[string]interface{}, len(metadata)+1)
+	for k, v := range metadata {
+		result[k] = v
+	}
+	keys := make([]interface{}, len(updates))
+	for i, update := range updates {
+		keys[i] = dependencyKey(update)
+	}
+	result[dependenciesMetadataKey] = keys
+	return result
+}
+
+// recordedDependencies returns the dependency versions pending consignments
+// naming the package were created for
+func recordedDependencies(pending []*consignment.Consignment, packageName string) map[string]bool {
+	recorded := make(map[string]bool)
+	for _, cons := range pending {
+		if !cons.AffectsPackage(packageName) {
+			continue
+		}
+		keys, _ := cons.Metadata[dependenciesMetadataKey].([]interface{})
+		for _, key := range keys {
+			if s, ok := key.(string); ok {
+				recorded[s] = true
+			}
+		}
+	}
+	return recorded
+}
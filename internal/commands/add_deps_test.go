@@ -0,0 +1,179 @@
+package commands
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/ecosystem"
+	"github.com/NatoNathan/shipyard/internal/git"
+	"github.com/NatoNathan/shipyard/pkg/types"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupDepsRepo creates a project with Go, npm and Helm packages, releases
+// all but api, then commits dependency updates to every manifest. It returns
+// the project path and a function committing more file changes.
+func setupDepsRepo(t *testing.T) (string, func(files map[string]string)) {
+	t.Helper()
+	tempDir := t.TempDir()
+	repo, err := gogit.PlainInit(tempDir, false)
+	require.NoError(t, err)
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+
+	when := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	commit := func(files map[string]string) {
+		for name, content := range files {
+			path := filepath.Join(tempDir, name)
+			require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+			require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+			_, err := wt.Add(name)
+			require.NoError(t, err)
+		}
+		_, err := wt.Commit("Update files", &gogit.CommitOptions{
+			Author: &object.Signature{Name: "Test", Email: "test@example.com", When: when},
+		})
+		require.NoError(t, err)
+		when = when.Add(time.Hour)
+	}
+
+	commit(map[string]string{
+		".shipyard/shipyard.yaml": `packages:
+  - name: core
+    path: ./core
+    ecosystem: go
+  - name: web
+    path: ./web
+    ecosystem: npm
+  - name: api
+    path: ./api
+    ecosystem: npm
+  - name: chart
+    path: ./chart
+    ecosystem: helm
+templates:
+  tagName:
+    inline: "{{ .Package }}/v{{ .Version }}"
+consignments:
+  path: .shipyard/consignments
+history:
+  path: .shipyard/history.json
+dependencyUpdates:
+  changeTypes:
+    react: major
+`,
+		"core/go.mod":      "module example.com/core\n\n// version: 1.0.0\n\ngo 1.25\n\nrequire (\n\tgithub.com/spf13/cobra v1.9.1\n\tgithub.com/stretchr/testify v1.10.0\n)\n",
+		"web/package.json": `{"name": "web", "version": "2.0.0", "dependencies": {"react": "^18.2.0", "lodash": "^4.17.20"}}`,
+		"api/package.json": `{"name": "api", "version": "0.1.0", "dependencies": {"express": "^4.19.0"}}`,
+		"chart/Chart.yaml": "apiVersion: v2\nname: chart\nversion: 0.1.0\n",
+	})
+	for _, tag := range []string{"core/v1.0.0", "web/v2.0.0", "chart/v0.1.0"} {
+		require.NoError(t, git.CreateLightweightTag(tempDir, tag))
+	}
+
+	commit(map[string]string{
+		"core/go.mod":      "module example.com/core\n\n// version: 1.0.0\n\ngo 1.25\n\nrequire (\n\tgithub.com/spf13/cobra v1.10.2\n\tgithub.com/stretchr/testify v1.11.1\n)\n",
+		"web/package.json": `{"name": "web", "version": "2.0.0", "dependencies": {"react": "^19.0.0", "lodash": "^4.17.20"}}`,
+		"api/package.json": `{"name": "api", "version": "0.1.0", "dependencies": {"express": "^5.0.0"}}`,
+	})
+	return tempDir, commit
+}
+
+// runAddDepsJSON runs add --deps and decodes its JSON output
+func runAddDepsJSON(t *testing.T, projectPath string, opts AddDepsOptions) AddDepsOutput {
+	t.Helper()
+	opts.JSON = true
+	opts.Timestamp = time.Date(2026, 3, 3, 9, 0, 0, 0, time.UTC)
+	var err error
+	output := captureOutput(func() { err = runAddDeps(projectPath, opts) })
+	require.NoError(t, err)
+	var result AddDepsOutput
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	return result
+}
+
+func TestAddDeps(t *testing.T) {
+	tempDir, commit := setupDepsRepo(t)
+	consignmentsDir := filepath.Join(tempDir, ".shipyard", "consignments")
+
+	t.Run("dry run", func(t *testing.T) {
+		result := runAddDepsJSON(t, tempDir, AddDepsOptions{DryRun: true})
+		assert.True(t, result.DryRun)
+		require.Len(t, result.Consignments, 2)
+
+		core := result.Consignments[0]
+		assert.Equal(t, "core", core.Package)
+		assert.Equal(t, "core/v1.0.0", core.Since)
+		assert.Equal(t, "core/go.mod", core.Manifest)
+		assert.Equal(t, types.ChangeTypePatch, core.Type)
+		assert.Equal(t, []ecosystem.DependencyUpdate{
+			{Name: "github.com/spf13/cobra", From: "v1.9.1", To: "v1.10.2"},
+			{Name: "github.com/stretchr/testify", From: "v1.10.0", To: "v1.11.1"},
+		}, core.Updates)
+		assert.Equal(t, "Update 2 dependencies\n\n"+
+			"- Update github.com/spf13/cobra from v1.9.1 to v1.10.2\n"+
+			"- Update github.com/stretchr/testify from v1.10.0 to v1.11.1", core.Summary)
+
+		web := result.Consignments[1]
+		assert.Equal(t, "web", web.Package)
+		assert.Equal(t, types.ChangeTypeMajor, web.Type, "react updates are escalated by dependencyUpdates.changeTypes")
+		assert.Equal(t, "Update react from ^18.2.0 to ^19.0.0", web.Summary)
+
+		skipped := make(map[string]string)
+		for _, s := range result.Skipped {
+			skipped[s.Package] = s.Reason
+		}
+		assert.Contains(t, skipped["api"], "no release tag")
+		assert.Contains(t, skipped["chart"], "not read for helm packages")
+
+		assert.NoDirExists(t, consignmentsDir, "a dry run writes nothing")
+	})
+
+	t.Run("creates a consignment per package", func(t *testing.T) {
+		result := runAddDepsJSON(t, tempDir, AddDepsOptions{Packages: []string{"core", "web"}, Metadata: map[string]string{"author": "bot"}})
+		require.Len(t, result.Consignments, 2)
+		assert.Empty(t, result.Skipped)
+
+		written := readCommitConsignments(t, tempDir)
+		require.Len(t, written, 2)
+		byPackage := make(map[string]int)
+		for i, c := range written {
+			require.Len(t, c.Packages, 1)
+			byPackage[c.Packages[0]] = i
+		}
+		core := written[byPackage["core"]]
+		assert.Equal(t, types.ChangeTypePatch, core.ChangeType)
+		assert.Contains(t, core.Summary, "- Update github.com/spf13/cobra from v1.9.1 to v1.10.2")
+		assert.Equal(t, "bot", core.Metadata["author"])
+		assert.Equal(t, []interface{}{"github.com/spf13/cobra@v1.10.2", "github.com/stretchr/testify@v1.11.1"}, core.Metadata["dependencies"])
+		assert.Equal(t, types.ChangeTypeMajor, written[byPackage["web"]].ChangeType)
+	})
+
+	t.Run("skips updates already logged", func(t *testing.T) {
+		result := runAddDepsJSON(t, tempDir, AddDepsOptions{Packages: []string{"core", "web"}})
+		assert.Empty(t, result.Consignments)
+
+		commit(map[string]string{
+			"core/go.mod": "module example.com/core\n\n// version: 1.0.0\n\ngo 1.25\n\nrequire (\n\tgithub.com/spf13/cobra v1.11.0\n\tgithub.com/stretchr/testify v1.11.1\n)\n",
+		})
+		result = runAddDepsJSON(t, tempDir, AddDepsOptions{Packages: []string{"core"}})
+		require.Len(t, result.Consignments, 1)
+		assert.Equal(t, "Update github.com/spf13/cobra from v1.9.1 to v1.11.0", result.Consignments[0].Summary)
+	})
+
+	t.Run("conflicting flags", func(t *testing.T) {
+		defer changeToDir(t, tempDir)()
+		cmd := NewAddCommand()
+		cmd.SetArgs([]string{"--deps", "--summary", "Bump deps"})
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		err := cmd.Execute()
+		assert.ErrorContains(t, err, "--deps cannot be combined with --summary")
+	})
+}
@@ -0,0 +1,189 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/errors"
+	"github.com/NatoNathan/shipyard/internal/graph"
+	"github.com/NatoNathan/shipyard/internal/history"
+	"github.com/NatoNathan/shipyard/internal/i18n"
+	"github.com/NatoNathan/shipyard/internal/release"
+	"github.com/NatoNathan/shipyard/internal/rules"
+	"github.com/NatoNathan/shipyard/internal/ui"
+	"github.com/NatoNathan/shipyard/internal/version"
+	"github.com/NatoNathan/shipyard/pkg/semver"
+)
+
+// Release boundary kinds
+const (
+	boundaryMajor  = "major"
+	boundaryYanked = "yanked"
+)
+
+// releaseBoundary describes a package whose next release deserves a second look
+// before more consignments are filed against it
+type releaseBoundary struct {
+	Package string
+	Kind    string // boundaryMajor or boundaryYanked
+	Message string
+}
+
+// detectReleaseBoundaries reports packages that are already queued for a major
+// bump or whose latest release was yanked. It never modifies the repository.
+func detectReleaseBoundaries(projectPath string, cfg *config.Config, packages []string, changeType string) ([]releaseBoundary, error) {
+	var boundaries []releaseBoundary
+
+	// A major consignment is already explicit about its impact
+	if changeType != boundaryMajor {
+		pendingMajor, err := pendingMajorPackages(projectPath, cfg)
+		if err != nil {
+			return nil, err
+		}
+		for _, pkg := range packages {
+			if pendingMajor[pkg] {
+				boundaries = append(boundaries, releaseBoundary{
+					Package: pkg,
+					Kind:    boundaryMajor,
+					Message: i18n.T("add.boundary_major", pkg, changeType),
+				})
+			}
+		}
+	}
+
+	entries, err := history.ReadHistory(filepath.Join(projectPath, cfg.History.Path))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	for _, pkg := range packages {
+		pkgEntries := history.SortByTimestamp(release.PackageEntries(cfg, entries, pkg), true)
+		if len(pkgEntries) > 0 && pkgEntries[0].Yanked {
+			boundaries = append(boundaries, releaseBoundary{
+				Package: pkg,
+				Kind:    boundaryYanked,
+				Message: i18n.T("add.boundary_yanked", pkg, pkgEntries[0].Version),
+			})
+		}
+	}
+
+	return boundaries, nil
+}
+
+// pendingMajorPackages returns the packages whose pending consignments, including
+// propagated dependency bumps, imply a major version bump
+func pendingMajorPackages(projectPath string, cfg *config.Config) (map[string]bool, error) {
+	consignments, err := readAllConsignments(filepath.Join(projectPath, cfg.Consignments.Path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read consignments: %w", err)
+	}
+	if len(consignments) == 0 {
+		return map[string]bool{}, nil
+	}
+
+	// Only the bump type matters here, so unreadable version files fall back to 0.0.0
+	currentVersions, err := release.ReadAllCurrentVersions(projectPath, cfg, release.SettingsFor(projectPath, cfg))
+	if err != nil {
+		currentVersions = make(map[string]semver.Version, len(cfg.Packages))
+		for _, pkg := range cfg.Packages {
+			currentVersions[pkg.Name] = semver.Version{}
+		}
+	}
+
+	depGraph, err := graph.BuildGraph(cfg)
+	if err != nil {
+		return nil, err
+	}
+	propagator, err := version.NewPropagator(depGraph)
+	if err != nil {
+		return nil, err
+	}
+	bumps, err := propagator.Propagate(currentVersions, consignments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate pending version bumps: %w", err)
+	}
+
+	result := make(map[string]bool)
+	for pkg, bump := range bumps {
+		if bump.ChangeType == boundaryMajor {
+			result[pkg] = true
+		}
+	}
+	return result, nil
+}
+
+// checkReleaseBoundaries warns about release boundaries before a consignment is
+// written. At error level (the default) interactive users confirm the notice and
+// non-interactive callers must pass the matching acknowledgment flag; at warn
+// level the notice is printed and the consignment is written.
+func checkReleaseBoundaries(projectPath string, cfg *config.Config, options AddOptions) error {
+	resolver, err := release.NewRuleResolver(cfg, options.MaxSeverity)
+	if err != nil {
+		return err
+	}
+	level := resolver.Level(rules.ReleaseBoundary)
+	if level == rules.LevelOff {
+		return nil
+	}
+
+	boundaries, err := detectReleaseBoundaries(projectPath, cfg, options.Packages, options.Type)
+	if err != nil {
+		return err
+	}
+
+	if level == rules.LevelWarn {
+		report := rules.NewReport(resolver)
+		for _, b := range boundaries {
+			report.Add(rules.ReleaseBoundary, b.Message)
+		}
+		if !options.JSON && !options.Quiet {
+			printRuleWarnings(report)
+		}
+		return nil
+	}
+
+	var unacknowledged []releaseBoundary
+	for _, b := range boundaries {
+		if (b.Kind == boundaryMajor && options.AckMajor) || (b.Kind == boundaryYanked && options.AckYanked) {
+			continue
+		}
+		unacknowledged = append(unacknowledged, b)
+	}
+	if len(unacknowledged) == 0 {
+		return nil
+	}
+
+	if options.Confirm == nil {
+		var messages []string
+		needed := map[string]bool{}
+		for _, b := range unacknowledged {
+			messages = append(messages, b.Message)
+			needed[b.Kind] = true
+		}
+		var flagNames []string
+		for _, kind := range []string{boundaryMajor, boundaryYanked} {
+			if needed[kind] {
+				flagNames = append(flagNames, "--ack-"+kind)
+			}
+		}
+		return errors.NewValidationError("packages",
+			i18n.T("add.boundary_ack", strings.Join(messages, "; "), strings.Join(flagNames, i18n.T("list.and")), rules.ReleaseBoundary))
+	}
+
+	fmt.Println()
+	for _, b := range unacknowledged {
+		fmt.Println(ui.WarningMessage(fmt.Sprintf("%s [%s]", b.Message, rules.ReleaseBoundary)))
+	}
+	fmt.Println()
+
+	confirmed, err := options.Confirm(i18n.T("add.boundary_confirm"))
+	if err != nil {
+		return fmt.Errorf("failed to confirm: %w", err)
+	}
+	if !confirmed {
+		return fmt.Errorf("%s", i18n.T("add.cancelled"))
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package commands
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/history"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAddCommand_PendingMajorBoundary tests the notice for packages already queued for a major bump
+func TestAddCommand_PendingMajorBoundary(t *testing.T) {
+	setup := func(t *testing.T) string {
+		t.Helper()
+		tempDir := t.TempDir()
+		initGitRepo(t, tempDir)
+		initShipyardConfig(t, tempDir)
+		require.NoError(t, runAdd(tempDir, AddOptions{
+			Packages:  []string{"core"},
+			Type:      "major",
+			Summary:   "Breaking change",
+			Timestamp: time.Date(2026, 1, 30, 14, 30, 22, 0, time.UTC),
+		}))
+		return tempDir
+	}
+	minor := func(opts AddOptions) AddOptions {
+		opts.Packages = []string{"core"}
+		opts.Type = "minor"
+		opts.Summary = "New feature"
+		opts.Timestamp = time.Date(2026, 1, 30, 15, 0, 0, 0, time.UTC)
+		return opts
+	}
+
+	t.Run("non-interactive requires --ack-major", func(t *testing.T) {
+		tempDir := setup(t)
+		err := runAdd(tempDir, minor(AddOptions{Quiet: true}))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "major bump")
+		assert.Contains(t, err.Error(), "--ack-major")
+		assertConsignmentCount(t, tempDir, 1)
+	})
+
+	t.Run("non-interactive with --ack-major", func(t *testing.T) {
+		tempDir := setup(t)
+		require.NoError(t, runAdd(tempDir, minor(AddOptions{Quiet: true, AckMajor: true})))
+		assertConsignmentCount(t, tempDir, 2)
+	})
+
+	t.Run("interactive confirmation", func(t *testing.T) {
+		tempDir := setup(t)
+		var asked bool
+		confirm := func(string) (bool, error) {
+			asked = true
+			return true, nil
+		}
+		output := captureOutput(func() {
+			require.NoError(t, runAdd(tempDir, minor(AddOptions{Quiet: true, Confirm: confirm})))
+		})
+		assert.True(t, asked)
+		assert.Contains(t, output, "imply a major bump")
+		assertConsignmentCount(t, tempDir, 2)
+	})
+
+	t.Run("interactive decline", func(t *testing.T) {
+		tempDir := setup(t)
+		confirm := func(string) (bool, error) { return false, nil }
+		captureOutput(func() {
+			assert.Error(t, runAdd(tempDir, minor(AddOptions{Quiet: true, Confirm: confirm})))
+		})
+		assertConsignmentCount(t, tempDir, 1)
+	})
+
+	t.Run("major consignments are not flagged", func(t *testing.T) {
+		tempDir := setup(t)
+		opts := minor(AddOptions{Quiet: true})
+		opts.Type = "major"
+		require.NoError(t, runAdd(tempDir, opts))
+	})
+
+	t.Run("warn level prints notice without blocking", func(t *testing.T) {
+		tempDir := setup(t)
+		output := captureOutput(func() {
+			require.NoError(t, runAdd(tempDir, minor(AddOptions{MaxSeverity: "warn"})))
+		})
+		assert.Contains(t, output, "imply a major bump")
+		assert.Contains(t, output, "[release-boundary]")
+		assertConsignmentCount(t, tempDir, 2)
+	})
+
+	t.Run("off in config skips the check", func(t *testing.T) {
+		tempDir := setup(t)
+		setRuleLevels(t, tempDir, map[string]string{"release-boundary": "off"})
+		require.NoError(t, runAdd(tempDir, minor(AddOptions{Quiet: true})))
+		assertConsignmentCount(t, tempDir, 2)
+	})
+
+	t.Run("flag escalates config level", func(t *testing.T) {
+		tempDir := setup(t)
+		setRuleLevels(t, tempDir, map[string]string{"release-boundary": "warn"})
+		err := runAdd(tempDir, minor(AddOptions{Quiet: true, MaxSeverity: "error"}))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "[release-boundary]")
+		assertConsignmentCount(t, tempDir, 1)
+	})
+
+	t.Run("other packages are not flagged", func(t *testing.T) {
+		tempDir := setup(t)
+		opts := minor(AddOptions{Quiet: true})
+		opts.Packages = []string{"api"}
+		require.NoError(t, runAdd(tempDir, opts))
+	})
+}
+
+// TestAddCommand_YankedBoundary tests the notice for packages whose latest release was yanked
+func TestAddCommand_YankedBoundary(t *testing.T) {
+	tempDir := t.TempDir()
+	initGitRepo(t, tempDir)
+	initShipyardConfig(t, tempDir)
+
+	entries := []history.Entry{
+		{Version: "1.0.0", Package: "core", Timestamp: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{Version: "1.1.0", Package: "core", Timestamp: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC), Yanked: true},
+		{Version: "2.0.0", Package: "api", Timestamp: time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)},
+	}
+	data, err := json.Marshal(entries)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, ".shipyard", "history.json"), data, 0644))
+
+	opts := AddOptions{
+		Packages:  []string{"core"},
+		Type:      "patch",
+		Summary:   "Fix regression",
+		Quiet:     true,
+		Timestamp: time.Date(2026, 1, 30, 14, 30, 22, 0, time.UTC),
+	}
+
+	err = runAdd(tempDir, opts)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "1.1.0")
+	assert.Contains(t, err.Error(), "--ack-yanked")
+	assert.NotContains(t, err.Error(), "--ack-major")
+
+	opts.AckYanked = true
+	require.NoError(t, runAdd(tempDir, opts))
+
+	// api's latest release is not yanked
+	require.NoError(t, runAdd(tempDir, AddOptions{
+		Packages:  []string{"api"},
+		Type:      "patch",
+		Summary:   "Fix api",
+		Quiet:     true,
+		Timestamp: time.Date(2026, 1, 30, 14, 31, 0, 0, time.UTC),
+	}))
+}
+
+func assertConsignmentCount(t *testing.T, dir string, expected int) {
+	t.Helper()
+	entries, err := os.ReadDir(filepath.Join(dir, ".shipyard", "consignments"))
+	require.NoError(t, err)
+	assert.Len(t, entries, expected)
+}
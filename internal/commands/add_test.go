@@ -1,12 +1,17 @@
 package commands
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/consignment"
+	"github.com/NatoNathan/shipyard/internal/i18n"
+	"github.com/NatoNathan/shipyard/internal/prompt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -58,6 +63,128 @@ func TestAddCommand_NonInteractive(t *testing.T) {
 	assert.Equal(t, 1, len(entries), "Should have created one consignment file")
 }
 
+// TestAddCommand_Accessible tests the interactive add flow with accessible prompts
+func TestAddCommand_Accessible(t *testing.T) {
+	tempDir := t.TempDir()
+	initGitRepo(t, tempDir)
+	initShipyardConfig(t, tempDir)
+
+	var out bytes.Buffer
+	prompt.SetAccessible(true)
+	prompt.SetAccessibleIO(strings.NewReader("2\n1\nFix login redirect\n"), &out)
+	t.Cleanup(func() {
+		prompt.SetAccessible(false)
+		prompt.SetAccessibleIO(os.Stdin, os.Stdout)
+	})
+
+	captureOutput(func() {
+		require.NoError(t, runInteractiveAdd(tempDir, nil, "", "", nil, AddOptions{}))
+	})
+
+	assert.Equal(t, `Select package(s) affected by this change:
+  1. core
+  2. api
+Enter numbers separated by commas: Select change type:
+  1. patch - Backwards compatible bug fixes
+  2. minor - Backwards compatible new features
+  3. major - Breaking changes
+Enter a number (1-3): Change summary: `, out.String())
+
+	consignments, err := consignment.ReadAllConsignments(filepath.Join(tempDir, ".shipyard", "consignments"))
+	require.NoError(t, err)
+	require.Len(t, consignments, 1)
+	assert.Equal(t, []string{"api"}, consignments[0].Packages)
+	assert.Equal(t, "patch", string(consignments[0].ChangeType))
+	assert.Equal(t, "Fix login redirect", consignments[0].Summary)
+}
+
+// TestAddCommand_AccessibleSpanish tests the interactive add flow with the Spanish locale
+func TestAddCommand_AccessibleSpanish(t *testing.T) {
+	tempDir := t.TempDir()
+	initGitRepo(t, tempDir)
+	initShipyardConfig(t, tempDir)
+
+	require.NoError(t, i18n.SetLocale("es"))
+	var out bytes.Buffer
+	prompt.SetAccessible(true)
+	prompt.SetAccessibleIO(strings.NewReader("\n9\n1\n4\n2\n\nCorrige la redirección\n"), &out)
+	t.Cleanup(func() {
+		_ = i18n.SetLocale("")
+		prompt.SetAccessible(false)
+		prompt.SetAccessibleIO(os.Stdin, os.Stdout)
+	})
+
+	output := captureOutput(func() {
+		require.NoError(t, runInteractiveAdd(tempDir, nil, "", "", nil, AddOptions{}))
+	})
+
+	assert.Equal(t, `Selecciona los paquetes afectados por este cambio:
+  1. core
+  2. api
+Escribe números separados por comas: Selecciona al menos una opción.
+Escribe números separados por comas: "9" no es un número entre 1 y 2.
+Escribe números separados por comas: Selecciona el tipo de cambio:
+  1. patch - Correcciones compatibles
+  2. minor - Funciones nuevas compatibles
+  3. major - Cambios incompatibles
+Escribe un número (1-3): "4" no es un número entre 1 y 3.
+Escribe un número (1-3): Resumen del cambio: El resumen no puede estar vacío.
+Resumen del cambio: `, out.String())
+
+	assert.Contains(t, output, "Envío creado:")
+	assert.Contains(t, output, "Paquetes")
+	assert.Contains(t, output, "Resumen")
+	assert.NotContains(t, output, "Created consignment")
+
+	consignments, err := consignment.ReadAllConsignments(filepath.Join(tempDir, ".shipyard", "consignments"))
+	require.NoError(t, err)
+	require.Len(t, consignments, 1)
+	assert.Equal(t, []string{"core"}, consignments[0].Packages)
+	assert.Equal(t, "minor", string(consignments[0].ChangeType))
+
+	// Validation errors are translated too
+	err = runAdd(tempDir, AddOptions{Packages: []string{"core"}, Type: "huge", Summary: "Algo"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "tipo de cambio no válido: huge")
+}
+
+// TestAddCommand_AccessiblePackageSummaries tests wording a change for each
+// package in the interactive flow
+func TestAddCommand_AccessiblePackageSummaries(t *testing.T) {
+	tempDir := t.TempDir()
+	initGitRepo(t, tempDir)
+	initShipyardConfig(t, tempDir)
+
+	var out bytes.Buffer
+	prompt.SetAccessible(true)
+	prompt.SetAccessibleIO(strings.NewReader("1,2\n2\nAdd project search\ny\nAdd the --search flag\n\n"), &out)
+	t.Cleanup(func() {
+		prompt.SetAccessible(false)
+		prompt.SetAccessibleIO(os.Stdin, os.Stdout)
+	})
+
+	captureOutput(func() {
+		require.NoError(t, runInteractiveAdd(tempDir, nil, "", "", nil, AddOptions{}))
+	})
+	assert.Contains(t, out.String(), "Word the change differently for each package? [y/N]: ")
+	assert.Contains(t, out.String(), "Summary for api (empty to use the shared summary): ")
+
+	consignments, err := consignment.ReadAllConsignments(filepath.Join(tempDir, ".shipyard", "consignments"))
+	require.NoError(t, err)
+	require.Len(t, consignments, 1)
+	assert.Equal(t, "Add project search", consignments[0].Summary)
+	assert.Equal(t, map[string]string{"core": "Add the --search flag"}, consignments[0].PackageSummaries)
+	assert.Equal(t, "Add project search", consignments[0].SummaryFor("api"))
+
+	err = runAdd(tempDir, AddOptions{
+		Packages:         []string{"core"},
+		Type:             "patch",
+		Summary:          "Fix crash",
+		PackageSummaries: map[string]string{"api": "Fix crash in handler"},
+	})
+	assert.ErrorContains(t, err, "per-package summary for api")
+}
+
 // TestAddCommand_InvalidPackage tests handling of invalid package names
 func TestAddCommand_InvalidPackage(t *testing.T) {
 	tempDir := t.TempDir()
@@ -229,3 +356,262 @@ func initShipyardConfig(t *testing.T, dir string) {
 	configPath := filepath.Join(shipyardDir, "shipyard.yaml")
 	require.NoError(t, config.WriteConfig(cfg, configPath))
 }
+
+// TestAddCommand_RequiredChangelogMetadata tests that changelog-required metadata keys are enforced
+func TestAddCommand_RequiredChangelogMetadata(t *testing.T) {
+	tempDir := t.TempDir()
+	initGitRepo(t, tempDir)
+	initShipyardConfig(t, tempDir)
+
+	configPath := filepath.Join(tempDir, ".shipyard", "shipyard.yaml")
+	configContent := `packages:
+  - name: core
+    path: ./
+    ecosystem: go
+  - name: api
+    path: ./api
+    ecosystem: go
+    changelog:
+      requiredMetadata: []
+changelog:
+  requiredMetadata: [issue, pr]
+`
+	require.NoError(t, os.WriteFile(configPath, []byte(configContent), 0644))
+
+	consignmentsDir := filepath.Join(tempDir, ".shipyard", "consignments")
+
+	t.Run("missing keys", func(t *testing.T) {
+		err := runAdd(tempDir, AddOptions{
+			Packages: []string{"core"},
+			Type:     "patch",
+			Summary:  "Fixed bug",
+			Metadata: map[string]string{"issue": "JIRA-1"},
+			Quiet:    true,
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "missing required metadata: pr")
+
+		entries, err := os.ReadDir(consignmentsDir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("package override", func(t *testing.T) {
+		err := runAdd(tempDir, AddOptions{
+			Packages:  []string{"api"},
+			Type:      "patch",
+			Summary:   "Fixed api bug",
+			Quiet:     true,
+			Timestamp: time.Date(2026, 1, 30, 14, 30, 22, 0, time.UTC),
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("all keys present", func(t *testing.T) {
+		err := runAdd(tempDir, AddOptions{
+			Packages:  []string{"core", "api"},
+			Type:      "minor",
+			Summary:   "Added feature",
+			Metadata:  map[string]string{"issue": "JIRA-2", "pr": "42"},
+			Quiet:     true,
+			Timestamp: time.Date(2026, 1, 30, 14, 31, 22, 0, time.UTC),
+		})
+		require.NoError(t, err)
+
+		consignments, err := consignment.ReadAllConsignments(consignmentsDir)
+		require.NoError(t, err)
+		var found *consignment.Consignment
+		for _, c := range consignments {
+			if c.Summary == "Added feature" {
+				found = c
+			}
+		}
+		require.NotNil(t, found)
+		assert.Equal(t, "JIRA-2", found.Metadata["issue"])
+		assert.Equal(t, "42", found.Metadata["pr"])
+	})
+}
+
+// TestAddCommand_MetaFlag tests the --meta alias for --metadata
+func TestAddCommand_MetaFlag(t *testing.T) {
+	tempDir := t.TempDir()
+	initGitRepo(t, tempDir)
+	initShipyardConfig(t, tempDir)
+	defer changeToDir(t, tempDir)()
+
+	cmd := NewAddCommand()
+	cmd.SetArgs([]string{"-p", "core", "-t", "patch", "-s", "Fixed bug", "--meta", "pr=42", "--metadata", "issue=JIRA-7"})
+	captureOutput(func() {
+		require.NoError(t, cmd.Execute())
+	})
+
+	consignments, err := consignment.ReadAllConsignments(filepath.Join(tempDir, ".shipyard", "consignments"))
+	require.NoError(t, err)
+	require.Len(t, consignments, 1)
+	assert.Equal(t, "42", consignments[0].Metadata["pr"])
+	assert.Equal(t, "JIRA-7", consignments[0].Metadata["issue"])
+}
+
+func TestMetadataPromptFields(t *testing.T) {
+	cfg := &config.Config{
+		Packages: []config.Package{{Name: "core", Path: "."}},
+		Metadata: config.MetadataConfig{Fields: []config.MetadataField{
+			{Name: "team", AllowedValues: []string{"a", "b"}},
+			{Name: "issue"},
+		}},
+		Changelog: config.ChangelogConfig{RequiredMetadata: []string{"issue", "pr"}},
+	}
+
+	fields := metadataPromptFields(cfg, []string{"core"})
+	require.Len(t, fields, 3)
+	assert.Equal(t, "team", fields[0].Name)
+	assert.False(t, fields[0].Required)
+	assert.Equal(t, "issue", fields[1].Name)
+	assert.True(t, fields[1].Required)
+	assert.Equal(t, config.MetadataField{Name: "pr", Type: "string", Required: true}, fields[2])
+	assert.False(t, cfg.Metadata.Fields[1].Required, "config fields must not be modified")
+}
+
+// TestAddCommand_BodyFlags tests the non-interactive description flags end-to-end
+func TestAddCommand_BodyFlags(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		stdin       string
+		wantSummary string
+	}{
+		{
+			name:        "body",
+			args:        []string{"-p", "core", "-t", "minor", "-s", "Add retries", "--body", "Requests are retried with backoff."},
+			wantSummary: "Add retries\n\nRequests are retried with backoff.",
+		},
+		{
+			name:        "body file",
+			args:        []string{"-p", "core", "-t", "minor", "-s", "Add retries", "--body-file", "notes.md"},
+			wantSummary: "Add retries\n\nRequests are retried with backoff.",
+		},
+		{
+			name:        "stdin summary and body",
+			args:        []string{"-p", "core", "-t", "minor", "--stdin"},
+			stdin:       "# Add retries\n\nRequests are retried with backoff.\n",
+			wantSummary: "Add retries\n\nRequests are retried with backoff.",
+		},
+		{
+			name:        "stdin body with summary flag",
+			args:        []string{"-p", "core", "-t", "minor", "-s", "Add retries", "--stdin"},
+			stdin:       "Requests are retried with backoff.\n",
+			wantSummary: "Add retries\n\nRequests are retried with backoff.",
+		},
+		{
+			name:        "gh pr view",
+			args:        []string{"-p", "core", "-t", "minor", "--stdin"},
+			stdin:       "title:\tAdd retries\nstate:\tOPEN\nauthor:\toctocat\nnumber:\t42\n--\nRequests are retried with backoff.\n",
+			wantSummary: "Add retries\n\nRequests are retried with backoff.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			initGitRepo(t, tempDir)
+			initShipyardConfig(t, tempDir)
+			require.NoError(t, os.WriteFile(filepath.Join(tempDir, "notes.md"), []byte("Requests are retried with backoff.\n"), 0644))
+			defer changeToDir(t, tempDir)()
+
+			cmd := NewAddCommand()
+			cmd.SetArgs(tt.args)
+			cmd.SetIn(strings.NewReader(tt.stdin))
+			output := captureOutput(func() {
+				require.NoError(t, cmd.Execute())
+			})
+
+			consignments, err := consignment.ReadAllConsignments(filepath.Join(tempDir, ".shipyard", "consignments"))
+			require.NoError(t, err)
+			require.Len(t, consignments, 1)
+			assert.Equal(t, tt.wantSummary, consignments[0].Summary)
+			assert.Contains(t, output, ".shipyard/consignments/"+consignments[0].ID+".md")
+		})
+	}
+}
+
+// TestAddCommand_StdinMissingFlags tests that --stdin reports what it cannot prompt for
+func TestAddCommand_StdinMissingFlags(t *testing.T) {
+	tempDir := t.TempDir()
+	initGitRepo(t, tempDir)
+	initShipyardConfig(t, tempDir)
+	defer changeToDir(t, tempDir)()
+
+	cmd := NewAddCommand()
+	cmd.SetArgs([]string{"-t", "patch", "--stdin"})
+	cmd.SetIn(strings.NewReader("Fix login redirect\n"))
+	cmd.SilenceUsage = true
+	err := cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "missing --package")
+
+	entries, err := os.ReadDir(filepath.Join(tempDir, ".shipyard", "consignments"))
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+// TestAddCommand_BodyFlagsExclusive tests that only one description source is accepted
+func TestAddCommand_BodyFlagsExclusive(t *testing.T) {
+	cmd := NewAddCommand()
+	cmd.SetArgs([]string{"-p", "core", "-t", "patch", "--body", "x", "--stdin"})
+	cmd.SetIn(strings.NewReader(""))
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	err := cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "none of the others can be")
+}
+
+// TestAddCommand_JSONPath tests that JSON output includes the consignment path
+func TestAddCommand_JSONPath(t *testing.T) {
+	tempDir := t.TempDir()
+	initGitRepo(t, tempDir)
+	initShipyardConfig(t, tempDir)
+
+	output := captureOutput(func() {
+		require.NoError(t, runAdd(tempDir, AddOptions{
+			Packages: []string{"core"},
+			Type:     "patch",
+			Summary:  "Fixed bug",
+			Body:     "Details",
+			JSON:     true,
+		}))
+	})
+	assertJSONOutput(t, output, "path")
+	assert.Contains(t, output, `".shipyard/consignments/`)
+}
+
+func TestAddCommand_IDStyle(t *testing.T) {
+	tempDir := t.TempDir()
+	initGitRepo(t, tempDir)
+	initShipyardConfig(t, tempDir)
+	configPath := filepath.Join(tempDir, ".shipyard", "shipyard.yaml")
+	cfg, err := config.LoadFromDir(tempDir)
+	require.NoError(t, err)
+	cfg.Consignments.IDStyle = "summary-slug"
+	require.NoError(t, config.WriteConfig(cfg, configPath))
+
+	add := func(summary string) string {
+		t.Helper()
+		require.NoError(t, runAdd(tempDir, AddOptions{
+			Packages: []string{"core"},
+			Type:     "patch",
+			Summary:  summary,
+			Quiet:    true,
+		}))
+		entries, err := os.ReadDir(filepath.Join(tempDir, ".shipyard", "consignments"))
+		require.NoError(t, err)
+		return entries[len(entries)-1].Name()
+	}
+
+	assert.Regexp(t, `^fix-token-refresh-[a-z0-9]{4}\.md$`, add("Fix token refresh"))
+
+	cfg.Consignments.IDStyle = "nautical"
+	require.NoError(t, config.WriteConfig(cfg, configPath))
+	err = runAdd(tempDir, AddOptions{Packages: []string{"core"}, Type: "patch", Summary: "x", Quiet: true})
+	assert.ErrorContains(t, err, `unknown consignments.idStyle "nautical"`)
+}
@@ -0,0 +1,215 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/release"
+	"github.com/NatoNathan/shipyard/internal/template"
+	"github.com/NatoNathan/shipyard/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// CacheListEntry is a cached template in the cache list JSON output
+type CacheListEntry struct {
+	template.CacheEntry
+	AgeSeconds int64 `json:"ageSeconds"`
+	TTLSeconds int64 `json:"ttlSeconds"`
+	Expired    bool  `json:"expired"` // Revalidated with the source on next use
+}
+
+// CacheListOutput is the JSON output of the cache list command
+type CacheListOutput struct {
+	Dir     string           `json:"dir"`
+	Entries []CacheListEntry `json:"entries"`
+}
+
+// NewCacheListCommand creates the cache list command
+func NewCacheListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "Take stock of the chart locker",
+		Long: `List the remote templates shipyard has cached, with their source, age,
+TTL, size, and cache hash.
+
+Entries older than the TTL (remote.cacheTTL, default 24h) are revalidated with
+their source the next time they are used.`,
+		Example: `  # Show cached templates
+  shipyard cache list
+
+  # Machine-readable listing
+  shipyard cache list --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCacheList(newCacheLoader(), GetGlobalFlags(cmd))
+		},
+	}
+}
+
+// NewCacheClearCommand creates the cache clear command
+func NewCacheClearCommand() *cobra.Command {
+	var source string
+
+	cmd := &cobra.Command{
+		Use:   "clear [--url source]",
+		Short: "Empty the chart locker",
+		Long: `Remove cached remote templates. Without --url every entry is removed;
+with --url only the entry for that source is removed.`,
+		Example: `  # Drop everything
+  shipyard cache clear
+
+  # Drop a single entry
+  shipyard cache clear --url https://example.com/templates/changelog.tmpl`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCacheClear(newCacheLoader(), source, GetGlobalFlags(cmd))
+		},
+	}
+
+	cmd.Flags().StringVar(&source, "url", "", "Remove only the entry for this source (URL or github:, gitlab:, bitbucket: reference)")
+
+	return cmd
+}
+
+// NewCacheRefreshCommand creates the cache refresh command
+func NewCacheRefreshCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "refresh <source>",
+		Short: "Fetch fresh charts from the source",
+		Long: `Fetch a remote template again, bypassing the cache, store it, and report
+whether its content changed.`,
+		Example: `  # Force a fresh fetch
+  shipyard cache refresh https://example.com/templates/changelog.tmpl`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCacheRefresh(newCacheLoader(), args[0], GetGlobalFlags(cmd))
+		},
+	}
+}
+
+// newCacheLoader returns a template loader using the project's remote
+// settings when run inside a project, and the defaults otherwise
+func newCacheLoader() *template.TemplateLoader {
+	loader := template.NewTemplateLoader()
+	if cwd, err := projectRoot(); err == nil {
+		if cfg, err := config.LoadFromDir(cwd); err == nil {
+			loader.SetRemoteOptions(release.SettingsFor(cwd, cfg).Remote)
+		}
+	}
+	return loader
+}
+
+func runCacheList(loader *template.TemplateLoader, flags GlobalFlags) error {
+	cached, err := loader.ListCached()
+	if err != nil {
+		return fmt.Errorf("failed to read template cache: %w", err)
+	}
+
+	ttl := loader.CacheTTL()
+	now := time.Now()
+	output := CacheListOutput{Dir: loader.CacheDir(), Entries: []CacheListEntry{}}
+	for _, entry := range cached {
+		age := now.Sub(entry.FetchedAt)
+		output.Entries = append(output.Entries, CacheListEntry{
+			CacheEntry: entry,
+			AgeSeconds: int64(age.Seconds()),
+			TTLSeconds: int64(ttl.Seconds()),
+			Expired:    age >= ttl,
+		})
+	}
+
+	if flags.JSON {
+		return PrintJSON(os.Stdout, output)
+	}
+	if flags.Quiet {
+		return nil
+	}
+
+	if len(output.Entries) == 0 {
+		fmt.Println(ui.Dimmed(fmt.Sprintf("No cached templates in %s", output.Dir)))
+		return nil
+	}
+
+	rows := make([][]string, 0, len(output.Entries))
+	for _, entry := range output.Entries {
+		source := entry.Source
+		if source == "" {
+			source = "(unknown)"
+		}
+		age := formatCacheAge(time.Duration(entry.AgeSeconds) * time.Second)
+		if entry.Expired {
+			age += " (expired)"
+		}
+		hash := entry.Hash
+		if len(hash) > 12 {
+			hash = hash[:12]
+		}
+		rows = append(rows, []string{source, age, ttl.String(), fmt.Sprintf("%d B", entry.Size), hash})
+	}
+	fmt.Println(ui.Table([]string{"Source", "Age", "TTL", "Size", "Hash"}, rows))
+	fmt.Println(ui.Dimmed(output.Dir))
+	return nil
+}
+
+func runCacheClear(loader *template.TemplateLoader, source string, flags GlobalFlags) error {
+	removed := 0
+	if source != "" {
+		found, err := loader.RemoveCached(source)
+		if err != nil {
+			return fmt.Errorf("failed to remove cache entry: %w", err)
+		}
+		if !found {
+			return fmt.Errorf("no cache entry for %s", source)
+		}
+		removed = 1
+	} else {
+		var err error
+		if removed, err = loader.ClearCache(); err != nil {
+			return fmt.Errorf("failed to clear template cache: %w", err)
+		}
+	}
+
+	if flags.JSON {
+		return PrintJSON(os.Stdout, map[string]int{"removed": removed})
+	}
+	if !flags.Quiet {
+		fmt.Println(ui.SuccessMessage(fmt.Sprintf("Removed %d cached template(s)", removed)))
+	}
+	return nil
+}
+
+func runCacheRefresh(loader *template.TemplateLoader, source string, flags GlobalFlags) error {
+	changed, err := loader.RefreshCached(source)
+	if err != nil {
+		return fmt.Errorf("failed to refresh %s: %w", source, err)
+	}
+
+	if flags.JSON {
+		return PrintJSON(os.Stdout, map[string]interface{}{"source": source, "changed": changed})
+	}
+	if flags.Quiet {
+		return nil
+	}
+	if changed {
+		fmt.Println(ui.SuccessMessage(fmt.Sprintf("Refreshed %s; content changed", source)))
+	} else {
+		fmt.Println(ui.SuccessMessage(fmt.Sprintf("Refreshed %s; content unchanged", source)))
+	}
+	return nil
+}
+
+// formatCacheAge renders an age at the coarsest useful unit, e.g. 45s, 12m, 3h20m, 2d4h
+func formatCacheAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd%dh", int(d.Hours())/24, int(d.Hours())%24)
+	}
+}
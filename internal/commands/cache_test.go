@@ -0,0 +1,70 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/template"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheCommands(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, "{{ .Version }}")
+	}))
+	t.Cleanup(server.Close)
+	url := server.URL + "/notes.tmpl"
+
+	loader := template.NewTemplateLoader()
+	loader.SetCacheDir(t.TempDir())
+	loader.SetCacheTTL(time.Hour)
+	_, err := loader.Load(url)
+	require.NoError(t, err)
+
+	list := func(t *testing.T) CacheListOutput {
+		t.Helper()
+		output := captureOutput(func() {
+			require.NoError(t, runCacheList(loader, GlobalFlags{JSON: true}))
+		})
+		var result CacheListOutput
+		require.NoError(t, json.Unmarshal([]byte(output), &result))
+		return result
+	}
+
+	t.Run("list", func(t *testing.T) {
+		result := list(t)
+		require.Len(t, result.Entries, 1)
+		entry := result.Entries[0]
+		assert.Equal(t, url, entry.Source)
+		assert.Equal(t, int64(3600), entry.TTLSeconds)
+		assert.False(t, entry.Expired)
+		assert.Equal(t, loader.CacheDir(), result.Dir)
+	})
+
+	t.Run("refresh", func(t *testing.T) {
+		output := captureOutput(func() {
+			require.NoError(t, runCacheRefresh(loader, url, GlobalFlags{JSON: true}))
+		})
+		assert.JSONEq(t, fmt.Sprintf(`{"source": %q, "changed": false}`, url), output)
+	})
+
+	t.Run("clear single entry", func(t *testing.T) {
+		err := runCacheClear(loader, server.URL+"/other.tmpl", GlobalFlags{Quiet: true})
+		assert.ErrorContains(t, err, "no cache entry")
+
+		require.NoError(t, runCacheClear(loader, url, GlobalFlags{Quiet: true}))
+		assert.Empty(t, list(t).Entries)
+	})
+}
+
+func TestFormatCacheAge(t *testing.T) {
+	assert.Equal(t, "45s", formatCacheAge(45*time.Second))
+	assert.Equal(t, "12m", formatCacheAge(12*time.Minute))
+	assert.Equal(t, "3h20m", formatCacheAge(3*time.Hour+20*time.Minute))
+	assert.Equal(t, "2d4h", formatCacheAge(52*time.Hour))
+}
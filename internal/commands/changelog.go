@@ -0,0 +1,233 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/consignment"
+	"github.com/NatoNathan/shipyard/internal/errors"
+	"github.com/NatoNathan/shipyard/internal/fileutil"
+	"github.com/NatoNathan/shipyard/internal/history"
+	"github.com/NatoNathan/shipyard/internal/release"
+	"github.com/NatoNathan/shipyard/internal/template"
+	"github.com/spf13/cobra"
+)
+
+// ChangelogOptions holds options for the changelog command
+type ChangelogOptions struct {
+	Packages    []string
+	FromVersion string // Oldest release to include
+	ToVersion   string // Newest release to include
+	Since       string // Only releases made on or after this date
+	Template    string
+	Output      string
+	Channel     string // Read this channel's history instead of the branch's
+}
+
+// NewChangelogCommand creates the changelog command
+func NewChangelogCommand() *cobra.Command {
+	opts := &ChangelogOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "changelog [-p package]... [--from-version version] [--to-version version] [--since date] [--template template] [-o file]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Copy the captain's log into a changelog",
+		Long: `Render changelogs from the captain's log without touching the fleet. Reads
+the version history, including archived entries, and prints each package's
+changelog with its configured template, or writes it to a file.
+
+Nothing is versioned, committed or tagged, and no changelog file in the
+repository is rewritten. Select releases by version range or date; versions
+are compared in each package's own versioning scheme.`,
+		Example: `  # Print every package's changelog
+  shipyard changelog
+
+  # Releases of core from 1.2.0 up to and including 1.4.0
+  shipyard changelog --package core --from-version 1.2.0 --to-version 1.4.0
+
+  # Everything released this year, with another template, to a file
+  shipyard changelog --since 2024-01-01 --template builtin:keepachangelog --output CHANGES.md`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cwd, err := projectRoot()
+			if err != nil {
+				return err
+			}
+			return runChangelog(cwd, opts)
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&opts.Packages, "package", "p", []string{}, "Limit to specific packages (can be specified multiple times)")
+	cmd.Flags().StringVar(&opts.FromVersion, "from-version", "", "Oldest release to include")
+	cmd.Flags().StringVar(&opts.ToVersion, "to-version", "", "Newest release to include")
+	cmd.Flags().StringVar(&opts.Since, "since", "", "Only releases made on or after this date (YYYY-MM-DD or RFC 3339)")
+	cmd.Flags().StringVar(&opts.Template, "template", "", "Changelog template for every package, overriding the config (path or builtin name)")
+	cmd.Flags().StringVarP(&opts.Output, "output", "o", "", "Output file (default: stdout)")
+	cmd.Flags().StringVar(&opts.Channel, "channel", "", "Read this channel's history (default: the channel mapped to the current branch)")
+
+	RegisterPackageCompletions(cmd, "package")
+
+	return cmd
+}
+
+// runChangelog renders the selected changelogs to stdout or the output file
+func runChangelog(projectPath string, opts *ChangelogOptions) error {
+	cfg, err := config.LoadFromDir(projectPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	query := changelogQuery{
+		Packages:    opts.Packages,
+		FromVersion: opts.FromVersion,
+		ToVersion:   opts.ToVersion,
+		Template:    opts.Template,
+	}
+	if opts.Template != "" {
+		if err := template.ValidateTemplate(opts.Template, template.TemplateTypeChangelog); err != nil {
+			return errors.NewValidationError("template", err.Error())
+		}
+	}
+	if opts.Since != "" {
+		if query.Since, err = parseSinceDate(opts.Since); err != nil {
+			return err
+		}
+	}
+	if query.Channel, err = release.ResolveChannel(projectPath, cfg, opts.Channel); err != nil {
+		return err
+	}
+
+	changelogs, err := renderChangelogs(projectPath, cfg, release.SettingsFor(projectPath, cfg), query)
+	if err != nil {
+		return err
+	}
+	if len(changelogs) == 0 {
+		return fmt.Errorf("no releases recorded in history for the selected packages")
+	}
+
+	contents := make([]string, len(changelogs))
+	for i, c := range changelogs {
+		contents[i] = strings.TrimRight(c.Content, "\n") + "\n"
+	}
+	output := strings.Join(contents, "\n")
+	if opts.Output != "" {
+		return fileutil.WriteFile(opts.Output, []byte(output), 0644)
+	}
+	_, err = fmt.Print(output)
+	return err
+}
+
+// changelogQuery selects the history changelogs are rendered from
+type changelogQuery struct {
+	Packages    []string  // Only these packages; all when empty
+	Channel     string    // Release channel whose history is read
+	FromVersion string    // Oldest release to include; open when empty
+	ToVersion   string    // Newest release to include; open when empty
+	Since       time.Time // Only releases made on or after this time; all when zero
+	Template    string    // Template overriding each package's configured one
+	Unreleased  bool      // List pending consignments under Unreleased
+}
+
+// filtered reports whether the query selects some releases only
+func (q changelogQuery) filtered() bool {
+	return q.FromVersion != "" || q.ToVersion != "" || !q.Since.IsZero()
+}
+
+// describe renders the query's release selection for messages
+func (q changelogQuery) describe() string {
+	var parts []string
+	if q.FromVersion != "" {
+		parts = append(parts, "from "+q.FromVersion)
+	}
+	if q.ToVersion != "" {
+		parts = append(parts, "to "+q.ToVersion)
+	}
+	if !q.Since.IsZero() {
+		parts = append(parts, "since "+q.Since.Format(time.RFC3339))
+	}
+	return strings.Join(parts, " ")
+}
+
+// renderedChangelog is a package's changelog rendered from history
+type renderedChangelog struct {
+	Package config.Package
+	Content string
+}
+
+// renderChangelogs renders the changelog of each selected package, in config
+// order, from the releases the query selects. Packages without any are left
+// out. A query that selects no release of any package is an error listing the
+// versions that are available.
+func renderChangelogs(projectPath string, cfg *config.Config, settings release.Settings, q changelogQuery) ([]renderedChangelog, error) {
+	for _, name := range q.Packages {
+		if _, ok := cfg.GetPackage(name); !ok {
+			return nil, fmt.Errorf("package %s not found in configuration", name)
+		}
+	}
+
+	// Older releases may have been compacted into the yearly archives
+	allEntries, err := settings.History(filepath.Join(projectPath, cfg.HistoryPathFor(q.Channel))).ReadWithArchives()
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read history for changelog generation: %w", err)
+	}
+	var pending []*consignment.Consignment
+	if q.Unreleased {
+		if pending, err = consignment.ReadAllConsignments(filepath.Join(projectPath, cfg.Consignments.Path)); err != nil {
+			return nil, fmt.Errorf("failed to read consignments: %w", err)
+		}
+	}
+
+	var changelogs []renderedChangelog
+	var available []string
+	for _, pkg := range cfg.Packages {
+		if len(q.Packages) > 0 && !slices.Contains(q.Packages, pkg.Name) {
+			continue
+		}
+		pkgEntries := history.FilterByPackage(allEntries, pkg.Name, pkg.Aliases...)
+		selected := pkgEntries
+		if q.FromVersion != "" || q.ToVersion != "" {
+			if selected, err = history.FilterByVersionRange(selected, q.FromVersion, q.ToVersion, pkg.ParseVersion); err != nil {
+				return nil, fmt.Errorf("%s: %w", pkg.Name, err)
+			}
+		}
+		if !q.Since.IsZero() {
+			selected = history.FilterSince(selected, q.Since)
+		}
+		if unreleased, ok := release.UnreleasedEntry(pkg.Name, pending); ok {
+			selected = append(selected, unreleased)
+		}
+		if len(pkgEntries) > 0 {
+			available = append(available, pkg.Name+" "+entryVersions(pkgEntries))
+		}
+		if len(selected) == 0 {
+			continue
+		}
+
+		content, err := release.RenderChangelog(cfg, settings, pkg, selected, q.Template)
+		if err != nil {
+			return nil, err
+		}
+		changelogs = append(changelogs, renderedChangelog{Package: pkg, Content: content})
+	}
+
+	if len(changelogs) == 0 && q.filtered() {
+		if len(available) == 0 {
+			return nil, fmt.Errorf("no releases %s: history has no releases of the selected packages", q.describe())
+		}
+		return nil, fmt.Errorf("no releases %s; available versions: %s", q.describe(), strings.Join(available, "; "))
+	}
+	return changelogs, nil
+}
+
+// entryVersions lists the versions of entries in history order
+func entryVersions(entries []history.Entry) string {
+	versions := make([]string, len(entries))
+	for i, entry := range entries {
+		versions[i] = entry.Version
+	}
+	return strings.Join(versions, ", ")
+}
@@ -0,0 +1,127 @@
+package commands
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/history"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupChangelogProject writes a two-package project whose history holds
+// releases of both packages, one month apart, in release order
+func setupChangelogProject(t *testing.T) string {
+	t.Helper()
+	tempDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDir, ".shipyard", "consignments"), 0755))
+	configContent := `packages:
+  - name: core
+    path: ./core
+    ecosystem: go
+  - name: api
+    path: ./api
+    ecosystem: go
+consignments:
+  path: .shipyard/consignments
+history:
+  path: .shipyard/history.json
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, ".shipyard", "shipyard.yaml"), []byte(configContent), 0644))
+
+	var entries []history.Entry
+	for i, release := range []struct{ pkg, version, summary string }{
+		{"core", "1.0.0", "Core launch"},
+		{"api", "0.9.0", "API preview"},
+		{"core", "1.2.0", "Core streaming"},
+		{"api", "1.1.0", "API pagination"},
+		{"core", "1.10.0", "Core plugins"},
+	} {
+		entries = append(entries, history.Entry{
+			Version:   release.version,
+			Package:   release.pkg,
+			Tag:       release.pkg + "/v" + release.version,
+			Timestamp: time.Date(2024, time.Month(i+1), 1, 0, 0, 0, 0, time.UTC),
+			Consignments: []history.Consignment{
+				{ID: release.pkg + release.version, Summary: release.summary, ChangeType: "minor"},
+			},
+		})
+	}
+	data, err := json.Marshal(entries)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, ".shipyard", "history.json"), data, 0644))
+	return tempDir
+}
+
+func TestChangelogCommand(t *testing.T) {
+	tempDir := setupChangelogProject(t)
+	render := func(t *testing.T, opts *ChangelogOptions) string {
+		t.Helper()
+		var err error
+		output := captureOutput(func() { err = runChangelog(tempDir, opts) })
+		require.NoError(t, err)
+		return output
+	}
+
+	t.Run("all releases", func(t *testing.T) {
+		output := render(t, &ChangelogOptions{})
+		for _, summary := range []string{"Core launch", "Core streaming", "Core plugins", "API preview", "API pagination"} {
+			assert.Contains(t, output, summary)
+		}
+		assert.NoFileExists(t, filepath.Join(tempDir, "core", "CHANGELOG.md"), "the command only prints")
+	})
+
+	t.Run("range spanning packages", func(t *testing.T) {
+		output := render(t, &ChangelogOptions{FromVersion: "1.0.0", ToVersion: "v1.5.0"})
+		assert.Contains(t, output, "Core launch")
+		assert.Contains(t, output, "Core streaming")
+		assert.Contains(t, output, "API pagination")
+		assert.NotContains(t, output, "Core plugins", "1.10.0 is above 1.5.0")
+		assert.NotContains(t, output, "API preview", "0.9.0 is below 1.0.0")
+	})
+
+	t.Run("open-ended range for one package", func(t *testing.T) {
+		output := render(t, &ChangelogOptions{Packages: []string{"core"}, FromVersion: "1.2.0"})
+		assert.Contains(t, output, "Core streaming")
+		assert.Contains(t, output, "Core plugins")
+		assert.NotContains(t, output, "Core launch")
+		assert.NotContains(t, output, "API")
+	})
+
+	t.Run("since", func(t *testing.T) {
+		output := render(t, &ChangelogOptions{Since: "2024-03-01"})
+		assert.Contains(t, output, "Core streaming")
+		assert.Contains(t, output, "API pagination")
+		assert.Contains(t, output, "Core plugins")
+		assert.NotContains(t, output, "Core launch")
+		assert.NotContains(t, output, "API preview")
+	})
+
+	t.Run("output file", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "CHANGES.md")
+		assert.Empty(t, render(t, &ChangelogOptions{Packages: []string{"api"}, Output: outputPath}))
+		content, err := os.ReadFile(outputPath)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "API pagination")
+		assert.NotContains(t, string(content), "Core")
+	})
+
+	t.Run("empty range lists available versions", func(t *testing.T) {
+		err := runChangelog(tempDir, &ChangelogOptions{FromVersion: "2.0.0", ToVersion: "3.0.0"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no releases from 2.0.0 to 3.0.0")
+		assert.Contains(t, err.Error(), "available versions: core 1.0.0, 1.2.0, 1.10.0; api 0.9.0, 1.1.0")
+	})
+
+	t.Run("invalid selection", func(t *testing.T) {
+		err := runChangelog(tempDir, &ChangelogOptions{Packages: []string{"web"}})
+		assert.ErrorContains(t, err, "package web not found")
+		err = runChangelog(tempDir, &ChangelogOptions{FromVersion: "latest"})
+		assert.ErrorContains(t, err, `invalid from version "latest"`)
+		err = runChangelog(tempDir, &ChangelogOptions{Since: "yesterday"})
+		assert.ErrorContains(t, err, "invalid --since")
+	})
+}
@@ -0,0 +1,364 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/consignment"
+	"github.com/NatoNathan/shipyard/internal/git"
+	"github.com/NatoNathan/shipyard/internal/history"
+	"github.com/NatoNathan/shipyard/internal/release"
+	"github.com/NatoNathan/shipyard/internal/ui"
+	"github.com/NatoNathan/shipyard/pkg/semver"
+	"github.com/NatoNathan/shipyard/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// ChannelPromoteOptions holds options for the channel promote command
+type ChannelPromoteOptions struct {
+	From     string
+	To       string
+	Packages []string
+	Preview  bool
+	NoTag    bool
+	JSON     bool
+	Quiet    bool
+}
+
+// ChannelPromoteOutput is the JSON output of the channel promote command
+type ChannelPromoteOutput struct {
+	From     string                   `json:"from"`
+	To       string                   `json:"to"`
+	Packages []ChannelPromotedPackage `json:"packages"`
+	Preview  bool                     `json:"preview,omitempty"`
+}
+
+// ChannelPromotedPackage is one release copied to another channel
+type ChannelPromotedPackage struct {
+	Name        string `json:"name"`
+	FromVersion string `json:"fromVersion"`
+	Version     string `json:"version"`
+	FromTag     string `json:"fromTag"`
+	Tag         string `json:"tag"`
+	Tagged      bool   `json:"tagged"`
+}
+
+// NewChannelPromoteCommand creates the channel promote command
+func NewChannelPromoteCommand() *cobra.Command {
+	opts := &ChannelPromoteOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "promote [--from CHANNEL] [--to CHANNEL] [-p package]... [--preview] [--no-tag]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Move a tested shipment into another channel",
+		Long: `Copy each package's latest release on one channel into another channel's
+history and tag the same commit for it. Promoting 1.3.0-beta.2 from beta to
+stable records 1.3.0 in the stable history and tags the commit tagged for
+1.3.0-beta.2 with the stable tag, e.g. v1.3.0.
+
+Version files and changelogs are not changed; regenerate changelogs with
+'shipyard version --regenerate --channel stable' on the branch that ships them.
+
+--from defaults to the only configured channel other than stable, and --to
+defaults to stable.`,
+		Example: `  # Promote the latest beta of every package to stable
+  shipyard channel promote --from beta
+
+  # Promote one package, showing the plan first
+  shipyard channel promote --from beta --package core --preview
+
+  # Record the promotion without creating tags
+  shipyard channel promote --from beta --no-tag`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			globalFlags := GetGlobalFlags(cmd)
+			opts.JSON = globalFlags.JSON
+			opts.Quiet = globalFlags.Quiet
+			return runChannelPromote(opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.From, "from", "", "Channel to promote from (default: the only channel other than stable)")
+	cmd.Flags().StringVar(&opts.To, "to", config.StableChannel, "Channel to promote to")
+	cmd.Flags().StringSliceVarP(&opts.Packages, "package", "p", []string{}, "Promote only these packages (can be specified multiple times)")
+	cmd.Flags().BoolVar(&opts.Preview, "preview", false, "Show what would be promoted without changing anything")
+	cmd.Flags().BoolVar(&opts.NoTag, "no-tag", false, "Record the promotion in history without creating tags")
+
+	RegisterPackageCompletions(cmd, "package")
+
+	return cmd
+}
+
+func runChannelPromote(opts *ChannelPromoteOptions) error {
+	cwd, err := projectRoot()
+	if err != nil {
+		return err
+	}
+	return runChannelPromoteWithDir(cwd, opts)
+}
+
+// channelPromotion is a planned promotion of one package's release
+type channelPromotion struct {
+	pkg     config.Package
+	source  history.Entry
+	version semver.Version
+	tag     string
+	message string
+}
+
+func runChannelPromoteWithDir(projectPath string, opts *ChannelPromoteOptions) error {
+	cfg, err := config.LoadFromDir(projectPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	settings := release.SettingsFor(projectPath, cfg)
+
+	from, to, err := promotionChannels(cfg, opts.From, opts.To)
+	if err != nil {
+		return err
+	}
+
+	fromPath := filepath.Join(projectPath, cfg.HistoryPathFor(from))
+	fromEntries, err := settings.History(fromPath).Read()
+	if os.IsNotExist(err) {
+		return fmt.Errorf("channel %s has no releases (%s does not exist)", from, cfg.HistoryPathFor(from))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s history: %w", from, err)
+	}
+	toPath := filepath.Join(projectPath, cfg.HistoryPathFor(to))
+	toEntries, err := settings.History(toPath).Read()
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s history: %w", to, err)
+	}
+
+	for _, name := range opts.Packages {
+		if _, ok := cfg.GetPackage(name); !ok {
+			return fmt.Errorf("package %s not found in configuration", name)
+		}
+	}
+
+	generator := release.NewGenerator(projectPath, cfg, settings)
+	generator.SetChannel(to)
+
+	var promotions []channelPromotion
+	for _, pkg := range cfg.Packages {
+		if len(opts.Packages) > 0 && !slices.Contains(opts.Packages, pkg.Name) {
+			continue
+		}
+		source, ok := latestEntry(history.FilterByPackage(fromEntries, pkg.Name, pkg.Aliases...))
+		if !ok {
+			if len(opts.Packages) > 0 {
+				return fmt.Errorf("%s has no release on channel %s", pkg.Name, from)
+			}
+			continue
+		}
+		if source.Yanked {
+			return fmt.Errorf("%s %s on channel %s was yanked; it cannot be promoted", pkg.Name, source.Version, from)
+		}
+
+		sourceVersion, err := pkg.ParseVersion(source.Version)
+		if err != nil {
+			return fmt.Errorf("invalid version %s for %s on channel %s: %w", source.Version, pkg.Name, from, err)
+		}
+		target := promotedVersion(pkg, sourceVersion, to, history.FilterByPackage(toEntries, pkg.Name, pkg.Aliases...))
+		if len(history.FilterByVersion(history.FilterByPackage(toEntries, pkg.Name, pkg.Aliases...), target.String())) > 0 {
+			return fmt.Errorf("%s %s is already on channel %s", pkg.Name, target, to)
+		}
+
+		tag, message, err := release.PackageTag(generator, cfg, pkg, entryConsignments(pkg.Name, source), target)
+		if err != nil {
+			return fmt.Errorf("failed to generate tag for package %s: %w", pkg.Name, err)
+		}
+		promotions = append(promotions, channelPromotion{pkg: pkg, source: source, version: target, tag: tag, message: message})
+	}
+	if len(promotions) == 0 {
+		return fmt.Errorf("channel %s has no releases to promote", from)
+	}
+
+	output := ChannelPromoteOutput{From: from, To: to, Preview: opts.Preview}
+	for _, p := range promotions {
+		output.Packages = append(output.Packages, ChannelPromotedPackage{
+			Name:        p.pkg.Name,
+			FromVersion: p.source.Version,
+			Version:     p.version.String(),
+			FromTag:     p.source.VersionTag(),
+			Tag:         p.tag,
+			Tagged:      !opts.NoTag && !opts.Preview,
+		})
+	}
+
+	if opts.Preview {
+		if opts.JSON {
+			return PrintJSON(os.Stdout, output)
+		}
+		if !opts.Quiet {
+			printChannelPromotions(output, fmt.Sprintf("Preview: promote %s to %s", from, to))
+			fmt.Println(ui.InfoMessage("Preview mode: no changes made"))
+		}
+		return nil
+	}
+
+	// Tags are created before history is written and removed again if
+	// writing it fails, so a failed promotion leaves nothing behind
+	var created []string
+	if !opts.NoTag {
+		tagNames := make([]string, len(promotions))
+		for i, p := range promotions {
+			tagNames[i] = p.tag
+		}
+		if err := git.EnsureTagsAbsent(projectPath, tagNames); err != nil {
+			return err
+		}
+		for _, p := range promotions {
+			if err := git.CreateTagAt(projectPath, p.tag, p.source.VersionTag(), p.message); err != nil {
+				return removePromotionTags(projectPath, created, fmt.Errorf("failed to tag %s %s: %w", p.pkg.Name, p.version, err))
+			}
+			created = append(created, p.tag)
+		}
+	}
+
+	now := time.Now()
+	entries := make([]history.Entry, len(promotions))
+	for i, p := range promotions {
+		entry := p.source
+		entry.Package = p.pkg.Name // the source may be recorded under a former name
+		entry.Version = p.version.String()
+		entry.PreviousVersion = ""
+		if previous, ok := latestEntry(history.FilterByPackage(toEntries, p.pkg.Name, p.pkg.Aliases...)); ok {
+			entry.PreviousVersion = previous.Version
+		}
+		entry.Tag = p.tag
+		entry.Timestamp = now
+		entry.Channel = to
+		entry.PromotedFrom = p.source.Version
+		entry.Artifacts = nil
+		entry.Notes = nil
+		entries[i] = entry
+	}
+	if err := history.EnsureHistory(toPath); err != nil {
+		return removePromotionTags(projectPath, created, fmt.Errorf("failed to record promotion: %w", err))
+	}
+	if err := settings.History(toPath).Append(entries); err != nil {
+		return removePromotionTags(projectPath, created, fmt.Errorf("failed to record promotion: %w", err))
+	}
+
+	if opts.JSON {
+		return PrintJSON(os.Stdout, output)
+	}
+	if !opts.Quiet {
+		printChannelPromotions(output, fmt.Sprintf("Promoted %s to %s", from, to))
+		fmt.Println(ui.SuccessMessage(fmt.Sprintf("Recorded %d release(s) in %s", len(entries), cfg.HistoryPathFor(to))))
+		if opts.NoTag {
+			fmt.Println(ui.Dimmed("Skipped git tags (--no-tag)"))
+		}
+	}
+	return nil
+}
+
+// promotionChannels resolves the --from and --to channels
+func promotionChannels(cfg *config.Config, from, to string) (string, string, error) {
+	if from == "" {
+		var candidates []string
+		for _, channel := range cfg.Channels {
+			if channel.Name != config.StableChannel {
+				candidates = append(candidates, channel.Name)
+			}
+		}
+		if len(candidates) != 1 {
+			return "", "", fmt.Errorf("pass --from to choose the channel to promote from")
+		}
+		from = candidates[0]
+	}
+	if to == "" {
+		to = config.StableChannel
+	}
+	for _, channel := range []string{from, to} {
+		if !cfg.HasChannel(channel) {
+			return "", "", fmt.Errorf("unknown channel %q", channel)
+		}
+	}
+	if from == to {
+		return "", "", fmt.Errorf("cannot promote channel %s to itself", from)
+	}
+	return from, to, nil
+}
+
+// promotedVersion returns the version a release becomes on the target
+// channel: its base version on stable, otherwise the next pre-release of that
+// base named after the channel, e.g. 1.3.0-rc.1 then 1.3.0-rc.2
+func promotedVersion(pkg config.Package, source semver.Version, channel string, targetEntries []history.Entry) semver.Version {
+	base := source.BaseVersion()
+	id := release.ChannelPrereleaseID(channel)
+	if id == "" {
+		return base
+	}
+	next := base.BumpPrerelease(id)
+	for _, entry := range targetEntries {
+		v, err := pkg.ParseVersion(entry.Version)
+		if err != nil || v.BaseVersion().Compare(base) != 0 {
+			continue
+		}
+		if candidate := v.BumpPrerelease(id); candidate.Compare(next) > 0 {
+			next = candidate
+		}
+	}
+	return next
+}
+
+// latestEntry returns the most recently released entry; later entries win ties
+func latestEntry(entries []history.Entry) (history.Entry, bool) {
+	var latest history.Entry
+	found := false
+	for _, entry := range entries {
+		if !found || !entry.Timestamp.Before(latest.Timestamp) {
+			latest, found = entry, true
+		}
+	}
+	return latest, found
+}
+
+// entryConsignments converts a history entry's consignments back to the
+// form tag templates receive
+func entryConsignments(packageName string, entry history.Entry) []*consignment.Consignment {
+	consignments := make([]*consignment.Consignment, len(entry.Consignments))
+	for i, c := range entry.Consignments {
+		consignments[i] = &consignment.Consignment{
+			ID:         c.ID,
+			Timestamp:  entry.Timestamp,
+			Packages:   []string{packageName},
+			ChangeType: types.ChangeType(c.ChangeType),
+			Summary:    c.Summary,
+			Metadata:   c.Metadata,
+		}
+	}
+	return consignments
+}
+
+// removePromotionTags deletes the tags a failed promotion created
+func removePromotionTags(projectPath string, tags []string, err error) error {
+	if len(tags) == 0 {
+		return err
+	}
+	if deleteErr := git.DeleteTags(projectPath, tags); deleteErr != nil {
+		return fmt.Errorf("%w; additionally failed to remove tags it created: %v", err, deleteErr)
+	}
+	return err
+}
+
+// printChannelPromotions prints the promotions as a table under a header
+func printChannelPromotions(output ChannelPromoteOutput, header string) {
+	fmt.Println()
+	fmt.Println(ui.Header("\U0001F4E6", header))
+	fmt.Println()
+	rows := make([][]string, len(output.Packages))
+	for i, p := range output.Packages {
+		rows[i] = []string{p.Name, p.FromVersion, p.Version, p.FromTag, p.Tag}
+	}
+	fmt.Println(ui.Table([]string{"Package", output.From, output.To, "From tag", "Tag"}, rows))
+	fmt.Println()
+}
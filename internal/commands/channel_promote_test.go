@@ -0,0 +1,122 @@
+package commands
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/history"
+	"github.com/NatoNathan/shipyard/pkg/semver"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tagCommit returns the commit a tag points at
+func tagCommit(t *testing.T, repo *gogit.Repository, name string) plumbing.Hash {
+	t.Helper()
+	ref, err := repo.Tag(name)
+	require.NoError(t, err)
+	if tag, err := repo.TagObject(ref.Hash()); err == nil {
+		commit, err := tag.Commit()
+		require.NoError(t, err)
+		return commit.Hash
+	}
+	return ref.Hash()
+}
+
+// setupPromoteTestRepo releases two betas and then moves main on, so the
+// latest beta's commit is no longer HEAD
+func setupPromoteTestRepo(t *testing.T) (string, *gogit.Repository) {
+	t.Helper()
+	tempDir, repo := setupChannelTestRepo(t)
+	releaseOnChannel(t, tempDir, repo, "c1", "minor", "Add feature", &VersionCommandOptions{})
+	releaseOnChannel(t, tempDir, repo, "c2", "patch", "Fix bug", &VersionCommandOptions{})
+	createTestConsignmentForVersion(t, filepath.Join(tempDir, ".shipyard", "consignments"), "c3", []string{"test-package"}, "minor", "Unreleased work")
+	commitChannelTestRepo(t, repo, "Add c3")
+	return tempDir, repo
+}
+
+func TestChannelPromote(t *testing.T) {
+	tempDir, repo := setupPromoteTestRepo(t)
+
+	captureOutput(func() {
+		require.NoError(t, runChannelPromoteWithDir(tempDir, &ChannelPromoteOptions{}))
+	})
+
+	stable := readChannelHistory(t, tempDir, "history.json")
+	require.Len(t, stable, 1)
+	assert.Equal(t, "1.1.0", stable[0].Version)
+	assert.Equal(t, "stable", stable[0].Channel)
+	assert.Equal(t, "1.1.0-beta.2", stable[0].PromotedFrom)
+	assert.Equal(t, "v1.1.0", stable[0].Tag)
+	assert.Empty(t, stable[0].PreviousVersion)
+	require.Len(t, stable[0].Consignments, 1)
+	assert.Contains(t, stable[0].Consignments[0].Summary, "Fix bug")
+	assert.Len(t, readChannelHistory(t, tempDir, "history.beta.json"), 2, "the beta history is left as it was")
+
+	assert.Equal(t, tagCommit(t, repo, "v1.1.0-beta.2"), tagCommit(t, repo, "v1.1.0"), "the stable tag marks the tested commit")
+
+	err := runChannelPromoteWithDir(tempDir, &ChannelPromoteOptions{From: "beta"})
+	assert.ErrorContains(t, err, "test-package 1.1.0 is already on channel stable")
+}
+
+func TestChannelPromote_Preview(t *testing.T) {
+	tempDir, repo := setupPromoteTestRepo(t)
+
+	output := captureOutput(func() {
+		require.NoError(t, runChannelPromoteWithDir(tempDir, &ChannelPromoteOptions{Preview: true}))
+	})
+
+	assert.Contains(t, output, "1.1.0-beta.2")
+	assert.Contains(t, output, "v1.1.0")
+	assert.Empty(t, readChannelHistory(t, tempDir, "history.json"))
+	assert.NotContains(t, tagNames(t, repo), "v1.1.0")
+}
+
+func TestChannelPromote_NoTag(t *testing.T) {
+	tempDir, repo := setupPromoteTestRepo(t)
+
+	captureOutput(func() {
+		require.NoError(t, runChannelPromoteWithDir(tempDir, &ChannelPromoteOptions{NoTag: true}))
+	})
+
+	assert.Len(t, readChannelHistory(t, tempDir, "history.json"), 1)
+	assert.Equal(t, []string{"v1.1.0-beta.1", "v1.1.0-beta.2"}, tagNames(t, repo))
+}
+
+func TestChannelPromote_Errors(t *testing.T) {
+	tempDir, _ := setupPromoteTestRepo(t)
+
+	tests := []struct {
+		name string
+		opts *ChannelPromoteOptions
+		want string
+	}{
+		{"unknown channel", &ChannelPromoteOptions{From: "nightly"}, `unknown channel "nightly"`},
+		{"same channel", &ChannelPromoteOptions{From: "beta", To: "beta"}, "cannot promote channel beta to itself"},
+		{"channel without releases", &ChannelPromoteOptions{From: "stable", To: "beta"}, "channel stable has no releases"},
+		{"unknown package", &ChannelPromoteOptions{Packages: []string{"missing"}}, "package missing not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorContains(t, runChannelPromoteWithDir(tempDir, tt.opts), tt.want)
+		})
+	}
+	assert.Empty(t, readChannelHistory(t, tempDir, "history.json"))
+}
+
+func TestPromotedVersion(t *testing.T) {
+	pkg := config.Package{Name: "core", Ecosystem: "go"}
+	source := semver.MustParse("1.3.0-beta.2")
+
+	assert.Equal(t, "1.3.0", promotedVersion(pkg, source, config.StableChannel, nil).String())
+	assert.Equal(t, "1.3.0-rc.1", promotedVersion(pkg, source, "rc", nil).String())
+
+	existing := []history.Entry{
+		{Package: "core", Version: "1.3.0-rc.1"},
+		{Package: "core", Version: "1.2.0-rc.4"},
+	}
+	assert.Equal(t, "1.3.0-rc.2", promotedVersion(pkg, source, "rc", existing).String())
+}
@@ -0,0 +1,233 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/NatoNathan/shipyard/internal/history"
+	"github.com/NatoNathan/shipyard/internal/runstate"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// channelsConfig maps main to the beta channel and release branches to stable
+const channelsConfig = `channels:
+  - name: beta
+    branches: [main]
+  - name: stable
+    branches: ["release/*"]
+`
+
+// setupChannelTestRepo creates a repository on main, which releases on the
+// beta channel, with nothing pending
+func setupChannelTestRepo(t *testing.T) (string, *gogit.Repository) {
+	t.Helper()
+	tempDir := setupVersionTestRepo(t)
+	configPath := filepath.Join(tempDir, ".shipyard", "shipyard.yaml")
+	content, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(configPath, append(content, channelsConfig...), 0644))
+
+	repo, err := gogit.PlainInit(tempDir, false)
+	require.NoError(t, err)
+	require.NoError(t, repo.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.NewBranchReferenceName("main"))))
+	commitChannelTestRepo(t, repo, "initial commit")
+	return tempDir, repo
+}
+
+// commitChannelTestRepo commits every change in the repository
+func commitChannelTestRepo(t *testing.T, repo *gogit.Repository, message string) plumbing.Hash {
+	t.Helper()
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+	_, err = wt.Add(".")
+	require.NoError(t, err)
+	head, err := wt.Commit(message, &gogit.CommitOptions{
+		Author: &object.Signature{Name: "Test", Email: "test@example.com"},
+	})
+	require.NoError(t, err)
+	return head
+}
+
+// releaseOnChannel commits a pending consignment and releases it
+func releaseOnChannel(t *testing.T, dir string, repo *gogit.Repository, id, changeType, summary string, opts *VersionCommandOptions) {
+	t.Helper()
+	createTestConsignmentForVersion(t, filepath.Join(dir, ".shipyard", "consignments"), id, []string{"test-package"}, changeType, summary)
+	commitChannelTestRepo(t, repo, "Add "+id)
+	captureOutput(func() {
+		require.NoError(t, runVersionWithDir(dir, opts))
+	})
+}
+
+// readChannelHistory reads a history file relative to .shipyard
+func readChannelHistory(t *testing.T, dir, name string) []history.Entry {
+	t.Helper()
+	entries, err := history.ReadHistory(filepath.Join(dir, ".shipyard", name))
+	require.NoError(t, err)
+	return entries
+}
+
+func TestVersionCommand_ChannelFromBranch(t *testing.T) {
+	tempDir, repo := setupChannelTestRepo(t)
+
+	// main releases betas into the beta history
+	releaseOnChannel(t, tempDir, repo, "c1", "minor", "Add beta feature", &VersionCommandOptions{})
+	releaseOnChannel(t, tempDir, repo, "c2", "patch", "Fix beta bug", &VersionCommandOptions{})
+
+	assert.Contains(t, readFile(t, filepath.Join(tempDir, "test-package", "version.go")), `"1.1.0-beta.2"`)
+	beta := readChannelHistory(t, tempDir, "history.beta.json")
+	require.Len(t, beta, 2)
+	assert.Equal(t, []string{"1.1.0-beta.1", "1.1.0-beta.2"}, []string{beta[0].Version, beta[1].Version})
+	assert.Equal(t, "beta", beta[1].Channel)
+	assert.Equal(t, "1.1.0-beta.1", beta[1].PreviousVersion)
+	assert.Empty(t, readChannelHistory(t, tempDir, "history.json"), "beta releases stay out of the stable history")
+	assert.Equal(t, []string{"v1.1.0-beta.1", "v1.1.0-beta.2"}, tagNames(t, repo))
+
+	// A release branch releases on stable and only sees the stable history
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+	require.NoError(t, wt.Checkout(&gogit.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("release/1.x"), Create: true}))
+	releaseOnChannel(t, tempDir, repo, "c3", "patch", "Fix stable bug", &VersionCommandOptions{})
+
+	stable := readChannelHistory(t, tempDir, "history.json")
+	require.Len(t, stable, 1)
+	assert.Equal(t, "1.1.0", stable[0].Version)
+	assert.Equal(t, "stable", stable[0].Channel)
+	assert.Len(t, readChannelHistory(t, tempDir, "history.beta.json"), 2, "the stable release leaves the beta history alone")
+
+	changelog := readFile(t, filepath.Join(tempDir, "test-package", "CHANGELOG.md"))
+	assert.Contains(t, changelog, "Fix stable bug")
+	assert.NotContains(t, changelog, "Add beta feature", "the stable changelog lists stable releases only")
+	assert.NotContains(t, changelog, "1.1.0-beta")
+}
+
+func TestVersionCommand_ChannelFlag(t *testing.T) {
+	t.Run("overrides the branch mapping", func(t *testing.T) {
+		tempDir, repo := setupChannelTestRepo(t)
+		releaseOnChannel(t, tempDir, repo, "c1", "minor", "Add feature", &VersionCommandOptions{Channel: "stable", NoTag: true})
+
+		assert.Len(t, readChannelHistory(t, tempDir, "history.json"), 1)
+		assert.NoFileExists(t, filepath.Join(tempDir, ".shipyard", "history.beta.json"))
+		assert.Contains(t, readFile(t, filepath.Join(tempDir, "test-package", "version.go")), `"1.1.0"`)
+	})
+
+	t.Run("works without a branch mapping", func(t *testing.T) {
+		tempDir, repo := setupChannelTestRepo(t)
+		wt, err := repo.Worktree()
+		require.NoError(t, err)
+		require.NoError(t, wt.Checkout(&gogit.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("feature"), Create: true}))
+		releaseOnChannel(t, tempDir, repo, "c1", "minor", "Add feature", &VersionCommandOptions{Channel: "beta", NoCommit: true, NoTag: true})
+
+		assert.Len(t, readChannelHistory(t, tempDir, "history.beta.json"), 1)
+	})
+
+	t.Run("unmapped branches release on stable", func(t *testing.T) {
+		tempDir, repo := setupChannelTestRepo(t)
+		wt, err := repo.Worktree()
+		require.NoError(t, err)
+		require.NoError(t, wt.Checkout(&gogit.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("feature"), Create: true}))
+		releaseOnChannel(t, tempDir, repo, "c1", "minor", "Add feature", &VersionCommandOptions{NoCommit: true, NoTag: true})
+
+		assert.Len(t, readChannelHistory(t, tempDir, "history.json"), 1)
+	})
+
+	t.Run("rejects unknown channels", func(t *testing.T) {
+		tempDir, _ := setupChannelTestRepo(t)
+		createTestConsignmentForVersion(t, filepath.Join(tempDir, ".shipyard", "consignments"), "c1", []string{"test-package"}, "minor", "Add feature")
+		err := runVersionWithDir(tempDir, &VersionCommandOptions{Channel: "nightly"})
+		assert.ErrorContains(t, err, `unknown channel "nightly"`)
+		assert.ErrorContains(t, err, "stable, beta")
+	})
+
+	t.Run("rejects a different pre-release identifier", func(t *testing.T) {
+		tempDir, _ := setupChannelTestRepo(t)
+		createTestConsignmentForVersion(t, filepath.Join(tempDir, ".shipyard", "consignments"), "c1", []string{"test-package"}, "minor", "Add feature")
+		err := runVersionWithDir(tempDir, &VersionCommandOptions{Prerelease: "rc"})
+		assert.ErrorContains(t, err, "--prerelease rc cannot be combined with channel beta")
+		assert.Empty(t, readChannelHistory(t, tempDir, "history.json"))
+	})
+}
+
+func TestVersionCommand_ChannelWithoutChannelsConfigured(t *testing.T) {
+	tempDir, _, _ := setupResumeTestRepo(t)
+	captureOutput(func() {
+		require.NoError(t, runVersionWithDir(tempDir, &VersionCommandOptions{NoTag: true}))
+	})
+
+	entries := readChannelHistory(t, tempDir, "history.json")
+	require.Len(t, entries, 1)
+	assert.Empty(t, entries[0].Channel, "history is unchanged for projects without channels")
+}
+
+func TestVersionCommand_ChannelTemplateData(t *testing.T) {
+	tempDir, repo := setupChannelTestRepo(t)
+	configPath := filepath.Join(tempDir, ".shipyard", "shipyard.yaml")
+	content, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	config := strings.Replace(string(content), "templates:\n", "templates:\n  tagName:\n    inline: \"{{ .Channel }}/v{{ .Version }}\"\n", 1)
+	require.NoError(t, os.WriteFile(configPath, []byte(config), 0644))
+
+	releaseOnChannel(t, tempDir, repo, "c1", "minor", "Add feature", &VersionCommandOptions{})
+
+	assert.Equal(t, []string{"beta/v1.1.0-beta.1"}, tagNames(t, repo))
+	entries := readChannelHistory(t, tempDir, "history.beta.json")
+	require.Len(t, entries, 1)
+	assert.Equal(t, "beta/v1.1.0-beta.1", entries[0].Tag)
+}
+
+func TestVersionCommand_ResumeKeepsChannel(t *testing.T) {
+	tempDir, repo := setupChannelTestRepo(t)
+	createTestConsignmentForVersion(t, filepath.Join(tempDir, ".shipyard", "consignments"), "c1", []string{"test-package"}, "minor", "Add feature")
+	commitChannelTestRepo(t, repo, "Add c1")
+
+	interruptAfter(t, runstate.PhaseVersions)
+	captureOutput(func() {
+		assert.PanicsWithValue(t, errSimulatedCrash, func() {
+			_ = runVersionWithDir(tempDir, &VersionCommandOptions{})
+		})
+	})
+	resumeAll()
+
+	// Resumed from another branch, the run still writes the beta history
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+	require.NoError(t, wt.Checkout(&gogit.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("release/1.x"), Create: true, Keep: true}))
+	captureOutput(func() {
+		require.NoError(t, runVersionWithDir(tempDir, &VersionCommandOptions{Resume: true}))
+	})
+
+	assert.Len(t, readChannelHistory(t, tempDir, "history.beta.json"), 1)
+	assert.Empty(t, readChannelHistory(t, tempDir, "history.json"))
+}
+
+func TestVersionCommand_RegenerateChannelChangelog(t *testing.T) {
+	tempDir, repo := setupChannelTestRepo(t)
+	releaseOnChannel(t, tempDir, repo, "c1", "minor", "Add beta feature", &VersionCommandOptions{NoTag: true})
+	changelogPath := filepath.Join(tempDir, "test-package", "CHANGELOG.md")
+	require.NoError(t, os.Remove(changelogPath))
+
+	captureOutput(func() {
+		require.NoError(t, runVersionWithDir(tempDir, &VersionCommandOptions{Regenerate: true}))
+	})
+	assert.Contains(t, readFile(t, changelogPath), "Add beta feature")
+
+	// The stable history has no releases, so there is nothing to regenerate
+	require.NoError(t, os.Remove(changelogPath))
+	captureOutput(func() {
+		require.NoError(t, runVersionWithDir(tempDir, &VersionCommandOptions{Regenerate: true, Channel: "stable"}))
+	})
+	assert.NoFileExists(t, changelogPath)
+}
+
+// readFile reads a file as a string
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(content)
+}
@@ -0,0 +1,217 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/NatoNathan/shipyard/internal/config"
+	shipyarderrors "github.com/NatoNathan/shipyard/internal/errors"
+	"github.com/NatoNathan/shipyard/internal/git"
+	"github.com/NatoNathan/shipyard/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// CheckOptions holds options for the check command
+type CheckOptions struct {
+	Since string
+	JSON  bool
+	Quiet bool
+}
+
+// CheckOutput is the JSON output of the check command
+type CheckOutput struct {
+	Since    string           `json:"since"`
+	Passed   bool             `json:"passed"`
+	Packages []CheckedPackage `json:"packages"`
+	Ignored  []string         `json:"ignored"` // Changed files matching check.ignore or Shipyard's own files
+	Unowned  []string         `json:"unowned"` // Changed files outside every package
+	Missing  []string         `json:"missing"` // Packages changed without a pending consignment
+}
+
+// CheckedPackage is a package the change touches and the pending
+// consignments that cover it
+type CheckedPackage struct {
+	Name         string   `json:"name"`
+	Files        []string `json:"files"`
+	Consignments []string `json:"consignments"`
+	Covered      bool     `json:"covered"`
+}
+
+// NewCheckCommand creates the check command
+func NewCheckCommand() *cobra.Command {
+	opts := &CheckOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "check --since REF",
+		DisableFlagsInUseLine: true,
+		Short:                 "Make sure every changed package has cargo logged",
+		Long: `Fail when a change touches a package without a pending consignment for it.
+
+Compares HEAD with the merge base of --since and HEAD, as a pull request diff
+does, maps each changed file to the package with the most specific path, and
+checks that at least one pending consignment lists every changed package.
+Files matching check.ignore in the config, files outside every package and
+Shipyard's own files never require a consignment.
+
+Exits 0 when every changed package is covered and 1 otherwise. Nothing is
+written, so it is safe to run on every pull request.`,
+		Example: `  # Gate a pull request against main
+  shipyard check --since origin/main
+
+  # Machine-readable detail for CI
+  shipyard check --since origin/main --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			globalFlags := GetGlobalFlags(cmd)
+			opts.JSON = globalFlags.JSON
+			opts.Quiet = globalFlags.Quiet
+			return runCheck(opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Since, "since", "", "Base ref to compare HEAD with, e.g. origin/main (required)")
+	_ = cmd.MarkFlagRequired("since")
+
+	return cmd
+}
+
+func runCheck(opts *CheckOptions) error {
+	cwd, err := projectRoot()
+	if err != nil {
+		return err
+	}
+	return runCheckWithDir(cwd, opts)
+}
+
+func runCheckWithDir(projectPath string, opts *CheckOptions) error {
+	if opts.Since == "" {
+		return fmt.Errorf("--since is required")
+	}
+	isGitRepo, err := git.IsRepository(projectPath)
+	if err != nil {
+		return fmt.Errorf("failed to check git repository: %w", err)
+	}
+	if !isGitRepo {
+		return fmt.Errorf("shipyard check needs a git repository")
+	}
+
+	cfg, err := config.LoadFromDir(projectPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	files, err := git.ChangedSince(projectPath, opts.Since)
+	if err != nil {
+		return err
+	}
+	consignments, err := readAllConsignments(filepath.Join(projectPath, cfg.Consignments.Path))
+	if err != nil {
+		return fmt.Errorf("failed to read consignments: %w", err)
+	}
+
+	output := CheckOutput{
+		Since:    opts.Since,
+		Packages: []CheckedPackage{},
+		Ignored:  []string{},
+		Unowned:  []string{},
+		Missing:  []string{},
+	}
+	changed := make(map[string][]string)
+	for _, file := range files {
+		if isShipyardFile(cfg, file) || cfg.Check.Ignores(file) {
+			output.Ignored = append(output.Ignored, file)
+			continue
+		}
+		owner, ok := cfg.PackageForPath(file)
+		if !ok {
+			output.Unowned = append(output.Unowned, file)
+			continue
+		}
+		changed[owner] = append(changed[owner], file)
+	}
+
+	for name, pkgFiles := range changed {
+		checked := CheckedPackage{Name: name, Files: pkgFiles, Consignments: []string{}}
+		for _, c := range consignments {
+			if slices.Contains(c.Packages, name) {
+				checked.Consignments = append(checked.Consignments, c.ID)
+			}
+		}
+		checked.Covered = len(checked.Consignments) > 0
+		if !checked.Covered {
+			output.Missing = append(output.Missing, name)
+		}
+		output.Packages = append(output.Packages, checked)
+	}
+	sort.Slice(output.Packages, func(i, j int) bool { return output.Packages[i].Name < output.Packages[j].Name })
+	sort.Strings(output.Missing)
+	output.Passed = len(output.Missing) == 0
+
+	var result error
+	if !output.Passed {
+		result = shipyarderrors.NewExitCodeError(1, fmt.Sprintf("%d changed package(s) have no pending consignment: %s",
+			len(output.Missing), strings.Join(output.Missing, ", ")))
+	}
+
+	if opts.JSON {
+		if err := PrintJSON(os.Stdout, output); err != nil {
+			return err
+		}
+		return result
+	}
+	if opts.Quiet {
+		return result
+	}
+
+	printCheck(output)
+	return result
+}
+
+// isShipyardFile reports whether file is one of Shipyard's own files, such
+// as a consignment or the history, which never need a consignment themselves
+func isShipyardFile(cfg *config.Config, file string) bool {
+	if strings.HasPrefix(file, ".shipyard/") {
+		return true
+	}
+	consignmentsDir := strings.TrimPrefix(path.Clean(filepath.ToSlash(cfg.Consignments.Path)), "./")
+	if consignmentsDir != "." && strings.HasPrefix(file, consignmentsDir+"/") {
+		return true
+	}
+	historyPath := path.Clean(filepath.ToSlash(cfg.History.Path))
+	historyBase := strings.TrimSuffix(historyPath, path.Ext(historyPath))
+	return file == historyPath || (strings.HasPrefix(file, historyBase+".") && path.Ext(file) == path.Ext(historyPath))
+}
+
+// printCheck prints the human summary of a check
+func printCheck(output CheckOutput) {
+	fmt.Println(ui.Header("\U0001F50D", "Consignment check against "+output.Since))
+	fmt.Println()
+
+	if len(output.Packages) == 0 {
+		fmt.Println(ui.SuccessMessage("No package changes; no consignment needed"))
+	}
+	for _, pkg := range output.Packages {
+		if pkg.Covered {
+			fmt.Println(ui.SuccessMessage(fmt.Sprintf("%s: %d file(s) changed, covered by %s", pkg.Name, len(pkg.Files), strings.Join(pkg.Consignments, ", "))))
+		} else {
+			fmt.Println(ui.ErrorMessage(fmt.Sprintf("%s: %d file(s) changed, no pending consignment", pkg.Name, len(pkg.Files))))
+		}
+	}
+	if len(output.Ignored)+len(output.Unowned) > 0 {
+		fmt.Println(ui.Dimmed(fmt.Sprintf("Skipped %d ignored file(s) and %d file(s) outside every package", len(output.Ignored), len(output.Unowned))))
+	}
+
+	if !output.Passed {
+		fmt.Println()
+		args := make([]string, len(output.Missing))
+		for i, name := range output.Missing {
+			args[i] = "--package " + name
+		}
+		fmt.Println(ui.InfoMessage("Record the change with: shipyard add " + strings.Join(args, " ")))
+	}
+}
@@ -0,0 +1,160 @@
+package commands
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NatoNathan/shipyard/internal/config"
+	shipyarderrors "github.com/NatoNathan/shipyard/internal/errors"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupCheckTestRepo creates a repository with core and api packages on
+// main, then checks out a feature branch whose commit touches both packages,
+// a test file and the docs
+func setupCheckTestRepo(t *testing.T) (string, *gogit.Repository) {
+	t.Helper()
+	tempDir := t.TempDir()
+	writeCheckFile(t, tempDir, ".shipyard/shipyard.yaml", `packages:
+  - name: core
+    path: ./core
+    ecosystem: go
+  - name: api
+    path: ./api
+    ecosystem: go
+consignments:
+  path: ".shipyard/consignments"
+history:
+  path: ".shipyard/history.json"
+check:
+  ignore:
+    - "docs/**"
+    - "*_test.go"
+`)
+	writeCheckFile(t, tempDir, ".shipyard/history.json", "[]")
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDir, ".shipyard", "consignments"), 0755))
+	writeCheckFile(t, tempDir, "core/version.go", "package core\n\nconst Version = \"1.0.0\"\n")
+	writeCheckFile(t, tempDir, "api/version.go", "package api\n\nconst Version = \"1.0.0\"\n")
+
+	repo, err := gogit.PlainInit(tempDir, false)
+	require.NoError(t, err)
+	require.NoError(t, repo.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.NewBranchReferenceName("main"))))
+	commitChannelTestRepo(t, repo, "initial commit")
+
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+	require.NoError(t, wt.Checkout(&gogit.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("feature"), Create: true}))
+	writeCheckFile(t, tempDir, "core/parse.go", "package core\n")
+	writeCheckFile(t, tempDir, "core/parse_test.go", "package core\n")
+	writeCheckFile(t, tempDir, "api/handler.go", "package api\n")
+	writeCheckFile(t, tempDir, "docs/guide.md", "# Guide\n")
+	writeCheckFile(t, tempDir, "README.md", "# Project\n")
+	commitChannelTestRepo(t, repo, "Add parser and handler")
+	return tempDir, repo
+}
+
+// writeCheckFile writes a file relative to dir, creating its directory
+func writeCheckFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestCheck(t *testing.T) {
+	tempDir, repo := setupCheckTestRepo(t)
+	consignmentsDir := filepath.Join(tempDir, ".shipyard", "consignments")
+
+	var err error
+	output := captureOutput(func() {
+		err = runCheckWithDir(tempDir, &CheckOptions{Since: "main"})
+	})
+	var exitErr *shipyarderrors.ExitCodeError
+	require.True(t, stderrors.As(err, &exitErr), "got %v", err)
+	assert.Equal(t, 1, exitErr.Code)
+	assert.ErrorContains(t, err, "2 changed package(s) have no pending consignment: api, core")
+	assert.Contains(t, output, "shipyard add --package api --package core")
+
+	// A consignment for core leaves api uncovered
+	createTestConsignmentForVersion(t, consignmentsDir, "c1", []string{"core"}, "minor", "Add parser")
+	commitChannelTestRepo(t, repo, "Add consignment")
+	captureOutput(func() {
+		err = runCheckWithDir(tempDir, &CheckOptions{Since: "main"})
+	})
+	assert.ErrorContains(t, err, "1 changed package(s) have no pending consignment: api")
+
+	createTestConsignmentForVersion(t, consignmentsDir, "c2", []string{"api"}, "patch", "Add handler")
+	commitChannelTestRepo(t, repo, "Add consignment")
+	output = captureOutput(func() {
+		err = runCheckWithDir(tempDir, &CheckOptions{Since: "main"})
+	})
+	require.NoError(t, err)
+	assert.Contains(t, output, "core: 1 file(s) changed, covered by c1")
+	assert.Contains(t, output, "api: 1 file(s) changed, covered by c2")
+}
+
+func TestCheck_JSON(t *testing.T) {
+	tempDir, _ := setupCheckTestRepo(t)
+	createTestConsignmentForVersion(t, filepath.Join(tempDir, ".shipyard", "consignments"), "c1", []string{"core"}, "minor", "Add parser")
+
+	var err error
+	output := captureOutput(func() {
+		err = runCheckWithDir(tempDir, &CheckOptions{Since: "main", JSON: true})
+	})
+	assert.Error(t, err)
+
+	var result CheckOutput
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.False(t, result.Passed)
+	assert.Equal(t, "main", result.Since)
+	assert.Equal(t, []string{"api"}, result.Missing)
+	assert.Equal(t, []string{"core/parse_test.go", "docs/guide.md"}, result.Ignored)
+	assert.Equal(t, []string{"README.md"}, result.Unowned)
+	require.Len(t, result.Packages, 2)
+	assert.Equal(t, CheckedPackage{Name: "api", Files: []string{"api/handler.go"}, Consignments: []string{}}, result.Packages[0])
+	assert.Equal(t, CheckedPackage{Name: "core", Files: []string{"core/parse.go"}, Consignments: []string{"c1"}, Covered: true}, result.Packages[1])
+}
+
+func TestCheck_OnlyIgnoredChanges(t *testing.T) {
+	tempDir, repo := setupCheckTestRepo(t)
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+	require.NoError(t, wt.Checkout(&gogit.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("docs"), Create: true}))
+	writeCheckFile(t, tempDir, "docs/guide.md", "# Guide\n\nMore\n")
+	writeCheckFile(t, tempDir, "api/handler_test.go", "package api\n")
+	commitChannelTestRepo(t, repo, "Document the handler")
+
+	output := captureOutput(func() {
+		require.NoError(t, runCheckWithDir(tempDir, &CheckOptions{Since: "feature"}))
+	})
+	assert.Contains(t, output, "No package changes")
+}
+
+func TestCheck_Errors(t *testing.T) {
+	tempDir, _ := setupCheckTestRepo(t)
+
+	assert.ErrorContains(t, runCheckWithDir(tempDir, &CheckOptions{}), "--since is required")
+	assert.ErrorContains(t, runCheckWithDir(tempDir, &CheckOptions{Since: "origin/main"}), "failed to resolve origin/main")
+	assert.ErrorContains(t, runCheckWithDir(t.TempDir(), &CheckOptions{Since: "main"}), "needs a git repository")
+}
+
+func TestIsShipyardFile(t *testing.T) {
+	tempDir, _ := setupCheckTestRepo(t)
+	cfg, err := config.LoadFromDir(tempDir)
+	require.NoError(t, err)
+	cfg.Consignments.Path = "changes"
+	cfg.History.Path = "releases/history.json"
+
+	assert.True(t, isShipyardFile(cfg, ".shipyard/shipyard.yaml"))
+	assert.True(t, isShipyardFile(cfg, "changes/c1.md"))
+	assert.True(t, isShipyardFile(cfg, "releases/history.json"))
+	assert.True(t, isShipyardFile(cfg, "releases/history.beta.json"))
+	assert.False(t, isShipyardFile(cfg, "releases/notes.md"))
+	assert.False(t, isShipyardFile(cfg, "changes.md"))
+}
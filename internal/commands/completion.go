@@ -2,8 +2,14 @@ package commands
 
 import (
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/consignment"
+	"github.com/NatoNathan/shipyard/pkg/types"
 	"github.com/spf13/cobra"
 )
 
@@ -77,28 +83,119 @@ func runCompletion(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// completionTimeout bounds how long a completion may spend loading the
+// project; completions are skipped rather than stalling the shell
+var completionTimeout = 500 * time.Millisecond
+
+// loadCompletionConfig loads the configuration for completions; tests replace it
+var loadCompletionConfig = config.LoadFromDir
+
+// completionConfig loads the configuration of the project the working
+// directory is in for a completion. ok is false when it is missing, invalid or
+// takes longer than completionTimeout, such as when a remote config cannot
+// be reached.
+func completionConfig() (cwd string, cfg *config.Config, ok bool) {
+	root, err := projectRoot()
+	if err != nil {
+		return "", nil, false
+	}
+	// The load may outlive this call, so it only reads its own copies and
+	// reports back over the channel
+	load := loadCompletionConfig
+	loaded := make(chan *config.Config, 1)
+	go func() {
+		cfg, err := load(root)
+		if err != nil {
+			cfg = nil
+		}
+		loaded <- cfg
+	}()
+	select {
+	case cfg = <-loaded:
+		return root, cfg, cfg != nil
+	case <-time.After(completionTimeout):
+		return "", nil, false
+	}
+}
+
 // RegisterPackageCompletions registers package name completions for a command flag.
 // This enables tab-completion of package names from the Shipyard configuration.
 func RegisterPackageCompletions(cmd *cobra.Command, flagName string) {
-	_ = cmd.RegisterFlagCompletionFunc(flagName, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		// Try to load configuration
-		cwd, err := os.Getwd()
-		if err != nil {
-			return nil, cobra.ShellCompDirectiveNoFileComp
+	_ = cmd.RegisterFlagCompletionFunc(flagName, completePackages)
+}
+
+// RegisterChangeTypeCompletions registers change type completions for a
+// command flag, described by their changelog sections when configured
+func RegisterChangeTypeCompletions(cmd *cobra.Command, flagName string) {
+	_ = cmd.RegisterFlagCompletionFunc(flagName, completeChangeTypes)
+}
+
+// RegisterConsignmentCompletions registers pending consignment ID
+// completions, described by their summaries, for a command flag
+func RegisterConsignmentCompletions(cmd *cobra.Command, flagName string) {
+	_ = cmd.RegisterFlagCompletionFunc(flagName, completeConsignments)
+}
+
+// completePackages completes the names of the configured packages
+func completePackages(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	_, cfg, ok := completionConfig()
+	if !ok {
+		// If config not found, don't show error, just don't provide completions
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var names []string
+	for _, pkg := range cfg.Packages {
+		if strings.HasPrefix(pkg.Name, toComplete) {
+			names = append(names, pkg.Name)
 		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
 
-		cfg, err := config.LoadFromDir(cwd)
-		if err != nil {
-			// If config not found, don't show error, just don't provide completions
-			return nil, cobra.ShellCompDirectiveNoFileComp
+// completeChangeTypes completes patch, minor and major. Outside a project
+// they are still offered, without the configured descriptions.
+func completeChangeTypes(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	sections := map[string]string{}
+	if _, cfg, ok := completionConfig(); ok {
+		for _, ct := range cfg.ChangeTypes {
+			if ct.Section != "" {
+				sections[ct.Name] = ct.Section
+			}
 		}
+	}
 
-		// Extract package names
-		var names []string
-		for _, pkg := range cfg.Packages {
-			names = append(names, pkg.Name)
+	var completions []string
+	for _, ct := range []types.ChangeType{types.ChangeTypePatch, types.ChangeTypeMinor, types.ChangeTypeMajor} {
+		name := string(ct)
+		if !strings.HasPrefix(name, toComplete) {
+			continue
+		}
+		if section := sections[name]; section != "" {
+			name += "\t" + section
 		}
+		completions = append(completions, name)
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
 
-		return names, cobra.ShellCompDirectiveNoFileComp
-	})
+// completeConsignments completes the IDs of the pending consignments, oldest
+// first, each described by its summary
+func completeConsignments(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cwd, cfg, ok := completionConfig()
+	if !ok {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	pending, err := consignment.ReadAllConsignments(filepath.Join(cwd, cfg.Consignments.Path))
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var ids []string
+	for _, c := range consignment.SortConsignmentsByTimestamp(pending) {
+		if strings.HasPrefix(c.ID, toComplete) && !slices.Contains(args, c.ID) {
+			ids = append(ids, c.ID+"\t"+summaryFirstLine(c.Summary))
+		}
+	}
+	return ids, cobra.ShellCompDirectiveNoFileComp
 }
@@ -1,12 +1,18 @@
 package commands
 
 import (
+	"bytes"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/consignment"
+	"github.com/NatoNathan/shipyard/pkg/types"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -161,3 +167,94 @@ func TestPackageCompletions_NoConfig(t *testing.T) {
 	flag := testCmd.Flags().Lookup("package")
 	require.NotNil(t, flag, "package flag should exist")
 }
+
+// completeIn runs cobra's hidden __complete command in dir and returns the
+// completions it prints, without the trailing directive line
+func completeIn(t *testing.T, dir string, args ...string) []string {
+	t.Helper()
+	cleanup := changeToDir(t, dir)
+	defer cleanup()
+
+	rootCmd := &cobra.Command{Use: "shipyard"}
+	rootCmd.AddCommand(NewVersionCommand(), NewAddCommand(), NewStatusCommand(), NewEditCommand(), NewRemoveCommand())
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetErr(io.Discard)
+	rootCmd.SetArgs(append([]string{cobra.ShellCompRequestCmd}, args...))
+	require.NoError(t, rootCmd.Execute())
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.NotEmpty(t, lines)
+	assert.Equal(t, fmt.Sprintf(":%d", cobra.ShellCompDirectiveNoFileComp), lines[len(lines)-1])
+	return lines[:len(lines)-1]
+}
+
+func TestDynamicCompletions(t *testing.T) {
+	tempDir := t.TempDir()
+	consignmentsDir := filepath.Join(tempDir, ".shipyard", "consignments")
+	require.NoError(t, os.MkdirAll(consignmentsDir, 0755))
+	configContent := `packages:
+  - name: core
+    path: ./core
+    ecosystem: go
+  - name: api
+    path: ./api
+    ecosystem: go
+changeTypes:
+  - name: minor
+    section: Features
+consignments:
+  path: .shipyard/consignments
+history:
+  path: .shipyard/history.json
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, ".shipyard", "shipyard.yaml"), []byte(configContent), 0644))
+	for i, c := range []*consignment.Consignment{
+		{ID: "20240101-120000-aaa111", Packages: []string{"core"}, ChangeType: types.ChangeTypePatch, Summary: "Fix crash\n\nDetails"},
+		{ID: "20240102-120000-bbb222", Packages: []string{"api"}, ChangeType: types.ChangeTypeMinor, Summary: "Add endpoint"},
+	} {
+		c.Timestamp = time.Date(2024, 1, i+1, 12, 0, 0, 0, time.UTC)
+		require.NoError(t, consignment.WriteConsignment(c, consignmentsDir))
+	}
+
+	t.Run("package names", func(t *testing.T) {
+		for _, command := range []string{"version", "add", "status"} {
+			assert.Equal(t, []string{"core", "api"}, completeIn(t, tempDir, command, "--package", ""), command)
+		}
+		assert.Equal(t, []string{"api"}, completeIn(t, tempDir, "version", "--package", "a"))
+	})
+
+	t.Run("change types", func(t *testing.T) {
+		assert.Equal(t, []string{"patch", "minor\tFeatures", "major"}, completeIn(t, tempDir, "add", "--type", ""))
+		assert.Equal(t, []string{"minor\tFeatures", "major"}, completeIn(t, tempDir, "edit", "x", "--type", "m"))
+	})
+
+	t.Run("consignment IDs", func(t *testing.T) {
+		want := []string{"20240101-120000-aaa111\tFix crash", "20240102-120000-bbb222\tAdd endpoint"}
+		assert.Equal(t, want, completeIn(t, tempDir, "version", "--consignment", ""))
+		assert.Equal(t, want, completeIn(t, tempDir, "edit", ""))
+		assert.Empty(t, completeIn(t, tempDir, "edit", "20240101-120000-aaa111", ""), "edit takes one ID")
+		assert.Equal(t, want[1:], completeIn(t, tempDir, "remove", "20240101-120000-aaa111", ""), "IDs already given are not offered")
+		assert.Equal(t, want[1:], completeIn(t, tempDir, "remove", "--id", "20240102"))
+	})
+
+	t.Run("outside a project", func(t *testing.T) {
+		emptyDir := t.TempDir()
+		assert.Empty(t, completeIn(t, emptyDir, "version", "--package", ""))
+		assert.Empty(t, completeIn(t, emptyDir, "version", "--consignment", ""))
+		assert.Equal(t, []string{"patch", "minor", "major"}, completeIn(t, emptyDir, "add", "--type", ""))
+	})
+
+	t.Run("slow config", func(t *testing.T) {
+		unblock := make(chan struct{})
+		defer close(unblock)
+		oldTimeout, oldLoad := completionTimeout, loadCompletionConfig
+		completionTimeout = 10 * time.Millisecond
+		loadCompletionConfig = func(dir string) (*config.Config, error) {
+			<-unblock
+			return oldLoad(dir)
+		}
+		defer func() { completionTimeout, loadCompletionConfig = oldTimeout, oldLoad }()
+		assert.Empty(t, completeIn(t, tempDir, "version", "--package", ""), "completions are skipped when loading takes too long")
+	})
+}
@@ -0,0 +1,111 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/fileutil"
+	"github.com/NatoNathan/shipyard/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// ConfigMigrateOptions holds the options for the config migrate command
+type ConfigMigrateOptions struct {
+	Write bool
+}
+
+// ConfigMigrateResult is the JSON output of the config migrate command
+type ConfigMigrateResult struct {
+	File    string   `json:"file"`
+	From    int      `json:"from"`
+	To      int      `json:"to"`
+	Changes []string `json:"changes"`
+	Written bool     `json:"written"`
+}
+
+// NewConfigMigrateCommand creates the config migrate command
+func NewConfigMigrateCommand() *cobra.Command {
+	opts := &ConfigMigrateOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "migrate [--write]",
+		Short: "Bring old standing orders up to the current charter",
+		Long: `Upgrade the configuration file to the current config schema.
+
+Older config shapes are migrated in memory whenever the config is loaded.
+This command lists the changes the migration makes, and with --write saves
+the upgraded file, recording schemaVersion. Comments in YAML files are kept.`,
+		Example: `  # List the changes a migration would make
+  shipyard config migrate
+
+  # Rewrite the config file in the current schema
+  shipyard config migrate --write`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cwd, err := projectRoot()
+			if err != nil {
+				return err
+			}
+			return runConfigMigrate(cwd, opts, GetGlobalFlags(cmd))
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.Write, "write", false, "Save the migrated config file")
+
+	return cmd
+}
+
+func runConfigMigrate(projectPath string, opts *ConfigMigrateOptions, flags GlobalFlags) error {
+	path, err := config.FindConfigFile(projectPath)
+	if err != nil {
+		return err
+	}
+
+	data, migration, err := config.MigrateFile(path)
+	if err != nil {
+		return err
+	}
+
+	current := migration.From == config.CurrentSchemaVersion
+	result := ConfigMigrateResult{
+		File:    path,
+		From:    max(migration.From, 1),
+		To:      config.CurrentSchemaVersion,
+		Changes: append([]string{}, migration.Changes...),
+	}
+	if opts.Write && !current {
+		if err := fileutil.AtomicWrite(path, data, 0644); err != nil {
+			return fmt.Errorf("failed to write config file: %w", err)
+		}
+		result.Written = true
+	}
+
+	if flags.JSON {
+		return PrintJSON(os.Stdout, result)
+	}
+	if flags.Quiet {
+		return nil
+	}
+
+	if current {
+		fmt.Println(ui.SuccessMessage(fmt.Sprintf("%s is already at config schema %d", path, config.CurrentSchemaVersion)))
+		return nil
+	}
+
+	if result.Written {
+		fmt.Println(ui.SuccessMessage(fmt.Sprintf("Migrated %s from config schema %d to %d", path, result.From, result.To)))
+	} else {
+		fmt.Printf("%s uses config schema %d; migrating to schema %d:\n", path, result.From, result.To)
+	}
+	for _, change := range result.Changes {
+		fmt.Printf("  %s\n", change)
+	}
+	if migration.From == 0 {
+		fmt.Printf("  schemaVersion: %d\n", config.CurrentSchemaVersion)
+	}
+	if !result.Written {
+		fmt.Println(ui.Dimmed("Run with --write to save the migrated file."))
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package commands
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeLegacyConfig writes a schema 2 config, with a comment, to .shipyard/shipyard.yaml
+func writeLegacyConfig(t *testing.T) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".shipyard"), 0755))
+	path := filepath.Join(dir, ".shipyard", "shipyard.yaml")
+	content := `schemaVersion: 2
+packages:
+  - name: core
+    path: ./
+    ecosystem: go
+changelog:
+  template: builtin:keepachangelog # our house style
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return dir, path
+}
+
+func TestConfigMigrate_DryRun(t *testing.T) {
+	dir, path := writeLegacyConfig(t)
+	before, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	output := captureOutput(func() {
+		require.NoError(t, runConfigMigrate(dir, &ConfigMigrateOptions{}, GlobalFlags{}))
+	})
+
+	assert.Contains(t, output, "uses config schema 2; migrating to schema 3")
+	assert.Contains(t, output, "changelog.template -> templates.changelog")
+	assert.Contains(t, output, "--write")
+
+	after, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(before), string(after))
+}
+
+func TestConfigMigrate_Write(t *testing.T) {
+	dir, path := writeLegacyConfig(t)
+
+	output := captureOutput(func() {
+		require.NoError(t, runConfigMigrate(dir, &ConfigMigrateOptions{Write: true}, GlobalFlags{}))
+	})
+	assert.Contains(t, output, "from config schema 2 to 3")
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "schemaVersion: 3\n")
+	assert.Contains(t, string(content), "templates:\n  changelog:\n    source: builtin:keepachangelog # our house style\n")
+	assert.NotContains(t, string(content), "template: builtin")
+
+	cfg, err := config.LoadFromDir(dir)
+	require.NoError(t, err)
+	assert.Equal(t, config.CurrentSchemaVersion, cfg.SchemaVersion)
+	assert.Equal(t, "builtin:keepachangelog", cfg.Templates.Changelog.Source)
+
+	// A second run has nothing to do
+	output = captureOutput(func() {
+		require.NoError(t, runConfigMigrate(dir, &ConfigMigrateOptions{Write: true}, GlobalFlags{}))
+	})
+	assert.Contains(t, output, "already at config schema 3")
+}
+
+func TestConfigMigrate_RecordsSchemaVersion(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".shipyard"), 0755))
+	path := filepath.Join(dir, ".shipyard", "shipyard.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("packages:\n  - name: core\n    path: ./\n"), 0644))
+
+	output := captureOutput(func() {
+		require.NoError(t, runConfigMigrate(dir, &ConfigMigrateOptions{Write: true}, GlobalFlags{JSON: true}))
+	})
+
+	var result ConfigMigrateResult
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.Equal(t, 1, result.From)
+	assert.Equal(t, config.CurrentSchemaVersion, result.To)
+	assert.Empty(t, result.Changes)
+	assert.True(t, result.Written)
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "schemaVersion: 3\npackages:\n  - name: core\n    path: ./\n", string(content))
+}
@@ -3,51 +3,95 @@ package commands
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/NatoNathan/shipyard/internal/config"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
 
+// ConfigShowOptions holds the options for the config show command
+type ConfigShowOptions struct {
+	Origin bool
+	Path   string
+}
+
+// ConfigShowOriginResult is the JSON output of config show --origin
+type ConfigShowOriginResult struct {
+	Config  *config.Config      `json:"config"`
+	Origins map[string][]string `json:"origins"`
+}
+
 // NewConfigShowCommand creates the config show command
 func NewConfigShowCommand() *cobra.Command {
+	opts := &ConfigShowOptions{}
+
 	cmd := &cobra.Command{
 		Use:     "show",
 		Aliases: []string{"view"},
 		Short:   "Read the ship's charter",
-		Long: `Display the current shipyard configuration with all defaults applied.
+		Long: `Display the effective shipyard configuration: the config file merged
+over the configs it extends, with all defaults applied.
 
-Outputs as YAML by default, or JSON with the --json flag.`,
+Outputs as YAML by default, or JSON with the --json flag. --origin notes
+where each top-level key came from: the config file, an extends source, or
+the defaults. --path prints a single value, for scripts.`,
 		Example: `  # Show config as YAML
   shipyard config show
 
   # Show config as JSON
-  shipyard config show --json`,
+  shipyard config show --json
+
+  # Show where each setting came from
+  shipyard config show --origin
+
+  # Print one value
+  shipyard config show --path templates.changelog.source`,
+		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			globalFlags := GetGlobalFlags(cmd)
-			return runConfigShow(globalFlags)
+			return runConfigShow(opts, globalFlags)
 		},
 	}
 
+	cmd.Flags().BoolVar(&opts.Origin, "origin", false, "Note where each top-level key came from")
+	cmd.Flags().StringVar(&opts.Path, "path", "", "Print the value at a dotted path, e.g. templates.changelog.source")
+	cmd.MarkFlagsMutuallyExclusive("origin", "path")
+
 	return cmd
 }
 
-func runConfigShow(flags GlobalFlags) error {
-	cwd, err := os.Getwd()
+func runConfigShow(opts *ConfigShowOptions, flags GlobalFlags) error {
+	cwd, err := projectRoot()
 	if err != nil {
-		return fmt.Errorf("failed to get current directory: %w", err)
+		return err
 	}
-	return runConfigShowWithDir(cwd, flags)
+	return runConfigShowWithDir(cwd, opts, flags)
 }
 
-func runConfigShowWithDir(projectPath string, flags GlobalFlags) error {
-	cfg, err := config.LoadFromDir(projectPath)
+func runConfigShowWithDir(projectPath string, opts *ConfigShowOptions, flags GlobalFlags) error {
+	cfg, origins, err := config.LoadFromDirWithOrigins(projectPath)
 	if err != nil {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
 
 	resolved := cfg.WithDefaults()
 
+	if opts.Path != "" {
+		return printConfigValue(resolved, opts.Path, flags)
+	}
+
+	if opts.Origin {
+		origins = displayOrigins(projectPath, origins)
+		if flags.JSON {
+			return PrintJSON(os.Stdout, ConfigShowOriginResult{Config: resolved, Origins: origins})
+		}
+		return printAnnotatedConfig(resolved, origins)
+	}
+
 	if flags.JSON {
 		return PrintJSON(os.Stdout, resolved)
 	}
@@ -60,3 +104,104 @@ func runConfigShowWithDir(projectPath string, flags GlobalFlags) error {
 	fmt.Print(string(data))
 	return nil
 }
+
+// printAnnotatedConfig prints the config as YAML with each top-level key's
+// origin as a line comment
+func printAnnotatedConfig(cfg *config.Config, origins config.Origins) error {
+	var doc yaml.Node
+	if err := doc.Encode(cfg); err != nil {
+		return fmt.Errorf("failed to marshal configuration: %w", err)
+	}
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		key := doc.Content[i]
+		if from, ok := origins[key.Value]; ok {
+			key.LineComment = "from " + strings.Join(from, ", ")
+		}
+	}
+
+	data, err := yaml.Marshal(&doc)
+	if err != nil {
+		return fmt.Errorf("failed to marshal configuration: %w", err)
+	}
+	fmt.Print(string(data))
+	return nil
+}
+
+// displayOrigins shows config files inside the project relative to it
+func displayOrigins(projectPath string, origins config.Origins) config.Origins {
+	display := make(config.Origins, len(origins))
+	for key, from := range origins {
+		for _, origin := range from {
+			if filepath.IsAbs(origin) {
+				if rel, err := filepath.Rel(projectPath, origin); err == nil && !strings.HasPrefix(rel, "..") {
+					origin = rel
+				}
+			}
+			display[key] = append(display[key], origin)
+		}
+	}
+	return display
+}
+
+// printConfigValue prints the value at a dotted path: scalars bare, and
+// sections as YAML, or JSON with --json. Numeric segments index lists.
+func printConfigValue(cfg *config.Config, path string, flags GlobalFlags) error {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal configuration: %w", err)
+	}
+	var value interface{}
+	if err := yaml.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("failed to read configuration: %w", err)
+	}
+
+	for _, segment := range strings.Split(path, ".") {
+		var ok bool
+		value, ok = configChild(value, segment)
+		if !ok {
+			return fmt.Errorf("config has no value at %s", path)
+		}
+	}
+
+	if flags.JSON {
+		return PrintJSON(os.Stdout, value)
+	}
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		out, err := yaml.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("failed to marshal configuration: %w", err)
+		}
+		fmt.Print(string(out))
+	default:
+		fmt.Println(value)
+	}
+	return nil
+}
+
+// configChild returns the child of a config section at segment; mapping keys
+// are matched case-insensitively
+func configChild(value interface{}, segment string) (interface{}, bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if child, ok := v[segment]; ok {
+			return child, true
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if strings.EqualFold(key, segment) {
+				return v[key], true
+			}
+		}
+	case []interface{}:
+		index, err := strconv.Atoi(segment)
+		if err == nil && index >= 0 && index < len(v) {
+			return v[index], true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeExtendingConfig writes a project config that extends a base file and
+// overrides one of its sections
+func writeExtendingConfig(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".shipyard"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".shipyard", "base.yaml"), []byte(`templates:
+  changelog:
+    source: builtin:grouped
+changelog:
+  placeholder: From the base
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".shipyard", "shipyard.yaml"), []byte(`extends:
+  - url: base.yaml
+packages:
+  - name: core
+    path: ./
+    ecosystem: go
+changelog:
+  placeholder: From the project
+`), 0644))
+	return dir
+}
+
+func TestConfigShow_Origin(t *testing.T) {
+	dir := writeExtendingConfig(t)
+
+	output := captureOutput(func() {
+		require.NoError(t, runConfigShowWithDir(dir, &ConfigShowOptions{Origin: true}, GlobalFlags{}))
+	})
+	assert.Contains(t, output, "templates: # from .shipyard/base.yaml\n")
+	assert.Contains(t, output, "changelog: # from .shipyard/shipyard.yaml\n")
+	assert.Contains(t, output, "placeholder: From the project\n")
+	assert.Contains(t, output, "consignments: # from default\n")
+
+	output = captureOutput(func() {
+		require.NoError(t, runConfigShowWithDir(dir, &ConfigShowOptions{Origin: true}, GlobalFlags{JSON: true}))
+	})
+	var result struct {
+		Origins map[string][]string `json:"origins"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.Equal(t, []string{filepath.Join(".shipyard", "base.yaml")}, result.Origins["templates"])
+	assert.Equal(t, []string{filepath.Join(".shipyard", "shipyard.yaml")}, result.Origins["packages"])
+}
+
+func TestConfigShow_Path(t *testing.T) {
+	dir := writeExtendingConfig(t)
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"templates.changelog.source", "builtin:grouped\n"},
+		{"Changelog.Placeholder", "From the project\n"},
+		{"packages.0.name", "core\n"},
+		{"templates.changelog", "source: builtin:grouped\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			output := captureOutput(func() {
+				require.NoError(t, runConfigShowWithDir(dir, &ConfigShowOptions{Path: tt.path}, GlobalFlags{}))
+			})
+			assert.Equal(t, tt.want, output)
+		})
+	}
+
+	err := runConfigShowWithDir(dir, &ConfigShowOptions{Path: "packages.3.name"}, GlobalFlags{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "config has no value at packages.3.name")
+}
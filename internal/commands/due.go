@@ -0,0 +1,151 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/consignment"
+	"github.com/NatoNathan/shipyard/internal/release"
+	"github.com/NatoNathan/shipyard/internal/schedule"
+	"github.com/NatoNathan/shipyard/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// DueOptions holds options for the due command
+type DueOptions struct {
+	JSON  bool
+	Quiet bool
+}
+
+// DueResult is the JSON output of the due command
+type DueResult struct {
+	Cron     string           `json:"cron"`
+	Timezone string           `json:"timezone"`
+	Open     bool             `json:"open"`
+	Current  *schedule.Window `json:"current,omitempty"`
+	Next     *schedule.Window `json:"next,omitempty"`
+	Packages []DuePackage     `json:"packages"`
+}
+
+// DuePackage summarises the pending cargo for one package
+type DuePackage struct {
+	Name         string `json:"name"`
+	Consignments int    `json:"consignments"`
+	ChangeType   string `json:"changeType"`
+}
+
+// NewDueCommand creates the due command
+func NewDueCommand() *cobra.Command {
+	opts := &DueOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "due",
+		DisableFlagsInUseLine: true,
+		Short:                 "Check whether the tide is right for sailing",
+		Long: `Report whether a release window from releaseSchedule is open now, when the
+next window opens, and which packages have pending consignments for it.`,
+		Example: `  # Is a release window open?
+  shipyard due
+
+  # Machine-readable output for CI
+  shipyard due --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			globalFlags := GetGlobalFlags(cmd)
+			opts.JSON = globalFlags.JSON
+			opts.Quiet = globalFlags.Quiet
+			return runDue(opts)
+		},
+	}
+
+	return cmd
+}
+
+func runDue(opts *DueOptions) error {
+	cwd, err := projectRoot()
+	if err != nil {
+		return err
+	}
+	return runDueWithDir(cwd, opts)
+}
+
+func runDueWithDir(projectPath string, opts *DueOptions) error {
+	cfg, err := config.LoadFromDir(projectPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if cfg.ReleaseSchedule == nil {
+		return fmt.Errorf("no release schedule configured (set releaseSchedule in shipyard.yaml)")
+	}
+	sched, err := cfg.ReleaseSchedule.Build()
+	if err != nil {
+		return err
+	}
+
+	consignments, err := readAllConsignments(filepath.Join(projectPath, cfg.Consignments.Path))
+	if err != nil {
+		return fmt.Errorf("failed to read consignments: %w", err)
+	}
+
+	status := sched.Status(release.ScheduleNow())
+	result := &DueResult{
+		Cron:     cfg.ReleaseSchedule.Cron,
+		Timezone: sched.Location().String(),
+		Open:     status.Open,
+		Current:  status.Current,
+		Next:     status.Next,
+		Packages: duePackages(consignments),
+	}
+
+	if opts.JSON {
+		return PrintJSON(os.Stdout, result)
+	}
+	if opts.Quiet {
+		return nil
+	}
+
+	fmt.Println(ui.Header("⏱", "Release schedule"))
+	fmt.Println(ui.KeyValue("Schedule", fmt.Sprintf("%s (%s)", result.Cron, result.Timezone)))
+	if result.Open {
+		fmt.Println(ui.SuccessMessage("Release window is open until " + result.Current.Closes.Format(release.ScheduleTimeFormat)))
+	} else {
+		fmt.Println(ui.WarningMessage("Release window is closed"))
+	}
+	if result.Next != nil {
+		fmt.Println(ui.KeyValue("Next window", result.Next.Opens.Format(release.ScheduleTimeFormat)))
+	}
+	fmt.Println()
+
+	if len(result.Packages) == 0 {
+		fmt.Println(ui.Dimmed("No pending consignments"))
+		return nil
+	}
+	for _, pkg := range result.Packages {
+		fmt.Printf("  %s: %d consignment(s), %s\n", pkg.Name, pkg.Consignments, pkg.ChangeType)
+	}
+	return nil
+}
+
+// duePackages groups pending consignments by package, sorted by name
+func duePackages(consignments []*consignment.Consignment) []DuePackage {
+	byPackage := make(map[string][]*consignment.Consignment)
+	for _, c := range consignments {
+		for _, pkg := range c.Packages {
+			byPackage[pkg] = append(byPackage[pkg], c)
+		}
+	}
+
+	packages := make([]DuePackage, 0, len(byPackage))
+	for name, cs := range byPackage {
+		packages = append(packages, DuePackage{
+			Name:         name,
+			Consignments: len(cs),
+			ChangeType:   string(consignment.GetHighestChangeType(cs)),
+		})
+	}
+	sort.Slice(packages, func(i, j int) bool { return packages[i].Name < packages[j].Name })
+	return packages
+}
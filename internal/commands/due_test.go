@@ -0,0 +1,133 @@
+package commands
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/release"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setSchedule writes a Thursday 10:00 London release schedule into the project config
+func setSchedule(t *testing.T, dir string, enforce bool) {
+	t.Helper()
+	configPath := filepath.Join(dir, ".shipyard", "shipyard.yaml")
+	cfg, err := config.Load(configPath)
+	require.NoError(t, err)
+	cfg.ReleaseSchedule = &config.ScheduleConfig{Cron: "0 10 * * 4", Timezone: "Europe/London", GraceHours: 6, Enforce: enforce}
+	require.NoError(t, config.WriteConfig(cfg, configPath))
+}
+
+// setScheduleNow fixes the release window clock for the duration of the test
+func setScheduleNow(t *testing.T, now time.Time) {
+	t.Helper()
+	original := release.ScheduleNow
+	release.ScheduleNow = func() time.Time { return now }
+	t.Cleanup(func() { release.ScheduleNow = original })
+}
+
+var (
+	// Thursday 2026-10-15, inside the 10:00-16:00 BST window
+	inWindow = time.Date(2026, 10, 15, 11, 0, 0, 0, time.UTC)
+	// Wednesday 2026-10-14, the day before the window
+	outsideWindow = time.Date(2026, 10, 14, 11, 0, 0, 0, time.UTC)
+)
+
+func TestDueCommand(t *testing.T) {
+	t.Run("reports the open window and pending packages", func(t *testing.T) {
+		tempDir := setupVersionTestRepo(t)
+		setSchedule(t, tempDir, false)
+		setScheduleNow(t, inWindow)
+		consignmentsDir := filepath.Join(tempDir, ".shipyard", "consignments")
+		createTestConsignmentForVersion(t, consignmentsDir, "c1", []string{"test-package"}, "patch", "Fix bug")
+		createTestConsignmentForVersion(t, consignmentsDir, "c2", []string{"test-package"}, "minor", "Add feature")
+
+		var err error
+		output := captureOutput(func() {
+			err = runDueWithDir(tempDir, &DueOptions{JSON: true})
+		})
+		require.NoError(t, err)
+
+		var result DueResult
+		require.NoError(t, json.Unmarshal([]byte(output), &result))
+		assert.True(t, result.Open)
+		assert.Equal(t, "Europe/London", result.Timezone)
+		require.NotNil(t, result.Current)
+		assert.True(t, time.Date(2026, 10, 15, 15, 0, 0, 0, time.UTC).Equal(result.Current.Closes))
+		require.NotNil(t, result.Next)
+		assert.True(t, time.Date(2026, 10, 22, 9, 0, 0, 0, time.UTC).Equal(result.Next.Opens))
+		assert.Equal(t, []DuePackage{{Name: "test-package", Consignments: 2, ChangeType: "minor"}}, result.Packages)
+	})
+
+	t.Run("reports the next window when closed", func(t *testing.T) {
+		tempDir := setupVersionTestRepo(t)
+		setSchedule(t, tempDir, false)
+		setScheduleNow(t, outsideWindow)
+
+		var err error
+		output := captureOutput(func() {
+			err = runDueWithDir(tempDir, &DueOptions{})
+		})
+		require.NoError(t, err)
+		assert.Contains(t, output, "Release window is closed")
+		assert.Contains(t, output, "Thu 15 Oct 2026 10:00 BST")
+		assert.Contains(t, output, "No pending consignments")
+	})
+
+	t.Run("requires a schedule", func(t *testing.T) {
+		tempDir := setupVersionTestRepo(t)
+		err := runDueWithDir(tempDir, &DueOptions{})
+		assert.ErrorContains(t, err, "no release schedule configured")
+	})
+}
+
+func TestVersionCommand_RespectSchedule(t *testing.T) {
+	tests := []struct {
+		name    string
+		enforce bool
+		now     time.Time
+		opts    VersionCommandOptions
+		wantErr string
+	}{
+		{name: "refuses outside the window", now: outsideWindow, opts: VersionCommandOptions{RespectSchedule: true, NoCommit: true, NoTag: true},
+			wantErr: "outside the release window; the next window opens Thu 15 Oct 2026 10:00 BST"},
+		{name: "allows inside the window", now: inWindow, opts: VersionCommandOptions{RespectSchedule: true, NoCommit: true, NoTag: true}},
+		{name: "ignored without the flag", now: outsideWindow, opts: VersionCommandOptions{NoCommit: true, NoTag: true}},
+		{name: "enforced by config", enforce: true, now: outsideWindow, opts: VersionCommandOptions{NoCommit: true, NoTag: true},
+			wantErr: "outside the release window"},
+		{name: "override", enforce: true, now: outsideWindow, opts: VersionCommandOptions{IgnoreSchedule: true, NoCommit: true, NoTag: true}},
+		{name: "preview is always allowed", enforce: true, now: outsideWindow, opts: VersionCommandOptions{Preview: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := setupVersionTestRepo(t)
+			setSchedule(t, tempDir, tt.enforce)
+			setScheduleNow(t, tt.now)
+			consignmentsDir := filepath.Join(tempDir, ".shipyard", "consignments")
+			createTestConsignmentForVersion(t, consignmentsDir, "c1", []string{"test-package"}, "minor", "Add feature")
+
+			var err error
+			captureOutput(func() {
+				err = runVersionWithDir(tempDir, &tt.opts)
+			})
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				assert.FileExists(t, filepath.Join(consignmentsDir, "c1.md"))
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+
+	t.Run("flag without a schedule", func(t *testing.T) {
+		tempDir := setupVersionTestRepo(t)
+		createTestConsignmentForVersion(t, filepath.Join(tempDir, ".shipyard", "consignments"), "c1", []string{"test-package"}, "minor", "Add feature")
+		err := runVersionWithDir(tempDir, &VersionCommandOptions{RespectSchedule: true})
+		assert.ErrorContains(t, err, "--respect-schedule requires releaseSchedule")
+	})
+}
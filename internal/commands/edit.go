@@ -0,0 +1,300 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/consignment"
+	"github.com/NatoNathan/shipyard/internal/ui"
+	"github.com/NatoNathan/shipyard/pkg/types"
+	"github.com/charmbracelet/huh"
+	"github.com/spf13/cobra"
+)
+
+// EditCommandOptions holds options for the edit command
+type EditCommandOptions struct {
+	ID             string
+	Summary        string
+	Type           string
+	AddPackages    []string
+	RemovePackages []string
+	JSON           bool
+	Quiet          bool
+}
+
+// EditOutput is the JSON output structure for the edit command
+type EditOutput struct {
+	ID         string   `json:"id"`
+	Path       string   `json:"path"`
+	Packages   []string `json:"packages"`
+	ChangeType string   `json:"changeType"`
+	Summary    string   `json:"summary"`
+}
+
+// hasChanges reports whether any non-interactive edit was requested
+func (o *EditCommandOptions) hasChanges() bool {
+	return o.Summary != "" || o.Type != "" || len(o.AddPackages) > 0 || len(o.RemovePackages) > 0
+}
+
+// NewEditCommand creates the edit command
+func NewEditCommand() *cobra.Command {
+	opts := &EditCommandOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "edit [id] [--summary text] [--type {patch|minor|major}] [--add-package name]... [--remove-package name]...",
+		Short: "Amend cargo already in the manifest",
+		Long: `Change the packages, change type or summary of a pending consignment.
+
+Without flags, a form pre-filled with the consignment's current values opens;
+without an ID, pick the consignment from a list first. The flags edit the
+consignment directly: --summary replaces the first line of the summary and
+keeps any description below it.
+
+The file is rewritten in the same format as shipyard add, keeping its ID,
+timestamp and metadata. Edits are validated like new consignments, so the
+last package cannot be removed and the change type must be patch, minor or
+major.`,
+		Example: `  # Edit a consignment in a form
+  shipyard edit 20240101-120000-abc123
+
+  # Pick the consignment to edit
+  shipyard edit
+
+  # Fix a typo in the summary
+  shipyard edit 20240101-120000-abc123 --summary "Fixed token refresh"
+
+  # Move a change from core to api and raise it to minor
+  shipyard edit 20240101-120000-abc123 --add-package api --remove-package core --type minor`,
+		Args: cobra.MaximumNArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return completeConsignments(cmd, args, toComplete)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			globalFlags := GetGlobalFlags(cmd)
+			if len(args) == 1 {
+				opts.ID = args[0]
+			}
+			opts.JSON = globalFlags.JSON
+			opts.Quiet = globalFlags.Quiet
+			return runEdit(opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Summary, "summary", "s", "", "Replace the summary line")
+	cmd.Flags().StringVarP(&opts.Type, "type", "t", "", "Change type: patch, minor, or major")
+	cmd.Flags().StringSliceVar(&opts.AddPackages, "add-package", nil, "Add a package to the consignment (can be repeated)")
+	cmd.Flags().StringSliceVar(&opts.RemovePackages, "remove-package", nil, "Remove a package from the consignment (can be repeated)")
+
+	RegisterPackageCompletions(cmd, "add-package")
+	RegisterPackageCompletions(cmd, "remove-package")
+	RegisterChangeTypeCompletions(cmd, "type")
+
+	return cmd
+}
+
+func runEdit(opts *EditCommandOptions) error {
+	cwd, err := projectRoot()
+	if err != nil {
+		return err
+	}
+	return runEditWithDir(cwd, opts)
+}
+
+func runEditWithDir(projectPath string, opts *EditCommandOptions) error {
+	cfg, err := config.LoadFromDir(projectPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	consignmentsPath := cfg.Consignments.Path
+	if consignmentsPath == "" {
+		consignmentsPath = ".shipyard/consignments"
+	}
+	consignmentsDir := filepath.Join(projectPath, consignmentsPath)
+
+	if opts.ID == "" {
+		if opts.hasChanges() {
+			return fmt.Errorf("specify the ID of the consignment to edit")
+		}
+		opts.ID, err = pickConsignmentToEdit(consignmentsDir)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := consignment.ValidateID(opts.ID); err != nil {
+		return err
+	}
+	cons, err := consignment.FindConsignment(consignmentsDir, opts.ID)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("consignment not found: %s", opts.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read consignment %s: %w", opts.ID, err)
+	}
+
+	if opts.hasChanges() {
+		applyConsignmentEdits(cons, opts)
+	} else if err := promptConsignmentEdits(cfg, cons); err != nil {
+		return err
+	}
+
+	// Edits are held to the same rules as a new consignment
+	if err := validatePackages(cfg, cons.Packages); err != nil {
+		return err
+	}
+	if err := validateChangeType(string(cons.ChangeType)); err != nil {
+		return err
+	}
+	if err := cons.Validate(); err != nil {
+		return fmt.Errorf("invalid consignment: %w", err)
+	}
+
+	if err := consignment.WriteConsignment(cons, consignmentsDir); err != nil {
+		return fmt.Errorf("failed to write consignment: %w", err)
+	}
+
+	relPath := filepath.ToSlash(filepath.Join(consignmentsPath, cons.FileName()))
+	if opts.JSON {
+		return PrintJSON(os.Stdout, EditOutput{
+			ID:         cons.ID,
+			Path:       relPath,
+			Packages:   cons.Packages,
+			ChangeType: string(cons.ChangeType),
+			Summary:    cons.Summary,
+		})
+	}
+
+	if !opts.Quiet {
+		fmt.Println()
+		fmt.Println(ui.SuccessMessage(fmt.Sprintf("Updated consignment %s", cons.ID)))
+		fmt.Println()
+		fmt.Println(ui.KeyValue("Path", relPath))
+		fmt.Println(ui.KeyValue("Packages", strings.Join(cons.Packages, ", ")))
+		fmt.Println(ui.KeyValue("Type", string(cons.ChangeType)))
+		fmt.Println(ui.KeyValue("Summary", truncateSummary(cons.Summary, 60)))
+		fmt.Println()
+	}
+
+	return nil
+}
+
+// applyConsignmentEdits applies the flag edits to a consignment. A new
+// summary replaces the first line and keeps the description below it.
+func applyConsignmentEdits(cons *consignment.Consignment, opts *EditCommandOptions) {
+	if opts.Summary != "" {
+		_, body, _ := strings.Cut(cons.Summary, "\n")
+		cons.Summary = composeSummary(opts.Summary, body)
+	}
+	if opts.Type != "" {
+		cons.ChangeType = types.ChangeType(opts.Type)
+	}
+
+	packages := slices.DeleteFunc(slices.Clone(cons.Packages), func(name string) bool {
+		return slices.Contains(opts.RemovePackages, name)
+	})
+	for _, name := range opts.AddPackages {
+		if !slices.Contains(packages, name) {
+			packages = append(packages, name)
+		}
+	}
+	cons.Packages = packages
+}
+
+// pickConsignmentToEdit lets the user choose a pending consignment
+func pickConsignmentToEdit(consignmentsDir string) (string, error) {
+	pending, err := consignment.ReadAllConsignments(consignmentsDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to read consignments: %w", err)
+	}
+	if len(pending) == 0 {
+		return "", fmt.Errorf("no pending consignments to edit")
+	}
+
+	options := make([]huh.Option[string], len(pending))
+	for i, c := range pending {
+		summary, _, _ := strings.Cut(c.Summary, "\n")
+		options[i] = huh.NewOption(fmt.Sprintf("%s [%s] %s: %s", c.ID, c.ChangeType, strings.Join(c.Packages, ", "), summary), c.ID)
+	}
+
+	var id string
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Select a consignment to edit:").
+				Options(options...).
+				Value(&id),
+		),
+	)
+	if err := runForm(form); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// promptConsignmentEdits edits a consignment in a form pre-filled with its
+// current packages, change type and summary
+func promptConsignmentEdits(cfg *config.Config, cons *consignment.Consignment) error {
+	// Packages no longer in the config stay listed so they can be removed
+	names := make([]string, 0, len(cfg.Packages))
+	for _, pkg := range cfg.Packages {
+		names = append(names, pkg.Name)
+	}
+	for _, name := range cons.Packages {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	packageOptions := make([]huh.Option[string], len(names))
+	for i, name := range names {
+		packageOptions[i] = huh.NewOption(name, name).Selected(slices.Contains(cons.Packages, name))
+	}
+
+	packages := slices.Clone(cons.Packages)
+	changeType := string(cons.ChangeType)
+	summary := cons.Summary
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Title("Packages").
+				Options(packageOptions...).
+				Value(&packages).
+				Validate(func(selected []string) error {
+					if len(selected) == 0 {
+						return fmt.Errorf("select at least one package")
+					}
+					return nil
+				}),
+			huh.NewSelect[string]().
+				Title("Change type").
+				Options(huh.NewOptions("patch", "minor", "major")...).
+				Value(&changeType),
+			huh.NewText().
+				Title("Summary").
+				Value(&summary).
+				Validate(func(s string) error {
+					if strings.TrimSpace(s) == "" {
+						return fmt.Errorf("summary cannot be empty")
+					}
+					return nil
+				}),
+		),
+	)
+	if err := runForm(form); err != nil {
+		return err
+	}
+
+	cons.Packages = packages
+	cons.ChangeType = types.ChangeType(changeType)
+	cons.Summary = strings.TrimSpace(summary)
+	return nil
+}
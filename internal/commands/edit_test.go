@@ -0,0 +1,121 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/consignment"
+	"github.com/NatoNathan/shipyard/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupEditTestProject(t *testing.T) (string, string) {
+	t.Helper()
+	tempDir := t.TempDir()
+	initShipyardConfig(t, tempDir)
+	consignmentsDir := filepath.Join(tempDir, ".shipyard", "consignments")
+
+	require.NoError(t, consignment.WriteConsignment(&consignment.Consignment{
+		ID:         "c1",
+		Timestamp:  time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC),
+		Packages:   []string{"core"},
+		ChangeType: types.ChangeTypePatch,
+		Summary:    "Fix tokne refresh\n\nRefresh tokens were dropped after a retry.",
+		Metadata:   map[string]interface{}{"author": "ana@example.com", "issue": "JIRA-12"},
+	}, consignmentsDir))
+	return tempDir, consignmentsDir
+}
+
+func TestEditCommand(t *testing.T) {
+	t.Run("edits with flags", func(t *testing.T) {
+		tempDir, consignmentsDir := setupEditTestProject(t)
+
+		require.NoError(t, runEditWithDir(tempDir, &EditCommandOptions{
+			ID:             "c1",
+			Summary:        "Fix token refresh",
+			Type:           "minor",
+			AddPackages:    []string{"api"},
+			RemovePackages: []string{"core"},
+			Quiet:          true,
+		}))
+
+		edited, err := consignment.ReadConsignment(filepath.Join(consignmentsDir, "c1.md"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"api"}, edited.Packages)
+		assert.Equal(t, types.ChangeTypeMinor, edited.ChangeType)
+		assert.Equal(t, "Fix token refresh\n\nRefresh tokens were dropped after a retry.", edited.Summary, "the description is kept")
+		assert.Equal(t, time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC), edited.Timestamp)
+		assert.Equal(t, map[string]interface{}{"author": "ana@example.com", "issue": "JIRA-12"}, edited.Metadata)
+	})
+
+	t.Run("round-trips the file canonically", func(t *testing.T) {
+		tempDir, consignmentsDir := setupEditTestProject(t)
+		path := filepath.Join(consignmentsDir, "c1.md")
+		original, err := os.ReadFile(path)
+		require.NoError(t, err)
+
+		require.NoError(t, runEditWithDir(tempDir, &EditCommandOptions{ID: "c1", Summary: "Fix token refresh", Quiet: true}))
+		edited, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, strings.Replace(string(original), "tokne", "token", 1), string(edited), "only the summary changes")
+
+		require.NoError(t, runEditWithDir(tempDir, &EditCommandOptions{ID: "c1", Type: "patch", Quiet: true}))
+		unchanged, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, string(edited), string(unchanged), "an edit that changes nothing leaves the file as is")
+	})
+
+	t.Run("rejects invalid edits", func(t *testing.T) {
+		tempDir, consignmentsDir := setupEditTestProject(t)
+		path := filepath.Join(consignmentsDir, "c1.md")
+		original, err := os.ReadFile(path)
+		require.NoError(t, err)
+
+		err = runEditWithDir(tempDir, &EditCommandOptions{ID: "c1", RemovePackages: []string{"core"}, Quiet: true})
+		assert.ErrorContains(t, err, "at least one package")
+
+		err = runEditWithDir(tempDir, &EditCommandOptions{ID: "c1", Type: "huge", Quiet: true})
+		assert.ErrorContains(t, err, "huge")
+
+		err = runEditWithDir(tempDir, &EditCommandOptions{ID: "c1", AddPackages: []string{"unknown"}, Quiet: true})
+		assert.ErrorContains(t, err, "unknown")
+
+		after, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, string(original), string(after), "rejected edits are not written")
+	})
+
+	t.Run("unknown consignment", func(t *testing.T) {
+		tempDir, _ := setupEditTestProject(t)
+
+		err := runEditWithDir(tempDir, &EditCommandOptions{ID: "missing", Summary: "x", Quiet: true})
+		assert.ErrorContains(t, err, "consignment not found: missing")
+
+		err = runEditWithDir(tempDir, &EditCommandOptions{ID: "../config", Summary: "x", Quiet: true})
+		assert.Error(t, err)
+	})
+
+	t.Run("finds a file named differently from its ID", func(t *testing.T) {
+		tempDir, consignmentsDir := setupEditTestProject(t)
+		require.NoError(t, os.Rename(filepath.Join(consignmentsDir, "c1.md"), filepath.Join(consignmentsDir, "token-fix.md")))
+
+		require.NoError(t, runEditWithDir(tempDir, &EditCommandOptions{ID: "c1", Type: "minor", Quiet: true}))
+
+		assert.NoFileExists(t, filepath.Join(consignmentsDir, "c1.md"), "the file keeps its name")
+		edited, err := consignment.ReadConsignment(filepath.Join(consignmentsDir, "token-fix.md"))
+		require.NoError(t, err)
+		assert.Equal(t, "c1", edited.ID)
+		assert.Equal(t, types.ChangeTypeMinor, edited.ChangeType)
+	})
+
+	t.Run("flags require an ID", func(t *testing.T) {
+		tempDir, _ := setupEditTestProject(t)
+
+		err := runEditWithDir(tempDir, &EditCommandOptions{Summary: "x", Quiet: true})
+		assert.ErrorContains(t, err, "specify the ID")
+	})
+}
@@ -3,8 +3,10 @@ package commands
 import (
 	"encoding/json"
 	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/NatoNathan/shipyard/internal/config"
 	"github.com/stretchr/testify/require"
 )
 
@@ -63,3 +65,40 @@ func assertContainsArrow(t *testing.T, output string) {
 	t.Helper()
 	require.Contains(t, output, "→", "Output should contain → arrow character")
 }
+
+// setRuleLevels rewrites the project config with the given rule levels
+func setRuleLevels(t *testing.T, dir string, levels map[string]string) {
+	t.Helper()
+	configPath := filepath.Join(dir, ".shipyard", "shipyard.yaml")
+	cfg, err := config.Load(configPath)
+	require.NoError(t, err)
+	cfg.Rules = levels
+	require.NoError(t, config.WriteConfig(cfg, configPath))
+}
+
+// setPackages rewrites the project config keeping only the named packages
+func setPackages(t *testing.T, dir string, names ...string) {
+	t.Helper()
+	configPath := filepath.Join(dir, ".shipyard", "shipyard.yaml")
+	cfg, err := config.Load(configPath)
+	require.NoError(t, err)
+	var kept []config.Package
+	for _, pkg := range cfg.Packages {
+		for _, name := range names {
+			if pkg.Name == name {
+				kept = append(kept, pkg)
+			}
+		}
+	}
+	cfg.Packages = kept
+	require.NoError(t, config.WriteConfig(cfg, configPath))
+}
+
+// writeGoVersion writes a version.go for a Go package at relPath under dir
+func writeGoVersion(t *testing.T, dir, relPath, version string) {
+	t.Helper()
+	pkgDir := filepath.Join(dir, relPath)
+	require.NoError(t, os.MkdirAll(pkgDir, 0755))
+	content := "package main\n\nconst Version = \"" + version + "\"\n"
+	require.NoError(t, os.WriteFile(filepath.Join(pkgDir, "version.go"), []byte(content), 0644))
+}
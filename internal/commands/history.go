@@ -0,0 +1,181 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/history"
+	"github.com/NatoNathan/shipyard/internal/release"
+	"github.com/NatoNathan/shipyard/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// HistoryConfigOptions holds options for the history config command
+type HistoryConfigOptions struct {
+	Version string
+	Package string
+	JSON    bool
+	Quiet   bool
+}
+
+// HistoryConfigResult is the JSON output of the history config command
+type HistoryConfigResult struct {
+	Package     string                  `json:"package"`
+	Version     string                  `json:"version"`
+	Recorded    *history.ConfigSnapshot `json:"recorded"`
+	CurrentHash string                  `json:"currentHash"`
+	Changed     bool                    `json:"changed"`
+	Diff        string                  `json:"diff,omitempty"`
+}
+
+// NewHistoryConfigCommand creates the history config command
+func NewHistoryConfigCommand() *cobra.Command {
+	opts := &HistoryConfigOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "config <version> [-p package]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Inspect the orders a voyage sailed under",
+		Long: `Show the configuration recorded when a version was shipped and compare it
+with the current effective configuration.
+
+The recorded hash is always shown. A line diff is printed when the full
+configuration was embedded at release time (history.embedConfig: true).`,
+		Example: `  # Show the config recorded for a version
+  shipyard history config 1.2.0
+
+  # Pick a package in a multi-package repository
+  shipyard history config 1.2.0 --package core`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			globalFlags := GetGlobalFlags(cmd)
+			opts.Version = args[0]
+			opts.JSON = globalFlags.JSON
+			opts.Quiet = globalFlags.Quiet
+			return runHistoryConfig(opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Package, "package", "p", "", "Package name (required for multi-package repos)")
+
+	RegisterPackageCompletions(cmd, "package")
+
+	return cmd
+}
+
+func runHistoryConfig(opts *HistoryConfigOptions) error {
+	cwd, err := projectRoot()
+	if err != nil {
+		return err
+	}
+	return runHistoryConfigWithDir(cwd, opts)
+}
+
+func runHistoryConfigWithDir(projectPath string, opts *HistoryConfigOptions) error {
+	result, err := compareHistoryConfig(projectPath, opts)
+	if err != nil {
+		return err
+	}
+	recorded := result.Recorded
+
+	if opts.JSON {
+		return PrintJSON(os.Stdout, result)
+	}
+	if opts.Quiet {
+		return nil
+	}
+
+	fmt.Println(ui.Header("⚙", fmt.Sprintf("Config for %s %s", result.Package, result.Version)))
+	fmt.Println(ui.KeyValue("Recorded hash", recorded.Hash))
+	if recorded.Path != "" {
+		fmt.Println(ui.KeyValue("Config file", recorded.Path))
+	}
+	if recorded.BlobHash != "" {
+		fmt.Println(ui.KeyValue("Blob hash", recorded.BlobHash))
+	}
+	fmt.Println(ui.KeyValue("Current hash", result.CurrentHash))
+	fmt.Println()
+
+	if !result.Changed {
+		fmt.Println(ui.SuccessMessage("Current configuration matches the recorded configuration"))
+		return nil
+	}
+
+	fmt.Println(ui.WarningMessage("Configuration has changed since this version was shipped"))
+	if result.Diff == "" {
+		fmt.Println(ui.Dimmed("Only the hash was recorded; set history.embedConfig: true to record the full configuration"))
+		return nil
+	}
+
+	fmt.Println()
+	fmt.Println(result.Diff)
+	return nil
+}
+
+// compareHistoryConfig looks up the config snapshot recorded for a version and
+// compares it with the current effective configuration
+func compareHistoryConfig(projectPath string, opts *HistoryConfigOptions) (*HistoryConfigResult, error) {
+	cfg, err := config.LoadFromDir(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	settings := release.SettingsFor(projectPath, cfg)
+
+	entry, err := findHistoryEntry(projectPath, cfg, settings, opts.Package, opts.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry.Config == nil {
+		return nil, fmt.Errorf("%s %s was shipped before config snapshots were recorded", entry.Package, entry.Version)
+	}
+
+	current, err := cfg.Snapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &HistoryConfigResult{
+		Package:     entry.Package,
+		Version:     entry.Version,
+		Recorded:    entry.Config,
+		CurrentHash: config.HashSnapshot(current),
+	}
+	result.Changed = result.CurrentHash != entry.Config.Hash
+	if result.Changed && entry.Config.Resolved != "" {
+		result.Diff = ui.Diff(entry.Config.Resolved, current)
+	}
+
+	return result, nil
+}
+
+// findHistoryEntry returns the most recent history entry for a package
+// version. The package may be omitted in single-package repositories.
+func findHistoryEntry(projectPath string, cfg *config.Config, settings release.Settings, packageName, versionArg string) (history.Entry, error) {
+	if len(cfg.Packages) > 1 && packageName == "" {
+		return history.Entry{}, fmt.Errorf("--package is required for multi-package repositories")
+	}
+	if len(cfg.Packages) == 1 && packageName == "" {
+		packageName = cfg.Packages[0].Name
+	}
+
+	entries, err := settings.History(filepath.Join(projectPath, cfg.History.Path)).Read()
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return history.Entry{}, fmt.Errorf("failed to read history: %w", err)
+		}
+		entries = []history.Entry{}
+	}
+
+	version, err := ParseVersionArg(versionArg)
+	if err != nil {
+		return history.Entry{}, err
+	}
+	entries = history.FilterByVersion(release.PackageEntries(cfg, entries, packageName), version)
+	if len(entries) == 0 {
+		return history.Entry{}, fmt.Errorf("no history entry found for %s %s", packageName, versionArg)
+	}
+	return entries[len(entries)-1], nil
+}
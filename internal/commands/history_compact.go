@@ -0,0 +1,146 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/history"
+	"github.com/NatoNathan/shipyard/internal/release"
+	"github.com/NatoNathan/shipyard/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// HistoryCompactOptions holds options for the history compact command
+type HistoryCompactOptions struct {
+	Keep   int
+	Since  string
+	DryRun bool
+	JSON   bool
+	Quiet  bool
+}
+
+// NewHistoryCompactCommand creates the history compact command
+func NewHistoryCompactCommand() *cobra.Command {
+	opts := &HistoryCompactOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "compact [--keep N] [--since DATE] [--dry-run]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Stow old voyage logs in the archive",
+		Long: `Move older history entries out of the main history file into yearly
+archive files, e.g. .shipyard/history/2023.json, keeping a window of recent
+entries in the main file.
+
+The newest and the highest version of every package always stay in the main
+file, so version calculation never needs the archives. Changelog regeneration
+and release notes for past versions read the archives as well.
+
+Without --keep or --since, history.keep from the configuration is used.`,
+		Example: `  # Keep the 50 most recent entries
+  shipyard history compact --keep 50
+
+  # Keep everything released this year
+  shipyard history compact --since 2026-01-01
+
+  # Show what would move without writing
+  shipyard history compact --keep 50 --dry-run`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			globalFlags := GetGlobalFlags(cmd)
+			opts.JSON = globalFlags.JSON
+			opts.Quiet = globalFlags.Quiet
+			return runHistoryCompact(opts)
+		},
+	}
+
+	cmd.Flags().IntVar(&opts.Keep, "keep", 0, "Number of most recent entries to keep in the main history file")
+	cmd.Flags().StringVar(&opts.Since, "since", "", "Keep entries released on or after this date (YYYY-MM-DD or RFC 3339)")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Show what would be archived without writing")
+
+	return cmd
+}
+
+func runHistoryCompact(opts *HistoryCompactOptions) error {
+	cwd, err := projectRoot()
+	if err != nil {
+		return err
+	}
+	return runHistoryCompactWithDir(cwd, opts)
+}
+
+func runHistoryCompactWithDir(projectPath string, opts *HistoryCompactOptions) error {
+	cfg, err := config.LoadFromDir(projectPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	settings := release.SettingsFor(projectPath, cfg)
+
+	compactOpts := history.CompactOptions{Keep: opts.Keep, DryRun: opts.DryRun}
+	if opts.Since != "" {
+		compactOpts.Since, err = parseSinceDate(opts.Since)
+		if err != nil {
+			return err
+		}
+	}
+	if compactOpts.Keep == 0 && compactOpts.Since.IsZero() {
+		compactOpts.Keep = cfg.History.Keep
+	}
+	if compactOpts.Keep == 0 && compactOpts.Since.IsZero() {
+		return fmt.Errorf("pass --keep or --since, or set history.keep in the configuration")
+	}
+
+	historyPath := filepath.Join(projectPath, cfg.History.Path)
+	result, err := settings.History(historyPath).Compact(compactOpts)
+	if err != nil {
+		return fmt.Errorf("failed to compact history: %w", err)
+	}
+
+	archives := make([]string, 0, len(result.Archives))
+	for _, archive := range result.Archives {
+		if rel, err := filepath.Rel(projectPath, archive); err == nil {
+			archive = rel
+		}
+		archives = append(archives, archive)
+	}
+
+	if opts.JSON {
+		return PrintJSON(os.Stdout, map[string]interface{}{
+			"kept":     result.Kept,
+			"archived": result.Archived,
+			"archives": archives,
+			"dryRun":   opts.DryRun,
+		})
+	}
+	if opts.Quiet {
+		return nil
+	}
+
+	switch {
+	case result.Archived == 0:
+		fmt.Println(ui.InfoMessage(fmt.Sprintf("Nothing to archive; %d entries are within the window", result.Kept)))
+		return nil
+	case opts.DryRun:
+		fmt.Println(ui.InfoMessage(fmt.Sprintf("Would archive %d entries and keep %d", result.Archived, result.Kept)))
+	default:
+		fmt.Println(ui.SuccessMessage(fmt.Sprintf("Archived %d entries and kept %d", result.Archived, result.Kept)))
+	}
+	for _, archive := range archives {
+		fmt.Println(ui.KeyValue("Archive", archive))
+	}
+	return nil
+}
+
+// parseSinceDate accepts a calendar date (UTC midnight) or an RFC 3339 timestamp
+func parseSinceDate(value string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --since %q: expected YYYY-MM-DD or an RFC 3339 timestamp", value)
+	}
+	return t, nil
+}
@@ -0,0 +1,241 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/editor"
+	"github.com/NatoNathan/shipyard/internal/git"
+	"github.com/NatoNathan/shipyard/internal/history"
+	"github.com/NatoNathan/shipyard/internal/release"
+	"github.com/NatoNathan/shipyard/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// openNoteEditor opens $EDITOR for a note; replaced in tests
+var openNoteEditor = editor.OpenEditor
+
+// HistoryAnnotateOptions holds options for the history annotate command
+type HistoryAnnotateOptions struct {
+	Version string
+	Package string
+	Message string
+	Author  string
+	Edit    bool
+	JSON    bool
+	Quiet   bool
+}
+
+// NewHistoryAnnotateCommand creates the history annotate command
+func NewHistoryAnnotateCommand() *cobra.Command {
+	opts := &HistoryAnnotateOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "annotate <version> [-p package] (-m message | --edit)",
+		DisableFlagsInUseLine: true,
+		Short:                 "Add a note to the log of a past voyage",
+		Long: `Attach a timestamped, authored note to a shipped version, such as a
+regression found after release.
+
+Notes are appended to the history entry and never rewrite it or affect
+version computation. They are shown by 'history show', included in
+release-notes JSON, and rendered in changelogs when changelog.notes is true.`,
+		Example: `  # Record a note for a version
+  shipyard history annotate 2.3.0 -m "Perf regression fixed in 2.3.1"
+
+  # Write the note in $EDITOR
+  shipyard history annotate 2.3.0 --package core --edit`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			globalFlags := GetGlobalFlags(cmd)
+			opts.Version = args[0]
+			opts.JSON = globalFlags.JSON
+			opts.Quiet = globalFlags.Quiet
+			return runHistoryAnnotate(opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Package, "package", "p", "", "Package name (required for multi-package repos)")
+	cmd.Flags().StringVarP(&opts.Message, "message", "m", "", "Note text")
+	cmd.Flags().StringVar(&opts.Author, "author", "", "Note author (default: git user.name and user.email)")
+	cmd.Flags().BoolVar(&opts.Edit, "edit", false, "Write the note in $EDITOR")
+	cmd.MarkFlagsMutuallyExclusive("message", "edit")
+
+	RegisterPackageCompletions(cmd, "package")
+
+	return cmd
+}
+
+func runHistoryAnnotate(opts *HistoryAnnotateOptions) error {
+	cwd, err := projectRoot()
+	if err != nil {
+		return err
+	}
+	return runHistoryAnnotateWithDir(cwd, opts)
+}
+
+func runHistoryAnnotateWithDir(projectPath string, opts *HistoryAnnotateOptions) error {
+	cfg, err := config.LoadFromDir(projectPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	settings := release.SettingsFor(projectPath, cfg)
+
+	entry, err := findHistoryEntry(projectPath, cfg, settings, opts.Package, opts.Version)
+	if err != nil {
+		return err
+	}
+
+	text := strings.TrimSpace(opts.Message)
+	if opts.Edit {
+		content, err := openNoteEditor(projectPath, fmt.Sprintf("\n# Note for %s %s. Lines starting with # are ignored.\n", entry.Package, entry.Version))
+		if err != nil {
+			return err
+		}
+		text = stripNoteComments(content)
+	}
+	if text == "" {
+		return fmt.Errorf("note cannot be empty; pass --message or --edit")
+	}
+
+	author := opts.Author
+	if author == "" {
+		author = git.ConfiguredAuthor(projectPath)
+	}
+	note := history.Note{Text: text, Author: author, Timestamp: time.Now().UTC()}
+
+	if err := settings.History(filepath.Join(projectPath, cfg.History.Path)).AddNote(entry.Package, entry.Version, note); err != nil {
+		return fmt.Errorf("failed to record note: %w", err)
+	}
+
+	if opts.JSON {
+		return PrintJSON(os.Stdout, map[string]interface{}{
+			"package": entry.Package,
+			"version": entry.Version,
+			"note":    note,
+		})
+	}
+	if !opts.Quiet {
+		fmt.Println(ui.SuccessMessage(fmt.Sprintf("Added note to %s %s", entry.Package, entry.Version)))
+	}
+	return nil
+}
+
+// stripNoteComments drops # comment lines and surrounding blank lines from editor content
+func stripNoteComments(content string) string {
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		lines = append(lines, strings.TrimRight(line, " \t\r"))
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// HistoryShowOptions holds options for the history show command
+type HistoryShowOptions struct {
+	Version string
+	Package string
+	JSON    bool
+	Quiet   bool
+}
+
+// NewHistoryShowCommand creates the history show command
+func NewHistoryShowCommand() *cobra.Command {
+	opts := &HistoryShowOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "show <version> [-p package]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Read the log entry for a voyage",
+		Long: `Show the history entry recorded for a shipped version: its tag, release
+date, consignments, published artifacts, and any notes added afterwards.`,
+		Example: `  # Show a version
+  shipyard history show 1.2.0
+
+  # Pick a package in a multi-package repository
+  shipyard history show 1.2.0 --package core`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			globalFlags := GetGlobalFlags(cmd)
+			opts.Version = args[0]
+			opts.JSON = globalFlags.JSON
+			opts.Quiet = globalFlags.Quiet
+			return runHistoryShow(opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Package, "package", "p", "", "Package name (required for multi-package repos)")
+
+	RegisterPackageCompletions(cmd, "package")
+
+	return cmd
+}
+
+func runHistoryShow(opts *HistoryShowOptions) error {
+	cwd, err := projectRoot()
+	if err != nil {
+		return err
+	}
+	return runHistoryShowWithDir(cwd, opts)
+}
+
+func runHistoryShowWithDir(projectPath string, opts *HistoryShowOptions) error {
+	cfg, err := config.LoadFromDir(projectPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	settings := release.SettingsFor(projectPath, cfg)
+
+	entry, err := findHistoryEntry(projectPath, cfg, settings, opts.Package, opts.Version)
+	if err != nil {
+		return err
+	}
+
+	if opts.JSON {
+		return PrintJSON(os.Stdout, entry)
+	}
+	if opts.Quiet {
+		return nil
+	}
+
+	fmt.Println(ui.Header("📜", fmt.Sprintf("%s %s", entry.Package, entry.Version)))
+	fmt.Println(ui.KeyValue("Tag", entry.VersionTag()))
+	fmt.Println(ui.KeyValue("Released", entry.Timestamp.Format("2006-01-02 15:04:05 MST")))
+	if entry.Yanked {
+		fmt.Println(ui.WarningMessage("This release was yanked"))
+	}
+
+	fmt.Println()
+	fmt.Printf("Consignments (%d):\n", len(entry.Consignments))
+	for _, c := range entry.Consignments {
+		fmt.Printf("  - [%s] %s %s\n", c.ChangeType, c.Summary, ui.Dimmed("("+c.ID+")"))
+	}
+
+	if len(entry.Artifacts) > 0 {
+		fmt.Println()
+		fmt.Println("Artifacts:")
+		for _, a := range entry.Artifacts {
+			fmt.Printf("  - %s %s\n", a.Reference, ui.Dimmed(a.Digest))
+		}
+	}
+
+	if len(entry.Notes) > 0 {
+		fmt.Println()
+		fmt.Println("Notes:")
+		for _, note := range entry.Notes {
+			byline := note.Timestamp.Format("2006-01-02")
+			if note.Author != "" {
+				byline = note.Author + ", " + byline
+			}
+			fmt.Printf("  - %s %s\n", strings.ReplaceAll(note.Text, "\n", "\n    "), ui.Dimmed("("+byline+")"))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/fileutil"
+	"github.com/NatoNathan/shipyard/internal/history"
+	"github.com/NatoNathan/shipyard/internal/release"
+	"github.com/NatoNathan/shipyard/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// HistoryRenameOptions holds options for the history rename-package command
+type HistoryRenameOptions struct {
+	OldName string
+	NewName string
+	DryRun  bool
+	JSON    bool
+	Quiet   bool
+}
+
+// NewHistoryRenamePackageCommand creates the history rename-package command
+func NewHistoryRenamePackageCommand() *cobra.Command {
+	opts := &HistoryRenameOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "rename-package <old> <new> [--dry-run]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Repaint a ship's name in the log",
+		Long: `Rewrite the history entries of a renamed package to its new name, in the
+main history file, every channel history and their yearly archives.
+
+Each rewritten file is first copied alongside itself with a .bak suffix.
+Tags and consignments are left as recorded.
+
+To keep the old entries as they are instead, list the old name under the
+package's aliases in the configuration.`,
+		Example: `  # Move web-app's history to web
+  shipyard history rename-package web-app web
+
+  # Show what would change without writing
+  shipyard history rename-package web-app web --dry-run`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			globalFlags := GetGlobalFlags(cmd)
+			opts.OldName = args[0]
+			opts.NewName = args[1]
+			opts.JSON = globalFlags.JSON
+			opts.Quiet = globalFlags.Quiet
+			return runHistoryRename(opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Show what would be renamed without writing")
+
+	return cmd
+}
+
+func runHistoryRename(opts *HistoryRenameOptions) error {
+	cwd, err := projectRoot()
+	if err != nil {
+		return err
+	}
+	return runHistoryRenameWithDir(cwd, opts)
+}
+
+func runHistoryRenameWithDir(projectPath string, opts *HistoryRenameOptions) error {
+	cfg, err := config.LoadFromDir(projectPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	settings := release.SettingsFor(projectPath, cfg)
+
+	historyPaths := []string{cfg.History.Path}
+	for _, channel := range cfg.Channels {
+		if path := cfg.HistoryPathFor(channel.Name); path != cfg.History.Path {
+			historyPaths = append(historyPaths, path)
+		}
+	}
+
+	rename := func(dryRun bool) (*history.RenameResult, error) {
+		total := &history.RenameResult{Files: []string{}, Backups: []string{}}
+		for _, path := range historyPaths {
+			historyPath := filepath.Join(projectPath, path)
+			if !fileutil.PathExists(historyPath) {
+				continue
+			}
+			result, err := settings.History(historyPath).RenamePackage(opts.OldName, opts.NewName, dryRun)
+			if err != nil {
+				return nil, fmt.Errorf("failed to rename %s in %s: %w", opts.OldName, path, err)
+			}
+			total.Renamed += result.Renamed
+			total.Files = append(total.Files, relativePaths(projectPath, result.Files)...)
+			total.Backups = append(total.Backups, relativePaths(projectPath, result.Backups)...)
+		}
+		return total, nil
+	}
+
+	// Check every history before writing any, so a conflict in one channel
+	// does not leave the others renamed
+	total, err := rename(true)
+	if err != nil {
+		return err
+	}
+	if !opts.DryRun && total.Renamed > 0 {
+		if total, err = rename(false); err != nil {
+			return err
+		}
+	}
+
+	if opts.JSON {
+		return PrintJSON(os.Stdout, map[string]interface{}{
+			"renamed": total.Renamed,
+			"files":   total.Files,
+			"backups": total.Backups,
+			"dryRun":  opts.DryRun,
+		})
+	}
+	if opts.Quiet {
+		return nil
+	}
+
+	switch {
+	case total.Renamed == 0:
+		fmt.Println(ui.InfoMessage(fmt.Sprintf("No history entries for %s", opts.OldName)))
+		return nil
+	case opts.DryRun:
+		fmt.Println(ui.InfoMessage(fmt.Sprintf("Would rename %d entries from %s to %s", total.Renamed, opts.OldName, opts.NewName)))
+	default:
+		fmt.Println(ui.SuccessMessage(fmt.Sprintf("Renamed %d entries from %s to %s", total.Renamed, opts.OldName, opts.NewName)))
+	}
+	for i, file := range total.Files {
+		fmt.Println(ui.KeyValue("File", file))
+		if !opts.DryRun {
+			fmt.Println(ui.KeyValue("Backup", total.Backups[i]))
+		}
+	}
+	if _, ok := cfg.GetPackage(opts.NewName); !ok {
+		fmt.Println(ui.WarningMessage(fmt.Sprintf("%s is not a configured package; rename it in the configuration too", opts.NewName)))
+	}
+	return nil
+}
+
+// relativePaths returns paths relative to the project, where possible
+func relativePaths(projectPath string, paths []string) []string {
+	relative := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if rel, err := filepath.Rel(projectPath, path); err == nil {
+			path = rel
+		}
+		relative = append(relative, path)
+	}
+	return relative
+}
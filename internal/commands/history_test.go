@@ -0,0 +1,375 @@
+package commands
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/history"
+	"github.com/NatoNathan/shipyard/internal/release"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistoryConfig_RecordsSnapshot(t *testing.T) {
+	tempDir := setupVersionTestRepo(t)
+	initGitRepo(t, tempDir)
+
+	configPath := filepath.Join(tempDir, ".shipyard", "shipyard.yaml")
+	configContent, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(configPath, append(configContent, []byte("  embedConfig: true\n")...), 0644))
+
+	consignmentsDir := filepath.Join(tempDir, ".shipyard", "consignments")
+	createTestConsignmentForVersion(t, consignmentsDir, "c1", []string{"test-package"}, "minor", "Add feature")
+	require.NoError(t, runVersionInDir(tempDir, &VersionCommandOptions{NoCommit: true, NoTag: true}))
+
+	entries, err := history.ReadHistory(filepath.Join(tempDir, ".shipyard", "history.json"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.NotNil(t, entries[0].Config)
+	assert.Equal(t, ".shipyard/shipyard.yaml", entries[0].Config.Path)
+	assert.Contains(t, entries[0].Config.Resolved, "test-package")
+
+	t.Run("hash is stable for unchanged config", func(t *testing.T) {
+		cfg, err := config.LoadFromDir(tempDir)
+		require.NoError(t, err)
+		hash, err := cfg.Hash()
+		require.NoError(t, err)
+		assert.Equal(t, entries[0].Config.Hash, hash)
+
+		result, err := compareHistoryConfig(tempDir, &HistoryConfigOptions{Version: "1.1.0"})
+		require.NoError(t, err)
+		assert.False(t, result.Changed)
+		assert.Empty(t, result.Diff)
+	})
+
+	t.Run("diff after config edit", func(t *testing.T) {
+		edited := strings.Replace(string(configContent), "builtin:default", "builtin:grouped", 1)
+		require.NoError(t, os.WriteFile(configPath, []byte(edited+"  embedConfig: true\n"), 0644))
+
+		result, err := compareHistoryConfig(tempDir, &HistoryConfigOptions{Version: "v1.1.0"})
+		require.NoError(t, err)
+		assert.True(t, result.Changed)
+		assert.NotEqual(t, result.Recorded.Hash, result.CurrentHash)
+		assert.Contains(t, result.Diff, "- ")
+		assert.Contains(t, result.Diff, "builtin:default")
+		assert.Contains(t, result.Diff, "+ ")
+		assert.Contains(t, result.Diff, "builtin:grouped")
+	})
+
+	t.Run("unknown version", func(t *testing.T) {
+		_, err := compareHistoryConfig(tempDir, &HistoryConfigOptions{Version: "9.9.9"})
+		assert.Error(t, err)
+	})
+}
+
+func TestHistoryConfig_HashOnly(t *testing.T) {
+	tempDir := setupVersionTestRepo(t)
+
+	consignmentsDir := filepath.Join(tempDir, ".shipyard", "consignments")
+	createTestConsignmentForVersion(t, consignmentsDir, "c1", []string{"test-package"}, "patch", "Fix bug")
+	require.NoError(t, runVersionInDir(tempDir, &VersionCommandOptions{NoCommit: true, NoTag: true}))
+
+	entries, err := history.ReadHistory(filepath.Join(tempDir, ".shipyard", "history.json"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.NotNil(t, entries[0].Config)
+	assert.NotEmpty(t, entries[0].Config.Hash)
+	assert.Empty(t, entries[0].Config.Resolved, "config should only be embedded when enabled")
+	assert.Empty(t, entries[0].Config.BlobHash, "blob hash requires a git repository")
+}
+
+func TestParseVersionArg(t *testing.T) {
+	tests := []struct {
+		name    string
+		arg     string
+		want    string
+		wantErr bool
+	}{
+		{name: "bare", arg: "1.2.0", want: "1.2.0"},
+		{name: "v prefix", arg: "v1.2.0", want: "1.2.0"},
+		{name: "pre-release", arg: "v1.2.0-rc.1", want: "1.2.0-rc.1"},
+		{name: "surrounding space", arg: " 1.2.0 ", want: "1.2.0"},
+		{name: "empty", arg: "", wantErr: true},
+		{name: "tag path", arg: "core/v1.2.0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseVersionArg(tt.arg)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestHistoryAnnotate(t *testing.T) {
+	tempDir := setupVersionTestRepo(t)
+	configPath := filepath.Join(tempDir, ".shipyard", "shipyard.yaml")
+	configContent, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(configPath, append(configContent, []byte("changelog:\n  notes: true\n")...), 0644))
+
+	consignmentsDir := filepath.Join(tempDir, ".shipyard", "consignments")
+	createTestConsignmentForVersion(t, consignmentsDir, "c1", []string{"test-package"}, "minor", "Add feature")
+	require.NoError(t, runVersionInDir(tempDir, &VersionCommandOptions{NoCommit: true, NoTag: true}))
+	historyPath := filepath.Join(tempDir, ".shipyard", "history.json")
+
+	t.Run("adds notes in order", func(t *testing.T) {
+		require.NoError(t, runHistoryAnnotateWithDir(tempDir, &HistoryAnnotateOptions{
+			Version: "v1.1.0", Message: "Perf regression, fixed in 1.1.1", Author: "Jane", Quiet: true,
+		}))
+
+		original := openNoteEditor
+		t.Cleanup(func() { openNoteEditor = original })
+		openNoteEditor = func(dir, initial string) (string, error) {
+			assert.Contains(t, initial, "test-package 1.1.0")
+			return initial + "Affects ARM builds only\n", nil
+		}
+		require.NoError(t, runHistoryAnnotateWithDir(tempDir, &HistoryAnnotateOptions{
+			Version: "1.1.0", Edit: true, Author: "Sam", Quiet: true,
+		}))
+
+		entries, err := history.ReadHistory(historyPath)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		require.Len(t, entries[0].Notes, 2)
+		assert.Equal(t, "Perf regression, fixed in 1.1.1", entries[0].Notes[0].Text)
+		assert.Equal(t, "Jane", entries[0].Notes[0].Author)
+		assert.False(t, entries[0].Notes[0].Timestamp.IsZero())
+		assert.Equal(t, "Affects ARM builds only", entries[0].Notes[1].Text)
+		assert.Equal(t, "1.1.0", entries[0].Version, "notes must not change the entry")
+	})
+
+	t.Run("show lists notes", func(t *testing.T) {
+		output := captureOutput(func() {
+			require.NoError(t, runHistoryShowWithDir(tempDir, &HistoryShowOptions{Version: "1.1.0", JSON: true}))
+		})
+		var entry history.Entry
+		require.NoError(t, json.Unmarshal([]byte(output), &entry))
+		require.Len(t, entry.Notes, 2)
+		assert.Equal(t, "Jane", entry.Notes[0].Author)
+	})
+
+	t.Run("changelog renders notes", func(t *testing.T) {
+		createTestConsignmentForVersion(t, consignmentsDir, "c2", []string{"test-package"}, "patch", "Fix regression")
+		require.NoError(t, runVersionInDir(tempDir, &VersionCommandOptions{NoCommit: true, NoTag: true}))
+
+		changelog, err := os.ReadFile(filepath.Join(tempDir, "test-package", "CHANGELOG.md"))
+		require.NoError(t, err)
+		assert.Contains(t, string(changelog), "### Notes\n- Perf regression, fixed in 1.1.1\n- Affects ARM builds only")
+		assert.Equal(t, 1, strings.Count(string(changelog), "### Notes"), "only annotated versions get a notes block")
+	})
+
+	t.Run("changelog omits notes by default", func(t *testing.T) {
+		cfg, err := config.LoadFromDir(tempDir)
+		require.NoError(t, err)
+		cfg.Changelog.Notes = false
+		entries, err := history.ReadHistory(historyPath)
+		require.NoError(t, err)
+		for _, entry := range release.ChangelogEntriesFor(cfg, entries) {
+			assert.Empty(t, entry.Notes)
+		}
+	})
+
+	t.Run("rejects unknown version and empty notes", func(t *testing.T) {
+		err := runHistoryAnnotateWithDir(tempDir, &HistoryAnnotateOptions{Version: "9.9.9", Message: "x", Quiet: true})
+		assert.ErrorContains(t, err, "no history entry found")
+
+		err = runHistoryAnnotateWithDir(tempDir, &HistoryAnnotateOptions{Version: "1.1.0", Message: "  ", Quiet: true})
+		assert.ErrorContains(t, err, "note cannot be empty")
+	})
+}
+
+func TestHistoryCompact_ThenBump(t *testing.T) {
+	tempDir := setupVersionTestRepo(t)
+	consignmentsDir := filepath.Join(tempDir, ".shipyard", "consignments")
+	historyPath := filepath.Join(tempDir, ".shipyard", "history.json")
+
+	for i, changeType := range []string{"minor", "patch", "minor"} {
+		createTestConsignmentForVersion(t, consignmentsDir, "c"+string(rune('1'+i)), []string{"test-package"}, changeType, "Change "+changeType)
+		require.NoError(t, runVersionInDir(tempDir, &VersionCommandOptions{NoCommit: true, NoTag: true}))
+	}
+
+	t.Run("requires a window", func(t *testing.T) {
+		err := runHistoryCompactWithDir(tempDir, &HistoryCompactOptions{Quiet: true})
+		assert.ErrorContains(t, err, "--keep or --since")
+	})
+
+	t.Run("dry run writes nothing", func(t *testing.T) {
+		output := captureOutput(func() {
+			require.NoError(t, runHistoryCompactWithDir(tempDir, &HistoryCompactOptions{Keep: 1, DryRun: true, JSON: true}))
+		})
+		var result map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(output), &result))
+		assert.Equal(t, float64(2), result["archived"])
+		assert.NoDirExists(t, history.ArchiveDir(historyPath))
+	})
+
+	require.NoError(t, runHistoryCompactWithDir(tempDir, &HistoryCompactOptions{Keep: 1, Quiet: true}))
+
+	entries, err := history.ReadHistory(historyPath)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "1.2.0", entries[0].Version)
+
+	createTestConsignmentForVersion(t, consignmentsDir, "c4", []string{"test-package"}, "minor", "Add after compaction")
+	require.NoError(t, runVersionInDir(tempDir, &VersionCommandOptions{NoCommit: true, NoTag: true}))
+
+	entries, err = history.ReadHistory(historyPath)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "1.3.0", entries[1].Version)
+
+	all, err := history.ReadHistoryWithArchives(historyPath)
+	require.NoError(t, err)
+	assert.Len(t, all, 4)
+
+	// The regenerated changelog still covers the archived versions
+	changelog, err := os.ReadFile(filepath.Join(tempDir, "test-package", "CHANGELOG.md"))
+	require.NoError(t, err)
+	for _, version := range []string{"1.1.0", "1.1.1", "1.2.0", "1.3.0"} {
+		assert.Contains(t, string(changelog), "["+version+"]")
+	}
+
+	// Archived versions can still be shown by release notes
+	defer changeToDir(t, tempDir)()
+	cmd := NewReleaseNotesCommand()
+	cmd.SetArgs([]string{"--version", "1.1.1"})
+	output := captureOutput(func() {
+		require.NoError(t, cmd.Execute())
+	})
+	assert.Contains(t, output, "Change patch")
+}
+
+func TestHistoryCompact_ConfigKeep(t *testing.T) {
+	tempDir := setupVersionTestRepo(t)
+	configPath := filepath.Join(tempDir, ".shipyard", "shipyard.yaml")
+	configContent, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	configContent = []byte(strings.Replace(string(configContent), "history:\n", "history:\n  keep: 1\n", 1))
+	require.NoError(t, os.WriteFile(configPath, configContent, 0644))
+
+	consignmentsDir := filepath.Join(tempDir, ".shipyard", "consignments")
+	for i := range 2 {
+		createTestConsignmentForVersion(t, consignmentsDir, "c"+string(rune('1'+i)), []string{"test-package"}, "patch", "Fix")
+		require.NoError(t, runVersionInDir(tempDir, &VersionCommandOptions{NoCommit: true, NoTag: true}))
+	}
+
+	require.NoError(t, runHistoryCompactWithDir(tempDir, &HistoryCompactOptions{Quiet: true}))
+	entries, err := history.ReadHistory(filepath.Join(tempDir, ".shipyard", "history.json"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "1.0.2", entries[0].Version)
+}
+
+func TestParseSinceDate(t *testing.T) {
+	since, err := parseSinceDate("2024-03-01")
+	require.NoError(t, err)
+	assert.Equal(t, "2024-03-01T00:00:00Z", since.Format("2006-01-02T15:04:05Z07:00"))
+
+	_, err = parseSinceDate("2024-03-01T10:00:00+02:00")
+	assert.NoError(t, err)
+
+	_, err = parseSinceDate("last year")
+	assert.ErrorContains(t, err, "invalid --since")
+}
+
+// renameTestPackage ships two releases of test-package, then renames it to
+// web in the configuration, listing the old name as an alias when aliased
+func renameTestPackage(t *testing.T, aliased bool) string {
+	t.Helper()
+	tempDir := setupVersionTestRepo(t)
+	consignmentsDir := filepath.Join(tempDir, ".shipyard", "consignments")
+	for i, changeType := range []string{"minor", "minor"} {
+		createTestConsignmentForVersion(t, consignmentsDir, "c"+string(rune('1'+i)), []string{"test-package"}, changeType, "Change "+string(rune('1'+i)))
+		require.NoError(t, runVersionInDir(tempDir, &VersionCommandOptions{NoCommit: true, NoTag: true}))
+	}
+
+	rename := "  - name: web\n"
+	if aliased {
+		rename += "    aliases: [test-package]\n"
+	}
+	configPath := filepath.Join(tempDir, ".shipyard", "shipyard.yaml")
+	configContent, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	configContent = []byte(strings.Replace(string(configContent), "  - name: test-package\n", rename, 1))
+	require.NoError(t, os.WriteFile(configPath, configContent, 0644))
+	return tempDir
+}
+
+// assertRenamedRelease ships a release of web and checks it continues the
+// renamed package's history
+func assertRenamedRelease(t *testing.T, tempDir string) {
+	t.Helper()
+	createTestConsignmentForVersion(t, filepath.Join(tempDir, ".shipyard", "consignments"), "c3", []string{"web"}, "minor", "Change 3")
+	require.NoError(t, runVersionInDir(tempDir, &VersionCommandOptions{NoCommit: true, NoTag: true}))
+
+	entries, err := history.ReadHistory(filepath.Join(tempDir, ".shipyard", "history.json"))
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, "web", entries[2].Package, "new entries use the canonical name")
+	assert.Equal(t, "1.3.0", entries[2].Version)
+
+	changelog, err := os.ReadFile(filepath.Join(tempDir, "test-package", "CHANGELOG.md"))
+	require.NoError(t, err)
+	for _, version := range []string{"1.1.0", "1.2.0", "1.3.0"} {
+		assert.Contains(t, string(changelog), "["+version+"]")
+	}
+}
+
+func TestPackageAliases(t *testing.T) {
+	tempDir := renameTestPackage(t, true)
+	assertRenamedRelease(t, tempDir)
+
+	entries, err := history.ReadHistory(filepath.Join(tempDir, ".shipyard", "history.json"))
+	require.NoError(t, err)
+	assert.Equal(t, "test-package", entries[0].Package, "aliased entries are left as recorded")
+
+	cfg, err := config.LoadFromDir(tempDir)
+	require.NoError(t, err)
+	entry, err := findHistoryEntry(tempDir, cfg, release.Settings{}, "web", "1.1.0")
+	require.NoError(t, err)
+	assert.Equal(t, "test-package", entry.Package)
+}
+
+func TestHistoryRenamePackage(t *testing.T) {
+	tempDir := renameTestPackage(t, false)
+	historyPath := filepath.Join(tempDir, ".shipyard", "history.json")
+	before, err := os.ReadFile(historyPath)
+	require.NoError(t, err)
+
+	t.Run("dry run writes nothing", func(t *testing.T) {
+		output := captureOutput(func() {
+			require.NoError(t, runHistoryRenameWithDir(tempDir, &HistoryRenameOptions{OldName: "test-package", NewName: "web", DryRun: true, JSON: true}))
+		})
+		var result map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(output), &result))
+		assert.Equal(t, float64(2), result["renamed"])
+		after, err := os.ReadFile(historyPath)
+		require.NoError(t, err)
+		assert.Equal(t, before, after)
+	})
+
+	require.NoError(t, runHistoryRenameWithDir(tempDir, &HistoryRenameOptions{OldName: "test-package", NewName: "web", Quiet: true}))
+
+	backup, err := os.ReadFile(history.BackupPath(historyPath))
+	require.NoError(t, err)
+	assert.Equal(t, before, backup)
+
+	entries, err := history.ReadHistory(historyPath)
+	require.NoError(t, err)
+	for _, entry := range entries {
+		assert.Equal(t, "web", entry.Package)
+	}
+
+	assertRenamedRelease(t, tempDir)
+}
@@ -0,0 +1,261 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/history"
+	"github.com/NatoNathan/shipyard/internal/keepachangelog"
+	"github.com/NatoNathan/shipyard/internal/release"
+	"github.com/NatoNathan/shipyard/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// ImportChangelogOptions holds options for the import changelog command
+type ImportChangelogOptions struct {
+	File    string // Changelog to import; the package's CHANGELOG.md by default
+	Package string // Package the releases belong to; required with several packages
+	DryRun  bool   // Report the versions without recording them
+	JSON    bool
+	Quiet   bool
+}
+
+// ImportChangelogOutput is the JSON output structure for the import changelog command
+type ImportChangelogOutput struct {
+	Package  string            `json:"package"`
+	Source   string            `json:"source"`
+	DryRun   bool              `json:"dryRun,omitempty"`
+	Imported []ImportedRelease `json:"imported"`
+	Skipped  []string          `json:"skipped,omitempty"` // Versions history already records
+}
+
+// ImportedRelease is a changelog version recorded as history
+type ImportedRelease struct {
+	Version      string `json:"version"`
+	Date         string `json:"date,omitempty"`
+	Changes      int    `json:"changes"`
+	Preformatted bool   `json:"preformatted,omitempty"` // Kept as written rather than split into changes
+}
+
+// NewImportChangelogCommand creates the import changelog command
+func NewImportChangelogCommand() *cobra.Command {
+	opts := &ImportChangelogOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "changelog [file]",
+		Short: "Take on the log of voyages already made",
+		Long: `Record the releases of an existing CHANGELOG.md in history, so changelogs
+shipyard writes keep them below its own releases.
+
+The changelog is read in the Keep a Changelog style, or the close variant
+conventional-changelog writes: a heading per version, optionally dated,
+with list items under section headings. Each item becomes a change whose
+type is guessed from its section: breaking changes and removals are major,
+additions, features, changes and deprecations minor, and everything else
+patch. A version whose text is not only list items is kept as written.
+The [Unreleased] section is left out.
+
+Versions history already records are skipped, so importing again is safe.
+The file defaults to the package's CHANGELOG.md; --package is required when
+several packages are configured.`,
+		Example: `  # Import the package's CHANGELOG.md
+  shipyard import changelog
+
+  # Import a monorepo package's changelog
+  shipyard import changelog --package core
+
+  # See what would be recorded from another file
+  shipyard import changelog docs/HISTORY.md --dry-run`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			globalFlags := GetGlobalFlags(cmd)
+			if len(args) == 1 {
+				// Named from the working directory, which may be below the project root
+				path, err := filepath.Abs(args[0])
+				if err != nil {
+					return fmt.Errorf("failed to resolve changelog %s: %w", args[0], err)
+				}
+				opts.File = path
+			}
+			opts.JSON = globalFlags.JSON
+			opts.Quiet = globalFlags.Quiet
+
+			cwd, err := projectRoot()
+			if err != nil {
+				return err
+			}
+			return runImportChangelog(cwd, opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Package, "package", "p", "", "Package the changelog belongs to")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Show the versions that would be imported without recording them")
+
+	return cmd
+}
+
+func runImportChangelog(projectPath string, opts *ImportChangelogOptions) error {
+	cfg, err := config.LoadFromDir(projectPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	var pkg config.Package
+	switch {
+	case opts.Package != "":
+		found, ok := cfg.GetPackage(opts.Package)
+		if !ok {
+			return fmt.Errorf("package %s is not in the config", opts.Package)
+		}
+		pkg = found
+	case len(cfg.Packages) == 1:
+		pkg = cfg.Packages[0]
+	default:
+		return fmt.Errorf("--package is required for multi-package repositories")
+	}
+
+	path := opts.File
+	if path == "" {
+		if path, err = release.ChangelogPath(projectPath, pkg); err != nil {
+			return err
+		}
+	} else if !filepath.IsAbs(path) {
+		path = filepath.Join(projectPath, path)
+	}
+
+	historyPath := filepath.Join(projectPath, cfg.HistoryPathFor(config.StableChannel))
+	output, err := importChangelog(projectPath, historyPath, pkg.Name, path, opts.DryRun)
+	if err != nil {
+		return err
+	}
+
+	if opts.JSON {
+		return PrintJSON(os.Stdout, output)
+	}
+	if opts.Quiet {
+		return nil
+	}
+
+	fmt.Println()
+	for _, imported := range output.Imported {
+		detail := fmt.Sprintf("%d change(s)", imported.Changes)
+		if imported.Preformatted {
+			detail = "kept as written"
+		}
+		if imported.Date != "" {
+			detail += ", " + imported.Date
+		}
+		fmt.Println(ui.KeyValue(imported.Version, detail))
+	}
+	for _, version := range output.Skipped {
+		fmt.Println(ui.Dimmed(fmt.Sprintf("%s is already in history; skipped", version)))
+	}
+	if opts.DryRun {
+		fmt.Println(ui.InfoMessage(fmt.Sprintf("Would import %d version(s) of %s from %s", len(output.Imported), output.Package, output.Source)))
+	} else {
+		fmt.Println(ui.SuccessMessage(fmt.Sprintf("Imported %d version(s) of %s from %s", len(output.Imported), output.Package, output.Source)))
+	}
+	fmt.Println()
+	return nil
+}
+
+// importChangelog records the releases of the changelog at path as history
+// entries of a package, skipping versions history already holds
+func importChangelog(projectPath, historyPath, packageName, path string, dryRun bool) (ImportChangelogOutput, error) {
+	source := projectRelPath(projectPath, path)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return ImportChangelogOutput{}, fmt.Errorf("failed to read changelog: %w", err)
+	}
+	releases, err := keepachangelog.Parse(content)
+	if err != nil {
+		return ImportChangelogOutput{}, fmt.Errorf("failed to read %s: %w", source, err)
+	}
+	entries := changelogHistoryEntries(packageName, releases)
+
+	var imported []history.Entry
+	if dryRun {
+		recorded, err := history.ReadHistory(historyPath)
+		if err != nil && !os.IsNotExist(err) {
+			return ImportChangelogOutput{}, fmt.Errorf("failed to read history: %w", err)
+		}
+		for _, entry := range entries {
+			if len(history.FilterByVersion(history.FilterByPackage(recorded, packageName), entry.Version)) == 0 {
+				imported = append(imported, entry)
+			}
+		}
+	} else {
+		if err := history.EnsureHistory(historyPath); err != nil {
+			return ImportChangelogOutput{}, fmt.Errorf("failed to create history: %w", err)
+		}
+		if imported, err = history.ImportEntries(historyPath, entries); err != nil {
+			return ImportChangelogOutput{}, fmt.Errorf("failed to record imported versions: %w", err)
+		}
+	}
+
+	output := ImportChangelogOutput{Package: packageName, Source: source, DryRun: dryRun, Imported: []ImportedRelease{}}
+	wrote := make(map[string]bool, len(imported))
+	for _, entry := range imported {
+		wrote[entry.Version] = true
+	}
+	// Report newest first, as the changelog lists them
+	for _, r := range releases {
+		if !wrote[r.Version] {
+			output.Skipped = append(output.Skipped, r.Version)
+			continue
+		}
+		imported := ImportedRelease{Version: r.Version, Changes: len(r.Changes), Preformatted: r.Body != ""}
+		if !r.Date.IsZero() {
+			imported.Date = r.Date.Format("2006-01-02")
+		}
+		output.Imported = append(output.Imported, imported)
+	}
+	return output, nil
+}
+
+// changelogHistoryEntries converts changelog releases, listed newest first,
+// to history entries, oldest first. A release is timestamped at its date;
+// one without a date, or dated no later than the release below it, is
+// placed a second after that release so history keeps the changelog's order.
+func changelogHistoryEntries(packageName string, releases []keepachangelog.Release) []history.Entry {
+	// Undated releases below every dated one come just before the oldest date
+	previous := time.Now().UTC().Truncate(time.Second)
+	for _, r := range releases {
+		if !r.Date.IsZero() {
+			previous = r.Date
+		}
+	}
+	previous = previous.Add(-time.Duration(len(releases)) * time.Second)
+
+	entries := make([]history.Entry, 0, len(releases))
+	for i := len(releases) - 1; i >= 0; i-- {
+		r := releases[i]
+		timestamp := r.Date
+		if !timestamp.After(previous) {
+			timestamp = previous.Add(time.Second)
+		}
+		previous = timestamp
+
+		entry := history.Entry{
+			Version:      r.Version,
+			Package:      packageName,
+			Timestamp:    timestamp,
+			Consignments: make([]history.Consignment, len(r.Changes)),
+			Yanked:       r.Yanked,
+			Imported:     true,
+			Preformatted: r.Body,
+		}
+		for j, change := range r.Changes {
+			entry.Consignments[j] = history.Consignment{
+				ID:         fmt.Sprintf("imported-%s-%d", r.Version, j+1),
+				Summary:    change.Summary,
+				ChangeType: string(change.Type),
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
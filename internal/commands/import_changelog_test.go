@@ -0,0 +1,157 @@
+package commands
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/history"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// existingChangelog is a CHANGELOG.md kept by hand before shipyard
+const existingChangelog = `# Changelog
+
+## [Unreleased]
+
+- Work in progress
+
+## [1.0.0] - 2024-03-01
+
+### Added
+
+- Export to CSV
+
+### Fixed
+
+- Crash on empty input
+
+## 0.9.0 - 2024-01-15
+
+First public preview. See the announcement for details.
+
+[1.0.0]: https://example.com/compare/v0.9.0...v1.0.0
+`
+
+func TestImportChangelog(t *testing.T) {
+	tempDir := setupVersionTestRepo(t)
+	initGitRepo(t, tempDir)
+	changelogPath := filepath.Join(tempDir, "test-package", "CHANGELOG.md")
+	require.NoError(t, os.WriteFile(changelogPath, []byte(existingChangelog), 0644))
+	historyPath := filepath.Join(tempDir, ".shipyard", "history.json")
+
+	runJSON := func(opts *ImportChangelogOptions) ImportChangelogOutput {
+		t.Helper()
+		opts.JSON = true
+		var err error
+		output := captureOutput(func() { err = runImportChangelog(tempDir, opts) })
+		require.NoError(t, err)
+		var result ImportChangelogOutput
+		require.NoError(t, json.Unmarshal([]byte(output), &result))
+		return result
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		result := runJSON(&ImportChangelogOptions{DryRun: true})
+		assert.Equal(t, "test-package", result.Package)
+		assert.Equal(t, "test-package/CHANGELOG.md", result.Source)
+		assert.Equal(t, []ImportedRelease{
+			{Version: "1.0.0", Date: "2024-03-01", Changes: 2},
+			{Version: "0.9.0", Date: "2024-01-15", Preformatted: true},
+		}, result.Imported)
+
+		entries, err := history.ReadHistory(historyPath)
+		require.NoError(t, err)
+		assert.Empty(t, entries, "a dry run records nothing")
+	})
+
+	t.Run("records imported entries", func(t *testing.T) {
+		result := runJSON(&ImportChangelogOptions{})
+		require.Len(t, result.Imported, 2)
+
+		entries, err := history.ReadHistory(historyPath)
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.Equal(t, "0.9.0", entries[0].Version)
+		assert.True(t, entries[0].Imported)
+		assert.Equal(t, "First public preview. See the announcement for details.", entries[0].Preformatted)
+		assert.Empty(t, entries[0].Consignments)
+
+		assert.Equal(t, "1.0.0", entries[1].Version)
+		assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), entries[1].Timestamp)
+		assert.Equal(t, []history.Consignment{
+			{ID: "imported-1.0.0-1", Summary: "Export to CSV", ChangeType: "minor"},
+			{ID: "imported-1.0.0-2", Summary: "Crash on empty input", ChangeType: "patch"},
+		}, entries[1].Consignments)
+	})
+
+	t.Run("importing again skips recorded versions", func(t *testing.T) {
+		result := runJSON(&ImportChangelogOptions{})
+		assert.Empty(t, result.Imported)
+		assert.Equal(t, []string{"1.0.0", "0.9.0"}, result.Skipped)
+	})
+
+	t.Run("regenerated changelog keeps imported releases", func(t *testing.T) {
+		createTestConsignmentForVersion(t, filepath.Join(tempDir, ".shipyard", "consignments"), "c1", []string{"test-package"}, "minor", "Add JSON export")
+		captureOutput(func() {
+			require.NoError(t, runVersionInDir(tempDir, &VersionCommandOptions{NoCommit: true, NoTag: true, NoPublish: true}))
+		})
+
+		content, err := os.ReadFile(changelogPath)
+		require.NoError(t, err)
+		changelog := string(content)
+		newRelease := strings.Index(changelog, "## [1.1.0]")
+		imported := strings.Index(changelog, "## [1.0.0] - 2024-03-01")
+		preview := strings.Index(changelog, "## [0.9.0] - 2024-01-15\n")
+		require.NotEqual(t, -1, newRelease)
+		require.NotEqual(t, -1, imported)
+		require.NotEqual(t, -1, preview)
+		assert.Less(t, newRelease, imported)
+		assert.Less(t, imported, preview)
+		assert.Contains(t, changelog, "- Export to CSV")
+		assert.Contains(t, changelog, "- Crash on empty input")
+		assert.Contains(t, changelog, "## [0.9.0] - 2024-01-15\n**Package**: test-package\n\nFirst public preview. See the announcement for details.")
+	})
+}
+
+func TestImportChangelog_Errors(t *testing.T) {
+	tempDir := setupVersionTestRepo(t)
+
+	err := runImportChangelog(tempDir, &ImportChangelogOptions{})
+	assert.ErrorContains(t, err, "failed to read changelog")
+
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "NOTES.md"), []byte("# Notes\n\nNothing here.\n"), 0644))
+	err = runImportChangelog(tempDir, &ImportChangelogOptions{File: "NOTES.md"})
+	assert.ErrorContains(t, err, "failed to read NOTES.md: no versions found")
+
+	err = runImportChangelog(tempDir, &ImportChangelogOptions{Package: "missing"})
+	assert.ErrorContains(t, err, "package missing is not in the config")
+}
+
+func TestInitCommand_ImportChangelog(t *testing.T) {
+	tempDir := t.TempDir()
+	initGitRepo(t, tempDir)
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "go.mod"), []byte("module example.com/app\n\ngo 1.25\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "CHANGELOG.md"), []byte(existingChangelog), 0644))
+	historyPath := filepath.Join(tempDir, ".shipyard", "history.json")
+
+	captureOutput(func() {
+		require.NoError(t, runInit(tempDir, InitOptions{Yes: true}))
+	})
+	entries, err := history.ReadHistory(historyPath)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "--yes alone leaves the changelog alone")
+
+	captureOutput(func() {
+		require.NoError(t, runInit(tempDir, InitOptions{Yes: true, Force: true, ImportChangelog: true}))
+	})
+	entries, err = history.ReadHistory(historyPath)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "0.9.0", entries[0].Version)
+	assert.Equal(t, "1.0.0", entries[1].Version)
+}
@@ -0,0 +1,282 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/changesets"
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/consignment"
+	"github.com/NatoNathan/shipyard/internal/git"
+	"github.com/NatoNathan/shipyard/internal/release"
+	"github.com/NatoNathan/shipyard/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// ImportChangesetsOptions holds options for the import changesets command
+type ImportChangesetsOptions struct {
+	Dir    string // Changesets directory; .changeset in the project by default
+	Remove bool   // Delete imported changeset files
+	Strict bool   // Fail on packages missing from the config instead of skipping them
+	JSON   bool
+	Quiet  bool
+}
+
+// ImportChangesetsOutput is the JSON output structure for the import changesets command
+type ImportChangesetsOutput struct {
+	Imported []ImportedChangeset `json:"imported"`
+	Skipped  []SkippedChangeset  `json:"skipped,omitempty"`
+}
+
+// ImportedChangeset records the consignments created from one changeset
+type ImportedChangeset struct {
+	Source          string   `json:"source"`
+	Consignments    []string `json:"consignments"`
+	SkippedPackages []string `json:"skippedPackages,omitempty"`
+	Removed         bool     `json:"removed,omitempty"`
+}
+
+// SkippedChangeset records a changeset that produced no consignments
+type SkippedChangeset struct {
+	Source string `json:"source"`
+	Reason string `json:"reason"`
+}
+
+// NewImportChangesetsCommand creates the import changesets command
+func NewImportChangesetsCommand() *cobra.Command {
+	opts := &ImportChangesetsOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "changesets [dir]",
+		Short: "Take on cargo from a changesets manifest",
+		Long: `Convert pending changesets (.changeset/*.md) into consignments.
+
+Each changeset becomes one consignment per bump type it uses, covering the
+packages released with that type, with the changeset's summary. The
+consignment is dated when the changeset was first committed, or when the
+file was last modified if it was never committed.
+
+Packages the config does not define are reported and skipped; --strict fails
+instead, before anything is written. Packages marked "none" are ignored.
+Changeset files are kept unless --remove is given.`,
+		Example: `  # Import .changeset/*.md
+  shipyard import changesets
+
+  # Import from another directory and delete the imported files
+  shipyard import changesets packages/web/.changeset --remove
+
+  # Fail if a changeset names a package shipyard does not know
+  shipyard import changesets --strict`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			globalFlags := GetGlobalFlags(cmd)
+			if len(args) == 1 {
+				// Named from the working directory, which may be below the project root
+				path, err := filepath.Abs(args[0])
+				if err != nil {
+					return fmt.Errorf("failed to resolve changesets directory %s: %w", args[0], err)
+				}
+				opts.Dir = path
+			}
+			opts.JSON = globalFlags.JSON
+			opts.Quiet = globalFlags.Quiet
+
+			cwd, err := projectRoot()
+			if err != nil {
+				return err
+			}
+			return runImportChangesets(cwd, opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.Remove, "remove", false, "Delete changeset files once imported")
+	cmd.Flags().BoolVar(&opts.Strict, "strict", false, "Fail when a changeset names a package missing from the config")
+
+	return cmd
+}
+
+func runImportChangesets(projectPath string, opts *ImportChangesetsOptions) (err error) {
+	cfg, err := config.LoadFromDir(projectPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	dir := opts.Dir
+	if dir == "" {
+		dir = changesets.DefaultDir
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(projectPath, dir)
+	}
+	pending, err := changesets.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	known := func(pkg string) bool {
+		_, ok := cfg.GetPackage(pkg)
+		return ok
+	}
+	unknown := make(map[string][]string)
+	for _, cs := range pending {
+		for _, r := range cs.Releases {
+			if !known(r.Package) {
+				unknown[cs.Name] = append(unknown[cs.Name], r.Package)
+			}
+		}
+	}
+	if opts.Strict && len(unknown) > 0 {
+		var problems []string
+		for _, cs := range pending {
+			if pkgs, ok := unknown[cs.Name]; ok {
+				problems = append(problems, fmt.Sprintf("%s: %s", cs.Name, strings.Join(pkgs, ", ")))
+			}
+		}
+		return fmt.Errorf("changesets name packages not in the config (%s)", strings.Join(problems, "; "))
+	}
+
+	consignmentsPath := cfg.Consignments.Path
+	if consignmentsPath == "" {
+		consignmentsPath = ".shipyard/consignments"
+	}
+	consignmentsDir := filepath.Join(projectPath, consignmentsPath)
+	isRepo, _ := git.IsRepository(projectPath)
+
+	// Write every consignment before removing any changeset, and restore
+	// everything if any step fails
+	tx := release.NewFileTransaction()
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				err = fmt.Errorf("%w; additionally failed to roll back: %v", err, rollbackErr)
+			}
+		}
+	}()
+
+	output := ImportChangesetsOutput{Imported: []ImportedChangeset{}}
+	var importedPaths, warnings []string
+	ids := consignment.NewIDGenerator(cfg.Consignments.IDStyle, consignmentsDir)
+	sequence, err := consignment.NextSequence(consignmentsDir)
+	if err != nil {
+		return fmt.Errorf("failed to number consignments: %w", err)
+	}
+	for _, cs := range pending {
+		source := projectRelPath(projectPath, cs.Path)
+		for _, pkg := range unknown[cs.Name] {
+			warnings = append(warnings, fmt.Sprintf("%s: package %s is not in the config; skipping it", source, pkg))
+		}
+
+		groups := cs.Groups(known)
+		if len(groups) == 0 {
+			reason := "releases no packages"
+			if len(unknown[cs.Name]) > 0 {
+				reason = "releases no configured packages"
+			}
+			output.Skipped = append(output.Skipped, SkippedChangeset{Source: source, Reason: reason})
+			warnings = append(warnings, fmt.Sprintf("%s %s; not imported", source, reason))
+			continue
+		}
+		if strings.TrimSpace(cs.Summary) == "" {
+			return fmt.Errorf("changeset %s has no summary", source)
+		}
+
+		timestamp, err := changesetTimestamp(projectPath, cs.Path, isRepo)
+		if err != nil {
+			return err
+		}
+
+		imported := ImportedChangeset{Source: source, SkippedPackages: unknown[cs.Name]}
+		for _, group := range groups {
+			id, err := ids.Generate(timestamp, cs.Summary)
+			if err != nil {
+				return fmt.Errorf("failed to generate consignment ID: %w", err)
+			}
+			cons := &consignment.Consignment{
+				ID:         id,
+				Timestamp:  timestamp,
+				Sequence:   sequence,
+				Packages:   group.Packages,
+				ChangeType: group.Type,
+				Summary:    cs.Summary,
+			}
+			if err := tx.Backup(filepath.Join(consignmentsDir, id+".md")); err != nil {
+				return err
+			}
+			if err := consignment.WriteConsignment(cons, consignmentsDir); err != nil {
+				return fmt.Errorf("failed to write consignment for %s: %w", source, err)
+			}
+			imported.Consignments = append(imported.Consignments, id)
+			sequence++
+		}
+		output.Imported = append(output.Imported, imported)
+		importedPaths = append(importedPaths, cs.Path)
+	}
+
+	if opts.Remove {
+		for i, path := range importedPaths {
+			if err := tx.Backup(path); err != nil {
+				return err
+			}
+			if err := os.Remove(path); err != nil {
+				return fmt.Errorf("failed to remove changeset %s: %w", output.Imported[i].Source, err)
+			}
+			output.Imported[i].Removed = true
+		}
+	}
+
+	for _, warning := range warnings {
+		fmt.Fprintln(os.Stderr, ui.WarningMessage(warning))
+	}
+
+	if opts.JSON {
+		return PrintJSON(os.Stdout, output)
+	}
+	if opts.Quiet {
+		return nil
+	}
+
+	fmt.Println()
+	created := 0
+	for _, imported := range output.Imported {
+		created += len(imported.Consignments)
+		fmt.Println(ui.KeyValue(imported.Source, strings.Join(imported.Consignments, ", ")))
+	}
+	fmt.Println(ui.SuccessMessage(fmt.Sprintf("Imported %d changeset(s) as %d consignment(s)", len(output.Imported), created)))
+	if opts.Remove && len(output.Imported) > 0 {
+		fmt.Println(ui.Dimmed("Imported changeset files were removed"))
+	}
+	fmt.Println()
+	return nil
+}
+
+// changesetTimestamp dates a changeset by the commit that added it, falling
+// back to the file's modification time when it was never committed
+func changesetTimestamp(projectPath, path string, isRepo bool) (time.Time, error) {
+	if isRepo {
+		added, ok, err := git.FileAddedAt(projectPath, projectRelPath(projectPath, path))
+		if err != nil {
+			return time.Time{}, err
+		}
+		if ok {
+			return added.UTC(), nil
+		}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read changeset: %w", err)
+	}
+	return info.ModTime().UTC().Truncate(time.Second), nil
+}
+
+// projectRelPath returns path relative to the project root with forward
+// slashes, or path itself when it lies outside the project
+func projectRelPath(projectPath, path string) string {
+	rel, err := filepath.Rel(projectPath, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,158 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/consignment"
+	"github.com/NatoNathan/shipyard/pkg/types"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupChangesetsRepo creates a project with @acme/ui and @acme/api packages
+// and commits changesets for them, plus one for a package shipyard does not know
+func setupChangesetsRepo(t *testing.T) (string, time.Time) {
+	t.Helper()
+	tempDir := t.TempDir()
+	files := map[string]string{
+		".shipyard/shipyard.yaml": `packages:
+  - name: "@acme/ui"
+    path: ./ui
+    ecosystem: npm
+  - name: "@acme/api"
+    path: ./api
+    ecosystem: npm
+consignments:
+  path: .shipyard/consignments
+history:
+  path: .shipyard/history.json
+`,
+		"ui/package.json":  `{"name": "@acme/ui", "version": "1.0.0"}`,
+		"api/package.json": `{"name": "@acme/api", "version": "0.3.0"}`,
+		".changeset/wild-ties-sing.md": `---
+"@acme/ui": major
+"@acme/api": patch
+---
+
+Removed the deprecated ` + "`kind`" + ` prop
+`,
+		".changeset/quiet-owls-rest.md": `---
+"@acme/api": minor
+"@acme/legacy": patch
+---
+
+Add pagination to list endpoints
+`,
+		".changeset/tired-cats-nap.md": `---
+"@acme/legacy": patch
+---
+
+Fix legacy export
+`,
+		".changeset/README.md": "# Changesets\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(tempDir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	repo, err := gogit.PlainInit(tempDir, false)
+	require.NoError(t, err)
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+	_, err = wt.Add(".")
+	require.NoError(t, err)
+	committed := time.Date(2026, 2, 3, 14, 5, 6, 0, time.UTC)
+	_, err = wt.Commit("Add changesets", &gogit.CommitOptions{
+		Author: &object.Signature{Name: "Test", Email: "test@example.com", When: committed},
+	})
+	require.NoError(t, err)
+	return tempDir, committed
+}
+
+func TestImportChangesets(t *testing.T) {
+	tempDir, committed := setupChangesetsRepo(t)
+
+	captureOutput(func() {
+		require.NoError(t, runImportChangesets(tempDir, &ImportChangesetsOptions{}))
+	})
+
+	imported, err := consignment.ReadAllConsignments(filepath.Join(tempDir, ".shipyard", "consignments"))
+	require.NoError(t, err)
+	require.Len(t, imported, 3)
+
+	byType := make(map[types.ChangeType]*consignment.Consignment)
+	for _, c := range imported {
+		byType[c.ChangeType] = c
+		assert.True(t, committed.Equal(c.Timestamp), "timestamp comes from the commit that added the changeset")
+	}
+	require.Contains(t, byType, types.ChangeTypeMajor)
+	assert.Equal(t, []string{"@acme/ui"}, byType[types.ChangeTypeMajor].Packages)
+	assert.Equal(t, "Removed the deprecated `kind` prop", byType[types.ChangeTypeMajor].Summary)
+	assert.Equal(t, []string{"@acme/api"}, byType[types.ChangeTypePatch].Packages)
+	assert.Equal(t, []string{"@acme/api"}, byType[types.ChangeTypeMinor].Packages, "unknown @acme/legacy is skipped")
+	assert.Equal(t, "Add pagination to list endpoints", byType[types.ChangeTypeMinor].Summary)
+
+	assert.FileExists(t, filepath.Join(tempDir, ".changeset", "wild-ties-sing.md"), "sources are kept without --remove")
+}
+
+func TestImportChangesets_Remove(t *testing.T) {
+	tempDir, _ := setupChangesetsRepo(t)
+
+	captureOutput(func() {
+		require.NoError(t, runImportChangesets(tempDir, &ImportChangesetsOptions{Remove: true}))
+	})
+
+	assert.NoFileExists(t, filepath.Join(tempDir, ".changeset", "wild-ties-sing.md"))
+	assert.NoFileExists(t, filepath.Join(tempDir, ".changeset", "quiet-owls-rest.md"))
+	assert.FileExists(t, filepath.Join(tempDir, ".changeset", "tired-cats-nap.md"), "a changeset that was not imported is kept")
+	assert.FileExists(t, filepath.Join(tempDir, ".changeset", "README.md"))
+}
+
+func TestImportChangesets_Strict(t *testing.T) {
+	tempDir, _ := setupChangesetsRepo(t)
+
+	err := runImportChangesets(tempDir, &ImportChangesetsOptions{Strict: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "quiet-owls-rest: @acme/legacy")
+	assert.Contains(t, err.Error(), "tired-cats-nap: @acme/legacy")
+
+	assert.NoDirExists(t, filepath.Join(tempDir, ".shipyard", "consignments"), "nothing is written")
+}
+
+func TestImportChangesets_UncommittedUsesModTime(t *testing.T) {
+	tempDir, _ := setupChangesetsRepo(t)
+	path := filepath.Join(tempDir, ".changeset", "fresh-bees-hum.md")
+	require.NoError(t, os.WriteFile(path, []byte("---\n\"@acme/ui\": minor\n---\n\nAdd tooltip\n"), 0644))
+	modified := time.Date(2026, 4, 1, 8, 0, 0, 0, time.UTC)
+	require.NoError(t, os.Chtimes(path, modified, modified))
+
+	captureOutput(func() {
+		require.NoError(t, runImportChangesets(tempDir, &ImportChangesetsOptions{}))
+	})
+
+	imported, err := consignment.ReadAllConsignments(filepath.Join(tempDir, ".shipyard", "consignments"))
+	require.NoError(t, err)
+	var found bool
+	for _, c := range imported {
+		if c.Summary == "Add tooltip" {
+			found = true
+			assert.True(t, modified.Equal(c.Timestamp), "got %s", c.Timestamp)
+		}
+	}
+	assert.True(t, found)
+}
+
+func TestImportChangesets_MissingDirectory(t *testing.T) {
+	tempDir, _ := setupChangesetsRepo(t)
+
+	err := runImportChangesets(tempDir, &ImportChangesetsOptions{Dir: "nowhere"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read changesets directory")
+}
@@ -4,14 +4,19 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/NatoNathan/shipyard/internal/config"
 	"github.com/NatoNathan/shipyard/internal/detect"
 	shipyarderrors "github.com/NatoNathan/shipyard/internal/errors"
 	"github.com/NatoNathan/shipyard/internal/fileutil"
 	"github.com/NatoNathan/shipyard/internal/git"
+	"github.com/NatoNathan/shipyard/internal/keepachangelog"
 	"github.com/NatoNathan/shipyard/internal/logger"
 	"github.com/NatoNathan/shipyard/internal/prompt"
+	"github.com/NatoNathan/shipyard/internal/release"
+	"github.com/NatoNathan/shipyard/internal/template"
 	"github.com/NatoNathan/shipyard/internal/ui"
 	"github.com/spf13/cobra"
 )
@@ -23,16 +28,33 @@ type InitOptions struct {
 	Yes    bool // Skip prompts and use defaults
 	JSON   bool // Output in JSON format
 	Quiet  bool // Suppress output
+
+	Type              string   // --type: single or monorepo (with --yes)
+	Packages          []string // --package: name=path:ecosystem (with --yes)
+	Auto              bool     // --auto: Take detected packages without confirmation (with --yes)
+	Repo              string   // --repo: GitHub repository as owner/name
+	ChangelogTemplate string   // --changelog-template: Changelog template source
+	SkipGitDetection  bool     // --skip-git-detection: Do not require a git repository
+	ScanDepth         int      // --scan-depth: Deepest directory level scanned for packages; 0 scans all
+	EcosystemPriority []string // --ecosystem-priority: Ecosystem kept when a directory has several manifests (with --yes)
+	ImportChangelog   bool     // --import-changelog: Record existing CHANGELOG.md releases in history without asking
 }
 
+// Repository types accepted by --type
+const (
+	initTypeSingle   = "single"
+	initTypeMonorepo = "monorepo"
+)
+
 // NewInitCommand creates the init command
 func NewInitCommand() *cobra.Command {
 	var force bool
 	var remote string
 	var yes bool
+	opts := InitOptions{}
 
 	cmd := &cobra.Command{
-		Use:                   "init [-f] [-y] [-r url]",
+		Use:                   "init [-f] [-y] [-r url] [--type type] [--package name=path:ecosystem]... [--auto]",
 		DisableFlagsInUseLine: true,
 		Aliases:               []string{"setup"},
 		Short:                 "Set sail - prepare your repository",
@@ -41,38 +63,56 @@ with cargo manifests, navigation charts, and the captain's log.
 
 In interactive mode, you'll configure your fleet (packages) and choose between
 sailing solo or commanding a flotilla (monorepo). Use --yes to set sail with
-default configurations.`,
+default configurations, or --yes with --package, --type and --auto to chart
+the fleet entirely from flags with no prompts.`,
 		Example: `  # Interactive setup
   shipyard init
 
   # Accept all defaults
   shipyard init --yes
 
+  # Bootstrap a monorepo without prompts
+  shipyard init --yes --type monorepo --package core=./core:go --package web=./web:npm
+
+  # Take every detected package and link releases to GitHub
+  shipyard init --yes --auto --repo acme/widgets
+
+  # Keep the releases of an existing CHANGELOG.md
+  shipyard init --yes --import-changelog
+
   # Force re-initialization
   shipyard init --force`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Get current working directory
-			cwd, err := os.Getwd()
+			cwd, err := initProjectDir()
 			if err != nil {
-				return fmt.Errorf("failed to get current directory: %w", err)
+				return err
 			}
 
 			// Extract global flags
 			globalFlags := GetGlobalFlags(cmd)
 
-			return runInit(cwd, InitOptions{
-				Force:  force,
-				Remote: remote,
-				Yes:    yes,
-				JSON:   globalFlags.JSON,
-				Quiet:  globalFlags.Quiet,
-			})
+			opts.Force = force
+			opts.Remote = remote
+			opts.Yes = yes
+			opts.JSON = globalFlags.JSON
+			opts.Quiet = globalFlags.Quiet
+			return runInit(cwd, opts)
 		},
 	}
 
 	cmd.Flags().BoolVarP(&force, "force", "f", false, "force re-initialization if already initialized")
 	cmd.Flags().StringVarP(&remote, "remote", "r", "", "remote configuration URL to extend from")
 	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "skip all prompts and accept defaults")
+	cmd.Flags().StringVar(&opts.Type, "type", "", "repository type with --yes: single or monorepo")
+	cmd.Flags().StringArrayVar(&opts.Packages, "package", nil, "package as name=path:ecosystem with --yes (repeatable)")
+	cmd.Flags().BoolVar(&opts.Auto, "auto", false, "take detected packages without confirmation (with --yes)")
+	cmd.Flags().StringVar(&opts.Repo, "repo", "", "GitHub repository as owner/name")
+	cmd.Flags().StringVar(&opts.ChangelogTemplate, "changelog-template", "", "changelog template source (default builtin:default)")
+	cmd.Flags().BoolVar(&opts.SkipGitDetection, "skip-git-detection", false, "initialize without requiring a git repository")
+	cmd.Flags().IntVar(&opts.ScanDepth, "scan-depth", 0, "deepest directory level scanned for packages (0 scans every level)")
+	cmd.Flags().BoolVar(&opts.ImportChangelog, "import-changelog", false, "record the releases of existing CHANGELOG.md files in history without asking")
+	cmd.Flags().StringSliceVar(&opts.EcosystemPriority, "ecosystem-priority", nil, "ecosystems in order of preference when a directory has several manifests, e.g. helm,npm (with --yes)")
 
 	return cmd
 }
@@ -82,13 +122,15 @@ func runInit(projectPath string, options InitOptions) error {
 	log := logger.Get()
 
 	// Step 1: Verify git repository
-	log.Info("Verifying git repository...")
-	isGitRepo, err := git.IsRepository(projectPath)
-	if err != nil {
-		return shipyarderrors.NewGitError("failed to check git repository", err)
-	}
-	if !isGitRepo {
-		return shipyarderrors.NewGitError("not a git repository", nil)
+	if !options.SkipGitDetection {
+		log.Debug("Verifying git repository in %s", projectPath)
+		isGitRepo, err := git.IsRepository(projectPath)
+		if err != nil {
+			return shipyarderrors.NewGitError("failed to check git repository", err)
+		}
+		if !isGitRepo {
+			return shipyarderrors.NewGitError("not a git repository", nil)
+		}
 	}
 
 	// Step 2: Check for existing configuration
@@ -99,19 +141,20 @@ func runInit(projectPath string, options InitOptions) error {
 		return shipyarderrors.NewConfigError("shipyard already initialized (use --force to reinitialize)", nil)
 	}
 
-	log.Info("Initializing Shipyard...")
-
-	// Step 3: Create directory structure
-	if err := initializeDirectories(projectPath); err != nil {
-		return fmt.Errorf("failed to create directories: %w", err)
-	}
+	log.Debug("Initializing Shipyard in %s", shipyardDir)
 
-	// Step 4: Generate configuration
+	// Step 3: Generate configuration, before anything is written so invalid
+	// flags leave the repository untouched
 	cfg, err := generateConfiguration(projectPath, options)
 	if err != nil {
 		return fmt.Errorf("failed to generate configuration: %w", err)
 	}
 
+	// Step 4: Create directory structure
+	if err := initializeDirectories(projectPath); err != nil {
+		return fmt.Errorf("failed to create directories: %w", err)
+	}
+
 	// Step 5: Write configuration file
 	if err := config.WriteConfig(cfg, configPath); err != nil {
 		return shipyarderrors.NewConfigError("failed to write configuration", err)
@@ -123,15 +166,26 @@ func runInit(projectPath string, options InitOptions) error {
 		return fmt.Errorf("failed to initialize history file: %w", err)
 	}
 
+	// Step 7: Keep the releases of changelogs written before shipyard
+	imports, err := importExistingChangelogs(projectPath, cfg, historyPath, options)
+	if err != nil {
+		return err
+	}
+	importedVersions := 0
+	for _, imported := range imports {
+		importedVersions += len(imported.Imported)
+	}
+
 	// Output based on format flags
 	if options.JSON {
 		// JSON output
 		jsonData := map[string]interface{}{
-			"success":         true,
-			"configPath":      configPath,
-			"consignmentsDir": filepath.Join(shipyardDir, "consignments"),
-			"historyFile":     historyPath,
-			"initialized":     true,
+			"success":          true,
+			"configPath":       configPath,
+			"consignmentsDir":  filepath.Join(shipyardDir, "consignments"),
+			"historyFile":      historyPath,
+			"initialized":      true,
+			"importedVersions": importedVersions,
 		}
 		return PrintJSON(os.Stdout, jsonData)
 	}
@@ -144,12 +198,24 @@ func runInit(projectPath string, options InitOptions) error {
 		fmt.Println(ui.KeyValue("Configuration", configPath))
 		fmt.Println(ui.KeyValue("Consignments directory", filepath.Join(shipyardDir, "consignments")))
 		fmt.Println(ui.KeyValue("History file", historyPath))
+		for _, imported := range imports {
+			fmt.Println(ui.KeyValue("Imported changelog", fmt.Sprintf("%s (%d versions of %s)", imported.Source, len(imported.Imported), imported.Package)))
+		}
 		fmt.Println()
 	}
 
 	return nil
 }
 
+// initStatus returns where init prints what it detected and configured;
+// --json and --quiet runs print nothing besides their result
+func initStatus(options InitOptions) *ui.Status {
+	if options.JSON || options.Quiet {
+		return ui.NewStatus(nil)
+	}
+	return ui.NewStatus(os.Stdout)
+}
+
 // initializeDirectories creates the required directory structure
 func initializeDirectories(projectPath string) error {
 	shipyardDir := filepath.Join(projectPath, ".shipyard")
@@ -171,9 +237,12 @@ func initializeDirectories(projectPath string) error {
 // generateConfiguration creates a configuration based on detected packages
 func generateConfiguration(projectPath string, options InitOptions) (*config.Config, error) {
 	log := logger.Get()
+	status := initStatus(options)
 
 	cfg := &config.Config{
-		Packages: []config.Package{},
+		SchemaVersion:      config.CurrentSchemaVersion,
+		MinShipyardVersion: config.ToolVersionRequirement(),
+		Packages:           []config.Package{},
 		Templates: config.TemplateConfig{
 			Changelog: &config.TemplateSource{
 				Source: "builtin:default",
@@ -198,25 +267,59 @@ func generateConfiguration(projectPath string, options InitOptions) (*config.Con
 		cfg.Extends = []config.RemoteConfig{config.NewRemoteConfig(options.Remote)}
 	}
 
+	// Flags are checked together so one run reports every problem
+	problems := applyInitFlags(cfg, options)
+	flagPackages, packageProblems := parseInitPackages(options.Packages)
+	problems = append(problems, packageProblems...)
+	explicit := options.Type != "" || len(options.Packages) > 0 || options.Auto
+	if explicit && !options.Yes {
+		problems = append(problems, "--type, --package and --auto require --yes")
+	}
+
 	// Auto-detect packages
 	log.Debug("Detecting packages...")
-	detectedPackages, err := detect.DetectPackages(projectPath)
+	candidates, err := detect.DetectCandidates(projectPath, detect.ScanOptions{MaxDepth: options.ScanDepth})
 	if err != nil {
 		return nil, fmt.Errorf("failed to detect packages: %w", err)
 	}
 
 	// Interactive mode (default) - prompt for repo type and packages
-	if !options.Yes {
-		return generateInteractiveConfig(cfg, detectedPackages, projectPath)
+	if !options.Yes && len(problems) == 0 {
+		return generateInteractiveConfig(cfg, candidates, projectPath, status)
+	}
+
+	// Without prompts, the priority list settles directories holding several manifests
+	detected := detect.ResolveConflicts(candidates, options.EcosystemPriority)
+	detectedPackages := make([]config.Package, len(detected))
+	for i, c := range detected {
+		detectedPackages[i] = c.Package
+	}
+
+	// Flag-driven mode - packages come from --package and, with --auto,
+	// detection; nothing is filled in by default
+	if explicit && options.Yes {
+		packages, packageProblems := resolveInitPackages(options, flagPackages, detectedPackages)
+		problems = append(problems, packageProblems...)
+		cfg.Packages = packages
+	}
+	if len(problems) > 0 {
+		return nil, initFlagsError(problems)
+	}
+	for _, c := range detected {
+		status.Info("Detected %s (%s) from %s", c.Package.Name, c.Package.Ecosystem, c.Manifest)
+	}
+	if explicit {
+		status.Info("Configured %d package(s)", len(cfg.Packages))
+		return cfg, nil
 	}
 
 	// Non-interactive mode (--yes flag) - use auto-detection
 	if len(detectedPackages) > 0 {
-		log.Info("Detected %d package(s)", len(detectedPackages))
+		status.Info("Detected %d package(s)", len(detectedPackages))
 		cfg.Packages = detectedPackages
 	} else {
 		// No packages detected, create a default one
-		log.Info("No packages detected, creating default package")
+		status.Info("No packages detected, creating default package")
 		defaultPkg := config.Package{
 			Name:      "default",
 			Path:      "./",
@@ -228,12 +331,150 @@ func generateConfiguration(projectPath string, options InitOptions) (*config.Con
 	return cfg, nil
 }
 
-// generateInteractiveConfig prompts user for all configuration options
-func generateInteractiveConfig(cfg *config.Config, detectedPackages []config.Package, projectPath string) (*config.Config, error) {
-	log := logger.Get()
+// applyInitFlags applies --repo and --changelog-template to cfg and returns
+// the problems with them and with the other option flags
+func applyInitFlags(cfg *config.Config, options InitOptions) []string {
+	var problems []string
+	if options.Type != "" && options.Type != initTypeSingle && options.Type != initTypeMonorepo {
+		problems = append(problems, fmt.Sprintf("--type %q must be single or monorepo", options.Type))
+	}
+	if options.ScanDepth < 0 {
+		problems = append(problems, "--scan-depth must not be negative")
+	}
+	for _, ecosystem := range options.EcosystemPriority {
+		if !slices.Contains(config.Ecosystems(), ecosystem) {
+			problems = append(problems, fmt.Sprintf("--ecosystem-priority has unknown ecosystem %q (expected one of %s)",
+				ecosystem, strings.Join(config.Ecosystems(), ", ")))
+		}
+	}
+	if options.Repo != "" {
+		owner, repo, ok := strings.Cut(options.Repo, "/")
+		if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+			problems = append(problems, fmt.Sprintf("--repo %q must be owner/name", options.Repo))
+		} else {
+			cfg.GitHub.Owner = owner
+			cfg.GitHub.Repo = strings.TrimSuffix(repo, ".git")
+		}
+	}
+	if options.ChangelogTemplate != "" {
+		if err := template.ValidateTemplate(options.ChangelogTemplate, template.TemplateTypeChangelog); err != nil {
+			problems = append(problems, fmt.Sprintf("--changelog-template: %v", err))
+		} else {
+			cfg.Templates.Changelog.Source = options.ChangelogTemplate
+		}
+	}
+	return problems
+}
+
+// parseInitPackages parses the --package values, returning the problems with
+// those that are malformed
+func parseInitPackages(specs []string) ([]config.Package, []string) {
+	var packages []config.Package
+	var problems []string
+	for _, spec := range specs {
+		pkg, err := parseInitPackage(spec)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		packages = append(packages, pkg)
+	}
+	return packages, problems
+}
+
+// parseInitPackage parses a --package value of the form name=path:ecosystem
+func parseInitPackage(spec string) (config.Package, error) {
+	name, rest, ok := strings.Cut(spec, "=")
+	i := strings.LastIndex(rest, ":")
+	if !ok || name == "" || i <= 0 || i == len(rest)-1 {
+		return config.Package{}, fmt.Errorf("--package %q must be name=path:ecosystem", spec)
+	}
+	path, ecosystem := rest[:i], rest[i+1:]
+	if !slices.Contains(config.Ecosystems(), ecosystem) {
+		return config.Package{}, fmt.Errorf("--package %q has unknown ecosystem %q (expected one of %s)",
+			spec, ecosystem, strings.Join(config.Ecosystems(), ", "))
+	}
+	return config.Package{Name: name, Path: path, Ecosystem: ecosystem}, nil
+}
+
+// resolveInitPackages combines the --package packages with, under --auto,
+// the detected packages not already named, and checks them against --type.
+// Without --type, more than one package makes a monorepo.
+func resolveInitPackages(options InitOptions, flagPackages, detectedPackages []config.Package) ([]config.Package, []string) {
+	var problems []string
+	packages := slices.Clone(flagPackages)
+	names := make(map[string]bool)
+	for _, pkg := range flagPackages {
+		if names[pkg.Name] {
+			problems = append(problems, fmt.Sprintf("--package %s is given more than once", pkg.Name))
+		}
+		names[pkg.Name] = true
+	}
+	if options.Auto {
+		for _, pkg := range detectedPackages {
+			if !names[pkg.Name] {
+				packages = append(packages, pkg)
+				names[pkg.Name] = true
+			}
+		}
+	}
+
+	switch {
+	case len(packages) == 0 && options.Auto:
+		problems = append(problems, "--auto detected no packages; add them with --package name=path:ecosystem")
+	case len(packages) == 0:
+		problems = append(problems, "no packages: pass --package name=path:ecosystem or --auto")
+	case options.Type == initTypeSingle && len(packages) > 1:
+		problems = append(problems, fmt.Sprintf("--type single takes exactly one package, got %d", len(packages)))
+	}
+	return packages, problems
+}
+
+// initFlagsError reports every problem with the init flags at once
+func initFlagsError(problems []string) error {
+	return shipyarderrors.NewValidationError("flags", strings.Join(problems, "; "))
+}
+
+// chooseDetectedPackages keeps one candidate per directory, asking which to
+// keep wherever several ecosystems were found in one directory
+func chooseDetectedPackages(candidates []detect.Candidate) ([]detect.Candidate, error) {
+	chosen := make(map[string]detect.Candidate)
+	for _, group := range detect.Conflicts(candidates) {
+		labels := make([]string, len(group))
+		for i, c := range group {
+			labels[i] = fmt.Sprintf("%s (%s) from %s", c.Package.Name, c.Package.Ecosystem, c.Manifest)
+		}
+		i, err := prompt.PromptSelectOne(fmt.Sprintf("Several manifests found in %s - which package is it?", group[0].Package.Path), labels)
+		if err != nil {
+			return nil, fmt.Errorf("package selection failed: %w", err)
+		}
+		chosen[group[i].Package.Path] = group[i]
+	}
 
+	resolved := detect.ResolveConflicts(candidates, nil)
+	for i, c := range resolved {
+		if pick, ok := chosen[c.Package.Path]; ok {
+			resolved[i] = pick
+		}
+	}
+	return resolved, nil
+}
+
+// generateInteractiveConfig prompts user for all configuration options
+func generateInteractiveConfig(cfg *config.Config, candidates []detect.Candidate, projectPath string, status *ui.Status) (*config.Config, error) {
 	fmt.Println() // Spacing
 
+	detected, err := chooseDetectedPackages(candidates)
+	if err != nil {
+		return nil, err
+	}
+	detectedPackages := make([]config.Package, len(detected))
+	sources := make([]string, len(detected))
+	for i, c := range detected {
+		detectedPackages[i] = c.Package
+		sources[i] = c.Manifest
+	}
+
 	// Step 1: Ask if monorepo or single repo
 	repoType, err := prompt.PromptRepoType()
 	if err != nil {
@@ -246,15 +487,15 @@ func generateInteractiveConfig(cfg *config.Config, detectedPackages []config.Pac
 	case prompt.RepoTypeMonorepo:
 		// Monorepo: Review detected packages
 		if len(detectedPackages) > 0 {
-			log.Info("Detected %d package(s)", len(detectedPackages))
-			selectedPackages, err := prompt.PromptReviewPackages(detectedPackages)
+			status.Info("Detected %d package(s)", len(detectedPackages))
+			selectedPackages, err := prompt.PromptReviewPackagesWithSources(detectedPackages, sources)
 			if err != nil {
 				return nil, fmt.Errorf("package review failed: %w", err)
 			}
 			cfg.Packages = selectedPackages
-			log.Info("Selected %d package(s)", len(selectedPackages))
+			status.Info("Selected %d package(s)", len(selectedPackages))
 		} else {
-			log.Warn("No packages detected in monorepo")
+			status.Warn("No packages detected in monorepo")
 			// Prompt to add manually
 			addManual, err := prompt.PromptConfirm("Would you like to add a package manually?", true)
 			if err != nil {
@@ -274,7 +515,7 @@ func generateInteractiveConfig(cfg *config.Config, detectedPackages []config.Pac
 		var pkg config.Package
 		if len(detectedPackages) == 1 {
 			// Use detected package as default
-			log.Info("Detected package: %s (%s)", detectedPackages[0].Name, detectedPackages[0].Ecosystem)
+			status.Info("Detected package: %s (%s) from %s", detectedPackages[0].Name, detectedPackages[0].Ecosystem, sources[0])
 			confirm, err := prompt.PromptConfirm("Use detected package configuration?", true)
 			if err != nil {
 				return nil, err
@@ -295,7 +536,7 @@ func generateInteractiveConfig(cfg *config.Config, detectedPackages []config.Pac
 			}
 		}
 		cfg.Packages = []config.Package{pkg}
-		log.Info("Configured package: %s", pkg.Name)
+		status.Info("Configured package: %s", pkg.Name)
 	}
 
 	return cfg, nil
@@ -323,6 +564,48 @@ func promptForPackageDetails(defaultName string) (config.Package, error) {
 	}, nil
 }
 
+// importExistingChangelogs records the releases of packages' existing
+// CHANGELOG.md files in history, asking first unless --import-changelog is
+// set. --yes without --import-changelog imports nothing. Files with no
+// versions to read are left alone.
+func importExistingChangelogs(projectPath string, cfg *config.Config, historyPath string, options InitOptions) ([]ImportChangelogOutput, error) {
+	if options.Yes && !options.ImportChangelog {
+		return nil, nil
+	}
+
+	var imports []ImportChangelogOutput
+	for _, pkg := range cfg.Packages {
+		path, err := release.ChangelogPath(projectPath, pkg)
+		if err != nil || !fileutil.PathExists(path) {
+			continue
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read changelog: %w", err)
+		}
+		releases, err := keepachangelog.Parse(content)
+		if err != nil {
+			logger.Get().Debug("Not importing %s: %v", path, err)
+			continue
+		}
+		if !options.ImportChangelog {
+			confirm, err := prompt.PromptConfirm(fmt.Sprintf("Found %s with %d version(s) - record them in history for %s?", projectRelPath(projectPath, path), len(releases), pkg.Name), true)
+			if err != nil {
+				return nil, err
+			}
+			if !confirm {
+				continue
+			}
+		}
+		imported, err := importChangelog(projectPath, historyPath, pkg.Name, path, false)
+		if err != nil {
+			return nil, err
+		}
+		imports = append(imports, imported)
+	}
+	return imports, nil
+}
+
 // initializeHistoryFile creates an empty history file
 func initializeHistoryFile(historyPath string) error {
 	// Create empty JSON array
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/NatoNathan/shipyard/internal/config"
 	gogit "github.com/go-git/go-git/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -51,6 +52,27 @@ func TestInitCommand_NewProject(t *testing.T) {
 	assert.Equal(t, "[]", string(historyContent), "History file should contain empty JSON array")
 }
 
+// TestInitCommand_WritesMinShipyardVersion tests that release builds record
+// the version line they were initialized with and dev builds record nothing
+func TestInitCommand_WritesMinShipyardVersion(t *testing.T) {
+	t.Cleanup(func() { config.SetToolVersion("dev") })
+
+	for _, tt := range []struct{ version, want string }{
+		{version: "0.9.3", want: "0.9.0"},
+		{version: "dev", want: ""},
+	} {
+		config.SetToolVersion(tt.version)
+		tempDir := t.TempDir()
+		initGitRepo(t, tempDir)
+		require.NoError(t, runInit(tempDir, InitOptions{Yes: true}))
+
+		cfg, err := config.LoadFromDir(tempDir)
+		require.NoError(t, err, tt.version)
+		assert.Equal(t, tt.want, cfg.MinShipyardVersion, tt.version)
+		assert.Equal(t, config.CurrentSchemaVersion, cfg.SchemaVersion, tt.version)
+	}
+}
+
 // TestInitCommand_ExistingConfiguration tests initialization when Shipyard is already configured
 func TestInitCommand_ExistingConfiguration(t *testing.T) {
 	// Create temporary directory for test
@@ -289,3 +311,121 @@ func TestInitCommand_RemoteConfig(t *testing.T) {
 	assert.Contains(t, string(configContent), remoteConfigPath, "Config should reference remote config URL")
 }
 
+// TestInitCommand_FlagDriven tests non-interactive init configured entirely by flags
+func TestInitCommand_FlagDriven(t *testing.T) {
+	t.Run("packages from flags", func(t *testing.T) {
+		tempDir := t.TempDir()
+		initGitRepo(t, tempDir)
+
+		cmd := NewInitCommand()
+		cmd.SetArgs([]string{"--yes", "--type", "monorepo",
+			"--package", "core=./core:go", "--package", "web=./web:npm",
+			"--repo", "acme/widgets", "--changelog-template", "builtin:keepachangelog"})
+		defer changeToDir(t, tempDir)()
+		require.NoError(t, cmd.Execute())
+
+		cfg, err := config.LoadFromDir(tempDir)
+		require.NoError(t, err)
+		assert.Equal(t, []config.Package{
+			{Name: "core", Path: "./core", Ecosystem: config.EcosystemGo},
+			{Name: "web", Path: "./web", Ecosystem: config.EcosystemNPM},
+		}, cfg.Packages)
+		assert.Equal(t, config.GitHubConfig{Owner: "acme", Repo: "widgets"}, cfg.GitHub)
+		assert.Equal(t, "builtin:keepachangelog", cfg.Templates.Changelog.Source)
+	})
+
+	t.Run("auto takes detected packages", func(t *testing.T) {
+		tempDir := t.TempDir()
+		initGitRepo(t, tempDir)
+		require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "api"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, "api", "package.json"), []byte(`{"name": "api", "version": "1.0.0"}`), 0644))
+
+		require.NoError(t, runInit(tempDir, InitOptions{Yes: true, Auto: true, Packages: []string{"tools=./tools:go"}}))
+
+		cfg, err := config.LoadFromDir(tempDir)
+		require.NoError(t, err)
+		require.Len(t, cfg.Packages, 2)
+		assert.Equal(t, "tools", cfg.Packages[0].Name)
+		assert.Equal(t, "api", cfg.Packages[1].Name)
+	})
+
+	t.Run("problems are reported together", func(t *testing.T) {
+		tempDir := t.TempDir()
+		initGitRepo(t, tempDir)
+
+		err := runInit(tempDir, InitOptions{
+			Yes:      true,
+			Type:     "single",
+			Packages: []string{"core=./core:go", "web=./web:ruby", "broken", "api=./api:go"},
+			Repo:     "widgets",
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `--repo "widgets" must be owner/name`)
+		assert.Contains(t, err.Error(), `--package "web=./web:ruby" has unknown ecosystem "ruby"`)
+		assert.Contains(t, err.Error(), `--package "broken" must be name=path:ecosystem`)
+		assert.Contains(t, err.Error(), "--type single takes exactly one package, got 2")
+		assert.NoDirExists(t, filepath.Join(tempDir, ".shipyard"), "invalid flags should write nothing")
+	})
+
+	t.Run("no packages", func(t *testing.T) {
+		tempDir := t.TempDir()
+		initGitRepo(t, tempDir)
+
+		err := runInit(tempDir, InitOptions{Yes: true, Type: "monorepo"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no packages: pass --package name=path:ecosystem or --auto")
+	})
+
+	t.Run("package flags require yes", func(t *testing.T) {
+		tempDir := t.TempDir()
+		initGitRepo(t, tempDir)
+
+		err := runInit(tempDir, InitOptions{Packages: []string{"core=./core:go"}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "require --yes")
+	})
+
+	t.Run("skip git detection", func(t *testing.T) {
+		tempDir := t.TempDir()
+
+		require.NoError(t, runInit(tempDir, InitOptions{Yes: true, SkipGitDetection: true, Packages: []string{"core=.:go"}}))
+		assert.FileExists(t, filepath.Join(tempDir, ".shipyard", "shipyard.yaml"))
+
+		// An existing config still needs --force
+		err := runInit(tempDir, InitOptions{Yes: true, SkipGitDetection: true, Packages: []string{"core=.:go"}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "already initialized")
+	})
+}
+
+// TestInitCommand_EcosystemPriority tests that --ecosystem-priority settles a
+// directory holding several manifests without prompting
+func TestInitCommand_EcosystemPriority(t *testing.T) {
+	setup := func(t *testing.T) string {
+		t.Helper()
+		tempDir := t.TempDir()
+		initGitRepo(t, tempDir)
+		chartDir := filepath.Join(tempDir, "charts", "dashboard")
+		require.NoError(t, os.MkdirAll(chartDir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(chartDir, "Chart.yaml"), []byte("name: dashboard\n"), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(chartDir, "package.json"), []byte(`{"name": "dashboard-ui"}`), 0644))
+		return tempDir
+	}
+
+	t.Run("priority picks the ecosystem", func(t *testing.T) {
+		tempDir := setup(t)
+		require.NoError(t, runInit(tempDir, InitOptions{Yes: true, EcosystemPriority: []string{"helm"}}))
+
+		cfg, err := config.LoadFromDir(tempDir)
+		require.NoError(t, err)
+		assert.Equal(t, []config.Package{{Name: "dashboard", Path: "./charts/dashboard", Ecosystem: config.EcosystemHelm}}, cfg.Packages)
+	})
+
+	t.Run("unknown ecosystem and negative depth", func(t *testing.T) {
+		tempDir := setup(t)
+		err := runInit(tempDir, InitOptions{Yes: true, EcosystemPriority: []string{"ruby"}, ScanDepth: -1})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `--ecosystem-priority has unknown ecosystem "ruby"`)
+		assert.Contains(t, err.Error(), "--scan-depth must not be negative")
+	})
+}
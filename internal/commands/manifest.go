@@ -0,0 +1,161 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/fileutil"
+	"github.com/NatoNathan/shipyard/internal/git"
+	"github.com/NatoNathan/shipyard/internal/history"
+	"github.com/NatoNathan/shipyard/internal/release"
+	"github.com/NatoNathan/shipyard/pkg/shipment"
+	"github.com/spf13/cobra"
+)
+
+// ManifestOptions holds options for the manifest command
+type ManifestOptions struct {
+	Packages []string
+	Version  string
+	Output   string
+}
+
+// NewManifestCommand creates the manifest command
+func NewManifestCommand() *cobra.Command {
+	opts := &ManifestOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "manifest [-p package]... [--version version] [-o file]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Draw up the bill of lading for a voyage",
+		Long: `Write the machine-readable manifest of a release from the captain's log:
+each package's old and new version, change type, tag, release commit and
+the consignments it carried, as versioned JSON for deploy tooling.
+
+Without --version, the manifest lists the latest release of every package.`,
+		Example: `  # Manifest of the latest release of every package
+  shipyard manifest
+
+  # Manifest of a past release, written to a file
+  shipyard manifest --version 1.2.0 --output release.json
+
+  # Manifest of one package's latest release
+  shipyard manifest --package core`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cwd, err := projectRoot()
+			if err != nil {
+				return err
+			}
+			return runManifest(cwd, opts)
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&opts.Packages, "package", "p", []string{}, "Limit to specific packages (can be specified multiple times)")
+	cmd.Flags().StringVar(&opts.Version, "version", "", "Manifest of a specific version instead of the latest releases")
+	cmd.Flags().StringVarP(&opts.Output, "output", "o", "", "Output file (default: stdout)")
+
+	RegisterPackageCompletions(cmd, "package")
+
+	return cmd
+}
+
+// runManifest writes the manifest of the selected history entries
+func runManifest(projectPath string, opts *ManifestOptions) error {
+	cfg, err := config.LoadFromDir(projectPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	settings := release.SettingsFor(projectPath, cfg)
+
+	for _, name := range opts.Packages {
+		if _, ok := cfg.GetPackage(name); !ok {
+			return fmt.Errorf("package %s not found in configuration", name)
+		}
+	}
+
+	historyFile := settings.History(filepath.Join(projectPath, cfg.History.Path))
+	readHistory := historyFile.Read
+	if opts.Version != "" {
+		// Past versions may have been compacted into the yearly archives
+		readHistory = historyFile.ReadWithArchives
+	}
+	entries, err := readHistory()
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return fmt.Errorf("failed to read history: %w", err)
+		}
+		entries = []history.Entry{}
+	}
+
+	selected, err := selectManifestEntries(cfg, entries, opts)
+	if err != nil {
+		return err
+	}
+
+	packages := make([]shipment.Package, len(selected))
+	for i, entry := range selected {
+		packages[i] = release.ManifestPackage(entry)
+		// The release commit is not in history, since history is part of it
+		if entry.Tag != "" {
+			commit, err := git.TagCommitHash(projectPath, entry.Tag)
+			if err != nil {
+				return fmt.Errorf("failed to resolve tag %s: %w", entry.Tag, err)
+			}
+			packages[i].Commit = commit
+		}
+	}
+
+	data, err := shipment.New(packages).Marshal()
+	if err != nil {
+		return err
+	}
+	if opts.Output != "" {
+		return fileutil.WriteFile(opts.Output, data, 0644)
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
+
+// selectManifestEntries picks the entries of one version, or the latest entry
+// of each package, in release order. Entries recorded under a package alias
+// count as the package's.
+func selectManifestEntries(cfg *config.Config, entries []history.Entry, opts *ManifestOptions) ([]history.Entry, error) {
+	if len(opts.Packages) > 0 {
+		var filtered []history.Entry
+		for _, name := range opts.Packages {
+			filtered = append(filtered, release.PackageEntries(cfg, entries, name)...)
+		}
+		entries = filtered
+	}
+	if opts.Version != "" {
+		version, err := ParseVersionArg(opts.Version)
+		if err != nil {
+			return nil, err
+		}
+		entries = history.FilterByVersion(entries, version)
+		if len(entries) == 0 {
+			return nil, fmt.Errorf("no history entry found for version %s", opts.Version)
+		}
+	}
+
+	latest := make(map[string]history.Entry)
+	for _, entry := range entries {
+		name := cfg.CanonicalPackageName(entry.Package)
+		if current, ok := latest[name]; !ok || !entry.Timestamp.Before(current.Timestamp) {
+			latest[name] = entry
+		}
+	}
+	selected := make([]history.Entry, 0, len(latest))
+	for _, entry := range latest {
+		selected = append(selected, entry)
+	}
+	sort.SliceStable(selected, func(i, j int) bool {
+		if !selected[i].Timestamp.Equal(selected[j].Timestamp) {
+			return selected[i].Timestamp.Before(selected[j].Timestamp)
+		}
+		return selected[i].Package < selected[j].Package
+	})
+	return selected, nil
+}
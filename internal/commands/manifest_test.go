@@ -0,0 +1,120 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/history"
+	"github.com/NatoNathan/shipyard/internal/release"
+	"github.com/NatoNathan/shipyard/pkg/shipment"
+	"github.com/NatoNathan/shipyard/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readManifestFile(t *testing.T, path string) *shipment.Manifest {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	manifest, err := shipment.Parse(data)
+	require.NoError(t, err)
+	return manifest
+}
+
+func TestVersionCommand_Manifest(t *testing.T) {
+	tempDir, repo, _ := setupResumeTestRepo(t)
+
+	output := captureOutput(func() {
+		require.NoError(t, runVersionInDir(tempDir, &VersionCommandOptions{Manifest: "release.json"}))
+	})
+	assert.Contains(t, output, "Wrote release manifest to")
+
+	head, err := repo.Head()
+	require.NoError(t, err)
+	manifest := readManifestFile(t, filepath.Join(tempDir, "release.json"))
+	assert.Equal(t, shipment.SchemaVersion, manifest.SchemaVersion)
+	require.Len(t, manifest.Packages, 1)
+	pkg := manifest.Packages[0]
+	assert.Equal(t, "test-package", pkg.Name)
+	assert.Equal(t, "1.0.0", pkg.OldVersion)
+	assert.Equal(t, "1.1.0", pkg.NewVersion)
+	assert.Equal(t, types.ChangeTypeMinor, pkg.ChangeType)
+	assert.Equal(t, "v1.1.0", pkg.Tag)
+	assert.Equal(t, head.Hash().String(), pkg.Commit)
+	require.Len(t, pkg.Consignments, 1)
+	assert.Equal(t, "c1", pkg.Consignments[0].ID)
+	assert.Contains(t, pkg.Consignments[0].Summary, "Add feature")
+
+	// The manifest can be regenerated from history later
+	require.NoError(t, runManifest(tempDir, &ManifestOptions{Version: "1.1.0", Output: filepath.Join(tempDir, "again.json")}))
+	assert.Equal(t, manifest, readManifestFile(t, filepath.Join(tempDir, "again.json")))
+}
+
+func TestVersionCommand_ManifestValidation(t *testing.T) {
+	tempDir, _, _ := setupResumeTestRepo(t)
+	err := runVersionInDir(tempDir, &VersionCommandOptions{Manifest: "release.json", Preview: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--manifest")
+}
+
+func TestSelectManifestEntries(t *testing.T) {
+	base := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	entries := []history.Entry{
+		{Package: "core", Version: "1.0.0", Timestamp: base},
+		{Package: "api", Version: "1.0.0", Timestamp: base.Add(time.Second)},
+		{Package: "core", Version: "1.1.0", Timestamp: base.Add(time.Hour)},
+	}
+	cfg := &config.Config{Packages: []config.Package{{Name: "core"}, {Name: "api"}}}
+	names := func(selected []history.Entry) []string {
+		var out []string
+		for _, e := range selected {
+			out = append(out, e.Package+"@"+e.Version)
+		}
+		return out
+	}
+
+	selected, err := selectManifestEntries(cfg, entries, &ManifestOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api@1.0.0", "core@1.1.0"}, names(selected), "latest release of each package")
+
+	selected, err = selectManifestEntries(cfg, entries, &ManifestOptions{Version: "v1.0.0"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"core@1.0.0", "api@1.0.0"}, names(selected))
+
+	selected, err = selectManifestEntries(cfg, entries, &ManifestOptions{Packages: []string{"core"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"core@1.1.0"}, names(selected))
+
+	_, err = selectManifestEntries(cfg, entries, &ManifestOptions{Version: "2.0.0"})
+	assert.ErrorContains(t, err, "no history entry found for version 2.0.0")
+
+	// After renaming core to platform, its releases still count as one package
+	renamed := &config.Config{Packages: []config.Package{{Name: "platform", Aliases: []string{"core"}}, {Name: "api"}}}
+	entries = append(entries, history.Entry{Package: "platform", Version: "1.2.0", Timestamp: base.Add(2 * time.Hour)})
+	selected, err = selectManifestEntries(renamed, entries, &ManifestOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api@1.0.0", "platform@1.2.0"}, names(selected))
+
+	selected, err = selectManifestEntries(renamed, entries[:3], &ManifestOptions{Packages: []string{"platform"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"core@1.1.0"}, names(selected))
+}
+
+func TestManifestPackage_LegacyEntry(t *testing.T) {
+	pkg := release.ManifestPackage(history.Entry{
+		Package: "core",
+		Version: "1.1.0",
+		Tag:     "v1.1.0",
+		Consignments: []history.Consignment{
+			{ID: "a", Summary: "Fix", ChangeType: "patch"},
+			{ID: "b", Summary: "Add", ChangeType: "minor", Metadata: map[string]interface{}{"issue": "12"}},
+		},
+	})
+	assert.Empty(t, pkg.OldVersion, "entries before the bump was recorded have no old version")
+	assert.Equal(t, types.ChangeTypeMinor, pkg.ChangeType, "the highest consignment change type")
+	assert.Equal(t, "v1.1.0", pkg.Tag)
+	assert.Equal(t, map[string]interface{}{"issue": "12"}, pkg.Consignments[1].Metadata)
+}
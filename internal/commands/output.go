@@ -13,6 +13,8 @@ type GlobalFlags struct {
 	JSON    bool
 	Quiet   bool
 	Verbose bool
+	// MaxSeverity overrides the level of every enabled rule (warn or error)
+	MaxSeverity string
 }
 
 // GetGlobalFlags extracts global flags from the root command
@@ -31,6 +33,9 @@ func GetGlobalFlags(cmd *cobra.Command) GlobalFlags {
 		if flag := rootCmd.PersistentFlags().Lookup("verbose"); flag != nil {
 			flags.Verbose, _ = rootCmd.PersistentFlags().GetBool("verbose")
 		}
+		if flag := rootCmd.PersistentFlags().Lookup("max-severity"); flag != nil {
+			flags.MaxSeverity, _ = rootCmd.PersistentFlags().GetString("max-severity")
+		}
 	}
 
 	return flags
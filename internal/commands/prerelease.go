@@ -12,6 +12,7 @@ import (
 	"github.com/NatoNathan/shipyard/internal/git"
 	"github.com/NatoNathan/shipyard/internal/graph"
 	"github.com/NatoNathan/shipyard/internal/prerelease"
+	"github.com/NatoNathan/shipyard/internal/release"
 	"github.com/NatoNathan/shipyard/internal/template"
 	"github.com/NatoNathan/shipyard/internal/ui"
 	"github.com/NatoNathan/shipyard/internal/version"
@@ -91,9 +92,9 @@ The stage is determined from .shipyard/prerelease.yml state file:
 }
 
 func runPrerelease(opts *PrereleaseCommandOptions) error {
-	cwd, err := os.Getwd()
+	cwd, err := projectRoot()
 	if err != nil {
-		return fmt.Errorf("failed to get current directory: %w", err)
+		return err
 	}
 	return runPrereleaseWithDir(cwd, opts)
 }
@@ -110,6 +111,7 @@ func runPrereleaseWithDir(projectPath string, opts *PrereleaseCommandOptions) er
 	if err != nil {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
+	settings := release.SettingsFor(projectPath, cfg)
 
 	// Validate pre-release stages exist
 	if len(cfg.PreRelease.Stages) == 0 {
@@ -141,7 +143,7 @@ func runPrereleaseWithDir(projectPath string, opts *PrereleaseCommandOptions) er
 		return fmt.Errorf("failed to build dependency graph: %w", err)
 	}
 
-	currentVersions, err := ReadAllCurrentVersions(projectPath, cfg)
+	currentVersions, err := release.ReadAllCurrentVersions(projectPath, cfg, settings)
 	if err != nil {
 		return err
 	}
@@ -170,6 +172,7 @@ func runPrereleaseWithDir(projectPath string, opts *PrereleaseCommandOptions) er
 
 	// 5. For each package with bumps, determine stage and counter
 	renderer := template.NewTemplateRenderer()
+	renderer.SetRepository(settings.Repository)
 	type prereleaseResult struct {
 		pkg           string
 		oldVersion    semver.Version
@@ -232,10 +235,11 @@ func runPrereleaseWithDir(projectPath string, opts *PrereleaseCommandOptions) er
 			tagTemplate = "v{{.Version}}-{{.Stage}}.{{.Counter}}"
 		}
 		tagCtx := map[string]interface{}{
-			"Version": bump.NewVersion.String(),
-			"Counter": counter,
-			"Package": pkgName,
-			"Stage":   stage.Name,
+			"Version":    bump.NewVersion.String(),
+			"VersionTag": "v" + bump.NewVersion.String(),
+			"Counter":    counter,
+			"Package":    pkgName,
+			"Stage":      stage.Name,
 		}
 		tagName, err := renderer.Render(tagTemplate, tagCtx)
 		if err != nil {
@@ -297,7 +301,7 @@ func runPrereleaseWithDir(projectPath string, opts *PrereleaseCommandOptions) er
 			return fmt.Errorf("package %s not found in configuration", r.pkg)
 		}
 		pkgPath := filepath.Join(projectPath, pkg.Path)
-		handler, err := GetEcosystemHandler(pkg, pkgPath)
+		handler, err := release.GetEcosystemHandler(pkg, pkgPath)
 		if err != nil {
 			return err
 		}
@@ -340,7 +344,7 @@ func runPrereleaseWithDir(projectPath string, opts *PrereleaseCommandOptions) er
 		for _, r := range results {
 			changedPackages[r.pkg] = true
 		}
-		filesToStage, err := CollectVersionFiles(projectPath, cfg, changedPackages)
+		filesToStage, err := release.CollectVersionFiles(projectPath, cfg, changedPackages)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,397 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/changelog"
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/consignment"
+	shipyarderrors "github.com/NatoNathan/shipyard/internal/errors"
+	"github.com/NatoNathan/shipyard/internal/fileutil"
+	"github.com/NatoNathan/shipyard/internal/history"
+	"github.com/NatoNathan/shipyard/internal/release"
+	"github.com/NatoNathan/shipyard/internal/template"
+	"github.com/NatoNathan/shipyard/internal/ui"
+	"github.com/NatoNathan/shipyard/internal/version"
+	"github.com/NatoNathan/shipyard/pkg/semver"
+	"github.com/NatoNathan/shipyard/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// previewKinds maps the --kind values to the template type they load
+var previewKinds = map[string]template.TemplateType{
+	"changelog":     template.TemplateTypeChangelog,
+	"tag":           template.TemplateTypeTag,
+	"commit":        template.TemplateTypeCommit,
+	"release-notes": template.TemplateTypeReleaseNotes,
+}
+
+// templateErrorPositionRe matches the position text/template reports in
+// parse and execution errors ("template: name:3:5: ..."), and the unknown
+// function form the parser rewrites them to ("template name, line 3: ...")
+var templateErrorPositionRe = regexp.MustCompile(`template(?:: .*?:(\d+)(?::(\d+))?: | .*?, line (\d+): )(.*)$`)
+
+// PreviewTemplateOptions holds the options for the preview-template command
+type PreviewTemplateOptions struct {
+	Kind     string
+	Packages []string
+	Sample   bool
+	Data     string
+}
+
+// templatePreview is the rendered output of a template for one package, or
+// for the whole release when Package is empty
+type templatePreview struct {
+	Package string
+	Output  string
+}
+
+// NewPreviewTemplateCommand creates the preview-template command
+func NewPreviewTemplateCommand() *cobra.Command {
+	opts := &PreviewTemplateOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "preview-template <source> [--kind {changelog|tag|commit|release-notes}] [--sample | --data file.json] [-p package]...",
+		DisableFlagsInUseLine: true,
+		Short:                 "Sketch a template against the cargo before sailing",
+		Long: `Render a changelog, tag, commit or release notes template without touching
+the repository. The template source can be a file path, a builtin (builtin:name)
+or a remote reference, exactly as in the config file.
+
+By default the template is rendered against the pending consignments and the
+versions they would produce. Use --sample to render against a bundled sample
+release instead, or --data to render against a JSON object of your own.
+
+Render errors are reported with the line and column of the template they
+occurred at.`,
+		Example: `  # Preview a changelog template against pending consignments
+  shipyard preview-template .shipyard/templates/changelog.tmpl
+
+  # Preview a builtin tag template against sample data
+  shipyard preview-template builtin:go-annotated --kind tag --sample
+
+  # Render against a custom context
+  shipyard preview-template notes.tmpl --kind release-notes --data context.json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			outputs, err := runPreviewTemplate(args[0], opts)
+			if err != nil {
+				return err
+			}
+			printTemplatePreviews(outputs)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Kind, "kind", "k", "changelog", "Template kind (changelog, tag, commit, release-notes)")
+	cmd.Flags().StringSliceVarP(&opts.Packages, "package", "p", nil, "Only render for these package(s)")
+	cmd.Flags().BoolVar(&opts.Sample, "sample", false, "Render against bundled sample data instead of pending consignments")
+	cmd.Flags().StringVar(&opts.Data, "data", "", "Render against the JSON object in this file")
+	cmd.MarkFlagsMutuallyExclusive("sample", "data")
+
+	RegisterPackageCompletions(cmd, "package")
+
+	return cmd
+}
+
+// runPreviewTemplate loads the template and renders it against the selected data
+func runPreviewTemplate(source string, opts *PreviewTemplateOptions) ([]templatePreview, error) {
+	kind, ok := previewKinds[opts.Kind]
+	if !ok {
+		return nil, shipyarderrors.NewValidationError("kind", fmt.Sprintf("unknown template kind %q (use changelog, tag, commit or release-notes)", opts.Kind))
+	}
+
+	cwd, err := projectRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	// Remote settings apply whenever a project config is at hand
+	cfg, cfgErr := config.LoadFromDir(cwd)
+	var settings release.Settings
+	if cfgErr == nil {
+		settings = release.SettingsFor(cwd, cfg)
+	}
+
+	loader := template.NewTemplateLoader()
+	loader.SetBaseDir(cwd)
+	loader.SetRemoteOptions(settings.Remote)
+	content, err := loader.Load(source, kind)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load template: %w", err)
+	}
+
+	var outputs []templatePreview
+	switch {
+	case opts.Data != "":
+		outputs, err = renderTemplateWithData(content, opts.Data, settings.Repository)
+	case opts.Sample:
+		consignments, bumps, ecosystems := sampleTemplateData()
+		renderOptions := template.DefaultRenderOptions()
+		renderOptions.Repository = settings.Repository
+		generator := changelog.NewChangelogGenerator()
+		generator.SetRenderOptions(renderOptions)
+		generator.SetPackageEcosystems(ecosystems)
+		generator.SetChangelogSections((&config.Config{}).ChangelogSections(""))
+		generator.SetClock(func() time.Time { return sampleReleaseTime })
+		outputs, err = renderTemplatePreviews(generator, kind, content, consignments, filterBumps(bumps, opts.Packages))
+	default:
+		if cfgErr != nil {
+			return nil, fmt.Errorf("failed to load configuration (use --sample to preview without a project): %w", cfgErr)
+		}
+		// Render against the release the version command would make
+		plan, planErr := release.NewPlan(cwd, cfg, settings, release.PlanOptions{}, nil)
+		if planErr != nil {
+			return nil, planErr
+		}
+		if len(plan.Consignments) == 0 {
+			return nil, fmt.Errorf("no pending consignments to render against; use --sample or --data")
+		}
+		generator := release.NewGenerator(cwd, cfg, settings)
+		generator.SetChannel(plan.Channel)
+		outputs, err = renderTemplatePreviews(generator, kind, content, plan.Consignments, filterBumps(plan.Bumps, opts.Packages))
+	}
+	if err != nil {
+		return nil, formatTemplateError(err, content)
+	}
+	if len(outputs) == 0 {
+		return nil, fmt.Errorf("no pending changes for package(s) %s", strings.Join(opts.Packages, ", "))
+	}
+	return outputs, nil
+}
+
+// renderTemplatePreviews renders a template the way a release would: once per
+// package for changelog, tag and release notes templates, and once for the
+// whole release for commit templates
+func renderTemplatePreviews(
+	generator *changelog.ChangelogGenerator,
+	kind template.TemplateType,
+	content string,
+	consignments []*consignment.Consignment,
+	bumps map[string]version.VersionBump,
+) ([]templatePreview, error) {
+	if len(bumps) == 0 {
+		return nil, nil
+	}
+
+	if kind == template.TemplateTypeCommit {
+		changelogBumps := make(map[string]changelog.VersionBump)
+		for name, bump := range bumps {
+			changelogBumps[name] = changelog.VersionBump{
+				Package:    bump.Package,
+				OldVersion: bump.OldVersion,
+				NewVersion: bump.NewVersion,
+				ChangeType: bump.ChangeType,
+			}
+		}
+		output, err := generator.GenerateCommitMessageWithTemplate(consignments, changelogBumps, content)
+		if err != nil {
+			return nil, err
+		}
+		return []templatePreview{{Output: output}}, nil
+	}
+
+	names := make([]string, 0, len(bumps))
+	for name := range bumps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var outputs []templatePreview
+	for _, name := range names {
+		newVersion := bumps[name].NewVersion
+
+		var output string
+		var err error
+		switch kind {
+		case template.TemplateTypeChangelog:
+			output, err = generator.GenerateForPackageWithTemplate(consignments, name, newVersion, content)
+		case template.TemplateTypeReleaseNotes:
+			output, err = generator.GenerateReleaseNotesWithTemplate(consignments, name, newVersion, content)
+		case template.TemplateTypeTag:
+			var tagName, message string
+			tagName, message, err = generator.GeneratePackageTagWithContext(consignments, name, newVersion, content)
+			output = tagName
+			if message != "" {
+				output += "\n\n" + message
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		outputs = append(outputs, templatePreview{Package: name, Output: output})
+	}
+	return outputs, nil
+}
+
+// renderTemplateWithData renders a template against the JSON object in path.
+// Strings holding RFC 3339 timestamps become times so date functions work on them.
+// Links point into repo when it is known.
+func renderTemplateWithData(content, path string, repo *template.Repository) ([]templatePreview, error) {
+	data, err := fileutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template data: %w", err)
+	}
+
+	var context map[string]interface{}
+	if err := json.Unmarshal(data, &context); err != nil {
+		return nil, shipyarderrors.NewValidationError("data", fmt.Sprintf("%s must hold a JSON object: %v", path, err))
+	}
+
+	renderer := template.NewTemplateRenderer()
+	renderer.SetRepository(repo)
+	output, err := renderer.Render(content, parseDataTimes(context))
+	if err != nil {
+		return nil, err
+	}
+	return []templatePreview{{Output: output}}, nil
+}
+
+// parseDataTimes converts RFC 3339 strings in decoded JSON to time.Time
+func parseDataTimes(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = parseDataTimes(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = parseDataTimes(item)
+		}
+	case string:
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return t
+		}
+	}
+	return value
+}
+
+// filterBumps keeps the bumps of the named packages, or all of them when none are named
+func filterBumps(bumps map[string]version.VersionBump, packages []string) map[string]version.VersionBump {
+	if len(packages) == 0 {
+		return bumps
+	}
+	filtered := make(map[string]version.VersionBump)
+	for _, name := range packages {
+		if bump, ok := bumps[name]; ok {
+			filtered[name] = bump
+		}
+	}
+	return filtered
+}
+
+// formatTemplateError adds the offending template line, and a caret under the
+// column when known, to a template error that reports its position
+func formatTemplateError(err error, content string) error {
+	match := templateErrorPositionRe.FindStringSubmatch(err.Error())
+	if match == nil {
+		return err
+	}
+
+	lineText := match[1]
+	if lineText == "" {
+		lineText = match[3]
+	}
+	line, convErr := strconv.Atoi(lineText)
+	lines := strings.Split(content, "\n")
+	if convErr != nil || line < 1 || line > len(lines) {
+		return err
+	}
+
+	position := fmt.Sprintf("line %d", line)
+	column := -1
+	if match[2] != "" {
+		column, _ = strconv.Atoi(match[2])
+		position += fmt.Sprintf(", column %d", column+1)
+	}
+
+	gutter := strconv.Itoa(line)
+	var b strings.Builder
+	fmt.Fprintf(&b, "template error at %s: %s\n", position, match[4])
+	fmt.Fprintf(&b, "  %s | %s", gutter, lines[line-1])
+	if column >= 0 && column <= len(lines[line-1]) {
+		fmt.Fprintf(&b, "\n  %s | %s^", strings.Repeat(" ", len(gutter)), strings.Repeat(" ", column))
+	}
+	return fmt.Errorf("%s", b.String())
+}
+
+// printTemplatePreviews prints rendered output, headed by the package name
+// when more than one package was rendered
+func printTemplatePreviews(outputs []templatePreview) {
+	for i, preview := range outputs {
+		if len(outputs) > 1 {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Println(ui.Dimmed(fmt.Sprintf("── %s ──", preview.Package)))
+		}
+		fmt.Println(strings.TrimRight(preview.Output, "\n"))
+	}
+}
+
+// sampleReleaseTime is the release date of the sample data
+var sampleReleaseTime = time.Date(2024, time.March, 15, 9, 30, 0, 0, time.UTC)
+
+// sampleTemplateData returns a small two-package release covering every
+// change type, a shared change, metadata and an internal-audience change
+func sampleTemplateData() ([]*consignment.Consignment, map[string]version.VersionBump, map[string]string) {
+	consignments := []*consignment.Consignment{
+		{
+			ID:         "20240312-101500-a1b2c3",
+			Timestamp:  time.Date(2024, time.March, 12, 10, 15, 0, 0, time.UTC),
+			Packages:   []string{"core"},
+			ChangeType: types.ChangeTypeMinor,
+			Summary:    "Add streaming uploads for large files",
+			Metadata:   map[string]interface{}{"author": "dana@example.com", "issue": "#142"},
+		},
+		{
+			ID:         "20240313-143000-d4e5f6",
+			Timestamp:  time.Date(2024, time.March, 13, 14, 30, 0, 0, time.UTC),
+			Packages:   []string{"core", "web"},
+			ChangeType: types.ChangeTypePatch,
+			Summary:    "Fix timeout when the registry responds slowly",
+			Metadata:   map[string]interface{}{"author": "sam@example.com"},
+		},
+		{
+			ID:         "20240314-090000-a7b8c9",
+			Timestamp:  time.Date(2024, time.March, 14, 9, 0, 0, 0, time.UTC),
+			Packages:   []string{"web"},
+			ChangeType: types.ChangeTypeMajor,
+			Summary:    "Drop support for Node 16",
+		},
+		{
+			ID:         "20240314-170000-d0e1f2",
+			Timestamp:  time.Date(2024, time.March, 14, 17, 0, 0, 0, time.UTC),
+			Packages:   []string{"web"},
+			ChangeType: types.ChangeTypePatch,
+			Summary:    "Run the test suite on the new CI runners",
+			Metadata:   map[string]interface{}{history.AudienceMetadataKey: history.AudienceInternal},
+		},
+	}
+
+	bumps := map[string]version.VersionBump{
+		"core": {
+			Package:    "core",
+			OldVersion: semver.Version{Major: 1, Minor: 4, Patch: 2},
+			NewVersion: semver.Version{Major: 1, Minor: 5, Patch: 0},
+			ChangeType: "minor",
+			Source:     "direct",
+		},
+		"web": {
+			Package:    "web",
+			OldVersion: semver.Version{Major: 2, Minor: 3, Patch: 1},
+			NewVersion: semver.Version{Major: 3, Minor: 0, Patch: 0},
+			ChangeType: "major",
+			Source:     "direct",
+		},
+	}
+
+	ecosystems := map[string]string{"core": "go", "web": "npm"}
+	return consignments, bumps, ecosystems
+}
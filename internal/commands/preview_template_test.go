@@ -0,0 +1,162 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/NatoNathan/shipyard/internal/template"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "rewrite testdata/*.golden")
+
+// previewGoldenDir is resolved before tests change the working directory
+var previewGoldenDir, _ = filepath.Abs(filepath.Join("testdata", "preview-template"))
+
+// joinPreviews renders previews the way golden files record them
+func joinPreviews(outputs []templatePreview) string {
+	var b strings.Builder
+	for _, preview := range outputs {
+		if preview.Package != "" {
+			fmt.Fprintf(&b, "-- %s --\n", preview.Package)
+		}
+		b.WriteString(strings.TrimRight(preview.Output, "\n"))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// TestPreviewTemplate_BuiltinGolden renders every builtin template of each
+// previewable kind against the sample data
+func TestPreviewTemplate_BuiltinGolden(t *testing.T) {
+	defer changeToDir(t, t.TempDir())()
+
+	for kind, templateType := range previewKinds {
+		names, err := template.ListBuiltinTemplates(templateType)
+		require.NoError(t, err)
+		require.NotEmpty(t, names)
+
+		for _, name := range names {
+			t.Run(kind+"/"+name, func(t *testing.T) {
+				outputs, err := runPreviewTemplate("builtin:"+name, &PreviewTemplateOptions{Kind: kind, Sample: true})
+				require.NoError(t, err)
+
+				golden := filepath.Join(previewGoldenDir, kind+"-"+name+".golden")
+				got := joinPreviews(outputs)
+				if *update {
+					require.NoError(t, os.MkdirAll(filepath.Dir(golden), 0755))
+					require.NoError(t, os.WriteFile(golden, []byte(got), 0644))
+				}
+				want, err := os.ReadFile(golden)
+				require.NoError(t, err)
+				assert.Equal(t, string(want), got)
+			})
+		}
+	}
+}
+
+func TestPreviewTemplate_PackageFilter(t *testing.T) {
+	defer changeToDir(t, t.TempDir())()
+
+	outputs, err := runPreviewTemplate("builtin:npm", &PreviewTemplateOptions{Kind: "tag", Sample: true, Packages: []string{"web"}})
+	require.NoError(t, err)
+	require.Len(t, outputs, 1)
+	assert.Equal(t, "web", outputs[0].Package)
+	assert.Equal(t, "web@3.0.0", outputs[0].Output)
+
+	_, err = runPreviewTemplate("builtin:npm", &PreviewTemplateOptions{Kind: "tag", Sample: true, Packages: []string{"missing"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no pending changes for package(s) missing")
+}
+
+func TestPreviewTemplate_PendingConsignments(t *testing.T) {
+	tempDir := setupVersionTestRepo(t)
+	defer changeToDir(t, tempDir)()
+	consignmentsDir := filepath.Join(tempDir, ".shipyard", "consignments")
+
+	_, err := runPreviewTemplate("builtin:default", &PreviewTemplateOptions{Kind: "changelog"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no pending consignments")
+
+	createTestConsignmentForVersion(t, consignmentsDir, "c1", []string{"test-package"}, "minor", "Add chart plotting")
+
+	outputs, err := runPreviewTemplate("builtin:default", &PreviewTemplateOptions{Kind: "changelog"})
+	require.NoError(t, err)
+	require.Len(t, outputs, 1)
+	assert.Contains(t, outputs[0].Output, "## [1.1.0]")
+	assert.Contains(t, outputs[0].Output, "Add chart plotting")
+
+	// The template is rendered without writing anything
+	_, err = os.Stat(filepath.Join(tempDir, "test-package", "CHANGELOG.md"))
+	assert.True(t, os.IsNotExist(err))
+	content, err := os.ReadFile(filepath.Join(tempDir, "test-package", "version.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"1.0.0"`)
+}
+
+func TestPreviewTemplate_Data(t *testing.T) {
+	tempDir := t.TempDir()
+	defer changeToDir(t, tempDir)()
+
+	require.NoError(t, os.WriteFile("context.json", []byte(`{"Version": "0.0.1-rc.1", "Date": "2024-01-02T03:04:05Z", "Items": ["a", "b"]}`), 0644))
+	require.NoError(t, os.WriteFile("tag.tmpl", []byte(`v{{ .Version }} {{ .Date | date "2006-01-02" }} {{ join "," .Items }}`), 0644))
+
+	outputs, err := runPreviewTemplate("tag.tmpl", &PreviewTemplateOptions{Kind: "tag", Data: "context.json"})
+	require.NoError(t, err)
+	require.Len(t, outputs, 1)
+	assert.Equal(t, "v0.0.1-rc.1 2024-01-02 a,b", outputs[0].Output)
+
+	require.NoError(t, os.WriteFile("list.json", []byte(`[1, 2]`), 0644))
+	_, err = runPreviewTemplate("tag.tmpl", &PreviewTemplateOptions{Kind: "tag", Data: "list.json"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must hold a JSON object")
+}
+
+func TestPreviewTemplate_UnknownKind(t *testing.T) {
+	_, err := runPreviewTemplate("builtin:default", &PreviewTemplateOptions{Kind: "release", Sample: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown template kind "release"`)
+}
+
+func TestPreviewTemplate_ErrorPositions(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{
+			name:     "execution error with column",
+			template: "# {{ .Package }}\n  {{ index .Versions 3 }}\n",
+			want: "template error at line 2, column 12: executing \"template\" at <.Versions>: can't evaluate field Versions in type template.ChangelogContext\n" +
+				"  2 |   {{ index .Versions 3 }}\n" +
+				"    |            ^",
+		},
+		{
+			name:     "parse error",
+			template: "one\ntwo\n{{ if }}\n",
+			want:     "template error at line 3: missing value for if\n  3 | {{ if }}",
+		},
+		{
+			name:     "unknown function",
+			template: "{{ nope 1 }}",
+			want:     "template error at line 1: unknown function \"nope\"\n  1 | {{ nope 1 }}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			defer changeToDir(t, tempDir)()
+			require.NoError(t, os.WriteFile("broken.tmpl", []byte(tt.template), 0644))
+
+			_, err := runPreviewTemplate("broken.tmpl", &PreviewTemplateOptions{Kind: "changelog", Sample: true})
+			require.Error(t, err)
+			assert.Equal(t, tt.want, err.Error())
+		})
+	}
+}
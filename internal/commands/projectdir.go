@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/NatoNathan/shipyard/internal/config"
+)
+
+// ProjectDirEnv names the project directory when --project-dir is not given
+const ProjectDirEnv = "SHIPYARD_PROJECT_DIR"
+
+// projectDir is the project directory set with --project-dir
+var projectDir string
+
+// SetProjectDir makes commands work on the project in dir instead of the one
+// found from the working directory; an empty dir restores discovery
+func SetProjectDir(dir string) {
+	projectDir = dir
+}
+
+// projectRoot returns the root of the project commands work on: the
+// --project-dir flag, else SHIPYARD_PROJECT_DIR, else the nearest directory
+// at or above the working directory with a shipyard config. Relative paths
+// in the config resolve against it. When no directory has a config, the
+// working directory is returned so commands report the project as not
+// initialized.
+func projectRoot() (string, error) {
+	if dir, err := explicitProjectDir(); err != nil || dir != "" {
+		return dir, err
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current directory: %w", err)
+	}
+	if root, err := config.FindProjectRoot(cwd); err == nil {
+		return root, nil
+	}
+	return cwd, nil
+}
+
+// initProjectDir returns the directory init sets up: --project-dir or
+// SHIPYARD_PROJECT_DIR when given, else the working directory. Parent
+// directories are not searched, so a nested project can be initialized.
+func initProjectDir() (string, error) {
+	if dir, err := explicitProjectDir(); err != nil || dir != "" {
+		return dir, err
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current directory: %w", err)
+	}
+	return cwd, nil
+}
+
+// explicitProjectDir returns the absolute project directory named by
+// --project-dir or SHIPYARD_PROJECT_DIR, or "" when neither is set
+func explicitProjectDir() (string, error) {
+	dir := projectDir
+	source := "--project-dir"
+	if dir == "" {
+		dir = os.Getenv(ProjectDirEnv)
+		source = ProjectDirEnv
+	}
+	if dir == "" {
+		return "", nil
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s %s: %w", source, dir, err)
+	}
+	info, err := os.Stat(abs)
+	if err != nil || !info.IsDir() {
+		return "", fmt.Errorf("%s %s is not a directory", source, dir)
+	}
+	return abs, nil
+}
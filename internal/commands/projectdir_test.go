@@ -0,0 +1,124 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NatoNathan/shipyard/internal/consignment"
+	"github.com/NatoNathan/shipyard/internal/history"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAddCommand_FromSubdirectory tests that add finds the project above the
+// working directory and writes the consignment at its root
+func TestAddCommand_FromSubdirectory(t *testing.T) {
+	tempDir := t.TempDir()
+	initGitRepo(t, tempDir)
+	initShipyardConfig(t, tempDir)
+	nested := filepath.Join(tempDir, "src", "internal")
+	require.NoError(t, os.MkdirAll(nested, 0755))
+	defer changeToDir(t, nested)()
+
+	cmd := NewAddCommand()
+	cmd.SetArgs([]string{"-p", "core", "-t", "patch", "-s", "Fixed bug"})
+	captureOutput(func() {
+		require.NoError(t, cmd.Execute())
+	})
+
+	consignments, err := consignment.ReadAllConsignments(filepath.Join(tempDir, ".shipyard", "consignments"))
+	require.NoError(t, err)
+	assert.Len(t, consignments, 1)
+	assert.NoDirExists(t, filepath.Join(nested, ".shipyard"))
+}
+
+// TestVersionCommand_FromSubdirectory tests that a release run from a
+// package directory updates the files the config names from the project root
+func TestVersionCommand_FromSubdirectory(t *testing.T) {
+	tempDir := setupVersionTestRepo(t)
+	initGitRepo(t, tempDir)
+	createTestConsignmentForVersion(t, filepath.Join(tempDir, ".shipyard", "consignments"), "c1", []string{"test-package"}, "minor", "Add feature")
+	defer changeToDir(t, filepath.Join(tempDir, "test-package"))()
+
+	cmd := NewVersionCommand()
+	cmd.SetArgs([]string{"--no-commit", "--no-tag"})
+	captureOutput(func() {
+		require.NoError(t, cmd.Execute())
+	})
+
+	entries, err := history.ReadHistory(filepath.Join(tempDir, ".shipyard", "history.json"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "1.1.0", entries[0].Version)
+	assert.FileExists(t, filepath.Join(tempDir, "test-package", "CHANGELOG.md"))
+	assert.NoFileExists(t, filepath.Join(tempDir, "test-package", "test-package", "CHANGELOG.md"))
+	assert.NoDirExists(t, filepath.Join(tempDir, "test-package", ".shipyard"))
+}
+
+// TestProjectRoot tests how the project commands work on is chosen
+func TestProjectRoot(t *testing.T) {
+	project := t.TempDir()
+	initShipyardConfig(t, project)
+	nested := filepath.Join(project, "a", "b")
+	require.NoError(t, os.MkdirAll(nested, 0755))
+	elsewhere := t.TempDir()
+
+	t.Run("nearest project above the working directory", func(t *testing.T) {
+		defer changeToDir(t, nested)()
+		root, err := projectRoot()
+		require.NoError(t, err)
+		assert.Equal(t, evalSymlinks(t, project), evalSymlinks(t, root))
+	})
+
+	t.Run("working directory without a project", func(t *testing.T) {
+		defer changeToDir(t, elsewhere)()
+		root, err := projectRoot()
+		require.NoError(t, err)
+		assert.Equal(t, evalSymlinks(t, elsewhere), evalSymlinks(t, root))
+	})
+
+	t.Run("project-dir flag", func(t *testing.T) {
+		defer changeToDir(t, elsewhere)()
+		SetProjectDir(project)
+		defer SetProjectDir("")
+		t.Setenv(ProjectDirEnv, nested)
+
+		root, err := projectRoot()
+		require.NoError(t, err)
+		assert.Equal(t, project, root)
+	})
+
+	t.Run("environment variable", func(t *testing.T) {
+		defer changeToDir(t, elsewhere)()
+		t.Setenv(ProjectDirEnv, project)
+
+		root, err := projectRoot()
+		require.NoError(t, err)
+		assert.Equal(t, project, root)
+	})
+
+	t.Run("init ignores projects above", func(t *testing.T) {
+		defer changeToDir(t, nested)()
+		dir, err := initProjectDir()
+		require.NoError(t, err)
+		assert.Equal(t, evalSymlinks(t, nested), evalSymlinks(t, dir))
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		SetProjectDir(filepath.Join(elsewhere, "missing"))
+		defer SetProjectDir("")
+
+		_, err := projectRoot()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--project-dir")
+		assert.Contains(t, err.Error(), "is not a directory")
+	})
+}
+
+func evalSymlinks(t *testing.T, path string) string {
+	t.Helper()
+	resolved, err := filepath.EvalSymlinks(path)
+	require.NoError(t, err)
+	return resolved
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/NatoNathan/shipyard/internal/git"
 	"github.com/NatoNathan/shipyard/internal/graph"
 	"github.com/NatoNathan/shipyard/internal/prerelease"
+	"github.com/NatoNathan/shipyard/internal/release"
 	"github.com/NatoNathan/shipyard/internal/template"
 	"github.com/NatoNathan/shipyard/internal/ui"
 	"github.com/NatoNathan/shipyard/internal/version"
@@ -89,9 +90,9 @@ At the highest stage, returns an error—use 'shipyard version' to promote to st
 }
 
 func runPromote(opts *PromoteCommandOptions) error {
-	cwd, err := os.Getwd()
+	cwd, err := projectRoot()
 	if err != nil {
-		return fmt.Errorf("failed to get current directory: %w", err)
+		return err
 	}
 	return runPromoteWithDir(cwd, opts)
 }
@@ -108,6 +109,7 @@ func runPromoteWithDir(projectPath string, opts *PromoteCommandOptions) error {
 	if err != nil {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
+	settings := release.SettingsFor(projectPath, cfg)
 
 	if len(cfg.PreRelease.Stages) == 0 {
 		return fmt.Errorf("no pre-release stages defined in configuration")
@@ -148,7 +150,7 @@ func runPromoteWithDir(projectPath string, opts *PromoteCommandOptions) error {
 		return fmt.Errorf("failed to build dependency graph: %w", err)
 	}
 
-	currentVersions, err := ReadAllCurrentVersions(projectPath, cfg)
+	currentVersions, err := release.ReadAllCurrentVersions(projectPath, cfg, settings)
 	if err != nil {
 		return err
 	}
@@ -171,6 +173,7 @@ func runPromoteWithDir(projectPath string, opts *PromoteCommandOptions) error {
 
 	// 4. For each package with state, determine next stage
 	renderer := template.NewTemplateRenderer()
+	renderer.SetRepository(settings.Repository)
 	type promoteResult struct {
 		pkg           string
 		oldVersion    semver.Version
@@ -240,10 +243,11 @@ func runPromoteWithDir(projectPath string, opts *PromoteCommandOptions) error {
 			tagTemplate = "v{{.Version}}-{{.Stage}}.{{.Counter}}"
 		}
 		tagCtx := map[string]interface{}{
-			"Version": targetVersion,
-			"Counter": counter,
-			"Package": pkgName,
-			"Stage":   nextStage.Name,
+			"Version":    targetVersion,
+			"VersionTag": "v" + targetVersion,
+			"Counter":    counter,
+			"Package":    pkgName,
+			"Stage":      nextStage.Name,
 		}
 		tagName, err := renderer.Render(tagTemplate, tagCtx)
 		if err != nil {
@@ -308,7 +312,7 @@ func runPromoteWithDir(projectPath string, opts *PromoteCommandOptions) error {
 			return fmt.Errorf("package %s not found in configuration", r.pkg)
 		}
 		pkgPath := filepath.Join(projectPath, pkg.Path)
-		handler, err := GetEcosystemHandler(pkg, pkgPath)
+		handler, err := release.GetEcosystemHandler(pkg, pkgPath)
 		if err != nil {
 			return err
 		}
@@ -354,7 +358,7 @@ func runPromoteWithDir(projectPath string, opts *PromoteCommandOptions) error {
 		for _, r := range results {
 			changedPackages[r.pkg] = true
 		}
-		filesToStage, err := CollectVersionFiles(projectPath, cfg, changedPackages)
+		filesToStage, err := release.CollectVersionFiles(projectPath, cfg, changedPackages)
 		if err != nil {
 			return err
 		}
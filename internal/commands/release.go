@@ -9,8 +9,11 @@ import (
 	"github.com/NatoNathan/shipyard/internal/config"
 	"github.com/NatoNathan/shipyard/internal/github"
 	"github.com/NatoNathan/shipyard/internal/history"
+	"github.com/NatoNathan/shipyard/internal/httpclient"
+	"github.com/NatoNathan/shipyard/internal/release"
 	"github.com/NatoNathan/shipyard/internal/template"
 	"github.com/NatoNathan/shipyard/internal/ui"
+	"github.com/NatoNathan/shipyard/internal/upgrade"
 	"github.com/NatoNathan/shipyard/pkg/semver"
 	"github.com/spf13/cobra"
 )
@@ -34,7 +37,9 @@ type ReleasePublisher interface {
 type ReleasePublisherFactory func(repoPath string, cfg *config.Config) ReleasePublisher
 
 var newReleasePublisher ReleasePublisherFactory = func(repoPath string, cfg *config.Config) ReleasePublisher {
-	return github.NewReleasePublisher(repoPath, cfg)
+	client := upgrade.NewGitHubClient()
+	client.SetTransport(httpclient.TransportFor(cfg.Remote.TLSOptions(repoPath)))
+	return github.NewReleasePublisherWithClient(repoPath, cfg, client)
 }
 
 // NewReleaseCommand creates the release command
@@ -70,7 +75,7 @@ Run 'shipyard version' first to create version tags, then push them with 'git pu
 	cmd.Flags().StringVarP(&opts.Package, "package", "p", "", "Package to release (required for multi-package repos)")
 	cmd.Flags().BoolVar(&opts.Draft, "draft", false, "Create as draft release")
 	cmd.Flags().BoolVar(&opts.Prerelease, "prerelease", false, "Mark as prerelease")
-	cmd.Flags().StringVar(&opts.Tag, "tag", "", "Use specific tag instead of latest for package")
+	cmd.Flags().StringVar(&opts.Tag, "tag", "", "Use specific tag or version instead of latest for package")
 
 	// Register package name completion
 	RegisterPackageCompletions(cmd, "package")
@@ -81,9 +86,9 @@ Run 'shipyard version' first to create version tags, then push them with 'git pu
 // runRelease executes the release command
 func runRelease(opts *ReleaseOptions) error {
 	// Get current directory
-	cwd, err := os.Getwd()
+	cwd, err := projectRoot()
 	if err != nil {
-		return fmt.Errorf("failed to get current directory: %w", err)
+		return err
 	}
 
 	// Load configuration
@@ -91,6 +96,7 @@ func runRelease(opts *ReleaseOptions) error {
 	if err != nil {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
+	settings := release.SettingsFor(cwd, cfg)
 
 	// Verify GitHub configuration
 	if cfg.GitHub.Owner == "" || cfg.GitHub.Repo == "" {
@@ -113,14 +119,13 @@ func runRelease(opts *ReleaseOptions) error {
 	}
 
 	// Read history to find latest entry for package
-	historyPath := filepath.Join(cwd, cfg.History.Path)
-	entries, err := history.ReadHistory(historyPath)
+	entries, err := settings.History(filepath.Join(cwd, cfg.History.Path)).Read()
 	if err != nil {
 		return fmt.Errorf("failed to read history: %w", err)
 	}
 
 	// Filter by package
-	entries = history.FilterByPackage(entries, opts.Package)
+	entries = release.PackageEntries(cfg, entries, opts.Package)
 	if len(entries) == 0 {
 		return fmt.Errorf("no releases found for package %s", opts.Package)
 	}
@@ -137,6 +142,15 @@ func runRelease(opts *ReleaseOptions) error {
 				break
 			}
 		}
+		// Fall back to the version, so "v1.0.0" finds a release tagged "core/v1.0.0"
+		if !found {
+			if version, err := ParseVersionArg(opts.Tag); err == nil {
+				if matched := history.FilterByVersion(entries, version); len(matched) > 0 {
+					selectedEntry = matched[len(matched)-1]
+					found = true
+				}
+			}
+		}
 		if !found {
 			return fmt.Errorf("tag %s not found in history", opts.Tag)
 		}
@@ -146,14 +160,15 @@ func runRelease(opts *ReleaseOptions) error {
 		selectedEntry = entries[0]
 	}
 
-	// Parse version
-	version, err := semver.Parse(selectedEntry.Version)
+	// Parse version using the package's versioning scheme
+	pkg, _ := cfg.GetPackage(opts.Package)
+	version, err := pkg.ParseVersion(selectedEntry.Version)
 	if err != nil {
 		return fmt.Errorf("failed to parse version %s: %w", selectedEntry.Version, err)
 	}
 
 	// Generate release notes from history entry
-	releaseNotes, err := template.RenderReleaseNotes([]history.Entry{selectedEntry})
+	releaseNotes, err := template.RenderReleaseNotesWithOptions(release.ChangelogEntriesFor(cfg, []history.Entry{selectedEntry}), "builtin:default", settings.RenderOptions())
 	if err != nil {
 		return fmt.Errorf("failed to generate release notes: %w", err)
 	}
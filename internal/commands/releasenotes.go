@@ -2,8 +2,10 @@ package commands
 
 import (
 	"fmt"
+	"github.com/NatoNathan/shipyard/internal/release"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/NatoNathan/shipyard/internal/fileutil"
@@ -20,10 +22,12 @@ type ReleaseNotesOptions struct {
 	Output         string
 	Version        string
 	AllVersions    bool
+	Latest         bool // Notes for the most recent release, made explicit for scripts
 	MetadataFilter []string
 	Template       string
-	JSON           bool // Output in JSON format
-	Quiet          bool // Suppress output
+	Format         string // Rendered notes format: markdown or plain
+	JSON           bool   // Output in JSON format
+	Quiet          bool   // Suppress output
 }
 
 // NewReleaseNotesCommand creates the release-notes command
@@ -31,9 +35,9 @@ func NewReleaseNotesCommand() *cobra.Command {
 	opts := &ReleaseNotesOptions{}
 
 	cmd := &cobra.Command{
-		Use:                   "release-notes [-p package] [-o file] [--version version | --all-versions]",
+		Use:                   "release-notes [-p package] [-o file] [--version version | --latest | --all-versions]",
 		DisableFlagsInUseLine: true,
-		Aliases:               []string{"notes", "changelog"},
+		Aliases:               []string{"notes"},
 		Short:                 "Tell the tale of your voyage",
 		Long: `Recount the journey from the captain's log. Transforms version history into
 tales of ports visited and cargo delivered. Filter by vessel or destination,
@@ -41,11 +45,17 @@ write to parchment (file) or speak aloud (stdout).`,
 		Example: `  # Show release notes for latest version
   shipyard release-notes --package core
 
+  # Show release notes for a past release across all packages
+  shipyard release-notes --version 1.2.0
+
   # Write release notes to file
   shipyard release-notes --output NOTES.md
 
   # Show complete version history
-  shipyard release-notes --all-versions`,
+  shipyard release-notes --all-versions
+
+  # Plain text notes for a commit message
+  shipyard release-notes --version 1.2.0 --format plain`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Extract global flags
 			globalFlags := GetGlobalFlags(cmd)
@@ -58,9 +68,11 @@ write to parchment (file) or speak aloud (stdout).`,
 	cmd.Flags().StringVarP(&opts.Package, "package", "p", "", "Filter by package name (required for multi-package repos)")
 	cmd.Flags().StringVarP(&opts.Output, "output", "o", "", "Output file (default: stdout)")
 	cmd.Flags().StringVar(&opts.Version, "version", "", "Generate notes for specific version")
+	cmd.Flags().BoolVar(&opts.Latest, "latest", false, "Generate notes for the most recent release")
 	cmd.Flags().BoolVar(&opts.AllVersions, "all-versions", false, "Show complete history instead of just latest version")
 	cmd.Flags().StringArrayVar(&opts.MetadataFilter, "filter", []string{}, "Filter by custom metadata (format: key=value, can be repeated)")
 	cmd.Flags().StringVar(&opts.Template, "template", "", "Template to use (path or builtin name)")
+	cmd.Flags().StringVar(&opts.Format, "format", "markdown", "Rendered notes format: markdown or plain")
 
 	// Register package name completion
 	RegisterPackageCompletions(cmd, "package")
@@ -70,10 +82,19 @@ write to parchment (file) or speak aloud (stdout).`,
 
 // runReleaseNotes executes the release notes generation
 func runReleaseNotes(opts *ReleaseNotesOptions) error {
+	if opts.Latest && (opts.Version != "" || opts.AllVersions) {
+		return fmt.Errorf("--latest cannot be combined with --version or --all-versions")
+	}
+	switch opts.Format {
+	case "", "markdown", "plain":
+	default:
+		return fmt.Errorf("invalid --format %q (must be markdown or plain)", opts.Format)
+	}
+
 	// Get current directory
-	cwd, err := os.Getwd()
+	cwd, err := projectRoot()
 	if err != nil {
-		return fmt.Errorf("failed to get current directory: %w", err)
+		return err
 	}
 
 	// Load configuration
@@ -81,10 +102,16 @@ func runReleaseNotes(opts *ReleaseNotesOptions) error {
 	if err != nil {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
+	settings := release.SettingsFor(cwd, cfg)
 
 	// Read history
-	historyPath := filepath.Join(cwd, cfg.History.Path)
-	entries, err := history.ReadHistory(historyPath)
+	historyFile := settings.History(filepath.Join(cwd, cfg.History.Path))
+	readHistory := historyFile.Read
+	if opts.AllVersions || opts.Version != "" {
+		// Past versions may have been compacted into the yearly archives
+		readHistory = historyFile.ReadWithArchives
+	}
+	entries, err := readHistory()
 	if err != nil {
 		if !os.IsNotExist(err) {
 			return fmt.Errorf("failed to read history: %w", err)
@@ -121,8 +148,9 @@ func runReleaseNotes(opts *ReleaseNotesOptions) error {
 		return nil
 	}
 
-	// Require --package for multi-package repos
-	if len(cfg.Packages) > 1 && opts.Package == "" {
+	// Require --package for multi-package repos, except for a specific version,
+	// which covers every package released at that version
+	if len(cfg.Packages) > 1 && opts.Package == "" && opts.Version == "" {
 		return fmt.Errorf("--package is required for multi-package repositories")
 	}
 
@@ -133,7 +161,20 @@ func runReleaseNotes(opts *ReleaseNotesOptions) error {
 
 	// Filter by package
 	if opts.Package != "" {
-		entries = history.FilterByPackage(entries, opts.Package)
+		entries = release.PackageEntries(cfg, entries, opts.Package)
+	}
+
+	// Resolve the requested version before metadata filters can hide it
+	if opts.Version != "" {
+		version, err := ParseVersionArg(opts.Version)
+		if err != nil {
+			return err
+		}
+		matched := history.FilterByVersion(entries, version)
+		if len(matched) == 0 {
+			return versionNotFoundError(entries, version, opts.Package)
+		}
+		entries = matched
 	}
 
 	// Filter by custom metadata (validate against config)
@@ -152,10 +193,8 @@ func runReleaseNotes(opts *ReleaseNotesOptions) error {
 		entries = history.FilterConsignmentsByMetadata(entries, key, value)
 	}
 
-	// Filter by version or default to latest
-	if opts.Version != "" {
-		entries = history.FilterByVersion(entries, opts.Version)
-	} else if !opts.AllVersions {
+	// Default to latest unless a version or full history was requested
+	if opts.Version == "" && !opts.AllVersions {
 		// Default: show only latest version
 		entries = history.SortByTimestamp(entries, true) // newest first
 		if len(entries) > 0 {
@@ -196,17 +235,35 @@ func runReleaseNotes(opts *ReleaseNotesOptions) error {
 		return PrintJSON(os.Stdout, jsonData)
 	}
 
-	// Render using the appropriate mode: changelog (all versions) or release-notes (single version)
+	// Render using the appropriate mode: changelog (all versions) or release-notes (single version).
+	// JSON output above keeps excluded change types; rendered notes drop them.
+	entries = release.ChangelogEntriesFor(cfg, entries)
 	var notes string
 	var renderErr error
 	if opts.AllVersions {
-		notes, renderErr = template.RenderChangelogWithTemplate(entries, templateType)
+		notes, renderErr = template.RenderChangelogWithOptions(entries, templateType, settings.RenderOptions())
+	} else if len(entries) > 1 {
+		// A release-wide version renders one set of notes per package
+		sort.SliceStable(entries, func(i, j int) bool { return entries[i].Package < entries[j].Package })
+		sections := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			section, err := template.RenderReleaseNotesWithOptions([]history.Entry{entry}, templateType, settings.RenderOptions())
+			if err != nil {
+				renderErr = err
+				break
+			}
+			sections = append(sections, section)
+		}
+		notes = strings.Join(sections, "\n")
 	} else {
-		notes, renderErr = template.RenderReleaseNotesWithTemplate(entries, templateType)
+		notes, renderErr = template.RenderReleaseNotesWithOptions(entries, templateType, settings.RenderOptions())
 	}
 	if renderErr != nil {
 		return fmt.Errorf("failed to render release notes: %w", renderErr)
 	}
+	if opts.Format == "plain" {
+		notes = template.PlainText(notes)
+	}
 
 	// Output rendered notes
 	if opts.Output != "" {
@@ -222,6 +279,27 @@ func runReleaseNotes(opts *ReleaseNotesOptions) error {
 	return nil
 }
 
+// versionNotFoundError reports a missing version with the versions that do exist, newest first
+func versionNotFoundError(entries []history.Entry, version, pkg string) error {
+	scope := "history"
+	if pkg != "" {
+		scope = fmt.Sprintf("history for package %s", pkg)
+	}
+
+	var available []string
+	seen := make(map[string]bool)
+	for _, entry := range history.SortByTimestamp(entries, true) {
+		if !seen[entry.Version] {
+			seen[entry.Version] = true
+			available = append(available, entry.Version)
+		}
+	}
+	if len(available) == 0 {
+		return fmt.Errorf("version %s not found in %s (no releases recorded)", version, scope)
+	}
+	return fmt.Errorf("version %s not found in %s (available: %s)", version, scope, strings.Join(available, ", "))
+}
+
 // validateMetadataFilter checks if metadata key/value are valid per config
 func validateMetadataFilter(cfg *config.Config, key, value string) error {
 	// Find the metadata field definition
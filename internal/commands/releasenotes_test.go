@@ -3,8 +3,11 @@ package commands
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/NatoNathan/shipyard/internal/history"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -47,6 +50,50 @@ func TestReleaseNotesCommand_Basic(t *testing.T) {
 		// Verify: Only core package shown
 		assert.Contains(t, output, "core", "Should show core package")
 	})
+
+	t.Run("includes install instructions for the package ecosystem", func(t *testing.T) {
+		tempDir := setupReleaseNotesTestRepo(t)
+		defer changeToDir(t, tempDir)()
+
+		cmd := NewReleaseNotesCommand()
+		cmd.SetArgs([]string{"--package", "core"})
+
+		output := captureOutput(func() {
+			require.NoError(t, cmd.Execute())
+		})
+
+		assert.Contains(t, output, "## Install")
+		assert.Contains(t, output, "Require `v1.1.0` of the module with `go get`.")
+	})
+
+	t.Run("renders plain text with --format plain", func(t *testing.T) {
+		tempDir := setupReleaseNotesTestRepo(t)
+		defer changeToDir(t, tempDir)()
+
+		cmd := NewReleaseNotesCommand()
+		cmd.SetArgs([]string{"--package", "core", "--format", "plain"})
+
+		output := captureOutput(func() {
+			require.NoError(t, cmd.Execute())
+		})
+
+		assert.Contains(t, output, "Release Notes: core v1.1.0")
+		assert.Contains(t, output, "Require v1.1.0 of the module with go get.")
+		assert.NotContains(t, output, "#")
+		assert.NotContains(t, output, "**")
+		assert.NotContains(t, output, "`")
+	})
+
+	t.Run("rejects an unknown format", func(t *testing.T) {
+		tempDir := setupReleaseNotesTestRepo(t)
+		defer changeToDir(t, tempDir)()
+
+		cmd := NewReleaseNotesCommand()
+		cmd.SetArgs([]string{"--package", "core", "--format", "html"})
+
+		err := cmd.Execute()
+		assert.ErrorContains(t, err, `invalid --format "html" (must be markdown or plain)`)
+	})
 }
 
 // TestReleaseNotesCommand_NoHistory tests when no history exists
@@ -117,6 +164,130 @@ func TestReleaseNotesCommand_UsesConfiguredHistoryPath(t *testing.T) {
 	assert.Contains(t, output, "1.1.0")
 }
 
+// TestReleaseNotesCommand_HistoricalVersion tests rendering notes for a recorded release
+func TestReleaseNotesCommand_HistoricalVersion(t *testing.T) {
+	record := func(t *testing.T, dir string) {
+		t.Helper()
+		require.NoError(t, history.AppendToHistory(filepath.Join(dir, ".shipyard", "history.json"), []history.Entry{
+			{
+				Version:      "1.1.0",
+				Package:      "api",
+				Timestamp:    time.Date(2026, 1, 30, 0, 0, 0, 0, time.UTC),
+				Consignments: []history.Consignment{{ID: "c3", Summary: "Add endpoint", ChangeType: "minor"}},
+			},
+			{
+				Version:      "1.2.0",
+				Package:      "core",
+				Timestamp:    time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+				Consignments: []history.Consignment{{ID: "c4", Summary: "Add streaming", ChangeType: "minor"}},
+			},
+		}))
+	}
+
+	t.Run("renders a past version for a package", func(t *testing.T) {
+		tempDir := setupReleaseNotesTestRepo(t)
+		record(t, tempDir)
+		defer changeToDir(t, tempDir)()
+
+		output := captureOutput(func() {
+			require.NoError(t, runReleaseNotes(&ReleaseNotesOptions{Package: "core", Version: "1.0.1"}))
+		})
+
+		assert.Contains(t, output, "1.0.1")
+		assert.Contains(t, output, "Fix bug")
+		assert.NotContains(t, output, "Add streaming")
+	})
+
+	t.Run("version matches with a v prefix", func(t *testing.T) {
+		tempDir := setupReleaseNotesTestRepo(t)
+		defer changeToDir(t, tempDir)()
+
+		output := captureOutput(func() {
+			require.NoError(t, runReleaseNotes(&ReleaseNotesOptions{Package: "core", Version: "v1.0.1"}))
+		})
+
+		assert.Contains(t, output, "Fix bug")
+	})
+
+	t.Run("bare version matches a v-prefixed history entry", func(t *testing.T) {
+		tempDir := setupReleaseNotesTestRepo(t)
+		require.NoError(t, history.AppendToHistory(filepath.Join(tempDir, ".shipyard", "history.json"), []history.Entry{{
+			Version:      "v1.3.0",
+			Package:      "core",
+			Tag:          "core/v1.3.0",
+			Timestamp:    time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+			Consignments: []history.Consignment{{ID: "c5", Summary: "Add retries", ChangeType: "minor"}},
+		}}))
+		defer changeToDir(t, tempDir)()
+		templatePath := filepath.Join(tempDir, "notes.tmpl")
+		require.NoError(t, os.WriteFile(templatePath, []byte("{{ .VersionTag }}\n"), 0644))
+
+		output := captureOutput(func() {
+			require.NoError(t, runReleaseNotes(&ReleaseNotesOptions{Package: "core", Version: "1.3.0", Template: templatePath}))
+		})
+
+		assert.Equal(t, "core/v1.3.0\n", output)
+	})
+
+	t.Run("renders a version release-wide without --package", func(t *testing.T) {
+		tempDir := setupReleaseNotesTestRepo(t)
+		record(t, tempDir)
+		defer changeToDir(t, tempDir)()
+
+		output := captureOutput(func() {
+			require.NoError(t, runReleaseNotes(&ReleaseNotesOptions{Version: "1.1.0"}))
+		})
+
+		assert.Contains(t, output, "Add endpoint")
+		assert.Contains(t, output, "Add new feature")
+		assert.Less(t, strings.Index(output, "Add endpoint"), strings.Index(output, "Add new feature"), "packages render in name order")
+	})
+
+	t.Run("latest picks the most recent release", func(t *testing.T) {
+		tempDir := setupReleaseNotesTestRepo(t)
+		record(t, tempDir)
+		defer changeToDir(t, tempDir)()
+
+		output := captureOutput(func() {
+			require.NoError(t, runReleaseNotes(&ReleaseNotesOptions{Package: "core", Latest: true}))
+		})
+
+		assert.Contains(t, output, "Add streaming")
+		assert.NotContains(t, output, "Add new feature")
+	})
+
+	t.Run("latest conflicts with version", func(t *testing.T) {
+		err := runReleaseNotes(&ReleaseNotesOptions{Latest: true, Version: "1.0.0"})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--latest cannot be combined")
+	})
+
+	t.Run("missing version lists available versions", func(t *testing.T) {
+		tempDir := setupReleaseNotesTestRepo(t)
+		record(t, tempDir)
+		defer changeToDir(t, tempDir)()
+
+		err := runReleaseNotes(&ReleaseNotesOptions{Package: "core", Version: "9.9.9"})
+
+		require.Error(t, err)
+		assert.EqualError(t, err, "version 9.9.9 not found in history for package core (available: 1.2.0, 1.1.0, 1.0.1)")
+	})
+
+	t.Run("template override", func(t *testing.T) {
+		tempDir := setupReleaseNotesTestRepo(t)
+		defer changeToDir(t, tempDir)()
+		templatePath := filepath.Join(tempDir, "notes.tmpl")
+		require.NoError(t, os.WriteFile(templatePath, []byte("{{ .Package }}@{{ .Version }}\n"), 0644))
+
+		output := captureOutput(func() {
+			require.NoError(t, runReleaseNotes(&ReleaseNotesOptions{Package: "core", Version: "1.0.1", Template: templatePath}))
+		})
+
+		assert.Equal(t, "core@1.0.1\n", output)
+	})
+}
+
 // Helper functions
 
 func setupReleaseNotesTestRepo(t *testing.T) string {
@@ -186,3 +357,60 @@ func setupMinimalShipyardDir(t *testing.T, dir string) {
 `
 	require.NoError(t, os.WriteFile(filepath.Join(shipyardDir, "shipyard.yaml"), []byte(configContent), 0644))
 }
+
+// TestReleaseNotesCommand_Audience tests audience-grouped notes for a release
+// spanning several packages
+func TestReleaseNotesCommand_Audience(t *testing.T) {
+	tempDir := t.TempDir()
+	shipyardDir := filepath.Join(tempDir, ".shipyard")
+	require.NoError(t, os.MkdirAll(shipyardDir, 0755))
+
+	configContent := `packages:
+  - name: core
+    path: ./core
+    ecosystem: go
+  - name: api
+    path: ./api
+    ecosystem: go
+changeTypes:
+  - name: patch
+    audience: internal
+`
+	require.NoError(t, os.WriteFile(filepath.Join(shipyardDir, "shipyard.yaml"), []byte(configContent), 0644))
+
+	entries := []history.Entry{
+		{Version: "2.0.0", Package: "core", Timestamp: time.Date(2026, 1, 30, 0, 0, 0, 0, time.UTC), Consignments: []history.Consignment{
+			{ID: "c1", Summary: "Add dark mode", ChangeType: "minor"},
+			{ID: "c2", Summary: "Bump CI runners", ChangeType: "patch"},
+		}},
+		{Version: "2.0.0", Package: "api", Timestamp: time.Date(2026, 1, 30, 0, 0, 0, 0, time.UTC), Consignments: []history.Consignment{
+			{ID: "c3", Summary: "Fix login redirect", ChangeType: "patch", Metadata: map[string]interface{}{"audience": "user"}},
+			{ID: "c4", Summary: "Rewrite queue workers", ChangeType: "minor", Metadata: map[string]interface{}{"audience": "internal"}},
+		}},
+	}
+	historyPath := filepath.Join(shipyardDir, "history.json")
+	require.NoError(t, os.WriteFile(historyPath, []byte("[]"), 0644))
+	require.NoError(t, history.AppendToHistory(historyPath, entries))
+	defer changeToDir(t, tempDir)()
+
+	cmd := NewReleaseNotesCommand()
+	cmd.SetArgs([]string{"--version", "2.0.0", "--template", "builtin:audience"})
+	output := captureOutput(func() {
+		require.NoError(t, cmd.Execute())
+	})
+
+	api, core, found := strings.Cut(output, "# Release Notes: core v2.0.0")
+	require.True(t, found, output)
+	for _, section := range []struct {
+		notes, user, internal string
+	}{
+		{api, "Fix login redirect", "Rewrite queue workers"},
+		{core, "Add dark mode", "Bump CI runners"},
+	} {
+		user, internal, found := strings.Cut(section.notes, "## Internal Changes")
+		require.True(t, found, section.notes)
+		assert.Contains(t, user, "## What's New")
+		assert.Contains(t, user, section.user)
+		assert.Contains(t, internal, section.internal)
+	}
+}
@@ -2,11 +2,17 @@ package commands
 
 import (
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/NatoNathan/shipyard/internal/config"
 	"github.com/NatoNathan/shipyard/internal/consignment"
+	"github.com/NatoNathan/shipyard/internal/history"
+	"github.com/NatoNathan/shipyard/internal/prompt"
+	"github.com/NatoNathan/shipyard/internal/runstate"
 	"github.com/NatoNathan/shipyard/internal/ui"
 	"github.com/spf13/cobra"
 )
@@ -15,14 +21,28 @@ import (
 type RemoveCommandOptions struct {
 	IDs   []string
 	All   bool
+	Yes   bool
 	JSON  bool
 	Quiet bool
+
+	// Confirm asks the user to go ahead with the removal. Nil with --yes,
+	// --json or when stdin is not a terminal, where nothing is asked.
+	Confirm func(message string) (bool, error)
 }
 
 // RemoveOutput is the JSON output structure for remove command
 type RemoveOutput struct {
-	Removed []string `json:"removed"`
-	Count   int      `json:"count"`
+	Removed []string           `json:"removed"`
+	Count   int                `json:"count"`
+	Bumps   []RemoveBumpChange `json:"bumps,omitempty"`
+}
+
+// RemoveBumpChange is a pending bump that changes when consignments are
+// removed. To is empty when the package would no longer be released.
+type RemoveBumpChange struct {
+	Package string `json:"package"`
+	From    string `json:"from"`
+	To      string `json:"to,omitempty"`
 }
 
 // NewRemoveCommand creates the remove command
@@ -30,45 +50,58 @@ func NewRemoveCommand() *cobra.Command {
 	opts := &RemoveCommandOptions{}
 
 	cmd := &cobra.Command{
-		Use:                   "remove {--id id... | --all}",
+		Use:                   "remove {id... | --id id... | --all} [--yes]",
 		DisableFlagsInUseLine: true,
 		Aliases:               []string{"rm", "delete"},
-		Short:   "Jettison cargo from the manifest",
+		ValidArgsFunction:     completeConsignments,
+		Short:                 "Jettison cargo from the manifest",
 		Long: `Remove one or more pending consignments from the manifest.
 
-Use --id to remove specific consignments by ID, or --all to remove all pending consignments.`,
+Pass consignment IDs as arguments or with --id, or use --all to remove all
+pending consignments. The consignments and the pending bumps that would no
+longer happen are shown before you confirm; --yes skips the confirmation,
+which is also not asked when stdin is not a terminal.
+
+Consignments that an interrupted version run is releasing cannot be removed,
+and nothing is removed while another version run is writing the history.`,
 		Example: `  # Remove specific consignment(s)
+  shipyard remove 20240101-120000-abc123
   shipyard remove --id 20240101-120000-abc123
 
-  # Remove all pending consignments
-  shipyard remove --all`,
+  # Remove all pending consignments without asking
+  shipyard remove --all --yes`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			globalFlags := GetGlobalFlags(cmd)
+			opts.IDs = append(opts.IDs, args...)
 			opts.JSON = globalFlags.JSON
 			opts.Quiet = globalFlags.Quiet
+			if !opts.Yes && !opts.JSON && prompt.Interactive() {
+				opts.Confirm = func(message string) (bool, error) {
+					return prompt.PromptConfirm(message, false)
+				}
+			}
 			return runRemove(opts)
 		},
 	}
 
 	cmd.Flags().StringSliceVar(&opts.IDs, "id", nil, "Consignment ID(s) to remove")
 	cmd.Flags().BoolVar(&opts.All, "all", false, "Remove all pending consignments")
+	cmd.Flags().BoolVarP(&opts.Yes, "yes", "y", false, "Remove without asking for confirmation")
+
+	RegisterConsignmentCompletions(cmd, "id")
 
 	return cmd
 }
 
 func runRemove(opts *RemoveCommandOptions) error {
-	cwd, err := os.Getwd()
+	cwd, err := projectRoot()
 	if err != nil {
-		return fmt.Errorf("failed to get current directory: %w", err)
+		return err
 	}
 	return runRemoveWithDir(cwd, opts)
 }
 
 func runRemoveWithDir(projectPath string, opts *RemoveCommandOptions) error {
-	if !opts.All && len(opts.IDs) == 0 {
-		return fmt.Errorf("specify --id or --all to remove consignments")
-	}
-
 	// Load configuration to get consignments path
 	cfg, err := config.LoadFromDir(projectPath)
 	if err != nil {
@@ -81,47 +114,94 @@ func runRemoveWithDir(projectPath string, opts *RemoveCommandOptions) error {
 	}
 	consignmentsDir := filepath.Join(projectPath, consignmentsPath)
 
-	var removedIDs []string
+	pending, err := consignment.ReadAllConsignments(consignmentsDir)
+	if err != nil {
+		return fmt.Errorf("failed to read consignments: %w", err)
+	}
 
-	if opts.All {
-		// Read all consignments
-		allConsignments, err := consignment.ReadAllConsignments(consignmentsDir)
-		if err != nil {
-			return fmt.Errorf("failed to read consignments: %w", err)
+	if !opts.All && len(opts.IDs) == 0 {
+		if len(pending) == 0 {
+			return fmt.Errorf("specify --id or --all to remove consignments; there are no pending consignments")
 		}
-
-		if len(allConsignments) == 0 {
-			if !opts.Quiet && !opts.JSON {
-				fmt.Println("No pending consignments to remove")
-			}
-			if opts.JSON {
-				return PrintJSON(os.Stdout, RemoveOutput{Removed: []string{}, Count: 0})
-			}
-			return nil
+		ids := make([]string, len(pending))
+		for i, c := range pending {
+			ids[i] = c.ID
 		}
+		return fmt.Errorf("specify --id or --all to remove consignments; pending: %s", strings.Join(ids, ", "))
+	}
 
-		for _, c := range allConsignments {
-			filePath := filepath.Join(consignmentsDir, c.ID+".md")
-			if err := os.Remove(filePath); err != nil {
-				return fmt.Errorf("failed to remove consignment %s: %w", c.ID, err)
-			}
-			removedIDs = append(removedIDs, c.ID)
+	var ids []string
+	if opts.All {
+		for _, c := range pending {
+			ids = append(ids, c.ID)
 		}
 	} else {
 		for _, id := range opts.IDs {
-			filePath := filepath.Join(consignmentsDir, id+".md")
-			if _, err := os.Stat(filePath); os.IsNotExist(err) {
+			if slices.Contains(ids, id) {
+				continue
+			}
+			if err := consignment.ValidateID(id); err != nil {
+				return err
+			}
+			if _, err := os.Stat(filepath.Join(consignmentsDir, id+".md")); os.IsNotExist(err) {
 				return fmt.Errorf("consignment not found: %s", id)
 			}
-			if err := os.Remove(filePath); err != nil {
-				return fmt.Errorf("failed to remove consignment %s: %w", id, err)
+			ids = append(ids, id)
+		}
+	}
+
+	if len(ids) == 0 {
+		if !opts.Quiet && !opts.JSON {
+			fmt.Println("No pending consignments to remove")
+		}
+		if opts.JSON {
+			return PrintJSON(os.Stdout, RemoveOutput{Removed: []string{}, Count: 0})
+		}
+		return nil
+	}
+
+	if err := checkRemoveAllowed(projectPath, cfg, consignmentsDir, ids); err != nil {
+		return err
+	}
+
+	removing := slices.DeleteFunc(slices.Clone(pending), func(c *consignment.Consignment) bool {
+		return !slices.Contains(ids, c.ID)
+	})
+	remaining := slices.DeleteFunc(slices.Clone(pending), func(c *consignment.Consignment) bool {
+		return slices.Contains(ids, c.ID)
+	})
+	bumps := removedBumpChanges(pending, remaining)
+
+	if !opts.Quiet && !opts.JSON {
+		printRemovalPlan(ids, removing, bumps)
+	}
+
+	if opts.Confirm != nil {
+		ok, err := opts.Confirm(fmt.Sprintf("Remove %d consignment(s)?", len(ids)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			if !opts.Quiet {
+				fmt.Println("Cancelled; no consignments were removed")
 			}
-			removedIDs = append(removedIDs, id)
+			return nil
 		}
 	}
 
+	removedIDs, removeErr := consignment.RemoveConsignments(consignmentsDir, ids)
+	if removeErr != nil && len(removedIDs) == 0 {
+		return removeErr
+	}
+	if removedIDs == nil {
+		removedIDs = []string{}
+	}
+
 	if opts.JSON {
-		return PrintJSON(os.Stdout, RemoveOutput{Removed: removedIDs, Count: len(removedIDs)})
+		if err := PrintJSON(os.Stdout, RemoveOutput{Removed: removedIDs, Count: len(removedIDs), Bumps: bumps}); err != nil {
+			return err
+		}
+		return removeErr
 	}
 
 	if !opts.Quiet {
@@ -133,5 +213,79 @@ func runRemoveWithDir(projectPath string, opts *RemoveCommandOptions) error {
 		fmt.Println()
 	}
 
+	return removeErr
+}
+
+// checkRemoveAllowed refuses to remove consignments an interrupted version
+// run is releasing, or anything while a version run is writing the history
+func checkRemoveAllowed(projectPath string, cfg *config.Config, consignmentsDir string, ids []string) error {
+	if history.Locked(filepath.Join(projectPath, cfg.History.Path)) {
+		return fmt.Errorf("a version run is in progress (%s is locked); remove consignments once it finishes", cfg.History.Path)
+	}
+
+	statePath := runstate.Path(projectPath)
+	if !runstate.Exists(statePath) {
+		return nil
+	}
+	run, err := runstate.Read(statePath)
+	if err != nil {
+		return err
+	}
+	for _, path := range run.Consignments {
+		abs := filepath.Join(projectPath, filepath.FromSlash(path))
+		if filepath.Dir(abs) != consignmentsDir {
+			continue
+		}
+		id := strings.TrimSuffix(filepath.Base(abs), ".md")
+		if slices.Contains(ids, id) {
+			return fmt.Errorf("consignment %s is part of an interrupted version run; run `shipyard version --resume` to finish it or `shipyard version --abort-run` to roll it back first", id)
+		}
+	}
 	return nil
 }
+
+// removedBumpChanges compares the direct bumps of the pending consignments
+// with those left after the removal, sorted by package
+func removedBumpChanges(pending, remaining []*consignment.Consignment) []RemoveBumpChange {
+	before := consignment.CalculateDirectBumps(pending)
+	after := consignment.CalculateDirectBumps(remaining)
+
+	var changes []RemoveBumpChange
+	for _, name := range slices.Sorted(maps.Keys(before)) {
+		if before[name] == after[name] {
+			continue
+		}
+		changes = append(changes, RemoveBumpChange{Package: name, From: string(before[name]), To: string(after[name])})
+	}
+	return changes
+}
+
+// printRemovalPlan lists the consignments to remove and the pending bumps
+// that change without them
+func printRemovalPlan(ids []string, removing []*consignment.Consignment, bumps []RemoveBumpChange) {
+	fmt.Println()
+	fmt.Println(ui.Section(fmt.Sprintf("Removing %d consignment(s)", len(ids))))
+	for _, id := range ids {
+		index := slices.IndexFunc(removing, func(c *consignment.Consignment) bool { return c.ID == id })
+		if index < 0 {
+			fmt.Printf("  - %s\n", id)
+			continue
+		}
+		c := removing[index]
+		summary, _, _ := strings.Cut(c.Summary, "\n")
+		fmt.Printf("  - %s [%s] %s: %s\n", c.ID, c.ChangeType, strings.Join(c.Packages, ", "), summary)
+	}
+
+	if len(bumps) > 0 {
+		fmt.Println()
+		fmt.Println(ui.Section("Pending bumps that change"))
+		for _, bump := range bumps {
+			to := bump.To
+			if to == "" {
+				to = "no release"
+			}
+			fmt.Printf("  - %s: %s -> %s\n", bump.Package, bump.From, to)
+		}
+	}
+	fmt.Println()
+}
@@ -5,11 +5,13 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/gofrs/flock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
 
 	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/runstate"
 )
 
 func setupRemoveTestProject(t *testing.T) string {
@@ -125,3 +127,67 @@ func TestRemove_AllEmpty(t *testing.T) {
 	err := runRemoveWithDir(dir, opts)
 	assert.NoError(t, err)
 }
+
+func TestRemove_Confirmation(t *testing.T) {
+	t.Run("declined", func(t *testing.T) {
+		dir := setupRemoveTestProject(t)
+
+		var asked string
+		output := captureOutput(func() {
+			require.NoError(t, runRemoveWithDir(dir, &RemoveCommandOptions{
+				IDs: []string{"20240102-120000-bbb222"},
+				Confirm: func(message string) (bool, error) {
+					asked = message
+					return false, nil
+				},
+			}))
+		})
+
+		assert.Equal(t, "Remove 1 consignment(s)?", asked)
+		assert.Contains(t, output, "20240102-120000-bbb222 [minor] my-pkg: Add a feature")
+		assert.Contains(t, output, "my-pkg: minor -> patch")
+		assert.FileExists(t, filepath.Join(dir, ".shipyard", "consignments", "20240102-120000-bbb222.md"))
+	})
+
+	t.Run("accepted", func(t *testing.T) {
+		dir := setupRemoveTestProject(t)
+
+		output := captureOutput(func() {
+			require.NoError(t, runRemoveWithDir(dir, &RemoveCommandOptions{
+				All:     true,
+				Confirm: func(string) (bool, error) { return true, nil },
+			}))
+		})
+
+		assert.Contains(t, output, "my-pkg: minor -> no release")
+		assert.NoFileExists(t, filepath.Join(dir, ".shipyard", "consignments", "20240101-120000-aaa111.md"))
+		assert.NoFileExists(t, filepath.Join(dir, ".shipyard", "consignments", "20240102-120000-bbb222.md"))
+	})
+}
+
+func TestRemove_InterruptedRun(t *testing.T) {
+	dir := setupRemoveTestProject(t)
+	require.NoError(t, runstate.Write(runstate.Path(dir), &runstate.Run{
+		Consignments: []string{".shipyard/consignments/20240101-120000-aaa111.md"},
+	}))
+
+	err := runRemoveWithDir(dir, &RemoveCommandOptions{IDs: []string{"20240101-120000-aaa111"}, Quiet: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "part of an interrupted version run")
+	assert.FileExists(t, filepath.Join(dir, ".shipyard", "consignments", "20240101-120000-aaa111.md"))
+
+	// Consignments the run does not release can still be removed
+	require.NoError(t, runRemoveWithDir(dir, &RemoveCommandOptions{IDs: []string{"20240102-120000-bbb222"}, Quiet: true}))
+}
+
+func TestRemove_HistoryLocked(t *testing.T) {
+	dir := setupRemoveTestProject(t)
+	held := flock.New(filepath.Join(dir, ".shipyard", "history.json.lock"))
+	require.NoError(t, held.Lock())
+	defer func() { _ = held.Unlock() }()
+
+	err := runRemoveWithDir(dir, &RemoveCommandOptions{All: true, Quiet: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "a version run is in progress")
+	assert.FileExists(t, filepath.Join(dir, ".shipyard", "consignments", "20240101-120000-aaa111.md"))
+}
@@ -0,0 +1,21 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/NatoNathan/shipyard/internal/rules"
+	"github.com/NatoNathan/shipyard/internal/ui"
+)
+
+// printRuleWarnings prints warn-level findings with their rule IDs
+func printRuleWarnings(report *rules.Report) {
+	warnings := report.Warnings()
+	if len(warnings) == 0 {
+		return
+	}
+	fmt.Println()
+	for _, f := range warnings {
+		fmt.Println(ui.WarningMessage(f.String()))
+	}
+	fmt.Println()
+}
@@ -12,6 +12,7 @@ import (
 	shipyarderrors "github.com/NatoNathan/shipyard/internal/errors"
 	"github.com/NatoNathan/shipyard/internal/git"
 	"github.com/NatoNathan/shipyard/internal/graph"
+	"github.com/NatoNathan/shipyard/internal/release"
 	"github.com/NatoNathan/shipyard/internal/template"
 	"github.com/NatoNathan/shipyard/internal/ui"
 	"github.com/NatoNathan/shipyard/internal/version"
@@ -88,9 +89,9 @@ Does not affect .shipyard/prerelease.yml or stage tracking.`,
 }
 
 func runSnapshot(opts *SnapshotCommandOptions) error {
-	cwd, err := os.Getwd()
+	cwd, err := projectRoot()
 	if err != nil {
-		return fmt.Errorf("failed to get current directory: %w", err)
+		return err
 	}
 	return runSnapshotWithDir(cwd, opts, time.Now().UTC())
 }
@@ -107,6 +108,7 @@ func runSnapshotWithDir(projectPath string, opts *SnapshotCommandOptions, now ti
 	if err != nil {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
+	settings := release.SettingsFor(projectPath, cfg)
 
 	// 2. Read consignments
 	consignmentsDir := filepath.Join(projectPath, cfg.Consignments.Path)
@@ -130,7 +132,7 @@ func runSnapshotWithDir(projectPath string, opts *SnapshotCommandOptions, now ti
 		return fmt.Errorf("failed to build dependency graph: %w", err)
 	}
 
-	currentVersions, err := ReadAllCurrentVersions(projectPath, cfg)
+	currentVersions, err := release.ReadAllCurrentVersions(projectPath, cfg, settings)
 	if err != nil {
 		return err
 	}
@@ -155,6 +157,7 @@ func runSnapshotWithDir(projectPath string, opts *SnapshotCommandOptions, now ti
 
 	// 5. Build snapshot versions and tags
 	renderer := template.NewTemplateRenderer()
+	renderer.SetRepository(settings.Repository)
 
 	snapshotTemplate := cfg.PreRelease.SnapshotTagTemplate
 	if snapshotTemplate == "" {
@@ -185,9 +188,10 @@ func runSnapshotWithDir(projectPath string, opts *SnapshotCommandOptions, now ti
 
 		// Render tag
 		tagCtx := map[string]interface{}{
-			"Version":   targetVersion,
-			"Timestamp": timestamp,
-			"Package":   pkgName,
+			"Version":    targetVersion,
+			"VersionTag": "v" + targetVersion,
+			"Timestamp":  timestamp,
+			"Package":    pkgName,
 		}
 		tagName, err := renderer.Render(snapshotTemplate, tagCtx)
 		if err != nil {
@@ -245,7 +249,7 @@ func runSnapshotWithDir(projectPath string, opts *SnapshotCommandOptions, now ti
 			return fmt.Errorf("package %s not found in configuration", r.pkg)
 		}
 		pkgPath := filepath.Join(projectPath, pkg.Path)
-		handler, err := GetEcosystemHandler(pkg, pkgPath)
+		handler, err := release.GetEcosystemHandler(pkg, pkgPath)
 		if err != nil {
 			return err
 		}
@@ -274,7 +278,7 @@ func runSnapshotWithDir(projectPath string, opts *SnapshotCommandOptions, now ti
 		for _, r := range results {
 			changedPackages[r.pkg] = true
 		}
-		filesToStage, err := CollectVersionFiles(projectPath, cfg, changedPackages)
+		filesToStage, err := release.CollectVersionFiles(projectPath, cfg, changedPackages)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,267 @@
+package commands
+
+import (
+	stderrors "errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/consignment"
+	"github.com/NatoNathan/shipyard/internal/prompt"
+	"github.com/NatoNathan/shipyard/internal/release"
+	"github.com/NatoNathan/shipyard/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// squashedArchiveDir is the directory under the consignments path that keeps
+// originals when squashing with --keep-originals
+const squashedArchiveDir = "squashed"
+
+// SquashCommandOptions holds options for the consignment squash command
+type SquashCommandOptions struct {
+	IDs           []string
+	Summary       string
+	Package       string
+	KeepOriginals bool
+	AckMixed      bool
+	Timestamp     time.Time // Timestamp for the new consignment; defaults to now
+	JSON          bool
+	Quiet         bool
+}
+
+// SquashOutput is the JSON output structure for the squash command
+type SquashOutput struct {
+	ID         string   `json:"id"`
+	Packages   []string `json:"packages"`
+	ChangeType string   `json:"changeType"`
+	Squashed   []string `json:"squashed"`
+	ArchivedTo string   `json:"archivedTo,omitempty"`
+}
+
+// NewConsignmentSquashCommand creates the consignment squash command
+func NewConsignmentSquashCommand() *cobra.Command {
+	opts := &SquashCommandOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "squash [id...] [--summary text] [--package name]",
+		Short: "Consolidate cargo into a single crate",
+		Long: `Merge pending consignments into one new consignment before release.
+
+The new consignment covers every package of the originals at the highest of
+their change types, and lists the original summaries under the new summary.
+Metadata values that differ, such as authors, are merged into a list. The
+original files are removed, or moved to the squashed/ directory under the
+consignments path with --keep-originals.
+
+Without IDs, pick consignments interactively (optionally only those for
+--package). Squashing consignments with different change types requires
+--ack-mixed, since the highest type then applies to every package.`,
+		Example: `  # Squash three patch consignments
+  shipyard consignment squash 20240101-120000-aaa111 20240102-120000-bbb222 20240103-120000-ccc333 \
+    --summary "Various stability fixes"
+
+  # Pick consignments for one package interactively
+  shipyard consignment squash --package core
+
+  # Keep the original files
+  shipyard consignment squash a b --summary "Fixes" --keep-originals`,
+		ValidArgsFunction: completeConsignments,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			globalFlags := GetGlobalFlags(cmd)
+			opts.IDs = args
+			opts.JSON = globalFlags.JSON
+			opts.Quiet = globalFlags.Quiet
+			return runSquash(opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Summary, "summary", "s", "", "Summary of the squashed consignment")
+	cmd.Flags().StringVarP(&opts.Package, "package", "p", "", "Only squash consignments for this package")
+	cmd.Flags().BoolVar(&opts.KeepOriginals, "keep-originals", false, "Archive the original files instead of deleting them")
+	cmd.Flags().BoolVar(&opts.AckMixed, "ack-mixed", false, "Allow squashing consignments with different change types")
+
+	RegisterPackageCompletions(cmd, "package")
+
+	return cmd
+}
+
+func runSquash(opts *SquashCommandOptions) error {
+	cwd, err := projectRoot()
+	if err != nil {
+		return err
+	}
+	return runSquashWithDir(cwd, opts)
+}
+
+func runSquashWithDir(projectPath string, opts *SquashCommandOptions) (err error) {
+	cfg, err := config.LoadFromDir(projectPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	if opts.Package != "" {
+		if _, ok := cfg.GetPackage(opts.Package); !ok {
+			return fmt.Errorf("package not found: %s", opts.Package)
+		}
+	}
+
+	consignmentsPath := cfg.Consignments.Path
+	if consignmentsPath == "" {
+		consignmentsPath = ".shipyard/consignments"
+	}
+	consignmentsDir := filepath.Join(projectPath, consignmentsPath)
+
+	pending, err := consignment.ReadAllConsignments(consignmentsDir)
+	if err != nil {
+		return fmt.Errorf("failed to read consignments: %w", err)
+	}
+	if opts.Package != "" {
+		pending = release.ConsignmentsFor(pending, opts.Package)
+	}
+
+	selected, err := selectSquashConsignments(pending, opts)
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(opts.Summary) == "" {
+		opts.Summary, err = prompt.PromptTextInput("Summary for the squashed consignment:", "")
+		if err != nil {
+			return err
+		}
+	}
+
+	squashed, err := consignment.Squash(selected, consignment.SquashOptions{
+		Summary:    opts.Summary,
+		Timestamp:  opts.Timestamp,
+		AllowMixed: opts.AckMixed,
+	})
+	if err != nil {
+		var conflict *consignment.SquashConflictError
+		if stderrors.As(err, &conflict) {
+			return fmt.Errorf("%w; pass --ack-mixed to squash anyway", err)
+		}
+		return err
+	}
+
+	squashed.ID, err = consignment.NewIDGenerator(cfg.Consignments.IDStyle, consignmentsDir).Generate(squashed.Timestamp, opts.Summary)
+	if err != nil {
+		return fmt.Errorf("failed to generate consignment ID: %w", err)
+	}
+	squashed.Sequence, err = consignment.NextSequence(consignmentsDir)
+	if err != nil {
+		return fmt.Errorf("failed to number consignment: %w", err)
+	}
+
+	// Write the new consignment before touching the originals, and restore
+	// everything if any step fails
+	tx := release.NewFileTransaction()
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				err = fmt.Errorf("%w; additionally failed to roll back: %v", err, rollbackErr)
+			}
+		}
+	}()
+
+	if err := tx.Backup(filepath.Join(consignmentsDir, squashed.ID+".md")); err != nil {
+		return err
+	}
+	if err := consignment.WriteConsignment(squashed, consignmentsDir); err != nil {
+		return err
+	}
+
+	archiveDir := filepath.Join(consignmentsDir, squashedArchiveDir)
+	var squashedIDs []string
+	for _, c := range selected {
+		path := filepath.Join(consignmentsDir, c.FileName())
+		if err := tx.Backup(path); err != nil {
+			return err
+		}
+		if opts.KeepOriginals {
+			if err := os.MkdirAll(archiveDir, 0755); err != nil {
+				return fmt.Errorf("failed to create archive directory: %w", err)
+			}
+			archived := filepath.Join(archiveDir, c.FileName())
+			if err := tx.Backup(archived); err != nil {
+				return err
+			}
+			if err := os.Rename(path, archived); err != nil {
+				return fmt.Errorf("failed to archive consignment %s: %w", c.ID, err)
+			}
+		} else if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to remove consignment %s: %w", c.ID, err)
+		}
+		squashedIDs = append(squashedIDs, c.ID)
+	}
+
+	output := SquashOutput{
+		ID:         squashed.ID,
+		Packages:   squashed.Packages,
+		ChangeType: string(squashed.ChangeType),
+		Squashed:   squashedIDs,
+	}
+	if opts.KeepOriginals {
+		output.ArchivedTo = filepath.ToSlash(filepath.Join(consignmentsPath, squashedArchiveDir))
+	}
+
+	if opts.JSON {
+		return PrintJSON(os.Stdout, output)
+	}
+
+	if !opts.Quiet {
+		fmt.Println()
+		fmt.Println(ui.SuccessMessage(fmt.Sprintf("Squashed %d consignment(s) into %s", len(squashedIDs), squashed.ID)))
+		fmt.Println(ui.KeyValue("Packages", strings.Join(squashed.Packages, ", ")))
+		fmt.Println(ui.KeyValue("Change type", string(squashed.ChangeType)))
+		if output.ArchivedTo != "" {
+			fmt.Println(ui.KeyValue("Originals", output.ArchivedTo))
+		}
+		fmt.Println()
+	}
+
+	return nil
+}
+
+// selectSquashConsignments resolves the consignments to squash from the given
+// IDs, or from an interactive picker when none were given
+func selectSquashConsignments(pending []*consignment.Consignment, opts *SquashCommandOptions) ([]*consignment.Consignment, error) {
+	if len(opts.IDs) == 0 {
+		if len(pending) < 2 {
+			return nil, fmt.Errorf("at least two pending consignments are required to squash")
+		}
+		labels := make([]string, len(pending))
+		for i, c := range pending {
+			summary, _, _ := strings.Cut(c.Summary, "\n")
+			labels[i] = fmt.Sprintf("%s [%s] %s: %s", c.ID, c.ChangeType, strings.Join(c.Packages, ", "), summary)
+		}
+		indexes, err := prompt.PromptForConsignments(labels)
+		if err != nil {
+			return nil, err
+		}
+		selected := make([]*consignment.Consignment, len(indexes))
+		for i, index := range indexes {
+			selected[i] = pending[index]
+		}
+		return selected, nil
+	}
+
+	var selected []*consignment.Consignment
+	for _, id := range opts.IDs {
+		index := slices.IndexFunc(pending, func(c *consignment.Consignment) bool { return c.ID == id })
+		if index < 0 {
+			if opts.Package != "" {
+				return nil, fmt.Errorf("consignment not found for package %s: %s", opts.Package, id)
+			}
+			return nil, fmt.Errorf("consignment not found: %s", id)
+		}
+		if !slices.Contains(selected, pending[index]) {
+			selected = append(selected, pending[index])
+		}
+	}
+	return selected, nil
+}
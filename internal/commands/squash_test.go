@@ -0,0 +1,141 @@
+package commands
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/consignment"
+	"github.com/NatoNathan/shipyard/internal/prompt"
+	"github.com/NatoNathan/shipyard/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupSquashTestProject(t *testing.T) (string, string) {
+	t.Helper()
+	tempDir := t.TempDir()
+	initShipyardConfig(t, tempDir)
+	consignmentsDir := filepath.Join(tempDir, ".shipyard", "consignments")
+
+	for i, c := range []*consignment.Consignment{
+		{ID: "c1", Packages: []string{"core"}, ChangeType: types.ChangeTypePatch, Summary: "Fix crash",
+			Metadata: map[string]interface{}{"author": "ana@example.com"}},
+		{ID: "c2", Packages: []string{"core", "api"}, ChangeType: types.ChangeTypePatch, Summary: "Fix timeout",
+			Metadata: map[string]interface{}{"author": "ben@example.com"}},
+		{ID: "c3", Packages: []string{"api"}, ChangeType: types.ChangeTypeMinor, Summary: "Add endpoint"},
+	} {
+		c.Timestamp = time.Date(2026, 1, 1+i, 12, 0, 0, 0, time.UTC)
+		require.NoError(t, consignment.WriteConsignment(c, consignmentsDir))
+	}
+	return tempDir, consignmentsDir
+}
+
+func TestSquashCommand(t *testing.T) {
+	t.Run("squashes into one consignment", func(t *testing.T) {
+		tempDir, consignmentsDir := setupSquashTestProject(t)
+
+		captureOutput(func() {
+			require.NoError(t, runSquashWithDir(tempDir, &SquashCommandOptions{
+				IDs:     []string{"c1", "c2"},
+				Summary: "Various stability fixes",
+				Quiet:   true,
+			}))
+		})
+
+		assert.NoFileExists(t, filepath.Join(consignmentsDir, "c1.md"))
+		assert.NoFileExists(t, filepath.Join(consignmentsDir, "c2.md"))
+		pending, err := consignment.ReadAllConsignments(consignmentsDir)
+		require.NoError(t, err)
+		require.Len(t, pending, 2)
+
+		squashed := pending[1]
+		if squashed.ID == "c3" {
+			squashed = pending[0]
+		}
+		assert.Equal(t, []string{"core", "api"}, squashed.Packages)
+		assert.Equal(t, types.ChangeTypePatch, squashed.ChangeType)
+		assert.Equal(t, "Various stability fixes\n\n- Fix crash\n- Fix timeout", squashed.Summary)
+		assert.Equal(t, []interface{}{"ana@example.com", "ben@example.com"}, squashed.Metadata["author"])
+	})
+
+	t.Run("keep originals archives them", func(t *testing.T) {
+		tempDir, consignmentsDir := setupSquashTestProject(t)
+
+		captureOutput(func() {
+			require.NoError(t, runSquashWithDir(tempDir, &SquashCommandOptions{
+				IDs:           []string{"c1", "c2"},
+				Summary:       "Fixes",
+				KeepOriginals: true,
+				Quiet:         true,
+			}))
+		})
+
+		assert.FileExists(t, filepath.Join(consignmentsDir, "squashed", "c1.md"))
+		assert.FileExists(t, filepath.Join(consignmentsDir, "squashed", "c2.md"))
+		pending, err := consignment.ReadAllConsignments(consignmentsDir)
+		require.NoError(t, err)
+		assert.Len(t, pending, 2, "archived originals are not pending")
+	})
+
+	t.Run("mixed change types need --ack-mixed", func(t *testing.T) {
+		tempDir, consignmentsDir := setupSquashTestProject(t)
+
+		err := runSquashWithDir(tempDir, &SquashCommandOptions{IDs: []string{"c2", "c3"}, Summary: "API work", Quiet: true})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--ack-mixed")
+		assert.FileExists(t, filepath.Join(consignmentsDir, "c2.md"))
+		assert.FileExists(t, filepath.Join(consignmentsDir, "c3.md"))
+
+		captureOutput(func() {
+			require.NoError(t, runSquashWithDir(tempDir, &SquashCommandOptions{IDs: []string{"c2", "c3"}, Summary: "API work", AckMixed: true, Quiet: true}))
+		})
+		pending, err := consignment.ReadAllConsignments(consignmentsDir)
+		require.NoError(t, err)
+		require.Len(t, pending, 2)
+	})
+
+	t.Run("package filter rejects other consignments", func(t *testing.T) {
+		tempDir, _ := setupSquashTestProject(t)
+
+		err := runSquashWithDir(tempDir, &SquashCommandOptions{IDs: []string{"c1", "c3"}, Summary: "Fixes", Package: "core"})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "consignment not found for package core: c3")
+	})
+
+	t.Run("unknown consignment", func(t *testing.T) {
+		tempDir, _ := setupSquashTestProject(t)
+
+		err := runSquashWithDir(tempDir, &SquashCommandOptions{IDs: []string{"c1", "nope"}, Summary: "Fixes"})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "consignment not found: nope")
+	})
+
+	t.Run("interactive picker with package filter", func(t *testing.T) {
+		tempDir, consignmentsDir := setupSquashTestProject(t)
+
+		var out bytes.Buffer
+		prompt.SetAccessible(true)
+		prompt.SetAccessibleIO(strings.NewReader("1 2\nCore fixes\n"), &out)
+		t.Cleanup(func() {
+			prompt.SetAccessible(false)
+			prompt.SetAccessibleIO(os.Stdin, os.Stdout)
+		})
+
+		captureOutput(func() {
+			require.NoError(t, runSquashWithDir(tempDir, &SquashCommandOptions{Package: "core", Quiet: true}))
+		})
+
+		assert.Equal(t, `Select consignments to squash:
+  1. c1 [patch] core: Fix crash
+  2. c2 [patch] core, api: Fix timeout
+Enter numbers separated by commas: Summary for the squashed consignment: `, out.String())
+		assert.NoFileExists(t, filepath.Join(consignmentsDir, "c1.md"))
+		assert.FileExists(t, filepath.Join(consignmentsDir, "c3.md"))
+	})
+}
@@ -2,18 +2,19 @@ package commands
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
-	"sort"
 	"strconv"
+	"strings"
+	"time"
 
-	"github.com/NatoNathan/shipyard/internal/config"
 	"github.com/NatoNathan/shipyard/internal/consignment"
-	"github.com/NatoNathan/shipyard/internal/errors"
-	"github.com/NatoNathan/shipyard/internal/graph"
+	shipyarderrors "github.com/NatoNathan/shipyard/internal/errors"
 	"github.com/NatoNathan/shipyard/internal/ui"
-	"github.com/NatoNathan/shipyard/internal/version"
+	"github.com/NatoNathan/shipyard/pkg/shipyard"
+	"github.com/NatoNathan/shipyard/pkg/types"
 	"github.com/spf13/cobra"
 )
 
@@ -54,7 +55,13 @@ loaded consignments grouped by vessel with calculated destination coordinates.`,
 					}
 				}
 			}
-			return runStatus(opts)
+			err := runStatus(opts)
+			var exitErr *shipyarderrors.ExitCodeError
+			if errors.As(err, &exitErr) {
+				// The no-pending message was already printed; only the exit code matters
+				cmd.SilenceErrors = true
+			}
+			return err
 		},
 	}
 
@@ -71,27 +78,27 @@ loaded consignments grouped by vessel with calculated destination coordinates.`,
 
 // runStatus executes the status command
 func runStatus(opts *StatusOptions) error {
+	cwd, err := projectRoot()
+	if err != nil {
+		return err
+	}
+
 	// Check if shipyard is initialized
-	shipyardDir := ".shipyard"
+	shipyardDir := filepath.Join(cwd, ".shipyard")
 	if _, err := os.Stat(shipyardDir); os.IsNotExist(err) {
-		return errors.ErrNotInitialized
+		return shipyarderrors.ErrNotInitialized
 	}
 
 	// Load configuration
-	cwd, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("failed to get current directory: %w", err)
-	}
-	cfg, err := config.LoadFromDir(cwd)
+	project, err := shipyard.Open(cwd)
 	if err != nil {
-		return fmt.Errorf("failed to load configuration: %w", err)
+		return err
 	}
 
 	// Read all pending consignments
-	consignmentsDir := filepath.Join(cwd, cfg.Consignments.Path)
-	consignments, err := readAllConsignments(consignmentsDir)
+	consignments, err := project.PendingConsignments()
 	if err != nil {
-		return fmt.Errorf("failed to read consignments: %w", err)
+		return err
 	}
 
 	// Filter by packages if specified
@@ -99,21 +106,20 @@ func runStatus(opts *StatusOptions) error {
 		consignments = filterConsignmentsByPackages(consignments, opts.Packages)
 	}
 
-	// Check if there are any consignments
+	// Check if there are any consignments; exit code 2 lets CI gate on pending changes
 	if len(consignments) == 0 {
 		if opts.Output == "json" {
-			return outputJSONWithBumps(
-				map[string][]*consignment.Consignment{},
-				map[string]version.VersionBump{},
-				opts,
-			)
+			if err := outputJSONWithBumps(map[string][]shipyard.Consignment{}, nil, opts); err != nil {
+				return err
+			}
+		} else if !opts.Quiet {
+			fmt.Println(ui.InfoMessage("No pending consignments"))
 		}
-		fmt.Println(ui.InfoMessage("No pending consignments"))
-		return nil
+		return shipyarderrors.NewExitCodeError(2, "no pending consignments")
 	}
 
-	// Calculate version bumps with propagation
-	versionBumps, err := calculateVersionBumpsForStatus(cfg, cwd, consignments)
+	// Project the same versions the version command would write
+	changes, err := project.ProjectedVersions(shipyard.PlanOptions{Packages: opts.Packages})
 	if err != nil {
 		return fmt.Errorf("failed to calculate version bumps: %w", err)
 	}
@@ -124,33 +130,10 @@ func runStatus(opts *StatusOptions) error {
 	// Output based on format
 	switch opts.Output {
 	case "json":
-		return outputJSONWithBumps(grouped, versionBumps, opts)
+		return outputJSONWithBumps(grouped, changes, opts)
 	default:
-		return outputTableWithBumps(grouped, versionBumps, opts)
-	}
-}
-
-// calculateVersionBumpsForStatus calculates version bumps including propagation
-func calculateVersionBumpsForStatus(cfg *config.Config, projectPath string, consignments []*consignment.Consignment) (map[string]version.VersionBump, error) {
-	// Build dependency graph
-	depGraph, err := graph.BuildGraph(cfg)
-	if err != nil {
-		return nil, err
-	}
-
-	// Read current versions
-	currentVersions, err := ReadAllCurrentVersions(projectPath, cfg)
-	if err != nil {
-		return nil, err
-	}
-
-	// Calculate bumps with propagation
-	propagator, err := version.NewPropagator(depGraph)
-	if err != nil {
-		return nil, err
+		return outputTableWithBumps(consignments, grouped, changes, opts)
 	}
-
-	return propagator.Propagate(currentVersions, consignments)
 }
 
 // readAllConsignments reads all consignment files from a directory
@@ -183,13 +166,13 @@ func readAllConsignments(dir string) ([]*consignment.Consignment, error) {
 }
 
 // filterConsignmentsByPackages filters consignments to only those affecting specified packages
-func filterConsignmentsByPackages(consignments []*consignment.Consignment, packages []string) []*consignment.Consignment {
+func filterConsignmentsByPackages(consignments []shipyard.Consignment, packages []string) []shipyard.Consignment {
 	packageSet := make(map[string]bool)
 	for _, pkg := range packages {
 		packageSet[pkg] = true
 	}
 
-	var filtered []*consignment.Consignment
+	var filtered []shipyard.Consignment
 	for _, c := range consignments {
 		for _, pkg := range c.Packages {
 			if packageSet[pkg] {
@@ -202,9 +185,19 @@ func filterConsignmentsByPackages(consignments []*consignment.Consignment, packa
 	return filtered
 }
 
-// groupConsignmentsByPackage groups consignments by package
-func groupConsignmentsByPackage(consignments []*consignment.Consignment) map[string][]*consignment.Consignment {
-	grouped := make(map[string][]*consignment.Consignment)
+// summaryFirstLine returns the first non-empty line of a consignment summary
+func summaryFirstLine(summary string) string {
+	for _, line := range strings.Split(summary, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// groupConsignmentsByPackage groups consignments, oldest first, by package
+func groupConsignmentsByPackage(consignments []shipyard.Consignment) map[string][]shipyard.Consignment {
+	grouped := make(map[string][]shipyard.Consignment)
 
 	for _, c := range consignments {
 		for _, pkg := range c.Packages {
@@ -215,44 +208,60 @@ func groupConsignmentsByPackage(consignments []*consignment.Consignment) map[str
 	return grouped
 }
 
-// outputJSONWithBumps outputs status in JSON format with calculated version bumps
-func outputJSONWithBumps(grouped map[string][]*consignment.Consignment, versionBumps map[string]version.VersionBump, opts *StatusOptions) error {
-	// Build JSON structure
-	output := make(map[string]interface{})
+// StatusPackageOutput is a package's entry in status JSON output
+type StatusPackageOutput struct {
+	Count        int                       `json:"count"`
+	Bump         string                    `json:"bump"`
+	Source       string                    `json:"source"`
+	OldVersion   string                    `json:"oldVersion"`
+	NewVersion   string                    `json:"newVersion"`
+	Consignments []StatusConsignmentOutput `json:"consignments,omitempty"`
+}
+
+// StatusConsignmentOutput is a pending consignment in status JSON output
+type StatusConsignmentOutput struct {
+	ID       string                 `json:"id"`
+	Created  time.Time              `json:"created"`
+	Packages []string               `json:"packages"`
+	Type     types.ChangeType       `json:"type"`
+	Summary  string                 `json:"summary"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
 
+// outputJSONWithBumps outputs status in JSON format with calculated version bumps.
+// Packages are keyed by name, which encoding/json writes in sorted order, and
+// each package's consignments are in pending order: creation time, then
+// sequence and ID.
+func outputJSONWithBumps(grouped map[string][]shipyard.Consignment, changes []shipyard.VersionChange, opts *StatusOptions) error {
 	// Include all packages that have bumps (direct or propagated)
-	jsonKeys := make([]string, 0, len(versionBumps))
-	for k := range versionBumps {
-		jsonKeys = append(jsonKeys, k)
-	}
-	sort.Strings(jsonKeys)
-	for _, pkg := range jsonKeys {
-		bump := versionBumps[pkg]
-		pkgData := make(map[string]interface{})
-
+	output := make(map[string]StatusPackageOutput, len(changes))
+	for _, change := range changes {
 		// Get consignments for this package (may be empty for propagated bumps)
-		consignments := grouped[pkg]
-		pkgData["count"] = len(consignments)
-		pkgData["bump"] = bump.ChangeType
-		pkgData["source"] = bump.Source
-		pkgData["oldVersion"] = bump.OldVersion.String()
-		pkgData["newVersion"] = bump.NewVersion.String()
-
-		// Include consignment details if verbose
-		if opts.Verbose && len(consignments) > 0 {
-			var details []map[string]interface{}
-			for _, c := range consignments {
-				details = append(details, map[string]interface{}{
-					"id":       c.ID,
-					"type":     c.ChangeType,
-					"summary":  c.Summary,
-					"metadata": c.Metadata,
-				})
+		consignments := grouped[change.Package]
+		pkgData := StatusPackageOutput{
+			Count:      len(consignments),
+			Bump:       string(change.ChangeType),
+			Source:     change.Source,
+			OldVersion: change.OldVersion,
+			NewVersion: change.NewVersion,
+		}
+
+		// Include consignment details; metadata only when verbose
+		for _, c := range consignments {
+			detail := StatusConsignmentOutput{
+				ID:       c.ID,
+				Created:  c.Created,
+				Packages: c.Packages,
+				Type:     c.ChangeType,
+				Summary:  c.Summary,
+			}
+			if opts.Verbose {
+				detail.Metadata = c.Metadata
 			}
-			pkgData["consignments"] = details
+			pkgData.Consignments = append(pkgData.Consignments, detail)
 		}
 
-		output[pkg] = pkgData
+		output[change.Package] = pkgData
 	}
 
 	data, err := json.MarshalIndent(output, "", "  ")
@@ -264,19 +273,13 @@ func outputJSONWithBumps(grouped map[string][]*consignment.Consignment, versionB
 	return nil
 }
 
-// outputTableWithBumps outputs status in table format with calculated version bumps
-func outputTableWithBumps(grouped map[string][]*consignment.Consignment, versionBumps map[string]version.VersionBump, opts *StatusOptions) error {
-	tableKeys := make([]string, 0, len(versionBumps))
-	for k := range versionBumps {
-		tableKeys = append(tableKeys, k)
-	}
-	sort.Strings(tableKeys)
-
+// outputTableWithBumps outputs status in table format with calculated version
+// bumps. pending is every consignment shown, in PendingConsignments order.
+func outputTableWithBumps(pending []shipyard.Consignment, grouped map[string][]shipyard.Consignment, changes []shipyard.VersionChange, opts *StatusOptions) error {
 	if opts.Quiet {
 		// Quiet mode: just package names and bump types
-		for _, pkg := range tableKeys {
-			bump := versionBumps[pkg]
-			fmt.Printf("%s: %s\n", pkg, bump.ChangeType)
+		for _, change := range changes {
+			fmt.Printf("%s: %s\n", change.Package, change.ChangeType)
 		}
 		return nil
 	}
@@ -286,16 +289,14 @@ func outputTableWithBumps(grouped map[string][]*consignment.Consignment, version
 	fmt.Println()
 
 	var rows [][]string
-	for _, pkg := range tableKeys {
-		bump := versionBumps[pkg]
-		consignments := grouped[pkg]
+	for _, change := range changes {
 		rows = append(rows, []string{
-			pkg,
-			bump.OldVersion.String(),
-			bump.NewVersion.String(),
-			ui.ChangeTypeBadge(string(bump.ChangeType)),
-			string(bump.Source),
-			strconv.Itoa(len(consignments)),
+			change.Package,
+			change.OldVersion,
+			change.NewVersion,
+			ui.ChangeTypeBadge(string(change.ChangeType)),
+			change.Source,
+			strconv.Itoa(len(grouped[change.Package])),
 		})
 	}
 
@@ -304,15 +305,44 @@ func outputTableWithBumps(grouped map[string][]*consignment.Consignment, version
 		rows,
 	))
 
+	// List each pending consignment of a versioned package once, in pending
+	// order: oldest first by timestamp, then sequence and ID
+	versioned := make(map[string]bool)
+	for _, change := range changes {
+		for _, c := range grouped[change.Package] {
+			versioned[c.ID] = true
+		}
+	}
+	var consignmentRows [][]string
+	for _, c := range pending {
+		if !versioned[c.ID] {
+			continue
+		}
+		consignmentRows = append(consignmentRows, []string{
+			c.ID,
+			c.Created.Format("2006-01-02"),
+			strings.Join(c.Packages, ", "),
+			ui.ChangeTypeBadge(string(c.ChangeType)),
+			summaryFirstLine(c.Summary),
+		})
+	}
+	if len(consignmentRows) > 0 {
+		fmt.Println()
+		fmt.Println(ui.Table(
+			[]string{"ID", "Created", "Packages", "Type", "Summary"},
+			consignmentRows,
+		))
+	}
+
 	// Verbose mode: show consignment details per package
 	if opts.Verbose {
-		for _, pkg := range tableKeys {
-			consignments := grouped[pkg]
+		for _, change := range changes {
+			consignments := grouped[change.Package]
 			if len(consignments) == 0 {
 				continue
 			}
 			fmt.Println()
-			fmt.Println(ui.Section(pkg))
+			fmt.Println(ui.Section(change.Package))
 			for _, c := range consignments {
 				fmt.Println(ui.KeyValue("ID", c.ID))
 				fmt.Println(ui.KeyValue("Type", string(c.ChangeType)))
@@ -4,10 +4,12 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/NatoNathan/shipyard/internal/consignment"
+	shipyarderrors "github.com/NatoNathan/shipyard/internal/errors"
 	"github.com/NatoNathan/shipyard/pkg/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -55,14 +57,20 @@ func TestStatusCommand_EmptyConsignments(t *testing.T) {
 	// Test: Run status command
 	cmd := NewStatusCommand()
 	cmd.SetArgs([]string{})
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
 
-	output := captureOutput(func() {
-		err := cmd.Execute()
-		require.NoError(t, err)
+	var err error
+	captureOutput(func() {
+		err = cmd.Execute()
 	})
 
-	// Verify: Output indicates no pending changes
-	assert.Contains(t, output, "No pending consignments")
+	// Verify: Exit code 2 signals no pending changes
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no pending consignments")
+	var exitErr *shipyarderrors.ExitCodeError
+	require.ErrorAs(t, err, &exitErr)
+	assert.Equal(t, 2, exitErr.Code)
 }
 
 func TestStatusCommand_MissingConsignmentsDirectoryJSON(t *testing.T) {
@@ -73,12 +81,17 @@ func TestStatusCommand_MissingConsignmentsDirectoryJSON(t *testing.T) {
 
 	cmd := NewStatusCommand()
 	cmd.SetArgs([]string{"--output", "json"})
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
 
+	var err error
 	output := captureOutput(func() {
-		err := cmd.Execute()
-		require.NoError(t, err)
+		err = cmd.Execute()
 	})
 
+	var exitErr *shipyarderrors.ExitCodeError
+	require.ErrorAs(t, err, &exitErr)
+	assert.Equal(t, 2, exitErr.Code)
 	assert.JSONEq(t, `{}`, output)
 	assert.True(t, json.Valid([]byte(output)))
 }
@@ -368,6 +381,83 @@ func TestStatusCommand_VerboseMode(t *testing.T) {
 	assert.Contains(t, output, "Fix")
 }
 
+// TestStatusCommand_ListsConsignments tests the per-consignment listing
+func TestStatusCommand_ListsConsignments(t *testing.T) {
+	tempDir := t.TempDir()
+	setupInitializedRepo(t, tempDir)
+	defer changeToDir(t, tempDir)()
+
+	consignmentsDir := filepath.Join(tempDir, ".shipyard", "consignments")
+	createStatusTestConsignment(t, consignmentsDir, "c1", []string{"core", "api"}, types.ChangeTypeMinor, "Add shared feature\n\nLonger description")
+
+	cmd := NewStatusCommand()
+	cmd.SetArgs([]string{})
+
+	output := captureOutput(func() {
+		require.NoError(t, cmd.Execute())
+	})
+
+	assert.Contains(t, output, "c1")
+	assert.Contains(t, output, "core, api")
+	assert.Contains(t, output, "Add shared feature")
+	assert.NotContains(t, output, "Longer description")
+	assert.Contains(t, output, time.Now().Format("2006-01-02"))
+
+	t.Run("json", func(t *testing.T) {
+		cmd := NewStatusCommand()
+		cmd.SetArgs([]string{"--output", "json"})
+
+		output := captureOutput(func() {
+			require.NoError(t, cmd.Execute())
+		})
+
+		var result map[string]struct {
+			NewVersion   string `json:"newVersion"`
+			Consignments []struct {
+				ID       string    `json:"id"`
+				Created  time.Time `json:"created"`
+				Packages []string  `json:"packages"`
+			} `json:"consignments"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(output), &result))
+		assert.Equal(t, "1.1.0", result["core"].NewVersion)
+		require.Len(t, result["core"].Consignments, 1)
+		assert.Equal(t, "c1", result["core"].Consignments[0].ID)
+		assert.Equal(t, []string{"core", "api"}, result["core"].Consignments[0].Packages)
+		assert.False(t, result["core"].Consignments[0].Created.IsZero())
+	})
+}
+
+func TestStatusCommand_ListsSameDayConsignmentsByTime(t *testing.T) {
+	tempDir := t.TempDir()
+	setupInitializedRepo(t, tempDir)
+	defer changeToDir(t, tempDir)()
+
+	// The later consignment has the lower ID, so a day-level sort would
+	// put it first
+	consignmentsDir := filepath.Join(tempDir, ".shipyard", "consignments")
+	day := time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)
+	for _, c := range []*consignment.Consignment{
+		{ID: "a-afternoon", Timestamp: day.Add(15 * time.Hour), Packages: []string{"core"}, ChangeType: types.ChangeTypePatch, Summary: "Afternoon fix"},
+		{ID: "z-morning", Timestamp: day.Add(9 * time.Hour), Packages: []string{"core"}, ChangeType: types.ChangeTypePatch, Summary: "Morning fix"},
+	} {
+		content, err := consignment.Serialize(c)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(consignmentsDir, c.ID+".md"), []byte(content), 0644))
+	}
+
+	cmd := NewStatusCommand()
+	cmd.SetArgs([]string{})
+	output := captureOutput(func() {
+		require.NoError(t, cmd.Execute())
+	})
+
+	morning, afternoon := strings.Index(output, "z-morning"), strings.Index(output, "a-afternoon")
+	require.NotEqual(t, -1, morning)
+	require.NotEqual(t, -1, afternoon)
+	assert.Less(t, morning, afternoon, "consignments created the same day are listed by time")
+}
+
 // Helper functions
 
 func setupInitializedRepo(t *testing.T, dir string) {
@@ -1,6 +1,7 @@
 package commands
 
 import (
+	stderrors "errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -9,69 +10,113 @@ import (
 	"github.com/NatoNathan/shipyard/internal/config"
 	"github.com/NatoNathan/shipyard/internal/consignment"
 	"github.com/NatoNathan/shipyard/internal/graph"
+	"github.com/NatoNathan/shipyard/internal/release"
+	"github.com/NatoNathan/shipyard/internal/rules"
+	"github.com/NatoNathan/shipyard/internal/template"
 	"github.com/NatoNathan/shipyard/internal/ui"
 	"github.com/spf13/cobra"
 )
 
 // ValidateOutput is the JSON output structure for validate command
 type ValidateOutput struct {
-	Valid    bool     `json:"valid"`
-	Errors   []string `json:"errors"`
-	Warnings []string `json:"warnings"`
+	Valid    bool            `json:"valid"`
+	Errors   []string        `json:"errors"`
+	Warnings []string        `json:"warnings"`
+	Findings []rules.Finding `json:"findings"`
 }
 
 // NewValidateCommand creates the validate command
 func NewValidateCommand() *cobra.Command {
+	var (
+		strict bool
+		format string
+	)
+
 	cmd := &cobra.Command{
 		Use:     "validate",
-		Aliases: []string{"check", "lint"},
+		Aliases: []string{"lint"},
 		Short:   "Inspect the hull before departure",
-		Long: `Validate shipyard configuration, consignment files, and the dependency graph.
+		Long: `Validate shipyard configuration, package manifests, consignment files,
+templates, and the dependency graph.
 
-Reports any errors or warnings found during validation.`,
+Reports one finding per line with the file and field it concerns. Each finding
+names the rule that produced it; set a rule to off, warn, or error under 'rules'
+in the config, or pass --max-severity to override every enabled rule. Exits
+non-zero when any error is found.`,
 		Example: `  # Validate everything
   shipyard validate
 
   # Validate with JSON output
-  shipyard validate --json`,
+  shipyard validate --format json
+
+  # Fail on warnings in CI
+  shipyard validate --strict`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			globalFlags := GetGlobalFlags(cmd)
+			switch format {
+			case "text":
+			case "json":
+				globalFlags.JSON = true
+			default:
+				return fmt.Errorf("invalid --format %q (must be text or json)", format)
+			}
+			if strict {
+				if globalFlags.MaxSeverity == string(rules.LevelWarn) {
+					return fmt.Errorf("--strict cannot be combined with --max-severity warn")
+				}
+				globalFlags.MaxSeverity = string(rules.LevelError)
+			}
 			return runValidate(globalFlags)
 		},
 	}
 
+	cmd.Flags().BoolVar(&strict, "strict", false, "Treat warnings as errors (same as --max-severity error)")
+	cmd.Flags().StringVar(&format, "format", "text", "Output format: text or json")
+
 	return cmd
 }
 
 func runValidate(flags GlobalFlags) error {
-	cwd, err := os.Getwd()
+	cwd, err := projectRoot()
 	if err != nil {
-		return fmt.Errorf("failed to get current directory: %w", err)
+		return err
 	}
 	return runValidateWithDir(cwd, flags)
 }
 
 func runValidateWithDir(projectPath string, flags GlobalFlags) error {
 	var validationErrors []string
-	var warnings []string
+	configFile := relPath(projectPath, configFileIn(projectPath))
 
 	// 1. Load and validate config
 	cfg, err := config.LoadFromDir(projectPath)
 	if err != nil {
-		validationErrors = append(validationErrors, fmt.Sprintf("config load error: %s", err))
+		validationErrors = append(validationErrors, fmt.Sprintf("%s: config load error: %s", configFile, err))
+	}
+
+	resolver, err := release.NewRuleResolver(cfg, flags.MaxSeverity)
+	if err != nil {
+		return err
 	}
+	report := rules.NewReport(resolver)
 
 	if cfg != nil {
 		if err := cfg.Validate(); err != nil {
-			validationErrors = append(validationErrors, fmt.Sprintf("config validation: %s", err))
+			validationErrors = append(validationErrors, fmt.Sprintf("%s: config validation: %s", configFile, err))
 		}
-
 		if err := config.ValidateDependencies(cfg); err != nil {
-			validationErrors = append(validationErrors, fmt.Sprintf("dependency validation: %s", err))
+			report.AddAt(rules.DependencyConfig, configFile, "", fmt.Sprintf("dependency validation: %s", err))
+		}
+	}
+
+	// 2. Check package paths and version files
+	if cfg != nil && resolver.Enabled(rules.PackageManifest) {
+		for _, pkg := range cfg.Packages {
+			validatePackageManifest(report, projectPath, configFile, pkg)
 		}
 	}
 
-	// 2. Read consignments and check for parse errors
+	// 3. Read consignments and check for parse errors and unknown packages
 	if cfg != nil {
 		consignmentsPath := cfg.Consignments.Path
 		if consignmentsPath == "" {
@@ -84,42 +129,91 @@ func runValidateWithDir(projectPath string, flags GlobalFlags) error {
 			if err != nil {
 				validationErrors = append(validationErrors, fmt.Sprintf("consignments directory: %s", err))
 			} else {
+				// Files differing only in case are a single file on macOS and Windows
+				seenFold := make(map[string]string)
 				for _, entry := range entries {
 					if entry.IsDir() || filepath.Ext(entry.Name()) != ".md" {
 						continue
 					}
 					filePath := filepath.Join(consignmentsDir, entry.Name())
-					_, err := consignment.ReadConsignment(filePath)
+					file := relPath(projectPath, filePath)
+					folded := strings.ToLower(entry.Name())
+					if other, ok := seenFold[folded]; ok {
+						report.AddAt(rules.ConsignmentParse, file, "id", fmt.Sprintf("consignment %s differs only in case from %s and collides with it on case-insensitive filesystems", entry.Name(), other))
+					}
+					seenFold[folded] = entry.Name()
+					c, err := consignment.ReadConsignment(filePath)
 					if err != nil {
-						validationErrors = append(validationErrors, fmt.Sprintf("consignment %s: %s", entry.Name(), err))
+						var fieldErr *consignment.FieldError
+						if stderrors.As(err, &fieldErr) {
+							report.AddAt(rules.ConsignmentParse, file, fieldErr.Field, fieldErr.Message)
+						} else {
+							report.AddAt(rules.ConsignmentParse, file, "", err.Error())
+						}
+						continue
+					}
+					for _, pkg := range c.Packages {
+						if _, ok := cfg.GetPackage(pkg); !ok {
+							report.AddAt(rules.StaleConsignment, file, "packages", fmt.Sprintf("consignment %s references unknown package %q", entry.Name(), pkg))
+						}
 					}
 				}
 			}
 		}
 
-		// 3. Build dependency graph and check for cycles
+		// 4. Load and parse configured templates
+		if resolver.Enabled(rules.TemplateSyntax) {
+			loader := template.NewTemplateLoader()
+			loader.SetBaseDir(projectPath)
+			loader.SetRemoteOptions(release.SettingsFor(projectPath, cfg).Remote)
+			validateTemplates(report, loader, configFile, "templates", &cfg.Templates)
+			for _, pkg := range cfg.Packages {
+				validateTemplates(report, loader, configFile, fmt.Sprintf("packages[%s].templates", pkg.Name), pkg.Templates)
+			}
+		}
+
+		// 5. Build dependency graph and check for cycles
 		depGraph, err := graph.BuildGraph(cfg)
 		if err != nil {
 			validationErrors = append(validationErrors, fmt.Sprintf("dependency graph: %s", err))
-		} else {
+		} else if resolver.Enabled(rules.DependencyCycle) {
 			hasCycles, cycles := graph.DetectCycles(depGraph)
 			if hasCycles {
 				for _, cycle := range cycles {
-					warnings = append(warnings, fmt.Sprintf("dependency cycle detected: %s", strings.Join(cycle, " -> ")))
+					report.AddAt(rules.DependencyCycle, configFile, "", fmt.Sprintf("dependency cycle detected: %s", strings.Join(cycle, " -> ")))
 				}
 			}
 		}
 	}
 
+	var warnings []string
+	for _, f := range report.Errors() {
+		validationErrors = append(validationErrors, f.String())
+	}
+	for _, f := range report.Warnings() {
+		warnings = append(warnings, f.String())
+	}
+
 	valid := len(validationErrors) == 0
 
 	// Output
 	if flags.JSON {
-		return PrintJSON(os.Stdout, ValidateOutput{
+		findings := report.Findings
+		if findings == nil {
+			findings = []rules.Finding{}
+		}
+		if err := PrintJSON(os.Stdout, ValidateOutput{
 			Valid:    valid,
 			Errors:   validationErrors,
 			Warnings: warnings,
-		})
+			Findings: findings,
+		}); err != nil {
+			return err
+		}
+		if !valid {
+			return fmt.Errorf("validation failed with %d error(s)", len(validationErrors))
+		}
+		return nil
 	}
 
 	if flags.Quiet {
@@ -155,3 +249,98 @@ func runValidateWithDir(projectPath string, flags GlobalFlags) error {
 
 	return nil
 }
+
+// validatePackageManifest checks that a package path exists and that its
+// ecosystem handler can read the current version
+func validatePackageManifest(report *rules.Report, projectPath, configFile string, pkg config.Package) {
+	field := fmt.Sprintf("packages[%s]", pkg.Name)
+	pkgPath := filepath.Join(projectPath, pkg.Path)
+
+	info, err := os.Stat(pkgPath)
+	if err != nil {
+		report.AddAt(rules.PackageManifest, configFile, field+".path", fmt.Sprintf("package path %s does not exist", pkg.Path))
+		return
+	}
+	if !info.IsDir() {
+		report.AddAt(rules.PackageManifest, configFile, field+".path", fmt.Sprintf("package path %s is not a directory", pkg.Path))
+		return
+	}
+
+	handler, err := release.GetEcosystemHandler(pkg, pkgPath)
+	if err != nil {
+		report.AddAt(rules.PackageManifest, configFile, field+".ecosystem", err.Error())
+		return
+	}
+	// Tag-only packages take their version from git tags
+	if pkg.IsTagOnly() {
+		return
+	}
+	if _, err := handler.ReadVersion(); err != nil {
+		report.AddAt(rules.PackageManifest, configFile, field, fmt.Sprintf("failed to read version: %s", err))
+	}
+}
+
+// validateTemplates loads and parses each configured template source
+func validateTemplates(report *rules.Report, loader *template.TemplateLoader, configFile, prefix string, templates *config.TemplateConfig) {
+	if templates == nil {
+		return
+	}
+
+	checks := []struct {
+		name         string
+		source       *config.TemplateSource
+		templateType template.TemplateType
+	}{
+		{"changelog", templates.Changelog, template.TemplateTypeChangelog},
+		{"tagName", templates.TagName, template.TemplateTypeTag},
+		{"releaseNotes", templates.ReleaseNotes, template.TemplateTypeReleaseNotes},
+		{"commitMessage", templates.CommitMessage, template.TemplateTypeCommit},
+	}
+
+	for _, check := range checks {
+		if check.source == nil {
+			continue
+		}
+		field := prefix + "." + check.name
+
+		content := check.source.Inline
+		if content == "" && check.source.Source != "" {
+			var err error
+			content, err = loader.Load(check.source.Source, check.templateType)
+			if err != nil {
+				report.AddAt(rules.TemplateSyntax, configFile, field, err.Error())
+				continue
+			}
+		}
+		if content == "" {
+			continue
+		}
+
+		if _, err := template.NewTemplateParser().Parse(check.name, content); err != nil {
+			report.AddAt(rules.TemplateSyntax, configFile, field, err.Error())
+		}
+	}
+}
+
+// configFileIn returns the config file LoadFromDir would read, or the
+// standard location when none exists
+func configFileIn(dir string) string {
+	for _, base := range []string{filepath.Join(dir, ".shipyard"), dir} {
+		for _, name := range []string{"shipyard.yaml", "shipyard.yml", "shipyard.json", "shipyard.toml"} {
+			path := filepath.Join(base, name)
+			if _, err := os.Stat(path); err == nil {
+				return path
+			}
+		}
+	}
+	return filepath.Join(dir, ".shipyard", "shipyard.yaml")
+}
+
+// relPath returns path relative to base, or path itself if it cannot be made relative
+func relPath(base, path string) string {
+	rel, err := filepath.Rel(base, path)
+	if err != nil {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
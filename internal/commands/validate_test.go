@@ -0,0 +1,260 @@
+package commands
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/rules"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestValidateCommand_RuleLevels verifies findings carry rule IDs and honor
+// config and --max-severity levels
+func TestValidateCommand_RuleLevels(t *testing.T) {
+	setup := func(t *testing.T) string {
+		t.Helper()
+		tempDir := t.TempDir()
+		initGitRepo(t, tempDir)
+		initShipyardConfig(t, tempDir)
+		writeGoVersion(t, tempDir, ".", "1.0.0")
+		writeGoVersion(t, tempDir, "api", "1.0.0")
+		require.NoError(t, runAdd(tempDir, AddOptions{
+			Packages:  []string{"core"},
+			Type:      "patch",
+			Summary:   "Fix bug",
+			Quiet:     true,
+			Timestamp: time.Date(2026, 1, 30, 14, 30, 22, 0, time.UTC),
+		}))
+		// Drop the package the consignment refers to
+		setPackages(t, tempDir, "api")
+		return tempDir
+	}
+	validate := func(t *testing.T, dir string, flags GlobalFlags) (ValidateOutput, error) {
+		t.Helper()
+		flags.JSON = true
+		var err error
+		output := captureOutput(func() {
+			err = runValidateWithDir(dir, flags)
+		})
+		var result ValidateOutput
+		require.NoError(t, json.Unmarshal([]byte(output), &result))
+		return result, err
+	}
+
+	t.Run("stale consignment warns by default", func(t *testing.T) {
+		result, err := validate(t, setup(t), GlobalFlags{})
+		require.NoError(t, err)
+		assert.True(t, result.Valid)
+		require.Len(t, result.Findings, 1)
+		assert.Equal(t, rules.StaleConsignment, result.Findings[0].Rule)
+		assert.Equal(t, rules.LevelWarn, result.Findings[0].Level)
+		require.Len(t, result.Warnings, 1)
+		assert.Contains(t, result.Warnings[0], `unknown package "core" [stale-consignment]`)
+	})
+
+	t.Run("max severity escalates to error", func(t *testing.T) {
+		result, err := validate(t, setup(t), GlobalFlags{MaxSeverity: "error"})
+		require.Error(t, err)
+		assert.False(t, result.Valid)
+		require.Len(t, result.Errors, 1)
+		assert.Contains(t, result.Errors[0], "[stale-consignment]")
+	})
+
+	t.Run("off in config drops the finding", func(t *testing.T) {
+		tempDir := setup(t)
+		setRuleLevels(t, tempDir, map[string]string{rules.StaleConsignment: "off"})
+		result, err := validate(t, tempDir, GlobalFlags{MaxSeverity: "error"})
+		require.NoError(t, err)
+		assert.True(t, result.Valid)
+		assert.Empty(t, result.Findings)
+	})
+
+	t.Run("invalid max severity", func(t *testing.T) {
+		err := runValidateWithDir(setup(t), GlobalFlags{MaxSeverity: "loud"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--max-severity")
+	})
+}
+
+// TestValidateCommand_Checks verifies findings name the file and field they concern
+func TestValidateCommand_Checks(t *testing.T) {
+	setup := func(t *testing.T) string {
+		t.Helper()
+		tempDir := t.TempDir()
+		initGitRepo(t, tempDir)
+		initShipyardConfig(t, tempDir)
+		writeGoVersion(t, tempDir, ".", "1.0.0")
+		writeGoVersion(t, tempDir, "api", "1.0.0")
+		return tempDir
+	}
+	validate := func(t *testing.T, dir string) (ValidateOutput, error) {
+		t.Helper()
+		var err error
+		output := captureOutput(func() {
+			err = runValidateWithDir(dir, GlobalFlags{JSON: true})
+		})
+		var result ValidateOutput
+		require.NoError(t, json.Unmarshal([]byte(output), &result))
+		return result, err
+	}
+
+	t.Run("clean project passes", func(t *testing.T) {
+		result, err := validate(t, setup(t))
+		require.NoError(t, err)
+		assert.True(t, result.Valid)
+		assert.Empty(t, result.Findings)
+	})
+
+	t.Run("missing package path", func(t *testing.T) {
+		tempDir := setup(t)
+		require.NoError(t, os.RemoveAll(filepath.Join(tempDir, "api")))
+
+		result, err := validate(t, tempDir)
+		require.Error(t, err)
+		require.Len(t, result.Findings, 1)
+		f := result.Findings[0]
+		assert.Equal(t, rules.PackageManifest, f.Rule)
+		assert.Equal(t, ".shipyard/shipyard.yaml", f.File)
+		assert.Equal(t, "packages[api].path", f.Field)
+		assert.Contains(t, result.Errors[0], ".shipyard/shipyard.yaml: packages[api].path: package path ./api does not exist")
+	})
+
+	t.Run("unparseable version file", func(t *testing.T) {
+		tempDir := setup(t)
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, "api", "version.go"), []byte("package main\n"), 0644))
+
+		result, err := validate(t, tempDir)
+		require.Error(t, err)
+		require.Len(t, result.Findings, 1)
+		assert.Equal(t, rules.PackageManifest, result.Findings[0].Rule)
+		assert.Equal(t, "packages[api]", result.Findings[0].Field)
+	})
+
+	t.Run("bad change type in consignment", func(t *testing.T) {
+		tempDir := setup(t)
+		content := "---\nid: c1\ntimestamp: 2026-01-30T14:30:22Z\npackages:\n  - core\nchangeType: huge\n---\n\nFix bug\n"
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, ".shipyard", "consignments", "c1.md"), []byte(content), 0644))
+
+		result, err := validate(t, tempDir)
+		require.Error(t, err)
+		require.Len(t, result.Findings, 1)
+		f := result.Findings[0]
+		assert.Equal(t, rules.ConsignmentParse, f.Rule)
+		assert.Equal(t, ".shipyard/consignments/c1.md", f.File)
+		assert.Equal(t, "changeType", f.Field)
+	})
+
+	t.Run("consignments differing only in case", func(t *testing.T) {
+		tempDir := setup(t)
+		setPackages(t, tempDir, "core")
+		dir := filepath.Join(tempDir, ".shipyard", "consignments")
+		for _, id := range []string{"c1", "C1"} {
+			content := "---\nid: " + id + "\ntimestamp: 2026-01-30T14:30:22Z\npackages:\n  - core\nchangeType: patch\n---\n\nFix bug\n"
+			require.NoError(t, os.WriteFile(filepath.Join(dir, id+".md"), []byte(content), 0644))
+		}
+
+		result, err := validate(t, tempDir)
+		require.Error(t, err)
+		require.Len(t, result.Findings, 1)
+		f := result.Findings[0]
+		assert.Equal(t, rules.ConsignmentParse, f.Rule)
+		assert.Equal(t, "id", f.Field)
+		assert.Contains(t, f.Message, "differs only in case")
+	})
+
+	t.Run("template that does not parse", func(t *testing.T) {
+		tempDir := setup(t)
+		configPath := filepath.Join(tempDir, ".shipyard", "shipyard.yaml")
+		cfg, err := config.Load(configPath)
+		require.NoError(t, err)
+		cfg.Templates.TagName = &config.TemplateSource{Inline: "{{ .Version "}
+		require.NoError(t, config.WriteConfig(cfg, configPath))
+
+		result, err := validate(t, tempDir)
+		require.Error(t, err)
+		require.Len(t, result.Findings, 1)
+		assert.Equal(t, rules.TemplateSyntax, result.Findings[0].Rule)
+		assert.Equal(t, "templates.tagName", result.Findings[0].Field)
+	})
+
+	t.Run("missing template file", func(t *testing.T) {
+		tempDir := setup(t)
+		setPackages(t, tempDir, "core")
+		configPath := filepath.Join(tempDir, ".shipyard", "shipyard.yaml")
+		cfg, err := config.Load(configPath)
+		require.NoError(t, err)
+		cfg.Packages[0].Templates = &config.TemplateConfig{
+			Changelog: &config.TemplateSource{Source: "templates/missing.tmpl"},
+		}
+		require.NoError(t, config.WriteConfig(cfg, configPath))
+
+		result, err := validate(t, tempDir)
+		require.Error(t, err)
+		require.Len(t, result.Findings, 1)
+		assert.Equal(t, "packages[core].templates.changelog", result.Findings[0].Field)
+	})
+}
+
+// TestValidateCommand_Flags verifies --strict and --format
+func TestValidateCommand_Flags(t *testing.T) {
+	tempDir := t.TempDir()
+	initGitRepo(t, tempDir)
+	initShipyardConfig(t, tempDir)
+	writeGoVersion(t, tempDir, ".", "1.0.0")
+	writeGoVersion(t, tempDir, "api", "1.0.0")
+	require.NoError(t, runAdd(tempDir, AddOptions{
+		Packages:  []string{"core"},
+		Type:      "patch",
+		Summary:   "Fix bug",
+		Quiet:     true,
+		Timestamp: time.Date(2026, 1, 30, 14, 30, 22, 0, time.UTC),
+	}))
+	setPackages(t, tempDir, "api")
+	defer changeToDir(t, tempDir)()
+
+	run := func(args ...string) (string, error) {
+		root := &cobra.Command{Use: "shipyard", SilenceUsage: true, SilenceErrors: true}
+		root.PersistentFlags().BoolP("json", "j", false, "output in JSON format")
+		root.PersistentFlags().BoolP("quiet", "q", false, "suppress non-error output")
+		root.PersistentFlags().String("max-severity", "", "report every enabled rule at this level")
+		root.AddCommand(NewValidateCommand())
+		root.SetArgs(append([]string{"validate"}, args...))
+		var err error
+		output := captureOutput(func() {
+			err = root.Execute()
+		})
+		return output, err
+	}
+
+	t.Run("format json", func(t *testing.T) {
+		output, err := run("--format", "json")
+		require.NoError(t, err)
+		var result ValidateOutput
+		require.NoError(t, json.Unmarshal([]byte(output), &result))
+		assert.True(t, result.Valid)
+		assert.Len(t, result.Warnings, 1)
+	})
+
+	t.Run("strict fails on warnings", func(t *testing.T) {
+		_, err := run("--strict", "--quiet")
+		require.Error(t, err)
+	})
+
+	t.Run("strict conflicts with max severity warn", func(t *testing.T) {
+		_, err := run("--strict", "--max-severity", "warn")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--strict")
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		_, err := run("--format", "yaml")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--format")
+	})
+}
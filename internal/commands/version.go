@@ -2,39 +2,72 @@ package commands
 
 import (
 	"fmt"
+	"io"
 	"os"
-	"path/filepath"
-	"slices"
 	"sort"
+	"strings"
 	"time"
 
-	"github.com/NatoNathan/shipyard/internal/fileutil"
-
-	"github.com/NatoNathan/shipyard/internal/changelog"
 	"github.com/NatoNathan/shipyard/internal/config"
 	"github.com/NatoNathan/shipyard/internal/consignment"
-	"github.com/NatoNathan/shipyard/internal/ecosystem"
-	"github.com/NatoNathan/shipyard/internal/git"
-	"github.com/NatoNathan/shipyard/internal/graph"
-	"github.com/NatoNathan/shipyard/internal/history"
-	"github.com/NatoNathan/shipyard/internal/prerelease"
+	"github.com/NatoNathan/shipyard/internal/errors"
+	"github.com/NatoNathan/shipyard/internal/i18n"
+	"github.com/NatoNathan/shipyard/internal/prompt"
+	"github.com/NatoNathan/shipyard/internal/release"
+	"github.com/NatoNathan/shipyard/internal/runstate"
 	"github.com/NatoNathan/shipyard/internal/template"
 	"github.com/NatoNathan/shipyard/internal/ui"
 	"github.com/NatoNathan/shipyard/internal/version"
-	"github.com/NatoNathan/shipyard/pkg/semver"
-	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/NatoNathan/shipyard/pkg/shipyard"
 	"github.com/spf13/cobra"
 )
 
 // VersionCommandOptions holds options for the version command
 type VersionCommandOptions struct {
-	Preview  bool     // --preview: Show changes without applying
-	NoCommit bool     // --no-commit: Skip git commit
-	NoTag    bool     // --no-tag: Skip git tag creation
-	Packages []string // --package: Filter to specific packages
-	Verbose  bool     // --verbose: Show detailed output
+	Preview     bool     // --preview: Show changes without applying
+	NoCommit    bool     // --no-commit: Skip git commit
+	NoTag       bool     // --no-tag: Skip git tag creation
+	Packages    []string // --package: Filter to specific packages
+	Verbose     bool     // --verbose: Show detailed output
+	NoPublish   bool     // --no-publish: Skip post-release publishing
+	Prerelease  string   // --prerelease: Release as the next pre-release with this identifier
+	Fresh       bool     // --fresh: Refetch remote templates instead of using the cache
+	Template    string   // --template: Changelog template for every package, overriding the config
+	MaxSeverity string   // --max-severity (global): Override the level of every enabled rule
+	JSON        bool     // --json (global): Print the preview as JSON
+
+	RespectSchedule bool // --respect-schedule: Refuse to release outside the release window
+	IgnoreSchedule  bool // --ignore-schedule: Release even when releaseSchedule.enforce is set
+
+	Resume   bool // --resume: Finish an interrupted run from its checkpoint
+	AbortRun bool // --abort-run: Roll back an interrupted run from its checkpoint
+
+	Push       bool // --push: Push the release commit and tags to the configured remote
+	DryRunPush bool // --dry-run-push: Check push credentials and remote reachability only
+
+	GitHubRelease bool // --github-release: Publish a GitHub release per pushed tag
+	Draft         bool // --draft: Publish the GitHub releases as drafts
+
+	Manifest string // --manifest: Write the release manifest JSON to this path
+
+	IncludeFrozen bool // --include-frozen: Version frozen packages too
+
+	IncludeUnreleased bool // --include-unreleased: List consignments left pending under Unreleased in changelogs
+	Regenerate        bool // --regenerate: Rewrite changelogs from history and pending consignments only
+
+	NoHooks bool // --no-hooks: Skip the configured preVersion and postVersion hooks
+
+	Channel string // --channel: Release on this channel instead of the branch's
+
+	Consignments []string // --consignment: Release only these consignments
+
+	SetVersions []string // --set-version: package=version overriding the calculated version
+	Force       bool     // --force: Allow --set-version to go backwards
 }
 
+// hookOutput receives the streamed output of hooks; tests replace it
+var hookOutput io.Writer = os.Stdout
+
 // NewVersionCommand creates the version command
 func NewVersionCommand() *cobra.Command {
 	opts := &VersionCommandOptions{}
@@ -60,12 +93,54 @@ Archive journey in captain's log.`,
   # Sail specific vessels only
   shipyard version --package core --package api
 
+  # Ship two of the pending consignments, leaving the rest on the dock
+  shipyard version --consignment 20240101-120000-a1b2c3 --consignment 20240102-090000-d4e5f6
+
   # Navigate but don't record the voyage
   shipyard version --no-commit
 
   # Sail and record, but don't plant harbor markers
-  shipyard version --no-tag`,
+  shipyard version --no-tag
+
+  # Launch at 2.0.0 whatever the consignments call for
+  shipyard version --set-version core=2.0.0
+
+  # Cut a release candidate (1.1.0 -> 1.2.0-rc.1, then 1.2.0-rc.2)
+  shipyard version --prerelease rc
+
+  # Write every changelog with one template, ignoring per-package overrides
+  shipyard version --template builtin:keepachangelog
+
+  # Finish a release that was interrupted part way through
+  shipyard version --resume
+
+  # Or roll the interrupted release back instead
+  shipyard version --abort-run
+
+  # Release and push the commit and tags (token from SHIPYARD_GIT_TOKEN, or GITHUB_TOKEN on github.com)
+  shipyard version --push
+
+  # Check that the release could be pushed, without releasing
+  shipyard version --dry-run-push
+
+  # Release, push and publish a GitHub release for each tag (token from GITHUB_TOKEN)
+  shipyard version --push --github-release
+
+  # Release and write a machine-readable manifest for deploy tooling
+  shipyard version --manifest release.json
+
+  # Release on the beta channel (1.2.0 -> 1.3.0-beta.1), with its own history
+  shipyard version --channel beta`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.Fresh {
+				// Every template loader in this process reads the variable
+				if err := os.Setenv(template.FreshTemplatesEnv, "1"); err != nil {
+					return fmt.Errorf("failed to set %s: %w", template.FreshTemplatesEnv, err)
+				}
+			}
+			globalFlags := GetGlobalFlags(cmd)
+			opts.MaxSeverity = globalFlags.MaxSeverity
+			opts.JSON = globalFlags.JSON
 			return runVersion(opts)
 		},
 	}
@@ -75,10 +150,34 @@ Archive journey in captain's log.`,
 	cmd.Flags().BoolVar(&opts.NoCommit, "no-commit", false, "Skip creating git commit")
 	cmd.Flags().BoolVar(&opts.NoTag, "no-tag", false, "Skip creating git tags")
 	cmd.Flags().StringSliceVarP(&opts.Packages, "package", "p", []string{}, "Filter to specific packages (can be specified multiple times)")
+	cmd.Flags().StringSliceVar(&opts.Consignments, "consignment", []string{}, "Release only the consignment with this ID; the rest stay pending (can be specified multiple times)")
+	cmd.Flags().StringSliceVar(&opts.SetVersions, "set-version", []string{}, "Release a package at this version instead of the calculated one, as package=version or a version alone with one package (can be specified multiple times)")
+	cmd.Flags().BoolVar(&opts.Force, "force", false, "Allow --set-version to a version at or below the current or released version")
 	cmd.Flags().BoolVarP(&opts.Verbose, "verbose", "v", false, "Show detailed output")
-
-	// Register package name completion
+	cmd.Flags().BoolVar(&opts.NoPublish, "no-publish", false, "Skip publishing Helm charts to configured registries")
+	cmd.Flags().StringVar(&opts.Prerelease, "prerelease", "", "Release as a pre-release with this identifier (e.g. rc)")
+	cmd.Flags().StringVar(&opts.Template, "template", "", "Changelog template for every package, overriding the config (path or builtin name)")
+	cmd.Flags().BoolVar(&opts.Fresh, "fresh", false, "Refetch remote templates instead of using cached copies")
+	cmd.Flags().BoolVar(&opts.RespectSchedule, "respect-schedule", false, "Refuse to release outside the configured release window")
+	cmd.Flags().BoolVar(&opts.IgnoreSchedule, "ignore-schedule", false, "Release outside the window even when releaseSchedule.enforce is set")
+	cmd.Flags().BoolVar(&opts.Resume, "resume", false, "Finish an interrupted version run using its recorded plan")
+	cmd.Flags().BoolVar(&opts.AbortRun, "abort-run", false, "Roll back an interrupted version run")
+	cmd.Flags().BoolVar(&opts.Push, "push", false, "Push the release commit and tags to the remote (git.remote, default origin)")
+	cmd.Flags().BoolVar(&opts.DryRunPush, "dry-run-push", false, "Check push credentials and remote reachability without releasing or pushing")
+	cmd.Flags().BoolVar(&opts.GitHubRelease, "github-release", false, "Publish a GitHub release for each pushed tag (requires --push)")
+	cmd.Flags().BoolVar(&opts.Draft, "draft", false, "Publish the GitHub releases as drafts")
+	cmd.Flags().StringVar(&opts.Manifest, "manifest", "", "Write a JSON manifest of the release to this file")
+	cmd.Flags().BoolVar(&opts.IncludeFrozen, "include-frozen", false, "Version packages marked frozen in the config")
+	cmd.Flags().BoolVar(&opts.IncludeUnreleased, "include-unreleased", false, "List consignments left pending under an Unreleased heading in changelogs")
+	cmd.Flags().BoolVar(&opts.Regenerate, "regenerate", false, "Rewrite changelogs from history with pending consignments under Unreleased, without releasing")
+	cmd.Flags().BoolVar(&opts.NoHooks, "no-hooks", false, "Skip the configured preVersion and postVersion hooks")
+	cmd.Flags().StringVar(&opts.Channel, "channel", "", "Release on this channel (default: the channel mapped to the current branch)")
+	cmd.MarkFlagsMutuallyExclusive("resume", "abort-run")
+	cmd.MarkFlagsMutuallyExclusive("push", "dry-run-push")
+
+	// Register package name and consignment ID completion
 	RegisterPackageCompletions(cmd, "package")
+	RegisterConsignmentCompletions(cmd, "consignment")
 
 	// Register subcommands
 	cmd.AddCommand(NewPrereleaseCommand())
@@ -90,9 +189,9 @@ Archive journey in captain's log.`,
 
 // runVersion executes the version command logic in the current directory
 func runVersion(opts *VersionCommandOptions) error {
-	cwd, err := os.Getwd()
+	cwd, err := projectRoot()
 	if err != nil {
-		return fmt.Errorf("failed to get current directory: %w", err)
+		return err
 	}
 	return runVersionWithDir(cwd, opts)
 }
@@ -100,449 +199,397 @@ func runVersion(opts *VersionCommandOptions) error {
 // runVersionWithDir executes the version command logic in a specific directory
 func runVersionWithDir(projectPath string, opts *VersionCommandOptions) (err error) {
 	// Phase 1: Validation and initialization
-	if opts.Preview {
+	jsonPreview := opts.Preview && opts.JSON
+	if opts.Preview && !jsonPreview {
 		fmt.Println()
-		fmt.Println(ui.InfoMessage("Preview Mode (no changes will be applied)"))
+		if prompt.Accessible() {
+			fmt.Println(i18n.T("version.preview_mode_plain"))
+		} else {
+			fmt.Println(ui.InfoMessage(i18n.T("version.preview_mode")))
+		}
 		fmt.Println()
 	}
 
-	// 1. Load configuration
-	cfg, err := config.LoadFromDir(projectPath)
-	if err != nil {
-		return fmt.Errorf("failed to load configuration: %w", err)
+	if err := release.CheckPrereleaseID(opts.Prerelease); err != nil {
+		return err
 	}
-
-	// 2. Read pending consignments
-	consignmentsDir := filepath.Join(projectPath, cfg.Consignments.Path)
-	var consignments []*consignment.Consignment
-	if len(opts.Packages) > 0 {
-		consignments, err = consignment.ReadAllConsignmentsFiltered(consignmentsDir, opts.Packages)
-	} else {
-		consignments, err = consignment.ReadAllConsignments(consignmentsDir)
+	if opts.Template != "" {
+		if err := template.ValidateTemplate(opts.Template, template.TemplateTypeChangelog); err != nil {
+			return errors.NewValidationError("template", err.Error())
+		}
 	}
-	if err != nil {
-		return fmt.Errorf("failed to read consignments: %w", err)
+	if (opts.Resume || opts.AbortRun) && opts.Preview {
+		return errors.NewValidationError("preview", i18n.T("version.preview_with_resume"))
 	}
-
-	// If no consignments, nothing to do
-	if len(consignments) == 0 {
-		if opts.Verbose {
-			fmt.Println()
-			fmt.Println(ui.InfoMessage("No pending consignments found"))
-			fmt.Println()
-		}
-		return nil
+	if opts.Resume && opts.AbortRun {
+		return errors.NewValidationError("resume", i18n.T("version.resume_with_abort"))
 	}
-
-	// 3. Build dependency graph
-	depGraph, err := graph.BuildGraph(cfg)
-	if err != nil {
-		return fmt.Errorf("failed to build dependency graph: %w", err)
+	if opts.DryRunPush && (opts.Push || opts.Preview || opts.Resume || opts.AbortRun) {
+		return errors.NewValidationError("dry-run-push", i18n.T("version.dry_run_push_combined"))
 	}
-
-	// 4. Read current versions for all packages
-	currentVersions, err := ReadAllCurrentVersions(projectPath, cfg)
-	if err != nil {
-		return err
+	if opts.Push && opts.NoCommit {
+		return errors.NewValidationError("push", i18n.T("version.push_no_commit"))
 	}
-
-	// 5. Calculate version bumps (with propagation)
-	propagator, err := version.NewPropagator(depGraph)
-	if err != nil {
-		return fmt.Errorf("failed to create propagator: %w", err)
+	if opts.GitHubRelease && !opts.Preview && (!opts.Push || opts.NoTag) {
+		return errors.NewValidationError("github-release", i18n.T("version.github_release_needs_push"))
 	}
-	versionBumps, err := propagator.Propagate(currentVersions, consignments)
-	if err != nil {
-		return fmt.Errorf("failed to calculate version bumps: %w", err)
+	if opts.Draft && !opts.GitHubRelease {
+		return errors.NewValidationError("draft", i18n.T("version.draft_needs_github_release"))
 	}
-
-	// Preview mode: Show what would change and exit
-	if opts.Preview {
-		displayPreview(versionBumps, consignments, cfg)
-		return nil
+	if opts.Manifest != "" && (opts.Preview || opts.DryRunPush || opts.Resume || opts.AbortRun) {
+		return errors.NewValidationError("manifest", i18n.T("version.manifest_combined"))
 	}
 
-	// 6. Apply version bumps to files
-	tx := newFileTransaction()
-	var originalHeadSet bool
-	originalHead := plumbing.ZeroHash
-	commitCreated := false
-	var createdTags []string
-	defer func() {
-		if err != nil {
-			if len(createdTags) > 0 {
-				if rollbackErr := git.DeleteTags(projectPath, createdTags); rollbackErr != nil {
-					err = fmt.Errorf("%w; additionally failed to delete created tags: %v", err, rollbackErr)
-				}
-			}
-			if commitCreated && originalHeadSet {
-				if rollbackErr := git.ResetMixed(projectPath, originalHead); rollbackErr != nil {
-					err = fmt.Errorf("%w; additionally failed to roll back git commit: %v", err, rollbackErr)
-				}
-			}
-			if rollbackErr := tx.Rollback(); rollbackErr != nil {
-				err = fmt.Errorf("%w; additionally failed to roll back filesystem changes: %v", err, rollbackErr)
-			}
-		}
-	}()
-
-	// Build version map with new versions for context
-	allNewVersions := make(map[string]semver.Version)
-	for pkgName, pkgBump := range versionBumps {
-		allNewVersions[pkgName] = pkgBump.NewVersion
+	if opts.Regenerate && (opts.Preview || opts.Resume || opts.AbortRun || opts.DryRunPush || opts.Push || opts.Prerelease != "" || opts.Manifest != "") {
+		return errors.NewValidationError("regenerate", i18n.T("version.regenerate_combined"))
 	}
 
-	for _, pkg := range cfg.Packages {
-		bump, hasBump := versionBumps[pkg.Name]
-		if !hasBump {
-			continue
-		}
-
-		pkgPath := filepath.Join(projectPath, pkg.Path)
-
-		// Create handler context
-		handlerCtx := &ecosystem.HandlerContext{
-			AllVersions:   allNewVersions,
-			PackageConfig: &pkg,
-		}
-
-		handler, err := GetEcosystemHandlerWithContext(pkg, pkgPath, handlerCtx)
+	// An interrupted run is rolled back from its checkpoint alone
+	if opts.AbortRun {
+		run, err := release.Abort(projectPath)
 		if err != nil {
 			return err
 		}
-
-		for _, versionFile := range handler.GetVersionFiles() {
-			if err := tx.Backup(filepath.Join(pkgPath, versionFile)); err != nil {
-				return err
-			}
-		}
-
-		if err := handler.UpdateVersion(bump.NewVersion); err != nil {
-			return fmt.Errorf("failed to update version for %s: %w", pkg.Name, err)
-		}
-
-		if opts.Verbose {
-			fmt.Println(ui.Dimmed(fmt.Sprintf("Updated %s: %s -> %s", pkg.Name, bump.OldVersion, bump.NewVersion)))
-		}
+		fmt.Println(ui.SuccessMessage(i18n.T("version.rolled_back", release.DescribeProgress(run.Completed))))
+		return nil
 	}
 
-	// 7. Generate tag names (needed for history entries)
-	generator := changelog.NewChangelogGenerator()
-	generator.SetBaseDir(projectPath)
-
-	globalTagTemplateSource := "builtin:default"
-	globalTagTemplateInline := ""
-	if cfg.Templates.TagName != nil {
-		switch {
-		case cfg.Templates.TagName.Inline != "":
-			globalTagTemplateInline = cfg.Templates.TagName.Inline
-		case cfg.Templates.TagName.Source != "":
-			globalTagTemplateSource = cfg.Templates.TagName.Source
-		}
+	// 1. Load configuration
+	cfg, err := config.LoadFromDir(projectPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
 	}
 
-	packageTags := make(map[string]changelog.PackageTag)
-	for _, pkg := range cfg.Packages {
-		bump, hasBump := versionBumps[pkg.Name]
-		if !hasBump {
-			continue
-		}
-		var tagName, tagMsg string
-		if pkg.Templates != nil && pkg.Templates.TagName != nil {
-			switch {
-			case pkg.Templates.TagName.Inline != "":
-				tagName, tagMsg, err = generator.GeneratePackageTagWithContext(consignments, pkg.Name, bump.NewVersion, pkg.Templates.TagName.Inline)
-			case pkg.Templates.TagName.Source != "":
-				tagName, tagMsg, err = generator.GeneratePackageTag(consignments, pkg.Name, bump.NewVersion, pkg.Templates.TagName.Source)
-			default:
-				if globalTagTemplateInline != "" {
-					tagName, tagMsg, err = generator.GeneratePackageTagWithContext(consignments, pkg.Name, bump.NewVersion, globalTagTemplateInline)
-				} else {
-					tagName, tagMsg, err = generator.GeneratePackageTag(consignments, pkg.Name, bump.NewVersion, globalTagTemplateSource)
-				}
-			}
-		} else {
-			if globalTagTemplateInline != "" {
-				tagName, tagMsg, err = generator.GeneratePackageTagWithContext(consignments, pkg.Name, bump.NewVersion, globalTagTemplateInline)
-			} else {
-				tagName, tagMsg, err = generator.GeneratePackageTag(consignments, pkg.Name, bump.NewVersion, globalTagTemplateSource)
-			}
-		}
-		if err != nil {
-			return fmt.Errorf("failed to generate tag for package %s: %w", pkg.Name, err)
-		}
-		packageTags[pkg.Name] = changelog.PackageTag{Name: tagName, Message: tagMsg}
+	if opts.DryRunPush {
+		return checkReleasePush(projectPath, cfg)
 	}
 
-	// 8. Archive consignments to history with version context
-	historyPath := filepath.Join(projectPath, cfg.History.Path)
-
-	var historyEntries []history.Entry
-	for _, pkg := range cfg.Packages {
-		bump, hasBump := versionBumps[pkg.Name]
-		if !hasBump {
-			continue
-		}
-
-		pkgConsignments := filterConsignmentsForPackage(consignments, pkg.Name)
-		if len(pkgConsignments) == 0 {
-			continue
-		}
-
-		historyConsignments := make([]history.Consignment, len(pkgConsignments))
-		for i, c := range pkgConsignments {
-			historyConsignments[i] = history.Consignment{
-				ID:         c.ID,
-				Summary:    c.Summary,
-				ChangeType: string(c.ChangeType),
-				Metadata:   c.Metadata,
-			}
-		}
-
-		tagName := ""
-		if tag, exists := packageTags[pkg.Name]; exists {
-			tagName = tag.Name
-		}
-
-		entry := history.Entry{
-			Version:      bump.NewVersion.String(),
-			Package:      pkg.Name,
-			Tag:          tagName,
-			Timestamp:    time.Now(),
-			Consignments: historyConsignments,
+	// A resumed run finishes its recorded plan; nothing is recomputed
+	if opts.Resume {
+		return resumeVersion(projectPath, opts)
+	}
+	if !opts.Preview {
+		if err := release.CheckInterrupted(projectPath); err != nil {
+			return err
 		}
-		historyEntries = append(historyEntries, entry)
 	}
 
-	if err := tx.Backup(historyPath); err != nil {
+	// Each channel keeps its own history
+	opts.Channel, err = release.ResolveChannel(projectPath, cfg, opts.Channel)
+	if err != nil {
 		return err
 	}
-	if err := history.AppendToHistory(historyPath, historyEntries); err != nil {
-		return fmt.Errorf("failed to archive consignments: %w", err)
+	if opts.Channel != "" && !jsonPreview {
+		fmt.Println(ui.Dimmed(i18n.T("version.channel", opts.Channel, cfg.HistoryPathFor(opts.Channel))))
 	}
 
-	if opts.Verbose {
-		fmt.Println(ui.Dimmed(fmt.Sprintf("Archived %d history entry/entries to history", len(historyEntries))))
+	if opts.Regenerate {
+		return regenerateChangelogs(projectPath, cfg, opts)
 	}
 
-	// 9. Generate changelogs (must happen AFTER archiving so current version is in history)
-	allEntries, err := history.ReadHistory(historyPath)
+	setVersions, err := parseSetVersions(cfg, opts.SetVersions)
 	if err != nil {
-		return fmt.Errorf("failed to read history for changelog generation: %w", err)
+		return err
+	}
+	planOpts := shipyard.PlanOptions{
+		Packages:      opts.Packages,
+		Consignments:  opts.Consignments,
+		Channel:       opts.Channel,
+		Prerelease:    opts.Prerelease,
+		IncludeFrozen: opts.IncludeFrozen,
+		SetVersions:   setVersions,
+		Force:         opts.Force,
+	}
+	if opts.Preview {
+		return previewVersion(projectPath, cfg, opts, planOpts)
 	}
 
-	for _, pkg := range cfg.Packages {
-		_, hasBump := versionBumps[pkg.Name]
-		if !hasBump {
-			continue
-		}
-
-		pkgEntries := history.FilterByPackage(allEntries, pkg.Name)
-		if len(pkgEntries) == 0 {
-			continue
-		}
-
-		templateSource := "changelog"
-		if cfg.Templates.Changelog.Source != "" {
-			templateSource = cfg.Templates.Changelog.Source
-		}
-
-		changelogContent, err := template.RenderChangelogWithTemplate(pkgEntries, templateSource)
-		if err != nil {
-			return fmt.Errorf("failed to generate changelog for %s: %w", pkg.Name, err)
-		}
-
-		changelogPath := filepath.Join(projectPath, pkg.Path, "CHANGELOG.md")
-		if err := tx.Backup(changelogPath); err != nil {
-			return err
-		}
-		if err := fileutil.WriteFile(changelogPath, []byte(changelogContent), 0644); err != nil {
-			return fmt.Errorf("failed to write changelog for %s: %w", pkg.Name, err)
-		}
-
+	// 2. Release through the same API embedders use
+	project, err := shipyard.Open(projectPath)
+	if err != nil {
+		return err
+	}
+	result, err := project.Apply(shipyard.ApplyOptions{
+		PlanOptions:       planOpts,
+		Output:            versionOutput(opts.Verbose),
+		NoCommit:          opts.NoCommit,
+		NoTag:             opts.NoTag,
+		NoPublish:         opts.NoPublish,
+		NoHooks:           opts.NoHooks,
+		Template:          opts.Template,
+		IncludeUnreleased: opts.IncludeUnreleased,
+		MaxSeverity:       opts.MaxSeverity,
+		RespectSchedule:   opts.RespectSchedule,
+		IgnoreSchedule:    opts.IgnoreSchedule,
+		Push:              opts.Push,
+		GitHubRelease:     opts.GitHubRelease,
+		Draft:             opts.Draft,
+		Manifest:          opts.Manifest,
+	})
+	if result == nil {
+		return err
+	}
+	if len(result.Packages) == 0 {
 		if opts.Verbose {
-			fmt.Println(ui.Dimmed(fmt.Sprintf("Generated changelog for %s", pkg.Name)))
+			fmt.Println()
+			fmt.Println(ui.InfoMessage(i18n.T("version.no_consignments")))
+			fmt.Println()
 		}
+	} else {
+		displayReleaseResult(result)
+	}
+	if err != nil {
+		return err
 	}
+	printFrozenSummary(result.Frozen, result.Held)
+	return nil
+}
 
-	// 10. Delete processed consignment files
-	for _, c := range consignments {
-		consignmentPath := filepath.Join(consignmentsDir, c.ID+".md")
-		if err := tx.Backup(consignmentPath); err != nil {
-			return err
+// parseSetVersions reads --set-version values: package=version, or a bare
+// version when the config has a single package
+func parseSetVersions(cfg *config.Config, values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	versions := make(map[string]string, len(values))
+	for _, value := range values {
+		name, v, ok := strings.Cut(value, "=")
+		if !ok {
+			if len(cfg.Packages) != 1 {
+				return nil, errors.NewValidationError("set-version", i18n.T("version.set_version_format", value))
+			}
+			name, v = cfg.Packages[0].Name, value
 		}
-		if err := os.Remove(consignmentPath); err != nil {
-			return fmt.Errorf("failed to delete consignment %s: %w", c.ID, err)
+		if name == "" || v == "" {
+			return nil, errors.NewValidationError("set-version", i18n.T("version.set_version_format", value))
 		}
+		versions[name] = v
 	}
+	return versions, nil
+}
 
-	if opts.Verbose {
-		fmt.Println(ui.Dimmed(fmt.Sprintf("Deleted %d consignment file(s)", len(consignments))))
-	}
-
-	// 11. Git operations (commit and tag)
-	changedPackages := make(map[string]bool)
-	for pkgName := range versionBumps {
-		changedPackages[pkgName] = true
-	}
-	filesToStage, err := CollectVersionFiles(projectPath, cfg, changedPackages)
+// resumeVersion finishes an interrupted run from its checkpoint
+func resumeVersion(projectPath string, opts *VersionCommandOptions) error {
+	project, err := shipyard.Open(projectPath)
 	if err != nil {
 		return err
 	}
-
-	if _, err := os.Stat(historyPath); err == nil {
-		filesToStage = append(filesToStage, historyPath)
+	if run, err := project.Interrupted(); err == nil && run != nil {
+		fmt.Println(ui.InfoMessage(i18n.T("version.resuming", run.StartedAt.Format(time.RFC3339), describeProgress(run.Completed))))
 	}
+	result, err := project.Resume(shipyard.ResumeOptions{Output: versionOutput(opts.Verbose)})
+	if result != nil {
+		displayReleaseResult(result)
+	}
+	return err
+}
 
-	for _, c := range consignments {
-		consignmentPath := filepath.Join(consignmentsDir, c.ID+".md")
-		filesToStage = append(filesToStage, consignmentPath)
+// previewVersion shows the release the pending consignments would make
+// without changing anything. It reads the plan's internals that Project
+// does not expose, so it plans with the project's settings itself.
+func previewVersion(projectPath string, cfg *config.Config, opts *VersionCommandOptions, planOpts shipyard.PlanOptions) error {
+	report := func(e release.Event) {
+		printReleaseEvent(shipyard.Event{Level: shipyard.Level(e.Level), Message: e.Message}, opts.Verbose)
+	}
+	settings := release.SettingsFor(projectPath, cfg)
+	plan, err := release.NewPlan(projectPath, cfg, settings, release.PlanOptions(planOpts), report)
+	if err != nil {
+		return err
 	}
 
-	prereleaseStatePath := filepath.Join(projectPath, ".shipyard", "prerelease.yml")
-	if prerelease.Exists(prereleaseStatePath) {
-		if err := tx.Backup(prereleaseStatePath); err != nil {
-			return err
-		}
-		if err := prerelease.DeleteState(prereleaseStatePath); err != nil {
-			return fmt.Errorf("failed to delete prerelease state: %w", err)
+	// If no consignments, nothing to do; a JSON preview still reports the graph
+	if opts.JSON {
+		if plan.Current == nil {
+			if plan.Current, err = release.ReadChannelCurrentVersions(projectPath, cfg, settings, plan.Channel); err != nil {
+				return err
+			}
 		}
-		filesToStage = append(filesToStage, prereleaseStatePath)
+		preview := newVersionPreview(plan.Bumps, plan.Explain())
+		preview.Channel = plan.Channel
+		return PrintJSON(os.Stdout, preview)
+	}
+	defer printFrozenSummary(plan.Frozen, consignmentIDs(plan.Held))
+	if len(plan.Consignments) == 0 {
 		if opts.Verbose {
-			fmt.Println(ui.Dimmed("Deleted .shipyard/prerelease.yml"))
+			fmt.Println()
+			fmt.Println(ui.InfoMessage(i18n.T("version.no_consignments")))
+			fmt.Println()
 		}
+		return nil
+	}
+	if opts.Verbose {
+		fmt.Println(ui.Dimmed(i18n.T("version.apply_order", release.FormatApplyOrder(plan.Order, plan.Bumps))))
 	}
 
-	shouldCommit := !opts.NoCommit && len(filesToStage) > 0
-	shouldTag := !opts.NoTag && shouldCommit && len(packageTags) > 0
+	gitPreview := previewGitOperations(projectPath, cfg, settings, opts, plan.Packages, plan.Bumps, plan.Consignments)
+	displayPreview(plan.Bumps, plan.Consignments, plan.Explain(), gitPreview)
+	return nil
+}
 
-	if shouldCommit || shouldTag {
-		originalHead, err = git.HeadHash(projectPath)
-		if err != nil {
-			return fmt.Errorf("failed to capture git HEAD before version changes: %w", err)
-		}
-		originalHeadSet = true
-	}
-
-	var annotatedTags []struct {
-		name    string
-		message string
-	}
-	var lightweightTags []string
-	var allTagNames []string
-
-	if shouldTag {
-		for pkgName, tag := range packageTags {
-			allTagNames = append(allTagNames, tag.Name)
-			if tag.Message != "" {
-				annotatedTags = append(annotatedTags, struct {
-					name    string
-					message string
-				}{name: tag.Name, message: tag.Message})
-			} else {
-				lightweightTags = append(lightweightTags, tag.Name)
-			}
+// versionOutput prints the events of a release as they happen and streams
+// hook output to hookOutput
+func versionOutput(verbose bool) shipyard.Output {
+	return shipyard.Output{
+		HookOutput: hookOutput,
+		OnEvent:    func(e shipyard.Event) { printReleaseEvent(e, verbose) },
+	}
+}
 
-			if opts.Verbose {
-				if tag.Message != "" {
-					fmt.Println(ui.Dimmed(fmt.Sprintf("Creating annotated tag for %s: %s", pkgName, tag.Name)))
-				} else {
-					fmt.Println(ui.Dimmed(fmt.Sprintf("Creating lightweight tag for %s: %s", pkgName, tag.Name)))
-				}
-			}
+// printReleaseEvent prints an event of a release; details only when verbose
+func printReleaseEvent(e shipyard.Event, verbose bool) {
+	switch e.Level {
+	case shipyard.LevelDetail:
+		if verbose {
+			fmt.Println(ui.Dimmed(e.Message))
 		}
+	case shipyard.LevelInfo:
+		fmt.Println(ui.InfoMessage(e.Message))
+	default:
+		fmt.Println(ui.WarningMessage(e.Message))
+	}
+}
 
-		if err := git.EnsureTagsAbsent(projectPath, allTagNames); err != nil {
-			return fmt.Errorf("failed to validate tags: %w", err)
-		}
+// displayReleaseResult prints the versions a release wrote, then what it
+// published
+func displayReleaseResult(result *shipyard.Result) {
+	fmt.Println()
+	fmt.Println(ui.SuccessMessage(i18n.T("version.versioned", len(result.Packages))))
+	var summaryRows [][]string
+	for _, pkg := range result.Packages {
+		summaryRows = append(summaryRows, []string{pkg.Package, pkg.OldVersion, pkg.NewVersion})
 	}
+	fmt.Println(ui.Table([]string{i18n.T("label.package"), i18n.T("label.old_version"), i18n.T("label.new_version")}, summaryRows))
 
-	if shouldCommit {
-		if err := git.StageFiles(projectPath, filesToStage); err != nil {
-			return fmt.Errorf("failed to stage files: %w", err)
-		}
+	displayChartPublishResults(result.Charts, result.Committed && !result.ArtifactsCommitted)
+	if result.Pushed != nil {
+		fmt.Println(ui.SuccessMessage(i18n.T("version.pushed", len(result.Pushed.Tags), result.Pushed.Remote)))
+	}
+	displayGitHubReleases(result.GitHubReleases, result.GitHubSkipped)
+	if result.Manifest != "" {
+		fmt.Println(ui.SuccessMessage(i18n.T("version.manifest_written", result.Manifest)))
+	}
+}
 
-		commitTemplateSource := "builtin:default"
-		if cfg.Templates.CommitMessage != nil && cfg.Templates.CommitMessage.Source != "" {
-			commitTemplateSource = cfg.Templates.CommitMessage.Source
-		}
+// displayGitHubReleases prints one line per published GitHub release
+func displayGitHubReleases(results []shipyard.GitHubRelease, skipped error) {
+	if skipped != nil {
+		fmt.Println(ui.WarningMessage(i18n.T("version.github_release_skipped", skipped)))
+		return
+	}
+	if len(results) == 0 {
+		return
+	}
 
-		changelogBumps := make(map[string]changelog.VersionBump)
-		for name, bump := range versionBumps {
-			changelogBumps[name] = changelog.VersionBump{
-				Package:    bump.Package,
-				OldVersion: bump.OldVersion,
-				NewVersion: bump.NewVersion,
-				ChangeType: bump.ChangeType,
-			}
+	fmt.Println()
+	failed := false
+	for _, result := range results {
+		switch {
+		case result.Err != nil:
+			failed = true
+			fmt.Println(ui.WarningMessage(i18n.T("version.github_release_failed", result.Tag, result.Err)))
+		case result.Created:
+			fmt.Println(ui.SuccessMessage(i18n.T("version.github_release_created", result.Tag, result.URL)))
+		default:
+			fmt.Println(ui.SuccessMessage(i18n.T("version.github_release_updated", result.Tag, result.URL)))
 		}
+	}
+	if failed {
+		fmt.Println(ui.Dimmed(i18n.T("version.github_release_retry")))
+	}
+}
 
-		commitMessage, err := generator.GenerateCommitMessage(consignments, changelogBumps, commitTemplateSource)
-		if err != nil {
-			return fmt.Errorf("failed to generate commit message: %w", err)
-		}
+// describeProgress lists the completed phases of an interrupted run
+func describeProgress(completed []string) string {
+	phases := make([]runstate.Phase, len(completed))
+	for i, phase := range completed {
+		phases[i] = runstate.Phase(phase)
+	}
+	return release.DescribeProgress(phases)
+}
 
-		if err := git.CreateCommit(projectPath, commitMessage); err != nil {
-			return fmt.Errorf("failed to create commit: %w", err)
-		}
-		commitCreated = true
+// consignmentIDs returns the IDs of consignments in order
+func consignmentIDs(consignments []*consignment.Consignment) []string {
+	ids := make([]string, 0, len(consignments))
+	for _, c := range consignments {
+		ids = append(ids, c.ID)
+	}
+	return ids
+}
 
-		if opts.Verbose {
-			fmt.Println(ui.Dimmed(fmt.Sprintf("Created commit with %d file(s)", len(filesToStage))))
-		}
+// printFrozenSummary lists the frozen packages that were not versioned and
+// the consignments left pending for them
+func printFrozenSummary(skipped, heldIDs []string) {
+	if len(skipped) == 0 {
+		return
 	}
+	fmt.Println(ui.InfoMessage(i18n.T("version.frozen_skipped", strings.Join(skipped, ", "))))
+	if len(heldIDs) > 0 {
+		fmt.Println(ui.Dimmed(i18n.T("version.frozen_retained", len(heldIDs), strings.Join(heldIDs, ", "))))
+	}
+}
 
-	if shouldTag {
-		for _, tag := range annotatedTags {
-			if err := git.CreateAnnotatedTag(projectPath, tag.name, tag.message); err != nil {
-				return fmt.Errorf("failed to create annotated tag %s: %w", tag.name, err)
-			}
-			createdTags = append(createdTags, tag.name)
-		}
+// VersionPreview is the output of version --preview --json
+type VersionPreview struct {
+	Packages    []VersionPreviewPackage `json:"packages"`
+	Propagation *version.Propagation    `json:"propagation"`
+	Channel     string                  `json:"channel,omitempty"`
+}
 
-		for _, tagName := range lightweightTags {
-			if err := git.CreateLightweightTag(projectPath, tagName); err != nil {
-				return fmt.Errorf("failed to create lightweight tag %s: %w", tagName, err)
-			}
-			createdTags = append(createdTags, tagName)
-		}
+// VersionPreviewPackage is one package that would be released
+type VersionPreviewPackage struct {
+	Name       string `json:"name"`
+	OldVersion string `json:"oldVersion"`
+	NewVersion string `json:"newVersion"`
+	ChangeType string `json:"changeType"`
+	Source     string `json:"source"`
+}
 
-		if opts.Verbose {
-			fmt.Println(ui.Dimmed(fmt.Sprintf("Created %d tag(s)", len(packageTags))))
-		}
+// newVersionPreview builds the JSON preview, with packages sorted by name
+func newVersionPreview(versionBumps map[string]version.VersionBump, propagation *version.Propagation) *VersionPreview {
+	preview := &VersionPreview{Packages: []VersionPreviewPackage{}, Propagation: propagation}
+	names := make([]string, 0, len(versionBumps))
+	for name := range versionBumps {
+		names = append(names, name)
 	}
-
-	// Success summary
-	fmt.Println()
-	fmt.Println(ui.SuccessMessage(fmt.Sprintf("Versioned %d package(s)", len(versionBumps))))
-	var summaryRows [][]string
-	for _, pkg := range cfg.Packages {
-		if bump, ok := versionBumps[pkg.Name]; ok {
-			summaryRows = append(summaryRows, []string{
-				pkg.Name,
-				bump.OldVersion.String(),
-				bump.NewVersion.String(),
-			})
-		}
+	sort.Strings(names)
+	for _, pkgName := range names {
+		bump := versionBumps[pkgName]
+		preview.Packages = append(preview.Packages, VersionPreviewPackage{
+			Name:       pkgName,
+			OldVersion: bump.OldVersion.String(),
+			NewVersion: bump.NewVersion.String(),
+			ChangeType: bump.ChangeType,
+			Source:     bump.Source,
+		})
 	}
-	fmt.Println(ui.Table([]string{"Package", "Old Version", "New Version"}, summaryRows))
-
-	return nil
+	return preview
 }
 
-// filterConsignmentsForPackage returns consignments that affect the given package
-func filterConsignmentsForPackage(consignments []*consignment.Consignment, packageName string) []*consignment.Consignment {
-	var filtered []*consignment.Consignment
-	for _, c := range consignments {
-		if slices.Contains(c.Packages, packageName) {
-			filtered = append(filtered, c)
+// previewGitOperations renders the release commit message and each package's
+// tag name with the calculated versions. A template that fails to render is
+// reported in the preview rather than aborting it.
+func previewGitOperations(projectPath string, cfg *config.Config, settings release.Settings, opts *VersionCommandOptions, releasePackages []config.Package, versionBumps map[string]version.VersionBump, consignments []*consignment.Consignment) ui.GitPreview {
+	preview := ui.GitPreview{NoCommit: opts.NoCommit, NoTag: opts.NoTag}
+	generator := release.NewGenerator(projectPath, cfg, settings)
+	generator.SetChannel(opts.Channel)
+
+	if !opts.NoCommit {
+		preview.CommitMessage, preview.CommitErr = release.CommitMessage(generator, cfg, consignments, versionBumps)
+	}
+	for _, pkg := range releasePackages {
+		bump, hasBump := versionBumps[pkg.Name]
+		if !hasBump {
+			continue
 		}
+		tagName, _, err := release.PackageTag(generator, cfg, pkg, consignments, bump.NewVersion)
+		preview.Tags = append(preview.Tags, ui.TagPreview{Package: pkg.Name, Name: tagName, Err: err})
 	}
-	return filtered
+	return preview
 }
 
-// displayPreview shows what changes would be made without applying them
-func displayPreview(versionBumps map[string]version.VersionBump, consignments []*consignment.Consignment, cfg *config.Config) {
+// displayPreview shows what changes would be made, and the git operations
+// that would run, without applying them
+func displayPreview(versionBumps map[string]version.VersionBump, consignments []*consignment.Consignment, propagation *version.Propagation, gitPreview ui.GitPreview) {
 	// Convert version bumps to PackageChange structs for preview display
 	var changes []ui.PackageChange
 
@@ -554,12 +601,12 @@ func displayPreview(versionBumps map[string]version.VersionBump, consignments []
 	for _, pkgName := range previewKeys {
 		bump := versionBumps[pkgName]
 		// Get consignments for this package
-		pkgConsignments := filterConsignmentsForPackage(consignments, pkgName)
+		pkgConsignments := release.ConsignmentsFor(consignments, pkgName)
 
 		// Extract change summaries
 		var changeSummaries []string
 		for _, c := range pkgConsignments {
-			changeSummaries = append(changeSummaries, c.Summary)
+			changeSummaries = append(changeSummaries, c.SummaryFor(pkgName))
 		}
 
 		changes = append(changes, ui.PackageChange{
@@ -568,13 +615,25 @@ func displayPreview(versionBumps map[string]version.VersionBump, consignments []
 			NewVersion: bump.NewVersion,
 			ChangeType: string(bump.ChangeType),
 			Changes:    changeSummaries,
+			Because:    propagation.Because(pkgName),
 		})
 	}
 
 	// Display the preview
+	if prompt.Accessible() {
+		fmt.Println(ui.RenderPlainPreview(changes))
+		fmt.Println()
+		fmt.Println(ui.RenderPlainGitPreview(gitPreview))
+		fmt.Println()
+		fmt.Println(i18n.T("version.preview_hint"))
+		fmt.Println()
+		return
+	}
 	preview := ui.RenderPreview(changes)
 	fmt.Println(preview)
 	fmt.Println()
-	fmt.Println(ui.InfoMessage("Run without --preview to apply these changes"))
+	fmt.Println(ui.RenderGitPreview(gitPreview))
+	fmt.Println()
+	fmt.Println(ui.InfoMessage(i18n.T("version.preview_hint")))
 	fmt.Println()
 }
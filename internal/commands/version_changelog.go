@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/fileutil"
+	"github.com/NatoNathan/shipyard/internal/i18n"
+	"github.com/NatoNathan/shipyard/internal/release"
+	"github.com/NatoNathan/shipyard/internal/ui"
+)
+
+// regenerateChangelogs rewrites each package's changelog from history, with
+// the pending consignments under Unreleased. Nothing is versioned, committed
+// or tagged.
+func regenerateChangelogs(projectPath string, cfg *config.Config, opts *VersionCommandOptions) error {
+	changelogs, err := renderChangelogs(projectPath, cfg, release.SettingsFor(projectPath, cfg), changelogQuery{
+		Packages:   opts.Packages,
+		Channel:    opts.Channel,
+		Template:   opts.Template,
+		Unreleased: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, c := range changelogs {
+		changelogPath, err := release.ChangelogPath(projectPath, c.Package)
+		if err != nil {
+			return err
+		}
+		if err := fileutil.WriteFile(changelogPath, []byte(c.Content), 0644); err != nil {
+			return fmt.Errorf("failed to write changelog for %s: %w", c.Package.Name, err)
+		}
+		if !opts.JSON {
+			fmt.Println(ui.SuccessMessage(i18n.T("version.changelog_generated", c.Package.Name)))
+		}
+	}
+	return nil
+}
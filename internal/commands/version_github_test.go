@@ -0,0 +1,146 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NatoNathan/shipyard/internal/github"
+	"github.com/NatoNathan/shipyard/internal/httpclient"
+	"github.com/NatoNathan/shipyard/internal/release"
+	"github.com/NatoNathan/shipyard/internal/upgrade"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeReleaseAPI keeps releases in memory, keyed by tag
+type fakeReleaseAPI struct {
+	releases map[string]*upgrade.ReleaseInfo
+	created  int
+	updated  int
+	err      error
+}
+
+func (f *fakeReleaseAPI) GetLatestRelease(ctx context.Context, owner, repo string) (*upgrade.ReleaseInfo, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *fakeReleaseAPI) GetReleaseByTag(ctx context.Context, owner, repo, tag string) (*upgrade.ReleaseInfo, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.releases[tag], nil
+}
+
+func (f *fakeReleaseAPI) CreateRelease(ctx context.Context, owner, repo string, release *upgrade.CreateReleaseRequest) (*upgrade.ReleaseInfo, error) {
+	f.created++
+	info := &upgrade.ReleaseInfo{ID: int64(len(f.releases) + 1), TagName: release.TagName, Name: release.Name, Body: release.Body, Draft: release.Draft}
+	f.releases[release.TagName] = info
+	return info, nil
+}
+
+func (f *fakeReleaseAPI) UpdateRelease(ctx context.Context, owner, repo string, id int64, release *upgrade.CreateReleaseRequest) (*upgrade.ReleaseInfo, error) {
+	f.updated++
+	info := &upgrade.ReleaseInfo{ID: id, TagName: release.TagName, Name: release.Name, Body: release.Body, Draft: release.Draft}
+	f.releases[release.TagName] = info
+	return info, nil
+}
+
+// useFakeReleaseAPI routes GitHub release publishing to an in-memory fake
+func useFakeReleaseAPI(t *testing.T) *fakeReleaseAPI {
+	t.Helper()
+	fake := &fakeReleaseAPI{releases: make(map[string]*upgrade.ReleaseInfo)}
+	original := release.NewGitHubReleaseAPI
+	release.NewGitHubReleaseAPI = func(string, httpclient.TLSOptions) github.ReleaseAPI { return fake }
+	t.Cleanup(func() { release.NewGitHubReleaseAPI = original })
+	t.Setenv("GITHUB_TOKEN", "token")
+	return fake
+}
+
+// setupGitHubReleaseRepo is setupResumeTestRepo with a pushable origin and
+// a committed github section naming octo/shipyard
+func setupGitHubReleaseRepo(t *testing.T) (string, *gogit.Repository) {
+	t.Helper()
+	tempDir, repo, _ := setupResumeTestRepo(t)
+	addBareOrigin(t, repo)
+
+	configPath := filepath.Join(tempDir, ".shipyard", "shipyard.yaml")
+	content, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	content = append(content, []byte("\ngithub:\n  owner: octo\n  repo: shipyard\n")...)
+	require.NoError(t, os.WriteFile(configPath, content, 0644))
+
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+	_, err = wt.Add(".shipyard/shipyard.yaml")
+	require.NoError(t, err)
+	_, err = wt.Commit("configure github", &gogit.CommitOptions{
+		Author: &object.Signature{Name: "Test", Email: "test@example.com"},
+	})
+	require.NoError(t, err)
+	return tempDir, repo
+}
+
+func TestVersionCommand_GitHubRelease(t *testing.T) {
+	tempDir, _ := setupGitHubReleaseRepo(t)
+	fake := useFakeReleaseAPI(t)
+
+	var err error
+	output := captureOutput(func() {
+		err = runVersionInDir(tempDir, &VersionCommandOptions{Push: true, GitHubRelease: true, Draft: true})
+	})
+	require.NoError(t, err)
+
+	release := fake.releases["v1.1.0"]
+	require.NotNil(t, release)
+	assert.Equal(t, 1, fake.created)
+	assert.True(t, release.Draft)
+	assert.Contains(t, release.Body, "Add feature")
+	assert.Contains(t, output, "https://github.com/octo/shipyard/releases/tag/v1.1.0")
+
+}
+
+func TestVersionCommand_GitHubReleaseFailureKeepsRelease(t *testing.T) {
+	tempDir, repo := setupGitHubReleaseRepo(t)
+	fake := useFakeReleaseAPI(t)
+	fake.err = errors.New("GitHub API returned status 502")
+
+	var err error
+	output := captureOutput(func() {
+		err = runVersionInDir(tempDir, &VersionCommandOptions{Push: true, GitHubRelease: true})
+	})
+	require.NoError(t, err, "the pushed release is not rolled back")
+	assert.Contains(t, output, "status 502")
+	assert.Contains(t, tagNames(t, repo), "v1.1.0")
+}
+
+func TestVersionCommand_GitHubReleaseValidation(t *testing.T) {
+	t.Run("requires push", func(t *testing.T) {
+		tempDir, _ := setupGitHubReleaseRepo(t)
+		err := runVersionInDir(tempDir, &VersionCommandOptions{GitHubRelease: true})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--push")
+	})
+
+	t.Run("draft requires github release", func(t *testing.T) {
+		tempDir, _ := setupGitHubReleaseRepo(t)
+		err := runVersionInDir(tempDir, &VersionCommandOptions{Push: true, Draft: true})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--github-release")
+	})
+
+	t.Run("missing token fails pre-flight", func(t *testing.T) {
+		tempDir, repo := setupGitHubReleaseRepo(t)
+		useFakeReleaseAPI(t)
+		t.Setenv("GITHUB_TOKEN", "")
+
+		err := runVersionInDir(tempDir, &VersionCommandOptions{Push: true, GitHubRelease: true})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "GITHUB_TOKEN")
+		assert.Empty(t, tagNames(t, repo), "no release should be made")
+	})
+}
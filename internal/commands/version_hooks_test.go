@@ -0,0 +1,205 @@
+package commands
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/NatoNathan/shipyard/internal/hooks"
+	"github.com/NatoNathan/shipyard/internal/runstate"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupHooksTestRepo creates a committed repository with one pending
+// consignment, the global hooks and test-package's hooks configured
+func setupHooksTestRepo(t *testing.T, globalHooks, packageHooks string) (string, *gogit.Repository, plumbing.Hash) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("hook scripts use sh")
+	}
+	tempDir := setupVersionTestRepo(t)
+	configPath := filepath.Join(tempDir, ".shipyard", "shipyard.yaml")
+	content, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	config := strings.Replace(string(content), "    ecosystem: go\n", "    ecosystem: go\n"+packageHooks, 1) + globalHooks
+	require.NoError(t, os.WriteFile(configPath, []byte(config), 0644))
+
+	repo, err := gogit.PlainInit(tempDir, false)
+	require.NoError(t, err)
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+	createTestConsignmentForVersion(t, filepath.Join(tempDir, ".shipyard", "consignments"), "c1", []string{"test-package"}, "minor", "Add feature")
+	_, err = wt.Add(".")
+	require.NoError(t, err)
+	head, err := wt.Commit("initial commit", &gogit.CommitOptions{
+		Author: &object.Signature{Name: "Test", Email: "test@example.com"},
+	})
+	require.NoError(t, err)
+	return tempDir, repo, head
+}
+
+// writeHookScript writes a script outside the repository and returns the
+// command that runs it
+func writeHookScript(t *testing.T, script string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "hook.sh")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0755))
+	return "sh " + path
+}
+
+// captureHookOutput collects hook output for the rest of the test
+func captureHookOutput(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	original := hookOutput
+	hookOutput = &buf
+	t.Cleanup(func() { hookOutput = original })
+	return &buf
+}
+
+func TestVersionCommand_HooksReceiveRelease(t *testing.T) {
+	outDir := t.TempDir()
+	record := writeHookScript(t, `echo "$1 $SHIPYARD_PACKAGE $SHIPYARD_OLD_VERSION $SHIPYARD_NEW_VERSION $SHIPYARD_TAG $(pwd)" >> "`+filepath.Join(outDir, "calls.txt")+`"
+echo "ran $1"
+`)
+	tempDir, _, _ := setupHooksTestRepo(t, `hooks:
+  preVersion:
+    - `+record+` global-pre
+`, `    hooks:
+      preVersion:
+        - `+record+` package-pre
+      postVersion:
+        - `+record+` package-post
+`)
+	output := captureHookOutput(t)
+
+	captureOutput(func() {
+		require.NoError(t, runVersionInDir(tempDir, &VersionCommandOptions{NoPublish: true}))
+	})
+
+	pkgDir, err := filepath.EvalSymlinks(filepath.Join(tempDir, "test-package"))
+	require.NoError(t, err)
+	calls, err := os.ReadFile(filepath.Join(outDir, "calls.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"global-pre test-package 1.0.0 1.1.0 v1.1.0 " + pkgDir,
+		"package-pre test-package 1.0.0 1.1.0 v1.1.0 " + pkgDir,
+		"package-post test-package 1.0.0 1.1.0 v1.1.0 " + pkgDir,
+	}, strings.Split(strings.TrimSpace(string(calls)), "\n"))
+
+	assert.Contains(t, output.String(), "[test-package preVersion 1/2] $ "+record+" global-pre\n")
+	assert.Contains(t, output.String(), "[test-package preVersion 2/2] ran package-pre\n")
+	assert.Contains(t, output.String(), "[test-package postVersion 1/1] ran package-post\n")
+}
+
+func TestVersionCommand_PreVersionHookFailureChangesNothing(t *testing.T) {
+	tempDir, repo, initialHead := setupHooksTestRepo(t, `hooks:
+  preVersion:
+    - echo checking && exit 3
+`, "")
+	captureHookOutput(t)
+
+	var err error
+	captureOutput(func() {
+		err = runVersionInDir(tempDir, &VersionCommandOptions{NoPublish: true})
+	})
+	require.Error(t, err)
+	var hookErr *hooks.Error
+	require.ErrorAs(t, err, &hookErr)
+	assert.Equal(t, hooks.PreVersion, hookErr.Stage)
+
+	versionContent, err := os.ReadFile(filepath.Join(tempDir, "test-package", "version.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(versionContent), `"1.0.0"`)
+	assert.FileExists(t, filepath.Join(tempDir, ".shipyard", "consignments", "c1.md"))
+	assert.NoFileExists(t, runstate.Path(tempDir))
+
+	head, err := repo.Head()
+	require.NoError(t, err)
+	assert.Equal(t, initialHead, head.Hash())
+}
+
+func TestVersionCommand_PostVersionHookFailureRollsBack(t *testing.T) {
+	tempDir, repo, initialHead := setupHooksTestRepo(t, `hooks:
+  postVersion:
+    - echo generated > generated.txt && echo changed >> version.go && exit 1
+`, "")
+	captureHookOutput(t)
+
+	var err error
+	captureOutput(func() {
+		err = runVersionInDir(tempDir, &VersionCommandOptions{NoPublish: true})
+	})
+	require.Error(t, err)
+	var hookErr *hooks.Error
+	require.ErrorAs(t, err, &hookErr)
+	assert.Equal(t, hooks.PostVersion, hookErr.Stage)
+
+	versionContent, err := os.ReadFile(filepath.Join(tempDir, "test-package", "version.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(versionContent), `"1.0.0"`)
+	assert.NotContains(t, string(versionContent), "changed")
+	assert.NoFileExists(t, filepath.Join(tempDir, "test-package", "generated.txt"))
+	assert.NoFileExists(t, filepath.Join(tempDir, "test-package", "CHANGELOG.md"))
+	assert.FileExists(t, filepath.Join(tempDir, ".shipyard", "consignments", "c1.md"))
+	assert.NoFileExists(t, runstate.Path(tempDir))
+
+	head, err := repo.Head()
+	require.NoError(t, err)
+	assert.Equal(t, initialHead, head.Hash(), "no release commit")
+}
+
+func TestVersionCommand_PostVersionHookFilesAreCommitted(t *testing.T) {
+	tempDir, repo, initialHead := setupHooksTestRepo(t, `hooks:
+  postVersion:
+    - echo "$SHIPYARD_NEW_VERSION" > generated.txt
+`, "")
+	captureHookOutput(t)
+
+	captureOutput(func() {
+		require.NoError(t, runVersionInDir(tempDir, &VersionCommandOptions{NoPublish: true}))
+	})
+
+	head, err := repo.Head()
+	require.NoError(t, err)
+	commit, err := repo.CommitObject(head.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, []plumbing.Hash{initialHead}, commit.ParentHashes)
+	file, err := commit.File("test-package/generated.txt")
+	require.NoError(t, err)
+	contents, err := file.Contents()
+	require.NoError(t, err)
+	assert.Equal(t, "1.1.0\n", contents)
+
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+	status, err := wt.Status()
+	require.NoError(t, err)
+	assert.NotContains(t, status.String(), "generated.txt", "hook output left uncommitted")
+}
+
+func TestVersionCommand_NoHooks(t *testing.T) {
+	tempDir, _, _ := setupHooksTestRepo(t, `hooks:
+  preVersion:
+    - exit 1
+  postVersion:
+    - exit 1
+`, "")
+	output := captureHookOutput(t)
+
+	captureOutput(func() {
+		require.NoError(t, runVersionInDir(tempDir, &VersionCommandOptions{NoPublish: true, NoHooks: true}))
+	})
+
+	assert.Empty(t, output.String())
+	versionContent, err := os.ReadFile(filepath.Join(tempDir, "test-package", "version.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(versionContent), `"1.1.0"`)
+}
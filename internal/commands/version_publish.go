@@ -0,0 +1,36 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/NatoNathan/shipyard/internal/i18n"
+	"github.com/NatoNathan/shipyard/internal/ui"
+	"github.com/NatoNathan/shipyard/pkg/shipyard"
+)
+
+// displayChartPublishResults prints one line per published chart, and says
+// when recorded digests were left out of the release commits
+func displayChartPublishResults(results []shipyard.Chart, digestsUncommitted bool) {
+	if len(results) == 0 {
+		return
+	}
+
+	fmt.Println()
+	recorded := false
+	for _, r := range results {
+		switch {
+		case r.Err != nil && r.Reference == "":
+			fmt.Println(ui.WarningMessage(i18n.T("version.chart_failed", r.Package, r.Err)))
+		case r.Err != nil:
+			fmt.Println(ui.WarningMessage(i18n.T("version.chart_warning", r.Package, r.Err)))
+		default:
+			recorded = true
+			fmt.Println(ui.SuccessMessage(i18n.T("version.chart_published", r.Package, r.Reference)))
+			fmt.Println(ui.Dimmed("  " + r.Digest))
+		}
+	}
+
+	if recorded && digestsUncommitted {
+		fmt.Println(ui.Dimmed(i18n.T("version.chart_digests_recorded")))
+	}
+}
@@ -0,0 +1,186 @@
+package commands
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/NatoNathan/shipyard/internal/history"
+	"github.com/NatoNathan/shipyard/internal/publish/publishtest"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupHelmPublishRepo creates a repo with a Helm chart configured to publish to registryURL
+func setupHelmPublishRepo(t *testing.T, registryURL string) string {
+	t.Helper()
+	tempDir := t.TempDir()
+
+	shipyardDir := filepath.Join(tempDir, ".shipyard")
+	require.NoError(t, os.MkdirAll(filepath.Join(shipyardDir, "consignments"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(shipyardDir, "history.json"), []byte("[]"), 0644))
+
+	configContent := `packages:
+  - name: chart
+    path: ./chart
+    ecosystem: helm
+    publish:
+      helm:
+        registry: ` + registryURL + `
+        usernameEnv: TEST_HELM_USER
+        passwordEnv: TEST_HELM_PASSWORD
+        plainHttp: true
+consignments:
+  path: ".shipyard/consignments"
+history:
+  path: ".shipyard/history.json"
+`
+	require.NoError(t, os.WriteFile(filepath.Join(shipyardDir, "shipyard.yaml"), []byte(configContent), 0644))
+
+	chartDir := filepath.Join(tempDir, "chart")
+	require.NoError(t, os.MkdirAll(filepath.Join(chartDir, "templates"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(chartDir, "Chart.yaml"), []byte("apiVersion: v2\nname: mychart\ndescription: test\nversion: 1.0.0\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(chartDir, "templates", "service.yaml"), []byte("kind: Service\n"), 0644))
+
+	createTestConsignmentForVersion(t, filepath.Join(shipyardDir, "consignments"), "c1", []string{"chart"}, "minor", "Add service")
+	return tempDir
+}
+
+func TestVersionCommand_PublishesHelmCharts(t *testing.T) {
+	t.Setenv("TEST_HELM_USER", "robot")
+	t.Setenv("TEST_HELM_PASSWORD", "secret")
+
+	registry := publishtest.NewRegistry()
+	registry.Username = "robot"
+	registry.Password = "secret"
+	server := httptest.NewServer(registry)
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	tempDir := setupHelmPublishRepo(t, "oci://"+host+"/acme/charts")
+
+	output := captureOutput(func() {
+		require.NoError(t, runVersionInDir(tempDir, &VersionCommandOptions{NoCommit: true, NoTag: true}))
+	})
+	assert.Contains(t, output, "Published chart chart")
+
+	_, ok := registry.Manifest("acme/charts/mychart", "1.1.0")
+	require.True(t, ok, "chart should be pushed with the new version")
+
+	entries, err := history.ReadHistory(filepath.Join(tempDir, ".shipyard", "history.json"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Len(t, entries[0].Artifacts, 1)
+	assert.Equal(t, "helm", entries[0].Artifacts[0].Type)
+	assert.Equal(t, host+"/acme/charts/mychart:1.1.0", entries[0].Artifacts[0].Reference)
+	assert.True(t, strings.HasPrefix(entries[0].Artifacts[0].Digest, "sha256:"))
+}
+
+func TestVersionCommand_HelmPublishFailureKeepsRelease(t *testing.T) {
+	t.Setenv("TEST_HELM_USER", "robot")
+	t.Setenv("TEST_HELM_PASSWORD", "wrong")
+
+	registry := publishtest.NewRegistry()
+	registry.Username = "robot"
+	registry.Password = "secret"
+	server := httptest.NewServer(registry)
+	defer server.Close()
+
+	tempDir := setupHelmPublishRepo(t, "oci://"+strings.TrimPrefix(server.URL, "http://")+"/acme/charts")
+
+	output := captureOutput(func() {
+		require.NoError(t, runVersionInDir(tempDir, &VersionCommandOptions{NoCommit: true, NoTag: true}))
+	})
+	assert.Contains(t, output, "Failed to publish chart chart")
+
+	chartYAML, err := os.ReadFile(filepath.Join(tempDir, "chart", "Chart.yaml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(chartYAML), "version: 1.1.0", "release should not be rolled back")
+
+	entries, err := history.ReadHistory(filepath.Join(tempDir, ".shipyard", "history.json"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Empty(t, entries[0].Artifacts)
+}
+
+func TestVersionCommand_NoPublish(t *testing.T) {
+	registry := publishtest.NewRegistry()
+	server := httptest.NewServer(registry)
+	defer server.Close()
+
+	tempDir := setupHelmPublishRepo(t, "oci://"+strings.TrimPrefix(server.URL, "http://")+"/acme/charts")
+
+	captureOutput(func() {
+		require.NoError(t, runVersionInDir(tempDir, &VersionCommandOptions{NoCommit: true, NoTag: true, NoPublish: true}))
+	})
+
+	_, ok := registry.Manifest("acme/charts/mychart", "1.1.0")
+	assert.False(t, ok)
+}
+
+func TestVersionCommand_CommitsPublishedDigests(t *testing.T) {
+	t.Setenv("TEST_HELM_USER", "robot")
+	t.Setenv("TEST_HELM_PASSWORD", "secret")
+
+	registry := publishtest.NewRegistry()
+	registry.Username = "robot"
+	registry.Password = "secret"
+	server := httptest.NewServer(registry)
+	defer server.Close()
+
+	tempDir := setupHelmPublishRepo(t, "oci://"+strings.TrimPrefix(server.URL, "http://")+"/acme/charts")
+	repo, err := gogit.PlainInit(tempDir, false)
+	require.NoError(t, err)
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+	_, err = wt.Add(".")
+	require.NoError(t, err)
+	releaseBase, err := wt.Commit("initial commit", &gogit.CommitOptions{
+		Author: &object.Signature{Name: "Test", Email: "test@example.com"},
+	})
+	require.NoError(t, err)
+
+	captureOutput(func() {
+		require.NoError(t, runVersionInDir(tempDir, &VersionCommandOptions{}))
+	})
+
+	status, err := wt.Status()
+	require.NoError(t, err)
+	for path, file := range status {
+		// The history lock file outlives the release by design
+		if file.Worktree == gogit.Untracked && strings.HasSuffix(path, ".lock") {
+			continue
+		}
+		t.Errorf("publishing left %s modified:\n%s", path, status)
+	}
+
+	// The digests are committed on top of the tagged release commit
+	head, err := repo.Head()
+	require.NoError(t, err)
+	commit, err := repo.CommitObject(head.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, "chore: record published artifacts", commit.Message)
+	require.Len(t, commit.ParentHashes, 1)
+
+	ref, err := repo.Tag("v1.1.0")
+	require.NoError(t, err)
+	tagged := ref.Hash()
+	if tag, err := repo.TagObject(tagged); err == nil {
+		tagged = tag.Target
+	}
+	assert.Equal(t, commit.ParentHashes[0], tagged)
+	release, err := repo.CommitObject(tagged)
+	require.NoError(t, err)
+	assert.Equal(t, []plumbing.Hash{releaseBase}, release.ParentHashes)
+
+	file, err := commit.File(".shipyard/history.json")
+	require.NoError(t, err)
+	contents, err := file.Contents()
+	require.NoError(t, err)
+	assert.Contains(t, contents, `"digest": "sha256:`)
+}
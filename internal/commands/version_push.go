@@ -0,0 +1,21 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/git"
+	"github.com/NatoNathan/shipyard/internal/i18n"
+	"github.com/NatoNathan/shipyard/internal/release"
+	"github.com/NatoNathan/shipyard/internal/ui"
+)
+
+// checkReleasePush checks that the checked-out branch could be pushed to the
+// release remote, without releasing or pushing anything (--dry-run-push)
+func checkReleasePush(projectPath string, cfg *config.Config) error {
+	if err := git.Push(projectPath, release.PushOptions(projectPath, cfg, nil, true)); err != nil {
+		return fmt.Errorf("push check failed: %w", err)
+	}
+	fmt.Println(ui.SuccessMessage(i18n.T("version.push_check_passed", release.Remote(cfg))))
+	return nil
+}
@@ -0,0 +1,118 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	gogit "github.com/go-git/go-git/v5"
+	gogitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// addBareOrigin registers an empty bare repository as the origin remote
+func addBareOrigin(t *testing.T, repo *gogit.Repository) *gogit.Repository {
+	t.Helper()
+	bareDir := t.TempDir()
+	bare, err := gogit.PlainInit(bareDir, true)
+	require.NoError(t, err)
+	_, err = repo.CreateRemote(&gogitconfig.RemoteConfig{Name: "origin", URLs: []string{bareDir}})
+	require.NoError(t, err)
+	return bare
+}
+
+// tagNames lists the tags in a repository
+func tagNames(t *testing.T, repo *gogit.Repository) []string {
+	t.Helper()
+	iter, err := repo.Tags()
+	require.NoError(t, err)
+	var names []string
+	require.NoError(t, iter.ForEach(func(ref *plumbing.Reference) error {
+		names = append(names, ref.Name().Short())
+		return nil
+	}))
+	return names
+}
+
+func TestVersionCommand_Push(t *testing.T) {
+	tempDir, repo, _ := setupResumeTestRepo(t)
+	bare := addBareOrigin(t, repo)
+
+	require.NoError(t, runVersionInDir(tempDir, &VersionCommandOptions{Push: true}))
+
+	head, err := repo.Head()
+	require.NoError(t, err)
+	remoteHead, err := bare.Reference(head.Name(), false)
+	require.NoError(t, err)
+	assert.Equal(t, head.Hash(), remoteHead.Hash(), "release commit should be pushed")
+
+	localTags := tagNames(t, repo)
+	require.NotEmpty(t, localTags)
+	assert.ElementsMatch(t, localTags, tagNames(t, bare), "release tags should be pushed")
+}
+
+func TestVersionCommand_PushPreflight(t *testing.T) {
+	tempDir, repo, initialHead := setupResumeTestRepo(t)
+	_, err := repo.CreateRemote(&gogitconfig.RemoteConfig{Name: "origin", URLs: []string{t.TempDir()}})
+	require.NoError(t, err)
+
+	// The remote is not a repository, so the release must not start
+	err = runVersionInDir(tempDir, &VersionCommandOptions{Push: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "pre-flight checks failed")
+
+	head, err := repo.Head()
+	require.NoError(t, err)
+	assert.Equal(t, initialHead, head.Hash(), "no release commit should be created")
+	assert.Empty(t, tagNames(t, repo))
+}
+
+func TestVersionCommand_DryRunPush(t *testing.T) {
+	t.Run("checks without releasing", func(t *testing.T) {
+		tempDir, repo, initialHead := setupResumeTestRepo(t)
+		bare := addBareOrigin(t, repo)
+
+		require.NoError(t, runVersionInDir(tempDir, &VersionCommandOptions{DryRunPush: true}))
+
+		head, err := repo.Head()
+		require.NoError(t, err)
+		assert.Equal(t, initialHead, head.Hash())
+		_, err = bare.Reference(head.Name(), false)
+		assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound, "nothing should be pushed")
+	})
+
+	t.Run("missing remote", func(t *testing.T) {
+		tempDir, _, _ := setupResumeTestRepo(t)
+		err := runVersionInDir(tempDir, &VersionCommandOptions{DryRunPush: true})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to get remote 'origin'")
+	})
+
+	t.Run("configured remote", func(t *testing.T) {
+		tempDir, repo, _ := setupResumeTestRepo(t)
+		_, err := repo.CreateRemote(&gogitconfig.RemoteConfig{Name: "release", URLs: []string{t.TempDir()}})
+		require.NoError(t, err)
+		configPath := filepath.Join(tempDir, ".shipyard", "shipyard.yaml")
+		content, err := os.ReadFile(configPath)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(configPath, append(content, "git:\n  remote: release\n"...), 0644))
+
+		err = runVersionInDir(tempDir, &VersionCommandOptions{DryRunPush: true})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to reach remote 'release'")
+	})
+}
+
+func TestVersionCommand_PushValidation(t *testing.T) {
+	tempDir := setupVersionTestRepo(t)
+
+	err := runVersionInDir(tempDir, &VersionCommandOptions{Push: true, NoCommit: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--no-commit")
+
+	err = runVersionInDir(tempDir, &VersionCommandOptions{DryRunPush: true, Preview: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--dry-run-push")
+}
@@ -0,0 +1,393 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/consignment"
+	"github.com/NatoNathan/shipyard/internal/history"
+	"github.com/NatoNathan/shipyard/internal/release"
+	"github.com/NatoNathan/shipyard/internal/runstate"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// errSimulatedCrash is the panic value interruptAfter uses to stop a run
+const errSimulatedCrash = "simulated crash"
+
+// interruptAfter makes version runs stop dead after phase is checkpointed,
+// as if the process was killed: nothing is rolled back
+func interruptAfter(t *testing.T, phase runstate.Phase) {
+	t.Helper()
+	original := release.AfterPhase
+	release.AfterPhase = func(completed runstate.Phase) {
+		if completed == phase {
+			panic(errSimulatedCrash)
+		}
+	}
+	t.Cleanup(func() { release.AfterPhase = original })
+}
+
+// resumeAll clears any interruption so the next run completes
+func resumeAll() {
+	release.AfterPhase = func(runstate.Phase) {}
+}
+
+// setupResumeTestRepo creates a committed repository with one pending consignment
+func setupResumeTestRepo(t *testing.T) (string, *gogit.Repository, plumbing.Hash) {
+	t.Helper()
+	tempDir := setupVersionTestRepo(t)
+	repo, err := gogit.PlainInit(tempDir, false)
+	require.NoError(t, err)
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+	createTestConsignmentForVersion(t, filepath.Join(tempDir, ".shipyard", "consignments"), "c1", []string{"test-package"}, "minor", "Add feature")
+	_, err = wt.Add(".")
+	require.NoError(t, err)
+	head, err := wt.Commit("initial commit", &gogit.CommitOptions{
+		Author: &object.Signature{Name: "Test", Email: "test@example.com"},
+	})
+	require.NoError(t, err)
+	return tempDir, repo, head
+}
+
+func TestVersionCommand_ResumeInterruptedRun(t *testing.T) {
+	tempDir, repo, initialHead := setupResumeTestRepo(t)
+	statePath := runstate.Path(tempDir)
+	historyPath := filepath.Join(tempDir, ".shipyard", "history.json")
+
+	interruptAfter(t, runstate.PhaseHistory)
+	assert.PanicsWithValue(t, errSimulatedCrash, func() {
+		_ = runVersionInDir(tempDir, &VersionCommandOptions{NoPublish: true})
+	})
+
+	// The crash left bumped manifests and history, but no commit or tags
+	require.FileExists(t, statePath)
+	run, err := runstate.Read(statePath)
+	require.NoError(t, err)
+	assert.Equal(t, []runstate.Phase{runstate.PhaseVersions, runstate.PhaseHistory}, run.Completed)
+	assert.Equal(t, "v1.1.0", run.Tags[0].Name)
+
+	versionContent, err := os.ReadFile(filepath.Join(tempDir, "test-package", "version.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(versionContent), `"1.1.0"`)
+	entries, err := history.ReadHistory(historyPath)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	head, err := repo.Head()
+	require.NoError(t, err)
+	assert.Equal(t, initialHead, head.Hash(), "no commit before the crash")
+	_, err = repo.Tag("v1.1.0")
+	assert.ErrorIs(t, err, gogit.ErrTagNotFound)
+
+	// A new run refuses to start over the interrupted one
+	resumeAll()
+	err = runVersionInDir(tempDir, &VersionCommandOptions{NoPublish: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--resume")
+	assert.Contains(t, err.Error(), "--abort-run")
+
+	// Resuming finishes the recorded plan
+	captureOutput(func() {
+		require.NoError(t, runVersionInDir(tempDir, &VersionCommandOptions{Resume: true}))
+	})
+
+	assert.NoFileExists(t, statePath)
+	assert.NoFileExists(t, filepath.Join(tempDir, ".shipyard", "consignments", "c1.md"))
+	assert.FileExists(t, filepath.Join(tempDir, "test-package", "CHANGELOG.md"))
+
+	entries, err = history.ReadHistory(historyPath)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "history must not be appended twice")
+
+	head, err = repo.Head()
+	require.NoError(t, err)
+	commit, err := repo.CommitObject(head.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, []plumbing.Hash{initialHead}, commit.ParentHashes, "exactly one release commit")
+	assert.Contains(t, commit.Message, "test-package")
+
+	tagged, err := repo.ResolveRevision(plumbing.Revision("v1.1.0"))
+	require.NoError(t, err)
+	assert.Equal(t, head.Hash(), *tagged, "tag points at the release commit")
+
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+	status, err := wt.Status()
+	require.NoError(t, err)
+	for path, fileStatus := range status {
+		assert.Contains(t, []gogit.StatusCode{gogit.Unmodified, gogit.Untracked}, fileStatus.Worktree, "%s is left modified", path)
+	}
+}
+
+func TestVersionCommand_ResumeRepeatsInterruptedPhase(t *testing.T) {
+	tempDir, repo, initialHead := setupResumeTestRepo(t)
+	statePath := runstate.Path(tempDir)
+
+	// Crash after the commit was created but before the phase was checkpointed
+	interruptAfter(t, runstate.PhaseCommit)
+	assert.Panics(t, func() {
+		_ = runVersionInDir(tempDir, &VersionCommandOptions{NoPublish: true})
+	})
+	run, err := runstate.Read(statePath)
+	require.NoError(t, err)
+	run.Completed = run.Completed[:len(run.Completed)-1]
+	require.NoError(t, runstate.Write(statePath, run))
+
+	resumeAll()
+	captureOutput(func() {
+		require.NoError(t, runVersionInDir(tempDir, &VersionCommandOptions{Resume: true}))
+	})
+
+	head, err := repo.Head()
+	require.NoError(t, err)
+	commit, err := repo.CommitObject(head.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, []plumbing.Hash{initialHead}, commit.ParentHashes, "the commit must not be created twice")
+	_, err = repo.Tag("v1.1.0")
+	require.NoError(t, err)
+}
+
+func TestVersionCommand_AbortInterruptedRun(t *testing.T) {
+	tempDir, repo, initialHead := setupResumeTestRepo(t)
+	versionFile := filepath.Join(tempDir, "test-package", "version.go")
+	historyPath := filepath.Join(tempDir, ".shipyard", "history.json")
+	originalVersion, err := os.ReadFile(versionFile)
+	require.NoError(t, err)
+	originalHistory, err := os.ReadFile(historyPath)
+	require.NoError(t, err)
+
+	interruptAfter(t, runstate.PhaseTags)
+	assert.Panics(t, func() {
+		_ = runVersionInDir(tempDir, &VersionCommandOptions{NoPublish: true})
+	})
+	_, err = repo.Tag("v1.1.0")
+	require.NoError(t, err, "the crash came after tagging")
+
+	resumeAll()
+	captureOutput(func() {
+		require.NoError(t, runVersionInDir(tempDir, &VersionCommandOptions{AbortRun: true}))
+	})
+
+	assert.NoFileExists(t, runstate.Path(tempDir))
+	head, err := repo.Head()
+	require.NoError(t, err)
+	assert.Equal(t, initialHead, head.Hash(), "release commit is undone")
+	_, err = repo.Tag("v1.1.0")
+	assert.ErrorIs(t, err, gogit.ErrTagNotFound, "release tag is deleted")
+
+	afterVersion, err := os.ReadFile(versionFile)
+	require.NoError(t, err)
+	assert.Equal(t, string(originalVersion), string(afterVersion))
+	afterHistory, err := os.ReadFile(historyPath)
+	require.NoError(t, err)
+	assert.Equal(t, string(originalHistory), string(afterHistory))
+	assert.FileExists(t, filepath.Join(tempDir, ".shipyard", "consignments", "c1.md"))
+	assert.NoFileExists(t, filepath.Join(tempDir, "test-package", "CHANGELOG.md"))
+
+	// The pending consignment can now be released normally
+	captureOutput(func() {
+		require.NoError(t, runVersionInDir(tempDir, &VersionCommandOptions{NoPublish: true}))
+	})
+}
+
+func TestVersionCommand_ResumeWithoutInterruptedRun(t *testing.T) {
+	tempDir := setupVersionTestRepo(t)
+
+	err := runVersionInDir(tempDir, &VersionCommandOptions{Resume: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no interrupted version run to resume")
+
+	err = runVersionInDir(tempDir, &VersionCommandOptions{AbortRun: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no interrupted version run to abort")
+}
+
+func TestVersionCommand_FailedRunRemovesCheckpoint(t *testing.T) {
+	tempDir := setupVersionTestRepo(t)
+	createTestConsignmentForVersion(t, filepath.Join(tempDir, ".shipyard", "consignments"), "c1", []string{"test-package"}, "patch", "Fix bug")
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, ".shipyard", "history.json"), []byte("not json"), 0644))
+
+	err := runVersionInDir(tempDir, &VersionCommandOptions{NoCommit: true, NoTag: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "nothing was applied")
+	assert.NoFileExists(t, runstate.Path(tempDir), "a rolled back run leaves nothing to resume")
+}
+
+func TestVersionCommand_RejectsChangelogOutsideProject(t *testing.T) {
+	tempDir := setupVersionTestRepo(t)
+	createTestConsignmentForVersion(t, filepath.Join(tempDir, ".shipyard", "consignments"), "c1", []string{"test-package"}, "patch", "Fix bug")
+
+	// The package directory resolves outside the project through a symlink
+	outside := filepath.Join(t.TempDir(), "test-package")
+	require.NoError(t, os.Rename(filepath.Join(tempDir, "test-package"), outside))
+	if err := os.Symlink(outside, filepath.Join(tempDir, "test-package")); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+
+	err := runVersionInDir(tempDir, &VersionCommandOptions{NoCommit: true, NoTag: true, NoPublish: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "escapes the project root")
+	assert.NoFileExists(t, filepath.Join(outside, "CHANGELOG.md"))
+
+	versionContent, err := os.ReadFile(filepath.Join(outside, "version.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(versionContent), `"1.0.0"`, "nothing is written before the check")
+}
+
+func TestVersionCommand_ResumeVerifiesLeftoverTags(t *testing.T) {
+	// interruptInTagPhase crashes a run after tagging but before the tag
+	// phase was checkpointed, so --resume repeats it
+	interruptInTagPhase := func(t *testing.T, tempDir string) {
+		t.Helper()
+		interruptAfter(t, runstate.PhaseTags)
+		assert.Panics(t, func() {
+			_ = runVersionInDir(tempDir, &VersionCommandOptions{NoPublish: true})
+		})
+		statePath := runstate.Path(tempDir)
+		run, err := runstate.Read(statePath)
+		require.NoError(t, err)
+		run.Completed = run.Completed[:len(run.Completed)-1]
+		require.NoError(t, runstate.Write(statePath, run))
+		resumeAll()
+	}
+
+	t.Run("tag from the interrupted run is kept", func(t *testing.T) {
+		tempDir, repo, _ := setupResumeTestRepo(t)
+		interruptInTagPhase(t, tempDir)
+		tagBefore, err := repo.Tag("v1.1.0")
+		require.NoError(t, err)
+
+		captureOutput(func() {
+			require.NoError(t, runVersionInDir(tempDir, &VersionCommandOptions{Resume: true}))
+		})
+
+		tagAfter, err := repo.Tag("v1.1.0")
+		require.NoError(t, err)
+		assert.Equal(t, tagBefore.Hash(), tagAfter.Hash())
+		assert.NoFileExists(t, runstate.Path(tempDir))
+	})
+
+	t.Run("tag pointing elsewhere fails", func(t *testing.T) {
+		tempDir, repo, initialHead := setupResumeTestRepo(t)
+		interruptInTagPhase(t, tempDir)
+		require.NoError(t, repo.DeleteTag("v1.1.0"))
+		_, err := repo.CreateTag("v1.1.0", initialHead, nil)
+		require.NoError(t, err)
+
+		err = runVersionInDir(tempDir, &VersionCommandOptions{Resume: true})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "tag v1.1.0 already exists and does not point at the release of test-package 1.1.0")
+		assert.Contains(t, err.Error(), "git tag -d v1.1.0")
+	})
+}
+
+func TestVersionCommand_RerunWithReleasedTag(t *testing.T) {
+	tempDir, repo, initialHead := setupResumeTestRepo(t)
+	captureOutput(func() {
+		require.NoError(t, runVersionInDir(tempDir, &VersionCommandOptions{NoPublish: true}))
+	})
+	releaseTag, err := repo.Tag("v1.1.0")
+	require.NoError(t, err)
+
+	// Undo the release commit but keep its tag, as a release interrupted
+	// before it was pushed and reset by hand would
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+	require.NoError(t, wt.Reset(&gogit.ResetOptions{Commit: initialHead, Mode: gogit.HardReset}))
+	require.FileExists(t, filepath.Join(tempDir, ".shipyard", "consignments", "c1.md"))
+
+	output := captureOutput(func() {
+		require.NoError(t, runVersionInDir(tempDir, &VersionCommandOptions{NoPublish: true}))
+	})
+	assert.Contains(t, output, "Tag v1.1.0 already marks the release of test-package 1.1.0")
+	assert.NotContains(t, output, "[tag-collision]")
+
+	tagAfter, err := repo.Tag("v1.1.0")
+	require.NoError(t, err)
+	assert.Equal(t, releaseTag.Hash(), tagAfter.Hash(), "existing tag must not be moved")
+}
+
+func TestVersionCommand_ConsignmentFileNamedDifferentlyFromID(t *testing.T) {
+	tempDir := setupVersionTestRepo(t)
+	consignmentsDir := filepath.Join(tempDir, ".shipyard", "consignments")
+	createTestConsignmentForVersion(t, consignmentsDir, "20250101-120000-abc123", []string{"test-package"}, "patch", "Fix typo")
+	legacyPath := filepath.Join(consignmentsDir, "fix-typo.md")
+	require.NoError(t, os.Rename(filepath.Join(consignmentsDir, "20250101-120000-abc123.md"), legacyPath))
+
+	captureOutput(func() {
+		require.NoError(t, runVersionWithDir(tempDir, &VersionCommandOptions{NoCommit: true, NoTag: true, NoPublish: true}))
+	})
+
+	assert.NoFileExists(t, legacyPath, "the released consignment is removed by its file name")
+	entries, err := history.ReadHistory(filepath.Join(tempDir, ".shipyard", "history.json"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Len(t, entries[0].Consignments, 1)
+	assert.Equal(t, "20250101-120000-abc123", entries[0].Consignments[0].ID)
+}
+
+func TestVersionCommand_SameTimestampConsignmentsKeepCreationOrder(t *testing.T) {
+	release := func() []string {
+		t.Helper()
+		tempDir := setupVersionTestRepo(t)
+		initGitRepo(t, tempDir)
+		consignmentsDir := filepath.Join(tempDir, ".shipyard", "consignments")
+		timestamp := time.Date(2026, 1, 30, 14, 30, 22, 0, time.UTC)
+
+		// Written before sequences existed, so it has none
+		legacy := "---\nid: zz-legacy\ntimestamp: 2026-01-30T14:30:22Z\npackages:\n  - test-package\nchangeType: patch\n---\n\nLegacy change\n"
+		require.NoError(t, os.WriteFile(filepath.Join(consignmentsDir, "zz-legacy.md"), []byte(legacy), 0644))
+		for _, summary := range []string{"First change", "Second change", "Third change", "Fourth change"} {
+			require.NoError(t, runAdd(tempDir, AddOptions{
+				Packages:  []string{"test-package"},
+				Type:      "patch",
+				Summary:   summary,
+				Timestamp: timestamp,
+				Quiet:     true,
+			}))
+		}
+
+		pending, err := consignment.ReadAllConsignments(consignmentsDir)
+		require.NoError(t, err)
+		sequences := make([]int, len(pending))
+		for i, c := range pending {
+			sequences[i] = c.Sequence
+		}
+		assert.Equal(t, []int{0, 1, 2, 3, 4}, sequences)
+
+		captureOutput(func() {
+			require.NoError(t, runVersionWithDir(tempDir, &VersionCommandOptions{NoCommit: true, NoTag: true, NoPublish: true}))
+		})
+
+		entries, err := history.ReadHistory(filepath.Join(tempDir, ".shipyard", "history.json"))
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		summaries := make([]string, len(entries[0].Consignments))
+		for i, c := range entries[0].Consignments {
+			summaries[i] = c.Summary
+		}
+
+		changelog, err := os.ReadFile(filepath.Join(tempDir, "test-package", "CHANGELOG.md"))
+		require.NoError(t, err)
+		var positions []int
+		for _, summary := range summaries {
+			positions = append(positions, strings.Index(string(changelog), summary))
+		}
+		assert.IsIncreasing(t, positions, "the changelog lists consignments in creation order")
+		return summaries
+	}
+
+	want := []string{"Legacy change", "First change", "Second change", "Third change", "Fourth change"}
+	// IDs are random, so only the sequences keep repeated runs in order
+	for range 3 {
+		assert.Equal(t, want, release())
+	}
+}
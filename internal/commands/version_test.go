@@ -1,13 +1,28 @@
 package commands
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/consignment"
+	"github.com/NatoNathan/shipyard/internal/ecosystem"
+	"github.com/NatoNathan/shipyard/internal/git"
+	"github.com/NatoNathan/shipyard/internal/history"
+	"github.com/NatoNathan/shipyard/internal/i18n"
+	"github.com/NatoNathan/shipyard/internal/prompt"
+	"github.com/NatoNathan/shipyard/internal/release"
+	"github.com/NatoNathan/shipyard/internal/version"
+	"github.com/NatoNathan/shipyard/pkg/semver"
+	"github.com/NatoNathan/shipyard/pkg/types"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -143,6 +158,41 @@ func TestVersionCommand_PreviewMode(t *testing.T) {
 		assert.Contains(t, output, "test-package", "Should show package name")
 	})
 
+	t.Run("accessible preview is plain text", func(t *testing.T) {
+		t.Setenv(prompt.AccessibleEnv, "1")
+		tempDir := setupVersionTestRepo(t)
+		consignmentsDir := filepath.Join(tempDir, ".shipyard", "consignments")
+		createTestConsignmentForVersion(t, consignmentsDir, "c1", []string{"test-package"}, "minor", "Add feature")
+
+		var err error
+		output := captureOutput(func() {
+			err = runVersionWithDir(tempDir, &VersionCommandOptions{Preview: true})
+		})
+
+		require.NoError(t, err)
+		assert.Contains(t, output, "Preview mode (no changes will be applied)\n")
+		assert.Contains(t, output, "Version preview:\ntest-package: 1.0.0 to 1.1.0 (minor)\n  - # Change\n    Add feature\n")
+		assert.NotContains(t, output, "→")
+		assert.NotContains(t, output, "ℹ")
+	})
+
+	t.Run("preview follows SHIPYARD_LOCALE", func(t *testing.T) {
+		t.Setenv(prompt.AccessibleEnv, "1")
+		t.Setenv(i18n.LocaleEnv, "es_ES.UTF-8")
+		tempDir := setupVersionTestRepo(t)
+		consignmentsDir := filepath.Join(tempDir, ".shipyard", "consignments")
+		createTestConsignmentForVersion(t, consignmentsDir, "c1", []string{"test-package"}, "minor", "Add feature")
+
+		var err error
+		output := captureOutput(func() {
+			err = runVersionWithDir(tempDir, &VersionCommandOptions{Preview: true})
+		})
+
+		require.NoError(t, err)
+		assert.Contains(t, output, "Modo vista previa (no se aplicará ningún cambio)\n")
+		assert.Contains(t, output, "Ejecuta sin --preview para aplicar estos cambios\n")
+	})
+
 	t.Run("preview does not modify files", func(t *testing.T) {
 		// Setup: Create initialized repo with consignment
 		tempDir := setupVersionTestRepo(t)
@@ -181,6 +231,162 @@ func TestVersionCommand_PreviewMode(t *testing.T) {
 	})
 }
 
+func TestVersionCommand_PreviewPropagation(t *testing.T) {
+	setup := func(t *testing.T) string {
+		t.Helper()
+		tempDir := t.TempDir()
+		shipyardDir := filepath.Join(tempDir, ".shipyard")
+		require.NoError(t, os.MkdirAll(filepath.Join(shipyardDir, "consignments"), 0755))
+		// api and core depend on each other; web pins core with a fixed edge
+		configContent := `packages:
+  - name: api
+    path: ./api
+    ecosystem: go
+    dependencies:
+      - package: core
+  - name: core
+    path: ./core
+    ecosystem: go
+    dependencies:
+      - package: api
+  - name: web
+    path: ./web
+    ecosystem: go
+    dependencies:
+      - package: core
+        strategy: fixed
+`
+		require.NoError(t, os.WriteFile(filepath.Join(shipyardDir, "shipyard.yaml"), []byte(configContent), 0644))
+		writeGoVersion(t, tempDir, "api", "2.0.0")
+		writeGoVersion(t, tempDir, "core", "1.0.0")
+		writeGoVersion(t, tempDir, "web", "3.0.0")
+		createTestConsignmentForVersion(t, filepath.Join(shipyardDir, "consignments"), "c1", []string{"core"}, "minor", "Add feature")
+		return tempDir
+	}
+
+	t.Run("JSON preview includes propagation", func(t *testing.T) {
+		tempDir := setup(t)
+
+		var err error
+		output := captureOutput(func() {
+			err = runVersionWithDir(tempDir, &VersionCommandOptions{Preview: true, JSON: true})
+		})
+		require.NoError(t, err)
+
+		var preview VersionPreview
+		require.NoError(t, json.Unmarshal([]byte(output), &preview), output)
+		assert.Equal(t, []VersionPreviewPackage{
+			{Name: "api", OldVersion: "2.0.0", NewVersion: "2.1.0", ChangeType: "minor", Source: "cycle"},
+			{Name: "core", OldVersion: "1.0.0", NewVersion: "1.1.0", ChangeType: "minor", Source: "cycle"},
+		}, preview.Packages)
+
+		require.NotNil(t, preview.Propagation)
+		assert.Len(t, preview.Propagation.Nodes, 3)
+		assert.Equal(t, version.PropagationEdge{From: "web", To: "core", Strategy: "fixed"}, preview.Propagation.Edges[2])
+		assert.Equal(t, []version.BumpProvenance{
+			{Package: "api", ChangeType: "minor", Source: "cycle", Cycle: []string{"core"}, Origins: []string{"core"}},
+			{Package: "core", ChangeType: "minor", Source: "cycle", Consignments: []string{"c1"}, Cycle: []string{"api"}},
+		}, preview.Propagation.Provenance)
+	})
+
+	t.Run("human preview shows because of", func(t *testing.T) {
+		t.Setenv(prompt.AccessibleEnv, "1")
+		tempDir := setup(t)
+
+		var err error
+		output := captureOutput(func() {
+			err = runVersionWithDir(tempDir, &VersionCommandOptions{Preview: true})
+		})
+		require.NoError(t, err)
+		assert.Contains(t, output, "api: 2.0.0 to 2.1.0 (minor) because of core\n")
+		assert.Contains(t, output, "core: 1.0.0 to 1.1.0 (minor)\n")
+	})
+}
+
+func TestVersionCommand_PreviewGitOperations(t *testing.T) {
+	t.Run("single repo", func(t *testing.T) {
+		t.Setenv(prompt.AccessibleEnv, "1")
+		tempDir := setupVersionTestRepo(t)
+		consignmentsDir := filepath.Join(tempDir, ".shipyard", "consignments")
+		createTestConsignmentForVersion(t, consignmentsDir, "c1", []string{"test-package"}, "minor", "Add feature")
+
+		var err error
+		output := captureOutput(func() {
+			err = runVersionWithDir(tempDir, &VersionCommandOptions{Preview: true})
+		})
+		require.NoError(t, err)
+		assert.Contains(t, output, "Git operations that would run (not executed):\n")
+		assert.Contains(t, output, "  tag test-package: v1.1.0\n")
+		assert.Contains(t, output, "  commit: ")
+	})
+
+	t.Run("monorepo", func(t *testing.T) {
+		t.Setenv(prompt.AccessibleEnv, "1")
+		tempDir := t.TempDir()
+		shipyardDir := filepath.Join(tempDir, ".shipyard")
+		require.NoError(t, os.MkdirAll(filepath.Join(shipyardDir, "consignments"), 0755))
+		configContent := `packages:
+  - name: api
+    path: ./api
+    ecosystem: go
+    dependencies:
+      - package: core
+  - name: core
+    path: ./core
+    ecosystem: go
+    templates:
+      tagName:
+        inline: "core-{{ .Version }}"
+templates:
+  tagName:
+    source: builtin:npm
+`
+		require.NoError(t, os.WriteFile(filepath.Join(shipyardDir, "shipyard.yaml"), []byte(configContent), 0644))
+		writeGoVersion(t, tempDir, "api", "2.0.0")
+		writeGoVersion(t, tempDir, "core", "1.0.0")
+		createTestConsignmentForVersion(t, filepath.Join(shipyardDir, "consignments"), "c1", []string{"core"}, "minor", "Add feature")
+
+		var err error
+		output := captureOutput(func() {
+			err = runVersionWithDir(tempDir, &VersionCommandOptions{Preview: true})
+		})
+		require.NoError(t, err)
+		assert.Contains(t, output, "  tag core: core-1.1.0\n")
+		assert.Contains(t, output, "  tag api: api@2.1.0\n")
+	})
+
+	t.Run("template error is shown inline", func(t *testing.T) {
+		t.Setenv(prompt.AccessibleEnv, "1")
+		tempDir := setupVersionTestRepo(t)
+		configPath := filepath.Join(tempDir, ".shipyard", "shipyard.yaml")
+		configContent, err := os.ReadFile(configPath)
+		require.NoError(t, err)
+		configContent = []byte(strings.Replace(string(configContent), "templates:\n", "templates:\n  tagName:\n    inline: \"{{ .Version\"\n", 1))
+		require.NoError(t, os.WriteFile(configPath, configContent, 0644))
+		createTestConsignmentForVersion(t, filepath.Join(tempDir, ".shipyard", "consignments"), "c1", []string{"test-package"}, "minor", "Add feature")
+
+		output := captureOutput(func() {
+			err = runVersionWithDir(tempDir, &VersionCommandOptions{Preview: true})
+		})
+		require.NoError(t, err)
+		assert.Contains(t, output, "  tag test-package: error: ")
+		assert.Contains(t, output, "Run without --preview to apply these changes\n")
+	})
+
+	t.Run("skipped operations", func(t *testing.T) {
+		t.Setenv(prompt.AccessibleEnv, "1")
+		tempDir := setupVersionTestRepo(t)
+		createTestConsignmentForVersion(t, filepath.Join(tempDir, ".shipyard", "consignments"), "c1", []string{"test-package"}, "minor", "Add feature")
+
+		var err error
+		output := captureOutput(func() {
+			err = runVersionWithDir(tempDir, &VersionCommandOptions{Preview: true, NoCommit: true})
+		})
+		require.NoError(t, err)
+		assert.Contains(t, output, "  commit: skipped (--no-commit)\n  tags: skipped\n")
+	})
+}
+
 func TestVersionCommand_RollsBackFilesystemChangesOnChangelogFailure(t *testing.T) {
 	tempDir := setupVersionTestRepo(t)
 	consignmentsDir := filepath.Join(tempDir, ".shipyard", "consignments")
@@ -259,17 +465,17 @@ func TestVersionCommand_PreviewFilesystemSemantics(t *testing.T) {
 func TestVersionCommand_VersionBumpPropagation(t *testing.T) {
 	tests := []struct {
 		name             string
-		packages         []string
-		dependencies     map[string][]string
+		startVersions    map[string]string // package -> current version
+		dependencies     map[string][]config.Dependency
 		directChanges    map[string]string // package -> changeType
 		expectedVersions map[string]string // package -> version
 		description      string
 	}{
 		{
-			name:     "linked dependency propagation",
-			packages: []string{"core", "api-client"},
-			dependencies: map[string][]string{
-				"api-client": {"core"},
+			name:          "linked dependency propagation",
+			startVersions: map[string]string{"core": "1.0.0", "api-client": "2.0.0"},
+			dependencies: map[string][]config.Dependency{
+				"api-client": {{Package: "core", Strategy: "linked"}},
 			},
 			directChanges: map[string]string{
 				"core": "minor",
@@ -281,10 +487,10 @@ func TestVersionCommand_VersionBumpPropagation(t *testing.T) {
 			description: "linked dependencies should propagate version bumps",
 		},
 		{
-			name:     "fixed dependency no propagation",
-			packages: []string{"core", "tool"},
-			dependencies: map[string][]string{
-				"tool": {"core"}, // but with fixed strategy
+			name:          "fixed dependency no propagation",
+			startVersions: map[string]string{"core": "1.0.0", "tool": "0.5.0"},
+			dependencies: map[string][]config.Dependency{
+				"tool": {{Package: "core", Strategy: "fixed"}},
 			},
 			directChanges: map[string]string{
 				"core": "major",
@@ -296,12 +502,27 @@ func TestVersionCommand_VersionBumpPropagation(t *testing.T) {
 			description: "fixed dependencies should not propagate",
 		},
 		{
-			name:     "cycle bump resolution",
-			packages: []string{"service-a", "service-b", "service-c"},
-			dependencies: map[string][]string{
-				"service-a": {"service-b"},
-				"service-b": {"service-c"},
-				"service-c": {"service-a"}, // creates cycle
+			name:          "patch dependency propagation",
+			startVersions: map[string]string{"core": "1.0.0", "cli": "3.2.1"},
+			dependencies: map[string][]config.Dependency{
+				"cli": {{Package: "core", Strategy: "patch"}},
+			},
+			directChanges: map[string]string{
+				"core": "minor",
+			},
+			expectedVersions: map[string]string{
+				"core": "1.1.0", // minor bump
+				"cli":  "3.2.2", // at least a patch bump
+			},
+			description: "patch dependencies should receive a patch bump",
+		},
+		{
+			name:          "cycle bump resolution",
+			startVersions: map[string]string{"service-a": "1.0.0", "service-b": "1.0.0", "service-c": "1.0.0"},
+			dependencies: map[string][]config.Dependency{
+				"service-a": {{Package: "service-b"}},
+				"service-b": {{Package: "service-c"}},
+				"service-c": {{Package: "service-a"}}, // creates cycle
 			},
 			directChanges: map[string]string{
 				"service-a": "minor",
@@ -318,7 +539,42 @@ func TestVersionCommand_VersionBumpPropagation(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			t.Skip("Implementation pending")
+			tempDir := t.TempDir()
+			consignmentsDir := filepath.Join(tempDir, ".shipyard", "consignments")
+			require.NoError(t, os.MkdirAll(consignmentsDir, 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(tempDir, ".shipyard", "history.json"), []byte("[]"), 0644))
+
+			cfg := &config.Config{
+				Consignments: config.ConsignmentConfig{Path: ".shipyard/consignments"},
+				History:      config.HistoryConfig{Path: ".shipyard/history.json"},
+			}
+			for name, ver := range tt.startVersions {
+				cfg.Packages = append(cfg.Packages, config.Package{
+					Name:         name,
+					Path:         "./" + name,
+					Ecosystem:    config.EcosystemGo,
+					Dependencies: tt.dependencies[name],
+				})
+				pkgDir := filepath.Join(tempDir, name)
+				require.NoError(t, os.MkdirAll(pkgDir, 0755))
+				require.NoError(t, os.WriteFile(filepath.Join(pkgDir, "version.go"),
+					[]byte(fmt.Sprintf("package pkg\n\nconst Version = %q\n", ver)), 0644))
+			}
+			require.NoError(t, config.WriteConfig(cfg, filepath.Join(tempDir, ".shipyard", "shipyard.yaml")))
+
+			for pkg, changeType := range tt.directChanges {
+				createTestConsignmentForVersion(t, consignmentsDir, "c-"+pkg, []string{pkg}, changeType, "Change to "+pkg)
+			}
+
+			captureOutput(func() {
+				require.NoError(t, runVersionInDir(tempDir, &VersionCommandOptions{NoCommit: true, NoTag: true, NoPublish: true}))
+			})
+
+			for pkg, want := range tt.expectedVersions {
+				content, err := os.ReadFile(filepath.Join(tempDir, pkg, "version.go"))
+				require.NoError(t, err)
+				assert.Contains(t, string(content), fmt.Sprintf("%q", want), "%s: %s", tt.description, pkg)
+			}
 		})
 	}
 }
@@ -567,7 +823,36 @@ func TestVersionCommand_ErrorHandling(t *testing.T) {
 // TestVersionCommand_ConcurrentSafety tests concurrent execution safety
 func TestVersionCommand_ConcurrentSafety(t *testing.T) {
 	t.Run("file locking prevents corruption", func(t *testing.T) {
-		t.Skip("Implementation pending - requires concurrent process simulation")
+		tempDir := setupVersionTestRepo(t)
+		historyPath := filepath.Join(tempDir, ".shipyard", "history.json")
+
+		const writers = 20
+		var wg sync.WaitGroup
+		errs := make(chan error, writers)
+		for i := 0; i < writers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				errs <- history.AppendToHistory(historyPath, []history.Entry{{
+					Version:   fmt.Sprintf("1.0.%d", i),
+					Package:   "test-package",
+					Timestamp: time.Now(),
+				}})
+			}(i)
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			require.NoError(t, err)
+		}
+
+		entries, err := history.ReadHistory(historyPath)
+		require.NoError(t, err, "history must remain valid JSON")
+		assert.Len(t, entries, writers, "no append may be lost")
+
+		leftovers, err := filepath.Glob(filepath.Join(tempDir, ".shipyard", "*.tmp"))
+		require.NoError(t, err)
+		assert.Empty(t, leftovers)
 	})
 }
 
@@ -777,3 +1062,1117 @@ go 1.21
 	assert.Contains(t, changelogStr, "1.1.0", "Changelog must include first version number")
 	assert.Contains(t, changelogStr, "1.1.1", "Changelog must include second version number")
 }
+
+// recordingHandler records the order in which package versions are written
+type recordingHandler struct {
+	ecosystem.Handler
+	name   string
+	writes *[]string
+}
+
+func (h *recordingHandler) UpdateVersion(v semver.Version) error {
+	*h.writes = append(*h.writes, h.name)
+	return h.Handler.UpdateVersion(v)
+}
+
+// failingHandler fails to write the version for one package
+type failingHandler struct {
+	ecosystem.Handler
+}
+
+func (h *failingHandler) UpdateVersion(v semver.Version) error {
+	return fmt.Errorf("permission denied")
+}
+
+// TestVersionCommand_AtomicApply verifies a failure part way through a release
+// leaves manifests, history and consignments exactly as they were
+func TestVersionCommand_AtomicApply(t *testing.T) {
+	setup := func(t *testing.T) string {
+		t.Helper()
+		tempDir := t.TempDir()
+		initGitRepo(t, tempDir)
+
+		shipyardDir := filepath.Join(tempDir, ".shipyard")
+		require.NoError(t, os.MkdirAll(filepath.Join(shipyardDir, "consignments"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(shipyardDir, "history.json"), []byte("[]"), 0644))
+		configContent := `packages:
+  - name: api
+    path: ./api
+    ecosystem: go
+    dependencies:
+      - package: core
+  - name: core
+    path: ./core
+    ecosystem: go
+consignments:
+  path: ".shipyard/consignments"
+history:
+  path: ".shipyard/history.json"
+`
+		require.NoError(t, os.WriteFile(filepath.Join(shipyardDir, "shipyard.yaml"), []byte(configContent), 0644))
+		for name, v := range map[string]string{"core": "1.0.0", "api": "2.0.0"} {
+			dir := filepath.Join(tempDir, name)
+			require.NoError(t, os.MkdirAll(dir, 0755))
+			content := fmt.Sprintf("package %s\n\nconst Version = %q\n", name, v)
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "version.go"), []byte(content), 0644))
+		}
+		createTestConsignmentForVersion(t, filepath.Join(shipyardDir, "consignments"), "c1", []string{"core"}, "minor", "Add feature")
+		return tempDir
+	}
+
+	t.Run("second package fails to update", func(t *testing.T) {
+		tempDir := setup(t)
+		coreFile := filepath.Join(tempDir, "core", "version.go")
+		originalCore, err := os.ReadFile(coreFile)
+		require.NoError(t, err)
+
+		original := release.NewVersionHandler
+		release.NewVersionHandler = func(pkg config.Package, pkgPath string, ctx *ecosystem.HandlerContext) (ecosystem.Handler, error) {
+			handler, err := original(pkg, pkgPath, ctx)
+			if err != nil || pkg.Name != "api" {
+				return handler, err
+			}
+			return &failingHandler{Handler: handler}, nil
+		}
+		t.Cleanup(func() { release.NewVersionHandler = original })
+
+		err = runVersionWithDir(tempDir, &VersionCommandOptions{NoCommit: true, NoTag: true})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to update version for api")
+		assert.Contains(t, err.Error(), "nothing was applied")
+
+		afterCore, err := os.ReadFile(coreFile)
+		require.NoError(t, err)
+		assert.Equal(t, originalCore, afterCore, "core manifest should be byte-identical")
+		history, err := os.ReadFile(filepath.Join(tempDir, ".shipyard", "history.json"))
+		require.NoError(t, err)
+		assert.Equal(t, "[]", string(history))
+		assert.FileExists(t, filepath.Join(tempDir, ".shipyard", "consignments", "c1.md"))
+	})
+
+	t.Run("history cannot be recorded", func(t *testing.T) {
+		tempDir := setup(t)
+		historyPath := filepath.Join(tempDir, ".shipyard", "history.json")
+		require.NoError(t, os.WriteFile(historyPath, []byte("not json"), 0644))
+		coreFile := filepath.Join(tempDir, "core", "version.go")
+		originalCore, err := os.ReadFile(coreFile)
+		require.NoError(t, err)
+
+		err = runVersionWithDir(tempDir, &VersionCommandOptions{NoCommit: true, NoTag: true})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to archive consignments")
+		assert.Contains(t, err.Error(), "nothing was applied")
+
+		afterCore, err := os.ReadFile(coreFile)
+		require.NoError(t, err)
+		assert.Equal(t, originalCore, afterCore, "core manifest should be restored")
+		assert.FileExists(t, filepath.Join(tempDir, ".shipyard", "consignments", "c1.md"))
+	})
+}
+
+// TestVersionCommand_AppliesInDependencyOrder verifies that releases are applied
+// dependencies-first even when the config lists dependents first
+func TestVersionCommand_AppliesInDependencyOrder(t *testing.T) {
+	tempDir := t.TempDir()
+	initGitRepo(t, tempDir)
+
+	shipyardDir := filepath.Join(tempDir, ".shipyard")
+	consignmentsDir := filepath.Join(shipyardDir, "consignments")
+	require.NoError(t, os.MkdirAll(consignmentsDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(shipyardDir, "history.json"), []byte("[]"), 0644))
+
+	// chart -> api -> core, listed dependents first
+	configContent := `packages:
+  - name: chart
+    path: ./chart
+    ecosystem: helm
+    options:
+      appDependency: api
+    dependencies:
+      - package: api
+  - name: api
+    path: ./api
+    ecosystem: go
+    dependencies:
+      - package: core
+  - name: core
+    path: ./core
+    ecosystem: go
+consignments:
+  path: ".shipyard/consignments"
+history:
+  path: ".shipyard/history.json"
+`
+	require.NoError(t, os.WriteFile(filepath.Join(shipyardDir, "shipyard.yaml"), []byte(configContent), 0644))
+
+	for name, v := range map[string]string{"core": "1.0.0", "api": "2.0.0"} {
+		dir := filepath.Join(tempDir, name)
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		content := fmt.Sprintf("package %s\n\nconst Version = %q\n", name, v)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "version.go"), []byte(content), 0644))
+	}
+	chartDir := filepath.Join(tempDir, "chart")
+	require.NoError(t, os.MkdirAll(chartDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(chartDir, "Chart.yaml"),
+		[]byte("apiVersion: v2\nname: chart\nversion: 0.3.0\nappVersion: \"2.0.0\"\n"), 0644))
+
+	createTestConsignmentForVersion(t, consignmentsDir, "c1", []string{"core"}, "minor", "Add feature")
+
+	var writes []string
+	original := release.NewVersionHandler
+	release.NewVersionHandler = func(pkg config.Package, pkgPath string, ctx *ecosystem.HandlerContext) (ecosystem.Handler, error) {
+		handler, err := original(pkg, pkgPath, ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &recordingHandler{Handler: handler, name: pkg.Name, writes: &writes}, nil
+	}
+	t.Cleanup(func() { release.NewVersionHandler = original })
+
+	output := captureOutput(func() {
+		require.NoError(t, runVersionInDir(tempDir, &VersionCommandOptions{NoCommit: true, NoTag: true, Verbose: true}))
+	})
+
+	assert.Equal(t, []string{"core", "api", "chart"}, writes)
+	assert.Contains(t, output, "Apply order: core → api → chart")
+
+	apiVersion, err := os.ReadFile(filepath.Join(tempDir, "api", "version.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(apiVersion), `"2.1.0"`)
+
+	chart, err := os.ReadFile(filepath.Join(chartDir, "Chart.yaml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(chart), "version: 0.4.0")
+	assert.Contains(t, string(chart), `appVersion: "2.1.0"`)
+}
+
+// TestVersionCommand_PrereleaseFlag verifies release candidates are cut with
+// --prerelease and promoted to the final release once the flag is dropped
+func TestVersionCommand_PrereleaseFlag(t *testing.T) {
+	tempDir := setupVersionTestRepo(t)
+	repo, err := gogit.PlainInit(tempDir, false)
+	require.NoError(t, err)
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+	_, err = wt.Add(".")
+	require.NoError(t, err)
+	_, err = wt.Commit("initial commit", &gogit.CommitOptions{
+		Author: &object.Signature{Name: "Test", Email: "test@example.com"},
+	})
+	require.NoError(t, err)
+
+	consignmentsDir := filepath.Join(tempDir, ".shipyard", "consignments")
+	historyPath := filepath.Join(tempDir, ".shipyard", "history.json")
+	versionFile := filepath.Join(tempDir, "test-package", "version.go")
+
+	steps := []struct {
+		changeType string
+		prerelease string
+		want       string
+	}{
+		{changeType: "minor", prerelease: "rc", want: "1.1.0-rc.1"},
+		{changeType: "patch", prerelease: "rc", want: "1.1.0-rc.2"},
+		{changeType: "patch", want: "1.1.0"},
+	}
+
+	for i, step := range steps {
+		createTestConsignmentForVersion(t, consignmentsDir, fmt.Sprintf("c%d", i), []string{"test-package"}, step.changeType, "Change")
+		_, err = wt.Add(".")
+		require.NoError(t, err)
+		base, err := wt.Commit("add consignment", &gogit.CommitOptions{
+			Author: &object.Signature{Name: "Test", Email: "test@example.com"},
+		})
+		require.NoError(t, err)
+
+		captureOutput(func() {
+			require.NoError(t, runVersionInDir(tempDir, &VersionCommandOptions{Prerelease: step.prerelease}))
+		})
+
+		content, err := os.ReadFile(versionFile)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), fmt.Sprintf("%q", step.want))
+
+		entries, err := history.ReadHistory(historyPath)
+		require.NoError(t, err)
+		require.Len(t, entries, i+1)
+		latest := entries[len(entries)-1]
+		assert.Equal(t, step.want, latest.Version)
+		assert.Contains(t, latest.Tag, step.want)
+		assert.Equal(t, base.String(), latest.BaseCommitSHA, "history records the commit the release was cut from")
+
+		_, err = repo.Tag(latest.Tag)
+		assert.NoError(t, err, "tag %s should exist", latest.Tag)
+	}
+
+	t.Run("invalid identifier", func(t *testing.T) {
+		err := runVersionInDir(tempDir, &VersionCommandOptions{Prerelease: "1rc"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid pre-release identifier")
+	})
+}
+
+func TestVersionCommand_MessageBudgetPreflight(t *testing.T) {
+	tempDir := setupVersionTestRepo(t)
+	configPath := filepath.Join(tempDir, ".shipyard", "shipyard.yaml")
+	configContent := `packages:
+  - name: test-package
+    path: ./test-package
+    ecosystem: go
+templates:
+  commitMessage:
+    source: "builtin:detailed"
+  maxMessageBytes: 10
+`
+	require.NoError(t, os.WriteFile(configPath, []byte(configContent), 0644))
+
+	consignmentsDir := filepath.Join(tempDir, ".shipyard", "consignments")
+	for i := 0; i < 50; i++ {
+		createTestConsignmentForVersion(t, consignmentsDir, fmt.Sprintf("c%02d", i), []string{"test-package"}, "patch", fmt.Sprintf("Fix issue %02d", i))
+	}
+
+	versionFile := filepath.Join(tempDir, "test-package", "version.go")
+	before, err := os.ReadFile(versionFile)
+	require.NoError(t, err)
+
+	err = runVersionInDir(tempDir, &VersionCommandOptions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "commit message")
+	assert.Contains(t, err.Error(), "templates.maxMessageBytes (10)")
+	assert.Contains(t, err.Error(), "[message-size]")
+
+	// Nothing was mutated
+	after, err := os.ReadFile(versionFile)
+	require.NoError(t, err)
+	assert.Equal(t, string(before), string(after))
+	remaining, err := os.ReadDir(consignmentsDir)
+	require.NoError(t, err)
+	assert.Len(t, remaining, 50)
+	historyContent, err := os.ReadFile(filepath.Join(tempDir, ".shipyard", "history.json"))
+	require.NoError(t, err)
+	assert.Equal(t, "[]", string(historyContent))
+	assert.NoFileExists(t, filepath.Join(tempDir, "test-package", "CHANGELOG.md"))
+
+	t.Run("warn level commits the oversized message", func(t *testing.T) {
+		repo, err := gogit.PlainInit(tempDir, false)
+		require.NoError(t, err)
+		wt, err := repo.Worktree()
+		require.NoError(t, err)
+		_, err = wt.Add(".")
+		require.NoError(t, err)
+		_, err = wt.Commit("initial commit", &gogit.CommitOptions{
+			Author: &object.Signature{Name: "Test", Email: "test@example.com"},
+		})
+		require.NoError(t, err)
+
+		output := captureOutput(func() {
+			require.NoError(t, runVersionInDir(tempDir, &VersionCommandOptions{MaxSeverity: "warn"}))
+		})
+		assert.Contains(t, output, "[message-size]")
+
+		after, err := os.ReadFile(versionFile)
+		require.NoError(t, err)
+		assert.Contains(t, string(after), `"1.0.1"`)
+	})
+}
+
+// TestVersionCommand_TagCollisionRule verifies existing release tags fail the
+// pre-flight by default and are skipped when the rule is relaxed
+func TestVersionCommand_TagCollisionRule(t *testing.T) {
+	setup := func(t *testing.T) (string, *gogit.Repository) {
+		t.Helper()
+		tempDir := setupVersionTestRepo(t)
+		repo, err := gogit.PlainInit(tempDir, false)
+		require.NoError(t, err)
+		wt, err := repo.Worktree()
+		require.NoError(t, err)
+		createTestConsignmentForVersion(t, filepath.Join(tempDir, ".shipyard", "consignments"), "c1", []string{"test-package"}, "patch", "Fix bug")
+		_, err = wt.Add(".")
+		require.NoError(t, err)
+		head, err := wt.Commit("initial commit", &gogit.CommitOptions{
+			Author: &object.Signature{Name: "Test", Email: "test@example.com"},
+		})
+		require.NoError(t, err)
+		_, err = repo.CreateTag("v1.0.1", head, nil)
+		require.NoError(t, err)
+		return tempDir, repo
+	}
+
+	t.Run("error by default", func(t *testing.T) {
+		tempDir, _ := setup(t)
+		err := runVersionInDir(tempDir, &VersionCommandOptions{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "tag v1.0.1 for test-package already exists")
+		assert.Contains(t, err.Error(), "[tag-collision]")
+
+		content, err := os.ReadFile(filepath.Join(tempDir, "test-package", "version.go"))
+		require.NoError(t, err)
+		assert.Contains(t, string(content), `"1.0.0"`, "pre-flight failure must not touch files")
+	})
+
+	t.Run("warn in config skips the tag", func(t *testing.T) {
+		tempDir, repo := setup(t)
+		configPath := filepath.Join(tempDir, ".shipyard", "shipyard.yaml")
+		configContent, err := os.ReadFile(configPath)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(configPath, append(configContent, []byte("rules:\n  tag-collision: warn\n")...), 0644))
+
+		tagBefore, err := repo.Tag("v1.0.1")
+		require.NoError(t, err)
+
+		output := captureOutput(func() {
+			require.NoError(t, runVersionInDir(tempDir, &VersionCommandOptions{}))
+		})
+		assert.Contains(t, output, "[tag-collision]")
+
+		tagAfter, err := repo.Tag("v1.0.1")
+		require.NoError(t, err)
+		assert.Equal(t, tagBefore.Hash(), tagAfter.Hash(), "existing tag must not be moved")
+
+		head, err := repo.Head()
+		require.NoError(t, err)
+		assert.NotEqual(t, tagAfter.Hash(), head.Hash(), "release commit is still created")
+	})
+
+	t.Run("flag escalates config warn", func(t *testing.T) {
+		tempDir, _ := setup(t)
+		configPath := filepath.Join(tempDir, ".shipyard", "shipyard.yaml")
+		configContent, err := os.ReadFile(configPath)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(configPath, append(configContent, []byte("rules:\n  tag-collision: warn\n")...), 0644))
+
+		err = runVersionInDir(tempDir, &VersionCommandOptions{MaxSeverity: "error"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "[tag-collision]")
+	})
+}
+
+func TestVersionCommand_CalVerPackages(t *testing.T) {
+	today := time.Now()
+	period := fmt.Sprintf("%04d.%02d", today.Year(), int(today.Month()))
+
+	setup := func(t *testing.T, toolsVersion string, historyJSON string) string {
+		t.Helper()
+		tempDir := t.TempDir()
+		consignmentsDir := filepath.Join(tempDir, ".shipyard", "consignments")
+		require.NoError(t, os.MkdirAll(consignmentsDir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, ".shipyard", "history.json"), []byte(historyJSON), 0644))
+
+		configContent := `packages:
+  - name: core
+    path: ./core
+    ecosystem: go
+  - name: tools
+    path: ./tools
+    ecosystem: go
+    versioningScheme: calver
+    calverFormat: YYYY.0M.MICRO
+    dependencies:
+      - package: core
+        strategy: linked
+consignments:
+  path: .shipyard/consignments
+history:
+  path: .shipyard/history.json
+`
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, ".shipyard", "shipyard.yaml"), []byte(configContent), 0644))
+
+		for name, ver := range map[string]string{"core": "1.0.0", "tools": toolsVersion} {
+			pkgDir := filepath.Join(tempDir, name)
+			require.NoError(t, os.MkdirAll(pkgDir, 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(pkgDir, "version.go"),
+				[]byte(fmt.Sprintf("package pkg\n\nconst Version = %q\n", ver)), 0644))
+		}
+		return tempDir
+	}
+
+	readVersion := func(t *testing.T, dir, pkg string) string {
+		t.Helper()
+		content, err := os.ReadFile(filepath.Join(dir, pkg, "version.go"))
+		require.NoError(t, err)
+		return string(content)
+	}
+
+	t.Run("dependency bump starts a new period", func(t *testing.T) {
+		tempDir := setup(t, "2001.01.4", "[]")
+		createTestConsignmentForVersion(t, filepath.Join(tempDir, ".shipyard", "consignments"), "c1", []string{"core"}, "minor", "Add feature")
+
+		captureOutput(func() {
+			require.NoError(t, runVersionInDir(tempDir, &VersionCommandOptions{NoCommit: true, NoTag: true, NoPublish: true}))
+		})
+
+		assert.Contains(t, readVersion(t, tempDir, "core"), `"1.1.0"`)
+		assert.Contains(t, readVersion(t, tempDir, "tools"), fmt.Sprintf("%q", period+".0"))
+
+		entries, err := history.ReadHistory(filepath.Join(tempDir, ".shipyard", "history.json"))
+		require.NoError(t, err)
+		versions := map[string]string{}
+		for _, e := range entries {
+			versions[e.Package] = e.Version
+		}
+		assert.Equal(t, "1.1.0", versions["core"])
+	})
+
+	t.Run("next micro follows history", func(t *testing.T) {
+		// The version file lags behind the latest archived release this period
+		historyJSON := fmt.Sprintf(`[{"version":"%s.3","package":"tools","tag":"v%s.3","timestamp":"%s","consignments":[]}]`,
+			period, period, today.UTC().Format(time.RFC3339))
+		tempDir := setup(t, period+".1", historyJSON)
+		createTestConsignmentForVersion(t, filepath.Join(tempDir, ".shipyard", "consignments"), "c1", []string{"tools"}, "patch", "Fix script")
+
+		captureOutput(func() {
+			require.NoError(t, runVersionInDir(tempDir, &VersionCommandOptions{NoCommit: true, NoTag: true, NoPublish: true}))
+		})
+
+		assert.Contains(t, readVersion(t, tempDir, "tools"), fmt.Sprintf("%q", period+".4"))
+		assert.Contains(t, readVersion(t, tempDir, "core"), `"1.0.0"`)
+
+		changelog, err := os.ReadFile(filepath.Join(tempDir, "tools", "CHANGELOG.md"))
+		require.NoError(t, err)
+		assert.Contains(t, string(changelog), "["+period+".4]")
+	})
+
+	t.Run("unparseable calver version", func(t *testing.T) {
+		tempDir := setup(t, "1.2.3", "[]")
+		createTestConsignmentForVersion(t, filepath.Join(tempDir, ".shipyard", "consignments"), "c1", []string{"tools"}, "patch", "Fix script")
+
+		err := runVersionInDir(tempDir, &VersionCommandOptions{NoCommit: true, NoTag: true, NoPublish: true})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to read version for tools")
+	})
+}
+
+func TestVersionCommand_TagOnlyVersionFromTags(t *testing.T) {
+	const goPackage = `  - name: cli
+    path: ./cli
+    ecosystem: go
+    versionFiles: [tag-only]
+`
+	setup := func(t *testing.T, pkgConfig string, tags ...string) string {
+		t.Helper()
+		tempDir := t.TempDir()
+		consignmentsDir := filepath.Join(tempDir, ".shipyard", "consignments")
+		require.NoError(t, os.MkdirAll(consignmentsDir, 0755))
+		require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "cli"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, "cli", "main.go"), []byte("package main\n"), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, ".shipyard", "history.json"), []byte("[]"), 0644))
+
+		configContent := "packages:\n" + pkgConfig + `consignments:
+  path: .shipyard/consignments
+history:
+  path: .shipyard/history.json
+`
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, ".shipyard", "shipyard.yaml"), []byte(configContent), 0644))
+		createTestConsignmentForVersion(t, consignmentsDir, "c1", []string{"cli"}, "minor", "Add flag")
+
+		repo, err := gogit.PlainInit(tempDir, false)
+		require.NoError(t, err)
+		wt, err := repo.Worktree()
+		require.NoError(t, err)
+		_, err = wt.Add(".")
+		require.NoError(t, err)
+		head, err := wt.Commit("initial commit", &gogit.CommitOptions{
+			Author: &object.Signature{Name: "Test", Email: "test@example.com"},
+		})
+		require.NoError(t, err)
+		for _, tag := range tags {
+			_, err := repo.CreateTag(tag, head, nil)
+			require.NoError(t, err)
+		}
+		return tempDir
+	}
+
+	currentVersion := func(t *testing.T, dir string) string {
+		t.Helper()
+		cfg, err := config.LoadFromDir(dir)
+		require.NoError(t, err)
+		versions, err := release.ReadAllCurrentVersions(dir, cfg, release.Settings{})
+		require.NoError(t, err)
+		return versions["cli"].String()
+	}
+
+	t.Run("latest matching tag", func(t *testing.T) {
+		tempDir := setup(t, goPackage, "v1.0.0", "v1.10.0", "v1.9.0", "other/v9.0.0", "vnext")
+		assert.Equal(t, "1.10.0", currentVersion(t, tempDir))
+
+		captureOutput(func() {
+			require.NoError(t, runVersionInDir(tempDir, &VersionCommandOptions{NoPublish: true}))
+		})
+		exists, err := git.VerifyTagExists(tempDir, "v1.11.0")
+		require.NoError(t, err)
+		assert.True(t, exists)
+		assert.NoFileExists(t, filepath.Join(tempDir, "cli", "version.go"), "tag-only packages write no version file")
+		assert.Equal(t, "1.11.0", currentVersion(t, tempDir))
+	})
+
+	t.Run("never released", func(t *testing.T) {
+		tempDir := setup(t, goPackage)
+		cfg, err := config.LoadFromDir(tempDir)
+		require.NoError(t, err)
+		_, err = release.ReadAllCurrentVersions(tempDir, cfg, release.Settings{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "set initialVersion")
+	})
+
+	t.Run("initial version", func(t *testing.T) {
+		tempDir := setup(t, goPackage+"    initialVersion: 0.1.0\n")
+		assert.Equal(t, "0.1.0", currentVersion(t, tempDir))
+
+		captureOutput(func() {
+			require.NoError(t, runVersionInDir(tempDir, &VersionCommandOptions{NoPublish: true}))
+		})
+		exists, err := git.VerifyTagExists(tempDir, "v0.2.0")
+		require.NoError(t, err)
+		assert.True(t, exists)
+	})
+
+	t.Run("tagOnly on another ecosystem", func(t *testing.T) {
+		tempDir := setup(t, `  - name: cli
+    path: ./cli
+    ecosystem: npm
+    tagOnly: true
+`, "v2.3.0")
+		manifest := `{"name": "cli", "version": "0.0.0-development"}`
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, "cli", "package.json"), []byte(manifest), 0644))
+		assert.Equal(t, "2.3.0", currentVersion(t, tempDir))
+
+		captureOutput(func() {
+			require.NoError(t, runVersionInDir(tempDir, &VersionCommandOptions{NoPublish: true}))
+		})
+		exists, err := git.VerifyTagExists(tempDir, "v2.4.0")
+		require.NoError(t, err)
+		assert.True(t, exists)
+		content, err := os.ReadFile(filepath.Join(tempDir, "cli", "package.json"))
+		require.NoError(t, err)
+		assert.Equal(t, manifest, string(content), "tag-only packages keep their manifest version")
+
+		history, err := os.ReadFile(filepath.Join(tempDir, ".shipyard", "history.json"))
+		require.NoError(t, err)
+		assert.Contains(t, string(history), `"version": "2.4.0"`)
+		changelog, err := os.ReadFile(filepath.Join(tempDir, "cli", "CHANGELOG.md"))
+		require.NoError(t, err)
+		assert.Contains(t, string(changelog), "Add flag")
+	})
+}
+
+func TestVersionCommand_SelectedConsignments(t *testing.T) {
+	tempDir := t.TempDir()
+	consignmentsDir := filepath.Join(tempDir, ".shipyard", "consignments")
+	require.NoError(t, os.MkdirAll(consignmentsDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, ".shipyard", "history.json"), []byte("[]"), 0644))
+	configContent := `packages:
+  - name: core
+    path: ./core
+    ecosystem: npm
+  - name: web
+    path: ./web
+    ecosystem: npm
+consignments:
+  path: .shipyard/consignments
+history:
+  path: .shipyard/history.json
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, ".shipyard", "shipyard.yaml"), []byte(configContent), 0644))
+	for _, name := range []string{"core", "web"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(tempDir, name), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, name, "package.json"), []byte(`{"name": "`+name+`", "version": "1.0.0"}`), 0644))
+	}
+	createTestConsignmentForVersion(t, consignmentsDir, "c1", []string{"core"}, "patch", "Fix crash")
+	createTestConsignmentForVersion(t, consignmentsDir, "c2", []string{"core"}, "minor", "Add option awaiting QA")
+	createTestConsignmentForVersion(t, consignmentsDir, "c3", []string{"web"}, "patch", "Fix layout")
+
+	packageVersion := func(name string) string {
+		t.Helper()
+		content, err := os.ReadFile(filepath.Join(tempDir, name, "package.json"))
+		require.NoError(t, err)
+		return string(content)
+	}
+	opts := func(ids ...string) *VersionCommandOptions {
+		return &VersionCommandOptions{NoCommit: true, NoTag: true, NoPublish: true, Consignments: ids}
+	}
+
+	t.Run("unknown ID", func(t *testing.T) {
+		err := runVersionInDir(tempDir, opts("c1", "c9"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "consignment c9 is not pending")
+		assert.FileExists(t, filepath.Join(consignmentsDir, "c1.md"))
+	})
+
+	t.Run("partial", func(t *testing.T) {
+		output := captureOutput(func() {
+			require.NoError(t, runVersionInDir(tempDir, opts("c1", "c3")))
+		})
+		assert.Contains(t, output, "c2 makes a minor change to core but is not selected")
+
+		assert.Contains(t, packageVersion("core"), `"version": "1.0.1"`)
+		assert.Contains(t, packageVersion("web"), `"version": "1.0.1"`)
+		assert.NoFileExists(t, filepath.Join(consignmentsDir, "c1.md"))
+		assert.NoFileExists(t, filepath.Join(consignmentsDir, "c3.md"))
+		assert.FileExists(t, filepath.Join(consignmentsDir, "c2.md"), "unselected consignments stay pending")
+
+		history, err := os.ReadFile(filepath.Join(tempDir, ".shipyard", "history.json"))
+		require.NoError(t, err)
+		assert.Contains(t, string(history), "Fix crash")
+		assert.NotContains(t, string(history), "awaiting QA")
+		changelog, err := os.ReadFile(filepath.Join(tempDir, "core", "CHANGELOG.md"))
+		require.NoError(t, err)
+		assert.Contains(t, string(changelog), "Fix crash")
+		assert.NotContains(t, string(changelog), "awaiting QA")
+	})
+
+	t.Run("full run ships the rest", func(t *testing.T) {
+		captureOutput(func() {
+			require.NoError(t, runVersionInDir(tempDir, opts()))
+		})
+		assert.Contains(t, packageVersion("core"), `"version": "1.1.0"`)
+		assert.Contains(t, packageVersion("web"), `"version": "1.0.1"`)
+		assert.NoFileExists(t, filepath.Join(consignmentsDir, "c2.md"))
+		changelog, err := os.ReadFile(filepath.Join(tempDir, "core", "CHANGELOG.md"))
+		require.NoError(t, err)
+		assert.Contains(t, string(changelog), "awaiting QA")
+	})
+}
+
+func TestVersionCommand_SetVersion(t *testing.T) {
+	setup := func(t *testing.T, historyContent string) (string, string) {
+		t.Helper()
+		tempDir := t.TempDir()
+		consignmentsDir := filepath.Join(tempDir, ".shipyard", "consignments")
+		require.NoError(t, os.MkdirAll(consignmentsDir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, ".shipyard", "history.json"), []byte(historyContent), 0644))
+		configContent := `packages:
+  - name: core
+    path: ./core
+    ecosystem: npm
+  - name: web
+    path: ./web
+    ecosystem: npm
+    options:
+      dependencyRange: caret
+    dependencies:
+      - package: core
+        strategy: linked
+consignments:
+  path: .shipyard/consignments
+history:
+  path: .shipyard/history.json
+`
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, ".shipyard", "shipyard.yaml"), []byte(configContent), 0644))
+		files := map[string]string{
+			"core/package.json": `{"name": "@org/core", "version": "1.0.0"}`,
+			"web/package.json":  `{"name": "@org/web", "version": "1.0.0", "dependencies": {"@org/core": "^1.0.0"}}`,
+		}
+		for path, content := range files {
+			require.NoError(t, os.MkdirAll(filepath.Join(tempDir, filepath.Dir(path)), 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(tempDir, path), []byte(content), 0644))
+		}
+		createTestConsignmentForVersion(t, consignmentsDir, "c1", []string{"core"}, "minor", "Launch dashboard")
+		return tempDir, consignmentsDir
+	}
+	run := func(dir string, setVersions []string, force bool) error {
+		var err error
+		captureOutput(func() {
+			err = runVersionInDir(dir, &VersionCommandOptions{NoCommit: true, NoTag: true, NoPublish: true, SetVersions: setVersions, Force: force})
+		})
+		return err
+	}
+	readFile := func(t *testing.T, path string) string {
+		t.Helper()
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		return string(content)
+	}
+
+	t.Run("override with propagation", func(t *testing.T) {
+		tempDir, consignmentsDir := setup(t, "[]")
+		require.NoError(t, run(tempDir, []string{"core=2.0.0"}, false))
+
+		assert.Contains(t, readFile(t, filepath.Join(tempDir, "core", "package.json")), `"version": "2.0.0"`)
+		web := readFile(t, filepath.Join(tempDir, "web", "package.json"))
+		assert.Contains(t, web, `"version": "1.1.0"`, "dependents keep the bump the change calls for")
+		assert.Contains(t, web, `"@org/core": "^2.0.0"`, "dependents depend on the version that was set")
+		assert.NoFileExists(t, filepath.Join(consignmentsDir, "c1.md"), "the consignments are consumed")
+		assert.Contains(t, readFile(t, filepath.Join(tempDir, "core", "CHANGELOG.md")), "2.0.0")
+
+		entries, err := history.ReadHistory(filepath.Join(tempDir, ".shipyard", "history.json"))
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "2.0.0", entries[0].Version)
+		assert.Equal(t, "1.0.0", entries[0].PreviousVersion)
+		assert.True(t, entries[0].SetVersion)
+
+		err = run(tempDir, []string{"core=3.0.0"}, false)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "core: the package has no pending changes")
+	})
+
+	t.Run("downgrade below history", func(t *testing.T) {
+		historyContent := `[{"version": "3.0.0", "package": "core", "tag": "v3.0.0", "timestamp": "2024-01-01T00:00:00Z", "consignments": []}]`
+		tempDir, consignmentsDir := setup(t, historyContent)
+		err := run(tempDir, []string{"core=2.5.0"}, false)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--set-version core=2.5.0 does not move past 3.0.0")
+		assert.FileExists(t, filepath.Join(consignmentsDir, "c1.md"))
+
+		require.NoError(t, run(tempDir, []string{"core=2.5.0"}, true), "--force allows going backwards")
+		assert.Contains(t, readFile(t, filepath.Join(tempDir, "core", "package.json")), `"version": "2.5.0"`)
+	})
+
+	t.Run("downgrade below manifest", func(t *testing.T) {
+		tempDir, _ := setup(t, "[]")
+		err := run(tempDir, []string{"core=1.0.0"}, false)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "does not move past 1.0.0")
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		tempDir, _ := setup(t, "[]")
+		for value, want := range map[string]string{
+			"2.0.0":     "must be package=version",
+			"api=2.0.0": `unknown package "api"`,
+			"core=two":  `invalid version "two" for core`,
+			"core=":     "must be package=version",
+		} {
+			err := run(tempDir, []string{value}, false)
+			require.Error(t, err, value)
+			assert.Contains(t, err.Error(), want, value)
+		}
+	})
+}
+
+func TestVersionCommand_NPMSiblingRanges(t *testing.T) {
+	tempDir := t.TempDir()
+	consignmentsDir := filepath.Join(tempDir, ".shipyard", "consignments")
+	require.NoError(t, os.MkdirAll(consignmentsDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, ".shipyard", "history.json"), []byte("[]"), 0644))
+	configContent := `packages:
+  - name: core
+    path: ./core
+    ecosystem: npm
+  - name: web
+    path: ./web
+    ecosystem: npm
+    options:
+      dependencyRange: caret
+    dependencies:
+      - package: core
+        strategy: linked
+consignments:
+  path: .shipyard/consignments
+history:
+  path: .shipyard/history.json
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, ".shipyard", "shipyard.yaml"), []byte(configContent), 0644))
+	files := map[string]string{
+		"core/package.json":     `{"name": "@org/core", "version": "1.0.0"}`,
+		"web/package.json":      `{"name": "@org/web", "version": "1.0.0", "dependencies": {"@org/core": "^1.0.0"}}`,
+		"web/package-lock.json": `{"name": "@org/web", "version": "1.0.0", "lockfileVersion": 3, "packages": {"": {"name": "@org/web", "version": "1.0.0", "dependencies": {"@org/core": "^1.0.0"}}}}`,
+	}
+	for path, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Join(tempDir, filepath.Dir(path)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, path), []byte(content), 0644))
+	}
+	createTestConsignmentForVersion(t, consignmentsDir, "c1", []string{"core"}, "major", "Drop legacy API")
+
+	captureOutput(func() {
+		require.NoError(t, runVersionInDir(tempDir, &VersionCommandOptions{NoCommit: true, NoTag: true, NoPublish: true}))
+	})
+
+	web, err := os.ReadFile(filepath.Join(tempDir, "web", "package.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(web), `"@org/core": "^2.0.0"`)
+	lock, err := os.ReadFile(filepath.Join(tempDir, "web", "package-lock.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(lock), `"@org/core": "^2.0.0"`)
+	assert.Contains(t, string(lock), `"version": "2.0.0"`, "the lockfile follows the linked bump of web")
+}
+
+func TestVersionCommand_PackageChangelogTemplates(t *testing.T) {
+	setup := func(t *testing.T) string {
+		t.Helper()
+		tempDir := t.TempDir()
+		shipyardDir := filepath.Join(tempDir, ".shipyard")
+		require.NoError(t, os.MkdirAll(filepath.Join(shipyardDir, "consignments"), 0755))
+		configContent := `packages:
+  - name: core
+    path: ./core
+    ecosystem: go
+  - name: charts
+    path: ./charts
+    ecosystem: go
+    changelogTemplate: builtin:keepachangelog
+templates:
+  changelog:
+    source: builtin:default
+`
+		require.NoError(t, os.WriteFile(filepath.Join(shipyardDir, "shipyard.yaml"), []byte(configContent), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(shipyardDir, "history.json"), []byte("[]"), 0644))
+		writeGoVersion(t, tempDir, "core", "1.0.0")
+		writeGoVersion(t, tempDir, "charts", "1.0.0")
+		createTestConsignmentForVersion(t, filepath.Join(shipyardDir, "consignments"), "c1", []string{"core"}, "minor", "Add feature")
+		createTestConsignmentForVersion(t, filepath.Join(shipyardDir, "consignments"), "c2", []string{"charts"}, "patch", "Fix chart")
+		return tempDir
+	}
+	readChangelog := func(t *testing.T, dir, pkg string) string {
+		t.Helper()
+		content, err := os.ReadFile(filepath.Join(dir, pkg, "CHANGELOG.md"))
+		require.NoError(t, err)
+		return string(content)
+	}
+
+	t.Run("packages render with their own templates", func(t *testing.T) {
+		tempDir := setup(t)
+
+		var err error
+		captureOutput(func() {
+			err = runVersionWithDir(tempDir, &VersionCommandOptions{NoCommit: true, NoTag: true})
+		})
+		require.NoError(t, err)
+
+		assert.NotContains(t, readChangelog(t, tempDir, "core"), "Keep a Changelog")
+		assert.Contains(t, readChangelog(t, tempDir, "charts"), "Keep a Changelog")
+	})
+
+	t.Run("template flag overrides every package", func(t *testing.T) {
+		tempDir := setup(t)
+
+		var err error
+		captureOutput(func() {
+			err = runVersionWithDir(tempDir, &VersionCommandOptions{NoCommit: true, NoTag: true, Template: "builtin:keepachangelog"})
+		})
+		require.NoError(t, err)
+
+		assert.Contains(t, readChangelog(t, tempDir, "core"), "Keep a Changelog")
+		assert.Contains(t, readChangelog(t, tempDir, "charts"), "Keep a Changelog")
+	})
+
+	t.Run("unknown template flag fails before releasing", func(t *testing.T) {
+		tempDir := setup(t)
+
+		err := runVersionWithDir(tempDir, &VersionCommandOptions{NoCommit: true, NoTag: true, Template: "builtin:missing"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "builtin template not found")
+		assert.NoFileExists(t, filepath.Join(tempDir, "core", "CHANGELOG.md"))
+	})
+}
+
+func TestVersionCommand_FrozenPackages(t *testing.T) {
+	configFor := func(frozen bool) string {
+		return fmt.Sprintf(`packages:
+  - name: core
+    path: ./core
+    ecosystem: go
+  - name: api-v1
+    path: ./api-v1
+    ecosystem: go
+    frozen: %t
+    dependencies:
+      - package: core
+        strategy: linked
+consignments:
+  path: .shipyard/consignments
+history:
+  path: .shipyard/history.json
+`, frozen)
+	}
+	setup := func(t *testing.T) (string, string) {
+		t.Helper()
+		tempDir := t.TempDir()
+		shipyardDir := filepath.Join(tempDir, ".shipyard")
+		consignmentsDir := filepath.Join(shipyardDir, "consignments")
+		require.NoError(t, os.MkdirAll(consignmentsDir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(shipyardDir, "shipyard.yaml"), []byte(configFor(true)), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(shipyardDir, "history.json"), []byte("[]"), 0644))
+		writeGoVersion(t, tempDir, "core", "1.0.0")
+		writeGoVersion(t, tempDir, "api-v1", "1.0.0")
+		createTestConsignmentForVersion(t, consignmentsDir, "c1", []string{"core"}, "minor", "Add feature")
+		createTestConsignmentForVersion(t, consignmentsDir, "c2", []string{"api-v1"}, "patch", "Fix legacy endpoint")
+		return tempDir, consignmentsDir
+	}
+	readVersion := func(t *testing.T, dir string) string {
+		t.Helper()
+		v, err := ecosystem.NewGoEcosystem(dir).ReadVersion()
+		require.NoError(t, err)
+		return v.String()
+	}
+	opts := func() *VersionCommandOptions {
+		return &VersionCommandOptions{NoCommit: true, NoTag: true, NoPublish: true}
+	}
+
+	t.Run("consignments survive and apply after unfreezing", func(t *testing.T) {
+		tempDir, consignmentsDir := setup(t)
+
+		output := captureOutput(func() {
+			require.NoError(t, runVersionInDir(tempDir, opts()))
+		})
+		assert.Equal(t, "1.1.0", readVersion(t, filepath.Join(tempDir, "core")))
+		assert.Equal(t, "1.0.0", readVersion(t, filepath.Join(tempDir, "api-v1")), "neither its consignment nor the linked core bump applies")
+		assert.NoFileExists(t, filepath.Join(consignmentsDir, "c1.md"))
+		assert.FileExists(t, filepath.Join(consignmentsDir, "c2.md"))
+		assert.Contains(t, output, "Skipped frozen package(s): api-v1")
+		assert.Contains(t, output, "Kept 1 consignment(s) for frozen packages: c2")
+
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, ".shipyard", "shipyard.yaml"), []byte(configFor(false)), 0644))
+		output = captureOutput(func() {
+			require.NoError(t, runVersionInDir(tempDir, opts()))
+		})
+		assert.Equal(t, "1.1.0", readVersion(t, filepath.Join(tempDir, "core")))
+		assert.Equal(t, "1.0.1", readVersion(t, filepath.Join(tempDir, "api-v1")))
+		assert.NoFileExists(t, filepath.Join(consignmentsDir, "c2.md"))
+		assert.NotContains(t, output, "frozen")
+	})
+
+	t.Run("include-frozen versions frozen packages", func(t *testing.T) {
+		tempDir, consignmentsDir := setup(t)
+
+		o := opts()
+		o.IncludeFrozen = true
+		output := captureOutput(func() {
+			require.NoError(t, runVersionInDir(tempDir, o))
+		})
+		assert.Equal(t, "1.0.1", readVersion(t, filepath.Join(tempDir, "api-v1")))
+		assert.NoFileExists(t, filepath.Join(consignmentsDir, "c2.md"))
+		assert.NotContains(t, output, "frozen")
+	})
+
+}
+
+func TestVersionCommand_UnreleasedChangelog(t *testing.T) {
+	setup := func(t *testing.T) (string, string) {
+		t.Helper()
+		tempDir := t.TempDir()
+		shipyardDir := filepath.Join(tempDir, ".shipyard")
+		consignmentsDir := filepath.Join(shipyardDir, "consignments")
+		require.NoError(t, os.MkdirAll(consignmentsDir, 0755))
+		configContent := `packages:
+  - name: core
+    path: ./core
+    ecosystem: go
+    changelogTemplate: builtin:keepachangelog
+  - name: api
+    path: ./api
+    ecosystem: go
+    frozen: true
+`
+		require.NoError(t, os.WriteFile(filepath.Join(shipyardDir, "shipyard.yaml"), []byte(configContent), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(shipyardDir, "history.json"), []byte("[]"), 0644))
+		writeGoVersion(t, tempDir, "core", "1.0.0")
+		writeGoVersion(t, tempDir, "api", "1.0.0")
+		createTestConsignmentForVersion(t, consignmentsDir, "c1", []string{"core"}, "minor", "Add feature")
+		// Held while api is frozen
+		require.NoError(t, consignment.WriteConsignment(&consignment.Consignment{
+			ID:         "c2",
+			Timestamp:  time.Now(),
+			Packages:   []string{"core", "api"},
+			ChangeType: types.ChangeTypePatch,
+			Summary:    "Fix shared client",
+		}, consignmentsDir))
+		return tempDir, consignmentsDir
+	}
+	readChangelog := func(t *testing.T, dir string) string {
+		t.Helper()
+		content, err := os.ReadFile(filepath.Join(dir, "core", "CHANGELOG.md"))
+		require.NoError(t, err)
+		return string(content)
+	}
+
+	t.Run("regenerate lists pending consignments without releasing", func(t *testing.T) {
+		tempDir, consignmentsDir := setup(t)
+
+		captureOutput(func() {
+			require.NoError(t, runVersionWithDir(tempDir, &VersionCommandOptions{Regenerate: true}))
+		})
+
+		changelog := readChangelog(t, tempDir)
+		assert.Contains(t, changelog, "## [Unreleased]\n\n### Added\n- Change Add feature\n\n### Fixed\n- Fix shared client")
+		assert.NotContains(t, changelog, "## [1.")
+		assert.FileExists(t, filepath.Join(consignmentsDir, "c1.md"))
+		v, err := ecosystem.NewGoEcosystem(filepath.Join(tempDir, "core")).ReadVersion()
+		require.NoError(t, err)
+		assert.Equal(t, "1.0.0", v.String())
+	})
+
+	t.Run("release keeps consignments left pending under Unreleased", func(t *testing.T) {
+		tempDir, _ := setup(t)
+
+		captureOutput(func() {
+			require.NoError(t, runVersionWithDir(tempDir, &VersionCommandOptions{NoCommit: true, NoTag: true, IncludeUnreleased: true}))
+		})
+
+		changelog := readChangelog(t, tempDir)
+		unreleasedAt := strings.Index(changelog, "## [Unreleased]")
+		releasedAt := strings.Index(changelog, "## [1.1.0]")
+		require.NotEqual(t, -1, unreleasedAt)
+		require.NotEqual(t, -1, releasedAt)
+		assert.Less(t, unreleasedAt, releasedAt)
+		assert.Contains(t, changelog[unreleasedAt:releasedAt], "Fix shared client")
+		assert.Contains(t, changelog[releasedAt:], "Add feature")
+		assert.NotContains(t, changelog[releasedAt:], "Fix shared client")
+	})
+
+	t.Run("section disappears once shipped", func(t *testing.T) {
+		tempDir, _ := setup(t)
+
+		captureOutput(func() {
+			require.NoError(t, runVersionWithDir(tempDir, &VersionCommandOptions{Regenerate: true}))
+			require.NoError(t, runVersionWithDir(tempDir, &VersionCommandOptions{NoCommit: true, NoTag: true, IncludeUnreleased: true, IncludeFrozen: true}))
+		})
+
+		changelog := readChangelog(t, tempDir)
+		assert.NotContains(t, changelog, "Unreleased")
+		assert.Contains(t, changelog, "- Fix shared client")
+	})
+
+	t.Run("without the flag nothing pending is listed", func(t *testing.T) {
+		tempDir, _ := setup(t)
+
+		captureOutput(func() {
+			require.NoError(t, runVersionWithDir(tempDir, &VersionCommandOptions{NoCommit: true, NoTag: true}))
+		})
+
+		assert.NotContains(t, readChangelog(t, tempDir), "Unreleased")
+	})
+
+	t.Run("regenerate cannot be combined with a release option", func(t *testing.T) {
+		tempDir, _ := setup(t)
+
+		var err error
+		captureOutput(func() {
+			err = runVersionWithDir(tempDir, &VersionCommandOptions{Regenerate: true, Preview: true})
+		})
+		assert.ErrorContains(t, err, "--regenerate")
+	})
+}
+
+func TestVersionCommand_ChangelogSections(t *testing.T) {
+	tempDir := t.TempDir()
+	shipyardDir := filepath.Join(tempDir, ".shipyard")
+	consignmentsDir := filepath.Join(shipyardDir, "consignments")
+	require.NoError(t, os.MkdirAll(consignmentsDir, 0755))
+	configContent := `packages:
+  - name: core
+    path: ./core
+    ecosystem: go
+    changelogTemplate: builtin:keepachangelog
+changeTypes:
+  - name: patch
+    section: Performance
+changelog:
+  sectionOrder: [minor]
+`
+	require.NoError(t, os.WriteFile(filepath.Join(shipyardDir, "shipyard.yaml"), []byte(configContent), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(shipyardDir, "history.json"), []byte("[]"), 0644))
+	writeGoVersion(t, tempDir, "core", "1.0.0")
+	createTestConsignmentForVersion(t, consignmentsDir, "c1", []string{"core"}, "patch", "Cache parsed templates")
+	createTestConsignmentForVersion(t, consignmentsDir, "c2", []string{"core"}, "minor", "Add search")
+
+	captureOutput(func() {
+		require.NoError(t, runVersionWithDir(tempDir, &VersionCommandOptions{NoCommit: true, NoTag: true}))
+	})
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "core", "CHANGELOG.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "### Added\n- Change Add search\n\n### Performance\n- Change Cache parsed templates\n")
+	assert.NotContains(t, string(content), "### Fixed")
+}
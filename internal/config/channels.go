@@ -0,0 +1,81 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// StableChannel is the channel of regular releases. Its history is
+// history.path itself; every other channel keeps its own history file.
+const StableChannel = "stable"
+
+// ChannelConfig is a release channel: a stream of releases with its own
+// history. Channels other than stable release pre-releases named after the
+// channel, e.g. 1.3.0-beta.2 on the beta channel.
+type ChannelConfig struct {
+	Name     string   `yaml:"name"`
+	Branches []string `yaml:"branches,omitempty"` // Branch patterns that release on this channel, e.g. release/*
+}
+
+// channelNameRe matches channel names, which become pre-release identifiers
+var channelNameRe = regexp.MustCompile(`^[A-Za-z][0-9A-Za-z-]*$`)
+
+// validateChannels checks channel names and branch patterns
+func (c *Config) validateChannels() error {
+	seen := make(map[string]bool, len(c.Channels))
+	for _, channel := range c.Channels {
+		if !channelNameRe.MatchString(channel.Name) {
+			return fmt.Errorf("invalid channel name %q (letters, digits and -, starting with a letter)", channel.Name)
+		}
+		if seen[channel.Name] {
+			return fmt.Errorf("duplicate channel name: %s", channel.Name)
+		}
+		seen[channel.Name] = true
+		for _, pattern := range channel.Branches {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid branch pattern %q for channel %s: %w", pattern, channel.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// HasChannel reports whether name is the stable channel or a configured one
+func (c *Config) HasChannel(name string) bool {
+	if name == StableChannel {
+		return true
+	}
+	for _, channel := range c.Channels {
+		if channel.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ChannelForBranch returns the first configured channel with a branch
+// pattern matching branch, or "" when none does
+func (c *Config) ChannelForBranch(branch string) string {
+	for _, channel := range c.Channels {
+		for _, pattern := range channel.Branches {
+			if matched, _ := path.Match(pattern, branch); matched {
+				return channel.Name
+			}
+		}
+	}
+	return ""
+}
+
+// HistoryPathFor returns the history path of a channel: history.path for
+// the stable channel (or no channel), otherwise history.path with the
+// channel before its extension, e.g. .shipyard/history.beta.json
+func (c *Config) HistoryPathFor(channel string) string {
+	if channel == "" || channel == StableChannel {
+		return c.History.Path
+	}
+	ext := filepath.Ext(c.History.Path)
+	return strings.TrimSuffix(c.History.Path, ext) + "." + channel + ext
+}
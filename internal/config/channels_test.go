@@ -0,0 +1,62 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_ValidateChannels(t *testing.T) {
+	base := func(channels ...ChannelConfig) *Config {
+		return &Config{Packages: []Package{{Name: "core", Path: "./"}}, Channels: channels}
+	}
+
+	assert.NoError(t, base(ChannelConfig{Name: "beta", Branches: []string{"main"}}, ChannelConfig{Name: "stable", Branches: []string{"release/*"}}).Validate())
+	assert.ErrorContains(t, base(ChannelConfig{Name: "1beta"}).Validate(), `invalid channel name "1beta"`)
+	assert.ErrorContains(t, base(ChannelConfig{Name: "beta.1"}).Validate(), `invalid channel name "beta.1"`)
+	assert.ErrorContains(t, base(ChannelConfig{Name: "beta"}, ChannelConfig{Name: "beta"}).Validate(), "duplicate channel name: beta")
+	assert.ErrorContains(t, base(ChannelConfig{Name: "beta", Branches: []string{"release/["}}).Validate(), `invalid branch pattern "release/["`)
+}
+
+func TestConfig_ChannelForBranch(t *testing.T) {
+	cfg := &Config{Channels: []ChannelConfig{
+		{Name: "beta", Branches: []string{"main", "next"}},
+		{Name: "stable", Branches: []string{"release/*"}},
+	}}
+
+	assert.Equal(t, "beta", cfg.ChannelForBranch("main"))
+	assert.Equal(t, "beta", cfg.ChannelForBranch("next"))
+	assert.Equal(t, "stable", cfg.ChannelForBranch("release/1.x"))
+	assert.Empty(t, cfg.ChannelForBranch("release/1.x/hotfix"), "* does not cross /")
+	assert.Empty(t, cfg.ChannelForBranch("feature"))
+
+	assert.True(t, cfg.HasChannel("beta"))
+	assert.True(t, cfg.HasChannel(StableChannel))
+	assert.False(t, cfg.HasChannel("nightly"))
+	assert.True(t, (&Config{}).HasChannel(StableChannel), "stable always exists")
+}
+
+func TestConfig_HistoryPathFor(t *testing.T) {
+	cfg := &Config{History: HistoryConfig{Path: ".shipyard/history.json"}}
+
+	assert.Equal(t, ".shipyard/history.json", cfg.HistoryPathFor(""))
+	assert.Equal(t, ".shipyard/history.json", cfg.HistoryPathFor(StableChannel))
+	assert.Equal(t, ".shipyard/history.beta.json", cfg.HistoryPathFor("beta"))
+
+	cfg.History.Path = "releases"
+	assert.Equal(t, "releases.beta", cfg.HistoryPathFor("beta"))
+}
+
+func TestConfig_MergeChannels(t *testing.T) {
+	base := &Config{Channels: []ChannelConfig{{Name: "beta", Branches: []string{"main"}}}}
+
+	merged := base.Merge(&Config{History: HistoryConfig{Keep: 10}})
+	assert.Equal(t, base.Channels, merged.Channels)
+
+	merged = base.Merge(&Config{Channels: []ChannelConfig{{Name: "rc"}}})
+	assert.Equal(t, []ChannelConfig{{Name: "rc"}}, merged.Channels)
+
+	defaults := base.WithDefaults()
+	defaults.Channels[0].Branches[0] = "next"
+	assert.Equal(t, "main", base.Channels[0].Branches[0], "WithDefaults copies channels")
+}
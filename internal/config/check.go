@@ -0,0 +1,91 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// CheckConfig configures `shipyard check`, the CI gate that requires a
+// consignment for every package a change touches
+type CheckConfig struct {
+	// Ignore lists path globs that never require a consignment, e.g.
+	// docs/** or *_test.go. A pattern without a slash matches the file name
+	// in any directory; ** matches any number of directories.
+	Ignore []string `yaml:"ignore,omitempty"`
+}
+
+// validate checks the ignore patterns
+func (c *CheckConfig) validate() error {
+	for _, pattern := range c.Ignore {
+		for _, elem := range strings.Split(pattern, "/") {
+			if _, err := path.Match(elem, ""); err != nil {
+				return fmt.Errorf("invalid check.ignore pattern %q: %w", pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Ignores reports whether file, a slash-separated path relative to the
+// project root, matches an ignore pattern
+func (c *CheckConfig) Ignores(file string) bool {
+	for _, pattern := range c.Ignore {
+		if matchPathGlob(pattern, file) {
+			return true
+		}
+	}
+	return false
+}
+
+// PackageForPath returns the package whose path most specifically contains
+// file, a slash-separated path relative to the project root, so a root
+// package does not claim files of packages nested inside it
+func (c *Config) PackageForPath(file string) (string, bool) {
+	owner, longest := "", -1
+	for _, pkg := range c.Packages {
+		dir := strings.TrimPrefix(cleanPackagePath(pkg.Path), "./")
+		if dir == "." {
+			dir = ""
+		}
+		if dir != "" && file != dir && !strings.HasPrefix(file, dir+"/") {
+			continue
+		}
+		if len(dir) > longest {
+			owner, longest = pkg.Name, len(dir)
+		}
+	}
+	return owner, longest >= 0
+}
+
+// matchPathGlob matches a slash-separated path against a glob. Patterns
+// without a slash match the last path element; otherwise the pattern is
+// matched element by element, with ** standing for zero or more elements.
+func matchPathGlob(pattern, name string) bool {
+	pattern = strings.TrimPrefix(pattern, "./")
+	if !strings.Contains(pattern, "/") {
+		matched, _ := path.Match(pattern, path.Base(name))
+		return matched
+	}
+	return matchElems(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+// matchElems matches path elements against pattern elements
+func matchElems(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(name); i++ {
+			if matchElems(pattern[1:], name[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(name) == 0 {
+		return false
+	}
+	matched, _ := path.Match(pattern[0], name[0])
+	return matched && matchElems(pattern[1:], name[1:])
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_PackageForPath(t *testing.T) {
+	cfg := &Config{Packages: []Package{
+		{Name: "root", Path: "."},
+		{Name: "web", Path: "./apps/web"},
+		{Name: "webkit", Path: "apps/webkit/"},
+	}}
+	tests := []struct {
+		file  string
+		owner string
+	}{
+		{file: "README.md", owner: "root"},
+		{file: "apps/web/index.js", owner: "web"},
+		{file: "apps/webkit/index.js", owner: "webkit"},
+		{file: "apps/website.md", owner: "root"},
+	}
+	for _, tt := range tests {
+		owner, ok := cfg.PackageForPath(tt.file)
+		assert.True(t, ok, tt.file)
+		assert.Equal(t, tt.owner, owner, tt.file)
+	}
+
+	cfg.Packages = cfg.Packages[1:]
+	_, ok := cfg.PackageForPath("README.md")
+	assert.False(t, ok)
+}
+
+func TestCheckConfig_Ignores(t *testing.T) {
+	check := CheckConfig{Ignore: []string{"docs/**", "*_test.go", "apps/*/fixtures/**", "./CONTRIBUTING.md"}}
+	tests := []struct {
+		file    string
+		ignored bool
+	}{
+		{file: "docs/index.md", ignored: true},
+		{file: "docs/guides/setup.md", ignored: true},
+		{file: "core/docs/index.md", ignored: false},
+		{file: "core/parse_test.go", ignored: true},
+		{file: "parse_test.go", ignored: true},
+		{file: "core/parse.go", ignored: false},
+		{file: "apps/web/fixtures/a/b.json", ignored: true},
+		{file: "apps/web/src/b.json", ignored: false},
+		{file: "CONTRIBUTING.md", ignored: true},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.ignored, check.Ignores(tt.file), tt.file)
+	}
+	assert.False(t, (&CheckConfig{}).Ignores("README.md"))
+}
+
+func TestCheckConfig_Validate(t *testing.T) {
+	base := func(ignore ...string) *Config {
+		return &Config{Packages: []Package{{Name: "core", Path: "./"}}, Check: CheckConfig{Ignore: ignore}}
+	}
+
+	assert.NoError(t, base("docs/**", "*.md").Validate())
+	assert.ErrorContains(t, base("docs/[").Validate(), `invalid check.ignore pattern "docs/["`)
+
+	merged := base("docs/**").Merge(&Config{Check: CheckConfig{Ignore: []string{"*.md"}}})
+	assert.Equal(t, []string{"*.md"}, merged.Check.Ignore)
+	assert.Equal(t, []string{"docs/**"}, base("docs/**").Merge(&Config{}).Check.Ignore)
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/NatoNathan/shipyard/pkg/semver"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// toolVersion is the version of the running binary; main sets it from the
+// build, and anything that is not a version (such as "dev") is treated as
+// newer than every release
+var toolVersion = "dev"
+
+// SetToolVersion sets the running binary's version for config compatibility checks
+func SetToolVersion(version string) {
+	toolVersion = version
+}
+
+// ToolVersionRequirement returns the minShipyardVersion new configs are written
+// with: the running release with its patch number zeroed, so patch releases of
+// the same line can read them. Dev builds return "" and write no requirement.
+func ToolVersionRequirement() string {
+	v, err := semver.Parse(toolVersion)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%d.%d.0", v.Major, v.Minor)
+}
+
+// IncompatibleConfigError reports a config written for a newer shipyard
+type IncompatibleConfigError struct {
+	Required    string   // The config's minShipyardVersion
+	Running     string   // The running binary's version
+	UnknownKeys []string // Top-level keys this binary does not recognize
+}
+
+func (e *IncompatibleConfigError) Error() string {
+	msg := fmt.Sprintf("config requires shipyard %s or newer, but this is shipyard %s; run `shipyard upgrade` to update",
+		e.Required, e.Running)
+	if len(e.UnknownKeys) > 0 {
+		msg += fmt.Sprintf(" (unrecognized top-level keys: %s)", strings.Join(e.UnknownKeys, ", "))
+	}
+	return msg
+}
+
+// checkCompatibility rejects a config whose minShipyardVersion is newer than
+// the running binary. keys are the config's top-level keys; those this
+// binary does not know are listed in the error to explain what would break.
+func checkCompatibility(required string, keys []string) error {
+	if required == "" {
+		return nil
+	}
+	want, err := semver.Parse(required)
+	if err != nil {
+		return fmt.Errorf("invalid minShipyardVersion %q: %w", required, err)
+	}
+	running, err := semver.Parse(toolVersion)
+	if err != nil || running.Compare(want) >= 0 {
+		return nil
+	}
+	return &IncompatibleConfigError{
+		Required:    required,
+		Running:     running.String(),
+		UnknownKeys: unknownTopLevelKeys(keys),
+	}
+}
+
+// topLevelKeys returns the top-level keys of the config viper read. YAML and
+// JSON files are read directly to keep the keys' casing and empty sections,
+// which viper drops; other formats fall back to viper's lowercased keys.
+func topLevelKeys(v *viper.Viper) []string {
+	path := v.ConfigFileUsed()
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json":
+		data, err := os.ReadFile(path)
+		if err != nil {
+			break
+		}
+		var raw map[string]interface{}
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			break
+		}
+		keys := make([]string, 0, len(raw))
+		for key := range raw {
+			keys = append(keys, key)
+		}
+		return keys
+	}
+	return v.AllKeys()
+}
+
+// unknownTopLevelKeys returns the keys that are not Config fields, sorted.
+// Keys are compared case-insensitively because viper lowercases them.
+func unknownTopLevelKeys(keys []string) []string {
+	known := make(map[string]bool)
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		known[strings.ToLower(name)] = true
+	}
+
+	seen := make(map[string]bool)
+	var unknown []string
+	for _, key := range keys {
+		top, _, _ := strings.Cut(key, ".")
+		if !known[strings.ToLower(top)] && !seen[top] {
+			seen[top] = true
+			unknown = append(unknown, top)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// useToolVersion sets the running binary's version for the rest of the test
+func useToolVersion(t *testing.T, version string) {
+	t.Helper()
+	original := toolVersion
+	SetToolVersion(version)
+	t.Cleanup(func() { SetToolVersion(original) })
+}
+
+// writeProjectConfig writes .shipyard/shipyard.yaml with one package at the root
+func writeProjectConfig(t *testing.T, extra string) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".shipyard"), 0755))
+	content := extra + `packages:
+  - name: core
+    path: ./
+    ecosystem: go
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".shipyard", "shipyard.yaml"), []byte(content), 0644))
+	return dir
+}
+
+func TestLoadFromDir_MinShipyardVersion(t *testing.T) {
+	t.Run("older binary is rejected", func(t *testing.T) {
+		useToolVersion(t, "0.5.2")
+		dir := writeProjectConfig(t, "minShipyardVersion: 0.9.0\nreleaseTrains:\n  weekly: true\nsigning: {}\n")
+
+		for name, load := range map[string]func() (*Config, error){
+			"LoadFromDir": func() (*Config, error) { return LoadFromDir(dir) },
+			"Load":        func() (*Config, error) { return Load(filepath.Join(dir, ".shipyard", "shipyard.yaml")) },
+		} {
+			_, err := load()
+			var compatErr *IncompatibleConfigError
+			require.True(t, errors.As(err, &compatErr), "%s: %v", name, err)
+			assert.Equal(t, "0.9.0", compatErr.Required)
+			assert.Equal(t, "0.5.2", compatErr.Running)
+			assert.Equal(t, []string{"releaseTrains", "signing"}, compatErr.UnknownKeys)
+			assert.Contains(t, err.Error(), "requires shipyard 0.9.0 or newer, but this is shipyard 0.5.2")
+			assert.Contains(t, err.Error(), "shipyard upgrade")
+			assert.Contains(t, err.Error(), "releaseTrains, signing")
+		}
+	})
+
+	t.Run("same or newer binary is accepted", func(t *testing.T) {
+		for _, version := range []string{"0.9.0", "v0.9.3", "1.0.0"} {
+			useToolVersion(t, version)
+			dir := writeProjectConfig(t, "minShipyardVersion: 0.9.0\n")
+			cfg, err := LoadFromDir(dir)
+			require.NoError(t, err, version)
+			assert.Equal(t, "0.9.0", cfg.MinShipyardVersion)
+		}
+	})
+
+	t.Run("absent field", func(t *testing.T) {
+		useToolVersion(t, "0.1.0")
+		dir := writeProjectConfig(t, "")
+		cfg, err := LoadFromDir(dir)
+		require.NoError(t, err)
+		assert.Empty(t, cfg.MinShipyardVersion)
+	})
+
+	t.Run("dev build is treated as newest", func(t *testing.T) {
+		useToolVersion(t, "dev")
+		dir := writeProjectConfig(t, "minShipyardVersion: 99.0.0\n")
+		_, err := LoadFromDir(dir)
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid requirement", func(t *testing.T) {
+		useToolVersion(t, "1.0.0")
+		dir := writeProjectConfig(t, "minShipyardVersion: soon\n")
+		_, err := LoadFromDir(dir)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `invalid minShipyardVersion "soon"`)
+	})
+}
+
+func TestToolVersionRequirement(t *testing.T) {
+	useToolVersion(t, "v0.9.3")
+	assert.Equal(t, "0.9.0", ToolVersionRequirement())
+
+	useToolVersion(t, "1.2.0-rc.1")
+	assert.Equal(t, "1.2.0", ToolVersionRequirement())
+
+	useToolVersion(t, "dev")
+	assert.Empty(t, ToolVersionRequirement())
+}
+
+func TestMerge_MinShipyardVersion(t *testing.T) {
+	base := &Config{MinShipyardVersion: "0.8.0"}
+	assert.Equal(t, "0.8.0", base.Merge(&Config{}).MinShipyardVersion)
+	assert.Equal(t, "0.9.0", base.Merge(&Config{MinShipyardVersion: "0.9.0"}).MinShipyardVersion)
+}
@@ -2,8 +2,21 @@ package config
 
 import (
 	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/consignment"
+	"github.com/NatoNathan/shipyard/internal/history"
+	"github.com/NatoNathan/shipyard/internal/httpclient"
+	"github.com/NatoNathan/shipyard/internal/rules"
+	"github.com/NatoNathan/shipyard/internal/schedule"
+	"github.com/NatoNathan/shipyard/internal/template"
+	"github.com/NatoNathan/shipyard/pkg/semver"
+	"github.com/NatoNathan/shipyard/pkg/types"
 )
 
 // Ecosystem types
@@ -14,18 +27,75 @@ const (
 	EcosystemHelm   = "helm"
 	EcosystemCargo  = "cargo"
 	EcosystemDeno   = "deno"
+	EcosystemDotnet = "dotnet"
+	EcosystemMaven  = "maven"
 )
 
+// Ecosystems returns every supported ecosystem name
+func Ecosystems() []string {
+	return []string{EcosystemGo, EcosystemNPM, EcosystemPython, EcosystemHelm, EcosystemCargo, EcosystemDeno, EcosystemDotnet, EcosystemMaven}
+}
+
 // Config represents the project-specific settings
 type Config struct {
-	Extends      []RemoteConfig    `yaml:"extends,omitempty"`
-	Packages     []Package         `yaml:"packages"`
-	Templates    TemplateConfig    `yaml:"templates,omitempty"`
-	Metadata     MetadataConfig    `yaml:"metadata,omitempty"`
-	Consignments ConsignmentConfig `yaml:"consignments,omitempty"`
-	History      HistoryConfig     `yaml:"history,omitempty"`
-	GitHub       GitHubConfig      `yaml:"github,omitempty"`
-	PreRelease   PreReleaseConfig  `yaml:"prerelease,omitempty"`
+	// SchemaVersion is the config shape the file is written in; older
+	// shapes are migrated when loaded (see CurrentSchemaVersion)
+	SchemaVersion int `yaml:"schemaVersion,omitempty"`
+
+	// MinShipyardVersion is the oldest shipyard that can read this config;
+	// older binaries refuse it instead of misreading newer settings
+	MinShipyardVersion string `yaml:"minShipyardVersion,omitempty"`
+
+	Extends         []RemoteConfig     `yaml:"extends,omitempty"`
+	Packages        []Package          `yaml:"packages"`
+	Templates       TemplateConfig     `yaml:"templates,omitempty"`
+	Changelog       ChangelogConfig    `yaml:"changelog,omitempty"`
+	ChangeTypes     []ChangeTypeConfig `yaml:"changeTypes,omitempty"`
+	Metadata        MetadataConfig     `yaml:"metadata,omitempty"`
+	Consignments    ConsignmentConfig  `yaml:"consignments,omitempty"`
+	History         HistoryConfig      `yaml:"history,omitempty"`
+	GitHub          GitHubConfig       `yaml:"github,omitempty"`
+	Git             GitConfig          `yaml:"git,omitempty"`
+	PreRelease      PreReleaseConfig   `yaml:"prerelease,omitempty"`
+	ReleaseSchedule *ScheduleConfig    `yaml:"releaseSchedule,omitempty"`
+	Remote          RemoteSettings     `yaml:"remote,omitempty"`
+	Rules           map[string]string  `yaml:"rules,omitempty"` // Rule ID -> level (off, warn, error)
+
+	// Interpolation controls ${VAR} expansion in config values
+	Interpolation InterpolationConfig `yaml:"interpolation,omitempty"`
+
+	// Hooks run for every released package, before the package's own hooks
+	Hooks HooksConfig `yaml:"hooks,omitempty"`
+
+	// Channels are release streams with separate histories, e.g. beta and stable
+	Channels []ChannelConfig `yaml:"channels,omitempty"`
+
+	// Check configures the consignment gate run by `shipyard check`
+	Check CheckConfig `yaml:"check,omitempty"`
+
+	// DependencyUpdates configures the consignments `shipyard add --deps` logs
+	DependencyUpdates DependencyUpdatesConfig `yaml:"dependencyUpdates,omitempty"`
+}
+
+// HooksConfig lists shell commands run for each released package, in the
+// package's directory
+type HooksConfig struct {
+	PreVersion  []string `yaml:"preVersion,omitempty"`  // Before any file changes
+	PostVersion []string `yaml:"postVersion,omitempty"` // After the release files are written, before the release commit
+}
+
+// HooksFor returns the hooks that run for a package: the global hooks, then
+// the package's own
+func (c *Config) HooksFor(pkg Package) HooksConfig {
+	hooks := HooksConfig{
+		PreVersion:  slices.Clone(c.Hooks.PreVersion),
+		PostVersion: slices.Clone(c.Hooks.PostVersion),
+	}
+	if pkg.Hooks != nil {
+		hooks.PreVersion = append(hooks.PreVersion, pkg.Hooks.PreVersion...)
+		hooks.PostVersion = append(hooks.PostVersion, pkg.Hooks.PostVersion...)
+	}
+	return hooks
 }
 
 // PreReleaseConfig holds pre-release stage definitions and snapshot template
@@ -125,6 +195,11 @@ type RemoteConfig struct {
 	Path string `yaml:"path,omitempty"`
 	Ref  string `yaml:"ref,omitempty"`
 	Auth string `yaml:"auth,omitempty"`
+
+	// Retries is how many times a failed fetch is retried; nil means
+	// SHIPYARD_FETCH_RETRIES, or 3
+	Retries *int   `yaml:"retries,omitempty"`
+	Timeout string `yaml:"timeout,omitempty"` // Per-attempt fetch timeout, e.g. "45s"
 }
 
 // TemplateConfig holds template definitions
@@ -133,6 +208,215 @@ type TemplateConfig struct {
 	TagName       *TemplateSource `yaml:"tagName,omitempty"`
 	ReleaseNotes  *TemplateSource `yaml:"releaseNotes,omitempty"`
 	CommitMessage *TemplateSource `yaml:"commitMessage,omitempty"`
+	AllowHTML     *bool           `yaml:"allowHtml,omitempty"` // Render HTML in consignment summaries unescaped; nil means escaped
+
+	// MaxMessageBytes caps the size of rendered commit messages and tag
+	// annotations. Consignment lists are truncated to fit; zero means no limit.
+	MaxMessageBytes int `yaml:"maxMessageBytes,omitempty"`
+}
+
+// HTMLAllowed reports whether HTML in consignment summaries is rendered unescaped
+func (t TemplateConfig) HTMLAllowed() bool {
+	return t.AllowHTML != nil && *t.AllowHTML
+}
+
+// DefaultChangelogPlaceholder is rendered for releases whose changes are all excluded
+const DefaultChangelogPlaceholder = "Internal changes only"
+
+// DefaultChangelogNotesHeading titles the block of history notes rendered per version
+const DefaultChangelogNotesHeading = "Notes"
+
+// ChangelogConfig controls which changes appear in rendered changelogs and release notes
+type ChangelogConfig struct {
+	ExcludeTypes []string `yaml:"excludeTypes,omitempty"` // Change types omitted from rendered output (still versioned and archived)
+	Placeholder  string   `yaml:"placeholder,omitempty"`  // Line shown when every change in a release is excluded
+
+	// RequiredMetadata lists metadata keys (e.g. issue, pr) that `shipyard add`
+	// requires on every consignment so changelog entries can link back to them
+	RequiredMetadata []string `yaml:"requiredMetadata,omitempty"`
+
+	// Notes renders notes added with `shipyard history annotate` under each
+	// version, titled NotesHeading. Off by default.
+	Notes        bool   `yaml:"notes,omitempty"`
+	NotesHeading string `yaml:"notesHeading,omitempty"`
+
+	// SectionOrder lists changelog sections, by title or change type, to
+	// render first; the rest follow in their usual order
+	SectionOrder []string `yaml:"sectionOrder,omitempty"`
+}
+
+// ChangelogFor returns the effective changelog settings for a package.
+// Package-level excludeTypes, requiredMetadata and sectionOrder lists replace the project
+// lists; an empty placeholder or notes heading falls back to the project
+// value, then the default. Notes render when enabled at either level.
+func (c *Config) ChangelogFor(packageName string) ChangelogConfig {
+	result := ChangelogConfig{
+		ExcludeTypes:     c.Changelog.ExcludeTypes,
+		Placeholder:      c.Changelog.Placeholder,
+		RequiredMetadata: c.Changelog.RequiredMetadata,
+		Notes:            c.Changelog.Notes,
+		NotesHeading:     c.Changelog.NotesHeading,
+		SectionOrder:     c.Changelog.SectionOrder,
+	}
+	if pkg, ok := c.GetPackage(packageName); ok && pkg.Changelog != nil {
+		if pkg.Changelog.ExcludeTypes != nil {
+			result.ExcludeTypes = pkg.Changelog.ExcludeTypes
+		}
+		if pkg.Changelog.RequiredMetadata != nil {
+			result.RequiredMetadata = pkg.Changelog.RequiredMetadata
+		}
+		if pkg.Changelog.Placeholder != "" {
+			result.Placeholder = pkg.Changelog.Placeholder
+		}
+		if pkg.Changelog.Notes {
+			result.Notes = true
+		}
+		if pkg.Changelog.NotesHeading != "" {
+			result.NotesHeading = pkg.Changelog.NotesHeading
+		}
+		if pkg.Changelog.SectionOrder != nil {
+			result.SectionOrder = pkg.Changelog.SectionOrder
+		}
+	}
+	if result.Placeholder == "" {
+		result.Placeholder = DefaultChangelogPlaceholder
+	}
+	if result.NotesHeading == "" {
+		result.NotesHeading = DefaultChangelogNotesHeading
+	}
+	return result
+}
+
+// RequiredMetadataFor returns the metadata keys required by any of the given
+// packages, in first-seen order
+func (c *Config) RequiredMetadataFor(packageNames []string) []string {
+	var keys []string
+	seen := make(map[string]bool)
+	for _, name := range packageNames {
+		for _, key := range c.ChangelogFor(name).RequiredMetadata {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
+// validate checks that every excluded change type is known and every
+// required metadata key is named
+func (c *ChangelogConfig) validate() error {
+	for _, t := range c.ExcludeTypes {
+		if err := types.ChangeType(t).Validate(); err != nil {
+			return fmt.Errorf("changelog.excludeTypes: %w", err)
+		}
+	}
+	for _, key := range c.RequiredMetadata {
+		if strings.TrimSpace(key) == "" {
+			return fmt.Errorf("changelog.requiredMetadata: key cannot be empty")
+		}
+	}
+	return nil
+}
+
+// ChangeTypeConfig configures how a change type is presented in release notes
+type ChangeTypeConfig struct {
+	Name     string `yaml:"name"`               // patch, minor, or major
+	Audience string `yaml:"audience,omitempty"` // user (default) or internal
+	Section  string `yaml:"section,omitempty"`  // changelog heading; the template's own when empty
+}
+
+// ChangelogSections returns the changelog sections of a package, in order.
+// Change types configured with a section come first, in declaration order,
+// sharing a section when they name the same one; the other change types
+// follow in the default order under the template's headings. The package's
+// changelog.sectionOrder then moves the sections it names to the front.
+func (c *Config) ChangelogSections(packageName string) []history.Section {
+	sections, _ := orderSections(c.changeTypeSections(), c.ChangelogFor(packageName).SectionOrder)
+	return sections
+}
+
+// changeTypeSections returns the sections of the configured change types
+// followed by the default sections of the rest
+func (c *Config) changeTypeSections() []history.Section {
+	var sections []history.Section
+	covered := make(map[string]bool)
+	for _, ct := range c.ChangeTypes {
+		if ct.Section == "" {
+			continue
+		}
+		covered[ct.Name] = true
+		i := slices.IndexFunc(sections, func(s history.Section) bool { return s.Title == ct.Section })
+		if i == -1 {
+			sections = append(sections, history.Section{Title: ct.Section})
+			i = len(sections) - 1
+		}
+		sections[i].ChangeTypes = append(sections[i].ChangeTypes, ct.Name)
+	}
+	for _, section := range history.DefaultSections {
+		if !covered[section.ChangeTypes[0]] {
+			sections = append(sections, history.Section{ChangeTypes: slices.Clone(section.ChangeTypes)})
+		}
+	}
+	return sections
+}
+
+// orderSections moves the sections named in order, by title or by one of
+// their change types, to the front; the rest keep their relative order
+func orderSections(sections []history.Section, order []string) ([]history.Section, error) {
+	ordered := make([]history.Section, 0, len(sections))
+	rest := slices.Clone(sections)
+	for _, name := range order {
+		i := slices.IndexFunc(rest, func(s history.Section) bool {
+			return s.Title == name || slices.Contains(s.ChangeTypes, name)
+		})
+		if i == -1 {
+			if slices.ContainsFunc(ordered, func(s history.Section) bool {
+				return s.Title == name || slices.Contains(s.ChangeTypes, name)
+			}) {
+				continue
+			}
+			return sections, fmt.Errorf("changelog.sectionOrder: unknown section %q", name)
+		}
+		ordered = append(ordered, rest[i])
+		rest = slices.Delete(rest, i, i+1)
+	}
+	return append(ordered, rest...), nil
+}
+
+// ChangeTypeAudiences maps each configured change type to its audience, for
+// audience-grouped release notes. Unlisted types are user-facing.
+func (c *Config) ChangeTypeAudiences() map[string]string {
+	if len(c.ChangeTypes) == 0 {
+		return nil
+	}
+	audiences := make(map[string]string, len(c.ChangeTypes))
+	for _, ct := range c.ChangeTypes {
+		if ct.Audience != "" {
+			audiences[ct.Name] = ct.Audience
+		}
+	}
+	return audiences
+}
+
+// validateChangeTypes checks change type names and audiences
+func (c *Config) validateChangeTypes() error {
+	seen := make(map[string]bool)
+	for _, ct := range c.ChangeTypes {
+		if err := types.ChangeType(ct.Name).Validate(); err != nil {
+			return fmt.Errorf("changeTypes: %w", err)
+		}
+		if seen[ct.Name] {
+			return fmt.Errorf("changeTypes: duplicate change type %s", ct.Name)
+		}
+		seen[ct.Name] = true
+		if ct.Audience != "" {
+			if err := history.ValidateAudience(ct.Audience); err != nil {
+				return fmt.Errorf("changeTypes.%s: %w", ct.Name, err)
+			}
+		}
+	}
+	return nil
 }
 
 // TemplateSource represents a template source
@@ -174,12 +458,158 @@ type MetadataField struct {
 
 // ConsignmentConfig holds consignment storage settings
 type ConsignmentConfig struct {
-	Path string `yaml:"path,omitempty"`
+	Path    string `yaml:"path,omitempty"`
+	IDStyle string `yaml:"idStyle,omitempty"` // How new consignments are named: "timestamp" (default), "slug" or "summary-slug"
 }
 
 // HistoryConfig holds history file settings
 type HistoryConfig struct {
-	Path string `yaml:"path,omitempty"`
+	Path        string `yaml:"path,omitempty"`
+	EmbedConfig bool   `yaml:"embedConfig,omitempty"` // Embed the full resolved config in each entry
+	LockTimeout string `yaml:"lockTimeout,omitempty"` // How long to wait for the history lock, e.g. "30s"
+	Keep        int    `yaml:"keep,omitempty"`        // Entries history compact keeps in the main file
+}
+
+// LockTimeoutDuration parses LockTimeout. Zero means the default timeout.
+func (h HistoryConfig) LockTimeoutDuration() (time.Duration, error) {
+	if h.LockTimeout == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(h.LockTimeout)
+	if err != nil {
+		return 0, fmt.Errorf("history.lockTimeout: %w", err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("history.lockTimeout must be positive")
+	}
+	return d, nil
+}
+
+// ScheduleConfig holds the release schedule. A release window opens each
+// time the cron expression fires and stays open for GraceHours.
+type ScheduleConfig struct {
+	Cron       string `yaml:"cron"`
+	Timezone   string `yaml:"timezone,omitempty"`   // IANA zone name, default UTC
+	GraceHours int    `yaml:"graceHours,omitempty"` // Window length in hours, default 24
+	Enforce    bool   `yaml:"enforce,omitempty"`    // Make version refuse to run outside a window by default
+}
+
+// Build parses the schedule
+func (s *ScheduleConfig) Build() (*schedule.Schedule, error) {
+	if s.Cron == "" {
+		return nil, fmt.Errorf("releaseSchedule.cron is required")
+	}
+	if s.GraceHours < 0 {
+		return nil, fmt.Errorf("releaseSchedule.graceHours must not be negative")
+	}
+	sched, err := schedule.New(s.Cron, s.Timezone, time.Duration(s.GraceHours)*time.Hour)
+	if err != nil {
+		return nil, fmt.Errorf("releaseSchedule: %w", err)
+	}
+	return sched, nil
+}
+
+// RemoteSettings holds settings for fetching remote templates and configs
+type RemoteSettings struct {
+	Auth     []RemoteAuth `yaml:"auth,omitempty"`
+	CacheTTL string       `yaml:"cacheTTL,omitempty"` // How long downloads are reused before revalidating, e.g. "6h"
+	CABundle string       `yaml:"caBundle,omitempty"` // PEM file of extra certificate authorities, relative to the project root or absolute
+
+	// InsecureSkipVerify disables TLS certificate verification for remote
+	// fetches. It is an escape hatch for broken proxies and warns on use.
+	InsecureSkipVerify bool `yaml:"insecureSkipVerify,omitempty"`
+}
+
+// TLSOptions returns the certificate settings for remote fetches, with
+// CABundle resolved against projectRoot
+func (r RemoteSettings) TLSOptions(projectRoot string) httpclient.TLSOptions {
+	bundle := r.CABundle
+	if bundle != "" && !filepath.IsAbs(bundle) {
+		bundle = filepath.Join(projectRoot, bundle)
+	}
+	return httpclient.TLSOptions{CABundle: bundle, InsecureSkipVerify: r.InsecureSkipVerify}
+}
+
+// CacheTTLDuration parses CacheTTL. Zero means the default TTL.
+func (r RemoteSettings) CacheTTLDuration() (time.Duration, error) {
+	if r.CacheTTL == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(r.CacheTTL)
+	if err != nil {
+		return 0, fmt.Errorf("remote.cacheTTL: %w", err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("remote.cacheTTL must be positive")
+	}
+	return d, nil
+}
+
+// Options returns the settings remote fetches run with: the cache TTL, the
+// remote.auth credentials resolved through getenv, and the certificate
+// settings with CABundle resolved against projectRoot. An invalid cacheTTL
+// keeps the default; Validate reports it.
+func (r RemoteSettings) Options(projectRoot string, getenv func(string) string) template.RemoteOptions {
+	ttl, _ := r.CacheTTLDuration()
+	return template.RemoteOptions{
+		CacheTTL:    ttl,
+		Credentials: r.Credentials(getenv),
+		TLS:         r.TLSOptions(projectRoot),
+	}
+}
+
+// RemoteAuth maps a host to the environment variable holding its token
+type RemoteAuth struct {
+	Host        string `yaml:"host"`
+	TokenEnv    string `yaml:"tokenEnv"`
+	Type        string `yaml:"type,omitempty"`        // "bearer" (default) or "basic"
+	UsernameEnv string `yaml:"usernameEnv,omitempty"` // Basic auth username, default "token"
+}
+
+// Validate checks a remote.auth entry
+func (a RemoteAuth) Validate() error {
+	if a.Host == "" {
+		return fmt.Errorf("remote.auth: host is required")
+	}
+	if strings.Contains(a.Host, "/") {
+		return fmt.Errorf("remote.auth: host %q must not include a scheme or path", a.Host)
+	}
+	if a.TokenEnv == "" {
+		return fmt.Errorf("remote.auth: tokenEnv is required for host %s", a.Host)
+	}
+	switch a.Type {
+	case "", "bearer", "basic":
+	default:
+		return fmt.Errorf("remote.auth: invalid type %q for host %s (must be bearer or basic)", a.Type, a.Host)
+	}
+	if a.UsernameEnv != "" && a.Type != "basic" {
+		return fmt.Errorf("remote.auth: usernameEnv requires type basic for host %s", a.Host)
+	}
+	return nil
+}
+
+// Credentials resolves remote.auth entries from the environment. Hosts whose
+// token variable is unset are left out.
+func (r RemoteSettings) Credentials(getenv func(string) string) map[string]*template.Credential {
+	credentials := make(map[string]*template.Credential)
+	for _, auth := range r.Auth {
+		token := getenv(auth.TokenEnv)
+		if token == "" {
+			continue
+		}
+		cred := &template.Credential{Basic: auth.Type == "basic", Token: token}
+		if auth.UsernameEnv != "" {
+			cred.Username = getenv(auth.UsernameEnv)
+		}
+		credentials[auth.Host] = cred
+	}
+	return credentials
+}
+
+// GitConfig holds settings for pushing releases
+type GitConfig struct {
+	Remote string `yaml:"remote,omitempty"` // Remote to push to (default "origin")
+	SSHKey string `yaml:"sshKey,omitempty"` // Private key for SSH remotes, relative to the project root or absolute
 }
 
 // GitHubConfig holds GitHub integration settings
@@ -189,22 +619,96 @@ type GitHubConfig struct {
 	Token string `yaml:"token,omitempty"` // Format: "env:VAR_NAME"
 }
 
-// Package represents a versionable package
+// Package represents a versionable package. A Path containing glob
+// characters (e.g. "packages/*") declares one package per matching directory;
+// see ExpandPackageGlobs.
 type Package struct {
 	Name         string                 `yaml:"name"`
 	Path         string                 `yaml:"path"`
-	Ecosystem    string                 `yaml:"ecosystem,omitempty"`
-	VersionFiles []string               `yaml:"versionFiles,omitempty"` // Use ["tag-only"] for tag-only mode
+	Ecosystem    string                 `yaml:"ecosystem,omitempty"`    // "auto" on a glob accepts any detected ecosystem
+	Exclude      []string               `yaml:"exclude,omitempty"`      // Paths or names a glob skips
+	VersionFiles []string               `yaml:"versionFiles,omitempty"` // ["tag-only"] is the older spelling of tagOnly
 	Dependencies []Dependency           `yaml:"dependencies,omitempty"`
 	Templates    *TemplateConfig        `yaml:"templates,omitempty"`
 	Options      map[string]interface{} `yaml:"options,omitempty"`
+	Publish      *PublishConfig         `yaml:"publish,omitempty"`
+	Changelog    *ChangelogConfig       `yaml:"changelog,omitempty"`
+
+	// ChangelogTemplate is shorthand for templates.changelog.source
+	ChangelogTemplate string `yaml:"changelogTemplate,omitempty"`
+
+	VersioningScheme string `yaml:"versioningScheme,omitempty"` // "semver" (default) or "calver"
+	CalVerFormat     string `yaml:"calverFormat,omitempty"`     // CalVer format, default "YYYY.0M.MICRO"
+
+	// Tag-only packages take their version from git tags alone; releases
+	// write no version files. InitialVersion is the version a package that
+	// was never tagged is taken to be at.
+	TagOnly        bool   `yaml:"tagOnly,omitempty"`
+	InitialVersion string `yaml:"initialVersion,omitempty"`
+
+	// Frozen packages accept consignments but are not versioned until
+	// unfrozen, or until a run passes --include-frozen
+	Frozen bool `yaml:"frozen,omitempty"`
+
+	// Hooks run for this package after the global hooks
+	Hooks *HooksConfig `yaml:"hooks,omitempty"`
+
+	// Aliases are former names of the package. History recorded under an
+	// alias counts as this package's; new entries use Name.
+	Aliases []string `yaml:"aliases,omitempty"`
+}
+
+// Versioning schemes
+const (
+	VersioningSemVer = "semver"
+	VersioningCalVer = "calver"
+)
+
+// IsCalVer returns true if this package uses calendar versioning
+func (p *Package) IsCalVer() bool {
+	return p.VersioningScheme == VersioningCalVer
+}
+
+// GetCalVerFormat returns the package's CalVer format, or the default
+func (p *Package) GetCalVerFormat() string {
+	if p.CalVerFormat == "" {
+		return semver.DefaultCalVerFormat
+	}
+	return p.CalVerFormat
+}
+
+// HistoryNames returns the names the package's history may be recorded
+// under: its name followed by its aliases
+func (p *Package) HistoryNames() []string {
+	return append([]string{p.Name}, p.Aliases...)
 }
 
-// IsTagOnly returns true if this package uses tag-only versioning (no file updates)
-// This is indicated by versionFiles containing "tag-only" keyword
+// ParseVersion parses a version string according to the package's versioning scheme
+func (p *Package) ParseVersion(s string) (semver.Version, error) {
+	if p.IsCalVer() {
+		return semver.ParseCalVer(p.GetCalVerFormat(), s)
+	}
+	return semver.Parse(s)
+}
+
+// PublishConfig configures post-release publishing for a package
+type PublishConfig struct {
+	Helm *HelmPublishConfig `yaml:"helm,omitempty"`
+}
+
+// HelmPublishConfig configures pushing a Helm chart to an OCI registry
+type HelmPublishConfig struct {
+	Registry    string `yaml:"registry"`              // e.g. oci://ghcr.io/acme/charts
+	UsernameEnv string `yaml:"usernameEnv,omitempty"` // Env var holding the registry username
+	PasswordEnv string `yaml:"passwordEnv,omitempty"` // Env var holding the registry password or token
+	PlainHTTP   bool   `yaml:"plainHttp,omitempty"`   // Use HTTP for local registries
+}
+
+// IsTagOnly returns true if this package uses tag-only versioning (no file
+// updates), set with tagOnly or the "tag-only" keyword in versionFiles
 func (p *Package) IsTagOnly() bool {
-	if len(p.VersionFiles) == 0 {
-		return false
+	if p.TagOnly {
+		return true
 	}
 	for _, vf := range p.VersionFiles {
 		if vf == "tag-only" {
@@ -214,9 +718,17 @@ func (p *Package) IsTagOnly() bool {
 	return false
 }
 
+// Strategies for a Helm chart's appVersion (options.appVersion)
+const (
+	HelmAppVersionFollow      = "follow"      // appVersion is set to the chart version
+	HelmAppVersionFixed       = "fixed"       // appVersion is left untouched
+	HelmAppVersionIndependent = "independent" // appVersion is set to the appDependency package's version
+)
+
 // HelmOptions contains Helm-specific package options
 type HelmOptions struct {
 	AppDependency string // Package name to use for appVersion
+	AppVersion    string // appVersion strategy: follow, fixed or independent
 }
 
 // GetHelmOptions extracts Helm-specific options from package options
@@ -226,16 +738,82 @@ func (p *Package) GetHelmOptions() *HelmOptions {
 	}
 
 	opts := &HelmOptions{}
-	if appDep, ok := p.Options["appDependency"].(string); ok {
+	if appDep, ok := p.option("appDependency").(string); ok {
 		opts.AppDependency = appDep
 	}
+	if appVersion, ok := p.option("appVersion").(string); ok {
+		opts.AppVersion = appVersion
+	}
 	return opts
 }
 
+// AppVersionStrategy returns how the chart's appVersion is updated. Unset,
+// it is independent when appDependency names a package and follows the chart
+// version otherwise.
+func (o *HelmOptions) AppVersionStrategy() string {
+	if o.AppVersion != "" {
+		return o.AppVersion
+	}
+	if o.AppDependency != "" {
+		return HelmAppVersionIndependent
+	}
+	return HelmAppVersionFollow
+}
+
+// Range strategies for sibling dependencies of npm packages
+// (options.dependencyRange)
+const (
+	NPMDependencyRangeExact = "exact" // "1.2.3"
+	NPMDependencyRangeCaret = "caret" // "^1.2.3"
+	NPMDependencyRangeTilde = "tilde" // "~1.2.3"
+)
+
+// NPMOptions contains npm-specific package options
+type NPMOptions struct {
+	DependencyRange string // Range written for released sibling packages; unset leaves ranges untouched
+}
+
+// GetNPMOptions extracts npm-specific options from package options
+func (p *Package) GetNPMOptions() *NPMOptions {
+	opts := &NPMOptions{}
+	if dependencyRange, ok := p.option("dependencyRange").(string); ok {
+		opts.DependencyRange = dependencyRange
+	}
+	return opts
+}
+
+// DotnetOptions contains .NET-specific package options
+type DotnetOptions struct {
+	Manifest string // csproj or props file holding <Version>, relative to the package path
+}
+
+// GetDotnetOptions extracts .NET-specific options from package options
+func (p *Package) GetDotnetOptions() *DotnetOptions {
+	opts := &DotnetOptions{}
+	if manifest, ok := p.option("manifest").(string); ok {
+		opts.Manifest = manifest
+	}
+	return opts
+}
+
+// option looks up a package option by key. Viper lowercases map keys when
+// loading YAML, so keys are matched case-insensitively.
+func (p *Package) option(key string) interface{} {
+	if value, ok := p.Options[key]; ok {
+		return value
+	}
+	for k, value := range p.Options {
+		if strings.EqualFold(k, key) {
+			return value
+		}
+	}
+	return nil
+}
+
 // Dependency represents a package dependency
 type Dependency struct {
 	Package     string            `yaml:"package"`
-	Strategy    string            `yaml:"strategy,omitempty"` // "fixed" or "linked"
+	Strategy    string            `yaml:"strategy,omitempty"` // "linked", "fixed" or "patch"
 	BumpMapping map[string]string `yaml:"bumpMapping,omitempty"`
 }
 
@@ -257,6 +835,83 @@ func (c *Config) Validate() error {
 		if err := pkg.Validate(); err != nil {
 			return fmt.Errorf("invalid package %s: %w", pkg.Name, err)
 		}
+		if pkg.Changelog != nil {
+			if err := pkg.Changelog.validate(); err != nil {
+				return fmt.Errorf("invalid package %s: %w", pkg.Name, err)
+			}
+		}
+	}
+
+	if err := c.validateAliases(); err != nil {
+		return err
+	}
+
+	if err := c.Changelog.validate(); err != nil {
+		return err
+	}
+
+	if err := c.validateChangeTypes(); err != nil {
+		return err
+	}
+
+	// Section orders name sections the change types define
+	if _, err := orderSections(c.changeTypeSections(), c.Changelog.SectionOrder); err != nil {
+		return err
+	}
+	for _, pkg := range c.Packages {
+		if pkg.Changelog == nil {
+			continue
+		}
+		if _, err := orderSections(c.changeTypeSections(), pkg.Changelog.SectionOrder); err != nil {
+			return fmt.Errorf("invalid package %s: %w", pkg.Name, err)
+		}
+	}
+
+	if err := c.validateChannels(); err != nil {
+		return err
+	}
+	if err := c.Check.validate(); err != nil {
+		return err
+	}
+	if err := c.DependencyUpdates.validate(); err != nil {
+		return err
+	}
+
+	switch c.Consignments.IDStyle {
+	case "", consignment.IDStyleTimestamp, consignment.IDStyleSlug, consignment.IDStyleSummarySlug:
+	default:
+		return fmt.Errorf("unknown consignments.idStyle %q (expected timestamp, slug or summary-slug)", c.Consignments.IDStyle)
+	}
+
+	if c.Templates.MaxMessageBytes < 0 {
+		return fmt.Errorf("templates.maxMessageBytes must not be negative")
+	}
+
+	if _, err := c.History.LockTimeoutDuration(); err != nil {
+		return err
+	}
+
+	if c.History.Keep < 0 {
+		return fmt.Errorf("history.keep must not be negative")
+	}
+
+	if c.ReleaseSchedule != nil {
+		if _, err := c.ReleaseSchedule.Build(); err != nil {
+			return err
+		}
+	}
+
+	for _, auth := range c.Remote.Auth {
+		if err := auth.Validate(); err != nil {
+			return err
+		}
+	}
+	if _, err := c.Remote.CacheTTLDuration(); err != nil {
+		return err
+	}
+
+	if err := rules.ValidateOverrides(c.Rules); err != nil {
+		return fmt.Errorf("invalid rules: %w", err)
 	}
 
 	// Validate package options (requires all packages to be known)
@@ -269,6 +924,27 @@ func (c *Config) Validate() error {
 	return nil
 }
 
+// validateAliases checks that every package alias names exactly one package,
+// so history recorded under it has a single owner
+func (c *Config) validateAliases() error {
+	owners := make(map[string]string)
+	for _, pkg := range c.Packages {
+		owners[pkg.Name] = pkg.Name
+	}
+	for _, pkg := range c.Packages {
+		for _, alias := range pkg.Aliases {
+			if owner, ok := owners[alias]; ok {
+				if owner == pkg.Name {
+					return fmt.Errorf("invalid package %s: alias %q is already the name of this package", pkg.Name, alias)
+				}
+				return fmt.Errorf("invalid package %s: alias %q is already used by package %s", pkg.Name, alias, owner)
+			}
+			owners[alias] = pkg.Name
+		}
+	}
+	return nil
+}
+
 // Validate checks if a package is valid
 func (p *Package) Validate() error {
 	if p.Name == "" {
@@ -277,6 +953,63 @@ func (p *Package) Validate() error {
 	if p.Path == "" {
 		return fmt.Errorf("package path is required")
 	}
+	if len(p.Exclude) > 0 && !p.IsGlob() {
+		return fmt.Errorf("exclude is only valid on package globs")
+	}
+	if len(p.Aliases) > 0 && p.IsGlob() {
+		return fmt.Errorf("aliases are not valid on package globs")
+	}
+	for _, alias := range p.Aliases {
+		if strings.TrimSpace(alias) == "" {
+			return fmt.Errorf("aliases must not be empty")
+		}
+	}
+	if p.ChangelogTemplate != "" && p.Templates != nil && p.Templates.Changelog != nil && p.Templates.Changelog.Source != "" {
+		return fmt.Errorf("set changelogTemplate or templates.changelog.source, not both")
+	}
+	if source := p.changelogTemplate(); source != "" {
+		if err := template.ValidateTemplate(source, template.TemplateTypeChangelog); err != nil {
+			return fmt.Errorf("invalid changelog template: %w", err)
+		}
+	}
+	switch p.VersioningScheme {
+	case "", VersioningSemVer:
+		if p.CalVerFormat != "" {
+			return fmt.Errorf("calverFormat requires versioningScheme: calver")
+		}
+	case VersioningCalVer:
+		if err := semver.ValidateCalVerFormat(p.GetCalVerFormat()); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown versioningScheme %q (expected semver or calver)", p.VersioningScheme)
+	}
+	for _, dep := range p.Dependencies {
+		switch dep.Strategy {
+		case "", "linked", "fixed", "patch":
+		default:
+			return fmt.Errorf("dependency %s has unknown strategy %q (expected linked, fixed or patch)", dep.Package, dep.Strategy)
+		}
+	}
+	for _, vf := range p.VersionFiles {
+		if vf == "tag-only" && len(p.VersionFiles) > 1 {
+			return fmt.Errorf("versionFiles: tag-only cannot be combined with other version files")
+		}
+		if vf != "tag-only" && !filepath.IsLocal(vf) {
+			return fmt.Errorf("versionFiles: %s must be relative to the package path", vf)
+		}
+	}
+	if p.TagOnly && len(p.VersionFiles) > 0 {
+		return fmt.Errorf("tagOnly cannot be combined with versionFiles")
+	}
+	if p.InitialVersion != "" {
+		if !p.IsTagOnly() {
+			return fmt.Errorf("initialVersion requires tagOnly")
+		}
+		if _, err := p.ParseVersion(p.InitialVersion); err != nil {
+			return fmt.Errorf("invalid initialVersion %q: %w", p.InitialVersion, err)
+		}
+	}
 	return nil
 }
 
@@ -285,6 +1018,19 @@ func (p *Package) Validate() error {
 func (p *Package) ValidateOptions(allPackages []Package) error {
 	if p.Ecosystem == EcosystemHelm {
 		helmOpts := p.GetHelmOptions()
+		switch helmOpts.AppVersion {
+		case "", HelmAppVersionFollow, HelmAppVersionFixed, HelmAppVersionIndependent:
+		default:
+			return fmt.Errorf("helm package %q has unknown appVersion strategy %q (expected follow, fixed or independent)",
+				p.Name, helmOpts.AppVersion)
+		}
+		if helmOpts.AppVersion == HelmAppVersionIndependent && helmOpts.AppDependency == "" {
+			return fmt.Errorf("helm package %q has appVersion independent but no appDependency to take it from", p.Name)
+		}
+		if helmOpts.AppDependency != "" && helmOpts.AppVersionStrategy() != HelmAppVersionIndependent {
+			return fmt.Errorf("helm package %q has appDependency %q, which only applies with appVersion independent",
+				p.Name, helmOpts.AppDependency)
+		}
 		if helmOpts.AppDependency != "" {
 			// Check that appDependency references a valid package
 			found := false
@@ -300,9 +1046,65 @@ func (p *Package) ValidateOptions(allPackages []Package) error {
 			}
 		}
 	}
+	if p.Ecosystem == EcosystemNPM {
+		switch dependencyRange := p.GetNPMOptions().DependencyRange; dependencyRange {
+		case "", NPMDependencyRangeExact, NPMDependencyRangeCaret, NPMDependencyRangeTilde:
+		default:
+			return fmt.Errorf("npm package %q has unknown dependencyRange %q (expected exact, caret or tilde)",
+				p.Name, dependencyRange)
+		}
+	}
+	if p.Publish != nil && p.Publish.Helm != nil {
+		if p.Ecosystem != "" && p.Ecosystem != EcosystemHelm {
+			return fmt.Errorf("package %q configures publish.helm but is not a helm package", p.Name)
+		}
+		if !strings.HasPrefix(p.Publish.Helm.Registry, "oci://") {
+			return fmt.Errorf("package %q publish.helm.registry must start with oci://", p.Name)
+		}
+	}
 	return nil
 }
 
+// changelogTemplate returns the package's own changelog template source, if any
+func (p *Package) changelogTemplate() string {
+	if p.ChangelogTemplate != "" {
+		return p.ChangelogTemplate
+	}
+	if p.Templates != nil && p.Templates.Changelog != nil {
+		return p.Templates.Changelog.Source
+	}
+	return ""
+}
+
+// ChangelogTemplateFor returns the changelog template source for a package:
+// its own override, else the project's templates.changelog, else the
+// "changelog" alias for the builtin default
+func (c *Config) ChangelogTemplateFor(packageName string) string {
+	if pkg, ok := c.GetPackage(packageName); ok {
+		if source := pkg.changelogTemplate(); source != "" {
+			return source
+		}
+	}
+	if c.Templates.Changelog != nil && c.Templates.Changelog.Source != "" {
+		return c.Templates.Changelog.Source
+	}
+	return "changelog"
+}
+
+// IsMonorepo reports whether the project configures more than one package
+func (c *Config) IsMonorepo() bool {
+	return len(c.Packages) > 1
+}
+
+// PackageEcosystems maps each configured package to its ecosystem
+func (c *Config) PackageEcosystems() map[string]string {
+	ecosystems := make(map[string]string, len(c.Packages))
+	for _, pkg := range c.Packages {
+		ecosystems[pkg.Name] = pkg.Ecosystem
+	}
+	return ecosystems
+}
+
 // GetPackage retrieves a package by name
 func (c *Config) GetPackage(name string) (Package, bool) {
 	for _, pkg := range c.Packages {
@@ -313,17 +1115,47 @@ func (c *Config) GetPackage(name string) (Package, bool) {
 	return Package{}, false
 }
 
+// CanonicalPackageName returns the current name of the package that name
+// refers to: the package whose alias it is, or name itself
+func (c *Config) CanonicalPackageName(name string) string {
+	for _, pkg := range c.Packages {
+		if slices.Contains(pkg.Aliases, name) {
+			return pkg.Name
+		}
+	}
+	return name
+}
+
 // Merge merges this config with another, with the overlay taking precedence
 func (c *Config) Merge(overlay *Config) *Config {
 	merged := &Config{
-		Packages:     append([]Package{}, c.Packages...),
-		Extends:      append([]RemoteConfig{}, c.Extends...),
-		Templates:    c.Templates,
-		Metadata:     c.Metadata,
-		Consignments: c.Consignments,
-		History:      c.History,
-		GitHub:       c.GitHub,
-		PreRelease:   c.PreRelease,
+		SchemaVersion:      c.SchemaVersion,
+		MinShipyardVersion: c.MinShipyardVersion,
+		Packages:           append([]Package{}, c.Packages...),
+		Extends:            append([]RemoteConfig{}, c.Extends...),
+		Templates:          c.Templates,
+		Changelog:          c.Changelog,
+		ChangeTypes:        c.ChangeTypes,
+		Metadata:           c.Metadata,
+		Consignments:       c.Consignments,
+		History:            c.History,
+		GitHub:             c.GitHub,
+		Git:                c.Git,
+		PreRelease:         c.PreRelease,
+		ReleaseSchedule:    c.ReleaseSchedule,
+		Rules:              copyStringMap(c.Rules),
+		Interpolation:      c.Interpolation,
+		Hooks:              c.Hooks,
+		Channels:           c.Channels,
+		Check:              c.Check,
+		DependencyUpdates:  c.DependencyUpdates,
+	}
+
+	if overlay.SchemaVersion != 0 {
+		merged.SchemaVersion = overlay.SchemaVersion
+	}
+	if overlay.MinShipyardVersion != "" {
+		merged.MinShipyardVersion = overlay.MinShipyardVersion
 	}
 
 	// Append overlay packages
@@ -334,23 +1166,74 @@ func (c *Config) Merge(overlay *Config) *Config {
 		merged.Extends = overlay.Extends
 	}
 	if overlay.Templates.Changelog != nil || overlay.Templates.TagName != nil || overlay.Templates.ReleaseNotes != nil || overlay.Templates.CommitMessage != nil {
+		allowHTML, maxMessageBytes := merged.Templates.AllowHTML, merged.Templates.MaxMessageBytes
 		merged.Templates = overlay.Templates
+		merged.Templates.AllowHTML, merged.Templates.MaxMessageBytes = allowHTML, maxMessageBytes
+	}
+	// Set on its own so an overlay can turn it off, or on without replacing the templates
+	if overlay.Templates.AllowHTML != nil {
+		merged.Templates.AllowHTML = overlay.Templates.AllowHTML
+	}
+	// Set on its own so an overlay can change the cap without replacing the templates
+	if overlay.Templates.MaxMessageBytes != 0 {
+		merged.Templates.MaxMessageBytes = overlay.Templates.MaxMessageBytes
+	}
+	if overlay.Changelog.ExcludeTypes != nil || overlay.Changelog.Placeholder != "" || overlay.Changelog.RequiredMetadata != nil || overlay.Changelog.Notes || overlay.Changelog.NotesHeading != "" || overlay.Changelog.SectionOrder != nil {
+		merged.Changelog = overlay.Changelog
+	}
+	if len(overlay.ChangeTypes) > 0 {
+		merged.ChangeTypes = overlay.ChangeTypes
 	}
 	if len(overlay.Metadata.Fields) > 0 {
 		merged.Metadata = overlay.Metadata
 	}
 	if overlay.Consignments.Path != "" {
-		merged.Consignments = overlay.Consignments
+		merged.Consignments.Path = overlay.Consignments.Path
+	}
+	if overlay.Consignments.IDStyle != "" {
+		merged.Consignments.IDStyle = overlay.Consignments.IDStyle
 	}
-	if overlay.History.Path != "" {
+	if overlay.History.Path != "" || overlay.History.EmbedConfig || overlay.History.LockTimeout != "" || overlay.History.Keep != 0 {
 		merged.History = overlay.History
 	}
 	if overlay.GitHub.Owner != "" || overlay.GitHub.Repo != "" {
 		merged.GitHub = overlay.GitHub
 	}
+	if overlay.Git.Remote != "" || overlay.Git.SSHKey != "" {
+		merged.Git = overlay.Git
+	}
 	if len(overlay.PreRelease.Stages) > 0 || overlay.PreRelease.SnapshotTagTemplate != "" {
 		merged.PreRelease = overlay.PreRelease
 	}
+	if overlay.ReleaseSchedule != nil {
+		merged.ReleaseSchedule = overlay.ReleaseSchedule
+	}
+	if len(overlay.Remote.Auth) > 0 || overlay.Remote.CacheTTL != "" || overlay.Remote.CABundle != "" || overlay.Remote.InsecureSkipVerify {
+		merged.Remote = overlay.Remote
+	}
+	if overlay.Interpolation.Disabled || overlay.Interpolation.Strict {
+		merged.Interpolation = overlay.Interpolation
+	}
+	if len(overlay.Hooks.PreVersion) > 0 || len(overlay.Hooks.PostVersion) > 0 {
+		merged.Hooks = overlay.Hooks
+	}
+	if len(overlay.Channels) > 0 {
+		merged.Channels = overlay.Channels
+	}
+	if len(overlay.Check.Ignore) > 0 {
+		merged.Check = overlay.Check
+	}
+	if len(overlay.DependencyUpdates.ChangeTypes) > 0 {
+		merged.DependencyUpdates = overlay.DependencyUpdates
+	}
+	// Rule levels are merged per rule so a local config can relax one rule
+	// without restating the rest
+	for id, level := range overlay.Rules {
+		if merged.Rules == nil {
+			merged.Rules = make(map[string]string)
+		}
+		merged.Rules[id] = level
+	}
 
 	return merged
 }
@@ -359,10 +1242,16 @@ func (c *Config) Merge(overlay *Config) *Config {
 // Performs a deep copy so the original config is not modified.
 func (c *Config) WithDefaults() *Config {
 	result := Config{
-		Templates:    c.Templates,
-		Consignments: c.Consignments,
-		History:      c.History,
-		GitHub:       c.GitHub,
+		SchemaVersion:      c.SchemaVersion,
+		MinShipyardVersion: c.MinShipyardVersion,
+		Templates:          c.Templates,
+		Changelog:          c.Changelog,
+		Consignments:       c.Consignments,
+		History:            c.History,
+		GitHub:             c.GitHub,
+		Git:                c.Git,
+		Interpolation:      c.Interpolation,
+		Hooks:              c.Hooks,
 	}
 
 	// Deep copy Extends
@@ -389,12 +1278,51 @@ func (c *Config) WithDefaults() *Config {
 		}
 	}
 
+	// Deep copy Changelog lists
+	if c.Changelog.RequiredMetadata != nil {
+		result.Changelog.RequiredMetadata = append([]string{}, c.Changelog.RequiredMetadata...)
+	}
+	if c.Changelog.ExcludeTypes != nil {
+		result.Changelog.ExcludeTypes = append([]string{}, c.Changelog.ExcludeTypes...)
+	}
+	if c.Changelog.SectionOrder != nil {
+		result.Changelog.SectionOrder = append([]string{}, c.Changelog.SectionOrder...)
+	}
+
+	if len(c.ChangeTypes) > 0 {
+		result.ChangeTypes = append([]ChangeTypeConfig{}, c.ChangeTypes...)
+	}
+
+	// Deep copy Channels and their branch patterns
+	for _, channel := range c.Channels {
+		channel.Branches = append([]string(nil), channel.Branches...)
+		result.Channels = append(result.Channels, channel)
+	}
+	result.Check.Ignore = append([]string(nil), c.Check.Ignore...)
+	if c.DependencyUpdates.ChangeTypes != nil {
+		result.DependencyUpdates.ChangeTypes = maps.Clone(c.DependencyUpdates.ChangeTypes)
+	}
+
 	// Deep copy Metadata.Fields
 	if len(c.Metadata.Fields) > 0 {
 		result.Metadata.Fields = make([]MetadataField, len(c.Metadata.Fields))
 		copy(result.Metadata.Fields, c.Metadata.Fields)
 	}
 
+	result.Rules = copyStringMap(c.Rules)
+
+	if c.ReleaseSchedule != nil {
+		sched := *c.ReleaseSchedule
+		result.ReleaseSchedule = &sched
+	}
+
+	result.Remote.CacheTTL = c.Remote.CacheTTL
+	result.Remote.CABundle = c.Remote.CABundle
+	result.Remote.InsecureSkipVerify = c.Remote.InsecureSkipVerify
+	if len(c.Remote.Auth) > 0 {
+		result.Remote.Auth = append([]RemoteAuth{}, c.Remote.Auth...)
+	}
+
 	// Deep copy PreRelease.Stages
 	result.PreRelease = c.PreRelease
 	if len(c.PreRelease.Stages) > 0 {
@@ -420,6 +1348,18 @@ func (c *Config) WithDefaults() *Config {
 	return &result
 }
 
+// copyStringMap returns a copy of m, or nil if m is nil
+func copyStringMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	result := make(map[string]string, len(m))
+	for k, v := range m {
+		result[k] = v
+	}
+	return result
+}
+
 // NewRemoteConfig parses a remote configuration source string into a typed
 // remote config descriptor. HTTP/file sources are stored as URL sources, while
 // git-style sources populate Git, Path, and Ref.
@@ -450,6 +1390,19 @@ func (rc *RemoteConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
 
 // parseImplied detects type from string format
 func (rc *RemoteConfig) parseImplied(value string) {
+	// Forge references (github:, gitlab:, bitbucket:)
+	if template.IsForgeSource(value) {
+		if fs, err := template.ParseForgeSource(value); err == nil {
+			rc.Git = fs.HTTPSURL()
+			rc.Path = fs.Path
+			rc.Ref = fs.Ref
+			if rc.Ref == "" {
+				rc.Ref = "main"
+			}
+			return
+		}
+	}
+
 	// Git URL patterns
 	if strings.HasPrefix(value, "git@") ||
 		strings.HasPrefix(value, "ssh://") ||
@@ -1,9 +1,14 @@
 package config
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/NatoNathan/shipyard/internal/history"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestConfig_Validate(t *testing.T) {
@@ -41,6 +46,48 @@ func TestConfig_Validate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "duplicate",
 		},
+		{
+			name: "package aliases",
+			config: &Config{
+				Packages: []Package{
+					{Name: "web", Path: "web", Aliases: []string{"web-app", "site"}},
+					{Name: "api", Path: "api"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "alias naming another package",
+			config: &Config{
+				Packages: []Package{
+					{Name: "web", Path: "web", Aliases: []string{"api"}},
+					{Name: "api", Path: "api"},
+				},
+			},
+			wantErr: true,
+			errMsg:  `alias "api" is already used by package api`,
+		},
+		{
+			name: "alias shared by two packages",
+			config: &Config{
+				Packages: []Package{
+					{Name: "web", Path: "web", Aliases: []string{"app"}},
+					{Name: "api", Path: "api", Aliases: []string{"app"}},
+				},
+			},
+			wantErr: true,
+			errMsg:  `alias "app" is already used by package web`,
+		},
+		{
+			name: "alias on a package glob",
+			config: &Config{
+				Packages: []Package{
+					{Name: "libs", Path: "libs/*", Aliases: []string{"lib"}},
+				},
+			},
+			wantErr: true,
+			errMsg:  "aliases are not valid on package globs",
+		},
 		{
 			name: "invalid package",
 			config: &Config{
@@ -84,6 +131,65 @@ func TestConfig_Validate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "no such package exists",
 		},
+		{
+			name: "helm appVersion fixed is valid",
+			config: &Config{
+				Packages: []Package{
+					{Name: "myapp-chart", Path: "./charts", Ecosystem: EcosystemHelm, Options: map[string]interface{}{"appVersion": "fixed"}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid helm appVersion strategy",
+			config: &Config{
+				Packages: []Package{
+					{Name: "myapp-chart", Path: "./charts", Ecosystem: EcosystemHelm, Options: map[string]interface{}{"appVersion": "linked"}},
+				},
+			},
+			wantErr: true,
+			errMsg:  "unknown appVersion strategy",
+		},
+		{
+			name: "helm appVersion independent requires appDependency",
+			config: &Config{
+				Packages: []Package{
+					{Name: "myapp-chart", Path: "./charts", Ecosystem: EcosystemHelm, Options: map[string]interface{}{"appVersion": "independent"}},
+				},
+			},
+			wantErr: true,
+			errMsg:  "no appDependency",
+		},
+		{
+			name: "helm appDependency conflicts with appVersion follow",
+			config: &Config{
+				Packages: []Package{
+					{Name: "myapp", Path: ".", Ecosystem: EcosystemGo},
+					{Name: "myapp-chart", Path: "./charts", Ecosystem: EcosystemHelm, Options: map[string]interface{}{"appDependency": "myapp", "appVersion": "follow"}},
+				},
+			},
+			wantErr: true,
+			errMsg:  "only applies with appVersion independent",
+		},
+		{
+			name: "npm dependencyRange caret is valid",
+			config: &Config{
+				Packages: []Package{
+					{Name: "web", Path: "./web", Ecosystem: EcosystemNPM, Options: map[string]interface{}{"dependencyRange": "caret"}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid npm dependencyRange",
+			config: &Config{
+				Packages: []Package{
+					{Name: "web", Path: "./web", Ecosystem: EcosystemNPM, Options: map[string]interface{}{"dependencyRange": "workspace"}},
+				},
+			},
+			wantErr: true,
+			errMsg:  "unknown dependencyRange",
+		},
 		{
 			name: "helm without appDependency is valid",
 			config: &Config{
@@ -94,6 +200,50 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "helm publish to oci registry",
+			config: &Config{
+				Packages: []Package{
+					{
+						Name:      "myapp-chart",
+						Path:      "./charts",
+						Ecosystem: EcosystemHelm,
+						Publish:   &PublishConfig{Helm: &HelmPublishConfig{Registry: "oci://ghcr.io/acme/charts"}},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "helm publish requires oci registry",
+			config: &Config{
+				Packages: []Package{
+					{
+						Name:      "myapp-chart",
+						Path:      "./charts",
+						Ecosystem: EcosystemHelm,
+						Publish:   &PublishConfig{Helm: &HelmPublishConfig{Registry: "https://charts.example.com"}},
+					},
+				},
+			},
+			wantErr: true,
+			errMsg:  "oci://",
+		},
+		{
+			name: "helm publish on non-helm package",
+			config: &Config{
+				Packages: []Package{
+					{
+						Name:      "myapp",
+						Path:      ".",
+						Ecosystem: EcosystemGo,
+						Publish:   &PublishConfig{Helm: &HelmPublishConfig{Registry: "oci://ghcr.io/acme/charts"}},
+					},
+				},
+			},
+			wantErr: true,
+			errMsg:  "not a helm package",
+		},
 	}
 	
 	for _, tt := range tests {
@@ -172,6 +322,65 @@ func TestConfig_Merge(t *testing.T) {
 	assert.True(t, foundOverlay)
 }
 
+func TestConfig_MergeAllowHTML(t *testing.T) {
+	allow := func(v bool) *bool { return &v }
+	base := &Config{Templates: TemplateConfig{
+		Changelog:     &TemplateSource{Source: "builtin:keepachangelog"},
+		CommitMessage: &TemplateSource{Source: "builtin:conventional"},
+	}}
+
+	t.Run("turning it on keeps the base templates", func(t *testing.T) {
+		merged := base.Merge(&Config{Templates: TemplateConfig{AllowHTML: allow(true)}})
+		assert.True(t, merged.Templates.HTMLAllowed())
+		require.NotNil(t, merged.Templates.Changelog)
+		assert.Equal(t, "builtin:keepachangelog", merged.Templates.Changelog.Source)
+		require.NotNil(t, merged.Templates.CommitMessage)
+		assert.Equal(t, "builtin:conventional", merged.Templates.CommitMessage.Source)
+	})
+
+	t.Run("an inherited true can be turned off", func(t *testing.T) {
+		allowed := base.Merge(&Config{Templates: TemplateConfig{AllowHTML: allow(true)}})
+		merged := allowed.Merge(&Config{Templates: TemplateConfig{AllowHTML: allow(false)}})
+		assert.False(t, merged.Templates.HTMLAllowed())
+		require.NotNil(t, merged.Templates.Changelog)
+	})
+
+	t.Run("replacing the templates keeps an inherited setting", func(t *testing.T) {
+		allowed := base.Merge(&Config{Templates: TemplateConfig{AllowHTML: allow(true)}})
+		merged := allowed.Merge(&Config{Templates: TemplateConfig{TagName: &TemplateSource{Source: "v{{ .Version }}"}}})
+		assert.True(t, merged.Templates.HTMLAllowed())
+	})
+}
+
+func TestConfig_MergeMaxMessageBytes(t *testing.T) {
+	base := &Config{Templates: TemplateConfig{
+		Changelog:     &TemplateSource{Source: "builtin:keepachangelog"},
+		CommitMessage: &TemplateSource{Source: "builtin:conventional"},
+	}}
+
+	t.Run("setting it keeps the base templates", func(t *testing.T) {
+		merged := base.Merge(&Config{Templates: TemplateConfig{MaxMessageBytes: 4096}})
+		assert.Equal(t, 4096, merged.Templates.MaxMessageBytes)
+		require.NotNil(t, merged.Templates.Changelog)
+		assert.Equal(t, "builtin:keepachangelog", merged.Templates.Changelog.Source)
+		require.NotNil(t, merged.Templates.CommitMessage)
+		assert.Equal(t, "builtin:conventional", merged.Templates.CommitMessage.Source)
+	})
+
+	t.Run("replacing the templates keeps an inherited cap", func(t *testing.T) {
+		capped := base.Merge(&Config{Templates: TemplateConfig{MaxMessageBytes: 4096}})
+		merged := capped.Merge(&Config{Templates: TemplateConfig{TagName: &TemplateSource{Source: "v{{ .Version }}"}}})
+		assert.Equal(t, 4096, merged.Templates.MaxMessageBytes)
+		assert.Nil(t, merged.Templates.Changelog)
+	})
+
+	t.Run("an overlay cap wins", func(t *testing.T) {
+		capped := base.Merge(&Config{Templates: TemplateConfig{MaxMessageBytes: 4096}})
+		merged := capped.Merge(&Config{Templates: TemplateConfig{MaxMessageBytes: 1024}})
+		assert.Equal(t, 1024, merged.Templates.MaxMessageBytes)
+	})
+}
+
 func TestConfig_Defaults(t *testing.T) {
 	config := &Config{
 		Packages: []Package{
@@ -378,6 +587,20 @@ func TestNewRemoteConfig(t *testing.T) {
 			wantPath: "shipyard.yaml",
 			wantRef:  "main",
 		},
+		{
+			name:     "GitLab reference with nested subgroups and host",
+			input:    "gitlab:host=git.corp.example.com:platform/shared/configs//base/shipyard.yaml@v2",
+			wantGit:  "https://git.corp.example.com/platform/shared/configs.git",
+			wantPath: "base/shipyard.yaml",
+			wantRef:  "v2",
+		},
+		{
+			name:     "Bitbucket reference defaults ref",
+			input:    "bitbucket:acme/configs/shipyard.yaml",
+			wantGit:  "https://bitbucket.org/acme/configs.git",
+			wantPath: "shipyard.yaml",
+			wantRef:  "main",
+		},
 		{
 			name:    "plain string treated as URL",
 			input:   "file:///local/shipyard.yaml",
@@ -442,3 +665,485 @@ func TestPackage_IsTagOnly(t *testing.T) {
 		})
 	}
 }
+
+func TestPackage_GetHelmOptions(t *testing.T) {
+	t.Run("camel case key", func(t *testing.T) {
+		pkg := Package{Options: map[string]interface{}{"appDependency": "api"}}
+		assert.Equal(t, "api", pkg.GetHelmOptions().AppDependency)
+	})
+
+	t.Run("key lowercased by config loading", func(t *testing.T) {
+		pkg := Package{Options: map[string]interface{}{"appdependency": "api"}}
+		assert.Equal(t, "api", pkg.GetHelmOptions().AppDependency)
+	})
+
+	t.Run("no options", func(t *testing.T) {
+		pkg := Package{}
+		assert.Empty(t, pkg.GetHelmOptions().AppDependency)
+	})
+
+	t.Run("appVersion strategy", func(t *testing.T) {
+		assert.Equal(t, HelmAppVersionFollow, (&Package{}).GetHelmOptions().AppVersionStrategy())
+
+		pkg := Package{Options: map[string]interface{}{"appDependency": "api"}}
+		assert.Equal(t, HelmAppVersionIndependent, pkg.GetHelmOptions().AppVersionStrategy(), "appDependency alone implies independent")
+
+		pkg = Package{Options: map[string]interface{}{"appversion": "fixed"}}
+		assert.Equal(t, HelmAppVersionFixed, pkg.GetHelmOptions().AppVersionStrategy())
+	})
+}
+
+func TestPackage_GetNPMOptions(t *testing.T) {
+	pkg := Package{Options: map[string]interface{}{"dependencyrange": "tilde"}}
+	assert.Equal(t, NPMDependencyRangeTilde, pkg.GetNPMOptions().DependencyRange)
+
+	assert.Empty(t, (&Package{}).GetNPMOptions().DependencyRange)
+}
+
+func TestPackage_GetDotnetOptions(t *testing.T) {
+	pkg := Package{Options: map[string]interface{}{"manifest": "src/Api/Api.csproj"}}
+	assert.Equal(t, "src/Api/Api.csproj", pkg.GetDotnetOptions().Manifest)
+
+	assert.Empty(t, (&Package{}).GetDotnetOptions().Manifest)
+}
+
+func TestConfig_ChangelogFor(t *testing.T) {
+	cfg := &Config{
+		Changelog: ChangelogConfig{ExcludeTypes: []string{"patch"}, Placeholder: "Maintenance only"},
+		Packages: []Package{
+			{Name: "core", Path: "."},
+			{Name: "api", Path: "api", Changelog: &ChangelogConfig{ExcludeTypes: []string{}}},
+			{Name: "web", Path: "web", Changelog: &ChangelogConfig{ExcludeTypes: []string{"minor"}, Placeholder: "Nothing user-facing"}},
+		},
+	}
+
+	core := cfg.ChangelogFor("core")
+	assert.Equal(t, []string{"patch"}, core.ExcludeTypes)
+	assert.Equal(t, "Maintenance only", core.Placeholder)
+
+	// An explicit empty list disables the project-level exclusions
+	api := cfg.ChangelogFor("api")
+	assert.Empty(t, api.ExcludeTypes)
+	assert.Equal(t, "Maintenance only", api.Placeholder)
+
+	web := cfg.ChangelogFor("web")
+	assert.Equal(t, []string{"minor"}, web.ExcludeTypes)
+	assert.Equal(t, "Nothing user-facing", web.Placeholder)
+
+	assert.Equal(t, DefaultChangelogPlaceholder, (&Config{}).ChangelogFor("core").Placeholder)
+}
+
+func TestConfig_ValidateChangelog(t *testing.T) {
+	cfg := &Config{
+		Packages:  []Package{{Name: "core", Path: "."}},
+		Changelog: ChangelogConfig{ExcludeTypes: []string{"chore"}},
+	}
+	assert.Error(t, cfg.Validate())
+
+	cfg.Changelog.ExcludeTypes = []string{"patch"}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Packages[0].Changelog = &ChangelogConfig{ExcludeTypes: []string{"docs"}}
+	assert.Error(t, cfg.Validate())
+}
+
+func TestConfig_ChangeTypeAudiences(t *testing.T) {
+	cfg := &Config{
+		Packages: []Package{{Name: "core", Path: "."}},
+		ChangeTypes: []ChangeTypeConfig{
+			{Name: "patch", Audience: "internal"},
+			{Name: "minor"},
+		},
+	}
+	require.NoError(t, cfg.Validate())
+	assert.Equal(t, map[string]string{"patch": "internal"}, cfg.ChangeTypeAudiences())
+	assert.Nil(t, (&Config{}).ChangeTypeAudiences())
+
+	merged := (&Config{}).Merge(cfg)
+	assert.Equal(t, cfg.ChangeTypes, merged.ChangeTypes)
+	assert.Equal(t, cfg.ChangeTypes, cfg.WithDefaults().ChangeTypes)
+
+	cfg.ChangeTypes = []ChangeTypeConfig{{Name: "patch", Audience: "ops"}}
+	assert.ErrorContains(t, cfg.Validate(), `invalid audience "ops"`)
+
+	cfg.ChangeTypes = []ChangeTypeConfig{{Name: "chore", Audience: "internal"}}
+	assert.Error(t, cfg.Validate())
+
+	cfg.ChangeTypes = []ChangeTypeConfig{{Name: "patch"}, {Name: "patch"}}
+	assert.ErrorContains(t, cfg.Validate(), "duplicate change type patch")
+}
+
+func TestConfig_ChangelogSections(t *testing.T) {
+	cfg := &Config{
+		Packages: []Package{
+			{Name: "core", Path: "."},
+			{Name: "api", Path: "api", Changelog: &ChangelogConfig{SectionOrder: []string{"patch"}}},
+		},
+		ChangeTypes: []ChangeTypeConfig{
+			{Name: "minor", Section: "Performance"},
+			{Name: "patch", Audience: "internal"},
+		},
+	}
+	require.NoError(t, cfg.Validate())
+	assert.Equal(t, []history.Section{
+		{Title: "Performance", ChangeTypes: []string{"minor"}},
+		{ChangeTypes: []string{"major"}},
+		{ChangeTypes: []string{"patch"}},
+	}, cfg.ChangelogSections("core"), "configured sections come first, then the defaults")
+
+	cfg.Changelog.SectionOrder = []string{"major"}
+	require.NoError(t, cfg.Validate())
+	assert.Equal(t, []history.Section{
+		{ChangeTypes: []string{"major"}},
+		{Title: "Performance", ChangeTypes: []string{"minor"}},
+		{ChangeTypes: []string{"patch"}},
+	}, cfg.ChangelogSections("core"))
+	assert.Equal(t, []history.Section{
+		{ChangeTypes: []string{"patch"}},
+		{Title: "Performance", ChangeTypes: []string{"minor"}},
+		{ChangeTypes: []string{"major"}},
+	}, cfg.ChangelogSections("api"), "a package order replaces the project order")
+
+	cfg.ChangeTypes = []ChangeTypeConfig{{Name: "minor", Section: "Changes"}, {Name: "patch", Section: "Changes"}}
+	cfg.Changelog.SectionOrder = []string{"Changes", "minor"}
+	require.NoError(t, cfg.Validate())
+	assert.Equal(t, []history.Section{
+		{Title: "Changes", ChangeTypes: []string{"minor", "patch"}},
+		{ChangeTypes: []string{"major"}},
+	}, cfg.ChangelogSections("core"), "change types naming one section share it")
+
+	cfg.Changelog.SectionOrder = []string{"Security"}
+	assert.ErrorContains(t, cfg.Validate(), `unknown section "Security"`)
+
+	merged := (&Config{}).Merge(cfg)
+	assert.Equal(t, []string{"Security"}, merged.Changelog.SectionOrder)
+}
+
+func TestConfig_ChangelogTemplateFor(t *testing.T) {
+	cfg := &Config{
+		Templates: TemplateConfig{Changelog: &TemplateSource{Source: "builtin:default"}},
+		Packages: []Package{
+			{Name: "core", Path: "."},
+			{Name: "charts", Path: "charts", ChangelogTemplate: "builtin:keepachangelog"},
+			{Name: "api", Path: "api", Templates: &TemplateConfig{Changelog: &TemplateSource{Source: "file:api.tmpl"}}},
+		},
+	}
+
+	assert.Equal(t, "builtin:default", cfg.ChangelogTemplateFor("core"))
+	assert.Equal(t, "builtin:keepachangelog", cfg.ChangelogTemplateFor("charts"))
+	assert.Equal(t, "file:api.tmpl", cfg.ChangelogTemplateFor("api"))
+	assert.Equal(t, "changelog", (&Config{}).ChangelogTemplateFor("core"))
+}
+
+func TestPackage_ValidateChangelogTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		pkg     Package
+		wantErr string
+	}{
+		{name: "builtin", pkg: Package{Name: "a", Path: "a", ChangelogTemplate: "builtin:keepachangelog"}},
+		{name: "file is checked on use", pkg: Package{Name: "a", Path: "a", ChangelogTemplate: "templates/changelog.tmpl"}},
+		{name: "unknown builtin", pkg: Package{Name: "a", Path: "a", ChangelogTemplate: "builtin:missing"}, wantErr: "builtin template not found"},
+		{name: "unparseable inline", pkg: Package{Name: "a", Path: "a", Templates: &TemplateConfig{Changelog: &TemplateSource{Source: "{{ .Broken\n"}}}, wantErr: "invalid changelog template"},
+		{
+			name: "both forms",
+			pkg: Package{Name: "a", Path: "a", ChangelogTemplate: "builtin:default",
+				Templates: &TemplateConfig{Changelog: &TemplateSource{Source: "builtin:keepachangelog"}}},
+			wantErr: "not both",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.pkg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestPackage_ValidateDependencyStrategy(t *testing.T) {
+	for _, strategy := range []string{"", "linked", "fixed", "patch"} {
+		pkg := Package{Name: "api", Path: ".", Dependencies: []Dependency{{Package: "core", Strategy: strategy}}}
+		assert.NoError(t, pkg.Validate(), "strategy %q", strategy)
+	}
+
+	pkg := Package{Name: "api", Path: ".", Dependencies: []Dependency{{Package: "core", Strategy: "pinned"}}}
+	err := pkg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown strategy "pinned"`)
+}
+
+func TestPackage_ValidateVersionFiles(t *testing.T) {
+	for _, files := range [][]string{nil, {"tag-only"}, {"version.go"}, {"VERSION", "internal/version/version.go"}} {
+		pkg := Package{Name: "a", Path: ".", VersionFiles: files}
+		assert.NoError(t, pkg.Validate(), "versionFiles %v", files)
+	}
+
+	pkg := Package{Name: "a", Path: ".", VersionFiles: []string{"tag-only", "VERSION"}}
+	assert.ErrorContains(t, pkg.Validate(), "tag-only cannot be combined")
+
+	pkg = Package{Name: "a", Path: ".", VersionFiles: []string{"../VERSION"}}
+	assert.ErrorContains(t, pkg.Validate(), "must be relative to the package path")
+}
+
+func TestPackage_ValidateTagOnly(t *testing.T) {
+	pkg := Package{Name: "a", Path: ".", TagOnly: true, InitialVersion: "0.1.0"}
+	assert.NoError(t, pkg.Validate())
+	assert.True(t, pkg.IsTagOnly())
+
+	pkg = Package{Name: "a", Path: ".", VersionFiles: []string{"tag-only"}, InitialVersion: "1.0.0"}
+	assert.NoError(t, pkg.Validate())
+
+	pkg = Package{Name: "a", Path: ".", TagOnly: true, VersionFiles: []string{"VERSION"}}
+	assert.ErrorContains(t, pkg.Validate(), "tagOnly cannot be combined with versionFiles")
+
+	pkg = Package{Name: "a", Path: ".", InitialVersion: "1.0.0"}
+	assert.ErrorContains(t, pkg.Validate(), "initialVersion requires tagOnly")
+
+	pkg = Package{Name: "a", Path: ".", TagOnly: true, InitialVersion: "one"}
+	assert.ErrorContains(t, pkg.Validate(), "invalid initialVersion")
+
+	pkg = Package{Name: "a", Path: ".", TagOnly: true, VersioningScheme: "calver", InitialVersion: "2026.01.0"}
+	assert.NoError(t, pkg.Validate())
+}
+
+func TestPackage_VersioningScheme(t *testing.T) {
+	tests := []struct {
+		name    string
+		pkg     Package
+		wantErr bool
+	}{
+		{name: "default semver", pkg: Package{Name: "a", Path: "."}},
+		{name: "explicit semver", pkg: Package{Name: "a", Path: ".", VersioningScheme: "semver"}},
+		{name: "calver default format", pkg: Package{Name: "a", Path: ".", VersioningScheme: "calver"}},
+		{name: "calver custom format", pkg: Package{Name: "a", Path: ".", VersioningScheme: "calver", CalVerFormat: "YY.0W.MICRO"}},
+		{name: "calver invalid format", pkg: Package{Name: "a", Path: ".", VersioningScheme: "calver", CalVerFormat: "YYYY.0M.PATCH"}, wantErr: true},
+		{name: "format without calver", pkg: Package{Name: "a", Path: ".", CalVerFormat: "YYYY.0M.MICRO"}, wantErr: true},
+		{name: "unknown scheme", pkg: Package{Name: "a", Path: ".", VersioningScheme: "romver"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.pkg.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	pkg := Package{Name: "a", Path: ".", VersioningScheme: "calver"}
+	assert.Equal(t, "YYYY.0M.MICRO", pkg.GetCalVerFormat())
+	v, err := pkg.ParseVersion("2024.06.2")
+	assert.NoError(t, err)
+	assert.Equal(t, "2024.06.2", v.String())
+
+	_, err = (&Package{Name: "b", Path: "."}).ParseVersion("2024.06.2")
+	assert.Error(t, err, "semver packages reject zero-padded versions")
+}
+
+func TestConfig_Rules(t *testing.T) {
+	cfg := &Config{
+		Packages: []Package{{Name: "core", Path: "."}},
+		Rules:    map[string]string{"tag-collision": "warn", "stale-consignment": "off"},
+	}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Rules["tag-colision"] = "warn"
+	assert.ErrorContains(t, cfg.Validate(), `unknown rule "tag-colision"`)
+	delete(cfg.Rules, "tag-colision")
+
+	cfg.Rules["message-size"] = "fatal"
+	assert.ErrorContains(t, cfg.Validate(), "invalid rule level")
+	delete(cfg.Rules, "message-size")
+
+	// Overlay rule levels are merged per rule
+	overlay := &Config{Rules: map[string]string{"tag-collision": "error", "dependency-cycle": "error"}}
+	merged := cfg.Merge(overlay)
+	assert.Equal(t, map[string]string{
+		"tag-collision":     "error",
+		"stale-consignment": "off",
+		"dependency-cycle":  "error",
+	}, merged.Rules)
+	assert.Equal(t, "warn", cfg.Rules["tag-collision"], "merge must not modify the base config")
+
+	// WithDefaults deep-copies the map
+	defaulted := cfg.WithDefaults()
+	defaulted.Rules["tag-collision"] = "off"
+	assert.Equal(t, "warn", cfg.Rules["tag-collision"])
+}
+
+func TestHistoryConfig_LockTimeout(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "", want: 0},
+		{value: "30s", want: 30 * time.Second},
+		{value: "2m", want: 2 * time.Minute},
+		{value: "soon", wantErr: true},
+		{value: "-1s", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := HistoryConfig{LockTimeout: tt.value}.LockTimeoutDuration()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestHistoryConfig_Keep(t *testing.T) {
+	cfg := &Config{Packages: []Package{{Name: "core", Path: "./"}}, History: HistoryConfig{Keep: 50}}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.History.Keep = -1
+	assert.ErrorContains(t, cfg.Validate(), "history.keep must not be negative")
+
+	merged := (&Config{History: HistoryConfig{Path: ".shipyard/history.json"}}).Merge(&Config{History: HistoryConfig{Keep: 10}})
+	assert.Equal(t, 10, merged.History.Keep)
+}
+
+func TestGitConfig_MergeAndDefaults(t *testing.T) {
+	base := &Config{Git: GitConfig{Remote: "upstream", SSHKey: "keys/deploy"}}
+
+	merged := base.Merge(&Config{Consignments: ConsignmentConfig{Path: ".changes"}})
+	assert.Equal(t, base.Git, merged.Git)
+
+	merged = base.Merge(&Config{Git: GitConfig{Remote: "release"}})
+	assert.Equal(t, GitConfig{Remote: "release"}, merged.Git)
+
+	assert.Equal(t, base.Git, base.WithDefaults().Git)
+}
+
+func TestScheduleConfig_Validate(t *testing.T) {
+	base := func(s *ScheduleConfig) *Config {
+		return &Config{Packages: []Package{{Name: "core", Path: "./"}}, ReleaseSchedule: s}
+	}
+
+	assert.NoError(t, base(&ScheduleConfig{Cron: "0 10 * * 4", Timezone: "Europe/London", GraceHours: 6}).Validate())
+	assert.ErrorContains(t, base(&ScheduleConfig{}).Validate(), "releaseSchedule.cron is required")
+	assert.ErrorContains(t, base(&ScheduleConfig{Cron: "0 10 * *"}).Validate(), "expected 5 fields")
+	assert.ErrorContains(t, base(&ScheduleConfig{Cron: "0 10 * * 4", Timezone: "Nowhere/Special"}).Validate(), "invalid timezone")
+	assert.ErrorContains(t, base(&ScheduleConfig{Cron: "0 10 * * 4", GraceHours: -1}).Validate(), "graceHours must not be negative")
+}
+
+func TestRemoteAuth_Validate(t *testing.T) {
+	base := func(auth ...RemoteAuth) *Config {
+		return &Config{Packages: []Package{{Name: "core", Path: "./"}}, Remote: RemoteSettings{Auth: auth}}
+	}
+
+	assert.NoError(t, base(RemoteAuth{Host: "templates.example.com", TokenEnv: "TEMPLATES_TOKEN"}).Validate())
+	assert.NoError(t, base(RemoteAuth{Host: "git.example.com", TokenEnv: "GIT_TOKEN", Type: "basic", UsernameEnv: "GIT_USER"}).Validate())
+	assert.ErrorContains(t, base(RemoteAuth{TokenEnv: "TOKEN"}).Validate(), "host is required")
+	assert.ErrorContains(t, base(RemoteAuth{Host: "https://example.com", TokenEnv: "TOKEN"}).Validate(), "must not include a scheme or path")
+	assert.ErrorContains(t, base(RemoteAuth{Host: "example.com"}).Validate(), "tokenEnv is required")
+	assert.ErrorContains(t, base(RemoteAuth{Host: "example.com", TokenEnv: "TOKEN", Type: "digest"}).Validate(), "invalid type")
+	assert.ErrorContains(t, base(RemoteAuth{Host: "example.com", TokenEnv: "TOKEN", UsernameEnv: "USER"}).Validate(), "usernameEnv requires type basic")
+}
+
+func TestRemoteSettings_CacheTTL(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "", want: 0},
+		{value: "6h", want: 6 * time.Hour},
+		{value: "later", wantErr: true},
+		{value: "0s", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := RemoteSettings{CacheTTL: tt.value}.CacheTTLDuration()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRemoteSettings_Credentials(t *testing.T) {
+	env := map[string]string{"TEMPLATES_TOKEN": "abc", "GIT_TOKEN": "def", "GIT_USER": "deploy"}
+	settings := RemoteSettings{Auth: []RemoteAuth{
+		{Host: "templates.example.com", TokenEnv: "TEMPLATES_TOKEN"},
+		{Host: "git.example.com", TokenEnv: "GIT_TOKEN", Type: "basic", UsernameEnv: "GIT_USER"},
+		{Host: "unset.example.com", TokenEnv: "UNSET_TOKEN"},
+	}}
+
+	credentials := settings.Credentials(func(key string) string { return env[key] })
+
+	require.Len(t, credentials, 2)
+	assert.Equal(t, "abc", credentials["templates.example.com"].Token)
+	assert.False(t, credentials["templates.example.com"].Basic)
+	assert.True(t, credentials["git.example.com"].Basic)
+	assert.Equal(t, "deploy", credentials["git.example.com"].Username)
+}
+
+func TestLoadFromDir_RemoteAuth(t *testing.T) {
+	dir := t.TempDir()
+	content := `packages:
+  - name: core
+    path: ./
+remote:
+  auth:
+    - host: templates.example.com
+      tokenEnv: TEMPLATES_TOKEN
+      type: basic
+      usernameEnv: TEMPLATES_USER
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "shipyard.yaml"), []byte(content), 0644))
+
+	cfg, err := LoadFromDir(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []RemoteAuth{{Host: "templates.example.com", TokenEnv: "TEMPLATES_TOKEN", Type: "basic", UsernameEnv: "TEMPLATES_USER"}}, cfg.Remote.Auth)
+}
+
+func TestConfig_HooksFor(t *testing.T) {
+	dir := t.TempDir()
+	content := `hooks:
+  preVersion:
+    - make test
+packages:
+  - name: api
+    path: ./
+    hooks:
+      preVersion:
+        - make lint
+      postVersion:
+        - go generate ./...
+  - name: docs
+    path: ./
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "shipyard.yaml"), []byte(content), 0644))
+
+	cfg, err := LoadFromDir(dir)
+	require.NoError(t, err)
+
+	api, ok := cfg.GetPackage("api")
+	require.True(t, ok)
+	assert.Equal(t, HooksConfig{
+		PreVersion:  []string{"make test", "make lint"},
+		PostVersion: []string{"go generate ./..."},
+	}, cfg.HooksFor(api))
+
+	docs, ok := cfg.GetPackage("docs")
+	require.True(t, ok)
+	assert.Equal(t, HooksConfig{PreVersion: []string{"make test"}}, cfg.HooksFor(docs))
+	assert.Equal(t, []string{"make test"}, cfg.Hooks.PreVersion, "the global hooks are not modified")
+}
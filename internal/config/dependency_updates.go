@@ -0,0 +1,63 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/NatoNathan/shipyard/pkg/types"
+)
+
+// DependencyUpdatesConfig configures `shipyard add --deps`, which logs the
+// dependency updates made since each package's last release
+type DependencyUpdatesConfig struct {
+	// ChangeTypes maps dependency name patterns to the change type an update
+	// of a matching dependency needs instead of patch, e.g. react: major or
+	// "@aws-sdk/*": minor. * matches any run of characters, including /.
+	ChangeTypes map[string]types.ChangeType `yaml:"changeTypes,omitempty"`
+}
+
+// validate checks the patterns and their change types
+func (c *DependencyUpdatesConfig) validate() error {
+	for pattern, changeType := range c.ChangeTypes {
+		if strings.TrimSpace(pattern) == "" {
+			return fmt.Errorf("dependencyUpdates.changeTypes has an empty pattern")
+		}
+		if err := changeType.Validate(); err != nil {
+			return fmt.Errorf("invalid dependencyUpdates.changeTypes entry %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// ChangeTypeFor returns the change type an update of the named dependency
+// needs: the highest of the patterns it matches, or patch
+func (c *DependencyUpdatesConfig) ChangeTypeFor(name string) types.ChangeType {
+	changeType := types.ChangeTypePatch
+	for pattern, patternType := range c.ChangeTypes {
+		if matchDependencyPattern(pattern, name) && patternType.Priority() > changeType.Priority() {
+			changeType = patternType
+		}
+	}
+	return changeType
+}
+
+// matchDependencyPattern reports whether name matches pattern, where *
+// matches any run of characters
+func matchDependencyPattern(pattern, name string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == name
+	}
+	if !strings.HasPrefix(name, parts[0]) {
+		return false
+	}
+	name = name[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(name, part)
+		if i < 0 {
+			return false
+		}
+		name = name[i+len(part):]
+	}
+	return strings.HasSuffix(name, parts[len(parts)-1])
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/NatoNathan/shipyard/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDependencyUpdatesConfig_ChangeTypeFor(t *testing.T) {
+	deps := DependencyUpdatesConfig{ChangeTypes: map[string]types.ChangeType{
+		"react":                 types.ChangeTypeMajor,
+		"@aws-sdk/*":            types.ChangeTypeMinor,
+		"github.com/*/protocol": types.ChangeTypeMinor,
+		"*-schema":              types.ChangeTypeMinor,
+		"github.com/acme/*":     types.ChangeTypeMajor,
+	}}
+	tests := []struct {
+		name string
+		want types.ChangeType
+	}{
+		{name: "react", want: types.ChangeTypeMajor},
+		{name: "react-dom", want: types.ChangeTypePatch},
+		{name: "@aws-sdk/client-s3", want: types.ChangeTypeMinor},
+		{name: "github.com/example/protocol", want: types.ChangeTypeMinor},
+		{name: "github.com/example/protocol/v2", want: types.ChangeTypePatch},
+		{name: "event-schema", want: types.ChangeTypeMinor},
+		{name: "github.com/acme/protocol", want: types.ChangeTypeMajor}, // The highest matching change type wins
+		{name: "lodash", want: types.ChangeTypePatch},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, deps.ChangeTypeFor(tt.name), tt.name)
+	}
+	assert.Equal(t, types.ChangeTypePatch, (&DependencyUpdatesConfig{}).ChangeTypeFor("react"))
+}
+
+func TestDependencyUpdatesConfig_Validate(t *testing.T) {
+	base := func(changeTypes map[string]types.ChangeType) *Config {
+		return &Config{Packages: []Package{{Name: "core", Path: "./"}}, DependencyUpdates: DependencyUpdatesConfig{ChangeTypes: changeTypes}}
+	}
+
+	assert.NoError(t, base(map[string]types.ChangeType{"react": types.ChangeTypeMajor}).Validate())
+	assert.ErrorContains(t, base(map[string]types.ChangeType{"react": "breaking"}).Validate(), `invalid dependencyUpdates.changeTypes entry "react"`)
+	assert.ErrorContains(t, base(map[string]types.ChangeType{" ": types.ChangeTypeMinor}).Validate(), "empty pattern")
+
+	merged := base(map[string]types.ChangeType{"react": types.ChangeTypeMajor}).Merge(&Config{DependencyUpdates: DependencyUpdatesConfig{ChangeTypes: map[string]types.ChangeType{"vue": types.ChangeTypeMinor}}})
+	assert.Equal(t, map[string]types.ChangeType{"vue": types.ChangeTypeMinor}, merged.DependencyUpdates.ChangeTypes)
+	assert.Equal(t, types.ChangeTypeMajor, base(map[string]types.ChangeType{"react": types.ChangeTypeMajor}).Merge(&Config{}).DependencyUpdates.ChangeTypeFor("react"))
+}
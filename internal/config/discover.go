@@ -0,0 +1,153 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// EcosystemAuto lets a package glob accept whichever ecosystem is detected
+// in each matched directory
+const EcosystemAuto = "auto"
+
+// PackageDetector infers the package in dir from its ecosystem markers,
+// returning nil when dir holds no package
+type PackageDetector func(rootPath, dir string) (*Package, error)
+
+// packageDetector expands package globs; the detect package registers the
+// detection `shipyard init` uses
+var packageDetector PackageDetector
+
+// SetPackageDetector sets the detector used to expand package globs
+func SetPackageDetector(detector PackageDetector) {
+	packageDetector = detector
+}
+
+// IsGlob reports whether the package entry is a glob such as "packages/*"
+// that expands to one package per matching directory
+func (p *Package) IsGlob() bool {
+	return strings.ContainsAny(p.Path, "*?[")
+}
+
+// ExpandPackageGlobs replaces each glob package entry with the packages
+// detected in the directories it matches under rootPath. Expanded packages
+// take their name, path and ecosystem from detection and every other field
+// from the glob entry. Explicit entries win over globbed packages with the
+// same name or path, and a glob's exclude patterns drop packages by path or
+// name. A glob that matches no packages is an error.
+func (c *Config) ExpandPackageGlobs(rootPath string) error {
+	explicitNames := make(map[string]bool)
+	explicitPaths := make(map[string]bool)
+	hasGlobs := false
+	for _, pkg := range c.Packages {
+		if pkg.IsGlob() {
+			hasGlobs = true
+			continue
+		}
+		explicitNames[pkg.Name] = true
+		explicitPaths[cleanPackagePath(pkg.Path)] = true
+	}
+	if !hasGlobs {
+		return nil
+	}
+	if packageDetector == nil {
+		return fmt.Errorf("package globs require package detection, which is not available")
+	}
+
+	expanded := make([]Package, 0, len(c.Packages))
+	globbedPaths := make(map[string]bool)
+	for _, entry := range c.Packages {
+		if !entry.IsGlob() {
+			expanded = append(expanded, entry)
+			continue
+		}
+		if entry.Name != "" {
+			return fmt.Errorf("package glob %q must not set a name; names are detected per directory", entry.Path)
+		}
+
+		matches, err := filepath.Glob(filepath.Join(rootPath, filepath.FromSlash(entry.Path)))
+		if err != nil {
+			return fmt.Errorf("invalid package glob %q: %w", entry.Path, err)
+		}
+
+		detected := 0
+		for _, match := range matches {
+			if info, err := os.Stat(match); err != nil || !info.IsDir() {
+				continue
+			}
+			found, err := packageDetector(rootPath, match)
+			if err != nil {
+				return fmt.Errorf("failed to detect package in %s: %w", match, err)
+			}
+			if found == nil {
+				continue
+			}
+			if entry.Ecosystem != "" && entry.Ecosystem != EcosystemAuto && entry.Ecosystem != found.Ecosystem {
+				continue
+			}
+			detected++
+
+			pkgPath := cleanPackagePath(found.Path)
+			if explicitNames[found.Name] || explicitPaths[pkgPath] || globbedPaths[pkgPath] {
+				continue
+			}
+			excluded, err := matchesAny(entry.Exclude, pkgPath, found.Name)
+			if err != nil {
+				return fmt.Errorf("invalid exclude for package glob %q: %w", entry.Path, err)
+			}
+			if excluded {
+				continue
+			}
+
+			pkg := entry
+			pkg.Name = found.Name
+			pkg.Path = found.Path
+			pkg.Ecosystem = found.Ecosystem
+			pkg.Exclude = nil
+			globbedPaths[pkgPath] = true
+			expanded = append(expanded, pkg)
+		}
+
+		if detected == 0 {
+			return fmt.Errorf("package glob %q matched no packages", entry.Path)
+		}
+	}
+
+	c.Packages = expanded
+	return nil
+}
+
+// cleanPackagePath normalizes a package path for comparison, so "./a/b",
+// "a/b/" and "a/b" are equal
+func cleanPackagePath(p string) string {
+	return path.Clean(filepath.ToSlash(p))
+}
+
+// matchesAny reports whether any pattern matches the package path or name
+func matchesAny(patterns []string, pkgPath, name string) (bool, error) {
+	for _, pattern := range patterns {
+		pattern = cleanPackagePath(pattern)
+		for _, candidate := range []string{pkgPath, name} {
+			matched, err := path.Match(pattern, candidate)
+			if err != nil {
+				return false, err
+			}
+			if matched {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// projectRootForConfig returns the project directory a config file belongs
+// to: the parent of .shipyard/, or the file's own directory
+func projectRootForConfig(configPath string) string {
+	dir := filepath.Dir(configPath)
+	if filepath.Base(dir) == ".shipyard" {
+		return filepath.Dir(dir)
+	}
+	return dir
+}
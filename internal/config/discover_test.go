@@ -0,0 +1,162 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubDetector detects a package in any directory with a marker file named
+// after its ecosystem, naming the package after the directory
+func stubDetector(rootPath, dir string) (*Package, error) {
+	for _, ecosystem := range []string{EcosystemGo, EcosystemNPM, EcosystemHelm} {
+		if _, err := os.Stat(filepath.Join(dir, ecosystem)); err == nil {
+			rel, _ := filepath.Rel(rootPath, dir)
+			return &Package{Name: filepath.Base(dir), Path: "./" + filepath.ToSlash(rel), Ecosystem: ecosystem}, nil
+		}
+	}
+	return nil, nil
+}
+
+func useDetector(t *testing.T, detector PackageDetector) {
+	t.Helper()
+	previous := packageDetector
+	SetPackageDetector(detector)
+	t.Cleanup(func() { SetPackageDetector(previous) })
+}
+
+func writeMarkers(t *testing.T, root string, markers map[string]string) {
+	t.Helper()
+	for dir, ecosystem := range markers {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, dir), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, dir, ecosystem), nil, 0644))
+	}
+}
+
+func packageNames(packages []Package) []string {
+	names := make([]string, len(packages))
+	for i, pkg := range packages {
+		names[i] = pkg.Name
+	}
+	return names
+}
+
+func TestExpandPackageGlobs(t *testing.T) {
+	useDetector(t, stubDetector)
+	root := t.TempDir()
+	writeMarkers(t, root, map[string]string{
+		"packages/api":    EcosystemGo,
+		"packages/web":    EcosystemNPM,
+		"packages/chart":  EcosystemHelm,
+		"packages/legacy": EcosystemGo,
+	})
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "packages", "docs"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "packages", "README.md"), nil, 0644))
+
+	t.Run("expands one package per detected directory", func(t *testing.T) {
+		cfg := &Config{Packages: []Package{{Path: "packages/*", Ecosystem: EcosystemAuto}}}
+		require.NoError(t, cfg.ExpandPackageGlobs(root))
+
+		assert.Equal(t, []string{"api", "chart", "legacy", "web"}, packageNames(cfg.Packages))
+		assert.Equal(t, Package{Name: "web", Path: "./packages/web", Ecosystem: EcosystemNPM}, cfg.Packages[3])
+	})
+
+	t.Run("glob fields apply to every expanded package", func(t *testing.T) {
+		cfg := &Config{Packages: []Package{{
+			Path:         "packages/*",
+			Dependencies: []Dependency{{Package: "shared"}},
+		}, {Name: "shared", Path: "./shared"}}}
+		require.NoError(t, cfg.ExpandPackageGlobs(root))
+
+		for _, pkg := range cfg.Packages[:4] {
+			assert.Equal(t, []Dependency{{Package: "shared"}}, pkg.Dependencies, pkg.Name)
+		}
+	})
+
+	t.Run("ecosystem filters matched directories", func(t *testing.T) {
+		cfg := &Config{Packages: []Package{{Path: "packages/*", Ecosystem: EcosystemGo}}}
+		require.NoError(t, cfg.ExpandPackageGlobs(root))
+
+		assert.Equal(t, []string{"api", "legacy"}, packageNames(cfg.Packages))
+	})
+
+	t.Run("exclude drops packages by path or name", func(t *testing.T) {
+		cfg := &Config{Packages: []Package{{Path: "packages/*", Exclude: []string{"./packages/legacy", "ch*"}}}}
+		require.NoError(t, cfg.ExpandPackageGlobs(root))
+
+		assert.Equal(t, []string{"api", "web"}, packageNames(cfg.Packages))
+	})
+
+	t.Run("explicit entries override globbed packages", func(t *testing.T) {
+		cfg := &Config{Packages: []Package{
+			{Name: "api", Path: "./services/api", Ecosystem: EcosystemGo},
+			{Path: "packages/*"},
+			{Name: "frontend", Path: "packages/web/", Ecosystem: EcosystemNPM},
+		}}
+		require.NoError(t, cfg.ExpandPackageGlobs(root))
+
+		assert.Equal(t, []string{"api", "chart", "legacy", "frontend"}, packageNames(cfg.Packages))
+		assert.Equal(t, "./services/api", cfg.Packages[0].Path)
+	})
+
+	t.Run("glob matching nothing fails", func(t *testing.T) {
+		cfg := &Config{Packages: []Package{{Path: "services/*"}}}
+		err := cfg.ExpandPackageGlobs(root)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `package glob "services/*" matched no packages`)
+	})
+
+	t.Run("glob with a name fails", func(t *testing.T) {
+		cfg := &Config{Packages: []Package{{Name: "all", Path: "packages/*"}}}
+		require.Error(t, cfg.ExpandPackageGlobs(root))
+	})
+
+	t.Run("configs without globs are untouched", func(t *testing.T) {
+		useDetector(t, nil)
+		cfg := &Config{Packages: []Package{{Name: "core", Path: "./core"}}}
+		require.NoError(t, cfg.ExpandPackageGlobs(root))
+		assert.Equal(t, []string{"core"}, packageNames(cfg.Packages))
+	})
+}
+
+func TestLoadFromDir_PackageGlobs(t *testing.T) {
+	useDetector(t, stubDetector)
+	root := t.TempDir()
+	writeMarkers(t, root, map[string]string{
+		"packages/api": EcosystemGo,
+		"packages/web": EcosystemNPM,
+	})
+	require.NoError(t, os.MkdirAll(filepath.Join(root, ".shipyard"), 0755))
+	configContent := `packages:
+  - path: packages/*
+    ecosystem: auto
+    exclude:
+      - web
+  - name: web
+    path: ./packages/web
+    ecosystem: npm
+`
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".shipyard", "shipyard.yaml"), []byte(configContent), 0644))
+
+	cfg, err := LoadFromDir(root)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api", "web"}, packageNames(cfg.Packages))
+
+	pkg, ok := cfg.GetPackage("api")
+	require.True(t, ok)
+	assert.Equal(t, "./packages/api", pkg.Path)
+
+	loaded, err := Load(filepath.Join(root, ".shipyard", "shipyard.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, cfg.Packages, loaded.Packages)
+}
+
+func TestPackageValidate_ExcludeRequiresGlob(t *testing.T) {
+	pkg := Package{Name: "core", Path: "./core", Exclude: []string{"x"}}
+	err := pkg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exclude is only valid on package globs")
+}
@@ -0,0 +1,327 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/logger"
+	"github.com/NatoNathan/shipyard/internal/template"
+	"gopkg.in/yaml.v3"
+)
+
+// OriginDefault is the origin of values filled in by WithDefaults
+const OriginDefault = "default"
+
+// defaultExtendsPath is read from git extends sources that name no path
+const defaultExtendsPath = ".shipyard/shipyard.yaml"
+
+// Origins records where each top-level config key's value came from: the
+// config file, an extends source, or OriginDefault. Keys merged across
+// layers, such as packages, list every layer that set them, base first.
+type Origins map[string][]string
+
+// configLayer is the settings read from one config file
+type configLayer struct {
+	origin   string
+	settings map[string]interface{}
+}
+
+// Source returns the extends source in the form the template loader reads:
+// a file path, an HTTP(S) URL, or a git: reference
+func (rc RemoteConfig) Source() string {
+	if rc.Git != "" {
+		path := rc.Path
+		if path == "" {
+			path = defaultExtendsPath
+		}
+		source := "git:" + rc.Git + "#" + path
+		if rc.Ref != "" {
+			source += "@" + rc.Ref
+		}
+		return source
+	}
+	return strings.TrimPrefix(rc.URL, "file://")
+}
+
+// maxExtendsDepth is how many configs an extends chain may pass through,
+// counting the config file itself
+const maxExtendsDepth = 10
+
+// extendsResolver follows extends chains depth-first, collecting layers
+// furthest ancestor first
+type extendsResolver struct {
+	seen   map[string]bool
+	layers []configLayer
+	remote template.RemoteOptions // Cache, credential and certificate settings of remote fetches
+}
+
+// resolveExtends reads the configs configPath extends, and the configs they
+// extend in turn, and rewrites string entries in settings as mappings. The
+// layers are ordered for merging: each config's bases, in the order listed,
+// come before it. A config reached twice is merged once, where first reached.
+// Remote bases are fetched with the settings in remote.
+func resolveExtends(settings map[string]interface{}, configPath string, remote template.RemoteOptions) ([]configLayer, error) {
+	path, err := filepath.Abs(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve config path: %w", err)
+	}
+	r := &extendsResolver{seen: map[string]bool{path: true}, remote: remote}
+	if err := r.resolve(settings, []string{path}); err != nil {
+		return nil, err
+	}
+	return r.layers, nil
+}
+
+// resolve reads the bases settings extends; chain holds the configs from
+// the config file down to the one settings were read from
+func (r *extendsResolver) resolve(settings map[string]interface{}, chain []string) error {
+	sources, err := extendsSources(settings)
+	if err != nil {
+		return err
+	}
+
+	parent := chain[len(chain)-1]
+	for _, rc := range sources {
+		if rc.Source() == "" {
+			return fmt.Errorf("extends entry in %s has no url or git source", parent)
+		}
+		source, err := resolveExtendsSource(rc.Source(), parent)
+		if err != nil {
+			return err
+		}
+
+		next := append(slices.Clone(chain), source)
+		if slices.Contains(chain, source) {
+			return fmt.Errorf("circular extends: %s", strings.Join(next, " -> "))
+		}
+		if r.seen[source] {
+			continue
+		}
+		if len(next) > maxExtendsDepth {
+			return fmt.Errorf("extends chain is deeper than %d configs: %s", maxExtendsDepth, strings.Join(next, " -> "))
+		}
+		r.seen[source] = true
+
+		base, err := loadBaseConfig(rc, source, r.remote)
+		if err != nil {
+			return err
+		}
+		if err := r.resolve(base, next); err != nil {
+			return err
+		}
+		// A base's extends are merged in its place, not as a setting
+		for key := range base {
+			if strings.EqualFold(key, "extends") {
+				delete(base, key)
+			}
+		}
+		r.layers = append(r.layers, configLayer{origin: source, settings: base})
+	}
+	return nil
+}
+
+// extendsSources parses the extends entries in settings and rewrites them
+// as mappings, which viper can unmarshal
+func extendsSources(settings map[string]interface{}) ([]RemoteConfig, error) {
+	var key string
+	for k := range settings {
+		if strings.EqualFold(k, "extends") {
+			key = k
+		}
+	}
+	if key == "" || settings[key] == nil {
+		return nil, nil
+	}
+
+	// Round-trip through YAML so string entries are parsed like the file's
+	data, err := yaml.Marshal(settings[key])
+	if err != nil {
+		return nil, fmt.Errorf("invalid extends: %w", err)
+	}
+	var sources []RemoteConfig
+	if err := yaml.Unmarshal(data, &sources); err != nil {
+		return nil, fmt.Errorf("invalid extends: %w", err)
+	}
+
+	data, err = yaml.Marshal(sources)
+	if err != nil {
+		return nil, fmt.Errorf("invalid extends: %w", err)
+	}
+	var entries []interface{}
+	if err := yaml.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("invalid extends: %w", err)
+	}
+	settings[key] = entries
+	return sources, nil
+}
+
+// resolveExtendsSource resolves a relative file source against the config
+// that lists it: a file's directory, a URL, or a path in the same git
+// repository and ref. Other sources are returned unchanged.
+func resolveExtendsSource(source, parent string) (string, error) {
+	if sourceType, _ := template.DetectSourceType(source); sourceType != template.SourceTypeFile || filepath.IsAbs(source) {
+		return source, nil
+	}
+
+	parentType, target := template.DetectSourceType(parent)
+	switch parentType {
+	case template.SourceTypeFile:
+		return filepath.Join(filepath.Dir(target), source), nil
+	case template.SourceTypeHTTPS:
+		base, err := url.Parse(target)
+		if err != nil {
+			return "", fmt.Errorf("invalid extends source %s: %w", parent, err)
+		}
+		ref, err := url.Parse(filepath.ToSlash(source))
+		if err != nil {
+			return "", fmt.Errorf("invalid extends source %q in %s: %w", source, parent, err)
+		}
+		return base.ResolveReference(ref).String(), nil
+	case template.SourceTypeGit:
+		repo, fragment, _ := strings.Cut(target, "#")
+		file, ref, hasRef := strings.Cut(fragment, "@")
+		resolved := "git:" + repo + "#" + path.Join(path.Dir(file), filepath.ToSlash(source))
+		if hasRef {
+			resolved += "@" + ref
+		}
+		return resolved, nil
+	}
+	return "", fmt.Errorf("relative extends %q in %s cannot be resolved; use a full source", source, parent)
+}
+
+// loadBaseConfig fetches and parses the config at source, as resolved from
+// rc, with the retries and timeout rc sets and the cache, remote.auth
+// credentials and certificate settings in remote. A remote.auth entry for
+// source's host takes precedence over the token rc.auth names. Base configs are checked for compatibility and migrated like the
+// config file itself.
+func loadBaseConfig(rc RemoteConfig, source string, remote template.RemoteOptions) (map[string]interface{}, error) {
+	loader := template.NewTemplateLoader()
+	loader.SetRemoteOptions(remote)
+	if rc.Auth != "" {
+		loader.SetAuthToken(os.Getenv(rc.Auth))
+	}
+	if rc.Retries != nil {
+		if *rc.Retries < 0 {
+			return nil, fmt.Errorf("invalid retries %d for extended config %s: must not be negative", *rc.Retries, source)
+		}
+		loader.SetRetries(*rc.Retries)
+	}
+	if rc.Timeout != "" {
+		timeout, err := time.ParseDuration(rc.Timeout)
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("invalid timeout %q for extended config %s: expected a positive duration such as 30s", rc.Timeout, source)
+		}
+		loader.SetTimeout(timeout)
+	}
+	logger.Get().Debug("Loading extended config %s", source)
+	content, err := loader.Load(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load extended config %s: %w", source, err)
+	}
+
+	var settings map[string]interface{}
+	if err := yaml.Unmarshal([]byte(content), &settings); err != nil {
+		return nil, fmt.Errorf("failed to parse extended config %s: %w", source, err)
+	}
+	if settings == nil {
+		settings = map[string]interface{}{}
+	}
+
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	required, _ := settings["minShipyardVersion"].(string)
+	if err := checkCompatibility(required, keys); err != nil {
+		return nil, fmt.Errorf("extended config %s: %w", source, err)
+	}
+
+	// Base configs belong to another project, so they are migrated without
+	// a notice the local project cannot act on
+	settings, _, err = MigrateSettings(settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate extended config %s: %w", source, err)
+	}
+	return settings, nil
+}
+
+// mergeLayers merges config layers, later layers taking precedence, and
+// records the origin of each top-level key. As in Merge, packages are
+// appended and rules are merged per rule; every other key is replaced whole.
+func mergeLayers(layers []configLayer) (map[string]interface{}, Origins) {
+	names := configKeyNames()
+	merged := make(map[string]interface{})
+	origins := make(Origins)
+
+	for _, layer := range layers {
+		for key, value := range layer.settings {
+			lower := strings.ToLower(key)
+			name, ok := names[lower]
+			if !ok {
+				name = key
+			}
+
+			switch lower {
+			case "packages":
+				existing, _ := merged[lower].([]interface{})
+				added, _ := value.([]interface{})
+				merged[lower] = append(append([]interface{}{}, existing...), added...)
+				origins[name] = append(origins[name], layer.origin)
+			case "rules":
+				rules, _ := merged[lower].(map[string]interface{})
+				combined := make(map[string]interface{}, len(rules))
+				for id, level := range rules {
+					combined[id] = level
+				}
+				if overlay, ok := value.(map[string]interface{}); ok {
+					for id, level := range overlay {
+						combined[id] = level
+					}
+				}
+				merged[lower] = combined
+				origins[name] = append(origins[name], layer.origin)
+			default:
+				merged[lower] = value
+				origins[name] = []string{layer.origin}
+			}
+		}
+	}
+	return merged, origins
+}
+
+// addDefaults records OriginDefault for the top-level keys of cfg that no
+// layer set
+func (o Origins) addDefaults(cfg *Config) error {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	var resolved map[string]interface{}
+	if err := yaml.Unmarshal(data, &resolved); err != nil {
+		return err
+	}
+	for key := range resolved {
+		if _, ok := o[key]; !ok {
+			o[key] = []string{OriginDefault}
+		}
+	}
+	return nil
+}
+
+// configKeyNames maps each lowercased top-level config key to its name
+func configKeyNames() map[string]string {
+	names := make(map[string]string)
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		names[strings.ToLower(name)] = name
+	}
+	return names
+}
@@ -0,0 +1,390 @@
+package config
+
+import (
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/httpclient"
+	"github.com/NatoNathan/shipyard/internal/template"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serveConfigs serves files by path over HTTP for the rest of the test, with
+// downloads cached in a temporary directory, and returns the server's URL
+func serveConfigs(t *testing.T, files map[string]string) string {
+	t.Helper()
+	t.Setenv(template.CacheDirEnv, t.TempDir())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(content))
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+// corePackage is a package list for configs whose packages are not under test
+const corePackage = "packages:\n  - name: core\n    path: ./\n    ecosystem: go\n"
+
+// writeConfigFiles writes files under dir/.shipyard and returns the path of
+// shipyard.yaml
+func writeConfigFiles(t *testing.T, dir string, files map[string]string) string {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".shipyard"), 0755))
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, ".shipyard", name), []byte(content), 0644))
+	}
+	return filepath.Join(dir, ".shipyard", "shipyard.yaml")
+}
+
+func TestLoadFromDir_ExtendsChain(t *testing.T) {
+	// The remote middle layer extends the organization's base by a URL
+	// relative to its own
+	server := serveConfigs(t, map[string]string{
+		"/team.yaml": `extends:
+  - org.yaml
+changelog:
+  placeholder: Team placeholder
+rules:
+  stale-consignment: error
+`,
+		"/org.yaml": `github:
+  owner: acme
+  repo: tools
+changelog:
+  placeholder: Org placeholder
+  excludeTypes: [patch]
+rules:
+  stale-consignment: warn
+  message-size: warn
+templates:
+  tag_name: builtin:npm
+packages:
+  - name: shared
+    path: ./shared
+    ecosystem: go
+`,
+	})
+
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "shared"), 0755))
+	configPath := writeConfigFiles(t, dir, map[string]string{
+		"shipyard.yaml": `extends:
+  - ` + server + `/team.yaml
+packages:
+  - name: core
+    path: ./
+    ecosystem: go
+rules:
+  message-size: "off"
+`,
+	})
+
+	cfg, origins, err := LoadFromDirWithOrigins(dir)
+	require.NoError(t, err)
+
+	// Packages are appended, furthest ancestor first
+	require.Len(t, cfg.Packages, 2)
+	assert.Equal(t, "shared", cfg.Packages[0].Name)
+	assert.Equal(t, "core", cfg.Packages[1].Name)
+	// The middle layer replaces the base's section whole
+	assert.Equal(t, "Team placeholder", cfg.Changelog.Placeholder)
+	assert.Empty(t, cfg.Changelog.ExcludeTypes)
+	// Rules are merged per rule across all three levels
+	assert.Equal(t, map[string]string{"stale-consignment": "error", "message-size": "off"}, cfg.Rules)
+	// Older base shapes are migrated
+	assert.Equal(t, "builtin:npm", cfg.Templates.TagName.Source)
+	assert.Equal(t, "acme", cfg.GitHub.Owner)
+	// Only the config file's own extends are kept
+	require.Len(t, cfg.Extends, 1)
+	assert.Equal(t, server+"/team.yaml", cfg.Extends[0].URL)
+
+	org, team := server+"/org.yaml", server+"/team.yaml"
+	assert.Equal(t, []string{org, configPath}, origins["packages"])
+	assert.Equal(t, []string{team}, origins["changelog"])
+	assert.Equal(t, []string{org, team, configPath}, origins["rules"])
+	assert.Equal(t, []string{org}, origins["github"])
+	assert.Equal(t, []string{configPath}, origins["extends"])
+	assert.Equal(t, []string{OriginDefault}, origins["consignments"])
+}
+
+func TestLoadFromDir_ExtendsOrder(t *testing.T) {
+	// Bases are merged in the order listed, each after its own bases, and a
+	// base reached twice is merged once, where first reached
+	dir := t.TempDir()
+	configPath := writeConfigFiles(t, dir, map[string]string{
+		"shipyard.yaml": "extends: [a.yaml, b.yaml]\n" + corePackage,
+		"a.yaml":        "extends: [common.yaml]\nchangelog:\n  placeholder: a\n",
+		"b.yaml":        "extends: [common.yaml]\ngithub:\n  owner: b\n",
+		"common.yaml":   "changelog:\n  placeholder: common\ngithub:\n  owner: common\n",
+	})
+
+	cfg, origins, err := LoadFromDirWithOrigins(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "a", cfg.Changelog.Placeholder)
+	assert.Equal(t, "b", cfg.GitHub.Owner)
+
+	base := filepath.Dir(configPath)
+	assert.Equal(t, []string{filepath.Join(base, "a.yaml")}, origins["changelog"])
+	assert.Equal(t, []string{filepath.Join(base, "b.yaml")}, origins["github"])
+}
+
+func TestLoadFromDir_ExtendsAllowHTML(t *testing.T) {
+	// A project's templates block replaces its base's, so it can turn off
+	// allowHtml the base turns on
+	dir := t.TempDir()
+	writeConfigFiles(t, dir, map[string]string{
+		"shipyard.yaml": "extends: [base.yaml]\ntemplates:\n  allowHtml: false\n" + corePackage,
+		"base.yaml":     "templates:\n  allowHtml: true\n  changelog:\n    source: builtin:keepachangelog\n",
+	})
+
+	cfg, err := LoadFromDir(dir)
+	require.NoError(t, err)
+	require.NotNil(t, cfg.Templates.AllowHTML)
+	assert.False(t, cfg.Templates.HTMLAllowed())
+}
+
+func TestLoadFromDir_CircularExtends(t *testing.T) {
+	t.Run("files", func(t *testing.T) {
+		dir := t.TempDir()
+		configPath := writeConfigFiles(t, dir, map[string]string{
+			"shipyard.yaml": "extends: [a.yaml]\n" + corePackage,
+			"a.yaml":        "extends: [b.yaml]\n",
+			"b.yaml":        "extends: [a.yaml]\n",
+		})
+
+		_, err := LoadFromDir(dir)
+		require.Error(t, err)
+		base := filepath.Dir(configPath)
+		a, b := filepath.Join(base, "a.yaml"), filepath.Join(base, "b.yaml")
+		assert.Contains(t, err.Error(), fmt.Sprintf("circular extends: %s -> %s -> %s -> %s", configPath, a, b, a))
+	})
+
+	t.Run("back to the config file", func(t *testing.T) {
+		dir := t.TempDir()
+		configPath := writeConfigFiles(t, dir, map[string]string{
+			"shipyard.yaml": "extends: [a.yaml]\n" + corePackage,
+			"a.yaml":        "extends: [shipyard.yaml]\n",
+		})
+
+		_, err := LoadFromDir(dir)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "circular extends: "+configPath+" -> ")
+	})
+
+	t.Run("urls", func(t *testing.T) {
+		server := serveConfigs(t, map[string]string{
+			"/team.yaml": "extends: [org.yaml]\n",
+			"/org.yaml":  "extends: [./team.yaml]\n",
+		})
+		dir := writeProjectConfig(t, "extends:\n  - "+server+"/team.yaml\n")
+
+		_, err := LoadFromDir(dir)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), fmt.Sprintf("%s/team.yaml -> %s/org.yaml -> %s/team.yaml", server, server, server))
+	})
+}
+
+func TestLoadFromDir_ExtendsDepthLimit(t *testing.T) {
+	files := map[string]string{"shipyard.yaml": "extends: [base1.yaml]\n" + corePackage}
+	for i := 1; i <= maxExtendsDepth; i++ {
+		files[fmt.Sprintf("base%d.yaml", i)] = fmt.Sprintf("extends: [base%d.yaml]\n", i+1)
+	}
+	files[fmt.Sprintf("base%d.yaml", maxExtendsDepth)] = "{}\n"
+
+	dir := t.TempDir()
+	writeConfigFiles(t, dir, files)
+	_, err := LoadFromDir(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), fmt.Sprintf("extends chain is deeper than %d configs", maxExtendsDepth))
+
+	// One level fewer is within the limit
+	files[fmt.Sprintf("base%d.yaml", maxExtendsDepth-1)] = "{}\n"
+	writeConfigFiles(t, dir, files)
+	_, err = LoadFromDir(dir)
+	require.NoError(t, err)
+}
+
+func TestLoadFromDir_ExtendsErrors(t *testing.T) {
+	t.Run("missing base", func(t *testing.T) {
+		dir := writeProjectConfig(t, "extends:\n  - url: missing.yaml\n")
+		_, err := LoadFromDir(dir)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to load extended config "+filepath.Join(dir, ".shipyard", "missing.yaml"))
+	})
+
+	t.Run("base for a newer shipyard", func(t *testing.T) {
+		useToolVersion(t, "0.5.2")
+		server := serveConfigs(t, map[string]string{"/shared.yaml": "minShipyardVersion: 0.9.0\n"})
+		dir := writeProjectConfig(t, "extends:\n  - "+server+"/shared.yaml\n")
+		_, err := LoadFromDir(dir)
+		var incompatible *IncompatibleConfigError
+		require.ErrorAs(t, err, &incompatible)
+		assert.Contains(t, err.Error(), "extended config "+server+"/shared.yaml")
+	})
+}
+
+func TestLoadFromDir_ExtendsOverSelfSignedTLS(t *testing.T) {
+	t.Setenv(template.CacheDirEnv, t.TempDir())
+	t.Setenv(httpclient.CABundleEnv, "")
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("changelog:\n  placeholder: From the proxy\n"))
+	}))
+	t.Cleanup(server.Close)
+
+	dir := writeProjectConfig(t, "extends:\n  - "+server.URL+"/base.yaml\n")
+	_, err := LoadFromDir(dir)
+	assert.ErrorContains(t, err, "certificate", "a self-signed server is untrusted without a bundle")
+
+	// remote.caBundle is resolved against the project root
+	block := &pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ca.pem"), pem.EncodeToMemory(block), 0644))
+	writeConfigFiles(t, dir, map[string]string{"shipyard.yaml": corePackage +
+		"remote:\n  caBundle: ca.pem\nextends:\n  - " + server.URL + "/base.yaml\n"})
+	cfg, err := LoadFromDir(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "From the proxy", cfg.Changelog.Placeholder)
+	assert.Equal(t, "ca.pem", cfg.Remote.CABundle)
+}
+
+func TestLoadFromDir_ExtendsWithRemoteAuth(t *testing.T) {
+	t.Setenv(template.CacheDirEnv, t.TempDir())
+	t.Setenv(template.RemoteTokenEnv, "")
+	t.Setenv("CONFIG_HOST_TOKEN", "s3cret")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte("changelog:\n  placeholder: Private\n"))
+	}))
+	t.Cleanup(server.Close)
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	dir := writeProjectConfig(t, "extends:\n  - "+server.URL+"/base.yaml\n")
+	_, err := LoadFromDir(dir)
+	assert.ErrorContains(t, err, "HTTP 401", "the host needs a token")
+
+	writeConfigFiles(t, dir, map[string]string{"shipyard.yaml": corePackage +
+		"remote:\n  auth:\n    - host: " + host + "\n      tokenEnv: CONFIG_HOST_TOKEN\n" +
+		"extends:\n  - " + server.URL + "/base.yaml\n"})
+	cfg, err := LoadFromDir(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "Private", cfg.Changelog.Placeholder)
+}
+
+func TestLoadFromDir_ExtendsRetries(t *testing.T) {
+	// flakyBase fails its first request with 503 and counts every request
+	flakyBase := func(t *testing.T) (string, *atomic.Int32) {
+		t.Setenv(template.CacheDirEnv, t.TempDir())
+		var hits atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if hits.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = w.Write([]byte("changelog:\n  placeholder: Shared\n"))
+		}))
+		t.Cleanup(server.Close)
+		return server.URL + "/base.yaml", &hits
+	}
+
+	t.Run("transient failure is retried", func(t *testing.T) {
+		url, hits := flakyBase(t)
+		dir := writeProjectConfig(t, "extends:\n  - url: "+url+"\n")
+		cfg, err := LoadFromDir(dir)
+		require.NoError(t, err)
+		assert.Equal(t, "Shared", cfg.Changelog.Placeholder)
+		assert.Equal(t, int32(2), hits.Load())
+	})
+
+	t.Run("retries from the extends entry", func(t *testing.T) {
+		url, hits := flakyBase(t)
+		dir := writeProjectConfig(t, "extends:\n  - url: "+url+"\n    retries: 0\n")
+		_, err := LoadFromDir(dir)
+		assert.ErrorContains(t, err, "HTTP 503")
+		assert.Equal(t, int32(1), hits.Load())
+	})
+
+	t.Run("retries from the environment", func(t *testing.T) {
+		url, hits := flakyBase(t)
+		t.Setenv(template.FetchRetriesEnv, "0")
+		dir := writeProjectConfig(t, "extends:\n  - "+url+"\n")
+		_, err := LoadFromDir(dir)
+		assert.ErrorContains(t, err, "HTTP 503")
+		assert.Equal(t, int32(1), hits.Load())
+	})
+
+	t.Run("invalid settings", func(t *testing.T) {
+		dir := writeProjectConfig(t, "extends:\n  - url: https://example.com/base.yaml\n    timeout: soon\n")
+		_, err := LoadFromDir(dir)
+		assert.ErrorContains(t, err, `invalid timeout "soon" for extended config https://example.com/base.yaml`)
+
+		dir = writeProjectConfig(t, "extends:\n  - url: https://example.com/base.yaml\n    retries: -1\n")
+		_, err = LoadFromDir(dir)
+		assert.ErrorContains(t, err, "invalid retries -1")
+	})
+}
+
+func TestRemoteSettings_TLSOptions(t *testing.T) {
+	opts := RemoteSettings{CABundle: "certs/ca.pem", InsecureSkipVerify: true}.TLSOptions("/repo")
+	assert.Equal(t, httpclient.TLSOptions{CABundle: filepath.Join("/repo", "certs", "ca.pem"), InsecureSkipVerify: true}, opts)
+	assert.Equal(t, "/etc/ssl/corp.pem", RemoteSettings{CABundle: "/etc/ssl/corp.pem"}.TLSOptions("/repo").CABundle)
+	assert.Empty(t, RemoteSettings{}.TLSOptions("/repo").CABundle)
+}
+
+func TestRemoteSettings_Options(t *testing.T) {
+	remote := RemoteSettings{
+		CacheTTL: "6h",
+		CABundle: "ca.pem",
+		Auth:     []RemoteAuth{{Host: "git.example.com", TokenEnv: "EXAMPLE_TOKEN"}},
+	}
+	opts := remote.Options("/repo", func(name string) string {
+		return map[string]string{"EXAMPLE_TOKEN": "s3cret"}[name]
+	})
+	assert.Equal(t, 6*time.Hour, opts.CacheTTL)
+	assert.Equal(t, map[string]*template.Credential{"git.example.com": {Token: "s3cret"}}, opts.Credentials)
+	assert.Equal(t, filepath.Join("/repo", "ca.pem"), opts.TLS.CABundle)
+}
+
+func TestResolveExtendsSource(t *testing.T) {
+	tests := []struct {
+		source, parent, want string
+	}{
+		{"base.yaml", "/repo/.shipyard/shipyard.yaml", "/repo/.shipyard/base.yaml"},
+		{"../shared/base.yaml", "/repo/.shipyard/shipyard.yaml", "/repo/shared/base.yaml"},
+		{"org.yaml", "https://example.com/configs/team.yaml", "https://example.com/configs/org.yaml"},
+		{"../org.yaml", "https://example.com/configs/team.yaml", "https://example.com/org.yaml"},
+		{"org.yaml", "git:https://example.com/configs.git#teams/team.yaml@v2", "git:https://example.com/configs.git#teams/org.yaml@v2"},
+		{"https://example.com/org.yaml", "/repo/.shipyard/shipyard.yaml", "https://example.com/org.yaml"},
+		{"/etc/shipyard/base.yaml", "https://example.com/team.yaml", "/etc/shipyard/base.yaml"},
+	}
+	for _, tt := range tests {
+		got, err := resolveExtendsSource(tt.source, tt.parent)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, got, "%s in %s", tt.source, tt.parent)
+	}
+
+	_, err := resolveExtendsSource("org.yaml", "github:acme/configs/team.yaml@v1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `relative extends "org.yaml"`)
+}
+
+func TestRemoteConfig_Source(t *testing.T) {
+	assert.Equal(t, "https://example.com/c.yaml", NewRemoteConfig("https://example.com/c.yaml").Source())
+	assert.Equal(t, "/tmp/base.yaml", NewRemoteConfig("file:///tmp/base.yaml").Source())
+	assert.Equal(t, "git:https://example.com/org/configs.git#shared.yaml@v2", NewRemoteConfig("https://example.com/org/configs.git#shared.yaml@v2").Source())
+	assert.Equal(t, "git:git@example.com:org/configs.git#.shipyard/shipyard.yaml", RemoteConfig{Git: "git@example.com:org/configs.git"}.Source())
+}
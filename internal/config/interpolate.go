@@ -0,0 +1,161 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// InterpolationConfig controls how ${VAR} references in config values are
+// expanded from the environment
+type InterpolationConfig struct {
+	Disabled bool `yaml:"disabled,omitempty"` // Leave ${VAR} references as written
+	Strict   bool `yaml:"strict,omitempty"`   // Fail on undefined variables without a default
+}
+
+// UndefinedVariablesError reports ${VAR} references a strict config could
+// not expand
+type UndefinedVariablesError struct {
+	References []string // Each as "VAR (field path)", sorted
+}
+
+func (e *UndefinedVariablesError) Error() string {
+	return fmt.Sprintf("undefined environment variables in config: %s", strings.Join(e.References, ", "))
+}
+
+// Interpolate expands ${VAR} and ${VAR:-default} references in every string
+// value of the config, looking variables up with lookup. The default is used
+// when the variable is unset or empty; otherwise an undefined variable
+// expands to "", or fails under interpolation.strict. $$ is a literal $.
+// References are expanded once; their values are not expanded again. Hook
+// commands are left as written: the shell expands them when the hook runs,
+// with the SHIPYARD_* variables of the release set.
+func (c *Config) Interpolate(lookup func(string) (string, bool)) error {
+	if c.Interpolation.Disabled {
+		return nil
+	}
+
+	var undefined []string
+	expand := func(value, path string) string {
+		return expandVariables(value, lookup, func(name string) {
+			undefined = append(undefined, fmt.Sprintf("%s (%s)", name, path))
+		})
+	}
+	interpolateValue(reflect.ValueOf(c).Elem(), "", expand)
+
+	if c.Interpolation.Strict && len(undefined) > 0 {
+		sort.Strings(undefined)
+		return &UndefinedVariablesError{References: undefined}
+	}
+	return nil
+}
+
+// hooksType is skipped by interpolateValue; see Interpolate
+var hooksType = reflect.TypeOf(HooksConfig{})
+
+// interpolateValue expands the strings in v, which must be settable. path is
+// the field path of v, named by yaml tags, for error messages.
+func interpolateValue(v reflect.Value, path string, expand func(value, path string) string) {
+	if v.Type() == hooksType {
+		return
+	}
+	switch v.Kind() {
+	case reflect.String:
+		if strings.Contains(v.String(), "$") {
+			v.SetString(expand(v.String(), path))
+		}
+	case reflect.Pointer:
+		if !v.IsNil() {
+			interpolateValue(v.Elem(), path, expand)
+		}
+	case reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		elem := reflect.New(v.Elem().Type()).Elem()
+		elem.Set(v.Elem())
+		interpolateValue(elem, path, expand)
+		v.Set(elem)
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+			if name == "" {
+				name = field.Name
+			}
+			interpolateValue(v.Field(i), joinPath(path, name), expand)
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			interpolateValue(v.Index(i), fmt.Sprintf("%s[%d]", path, i), expand)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(iter.Value())
+			interpolateValue(elem, joinPath(path, fmt.Sprint(iter.Key().Interface())), expand)
+			v.SetMapIndex(iter.Key(), elem)
+		}
+	}
+}
+
+// expandVariables expands the ${VAR} and ${VAR:-default} references in
+// value. undefined is called with the name of each variable that has no
+// value and no default. Text that is not a well-formed reference is kept.
+func expandVariables(value string, lookup func(string) (string, bool), undefined func(name string)) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '$' || i+1 == len(value) {
+			b.WriteByte(value[i])
+			continue
+		}
+		switch value[i+1] {
+		case '$':
+			b.WriteByte('$')
+			i++
+			continue
+		case '{':
+			end := strings.IndexByte(value[i+2:], '}')
+			if end == -1 {
+				break
+			}
+			name, fallback, hasDefault := strings.Cut(value[i+2:i+2+end], ":-")
+			if !isVariableName(name) {
+				break
+			}
+			if env, ok := lookup(name); ok && (env != "" || !hasDefault) {
+				b.WriteString(env)
+			} else if hasDefault {
+				b.WriteString(fallback)
+			} else {
+				undefined(name)
+			}
+			i += 2 + end
+			continue
+		}
+		b.WriteByte('$')
+	}
+	return b.String()
+}
+
+// isVariableName reports whether name is a valid environment variable name
+func isVariableName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r == '_', r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
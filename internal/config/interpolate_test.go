@@ -0,0 +1,157 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mapLookup looks variables up in env
+func mapLookup(env map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+}
+
+func TestExpandVariables(t *testing.T) {
+	env := map[string]string{"REPO": "acme/tools", "EMPTY": "", "CHANNEL": "beta"}
+
+	tests := []struct {
+		value     string
+		want      string
+		undefined []string
+	}{
+		{"github.com/${REPO}", "github.com/acme/tools", nil},
+		{"${CHANNEL}-${REPO}", "beta-acme/tools", nil},
+		{"${MISSING:-stable}", "stable", nil},
+		{"${EMPTY:-fallback}", "fallback", nil},
+		{"${EMPTY}", "", nil},
+		{"${CHANNEL:-stable}", "beta", nil},
+		{"${MISSING:-}", "", nil},
+		{"v${MISSING}", "v", []string{"MISSING"}},
+		{"$${REPO}", "${REPO}", nil},
+		{"cost: $$5", "cost: $5", nil},
+		{"$$$${REPO}", "$${REPO}", nil},
+		{"$REPO and $", "$REPO and $", nil},
+		{"${REPO", "${REPO", nil},
+		{"${9LIVES} ${a-b}", "${9LIVES} ${a-b}", nil},
+		{"{{ .Version }}", "{{ .Version }}", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			var undefined []string
+			got := expandVariables(tt.value, mapLookup(env), func(name string) {
+				undefined = append(undefined, name)
+			})
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.undefined, undefined)
+		})
+	}
+}
+
+func TestConfig_Interpolate(t *testing.T) {
+	env := map[string]string{"OWNER": "acme", "REGISTRY": "registry.example.com", "PKG_DIR": "services"}
+
+	cfg := &Config{
+		GitHub: GitHubConfig{Owner: "${OWNER}", Repo: "${REPO:-tools}"},
+		Packages: []Package{{
+			Name:      "api",
+			Path:      "./${PKG_DIR}/api",
+			Ecosystem: "npm",
+			Dependencies: []Dependency{{
+				Package:     "core",
+				BumpMapping: map[string]string{"major": "${API_BUMP:-minor}"},
+			}},
+			Templates: &TemplateConfig{TagName: &TemplateSource{Inline: "api-v{{ .Version }}-${CHANNEL:-stable}"}},
+			Options:   map[string]interface{}{"registry": "https://${REGISTRY}", "scopes": []interface{}{"@${OWNER}"}, "retries": 3},
+		}},
+		ChangeTypes: []ChangeTypeConfig{{Name: "minor", Section: "${SECTION:-Features}"}},
+		Rules:       map[string]string{"message-size": "${SIZE_LEVEL:-warn}"},
+	}
+
+	require.NoError(t, cfg.Interpolate(mapLookup(env)))
+
+	assert.Equal(t, "acme", cfg.GitHub.Owner)
+	assert.Equal(t, "tools", cfg.GitHub.Repo)
+	assert.Equal(t, "./services/api", cfg.Packages[0].Path)
+	assert.Equal(t, map[string]string{"major": "minor"}, cfg.Packages[0].Dependencies[0].BumpMapping)
+	assert.Equal(t, "api-v{{ .Version }}-stable", cfg.Packages[0].Templates.TagName.Inline)
+	assert.Equal(t, "https://registry.example.com", cfg.Packages[0].Options["registry"])
+	assert.Equal(t, []interface{}{"@acme"}, cfg.Packages[0].Options["scopes"])
+	assert.Equal(t, 3, cfg.Packages[0].Options["retries"])
+	assert.Equal(t, "Features", cfg.ChangeTypes[0].Section)
+	assert.Equal(t, "warn", cfg.Rules["message-size"])
+}
+
+func TestConfig_Interpolate_Strict(t *testing.T) {
+	cfg := &Config{
+		Interpolation: InterpolationConfig{Strict: true},
+		GitHub:        GitHubConfig{Repo: "${REPO}", Owner: "${OWNER:-acme}"},
+		Packages:      []Package{{Name: "api", Path: "./${DIR}"}},
+	}
+
+	err := cfg.Interpolate(mapLookup(nil))
+	var undefined *UndefinedVariablesError
+	require.ErrorAs(t, err, &undefined)
+	assert.Equal(t, []string{"DIR (packages[0].path)", "REPO (github.repo)"}, undefined.References)
+	assert.Contains(t, err.Error(), "undefined environment variables in config: DIR (packages[0].path), REPO (github.repo)")
+}
+
+func TestConfig_Interpolate_Disabled(t *testing.T) {
+	cfg := &Config{
+		Interpolation: InterpolationConfig{Disabled: true, Strict: true},
+		GitHub:        GitHubConfig{Repo: "${REPO}"},
+	}
+
+	require.NoError(t, cfg.Interpolate(mapLookup(map[string]string{"REPO": "tools"})))
+	assert.Equal(t, "${REPO}", cfg.GitHub.Repo)
+}
+
+func TestLoadFromDir_Interpolation(t *testing.T) {
+	t.Setenv("SHIPYARD_TEST_OWNER", "acme")
+
+	t.Run("expanded", func(t *testing.T) {
+		dir := writeProjectConfig(t, "github:\n  owner: ${SHIPYARD_TEST_OWNER}\n  repo: ${SHIPYARD_TEST_REPO:-tools}\n")
+		cfg, err := LoadFromDir(dir)
+		require.NoError(t, err)
+		assert.Equal(t, "acme", cfg.GitHub.Owner)
+		assert.Equal(t, "tools", cfg.GitHub.Repo)
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		dir := writeProjectConfig(t, "interpolation:\n  strict: true\ngithub:\n  repo: ${SHIPYARD_TEST_REPO}\n")
+		_, err := LoadFromDir(dir)
+		var undefined *UndefinedVariablesError
+		require.ErrorAs(t, err, &undefined)
+		assert.Equal(t, []string{"SHIPYARD_TEST_REPO (github.repo)"}, undefined.References)
+	})
+
+	t.Run("hooks left for the shell", func(t *testing.T) {
+		dir := t.TempDir()
+		writeConfigFiles(t, dir, map[string]string{"shipyard.yaml": `interpolation:
+  strict: true
+hooks:
+  preVersion: ["echo ${SHIPYARD_NEW_VERSION}"]
+packages:
+  - name: core
+    path: ./
+    ecosystem: go
+    hooks:
+      postVersion: ["make release VERSION=${SHIPYARD_NEW_VERSION:-dev}"]
+`})
+		cfg, err := LoadFromDir(dir)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"echo ${SHIPYARD_NEW_VERSION}"}, cfg.Hooks.PreVersion)
+		require.NotNil(t, cfg.Packages[0].Hooks)
+		assert.Equal(t, []string{"make release VERSION=${SHIPYARD_NEW_VERSION:-dev}"}, cfg.Packages[0].Hooks.PostVersion)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		dir := writeProjectConfig(t, "interpolation:\n  disabled: true\ngithub:\n  owner: ${SHIPYARD_TEST_OWNER}\n")
+		cfg, err := LoadFromDir(dir)
+		require.NoError(t, err)
+		assert.Equal(t, "${SHIPYARD_TEST_OWNER}", cfg.GitHub.Owner)
+	})
+}
@@ -1,7 +1,9 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 
 	"github.com/NatoNathan/shipyard/internal/fileutil"
@@ -21,10 +23,15 @@ func Load(configPath string) (*Config, error) {
 		return nil, fmt.Errorf("failed to read config: %w", err)
 	}
 
-	// Unmarshal into Config struct
-	var cfg Config
-	if err := v.Unmarshal(&cfg); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	// Unmarshal into Config struct, refusing configs for a newer shipyard
+	cfg, _, err := decodeConfig(v)
+	if err != nil {
+		return nil, err
+	}
+
+	// Expand package globs against the project the config belongs to
+	if err := cfg.ExpandPackageGlobs(projectRootForConfig(configPath)); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
 	}
 
 	// Apply defaults
@@ -42,6 +49,13 @@ func Load(configPath string) (*Config, error) {
 // It looks for shipyard.yaml, shipyard.yml, shipyard.json, or shipyard.toml
 // First checks .shipyard/ subdirectory, then the root directory
 func LoadFromDir(dir string) (*Config, error) {
+	cfg, _, err := LoadFromDirWithOrigins(dir)
+	return cfg, err
+}
+
+// LoadFromDirWithOrigins loads the configuration from a directory like
+// LoadFromDir, and reports where each top-level key's value came from
+func LoadFromDirWithOrigins(dir string) (*Config, Origins, error) {
 	v := viper.New()
 
 	v.SetConfigName("shipyard")
@@ -52,21 +66,85 @@ func LoadFromDir(dir string) (*Config, error) {
 	v.SetConfigType("yaml") // Will auto-detect format
 
 	if err := v.ReadInConfig(); err != nil {
-		return nil, fmt.Errorf("failed to read config from %s: %w", dir, err)
+		return nil, nil, fmt.Errorf("failed to read config from %s: %w", dir, err)
 	}
 
-	var cfg Config
-	if err := v.Unmarshal(&cfg); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	cfg, origins, err := decodeConfig(v)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := cfg.ExpandPackageGlobs(dir); err != nil {
+		return nil, nil, fmt.Errorf("invalid config: %w", err)
 	}
 
 	result := cfg.WithDefaults()
 
 	if err := result.Validate(); err != nil {
-		return nil, fmt.Errorf("invalid config: %w", err)
+		return nil, nil, fmt.Errorf("invalid config: %w", err)
+	}
+	if err := ValidatePackagePaths(result, dir); err != nil {
+		return nil, nil, fmt.Errorf("invalid config: %w", err)
 	}
 
-	return result, nil
+	if err := origins.addDefaults(result); err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve config origins: %w", err)
+	}
+
+	return result, origins, nil
+}
+
+// decodeConfig unmarshals the config viper read, merged over the configs it
+// extends. Configs written for a newer shipyard are refused before they can
+// be misread, and older config shapes are migrated in memory with a note on
+// stderr.
+func decodeConfig(v *viper.Viper) (*Config, Origins, error) {
+	path := v.ConfigFileUsed()
+	if err := checkCompatibility(v.GetString("minShipyardVersion"), topLevelKeys(v)); err != nil {
+		return nil, nil, err
+	}
+
+	settings, result, err := MigrateSettings(v.AllSettings())
+	var schemaErr *SchemaVersionError
+	if errors.As(err, &schemaErr) {
+		return nil, nil, err
+	} else if err != nil {
+		return nil, nil, fmt.Errorf("failed to migrate config: %w", err)
+	}
+	if result.Changed() {
+		noteMigration(path, result)
+	}
+
+	// The config file's own remote settings apply to fetching its bases
+	var remote RemoteSettings
+	if err := v.UnmarshalKey("remote", &remote); err != nil {
+		return nil, nil, fmt.Errorf("failed to read remote settings: %w", err)
+	}
+
+	// Bases are merged first so the config file's own values win
+	layers, err := resolveExtends(settings, path, remote.Options(projectRootForConfig(path), os.Getenv))
+	if err != nil {
+		return nil, nil, err
+	}
+	merged, origins := mergeLayers(append(layers, configLayer{origin: path, settings: settings}))
+	if result.Changed() || len(layers) > 0 {
+		v = viper.New()
+		if err := v.MergeConfigMap(merged); err != nil {
+			return nil, nil, fmt.Errorf("failed to read merged config: %w", err)
+		}
+	}
+
+	var cfg Config
+	if err := v.Unmarshal(&cfg); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+
+	// ${VAR} references are expanded once the layers are merged, so a base
+	// can refer to variables each project sets
+	if err := cfg.Interpolate(os.LookupEnv); err != nil {
+		return nil, nil, err
+	}
+	return &cfg, origins, nil
 }
 
 // FindConfig searches for a shipyard config file in the current directory
@@ -103,6 +181,26 @@ func FindConfig(startDir string) (string, error) {
 	return "", fmt.Errorf("shipyard config not found in %s or parent directories", startDir)
 }
 
+// FindProjectRoot returns the nearest directory at or above startDir with a
+// config LoadFromDir would read, the way git finds the repository from a
+// subdirectory
+func FindProjectRoot(startDir string) (string, error) {
+	dir, err := filepath.Abs(startDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", startDir, err)
+	}
+	for {
+		if _, err := FindConfigFile(dir); err == nil {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("shipyard config not found in %s or parent directories", startDir)
+		}
+		dir = parent
+	}
+}
+
 func fileExists(path string) bool {
 	return fileutil.PathExists(path)
 }
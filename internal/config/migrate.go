@@ -0,0 +1,445 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/NatoNathan/shipyard/internal/fileutil"
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentSchemaVersion is the config shape this binary reads. Configs
+// without a schemaVersion are treated as schema 1 and migrated.
+const CurrentSchemaVersion = 3
+
+// schemaVersionKey is the top-level key holding a config's schema version
+const schemaVersionKey = "schemaVersion"
+
+// SchemaVersionError reports a config written for a newer schema than this
+// binary can read
+type SchemaVersionError struct {
+	Version   int // The config's schemaVersion
+	Supported int // The newest schema this binary reads
+}
+
+func (e *SchemaVersionError) Error() string {
+	return fmt.Sprintf("config schema version %d is newer than this shipyard supports (%d); run `shipyard upgrade` to update",
+		e.Version, e.Supported)
+}
+
+// migrationOpKind is the kind of edit a migration makes
+type migrationOpKind int
+
+const (
+	opRename migrationOpKind = iota // Rename the key at path to to, in place
+	opMove                          // Move the value at path to the dotted path to, whose "*" segments follow path's
+	opWrap                          // Replace a scalar at path with a mapping {to: scalar}
+)
+
+// migrationOp is one edit of a migration. Paths are dotted; a "*" segment
+// matches every element of a list.
+type migrationOp struct {
+	kind migrationOpKind
+	path string
+	to   string
+}
+
+// migration upgrades a config from schema from to from+1
+type migration struct {
+	from int
+	ops  []migrationOp
+}
+
+// migrations are applied in order to bring older configs to CurrentSchemaVersion
+var migrations = []migration{
+	{
+		// Schema 1 spelled multi-word keys in snake_case
+		from: 1,
+		ops: []migrationOp{
+			{opRename, "min_shipyard_version", "minShipyardVersion"},
+			{opRename, "change_types", "changeTypes"},
+			{opRename, "release_schedule", "releaseSchedule"},
+			{opRename, "pre_release", "prerelease"},
+			{opRename, "templates.tag_name", "tagName"},
+			{opRename, "templates.release_notes", "releaseNotes"},
+			{opRename, "templates.commit_message", "commitMessage"},
+			{opRename, "templates.allow_html", "allowHtml"},
+			{opRename, "templates.max_message_bytes", "maxMessageBytes"},
+			{opRename, "changelog.exclude_types", "excludeTypes"},
+			{opRename, "changelog.required_metadata", "requiredMetadata"},
+			{opRename, "changelog.notes_heading", "notesHeading"},
+			{opRename, "changelog.section_order", "sectionOrder"},
+			{opRename, "history.embed_config", "embedConfig"},
+			{opRename, "history.lock_timeout", "lockTimeout"},
+			{opRename, "packages.*.version_files", "versionFiles"},
+			{opRename, "packages.*.changelog_template", "changelogTemplate"},
+			{opRename, "packages.*.versioning_scheme", "versioningScheme"},
+			{opRename, "packages.*.calver_format", "calverFormat"},
+			{opRename, "packages.*.dependencies.*.bump_mapping", "bumpMapping"},
+		},
+	},
+	{
+		// Schema 2 named templates with plain strings, and the changelog
+		// template under changelog
+		from: 2,
+		ops: []migrationOp{
+			{opMove, "changelog.template", "templates.changelog"},
+			{opMove, "packages.*.changelog.template", "packages.*.changelogTemplate"},
+			{opWrap, "templates.changelog", "source"},
+			{opWrap, "templates.tagName", "source"},
+			{opWrap, "templates.releaseNotes", "source"},
+			{opWrap, "templates.commitMessage", "source"},
+			{opWrap, "packages.*.templates.changelog", "source"},
+			{opWrap, "packages.*.templates.tagName", "source"},
+			{opWrap, "packages.*.templates.releaseNotes", "source"},
+			{opWrap, "packages.*.templates.commitMessage", "source"},
+		},
+	},
+}
+
+// MigrationResult describes the migration of a config document
+type MigrationResult struct {
+	From    int      // Schema version the document declared; 0 when it declared none (read as schema 1)
+	Changes []string // One line per edit, e.g. "changelog.template -> templates.changelog"
+}
+
+// Changed reports whether migrating edited the document
+func (r MigrationResult) Changed() bool {
+	return len(r.Changes) > 0
+}
+
+// MigrateNode upgrades a YAML document to CurrentSchemaVersion in place,
+// keeping comments on the nodes it moves. schemaVersion is set to the
+// current version when anything changed or the document already declared one.
+func MigrateNode(doc *yaml.Node) (MigrationResult, error) {
+	root := doc
+	if root.Kind == yaml.DocumentNode {
+		if len(root.Content) == 0 {
+			return MigrationResult{From: CurrentSchemaVersion}, nil
+		}
+		root = root.Content[0]
+	}
+	if root.Kind != yaml.MappingNode {
+		return MigrationResult{}, fmt.Errorf("config must be a mapping")
+	}
+
+	var result MigrationResult
+	_, versionNode := mappingEntry(root, schemaVersionKey)
+	if versionNode != nil {
+		version, err := strconv.Atoi(versionNode.Value)
+		if err != nil || version < 1 {
+			return MigrationResult{}, fmt.Errorf("invalid %s %q: must be a positive integer", schemaVersionKey, versionNode.Value)
+		}
+		if version > CurrentSchemaVersion {
+			return MigrationResult{}, &SchemaVersionError{Version: version, Supported: CurrentSchemaVersion}
+		}
+		result.From = version
+	}
+
+	for _, m := range migrations {
+		if m.from < max(result.From, 1) {
+			continue
+		}
+		for _, op := range m.ops {
+			changes, err := op.apply(root)
+			if err != nil {
+				return MigrationResult{}, err
+			}
+			result.Changes = append(result.Changes, changes...)
+		}
+	}
+
+	if result.Changed() || versionNode != nil {
+		setSchemaVersion(root, CurrentSchemaVersion)
+	}
+	return result, nil
+}
+
+// MigrateSettings upgrades decoded config settings, such as viper's, to
+// CurrentSchemaVersion. Keys are matched case-insensitively.
+func MigrateSettings(settings map[string]interface{}) (map[string]interface{}, MigrationResult, error) {
+	var doc yaml.Node
+	if err := doc.Encode(settings); err != nil {
+		return nil, MigrationResult{}, fmt.Errorf("failed to read config for migration: %w", err)
+	}
+	result, err := MigrateNode(&doc)
+	if err != nil {
+		return nil, MigrationResult{}, err
+	}
+	if !result.Changed() {
+		return settings, result, nil
+	}
+
+	var migrated map[string]interface{}
+	if err := doc.Decode(&migrated); err != nil {
+		return nil, MigrationResult{}, fmt.Errorf("failed to read migrated config: %w", err)
+	}
+	return migrated, result, nil
+}
+
+// MigrateFile migrates the config file at path and returns its new content,
+// which always records schemaVersion. YAML files keep their comments; JSON
+// files are rewritten with sorted keys; other formats are not supported.
+func MigrateFile(path string) ([]byte, MigrationResult, error) {
+	data, err := fileutil.ReadFile(path)
+	if err != nil {
+		return nil, MigrationResult{}, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext != ".yaml" && ext != ".yml" && ext != ".json" {
+		return nil, MigrationResult{}, fmt.Errorf("cannot migrate %s: only YAML and JSON config files can be rewritten", filepath.Base(path))
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, MigrationResult{}, fmt.Errorf("failed to parse config: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	result, err := MigrateNode(&doc)
+	if err != nil {
+		return nil, MigrationResult{}, err
+	}
+	setSchemaVersion(doc.Content[0], CurrentSchemaVersion)
+
+	if ext == ".json" {
+		var value interface{}
+		if err := doc.Decode(&value); err != nil {
+			return nil, MigrationResult{}, fmt.Errorf("failed to encode config: %w", err)
+		}
+		out, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return nil, MigrationResult{}, fmt.Errorf("failed to encode config: %w", err)
+		}
+		return append(out, '\n'), result, nil
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, MigrationResult{}, fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, MigrationResult{}, fmt.Errorf("failed to encode config: %w", err)
+	}
+	return buf.Bytes(), result, nil
+}
+
+// migrationNotices receives the note printed when a config is migrated on
+// load; notes are dropped unless SetMigrationNotices is called
+var migrationNotices io.Writer = io.Discard
+
+// SetMigrationNotices sets where the note about a config migrated on load
+// is written. The CLI writes it to stderr; library callers get no output
+// unless they ask for it.
+func SetMigrationNotices(w io.Writer) {
+	migrationNotices = w
+}
+
+// noticedMigrations holds the config files already reported, so each is
+// reported once per run however often it is loaded
+var noticedMigrations sync.Map
+
+// noteMigration reports that the config at path was migrated in memory
+func noteMigration(path string, result MigrationResult) {
+	if _, seen := noticedMigrations.LoadOrStore(path, true); seen {
+		return
+	}
+	fmt.Fprintf(migrationNotices, "Note: %s uses config schema %d and was upgraded in memory to schema %d (%s); run `shipyard config migrate --write` to update the file\n",
+		path, max(result.From, 1), CurrentSchemaVersion, strings.Join(result.Changes, ", "))
+}
+
+// apply performs the operation on every match in root, returning a line per edit
+func (op migrationOp) apply(root *yaml.Node) ([]string, error) {
+	segments := strings.Split(op.path, ".")
+	parents := matchParents(root, segments[:len(segments)-1], "", nil)
+	key := segments[len(segments)-1]
+
+	var changes []string
+	for _, parent := range parents {
+		keyNode, value := mappingEntry(parent.node, key)
+		if keyNode == nil {
+			continue
+		}
+		from := joinPath(parent.path, keyNode.Value)
+
+		switch op.kind {
+		case opRename:
+			if existing, _ := mappingEntry(parent.node, op.to); existing != nil {
+				return nil, fmt.Errorf("config sets both %s and %s", from, joinPath(parent.path, op.to))
+			}
+			keyNode.Value = op.to
+			changes = append(changes, fmt.Sprintf("%s -> %s", from, joinPath(parent.path, op.to)))
+		case opMove:
+			toSegments := strings.Split(op.to, ".")
+			target, targetPath := ensureMapping(root, toSegments[:len(toSegments)-1], parent.indices)
+			to := joinPath(targetPath, toSegments[len(toSegments)-1])
+			if target == nil {
+				return nil, fmt.Errorf("cannot move %s to %s: %s is not a mapping", from, to, targetPath)
+			}
+			if existing, _ := mappingEntry(target, toSegments[len(toSegments)-1]); existing != nil {
+				return nil, fmt.Errorf("config sets both %s and %s", from, to)
+			}
+			removeMappingEntry(parent.node, keyNode)
+			if len(parent.node.Content) == 0 && parent.owner != nil {
+				removeMappingEntry(parent.owner, parent.ownerKey)
+			}
+			keyNode.Value = toSegments[len(toSegments)-1]
+			target.Content = append(target.Content, keyNode, value)
+			changes = append(changes, fmt.Sprintf("%s -> %s", from, to))
+		case opWrap:
+			if value.Kind != yaml.ScalarNode || value.Tag == "!!null" {
+				continue
+			}
+			scalar := *value
+			*value = yaml.Node{
+				Kind: yaml.MappingNode,
+				Tag:  "!!map",
+				Content: []*yaml.Node{
+					{Kind: yaml.ScalarNode, Tag: "!!str", Value: op.to},
+					&scalar,
+				},
+			}
+			changes = append(changes, fmt.Sprintf("%s -> %s.%s", from, from, op.to))
+		}
+	}
+	return changes, nil
+}
+
+// matchedNode is a node found by a path, with its display path, the list
+// index each "*" segment matched, and the mapping entry holding it
+type matchedNode struct {
+	node     *yaml.Node
+	path     string
+	indices  []int
+	owner    *yaml.Node
+	ownerKey *yaml.Node
+}
+
+// matchParents returns the mappings a path of segments leads to from node
+func matchParents(node *yaml.Node, segments []string, path string, indices []int) []matchedNode {
+	if len(segments) == 0 {
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+		return []matchedNode{{node: node, path: path, indices: indices}}
+	}
+	if len(segments) == 1 && segments[0] != "*" && node.Kind == yaml.MappingNode {
+		keyNode, value := mappingEntry(node, segments[0])
+		if keyNode == nil || value.Kind != yaml.MappingNode {
+			return nil
+		}
+		return []matchedNode{{node: value, path: joinPath(path, keyNode.Value), indices: indices, owner: node, ownerKey: keyNode}}
+	}
+
+	if segments[0] == "*" {
+		if node.Kind != yaml.SequenceNode {
+			return nil
+		}
+		var matches []matchedNode
+		for i, item := range node.Content {
+			itemIndices := append(append([]int{}, indices...), i)
+			matches = append(matches, matchParents(item, segments[1:], fmt.Sprintf("%s[%d]", path, i), itemIndices)...)
+		}
+		return matches
+	}
+
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	keyNode, value := mappingEntry(node, segments[0])
+	if keyNode == nil {
+		return nil
+	}
+	return matchParents(value, segments[1:], joinPath(path, keyNode.Value), indices)
+}
+
+// ensureMapping returns the mapping at the dotted segments below root and
+// its display path, creating empty mappings along the way. "*" segments take
+// the list elements given by indices, in order. The mapping is nil when a
+// value in the way is not a mapping.
+func ensureMapping(root *yaml.Node, segments []string, indices []int) (*yaml.Node, string) {
+	node := root
+	path := ""
+	for _, segment := range segments {
+		if segment == "*" {
+			if node.Kind != yaml.SequenceNode || len(indices) == 0 || indices[0] >= len(node.Content) {
+				return nil, path
+			}
+			path = fmt.Sprintf("%s[%d]", path, indices[0])
+			node, indices = node.Content[indices[0]], indices[1:]
+			continue
+		}
+		if node.Kind != yaml.MappingNode {
+			return nil, path
+		}
+
+		_, value := mappingEntry(node, segment)
+		if value == nil {
+			value = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: segment}, value)
+		} else if value.Tag == "!!null" {
+			value.Kind, value.Tag, value.Value = yaml.MappingNode, "!!map", ""
+		}
+		path = joinPath(path, segment)
+		node = value
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil, path
+	}
+	return node, path
+}
+
+// mappingEntry returns the key and value nodes of key in a mapping, matched
+// case-insensitively because viper lowercases keys
+func mappingEntry(mapping *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if strings.EqualFold(mapping.Content[i].Value, key) {
+			return mapping.Content[i], mapping.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+// removeMappingEntry removes the entry with keyNode from a mapping
+func removeMappingEntry(mapping, keyNode *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i] == keyNode {
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+// setSchemaVersion sets the schemaVersion of a config mapping, adding the key
+// first so it leads the file
+func setSchemaVersion(root *yaml.Node, version int) {
+	value := strconv.Itoa(version)
+	if _, existing := mappingEntry(root, schemaVersionKey); existing != nil {
+		existing.Kind, existing.Tag, existing.Value = yaml.ScalarNode, "!!int", value
+		return
+	}
+	key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: schemaVersionKey}
+	if len(root.Content) > 0 {
+		// A comment heading the file stays at the top
+		key.HeadComment, root.Content[0].HeadComment = root.Content[0].HeadComment, ""
+	}
+	root.Content = append([]*yaml.Node{key, {Kind: yaml.ScalarNode, Tag: "!!int", Value: value}}, root.Content...)
+}
+
+// joinPath appends a key to a dotted display path
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,181 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+var update = flag.Bool("update", false, "rewrite testdata/*.golden")
+
+// captureMigrationNotices collects load-time migration notices for the rest of the test
+func captureMigrationNotices(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	original := migrationNotices
+	SetMigrationNotices(&buf)
+	noticedMigrations = sync.Map{}
+	t.Cleanup(func() {
+		SetMigrationNotices(original)
+		noticedMigrations = sync.Map{}
+	})
+	return &buf
+}
+
+func TestMigrateFile_HistoricalShapes(t *testing.T) {
+	for _, name := range []string{"schema1", "schema2"} {
+		t.Run(name, func(t *testing.T) {
+			got, result, err := MigrateFile(filepath.Join("testdata", "migrate", name+".yaml"))
+			require.NoError(t, err)
+			assert.True(t, result.Changed())
+
+			golden := filepath.Join("testdata", "migrate", name+".golden")
+			if *update {
+				require.NoError(t, os.WriteFile(golden, got, 0644))
+			}
+			want, err := os.ReadFile(golden)
+			require.NoError(t, err)
+			assert.Equal(t, string(want), string(got))
+
+			// The migrated file is current and migrates no further
+			var doc yaml.Node
+			require.NoError(t, yaml.Unmarshal(got, &doc))
+			again, err := MigrateNode(&doc)
+			require.NoError(t, err)
+			assert.Equal(t, CurrentSchemaVersion, again.From)
+			assert.False(t, again.Changed())
+		})
+	}
+}
+
+func TestMigrateFile_Changes(t *testing.T) {
+	_, result, err := MigrateFile(filepath.Join("testdata", "migrate", "schema2.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.From)
+	assert.Equal(t, []string{
+		"changelog.template -> templates.changelog",
+		"packages[0].changelog.template -> packages[0].changelogTemplate",
+		"templates.changelog -> templates.changelog.source",
+		"templates.tagName -> templates.tagName.source",
+		"packages[0].templates.releaseNotes -> packages[0].templates.releaseNotes.source",
+	}, result.Changes)
+}
+
+func TestLoad_MigratesHistoricalShapes(t *testing.T) {
+	notices := captureMigrationNotices(t)
+
+	t.Run("schema1", func(t *testing.T) {
+		path := filepath.Join("testdata", "migrate", "schema1.yaml")
+		cfg, err := Load(path)
+		require.NoError(t, err)
+
+		assert.Equal(t, CurrentSchemaVersion, cfg.SchemaVersion)
+		assert.Equal(t, "0.1.0", cfg.MinShipyardVersion)
+		require.Len(t, cfg.Packages, 2)
+		assert.Equal(t, []string{"tag-only"}, cfg.Packages[0].VersionFiles)
+		assert.Equal(t, map[string]string{"major": "minor"}, cfg.Packages[1].Dependencies[0].BumpMapping)
+		require.Len(t, cfg.ChangeTypes, 1)
+		assert.Equal(t, "internal", cfg.ChangeTypes[0].Audience)
+		assert.Equal(t, []string{"patch"}, cfg.Changelog.ExcludeTypes)
+		assert.Equal(t, "builtin:keepachangelog", cfg.Templates.Changelog.Source)
+		assert.Equal(t, "builtin:go", cfg.Templates.TagName.Source)
+		assert.Equal(t, "45s", cfg.History.LockTimeout)
+
+		assert.Contains(t, notices.String(), path+" uses config schema 1 and was upgraded in memory to schema 3")
+		assert.Contains(t, notices.String(), "change_types -> changeTypes")
+		assert.Contains(t, notices.String(), "shipyard config migrate --write")
+	})
+
+	t.Run("schema2", func(t *testing.T) {
+		cfg, err := Load(filepath.Join("testdata", "migrate", "schema2.yaml"))
+		require.NoError(t, err)
+
+		assert.Equal(t, "./templates/changelog.tmpl", cfg.Templates.Changelog.Source)
+		assert.Equal(t, "builtin:npm", cfg.Templates.TagName.Source)
+		assert.Equal(t, "builtin:detailed", cfg.Templates.CommitMessage.Source)
+		assert.Equal(t, "Maintenance only", cfg.Changelog.Placeholder)
+		require.Len(t, cfg.Packages, 1)
+		assert.Equal(t, "builtin:keepachangelog", cfg.ChangelogTemplateFor("app"))
+		assert.Equal(t, "builtin:audience", cfg.Packages[0].Templates.ReleaseNotes.Source)
+	})
+
+	t.Run("notice is printed once per file", func(t *testing.T) {
+		notices.Reset()
+		_, err := Load(filepath.Join("testdata", "migrate", "schema2.yaml"))
+		require.NoError(t, err)
+		assert.Empty(t, notices.String())
+	})
+}
+
+func TestLoad_CurrentConfigIsNotMigrated(t *testing.T) {
+	notices := captureMigrationNotices(t)
+
+	for _, extra := range []string{"", "schemaVersion: 3\n"} {
+		dir := writeProjectConfig(t, extra+"templates:\n  changelog:\n    source: builtin:default\n")
+		cfg, err := LoadFromDir(dir)
+		require.NoError(t, err)
+		assert.Equal(t, "builtin:default", cfg.Templates.Changelog.Source)
+	}
+	assert.Empty(t, notices.String())
+}
+
+func TestLoad_RejectsNewerSchema(t *testing.T) {
+	dir := writeProjectConfig(t, "schemaVersion: 9\n")
+
+	_, err := LoadFromDir(dir)
+	var schemaErr *SchemaVersionError
+	require.True(t, errors.As(err, &schemaErr), "%v", err)
+	assert.Equal(t, 9, schemaErr.Version)
+	assert.Equal(t, CurrentSchemaVersion, schemaErr.Supported)
+	assert.Contains(t, err.Error(), "shipyard upgrade")
+
+	_, _, err = MigrateFile(filepath.Join(dir, ".shipyard", "shipyard.yaml"))
+	assert.True(t, errors.As(err, &schemaErr))
+}
+
+func TestMigrateNode_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"invalid version", "schemaVersion: two\n", `invalid schemaVersion "two"`},
+		{"old and new key", "change_types: []\nchangeTypes: []\n", "config sets both change_types and changeTypes"},
+		{"move onto existing", "schemaVersion: 2\nchangelog:\n  template: a\ntemplates:\n  changelog: b\n", "config sets both changelog.template and templates.changelog"},
+		{"not a mapping", "- a\n- b\n", "config must be a mapping"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var doc yaml.Node
+			require.NoError(t, yaml.Unmarshal([]byte(tt.content), &doc))
+			_, err := MigrateNode(&doc)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestMigrateFile_JSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shipyard.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"change_types": [{"name": "patch"}], "packages": []}`), 0644))
+
+	got, result, err := MigrateFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"change_types -> changeTypes"}, result.Changes)
+	assert.Equal(t, "{\n  \"changeTypes\": [\n    {\n      \"name\": \"patch\"\n    }\n  ],\n  \"packages\": [],\n  \"schemaVersion\": 3\n}\n", string(got))
+
+	toml := filepath.Join(t.TempDir(), "shipyard.toml")
+	require.NoError(t, os.WriteFile(toml, []byte("packages = []\n"), 0644))
+	_, _, err = MigrateFile(toml)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "only YAML and JSON")
+}
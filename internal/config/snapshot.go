@@ -0,0 +1,58 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Snapshot returns the canonical YAML form of the configuration.
+// The same configuration always produces the same snapshot.
+func (c *Config) Snapshot() (string, error) {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal config snapshot: %w", err)
+	}
+	return string(data), nil
+}
+
+// Hash returns the sha256 digest of the configuration's canonical YAML form,
+// formatted as "sha256:<hex>"
+func (c *Config) Hash() (string, error) {
+	snapshot, err := c.Snapshot()
+	if err != nil {
+		return "", err
+	}
+	return HashSnapshot(snapshot), nil
+}
+
+// HashSnapshot returns the digest of a snapshot produced by Config.Snapshot
+func HashSnapshot(snapshot string) string {
+	sum := sha256.Sum256([]byte(snapshot))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// FindConfigFile returns the path of the config file LoadFromDir reads for dir.
+// The .shipyard/ subdirectory is checked before the root directory.
+func FindConfigFile(dir string) (string, error) {
+	names := []string{
+		"shipyard.yaml",
+		"shipyard.yml",
+		"shipyard.json",
+		"shipyard.toml",
+	}
+
+	for _, base := range []string{filepath.Join(dir, ".shipyard"), dir} {
+		for _, name := range names {
+			configPath := filepath.Join(base, name)
+			if fileExists(configPath) {
+				return configPath, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("shipyard config not found in %s", dir)
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Hash(t *testing.T) {
+	writeConfig := func(t *testing.T, dir, content string) {
+		t.Helper()
+		shipyardDir := filepath.Join(dir, ".shipyard")
+		require.NoError(t, os.MkdirAll(shipyardDir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(shipyardDir, "shipyard.yaml"), []byte(content), 0644))
+	}
+	content := "packages:\n  - name: core\n    path: ./\n    ecosystem: go\n"
+
+	t.Run("stable for unchanged config", func(t *testing.T) {
+		dir := t.TempDir()
+		writeConfig(t, dir, content)
+
+		first, err := LoadFromDir(dir)
+		require.NoError(t, err)
+		second, err := LoadFromDir(dir)
+		require.NoError(t, err)
+
+		firstHash, err := first.Hash()
+		require.NoError(t, err)
+		secondHash, err := second.Hash()
+		require.NoError(t, err)
+
+		assert.Equal(t, firstHash, secondHash)
+		assert.True(t, strings.HasPrefix(firstHash, "sha256:"))
+	})
+
+	t.Run("changes when config changes", func(t *testing.T) {
+		dir := t.TempDir()
+		writeConfig(t, dir, content)
+		before, err := LoadFromDir(dir)
+		require.NoError(t, err)
+
+		writeConfig(t, dir, strings.Replace(content, "core", "api", 1))
+		after, err := LoadFromDir(dir)
+		require.NoError(t, err)
+
+		beforeHash, err := before.Hash()
+		require.NoError(t, err)
+		afterHash, err := after.Hash()
+		require.NoError(t, err)
+		assert.NotEqual(t, beforeHash, afterHash)
+	})
+}
+
+func TestFindConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	_, err := FindConfigFile(dir)
+	assert.Error(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "shipyard.yaml"), []byte("packages: []\n"), 0644))
+	path, err := FindConfigFile(dir)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "shipyard.yaml"), path)
+}
+
+func TestFindProjectRoot(t *testing.T) {
+	dir := t.TempDir()
+	nested := filepath.Join(dir, "packages", "core")
+	require.NoError(t, os.MkdirAll(nested, 0755))
+	_, err := FindProjectRoot(nested)
+	assert.Error(t, err)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".shipyard"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".shipyard", "shipyard.yaml"), []byte("packages: []\n"), 0644))
+	root, err := FindProjectRoot(nested)
+	require.NoError(t, err)
+	assert.Equal(t, dir, root)
+
+	// The nearest project wins over one further up
+	require.NoError(t, os.WriteFile(filepath.Join(nested, "shipyard.yaml"), []byte("packages: []\n"), 0644))
+	root, err = FindProjectRoot(nested)
+	require.NoError(t, err)
+	assert.Equal(t, nested, root)
+}
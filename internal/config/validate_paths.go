@@ -0,0 +1,57 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/NatoNathan/shipyard/internal/fileutil"
+)
+
+// ValidatePackagePaths checks that each configured package path matches the
+// casing of the directory on disk. A mismatch works on case-insensitive
+// filesystems (macOS, Windows) but breaks on Linux, so it is an error that
+// suggests the actual casing. Paths that do not exist are left to the
+// commands that read them.
+func ValidatePackagePaths(cfg *Config, projectPath string) error {
+	if cfg == nil {
+		return fmt.Errorf("cannot validate package paths: config is nil")
+	}
+
+	var errs []string
+	for _, pkg := range cfg.Packages {
+		if pkg.Path == "" || filepath.IsAbs(pkg.Path) {
+			continue
+		}
+		actual, err := fileutil.ActualCase(projectPath, pkg.Path)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return fmt.Errorf("failed to check path of package %q: %w", pkg.Name, err)
+		}
+		configured := filepath.Clean(filepath.FromSlash(pkg.Path))
+		if actual != configured {
+			errs = append(errs, fmt.Sprintf(
+				"package %q path %q does not match the directory casing on disk; use %q",
+				pkg.Name, pkg.Path, suggestPath(pkg.Path, actual)))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("package path validation failed:\n  - %s", strings.Join(errs, "\n  - "))
+	}
+	return nil
+}
+
+// suggestPath spells actual the way the configured path was written, keeping
+// a leading "./" and forward slashes
+func suggestPath(configured, actual string) string {
+	suggestion := filepath.ToSlash(actual)
+	if strings.HasPrefix(configured, "./") {
+		suggestion = "./" + suggestion
+	}
+	return suggestion
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePackagePaths(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "packages", "api"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "Tools", "CLI"), 0755))
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr string
+	}{
+		{name: "matching casing", path: "./packages/api"},
+		{name: "root", path: "./"},
+		{name: "missing path is left to other checks", path: "./packages/web"},
+		{name: "trailing slash", path: "packages/api/"},
+		{
+			name:    "mismatched casing",
+			path:    "./Packages/API",
+			wantErr: `package "api" path "./Packages/API" does not match the directory casing on disk; use "./packages/api"`,
+		},
+		{
+			name:    "mismatched casing without dot prefix",
+			path:    "tools/cli",
+			wantErr: `use "Tools/CLI"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Packages: []Package{{Name: "api", Path: tt.path}}}
+			err := ValidatePackagePaths(cfg, root)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+
+	t.Run("nil config", func(t *testing.T) {
+		assert.Error(t, ValidatePackagePaths(nil, root))
+	})
+}
+
+func TestLoadFromDir_PackagePathCase(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "packages", "api"), 0755))
+	content := "packages:\n  - name: api\n    path: ./Packages/API\n"
+	require.NoError(t, os.WriteFile(filepath.Join(root, "shipyard.yaml"), []byte(content), 0644))
+
+	_, err := LoadFromDir(root)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `use "./packages/api"`)
+}
@@ -1,7 +1,6 @@
 package consignment
 
 import (
-	"crypto/rand"
 	"fmt"
 	"strings"
 	"time"
@@ -13,32 +12,37 @@ import (
 type Consignment struct {
 	ID         string                 `yaml:"id"`
 	Timestamp  time.Time              `yaml:"timestamp"`
+	Sequence   int                    `yaml:"sequence,omitempty"`
 	Packages   []string               `yaml:"packages"`
 	ChangeType types.ChangeType       `yaml:"changeType"`
 	Summary    string                 `yaml:"-"` // Stored in markdown body
 	Metadata   map[string]interface{} `yaml:"metadata,omitempty"`
+
+	// PackageSummaries holds wording for individual packages, stored as
+	// "## <package>" sections after the summary in the markdown body.
+	// Packages without a section use Summary.
+	PackageSummaries map[string]string `yaml:"-"`
+
+	// File is the name of the file the consignment was read from. Files
+	// named by hand or by older tools may differ from the ID in their
+	// frontmatter, which is what identifies the consignment.
+	File string `yaml:"-" json:"-"`
+}
+
+// FileName returns the name of the consignment's file: the file it was
+// read from, or the ID's file for a new consignment
+func (c *Consignment) FileName() string {
+	if c.File != "" {
+		return c.File
+	}
+	return c.ID + ".md"
 }
 
 // GenerateIDFromTime generates a unique consignment ID from a timestamp
 // Format: YYYYMMDD-HHMMSS-{random6}
 // Deprecated: Use GenerateID(timestamp) instead
 func GenerateIDFromTime(timestamp time.Time) (string, error) {
-	// Format date and time components
-	dateTime := timestamp.Format("20060102-150405")
-
-	// Generate 6-character random alphanumeric string (lowercase)
-	randomBytes := make([]byte, 6)
-	if _, err := rand.Read(randomBytes); err != nil {
-		return "", fmt.Errorf("failed to generate random bytes: %w", err)
-	}
-
-	// Convert to lowercase alphanumeric (a-z, 0-9)
-	const charset = "abcdefghijklmnopqrstuvwxyz0123456789"
-	for i := range randomBytes {
-		randomBytes[i] = charset[int(randomBytes[i])%len(charset)]
-	}
-
-	return fmt.Sprintf("%s-%s", dateTime, string(randomBytes)), nil
+	return GenerateID(timestamp)
 }
 
 // New creates a new Consignment with generated ID and timestamp
@@ -68,6 +72,10 @@ func (c *Consignment) Validate() error {
 	if c.Timestamp.IsZero() {
 		return fmt.Errorf("timestamp is required")
 	}
+
+	if c.Sequence < 0 {
+		return fmt.Errorf("sequence must not be negative")
+	}
 	
 	if len(c.Packages) == 0 {
 		return fmt.Errorf("at least one package is required")
@@ -84,6 +92,15 @@ func (c *Consignment) Validate() error {
 	return nil
 }
 
+// SummaryFor returns the summary to use for a package: its own section of
+// the body when it has one, otherwise the shared summary
+func (c *Consignment) SummaryFor(packageName string) string {
+	if summary, ok := c.PackageSummaries[packageName]; ok {
+		return summary
+	}
+	return c.Summary
+}
+
 // AffectsPackage checks if this consignment affects the specified package
 func (c *Consignment) AffectsPackage(packageName string) bool {
 	for _, pkg := range c.Packages {
@@ -68,3 +68,47 @@ func DeleteConsignmentsByIDs(consignmentsDir string, ids []string) error {
 
 	return errors.Join(errs...)
 }
+
+// ErrNotFound is returned when no pending consignment has the given ID
+var ErrNotFound = errors.New("consignment not found")
+
+// RemoveConsignment deletes the pending consignment with the given ID. A
+// read-only file is refused rather than deleted, as rm would ask first.
+func RemoveConsignment(consignmentsDir, id string) error {
+	if err := ValidateID(id); err != nil {
+		return err
+	}
+	path := filepath.Join(consignmentsDir, id+".md")
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to remove consignment %s: %w", id, err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("failed to remove consignment %s: path is a directory", id)
+	}
+	if info.Mode().Perm()&0200 == 0 {
+		return fmt.Errorf("failed to remove consignment %s: file is read-only", id)
+	}
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to remove consignment %s: %w", id, err)
+	}
+	return nil
+}
+
+// RemoveConsignments deletes the pending consignments with the given IDs. It
+// removes every one it can and returns their IDs, with the errors for the rest.
+func RemoveConsignments(consignmentsDir string, ids []string) ([]string, error) {
+	var removed []string
+	var errs []error
+	for _, id := range ids {
+		if err := RemoveConsignment(consignmentsDir, id); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		removed = append(removed, id)
+	}
+	return removed, errors.Join(errs...)
+}
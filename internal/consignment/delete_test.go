@@ -214,3 +214,36 @@ func TestDeleteConsignments_PreserveUnrelated(t *testing.T) {
 	_, err = os.Stat(otherFile)
 	assert.NoError(t, err, "Unrelated file should still exist")
 }
+
+// TestRemoveConsignment_UnknownID tests removing an ID with no consignment file
+func TestRemoveConsignment_UnknownID(t *testing.T) {
+	tempDir := t.TempDir()
+
+	err := RemoveConsignment(tempDir, "20240101-120000-abc123")
+	require.ErrorIs(t, err, ErrNotFound)
+	assert.Contains(t, err.Error(), "20240101-120000-abc123")
+
+	assert.Error(t, RemoveConsignment(tempDir, "../shipyard"), "IDs are not paths")
+}
+
+// TestRemoveConsignments_PartialFailure tests that a read-only consignment is
+// kept while the other requested consignments are removed
+func TestRemoveConsignments_PartialFailure(t *testing.T) {
+	tempDir := t.TempDir()
+	for _, id := range []string{"c1", "c2", "c3"} {
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, id+".md"), []byte(id), 0644))
+	}
+	require.NoError(t, os.Chmod(filepath.Join(tempDir, "c2.md"), 0444))
+
+	removed, err := RemoveConsignments(tempDir, []string{"c1", "c2", "missing"})
+	assert.Equal(t, []string{"c1"}, removed)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "c2: file is read-only")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	assert.NoFileExists(t, filepath.Join(tempDir, "c1.md"))
+	assert.FileExists(t, filepath.Join(tempDir, "c2.md"))
+	content, err := os.ReadFile(filepath.Join(tempDir, "c3.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "c3", string(content), "unrelated consignments are untouched")
+}
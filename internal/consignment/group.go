@@ -75,7 +75,7 @@ func FilterConsignmentsByPackage(consignments []*Consignment, packageName string
 	return filtered
 }
 
-// SortConsignmentsByTimestamp returns a new slice sorted by timestamp (oldest first)
+// SortConsignmentsByTimestamp returns a new slice sorted by timestamp (oldest first), then sequence and ID
 // Does not modify the input slice
 func SortConsignmentsByTimestamp(consignments []*Consignment) []*Consignment {
 	// Create a copy to avoid modifying input
@@ -83,12 +83,25 @@ func SortConsignmentsByTimestamp(consignments []*Consignment) []*Consignment {
 	copy(sorted, consignments)
 
 	sort.Slice(sorted, func(i, j int) bool {
-		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+		return Less(sorted[i], sorted[j])
 	})
 
 	return sorted
 }
 
+// Less orders consignments by timestamp (oldest first), breaking ties by
+// sequence and then ID. Files written before sequences existed have none,
+// so they sort before sequenced ones created in the same second.
+func Less(a, b *Consignment) bool {
+	if !a.Timestamp.Equal(b.Timestamp) {
+		return a.Timestamp.Before(b.Timestamp)
+	}
+	if a.Sequence != b.Sequence {
+		return a.Sequence < b.Sequence
+	}
+	return a.ID < b.ID
+}
+
 // GroupConsignmentsByMetadataField groups consignments by a specific metadata field value
 // Returns a map where keys are the field values (as strings) and values are consignment slices
 func GroupConsignmentsByMetadataField(consignments []*Consignment, fieldName string) map[string][]*Consignment {
@@ -320,6 +320,48 @@ func TestSortConsignmentsByTimestamp(t *testing.T) {
 	assert.Equal(t, "c3", sorted[2].ID)
 }
 
+func TestSortConsignmentsByTimestamp_TiesOrderedByID(t *testing.T) {
+	now := time.Now()
+
+	consignments := []*Consignment{
+		{ID: "c-b", Timestamp: now},
+		{ID: "c-c", Timestamp: now},
+		{ID: "c-z", Timestamp: now.Add(-time.Minute)},
+		{ID: "c-a", Timestamp: now},
+	}
+
+	sorted := SortConsignmentsByTimestamp(consignments)
+
+	ids := make([]string, len(sorted))
+	for i, c := range sorted {
+		ids[i] = c.ID
+	}
+	assert.Equal(t, []string{"c-z", "c-a", "c-b", "c-c"}, ids)
+}
+
+func TestSortConsignmentsByTimestamp_TiesOrderedBySequence(t *testing.T) {
+	now := time.Now()
+
+	consignments := []*Consignment{
+		{ID: "c-a", Timestamp: now, Sequence: 3},
+		{ID: "c-z", Timestamp: now, Sequence: 1},
+		{ID: "c-m", Timestamp: now, Sequence: 2},
+		{ID: "legacy-b", Timestamp: now},
+		{ID: "legacy-a", Timestamp: now},
+		{ID: "c-y", Timestamp: now.Add(-time.Minute), Sequence: 9},
+	}
+
+	sorted := SortConsignmentsByTimestamp(consignments)
+
+	ids := make([]string, len(sorted))
+	for i, c := range sorted {
+		ids[i] = c.ID
+	}
+	// Timestamps come first, and consignments without a sequence sort
+	// before sequenced ones created in the same second
+	assert.Equal(t, []string{"c-y", "legacy-a", "legacy-b", "c-z", "c-m", "c-a"}, ids)
+}
+
 func TestGetUniquePackages(t *testing.T) {
 	now := time.Now()
 
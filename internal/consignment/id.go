@@ -3,7 +3,52 @@ package consignment
 import (
 	"crypto/rand"
 	"fmt"
+	"math/big"
+	"os"
+	"strings"
 	"time"
+	"unicode"
+)
+
+// idAlphabet is the alphabet of the random ID suffix. It is lowercase only,
+// so two generated IDs never differ just by case and collide as files on
+// case-insensitive filesystems.
+const idAlphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+// maxIDAttempts bounds how often UniqueID regenerates a colliding ID
+const maxIDAttempts = 10
+
+// ID styles, set with consignments.idStyle
+const (
+	IDStyleTimestamp   = "timestamp"    // 20260130-101500-k3x9qa
+	IDStyleSlug        = "slug"         // brave-otter
+	IDStyleSummarySlug = "summary-slug" // fix-crash-on-empty-query-k3x9
+)
+
+// summarySlugWords is how many words of the summary a summary-slug ID keeps
+const summarySlugWords = 5
+
+// maxSummarySlugLength bounds the summary part of a summary-slug ID
+const maxSummarySlugLength = 40
+
+// slugAdjectives and slugNouns make up slug IDs
+var (
+	slugAdjectives = []string{
+		"amber", "bold", "brave", "breezy", "bright", "brisk", "calm", "clever",
+		"crisp", "daring", "eager", "fair", "fleet", "gentle", "golden", "hardy",
+		"jolly", "keen", "kind", "lively", "loyal", "lucky", "mellow", "merry",
+		"misty", "nimble", "noble", "plucky", "proud", "quick", "quiet", "rapid",
+		"salty", "shiny", "silver", "sleek", "smooth", "snug", "spry", "steady",
+		"stout", "sturdy", "sunny", "swift", "tidal", "trusty", "witty", "zesty",
+	}
+	slugNouns = []string{
+		"albatross", "anchor", "barnacle", "beacon", "bosun", "breeze", "buoy", "capstan",
+		"compass", "coral", "cove", "current", "dinghy", "dolphin", "dory", "galleon",
+		"gull", "harbor", "helm", "inlet", "island", "jetty", "keel", "kelp",
+		"lagoon", "lantern", "mast", "narwhal", "otter", "oyster", "pelican", "pier",
+		"puffin", "reef", "rudder", "sail", "schooner", "seal", "sextant", "skiff",
+		"sloop", "starfish", "tide", "turtle", "voyage", "walrus", "whale", "wharf",
+	}
 )
 
 // GenerateID generates a unique consignment ID with format: YYYYMMDD-HHMMSS-random6
@@ -13,16 +58,185 @@ func GenerateID(timestamp time.Time) (string, error) {
 	dateTime := timestamp.Format("20060102-150405")
 
 	// Generate 6-character random alphanumeric string (lowercase)
-	randomBytes := make([]byte, 6)
+	suffix, err := randomSuffix(6)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s-%s", dateTime, suffix), nil
+}
+
+// UniqueID generates an ID whose file name matches no existing entry in dir,
+// compared case-insensitively
+func UniqueID(dir string, timestamp time.Time) (string, error) {
+	return NewIDGenerator(IDStyleTimestamp, dir).Generate(timestamp, "")
+}
+
+// IDGenerator generates consignment IDs in one style. Each ID is unique
+// among the files in its directory, compared case-insensitively, and among
+// the IDs the generator returned before, so a batch can be generated before
+// any of it is written.
+type IDGenerator struct {
+	style string
+	dir   string
+	taken map[string]bool
+}
+
+// NewIDGenerator returns a generator of IDs in style, one of the IDStyle
+// constants, for consignments written to dir. An empty or unknown style
+// generates timestamp IDs.
+func NewIDGenerator(style, dir string) *IDGenerator {
+	return &IDGenerator{style: style, dir: dir, taken: make(map[string]bool)}
+}
+
+// Generate returns an unused ID for a consignment with the given timestamp
+// and summary, regenerating colliding IDs
+func (g *IDGenerator) Generate(timestamp time.Time, summary string) (string, error) {
+	for attempt := 0; attempt < maxIDAttempts; attempt++ {
+		id, err := g.candidate(timestamp, summary, attempt)
+		if err != nil {
+			return "", err
+		}
+		if g.taken[id] {
+			continue
+		}
+		existing, err := findFileFold(g.dir, id+".md")
+		if err != nil {
+			return "", err
+		}
+		if existing == "" {
+			g.taken[id] = true
+			return id, nil
+		}
+	}
+	return "", fmt.Errorf("failed to generate a unique consignment ID after %d attempts", maxIDAttempts)
+}
+
+// candidate returns one ID in the generator's style. Slug IDs gain a random
+// suffix once half the attempts have collided, so a directory holding many
+// of the word pairs still gets a readable ID.
+func (g *IDGenerator) candidate(timestamp time.Time, summary string, attempt int) (string, error) {
+	switch g.style {
+	case IDStyleSlug:
+		return wordSlug(attempt >= maxIDAttempts/2)
+	case IDStyleSummarySlug:
+		slug := summarySlug(summary)
+		if slug == "" {
+			return wordSlug(true)
+		}
+		suffix, err := randomSuffix(4)
+		if err != nil {
+			return "", err
+		}
+		return slug + "-" + suffix, nil
+	}
+	return GenerateID(timestamp)
+}
+
+// wordSlug returns an adjective-noun pair, such as brave-otter, with a
+// random suffix when withSuffix is set
+func wordSlug(withSuffix bool) (string, error) {
+	adjective, err := randomIndex(len(slugAdjectives))
+	if err != nil {
+		return "", err
+	}
+	noun, err := randomIndex(len(slugNouns))
+	if err != nil {
+		return "", err
+	}
+	slug := slugAdjectives[adjective] + "-" + slugNouns[noun]
+	if !withSuffix {
+		return slug, nil
+	}
+	suffix, err := randomSuffix(4)
+	if err != nil {
+		return "", err
+	}
+	return slug + "-" + suffix, nil
+}
+
+// summarySlug returns the first words of a summary's first line, lowercased
+// and joined by '-'. Characters other than ASCII letters and digits separate
+// words. Returns "" when the summary has no such words.
+func summarySlug(summary string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(summary), "\n")
+	words := strings.FieldsFunc(strings.ToLower(line), func(r rune) bool {
+		return r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r))
+	})
+
+	var slug string
+	for i, word := range words {
+		if i == summarySlugWords {
+			break
+		}
+		next := word
+		if slug != "" {
+			next = slug + "-" + word
+		}
+		if len(next) > maxSummarySlugLength {
+			if slug == "" {
+				slug = word[:maxSummarySlugLength]
+			}
+			break
+		}
+		slug = next
+	}
+	return slug
+}
+
+// randomSuffix returns n random characters from idAlphabet
+func randomSuffix(n int) (string, error) {
+	randomBytes := make([]byte, n)
 	if _, err := rand.Read(randomBytes); err != nil {
 		return "", fmt.Errorf("failed to generate random bytes: %w", err)
 	}
-
-	// Convert to lowercase alphanumeric (a-z, 0-9)
-	const charset = "abcdefghijklmnopqrstuvwxyz0123456789"
 	for i := range randomBytes {
-		randomBytes[i] = charset[int(randomBytes[i])%len(charset)]
+		randomBytes[i] = idAlphabet[int(randomBytes[i])%len(idAlphabet)]
+	}
+	return string(randomBytes), nil
+}
+
+// randomIndex returns a random index below n
+func randomIndex(n int) (int, error) {
+	i, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to generate random index: %w", err)
 	}
+	return int(i.Int64()), nil
+}
 
-	return fmt.Sprintf("%s-%s", dateTime, string(randomBytes)), nil
+// ValidateID checks that id is safe as a consignment file name on every
+// filesystem: lowercase letters, digits, '-' and '_' only
+func ValidateID(id string) error {
+	if id == "" {
+		return fmt.Errorf("consignment ID is required")
+	}
+	for _, r := range id {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' && r != '_' {
+			return fmt.Errorf("invalid consignment ID %q: use lowercase letters, digits, '-' and '_' so IDs stay distinct on case-insensitive filesystems", id)
+		}
+	}
+	return nil
+}
+
+// findFileFold returns the entry in dir whose name equals name ignoring case,
+// preferring an exact match, or "" when there is none
+func findFileFold(dir, name string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read consignments directory: %w", err)
+	}
+	match := ""
+	for _, entry := range entries {
+		if entry.Name() == name {
+			return name, nil
+		}
+		if match == "" && strings.EqualFold(entry.Name(), name) {
+			match = entry.Name()
+		}
+	}
+	return match, nil
 }
@@ -1,7 +1,10 @@
 package consignment
 
 import (
+	"os"
+	"path/filepath"
 	"regexp"
+	"strings"
 	"testing"
 	"time"
 
@@ -97,3 +100,105 @@ func TestGenerateID_Consistency(t *testing.T) {
 	// But random components should be different
 	assert.NotEqual(t, id1[16:22], id2[16:22], "Random components should be different")
 }
+
+// TestValidateID tests that only case-insensitive-safe IDs are accepted
+func TestValidateID(t *testing.T) {
+	for _, id := range []string{"20260130-143022-a1b2c3", "c1", "fix_bug-2"} {
+		assert.NoError(t, ValidateID(id), id)
+	}
+	for _, id := range []string{"", "20260130-143022-A1b2c3", "../escape", "with space"} {
+		assert.Error(t, ValidateID(id), id)
+	}
+}
+
+// TestUniqueID_AvoidsCaseInsensitiveCollisions tests that UniqueID never
+// returns an ID whose file exists in any casing
+func TestUniqueID_AvoidsCaseInsensitiveCollisions(t *testing.T) {
+	dir := t.TempDir()
+	timestamp := time.Date(2026, 1, 30, 14, 30, 22, 0, time.UTC)
+
+	id, err := UniqueID(dir, timestamp)
+	require.NoError(t, err)
+	assert.NoError(t, ValidateID(id))
+
+	existing, err := findFileFold(dir, id+".md")
+	require.NoError(t, err)
+	assert.Empty(t, existing)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, strings.ToUpper(id)+".md"), []byte("x"), 0644))
+	existing, err = findFileFold(dir, id+".md")
+	require.NoError(t, err)
+	assert.Equal(t, strings.ToUpper(id)+".md", existing)
+}
+
+func TestIDGenerator_Styles(t *testing.T) {
+	timestamp := time.Date(2026, 1, 30, 14, 30, 22, 0, time.UTC)
+
+	tests := []struct {
+		style   string
+		summary string
+		pattern string
+	}{
+		{style: "", pattern: `^20260130-143022-[a-z0-9]{6}$`},
+		{style: IDStyleTimestamp, pattern: `^20260130-143022-[a-z0-9]{6}$`},
+		{style: IDStyleSlug, pattern: `^[a-z]+-[a-z]+$`},
+		{style: IDStyleSummarySlug, summary: "Fix crash on empty search query\n\nDetails.", pattern: `^fix-crash-on-empty-search-[a-z0-9]{4}$`},
+		{style: IDStyleSummarySlug, summary: "¡Añadir búsqueda!", pattern: `^a-adir-b-squeda-[a-z0-9]{4}$`},
+		{style: IDStyleSummarySlug, summary: "🚀", pattern: `^[a-z]+-[a-z]+-[a-z0-9]{4}$`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.style+" "+tt.summary, func(t *testing.T) {
+			id, err := NewIDGenerator(tt.style, t.TempDir()).Generate(timestamp, tt.summary)
+			require.NoError(t, err)
+			assert.Regexp(t, regexp.MustCompile(tt.pattern), id)
+			assert.NoError(t, ValidateID(id))
+		})
+	}
+}
+
+func TestSummarySlug(t *testing.T) {
+	tests := map[string]string{
+		"Add OAuth2 support":                         "add-oauth2-support",
+		"  fix: handle `nil` config (#42)  ":         "fix-handle-nil-config-42",
+		"One two three four five six seven":          "one-two-three-four-five",
+		"Refactor internationalization localization": "refactor-internationalization",
+		strings.Repeat("x", 50):                      strings.Repeat("x", 40),
+		"...":                                        "",
+	}
+	for summary, expected := range tests {
+		assert.Equal(t, expected, summarySlug(summary), summary)
+	}
+}
+
+func TestIDGenerator_SlugCollisions(t *testing.T) {
+	adjectives, nouns := slugAdjectives, slugNouns
+	slugAdjectives, slugNouns = []string{"brave"}, []string{"otter"}
+	t.Cleanup(func() { slugAdjectives, slugNouns = adjectives, nouns })
+	timestamp := time.Date(2026, 1, 30, 14, 30, 22, 0, time.UTC)
+	suffixed := regexp.MustCompile(`^brave-otter-[a-z0-9]{4}$`)
+
+	t.Run("free name is used as is", func(t *testing.T) {
+		id, err := NewIDGenerator(IDStyleSlug, t.TempDir()).Generate(timestamp, "")
+		require.NoError(t, err)
+		assert.Equal(t, "brave-otter", id)
+	})
+
+	t.Run("existing file gets a suffix", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "Brave-Otter.md"), []byte("x"), 0644))
+		id, err := NewIDGenerator(IDStyleSlug, dir).Generate(timestamp, "")
+		require.NoError(t, err)
+		assert.Regexp(t, suffixed, id)
+	})
+
+	t.Run("IDs generated in a batch are distinct", func(t *testing.T) {
+		ids := NewIDGenerator(IDStyleSlug, t.TempDir())
+		first, err := ids.Generate(timestamp, "")
+		require.NoError(t, err)
+		second, err := ids.Generate(timestamp, "")
+		require.NoError(t, err)
+		assert.Equal(t, "brave-otter", first)
+		assert.Regexp(t, suffixed, second)
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 
@@ -30,6 +31,9 @@ func ReadConsignment(path string) (*Consignment, error) {
 		return nil, fmt.Errorf("consignment file is empty: %s", path)
 	}
 
+	// Files edited on Windows may carry a BOM and CRLF line endings
+	content = []byte(fileutil.NormalizeNewlines(string(content)))
+
 	// Parse markdown with frontmatter using goldmark
 	md := goldmark.New(
 		goldmark.WithExtensions(meta.Meta),
@@ -64,16 +68,19 @@ func ReadConsignment(path string) (*Consignment, error) {
 
 	// Validate required fields
 	if c.ID == "" {
-		return nil, fmt.Errorf("missing required field: id")
+		return nil, &FieldError{Field: "id", Message: "missing required field: id"}
 	}
 	if len(c.Packages) == 0 {
-		return nil, fmt.Errorf("missing required field: packages")
+		return nil, &FieldError{Field: "packages", Message: "missing required field: packages"}
 	}
 	if c.ChangeType == "" {
-		return nil, fmt.Errorf("missing required field: changeType")
+		return nil, &FieldError{Field: "changeType", Message: "missing required field: changeType"}
 	}
 	if c.Timestamp.IsZero() {
-		return nil, fmt.Errorf("missing or invalid required field: timestamp")
+		return nil, &FieldError{Field: "timestamp", Message: "missing or invalid required field: timestamp"}
+	}
+	if c.Sequence < 0 {
+		return nil, &FieldError{Field: "sequence", Message: fmt.Sprintf("invalid sequence: %d (must not be negative)", c.Sequence)}
 	}
 
 	// Validate changeType enum
@@ -83,20 +90,77 @@ func ReadConsignment(path string) (*Consignment, error) {
 		types.ChangeTypeMajor: true,
 	}
 	if !validTypes[c.ChangeType] {
-		return nil, fmt.Errorf("invalid changeType: %s (must be patch, minor, or major)", c.ChangeType)
+		return nil, &FieldError{Field: "changeType", Message: fmt.Sprintf("invalid changeType: %s (must be patch, minor, or major)", c.ChangeType)}
 	}
 
 	// Extract markdown body (everything after frontmatter)
 	body := extractMarkdownBody(string(content))
-	c.Summary = strings.TrimSpace(body)
+	c.Summary, c.PackageSummaries = splitPackageSections(body, c.Packages)
 
 	if c.Summary == "" {
-		return nil, fmt.Errorf("consignment summary cannot be empty")
+		if len(c.PackageSummaries) > 0 {
+			return nil, &FieldError{Field: "summary", Message: "consignment summary cannot be empty: write the shared summary above the package sections"}
+		}
+		return nil, &FieldError{Field: "summary", Message: "consignment summary cannot be empty"}
 	}
+	c.File = filepath.Base(path)
 
 	return &c, nil
 }
 
+// FindConsignment reads the consignment with the given ID from dir. The ID
+// in a file's frontmatter identifies it, so a file named differently from
+// its ID is found too. Returns an error wrapping os.ErrNotExist when no
+// consignment has the ID.
+func FindConsignment(dir, id string) (*Consignment, error) {
+	path := filepath.Join(dir, id+".md")
+	if _, err := os.Stat(path); err == nil {
+		c, err := ReadConsignment(path)
+		if err != nil {
+			return nil, err
+		}
+		if c.ID == id {
+			return c, nil
+		}
+	}
+
+	consignments, _, err := ReadAllConsignmentsWithErrors(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range consignments {
+		if c.ID == id {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("consignment not found: %s: %w", id, os.ErrNotExist)
+}
+
+// NextSequence returns the sequence for a new consignment in dir: one more
+// than the highest sequence of the consignments already there, or 1 when
+// none has one. Files that fail to parse are ignored.
+func NextSequence(dir string) (int, error) {
+	consignments, _, err := ReadAllConsignmentsWithErrors(dir)
+	if err != nil {
+		return 0, err
+	}
+	highest := 0
+	for _, c := range consignments {
+		highest = max(highest, c.Sequence)
+	}
+	return highest + 1, nil
+}
+
+// FieldError reports an invalid or missing consignment field
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return e.Message
+}
+
 // ParseError represents a failure to parse a single consignment file
 type ParseError struct {
 	File    string
@@ -113,7 +177,7 @@ func (e *ParseError) Unwrap() error {
 }
 
 // ReadAllConsignments reads all consignment files from a directory
-// Returns a slice of Consignment structs sorted by timestamp (oldest first)
+// Returns a slice of Consignment structs sorted by timestamp (oldest first), then sequence and ID
 // Parse errors are logged to stderr but do not cause the function to fail
 func ReadAllConsignments(consignmentDir string) ([]*Consignment, error) {
 	consignments, parseErrors, err := ReadAllConsignmentsWithErrors(consignmentDir)
@@ -199,9 +263,10 @@ func readAllConsignmentsInternal(consignmentDir string, packageFilter []string)
 		consignments = append(consignments, c)
 	}
 
-	// Sort by timestamp (oldest first)
+	// Sort by timestamp (oldest first); sequences and IDs break ties so that
+	// consignments written in the same second keep a stable order across runs
 	sort.Slice(consignments, func(i, j int) bool {
-		return consignments[i].Timestamp.Before(consignments[j].Timestamp)
+		return Less(consignments[i], consignments[j])
 	})
 
 	return consignments, parseErrors, nil
@@ -228,6 +293,50 @@ func extractMarkdownBody(content string) string {
 	return content // Malformed frontmatter, return as-is
 }
 
+// splitPackageSections splits a consignment body at level-2 headings naming
+// one of its packages. The text before the first such heading is the shared
+// summary; each section is that package's own summary. Other headings, and
+// headings inside code fences, stay part of the text around them.
+func splitPackageSections(body string, packages []string) (string, map[string]string) {
+	var sections map[string]string
+	var current []string
+	currentPackage := ""
+	summary := ""
+	inFence := false
+
+	flush := func() {
+		text := strings.TrimSpace(strings.Join(current, "\n"))
+		if currentPackage == "" {
+			summary = text
+		} else if text != "" {
+			if sections == nil {
+				sections = make(map[string]string)
+			}
+			sections[currentPackage] = text
+		}
+		current = nil
+	}
+
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+		}
+		if !inFence && strings.HasPrefix(line, "## ") {
+			name := strings.TrimSpace(strings.TrimRight(strings.TrimPrefix(line, "## "), "# "))
+			if slices.Contains(packages, name) {
+				flush()
+				currentPackage = name
+				continue
+			}
+		}
+		current = append(current, line)
+	}
+	flush()
+
+	return summary, sections
+}
+
 // containsAnyPackage checks if any package in the consignment matches the filter
 func containsAnyPackage(consignmentPackages []string, filter []string) bool {
 	filterSet := make(map[string]bool)
@@ -3,8 +3,11 @@ package consignment
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/NatoNathan/shipyard/pkg/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -295,6 +298,24 @@ Content`
 	assert.Len(t, result, 2)
 }
 
+func TestReadConsignment_WindowsLineEndings(t *testing.T) {
+	// The fixture starts with a BOM and uses CRLF throughout, as Notepad saves it
+	c, err := ReadConsignment(filepath.Join("testdata", "windows-line-endings.md"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "20260130-143022-a1b2c3", c.ID)
+	assert.Equal(t, []string{"core"}, c.Packages)
+	assert.Equal(t, "Fix crash on startup\n\nEdited in Notepad.", c.Summary)
+
+	// Writing it back produces LF only
+	dir := t.TempDir()
+	require.NoError(t, WriteConsignment(c, dir))
+	written, err := os.ReadFile(filepath.Join(dir, c.FileName()))
+	require.NoError(t, err)
+	assert.NotContains(t, string(written), "\r")
+	assert.False(t, strings.HasPrefix(string(written), "\uFEFF"))
+}
+
 func TestReadConsignmentErrorHandling(t *testing.T) {
 	t.Run("nonexistent file", func(t *testing.T) {
 		_, err := ReadConsignment("/nonexistent/path.md")
@@ -315,4 +336,142 @@ func TestReadConsignmentErrorHandling(t *testing.T) {
 		_, err := ReadConsignment(filePath)
 		assert.Error(t, err)
 	})
+	t.Run("invalid field reports the field", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		filePath := filepath.Join(tmpDir, "bad.md")
+		content := "---\nid: \"x\"\ntimestamp: \"2026-01-30T14:30:22Z\"\npackages: [core]\nchangeType: huge\n---\n\nSummary\n"
+		require.NoError(t, os.WriteFile(filePath, []byte(content), 0644))
+
+		_, err := ReadConsignment(filePath)
+
+		var fieldErr *FieldError
+		require.ErrorAs(t, err, &fieldErr)
+		assert.Equal(t, "changeType", fieldErr.Field)
+		assert.Contains(t, err.Error(), "invalid changeType: huge")
+	})
+}
+
+func TestFindConsignment_FileNamedDifferentlyFromID(t *testing.T) {
+	dir := t.TempDir()
+	legacy := `---
+id: 20250101-120000-abc123
+timestamp: 2025-01-01T12:00:00Z
+packages:
+  - core
+changeType: patch
+---
+Fix typo
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "fix-typo.md"), []byte(legacy), 0644))
+	// A file named after one ID may hold another
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "brave-otter.md"), []byte(strings.Replace(legacy, "20250101-120000-abc123", "calm-seal", 1)), 0644))
+
+	all, err := ReadAllConsignments(dir)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	ids := []string{all[0].ID, all[1].ID}
+	assert.ElementsMatch(t, []string{"20250101-120000-abc123", "calm-seal"}, ids, "the frontmatter ID wins over the file name")
+
+	c, err := FindConsignment(dir, "20250101-120000-abc123")
+	require.NoError(t, err)
+	assert.Equal(t, "fix-typo.md", c.FileName())
+
+	c, err = FindConsignment(dir, "calm-seal")
+	require.NoError(t, err)
+	assert.Equal(t, "brave-otter.md", c.FileName())
+
+	_, err = FindConsignment(dir, "brave-otter")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	// Writing it back keeps the file name
+	c.Summary = "Fix typos"
+	require.NoError(t, WriteConsignment(c, dir))
+	assert.NoFileExists(t, filepath.Join(dir, "calm-seal.md"))
+	updated, err := ReadConsignment(filepath.Join(dir, "brave-otter.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "Fix typos", updated.Summary)
+}
+
+func TestNextSequence(t *testing.T) {
+	dir := t.TempDir()
+
+	next, err := NextSequence(filepath.Join(dir, "missing"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, next, "an empty directory starts at 1")
+
+	write := func(id string, sequence int) {
+		t.Helper()
+		require.NoError(t, WriteConsignment(&Consignment{
+			ID:         id,
+			Timestamp:  time.Date(2026, 1, 30, 14, 30, 22, 0, time.UTC),
+			Sequence:   sequence,
+			Packages:   []string{"core"},
+			ChangeType: types.ChangeTypePatch,
+			Summary:    "Change " + id,
+		}, dir))
+	}
+
+	write("legacy", 0)
+	next, err = NextSequence(dir)
+	require.NoError(t, err)
+	assert.Equal(t, 1, next, "files without a sequence count as 0")
+
+	write("b", 7)
+	write("a", 3)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.md"), []byte("not a consignment"), 0644))
+	next, err = NextSequence(dir)
+	require.NoError(t, err)
+	assert.Equal(t, 8, next)
+}
+
+func TestReadConsignment_Sequence(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "c.md")
+	content := "---\nid: c\ntimestamp: 2026-01-30T14:30:22Z\nsequence: 4\npackages: [core]\nchangeType: patch\n---\n\nSummary\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	c, err := ReadConsignment(path)
+	require.NoError(t, err)
+	assert.Equal(t, 4, c.Sequence)
+
+	require.NoError(t, os.WriteFile(path, []byte(strings.Replace(content, "sequence: 4", "sequence: -1", 1)), 0644))
+	_, err = ReadConsignment(path)
+	var fieldErr *FieldError
+	require.ErrorAs(t, err, &fieldErr)
+	assert.Equal(t, "sequence", fieldErr.Field)
+}
+
+func TestReadConsignment_PackageSections(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "c.md")
+	content := "---\nid: c\ntimestamp: 2026-01-30T14:30:22Z\npackages: [api, cli, web]\nchangeType: minor\n---\n\n" +
+		"Add project search\n\n## Details\n\nSearch matches names and descriptions.\n\n" +
+		"## api\n\nAdd the `/search` endpoint\n\n```md\n## cli\n```\n\n" +
+		"## cli\n\nAdd the `--search` flag\n\n" +
+		"## docs\n\nNot a package of this consignment\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	c, err := ReadConsignment(path)
+	require.NoError(t, err)
+	assert.Equal(t, "Add project search\n\n## Details\n\nSearch matches names and descriptions.", c.Summary)
+	assert.Equal(t, map[string]string{
+		"api": "Add the `/search` endpoint\n\n```md\n## cli\n```",
+		"cli": "Add the `--search` flag\n\n## docs\n\nNot a package of this consignment",
+	}, c.PackageSummaries)
+	assert.Equal(t, c.PackageSummaries["api"], c.SummaryFor("api"))
+	assert.Equal(t, c.Summary, c.SummaryFor("web"), "packages without a section use the shared summary")
+
+	// Writing the consignment back keeps its sections
+	require.NoError(t, WriteConsignment(c, dir))
+	reread, err := ReadConsignment(path)
+	require.NoError(t, err)
+	assert.Equal(t, c.Summary, reread.Summary)
+	assert.Equal(t, c.PackageSummaries, reread.PackageSummaries)
+
+	// A body of package sections alone has no shared summary
+	require.NoError(t, os.WriteFile(path, []byte("---\nid: c\ntimestamp: 2026-01-30T14:30:22Z\npackages: [api, cli]\nchangeType: minor\n---\n\n## api\n\nEndpoint\n"), 0644))
+	_, err = ReadConsignment(path)
+	var fieldErr *FieldError
+	require.ErrorAs(t, err, &fieldErr)
+	assert.Equal(t, "summary", fieldErr.Field)
 }
@@ -4,15 +4,18 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/NatoNathan/shipyard/internal/fileutil"
 	"gopkg.in/yaml.v3"
 )
 
-// Serialize converts a consignment to markdown with YAML frontmatter
+// Serialize converts a consignment to markdown with YAML frontmatter. The
+// output always uses LF line endings.
 func Serialize(cons *Consignment) (string, error) {
 	// Create a struct for frontmatter (excludes Summary)
 	type Frontmatter struct {
 		ID         string                 `yaml:"id"`
 		Timestamp  string                 `yaml:"timestamp"`
+		Sequence   int                    `yaml:"sequence,omitempty"`
 		Packages   []string               `yaml:"packages"`
 		ChangeType string                 `yaml:"changeType"`
 		Metadata   map[string]interface{} `yaml:"metadata,omitempty"`
@@ -21,6 +24,7 @@ func Serialize(cons *Consignment) (string, error) {
 	frontmatter := Frontmatter{
 		ID:         cons.ID,
 		Timestamp:  cons.Timestamp.Format("2006-01-02T15:04:05Z"),
+		Sequence:   cons.Sequence,
 		Packages:   cons.Packages,
 		ChangeType: string(cons.ChangeType),
 		Metadata:   cons.Metadata,
@@ -38,8 +42,19 @@ func Serialize(cons *Consignment) (string, error) {
 	builder.Write(yamlBytes)
 	builder.WriteString("---\n")
 	builder.WriteString("\n")
-	builder.WriteString(cons.Summary)
+	builder.WriteString(fileutil.NormalizeNewlines(cons.Summary))
 	builder.WriteString("\n")
 
+	// Package sections follow the summary in package order
+	for _, pkg := range cons.Packages {
+		summary, ok := cons.PackageSummaries[pkg]
+		if !ok || strings.TrimSpace(summary) == "" {
+			continue
+		}
+		builder.WriteString("\n## " + pkg + "\n\n")
+		builder.WriteString(fileutil.NormalizeNewlines(strings.TrimSpace(summary)))
+		builder.WriteString("\n")
+	}
+
 	return builder.String(), nil
 }
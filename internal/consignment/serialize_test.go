@@ -37,6 +37,42 @@ func TestSerialize_BasicConsignment(t *testing.T) {
 	assert.Contains(t, content, "Fixed a bug", "Should contain summary")
 }
 
+func TestSerialize_Sequence(t *testing.T) {
+	cons := &Consignment{
+		ID:         "20260130-143022-a1b2c3",
+		Timestamp:  time.Date(2026, 1, 30, 14, 30, 22, 0, time.UTC),
+		Packages:   []string{"core"},
+		ChangeType: types.ChangeTypePatch,
+		Summary:    "Fixed a bug",
+	}
+
+	content, err := Serialize(cons)
+	require.NoError(t, err)
+	assert.NotContains(t, content, "sequence:", "Files without a sequence keep their old frontmatter")
+
+	cons.Sequence = 12
+	content, err = Serialize(cons)
+	require.NoError(t, err)
+	assert.Contains(t, content, "sequence: 12")
+}
+
+// TestSerialize_NormalizesLineEndings tests that a summary entered with CRLF
+// line endings is written with LF
+func TestSerialize_NormalizesLineEndings(t *testing.T) {
+	cons := &Consignment{
+		ID:         "20260130-143022-a1b2c3",
+		Timestamp:  time.Date(2026, 1, 30, 14, 30, 22, 0, time.UTC),
+		Packages:   []string{"core"},
+		ChangeType: types.ChangeTypePatch,
+		Summary:    "Fixed a bug\r\n\r\nDetails",
+	}
+
+	content, err := Serialize(cons)
+	require.NoError(t, err)
+	assert.NotContains(t, content, "\r")
+	assert.True(t, strings.HasSuffix(content, "\n\nFixed a bug\n\nDetails\n"))
+}
+
 // TestSerialize_WithMetadata tests serializing a consignment with metadata
 func TestSerialize_WithMetadata(t *testing.T) {
 	cons := &Consignment{
@@ -0,0 +1,152 @@
+package consignment
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/NatoNathan/shipyard/pkg/types"
+)
+
+// SquashConflictError reports consignments with different change types.
+// Squashing them applies the highest type to every package in the result.
+type SquashConflictError struct {
+	ChangeTypes []types.ChangeType // distinct change types, in consignment order
+	Result      types.ChangeType   // change type the squashed consignment would have
+}
+
+func (e *SquashConflictError) Error() string {
+	names := make([]string, len(e.ChangeTypes))
+	for i, ct := range e.ChangeTypes {
+		names[i] = string(ct)
+	}
+	return fmt.Sprintf("consignments have different change types (%s); the squashed consignment would be %s for every package",
+		strings.Join(names, ", "), e.Result)
+}
+
+// SquashOptions controls how consignments are merged
+type SquashOptions struct {
+	Summary    string    // Summary line of the new consignment
+	Timestamp  time.Time // Timestamp of the new consignment; defaults to now
+	AllowMixed bool      // Allow consignments with different change types
+}
+
+// Squash merges consignments into one new consignment. Packages are the union
+// of the originals, the change type is the highest among them, and the
+// original summaries are listed under the new summary. Metadata values that
+// differ between consignments, such as authors, are merged into a list.
+func Squash(consignments []*Consignment, opts SquashOptions) (*Consignment, error) {
+	if len(consignments) < 2 {
+		return nil, fmt.Errorf("at least two consignments are required to squash")
+	}
+	if strings.TrimSpace(opts.Summary) == "" {
+		return nil, fmt.Errorf("summary is required")
+	}
+
+	var changeTypes []types.ChangeType
+	seenTypes := make(map[types.ChangeType]bool)
+	for _, c := range consignments {
+		if !seenTypes[c.ChangeType] {
+			seenTypes[c.ChangeType] = true
+			changeTypes = append(changeTypes, c.ChangeType)
+		}
+	}
+	changeType := GetHighestChangeType(consignments)
+	if len(changeTypes) > 1 && !opts.AllowMixed {
+		return nil, &SquashConflictError{ChangeTypes: changeTypes, Result: changeType}
+	}
+
+	var packages []string
+	seenPackages := make(map[string]bool)
+	for _, c := range consignments {
+		for _, pkg := range c.Packages {
+			if !seenPackages[pkg] {
+				seenPackages[pkg] = true
+				packages = append(packages, pkg)
+			}
+		}
+	}
+
+	var body strings.Builder
+	body.WriteString(strings.TrimSpace(opts.Summary))
+	body.WriteString("\n")
+	for _, c := range consignments {
+		lines := strings.Split(strings.TrimSpace(c.Summary), "\n")
+		body.WriteString("\n- " + lines[0])
+		for _, line := range lines[1:] {
+			if line == "" {
+				body.WriteString("\n")
+				continue
+			}
+			body.WriteString("\n  " + line)
+		}
+	}
+
+	timestamp := opts.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now().UTC()
+	}
+	id, err := GenerateID(timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate ID: %w", err)
+	}
+
+	return &Consignment{
+		ID:         id,
+		Timestamp:  timestamp,
+		Packages:   packages,
+		ChangeType: changeType,
+		Summary:    body.String(),
+		Metadata:   mergeMetadata(consignments),
+	}, nil
+}
+
+// mergeMetadata combines metadata from every consignment. A key keeps a single
+// value when all consignments agree; otherwise its distinct values, flattening
+// existing lists, are collected in consignment order. Keys that already hold a
+// list stay lists.
+func mergeMetadata(consignments []*Consignment) map[string]interface{} {
+	values := make(map[string][]interface{})
+	lists := make(map[string]bool)
+	var keys []string
+	for _, c := range consignments {
+		for key, value := range c.Metadata {
+			if _, ok := values[key]; !ok {
+				keys = append(keys, key)
+			}
+			items := []interface{}{value}
+			if list, ok := value.([]interface{}); ok {
+				items = list
+				lists[key] = true
+			}
+			for _, item := range items {
+				if !containsValue(values[key], item) {
+					values[key] = append(values[key], item)
+				}
+			}
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	merged := make(map[string]interface{}, len(keys))
+	for _, key := range keys {
+		if len(values[key]) == 1 && !lists[key] {
+			merged[key] = values[key][0]
+		} else {
+			merged[key] = values[key]
+		}
+	}
+	return merged
+}
+
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if reflect.DeepEqual(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package consignment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NatoNathan/shipyard/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSquash(t *testing.T) {
+	timestamp := time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("merges packages, change types and summaries", func(t *testing.T) {
+		consignments := []*Consignment{
+			{ID: "c1", Packages: []string{"core"}, ChangeType: types.ChangeTypePatch, Summary: "Fix crash"},
+			{ID: "c2", Packages: []string{"api", "core"}, ChangeType: types.ChangeTypeMinor, Summary: "Add retries\n\nRetries use backoff."},
+			{ID: "c3", Packages: []string{"web"}, ChangeType: types.ChangeTypePatch, Summary: "Fix typo"},
+		}
+
+		got, err := Squash(consignments, SquashOptions{Summary: "Various stability fixes", Timestamp: timestamp, AllowMixed: true})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"core", "api", "web"}, got.Packages)
+		assert.Equal(t, types.ChangeTypeMinor, got.ChangeType)
+		assert.Equal(t, timestamp, got.Timestamp)
+		assert.NotEmpty(t, got.ID)
+		assert.Equal(t, "Various stability fixes\n\n- Fix crash\n- Add retries\n\n  Retries use backoff.\n- Fix typo", got.Summary)
+	})
+
+	t.Run("mixed change types need acknowledgement", func(t *testing.T) {
+		consignments := []*Consignment{
+			{ID: "c1", Packages: []string{"core"}, ChangeType: types.ChangeTypePatch, Summary: "Fix"},
+			{ID: "c2", Packages: []string{"api"}, ChangeType: types.ChangeTypeMajor, Summary: "Break"},
+		}
+
+		_, err := Squash(consignments, SquashOptions{Summary: "Changes", Timestamp: timestamp})
+
+		var conflict *SquashConflictError
+		require.ErrorAs(t, err, &conflict)
+		assert.Equal(t, types.ChangeTypeMajor, conflict.Result)
+		assert.EqualError(t, err, "consignments have different change types (patch, major); the squashed consignment would be major for every package")
+	})
+
+	t.Run("merges metadata", func(t *testing.T) {
+		consignments := []*Consignment{
+			{ID: "c1", Packages: []string{"core"}, ChangeType: types.ChangeTypePatch, Summary: "A",
+				Metadata: map[string]interface{}{"author": "ana@example.com", "team": "platform", "issues": []interface{}{"JIRA-1"}}},
+			{ID: "c2", Packages: []string{"core"}, ChangeType: types.ChangeTypePatch, Summary: "B",
+				Metadata: map[string]interface{}{"author": "ben@example.com", "team": "platform"}},
+			{ID: "c3", Packages: []string{"core"}, ChangeType: types.ChangeTypePatch, Summary: "C",
+				Metadata: map[string]interface{}{"author": "ana@example.com", "issues": []interface{}{"JIRA-1", "JIRA-2"}}},
+		}
+
+		got, err := Squash(consignments, SquashOptions{Summary: "Fixes", Timestamp: timestamp})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{
+			"author": []interface{}{"ana@example.com", "ben@example.com"},
+			"team":   "platform",
+			"issues": []interface{}{"JIRA-1", "JIRA-2"},
+		}, got.Metadata)
+	})
+
+	t.Run("requires two consignments and a summary", func(t *testing.T) {
+		one := []*Consignment{{ID: "c1", Packages: []string{"core"}, ChangeType: types.ChangeTypePatch, Summary: "A"}}
+		_, err := Squash(one, SquashOptions{Summary: "Fixes"})
+		assert.Error(t, err)
+
+		two := append(one, &Consignment{ID: "c2", Packages: []string{"core"}, ChangeType: types.ChangeTypePatch, Summary: "B"})
+		_, err = Squash(two, SquashOptions{Summary: "  "})
+		assert.Error(t, err)
+	})
+}
@@ -7,8 +7,24 @@ import (
 	"github.com/NatoNathan/shipyard/internal/fileutil"
 )
 
-// WriteConsignment writes a consignment to a markdown file with atomic write
+// WriteConsignment writes a consignment to a markdown file with atomic write.
+// A consignment read from a file is written back to that file; a new one is
+// named after its ID. It refuses a name that differs only in case from an
+// existing consignment file, which would overwrite it on a case-insensitive
+// filesystem.
 func WriteConsignment(cons *Consignment, dir string) error {
+	if err := ValidateID(cons.ID); err != nil {
+		return err
+	}
+	filename := cons.FileName()
+	existing, err := findFileFold(dir, filename)
+	if err != nil {
+		return err
+	}
+	if existing != "" && existing != filename {
+		return fmt.Errorf("consignment %s collides with %s, which differs only in case", filename, existing)
+	}
+
 	// Ensure directory exists
 	if err := fileutil.MkdirAll(dir, 0755); err != nil {
 		return fmt.Errorf("failed to create consignments directory: %w", err)
@@ -21,7 +37,6 @@ func WriteConsignment(cons *Consignment, dir string) error {
 	}
 
 	// Build file path
-	filename := fmt.Sprintf("%s.md", cons.ID)
 	filePath := filepath.Join(dir, filename)
 
 	// Write atomically
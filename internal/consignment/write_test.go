@@ -148,3 +148,27 @@ func TestWriteConsignment_Filename(t *testing.T) {
 	require.NoError(t, err, "Should be able to read directory")
 	assert.Equal(t, 1, len(entries), "Should have exactly one file")
 }
+
+// TestWriteConsignment_CaseCollision tests that an ID differing only in case
+// from an existing file is refused
+func TestWriteConsignment_CaseCollision(t *testing.T) {
+	tempDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "20260130-143022-A1B2C3.md"), []byte("existing"), 0644))
+
+	cons := &Consignment{
+		ID:         "20260130-143022-a1b2c3",
+		Timestamp:  time.Date(2026, 1, 30, 14, 30, 22, 0, time.UTC),
+		Packages:   []string{"core"},
+		ChangeType: types.ChangeTypePatch,
+		Summary:    "Test",
+	}
+
+	err := WriteConsignment(cons, tempDir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "differs only in case")
+
+	cons.ID = "20260130-143022-A1B2C3"
+	err = WriteConsignment(cons, tempDir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid consignment ID")
+}
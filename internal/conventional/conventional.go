@@ -0,0 +1,64 @@
+// Package conventional parses commit messages written to the Conventional
+// Commits specification (https://www.conventionalcommits.org) and maps them
+// to change types.
+package conventional
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/NatoNathan/shipyard/pkg/types"
+)
+
+// headerPattern matches "type(scope)!: description"
+var headerPattern = regexp.MustCompile(`^([A-Za-z]+)(?:\(([^()]*)\))?(!)?: +(\S.*)$`)
+
+// Commit is a parsed conventional commit message
+type Commit struct {
+	Type        string // Lowercased, e.g. feat or fix
+	Scope       string
+	Breaking    bool // Marked with "!" or a BREAKING CHANGE footer
+	Description string
+	Body        string // Everything after the header, footers included
+}
+
+// Parse parses a commit message. ok is false when the first line is not a
+// conventional commit header.
+func Parse(message string) (commit *Commit, ok bool) {
+	message = strings.ReplaceAll(message, "\r\n", "\n")
+	header, body, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	match := headerPattern.FindStringSubmatch(strings.TrimSpace(header))
+	if match == nil {
+		return nil, false
+	}
+
+	commit = &Commit{
+		Type:        strings.ToLower(match[1]),
+		Scope:       strings.TrimSpace(match[2]),
+		Breaking:    match[3] == "!",
+		Description: strings.TrimSpace(match[4]),
+		Body:        strings.TrimSpace(body),
+	}
+	for _, line := range strings.Split(commit.Body, "\n") {
+		if strings.HasPrefix(line, "BREAKING CHANGE:") || strings.HasPrefix(line, "BREAKING-CHANGE:") {
+			commit.Breaking = true
+		}
+	}
+	return commit, true
+}
+
+// ChangeType maps the commit to a change type: breaking changes are major,
+// feat is minor and fix is patch. ok is false for types that do not release
+// anything, such as chore or docs.
+func (c *Commit) ChangeType() (changeType types.ChangeType, ok bool) {
+	switch {
+	case c.Breaking:
+		return types.ChangeTypeMajor, true
+	case c.Type == "feat":
+		return types.ChangeTypeMinor, true
+	case c.Type == "fix":
+		return types.ChangeTypePatch, true
+	default:
+		return "", false
+	}
+}
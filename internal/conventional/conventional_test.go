@@ -0,0 +1,83 @@
+package conventional
+
+import (
+	"testing"
+
+	"github.com/NatoNathan/shipyard/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name       string
+		message    string
+		want       Commit
+		changeType types.ChangeType
+	}{
+		{
+			name:       "feat",
+			message:    "feat: add export button\n",
+			want:       Commit{Type: "feat", Description: "add export button"},
+			changeType: types.ChangeTypeMinor,
+		},
+		{
+			name:       "fix with scope and body",
+			message:    "fix(api): handle empty pages\n\nThe list endpoint returned null.\n",
+			want:       Commit{Type: "fix", Scope: "api", Description: "handle empty pages", Body: "The list endpoint returned null."},
+			changeType: types.ChangeTypePatch,
+		},
+		{
+			name:       "bang",
+			message:    "refactor(core)!: drop Node 16",
+			want:       Commit{Type: "refactor", Scope: "core", Breaking: true, Description: "drop Node 16"},
+			changeType: types.ChangeTypeMajor,
+		},
+		{
+			name:       "breaking footer",
+			message:    "feat: new config format\r\n\r\nBREAKING CHANGE: the old format is gone\r\n",
+			want:       Commit{Type: "feat", Breaking: true, Description: "new config format", Body: "BREAKING CHANGE: the old format is gone"},
+			changeType: types.ChangeTypeMajor,
+		},
+		{
+			name:       "hyphenated breaking footer",
+			message:    "Fix: typo\n\nBREAKING-CHANGE: renamed flag",
+			want:       Commit{Type: "fix", Breaking: true, Description: "typo", Body: "BREAKING-CHANGE: renamed flag"},
+			changeType: types.ChangeTypeMajor,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Parse(tt.message)
+			require.True(t, ok)
+			assert.Equal(t, tt.want, *got)
+			changeType, ok := got.ChangeType()
+			require.True(t, ok)
+			assert.Equal(t, tt.changeType, changeType)
+		})
+	}
+}
+
+func TestParse_NotConventional(t *testing.T) {
+	for _, message := range []string{
+		"Update README",
+		"Merge branch 'main' into feature",
+		"feat add button",
+		"feat:",
+		"feat(api: unbalanced scope",
+		"",
+	} {
+		_, ok := Parse(message)
+		assert.False(t, ok, message)
+	}
+}
+
+func TestChangeType_NoRelease(t *testing.T) {
+	for _, message := range []string{"chore: bump deps", "docs(readme): fix link", "ci: cache modules"} {
+		commit, ok := Parse(message)
+		require.True(t, ok, message)
+		_, ok = commit.ChangeType()
+		assert.False(t, ok, message)
+	}
+}
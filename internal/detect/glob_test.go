@@ -0,0 +1,78 @@
+package detect
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeMixedMonorepo creates Go, npm and Helm packages under packages/ plus
+// a directory without any ecosystem marker
+func writeMixedMonorepo(t *testing.T, root string) {
+	t.Helper()
+	files := map[string]string{
+		"packages/api/go.mod":             "module github.com/example/api\n\ngo 1.21\n",
+		"packages/web/package.json":       `{"name": "@example/web", "version": "1.0.0"}`,
+		"packages/chart/Chart.yaml":       "apiVersion: v2\nname: example-chart\nversion: 0.1.0\n",
+		"packages/notes/README.md":        "# Not a package\n",
+		"tools/cli/go.mod":                "module github.com/example/cli\n\ngo 1.21\n",
+		".shipyard/consignments/.gitkeep": "",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+func TestDetectPackage(t *testing.T) {
+	root := t.TempDir()
+	writeMixedMonorepo(t, root)
+
+	pkg, err := DetectPackage(root, filepath.Join(root, "packages", "chart"))
+	require.NoError(t, err)
+	require.NotNil(t, pkg)
+	assert.Equal(t, config.Package{Name: "example-chart", Path: "./packages/chart", Ecosystem: config.EcosystemHelm}, *pkg)
+
+	pkg, err = DetectPackage(root, filepath.Join(root, "packages", "notes"))
+	require.NoError(t, err)
+	assert.Nil(t, pkg)
+}
+
+func TestLoadFromDir_PackageGlobsUseDetection(t *testing.T) {
+	root := t.TempDir()
+	writeMixedMonorepo(t, root)
+	configContent := `packages:
+  - path: packages/*
+    ecosystem: auto
+  - path: tools/*
+    ecosystem: go
+    dependencies:
+      - package: api
+`
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".shipyard", "shipyard.yaml"), []byte(configContent), 0644))
+
+	cfg, err := config.LoadFromDir(root)
+	require.NoError(t, err)
+
+	assert.Equal(t, []config.Package{
+		{Name: "api", Path: "./packages/api", Ecosystem: config.EcosystemGo},
+		{Name: "example-chart", Path: "./packages/chart", Ecosystem: config.EcosystemHelm},
+		{Name: "@example/web", Path: "./packages/web", Ecosystem: config.EcosystemNPM},
+		{Name: "cli", Path: "./tools/cli", Ecosystem: config.EcosystemGo,
+			Dependencies: []config.Dependency{{Package: "api", Strategy: "linked"}}},
+	}, cfg.Packages)
+
+	t.Run("glob without packages fails validation", func(t *testing.T) {
+		configContent := "packages:\n  - path: packages/*\n    ecosystem: cargo\n"
+		require.NoError(t, os.WriteFile(filepath.Join(root, ".shipyard", "shipyard.yaml"), []byte(configContent), 0644))
+
+		_, err := config.LoadFromDir(root)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `package glob "packages/*" matched no packages`)
+	})
+}
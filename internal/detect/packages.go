@@ -3,95 +3,247 @@ package detect
 import (
 	"encoding/json"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 
+	"github.com/NatoNathan/shipyard/internal/ecosystem"
 	"github.com/NatoNathan/shipyard/internal/fileutil"
 
 	"github.com/BurntSushi/toml"
 	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
 )
 
-// DetectPackages scans a directory tree and detects packages based on ecosystem markers
+func init() {
+	config.SetPackageDetector(DetectPackage)
+}
+
+// Candidate is a package found during a scan, with the manifest that
+// identified it
+type Candidate struct {
+	Package  config.Package
+	Manifest string // Manifest path relative to the scan root, slash-separated
+}
+
+// ScanOptions controls how DetectCandidates walks the tree
+type ScanOptions struct {
+	// MaxDepth is the deepest directory level scanned, counting the root's
+	// children as 1. Zero scans every level.
+	MaxDepth int
+}
+
+// skippedDirs are never scanned: dependencies, build output and caches
+var skippedDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	"__pycache__":  true,
+	"dist":         true,
+	"build":        true,
+	"target":       true,
+}
+
+// DetectPackages scans a directory tree and detects packages based on
+// ecosystem markers, keeping one package per directory as ResolveConflicts
+// does with the default priority
 func DetectPackages(rootPath string) ([]config.Package, error) {
-	var packages []config.Package
-	seen := make(map[string]bool) // Track seen paths to avoid duplicates
+	candidates, err := DetectCandidates(rootPath, ScanOptions{})
+	if err != nil {
+		return nil, err
+	}
+	resolved := ResolveConflicts(candidates, nil)
+	packages := make([]config.Package, len(resolved))
+	for i, c := range resolved {
+		packages[i] = c.Package
+	}
+	return packages, nil
+}
+
+// DetectCandidates walks a directory tree and returns one candidate per
+// ecosystem found in each directory, so a directory holding both a
+// package.json and a Chart.yaml yields two. Hidden directories, paths
+// ignored by .gitignore files and dependency or build directories are
+// skipped.
+func DetectCandidates(rootPath string, opts ScanOptions) ([]Candidate, error) {
+	var candidates []Candidate
+	seen := make(map[string]bool) // directory and ecosystem already detected
 	cleanRootPath := filepath.Clean(rootPath)
+	var ignores []gitignore.Pattern
 
-	err := filepath.Walk(rootPath, func(path string, info os.FileInfo, err error) error {
+	err := filepath.WalkDir(cleanRootPath, func(path string, entry fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
-
-		// Skip hidden directories.
-		if info.IsDir() && filepath.Clean(path) != cleanRootPath && strings.HasPrefix(info.Name(), ".") {
-			return filepath.SkipDir
+		rel, err := filepath.Rel(cleanRootPath, path)
+		if err != nil {
+			return err
+		}
+		var parts []string
+		if rel != "." {
+			parts = strings.Split(filepath.ToSlash(rel), "/")
 		}
 
-		// Skip common directories that shouldn't be scanned
-		if info.IsDir() {
-			dirName := info.Name()
-			if dirName == "node_modules" || dirName == "vendor" || dirName == "__pycache__" ||
-				dirName == "dist" || dirName == "build" || dirName == "target" {
-				return filepath.SkipDir
+		if entry.IsDir() {
+			if rel != "." {
+				if strings.HasPrefix(entry.Name(), ".") || skippedDirs[entry.Name()] ||
+					gitignore.NewMatcher(ignores).Match(parts, true) {
+					return filepath.SkipDir
+				}
+				if opts.MaxDepth > 0 && len(parts) > opts.MaxDepth {
+					return filepath.SkipDir
+				}
 			}
+			// Patterns apply below the directory holding the .gitignore
+			ignores = append(ignores, readGitignore(path, parts)...)
+			return nil
 		}
-
-		if info.IsDir() {
+		if gitignore.NewMatcher(ignores).Match(parts, false) {
 			return nil
 		}
 
-		// Get the directory containing this file
 		dir := filepath.Dir(path)
-
-		// Skip if we've already processed this directory
-		if seen[dir] {
+		pkg, detectErr := detectMarker(cleanRootPath, dir, path, entry.Name())
+		if detectErr != nil || pkg == nil {
+			// Unreadable or nameless manifests are skipped
 			return nil
 		}
+		key := dir + "\x00" + pkg.Ecosystem
+		if seen[key] {
+			return nil
+		}
+		seen[key] = true
+		candidates = append(candidates, Candidate{Package: *pkg, Manifest: filepath.ToSlash(rel)})
+		return nil
+	})
 
-		// Detect package based on file markers
-		var pkg *config.Package
-		var detectErr error
-
-		switch info.Name() {
-		case "go.mod":
-			pkg, detectErr = detectGoPackage(rootPath, dir, path)
-		case "package.json":
-			pkg, detectErr = detectNPMPackage(rootPath, dir, path)
-		case "pyproject.toml":
-			pkg, detectErr = detectPythonPackage(rootPath, dir, path)
-		case "setup.py":
-			if !seen[dir] { // Only detect if no pyproject.toml was found
-				pkg, detectErr = detectPythonSetupPackage(rootPath, dir, path)
-			}
-		case "Chart.yaml":
-			pkg, detectErr = detectHelmPackage(rootPath, dir, path)
-		case "Cargo.toml":
-			pkg, detectErr = detectCargoPackage(rootPath, dir, path)
-		case "deno.json", "deno.jsonc":
-			pkg, detectErr = detectDenoPackage(rootPath, dir, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk directory tree: %w", err)
+	}
+
+	return candidates, nil
+}
+
+// readGitignore parses the .gitignore in dir, whose path below the scan root
+// is domain. A missing or unreadable file contributes no patterns.
+func readGitignore(dir string, domain []string) []gitignore.Pattern {
+	content, err := fileutil.ReadFile(filepath.Join(dir, ".gitignore"))
+	if err != nil {
+		return nil
+	}
+	var patterns []gitignore.Pattern
+	for _, line := range strings.Split(fileutil.NormalizeNewlines(string(content)), "\n") {
+		if strings.HasPrefix(line, "#") || strings.TrimSpace(line) == "" {
+			continue
 		}
+		patterns = append(patterns, gitignore.ParsePattern(line, domain))
+	}
+	return patterns
+}
 
-		if detectErr != nil {
-			// Log error but continue scanning
-			return nil
+// ResolveConflicts keeps one candidate per directory. Where several
+// ecosystems were found in one directory, the one listed first in priority
+// wins; ecosystems not in priority follow in config.Ecosystems order.
+func ResolveConflicts(candidates []Candidate, priority []string) []Candidate {
+	rank := ecosystemRank(priority)
+	best := make(map[string]int) // directory -> index into resolved
+	var resolved []Candidate
+	for _, c := range candidates {
+		i, ok := best[c.Package.Path]
+		if !ok {
+			best[c.Package.Path] = len(resolved)
+			resolved = append(resolved, c)
+			continue
 		}
+		if rank(c.Package.Ecosystem) < rank(resolved[i].Package.Ecosystem) {
+			resolved[i] = c
+		}
+	}
+	return resolved
+}
 
-		if pkg != nil {
-			seen[dir] = true
-			packages = append(packages, *pkg)
+// Conflicts returns the directories where candidates of more than one
+// ecosystem were found, each with its candidates in scan order
+func Conflicts(candidates []Candidate) [][]Candidate {
+	byPath := make(map[string][]Candidate)
+	var order []string
+	for _, c := range candidates {
+		if _, ok := byPath[c.Package.Path]; !ok {
+			order = append(order, c.Package.Path)
+		}
+		byPath[c.Package.Path] = append(byPath[c.Package.Path], c)
+	}
+	var conflicts [][]Candidate
+	for _, path := range order {
+		if len(byPath[path]) > 1 {
+			conflicts = append(conflicts, byPath[path])
 		}
+	}
+	return conflicts
+}
 
-		return nil
-	})
+// ecosystemRank orders ecosystems by priority, then by config.Ecosystems
+func ecosystemRank(priority []string) func(string) int {
+	order := append(slices.Clone(priority), config.Ecosystems()...)
+	return func(ecosystem string) int {
+		if i := slices.Index(order, ecosystem); i >= 0 {
+			return i
+		}
+		return len(order)
+	}
+}
 
+// DetectPackage detects the package in dir from its ecosystem markers, the
+// way DetectPackages does for each directory it scans. It returns nil when
+// dir holds no recognizable package.
+func DetectPackage(rootPath, dir string) (*config.Package, error) {
+	entries, err := os.ReadDir(dir)
 	if err != nil {
-		return nil, fmt.Errorf("failed to walk directory tree: %w", err)
+		return nil, fmt.Errorf("failed to read directory: %w", err)
+	}
+	rank := ecosystemRank(nil)
+	var best *config.Package
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		pkg, err := detectMarker(rootPath, dir, filepath.Join(dir, entry.Name()), entry.Name())
+		if err == nil && pkg != nil && (best == nil || rank(pkg.Ecosystem) < rank(best.Ecosystem)) {
+			best = pkg
+		}
 	}
+	return best, nil
+}
 
-	return packages, nil
+// detectMarker detects a package from a single marker file in dir, returning
+// nil when name is not a marker
+func detectMarker(rootPath, dir, path, name string) (*config.Package, error) {
+	switch name {
+	case "go.mod":
+		return detectGoPackage(rootPath, dir, path)
+	case "package.json":
+		return detectNPMPackage(rootPath, dir, path)
+	case "pyproject.toml":
+		return detectPythonPackage(rootPath, dir, path)
+	case "setup.cfg":
+		return detectPythonSetupCfgPackage(rootPath, dir, path)
+	case "setup.py":
+		return detectPythonSetupPackage(rootPath, dir, path)
+	case "Chart.yaml":
+		return detectHelmPackage(rootPath, dir, path)
+	case "Cargo.toml":
+		return detectCargoPackage(rootPath, dir, path)
+	case "jsr.json", "deno.json", "deno.jsonc":
+		return detectDenoPackage(rootPath, dir)
+	case "pom.xml", "gradle.properties":
+		return detectMavenPackage(rootPath, dir)
+	}
+	if strings.HasSuffix(name, ".csproj") {
+		return detectDotnetPackage(rootPath, dir, path)
+	}
+	return nil, nil
 }
 
 // detectGoPackage detects a Go package from go.mod
@@ -165,18 +317,64 @@ func detectPythonPackage(rootPath, dir, pyprojectPath string) (*config.Package,
 		Project struct {
 			Name string `toml:"name"`
 		} `toml:"project"`
+		Tool struct {
+			Poetry struct {
+				Name string `toml:"name"`
+			} `toml:"poetry"`
+		} `toml:"tool"`
 	}
 
 	if err := toml.Unmarshal(content, &pyproject); err != nil {
 		return nil, fmt.Errorf("failed to parse pyproject.toml: %w", err)
 	}
 
-	if pyproject.Project.Name == "" {
+	name := pyproject.Project.Name
+	if name == "" {
+		name = pyproject.Tool.Poetry.Name
+	}
+	if name == "" {
 		return nil, fmt.Errorf("no project name found in pyproject.toml")
 	}
 
 	return &config.Package{
-		Name:      pyproject.Project.Name,
+		Name:      name,
+		Path:      NormalizePackagePath(rootPath, dir),
+		Ecosystem: config.EcosystemPython,
+	}, nil
+}
+
+// detectPythonSetupCfgPackage detects a Python package from the [metadata] section of setup.cfg
+func detectPythonSetupCfgPackage(rootPath, dir, setupCfgPath string) (*config.Package, error) {
+	content, err := fileutil.ReadFile(setupCfgPath)
+	if err != nil {
+		return nil, err
+	}
+
+	inMetadata := false
+	var packageName string
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			inMetadata = line == "[metadata]"
+			continue
+		}
+		if !inMetadata {
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		if found && strings.TrimSpace(key) == "name" {
+			packageName = strings.TrimSpace(value)
+			break
+		}
+	}
+
+	// setup.cfg is also used purely for tool settings; only treat it as a package with metadata
+	if packageName == "" {
+		return nil, fmt.Errorf("no name found in setup.cfg [metadata]")
+	}
+
+	return &config.Package{
+		Name:      packageName,
 		Path:      NormalizePackagePath(rootPath, dir),
 		Ecosystem: config.EcosystemPython,
 	}, nil
@@ -283,30 +481,76 @@ func detectCargoPackage(rootPath, dir, cargoPath string) (*config.Package, error
 	}, nil
 }
 
-// detectDenoPackage detects a Deno project from deno.json or deno.jsonc
-func detectDenoPackage(rootPath, dir, denoPath string) (*config.Package, error) {
-	content, err := fileutil.ReadFile(denoPath)
+// detectDenoPackage detects a Deno project from jsr.json, deno.json or
+// deno.jsonc, named from the manifest the Deno handler prefers
+func detectDenoPackage(rootPath, dir string) (*config.Package, error) {
+	packageName, err := ecosystem.NewDenoEcosystem(dir).ReadName()
 	if err != nil {
 		return nil, err
 	}
-
-	var denoConfig struct {
-		Name string `json:"name"`
+	if packageName == "" {
+		// Fallback to directory name if no name field
+		packageName = filepath.Base(dir)
 	}
 
-	if err := json.Unmarshal(content, &denoConfig); err != nil {
-		return nil, fmt.Errorf("failed to parse %s: %w", filepath.Base(denoPath), err)
-	}
+	return &config.Package{
+		Name:      packageName,
+		Path:      NormalizePackagePath(rootPath, dir),
+		Ecosystem: config.EcosystemDeno,
+	}, nil
+}
 
-	packageName := denoConfig.Name
+// detectMavenPackage detects a Maven project from pom.xml or a Gradle
+// project from gradle.properties. Modules that inherit their version from a
+// parent pom, and Gradle projects without a version property, are not
+// packages of their own.
+func detectMavenPackage(rootPath, dir string) (*config.Package, error) {
+	handler := ecosystem.NewMavenEcosystem(dir)
+	if _, err := handler.ReadVersion(); err != nil {
+		return nil, nil
+	}
+	packageName, err := handler.ReadName()
+	if err != nil {
+		return nil, err
+	}
 	if packageName == "" {
-		// Fallback to directory name if no name field
 		packageName = filepath.Base(dir)
 	}
 
 	return &config.Package{
 		Name:      packageName,
 		Path:      NormalizePackagePath(rootPath, dir),
-		Ecosystem: config.EcosystemDeno,
+		Ecosystem: config.EcosystemMaven,
 	}, nil
 }
+
+// detectDotnetPackage detects a .NET project from a .csproj file. The package
+// is named after <PackageId>, then <AssemblyName>, then the project file name.
+func detectDotnetPackage(rootPath, dir, csprojPath string) (*config.Package, error) {
+	content, err := fileutil.ReadFile(csprojPath)
+	if err != nil {
+		return nil, err
+	}
+
+	packageName := strings.TrimSuffix(filepath.Base(csprojPath), ".csproj")
+	for _, property := range []string{"PackageId", "AssemblyName"} {
+		re := regexp.MustCompile(`<` + property + `>\s*([^<$]+?)\s*</` + property + `>`)
+		if matches := re.FindSubmatch(content); matches != nil {
+			packageName = string(matches[1])
+			break
+		}
+	}
+
+	pkg := &config.Package{
+		Name:      packageName,
+		Path:      NormalizePackagePath(rootPath, dir),
+		Ecosystem: config.EcosystemDotnet,
+	}
+
+	// Pin the manifest when the directory holds more than one project
+	if projects, err := filepath.Glob(filepath.Join(dir, "*.csproj")); err == nil && len(projects) > 1 {
+		pkg.Options = map[string]interface{}{"manifest": filepath.Base(csprojPath)}
+	}
+
+	return pkg, nil
+}
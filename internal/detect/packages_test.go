@@ -92,6 +92,27 @@ setup(
 `,
 			expectedName: "my-setup-package",
 		},
+		{
+			name:     "poetry pyproject.toml",
+			filename: "pyproject.toml",
+			content: `[tool.poetry]
+name = "my-poetry-package"
+version = "0.1.0"
+`,
+			expectedName: "my-poetry-package",
+		},
+		{
+			name:     "setup.cfg",
+			filename: "setup.cfg",
+			content: `[metadata]
+name = my-cfg-package
+version = 0.1.0
+
+[options]
+packages = find:
+`,
+			expectedName: "my-cfg-package",
+		},
 	}
 
 	for _, tt := range tests {
@@ -278,6 +299,70 @@ func TestDetectPackages_DenoPackage(t *testing.T) {
 		assert.Equal(t, config.EcosystemDeno, packages[0].Ecosystem)
 		assert.Equal(t, filepath.Base(tempDir), packages[0].Name)
 	})
+
+	t.Run("jsr.json name preferred over deno.json", func(t *testing.T) {
+		tempDir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, "deno.json"), []byte(`{"name": "@scope/dev", "tasks": {}}`), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, "jsr.json"), []byte(`{"name": "@scope/lib", "version": "0.1.0"}`), 0644))
+
+		packages, err := DetectPackages(tempDir)
+		require.NoError(t, err)
+
+		require.Len(t, packages, 1)
+		assert.Equal(t, "@scope/lib", packages[0].Name)
+		assert.Equal(t, config.EcosystemDeno, packages[0].Ecosystem)
+	})
+
+	t.Run("deno.jsonc with comments", func(t *testing.T) {
+		tempDir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, "deno.jsonc"), []byte("{\n  // JSR package\n  \"name\": \"@scope/app\",\n}\n"), 0644))
+
+		packages, err := DetectPackages(tempDir)
+		require.NoError(t, err)
+
+		require.Len(t, packages, 1)
+		assert.Equal(t, "@scope/app", packages[0].Name)
+	})
+}
+
+// TestDetectPackages_MavenPackage tests detection of Maven and Gradle projects
+func TestDetectPackages_MavenPackage(t *testing.T) {
+	t.Run("multi-module pom detects only the parent", func(t *testing.T) {
+		tempDir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, "pom.xml"), []byte(`<project>
+  <artifactId>platform-parent</artifactId>
+  <version>1.0.0</version>
+  <modules><module>orders</module></modules>
+</project>
+`), 0644))
+		require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "orders"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, "orders", "pom.xml"), []byte(`<project>
+  <parent><artifactId>platform-parent</artifactId><version>1.0.0</version></parent>
+  <artifactId>orders</artifactId>
+</project>
+`), 0644))
+
+		packages, err := DetectPackages(tempDir)
+		require.NoError(t, err)
+
+		require.Len(t, packages, 1)
+		assert.Equal(t, "platform-parent", packages[0].Name)
+		assert.Equal(t, config.EcosystemMaven, packages[0].Ecosystem)
+	})
+
+	t.Run("gradle.properties with a version", func(t *testing.T) {
+		tempDir := t.TempDir()
+		serviceDir := filepath.Join(tempDir, "billing")
+		require.NoError(t, os.MkdirAll(serviceDir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(serviceDir, "gradle.properties"), []byte("version=0.3.0\n"), 0644))
+
+		packages, err := DetectPackages(tempDir)
+		require.NoError(t, err)
+
+		require.Len(t, packages, 1)
+		assert.Equal(t, "billing", packages[0].Name)
+		assert.Equal(t, config.EcosystemMaven, packages[0].Ecosystem)
+	})
 }
 
 // TestDetectPackages_MultipleEcosystemsSameDir tests behavior when multiple ecosystem markers exist in same directory
@@ -296,3 +381,146 @@ func TestDetectPackages_MultipleEcosystemsSameDir(t *testing.T) {
 	// Current implementation should detect both
 	assert.GreaterOrEqual(t, len(packages), 1, "Should detect at least one package")
 }
+
+// TestDetectPackages_DotnetPackage tests detection of .NET projects
+func TestDetectPackages_DotnetPackage(t *testing.T) {
+	t.Run("named after PackageId", func(t *testing.T) {
+		tempDir := t.TempDir()
+		servicePath := filepath.Join(tempDir, "src", "Orders")
+		require.NoError(t, os.MkdirAll(servicePath, 0755))
+		csproj := `<Project Sdk="Microsoft.NET.Sdk">
+  <PropertyGroup>
+    <PackageId>Acme.Orders</PackageId>
+    <Version>1.0.0</Version>
+  </PropertyGroup>
+</Project>
+`
+		require.NoError(t, os.WriteFile(filepath.Join(servicePath, "Orders.csproj"), []byte(csproj), 0644))
+
+		packages, err := DetectPackages(tempDir)
+		require.NoError(t, err)
+
+		require.Len(t, packages, 1)
+		assert.Equal(t, "Acme.Orders", packages[0].Name)
+		assert.Equal(t, "./src/Orders", packages[0].Path)
+		assert.Equal(t, config.EcosystemDotnet, packages[0].Ecosystem)
+		assert.Nil(t, packages[0].Options)
+	})
+
+	t.Run("falls back to the project file name", func(t *testing.T) {
+		tempDir := t.TempDir()
+		csproj := `<Project Sdk="Microsoft.NET.Sdk"><PropertyGroup><PackageId>$(MSBuildProjectName)</PackageId></PropertyGroup></Project>`
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, "Billing.csproj"), []byte(csproj), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, "Billing.Tests.csproj"), []byte(csproj), 0644))
+
+		packages, err := DetectPackages(tempDir)
+		require.NoError(t, err)
+
+		require.Len(t, packages, 1)
+		assert.Equal(t, "Billing.Tests", packages[0].Name)
+		assert.Equal(t, map[string]interface{}{"manifest": "Billing.Tests.csproj"}, packages[0].Options)
+	})
+}
+
+// writeTree writes files, keyed by slash-separated path, under root
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+// TestDetectCandidates_SyntheticTree tests scanning a tree with nested
+// packages across ecosystems, ignored paths and a directory with two manifests
+func TestDetectCandidates_SyntheticTree(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTree(t, tempDir, map[string]string{
+		"go.mod":                                      "module example.com/root\n",
+		".gitignore":                                  "# generated\nsandbox/\n",
+		"services/api/go.mod":                         "module example.com/services/api\n",
+		"services/billing/worker/Cargo.toml":          "[package]\nname = \"billing-worker\"\n",
+		"libs/python/tools/pyproject.toml":            "[project]\nname = \"tools\"\n",
+		"libs/python/tools/setup.py":                  "setup(name=\"tools-legacy\")\n",
+		"deploy/charts/dashboard/Chart.yaml":          "name: dashboard\n",
+		"deploy/charts/dashboard/package.json":        `{"name": "dashboard-ui"}`,
+		"apps/web/package.json":                       `{"name": "web"}`,
+		"apps/web/node_modules/left-pad/package.json": `{"name": "left-pad"}`,
+		"apps/deno/deno.json":                         `{"name": "@acme/deno"}`,
+		"vendor/github.com/x/go.mod":                  "module github.com/x\n",
+		"sandbox/go.mod":                              "module example.com/sandbox\n",
+		"apps/web/.gitignore":                         "fixtures\n",
+		"apps/web/fixtures/package.json":              `{"name": "fixture"}`,
+		".hidden/package.json":                        `{"name": "hidden"}`,
+	})
+
+	candidates, err := DetectCandidates(tempDir, ScanOptions{})
+	require.NoError(t, err)
+
+	found := make(map[string]string)
+	for _, c := range candidates {
+		found[c.Manifest] = c.Package.Name + " " + c.Package.Ecosystem + " " + c.Package.Path
+	}
+	assert.Equal(t, map[string]string{
+		"go.mod":                               "root go ./",
+		"apps/deno/deno.json":                  "@acme/deno deno ./apps/deno",
+		"apps/web/package.json":                "web npm ./apps/web",
+		"deploy/charts/dashboard/Chart.yaml":   "dashboard helm ./deploy/charts/dashboard",
+		"deploy/charts/dashboard/package.json": "dashboard-ui npm ./deploy/charts/dashboard",
+		"libs/python/tools/pyproject.toml":     "tools python ./libs/python/tools",
+		"services/api/go.mod":                  "api go ./services/api",
+		"services/billing/worker/Cargo.toml":   "billing-worker cargo ./services/billing/worker",
+	}, found)
+
+	conflicts := Conflicts(candidates)
+	require.Len(t, conflicts, 1)
+	assert.Len(t, conflicts[0], 2)
+	assert.Equal(t, "./deploy/charts/dashboard", conflicts[0][0].Package.Path)
+
+	t.Run("default priority", func(t *testing.T) {
+		resolved := ResolveConflicts(candidates, nil)
+		assert.Len(t, resolved, 7)
+		for _, c := range resolved {
+			if c.Package.Path == "./deploy/charts/dashboard" {
+				assert.Equal(t, config.EcosystemNPM, c.Package.Ecosystem)
+			}
+		}
+	})
+
+	t.Run("configured priority", func(t *testing.T) {
+		resolved := ResolveConflicts(candidates, []string{config.EcosystemHelm})
+		assert.Len(t, resolved, 7)
+		for _, c := range resolved {
+			if c.Package.Path == "./deploy/charts/dashboard" {
+				assert.Equal(t, "dashboard", c.Package.Name)
+				assert.Equal(t, "deploy/charts/dashboard/Chart.yaml", c.Manifest)
+			}
+		}
+	})
+
+	t.Run("depth limit", func(t *testing.T) {
+		shallow, err := DetectCandidates(tempDir, ScanOptions{MaxDepth: 2})
+		require.NoError(t, err)
+		var manifests []string
+		for _, c := range shallow {
+			manifests = append(manifests, c.Manifest)
+		}
+		assert.ElementsMatch(t, []string{"go.mod", "apps/deno/deno.json", "apps/web/package.json", "services/api/go.mod"}, manifests)
+	})
+}
+
+// TestDetectPackage_PrefersEcosystemPriority tests that single-directory
+// detection resolves several manifests the way the scan does
+func TestDetectPackage_PrefersEcosystemPriority(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTree(t, tempDir, map[string]string{
+		"Chart.yaml":   "name: dashboard\n",
+		"package.json": `{"name": "dashboard-ui"}`,
+	})
+
+	pkg, err := DetectPackage(tempDir, tempDir)
+	require.NoError(t, err)
+	require.NotNil(t, pkg)
+	assert.Equal(t, config.EcosystemNPM, pkg.Ecosystem)
+}
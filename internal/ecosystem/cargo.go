@@ -17,6 +17,8 @@ var _ Handler = (*CargoEcosystem)(nil)
 
 // CargoEcosystem handles version management for Rust/Cargo projects
 type CargoEcosystem struct {
+	versionParser
+
 	path string
 }
 
@@ -55,7 +57,7 @@ func (c *CargoEcosystem) ReadVersion() (semver.Version, error) {
 		return semver.Version{}, fmt.Errorf("no version field found in Cargo.toml [package] section")
 	}
 
-	return semver.Parse(manifest.Package.Version)
+	return c.parseVersion(manifest.Package.Version)
 }
 
 // UpdateVersion updates the version in Cargo.toml using regex replacement
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"regexp"
 
 	"github.com/NatoNathan/shipyard/internal/fileutil"
 
@@ -14,8 +13,15 @@ import (
 
 var _ Handler = (*DenoEcosystem)(nil)
 
+// denoManifests are the files holding a Deno package's name and version, in
+// order of preference. jsr.json is what JSR publishes from, so it wins when a
+// package has both it and deno.json.
+var denoManifests = []string{"jsr.json", "deno.json", "deno.jsonc"}
+
 // DenoEcosystem handles version management for Deno projects
 type DenoEcosystem struct {
+	versionParser
+
 	path string
 }
 
@@ -24,101 +30,139 @@ func NewDenoEcosystem(path string) *DenoEcosystem {
 	return &DenoEcosystem{path: path}
 }
 
-// DenoConfig represents the structure of deno.json/deno.jsonc
+// DenoConfig represents the structure of jsr.json, deno.json or deno.jsonc
 type DenoConfig struct {
-	Name    string `json:"name,omitempty"`
-	Version string `json:"version"`
-	Exports string `json:"exports,omitempty"`
+	Name    string      `json:"name,omitempty"`
+	Version string      `json:"version"`
+	Exports interface{} `json:"exports,omitempty"`
 }
 
-// ReadVersion reads the current version from deno.json or deno.jsonc
+// ReadVersion reads the current version from jsr.json, deno.json or
+// deno.jsonc, in that order of preference
 func (d *DenoEcosystem) ReadVersion() (semver.Version, error) {
-	// Try deno.json first
-	denoPath := filepath.Join(d.path, "deno.json")
-	if _, err := os.Stat(denoPath); os.IsNotExist(err) {
-		// Try deno.jsonc
-		denoPath = filepath.Join(d.path, "deno.jsonc")
-		if _, err := os.Stat(denoPath); os.IsNotExist(err) {
-			return semver.Version{}, fmt.Errorf("no deno.json or deno.jsonc found")
-		}
+	manifest := d.manifest()
+	if manifest == "" {
+		return semver.Version{}, fmt.Errorf("no jsr.json, deno.json or deno.jsonc found")
 	}
 
-	content, err := fileutil.ReadFile(denoPath)
+	config, err := d.readManifest(manifest)
 	if err != nil {
-		return semver.Version{}, fmt.Errorf("failed to read %s: %w", filepath.Base(denoPath), err)
+		return semver.Version{}, err
+	}
+	if config.Version == "" {
+		return semver.Version{}, fmt.Errorf("no version field found in %s", manifest)
 	}
 
-	// Strip comments for JSONC support
-	content = stripJSONComments(content)
+	return d.parseVersion(config.Version)
+}
 
-	var config DenoConfig
-	if err := json.Unmarshal(content, &config); err != nil {
-		return semver.Version{}, fmt.Errorf("failed to parse %s: %w", filepath.Base(denoPath), err)
+// ReadName reads the package name from the preferred manifest. It is empty
+// when the manifest has none.
+func (d *DenoEcosystem) ReadName() (string, error) {
+	manifest := d.manifest()
+	if manifest == "" {
+		return "", fmt.Errorf("no jsr.json, deno.json or deno.jsonc found")
 	}
-
-	if config.Version == "" {
-		return semver.Version{}, fmt.Errorf("no version field found in %s", filepath.Base(denoPath))
+	config, err := d.readManifest(manifest)
+	if err != nil {
+		return "", err
 	}
-
-	return semver.Parse(config.Version)
+	return config.Name, nil
 }
 
-// UpdateVersion updates the version in deno.json or deno.jsonc using regex
-// replacement to preserve original formatting and comments.
+// UpdateVersion sets the top-level version in each version file. Only the
+// version string is replaced, so formatting, key order and comments are kept.
 func (d *DenoEcosystem) UpdateVersion(version semver.Version) error {
-	// Determine which config file exists
-	denoPath := filepath.Join(d.path, "deno.json")
-	if _, err := os.Stat(denoPath); os.IsNotExist(err) {
-		denoPath = filepath.Join(d.path, "deno.jsonc")
-		if _, err := os.Stat(denoPath); os.IsNotExist(err) {
-			return fmt.Errorf("no deno.json or deno.jsonc found")
-		}
+	manifests := d.GetVersionFiles()
+	if len(manifests) == 0 {
+		return fmt.Errorf("no jsr.json, deno.json or deno.jsonc found")
 	}
 
-	// Read existing content
-	content, err := fileutil.ReadFile(denoPath)
-	if err != nil {
-		return fmt.Errorf("failed to read %s: %w", filepath.Base(denoPath), err)
-	}
+	for _, manifest := range manifests {
+		path := filepath.Join(d.path, manifest)
+		content, err := fileutil.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", manifest, err)
+		}
+		if _, err := d.readManifest(manifest); err != nil {
+			return err
+		}
 
-	// Use regex to replace only the version value, preserving all formatting and comments
-	re := regexp.MustCompile(`("version"\s*:\s*")([^"]+)(")`)
-	newContent := re.ReplaceAll(content, []byte(fmt.Sprintf(`${1}%s${3}`, version.String())))
+		// Comments are blanked rather than removed, so offsets in the
+		// parsed text match the original
+		start, end, ok := jsonStringSpan(blankJSONComments(content), "version")
+		if !ok {
+			return fmt.Errorf("no version field found in %s", manifest)
+		}
+		newContent := applyJSONEdits(content, []jsonEdit{{start: start, end: end, value: version.String()}})
+		if err := fileutil.WriteFile(path, newContent, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	if string(newContent) == string(content) {
-		return fmt.Errorf("no version field found in %s", filepath.Base(denoPath))
+// GetVersionFiles returns the manifest the version is read from, followed by
+// deno.json or deno.jsonc when jsr.json is preferred but they also declare a
+// version, so both stay in sync
+func (d *DenoEcosystem) GetVersionFiles() []string {
+	manifest := d.manifest()
+	if manifest == "" {
+		return []string{}
+	}
+	files := []string{manifest}
+	if manifest != "jsr.json" {
+		return files
+	}
+	for _, other := range denoManifests[1:] {
+		if config, err := d.readManifest(other); err == nil && config.Version != "" {
+			files = append(files, other)
+			break
+		}
 	}
+	return files
+}
 
-	return fileutil.WriteFile(denoPath, newContent, 0644)
+// manifest returns the preferred manifest in the package, or "" when there
+// is none
+func (d *DenoEcosystem) manifest() string {
+	for _, name := range denoManifests {
+		if _, err := os.Stat(filepath.Join(d.path, name)); err == nil {
+			return name
+		}
+	}
+	return ""
 }
 
-// GetVersionFiles returns paths to all version-containing files
-func (d *DenoEcosystem) GetVersionFiles() []string {
-	// Check for deno.json
-	denoPath := filepath.Join(d.path, "deno.json")
-	if _, err := os.Stat(denoPath); err == nil {
-		return []string{"deno.json"}
+// readManifest parses a manifest in the package. deno.json and deno.jsonc may
+// hold comments, as Deno allows; jsr.json must be plain JSON.
+func (d *DenoEcosystem) readManifest(name string) (*DenoConfig, error) {
+	content, err := fileutil.ReadFile(filepath.Join(d.path, name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
 	}
 
-	// Check for deno.jsonc
-	denoPath = filepath.Join(d.path, "deno.jsonc")
-	if _, err := os.Stat(denoPath); err == nil {
-		return []string{"deno.jsonc"}
+	parsed := content
+	if name != "jsr.json" {
+		parsed = blankJSONComments(content)
+	} else if !json.Valid(content) && json.Valid(blankJSONComments(content)) {
+		return nil, fmt.Errorf("failed to parse jsr.json: comments are not allowed in jsr.json")
 	}
 
-	return []string{}
+	var config DenoConfig
+	if err := json.Unmarshal(parsed, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	return &config, nil
 }
 
 // DetectDenoEcosystem checks if a directory contains a Deno project
 func DetectDenoEcosystem(path string) bool {
-	// Check for deno.json
-	if _, err := os.Stat(filepath.Join(path, "deno.json")); err == nil {
-		return true
-	}
-
-	// Check for deno.jsonc
-	if _, err := os.Stat(filepath.Join(path, "deno.jsonc")); err == nil {
-		return true
+	// Check for jsr.json, deno.json or deno.jsonc
+	for _, name := range denoManifests {
+		if _, err := os.Stat(filepath.Join(path, name)); err == nil {
+			return true
+		}
 	}
 
 	// Check for mod.ts (common Deno entry point)
@@ -129,73 +173,72 @@ func DetectDenoEcosystem(path string) bool {
 	return false
 }
 
-// stripJSONComments removes single-line (//) and multi-line (/* */) comments
-// from JSONC content using a state-machine parser. This correctly handles
-// comments inside strings (e.g., URLs containing "//") by tracking whether
-// we are inside a JSON string literal.
-func stripJSONComments(content []byte) []byte {
-	result := make([]byte, 0, len(content))
-	i := 0
-	n := len(content)
-
-	for i < n {
-		ch := content[i]
-
-		// Handle string literals - pass through unchanged
-		if ch == '"' {
-			result = append(result, ch)
-			i++
-			for i < n {
-				sch := content[i]
-				result = append(result, sch)
-				if sch == '\\' {
-					// Skip escaped character
-					i++
-					if i < n {
-						result = append(result, content[i])
-						i++
-					}
-					continue
-				}
-				if sch == '"' {
-					i++
-					break
-				}
-				i++
+// blankJSONComments replaces single-line (//) and multi-line (/* */)
+// comments and trailing commas in JSONC content with spaces, keeping line
+// breaks, so the result parses as JSON with every value at its original
+// offset. Strings are tracked, so "//" inside a URL is left alone.
+func blankJSONComments(content []byte) []byte {
+	result := append([]byte(nil), content...)
+	n := len(result)
+	blank := func(from, to int) {
+		for j := from; j < to; j++ {
+			if result[j] != '\n' && result[j] != '\r' {
+				result[j] = ' '
 			}
-			continue
 		}
+	}
 
-		// Check for comments (only outside strings)
-		if ch == '/' && i+1 < n {
-			next := content[i+1]
-
-			// Single-line comment: skip until end of line
-			if next == '/' {
-				i += 2
-				for i < n && content[i] != '\n' {
-					i++
-				}
-				continue
+	for i := 0; i < n; i++ {
+		switch {
+		case result[i] == '"':
+			i = jsonStringEnd(result, i)
+		case result[i] == '/' && i+1 < n && result[i+1] == '/':
+			start := i
+			for i < n && result[i] != '\n' {
+				i++
 			}
-
-			// Multi-line comment: skip until */
-			if next == '*' {
-				i += 2
-				for i+1 < n {
-					if content[i] == '*' && content[i+1] == '/' {
-						i += 2
-						break
-					}
-					i++
-				}
-				continue
+			blank(start, i)
+		case result[i] == '/' && i+1 < n && result[i+1] == '*':
+			start := i
+			i += 2
+			for i+1 < n && !(result[i] == '*' && result[i+1] == '/') {
+				i++
 			}
+			i = min(i+2, n)
+			blank(start, i)
+			i--
 		}
-
-		result = append(result, ch)
-		i++
 	}
 
+	// Trailing commas go once comments are blanked, so a comment between
+	// the comma and the closing bracket does not hide it
+	for i := 0; i < n; i++ {
+		switch result[i] {
+		case '"':
+			i = jsonStringEnd(result, i)
+		case ',':
+			j := i + 1
+			for j < n && (result[j] == ' ' || result[j] == '\t' || result[j] == '\n' || result[j] == '\r') {
+				j++
+			}
+			if j < n && (result[j] == '}' || result[j] == ']') {
+				result[i] = ' '
+			}
+		}
+	}
 	return result
 }
+
+// jsonStringEnd returns the index of the quote closing the string that opens
+// at content[start]
+func jsonStringEnd(content []byte, start int) int {
+	for i := start + 1; i < len(content); i++ {
+		switch content[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return len(content)
+}
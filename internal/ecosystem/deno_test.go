@@ -3,6 +3,7 @@ package ecosystem
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/NatoNathan/shipyard/pkg/semver"
@@ -248,3 +249,97 @@ func TestDetectDenoEcosystem(t *testing.T) {
 		assert.False(t, detected)
 	})
 }
+
+// TestDenoEcosystem_JSRManifest tests packages published to JSR from jsr.json
+func TestDenoEcosystem_JSRManifest(t *testing.T) {
+	t.Run("jsr.json alone", func(t *testing.T) {
+		tempDir := t.TempDir()
+		jsrPath := filepath.Join(tempDir, "jsr.json")
+		content := "{\n  \"name\": \"@scope/lib\",\n  \"version\": \"0.3.0\",\n  \"exports\": {\".\": \"./mod.ts\"}\n}\n"
+		require.NoError(t, os.WriteFile(jsrPath, []byte(content), 0644))
+
+		deno := NewDenoEcosystem(tempDir)
+		assert.Equal(t, []string{"jsr.json"}, deno.GetVersionFiles())
+		version, err := deno.ReadVersion()
+		require.NoError(t, err)
+		assert.Equal(t, "0.3.0", version.String())
+
+		require.NoError(t, deno.UpdateVersion(semver.MustParse("0.4.0")))
+		updated, err := os.ReadFile(jsrPath)
+		require.NoError(t, err)
+		assert.Equal(t, "{\n  \"name\": \"@scope/lib\",\n  \"version\": \"0.4.0\",\n  \"exports\": {\".\": \"./mod.ts\"}\n}\n", string(updated))
+	})
+
+	t.Run("jsr.json preferred over deno.json", func(t *testing.T) {
+		tempDir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, "jsr.json"), []byte(`{"name": "@scope/lib", "version": "1.1.0"}`), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, "deno.json"), []byte(`{"version": "1.0.0", "tasks": {"test": "deno test"}}`), 0644))
+
+		deno := NewDenoEcosystem(tempDir)
+		assert.Equal(t, []string{"jsr.json", "deno.json"}, deno.GetVersionFiles())
+		version, err := deno.ReadVersion()
+		require.NoError(t, err)
+		assert.Equal(t, "1.1.0", version.String())
+
+		require.NoError(t, deno.UpdateVersion(semver.MustParse("1.2.0")))
+		jsr, err := os.ReadFile(filepath.Join(tempDir, "jsr.json"))
+		require.NoError(t, err)
+		assert.Equal(t, `{"name": "@scope/lib", "version": "1.2.0"}`, string(jsr))
+		denoJSON, err := os.ReadFile(filepath.Join(tempDir, "deno.json"))
+		require.NoError(t, err)
+		assert.Equal(t, `{"version": "1.2.0", "tasks": {"test": "deno test"}}`, string(denoJSON), "deno.json stays in sync")
+	})
+
+	t.Run("deno.json without version is left alone", func(t *testing.T) {
+		tempDir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, "jsr.json"), []byte(`{"version": "1.1.0"}`), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, "deno.json"), []byte(`{"tasks": {}}`), 0644))
+
+		assert.Equal(t, []string{"jsr.json"}, NewDenoEcosystem(tempDir).GetVersionFiles())
+	})
+
+	t.Run("comments in jsr.json are rejected", func(t *testing.T) {
+		tempDir := t.TempDir()
+		jsrPath := filepath.Join(tempDir, "jsr.json")
+		content := "{\"version\": \"1.0.0\"} // published to JSR\n"
+		require.NoError(t, os.WriteFile(jsrPath, []byte(content), 0644))
+
+		deno := NewDenoEcosystem(tempDir)
+		_, err := deno.ReadVersion()
+		assert.ErrorContains(t, err, "comments are not allowed in jsr.json")
+
+		err = deno.UpdateVersion(semver.MustParse("1.1.0"))
+		assert.ErrorContains(t, err, "comments are not allowed in jsr.json")
+		unchanged, readErr := os.ReadFile(jsrPath)
+		require.NoError(t, readErr)
+		assert.Equal(t, content, string(unchanged))
+	})
+}
+
+// TestDenoEcosystem_UpdateVersionJSONC tests targeted edits in deno.jsonc
+func TestDenoEcosystem_UpdateVersionJSONC(t *testing.T) {
+	tempDir := t.TempDir()
+	denoPath := filepath.Join(tempDir, "deno.jsonc")
+	content := `{
+  // "version": "0.0.1" was the first release
+  "name": "@scope/app",
+  "version": "1.0.0", /* bumped by shipyard */
+  "imports": {
+    "std/": "https://deno.land/std@0.200.0/", // pinned
+  },
+  "compilerOptions": {"strict": true},
+}
+`
+	require.NoError(t, os.WriteFile(denoPath, []byte(content), 0644))
+
+	deno := NewDenoEcosystem(tempDir)
+	version, err := deno.ReadVersion()
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", version.String())
+
+	require.NoError(t, deno.UpdateVersion(semver.MustParse("1.0.1")))
+	updated, err := os.ReadFile(denoPath)
+	require.NoError(t, err)
+	assert.Equal(t, strings.Replace(content, `"version": "1.0.0"`, `"version": "1.0.1"`, 1), string(updated),
+		"only the top-level version changes; comments and trailing commas are kept")
+}
@@ -0,0 +1,127 @@
+package ecosystem
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/NatoNathan/shipyard/internal/config"
+)
+
+// DependencyUpdate is a dependency whose version changed between two
+// revisions of a manifest. From is empty for an added dependency and To is
+// empty for a removed one.
+type DependencyUpdate struct {
+	Name string `json:"name"`
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+}
+
+// String describes the update for a consignment summary
+func (u DependencyUpdate) String() string {
+	switch {
+	case u.From == "":
+		return fmt.Sprintf("Add %s %s", u.Name, u.To)
+	case u.To == "":
+		return fmt.Sprintf("Remove %s", u.Name)
+	default:
+		return fmt.Sprintf("Update %s from %s to %s", u.Name, u.From, u.To)
+	}
+}
+
+// DependencyManifest returns the file, relative to the package directory,
+// that lists a package's dependencies. ok is false for ecosystems whose
+// dependencies are not read.
+func DependencyManifest(eco string) (string, bool) {
+	switch eco {
+	case config.EcosystemGo:
+		return "go.mod", true
+	case config.EcosystemNPM:
+		return "package.json", true
+	}
+	return "", false
+}
+
+// ParseDependencies returns the dependency versions a manifest of the given
+// ecosystem declares, by name. Indirect Go requirements are left out.
+func ParseDependencies(eco string, content []byte) (map[string]string, error) {
+	switch eco {
+	case config.EcosystemGo:
+		return parseGoModRequires(content), nil
+	case config.EcosystemNPM:
+		return parsePackageJSONDependencies(content)
+	}
+	return nil, fmt.Errorf("dependencies are not read for %s packages", eco)
+}
+
+// DiffDependencies lists the dependencies added, removed or changed between
+// two manifests, sorted by name
+func DiffDependencies(before, after map[string]string) []DependencyUpdate {
+	var updates []DependencyUpdate
+	for name, to := range after {
+		if from := before[name]; from != to {
+			updates = append(updates, DependencyUpdate{Name: name, From: from, To: to})
+		}
+	}
+	for name, from := range before {
+		if _, ok := after[name]; !ok {
+			updates = append(updates, DependencyUpdate{Name: name, From: from})
+		}
+	}
+	sort.Slice(updates, func(i, j int) bool { return updates[i].Name < updates[j].Name })
+	return updates
+}
+
+// parseGoModRequires reads the direct requirements of a go.mod, in both the
+// single-line and block forms of the require directive
+func parseGoModRequires(content []byte) map[string]string {
+	requires := make(map[string]string)
+	inBlock := false
+	for _, line := range strings.Split(string(content), "\n") {
+		line, comment, _ := strings.Cut(line, "//")
+		fields := strings.Fields(line)
+		switch {
+		case inBlock && len(fields) == 1 && fields[0] == ")":
+			inBlock = false
+			continue
+		case !inBlock && len(fields) >= 2 && fields[0] == "require" && fields[1] == "(":
+			inBlock = true
+			continue
+		case !inBlock && len(fields) == 3 && fields[0] == "require":
+			fields = fields[1:]
+		case !inBlock:
+			continue
+		}
+		if len(fields) != 2 || strings.TrimSpace(comment) == "indirect" {
+			continue
+		}
+		requires[fields[0]] = fields[1]
+	}
+	return requires
+}
+
+// parsePackageJSONDependencies reads every dependency list of a
+// package.json. A name listed twice keeps the version of the list that
+// ships with the package.
+func parsePackageJSONDependencies(content []byte) (map[string]string, error) {
+	var manifest struct {
+		Dependencies         map[string]string `json:"dependencies"`
+		OptionalDependencies map[string]string `json:"optionalDependencies"`
+		PeerDependencies     map[string]string `json:"peerDependencies"`
+		DevDependencies      map[string]string `json:"devDependencies"`
+	}
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse package.json: %w", err)
+	}
+
+	dependencies := make(map[string]string)
+	for _, list := range []map[string]string{manifest.Dependencies, manifest.OptionalDependencies, manifest.PeerDependencies, manifest.DevDependencies} {
+		for name, version := range list {
+			if _, ok := dependencies[name]; !ok {
+				dependencies[name] = version
+			}
+		}
+	}
+	return dependencies, nil
+}
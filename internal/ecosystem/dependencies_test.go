@@ -0,0 +1,100 @@
+package ecosystem
+
+import (
+	"testing"
+
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffDependencies_GoMod(t *testing.T) {
+	before := `module github.com/example/core
+
+// version: 1.2.0
+
+go 1.24
+
+require github.com/spf13/cobra v1.9.1
+
+require (
+	github.com/stretchr/testify v1.10.0
+	gopkg.in/yaml.v3 v3.0.1
+	github.com/davecgh/go-spew v1.1.1 // indirect
+)
+`
+	after := `module github.com/example/core
+
+// version: 1.2.0
+
+go 1.25
+
+require github.com/spf13/cobra v1.10.2
+
+require (
+	github.com/stretchr/testify v1.11.1
+	golang.org/x/sync v0.20.0 // pinned for errgroup
+	github.com/davecgh/go-spew v1.1.2 // indirect
+)
+`
+	old, err := ParseDependencies(config.EcosystemGo, []byte(before))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"github.com/spf13/cobra":      "v1.9.1",
+		"github.com/stretchr/testify": "v1.10.0",
+		"gopkg.in/yaml.v3":            "v3.0.1",
+	}, old)
+	current, err := ParseDependencies(config.EcosystemGo, []byte(after))
+	require.NoError(t, err)
+
+	assert.Equal(t, []DependencyUpdate{
+		{Name: "github.com/spf13/cobra", From: "v1.9.1", To: "v1.10.2"},
+		{Name: "github.com/stretchr/testify", From: "v1.10.0", To: "v1.11.1"},
+		{Name: "golang.org/x/sync", To: "v0.20.0"},
+		{Name: "gopkg.in/yaml.v3", From: "v3.0.1"},
+	}, DiffDependencies(old, current), "indirect requirements are left out")
+}
+
+func TestDiffDependencies_PackageJSON(t *testing.T) {
+	before := `{
+  "name": "@example/web",
+  "version": "2.0.0",
+  "dependencies": {"react": "^18.2.0", "lodash": "^4.17.20"},
+  "devDependencies": {"typescript": "~5.4.0", "react": "^18.2.0"}
+}`
+	after := `{
+  "name": "@example/web",
+  "version": "2.0.0",
+  "dependencies": {"react": "^19.0.0", "lodash": "^4.17.20"},
+  "peerDependencies": {"react-dom": ">=18"},
+  "devDependencies": {"typescript": "~5.6.2"}
+}`
+	old, err := ParseDependencies(config.EcosystemNPM, []byte(before))
+	require.NoError(t, err)
+	current, err := ParseDependencies(config.EcosystemNPM, []byte(after))
+	require.NoError(t, err)
+
+	updates := DiffDependencies(old, current)
+	assert.Equal(t, []DependencyUpdate{
+		{Name: "react", From: "^18.2.0", To: "^19.0.0"},
+		{Name: "react-dom", To: ">=18"},
+		{Name: "typescript", From: "~5.4.0", To: "~5.6.2"},
+	}, updates)
+	assert.Equal(t, "Update react from ^18.2.0 to ^19.0.0", updates[0].String())
+	assert.Equal(t, "Add react-dom >=18", updates[1].String())
+	assert.Equal(t, "Remove lodash", DependencyUpdate{Name: "lodash", From: "^4.17.20"}.String())
+
+	_, err = ParseDependencies(config.EcosystemNPM, []byte("{"))
+	assert.Error(t, err)
+}
+
+func TestDependencyManifest(t *testing.T) {
+	manifest, ok := DependencyManifest(config.EcosystemGo)
+	assert.True(t, ok)
+	assert.Equal(t, "go.mod", manifest)
+	manifest, ok = DependencyManifest(config.EcosystemNPM)
+	assert.True(t, ok)
+	assert.Equal(t, "package.json", manifest)
+	_, ok = DependencyManifest(config.EcosystemHelm)
+	assert.False(t, ok)
+}
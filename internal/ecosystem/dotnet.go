@@ -0,0 +1,231 @@
+package ecosystem
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/NatoNathan/shipyard/internal/fileutil"
+
+	"github.com/NatoNathan/shipyard/pkg/semver"
+)
+
+var _ Handler = (*DotnetEcosystem)(nil)
+
+// directoryBuildProps is the MSBuild file that centralizes properties for
+// every project below it
+const directoryBuildProps = "Directory.Build.props"
+
+// dotnetVersionRe matches a <Version> element and captures its text. Related
+// properties such as <VersionPrefix> are not matched.
+var dotnetVersionRe = regexp.MustCompile(`(<Version(?:\s[^>]*)?>)([^<]*)(</Version>)`)
+
+// xmlCommentRe matches XML comments, which are ignored when looking for <Version>
+var xmlCommentRe = regexp.MustCompile(`(?s)<!--.*?-->`)
+
+// DotnetEcosystem handles version management for .NET projects
+type DotnetEcosystem struct {
+	versionParser
+
+	path    string
+	options *DotnetEcosystemOptions
+}
+
+// DotnetEcosystemOptions configures .NET ecosystem behavior
+type DotnetEcosystemOptions struct {
+	Manifest string // csproj or props file relative to the package path; found automatically when empty
+}
+
+// NewDotnetEcosystem creates a new .NET ecosystem handler with default options
+func NewDotnetEcosystem(path string) *DotnetEcosystem {
+	return NewDotnetEcosystemWithOptions(path, nil)
+}
+
+// NewDotnetEcosystemWithOptions creates a new .NET ecosystem handler with custom options
+func NewDotnetEcosystemWithOptions(path string, options *DotnetEcosystemOptions) *DotnetEcosystem {
+	if options == nil {
+		options = &DotnetEcosystemOptions{}
+	}
+	return &DotnetEcosystem{
+		path:    path,
+		options: options,
+	}
+}
+
+// ReadVersion reads the current version from the <Version> property of the
+// project file, falling back to the nearest Directory.Build.props
+func (d *DotnetEcosystem) ReadVersion() (semver.Version, error) {
+	versionFile, err := d.versionFile()
+	if err != nil {
+		return semver.Version{}, err
+	}
+
+	content, err := fileutil.ReadFile(versionFile)
+	if err != nil {
+		return semver.Version{}, fmt.Errorf("failed to read %s: %w", filepath.Base(versionFile), err)
+	}
+
+	loc := findDotnetVersion(content)
+	if loc == nil {
+		return semver.Version{}, fmt.Errorf("no <Version> property found in %s", filepath.Base(versionFile))
+	}
+
+	value := strings.TrimSpace(string(content[loc[4]:loc[5]]))
+	if value == "" {
+		return semver.Version{}, fmt.Errorf("empty <Version> property in %s", filepath.Base(versionFile))
+	}
+	return d.parseVersion(value)
+}
+
+// UpdateVersion rewrites the text of every <Version> element in the version
+// file. Everything else, including the XML declaration, byte order mark,
+// attributes and whitespace, is left untouched.
+func (d *DotnetEcosystem) UpdateVersion(version semver.Version) error {
+	versionFile, err := d.versionFile()
+	if err != nil {
+		return err
+	}
+
+	content, err := fileutil.ReadFile(versionFile)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filepath.Base(versionFile), err)
+	}
+
+	masked := maskXMLComments(content)
+	matches := dotnetVersionRe.FindAllSubmatchIndex(masked, -1)
+	if len(matches) == 0 {
+		return fmt.Errorf("no <Version> property found in %s", filepath.Base(versionFile))
+	}
+
+	var updated []byte
+	last := 0
+	for _, m := range matches {
+		updated = append(updated, content[last:m[4]]...)
+		updated = append(updated, version.String()...)
+		last = m[5]
+	}
+	updated = append(updated, content[last:]...)
+
+	return fileutil.WriteFile(versionFile, updated, 0644)
+}
+
+// GetVersionFiles returns the file holding the version, relative to the
+// package path. A shared Directory.Build.props may be outside the package.
+func (d *DotnetEcosystem) GetVersionFiles() []string {
+	versionFile, err := d.versionFile()
+	if err != nil {
+		return []string{}
+	}
+	rel, err := filepath.Rel(d.path, versionFile)
+	if err != nil {
+		return []string{}
+	}
+	return []string{rel}
+}
+
+// manifest returns the configured manifest, or the single .csproj in the
+// package directory, or a Directory.Build.props next to it
+func (d *DotnetEcosystem) manifest() (string, error) {
+	if d.options.Manifest != "" {
+		manifest := filepath.Join(d.path, d.options.Manifest)
+		if _, err := os.Stat(manifest); err != nil {
+			return "", fmt.Errorf("configured manifest %s not found", d.options.Manifest)
+		}
+		return manifest, nil
+	}
+
+	projects, err := filepath.Glob(filepath.Join(d.path, "*.csproj"))
+	if err != nil {
+		return "", err
+	}
+	switch len(projects) {
+	case 1:
+		return projects[0], nil
+	case 0:
+		props := filepath.Join(d.path, directoryBuildProps)
+		if _, err := os.Stat(props); err == nil {
+			return props, nil
+		}
+		return "", fmt.Errorf("no .csproj or %s found in %s", directoryBuildProps, d.path)
+	default:
+		names := make([]string, len(projects))
+		for i, p := range projects {
+			names[i] = filepath.Base(p)
+		}
+		return "", fmt.Errorf("multiple project files found (%s); set options.manifest to choose one", strings.Join(names, ", "))
+	}
+}
+
+// versionFile returns the file that holds the <Version> property. When a
+// project file lacks it, the nearest Directory.Build.props above the project
+// is used, as MSBuild would.
+func (d *DotnetEcosystem) versionFile() (string, error) {
+	manifest, err := d.manifest()
+	if err != nil {
+		return "", err
+	}
+
+	content, err := fileutil.ReadFile(manifest)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", filepath.Base(manifest), err)
+	}
+	if findDotnetVersion(content) != nil {
+		return manifest, nil
+	}
+
+	if filepath.Base(manifest) != directoryBuildProps {
+		if props := findDirectoryBuildProps(filepath.Dir(manifest)); props != "" {
+			if propsContent, err := fileutil.ReadFile(props); err == nil && findDotnetVersion(propsContent) != nil {
+				return props, nil
+			}
+		}
+	}
+
+	name := filepath.Base(manifest)
+	return "", fmt.Errorf("no <Version> property found in %s or a %s above it; add <Version>1.0.0</Version> to a <PropertyGroup> in %s or %s",
+		name, directoryBuildProps, name, directoryBuildProps)
+}
+
+// findDirectoryBuildProps walks up from dir to the repository root looking
+// for Directory.Build.props
+func findDirectoryBuildProps(dir string) string {
+	for {
+		props := filepath.Join(dir, directoryBuildProps)
+		if _, err := os.Stat(props); err == nil {
+			return props
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return ""
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// findDotnetVersion returns the submatch indexes of the first <Version>
+// element outside a comment, or nil
+func findDotnetVersion(content []byte) []int {
+	return dotnetVersionRe.FindSubmatchIndex(maskXMLComments(content))
+}
+
+// maskXMLComments blanks out comments while keeping byte offsets intact
+func maskXMLComments(content []byte) []byte {
+	masked := append([]byte(nil), content...)
+	for _, loc := range xmlCommentRe.FindAllIndex(masked, -1) {
+		for i := loc[0]; i < loc[1]; i++ {
+			masked[i] = ' '
+		}
+	}
+	return masked
+}
+
+// DetectDotnetEcosystem checks if a directory contains a .NET project
+func DetectDotnetEcosystem(path string) bool {
+	projects, err := filepath.Glob(filepath.Join(path, "*.csproj"))
+	return err == nil && len(projects) > 0
+}
@@ -0,0 +1,171 @@
+package ecosystem
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/NatoNathan/shipyard/pkg/semver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sdkStyleCsproj = "\ufeff" + `<?xml version="1.0" encoding="utf-8"?>
+<Project Sdk="Microsoft.NET.Sdk">
+
+  <PropertyGroup>
+    <TargetFramework>net8.0</TargetFramework>
+    <Nullable>enable</Nullable>
+  </PropertyGroup>
+
+  <!-- <Version>0.0.1</Version> -->
+  <PropertyGroup Label="Packaging">
+    <PackageId>Acme.Orders</PackageId>
+    <VersionPrefix>9.9.9</VersionPrefix>
+    <Version Condition="'$(Version)' == ''">1.4.2</Version>
+  </PropertyGroup>
+
+</Project>
+`
+
+const centralProps = `<Project>
+  <PropertyGroup>
+    <Company>Acme</Company>
+    <Version>2.0.0</Version>
+  </PropertyGroup>
+</Project>
+`
+
+const noVersionCsproj = `<Project Sdk="Microsoft.NET.Sdk">
+  <PropertyGroup>
+    <TargetFramework>net8.0</TargetFramework>
+  </PropertyGroup>
+</Project>
+`
+
+// writeDotnetFile writes a fixture file, creating parent directories
+func writeDotnetFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+// TestDotnetEcosystem_SDKStyleProject tests reading and updating a version
+// held in the project file itself
+func TestDotnetEcosystem_SDKStyleProject(t *testing.T) {
+	tempDir := t.TempDir()
+	csprojPath := filepath.Join(tempDir, "Orders.csproj")
+	writeDotnetFile(t, csprojPath, sdkStyleCsproj)
+
+	dotnet := NewDotnetEcosystem(tempDir)
+
+	version, err := dotnet.ReadVersion()
+	require.NoError(t, err)
+	assert.Equal(t, "1.4.2", version.String())
+	assert.Equal(t, []string{"Orders.csproj"}, dotnet.GetVersionFiles())
+
+	require.NoError(t, dotnet.UpdateVersion(semver.Version{Major: 1, Minor: 5, Patch: 0}))
+
+	content, err := os.ReadFile(csprojPath)
+	require.NoError(t, err)
+	assert.Equal(t,
+		replaceOnce(t, sdkStyleCsproj, `'$(Version)' == ''">1.4.2</Version>`, `'$(Version)' == ''">1.5.0</Version>`),
+		string(content),
+		"only the element text changes; BOM, declaration, attributes and comments are preserved")
+}
+
+// TestDotnetEcosystem_CentralProps tests the Directory.Build.props fallback
+func TestDotnetEcosystem_CentralProps(t *testing.T) {
+	repo := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(repo, ".git"), 0755))
+	propsPath := filepath.Join(repo, "Directory.Build.props")
+	writeDotnetFile(t, propsPath, centralProps)
+	servicePath := filepath.Join(repo, "src", "Billing")
+	writeDotnetFile(t, filepath.Join(servicePath, "Billing.csproj"), noVersionCsproj)
+
+	dotnet := NewDotnetEcosystem(servicePath)
+
+	version, err := dotnet.ReadVersion()
+	require.NoError(t, err)
+	assert.Equal(t, "2.0.0", version.String())
+	assert.Equal(t, []string{filepath.Join("..", "..", "Directory.Build.props")}, dotnet.GetVersionFiles())
+
+	require.NoError(t, dotnet.UpdateVersion(semver.Version{Major: 2, Minor: 1, Patch: 0}))
+
+	props, err := os.ReadFile(propsPath)
+	require.NoError(t, err)
+	assert.Equal(t, replaceOnce(t, centralProps, "2.0.0", "2.1.0"), string(props))
+	csproj, err := os.ReadFile(filepath.Join(servicePath, "Billing.csproj"))
+	require.NoError(t, err)
+	assert.Equal(t, noVersionCsproj, string(csproj))
+}
+
+// TestDotnetEcosystem_ConfiguredManifest tests choosing the manifest explicitly
+func TestDotnetEcosystem_ConfiguredManifest(t *testing.T) {
+	tempDir := t.TempDir()
+	writeDotnetFile(t, filepath.Join(tempDir, "Api.csproj"), sdkStyleCsproj)
+	writeDotnetFile(t, filepath.Join(tempDir, "Api.Tests.csproj"), noVersionCsproj)
+	writeDotnetFile(t, filepath.Join(tempDir, "Directory.Build.props"), centralProps)
+
+	_, err := NewDotnetEcosystem(tempDir).ReadVersion()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "multiple project files found (Api.Tests.csproj, Api.csproj); set options.manifest")
+
+	version, err := NewDotnetEcosystemWithOptions(tempDir, &DotnetEcosystemOptions{Manifest: "Api.csproj"}).ReadVersion()
+	require.NoError(t, err)
+	assert.Equal(t, "1.4.2", version.String())
+
+	version, err = NewDotnetEcosystemWithOptions(tempDir, &DotnetEcosystemOptions{Manifest: "Directory.Build.props"}).ReadVersion()
+	require.NoError(t, err)
+	assert.Equal(t, "2.0.0", version.String())
+
+	_, err = NewDotnetEcosystemWithOptions(tempDir, &DotnetEcosystemOptions{Manifest: "Missing.csproj"}).ReadVersion()
+	assert.ErrorContains(t, err, "configured manifest Missing.csproj not found")
+}
+
+// TestDotnetEcosystem_MissingVersion tests the error for a project without a version
+func TestDotnetEcosystem_MissingVersion(t *testing.T) {
+	tempDir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(tempDir, ".git"), 0755))
+	writeDotnetFile(t, filepath.Join(tempDir, "Worker.csproj"), noVersionCsproj)
+
+	dotnet := NewDotnetEcosystem(tempDir)
+
+	_, err := dotnet.ReadVersion()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no <Version> property found in Worker.csproj or a Directory.Build.props above it")
+	assert.Contains(t, err.Error(), "add <Version>1.0.0</Version> to a <PropertyGroup> in Worker.csproj or Directory.Build.props")
+
+	assert.Error(t, dotnet.UpdateVersion(semver.Version{Major: 1}))
+	assert.Empty(t, dotnet.GetVersionFiles())
+
+	_, err = NewDotnetEcosystem(t.TempDir()).ReadVersion()
+	assert.ErrorContains(t, err, "no .csproj or Directory.Build.props found")
+}
+
+// TestDotnetEcosystem_CalVer tests calendar-versioned projects
+func TestDotnetEcosystem_CalVer(t *testing.T) {
+	tempDir := t.TempDir()
+	writeDotnetFile(t, filepath.Join(tempDir, "App.csproj"), `<Project><PropertyGroup><Version>2026.10.3</Version></PropertyGroup></Project>`)
+
+	dotnet := NewDotnetEcosystem(tempDir)
+	dotnet.SetCalVerFormat("YYYY.0M.MICRO")
+	version, err := dotnet.ReadVersion()
+	require.NoError(t, err)
+	assert.Equal(t, "2026.10.3", version.String())
+}
+
+func TestDetectDotnetEcosystem(t *testing.T) {
+	tempDir := t.TempDir()
+	assert.False(t, DetectDotnetEcosystem(tempDir))
+	writeDotnetFile(t, filepath.Join(tempDir, "App.csproj"), noVersionCsproj)
+	assert.True(t, DetectDotnetEcosystem(tempDir))
+}
+
+// replaceOnce replaces exactly one occurrence of old in s
+func replaceOnce(t *testing.T, s, old, new string) string {
+	t.Helper()
+	require.Contains(t, s, old)
+	return strings.Replace(s, old, new, 1)
+}
@@ -16,6 +16,8 @@ type Handler interface {
 type HandlerContext struct {
 	AllVersions   map[string]semver.Version // All package versions (new versions after bumps)
 	PackageConfig *config.Package           // Full package configuration
+	Packages      []config.Package          // All configured packages
+	ProjectPath   string                    // Project root that package paths are relative to
 }
 
 // HandlerWithContext is an optional interface for handlers that need additional context
@@ -23,3 +25,59 @@ type HandlerWithContext interface {
 	Handler
 	SetContext(ctx *HandlerContext)
 }
+
+// WarningReporter is an optional interface for handlers that leave files
+// they cannot update, such as lockfiles of other package managers. Warnings
+// describes what the last UpdateVersion left for the user to fix.
+type WarningReporter interface {
+	Handler
+	Warnings() []string
+}
+
+// CalVerAware is an optional interface for handlers that can read versions
+// written in a calendar versioning scheme
+type CalVerAware interface {
+	Handler
+	SetCalVerFormat(format string)
+}
+
+// versionParser parses version strings read from version files. Handlers
+// embed it so calendar-versioned packages are read with their CalVer format.
+type versionParser struct {
+	calverFormat string
+}
+
+// SetCalVerFormat makes the handler parse versions as CalVer in the given format
+func (p *versionParser) SetCalVerFormat(format string) {
+	p.calverFormat = format
+}
+
+// parseVersion parses a version string using the handler's versioning scheme
+func (p *versionParser) parseVersion(s string) (semver.Version, error) {
+	if p.calverFormat != "" {
+		return semver.ParseCalVer(p.calverFormat, s)
+	}
+	return semver.Parse(s)
+}
+
+// tagOnlyHandler wraps a handler for a package whose version lives in git
+// tags: it still reads a version file when one exists, but never writes one
+type tagOnlyHandler struct {
+	Handler
+}
+
+// NewTagOnlyHandler returns a handler that reads versions through h and
+// leaves the package's files untouched on release
+func NewTagOnlyHandler(h Handler) Handler {
+	return &tagOnlyHandler{Handler: h}
+}
+
+// UpdateVersion is a no-op; the release tag records the new version
+func (h *tagOnlyHandler) UpdateVersion(version semver.Version) error {
+	return nil
+}
+
+// GetVersionFiles returns no files since none are updated
+func (h *tagOnlyHandler) GetVersionFiles() []string {
+	return []string{}
+}
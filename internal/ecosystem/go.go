@@ -16,15 +16,36 @@ var _ Handler = (*GoEcosystem)(nil)
 
 // GoEcosystem handles version management for Go projects
 type GoEcosystem struct {
+	versionParser
+
 	path    string
 	options *GoEcosystemOptions
 }
 
 // GoEcosystemOptions configures Go ecosystem behavior
 type GoEcosystemOptions struct {
-	TagOnly bool // If true, only create git tags without updating version files
+	TagOnly      bool     // If true, only create git tags without updating version files
+	VersionFiles []string // Files holding the version, relative to the package; detected when empty
 }
 
+// goVersionFileCandidates are the files detected when no version files are
+// configured, in the order the version is read from
+var goVersionFileCandidates = []string{"version.go", "VERSION", ".version", "go.mod"}
+
+// goVersionPattern matches a version with an optional pre-release suffix
+const goVersionPattern = `[0-9]+\.[0-9]+\.[0-9]+(?:-[a-zA-Z0-9._-]+)?`
+
+var (
+	// goSourceVersionRe matches a Version constant or variable in Go source,
+	// standalone (const Version = "1.2.3", var Version string = "1.2.3") or
+	// inside a const or var block (Version = "1.2.3"). The quote and any v
+	// prefix are captured so updates keep them.
+	goSourceVersionRe = regexp.MustCompile(`(?m)^(\s*(?:(?:const|var)\s+)?Version(?:\s+string)?\s*=\s*)(["'\x60])(v?)(` + goVersionPattern + `)["'\x60]`)
+
+	// goModVersionRe matches a version comment in go.mod: // version: 1.2.3
+	goModVersionRe = regexp.MustCompile(`(?m)^(//\s*version:\s*)(` + goVersionPattern + `)`)
+)
+
 // NewGoEcosystem creates a new Go ecosystem handler with default options
 func NewGoEcosystem(path string) *GoEcosystem {
 	return &GoEcosystem{
@@ -44,55 +65,34 @@ func NewGoEcosystemWithOptions(path string, options *GoEcosystemOptions) *GoEcos
 	}
 }
 
-// ReadVersion reads the current version from Go version files
-// Checks in order: version.go, go.mod
+// ReadVersion reads the current version from the first version file: the
+// first configured one, or the first of version.go, VERSION, .version and
+// go.mod that exists
 func (g *GoEcosystem) ReadVersion() (semver.Version, error) {
-	// Try version.go first
-	versionGoPath := filepath.Join(g.path, "version.go")
-	if _, err := os.Stat(versionGoPath); err == nil {
-		return g.readVersionFromVersionGo(versionGoPath)
+	files := g.versionFiles()
+	if len(files) == 0 {
+		return semver.Version{}, fmt.Errorf("no version file found in Go project at %s", g.path)
 	}
-
-	// Try go.mod
-	goModPath := filepath.Join(g.path, "go.mod")
-	if _, err := os.Stat(goModPath); err == nil {
-		return g.readVersionFromGoMod(goModPath)
-	}
-
-	return semver.Version{}, fmt.Errorf("no version file found in Go project at %s", g.path)
+	return g.readVersionFile(filepath.Join(g.path, files[0]))
 }
 
-// UpdateVersion updates the version in Go version files
+// UpdateVersion writes the version to every version file, so configured
+// files stay in sync
 func (g *GoEcosystem) UpdateVersion(version semver.Version) error {
 	// In tag-only mode, skip file updates
 	if g.options != nil && g.options.TagOnly {
 		return nil
 	}
 
-	updated := false
-
-	// Update version.go if it exists
-	versionGoPath := filepath.Join(g.path, "version.go")
-	if _, err := os.Stat(versionGoPath); err == nil {
-		if err := g.updateVersionGo(versionGoPath, version); err != nil {
-			return err
-		}
-		updated = true
+	files := g.versionFiles()
+	if len(files) == 0 {
+		return fmt.Errorf("no version files to update in Go project at %s", g.path)
 	}
-
-	// Update go.mod if it exists and contains a version comment
-	goModPath := filepath.Join(g.path, "go.mod")
-	if _, err := os.Stat(goModPath); err == nil {
-		if err := g.updateGoMod(goModPath, version); err != nil {
+	for _, file := range files {
+		if err := g.updateVersionFile(filepath.Join(g.path, file), version); err != nil {
 			return err
 		}
-		updated = true
-	}
-
-	if !updated {
-		return fmt.Errorf("no version files to update in Go project at %s", g.path)
 	}
-
 	return nil
 }
 
@@ -102,39 +102,67 @@ func (g *GoEcosystem) GetVersionFiles() []string {
 	if g.options != nil && g.options.TagOnly {
 		return []string{}
 	}
+	files := g.versionFiles()
+	if files == nil {
+		return []string{}
+	}
+	return files
+}
 
+// versionFiles returns the configured version files, or the candidates that
+// exist in the package
+func (g *GoEcosystem) versionFiles() []string {
+	if g.options != nil && len(g.options.VersionFiles) > 0 {
+		return g.options.VersionFiles
+	}
 	var files []string
-
-	versionGoPath := filepath.Join(g.path, "version.go")
-	if _, err := os.Stat(versionGoPath); err == nil {
-		files = append(files, "version.go")
+	for _, candidate := range goVersionFileCandidates {
+		if _, err := os.Stat(filepath.Join(g.path, candidate)); err == nil {
+			files = append(files, candidate)
+		}
 	}
+	return files
+}
 
-	goModPath := filepath.Join(g.path, "go.mod")
-	if _, err := os.Stat(goModPath); err == nil {
-		files = append(files, "go.mod")
+// readVersionFile reads the version from a Go source file, go.mod, or a
+// plain file holding only the version
+func (g *GoEcosystem) readVersionFile(path string) (semver.Version, error) {
+	switch {
+	case strings.HasSuffix(path, ".go"):
+		return g.readVersionFromVersionGo(path)
+	case filepath.Base(path) == "go.mod":
+		return g.readVersionFromGoMod(path)
+	default:
+		return g.readVersionFromPlainFile(path)
 	}
+}
 
-	return files
+// updateVersionFile writes the version to a file read by readVersionFile
+func (g *GoEcosystem) updateVersionFile(path string, version semver.Version) error {
+	switch {
+	case strings.HasSuffix(path, ".go"):
+		return g.updateVersionGo(path, version)
+	case filepath.Base(path) == "go.mod":
+		return g.updateGoMod(path, version)
+	default:
+		return g.updatePlainFile(path, version)
+	}
 }
 
-// readVersionFromVersionGo extracts version from version.go file
+// readVersionFromVersionGo extracts version from a Go source file
 // Looks for patterns like: const Version = "1.2.3" or var Version = "1.2.3"
 func (g *GoEcosystem) readVersionFromVersionGo(path string) (semver.Version, error) {
 	content, err := fileutil.ReadFile(path)
 	if err != nil {
-		return semver.Version{}, fmt.Errorf("failed to read version.go: %w", err)
+		return semver.Version{}, fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
 	}
 
-	// Match: const Version = "1.2.3" or var Version = "1.2.3" (with optional pre-release suffix)
-	re := regexp.MustCompile(`(?m)^\s*(?:const|var)\s+Version\s*=\s*["']([0-9]+\.[0-9]+\.[0-9]+(?:-[a-zA-Z0-9._-]+)?)["']`)
-	matches := re.FindSubmatch(content)
-
-	if len(matches) < 2 {
+	matches := goSourceVersionRe.FindSubmatch(content)
+	if len(matches) < 5 {
 		return semver.Version{}, fmt.Errorf("no version found in %s", path)
 	}
 
-	return semver.Parse(string(matches[1]))
+	return g.parseVersion(string(matches[4]))
 }
 
 // readVersionFromGoMod extracts version from go.mod comment
@@ -145,31 +173,40 @@ func (g *GoEcosystem) readVersionFromGoMod(path string) (semver.Version, error)
 		return semver.Version{}, fmt.Errorf("failed to read go.mod: %w", err)
 	}
 
-	// Match: // version: 1.2.3 (with optional pre-release suffix)
-	re := regexp.MustCompile(`(?m)^//\s*version:\s*([0-9]+\.[0-9]+\.[0-9]+(?:-[a-zA-Z0-9._-]+)?)`)
-	matches := re.FindSubmatch(content)
-
-	if len(matches) < 2 {
+	matches := goModVersionRe.FindSubmatch(content)
+	if len(matches) < 3 {
 		return semver.Version{}, fmt.Errorf("no version comment found in %s", path)
 	}
 
-	return semver.Parse(string(matches[1]))
+	return g.parseVersion(string(matches[2]))
 }
 
-// updateVersionGo updates the version in version.go
-func (g *GoEcosystem) updateVersionGo(path string, version semver.Version) error {
+// readVersionFromPlainFile reads a file holding only the version, such as
+// .version or VERSION, with an optional v prefix
+func (g *GoEcosystem) readVersionFromPlainFile(path string) (semver.Version, error) {
 	content, err := fileutil.ReadFile(path)
 	if err != nil {
-		return fmt.Errorf("failed to read version.go: %w", err)
+		return semver.Version{}, fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
 	}
 
-	// Replace: const/var Version = "old" with const/var Version = "new" (with optional pre-release suffix)
-	re := regexp.MustCompile(`((?:const|var)\s+Version\s*=\s*)["']([0-9]+\.[0-9]+\.[0-9]+(?:-[a-zA-Z0-9._-]+)?)["']`)
-	newContent := re.ReplaceAll(content, []byte(fmt.Sprintf(`${1}"%s"`, version.String())))
+	value := strings.TrimPrefix(strings.TrimSpace(string(content)), "v")
+	if value == "" {
+		return semver.Version{}, fmt.Errorf("no version found in %s", path)
+	}
+	return g.parseVersion(value)
+}
 
-	if string(newContent) == string(content) {
+// updateVersionGo updates the Version declaration in a Go source file
+func (g *GoEcosystem) updateVersionGo(path string, version semver.Version) error {
+	content, err := fileutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+
+	if !goSourceVersionRe.Match(content) {
 		return fmt.Errorf("no version declaration found in %s", path)
 	}
+	newContent := goSourceVersionRe.ReplaceAll(content, []byte(fmt.Sprintf(`${1}${2}${3}%s${2}`, version.String())))
 
 	return fileutil.WriteFile(path, newContent, 0644)
 }
@@ -181,19 +218,34 @@ func (g *GoEcosystem) updateGoMod(path string, version semver.Version) error {
 		return fmt.Errorf("failed to read go.mod: %w", err)
 	}
 
-	// Replace: // version: old with // version: new (with optional pre-release suffix)
-	re := regexp.MustCompile(`(//\s*version:\s*)([0-9]+\.[0-9]+\.[0-9]+(?:-[a-zA-Z0-9._-]+)?)`)
-
-	if !re.Match(content) {
+	if !goModVersionRe.Match(content) {
 		// Version comment doesn't exist, don't add it
 		return nil
 	}
 
-	newContent := re.ReplaceAll(content, []byte(fmt.Sprintf(`${1}%s`, version.String())))
+	newContent := goModVersionRe.ReplaceAll(content, []byte(fmt.Sprintf(`${1}%s`, version.String())))
 
 	return fileutil.WriteFile(path, newContent, 0644)
 }
 
+// updatePlainFile replaces the contents of a plain version file, keeping a
+// v prefix and trailing newline when it had them
+func (g *GoEcosystem) updatePlainFile(path string, version semver.Version) error {
+	content, err := fileutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+
+	value := version.String()
+	if strings.HasPrefix(strings.TrimSpace(string(content)), "v") {
+		value = "v" + value
+	}
+	if len(content) == 0 || strings.HasSuffix(string(content), "\n") {
+		value += "\n"
+	}
+	return fileutil.WriteFile(path, []byte(value), 0644)
+}
+
 // DetectGoEcosystem checks if a directory contains a Go project
 func DetectGoEcosystem(path string) bool {
 	// Check for go.mod
@@ -419,3 +419,91 @@ const Version = "1.0.0"
 		assert.Contains(t, string(content), "2.0.0")
 	})
 }
+
+func TestGoEcosystem_PlainVersionFile(t *testing.T) {
+	for _, name := range []string{"VERSION", ".version"} {
+		t.Run(name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			path := filepath.Join(tempDir, name)
+			require.NoError(t, os.WriteFile(path, []byte("v1.2.3\n"), 0644))
+
+			eco := NewGoEcosystem(tempDir)
+			version, err := eco.ReadVersion()
+			require.NoError(t, err)
+			assert.Equal(t, "1.2.3", version.String())
+
+			require.NoError(t, eco.UpdateVersion(semver.MustParse("1.3.0")))
+			content, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, "v1.3.0\n", string(content), "v prefix and trailing newline are kept")
+		})
+	}
+}
+
+func TestGoEcosystem_VersionDeclarations(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "var",
+			content:  "package version\n\nvar Version = \"1.0.0\"\n",
+			expected: "package version\n\nvar Version = \"2.0.0\"\n",
+		},
+		{
+			name:     "typed const",
+			content:  "package version\n\nconst Version string = `1.0.0`\n",
+			expected: "package version\n\nconst Version string = `2.0.0`\n",
+		},
+		{
+			name:     "grouped const",
+			content:  "package version\n\nconst (\n\tName    = \"tool\"\n\tVersion = \"v1.0.0\"\n)\n",
+			expected: "package version\n\nconst (\n\tName    = \"tool\"\n\tVersion = \"v2.0.0\"\n)\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			path := filepath.Join(tempDir, "version.go")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0644))
+
+			eco := NewGoEcosystem(tempDir)
+			version, err := eco.ReadVersion()
+			require.NoError(t, err)
+			assert.Equal(t, "1.0.0", version.String())
+
+			require.NoError(t, eco.UpdateVersion(semver.MustParse("2.0.0")))
+			content, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(content))
+		})
+	}
+}
+
+func TestGoEcosystem_ConfiguredVersionFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "internal", "version"), 0755))
+	goFile := filepath.Join(tempDir, "internal", "version", "version.go")
+	plainFile := filepath.Join(tempDir, "VERSION")
+	require.NoError(t, os.WriteFile(goFile, []byte("package version\n\nconst Version = \"1.0.0\"\n"), 0644))
+	require.NoError(t, os.WriteFile(plainFile, []byte("1.0.0\n"), 0644))
+
+	eco := NewGoEcosystemWithOptions(tempDir, &GoEcosystemOptions{
+		VersionFiles: []string{"internal/version/version.go", "VERSION"},
+	})
+	assert.Equal(t, []string{"internal/version/version.go", "VERSION"}, eco.GetVersionFiles())
+
+	version, err := eco.ReadVersion()
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", version.String(), "read from the first configured file")
+
+	require.NoError(t, eco.UpdateVersion(semver.MustParse("1.1.0")))
+	content, err := os.ReadFile(goFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `const Version = "1.1.0"`)
+	content, err = os.ReadFile(plainFile)
+	require.NoError(t, err)
+	assert.Equal(t, "1.1.0\n", string(content), "configured files stay in sync")
+}
@@ -1,11 +1,15 @@
 package ecosystem
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
-	"regexp"
+	"sort"
+	"strconv"
+	"unicode/utf8"
 
+	"github.com/NatoNathan/shipyard/internal/config"
 	"github.com/NatoNathan/shipyard/internal/fileutil"
 
 	"github.com/NatoNathan/shipyard/pkg/semver"
@@ -17,6 +21,8 @@ var _ HandlerWithContext = (*HelmEcosystem)(nil)
 
 // HelmEcosystem handles version management for Helm charts
 type HelmEcosystem struct {
+	versionParser
+
 	path    string
 	context *HandlerContext // Optional context for advanced features
 }
@@ -59,51 +65,226 @@ func (h *HelmEcosystem) ReadVersion() (semver.Version, error) {
 		return semver.Version{}, fmt.Errorf("no version field found in Chart.yaml")
 	}
 
-	return semver.Parse(chart.Version)
+	return h.parseVersion(chart.Version)
+}
+
+// ReadAppVersion reads the appVersion from Chart.yaml, which need not be a
+// semantic version. It is empty when the chart has none.
+func (h *HelmEcosystem) ReadAppVersion() (string, error) {
+	content, err := fileutil.ReadFile(filepath.Join(h.path, "Chart.yaml"))
+	if err != nil {
+		return "", fmt.Errorf("failed to read Chart.yaml: %w", err)
+	}
+
+	var chart HelmChart
+	if err := yaml.Unmarshal(content, &chart); err != nil {
+		return "", fmt.Errorf("failed to parse Chart.yaml: %w", err)
+	}
+	return chart.AppVersion, nil
 }
 
-// UpdateVersion updates the version and appVersion in Chart.yaml using regex
-// replacement to preserve YAML comments and formatting.
+// UpdateVersion sets the chart version in Chart.yaml and updates appVersion
+// by the package's appVersion strategy. Both fields are found in the YAML
+// node tree and their values replaced in place, so comments, key order,
+// anchors and quoting are preserved.
 func (h *HelmEcosystem) UpdateVersion(version semver.Version) error {
 	chartPath := filepath.Join(h.path, "Chart.yaml")
 
-	// Read existing content
 	content, err := fileutil.ReadFile(chartPath)
 	if err != nil {
 		return fmt.Errorf("failed to read Chart.yaml: %w", err)
 	}
 
-	versionStr := version.String()
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return fmt.Errorf("failed to parse Chart.yaml: %w", err)
+	}
+	versionNode := chartField(&doc, "version")
+	if versionNode == nil {
+		return fmt.Errorf("no version field found in Chart.yaml")
+	}
 
-	// Replace version field using regex to preserve formatting/comments
-	versionRe := regexp.MustCompile(`(?m)^(version:\s*)(.+)$`)
-	newContent := versionRe.ReplaceAll(content, []byte(fmt.Sprintf(`${1}%s`, versionStr)))
+	edits := []scalarEdit{{node: versionNode, value: version.String()}}
+	if appVersionNode := chartField(&doc, "appVersion"); appVersionNode != nil {
+		if edit, ok := h.appVersionEdit(version, versionNode, appVersionNode); ok {
+			edits = append(edits, edit)
+		}
+	}
 
-	// Determine appVersion value
-	appVersionStr := versionStr // Default: use chart's own version
+	newContent, err := applyScalarEdits(content, edits)
+	if err != nil {
+		return fmt.Errorf("failed to update Chart.yaml: %w", err)
+	}
+	return fileutil.WriteFile(chartPath, newContent, 0644)
+}
 
+// appVersionEdit returns the change to appVersion for the package's strategy:
+// follow sets it to the chart version, fixed leaves it and independent takes
+// the appDependency package's new version, leaving it when that package is
+// not released. An appVersion aliasing the chart version is replaced by the
+// value it should keep.
+func (h *HelmEcosystem) appVersionEdit(version semver.Version, versionNode, appVersionNode *yaml.Node) (scalarEdit, bool) {
+	strategy := config.HelmAppVersionFollow
+	var helmOpts *config.HelmOptions
 	if h.context != nil && h.context.PackageConfig != nil {
-		helmOpts := h.context.PackageConfig.GetHelmOptions()
-		if helmOpts.AppDependency != "" {
-			// Use dependency's version for appVersion
-			if depVersion, ok := h.context.AllVersions[helmOpts.AppDependency]; ok {
-				appVersionStr = depVersion.String()
-			}
+		helmOpts = h.context.PackageConfig.GetHelmOptions()
+		strategy = helmOpts.AppVersionStrategy()
+	}
+
+	followsVersion := appVersionNode.Kind == yaml.AliasNode && appVersionNode.Alias == versionNode
+	value := ""
+	switch strategy {
+	case config.HelmAppVersionFollow:
+		if followsVersion {
+			return scalarEdit{}, false
+		}
+		value = version.String()
+	case config.HelmAppVersionIndependent:
+		if depVersion, ok := h.context.AllVersions[helmOpts.AppDependency]; ok {
+			value = depVersion.String()
+		}
+	}
+	if value == "" {
+		if !followsVersion {
+			return scalarEdit{}, false
 		}
+		value = versionNode.Value
 	}
+	return scalarEdit{node: appVersionNode, value: value, quote: true}, true
+}
 
-	// Update appVersion field (if it exists)
-	appVersionRe := regexp.MustCompile(`(?m)^(appVersion:\s*)(.+)$`)
-	if appVersionRe.Match(newContent) {
-		newContent = appVersionRe.ReplaceAll(newContent,
-			[]byte(fmt.Sprintf(`${1}"%s"`, appVersionStr)))
+// chartField returns the value node of a top-level key in Chart.yaml
+func chartField(doc *yaml.Node, key string) *yaml.Node {
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil
+	}
+	mapping := doc.Content[0]
+	if mapping.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
 	}
+	return nil
+}
 
-	if string(newContent) == string(content) {
-		return fmt.Errorf("no version field found in Chart.yaml")
+// scalarEdit replaces the value of a scalar or alias node. quote writes a
+// plain or aliased value double-quoted, as Helm recommends for appVersion.
+type scalarEdit struct {
+	node  *yaml.Node
+	value string
+	quote bool
+}
+
+// applyScalarEdits replaces each edited node's text in content, keeping
+// anchors, tags, quote style and everything around the value
+func applyScalarEdits(content []byte, edits []scalarEdit) ([]byte, error) {
+	type span struct {
+		start, end int
+		text       string
+	}
+	spans := make([]span, 0, len(edits))
+	for _, edit := range edits {
+		start, end, text, err := scalarSpan(content, edit)
+		if err != nil {
+			return nil, err
+		}
+		spans = append(spans, span{start, end, text})
 	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start > spans[j].start })
 
-	return fileutil.WriteFile(chartPath, newContent, 0644)
+	result := append([]byte(nil), content...)
+	for _, s := range spans {
+		result = append(result[:s.start], append([]byte(s.text), result[s.end:]...)...)
+	}
+	return result, nil
+}
+
+// scalarSpan finds the byte range of a node's value in content and the text
+// that replaces it
+func scalarSpan(content []byte, edit scalarEdit) (int, int, string, error) {
+	node := edit.node
+	start, ok := nodeOffset(content, node.Line, node.Column)
+	if !ok {
+		return 0, 0, "", fmt.Errorf("cannot locate line %d column %d", node.Line, node.Column)
+	}
+
+	quoted := strconv.Quote(edit.value)
+	if node.Kind == yaml.AliasNode {
+		end := start + 1 + len(node.Value)
+		if end > len(content) || string(content[start:end]) != "*"+node.Value {
+			return 0, 0, "", fmt.Errorf("cannot locate alias *%s on line %d", node.Value, node.Line)
+		}
+		return start, end, quoted, nil
+	}
+	if node.Kind != yaml.ScalarNode {
+		return 0, 0, "", fmt.Errorf("value on line %d is not a scalar", node.Line)
+	}
+
+	// Skip an anchor (&name) or tag (!!str) before the value
+	for start < len(content) && (content[start] == '&' || content[start] == '!') {
+		for start < len(content) && content[start] != ' ' && content[start] != '\t' && content[start] != '\n' {
+			start++
+		}
+		for start < len(content) && (content[start] == ' ' || content[start] == '\t') {
+			start++
+		}
+	}
+
+	switch node.Style {
+	case yaml.DoubleQuotedStyle:
+		end := start + 1
+		for end < len(content) && content[end] != '"' {
+			if content[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		if end >= len(content) {
+			return 0, 0, "", fmt.Errorf("unterminated string on line %d", node.Line)
+		}
+		return start, end + 1, quoted, nil
+	case yaml.SingleQuotedStyle:
+		end := bytes.IndexByte(content[start+1:], '\'')
+		if end < 0 {
+			return 0, 0, "", fmt.Errorf("unterminated string on line %d", node.Line)
+		}
+		return start, start + end + 2, "'" + edit.value + "'", nil
+	case 0:
+		end := start + len(node.Value)
+		if end > len(content) || string(content[start:end]) != node.Value {
+			return 0, 0, "", fmt.Errorf("cannot locate %q on line %d", node.Value, node.Line)
+		}
+		if edit.quote {
+			return start, end, quoted, nil
+		}
+		return start, end, edit.value, nil
+	default:
+		return 0, 0, "", fmt.Errorf("value on line %d must be a plain or quoted string", node.Line)
+	}
+}
+
+// nodeOffset converts a 1-based line and column, counted in characters, to
+// a byte offset in content
+func nodeOffset(content []byte, line, column int) (int, bool) {
+	offset := 0
+	for l := 1; l < line; l++ {
+		next := bytes.IndexByte(content[offset:], '\n')
+		if next < 0 {
+			return 0, false
+		}
+		offset += next + 1
+	}
+	for c := 1; c < column; c++ {
+		if offset >= len(content) || content[offset] == '\n' {
+			return 0, false
+		}
+		_, size := utf8.DecodeRune(content[offset:])
+		offset += size
+	}
+	return offset, true
 }
 
 // GetVersionFiles returns paths to all version-containing files
@@ -262,21 +262,21 @@ appVersion: "2.0.0"
 			description: "Without appDependency option, appVersion should match chart version",
 		},
 		{
-			name: "appVersion fallback when dependency not in version map",
+			name: "appVersion unchanged when dependency is not released",
 			initialChart: `apiVersion: v2
 name: my-chart
 version: 1.0.0
-appVersion: "1.0.0"
+appVersion: "0.5.0"
 `,
 			chartVersion:  "1.1.0",
-			appDependency: "non-existent",
+			appDependency: "my-app",
 			depVersion:    "", // Not in version map
 			expectedChart: `apiVersion: v2
 name: my-chart
 version: 1.1.0
-appVersion: "1.1.0"
+appVersion: "0.5.0"
 `,
-			description: "When dependency not in version map, fallback to chart version",
+			description: "When the dependency has no new version, appVersion still tracks its last release",
 		},
 		{
 			name: "preserves YAML formatting and structure",
@@ -352,3 +352,174 @@ description: A chart
 		})
 	}
 }
+
+// TestHelmEcosystem_AppVersionStrategies tests options.appVersion
+func TestHelmEcosystem_AppVersionStrategies(t *testing.T) {
+	const chart = `# Chart for the API
+apiVersion: v2
+name: api-chart   # keep in sync with the image
+version: &chartVersion 1.0.0
+# Image tag deployed by default
+appVersion: '0.9.0'
+annotations:
+  release: *chartVersion
+description: >-
+  The API
+`
+	tests := []struct {
+		name     string
+		options  map[string]interface{}
+		expected string
+	}{
+		{
+			name:    "follow",
+			options: map[string]interface{}{"appVersion": "follow"},
+			expected: `# Chart for the API
+apiVersion: v2
+name: api-chart   # keep in sync with the image
+version: &chartVersion 1.1.0
+# Image tag deployed by default
+appVersion: '1.1.0'
+annotations:
+  release: *chartVersion
+description: >-
+  The API
+`,
+		},
+		{
+			name:    "fixed",
+			options: map[string]interface{}{"appVersion": "fixed"},
+			expected: `# Chart for the API
+apiVersion: v2
+name: api-chart   # keep in sync with the image
+version: &chartVersion 1.1.0
+# Image tag deployed by default
+appVersion: '0.9.0'
+annotations:
+  release: *chartVersion
+description: >-
+  The API
+`,
+		},
+		{
+			name:    "independent",
+			options: map[string]interface{}{"appVersion": "independent", "appDependency": "api"},
+			expected: `# Chart for the API
+apiVersion: v2
+name: api-chart   # keep in sync with the image
+version: &chartVersion 1.1.0
+# Image tag deployed by default
+appVersion: '2.4.0'
+annotations:
+  release: *chartVersion
+description: >-
+  The API
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			chartPath := filepath.Join(tmpDir, "Chart.yaml")
+			require.NoError(t, os.WriteFile(chartPath, []byte(chart), 0644))
+
+			h := NewHelmEcosystem(tmpDir)
+			h.SetContext(&HandlerContext{
+				AllVersions:   map[string]semver.Version{"api": semver.MustParse("2.4.0"), "api-chart": semver.MustParse("1.1.0")},
+				PackageConfig: &config.Package{Name: "api-chart", Ecosystem: "helm", Options: tt.options},
+			})
+			require.NoError(t, h.UpdateVersion(semver.MustParse("1.1.0")))
+
+			result, err := os.ReadFile(chartPath)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(result), "comments, key order, anchors and quoting are preserved")
+
+			version, err := h.ReadVersion()
+			require.NoError(t, err)
+			assert.Equal(t, "1.1.0", version.String())
+		})
+	}
+}
+
+// TestHelmEcosystem_AppVersionAlias tests an appVersion that aliases the chart version
+func TestHelmEcosystem_AppVersionAlias(t *testing.T) {
+	const chart = `apiVersion: v2
+name: my-chart
+version: &v "1.0.0" # chart and app ship together
+appVersion: *v
+`
+	tests := []struct {
+		name       string
+		options    map[string]interface{}
+		appVersion string
+		expected   string
+	}{
+		{
+			name:       "follow keeps the alias",
+			appVersion: "1.1.0",
+			expected: `apiVersion: v2
+name: my-chart
+version: &v "1.1.0" # chart and app ship together
+appVersion: *v
+`,
+		},
+		{
+			name:       "fixed replaces the alias with the old version",
+			options:    map[string]interface{}{"appVersion": "fixed"},
+			appVersion: "1.0.0",
+			expected: `apiVersion: v2
+name: my-chart
+version: &v "1.1.0" # chart and app ship together
+appVersion: "1.0.0"
+`,
+		},
+		{
+			name:       "independent replaces the alias with the dependency version",
+			options:    map[string]interface{}{"appDependency": "api"},
+			appVersion: "3.0.0",
+			expected: `apiVersion: v2
+name: my-chart
+version: &v "1.1.0" # chart and app ship together
+appVersion: "3.0.0"
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "Chart.yaml"), []byte(chart), 0644))
+
+			h := NewHelmEcosystem(tmpDir)
+			h.SetContext(&HandlerContext{
+				AllVersions:   map[string]semver.Version{"api": semver.MustParse("3.0.0")},
+				PackageConfig: &config.Package{Name: "my-chart", Ecosystem: "helm", Options: tt.options},
+			})
+			require.NoError(t, h.UpdateVersion(semver.MustParse("1.1.0")))
+
+			result, err := os.ReadFile(filepath.Join(tmpDir, "Chart.yaml"))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(result))
+
+			appVersion, err := h.ReadAppVersion()
+			require.NoError(t, err)
+			assert.Equal(t, tt.appVersion, appVersion)
+		})
+	}
+}
+
+// TestHelmEcosystem_ReadAppVersion tests reading appVersion from Chart.yaml
+func TestHelmEcosystem_ReadAppVersion(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "Chart.yaml"), []byte("apiVersion: v2\nname: c\nversion: 1.0.0\nappVersion: \"v1.4.2-alpine\"\n"), 0644))
+
+	appVersion, err := NewHelmEcosystem(tmpDir).ReadAppVersion()
+	require.NoError(t, err)
+	assert.Equal(t, "v1.4.2-alpine", appVersion, "appVersion need not be semver")
+
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "Chart.yaml"), []byte("apiVersion: v2\nname: c\nversion: 1.0.0\n"), 0644))
+	appVersion, err = NewHelmEcosystem(tmpDir).ReadAppVersion()
+	require.NoError(t, err)
+	assert.Empty(t, appVersion)
+}
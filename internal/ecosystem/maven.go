@@ -0,0 +1,220 @@
+package ecosystem
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/NatoNathan/shipyard/internal/fileutil"
+
+	"github.com/NatoNathan/shipyard/pkg/semver"
+)
+
+var _ Handler = (*MavenEcosystem)(nil)
+
+// mavenManifests are the files holding a JVM project's version, in the order
+// they are detected
+var mavenManifests = []string{"pom.xml", "gradle.properties"}
+
+// xmlTagRe matches an XML start, end or empty-element tag and captures the
+// slash of an end tag, the local name and the slash of an empty element.
+// Declarations, processing instructions and comments do not match.
+var xmlTagRe = regexp.MustCompile(`<(/?)(?:[A-Za-z_][\w.-]*:)?([A-Za-z_][\w.-]*)(?:\s[^>]*?)?(/?)>`)
+
+// pomPropertyRe matches a property reference such as ${revision}
+var pomPropertyRe = regexp.MustCompile(`^\$\{([\w.-]+)\}$`)
+
+// gradleVersionRe matches the version property in gradle.properties and
+// captures its value
+var gradleVersionRe = regexp.MustCompile(`(?m)^([ \t]*version[ \t]*[=:][ \t]*)([^\s#!]+)`)
+
+// gradleRootProjectRe matches rootProject.name in settings.gradle(.kts)
+var gradleRootProjectRe = regexp.MustCompile(`rootProject\.name\s*=\s*["']([^"']+)["']`)
+
+// MavenEcosystem handles version management for Maven and Gradle projects
+type MavenEcosystem struct {
+	versionParser
+
+	path string
+}
+
+// NewMavenEcosystem creates a new Maven/Gradle ecosystem handler
+func NewMavenEcosystem(path string) *MavenEcosystem {
+	return &MavenEcosystem{path: path}
+}
+
+// ReadVersion reads the project version from pom.xml or, for Gradle
+// projects, from gradle.properties
+func (m *MavenEcosystem) ReadVersion() (semver.Version, error) {
+	manifest, content, start, end, err := m.versionSpan()
+	if err != nil {
+		return semver.Version{}, err
+	}
+	value := string(content[start:end])
+	if value == "" {
+		return semver.Version{}, fmt.Errorf("empty version in %s", manifest)
+	}
+	return m.parseVersion(value)
+}
+
+// ReadName returns the project's artifactId from pom.xml, or the
+// rootProject.name from a Gradle settings file. It is empty when neither is
+// set.
+func (m *MavenEcosystem) ReadName() (string, error) {
+	if m.manifest() == "pom.xml" {
+		content, err := fileutil.ReadFile(filepath.Join(m.path, "pom.xml"))
+		if err != nil {
+			return "", fmt.Errorf("failed to read pom.xml: %w", err)
+		}
+		if start, end, ok := xmlElementText(content, "project", "artifactId"); ok {
+			return string(content[start:end]), nil
+		}
+		return "", nil
+	}
+
+	for _, settings := range []string{"settings.gradle", "settings.gradle.kts"} {
+		content, err := fileutil.ReadFile(filepath.Join(m.path, settings))
+		if err != nil {
+			continue
+		}
+		if match := gradleRootProjectRe.FindSubmatch(content); match != nil {
+			return string(match[1]), nil
+		}
+	}
+	return "", nil
+}
+
+// UpdateVersion replaces the project version text in place. Everything
+// else in the file, including comments, namespaces, dependency and parent
+// versions, and module poms, is left untouched.
+func (m *MavenEcosystem) UpdateVersion(version semver.Version) error {
+	manifest, content, start, end, err := m.versionSpan()
+	if err != nil {
+		return err
+	}
+
+	var updated []byte
+	updated = append(updated, content[:start]...)
+	updated = append(updated, version.String()...)
+	updated = append(updated, content[end:]...)
+	return fileutil.WriteFile(filepath.Join(m.path, manifest), updated, 0644)
+}
+
+// GetVersionFiles returns the file holding the version, relative to the
+// package path
+func (m *MavenEcosystem) GetVersionFiles() []string {
+	if manifest := m.manifest(); manifest != "" {
+		return []string{manifest}
+	}
+	return []string{}
+}
+
+// manifest returns pom.xml, else gradle.properties, or "" when the package
+// has neither
+func (m *MavenEcosystem) manifest() string {
+	for _, name := range mavenManifests {
+		if _, err := os.Stat(filepath.Join(m.path, name)); err == nil {
+			return name
+		}
+	}
+	return ""
+}
+
+// versionSpan reads the manifest and locates the project version in it
+func (m *MavenEcosystem) versionSpan() (manifest string, content []byte, start, end int, err error) {
+	manifest = m.manifest()
+	if manifest == "" {
+		return "", nil, 0, 0, fmt.Errorf("no pom.xml or gradle.properties found")
+	}
+	content, err = fileutil.ReadFile(filepath.Join(m.path, manifest))
+	if err != nil {
+		return "", nil, 0, 0, fmt.Errorf("failed to read %s: %w", manifest, err)
+	}
+
+	if manifest == "gradle.properties" {
+		loc := gradleVersionRe.FindSubmatchIndex(content)
+		if loc == nil {
+			return "", nil, 0, 0, fmt.Errorf("no version property found in gradle.properties")
+		}
+		return manifest, content, loc[4], loc[5], nil
+	}
+
+	start, end, err = pomVersionSpan(content)
+	if err != nil {
+		return "", nil, 0, 0, err
+	}
+	return manifest, content, start, end, nil
+}
+
+// pomVersionSpan locates the text of the root project's own <version>. A
+// module that inherits its version from <parent> has none. A ${property}
+// version resolves to the property in the project's <properties>, as used by
+// CI-friendly versions such as ${revision}.
+func pomVersionSpan(content []byte) (int, int, error) {
+	start, end, ok := xmlElementText(content, "project", "version")
+	if !ok {
+		return 0, 0, fmt.Errorf("no <version> found in pom.xml; a module inheriting its version from <parent> is released with its parent")
+	}
+
+	if ref := pomPropertyRe.FindSubmatch(content[start:end]); ref != nil {
+		property := string(ref[1])
+		start, end, ok = xmlElementText(content, "project", "properties", property)
+		if !ok {
+			return 0, 0, fmt.Errorf("pom.xml version refers to ${%s}, which is not set in <properties>", property)
+		}
+	}
+	return start, end, nil
+}
+
+// xmlElementText returns the span of the trimmed text of the first element
+// reached by following child element names from the document root. Comments
+// are ignored and namespace prefixes are matched by local name.
+func xmlElementText(content []byte, path ...string) (int, int, bool) {
+	masked := maskXMLComments(content)
+	var stack []string
+	textStart := -1
+	for _, loc := range xmlTagRe.FindAllSubmatchIndex(masked, -1) {
+		closing := loc[3] > loc[2]
+		name := string(masked[loc[4]:loc[5]])
+		empty := loc[7] > loc[6]
+
+		switch {
+		case closing:
+			if textStart >= 0 && stackMatches(stack, path) {
+				text := content[textStart:loc[0]]
+				start := textStart + len(text) - len(bytes.TrimLeft(text, " \t\r\n"))
+				end := textStart + len(bytes.TrimRight(text, " \t\r\n"))
+				return start, max(start, end), true
+			}
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case empty:
+			continue
+		default:
+			stack = append(stack, name)
+			if stackMatches(stack, path) {
+				textStart = loc[1]
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// stackMatches reports whether the open elements are exactly path
+func stackMatches(stack, path []string) bool {
+	return strings.Join(stack, "/") == strings.Join(path, "/")
+}
+
+// DetectMavenEcosystem checks if a directory contains a Maven project, or a
+// Gradle project with a version in gradle.properties
+func DetectMavenEcosystem(path string) bool {
+	if _, err := os.Stat(filepath.Join(path, "pom.xml")); err == nil {
+		return true
+	}
+	content, err := fileutil.ReadFile(filepath.Join(path, "gradle.properties"))
+	return err == nil && gradleVersionRe.Match(content)
+}
@@ -0,0 +1,205 @@
+package ecosystem
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/NatoNathan/shipyard/pkg/semver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parentPom is a multi-module parent whose <parent>, dependency, plugin and
+// property versions must survive a bump untouched
+const parentPom = `<?xml version="1.0" encoding="UTF-8"?>
+<!-- Platform services parent. <version>9.9.9</version> -->
+<project xmlns="http://maven.apache.org/POM/4.0.0"
+         xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"
+         xsi:schemaLocation="http://maven.apache.org/POM/4.0.0 https://maven.apache.org/xsd/maven-4.0.0.xsd">
+  <modelVersion>4.0.0</modelVersion>
+
+  <parent>
+    <groupId>org.springframework.boot</groupId>
+    <artifactId>spring-boot-starter-parent</artifactId>
+    <version>3.2.1</version>
+    <relativePath/>
+  </parent>
+
+  <groupId>com.example.platform</groupId>
+  <artifactId>platform-parent</artifactId>
+  <version>1.4.0</version>
+  <packaging>pom</packaging>
+
+  <modules>
+    <module>orders</module>
+  </modules>
+
+  <properties>
+    <java.version>21</java.version>
+    <jackson.version>2.16.0</jackson.version>
+  </properties>
+
+  <dependencies>
+    <dependency>
+      <groupId>com.fasterxml.jackson.core</groupId>
+      <artifactId>jackson-databind</artifactId>
+      <version>${jackson.version}</version>
+    </dependency>
+  </dependencies>
+</project>
+`
+
+// modulePom inherits its version from parentPom
+const modulePom = `<?xml version="1.0" encoding="UTF-8"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0">
+  <modelVersion>4.0.0</modelVersion>
+  <parent>
+    <groupId>com.example.platform</groupId>
+    <artifactId>platform-parent</artifactId>
+    <version>1.4.0</version>
+  </parent>
+  <artifactId>orders</artifactId>
+</project>
+`
+
+func TestMavenEcosystem_Pom(t *testing.T) {
+	tempDir := t.TempDir()
+	pomPath := filepath.Join(tempDir, "pom.xml")
+	require.NoError(t, os.WriteFile(pomPath, []byte(parentPom), 0644))
+	modulePath := filepath.Join(tempDir, "orders", "pom.xml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(modulePath), 0755))
+	require.NoError(t, os.WriteFile(modulePath, []byte(modulePom), 0644))
+
+	maven := NewMavenEcosystem(tempDir)
+	assert.Equal(t, []string{"pom.xml"}, maven.GetVersionFiles())
+
+	version, err := maven.ReadVersion()
+	require.NoError(t, err)
+	assert.Equal(t, "1.4.0", version.String(), "the project version, not the parent or a comment")
+
+	name, err := maven.ReadName()
+	require.NoError(t, err)
+	assert.Equal(t, "platform-parent", name)
+
+	require.NoError(t, maven.UpdateVersion(semver.MustParse("1.5.0")))
+	updated, err := os.ReadFile(pomPath)
+	require.NoError(t, err)
+	assert.Equal(t, strings.Replace(parentPom, "<version>1.4.0</version>", "<version>1.5.0</version>", 1), string(updated),
+		"only the project <version> changes")
+
+	module, err := os.ReadFile(modulePath)
+	require.NoError(t, err)
+	assert.Equal(t, modulePom, string(module), "module poms are not bumped")
+}
+
+func TestMavenEcosystem_PomVariants(t *testing.T) {
+	t.Run("CI-friendly revision property", func(t *testing.T) {
+		tempDir := t.TempDir()
+		pom := `<project>
+  <artifactId>api</artifactId>
+  <version>${revision}</version>
+  <properties>
+    <revision> 2.0.0 </revision>
+  </properties>
+</project>
+`
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, "pom.xml"), []byte(pom), 0644))
+
+		maven := NewMavenEcosystem(tempDir)
+		version, err := maven.ReadVersion()
+		require.NoError(t, err)
+		assert.Equal(t, "2.0.0", version.String())
+
+		require.NoError(t, maven.UpdateVersion(semver.MustParse("2.1.0")))
+		updated, err := os.ReadFile(filepath.Join(tempDir, "pom.xml"))
+		require.NoError(t, err)
+		assert.Equal(t, strings.Replace(pom, "<revision> 2.0.0 </revision>", "<revision> 2.1.0 </revision>", 1), string(updated))
+	})
+
+	t.Run("namespace prefix", func(t *testing.T) {
+		tempDir := t.TempDir()
+		pom := `<pom:project xmlns:pom="http://maven.apache.org/POM/4.0.0"><pom:version>0.1.0</pom:version></pom:project>`
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, "pom.xml"), []byte(pom), 0644))
+
+		maven := NewMavenEcosystem(tempDir)
+		require.NoError(t, maven.UpdateVersion(semver.MustParse("0.2.0")))
+		updated, err := os.ReadFile(filepath.Join(tempDir, "pom.xml"))
+		require.NoError(t, err)
+		assert.Equal(t, `<pom:project xmlns:pom="http://maven.apache.org/POM/4.0.0"><pom:version>0.2.0</pom:version></pom:project>`, string(updated))
+	})
+
+	t.Run("module inheriting its version", func(t *testing.T) {
+		tempDir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, "pom.xml"), []byte(modulePom), 0644))
+
+		_, err := NewMavenEcosystem(tempDir).ReadVersion()
+		assert.ErrorContains(t, err, "inheriting its version from <parent>")
+	})
+
+	t.Run("undefined property", func(t *testing.T) {
+		tempDir := t.TempDir()
+		pom := `<project><version>${revision}</version></project>`
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, "pom.xml"), []byte(pom), 0644))
+
+		_, err := NewMavenEcosystem(tempDir).ReadVersion()
+		assert.ErrorContains(t, err, "${revision}")
+	})
+}
+
+func TestMavenEcosystem_GradleProperties(t *testing.T) {
+	tempDir := t.TempDir()
+	propsPath := filepath.Join(tempDir, "gradle.properties")
+	props := `# Build settings
+org.gradle.jvmargs=-Xmx2g
+kotlin.version=1.9.22
+version = 0.9.0
+group=com.example
+`
+	require.NoError(t, os.WriteFile(propsPath, []byte(props), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "settings.gradle.kts"), []byte(`rootProject.name = "billing"`+"\n"), 0644))
+
+	gradle := NewMavenEcosystem(tempDir)
+	assert.Equal(t, []string{"gradle.properties"}, gradle.GetVersionFiles())
+
+	version, err := gradle.ReadVersion()
+	require.NoError(t, err)
+	assert.Equal(t, "0.9.0", version.String())
+
+	name, err := gradle.ReadName()
+	require.NoError(t, err)
+	assert.Equal(t, "billing", name)
+
+	require.NoError(t, gradle.UpdateVersion(semver.MustParse("1.0.0")))
+	updated, err := os.ReadFile(propsPath)
+	require.NoError(t, err)
+	assert.Equal(t, strings.Replace(props, "version = 0.9.0", "version = 1.0.0", 1), string(updated))
+}
+
+func TestMavenEcosystem_PomPreferredOverGradle(t *testing.T) {
+	tempDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "pom.xml"), []byte(`<project><version>3.0.0</version></project>`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "gradle.properties"), []byte("version=1.0.0\n"), 0644))
+
+	maven := NewMavenEcosystem(tempDir)
+	assert.Equal(t, []string{"pom.xml"}, maven.GetVersionFiles())
+	version, err := maven.ReadVersion()
+	require.NoError(t, err)
+	assert.Equal(t, "3.0.0", version.String())
+}
+
+func TestDetectMavenEcosystem(t *testing.T) {
+	tempDir := t.TempDir()
+	assert.False(t, DetectMavenEcosystem(tempDir))
+
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "gradle.properties"), []byte("org.gradle.caching=true\n"), 0644))
+	assert.False(t, DetectMavenEcosystem(tempDir), "gradle.properties without a version")
+
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "gradle.properties"), []byte("version=1.0.0\n"), 0644))
+	assert.True(t, DetectMavenEcosystem(tempDir))
+
+	pomDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(pomDir, "pom.xml"), []byte(`<project/>`), 0644))
+	assert.True(t, DetectMavenEcosystem(pomDir))
+}
@@ -1,22 +1,40 @@
 package ecosystem
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 
+	"github.com/NatoNathan/shipyard/internal/config"
 	"github.com/NatoNathan/shipyard/internal/fileutil"
 
 	"github.com/NatoNathan/shipyard/pkg/semver"
 )
 
 var _ Handler = (*NPMEcosystem)(nil)
+var _ HandlerWithContext = (*NPMEcosystem)(nil)
+var _ WarningReporter = (*NPMEcosystem)(nil)
+
+// npmDependencySections are the package.json fields holding dependency ranges
+var npmDependencySections = []string{"dependencies", "devDependencies", "peerDependencies", "optionalDependencies"}
+
+// npmPlainRangeRe matches the ranges Shipyard rewrites for sibling packages: a
+// single version, optionally pinned with ^ or ~. Ranges such as "workspace:*",
+// ">=1.0.0 <2.0.0" or "file:../core" are left alone.
+var npmPlainRangeRe = regexp.MustCompile(`^[\^~]?v?[0-9]+\.[0-9]+\.[0-9]+(?:-[0-9A-Za-z.-]+)?(?:\+[0-9A-Za-z.-]+)?$`)
 
 // NPMEcosystem handles version management for NPM/Node.js projects
 type NPMEcosystem struct {
-	path string
+	versionParser
+
+	path     string
+	context  *HandlerContext // Optional context for sibling dependency ranges
+	warnings []string
 }
 
 // NewNPMEcosystem creates a new NPM ecosystem handler
@@ -24,6 +42,17 @@ func NewNPMEcosystem(path string) *NPMEcosystem {
 	return &NPMEcosystem{path: path}
 }
 
+// SetContext implements HandlerWithContext
+func (n *NPMEcosystem) SetContext(ctx *HandlerContext) {
+	n.context = ctx
+}
+
+// Warnings implements WarningReporter, listing lockfiles the last
+// UpdateVersion found but could not update
+func (n *NPMEcosystem) Warnings() []string {
+	return n.warnings
+}
+
 // ReadVersion reads the current version from package.json
 func (n *NPMEcosystem) ReadVersion() (semver.Version, error) {
 	packageJSONPath := filepath.Join(n.path, "package.json")
@@ -43,40 +72,156 @@ func (n *NPMEcosystem) ReadVersion() (semver.Version, error) {
 		return semver.Version{}, fmt.Errorf("no version field found in package.json")
 	}
 
-	return semver.Parse(versionStr)
+	return n.parseVersion(versionStr)
 }
 
-// UpdateVersion updates the version in package.json using regex replacement
-// to preserve original file formatting, indentation, and key order.
+// UpdateVersion updates the version in package.json and, when present, in
+// package-lock.json. With options.dependencyRange set, ranges on released
+// sibling packages are rewritten in both files. Only the affected string
+// values are replaced, preserving formatting, indentation and key order.
 func (n *NPMEcosystem) UpdateVersion(version semver.Version) error {
-	packageJSONPath := filepath.Join(n.path, "package.json")
+	n.warnings = nil
+	ranges, err := n.siblingRanges()
+	if err != nil {
+		return err
+	}
 
-	// Read existing content
+	packageJSONPath := filepath.Join(n.path, "package.json")
 	content, err := fileutil.ReadFile(packageJSONPath)
 	if err != nil {
 		return fmt.Errorf("failed to read package.json: %w", err)
 	}
+	edits, err := npmManifestEdits(content, nil, version, ranges)
+	if err != nil {
+		return fmt.Errorf("failed to update package.json: %w", err)
+	}
+	if len(edits) == 0 {
+		return fmt.Errorf("no version field found in package.json")
+	}
+	if err := fileutil.WriteFile(packageJSONPath, applyJSONEdits(content, edits), 0644); err != nil {
+		return err
+	}
 
-	// Use regex to replace only the version value, preserving all formatting
-	re := regexp.MustCompile(`("version"\s*:\s*")([^"]+)(")`)
-	newContent := re.ReplaceAll(content, []byte(fmt.Sprintf(`${1}%s${3}`, version.String())))
+	if err := n.updateLockfile(version, ranges); err != nil {
+		return err
+	}
+	n.warnings = n.unsupportedLockfiles()
+	return nil
+}
 
-	if string(newContent) == string(content) {
-		return fmt.Errorf("no version field found in package.json")
+// updateLockfile sets the version in package-lock.json: the top-level field of
+// lockfile versions 1 and 2, and the root package entry packages[""] of
+// versions 2 and 3, along with its dependency ranges
+func (n *NPMEcosystem) updateLockfile(version semver.Version, ranges map[string]string) error {
+	lockPath := filepath.Join(n.path, "package-lock.json")
+	content, err := fileutil.ReadFile(lockPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read package-lock.json: %w", err)
 	}
 
-	return fileutil.WriteFile(packageJSONPath, newContent, 0644)
+	edits, err := npmManifestEdits(content, nil, version, nil)
+	if err != nil {
+		return fmt.Errorf("failed to update package-lock.json: %w", err)
+	}
+	rootEdits, err := npmManifestEdits(content, []string{"packages", ""}, version, ranges)
+	if err != nil {
+		return fmt.Errorf("failed to update package-lock.json: %w", err)
+	}
+	edits = append(edits, rootEdits...)
+	if len(edits) == 0 {
+		return nil
+	}
+	return fileutil.WriteFile(lockPath, applyJSONEdits(content, edits), 0644)
 }
 
-// GetVersionFiles returns paths to all version-containing files
-func (n *NPMEcosystem) GetVersionFiles() []string {
-	packageJSONPath := filepath.Join(n.path, "package.json")
+// siblingRanges maps the npm names of other released npm packages to the
+// dependency range options.dependencyRange writes for their new version
+func (n *NPMEcosystem) siblingRanges() (map[string]string, error) {
+	if n.context == nil || n.context.PackageConfig == nil {
+		return nil, nil
+	}
+	strategy := n.context.PackageConfig.GetNPMOptions().DependencyRange
+	if strategy == "" {
+		return nil, nil
+	}
+
+	ranges := make(map[string]string)
+	for _, pkg := range n.context.Packages {
+		if pkg.Name == n.context.PackageConfig.Name || pkg.Ecosystem != config.EcosystemNPM {
+			continue
+		}
+		version, ok := n.context.AllVersions[pkg.Name]
+		if !ok {
+			continue
+		}
+		name, err := npmPackageName(filepath.Join(n.context.ProjectPath, pkg.Path))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read name of %s: %w", pkg.Name, err)
+		}
+		if name == "" {
+			continue
+		}
+		switch strategy {
+		case config.NPMDependencyRangeCaret:
+			ranges[name] = "^" + version.String()
+		case config.NPMDependencyRangeTilde:
+			ranges[name] = "~" + version.String()
+		default:
+			ranges[name] = version.String()
+		}
+	}
+	return ranges, nil
+}
 
-	if _, err := os.Stat(packageJSONPath); err == nil {
-		return []string{"package.json"}
+// unsupportedLockfiles reports lockfiles from the package up to the project
+// root that UpdateVersion leaves stale
+func (n *NPMEcosystem) unsupportedLockfiles() []string {
+	root := n.path
+	if n.context != nil && n.context.ProjectPath != "" {
+		if rel, err := filepath.Rel(n.context.ProjectPath, n.path); err == nil && filepath.IsLocal(rel) {
+			root = n.context.ProjectPath
+		}
 	}
 
-	return []string{}
+	var warnings []string
+	for dir := n.path; ; dir = filepath.Dir(dir) {
+		display, err := filepath.Rel(root, dir)
+		if err != nil {
+			display = dir
+		}
+		if _, err := os.Stat(filepath.Join(dir, "pnpm-lock.yaml")); err == nil {
+			warnings = append(warnings, fmt.Sprintf("%s is not updated; run pnpm install to refresh it",
+				filepath.ToSlash(filepath.Join(display, "pnpm-lock.yaml"))))
+		}
+		if content, err := fileutil.ReadFile(filepath.Join(dir, "yarn.lock")); err == nil && bytes.Contains(content, []byte("__metadata:")) {
+			warnings = append(warnings, fmt.Sprintf("%s (Yarn Berry) is not updated; run yarn install to refresh it",
+				filepath.ToSlash(filepath.Join(display, "yarn.lock"))))
+		}
+		if dir != n.path {
+			if _, err := os.Stat(filepath.Join(dir, "package-lock.json")); err == nil {
+				warnings = append(warnings, fmt.Sprintf("%s is not updated; run npm install to refresh it",
+					filepath.ToSlash(filepath.Join(display, "package-lock.json"))))
+			}
+		}
+		if dir == root || filepath.Dir(dir) == dir {
+			break
+		}
+	}
+	return warnings
+}
+
+// GetVersionFiles returns paths to all version-containing files
+func (n *NPMEcosystem) GetVersionFiles() []string {
+	files := []string{}
+	for _, name := range []string{"package.json", "package-lock.json"} {
+		if _, err := os.Stat(filepath.Join(n.path, name)); err == nil {
+			files = append(files, name)
+		}
+	}
+	return files
 }
 
 // DetectNPMEcosystem checks if a directory contains an NPM project
@@ -98,3 +243,141 @@ func DetectNPMEcosystem(path string) bool {
 
 	return false
 }
+
+// npmPackageName reads the name field of the package.json in dir
+func npmPackageName(dir string) (string, error) {
+	content, err := fileutil.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return "", err
+	}
+	var manifest struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return "", fmt.Errorf("failed to parse package.json: %w", err)
+	}
+	return manifest.Name, nil
+}
+
+// npmManifestEdits returns the edits setting the version and sibling ranges of
+// the manifest object at path in a package.json or package-lock.json document
+func npmManifestEdits(content []byte, path []string, version semver.Version, ranges map[string]string) ([]jsonEdit, error) {
+	if !json.Valid(content) {
+		return nil, fmt.Errorf("invalid JSON")
+	}
+
+	var edits []jsonEdit
+	if start, end, ok := jsonStringSpan(content, append(append([]string{}, path...), "version")...); ok {
+		edits = append(edits, jsonEdit{start: start, end: end, value: version.String()})
+	}
+
+	names := make([]string, 0, len(ranges))
+	for name := range ranges {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, section := range npmDependencySections {
+		for _, name := range names {
+			keys := append(append([]string{}, path...), section, name)
+			start, end, ok := jsonStringSpan(content, keys...)
+			if !ok {
+				continue
+			}
+			var current string
+			if err := json.Unmarshal(content[start:end], &current); err != nil || !npmPlainRangeRe.MatchString(current) {
+				continue
+			}
+			edits = append(edits, jsonEdit{start: start, end: end, value: ranges[name]})
+		}
+	}
+	return edits, nil
+}
+
+// jsonEdit replaces the JSON string spanning content[start:end] with value
+type jsonEdit struct {
+	start, end int
+	value      string
+}
+
+// applyJSONEdits applies non-overlapping edits to content
+func applyJSONEdits(content []byte, edits []jsonEdit) []byte {
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+	var out bytes.Buffer
+	last := 0
+	for _, edit := range edits {
+		out.Write(content[last:edit.start])
+		quoted, _ := json.Marshal(edit.value)
+		out.Write(quoted)
+		last = edit.end
+	}
+	out.Write(content[last:])
+	return out.Bytes()
+}
+
+// jsonStringSpan returns the byte span, quotes included, of the string value
+// reached by following object keys from the document root. ok is false when
+// a key is missing or the value is not a string.
+func jsonStringSpan(content []byte, keys ...string) (start, end int, ok bool) {
+	dec := json.NewDecoder(bytes.NewReader(content))
+	for _, key := range keys {
+		if !seekJSONKey(dec, key) {
+			return 0, 0, false
+		}
+	}
+
+	before := int(dec.InputOffset())
+	token, err := dec.Token()
+	if err != nil {
+		return 0, 0, false
+	}
+	if _, isString := token.(string); !isString {
+		return 0, 0, false
+	}
+	end = int(dec.InputOffset())
+	start = before + bytes.IndexByte(content[before:end], '"')
+	return start, end, true
+}
+
+// seekJSONKey reads an object from dec up to key, leaving dec before its value
+func seekJSONKey(dec *json.Decoder, key string) bool {
+	token, err := dec.Token()
+	if err != nil || token != json.Delim('{') {
+		return false
+	}
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return false
+		}
+		if token == key {
+			return true
+		}
+		if err := skipJSONValue(dec); err != nil {
+			return false
+		}
+	}
+	return false
+}
+
+// skipJSONValue reads one complete value from dec
+func skipJSONValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		token, err := dec.Token()
+		if err != nil {
+			if err == io.EOF {
+				return io.ErrUnexpectedEOF
+			}
+			return err
+		}
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
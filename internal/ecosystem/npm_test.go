@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/NatoNathan/shipyard/internal/config"
 	"github.com/NatoNathan/shipyard/pkg/semver"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -270,3 +271,236 @@ func TestNPMEcosystem_ErrorHandling(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestNPMEcosystem_UpdateLockfile(t *testing.T) {
+	tests := []struct {
+		name     string
+		lockfile string
+		expected string
+	}{
+		{
+			name: "lockfile v2",
+			lockfile: `{
+  "name": "web",
+  "version": "1.0.0",
+  "lockfileVersion": 2,
+  "requires": true,
+  "packages": {
+    "": {
+      "name": "web",
+      "version": "1.0.0",
+      "dependencies": {
+        "left-pad": "^1.0.0"
+      }
+    },
+    "node_modules/left-pad": {
+      "version": "1.0.0"
+    }
+  },
+  "dependencies": {
+    "left-pad": {
+      "version": "1.0.0"
+    }
+  }
+}
+`,
+			expected: `{
+  "name": "web",
+  "version": "1.1.0",
+  "lockfileVersion": 2,
+  "requires": true,
+  "packages": {
+    "": {
+      "name": "web",
+      "version": "1.1.0",
+      "dependencies": {
+        "left-pad": "^1.0.0"
+      }
+    },
+    "node_modules/left-pad": {
+      "version": "1.0.0"
+    }
+  },
+  "dependencies": {
+    "left-pad": {
+      "version": "1.0.0"
+    }
+  }
+}
+`,
+		},
+		{
+			name: "lockfile v3",
+			lockfile: `{
+  "name": "web",
+  "lockfileVersion": 3,
+  "requires": true,
+  "packages": {
+    "node_modules/left-pad": {"version": "1.0.0"},
+    "": {"name": "web", "version": "1.0.0"}
+  }
+}
+`,
+			expected: `{
+  "name": "web",
+  "lockfileVersion": 3,
+  "requires": true,
+  "packages": {
+    "node_modules/left-pad": {"version": "1.0.0"},
+    "": {"name": "web", "version": "1.1.0"}
+  }
+}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "package.json"), []byte(`{"name": "web", "version": "1.0.0"}`), 0644))
+			lockPath := filepath.Join(tmpDir, "package-lock.json")
+			require.NoError(t, os.WriteFile(lockPath, []byte(tt.lockfile), 0644))
+
+			eco := NewNPMEcosystem(tmpDir)
+			assert.Equal(t, []string{"package.json", "package-lock.json"}, eco.GetVersionFiles())
+			require.NoError(t, eco.UpdateVersion(semver.MustParse("1.1.0")))
+
+			content, err := os.ReadFile(lockPath)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(content))
+
+			// Writing the same version again is a no-op
+			require.NoError(t, eco.UpdateVersion(semver.MustParse("1.1.0")))
+			content, err = os.ReadFile(lockPath)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(content))
+		})
+	}
+}
+
+func TestNPMEcosystem_DependencyRanges(t *testing.T) {
+	setup := func(t *testing.T) string {
+		t.Helper()
+		root := t.TempDir()
+		for dir, content := range map[string]string{
+			"core/package.json": `{"name": "@org/core", "version": "2.0.0"}`,
+			"ui/package.json":   `{"name": "@org/ui", "version": "1.0.0"}`,
+			"web/package.json": `{
+  "name": "@org/web",
+  "version": "1.0.0",
+  "dependencies": {
+    "@org/core": "^1.0.0",
+    "@org/ui": "^1.0.0",
+    "left-pad": "^1.0.0"
+  },
+  "devDependencies": {
+    "@org/core": "workspace:*"
+  },
+  "peerDependencies": {
+    "@org/core": ">=1.0.0 <3.0.0"
+  }
+}
+`,
+			"web/package-lock.json": `{
+  "name": "@org/web",
+  "version": "1.0.0",
+  "lockfileVersion": 3,
+  "packages": {
+    "": {
+      "name": "@org/web",
+      "version": "1.0.0",
+      "dependencies": {
+        "@org/core": "^1.0.0"
+      }
+    }
+  }
+}
+`,
+		} {
+			path := filepath.Join(root, dir)
+			require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+			require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		}
+		return root
+	}
+
+	context := func(root, dependencyRange string) *HandlerContext {
+		web := config.Package{Name: "web", Path: "web", Ecosystem: config.EcosystemNPM}
+		if dependencyRange != "" {
+			web.Options = map[string]interface{}{"dependencyRange": dependencyRange}
+		}
+		return &HandlerContext{
+			AllVersions: map[string]semver.Version{
+				"core": semver.MustParse("2.0.0"),
+				"web":  semver.MustParse("1.0.1"),
+			},
+			PackageConfig: &web,
+			Packages: []config.Package{
+				{Name: "core", Path: "core", Ecosystem: config.EcosystemNPM},
+				{Name: "ui", Path: "ui", Ecosystem: config.EcosystemNPM},
+				web,
+			},
+			ProjectPath: root,
+		}
+	}
+
+	readDependencies := func(t *testing.T, path string, keys ...string) map[string]interface{} {
+		t.Helper()
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		var doc map[string]interface{}
+		require.NoError(t, json.Unmarshal(content, &doc))
+		for _, key := range keys {
+			doc = doc[key].(map[string]interface{})
+		}
+		return doc
+	}
+
+	for _, tt := range []struct {
+		dependencyRange string
+		expected        string
+	}{
+		{config.NPMDependencyRangeExact, "2.0.0"},
+		{config.NPMDependencyRangeCaret, "^2.0.0"},
+		{config.NPMDependencyRangeTilde, "~2.0.0"},
+		{"", "^1.0.0"},
+	} {
+		t.Run("range "+tt.dependencyRange, func(t *testing.T) {
+			root := setup(t)
+			eco := NewNPMEcosystem(filepath.Join(root, "web"))
+			eco.SetContext(context(root, tt.dependencyRange))
+			require.NoError(t, eco.UpdateVersion(semver.MustParse("1.0.1")))
+
+			packageJSON := filepath.Join(root, "web", "package.json")
+			deps := readDependencies(t, packageJSON, "dependencies")
+			assert.Equal(t, tt.expected, deps["@org/core"])
+			assert.Equal(t, "^1.0.0", deps["@org/ui"], "unreleased siblings keep their range")
+			assert.Equal(t, "^1.0.0", deps["left-pad"])
+			assert.Equal(t, "workspace:*", readDependencies(t, packageJSON, "devDependencies")["@org/core"])
+			assert.Equal(t, ">=1.0.0 <3.0.0", readDependencies(t, packageJSON, "peerDependencies")["@org/core"])
+
+			lockDeps := readDependencies(t, filepath.Join(root, "web", "package-lock.json"), "packages", "", "dependencies")
+			assert.Equal(t, tt.expected, lockDeps["@org/core"], "lockfile ranges match package.json")
+		})
+	}
+}
+
+func TestNPMEcosystem_UnsupportedLockfileWarnings(t *testing.T) {
+	root := t.TempDir()
+	pkgDir := filepath.Join(root, "packages", "web")
+	require.NoError(t, os.MkdirAll(pkgDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(pkgDir, "package.json"), []byte(`{"name": "web", "version": "1.0.0"}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "pnpm-lock.yaml"), []byte("lockfileVersion: '9.0'\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(pkgDir, "yarn.lock"), []byte("__metadata:\n  version: 8\n"), 0644))
+
+	eco := NewNPMEcosystem(pkgDir)
+	eco.SetContext(&HandlerContext{
+		PackageConfig: &config.Package{Name: "web", Path: "packages/web", Ecosystem: config.EcosystemNPM},
+		ProjectPath:   root,
+	})
+	require.NoError(t, eco.UpdateVersion(semver.MustParse("1.1.0")))
+	assert.Equal(t, []string{
+		"packages/web/yarn.lock (Yarn Berry) is not updated; run yarn install to refresh it",
+		"pnpm-lock.yaml is not updated; run pnpm install to refresh it",
+	}, eco.Warnings())
+}
@@ -17,6 +17,8 @@ var _ Handler = (*PythonEcosystem)(nil)
 
 // PythonEcosystem handles version management for Python projects
 type PythonEcosystem struct {
+	versionParser
+
 	path string
 }
 
@@ -26,7 +28,7 @@ func NewPythonEcosystem(path string) *PythonEcosystem {
 }
 
 // ReadVersion reads the current version from Python version files
-// Checks in order: pyproject.toml, __version__.py, setup.py
+// Checks in order: pyproject.toml, __version__.py, setup.cfg, setup.py
 func (p *PythonEcosystem) ReadVersion() (semver.Version, error) {
 	// Try pyproject.toml first
 	pyprojectPath := filepath.Join(p.path, "pyproject.toml")
@@ -43,22 +45,43 @@ func (p *PythonEcosystem) ReadVersion() (semver.Version, error) {
 		return p.readVersionFromVersionPy(versionPyPath)
 	}
 
+	// Try setup.cfg
+	setupCfgPath := filepath.Join(p.path, "setup.cfg")
+	if _, err := os.Stat(setupCfgPath); err == nil {
+		version, err := p.readVersionFromSetupCfg(setupCfgPath)
+		if err == nil {
+			return version, nil
+		}
+	}
+
 	// Try setup.py
 	setupPyPath := filepath.Join(p.path, "setup.py")
 	if _, err := os.Stat(setupPyPath); err == nil {
-		return p.readVersionFromSetupPy(setupPyPath)
+		version, err := p.readVersionFromSetupPy(setupPyPath)
+		if err == nil {
+			return version, nil
+		}
+		if dynErr := p.dynamicVersionError(); dynErr != nil {
+			return semver.Version{}, dynErr
+		}
+		return semver.Version{}, err
+	}
+
+	if err := p.dynamicVersionError(); err != nil {
+		return semver.Version{}, err
 	}
 
 	return semver.Version{}, fmt.Errorf("no version file found in Python project at %s", p.path)
 }
 
 // UpdateVersion updates the version in Python version files
+// Files that declare a dynamic version are left untouched.
 func (p *PythonEcosystem) UpdateVersion(version semver.Version) error {
 	updated := false
 
-	// Update pyproject.toml if it exists
+	// Update pyproject.toml if it holds a static version
 	pyprojectPath := filepath.Join(p.path, "pyproject.toml")
-	if _, err := os.Stat(pyprojectPath); err == nil {
+	if p.hasStaticPyprojectVersion(pyprojectPath) {
 		if err := p.updatePyproject(pyprojectPath, version); err != nil {
 			return err
 		}
@@ -74,9 +97,18 @@ func (p *PythonEcosystem) UpdateVersion(version semver.Version) error {
 		updated = true
 	}
 
-	// Update setup.py if it exists
+	// Update setup.cfg if it holds a static version
+	setupCfgPath := filepath.Join(p.path, "setup.cfg")
+	if p.hasStaticSetupCfgVersion(setupCfgPath) {
+		if err := p.updateSetupCfg(setupCfgPath, version); err != nil {
+			return err
+		}
+		updated = true
+	}
+
+	// Update setup.py if it holds a version (it may be a shim around setup.cfg)
 	setupPyPath := filepath.Join(p.path, "setup.py")
-	if _, err := os.Stat(setupPyPath); err == nil {
+	if _, err := p.readVersionFromSetupPy(setupPyPath); err == nil {
 		if err := p.updateSetupPy(setupPyPath, version); err != nil {
 			return err
 		}
@@ -84,6 +116,9 @@ func (p *PythonEcosystem) UpdateVersion(version semver.Version) error {
 	}
 
 	if !updated {
+		if err := p.dynamicVersionError(); err != nil {
+			return err
+		}
 		return fmt.Errorf("no version files to update in Python project at %s", p.path)
 	}
 
@@ -100,10 +135,25 @@ func (p *PythonEcosystem) GetVersionFiles() []string {
 	}{
 		{filepath.Join(p.path, "pyproject.toml"), "pyproject.toml"},
 		{filepath.Join(p.path, "__version__.py"), "__version__.py"},
+		{filepath.Join(p.path, "setup.cfg"), "setup.cfg"},
 		{filepath.Join(p.path, "setup.py"), "setup.py"},
 	}
 
 	for _, c := range candidates {
+		switch c.filename {
+		case "pyproject.toml":
+			if !p.hasStaticPyprojectVersion(c.fullPath) {
+				continue
+			}
+		case "setup.cfg":
+			if !p.hasStaticSetupCfgVersion(c.fullPath) {
+				continue
+			}
+		case "setup.py":
+			if _, err := p.readVersionFromSetupPy(c.fullPath); err != nil {
+				continue
+			}
+		}
 		if _, err := os.Stat(c.fullPath); err == nil {
 			files = append(files, c.filename)
 		}
@@ -112,36 +162,60 @@ func (p *PythonEcosystem) GetVersionFiles() []string {
 	return files
 }
 
-// readVersionFromPyproject extracts version from pyproject.toml
-func (p *PythonEcosystem) readVersionFromPyproject(path string) (semver.Version, error) {
-	var config struct {
-		Tool struct {
-			Poetry struct {
-				Version string `toml:"version"`
-			} `toml:"poetry"`
-		} `toml:"tool"`
-		Project struct {
+// pyprojectMetadata holds the version-related fields of pyproject.toml
+type pyprojectMetadata struct {
+	Tool struct {
+		Poetry struct {
 			Version string `toml:"version"`
-		} `toml:"project"`
+		} `toml:"poetry"`
+	} `toml:"tool"`
+	Project struct {
+		Version string   `toml:"version"`
+		Dynamic []string `toml:"dynamic"`
+	} `toml:"project"`
+}
+
+// staticVersion returns the declared version, preferring [tool.poetry]
+func (m pyprojectMetadata) staticVersion() string {
+	if m.Tool.Poetry.Version != "" {
+		return m.Tool.Poetry.Version
 	}
+	return m.Project.Version
+}
 
-	if _, err := toml.DecodeFile(path, &config); err != nil {
-		return semver.Version{}, fmt.Errorf("failed to parse pyproject.toml: %w", err)
+// dynamicVersion reports whether [project] lists version as dynamic
+func (m pyprojectMetadata) dynamicVersion() bool {
+	for _, field := range m.Project.Dynamic {
+		if field == "version" {
+			return true
+		}
 	}
+	return false
+}
 
-	// Check [tool.poetry] format
-	if config.Tool.Poetry.Version != "" {
-		return semver.Parse(config.Tool.Poetry.Version)
+// readVersionFromPyproject extracts version from pyproject.toml
+func (p *PythonEcosystem) readVersionFromPyproject(path string) (semver.Version, error) {
+	var config pyprojectMetadata
+	if _, err := toml.DecodeFile(path, &config); err != nil {
+		return semver.Version{}, fmt.Errorf("failed to parse pyproject.toml: %w", err)
 	}
 
-	// Check [project] format
-	if config.Project.Version != "" {
-		return semver.Parse(config.Project.Version)
+	if version := config.staticVersion(); version != "" {
+		return p.parseVersion(version)
 	}
 
 	return semver.Version{}, fmt.Errorf("no version found in pyproject.toml")
 }
 
+// hasStaticPyprojectVersion reports whether pyproject.toml exists and declares a literal version
+func (p *PythonEcosystem) hasStaticPyprojectVersion(path string) bool {
+	var config pyprojectMetadata
+	if _, err := toml.DecodeFile(path, &config); err != nil {
+		return false
+	}
+	return config.staticVersion() != ""
+}
+
 // readVersionFromVersionPy extracts version from __version__.py
 func (p *PythonEcosystem) readVersionFromVersionPy(path string) (semver.Version, error) {
 	content, err := fileutil.ReadFile(path)
@@ -157,7 +231,7 @@ func (p *PythonEcosystem) readVersionFromVersionPy(path string) (semver.Version,
 		return semver.Version{}, fmt.Errorf("no __version__ found in %s", path)
 	}
 
-	return semver.Parse(string(matches[1]))
+	return p.parseVersion(string(matches[1]))
 }
 
 // readVersionFromSetupPy extracts version from setup.py
@@ -175,7 +249,7 @@ func (p *PythonEcosystem) readVersionFromSetupPy(path string) (semver.Version, e
 		return semver.Version{}, fmt.Errorf("no version found in setup.py")
 	}
 
-	return semver.Parse(string(matches[1]))
+	return p.parseVersion(string(matches[1]))
 }
 
 // updatePyproject updates version in pyproject.toml, only matching version
@@ -262,6 +336,115 @@ func (p *PythonEcosystem) updateSetupPy(path string, version semver.Version) err
 	return fileutil.WriteFile(path, newContent, 0644)
 }
 
+// setupCfgVersionRe matches the version key in setup.cfg, capturing the key prefix and value
+var setupCfgVersionRe = regexp.MustCompile(`(?m)^(version\s*[=:]\s*)(\S.*?)\s*$`)
+
+// setupCfgMetadata returns the bounds of the [metadata] section in setup.cfg
+func setupCfgMetadata(content string) (start, end int, ok bool) {
+	headerRe := regexp.MustCompile(`(?m)^\[metadata\]\s*$`)
+	loc := headerRe.FindStringIndex(content)
+	if loc == nil {
+		return 0, 0, false
+	}
+
+	rest := content[loc[1]:]
+	next := regexp.MustCompile(`(?m)^\[`).FindStringIndex(rest)
+	if next == nil {
+		return loc[1], len(content), true
+	}
+	return loc[1], loc[1] + next[0], true
+}
+
+// setupCfgVersion returns the raw version value from the [metadata] section of setup.cfg
+func setupCfgVersion(content string) (string, bool) {
+	start, end, ok := setupCfgMetadata(content)
+	if !ok {
+		return "", false
+	}
+	matches := setupCfgVersionRe.FindStringSubmatch(content[start:end])
+	if len(matches) < 3 {
+		return "", false
+	}
+	return matches[2], true
+}
+
+// isDynamicSetupCfgVersion reports whether a setup.cfg version uses an attr: or file: directive
+func isDynamicSetupCfgVersion(value string) bool {
+	return strings.HasPrefix(value, "attr:") || strings.HasPrefix(value, "file:")
+}
+
+// readVersionFromSetupCfg extracts version from the [metadata] section of setup.cfg
+func (p *PythonEcosystem) readVersionFromSetupCfg(path string) (semver.Version, error) {
+	content, err := fileutil.ReadFile(path)
+	if err != nil {
+		return semver.Version{}, fmt.Errorf("failed to read setup.cfg: %w", err)
+	}
+
+	value, ok := setupCfgVersion(string(content))
+	if !ok || isDynamicSetupCfgVersion(value) {
+		return semver.Version{}, fmt.Errorf("no static version found in setup.cfg")
+	}
+
+	return p.parseVersion(strings.Trim(value, `"'`))
+}
+
+// hasStaticSetupCfgVersion reports whether setup.cfg exists and declares a literal version
+func (p *PythonEcosystem) hasStaticSetupCfgVersion(path string) bool {
+	_, err := p.readVersionFromSetupCfg(path)
+	return err == nil
+}
+
+// updateSetupCfg updates version in the [metadata] section of setup.cfg,
+// leaving the rest of the file untouched
+func (p *PythonEcosystem) updateSetupCfg(path string, version semver.Version) error {
+	content, err := fileutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read setup.cfg: %w", err)
+	}
+
+	contentStr := string(content)
+	start, end, ok := setupCfgMetadata(contentStr)
+	if !ok {
+		return fmt.Errorf("no [metadata] section found in setup.cfg")
+	}
+
+	section := contentStr[start:end]
+	loc := setupCfgVersionRe.FindStringSubmatchIndex(section)
+	if loc == nil {
+		return fmt.Errorf("no version field found in setup.cfg")
+	}
+
+	// Replace only the value so comments, spacing, and quoting style elsewhere are preserved
+	value := section[loc[4]:loc[5]]
+	replacement := version.String()
+	if len(value) > 1 && (value[0] == '"' || value[0] == '\'') {
+		replacement = string(value[0]) + replacement + string(value[0])
+	}
+	newSection := section[:loc[4]] + replacement + section[loc[5]:]
+
+	return fileutil.WriteFile(path, []byte(contentStr[:start]+newSection+contentStr[end:]), 0644)
+}
+
+// dynamicVersionError explains that the project computes its version at build
+// time, so there is no file shipyard can read or update. Returns nil if no
+// dynamic version is declared.
+func (p *PythonEcosystem) dynamicVersionError() error {
+	var config pyprojectMetadata
+	if _, err := toml.DecodeFile(filepath.Join(p.path, "pyproject.toml"), &config); err == nil {
+		if config.staticVersion() == "" && config.dynamicVersion() {
+			return fmt.Errorf("pyproject.toml in %s declares a dynamic version (project.dynamic); shipyard cannot update it. Add a __version__.py file or a static version", p.path)
+		}
+	}
+
+	if content, err := fileutil.ReadFile(filepath.Join(p.path, "setup.cfg")); err == nil {
+		if value, ok := setupCfgVersion(string(content)); ok && isDynamicSetupCfgVersion(value) {
+			return fmt.Errorf("setup.cfg in %s declares a dynamic version (%s); shipyard cannot update it. Add a __version__.py file or a static version", p.path, value)
+		}
+	}
+
+	return nil
+}
+
 // DetectPythonEcosystem checks if a directory contains a Python project
 func DetectPythonEcosystem(path string) bool {
 	// Check for pyproject.toml
@@ -274,6 +457,11 @@ func DetectPythonEcosystem(path string) bool {
 		return true
 	}
 
+	// Check for setup.cfg
+	if _, err := os.Stat(filepath.Join(path, "setup.cfg")); err == nil {
+		return true
+	}
+
 	// Check for __version__.py
 	if _, err := os.Stat(filepath.Join(path, "__version__.py")); err == nil {
 		return true
@@ -3,6 +3,7 @@ package ecosystem
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/NatoNathan/shipyard/pkg/semver"
@@ -152,6 +153,121 @@ description = "A test project"
 	})
 }
 
+func TestPythonEcosystem_SetupCfg(t *testing.T) {
+	original := `[metadata]
+name = myservice
+# keep in sync with releases
+version = 1.4.0
+description = A service
+
+[options]
+install_requires =
+    requests>=2.0.0
+python_requires = >=3.9
+`
+
+	t.Run("read version", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "setup.cfg"), []byte(original), 0644))
+
+		version, err := NewPythonEcosystem(tmpDir).ReadVersion()
+		require.NoError(t, err)
+		assert.Equal(t, "1.4.0", version.String())
+	})
+
+	t.Run("update version in place", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		setupCfgPath := filepath.Join(tmpDir, "setup.cfg")
+		require.NoError(t, os.WriteFile(setupCfgPath, []byte(original), 0644))
+		// setup.py shim without a version should be left alone
+		shim := "from setuptools import setup\n\nsetup()\n"
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "setup.py"), []byte(shim), 0644))
+
+		eco := NewPythonEcosystem(tmpDir)
+		newVer, _ := semver.Parse("1.5.0")
+		require.NoError(t, eco.UpdateVersion(newVer))
+
+		content, err := os.ReadFile(setupCfgPath)
+		require.NoError(t, err)
+		assert.Equal(t, strings.Replace(original, "version = 1.4.0", "version = 1.5.0", 1), string(content))
+		assert.Equal(t, []string{"setup.cfg"}, eco.GetVersionFiles())
+	})
+
+	t.Run("version outside metadata is ignored", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		content := "[tool:pytest]\nversion = 9.9.9\n"
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "setup.cfg"), []byte(content), 0644))
+
+		_, err := NewPythonEcosystem(tmpDir).ReadVersion()
+		assert.Error(t, err)
+	})
+}
+
+func TestPythonEcosystem_DynamicVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		content  string
+	}{
+		{
+			name:     "pyproject.toml dynamic version",
+			filename: "pyproject.toml",
+			content: `[project]
+name = "myservice"
+dynamic = ["version"]
+
+[tool.setuptools.dynamic]
+version = {attr = "myservice.VERSION"}
+`,
+		},
+		{
+			name:     "setup.cfg attr version",
+			filename: "setup.cfg",
+			content: `[metadata]
+name = myservice
+version = attr: myservice.__version__
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			path := filepath.Join(tmpDir, tt.filename)
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0644))
+
+			eco := NewPythonEcosystem(tmpDir)
+			_, err := eco.ReadVersion()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "dynamic version")
+
+			newVer, _ := semver.Parse("2.0.0")
+			err = eco.UpdateVersion(newVer)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "dynamic version")
+
+			content, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.content, string(content), "file must not be modified")
+		})
+	}
+
+	t.Run("dynamic version backed by __version__.py", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "pyproject.toml"), []byte(tests[0].content), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "__version__.py"), []byte("__version__ = \"1.0.0\"\n"), 0644))
+
+		eco := NewPythonEcosystem(tmpDir)
+		version, err := eco.ReadVersion()
+		require.NoError(t, err)
+		assert.Equal(t, "1.0.0", version.String())
+
+		newVer, _ := semver.Parse("1.1.0")
+		require.NoError(t, eco.UpdateVersion(newVer))
+		assert.Equal(t, []string{"__version__.py"}, eco.GetVersionFiles())
+	})
+}
+
 func TestDetectPythonEcosystem(t *testing.T) {
 	tests := []struct {
 		name     string
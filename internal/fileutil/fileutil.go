@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -20,12 +21,31 @@ func AtomicWrite(path string, data []byte, perm os.FileMode) error {
 	}
 
 	// Create temp file in same directory (for atomic rename)
-	tmpFile := path + ".tmp"
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpFile := tmp.Name()
 
-	// Write to temp file
-	if err := WriteFile(tmpFile, data, perm); err != nil {
+	// Write and sync the temp file so the rename never exposes partial data
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpFile)
 		return fmt.Errorf("failed to write temp file: %w", err)
 	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpFile)
+		return fmt.Errorf("failed to sync temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpFile)
+		return fmt.Errorf("failed to close temp file: %w", err)
+	}
+	if err := os.Chmod(tmpFile, perm); err != nil {
+		_ = os.Remove(tmpFile)
+		return fmt.Errorf("failed to set temp file mode: %w", err)
+	}
 
 	// Atomic rename
 	if err := os.Rename(tmpFile, path); err != nil {
@@ -73,6 +93,15 @@ func ReadFile(path string) ([]byte, error) {
 	return os.ReadFile(cleanPath) // #nosec G304 -- Shipyard intentionally reads configured repository paths.
 }
 
+// NormalizeNewlines strips a leading UTF-8 byte order mark and converts CRLF
+// and lone CR line endings to LF, so files saved by Windows editors parse the
+// same as files Shipyard wrote
+func NormalizeNewlines(s string) string {
+	s = strings.TrimPrefix(s, "\uFEFF")
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.ReplaceAll(s, "\r", "\n")
+}
+
 // WriteFile writes a project-scoped file path. Shipyard writes user-visible
 // repository artifacts, so 0644 is an intentional default for many callers.
 func WriteFile(path string, data []byte, perm os.FileMode) error {
@@ -120,3 +149,108 @@ func WriteYAMLFile(path string, v interface{}, perm os.FileMode) error {
 
 	return nil
 }
+
+// ActualCase returns rel, relative to root, with each element spelled as it
+// is on disk. An element with no exact directory entry takes the entry that
+// matches it case-insensitively, so on a case-insensitive filesystem the
+// result reveals the real casing and on a case-sensitive one it finds the
+// path that was meant. It returns an error wrapping os.ErrNotExist when an
+// element matches no entry.
+func ActualCase(root, rel string) (string, error) {
+	elements := strings.Split(filepath.ToSlash(filepath.Clean(rel)), "/")
+	dir := root
+	for i, element := range elements {
+		if element == "." || element == ".." || element == "" {
+			dir = filepath.Join(dir, element)
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return "", err
+		}
+		match := ""
+		for _, entry := range entries {
+			if entry.Name() == element {
+				match = element
+				break
+			}
+			if match == "" && strings.EqualFold(entry.Name(), element) {
+				match = entry.Name()
+			}
+		}
+		if match == "" {
+			return "", fmt.Errorf("%s: %w", filepath.Join(dir, element), os.ErrNotExist)
+		}
+		elements[i] = match
+		dir = filepath.Join(dir, match)
+	}
+	return filepath.FromSlash(strings.Join(elements, "/")), nil
+}
+
+// SanitizePath turns arbitrary text into a relative, slash-separated path
+// that cannot leave the directory it is joined to: separators are
+// normalized, control characters and drive colons are replaced with "-", and
+// empty, "." and ".." elements are dropped. It returns "" when nothing usable
+// is left.
+func SanitizePath(s string) string {
+	s = strings.ToValidUTF8(s, "-")
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r == '\\':
+			return '/'
+		case r < 0x20, r == 0x7f, r == ':':
+			return '-'
+		}
+		return r
+	}, s)
+
+	var elements []string
+	for _, element := range strings.Split(s, "/") {
+		if element == "" || element == "." || element == ".." {
+			continue
+		}
+		elements = append(elements, element)
+	}
+	return strings.Join(elements, "/")
+}
+
+// WithinRoot joins rel to root and verifies the result stays inside root,
+// both lexically and after resolving symlinks in the part of the path that
+// already exists. Absolute paths and paths that escape root are rejected
+// with an error naming the offending value.
+func WithinRoot(root, rel string) (string, error) {
+	if filepath.IsAbs(rel) || filepath.VolumeName(rel) != "" {
+		return "", fmt.Errorf("path %q must be relative to the project root", rel)
+	}
+
+	joined := filepath.Join(root, rel)
+	if !isWithin(filepath.Clean(root), joined) {
+		return "", fmt.Errorf("path %q escapes the project root", rel)
+	}
+
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return joined, nil
+	}
+	existing := joined
+	for !PathExists(existing) && existing != filepath.Clean(root) {
+		existing = filepath.Dir(existing)
+	}
+	realExisting, err := filepath.EvalSymlinks(existing)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve path %q: %w", rel, err)
+	}
+	if !isWithin(realRoot, realExisting) {
+		return "", fmt.Errorf("path %q escapes the project root through a symlink", rel)
+	}
+	return joined, nil
+}
+
+// isWithin reports whether path is root or below it; both must be clean
+func isWithin(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -3,6 +3,7 @@ package fileutil
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -207,3 +208,137 @@ func TestWriteYAMLFile(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, data["name"], result["name"])
 }
+
+func TestActualCase(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "packages", "API"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "packages", "API", "go.mod"), []byte("module api\n"), 0644))
+
+	tests := []struct {
+		rel  string
+		want string
+	}{
+		{rel: "packages/API", want: filepath.Join("packages", "API")},
+		{rel: "./Packages/api", want: filepath.Join("packages", "API")},
+		{rel: "PACKAGES/api/GO.MOD", want: filepath.Join("packages", "API", "go.mod")},
+		{rel: "./", want: "."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rel, func(t *testing.T) {
+			got, err := ActualCase(root, tt.rel)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := ActualCase(root, "packages/web")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+// hostilePathInputs are values a template could render into a file path
+var hostilePathInputs = []string{
+	"../../etc/passwd",
+	"/etc/passwd",
+	"..\\..\\Windows\\system32",
+	"C:\\Windows",
+	"docs/../../secret",
+	"a/./b//c",
+	"name\x00.md",
+	"line\nbreak.md",
+	"..",
+	".",
+	"",
+	"\xff\xfe",
+	"ünïcödé/İ.md",
+}
+
+func TestSanitizePath(t *testing.T) {
+	tests := map[string]string{
+		"docs/CHANGELOG.md": "docs/CHANGELOG.md",
+		"../../etc/passwd":  "etc/passwd",
+		"/etc/passwd":       "etc/passwd",
+		"..\\..\\Windows":   "Windows",
+		"C:\\Windows":       "C-/Windows",
+		"a/./b//c":          "a/b/c",
+		"line\nbreak.md":    "line-break.md",
+		"..":                "",
+		"":                  "",
+	}
+	for input, want := range tests {
+		assert.Equal(t, want, SanitizePath(input), "%q", input)
+	}
+}
+
+func TestNormalizeNewlines(t *testing.T) {
+	tests := map[string]string{
+		"a\nb\n":            "a\nb\n",
+		"a\r\nb\r\n":        "a\nb\n",
+		"a\rb":              "a\nb",
+		"\uFEFF---\r\nid: x": "---\nid: x",
+		"mid\uFEFFdle":      "mid\uFEFFdle",
+	}
+	for input, want := range tests {
+		assert.Equal(t, want, NormalizeNewlines(input), "%q", input)
+	}
+}
+
+func TestWithinRoot(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "pkg"), 0755))
+
+	path, err := WithinRoot(root, "pkg/CHANGELOG.md")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "pkg", "CHANGELOG.md"), path)
+
+	path, err = WithinRoot(root, "new/dir/file.md")
+	require.NoError(t, err, "paths that do not exist yet are allowed")
+	assert.Equal(t, filepath.Join(root, "new", "dir", "file.md"), path)
+
+	for _, rel := range []string{"../outside.md", "pkg/../../outside.md", "/etc/passwd"} {
+		_, err := WithinRoot(root, rel)
+		require.Error(t, err, rel)
+		assert.Contains(t, err.Error(), rel)
+	}
+}
+
+func TestWithinRoot_Symlink(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(root, "link")); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+
+	_, err := WithinRoot(root, "link/CHANGELOG.md")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "symlink")
+}
+
+func FuzzSanitizePath(f *testing.F) {
+	for _, input := range hostilePathInputs {
+		f.Add(input)
+	}
+	root := f.TempDir()
+	f.Fuzz(func(t *testing.T, input string) {
+		rel := SanitizePath(input)
+		if _, err := WithinRoot(root, rel); err != nil {
+			t.Fatalf("SanitizePath(%q) = %q escapes the root: %v", input, rel, err)
+		}
+	})
+}
+
+func FuzzWithinRoot(f *testing.F) {
+	for _, input := range hostilePathInputs {
+		f.Add(input)
+	}
+	root := f.TempDir()
+	f.Fuzz(func(t *testing.T, rel string) {
+		path, err := WithinRoot(root, rel)
+		if err != nil {
+			return
+		}
+		inside, relErr := filepath.Rel(root, path)
+		if relErr != nil || inside == ".." || strings.HasPrefix(inside, ".."+string(filepath.Separator)) {
+			t.Fatalf("WithinRoot(%q) accepted %q outside %q", rel, path, root)
+		}
+	})
+}
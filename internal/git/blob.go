@@ -0,0 +1,87 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// BlobHashAtHead returns the git blob hash of a file as committed at HEAD.
+// filePath may be absolute or relative to repoPath.
+func BlobHashAtHead(repoPath, filePath string) (string, error) {
+	repo, err := gogit.PlainOpenWithOptions(repoPath, &gogit.PlainOpenOptions{
+		DetectDotGit: true,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return "", fmt.Errorf("failed to get worktree: %w", err)
+	}
+
+	if !filepath.IsAbs(filePath) {
+		filePath = filepath.Join(repoPath, filePath)
+	}
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve path: %w", err)
+	}
+	root, err := filepath.EvalSymlinks(worktree.Filesystem.Root())
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve repository root: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(absPath); err == nil {
+		absPath = resolved
+	}
+	relPath, err := filepath.Rel(root, absPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve path relative to repository: %w", err)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("failed to get HEAD: %w", err)
+	}
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return "", fmt.Errorf("failed to get HEAD commit: %w", err)
+	}
+	file, err := commit.File(filepath.ToSlash(relPath))
+	if err != nil {
+		return "", fmt.Errorf("failed to find %s at HEAD: %w", relPath, err)
+	}
+
+	return file.Hash.String(), nil
+}
+
+// ReadFileAtRevision returns the content of a file as committed at rev, a
+// branch, tag or commit hash. file is slash separated and relative to the
+// repository root. ok is false when the file does not exist at rev.
+func ReadFileAtRevision(repoPath, rev, file string) (content []byte, ok bool, err error) {
+	repo, err := gogit.PlainOpen(repoPath)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to open repository: %w", err)
+	}
+	commit, err := resolveCommit(repo, rev)
+	if err != nil {
+		return nil, false, err
+	}
+
+	f, err := commit.File(file)
+	if errors.Is(err, object.ErrFileNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to find %s at %s: %w", file, rev, err)
+	}
+	contents, err := f.Contents()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read %s at %s: %w", file, rev, err)
+	}
+	return []byte(contents), true, nil
+}
@@ -0,0 +1,99 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBlobHashAtHead tests reading a committed file's blob hash
+func TestBlobHashAtHead(t *testing.T) {
+	tempDir := t.TempDir()
+	repo, err := gogit.PlainInit(tempDir, false)
+	require.NoError(t, err)
+
+	content := []byte("packages: []\n")
+	configDir := filepath.Join(tempDir, ".shipyard")
+	require.NoError(t, os.MkdirAll(configDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "shipyard.yaml"), content, 0644))
+
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	_, err = worktree.Add(".shipyard/shipyard.yaml")
+	require.NoError(t, err)
+	require.NoError(t, CreateCommit(tempDir, "Add config"))
+
+	expected := plumbing.ComputeHash(plumbing.BlobObject, content).String()
+
+	t.Run("absolute path", func(t *testing.T) {
+		hash, err := BlobHashAtHead(tempDir, filepath.Join(configDir, "shipyard.yaml"))
+		require.NoError(t, err)
+		assert.Equal(t, expected, hash)
+	})
+
+	t.Run("relative path", func(t *testing.T) {
+		hash, err := BlobHashAtHead(tempDir, ".shipyard/shipyard.yaml")
+		require.NoError(t, err)
+		assert.Equal(t, expected, hash)
+	})
+
+	t.Run("uncommitted file", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, "other.yaml"), content, 0644))
+		_, err := BlobHashAtHead(tempDir, "other.yaml")
+		assert.Error(t, err)
+	})
+}
+
+// TestBlobHashAtHead_NoCommits tests a repository without a HEAD commit
+func TestBlobHashAtHead_NoCommits(t *testing.T) {
+	tempDir := t.TempDir()
+	_, err := gogit.PlainInit(tempDir, false)
+	require.NoError(t, err)
+
+	_, err = BlobHashAtHead(tempDir, "shipyard.yaml")
+	assert.Error(t, err)
+}
+
+// TestReadFileAtRevision tests reading a file as committed at a tag
+func TestReadFileAtRevision(t *testing.T) {
+	tempDir := t.TempDir()
+	repo, err := gogit.PlainInit(tempDir, false)
+	require.NoError(t, err)
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+
+	manifest := filepath.Join(tempDir, "core", "go.mod")
+	require.NoError(t, os.MkdirAll(filepath.Dir(manifest), 0755))
+	require.NoError(t, os.WriteFile(manifest, []byte("module core\n"), 0644))
+	_, err = worktree.Add("core/go.mod")
+	require.NoError(t, err)
+	require.NoError(t, CreateCommit(tempDir, "Add core"))
+	require.NoError(t, CreateAnnotatedTag(tempDir, "core/v1.0.0", "Release core 1.0.0"))
+
+	require.NoError(t, os.WriteFile(manifest, []byte("module core\n\ngo 1.25\n"), 0644))
+	_, err = worktree.Add("core/go.mod")
+	require.NoError(t, err)
+	require.NoError(t, CreateCommit(tempDir, "Set go version"))
+
+	content, ok, err := ReadFileAtRevision(tempDir, "core/v1.0.0", "core/go.mod")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "module core\n", string(content))
+
+	content, ok, err = ReadFileAtRevision(tempDir, "HEAD", "core/go.mod")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "module core\n\ngo 1.25\n", string(content))
+
+	_, ok, err = ReadFileAtRevision(tempDir, "core/v1.0.0", "core/package.json")
+	require.NoError(t, err)
+	assert.False(t, ok, "a missing file is not an error")
+
+	_, _, err = ReadFileAtRevision(tempDir, "core/v9.9.9", "core/go.mod")
+	assert.Error(t, err)
+}
@@ -32,6 +32,23 @@ func getCommitAuthor(repo *gogit.Repository) *object.Signature {
 	return sig
 }
 
+// ConfiguredAuthor returns the configured git user as "Name <email>", or an
+// empty string when no user is configured or repoPath is not a repository
+func ConfiguredAuthor(repoPath string) string {
+	repo, err := gogit.PlainOpen(repoPath)
+	if err != nil {
+		return ""
+	}
+	cfg, err := repo.ConfigScoped(gogitconfig.GlobalScope)
+	if err != nil || cfg.User.Name == "" {
+		return ""
+	}
+	if cfg.User.Email == "" {
+		return cfg.User.Name
+	}
+	return fmt.Sprintf("%s <%s>", cfg.User.Name, cfg.User.Email)
+}
+
 // CreateCommit creates a git commit with the given message
 // Returns error if repository is invalid or no changes are staged
 func CreateCommit(repoPath, message string) error {
@@ -97,6 +114,56 @@ func HeadHash(repoPath string) (plumbing.Hash, error) {
 	return head.Hash(), nil
 }
 
+// CurrentBranch returns the name of the checked-out branch, or "" when HEAD
+// is detached
+func CurrentBranch(repoPath string) (string, error) {
+	repo, err := gogit.PlainOpen(repoPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	head, err := repo.Reference(plumbing.HEAD, false)
+	if err != nil {
+		return "", fmt.Errorf("failed to get HEAD: %w", err)
+	}
+	if head.Type() != plumbing.SymbolicReference || !head.Target().IsBranch() {
+		return "", nil
+	}
+	return head.Target().Short(), nil
+}
+
+// FileAddedAt returns the author time of the first commit that touched path,
+// relative to the repository root. ok is false when no commit on HEAD
+// touches it, such as for an uncommitted file or a repository without commits.
+func FileAddedAt(repoPath, path string) (added time.Time, ok bool, err error) {
+	repo, err := gogit.PlainOpen(repoPath)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("failed to open repository: %w", err)
+	}
+	if _, err := repo.Head(); err != nil {
+		if err == plumbing.ErrReferenceNotFound {
+			return time.Time{}, false, nil
+		}
+		return time.Time{}, false, fmt.Errorf("failed to get HEAD: %w", err)
+	}
+
+	commits, err := repo.Log(&gogit.LogOptions{FileName: &path})
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("failed to read history of %s: %w", path, err)
+	}
+	defer commits.Close()
+
+	// The log runs newest first, so the last commit seen added the file
+	err = commits.ForEach(func(c *object.Commit) error {
+		added, ok = c.Author.When, true
+		return nil
+	})
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("failed to read history of %s: %w", path, err)
+	}
+	return added, ok, nil
+}
+
 // ResetHard resets the working tree and HEAD to the given commit hash.
 func ResetHard(repoPath string, hash plumbing.Hash) error {
 	repo, err := gogit.PlainOpen(repoPath)
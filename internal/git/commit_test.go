@@ -4,8 +4,10 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -291,3 +293,70 @@ func TestResetMixed_RewindsHeadWithoutChangingWorktree(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "uncommitted", string(content))
 }
+
+// TestFileAddedAt tests finding when a file was first committed
+func TestFileAddedAt(t *testing.T) {
+	tempDir := t.TempDir()
+	repo, err := gogit.PlainInit(tempDir, false)
+	require.NoError(t, err)
+
+	_, ok, err := FileAddedAt(tempDir, "a.txt")
+	require.NoError(t, err)
+	assert.False(t, ok, "repository without commits")
+
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	commitAt := func(name, content string, when time.Time) {
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644))
+		_, err := worktree.Add(name)
+		require.NoError(t, err)
+		_, err = worktree.Commit("Update "+name, &gogit.CommitOptions{
+			Author: &object.Signature{Name: "Test User", Email: "test@example.com", When: when},
+		})
+		require.NoError(t, err)
+	}
+
+	created := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	commitAt("a.txt", "one", created)
+	commitAt("a.txt", "two", created.Add(48*time.Hour))
+	commitAt("b.txt", "other", created.Add(72*time.Hour))
+
+	added, ok, err := FileAddedAt(tempDir, "a.txt")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.True(t, created.Equal(added), "got %s", added)
+
+	_, ok, err = FileAddedAt(tempDir, "untracked.txt")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestCurrentBranch(t *testing.T) {
+	tempDir := t.TempDir()
+	repo, err := gogit.PlainInit(tempDir, false)
+	require.NoError(t, err)
+
+	branch, err := CurrentBranch(tempDir)
+	require.NoError(t, err)
+	assert.Equal(t, "master", branch, "an unborn branch is still checked out")
+
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "a.txt"), []byte("a"), 0644))
+	_, err = worktree.Add("a.txt")
+	require.NoError(t, err)
+	hash, err := worktree.Commit("Initial commit", &gogit.CommitOptions{
+		Author: &object.Signature{Name: "Test User", Email: "test@example.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, worktree.Checkout(&gogit.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("release/1.x"), Create: true}))
+	branch, err = CurrentBranch(tempDir)
+	require.NoError(t, err)
+	assert.Equal(t, "release/1.x", branch)
+
+	require.NoError(t, worktree.Checkout(&gogit.CheckoutOptions{Hash: hash}))
+	branch, err = CurrentBranch(tempDir)
+	require.NoError(t, err)
+	assert.Empty(t, branch, "detached HEAD")
+}
@@ -0,0 +1,76 @@
+package git
+
+import (
+	"fmt"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// ChangedSince returns the paths HEAD changed relative to baseRef, as a pull
+// request diff shows them: from the merge base of baseRef and HEAD to HEAD,
+// so commits that landed on baseRef since the branch point are left out.
+// baseRef is a branch, remote branch (origin/main), tag or commit hash.
+// Paths are slash separated, relative to the repository root, and sorted.
+func ChangedSince(repoPath, baseRef string) ([]string, error) {
+	repo, err := gogit.PlainOpen(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HEAD: %w", err)
+	}
+	headCommit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read HEAD commit: %w", err)
+	}
+	baseCommit, err := resolveCommit(repo, baseRef)
+	if err != nil {
+		return nil, err
+	}
+
+	bases, err := baseCommit.MergeBase(headCommit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the merge base of %s and HEAD: %w", baseRef, err)
+	}
+	if len(bases) == 0 {
+		return nil, fmt.Errorf("%s and HEAD have no common history", baseRef)
+	}
+
+	baseTree, err := bases[0].Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tree of %s: %w", bases[0].Hash, err)
+	}
+	headTree, err := headCommit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tree of HEAD: %w", err)
+	}
+	files, err := diffPaths(baseTree, headTree)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff %s against HEAD: %w", baseRef, err)
+	}
+	return files, nil
+}
+
+// resolveCommit resolves a revision to a commit, peeling annotated tags
+func resolveCommit(repo *gogit.Repository, rev string) (*object.Commit, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", rev, err)
+	}
+	if tagObject, err := repo.TagObject(*hash); err == nil {
+		commit, err := tagObject.Commit()
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve tag %s: %w", rev, err)
+		}
+		return commit, nil
+	}
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", rev, err)
+	}
+	return commit, nil
+}
@@ -0,0 +1,52 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangedSince(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := gogit.PlainInit(dir, false)
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "api"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "web"), 0755))
+
+	commitFile(t, repo, dir, "README.md", "# Project\n")
+	require.NoError(t, CreateAnnotatedTag(dir, "v1.0.0", "Release 1.0.0"))
+	head, err := repo.Head()
+	require.NoError(t, err)
+	base := head.Name()
+
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+	require.NoError(t, wt.Checkout(&gogit.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("feature"), Create: true}))
+	commitFile(t, repo, dir, "api/main.go", "package main\n")
+	commitFile(t, repo, dir, "web/index.js", "export {}\n")
+
+	// A commit on the base branch after the branch point is not part of the change
+	require.NoError(t, wt.Checkout(&gogit.CheckoutOptions{Branch: base}))
+	commitFile(t, repo, dir, "README.md", "# Project\n\nUsage\n")
+	require.NoError(t, wt.Checkout(&gogit.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("feature")}))
+
+	files, err := ChangedSince(dir, base.Short())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api/main.go", "web/index.js"}, files)
+
+	files, err = ChangedSince(dir, "v1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api/main.go", "web/index.js"}, files, "annotated tags resolve to their commit")
+
+	files, err = ChangedSince(dir, "feature")
+	require.NoError(t, err)
+	assert.Empty(t, files)
+
+	_, err = ChangedSince(dir, "origin/missing")
+	assert.ErrorContains(t, err, "failed to resolve origin/missing")
+}
@@ -0,0 +1,132 @@
+package git
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// LogEntry is a commit on HEAD and the files it changed
+type LogEntry struct {
+	Hash    string
+	Message string
+	When    time.Time // Author time
+	Files   []string  // Paths relative to the repository root, slash separated
+}
+
+// CommitsSince returns the commits on HEAD that are not reachable from
+// tagName, oldest first. An empty tagName returns the whole history. Merge
+// commits are left out, since the commits they bring in are listed themselves.
+func CommitsSince(repoPath, tagName string) ([]LogEntry, error) {
+	repo, err := gogit.PlainOpen(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		if err == plumbing.ErrReferenceNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get HEAD: %w", err)
+	}
+
+	released := make(map[plumbing.Hash]bool)
+	if tagName != "" {
+		ref, err := repo.Tag(tagName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find tag %s: %w", tagName, err)
+		}
+		commit, err := tagCommit(repo, ref, tagName)
+		if err != nil {
+			return nil, err
+		}
+		err = object.NewCommitPreorderIter(commit, nil, nil).ForEach(func(c *object.Commit) error {
+			released[c.Hash] = true
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read history of tag %s: %w", tagName, err)
+		}
+	}
+
+	headCommit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read HEAD commit: %w", err)
+	}
+
+	var entries []LogEntry
+	err = object.NewCommitPreorderIter(headCommit, released, nil).ForEach(func(c *object.Commit) error {
+		if c.NumParents() > 1 {
+			return nil
+		}
+		files, err := changedFiles(c)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, LogEntry{
+			Hash:    c.Hash.String(),
+			Message: c.Message,
+			When:    c.Author.When,
+			Files:   files,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+
+	// The walk runs from HEAD back; report history in the order it happened
+	slices.Reverse(entries)
+	return entries, nil
+}
+
+// changedFiles lists the paths a commit changed relative to its parent, or
+// every path for a root commit
+func changedFiles(c *object.Commit) ([]string, error) {
+	tree, err := c.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tree of %s: %w", c.Hash, err)
+	}
+	var parentTree *object.Tree
+	if c.NumParents() == 1 {
+		parent, err := c.Parent(0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read parent of %s: %w", c.Hash, err)
+		}
+		if parentTree, err = parent.Tree(); err != nil {
+			return nil, fmt.Errorf("failed to read tree of %s: %w", parent.Hash, err)
+		}
+	}
+
+	files, err := diffPaths(parentTree, tree)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff %s: %w", c.Hash, err)
+	}
+	return files, nil
+}
+
+// diffPaths lists the paths that differ between two trees, sorted. A nil
+// tree is empty. Renames list both the old and the new path.
+func diffPaths(from, to *object.Tree) ([]string, error) {
+	changes, err := object.DiffTree(from, to)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var files []string
+	for _, change := range changes {
+		for _, name := range []string{change.From.Name, change.To.Name} {
+			if name != "" && !seen[name] {
+				seen[name] = true
+				files = append(files, name)
+			}
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
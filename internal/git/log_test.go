@@ -0,0 +1,51 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommitsSince(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := gogit.PlainInit(dir, false)
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "api"), 0755))
+
+	first := commitFile(t, repo, dir, "README.md", "# Project\n")
+	second := commitFile(t, repo, dir, "api/main.go", "package main\n")
+	require.NoError(t, CreateAnnotatedTag(dir, "v1.0.0", "Release 1.0.0"))
+	third := commitFile(t, repo, dir, "api/main.go", "package main\n\nfunc main() {}\n")
+	fourth := commitFile(t, repo, dir, "README.md", "# Project\n\nUsage\n")
+
+	all, err := CommitsSince(dir, "")
+	require.NoError(t, err)
+	require.Len(t, all, 4)
+	assert.Equal(t, first.String(), all[0].Hash, "oldest first")
+	assert.Equal(t, []string{"README.md"}, all[0].Files, "a root commit changes every file")
+	assert.Equal(t, second.String(), all[1].Hash)
+	assert.Equal(t, []string{"api/main.go"}, all[1].Files)
+	assert.Equal(t, "Update api/main.go", all[1].Message)
+
+	since, err := CommitsSince(dir, "v1.0.0")
+	require.NoError(t, err)
+	require.Len(t, since, 2)
+	assert.Equal(t, third.String(), since[0].Hash)
+	assert.Equal(t, fourth.String(), since[1].Hash)
+
+	_, err = CommitsSince(dir, "v9.9.9")
+	assert.Error(t, err)
+}
+
+func TestCommitsSince_NoCommits(t *testing.T) {
+	dir := t.TempDir()
+	initGitRepo(t, dir)
+
+	entries, err := CommitsSince(dir, "")
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
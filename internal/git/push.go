@@ -0,0 +1,264 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/NatoNathan/shipyard/internal/logger"
+	gogit "github.com/go-git/go-git/v5"
+	gogitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+)
+
+// Environment variables holding a token for HTTPS remotes, in precedence order.
+// GITHUB_TOKEN is only sent to GitHubHost.
+const (
+	TokenEnv       = "SHIPYARD_GIT_TOKEN"
+	GitHubTokenEnv = "GITHUB_TOKEN"
+)
+
+// GitHubHost is the host a GitHub token is sent to
+const GitHubHost = "github.com"
+
+// DefaultRemote is the remote releases are pushed to when none is configured
+const DefaultRemote = "origin"
+
+// tokenUsername is sent with token auth; GitHub, GitLab and Gitea accept any
+// non-empty username alongside a token
+const tokenUsername = "x-access-token"
+
+// PushOptions configures Push
+type PushOptions struct {
+	Remote      string   // Remote name; DefaultRemote when empty
+	Branch      string   // Local branch to push; the checked-out branch when empty
+	Tags        []string // Tags to push alongside the branch
+	Token       string   // Token for HTTPS remotes on any host
+	GitHubToken string   // Token for HTTPS remotes on GitHubHost when Token is empty
+	SSHKeyPath  string   // Private key for SSH remotes; ssh-agent when empty
+	DryRun      bool     // Check credentials, reachability and refs without pushing
+}
+
+// PushRefError reports the ref a push failed on
+type PushRefError struct {
+	Ref string
+	Err error
+}
+
+func (e *PushRefError) Error() string {
+	return fmt.Sprintf("failed to push %s: %v", e.Ref, e.Err)
+}
+
+func (e *PushRefError) Unwrap() error {
+	return e.Err
+}
+
+// tokenFor returns the token for an HTTPS remote on host: Token, or
+// GitHubToken when the host is GitHubHost. A GitHub token is never sent to
+// other hosts.
+func (o PushOptions) tokenFor(host string) string {
+	if o.Token != "" {
+		return o.Token
+	}
+	if strings.EqualFold(host, GitHubHost) {
+		return o.GitHubToken
+	}
+	return ""
+}
+
+// pushAuth picks the auth method for a remote URL. HTTPS remotes use the
+// token for their host, SSH remotes the key file; without either, go-git's
+// defaults apply (anonymous HTTPS, ssh-agent for SSH). Local remotes need no
+// auth.
+func pushAuth(url string, opts PushOptions) (transport.AuthMethod, error) {
+	endpoint, err := transport.NewEndpoint(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote URL %q: %w", url, err)
+	}
+
+	switch endpoint.Protocol {
+	case "http", "https":
+		token := opts.tokenFor(endpoint.Host)
+		if token == "" {
+			return nil, nil
+		}
+		return &http.BasicAuth{Username: tokenUsername, Password: token}, nil
+	case "ssh":
+		if opts.SSHKeyPath == "" {
+			return nil, nil
+		}
+		user := endpoint.User
+		if user == "" {
+			user = "git"
+		}
+		if _, err := os.Stat(opts.SSHKeyPath); err != nil {
+			return nil, fmt.Errorf("failed to read SSH key: %w", err)
+		}
+		auth, err := ssh.NewPublicKeysFromFile(user, opts.SSHKeyPath, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to load SSH key %s: %w", opts.SSHKeyPath, err)
+		}
+		return auth, nil
+	}
+	return nil, nil
+}
+
+// Push pushes a branch and tags to a remote in a single atomic push where the
+// remote supports it. Before pushing, it lists the remote's refs, which checks
+// credentials and reachability, and rejects any ref the remote would refuse:
+// a branch that does not fast-forward or a tag that points elsewhere. Refs the
+// remote already has are skipped. With DryRun set, Push stops after these checks.
+func Push(repoPath string, opts PushOptions) error {
+	repo, err := gogit.PlainOpen(repoPath)
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	remoteName := opts.Remote
+	if remoteName == "" {
+		remoteName = DefaultRemote
+	}
+	remote, err := repo.Remote(remoteName)
+	if err != nil {
+		return fmt.Errorf("failed to get remote '%s': %w", remoteName, err)
+	}
+	urls := remote.Config().URLs
+	if len(urls) == 0 {
+		return fmt.Errorf("remote '%s' has no URL", remoteName)
+	}
+
+	auth, err := pushAuth(urls[0], opts)
+	if err != nil {
+		return err
+	}
+
+	branch, err := pushBranch(repo, opts.Branch)
+	if err != nil {
+		return err
+	}
+	refs := []plumbing.ReferenceName{branch}
+	for _, tag := range opts.Tags {
+		refs = append(refs, plumbing.NewTagReferenceName(tag))
+	}
+
+	remoteRefs, err := remote.List(&gogit.ListOptions{Auth: auth})
+	if err != nil && !errors.Is(err, transport.ErrEmptyRemoteRepository) {
+		return fmt.Errorf("failed to reach remote '%s': %w", remoteName, err)
+	}
+	advertised := make(map[plumbing.ReferenceName]plumbing.Hash, len(remoteRefs))
+	for _, ref := range remoteRefs {
+		advertised[ref.Name()] = ref.Hash()
+	}
+
+	var specs []gogitconfig.RefSpec
+	for _, name := range refs {
+		update, err := checkPushRef(repo, name, advertised)
+		if err != nil {
+			return &PushRefError{Ref: name.String(), Err: err}
+		}
+		if update {
+			specs = append(specs, gogitconfig.RefSpec(name.String()+":"+name.String()))
+		}
+	}
+	if opts.DryRun || len(specs) == 0 {
+		logger.Get().Debug("Nothing to push to %s (dry run: %t)", remoteName, opts.DryRun)
+		return nil
+	}
+	logger.Get().Debug("Pushing %d ref(s) to %s", len(specs), remoteName)
+
+	err = remote.Push(&gogit.PushOptions{
+		RemoteName: remoteName,
+		RefSpecs:   specs,
+		Auth:       auth,
+		Atomic:     true,
+	})
+	if err != nil && !errors.Is(err, gogit.NoErrAlreadyUpToDate) {
+		// The remote names the ref it refused; go-git's own errors do not
+		for _, spec := range specs {
+			if ref := spec.Src(); strings.Contains(err.Error(), ref) {
+				return &PushRefError{Ref: ref, Err: err}
+			}
+		}
+		return fmt.Errorf("failed to push to '%s': %w", remoteName, err)
+	}
+	return nil
+}
+
+// RemoteURL returns the first URL of a remote
+func RemoteURL(repoPath, remoteName string) (string, error) {
+	repo, err := gogit.PlainOpen(repoPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open repository: %w", err)
+	}
+	remote, err := repo.Remote(remoteName)
+	if err != nil {
+		return "", fmt.Errorf("failed to get remote '%s': %w", remoteName, err)
+	}
+	urls := remote.Config().URLs
+	if len(urls) == 0 {
+		return "", fmt.Errorf("remote '%s' has no URL", remoteName)
+	}
+	return urls[0], nil
+}
+
+// pushBranch resolves the branch to push, defaulting to the checked-out one
+func pushBranch(repo *gogit.Repository, name string) (plumbing.ReferenceName, error) {
+	if name != "" {
+		ref := plumbing.NewBranchReferenceName(name)
+		if _, err := repo.Reference(ref, false); err != nil {
+			return "", fmt.Errorf("failed to find branch '%s': %w", name, err)
+		}
+		return ref, nil
+	}
+
+	head, err := repo.Reference(plumbing.HEAD, false)
+	if err != nil {
+		return "", fmt.Errorf("failed to get HEAD: %w", err)
+	}
+	if head.Type() != plumbing.SymbolicReference || !head.Target().IsBranch() {
+		return "", fmt.Errorf("HEAD is detached; check out the release branch before pushing")
+	}
+	return head.Target(), nil
+}
+
+// checkPushRef reports whether a local ref needs pushing, or why the remote
+// would refuse it
+func checkPushRef(repo *gogit.Repository, name plumbing.ReferenceName, advertised map[plumbing.ReferenceName]plumbing.Hash) (bool, error) {
+	local, err := repo.Reference(name, true)
+	if err != nil {
+		return false, fmt.Errorf("not found locally: %w", err)
+	}
+
+	remoteHash, exists := advertised[name]
+	if !exists {
+		return true, nil
+	}
+	if remoteHash == local.Hash() {
+		return false, nil
+	}
+	if name.IsTag() {
+		return false, fmt.Errorf("tag already exists on the remote with a different target")
+	}
+
+	// A branch update must fast-forward the remote branch
+	remoteCommit, err := repo.CommitObject(remoteHash)
+	if err != nil {
+		return false, fmt.Errorf("remote has commits that are not present locally; pull before pushing")
+	}
+	localCommit, err := repo.CommitObject(local.Hash())
+	if err != nil {
+		return false, fmt.Errorf("failed to read local commit: %w", err)
+	}
+	ancestor, err := remoteCommit.IsAncestor(localCommit)
+	if err != nil {
+		return false, fmt.Errorf("failed to compare with the remote branch: %w", err)
+	}
+	if !ancestor {
+		return false, fmt.Errorf("remote branch has diverged; pull before pushing")
+	}
+	return true, nil
+}
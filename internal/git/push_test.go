@@ -0,0 +1,253 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	gogitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupPushRepos creates a repository with one commit on main and an empty
+// bare repository registered as its origin remote
+func setupPushRepos(t *testing.T) (string, *gogit.Repository, *gogit.Repository) {
+	t.Helper()
+	dir := t.TempDir()
+	repo, err := gogit.PlainInitWithOptions(dir, &gogit.PlainInitOptions{
+		InitOptions: gogit.InitOptions{DefaultBranch: plumbing.NewBranchReferenceName("main")},
+	})
+	require.NoError(t, err)
+
+	bareDir := t.TempDir()
+	bare, err := gogit.PlainInit(bareDir, true)
+	require.NoError(t, err)
+
+	_, err = repo.CreateRemote(&gogitconfig.RemoteConfig{Name: "origin", URLs: []string{bareDir}})
+	require.NoError(t, err)
+
+	commitFile(t, repo, dir, "a.txt", "one")
+	return dir, repo, bare
+}
+
+// commitFile writes a file and commits it
+func commitFile(t *testing.T, repo *gogit.Repository, dir, name, content string) plumbing.Hash {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	_, err = worktree.Add(name)
+	require.NoError(t, err)
+	hash, err := worktree.Commit("Update "+name, &gogit.CommitOptions{
+		Author: &object.Signature{Name: "Test User", Email: "test@example.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+	return hash
+}
+
+func TestPush_BranchAndTags(t *testing.T) {
+	dir, repo, bare := setupPushRepos(t)
+	require.NoError(t, CreateAnnotatedTag(dir, "core/v1.0.0", "Release core 1.0.0"))
+	require.NoError(t, CreateLightweightTag(dir, "api/v0.1.0"))
+
+	require.NoError(t, Push(dir, PushOptions{Tags: []string{"core/v1.0.0", "api/v0.1.0"}}))
+
+	head, err := repo.Head()
+	require.NoError(t, err)
+	branch, err := bare.Reference(plumbing.NewBranchReferenceName("main"), false)
+	require.NoError(t, err)
+	assert.Equal(t, head.Hash(), branch.Hash())
+
+	for _, tag := range []string{"core/v1.0.0", "api/v0.1.0"} {
+		local, err := repo.Tag(tag)
+		require.NoError(t, err)
+		remote, err := bare.Tag(tag)
+		require.NoError(t, err, tag)
+		assert.Equal(t, local.Hash(), remote.Hash(), tag)
+	}
+
+	// Pushing again is a no-op
+	require.NoError(t, Push(dir, PushOptions{Tags: []string{"core/v1.0.0", "api/v0.1.0"}}))
+}
+
+func TestPush_DryRun(t *testing.T) {
+	dir, _, bare := setupPushRepos(t)
+	require.NoError(t, CreateLightweightTag(dir, "v1.0.0"))
+
+	require.NoError(t, Push(dir, PushOptions{Tags: []string{"v1.0.0"}, DryRun: true}))
+
+	_, err := bare.Reference(plumbing.NewBranchReferenceName("main"), false)
+	assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+	_, err = bare.Tag("v1.0.0")
+	assert.Error(t, err)
+}
+
+func TestPush_RemoteOverride(t *testing.T) {
+	dir, repo, bare := setupPushRepos(t)
+	_, err := repo.CreateRemote(&gogitconfig.RemoteConfig{Name: "release", URLs: []string{t.TempDir()}})
+	require.NoError(t, err)
+
+	// The release remote points at an empty directory that is not a repository
+	err = Push(dir, PushOptions{Remote: "release"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to reach remote 'release'")
+
+	require.NoError(t, Push(dir, PushOptions{Remote: "origin"}))
+	_, err = bare.Reference(plumbing.NewBranchReferenceName("main"), false)
+	assert.NoError(t, err)
+
+	err = Push(dir, PushOptions{Remote: "missing"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to get remote 'missing'")
+}
+
+func TestPush_RejectedRefs(t *testing.T) {
+	t.Run("tag with a different target", func(t *testing.T) {
+		dir, repo, _ := setupPushRepos(t)
+		require.NoError(t, CreateLightweightTag(dir, "v1.0.0"))
+		require.NoError(t, Push(dir, PushOptions{Tags: []string{"v1.0.0"}}))
+
+		// Move the tag locally to a new commit
+		require.NoError(t, DeleteTags(dir, []string{"v1.0.0"}))
+		commitFile(t, repo, dir, "a.txt", "two")
+		require.NoError(t, CreateLightweightTag(dir, "v1.0.0"))
+
+		for _, dryRun := range []bool{true, false} {
+			err := Push(dir, PushOptions{Tags: []string{"v1.0.0"}, DryRun: dryRun})
+			var refErr *PushRefError
+			require.True(t, errors.As(err, &refErr), "dryRun=%v: %v", dryRun, err)
+			assert.Equal(t, "refs/tags/v1.0.0", refErr.Ref)
+			assert.Contains(t, err.Error(), "different target")
+		}
+	})
+
+	t.Run("diverged branch", func(t *testing.T) {
+		dir, repo, _ := setupPushRepos(t)
+		require.NoError(t, Push(dir, PushOptions{}))
+
+		// Another clone pushes a commit the local repository does not have
+		otherDir := t.TempDir()
+		remoteURL := remoteURLOf(t, repo)
+		other, err := gogit.PlainClone(otherDir, false, &gogit.CloneOptions{
+			URL:           remoteURL,
+			ReferenceName: plumbing.NewBranchReferenceName("main"),
+		})
+		require.NoError(t, err)
+		commitFile(t, other, otherDir, "b.txt", "other")
+		require.NoError(t, other.Push(&gogit.PushOptions{}))
+
+		commitFile(t, repo, dir, "a.txt", "two")
+		require.NoError(t, CreateLightweightTag(dir, "v1.1.0"))
+
+		err = Push(dir, PushOptions{Tags: []string{"v1.1.0"}})
+		var refErr *PushRefError
+		require.True(t, errors.As(err, &refErr), "%v", err)
+		assert.Equal(t, "refs/heads/main", refErr.Ref)
+		assert.Contains(t, err.Error(), "pull before pushing")
+	})
+
+	t.Run("missing local tag", func(t *testing.T) {
+		dir, _, _ := setupPushRepos(t)
+		err := Push(dir, PushOptions{Tags: []string{"v9.9.9"}})
+		var refErr *PushRefError
+		require.True(t, errors.As(err, &refErr), "%v", err)
+		assert.Equal(t, "refs/tags/v9.9.9", refErr.Ref)
+	})
+}
+
+func TestPush_DetachedHead(t *testing.T) {
+	dir, repo, _ := setupPushRepos(t)
+	head, err := repo.Head()
+	require.NoError(t, err)
+	require.NoError(t, repo.Storer.SetReference(plumbing.NewHashReference(plumbing.HEAD, head.Hash())))
+
+	err = Push(dir, PushOptions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "HEAD is detached")
+
+	// Naming the branch works from a detached HEAD
+	require.NoError(t, Push(dir, PushOptions{Branch: "main"}))
+}
+
+func TestPushAuth(t *testing.T) {
+	t.Run("HTTPS with token", func(t *testing.T) {
+		auth, err := pushAuth("https://github.com/acme/repo.git", PushOptions{Token: "secret"})
+		require.NoError(t, err)
+		basic, ok := auth.(*http.BasicAuth)
+		require.True(t, ok)
+		assert.Equal(t, "secret", basic.Password)
+		assert.NotEmpty(t, basic.Username)
+	})
+
+	t.Run("HTTPS without token", func(t *testing.T) {
+		auth, err := pushAuth("https://github.com/acme/repo.git", PushOptions{})
+		require.NoError(t, err)
+		assert.Nil(t, auth)
+	})
+
+	t.Run("token is not sent to SSH remotes", func(t *testing.T) {
+		auth, err := pushAuth("git@github.com:acme/repo.git", PushOptions{Token: "secret", GitHubToken: "github"})
+		require.NoError(t, err)
+		assert.Nil(t, auth)
+	})
+
+	t.Run("missing SSH key", func(t *testing.T) {
+		keyPath := filepath.Join(t.TempDir(), "id_ed25519")
+		_, err := pushAuth("ssh://git@github.com/acme/repo.git", PushOptions{SSHKeyPath: keyPath})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "id_ed25519")
+	})
+
+	t.Run("invalid SSH key", func(t *testing.T) {
+		keyPath := filepath.Join(t.TempDir(), "id_ed25519")
+		require.NoError(t, os.WriteFile(keyPath, []byte("not a key"), 0600))
+		_, err := pushAuth("git@github.com:acme/repo.git", PushOptions{SSHKeyPath: keyPath})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to load SSH key")
+	})
+
+	t.Run("local remote", func(t *testing.T) {
+		auth, err := pushAuth("/srv/git/repo.git", PushOptions{Token: "secret"})
+		require.NoError(t, err)
+		assert.Nil(t, auth)
+	})
+}
+
+func TestPushAuth_GitHubTokenScope(t *testing.T) {
+	opts := PushOptions{GitHubToken: "github"}
+
+	auth, err := pushAuth("https://github.com/acme/repo.git", opts)
+	require.NoError(t, err)
+	basic, ok := auth.(*http.BasicAuth)
+	require.True(t, ok)
+	assert.Equal(t, "github", basic.Password)
+
+	auth, err = pushAuth("https://git.example.com/acme/repo.git", opts)
+	require.NoError(t, err)
+	assert.Nil(t, auth, "a GitHub token must not reach other hosts")
+
+	// SHIPYARD_GIT_TOKEN is sent to any host, and wins on GitHub
+	opts.Token = "shipyard"
+	for _, url := range []string{"https://git.example.com/acme/repo.git", "https://github.com/acme/repo.git"} {
+		auth, err = pushAuth(url, opts)
+		require.NoError(t, err)
+		basic, ok = auth.(*http.BasicAuth)
+		require.True(t, ok, url)
+		assert.Equal(t, "shipyard", basic.Password, url)
+	}
+}
+
+// remoteURLOf returns the URL of a repository's origin remote
+func remoteURLOf(t *testing.T, repo *gogit.Repository) string {
+	t.Helper()
+	remote, err := repo.Remote("origin")
+	require.NoError(t, err)
+	return remote.Config().URLs[0]
+}
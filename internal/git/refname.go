@@ -0,0 +1,114 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// refForbidden lists the bytes git never allows in a ref name besides
+// ASCII control characters
+const refForbidden = " ~^:?*[\\"
+
+// ValidateRefName checks a short ref name (a tag or branch name) against
+// git's ref-name rules (git check-ref-format). The checks work on bytes, so
+// the result never depends on the locale. The error quotes the offending
+// value so control characters and path tricks are visible.
+func ValidateRefName(name string) error {
+	if problem := refNameProblem(name); problem != "" {
+		return fmt.Errorf("invalid ref name %q: %s", name, problem)
+	}
+	return nil
+}
+
+// refNameProblem describes the first rule name breaks, or "" if it is valid
+func refNameProblem(name string) string {
+	switch {
+	case name == "":
+		return "name is empty"
+	case name == "@":
+		return `name is "@"`
+	case !utf8.ValidString(name):
+		return "name is not valid UTF-8"
+	case strings.HasPrefix(name, "-"):
+		return `name begins with "-"`
+	case strings.HasPrefix(name, "/"), strings.HasSuffix(name, "/"):
+		return `name begins or ends with "/"`
+	case strings.HasSuffix(name, "."):
+		return `name ends with "."`
+	case strings.Contains(name, ".."):
+		return `name contains ".."`
+	case strings.Contains(name, "//"):
+		return `name contains "//"`
+	case strings.Contains(name, "@{"):
+		return `name contains "@{"`
+	}
+
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if c < 0x20 || c == 0x7f {
+			return fmt.Sprintf("name contains control character %#02x", c)
+		}
+		if strings.IndexByte(refForbidden, c) >= 0 {
+			return fmt.Sprintf("name contains %q", c)
+		}
+	}
+
+	for _, component := range strings.Split(name, "/") {
+		if strings.HasPrefix(component, ".") {
+			return fmt.Sprintf("component %q begins with \".\"", component)
+		}
+		if strings.HasSuffix(component, ".lock") {
+			return fmt.Sprintf("component %q ends with \".lock\"", component)
+		}
+	}
+	return ""
+}
+
+// SanitizeRefName turns arbitrary text into a valid ref name by replacing
+// forbidden characters with "-" and dropping the sequences git rejects.
+// It returns "" when nothing usable is left.
+func SanitizeRefName(s string) string {
+	s = strings.ToValidUTF8(s, "-")
+	s = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || (r < utf8.RuneSelf && strings.ContainsRune(refForbidden, r)) {
+			return '-'
+		}
+		return r
+	}, s)
+	s = strings.ReplaceAll(s, "@{", "@-")
+
+	var components []string
+	for _, component := range strings.Split(s, "/") {
+		component = cleanRefComponent(component, len(components) == 0)
+		if component != "" {
+			components = append(components, component)
+		}
+	}
+
+	name := strings.Join(components, "/")
+	if name == "@" {
+		return ""
+	}
+	return name
+}
+
+// cleanRefComponent strips what git rejects from one "/"-separated component:
+// "..", leading and trailing dots, a ".lock" suffix and, for the first
+// component, leading dashes
+func cleanRefComponent(component string, first bool) string {
+	for {
+		before := component
+		for strings.Contains(component, "..") {
+			component = strings.ReplaceAll(component, "..", ".")
+		}
+		component = strings.Trim(component, ".")
+		component = strings.TrimSuffix(component, ".lock")
+		if first {
+			component = strings.TrimLeft(component, "-")
+		}
+		if component == before {
+			return component
+		}
+	}
+}
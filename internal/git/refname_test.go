@@ -0,0 +1,145 @@
+package git
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hostileRefInputs are values a consignment summary or metadata field could
+// smuggle into a tag template
+var hostileRefInputs = []string{
+	"../../etc/passwd",
+	"v1.0.0/../../HEAD",
+	"refs/heads/main",
+	"-delete",
+	"@",
+	"a@{1}",
+	"fix: login bug",
+	"tag\nsecond line",
+	"tag\x00nul",
+	"tag\x7f",
+	"C:\\Windows\\system32",
+	"v1.lock",
+	".hidden/.git",
+	"a//b/",
+	"trailing.",
+	"star*?[glob]~^",
+	"ünïcödé-İ-ß",
+	"\xff\xfe",
+	"",
+}
+
+func TestValidateRefName(t *testing.T) {
+	valid := []string{"v1.0.0", "core/v1.2.3", "release-2026.01.30", "api/v2.0.0-rc.1", "ünïcödé", "a@b"}
+	for _, name := range valid {
+		assert.NoError(t, ValidateRefName(name), name)
+	}
+
+	tests := []struct {
+		name    string
+		problem string
+	}{
+		{"", "empty"},
+		{"@", `"@"`},
+		{"../x", `".."`},
+		{"v1/../x", `".."`},
+		{"/v1", `"/"`},
+		{"v1/", `"/"`},
+		{"a//b", `"//"`},
+		{"v1.", `ends with "."`},
+		{"a@{1}", `"@{"`},
+		{"-v1", `begins with "-"`},
+		{"v 1", `' '`},
+		{"v1\n", "control character 0x0a"},
+		{"v1\x7f", "control character 0x7f"},
+		{"v1:x", `':'`},
+		{"v1~1", `'~'`},
+		{"v1^", `'^'`},
+		{"v1?", `'?'`},
+		{"v1*", `'*'`},
+		{"v1[", `'['`},
+		{"a\\b", `'\\'`},
+		{".v1", `begins with "."`},
+		{"core/.v1", `begins with "."`},
+		{"v1.lock", `".lock"`},
+		{"\xff", "UTF-8"},
+	}
+	for _, tt := range tests {
+		err := ValidateRefName(tt.name)
+		require.Error(t, err, "%q", tt.name)
+		assert.Contains(t, err.Error(), tt.problem, "%q", tt.name)
+	}
+}
+
+func TestValidateRefName_QuotesOffendingValue(t *testing.T) {
+	err := ValidateRefName("v1\n../x")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"v1\n../x"`)
+}
+
+func TestSanitizeRefName(t *testing.T) {
+	tests := map[string]string{
+		"v1.0.0":           "v1.0.0",
+		"core/v1.2.3":      "core/v1.2.3",
+		"../../etc/passwd": "etc/passwd",
+		"fix: login bug":   "fix--login-bug",
+		"-delete":          "delete",
+		"a@{1}":            "a@-1}",
+		"v1.lock":          "v1",
+		"a//b/":            "a/b",
+		"trailing.":        "trailing",
+		"tag\nsecond":      "tag-second",
+		"@":                "",
+		"...":              "",
+		"":                 "",
+	}
+	for input, want := range tests {
+		assert.Equal(t, want, SanitizeRefName(input), "%q", input)
+	}
+}
+
+func TestSanitizeRefName_HostileInputs(t *testing.T) {
+	for _, input := range hostileRefInputs {
+		if name := SanitizeRefName(input); name != "" {
+			assert.NoError(t, ValidateRefName(name), "%q -> %q", input, name)
+		}
+	}
+}
+
+func FuzzSanitizeRefName(f *testing.F) {
+	for _, input := range hostileRefInputs {
+		f.Add(input)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		name := SanitizeRefName(input)
+		if name == "" {
+			return
+		}
+		if err := ValidateRefName(name); err != nil {
+			t.Fatalf("SanitizeRefName(%q) = %q: %v", input, name, err)
+		}
+	})
+}
+
+func FuzzValidateRefName(f *testing.F) {
+	for _, input := range hostileRefInputs {
+		f.Add(input)
+	}
+	f.Fuzz(func(t *testing.T, name string) {
+		if ValidateRefName(name) != nil {
+			return
+		}
+		// Anything accepted stays a plain name under refs/tags
+		for _, forbidden := range []string{"..", "//", "@{", "\\", "\x00", "\n", " "} {
+			if strings.Contains(name, forbidden) {
+				t.Fatalf("accepted %q containing %q", name, forbidden)
+			}
+		}
+		if strings.HasPrefix(name, "/") || strings.HasPrefix(name, "-") {
+			t.Fatalf("accepted %q", name)
+		}
+	})
+}
@@ -2,12 +2,17 @@ package git
 
 import (
 	"fmt"
+	"path"
 	"path/filepath"
+	"strings"
 
+	"github.com/NatoNathan/shipyard/internal/fileutil"
 	gogit "github.com/go-git/go-git/v5"
 )
 
-// StageFiles stages multiple files in the git repository
+// StageFiles stages multiple files in the git repository. Paths are staged
+// with the casing the index already records for them, so a path spelled
+// differently on a case-insensitive filesystem does not add a second entry.
 func StageFiles(repoPath string, filePaths []string) error {
 	repo, err := gogit.PlainOpen(repoPath)
 	if err != nil {
@@ -19,6 +24,14 @@ func StageFiles(repoPath string, filePaths []string) error {
 		return fmt.Errorf("failed to get worktree: %w", err)
 	}
 
+	var recorded []string
+	if idx, err := repo.Storer.Index(); err == nil {
+		for _, entry := range idx.Entries {
+			recorded = append(recorded, entry.Name)
+		}
+	}
+	casing := newRecordedCasing(recorded)
+
 	for _, filePath := range filePaths {
 		// Convert to relative path from repo root
 		// If filePath is already relative, use it as-is
@@ -32,7 +45,14 @@ func StageFiles(repoPath string, filePaths []string) error {
 				relPath = filePath
 			}
 		}
+		// Only respell when the recorded spelling reaches the same file,
+		// which holds on case-insensitive filesystems
+		if respelled := casing.apply(relPath); respelled != relPath && fileutil.PathExists(filepath.Join(repoPath, respelled)) {
+			relPath = respelled
+		}
 
+		// go-git matches index entries by slash-separated path
+		relPath = filepath.ToSlash(relPath)
 		_, err = worktree.Add(relPath)
 		if err != nil {
 			return fmt.Errorf("failed to stage %s: %w", relPath, err)
@@ -41,3 +61,36 @@ func StageFiles(repoPath string, filePaths []string) error {
 
 	return nil
 }
+
+// recordedCasing maps lowercased index paths, and their parent directories,
+// to the casing the index records
+type recordedCasing map[string]string
+
+func newRecordedCasing(names []string) recordedCasing {
+	casing := make(recordedCasing)
+	for _, name := range names {
+		for p := name; p != "." && p != "/" && p != ""; p = path.Dir(p) {
+			if _, ok := casing[strings.ToLower(p)]; ok {
+				break
+			}
+			casing[strings.ToLower(p)] = p
+		}
+	}
+	return casing
+}
+
+// apply respells relPath with the recorded casing of the longest recorded
+// prefix. Paths already recorded exactly, or not recorded at all, are
+// returned unchanged.
+func (c recordedCasing) apply(relPath string) string {
+	slashed := filepath.ToSlash(relPath)
+	if recorded, ok := c[strings.ToLower(slashed)]; ok {
+		return filepath.FromSlash(recorded)
+	}
+	for dir := path.Dir(slashed); dir != "." && dir != "/"; dir = path.Dir(dir) {
+		if recorded, ok := c[strings.ToLower(dir)]; ok {
+			return filepath.FromSlash(recorded + slashed[len(dir):])
+		}
+	}
+	return relPath
+}
@@ -177,3 +177,74 @@ func TestStageFiles_NonExistentFile(t *testing.T) {
 	// The behavior depends on the version, so we just check it doesn't panic
 	assert.NotNil(t, err, "Should return error for non-existent file")
 }
+
+func TestStageFiles_RecordedCasing(t *testing.T) {
+	tmpDir := t.TempDir()
+	repo, err := gogit.PlainInit(tmpDir, false)
+	require.NoError(t, err)
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "Docs"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "Docs", "Guide.md"), []byte("v1"), 0644))
+	_, err = worktree.Add("Docs/Guide.md")
+	require.NoError(t, err)
+	_, err = worktree.Commit("Initial commit", &gogit.CommitOptions{
+		Author: &object.Signature{Name: "Test", Email: "test@example.com"},
+	})
+	require.NoError(t, err)
+
+	// A path spelled with different casing, as a case-insensitive filesystem
+	// would accept it, stages the recorded entry
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "Docs", "Guide.md"), []byte("v2"), 0644))
+	require.NoError(t, StageFiles(tmpDir, []string{filepath.Join(tmpDir, "docs", "guide.md")}))
+
+	status, err := worktree.Status()
+	require.NoError(t, err)
+	assert.Len(t, status, 1)
+	assert.Equal(t, gogit.Modified, status.File("Docs/Guide.md").Staging)
+}
+
+func TestStageFiles_NestedPathsUseSlashes(t *testing.T) {
+	tmpDir := t.TempDir()
+	repo, err := gogit.PlainInit(tmpDir, false)
+	require.NoError(t, err)
+
+	// Paths built with the OS separator, absolute and relative, are staged
+	// under their slash-separated index names
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "packages", "core"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "packages", "core", "CHANGELOG.md"), []byte("# Changelog\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "packages", "core", "version.go"), []byte("package core\n"), 0644))
+	require.NoError(t, StageFiles(tmpDir, []string{
+		filepath.Join(tmpDir, "packages", "core", "CHANGELOG.md"),
+		filepath.Join("packages", "core", "version.go"),
+	}))
+
+	idx, err := repo.Storer.Index()
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range idx.Entries {
+		names = append(names, entry.Name)
+	}
+	assert.ElementsMatch(t, []string{"packages/core/CHANGELOG.md", "packages/core/version.go"}, names)
+}
+
+func TestRecordedCasing(t *testing.T) {
+	casing := newRecordedCasing([]string{"Packages/API/version.go", "README.md"})
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "packages/api/version.go", want: filepath.FromSlash("Packages/API/version.go")},
+		{path: "packages/api/CHANGELOG.md", want: filepath.FromSlash("Packages/API/CHANGELOG.md")},
+		{path: "readme.md", want: "README.md"},
+		{path: "Packages/API/version.go", want: filepath.FromSlash("Packages/API/version.go")},
+		{path: "other/file.txt", want: "other/file.txt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, casing.apply(tt.path))
+		})
+	}
+}
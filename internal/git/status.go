@@ -0,0 +1,90 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/NatoNathan/shipyard/internal/fileutil"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// ChangedFiles returns the slash-separated paths, relative to the
+// repository root, of files that differ from HEAD in the index or worktree,
+// including untracked files that are not ignored. Sorted.
+func ChangedFiles(repoPath string) ([]string, error) {
+	repo, err := gogit.PlainOpen(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get worktree: %w", err)
+	}
+
+	status, err := worktree.Status()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read repository status: %w", err)
+	}
+
+	var changed []string
+	for path, fileStatus := range status {
+		if fileStatus.Staging != gogit.Unmodified || fileStatus.Worktree != gogit.Unmodified {
+			changed = append(changed, path)
+		}
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// RestoreFiles returns files, given as slash-separated paths relative to the
+// repository root, to their content at HEAD. Files HEAD does not have are
+// removed.
+func RestoreFiles(repoPath string, paths []string) error {
+	repo, err := gogit.PlainOpen(repoPath)
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	var commit *object.Commit
+	if head, err := repo.Head(); err == nil {
+		commit, err = repo.CommitObject(head.Hash())
+		if err != nil {
+			return fmt.Errorf("failed to get HEAD commit: %w", err)
+		}
+	}
+
+	for _, path := range paths {
+		target := filepath.Join(repoPath, filepath.FromSlash(path))
+
+		var file *object.File
+		if commit != nil {
+			file, _ = commit.File(path)
+		}
+		if file == nil {
+			if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove %s: %w", path, err)
+			}
+			continue
+		}
+
+		content, err := file.Contents()
+		if err != nil {
+			return fmt.Errorf("failed to read %s at HEAD: %w", path, err)
+		}
+		mode, err := file.Mode.ToOSFileMode()
+		if err != nil {
+			mode = 0644
+		}
+		if err := fileutil.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return fmt.Errorf("failed to restore %s: %w", path, err)
+		}
+		if err := fileutil.WriteFile(target, []byte(content), mode.Perm()); err != nil {
+			return fmt.Errorf("failed to restore %s: %w", path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestChangedFilesAndRestore tests listing files changed since HEAD and
+// restoring them
+func TestChangedFilesAndRestore(t *testing.T) {
+	tempDir := t.TempDir()
+	repo, err := gogit.PlainInit(tempDir, false)
+	require.NoError(t, err)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "chart"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "chart", "Chart.lock"), []byte("old\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "README.md"), []byte("readme\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, ".gitignore"), []byte("build/\n"), 0644))
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	for _, path := range []string{"chart/Chart.lock", "README.md", ".gitignore"} {
+		_, err = worktree.Add(path)
+		require.NoError(t, err)
+	}
+	require.NoError(t, CreateCommit(tempDir, "Initial commit"))
+
+	changed, err := ChangedFiles(tempDir)
+	require.NoError(t, err)
+	assert.Empty(t, changed)
+
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "chart", "Chart.lock"), []byte("new\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "chart", "types.d.ts"), []byte("types\n"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "build"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "build", "out.js"), []byte("ignored\n"), 0644))
+
+	changed, err = ChangedFiles(tempDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"chart/Chart.lock", "chart/types.d.ts"}, changed)
+
+	require.NoError(t, RestoreFiles(tempDir, changed))
+	content, err := os.ReadFile(filepath.Join(tempDir, "chart", "Chart.lock"))
+	require.NoError(t, err)
+	assert.Equal(t, "old\n", string(content))
+	assert.NoFileExists(t, filepath.Join(tempDir, "chart", "types.d.ts"))
+
+	changed, err = ChangedFiles(tempDir)
+	require.NoError(t, err)
+	assert.Empty(t, changed)
+}
@@ -2,12 +2,19 @@ package git
 
 import (
 	"fmt"
+	"regexp"
 
 	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 )
 
 // CreateAnnotatedTag creates an annotated git tag at HEAD
 func CreateAnnotatedTag(repoPath, tagName, message string) error {
+	if err := ValidateRefName(tagName); err != nil {
+		return err
+	}
+
 	// Open repository
 	repo, err := gogit.PlainOpen(repoPath)
 	if err != nil {
@@ -61,6 +68,10 @@ func CreateAnnotatedTags(repoPath string, tags map[string]string) error {
 
 // CreateLightweightTag creates a lightweight git tag at HEAD
 func CreateLightweightTag(repoPath, tagName string) error {
+	if err := ValidateRefName(tagName); err != nil {
+		return err
+	}
+
 	// Open repository
 	repo, err := gogit.PlainOpen(repoPath)
 	if err != nil {
@@ -115,6 +126,45 @@ func CreateLightweightTags(repoPath string, tagNames []string) error {
 	return nil
 }
 
+// CreateTagAt creates a tag pointing at the commit another tag points at.
+// With a message the tag is annotated, otherwise lightweight.
+func CreateTagAt(repoPath, tagName, sourceTag, message string) error {
+	if err := ValidateRefName(tagName); err != nil {
+		return err
+	}
+
+	repo, err := gogit.PlainOpen(repoPath)
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	if _, err := repo.Tag(tagName); err == nil {
+		return fmt.Errorf("tag already exists: %s", tagName)
+	}
+
+	ref, err := repo.Tag(sourceTag)
+	if err != nil {
+		return fmt.Errorf("failed to find tag %s: %w", sourceTag, err)
+	}
+	commit, err := tagCommit(repo, ref, sourceTag)
+	if err != nil {
+		return err
+	}
+
+	var opts *gogit.CreateTagOptions
+	if message != "" {
+		opts = &gogit.CreateTagOptions{
+			Tagger:  getCommitAuthor(repo),
+			Message: message,
+		}
+	}
+	if _, err := repo.CreateTag(tagName, commit.Hash, opts); err != nil {
+		return fmt.Errorf("failed to create tag: %w", err)
+	}
+
+	return nil
+}
+
 // EnsureTagsAbsent verifies that none of the provided tags already exist.
 func EnsureTagsAbsent(repoPath string, tagNames []string) error {
 	repo, err := gogit.PlainOpen(repoPath)
@@ -167,6 +217,118 @@ func VerifyTagExists(repoPath, tagName string) (bool, error) {
 	return true, nil
 }
 
+// VerifyTagMatchesRelease reports whether an existing tag points at a commit
+// that already holds the release: every manifest in versions, keyed by path
+// relative to the repository root, must contain its version there. A rerun of
+// an interrupted release can then treat the tag as done rather than a
+// collision. A missing tag or manifest does not match.
+func VerifyTagMatchesRelease(repoPath, tagName string, versions map[string]string) (bool, error) {
+	repo, err := gogit.PlainOpen(repoPath)
+	if err != nil {
+		return false, fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	ref, err := repo.Tag(tagName)
+	if err != nil {
+		if err == gogit.ErrTagNotFound {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check tag: %w", err)
+	}
+
+	commit, err := tagCommit(repo, ref, tagName)
+	if err != nil {
+		return false, err
+	}
+
+	for path, version := range versions {
+		file, err := commit.File(path)
+		if err != nil {
+			if err == object.ErrFileNotFound {
+				return false, nil
+			}
+			return false, fmt.Errorf("failed to read %s at tag %s: %w", path, tagName, err)
+		}
+		content, err := file.Contents()
+		if err != nil {
+			return false, fmt.Errorf("failed to read %s at tag %s: %w", path, tagName, err)
+		}
+		if !containsVersion(content, version) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// ListTags returns the names of the tags in the local repository
+func ListTags(repoPath string) ([]string, error) {
+	repo, err := gogit.PlainOpen(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	iter, err := repo.Tags()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	var names []string
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		names = append(names, ref.Name().Short())
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	return names, nil
+}
+
+// TagCommitHash returns the SHA of the commit a tag points at, or "" when
+// the tag does not exist locally
+func TagCommitHash(repoPath, tagName string) (string, error) {
+	repo, err := gogit.PlainOpen(repoPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	ref, err := repo.Tag(tagName)
+	if err != nil {
+		if err == gogit.ErrTagNotFound {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to check tag: %w", err)
+	}
+
+	commit, err := tagCommit(repo, ref, tagName)
+	if err != nil {
+		return "", err
+	}
+	return commit.Hash.String(), nil
+}
+
+// tagCommit resolves a tag reference to its commit. Annotated tags point at a
+// tag object, lightweight tags at the commit.
+func tagCommit(repo *gogit.Repository, ref *plumbing.Reference, tagName string) (*object.Commit, error) {
+	if tagObject, err := repo.TagObject(ref.Hash()); err == nil {
+		commit, err := tagObject.Commit()
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve tag %s: %w", tagName, err)
+		}
+		return commit, nil
+	}
+	commit, err := repo.CommitObject(ref.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve tag %s: %w", tagName, err)
+	}
+	return commit, nil
+}
+
+// containsVersion reports whether content holds version as a whole version,
+// so 1.2.0 does not match 11.2.0, 1.2.0.1 or 1.2.0-rc.1
+func containsVersion(content, version string) bool {
+	re := regexp.MustCompile(`(^|[^0-9.])` + regexp.QuoteMeta(version) + `($|[^0-9A-Za-z.+-])`)
+	return re.MatchString(content)
+}
+
 // VerifyTagPushedToRemote checks if a tag has been pushed to remote
 func VerifyTagPushedToRemote(repoPath, remoteName, tagName string) (bool, error) {
 	repo, err := gogit.PlainOpen(repoPath)
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -623,3 +624,145 @@ func TestVerifyTagPushedToRemote_NoRemote(t *testing.T) {
 	assert.False(t, pushed)
 	assert.Contains(t, err.Error(), "failed to get remote")
 }
+
+// TestVerifyTagMatchesRelease tests matching tags against the manifest versions at their commit
+func TestVerifyTagMatchesRelease(t *testing.T) {
+	tempDir := t.TempDir()
+	repo, err := gogit.PlainInit(tempDir, false)
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(tempDir+"/core", 0755))
+
+	commitFile(t, repo, tempDir, "core/version.go", "package core\n\nconst Version = \"1.0.0\"\n")
+	require.NoError(t, CreateLightweightTag(tempDir, "old"))
+	commitFile(t, repo, tempDir, "core/version.go", "package core\n\nconst Version = \"1.1.0\"\n")
+	require.NoError(t, CreateAnnotatedTag(tempDir, "core/v1.1.0", "Release core 1.1.0"))
+	require.NoError(t, CreateLightweightTag(tempDir, "light"))
+
+	release := map[string]string{"core/version.go": "1.1.0"}
+	tests := []struct {
+		name     string
+		tag      string
+		versions map[string]string
+		want     bool
+	}{
+		{name: "annotated tag at the release", tag: "core/v1.1.0", versions: release, want: true},
+		{name: "lightweight tag at the release", tag: "light", versions: release, want: true},
+		{name: "tag at an older commit", tag: "old", versions: release},
+		{name: "manifest missing at the tag", tag: "core/v1.1.0", versions: map[string]string{"api/package.json": "1.1.0"}},
+		{name: "missing tag", tag: "core/v9.9.9", versions: release},
+		{name: "no manifests to compare", tag: "old", versions: map[string]string{}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches, err := VerifyTagMatchesRelease(tempDir, tt.tag, tt.versions)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, matches)
+		})
+	}
+}
+
+// TestContainsVersion tests that versions only match whole
+func TestContainsVersion(t *testing.T) {
+	assert.True(t, containsVersion(`const Version = "1.2.0"`, "1.2.0"))
+	assert.True(t, containsVersion("version = 1.2.0\n", "1.2.0"))
+	assert.True(t, containsVersion(`"version": "1.2.0-rc.1"`, "1.2.0-rc.1"))
+	assert.False(t, containsVersion(`const Version = "11.2.0"`, "1.2.0"))
+	assert.False(t, containsVersion(`const Version = "1.2.0.1"`, "1.2.0"))
+	assert.False(t, containsVersion(`const Version = "1.2.0-rc.1"`, "1.2.0"))
+	assert.False(t, containsVersion(`const Version = "1.2.01"`, "1.2.0"))
+}
+
+func TestTagCommitHash(t *testing.T) {
+	tempDir := t.TempDir()
+	repo, err := gogit.PlainInit(tempDir, false)
+	require.NoError(t, err)
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(tempDir+"/test.txt", []byte("test"), 0644))
+	_, err = worktree.Add("test.txt")
+	require.NoError(t, err)
+	head, err := worktree.Commit("Initial commit", &gogit.CommitOptions{
+		Author: &object.Signature{Name: "Test User", Email: "test@example.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, CreateAnnotatedTag(tempDir, "v1.0.0", "Test release"))
+	require.NoError(t, CreateLightweightTag(tempDir, "v1.0.1"))
+
+	for _, tagName := range []string{"v1.0.0", "v1.0.1"} {
+		hash, err := TagCommitHash(tempDir, tagName)
+		require.NoError(t, err)
+		assert.Equal(t, head.String(), hash, tagName)
+	}
+
+	hash, err := TagCommitHash(tempDir, "v2.0.0")
+	require.NoError(t, err)
+	assert.Empty(t, hash)
+}
+
+func TestListTags(t *testing.T) {
+	tempDir := t.TempDir()
+	repo, err := gogit.PlainInit(tempDir, false)
+	require.NoError(t, err)
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(tempDir+"/test.txt", []byte("test"), 0644))
+	_, err = worktree.Add("test.txt")
+	require.NoError(t, err)
+	_, err = worktree.Commit("Initial commit", &gogit.CommitOptions{
+		Author: &object.Signature{Name: "Test User", Email: "test@example.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+
+	tags, err := ListTags(tempDir)
+	require.NoError(t, err)
+	assert.Empty(t, tags)
+
+	require.NoError(t, CreateAnnotatedTag(tempDir, "v1.0.0", "Test release"))
+	require.NoError(t, CreateLightweightTag(tempDir, "core/v1.0.1"))
+	tags, err = ListTags(tempDir)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"v1.0.0", "core/v1.0.1"}, tags)
+}
+
+func TestCreateTagAt(t *testing.T) {
+	tempDir := t.TempDir()
+	repo, err := gogit.PlainInit(tempDir, false)
+	require.NoError(t, err)
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	commit := func(content string) plumbing.Hash {
+		t.Helper()
+		require.NoError(t, os.WriteFile(tempDir+"/test.txt", []byte(content), 0644))
+		_, err := worktree.Add("test.txt")
+		require.NoError(t, err)
+		hash, err := worktree.Commit("Update "+content, &gogit.CommitOptions{
+			Author: &object.Signature{Name: "Test User", Email: "test@example.com", When: time.Now()},
+		})
+		require.NoError(t, err)
+		return hash
+	}
+
+	tagged := commit("beta")
+	require.NoError(t, CreateAnnotatedTag(tempDir, "v1.3.0-beta.2", "Beta"))
+	commit("later")
+
+	require.NoError(t, CreateTagAt(tempDir, "v1.3.0", "v1.3.0-beta.2", "Release v1.3.0"))
+	hash, err := TagCommitHash(tempDir, "v1.3.0")
+	require.NoError(t, err)
+	assert.Equal(t, tagged.String(), hash, "the new tag marks the source tag's commit, not HEAD")
+	ref, err := repo.Tag("v1.3.0")
+	require.NoError(t, err)
+	tagObj, err := repo.TagObject(ref.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, "Release v1.3.0\n", tagObj.Message)
+
+	require.NoError(t, CreateTagAt(tempDir, "latest", "v1.3.0", ""))
+	ref, err = repo.Tag("latest")
+	require.NoError(t, err)
+	assert.Equal(t, tagged, ref.Hash(), "without a message the tag is lightweight")
+
+	assert.ErrorContains(t, CreateTagAt(tempDir, "v1.3.0", "v1.3.0-beta.2", ""), "tag already exists")
+	assert.ErrorContains(t, CreateTagAt(tempDir, "v2.0.0", "v9.9.9", ""), "failed to find tag v9.9.9")
+}
@@ -0,0 +1,62 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/NatoNathan/shipyard/internal/upgrade"
+	"github.com/NatoNathan/shipyard/pkg/semver"
+)
+
+// ReleaseAPI is the part of the GitHub releases API that publishing from
+// the version command uses
+type ReleaseAPI interface {
+	ReleaseClient
+	// GetReleaseByTag returns the release for a tag, or nil when it has none
+	GetReleaseByTag(ctx context.Context, owner, repo, tag string) (*upgrade.ReleaseInfo, error)
+	UpdateRelease(ctx context.Context, owner, repo string, id int64, release *upgrade.CreateReleaseRequest) (*upgrade.ReleaseInfo, error)
+}
+
+// NewReleaseRequest builds the release of a package version, titled by the
+// first line of its notes
+func NewReleaseRequest(packageName string, version semver.Version, tagName, releaseNotes string, draft, prerelease bool) *upgrade.CreateReleaseRequest {
+	return &upgrade.CreateReleaseRequest{
+		TagName:    tagName,
+		Name:       extractTitleFromNotes(releaseNotes, packageName, version),
+		Body:       releaseNotes,
+		Draft:      draft,
+		Prerelease: prerelease,
+	}
+}
+
+// UpsertRelease creates the release for release.TagName, or updates the
+// release the tag already has so publishing can be repeated. created reports
+// which happened.
+func UpsertRelease(ctx context.Context, api ReleaseAPI, owner, repo string, release *upgrade.CreateReleaseRequest) (info *upgrade.ReleaseInfo, created bool, err error) {
+	existing, err := api.GetReleaseByTag(ctx, owner, repo, release.TagName)
+	if err != nil {
+		return nil, false, err
+	}
+	if existing != nil {
+		info, err = api.UpdateRelease(ctx, owner, repo, existing.ID, release)
+		return info, false, err
+	}
+	info, err = api.CreateRelease(ctx, owner, repo, release)
+	return info, true, err
+}
+
+// remotePattern matches the owner and repository of a github.com remote URL
+// in SSH (git@github.com:owner/repo.git, ssh://git@github.com/owner/repo) or
+// HTTPS (https://github.com/owner/repo.git) form
+var remotePattern = regexp.MustCompile(`^(?:[a-z+]+://)?(?:[^@/]+@)?github\.com[:/]([^/]+)/([^/]+?)(?:\.git)?/?$`)
+
+// ParseRemoteURL returns the owner and repository of a github.com remote URL
+func ParseRemoteURL(url string) (owner, repo string, err error) {
+	match := remotePattern.FindStringSubmatch(strings.TrimSpace(url))
+	if match == nil {
+		return "", "", fmt.Errorf("%s is not a github.com repository URL", url)
+	}
+	return match[1], match[2], nil
+}
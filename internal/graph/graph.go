@@ -16,7 +16,7 @@ type GraphNode struct {
 type GraphEdge struct {
 	From     string
 	To       string
-	Strategy string                // "fixed" or "linked"
+	Strategy string                // "linked", "fixed" or "patch"
 	BumpMap  map[string]string     // changeType -> changeType mapping
 }
 
@@ -1,8 +1,11 @@
 package graph
 
+import "sort"
+
 // FindStronglyConnectedComponents uses Tarjan's algorithm to identify
 // strongly connected components (SCCs) in the dependency graph.
 // Returns a slice of SCCs, where each SCC is a slice of package names.
+// Nodes are visited in name order, so SCCs and their IDs are stable across runs.
 // Also sets the SCC field on each node to its component ID.
 func FindStronglyConnectedComponents(g *DependencyGraph) [][]string {
 	if g == nil || len(g.nodes) == 0 {
@@ -20,8 +23,14 @@ func FindStronglyConnectedComponents(g *DependencyGraph) [][]string {
 		sccID:    1, // Start SCC IDs at 1 (0 means not in cycle)
 	}
 
-	// Run algorithm for each unvisited node
+	names := make([]string, 0, len(g.nodes))
 	for name := range g.nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Run algorithm for each unvisited node
+	for _, name := range names {
 		if _, visited := state.indices[name]; !visited {
 			state.strongConnect(name)
 		}
@@ -307,4 +307,25 @@ func TestFindStronglyConnectedComponents(t *testing.T) {
 		node, _ := g.GetNode("solo")
 		assert.NotEqual(t, 0, node.SCC)
 	})
+
+	t.Run("stable order across runs", func(t *testing.T) {
+		// Two independent cycles: a <-> b and x <-> y
+		cfg := &config.Config{
+			Packages: []config.Package{
+				{Name: "y", Path: "./y", Dependencies: []config.Dependency{{Package: "x", Strategy: "linked"}}},
+				{Name: "x", Path: "./x", Dependencies: []config.Dependency{{Package: "y", Strategy: "linked"}}},
+				{Name: "b", Path: "./b", Dependencies: []config.Dependency{{Package: "a", Strategy: "linked"}}},
+				{Name: "a", Path: "./a", Dependencies: []config.Dependency{{Package: "b", Strategy: "linked"}}},
+			},
+		}
+
+		g, err := BuildGraph(cfg)
+		require.NoError(t, err)
+
+		want := FindStronglyConnectedComponents(g)
+		assert.Equal(t, [][]string{{"b", "a"}, {"y", "x"}}, want)
+		for i := 0; i < 20; i++ {
+			assert.Equal(t, want, FindStronglyConnectedComponents(g))
+		}
+	})
 }
@@ -2,10 +2,13 @@ package graph
 
 import (
 	"fmt"
+	"sort"
 )
 
 // TopologicalSort performs a topological sort on a compressed graph using Kahn's algorithm.
 // Returns nodes in dependency order (dependencies before dependents).
+// Nodes that are ready at the same time are ordered by their first member name, so the
+// result is deterministic.
 // The compressed graph must be a DAG (cycles should be compressed first).
 // Returns an error if a cycle is detected (should not happen with properly compressed graph).
 func TopologicalSort(cg *CompressedGraph) ([]*CompressedNode, error) {
@@ -13,46 +16,52 @@ func TopologicalSort(cg *CompressedGraph) ([]*CompressedNode, error) {
 		return []*CompressedNode{}, nil
 	}
 
-	// Calculate in-degree for each node
-	inDegree := make(map[string]int)
-	for _, node := range cg.GetAllNodes() {
-		inDegree[node.Name] = 0
-	}
-
+	// Edges go FROM dependent TO dependency, so a node is ready once all of
+	// its outgoing edges have been satisfied
+	remaining := make(map[string]int)
+	dependents := make(map[string][]string)
 	for _, node := range cg.GetAllNodes() {
 		edges := cg.GetEdgesFrom(node.Name)
+		remaining[node.Name] = len(edges)
 		for _, edge := range edges {
-			inDegree[edge.To]++
+			dependents[edge.To] = append(dependents[edge.To], node.Name)
 		}
 	}
 
-	// Initialize queue with nodes that have no incoming edges
-	queue := []string{}
-	for nodeName, degree := range inDegree {
-		if degree == 0 {
-			queue = append(queue, nodeName)
+	// Initialize ready list with nodes that have no dependencies
+	ready := []string{}
+	for nodeName, count := range remaining {
+		if count == 0 {
+			ready = append(ready, nodeName)
 		}
 	}
+	byName := func(names []string) {
+		sort.Slice(names, func(i, j int) bool {
+			return cg.nodes[names[i]].Members[0] < cg.nodes[names[j]].Members[0]
+		})
+	}
+	byName(ready)
 
 	// Process nodes in topological order
 	sorted := []*CompressedNode{}
-	for len(queue) > 0 {
-		// Pop from queue
-		current := queue[0]
-		queue = queue[1:]
+	for len(ready) > 0 {
+		current := ready[0]
+		ready = ready[1:]
 
-		// Add to result
 		node, _ := cg.GetNode(current)
 		sorted = append(sorted, node)
 
-		// Reduce in-degree of neighbors
-		edges := cg.GetEdgesFrom(current)
-		for _, edge := range edges {
-			inDegree[edge.To]--
-			if inDegree[edge.To] == 0 {
-				queue = append(queue, edge.To)
+		released := false
+		for _, dependent := range dependents[current] {
+			remaining[dependent]--
+			if remaining[dependent] == 0 {
+				ready = append(ready, dependent)
+				released = true
 			}
 		}
+		if released {
+			byName(ready)
+		}
 	}
 
 	// Check if all nodes were processed (if not, there's a cycle)
@@ -61,12 +70,23 @@ func TopologicalSort(cg *CompressedGraph) ([]*CompressedNode, error) {
 			len(sorted), cg.GetNodeCount())
 	}
 
-	// Reverse the result to get dependencies before dependents
-	// (Our edges go FROM dependent TO dependency, so Kahn's gives us dependents first)
-	reversed := make([]*CompressedNode, len(sorted))
-	for i, node := range sorted {
-		reversed[len(sorted)-1-i] = node
+	return sorted, nil
+}
+
+// ApplyOrder returns package names grouped in the order their releases should be
+// applied: dependencies before dependents. Packages in a dependency cycle share a
+// group, sorted by name.
+func ApplyOrder(g *DependencyGraph) ([][]string, error) {
+	FindStronglyConnectedComponents(g)
+
+	sorted, err := TopologicalSort(CompressGraph(g))
+	if err != nil {
+		return nil, err
 	}
 
-	return reversed, nil
+	order := make([][]string, len(sorted))
+	for i, node := range sorted {
+		order[i] = node.Members
+	}
+	return order, nil
 }
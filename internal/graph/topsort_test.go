@@ -339,3 +339,52 @@ func TestTopologicalSort(t *testing.T) {
 		assert.Less(t, positions["e"], positions[cycle2Name])
 	})
 }
+
+func TestApplyOrder(t *testing.T) {
+	t.Run("dependencies before dependents regardless of config order", func(t *testing.T) {
+		cfg := &config.Config{
+			Packages: []config.Package{
+				{Name: "web", Path: "./web", Ecosystem: config.EcosystemGo,
+					Dependencies: []config.Dependency{{Package: "api", Strategy: "linked"}},
+				},
+				{Name: "api", Path: "./api", Ecosystem: config.EcosystemGo,
+					Dependencies: []config.Dependency{{Package: "core", Strategy: "linked"}},
+				},
+				{Name: "docs", Path: "./docs", Ecosystem: config.EcosystemGo},
+				{Name: "core", Path: "./core", Ecosystem: config.EcosystemGo},
+			},
+		}
+
+		for i := 0; i < 10; i++ {
+			g, err := BuildGraph(cfg)
+			require.NoError(t, err)
+
+			order, err := ApplyOrder(g)
+			require.NoError(t, err)
+			assert.Equal(t, [][]string{{"core"}, {"api"}, {"docs"}, {"web"}}, order)
+		}
+	})
+
+	t.Run("cycles are grouped", func(t *testing.T) {
+		cfg := &config.Config{
+			Packages: []config.Package{
+				{Name: "app", Path: "./app", Ecosystem: config.EcosystemGo,
+					Dependencies: []config.Dependency{{Package: "b", Strategy: "linked"}},
+				},
+				{Name: "a", Path: "./a", Ecosystem: config.EcosystemGo,
+					Dependencies: []config.Dependency{{Package: "b", Strategy: "linked"}},
+				},
+				{Name: "b", Path: "./b", Ecosystem: config.EcosystemGo,
+					Dependencies: []config.Dependency{{Package: "a", Strategy: "linked"}},
+				},
+			},
+		}
+
+		g, err := BuildGraph(cfg)
+		require.NoError(t, err)
+
+		order, err := ApplyOrder(g)
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"a", "b"}, {"app"}}, order)
+	})
+}
@@ -4,31 +4,122 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
 
 	"github.com/NatoNathan/shipyard/internal/fileutil"
-
-	"github.com/gofrs/flock"
 )
 
 // AppendToHistory appends history entries to the history file with file locking
 // Returns error if file doesn't exist, contains invalid JSON, or write fails
 func AppendToHistory(historyPath string, entries []Entry) error {
+	return File{Path: historyPath}.Append(entries)
+}
+
+// Append appends entries to the history file under its lock
+func (f File) Append(entries []Entry) error {
 	// Early return for empty list
 	if len(entries) == 0 {
 		return nil
 	}
 
-	// Create file lock
-	fileLock := flock.New(historyPath + ".lock")
+	return f.update(func(history []Entry) ([]Entry, error) {
+		return append(history, entries...), nil
+	})
+}
+
+// ImportEntries records releases that predate the history, such as those
+// read from an existing changelog, ahead of the entries already recorded.
+// Entries for a package version history already holds are left out; the
+// entries written are returned.
+func ImportEntries(historyPath string, entries []Entry) ([]Entry, error) {
+	return File{Path: historyPath}.Import(entries)
+}
 
-	// Acquire exclusive lock
-	if err := fileLock.Lock(); err != nil {
-		return fmt.Errorf("failed to acquire lock: %w", err)
+// Import is ImportEntries for a history file with its own lock timeout
+func (f File) Import(entries []Entry) ([]Entry, error) {
+	var imported []Entry
+	err := f.update(func(history []Entry) ([]Entry, error) {
+		recorded := make(map[string]bool, len(history))
+		for _, e := range history {
+			recorded[e.Package+"@"+NormalizeVersion(e.Version)] = true
+		}
+		for _, e := range entries {
+			key := e.Package + "@" + NormalizeVersion(e.Version)
+			if !recorded[key] {
+				recorded[key] = true
+				imported = append(imported, e)
+			}
+		}
+		return append(slices.Clone(imported), history...), nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	defer func() { _ = fileLock.Unlock() }()
+	return imported, nil
+}
+
+// EnsureHistory creates an empty history file when none exists yet, e.g.
+// before the first release on a channel
+func EnsureHistory(historyPath string) error {
+	if _, err := os.Stat(historyPath); !os.IsNotExist(err) {
+		return err
+	}
+	return fileutil.AtomicWrite(historyPath, []byte("[]"), 0644)
+}
+
+// RecordArtifacts attaches published artifacts to the entry for a package version
+func RecordArtifacts(historyPath, packageName, version string, artifacts []Artifact) error {
+	return File{Path: historyPath}.RecordArtifacts(packageName, version, artifacts)
+}
+
+// RecordArtifacts attaches published artifacts to the entry for a package
+// version, under the history file's lock
+func (f File) RecordArtifacts(packageName, version string, artifacts []Artifact) error {
+	if len(artifacts) == 0 {
+		return nil
+	}
+
+	return f.update(func(history []Entry) ([]Entry, error) {
+		for i := len(history) - 1; i >= 0; i-- {
+			if history[i].Package == packageName && history[i].Version == version {
+				history[i].Artifacts = append(history[i].Artifacts, artifacts...)
+				return history, nil
+			}
+		}
+		return nil, fmt.Errorf("no history entry for %s %s", packageName, version)
+	})
+}
+
+// AddNote appends a note to the entry for a package version. Existing notes
+// are never rewritten, so each note is an amendment to the entry.
+func AddNote(historyPath, packageName, version string, note Note) error {
+	return File{Path: historyPath}.AddNote(packageName, version, note)
+}
+
+// AddNote appends a note to the entry for a package version, under the
+// history file's lock
+func (f File) AddNote(packageName, version string, note Note) error {
+	return f.update(func(history []Entry) ([]Entry, error) {
+		for i := len(history) - 1; i >= 0; i-- {
+			if history[i].Package == packageName && NormalizeVersion(history[i].Version) == NormalizeVersion(version) {
+				history[i].Notes = append(history[i].Notes, note)
+				return history, nil
+			}
+		}
+		return nil, fmt.Errorf("no history entry for %s %s", packageName, version)
+	})
+}
+
+// update rewrites the history file under an exclusive lock
+func (f File) update(update func([]Entry) ([]Entry, error)) error {
+	unlock, err := f.lock(true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
 	// Read existing history
-	data, err := fileutil.ReadFile(historyPath)
+	data, err := fileutil.ReadFile(f.Path)
 	if err != nil {
 		return fmt.Errorf("failed to read history file: %w", err)
 	}
@@ -39,8 +130,10 @@ func AppendToHistory(historyPath string, entries []Entry) error {
 		return fmt.Errorf("failed to unmarshal history: %w", err)
 	}
 
-	// Append new entries
-	history = append(history, entries...)
+	history, err = update(history)
+	if err != nil {
+		return err
+	}
 
 	// Marshal updated history
 	updatedData, err := json.MarshalIndent(history, "", "  ")
@@ -48,17 +141,9 @@ func AppendToHistory(historyPath string, entries []Entry) error {
 		return fmt.Errorf("failed to marshal history: %w", err)
 	}
 
-	// Write atomically: write to temp file, then rename
-	tempPath := historyPath + ".tmp"
-	if err := fileutil.WriteFile(tempPath, updatedData, 0644); err != nil {
-		return fmt.Errorf("failed to write temp file: %w", err)
-	}
-
-	// Atomic rename
-	if err := os.Rename(tempPath, historyPath); err != nil {
-		// Clean up temp file on failure
-		_ = os.Remove(tempPath)
-		return fmt.Errorf("failed to rename temp file: %w", err)
+	// Write to a synced temp file, then rename, so a crash never leaves partial JSON
+	if err := fileutil.AtomicWrite(f.Path, updatedData, 0644); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
 	}
 
 	return nil
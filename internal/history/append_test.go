@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -22,10 +23,11 @@ func TestAppendToHistory_WithEntries(t *testing.T) {
 	// Test: Append Entry objects with version context
 	entries := []Entry{
 		{
-			Version:   "1.0.0",
-			Package:   "core",
-			Tag:       "core-v1.0.0",
-			Timestamp: time.Now(),
+			Version:       "1.0.0",
+			Package:       "core",
+			Tag:           "core-v1.0.0",
+			BaseCommitSHA: "0123456789abcdef0123456789abcdef01234567",
+			Timestamp:     time.Now(),
 			Consignments: []Consignment{
 				{ID: "c1", Summary: "Fix bug", ChangeType: "patch"},
 				{ID: "c2", Summary: "Add test", ChangeType: "patch"},
@@ -56,12 +58,18 @@ func TestAppendToHistory_WithEntries(t *testing.T) {
 	assert.Equal(t, "1.0.0", readEntries[0].Version)
 	assert.Equal(t, "core", readEntries[0].Package)
 	assert.Equal(t, "core-v1.0.0", readEntries[0].Tag)
+	assert.Equal(t, "0123456789abcdef0123456789abcdef01234567", readEntries[0].BaseCommitSHA)
 	assert.Len(t, readEntries[0].Consignments, 2)
 
 	// Verify second entry
 	assert.Equal(t, "1.0.0", readEntries[1].Version)
 	assert.Equal(t, "api", readEntries[1].Package)
 	assert.Equal(t, "api-v1.0.0", readEntries[1].Tag)
+	assert.Empty(t, readEntries[1].BaseCommitSHA)
+
+	data, err := os.ReadFile(historyPath)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(data), `"baseCommitSha"`), "an unknown commit is omitted")
 	assert.Len(t, readEntries[1].Consignments, 1)
 }
 
@@ -176,6 +184,23 @@ func TestAppendToHistory_NonexistentFile(t *testing.T) {
 	assert.Contains(t, err.Error(), "failed to read history")
 }
 
+// TestEnsureHistory tests that a missing history file is created empty and an
+// existing one is left alone
+func TestEnsureHistory(t *testing.T) {
+	historyPath := filepath.Join(t.TempDir(), "history.beta.json")
+
+	require.NoError(t, EnsureHistory(historyPath))
+	data, err := os.ReadFile(historyPath)
+	require.NoError(t, err)
+	assert.JSONEq(t, "[]", string(data))
+
+	require.NoError(t, AppendToHistory(historyPath, []Entry{{Version: "1.0.0-beta.1", Package: "core", Timestamp: time.Now()}}))
+	require.NoError(t, EnsureHistory(historyPath))
+	entries, err := ReadHistory(historyPath)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
 // TestAppendToHistory_InvalidJSON tests error when history file contains invalid JSON
 func TestAppendToHistory_InvalidJSON(t *testing.T) {
 	// Setup: Create file with invalid JSON
@@ -307,3 +332,81 @@ func TestAppendToHistory_PreservesOrder(t *testing.T) {
 	assert.Equal(t, "4", entries[3].Consignments[0].ID)
 	assert.Equal(t, "5", entries[4].Consignments[0].ID)
 }
+
+// TestRecordArtifacts tests attaching published artifacts to an existing entry
+func TestRecordArtifacts(t *testing.T) {
+	tempDir := t.TempDir()
+	historyPath := filepath.Join(tempDir, "history.json")
+	require.NoError(t, os.WriteFile(historyPath, []byte("[]"), 0644))
+
+	require.NoError(t, AppendToHistory(historyPath, []Entry{
+		{Version: "1.0.0", Package: "chart", Timestamp: time.Now()},
+		{Version: "1.1.0", Package: "chart", Timestamp: time.Now()},
+	}))
+
+	artifact := Artifact{Type: "helm", Reference: "ghcr.io/acme/charts/chart:1.1.0", Digest: "sha256:abc"}
+	require.NoError(t, RecordArtifacts(historyPath, "chart", "1.1.0", []Artifact{artifact}))
+
+	entries, err := ReadHistory(historyPath)
+	require.NoError(t, err)
+	assert.Empty(t, entries[0].Artifacts)
+	assert.Equal(t, []Artifact{artifact}, entries[1].Artifacts)
+
+	err = RecordArtifacts(historyPath, "chart", "9.9.9", []Artifact{artifact})
+	assert.Error(t, err, "unknown versions should be reported")
+}
+
+func TestAddNote(t *testing.T) {
+	tempDir := t.TempDir()
+	historyPath := filepath.Join(tempDir, "history.json")
+	require.NoError(t, os.WriteFile(historyPath, []byte("[]"), 0644))
+
+	require.NoError(t, AppendToHistory(historyPath, []Entry{
+		{Version: "1.0.0", Package: "core", Timestamp: time.Now()},
+		{Version: "1.0.0", Package: "api", Timestamp: time.Now()},
+	}))
+
+	first := Note{Text: "Regression fixed in 1.0.1", Author: "Jane", Timestamp: time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)}
+	second := Note{Text: "Only affects ARM", Timestamp: time.Date(2026, 3, 3, 10, 0, 0, 0, time.UTC)}
+	require.NoError(t, AddNote(historyPath, "core", "v1.0.0", first))
+	require.NoError(t, AddNote(historyPath, "core", "1.0.0", second))
+
+	entries, err := ReadHistory(historyPath)
+	require.NoError(t, err)
+	assert.Equal(t, []Note{first, second}, entries[0].Notes)
+	assert.Empty(t, entries[1].Notes, "notes are scoped to the package")
+
+	err = AddNote(historyPath, "core", "9.9.9", first)
+	assert.Error(t, err, "unknown versions should be reported")
+}
+
+func TestImportEntries(t *testing.T) {
+	tempDir := t.TempDir()
+	historyPath := filepath.Join(tempDir, "history.json")
+	require.NoError(t, os.WriteFile(historyPath, []byte("[]"), 0644))
+	require.NoError(t, AppendToHistory(historyPath, []Entry{
+		{Version: "1.2.0", Package: "core", Timestamp: time.Now()},
+	}))
+
+	imported, err := ImportEntries(historyPath, []Entry{
+		{Version: "1.0.0", Package: "core", Imported: true, Preformatted: "Initial release"},
+		{Version: "1.1.0", Package: "core", Imported: true},
+		{Version: "v1.2.0", Package: "core", Imported: true},
+	})
+	require.NoError(t, err)
+	require.Len(t, imported, 2, "versions already in history are left out")
+
+	entries, err := ReadHistory(historyPath)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, "1.0.0", entries[0].Version)
+	assert.True(t, entries[0].Imported)
+	assert.Equal(t, "Initial release", entries[0].Preformatted)
+	assert.Equal(t, "1.1.0", entries[1].Version)
+	assert.Equal(t, "1.2.0", entries[2].Version)
+	assert.False(t, entries[2].Imported)
+
+	imported, err = ImportEntries(historyPath, []Entry{{Version: "1.0.0", Package: "core", Imported: true}})
+	require.NoError(t, err)
+	assert.Empty(t, imported, "importing again adds nothing")
+}
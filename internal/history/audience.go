@@ -0,0 +1,69 @@
+package history
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Audiences split release notes into what users notice and what only
+// maintainers care about
+const (
+	AudienceUser     = "user"     // user-facing changes; the default
+	AudienceInternal = "internal" // platform, tooling and maintenance changes
+)
+
+// AudienceMetadataKey is the consignment metadata key that overrides the
+// audience of a single change
+const AudienceMetadataKey = "audience"
+
+// AudienceGroups holds changes split by audience, in their original order
+type AudienceGroups[T any] struct {
+	User     []T
+	Internal []T
+}
+
+// ValidateAudience checks that audience is a known audience
+func ValidateAudience(audience string) error {
+	switch audience {
+	case AudienceUser, AudienceInternal:
+		return nil
+	}
+	return fmt.Errorf("invalid audience %q (expected %s or %s)", audience, AudienceUser, AudienceInternal)
+}
+
+// ResolveAudience returns the audience of a change: the audience metadata
+// key when it names a known audience, then the audience configured for the
+// change type in audiences, then user
+func ResolveAudience(changeType string, metadata map[string]interface{}, audiences map[string]string) string {
+	if value, ok := metadata[AudienceMetadataKey].(string); ok {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if ValidateAudience(value) == nil {
+			return value
+		}
+	}
+	if audience := audiences[changeType]; audience != "" {
+		return audience
+	}
+	return AudienceUser
+}
+
+// GroupByAudience splits items by the audience audienceOf resolves for each
+func GroupByAudience[T any](items []T, audienceOf func(T) string) AudienceGroups[T] {
+	var groups AudienceGroups[T]
+	for _, item := range items {
+		if audienceOf(item) == AudienceInternal {
+			groups.Internal = append(groups.Internal, item)
+		} else {
+			groups.User = append(groups.User, item)
+		}
+	}
+	return groups
+}
+
+// ChangesByAudience splits the entry's consignments by audience using the
+// change-type audiences configured for rendering
+func (e Entry) ChangesByAudience() AudienceGroups[Consignment] {
+	return GroupByAudience(e.Consignments, func(c Consignment) string {
+		return ResolveAudience(c.ChangeType, c.Metadata, e.Audiences)
+	})
+}
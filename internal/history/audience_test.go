@@ -0,0 +1,53 @@
+package history
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveAudience(t *testing.T) {
+	audiences := map[string]string{"patch": AudienceInternal}
+
+	tests := []struct {
+		name       string
+		changeType string
+		metadata   map[string]interface{}
+		expected   string
+	}{
+		{"default is user", "minor", nil, AudienceUser},
+		{"change type audience", "patch", nil, AudienceInternal},
+		{"metadata overrides change type", "patch", map[string]interface{}{"audience": "user"}, AudienceUser},
+		{"metadata overrides default", "major", map[string]interface{}{"audience": " Internal "}, AudienceInternal},
+		{"unknown metadata value is ignored", "patch", map[string]interface{}{"audience": "ops"}, AudienceInternal},
+		{"non-string metadata is ignored", "minor", map[string]interface{}{"audience": 1}, AudienceUser},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ResolveAudience(tt.changeType, tt.metadata, audiences))
+		})
+	}
+}
+
+func TestEntry_ChangesByAudience(t *testing.T) {
+	entry := Entry{
+		Consignments: []Consignment{
+			{ID: "c1", ChangeType: "minor"},
+			{ID: "c2", ChangeType: "patch"},
+			{ID: "c3", ChangeType: "patch", Metadata: map[string]interface{}{"audience": "user"}},
+		},
+		Audiences: map[string]string{"patch": AudienceInternal},
+	}
+
+	groups := entry.ChangesByAudience()
+	assert.Equal(t, []Consignment{entry.Consignments[0], entry.Consignments[2]}, groups.User)
+	assert.Equal(t, []Consignment{entry.Consignments[1]}, groups.Internal)
+
+	assert.Len(t, Entry{Consignments: entry.Consignments}.ChangesByAudience().User, 3, "everything is user-facing without configuration")
+}
+
+func TestValidateAudience(t *testing.T) {
+	assert.NoError(t, ValidateAudience("user"))
+	assert.NoError(t, ValidateAudience("internal"))
+	assert.Error(t, ValidateAudience("ops"))
+}
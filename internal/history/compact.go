@@ -0,0 +1,304 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/fileutil"
+	"github.com/NatoNathan/shipyard/pkg/semver"
+)
+
+// CompactOptions selects the entries that stay in the main history file. An
+// entry stays when it is one of the Keep newest or was released at or after
+// Since. The newest and the highest version of every package always stay, so
+// latest-version lookups never need the archives.
+type CompactOptions struct {
+	Keep   int       // Number of newest entries to keep; 0 means no count limit
+	Since  time.Time // Keep entries released at or after this time; zero means no date limit
+	DryRun bool      // Report what would move without writing anything
+}
+
+// CompactResult reports what a compaction moved
+type CompactResult struct {
+	Kept     int      `json:"kept"`     // Entries left in the main history file
+	Archived int      `json:"archived"` // Entries moved to yearly archives
+	Archives []string `json:"archives"` // Archive files written (or that would be written)
+}
+
+// ArchiveDir returns the directory holding the yearly archives of a history
+// file: the history path without its extension, e.g. .shipyard/history for
+// .shipyard/history.json
+func ArchiveDir(historyPath string) string {
+	ext := filepath.Ext(historyPath)
+	if ext == "" {
+		return historyPath + "-archive"
+	}
+	return strings.TrimSuffix(historyPath, ext)
+}
+
+// ArchivePath returns the archive file for entries released in year (UTC)
+func ArchivePath(historyPath string, year int) string {
+	return filepath.Join(ArchiveDir(historyPath), strconv.Itoa(year)+".json")
+}
+
+// ListArchives returns the yearly archive files of a history file, oldest first
+func ListArchives(historyPath string) ([]string, error) {
+	dirEntries, err := os.ReadDir(ArchiveDir(historyPath))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read history archives: %w", err)
+	}
+
+	var archives []string
+	for _, dirEntry := range dirEntries {
+		name := dirEntry.Name()
+		year := strings.TrimSuffix(name, ".json")
+		if dirEntry.IsDir() || year == name {
+			continue
+		}
+		if _, err := strconv.Atoi(year); err != nil {
+			continue
+		}
+		archives = append(archives, filepath.Join(ArchiveDir(historyPath), name))
+	}
+	sort.Strings(archives)
+	return archives, nil
+}
+
+// Compact moves history entries that fall outside the window in opts into
+// yearly archive files next to the history file. Archives are written before
+// the main file is rewritten, so an interrupted compaction can leave an entry
+// in both places but never loses one; readers merging archives drop the
+// duplicate.
+func Compact(historyPath string, opts CompactOptions) (*CompactResult, error) {
+	return File{Path: historyPath}.Compact(opts)
+}
+
+// Compact is Compact for a history file with its own lock timeout
+func (f File) Compact(opts CompactOptions) (*CompactResult, error) {
+	if opts.Keep < 0 {
+		return nil, fmt.Errorf("keep must not be negative, got %d", opts.Keep)
+	}
+	if opts.Keep == 0 && opts.Since.IsZero() {
+		return nil, fmt.Errorf("compaction needs a number of entries to keep or a since date")
+	}
+
+	unlock, err := f.lock(true)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	entries, err := readEntries(f.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history file: %w", err)
+	}
+
+	keep := compactionWindow(entries, opts)
+	var kept []Entry
+	byYear := make(map[int][]Entry)
+	for i, entry := range entries {
+		if keep[i] {
+			kept = append(kept, entry)
+			continue
+		}
+		year := entry.Timestamp.UTC().Year()
+		byYear[year] = append(byYear[year], entry)
+	}
+
+	result := &CompactResult{Kept: len(kept), Archived: len(entries) - len(kept), Archives: []string{}}
+	years := make([]int, 0, len(byYear))
+	for year := range byYear {
+		years = append(years, year)
+	}
+	sort.Ints(years)
+	for _, year := range years {
+		result.Archives = append(result.Archives, ArchivePath(f.Path, year))
+	}
+
+	if opts.DryRun || result.Archived == 0 {
+		return result, nil
+	}
+
+	if err := fileutil.EnsureDir(ArchiveDir(f.Path)); err != nil {
+		return nil, err
+	}
+	for _, year := range years {
+		if err := appendToArchive(ArchivePath(f.Path, year), byYear[year]); err != nil {
+			return nil, err
+		}
+	}
+
+	if kept == nil {
+		kept = []Entry{}
+	}
+	if err := writeEntries(f.Path, kept); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// compactionWindow marks the entries that stay in the main history file
+func compactionWindow(entries []Entry, opts CompactOptions) []bool {
+	keep := make([]bool, len(entries))
+
+	order := make([]int, len(entries))
+	for i := range order {
+		order[i] = i
+	}
+	// Newest first; later entries in the file win ties
+	sort.SliceStable(order, func(a, b int) bool {
+		ta, tb := entries[order[a]].Timestamp, entries[order[b]].Timestamp
+		if ta.Equal(tb) {
+			return order[a] > order[b]
+		}
+		return ta.After(tb)
+	})
+	for rank, i := range order {
+		if rank < opts.Keep {
+			keep[i] = true
+		}
+		if !opts.Since.IsZero() && !entries[i].Timestamp.Before(opts.Since) {
+			keep[i] = true
+		}
+	}
+
+	newest := make(map[string]int)
+	highest := make(map[string]int)
+	highestVersion := make(map[string]semver.Version)
+	for _, i := range order {
+		entry := entries[i]
+		if _, ok := newest[entry.Package]; !ok {
+			newest[entry.Package] = i
+		}
+		v, err := semver.Parse(NormalizeVersion(entry.Version))
+		if err != nil {
+			continue
+		}
+		if current, ok := highestVersion[entry.Package]; !ok || v.Compare(current) > 0 {
+			highestVersion[entry.Package] = v
+			highest[entry.Package] = i
+		}
+	}
+	for _, i := range newest {
+		keep[i] = true
+	}
+	for _, i := range highest {
+		keep[i] = true
+	}
+
+	return keep
+}
+
+// appendToArchive merges entries into an archive file, dropping duplicates
+// and keeping the file in release order
+func appendToArchive(path string, entries []Entry) error {
+	var existing []Entry
+	if fileutil.PathExists(path) {
+		var err error
+		existing, err = readEntries(path)
+		if err != nil {
+			return fmt.Errorf("failed to read history archive %s: %w", path, err)
+		}
+	}
+
+	merged := mergeEntries(existing, entries)
+	return writeEntries(path, merged)
+}
+
+// writeEntries writes entries to a history or archive file atomically
+func writeEntries(path string, entries []Entry) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal history: %w", err)
+	}
+	if err := fileutil.AtomicWrite(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// ReadHistoryWithArchives reads the history file together with its yearly
+// archives, oldest first. Use it where every past release matters, such as
+// regenerating a full changelog; ReadHistory is enough for latest-version
+// lookups because compaction always keeps each package's latest entries.
+func ReadHistoryWithArchives(path string) ([]Entry, error) {
+	return File{Path: path}.ReadWithArchives()
+}
+
+// ReadWithArchives reads the history file and its yearly archives under the
+// history file's shared lock
+func (f File) ReadWithArchives() ([]Entry, error) {
+	if _, err := os.Stat(f.Path); err != nil {
+		return nil, err
+	}
+
+	unlock, err := f.readLock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	archives, err := ListArchives(f.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var archived []Entry
+	for _, archive := range archives {
+		entries, err := readEntries(archive)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read history archive %s: %w", archive, err)
+		}
+		archived = append(archived, entries...)
+	}
+
+	entries, err := readEntries(f.Path)
+	if err != nil {
+		return nil, err
+	}
+	if len(archived) == 0 {
+		return entries, nil
+	}
+
+	return mergeEntries(archived, entries), nil
+}
+
+// mergeEntries combines two entry lists, dropping entries that appear in
+// both (same package, version and timestamp), and orders them by timestamp.
+// Entries from b replace their duplicates in a, since the main file may have
+// been annotated after an interrupted compaction.
+func mergeEntries(a, b []Entry) []Entry {
+	index := make(map[string]int)
+	merged := make([]Entry, 0, len(a)+len(b))
+	for _, list := range [][]Entry{a, b} {
+		for _, entry := range list {
+			key := entryKey(entry)
+			if i, ok := index[key]; ok {
+				merged[i] = entry
+				continue
+			}
+			index[key] = len(merged)
+			merged = append(merged, entry)
+		}
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Timestamp.Before(merged[j].Timestamp)
+	})
+	return merged
+}
+
+// entryKey identifies a release across the history file and its archives
+func entryKey(e Entry) string {
+	return e.Package + "\x00" + NormalizeVersion(e.Version) + "\x00" + e.Timestamp.UTC().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,167 @@
+package history
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestHistory(t *testing.T, entries []Entry) string {
+	t.Helper()
+	historyPath := filepath.Join(t.TempDir(), ".shipyard", "history.json")
+	require.NoError(t, os.MkdirAll(filepath.Dir(historyPath), 0755))
+	data, err := json.Marshal(entries)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(historyPath, data, 0644))
+	return historyPath
+}
+
+func compactTestEntries() []Entry {
+	at := func(year int, month time.Month) time.Time {
+		return time.Date(year, month, 1, 12, 0, 0, 0, time.UTC)
+	}
+	return []Entry{
+		{Package: "core", Version: "1.0.0", Timestamp: at(2023, time.February)},
+		{Package: "api", Version: "0.1.0", Timestamp: at(2023, time.June)},
+		{Package: "core", Version: "1.1.0", Timestamp: at(2024, time.March)},
+		{Package: "core", Version: "1.2.0", Timestamp: at(2025, time.January)},
+		{Package: "core", Version: "2.0.0", Timestamp: at(2025, time.May)},
+	}
+}
+
+func TestArchivePath(t *testing.T) {
+	assert.Equal(t, filepath.Join(".shipyard", "history", "2023.json"), ArchivePath(filepath.Join(".shipyard", "history.json"), 2023))
+	assert.Equal(t, "history-archive", ArchiveDir("history"))
+}
+
+func TestCompact_Keep(t *testing.T) {
+	historyPath := writeTestHistory(t, compactTestEntries())
+
+	result, err := Compact(historyPath, CompactOptions{Keep: 2})
+	require.NoError(t, err)
+
+	// api 0.1.0 is the only api entry, so it stays despite its age
+	assert.Equal(t, 3, result.Kept)
+	assert.Equal(t, 2, result.Archived)
+	assert.Equal(t, []string{ArchivePath(historyPath, 2023), ArchivePath(historyPath, 2024)}, result.Archives)
+
+	main, err := ReadHistory(historyPath)
+	require.NoError(t, err)
+	var versions []string
+	for _, entry := range main {
+		versions = append(versions, entry.Package+"@"+entry.Version)
+	}
+	assert.Equal(t, []string{"api@0.1.0", "core@1.2.0", "core@2.0.0"}, versions)
+
+	archived, err := readEntries(ArchivePath(historyPath, 2023))
+	require.NoError(t, err)
+	require.Len(t, archived, 1)
+	assert.Equal(t, "1.0.0", archived[0].Version)
+}
+
+func TestCompact_Since(t *testing.T) {
+	historyPath := writeTestHistory(t, compactTestEntries())
+
+	result, err := Compact(historyPath, CompactOptions{Since: time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)})
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Kept)
+	assert.Equal(t, 2, result.Archived)
+}
+
+func TestCompact_KeepsHighestVersion(t *testing.T) {
+	// A backport released after the latest major must not hide 2.0.0
+	entries := []Entry{
+		{Package: "core", Version: "2.0.0", Timestamp: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{Package: "core", Version: "1.9.1", Timestamp: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{Package: "core", Version: "1.9.2", Timestamp: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	historyPath := writeTestHistory(t, entries)
+
+	_, err := Compact(historyPath, CompactOptions{Keep: 1})
+	require.NoError(t, err)
+
+	main, err := ReadHistory(historyPath)
+	require.NoError(t, err)
+	require.Len(t, main, 2)
+	assert.Equal(t, "2.0.0", main[0].Version)
+	assert.Equal(t, "1.9.2", main[1].Version)
+}
+
+func TestCompact_DryRun(t *testing.T) {
+	historyPath := writeTestHistory(t, compactTestEntries())
+	before, err := os.ReadFile(historyPath)
+	require.NoError(t, err)
+
+	result, err := Compact(historyPath, CompactOptions{Keep: 1, DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Archived)
+
+	after, err := os.ReadFile(historyPath)
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+	assert.NoDirExists(t, ArchiveDir(historyPath))
+}
+
+func TestCompact_RequiresWindow(t *testing.T) {
+	historyPath := writeTestHistory(t, compactTestEntries())
+
+	_, err := Compact(historyPath, CompactOptions{})
+	assert.Error(t, err)
+	_, err = Compact(historyPath, CompactOptions{Keep: -1})
+	assert.Error(t, err)
+}
+
+func TestCompact_Repeated(t *testing.T) {
+	historyPath := writeTestHistory(t, compactTestEntries())
+
+	_, err := Compact(historyPath, CompactOptions{Keep: 4})
+	require.NoError(t, err)
+	_, err = Compact(historyPath, CompactOptions{Keep: 2})
+	require.NoError(t, err)
+	// Nothing left to move
+	result, err := Compact(historyPath, CompactOptions{Keep: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Archived)
+
+	all, err := ReadHistoryWithArchives(historyPath)
+	require.NoError(t, err)
+	assert.Len(t, all, len(compactTestEntries()))
+	for i := 1; i < len(all); i++ {
+		assert.False(t, all[i].Timestamp.Before(all[i-1].Timestamp), "entries out of order")
+	}
+}
+
+func TestReadHistoryWithArchives_DropsDuplicates(t *testing.T) {
+	entries := compactTestEntries()
+	historyPath := writeTestHistory(t, entries)
+
+	// Simulate a compaction interrupted after the archive was written
+	require.NoError(t, os.MkdirAll(ArchiveDir(historyPath), 0755))
+	require.NoError(t, writeEntries(ArchivePath(historyPath, 2023), entries[:2]))
+
+	all, err := ReadHistoryWithArchives(historyPath)
+	require.NoError(t, err)
+	assert.Len(t, all, len(entries))
+
+	main, err := ReadHistory(historyPath)
+	require.NoError(t, err)
+	assert.Len(t, main, len(entries))
+}
+
+func TestListArchives_IgnoresOtherFiles(t *testing.T) {
+	historyPath := writeTestHistory(t, compactTestEntries())
+	dir := ArchiveDir(historyPath)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "2022.json"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.json"), []byte("[]"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "2024.json"), []byte("[]"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "2023.json"), []byte("[]"), 0644))
+
+	archives, err := ListArchives(historyPath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "2023.json"), filepath.Join(dir, "2024.json")}, archives)
+}
@@ -1,15 +1,25 @@
 package history
 
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/NatoNathan/shipyard/pkg/semver"
+)
+
 // FilterByPackage filters history entries by package name
-// Returns all entries if packageName is empty
-func FilterByPackage(entries []Entry, packageName string) []Entry {
+// Returns all entries if packageName is empty. Entries recorded under one of
+// the package's aliases (former names) match too.
+func FilterByPackage(entries []Entry, packageName string, aliases ...string) []Entry {
 	if packageName == "" {
 		return entries
 	}
 
 	var filtered []Entry
 	for _, entry := range entries {
-		if entry.Package == packageName {
+		if entry.Package == packageName || slices.Contains(aliases, entry.Package) {
 			filtered = append(filtered, entry)
 		}
 	}
@@ -17,15 +27,77 @@ func FilterByPackage(entries []Entry, packageName string) []Entry {
 }
 
 // FilterByVersion filters history entries by version
-// Returns all entries if version is empty
+// Returns all entries if version is empty. A leading "v" on either side is
+// ignored, so "v1.2.0" matches an entry stored as "1.2.0" and vice versa.
 func FilterByVersion(entries []Entry, version string) []Entry {
 	if version == "" {
 		return entries
 	}
 
+	want := NormalizeVersion(version)
 	var filtered []Entry
 	for _, entry := range entries {
-		if entry.Version == version {
+		if NormalizeVersion(entry.Version) == want {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
+// NormalizeVersion returns the canonical bare form of a version string,
+// without surrounding whitespace or a leading "v"
+func NormalizeVersion(version string) string {
+	version = strings.TrimSpace(version)
+	if len(version) > 1 && (version[0] == 'v' || version[0] == 'V') && version[1] >= '0' && version[1] <= '9' {
+		return version[1:]
+	}
+	return version
+}
+
+// FilterByVersionRange returns the entries whose version lies between from
+// and to, both inclusive. An empty bound leaves that end of the range open.
+// Versions are compared with parse, which reads them in the package's
+// versioning scheme, so the entries should be one package's. Entries whose
+// version does not parse are left out.
+func FilterByVersionRange(entries []Entry, from, to string, parse func(string) (semver.Version, error)) ([]Entry, error) {
+	bound := func(name, value string) (*semver.Version, error) {
+		if value == "" {
+			return nil, nil
+		}
+		v, err := parse(NormalizeVersion(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s version %q: %w", name, value, err)
+		}
+		return &v, nil
+	}
+	lower, err := bound("from", from)
+	if err != nil {
+		return nil, err
+	}
+	upper, err := bound("to", to)
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []Entry
+	for _, entry := range entries {
+		v, err := parse(NormalizeVersion(entry.Version))
+		if err != nil {
+			continue
+		}
+		if (lower != nil && v.Compare(*lower) < 0) || (upper != nil && v.Compare(*upper) > 0) {
+			continue
+		}
+		filtered = append(filtered, entry)
+	}
+	return filtered, nil
+}
+
+// FilterSince returns the entries released at or after since
+func FilterSince(entries []Entry, since time.Time) []Entry {
+	var filtered []Entry
+	for _, entry := range entries {
+		if !entry.Timestamp.Before(since) {
 			filtered = append(filtered, entry)
 		}
 	}
@@ -40,13 +112,8 @@ func FilterConsignmentsByMetadata(entries []Entry, metadataKey, metadataValue st
 	filtered := make([]Entry, len(entries))
 	for i, entry := range entries {
 		// Copy entry metadata
-		filtered[i] = Entry{
-			Version:      entry.Version,
-			Package:      entry.Package,
-			Tag:          entry.Tag,
-			Timestamp:    entry.Timestamp,
-			Consignments: []Consignment{},
-		}
+		filtered[i] = entry
+		filtered[i].Consignments = []Consignment{}
 
 		// Filter consignments by metadata
 		for _, c := range entry.Consignments {
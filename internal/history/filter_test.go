@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/NatoNathan/shipyard/pkg/semver"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -66,6 +67,16 @@ func TestFilterByPackage(t *testing.T) {
 		// Verify: All entries returned
 		assert.Len(t, filtered, 3)
 	})
+
+	t.Run("includes entries recorded under an alias", func(t *testing.T) {
+		// Test: "platform" was formerly named "core"
+		filtered := FilterByPackage(entries, "platform", "core")
+
+		// Verify: The old name's entries are returned, unchanged
+		require.Len(t, filtered, 2)
+		assert.Equal(t, "1.1.0", filtered[0].Version)
+		assert.Equal(t, "core", filtered[0].Package)
+	})
 }
 
 // TestFilterByVersion tests filtering history entries by version
@@ -116,6 +127,39 @@ func TestFilterByVersion(t *testing.T) {
 		// Verify: All entries returned
 		assert.Len(t, filtered, 2)
 	})
+
+	t.Run("ignores a v prefix on either side", func(t *testing.T) {
+		filtered := FilterByVersion(entries, "v1.1.0")
+		require.Len(t, filtered, 1)
+		assert.Equal(t, "core", filtered[0].Package)
+
+		legacy := []Entry{{Version: "v3.0.0", Package: "web"}}
+		assert.Len(t, FilterByVersion(legacy, "3.0.0"), 1)
+		assert.Len(t, FilterByVersion(legacy, "V3.0.0"), 1)
+	})
+}
+
+func TestNormalizeVersion(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"1.2.0", "1.2.0"},
+		{"v1.2.0", "1.2.0"},
+		{"V1.2.0-rc.1", "1.2.0-rc.1"},
+		{" v2026.01.1 ", "2026.01.1"},
+		{"v", "v"},
+		{"vnext", "vnext"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, NormalizeVersion(tt.in), tt.in)
+	}
+}
+
+func TestEntry_VersionTag(t *testing.T) {
+	assert.Equal(t, "core/v1.2.0", Entry{Version: "1.2.0", Tag: "core/v1.2.0"}.VersionTag())
+	assert.Equal(t, "v1.2.0", Entry{Version: "1.2.0"}.VersionTag())
+	assert.Equal(t, "v1.2.0", Entry{Version: "v1.2.0"}.VersionTag())
 }
 
 // TestCombinedFilters tests applying both package and version filters
@@ -276,3 +320,53 @@ func mustParseTime(s string) time.Time {
 	}
 	return t
 }
+
+func TestFilterByVersionRange(t *testing.T) {
+	entries := []Entry{
+		{Version: "1.2.0", Package: "core"},
+		{Version: "1.10.0", Package: "core"},
+		{Version: "1.9.0", Package: "core"},
+		{Version: "2.0.0-rc.1", Package: "core"},
+		{Version: "2.0.0", Package: "core"},
+	}
+	versions := func(entries []Entry) []string {
+		var vs []string
+		for _, e := range entries {
+			vs = append(vs, e.Version)
+		}
+		return vs
+	}
+
+	// Versions compare numerically, not as strings
+	got, err := FilterByVersionRange(entries, "1.9.0", "v1.10.0", semver.Parse)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1.10.0", "1.9.0"}, versions(got))
+
+	got, err = FilterByVersionRange(entries, "2.0.0-rc.1", "", semver.Parse)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2.0.0-rc.1", "2.0.0"}, versions(got))
+
+	got, err = FilterByVersionRange(entries, "", "1.5.0", semver.Parse)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1.2.0"}, versions(got))
+
+	got, err = FilterByVersionRange(entries, "3.0.0", "", semver.Parse)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	_, err = FilterByVersionRange(entries, "one", "", semver.Parse)
+	assert.ErrorContains(t, err, `invalid from version "one"`)
+}
+
+func TestFilterSince(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 0, 0, 0, 0, time.UTC) }
+	entries := []Entry{
+		{Version: "1.0.0", Timestamp: day(1)},
+		{Version: "1.1.0", Timestamp: day(10)},
+		{Version: "1.2.0", Timestamp: day(20)},
+	}
+	got := FilterSince(entries, day(10))
+	require.Len(t, got, 2)
+	assert.Equal(t, "1.1.0", got[0].Version)
+	assert.Empty(t, FilterSince(entries, day(21)))
+}
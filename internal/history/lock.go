@@ -0,0 +1,95 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// DefaultLockTimeout is how long history reads and writes wait for another
+// shipyard process to release the history lock
+const DefaultLockTimeout = 10 * time.Second
+
+// lockRetryDelay is how often a held lock is retried
+const lockRetryDelay = 50 * time.Millisecond
+
+// ErrLocked is returned when the history lock is still held after the timeout
+var ErrLocked = errors.New("another shipyard process holds the history lock")
+
+// File is a history file together with how long its readers and writers
+// wait for the history lock
+type File struct {
+	Path        string
+	LockTimeout time.Duration // Zero or negative means DefaultLockTimeout
+}
+
+// lockTimeout returns how long to wait for the lock
+func (f File) lockTimeout() time.Duration {
+	if f.LockTimeout <= 0 {
+		return DefaultLockTimeout
+	}
+	return f.LockTimeout
+}
+
+// lockPath returns the advisory lock file guarding a history file
+func lockPath(historyPath string) string {
+	return historyPath + ".lock"
+}
+
+// lock takes the advisory lock for the history file, shared for readers and
+// exclusive for writers, and returns a function that releases it
+func (f File) lock(exclusive bool) (func(), error) {
+	fileLock := flock.New(lockPath(f.Path))
+
+	timeout := f.lockTimeout()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var locked bool
+	var err error
+	if exclusive {
+		locked, err = fileLock.TryLockContext(ctx, lockRetryDelay)
+	} else {
+		locked, err = fileLock.TryRLockContext(ctx, lockRetryDelay)
+	}
+	if !locked {
+		if err == nil || errors.Is(err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w (%s); waited %s", ErrLocked, lockPath(f.Path), timeout)
+		}
+		return nil, fmt.Errorf("failed to acquire history lock: %w", err)
+	}
+
+	return func() { _ = fileLock.Unlock() }, nil
+}
+
+// readLock takes the shared lock for reading the history file. When the lock
+// file cannot be created (e.g. a read-only checkout) it reads unlocked:
+// writes are atomic renames, so reading without the lock is still safe.
+func (f File) readLock() (func(), error) {
+	unlock, err := f.lock(false)
+	if err != nil {
+		if errors.Is(err, ErrLocked) {
+			return nil, err
+		}
+		return func() {}, nil
+	}
+	return unlock, nil
+}
+
+// Locked reports whether another shipyard process is writing the history
+// file, without waiting for it. A lock file that cannot be opened counts as
+// unlocked, as for readers.
+func Locked(historyPath string) bool {
+	fileLock := flock.New(lockPath(historyPath))
+	locked, err := fileLock.TryRLock()
+	if err != nil {
+		return false
+	}
+	if locked {
+		_ = fileLock.Unlock()
+	}
+	return !locked
+}
@@ -0,0 +1,69 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHistoryLock_Timeout verifies reads and writes give up with ErrLocked
+// while another process holds the lock
+func TestHistoryLock_Timeout(t *testing.T) {
+	historyPath := filepath.Join(t.TempDir(), "history.json")
+	require.NoError(t, os.WriteFile(historyPath, []byte("[]"), 0644))
+
+	file := File{Path: historyPath, LockTimeout: 100 * time.Millisecond}
+
+	held := flock.New(lockPath(historyPath))
+	require.NoError(t, held.Lock())
+
+	start := time.Now()
+	err := file.Append([]Entry{{Version: "1.0.0", Package: "core"}})
+	require.ErrorIs(t, err, ErrLocked)
+	assert.Contains(t, err.Error(), "another shipyard process holds the history lock")
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+
+	_, err = file.Read()
+	require.ErrorIs(t, err, ErrLocked)
+
+	require.NoError(t, held.Unlock())
+	require.NoError(t, AppendToHistory(historyPath, []Entry{{Version: "1.0.0", Package: "core"}}))
+
+	entries, err := ReadHistory(historyPath)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+// TestHistoryLock_SharedReads verifies readers do not block each other
+func TestHistoryLock_SharedReads(t *testing.T) {
+	historyPath := filepath.Join(t.TempDir(), "history.json")
+	require.NoError(t, os.WriteFile(historyPath, []byte("[]"), 0644))
+
+	file := File{Path: historyPath, LockTimeout: 100 * time.Millisecond}
+
+	reader := flock.New(lockPath(historyPath))
+	require.NoError(t, reader.RLock())
+	defer func() { _ = reader.Unlock() }()
+
+	_, err := file.Read()
+	assert.NoError(t, err)
+}
+
+// TestLocked verifies a held write lock is reported without waiting
+func TestLocked(t *testing.T) {
+	historyPath := filepath.Join(t.TempDir(), "history.json")
+	require.NoError(t, os.WriteFile(historyPath, []byte("[]"), 0644))
+	assert.False(t, Locked(historyPath))
+
+	held := flock.New(lockPath(historyPath))
+	require.NoError(t, held.Lock())
+	assert.True(t, Locked(historyPath))
+
+	require.NoError(t, held.Unlock())
+	assert.False(t, Locked(historyPath))
+}
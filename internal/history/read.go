@@ -2,6 +2,7 @@ package history
 
 import (
 	"encoding/json"
+	"os"
 	"time"
 
 	"github.com/NatoNathan/shipyard/internal/fileutil"
@@ -9,23 +10,101 @@ import (
 
 // Entry represents a version history entry
 type Entry struct {
-	Version      string        `json:"version"`
-	Package      string        `json:"package"`
-	Tag          string        `json:"tag"` // Git tag name for this version
-	Timestamp    time.Time     `json:"timestamp"`
-	Consignments []Consignment `json:"consignments"`
+	Version         string            `json:"version"`
+	Package         string            `json:"package"`
+	PreviousVersion string            `json:"previousVersion,omitempty"` // Version before this release, when recorded
+	ChangeType      string            `json:"changeType,omitempty"`      // Bump applied, which propagation can raise above the consignments'
+	Tag             string            `json:"tag"`                       // Git tag name for this version
+	BaseCommitSHA   string            `json:"baseCommitSha,omitempty"`   // HEAD when the release was cut, the release commit's parent; empty for older entries
+	Timestamp       time.Time         `json:"timestamp"`
+	Consignments    []Consignment     `json:"consignments"`
+	Config          *ConfigSnapshot   `json:"config,omitempty"`       // Config that produced this entry
+	Yanked          bool              `json:"yanked,omitempty"`       // Release was withdrawn after publishing
+	Artifacts       []Artifact        `json:"artifacts,omitempty"`    // Artifacts published for this version
+	Notes           []Note            `json:"notes,omitempty"`        // Post-release notes, appended by history annotate
+	Channel         string            `json:"channel,omitempty"`      // Release channel, when channels are configured
+	PromotedFrom    string            `json:"promotedFrom,omitempty"` // Version on the channel this release was promoted from
+	SetVersion      bool              `json:"setVersion,omitempty"`   // Version was set by hand rather than calculated from the changes
+	Imported        bool              `json:"imported,omitempty"`     // Read from a changelog written before shipyard was adopted
+	Preformatted    string            `json:"preformatted,omitempty"` // Imported release text that could not be split into changes, rendered as written
+	Placeholder     string            `json:"-"`                      // Shown by templates when every change was excluded from rendering
+	NotesHeading    string            `json:"-"`                      // Title templates render above Notes
+	Ecosystem       string            `json:"-"`                      // Package ecosystem, for templates that branch on it
+	IsMonorepo      bool              `json:"-"`                      // Project configures more than one package
+	Audiences       map[string]string `json:"-"`                      // Change type -> audience, for ChangesByAudience
+
+	ChangelogSections []Section `json:"-"` // Changelog sections in order, for Sections; DefaultSections when nil
+
+	// Set for changelog templates from the entries' order
+	PreviousTag string `json:"-"` // Tag of the previous release; empty for the first
+	CompareURL  string `json:"-"` // Link comparing PreviousTag to this release's tag, or browsing the tag for the first release
+	ReleaseURL  string `json:"-"` // Link to the hosted release page of this release's tag
+}
+
+// VersionTag returns the git tag recorded for this version, falling back to
+// the v-prefixed version for entries written before tags were recorded
+func (e Entry) VersionTag() string {
+	if e.Tag != "" {
+		return e.Tag
+	}
+	return "v" + NormalizeVersion(e.Version)
+}
+
+// Artifact records a package artifact pushed to a registry after release
+type Artifact struct {
+	Type      string `json:"type"`      // Publisher type, e.g. "helm"
+	Reference string `json:"reference"` // Registry reference, e.g. ghcr.io/acme/charts/api:1.2.0
+	Digest    string `json:"digest"`    // Content digest reported by the registry
+}
+
+// Note is a remark recorded after a version shipped. Notes are only ever
+// appended and never affect version computation.
+type Note struct {
+	Text      string    `json:"text"`
+	Author    string    `json:"author,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// ConfigSnapshot records which configuration produced a history entry
+type ConfigSnapshot struct {
+	Hash     string `json:"hash"`               // sha256 of the resolved effective config
+	BlobHash string `json:"blobHash,omitempty"` // git blob hash of the config file at HEAD
+	Path     string `json:"path,omitempty"`     // config file path relative to the project root
+	Resolved string `json:"resolved,omitempty"` // full resolved config YAML, when embedding is enabled
 }
 
 // Consignment represents a change in a version
 type Consignment struct {
 	ID         string                 `json:"id"`
 	Summary    string                 `json:"summary"`
+	RawSummary string                 `json:"-"` // Unnormalized summary, populated for template rendering
 	ChangeType string                 `json:"changeType"`
 	Metadata   map[string]interface{} `json:"metadata,omitempty"`
 }
 
-// ReadHistory reads history entries from a JSON file
+// ReadHistory reads history entries from a JSON file under a shared lock,
+// so it never observes a write in progress from another shipyard process
 func ReadHistory(path string) ([]Entry, error) {
+	return File{Path: path}.Read()
+}
+
+// Read reads the history file's entries under its shared lock
+func (f File) Read() ([]Entry, error) {
+	if _, err := os.Stat(f.Path); err != nil {
+		return nil, err
+	}
+
+	unlock, err := f.readLock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	return readEntries(f.Path)
+}
+
+// readEntries decodes a history file without taking the lock
+func readEntries(path string) ([]Entry, error) {
 	data, err := fileutil.ReadFile(path)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,106 @@
+package history
+
+import (
+	"fmt"
+
+	"github.com/NatoNathan/shipyard/internal/fileutil"
+)
+
+// RenameResult reports what a package rename rewrote
+type RenameResult struct {
+	Renamed int      `json:"renamed"` // Entries moved to the new name
+	Files   []string `json:"files"`   // History and archive files rewritten (or that would be rewritten)
+	Backups []string `json:"backups"` // Copies of those files as they were before the rename
+}
+
+// BackupPath returns where RenamePackage keeps the previous contents of a
+// history or archive file
+func BackupPath(path string) string {
+	return path + ".bak"
+}
+
+// RenamePackage rewrites the entries of oldName in a history file and its
+// yearly archives to newName. Each rewritten file is first copied to its
+// BackupPath. Every file is checked before any is written, so a rename that
+// would give newName the same version twice changes nothing. Tags and
+// consignments are left as recorded.
+func RenamePackage(historyPath, oldName, newName string, dryRun bool) (*RenameResult, error) {
+	return File{Path: historyPath}.RenamePackage(oldName, newName, dryRun)
+}
+
+// RenamePackage is RenamePackage for a history file with its own lock timeout
+func (f File) RenamePackage(oldName, newName string, dryRun bool) (*RenameResult, error) {
+	if oldName == "" || newName == "" {
+		return nil, fmt.Errorf("package names must not be empty")
+	}
+	if oldName == newName {
+		return nil, fmt.Errorf("package is already named %s", newName)
+	}
+
+	unlock, err := f.lock(true)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	archives, err := ListArchives(f.Path)
+	if err != nil {
+		return nil, err
+	}
+	paths := append(archives, f.Path)
+
+	files := make([][]Entry, len(paths))
+	existing := make(map[string]bool)
+	for i, path := range paths {
+		files[i], err = readEntries(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		for _, entry := range files[i] {
+			if entry.Package == newName {
+				existing[NormalizeVersion(entry.Version)] = true
+			}
+		}
+	}
+
+	result := &RenameResult{Files: []string{}, Backups: []string{}}
+	var changed []int
+	for i, entries := range files {
+		renamed := 0
+		for j := range entries {
+			if entries[j].Package != oldName {
+				continue
+			}
+			if existing[NormalizeVersion(entries[j].Version)] {
+				return nil, fmt.Errorf("history already has %s %s; renaming %s would record that version twice",
+					newName, entries[j].Version, oldName)
+			}
+			entries[j].Package = newName
+			renamed++
+		}
+		if renamed > 0 {
+			result.Renamed += renamed
+			result.Files = append(result.Files, paths[i])
+			result.Backups = append(result.Backups, BackupPath(paths[i]))
+			changed = append(changed, i)
+		}
+	}
+
+	if dryRun {
+		return result, nil
+	}
+
+	for _, i := range changed {
+		data, err := fileutil.ReadFile(paths[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", paths[i], err)
+		}
+		if err := fileutil.AtomicWrite(BackupPath(paths[i]), data, 0644); err != nil {
+			return nil, fmt.Errorf("failed to back up %s: %w", paths[i], err)
+		}
+		if err := writeEntries(paths[i], files[i]); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
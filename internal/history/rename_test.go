@@ -0,0 +1,75 @@
+package history
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenamePackage(t *testing.T) {
+	historyPath := writeTestHistory(t, compactTestEntries())
+	_, err := Compact(historyPath, CompactOptions{Keep: 2})
+	require.NoError(t, err)
+	before, err := os.ReadFile(historyPath)
+	require.NoError(t, err)
+
+	result, err := RenamePackage(historyPath, "core", "platform", false)
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, result.Renamed)
+	assert.Equal(t, []string{ArchivePath(historyPath, 2023), ArchivePath(historyPath, 2024), historyPath}, result.Files)
+	assert.Equal(t, BackupPath(historyPath), result.Backups[2])
+
+	entries, err := ReadHistoryWithArchives(historyPath)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Package+"@"+entry.Version)
+	}
+	assert.Equal(t, []string{"platform@1.0.0", "api@0.1.0", "platform@1.1.0", "platform@1.2.0", "platform@2.0.0"}, names)
+
+	backup, err := os.ReadFile(BackupPath(historyPath))
+	require.NoError(t, err)
+	assert.Equal(t, before, backup, "the backup holds the history as it was")
+
+	archives, err := ListArchives(historyPath)
+	require.NoError(t, err)
+	assert.Len(t, archives, 2, "backups are not mistaken for archives")
+}
+
+func TestRenamePackage_DryRun(t *testing.T) {
+	historyPath := writeTestHistory(t, compactTestEntries())
+	before, err := os.ReadFile(historyPath)
+	require.NoError(t, err)
+
+	result, err := RenamePackage(historyPath, "core", "platform", true)
+	require.NoError(t, err)
+	assert.Equal(t, 4, result.Renamed)
+
+	after, err := os.ReadFile(historyPath)
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+	assert.NoFileExists(t, BackupPath(historyPath))
+}
+
+func TestRenamePackage_Errors(t *testing.T) {
+	historyPath := writeTestHistory(t, append(compactTestEntries(), Entry{Package: "platform", Version: "v2.0.0"}))
+	before, err := os.ReadFile(historyPath)
+	require.NoError(t, err)
+
+	_, err = RenamePackage(historyPath, "core", "platform", false)
+	assert.ErrorContains(t, err, "history already has platform 2.0.0; renaming core would record that version twice")
+	after, err := os.ReadFile(historyPath)
+	require.NoError(t, err)
+	assert.Equal(t, before, after, "a conflicting rename changes nothing")
+
+	_, err = RenamePackage(historyPath, "core", "core", false)
+	assert.ErrorContains(t, err, "already named core")
+
+	result, err := RenamePackage(historyPath, "missing", "other", false)
+	require.NoError(t, err)
+	assert.Zero(t, result.Renamed)
+	assert.NoFileExists(t, BackupPath(historyPath))
+}
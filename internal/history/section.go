@@ -0,0 +1,62 @@
+package history
+
+import "slices"
+
+// Section is a changelog heading and the change types listed under it. An
+// empty Title leaves the heading to the template, which names it after its
+// only change type.
+type Section struct {
+	Title       string
+	ChangeTypes []string
+}
+
+// SectionChanges is a changelog section with its changes, in their original
+// order
+type SectionChanges[T any] struct {
+	Section
+	Consignments []T
+}
+
+// DefaultSections lists breaking changes, then features, then fixes, each
+// headed by the template
+var DefaultSections = []Section{
+	{ChangeTypes: []string{"major"}},
+	{ChangeTypes: []string{"minor"}},
+	{ChangeTypes: []string{"patch"}},
+}
+
+// GroupBySection lists items under the sections that cover their change
+// type, in section order, leaving out sections with no items. Items of a
+// change type no section covers follow in sections of their own, so no
+// change is dropped. nil sections means DefaultSections.
+func GroupBySection[T any](items []T, changeTypeOf func(T) string, sections []Section) []SectionChanges[T] {
+	if sections == nil {
+		sections = DefaultSections
+	}
+	groups := make([]SectionChanges[T], 0, len(sections))
+	for _, section := range sections {
+		groups = append(groups, SectionChanges[T]{Section: section})
+	}
+	for _, item := range items {
+		changeType := changeTypeOf(item)
+		i := slices.IndexFunc(groups, func(g SectionChanges[T]) bool {
+			return slices.Contains(g.ChangeTypes, changeType)
+		})
+		if i == -1 {
+			i = len(groups)
+			groups = append(groups, SectionChanges[T]{Section: Section{ChangeTypes: []string{changeType}}})
+		}
+		groups[i].Consignments = append(groups[i].Consignments, item)
+	}
+	return slices.DeleteFunc(groups, func(g SectionChanges[T]) bool {
+		return len(g.Consignments) == 0
+	})
+}
+
+// Sections lists the entry's consignments under the changelog sections
+// configured for rendering
+func (e Entry) Sections() []SectionChanges[Consignment] {
+	return GroupBySection(e.Consignments, func(c Consignment) string {
+		return c.ChangeType
+	}, e.ChangelogSections)
+}
@@ -0,0 +1,42 @@
+package history
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntry_Sections(t *testing.T) {
+	consignments := []Consignment{
+		{ID: "c1", ChangeType: "patch"},
+		{ID: "c2", ChangeType: "minor"},
+		{ID: "c3", ChangeType: "patch"},
+		{ID: "c4", ChangeType: "docs"},
+	}
+	ids := func(groups []SectionChanges[Consignment]) [][]string {
+		var result [][]string
+		for _, g := range groups {
+			var group []string
+			for _, c := range g.Consignments {
+				group = append(group, c.ID)
+			}
+			result = append(result, group)
+		}
+		return result
+	}
+
+	t.Run("default sections", func(t *testing.T) {
+		groups := Entry{Consignments: consignments}.Sections()
+		assert.Equal(t, [][]string{{"c2"}, {"c1", "c3"}, {"c4"}}, ids(groups), "empty sections are left out and uncovered types come last")
+		assert.Equal(t, Section{ChangeTypes: []string{"docs"}}, groups[2].Section)
+	})
+
+	t.Run("configured sections", func(t *testing.T) {
+		groups := Entry{Consignments: consignments, ChangelogSections: []Section{
+			{Title: "Performance", ChangeTypes: []string{"patch"}},
+			{ChangeTypes: []string{"minor"}},
+		}}.Sections()
+		assert.Equal(t, [][]string{{"c1", "c3"}, {"c2"}, {"c4"}}, ids(groups))
+		assert.Equal(t, "Performance", groups[0].Title)
+	})
+}
@@ -0,0 +1,124 @@
+// Package hooks runs the shell commands configured around a release.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"sync"
+)
+
+// Stage names when a hook runs
+type Stage string
+
+const (
+	PreVersion  Stage = "preVersion"  // before any file changes
+	PostVersion Stage = "postVersion" // after the release files are written, before the release commit
+)
+
+// Release describes the package release a hook runs for
+type Release struct {
+	Package    string
+	OldVersion string
+	NewVersion string
+	Tag        string // "" when the release is not tagged
+	Dir        string // the package directory hooks run in
+}
+
+// Env returns the variables hooks receive on top of the process environment
+func (r Release) Env() []string {
+	return []string{
+		"SHIPYARD_PACKAGE=" + r.Package,
+		"SHIPYARD_OLD_VERSION=" + r.OldVersion,
+		"SHIPYARD_NEW_VERSION=" + r.NewVersion,
+		"SHIPYARD_TAG=" + r.Tag,
+	}
+}
+
+// Error reports a hook that failed
+type Error struct {
+	Stage   Stage
+	Package string
+	Command string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s hook for %s failed: %s: %v", e.Stage, e.Package, e.Command, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Run runs commands in order with the system shell in the release's
+// directory. Each command's output, stdout and stderr alike, is streamed to
+// out line by line, prefixed with the package, stage and command number.
+// The first command that fails stops the run.
+func Run(ctx context.Context, stage Stage, commands []string, release Release, out io.Writer) error {
+	for i, command := range commands {
+		prefix := fmt.Sprintf("[%s %s %d/%d] ", release.Package, stage, i+1, len(commands))
+		fmt.Fprintf(out, "%s$ %s\n", prefix, command)
+
+		lines := &prefixWriter{out: out, prefix: prefix}
+		cmd := shellCommand(ctx, command)
+		cmd.Dir = release.Dir
+		cmd.Env = append(os.Environ(), release.Env()...)
+		cmd.Stdout = lines
+		cmd.Stderr = lines
+		err := cmd.Run()
+		lines.Flush()
+		if err != nil {
+			return &Error{Stage: stage, Package: release.Package, Command: command, Err: err}
+		}
+	}
+	return nil
+}
+
+// shellCommand returns a command running command with the system shell
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command) // #nosec G204 -- hooks are commands the project configures to run.
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command) // #nosec G204 -- hooks are commands the project configures to run.
+}
+
+// prefixWriter writes complete lines to out, each starting with prefix
+type prefixWriter struct {
+	mu      sync.Mutex
+	out     io.Writer
+	prefix  string
+	partial []byte
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i == -1 {
+			break
+		}
+		if _, err := fmt.Fprintf(w.out, "%s%s\n", w.prefix, w.partial[:i]); err != nil {
+			return 0, err
+		}
+		w.partial = w.partial[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush writes a final line that did not end in a newline
+func (w *prefixWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.partial) > 0 {
+		fmt.Fprintf(w.out, "%s%s\n", w.prefix, w.partial)
+		w.partial = nil
+	}
+}
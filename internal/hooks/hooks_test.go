@@ -0,0 +1,52 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook commands use sh")
+	}
+	dir := t.TempDir()
+	release := Release{Package: "core", OldVersion: "1.0.0", NewVersion: "1.1.0", Tag: "core/v1.1.0", Dir: dir}
+
+	t.Run("streams prefixed output", func(t *testing.T) {
+		var out bytes.Buffer
+		err := Run(context.Background(), PreVersion, []string{
+			`echo "$SHIPYARD_PACKAGE $SHIPYARD_OLD_VERSION $SHIPYARD_NEW_VERSION $SHIPYARD_TAG"`,
+			`echo oops >&2; printf partial`,
+		}, release, &out)
+		require.NoError(t, err)
+		assert.Equal(t, "[core preVersion 1/2] $ echo \"$SHIPYARD_PACKAGE $SHIPYARD_OLD_VERSION $SHIPYARD_NEW_VERSION $SHIPYARD_TAG\"\n"+
+			"[core preVersion 1/2] core 1.0.0 1.1.0 core/v1.1.0\n"+
+			"[core preVersion 2/2] $ echo oops >&2; printf partial\n"+
+			"[core preVersion 2/2] oops\n"+
+			"[core preVersion 2/2] partial\n", out.String())
+	})
+
+	t.Run("runs in the release directory", func(t *testing.T) {
+		require.NoError(t, Run(context.Background(), PostVersion, []string{"touch marker"}, release, &bytes.Buffer{}))
+		assert.FileExists(t, filepath.Join(dir, "marker"))
+	})
+
+	t.Run("stops at the first failure", func(t *testing.T) {
+		err := Run(context.Background(), PostVersion, []string{"exit 2", "touch second"}, release, &bytes.Buffer{})
+		require.Error(t, err)
+		var hookErr *Error
+		require.ErrorAs(t, err, &hookErr)
+		assert.Equal(t, PostVersion, hookErr.Stage)
+		assert.Equal(t, "exit 2", hookErr.Command)
+		assert.Contains(t, err.Error(), "postVersion hook for core failed: exit 2")
+		_, statErr := os.Stat(filepath.Join(dir, "second"))
+		assert.True(t, os.IsNotExist(statErr))
+	})
+}
@@ -0,0 +1,122 @@
+// Package httpclient provides the HTTP transport shared by every remote
+// fetch: templates, extended configs and GitHub API calls.
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/NatoNathan/shipyard/internal/logger"
+)
+
+// CABundleEnv names the environment variable holding a PEM file of extra
+// certificate authorities trusted alongside the system pool
+const CABundleEnv = "SHIPYARD_CA_BUNDLE"
+
+// TLSOptions configures certificate verification for remote fetches
+type TLSOptions struct {
+	CABundle           string // PEM file of extra certificate authorities, added to CABundleEnv's
+	InsecureSkipVerify bool   // Skip certificate verification entirely
+}
+
+var (
+	mu         sync.Mutex
+	transports = make(map[transportKey]http.RoundTripper)
+)
+
+// transportKey is what a transport was built from
+type transportKey struct {
+	options  TLSOptions
+	envValue string
+}
+
+// Transport returns the transport of remote fetches made without project
+// settings, such as upgrade downloads. It is TransportFor with no options.
+func Transport() http.RoundTripper {
+	return TransportFor(TLSOptions{})
+}
+
+// TransportFor returns the transport remote fetches under opts use. It
+// honours HTTP_PROXY, HTTPS_PROXY and NO_PROXY, and trusts the system
+// certificate pool plus the bundles from CABundleEnv and opts. A bundle that
+// cannot be read fails every request with the reason rather than silently
+// falling back to the system pool. Transports are built once per set of
+// options and shared. Disabling certificate verification is logged as a
+// warning when its transport is built, since anyone on the network path can
+// then tamper with fetched templates and configs.
+func TransportFor(opts TLSOptions) http.RoundTripper {
+	mu.Lock()
+	defer mu.Unlock()
+	key := transportKey{options: opts, envValue: os.Getenv(CABundleEnv)}
+	if transport, ok := transports[key]; ok {
+		return transport
+	}
+
+	if opts.InsecureSkipVerify {
+		logger.Get().Warn("TLS certificate verification is disabled by remote.insecureSkipVerify; remote templates and configs can be intercepted or altered")
+	}
+	var transport http.RoundTripper
+	built, err := newTransport(key)
+	if err != nil {
+		transport = failingTransport{err}
+	} else {
+		transport = built
+	}
+	transports[key] = transport
+	return transport
+}
+
+// newTransport builds a transport for key
+func newTransport(key transportKey) (*http.Transport, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = http.ProxyFromEnvironment
+
+	if key.options.InsecureSkipVerify {
+		// #nosec G402 -- opted into with remote.insecureSkipVerify, which warns
+		t.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+		return t, nil
+	}
+
+	var bundles []string
+	if key.envValue != "" {
+		bundles = append(bundles, key.envValue)
+	}
+	if key.options.CABundle != "" {
+		bundles = append(bundles, key.options.CABundle)
+	}
+	if len(bundles) == 0 {
+		return t, nil
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	for _, bundle := range bundles {
+		pem, err := os.ReadFile(bundle)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA bundle %s holds no PEM certificates", bundle)
+		}
+	}
+	t.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	return t, nil
+}
+
+// failingTransport fails every request with err
+type failingTransport struct {
+	err error
+}
+
+func (f failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+	return nil, f.err
+}
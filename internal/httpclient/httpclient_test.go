@@ -0,0 +1,90 @@
+package httpclient
+
+import (
+	"bytes"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NatoNathan/shipyard/internal/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTLSServer starts a TLS server with a self-signed certificate and
+// writes that certificate to a PEM bundle, returning the server URL and the
+// bundle path
+func newTLSServer(t *testing.T) (string, string) {
+	t.Helper()
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	t.Cleanup(server.Close)
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	block := &pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}
+	require.NoError(t, os.WriteFile(bundle, pem.EncodeToMemory(block), 0644))
+	return server.URL, bundle
+}
+
+// get fetches url with the transport for opts
+func get(url string, opts TLSOptions) error {
+	client := &http.Client{Transport: TransportFor(opts)}
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func TestTransport_SelfSignedServer(t *testing.T) {
+	url, bundle := newTLSServer(t)
+	t.Setenv(CABundleEnv, "")
+
+	assert.ErrorContains(t, get(url, TLSOptions{}), "certificate", "untrusted without a bundle")
+	assert.NoError(t, get(url, TLSOptions{CABundle: bundle}), "trusted with the configured bundle")
+	assert.Error(t, get(url, TLSOptions{}), "a bundle only applies to the options naming it")
+}
+
+func TestTransport_CABundleEnv(t *testing.T) {
+	url, bundle := newTLSServer(t)
+
+	t.Setenv(CABundleEnv, bundle)
+	assert.NoError(t, get(url, TLSOptions{}))
+
+	t.Setenv(CABundleEnv, filepath.Join(t.TempDir(), "missing.pem"))
+	assert.ErrorContains(t, get(url, TLSOptions{}), "failed to read CA bundle")
+}
+
+func TestTransport_InvalidBundle(t *testing.T) {
+	url, _ := newTLSServer(t)
+	t.Setenv(CABundleEnv, "")
+	bundle := filepath.Join(t.TempDir(), "empty.pem")
+	require.NoError(t, os.WriteFile(bundle, []byte("not a certificate"), 0644))
+
+	assert.ErrorContains(t, get(url, TLSOptions{CABundle: bundle}), "holds no PEM certificates")
+}
+
+func TestTransport_InsecureSkipVerify(t *testing.T) {
+	url, _ := newTLSServer(t)
+	t.Setenv(CABundleEnv, "")
+	var logs bytes.Buffer
+	previous := logger.Get()
+	logger.SetGlobal(logger.New(&logs, logger.LevelWarn, false))
+	t.Cleanup(func() { logger.SetGlobal(previous) })
+
+	// A fresh bundle path keeps the options distinct from other tests'
+	insecure := TLSOptions{InsecureSkipVerify: true, CABundle: filepath.Join(t.TempDir(), "unused.pem")}
+	assert.NoError(t, get(url, insecure))
+	assert.NoError(t, get(url, insecure))
+	assert.Equal(t, 1, bytes.Count(logs.Bytes(), []byte("TLS certificate verification is disabled")), "warns once when built")
+}
+
+func TestTransport_UsesProxyFromEnvironment(t *testing.T) {
+	transport, ok := Transport().(*http.Transport)
+	require.True(t, ok)
+	assert.NotNil(t, transport.Proxy)
+}
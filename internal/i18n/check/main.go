@@ -0,0 +1,267 @@
+// Command check enforces the message catalog. It reports user-facing string
+// literals in migrated files that bypass i18n.T, and i18n.T calls anywhere in
+// the module whose message ID has no English text.
+//
+// Run it with go generate ./internal/i18n/...; its test runs the same check
+// under go test, so CI fails when a migrated file regresses.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/NatoNathan/shipyard/internal/i18n"
+)
+
+// ignoreComment exempts a line from the literal check
+const ignoreComment = "i18n-ignore"
+
+// migratedFiles are the files whose user-facing text must come from the catalog
+var migratedFiles = []string{
+	"internal/commands/add.go",
+	"internal/commands/add_commits.go",
+	"internal/commands/add_guard.go",
+	"internal/commands/version.go",
+	"internal/commands/version_publish.go",
+	"internal/commands/version_push.go",
+	"internal/release/plan.go",
+	"internal/release/release.go",
+	"internal/release/run.go",
+	"internal/prompt/accessible.go",
+	"internal/prompt/changetype.go",
+	"internal/prompt/packages.go",
+}
+
+// messageFuncs are calls whose string arguments reach the user
+var messageFuncs = map[string]bool{
+	"SuccessMessage": true,
+	"InfoMessage":    true,
+	"WarningMessage": true,
+	"ErrorMessage":   true,
+	"KeyValue":       true,
+	"Header":         true,
+	"Dimmed":         true,
+	"Table":          true,
+	"Render":         true,
+	"Print":          true,
+	"Println":        true,
+	"Printf":         true,
+	"Fprint":         true,
+	"Fprintln":       true,
+	"Fprintf":        true,
+	"say":            true,
+	"Title":          true,
+	"Description":    true,
+	"Placeholder":    true,
+}
+
+// formatVerbRe matches fmt verbs, which carry no translatable text
+var formatVerbRe = regexp.MustCompile(`%[-+# 0-9.*\[\]]*[a-zA-Z%]`)
+
+// violation is one catalog problem at a source position
+type violation struct {
+	Pos     token.Position
+	Message string
+}
+
+func (v violation) String() string {
+	return fmt.Sprintf("%s: %s", v.Pos, v.Message)
+}
+
+func main() {
+	root := flag.String("root", ".", "module root directory")
+	flag.Parse()
+
+	violations, err := check(*root, migratedFiles)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	for _, v := range violations {
+		fmt.Fprintln(os.Stderr, v)
+	}
+	if len(violations) > 0 {
+		os.Exit(1)
+	}
+}
+
+// check runs both catalog checks under root. Files are relative to root.
+func check(root string, files []string) ([]violation, error) {
+	fset := token.NewFileSet()
+	english := i18n.Messages(i18n.DefaultLocale)
+
+	var violations []violation
+	for _, name := range files {
+		file, err := parser.ParseFile(fset, filepath.Join(root, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		violations = append(violations, literalViolations(fset, file)...)
+	}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			// Nested modules and fixtures are not part of this module
+			if path != root && (strings.HasPrefix(d.Name(), ".") || d.Name() == "testdata" || d.Name() == "dagger") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return err
+		}
+		violations = append(violations, unknownIDViolations(fset, file, english)...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(violations, func(i, j int) bool {
+		a, b := violations[i].Pos, violations[j].Pos
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		return a.Line < b.Line
+	})
+	return violations, nil
+}
+
+// literalViolations reports string literals with text passed to messageFuncs
+func literalViolations(fset *token.FileSet, file *ast.File) []violation {
+	ignored := make(map[int]bool)
+	for _, group := range file.Comments {
+		for _, c := range group.List {
+			if strings.Contains(c.Text, ignoreComment) {
+				ignored[fset.Position(c.Pos()).Line] = true
+			}
+		}
+	}
+
+	var violations []violation
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		if !isMessageCall(call) {
+			return true
+		}
+		name, args := funcName(call), call.Args
+		if name == "NewValidationError" {
+			// The first argument is a field name, not a message
+			args = args[1:]
+		}
+		violations = append(violations, textLiterals(fset, args, name, ignored)...)
+		return true
+	})
+	return violations
+}
+
+// textLiterals finds string literals with letters in args, skipping catalog lookups
+func textLiterals(fset *token.FileSet, args []ast.Expr, fn string, ignored map[int]bool) []violation {
+	var violations []violation
+	for _, arg := range args {
+		ast.Inspect(arg, func(n ast.Node) bool {
+			// Nested message calls are checked on their own
+			if call, ok := n.(*ast.CallExpr); ok && (isCatalogCall(call) || isMessageCall(call)) {
+				return false
+			}
+			lit, ok := n.(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				return true
+			}
+			pos := fset.Position(lit.Pos())
+			if ignored[pos.Line] || !hasText(lit.Value) {
+				return true
+			}
+			violations = append(violations, violation{
+				Pos:     pos,
+				Message: fmt.Sprintf("string %s passed to %s bypasses the message catalog; use i18n.T", lit.Value, fn),
+			})
+			return true
+		})
+	}
+	return violations
+}
+
+// unknownIDViolations reports i18n.T calls whose ID has no English message
+func unknownIDViolations(fset *token.FileSet, file *ast.File, english map[string]string) []violation {
+	var violations []violation
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || !isCatalogCall(call) || len(call.Args) == 0 {
+			return true
+		}
+		lit, ok := call.Args[0].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return true
+		}
+		id, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return true
+		}
+		if _, ok := english[id]; !ok {
+			violations = append(violations, violation{
+				Pos:     fset.Position(lit.Pos()),
+				Message: fmt.Sprintf("message ID %q is missing from locales/%s.json", id, i18n.DefaultLocale),
+			})
+		}
+		return true
+	})
+	return violations
+}
+
+// funcName returns the name of the called function or method
+func funcName(call *ast.CallExpr) string {
+	switch fn := call.Fun.(type) {
+	case *ast.Ident:
+		return fn.Name
+	case *ast.SelectorExpr:
+		return fn.Sel.Name
+	}
+	return ""
+}
+
+// isMessageCall reports whether call passes its arguments to the user
+func isMessageCall(call *ast.CallExpr) bool {
+	name := funcName(call)
+	return messageFuncs[name] || (name == "NewValidationError" && len(call.Args) > 1)
+}
+
+// isCatalogCall reports whether call is i18n.T
+func isCatalogCall(call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "T" {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && pkg.Name == "i18n"
+}
+
+// hasText reports whether a quoted literal contains letters outside fmt verbs
+func hasText(quoted string) bool {
+	value, err := strconv.Unquote(quoted)
+	if err != nil {
+		return false
+	}
+	return strings.IndexFunc(formatVerbRe.ReplaceAllString(value, ""), unicode.IsLetter) >= 0
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigratedFilesUseCatalog(t *testing.T) {
+	violations, err := check(filepath.Join("..", "..", ".."), migratedFiles)
+	require.NoError(t, err)
+	for _, v := range violations {
+		t.Error(v)
+	}
+}
+
+func TestCheck_ReportsBypassedStrings(t *testing.T) {
+	dir := t.TempDir()
+	source := `package sample
+
+import (
+	"fmt"
+
+	"github.com/NatoNathan/shipyard/internal/errors"
+	"github.com/NatoNathan/shipyard/internal/i18n"
+	"github.com/NatoNathan/shipyard/internal/ui"
+)
+
+func run(name string) error {
+	fmt.Println(ui.SuccessMessage(fmt.Sprintf("Created %s", name)))
+	fmt.Println(ui.SuccessMessage(i18n.T("add.created", name)))
+	fmt.Println(ui.Dimmed(fmt.Sprintf("%s: %d", name, 1)))
+	fmt.Println(ui.Dimmed("Skipped")) // i18n-ignore
+	fmt.Println(i18n.T("no.such.message"))
+	return errors.NewValidationError("summary", "summary cannot be empty")
+}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sample.go"), []byte(source), 0644))
+
+	violations, err := check(dir, []string{"sample.go"})
+	require.NoError(t, err)
+
+	var lines []int
+	for _, v := range violations {
+		lines = append(lines, v.Pos.Line)
+	}
+	assert.Equal(t, []int{12, 16, 17}, lines)
+	assert.Contains(t, violations[1].Message, `"no.such.message"`)
+}
+
+func TestHasText(t *testing.T) {
+	tests := []struct {
+		literal string
+		want    bool
+	}{
+		{`"Created consignment"`, true},
+		{`"%s: %s -> %s"`, false},
+		{`"  %d. %s\n"`, false},
+		{`"%-10s [%v]"`, false},
+		{`"%s (pass %s)"`, true},
+		{"`raw text`", true},
+		{`""`, false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, hasText(tt.literal), tt.literal)
+	}
+}
@@ -0,0 +1,153 @@
+// Package i18n translates user-facing CLI messages.
+//
+// Messages live in a catalog keyed by message ID, with one embedded JSON
+// bundle per locale under locales/. English is the source of truth: every
+// message has an English text, and other bundles may translate any subset of
+// it. Missing translations fall back to English.
+package i18n
+
+//go:generate go run ./check -root ../..
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// LocaleEnv selects the message locale when --locale is not given
+const LocaleEnv = "SHIPYARD_LOCALE"
+
+// DefaultLocale is the locale every message is written in
+const DefaultLocale = "en"
+
+//go:embed locales/*.json
+var localeFS embed.FS
+
+var (
+	loadOnce sync.Once
+	bundles  map[string]map[string]string
+	loadErr  error
+
+	locale string
+)
+
+// load parses the embedded locale bundles once
+func load() (map[string]map[string]string, error) {
+	loadOnce.Do(func() {
+		files, err := localeFS.ReadDir("locales")
+		if err != nil {
+			loadErr = err
+			return
+		}
+		bundles = make(map[string]map[string]string, len(files))
+		for _, file := range files {
+			data, err := localeFS.ReadFile(path.Join("locales", file.Name()))
+			if err != nil {
+				loadErr = err
+				return
+			}
+			var messages map[string]string
+			if err := json.Unmarshal(data, &messages); err != nil {
+				loadErr = fmt.Errorf("invalid locale bundle %s: %w", file.Name(), err)
+				return
+			}
+			bundles[strings.TrimSuffix(file.Name(), ".json")] = messages
+		}
+	})
+	return bundles, loadErr
+}
+
+// Locales returns the embedded locales, sorted
+func Locales() []string {
+	all, _ := load()
+	locales := make([]string, 0, len(all))
+	for name := range all {
+		locales = append(locales, name)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Messages returns a copy of a locale's bundle, or nil if it is not embedded
+func Messages(name string) map[string]string {
+	all, _ := load()
+	bundle, ok := all[name]
+	if !ok {
+		return nil
+	}
+	messages := make(map[string]string, len(bundle))
+	for id, text := range bundle {
+		messages[id] = text
+	}
+	return messages
+}
+
+// normalize reduces a locale tag such as "es_ES.UTF-8" or "es-MX" to its
+// language ("es")
+func normalize(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, ".@"); i >= 0 {
+		tag = tag[:i]
+	}
+	if i := strings.IndexAny(tag, "_-"); i >= 0 {
+		tag = tag[:i]
+	}
+	return tag
+}
+
+// supported reports whether a normalized locale has an embedded bundle
+func supported(name string) bool {
+	all, _ := load()
+	_, ok := all[name]
+	return ok
+}
+
+// SetLocale selects the message locale (the --locale flag). An empty tag
+// clears the selection, so the SHIPYARD_LOCALE environment variable applies.
+func SetLocale(tag string) error {
+	if tag == "" {
+		locale = ""
+		return nil
+	}
+	name := normalize(tag)
+	if !supported(name) {
+		return fmt.Errorf("unsupported locale %q (available: %s)", tag, strings.Join(Locales(), ", "))
+	}
+	locale = name
+	return nil
+}
+
+// Locale returns the active locale, either from SetLocale (the --locale flag)
+// or the SHIPYARD_LOCALE environment variable. Unsupported values fall back to English.
+func Locale() string {
+	if locale != "" {
+		return locale
+	}
+	if name := normalize(os.Getenv(LocaleEnv)); name != "" && supported(name) {
+		return name
+	}
+	return DefaultLocale
+}
+
+// T returns the message with the given ID in the active locale, formatted with
+// args as by fmt.Sprintf. Messages missing from the locale fall back to English;
+// unknown IDs are returned as is so a missing entry is visible rather than blank.
+func T(id string, args ...any) string {
+	all, _ := load()
+	text, ok := all[Locale()][id]
+	if !ok {
+		text, ok = all[DefaultLocale][id]
+	}
+	if !ok {
+		text = id
+	}
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
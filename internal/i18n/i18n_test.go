@@ -0,0 +1,96 @@
+package i18n
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// useLocale selects a locale for the rest of the test
+func useLocale(t *testing.T, tag string) {
+	t.Helper()
+	require.NoError(t, SetLocale(tag))
+	t.Cleanup(func() { _ = SetLocale("") })
+}
+
+func TestT(t *testing.T) {
+	t.Setenv(LocaleEnv, "")
+
+	t.Run("English by default", func(t *testing.T) {
+		assert.Equal(t, "Created consignment: abc.md", T("add.created", "abc.md"))
+	})
+
+	t.Run("selected locale", func(t *testing.T) {
+		useLocale(t, "es")
+		assert.Equal(t, "Envío creado: abc.md", T("add.created", "abc.md"))
+	})
+
+	t.Run("missing translation falls back to English", func(t *testing.T) {
+		useLocale(t, "es")
+		all, err := load()
+		require.NoError(t, err)
+		saved := all["es"]["label.path"]
+		delete(all["es"], "label.path")
+		t.Cleanup(func() { all["es"]["label.path"] = saved })
+
+		assert.Equal(t, "Path", T("label.path"))
+	})
+
+	t.Run("unknown ID is returned as is", func(t *testing.T) {
+		assert.Equal(t, "no.such.message", T("no.such.message"))
+	})
+}
+
+func TestLocale(t *testing.T) {
+	tests := []struct {
+		name string
+		env  string
+		flag string
+		want string
+	}{
+		{name: "default", want: "en"},
+		{name: "env", env: "es", want: "es"},
+		{name: "env with region and encoding", env: "es_ES.UTF-8", want: "es"},
+		{name: "env with BCP 47 region", env: "es-MX", want: "es"},
+		{name: "unsupported env falls back to English", env: "fr_FR", want: "en"},
+		{name: "flag overrides env", env: "es", flag: "en", want: "en"},
+		{name: "flag", flag: "ES", want: "es"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(LocaleEnv, tt.env)
+			if tt.flag != "" {
+				useLocale(t, tt.flag)
+			}
+			assert.Equal(t, tt.want, Locale())
+		})
+	}
+}
+
+func TestSetLocale_Unsupported(t *testing.T) {
+	err := SetLocale("fr")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "en, es")
+	assert.Equal(t, DefaultLocale, Locale())
+}
+
+func TestBundles(t *testing.T) {
+	english := Messages(DefaultLocale)
+	require.NotEmpty(t, english)
+	assert.Equal(t, []string{"en", "es"}, Locales())
+
+	verbRe := regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+	for _, name := range Locales() {
+		for id, text := range Messages(name) {
+			source, ok := english[id]
+			if !assert.True(t, ok, "%s: %q has no English message", name, id) {
+				continue
+			}
+			// Translations must take the same arguments in the same order
+			assert.Equal(t, verbRe.FindAllString(source, -1), verbRe.FindAllString(text, -1), "%s: %q", name, id)
+		}
+	}
+}
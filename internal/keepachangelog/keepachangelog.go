@@ -0,0 +1,192 @@
+// Package keepachangelog reads a CHANGELOG.md written before shipyard was
+// adopted, in the Keep a Changelog style (https://keepachangelog.com) or the
+// close variant conventional-changelog writes, so its releases can be
+// imported as history.
+package keepachangelog
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/NatoNathan/shipyard/pkg/types"
+)
+
+// Release is one version of a changelog
+type Release struct {
+	Version string    // Bare version, without a leading v
+	Date    time.Time // Zero when the heading has no date
+	Yanked  bool      // Heading is marked [YANKED]
+	Changes []Change  // List items in file order; empty when Body is set
+	Body    string    // Release text kept as written when it is not sections of list items
+}
+
+// Change is one list item of a release
+type Change struct {
+	Section string           // Heading the item is listed under, e.g. Added; empty above any heading
+	Type    types.ChangeType // Best guess from Section
+	Summary string
+}
+
+var (
+	// headingRe matches a markdown heading of level 1 to 3
+	headingRe = regexp.MustCompile(`^(#{1,3})\s+(.*?)\s*#*\s*$`)
+
+	// versionHeadingRe matches a release heading's version, bracketed or
+	// linked as in "[1.2.0] - 2024-01-15" or "[1.2.0](https://...) (2024-01-15)"
+	versionHeadingRe = regexp.MustCompile(`^\[?v?(\d+\.\d+(?:\.\d+)?(?:-[0-9A-Za-z.-]+)?(?:\+[0-9A-Za-z.-]+)?)\]?(?:\([^)]*\))?(.*)$`)
+
+	// dateRe matches the release date following the version
+	dateRe = regexp.MustCompile(`\b(\d{4}-\d{2}-\d{2})\b`)
+
+	// itemRe matches a list item
+	itemRe = regexp.MustCompile(`^[-*+]\s+(.*)$`)
+
+	// ignoredLineRe matches link reference definitions, such as the compare
+	// links at the end of the file, and the anchors older conventional-changelog
+	// versions put above each heading
+	ignoredLineRe = regexp.MustCompile(`^(?:\[[^\]]+\]:\s+\S+.*|<a\s+(?:name|id)="[^"]*">\s*</a>)$`)
+)
+
+// Parse reads the releases of a changelog, newest first as the file lists
+// them. Text above the first release and an [Unreleased] section are left
+// out. A release whose text is anything but list items under headings is
+// kept whole in Body rather than rejected.
+func Parse(content []byte) ([]Release, error) {
+	text := strings.ReplaceAll(string(content), "\r\n", "\n")
+
+	var releases []Release
+	var body []string
+	current := -1 // index of the release body collects; -1 before the first or in Unreleased
+	finish := func() {
+		if current >= 0 {
+			readBody(&releases[current], body)
+		}
+		body = nil
+	}
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(text, "\n") {
+		heading := headingRe.FindStringSubmatch(line)
+		if heading == nil {
+			body = append(body, line)
+			continue
+		}
+		title := heading[2]
+		if strings.EqualFold(strings.Trim(title, "[]"), "unreleased") || strings.HasPrefix(strings.ToLower(title), "[unreleased]") {
+			finish()
+			current = -1
+			continue
+		}
+		release, ok := parseReleaseHeading(title)
+		if !ok {
+			body = append(body, line)
+			continue
+		}
+		if seen[release.Version] {
+			return nil, fmt.Errorf("version %s is listed twice", release.Version)
+		}
+		seen[release.Version] = true
+		finish()
+		releases = append(releases, release)
+		current = len(releases) - 1
+	}
+	finish()
+
+	if len(releases) == 0 {
+		return nil, fmt.Errorf("no versions found")
+	}
+	return releases, nil
+}
+
+// parseReleaseHeading reads the version, date and yanked marker of a release
+// heading. ok is false for a heading that does not start with a version.
+func parseReleaseHeading(title string) (Release, bool) {
+	match := versionHeadingRe.FindStringSubmatch(title)
+	if match == nil {
+		return Release{}, false
+	}
+	release := Release{Version: match[1]}
+	rest := match[2]
+	if date := dateRe.FindString(rest); date != "" {
+		if parsed, err := time.Parse("2006-01-02", date); err == nil {
+			release.Date = parsed
+		}
+	}
+	release.Yanked = strings.Contains(strings.ToUpper(rest), "[YANKED]")
+	return release, true
+}
+
+// readBody splits a release's lines into its changes, or keeps the text in
+// Body when it holds anything but section headings and list items
+func readBody(release *Release, lines []string) {
+	var kept []string
+	var changes []Change
+	section := ""
+	opaque := false
+	for _, line := range lines {
+		if ignoredLineRe.MatchString(strings.TrimSpace(line)) {
+			continue
+		}
+		kept = append(kept, line)
+		if opaque {
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+		case strings.HasPrefix(line, "#"):
+			section = headingText(line)
+		case itemRe.MatchString(line):
+			summary := itemRe.FindStringSubmatch(line)[1]
+			changes = append(changes, Change{Section: section, Type: SectionChangeType(section), Summary: summary})
+		case len(changes) > 0 && (strings.HasPrefix(line, "  ") || strings.HasPrefix(line, "\t")):
+			// Continuation of the previous item, such as a nested list
+			last := &changes[len(changes)-1]
+			last.Summary += "\n" + dedent(line)
+		default:
+			opaque = true
+		}
+	}
+
+	if opaque {
+		release.Body = strings.TrimSpace(strings.Join(kept, "\n"))
+		return
+	}
+	for i := range changes {
+		changes[i].Summary = strings.TrimSpace(changes[i].Summary)
+	}
+	release.Changes = changes
+}
+
+// headingText returns a heading's text without its markers
+func headingText(line string) string {
+	return strings.TrimSpace(strings.Trim(strings.TrimSpace(line), "#"))
+}
+
+// dedent removes the two spaces or tab that indent an item's continuation
+func dedent(line string) string {
+	if strings.HasPrefix(line, "\t") {
+		return line[1:]
+	}
+	return strings.TrimPrefix(line, "  ")
+}
+
+// SectionChangeType guesses the change type of the items under a changelog
+// heading: breaking changes and removals are major, additions, features and
+// other changes to behavior are minor, and fixes and anything else are patch
+func SectionChangeType(heading string) types.ChangeType {
+	name := strings.Join(strings.FieldsFunc(strings.ToLower(heading), func(r rune) bool {
+		return r < 'a' || r > 'z'
+	}), " ")
+	switch {
+	case strings.Contains(name, "breaking"), name == "removed", name == "removals":
+		return types.ChangeTypeMajor
+	}
+	switch name {
+	case "added", "features", "feature", "new features", "changed", "changes", "deprecated", "enhancements", "improvements":
+		return types.ChangeTypeMinor
+	}
+	return types.ChangeTypePatch
+}
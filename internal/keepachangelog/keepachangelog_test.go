@@ -0,0 +1,91 @@
+package keepachangelog
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/NatoNathan/shipyard/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "rewrite testdata/*.golden")
+
+// render prints each release's heading, changes and body for golden files
+func render(releases []Release) string {
+	var b strings.Builder
+	for _, r := range releases {
+		fmt.Fprintf(&b, "version: %s\n", r.Version)
+		if !r.Date.IsZero() {
+			fmt.Fprintf(&b, "date: %s\n", r.Date.Format("2006-01-02"))
+		}
+		if r.Yanked {
+			fmt.Fprintln(&b, "yanked: true")
+		}
+		for _, c := range r.Changes {
+			fmt.Fprintf(&b, "change: %s [%s]\n%s\n", c.Type, c.Section, c.Summary)
+		}
+		if r.Body != "" {
+			fmt.Fprintf(&b, "body:\n%s\n", r.Body)
+		}
+		fmt.Fprintln(&b)
+	}
+	return b.String()
+}
+
+func TestParse_Golden(t *testing.T) {
+	for _, name := range []string{"keepachangelog", "conventional-changelog"} {
+		t.Run(name, func(t *testing.T) {
+			content, err := os.ReadFile(filepath.Join("testdata", name+".md"))
+			require.NoError(t, err)
+			releases, err := Parse(content)
+			require.NoError(t, err)
+
+			golden := filepath.Join("testdata", name+".golden")
+			got := render(releases)
+			if *update {
+				require.NoError(t, os.WriteFile(golden, []byte(got), 0644))
+			}
+			want, err := os.ReadFile(golden)
+			require.NoError(t, err)
+			assert.Equal(t, string(want), got)
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	_, err := Parse([]byte("# Changelog\n\nNothing released yet.\n"))
+	assert.ErrorContains(t, err, "no versions found")
+
+	_, err = Parse([]byte("## [1.0.0]\n\n- One\n\n## [1.0.0]\n\n- Two\n"))
+	assert.ErrorContains(t, err, "version 1.0.0 is listed twice")
+}
+
+func TestParse_WindowsLineEndings(t *testing.T) {
+	releases, err := Parse([]byte("## [1.0.0] - 2024-01-02\r\n\r\n### Fixed\r\n\r\n- Crash on start\r\n"))
+	require.NoError(t, err)
+	require.Len(t, releases, 1)
+	assert.Equal(t, []Change{{Section: "Fixed", Type: types.ChangeTypePatch, Summary: "Crash on start"}}, releases[0].Changes)
+}
+
+func TestSectionChangeType(t *testing.T) {
+	tests := map[string]types.ChangeType{
+		"⚠ BREAKING CHANGES":       types.ChangeTypeMajor,
+		"Removed":                  types.ChangeTypeMajor,
+		"Added":                    types.ChangeTypeMinor,
+		"Features":                 types.ChangeTypeMinor,
+		"Deprecated":               types.ChangeTypeMinor,
+		"Fixed":                    types.ChangeTypePatch,
+		"Bug Fixes":                types.ChangeTypePatch,
+		"Security":                 types.ChangeTypePatch,
+		"Performance Improvements": types.ChangeTypePatch,
+		"":                         types.ChangeTypePatch,
+	}
+	for heading, want := range tests {
+		assert.Equal(t, want, SectionChangeType(heading), heading)
+	}
+}
@@ -1,10 +1,12 @@
 package logger
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -12,8 +14,9 @@ import (
 var globalLogger *Logger
 
 func init() {
-	// Initialize with default logger
-	globalLogger = New(os.Stdout, LevelInfo, false)
+	// Log lines go to stderr so stdout stays parseable for --json commands.
+	// Only warnings and errors are shown unless a command asks for more.
+	globalLogger = New(os.Stderr, LevelWarn, false)
 }
 
 // Level represents the logging level
@@ -58,11 +61,35 @@ func ParseLevel(s string) (Level, error) {
 	}
 }
 
+// Format is how log lines are written
+type Format int
+
+const (
+	// FormatText writes "[LEVEL] timestamp message" lines
+	FormatText Format = iota
+	// FormatJSON writes one JSON object per line with time, level and msg
+	FormatJSON
+)
+
+// ParseFormat parses a string into a Format
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "text":
+		return FormatText, nil
+	case "json":
+		return FormatJSON, nil
+	default:
+		return FormatText, fmt.Errorf("invalid log format: %s (expected text or json)", s)
+	}
+}
+
 // Logger provides structured logging with verbosity levels
 type Logger struct {
+	mu     sync.Mutex
 	writer io.Writer
 	level  Level
 	quiet  bool
+	format Format
 }
 
 // New creates a new Logger instance
@@ -104,10 +131,21 @@ func (l *Logger) Error(format string, args ...interface{}) {
 
 // log is the internal logging method
 func (l *Logger) log(level Level, format string, args ...interface{}) {
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
+	now := time.Now()
 	message := fmt.Sprintf(format, args...)
 
-	_, _ = fmt.Fprintf(l.writer, "[%s] %s %s\n", level.String(), timestamp, message)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.format == FormatJSON {
+		line, _ := json.Marshal(struct {
+			Time  string `json:"time"`
+			Level string `json:"level"`
+			Msg   string `json:"msg"`
+		}{now.Format(time.RFC3339), strings.ToLower(level.String()), message})
+		_, _ = fmt.Fprintf(l.writer, "%s\n", line)
+		return
+	}
+	_, _ = fmt.Fprintf(l.writer, "[%s] %s %s\n", level.String(), now.Format("2006-01-02 15:04:05"), message)
 }
 
 // SetLevel changes the logging level
@@ -115,6 +153,23 @@ func (l *Logger) SetLevel(level Level) {
 	l.level = level
 }
 
+// Level returns the current logging level
+func (l *Logger) Level() Level {
+	return l.level
+}
+
+// SetFormat changes how log lines are written
+func (l *Logger) SetFormat(format Format) {
+	l.format = format
+}
+
+// SetWriter changes where log lines are written
+func (l *Logger) SetWriter(w io.Writer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.writer = w
+}
+
 // SetQuiet enables or disables quiet mode
 func (l *Logger) SetQuiet(quiet bool) {
 	l.quiet = quiet
@@ -130,7 +185,6 @@ func SetGlobal(l *Logger) {
 	globalLogger = l
 }
 
-
 // Writer returns the underlying writer
 func (l *Logger) Writer() io.Writer {
 	return l.writer
@@ -2,9 +2,14 @@ package logger
 
 import (
 	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNew(t *testing.T) {
@@ -185,3 +190,47 @@ func TestLevel_String(t *testing.T) {
 		})
 	}
 }
+
+func TestLogger_JSONFormat(t *testing.T) {
+	var buf bytes.Buffer
+	log := New(&buf, LevelDebug, false)
+	log.SetFormat(FormatJSON)
+
+	log.Debug("cloning %s", "https://example.com/repo.git")
+	log.Warn("careful")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var entry struct {
+		Time  string `json:"time"`
+		Level string `json:"level"`
+		Msg   string `json:"msg"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+	assert.Equal(t, "debug", entry.Level)
+	assert.Equal(t, "cloning https://example.com/repo.git", entry.Msg)
+	_, err := time.Parse(time.RFC3339, entry.Time)
+	assert.NoError(t, err)
+
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &entry))
+	assert.Equal(t, "warn", entry.Level)
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("json")
+	require.NoError(t, err)
+	assert.Equal(t, FormatJSON, format)
+
+	format, err = ParseFormat("TEXT")
+	require.NoError(t, err)
+	assert.Equal(t, FormatText, format)
+
+	_, err = ParseFormat("xml")
+	assert.ErrorContains(t, err, "invalid log format: xml")
+}
+
+func TestGet_DefaultsToWarnOnStderr(t *testing.T) {
+	assert.Equal(t, LevelWarn, Get().Level())
+	assert.Equal(t, os.Stderr, Get().Writer())
+}
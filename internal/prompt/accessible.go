@@ -0,0 +1,200 @@
+package prompt
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/NatoNathan/shipyard/internal/i18n"
+)
+
+// AccessibleEnv enables accessible prompts when set to a truthy value
+const AccessibleEnv = "SHIPYARD_ACCESSIBLE"
+
+// Accessible prompts are plain sequential question/answer text with no live
+// redraws or cursor movement, so they work with screen readers and pipes.
+var (
+	accessible    bool
+	accessibleIn            = bufio.NewReader(os.Stdin)
+	accessibleOut io.Writer = os.Stdout
+)
+
+// SetAccessible enables or disables accessible prompts
+func SetAccessible(enabled bool) {
+	accessible = enabled
+}
+
+// Accessible reports whether prompts run in accessible mode, either from
+// SetAccessible (the --accessible flag) or the SHIPYARD_ACCESSIBLE environment variable
+func Accessible() bool {
+	if accessible {
+		return true
+	}
+	enabled, err := strconv.ParseBool(os.Getenv(AccessibleEnv))
+	return err == nil && enabled
+}
+
+// SetAccessibleIO sets where accessible prompts read answers and write questions
+func SetAccessibleIO(in io.Reader, out io.Writer) {
+	accessibleIn = bufio.NewReader(in)
+	accessibleOut = out
+}
+
+// AccessibleIO returns the reader and writer accessible prompts use, so other
+// prompt libraries can share them
+func AccessibleIO() (io.Reader, io.Writer) {
+	return accessibleIn, accessibleOut
+}
+
+// readLine reads one answer line. EOF before any input cancels the prompt.
+func readLine() (string, error) {
+	line, err := accessibleIn.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		if err == io.EOF {
+			return "", fmt.Errorf("cancelled: no input")
+		}
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+func say(format string, args ...interface{}) {
+	_, _ = fmt.Fprintf(accessibleOut, format, args...)
+}
+
+// accessibleConfirm asks a y/n question until it gets a valid answer
+func accessibleConfirm(message string, defaultYes bool) (bool, error) {
+	hint := "y/N"
+	if defaultYes {
+		hint = "Y/n"
+	}
+
+	for {
+		say("%s [%s]: ", message, hint)
+		answer, err := readLine()
+		if err != nil {
+			return false, err
+		}
+
+		switch strings.ToLower(answer) {
+		case "":
+			return defaultYes, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		say("%s\n", i18n.T("prompt.answer_yes_no"))
+	}
+}
+
+// accessibleSelectOne lists numbered options and asks for one number
+func accessibleSelectOne(title string, labels []string) (int, error) {
+	say("%s\n", title)
+	for i, label := range labels {
+		say("  %d. %s\n", i+1, label)
+	}
+
+	for {
+		say("%s ", i18n.T("prompt.enter_number", len(labels)))
+		answer, err := readLine()
+		if err != nil {
+			return 0, err
+		}
+
+		n, err := strconv.Atoi(answer)
+		if err == nil && n >= 1 && n <= len(labels) {
+			return n - 1, nil
+		}
+		say("%s.\n", i18n.T("prompt.not_in_range", answer, len(labels)))
+	}
+}
+
+// accessibleSelectMany lists numbered options and asks for a comma or space
+// separated list of numbers. An empty answer keeps the preselected options.
+func accessibleSelectMany(title string, labels []string, preselected []int) ([]int, error) {
+	say("%s\n", title)
+	for i, label := range labels {
+		say("  %d. %s\n", i+1, label)
+	}
+
+	for {
+		if len(preselected) > 0 {
+			say("%s ", i18n.T("prompt.enter_numbers_all"))
+		} else {
+			say("%s ", i18n.T("prompt.enter_numbers"))
+		}
+		answer, err := readLine()
+		if err != nil {
+			return nil, err
+		}
+
+		if answer == "" {
+			if len(preselected) > 0 {
+				return preselected, nil
+			}
+			say("%s\n", i18n.T("prompt.select_at_least_one"))
+			continue
+		}
+
+		indexes, err := parseSelection(answer, len(labels))
+		if err != nil {
+			say("%v.\n", err)
+			continue
+		}
+		return indexes, nil
+	}
+}
+
+// parseSelection parses "1, 3 4" into zero-based, de-duplicated indexes in entry order
+func parseSelection(answer string, count int) ([]int, error) {
+	fields := strings.FieldsFunc(answer, func(r rune) bool { return r == ',' || r == ' ' })
+	seen := make(map[int]bool, len(fields))
+	var indexes []int
+	for _, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 1 || n > count {
+			return nil, fmt.Errorf("%s", i18n.T("prompt.not_in_range", field, count))
+		}
+		if !seen[n-1] {
+			seen[n-1] = true
+			indexes = append(indexes, n-1)
+		}
+	}
+	return indexes, nil
+}
+
+// accessibleText asks for a single line of text
+func accessibleText(message, defaultValue string) (string, error) {
+	if defaultValue != "" {
+		say("%s [%s]: ", message, defaultValue)
+	} else {
+		say("%s ", message)
+	}
+
+	answer, err := readLine()
+	if err != nil {
+		return "", err
+	}
+	if answer == "" {
+		return defaultValue, nil
+	}
+	return answer, nil
+}
+
+// accessibleSummary asks for a change summary until it gets a non-empty one
+func accessibleSummary() (string, error) {
+	for {
+		summary, err := accessibleText(i18n.T("prompt.summary"), "")
+		if err != nil {
+			return "", err
+		}
+		if summary != "" {
+			return summary, nil
+		}
+		say("%s\n", i18n.T("prompt.summary_empty"))
+	}
+}
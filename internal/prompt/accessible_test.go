@@ -0,0 +1,188 @@
+package prompt
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// useAccessible enables accessible prompts with scripted input and returns
+// the captured output
+func useAccessible(t *testing.T, input string) *bytes.Buffer {
+	t.Helper()
+	var out bytes.Buffer
+	SetAccessible(true)
+	SetAccessibleIO(strings.NewReader(input), &out)
+	t.Cleanup(func() {
+		SetAccessible(false)
+		SetAccessibleIO(os.Stdin, os.Stdout)
+	})
+	return &out
+}
+
+func TestAccessible_Env(t *testing.T) {
+	t.Setenv(AccessibleEnv, "1")
+	assert.True(t, Accessible())
+
+	t.Setenv(AccessibleEnv, "false")
+	assert.False(t, Accessible())
+
+	t.Setenv(AccessibleEnv, "")
+	assert.False(t, Accessible())
+}
+
+func TestAccessible_Confirm(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		defaultYes bool
+		want       bool
+		golden     string
+	}{
+		{name: "yes", input: "y\n", want: true, golden: "Proceed? [y/N]: "},
+		{name: "no", input: "no\n", defaultYes: true, want: false, golden: "Proceed? [Y/n]: "},
+		{name: "empty uses default", input: "\n", defaultYes: true, want: true, golden: "Proceed? [Y/n]: "},
+		{
+			name:   "re-asks on invalid answer",
+			input:  "maybe\nY\n",
+			want:   true,
+			golden: "Proceed? [y/N]: Please answer y or n.\nProceed? [y/N]: ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := useAccessible(t, tt.input)
+
+			got, err := PromptConfirm("Proceed?", tt.defaultYes)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.golden, out.String())
+		})
+	}
+}
+
+func TestAccessible_Packages(t *testing.T) {
+	out := useAccessible(t, "4\n3, 1 3\n")
+
+	got, err := PromptForPackages([]string{"core", "api", "web"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"web", "core"}, got)
+	assert.Equal(t, `Select package(s) affected by this change:
+  1. core
+  2. api
+  3. web
+Enter numbers separated by commas: "4" is not a number between 1 and 3.
+Enter numbers separated by commas: `, out.String())
+}
+
+func TestAccessible_ChangeType(t *testing.T) {
+	out := useAccessible(t, "2\n")
+
+	got, err := PromptForChangeType()
+
+	require.NoError(t, err)
+	assert.Equal(t, types.ChangeTypeMinor, got)
+	assert.Equal(t, `Select change type:
+  1. patch - Backwards compatible bug fixes
+  2. minor - Backwards compatible new features
+  3. major - Breaking changes
+Enter a number (1-3): `, out.String())
+}
+
+func TestAccessible_RepoType(t *testing.T) {
+	out := useAccessible(t, "x\n2\n")
+
+	got, err := PromptRepoType()
+
+	require.NoError(t, err)
+	assert.Equal(t, RepoTypeMonorepo, got)
+	assert.Equal(t, `What type of repository is this?
+  1. Single repository - One package/project in this repository
+  2. Monorepo - Multiple packages/projects in this repository
+Enter a number (1-2): "x" is not a number between 1 and 2.
+Enter a number (1-2): `, out.String())
+}
+
+func TestAccessible_ReviewPackages(t *testing.T) {
+	packages := []config.Package{
+		{Name: "core", Path: "./core", Ecosystem: "go"},
+		{Name: "web", Path: "./web", Ecosystem: "npm"},
+	}
+
+	t.Run("empty keeps all", func(t *testing.T) {
+		out := useAccessible(t, "\n")
+
+		got, err := PromptReviewPackages(packages)
+
+		require.NoError(t, err)
+		assert.Equal(t, packages, got)
+		assert.Equal(t, `Detected packages - select which to include:
+  1. core (go) at ./core
+  2. web (npm) at ./web
+Enter numbers separated by commas (press Enter for all): `, out.String())
+	})
+
+	t.Run("numbers select a subset", func(t *testing.T) {
+		useAccessible(t, "2\n")
+
+		got, err := PromptReviewPackages(packages)
+
+		require.NoError(t, err)
+		assert.Equal(t, packages[1:], got)
+	})
+
+	t.Run("sources are listed", func(t *testing.T) {
+		out := useAccessible(t, "\n")
+
+		_, err := PromptReviewPackagesWithSources(packages, []string{"core/go.mod", "web/package.json"})
+
+		require.NoError(t, err)
+		assert.Contains(t, out.String(), "  1. core (go) at ./core from core/go.mod\n  2. web (npm) at ./web from web/package.json\n")
+	})
+}
+
+func TestAccessible_SelectOne(t *testing.T) {
+	out := useAccessible(t, "2\n")
+
+	got, err := PromptSelectOne("Several manifests found in ./chart - which package is it?",
+		[]string{"chart (helm) from chart/Chart.yaml", "chart-ui (npm) from chart/package.json"})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, got)
+	assert.Equal(t, `Several manifests found in ./chart - which package is it?
+  1. chart (helm) from chart/Chart.yaml
+  2. chart-ui (npm) from chart/package.json
+Enter a number (1-2): `, out.String())
+}
+
+func TestAccessible_TextAndSummary(t *testing.T) {
+	out := useAccessible(t, "\n\nFix the thing\n")
+
+	name, err := PromptTextInput("Package name:", "core")
+	require.NoError(t, err)
+	assert.Equal(t, "core", name)
+
+	summary, err := PromptSummary(t.TempDir())
+	require.NoError(t, err)
+	assert.Equal(t, "Fix the thing", summary)
+
+	assert.Equal(t, "Package name: [core]: Change summary: Summary cannot be empty.\nChange summary: ", out.String())
+}
+
+func TestAccessible_EOFCancels(t *testing.T) {
+	useAccessible(t, "")
+
+	_, err := PromptConfirm("Proceed?", true)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cancelled")
+}
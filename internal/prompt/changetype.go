@@ -3,8 +3,9 @@ package prompt
 import (
 	"fmt"
 
-	tea "github.com/charmbracelet/bubbletea"
+	"github.com/NatoNathan/shipyard/internal/i18n"
 	"github.com/NatoNathan/shipyard/pkg/types"
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 type changeTypeModel struct {
@@ -59,7 +60,7 @@ func (m changeTypeModel) View() string {
 		return ""
 	}
 
-	s := titleStyle.Render("Select change type:") + "\n\n"
+	s := titleStyle.Render(i18n.T("prompt.change_type")) + "\n\n"
 
 	for i, opt := range m.options {
 		cursor := "  "
@@ -71,7 +72,7 @@ func (m changeTypeModel) View() string {
 		}
 	}
 
-	s += "\n" + helpStyle.Render("↑/↓: navigate • enter: confirm • q: quit")
+	s += "\n" + helpStyle.Render(i18n.T("prompt.select_one_help"))
 
 	return s
 }
@@ -95,15 +96,27 @@ func PromptForChangeTypeFunc(inputFunc func() (types.ChangeType, error)) (types.
 		return selected, nil
 	}
 
-	// Interactive prompt using Bubble Tea
-	m := changeTypeModel{
-		options: []changeTypeOption{
-			{types.ChangeTypePatch, "patch", "Backwards compatible bug fixes"},
-			{types.ChangeTypeMinor, "minor", "Backwards compatible new features"},
-			{types.ChangeTypeMajor, "major", "Breaking changes"},
-		},
+	options := []changeTypeOption{
+		{types.ChangeTypePatch, "patch", i18n.T("prompt.change_type_patch")},
+		{types.ChangeTypeMinor, "minor", i18n.T("prompt.change_type_minor")},
+		{types.ChangeTypeMajor, "major", i18n.T("prompt.change_type_major")},
 	}
 
+	if Accessible() {
+		labels := make([]string, len(options))
+		for i, opt := range options {
+			labels[i] = fmt.Sprintf("%s - %s", opt.label, opt.description)
+		}
+		index, err := accessibleSelectOne(i18n.T("prompt.change_type"), labels)
+		if err != nil {
+			return "", err
+		}
+		return options[index].value, nil
+	}
+
+	// Interactive prompt using Bubble Tea
+	m := changeTypeModel{options: options}
+
 	p := tea.NewProgram(m)
 	finalModel, err := p.Run()
 	if err != nil {
@@ -2,8 +2,10 @@ package prompt
 
 import (
 	"fmt"
+	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mattn/go-isatty"
 )
 
 type confirmModel struct {
@@ -75,6 +77,11 @@ func (m confirmModel) View() string {
 	return s
 }
 
+// Interactive reports whether stdin is a terminal that can answer prompts
+func Interactive() bool {
+	return isatty.IsTerminal(os.Stdin.Fd())
+}
+
 // PromptConfirm prompts the user for yes/no confirmation
 func PromptConfirm(message string, defaultYes bool) (bool, error) {
 	return PromptConfirmFunc(message, defaultYes, nil)
@@ -87,6 +94,10 @@ func PromptConfirmFunc(message string, defaultYes bool, inputFunc func() (bool,
 		return inputFunc()
 	}
 
+	if Accessible() {
+		return accessibleConfirm(message, defaultYes)
+	}
+
 	// Interactive prompt using Bubble Tea
 	m := confirmModel{
 		message:  message,
@@ -0,0 +1,59 @@
+package prompt
+
+import (
+	"fmt"
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// PromptForConsignments prompts the user to pick consignments from the given
+// labels and returns the selected indexes in list order
+func PromptForConsignments(labels []string) ([]int, error) {
+	return PromptForConsignmentsFunc(labels, nil)
+}
+
+// PromptForConsignmentsFunc allows dependency injection for testing
+func PromptForConsignmentsFunc(labels []string, inputFunc func() ([]int, error)) ([]int, error) {
+	if len(labels) == 0 {
+		return nil, fmt.Errorf("no consignments available")
+	}
+
+	if inputFunc != nil {
+		return inputFunc()
+	}
+
+	if Accessible() {
+		indexes, err := accessibleSelectMany("Select consignments to squash:", labels, nil)
+		if err != nil {
+			return nil, err
+		}
+		sort.Ints(indexes)
+		return indexes, nil
+	}
+
+	m := packageModel{
+		title:    "Select consignments to squash:",
+		item:     "consignment",
+		packages: labels,
+		selected: make(map[int]bool),
+	}
+
+	p := tea.NewProgram(m)
+	finalModel, err := p.Run()
+	if err != nil {
+		return nil, fmt.Errorf("consignment selection failed: %w", err)
+	}
+
+	result := finalModel.(packageModel)
+	if result.err != nil {
+		return nil, result.err
+	}
+
+	indexes := make([]int, 0, len(result.selected))
+	for i := range result.selected {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	return indexes, nil
+}
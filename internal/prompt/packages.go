@@ -3,6 +3,7 @@ package prompt
 import (
 	"fmt"
 
+	"github.com/NatoNathan/shipyard/internal/i18n"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -15,6 +16,8 @@ var (
 )
 
 type packageModel struct {
+	title    string
+	item     string // noun used in the empty selection error
 	packages []string
 	selected map[int]bool
 	cursor   int
@@ -37,7 +40,7 @@ func (m packageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case "enter":
 			if len(m.selected) == 0 {
-				m.err = fmt.Errorf("must select at least one package")
+				m.err = fmt.Errorf("must select at least one %s", m.item)
 			}
 			m.done = true
 			return m, tea.Quit
@@ -69,7 +72,7 @@ func (m packageModel) View() string {
 		return ""
 	}
 
-	s := titleStyle.Render("Select package(s) affected by this change:") + "\n\n"
+	s := titleStyle.Render(m.title) + "\n\n"
 
 	for i, pkg := range m.packages {
 		cursor := "  "
@@ -85,7 +88,7 @@ func (m packageModel) View() string {
 		s += fmt.Sprintf("%s%s %s\n", cursor, checked, pkg)
 	}
 
-	s += "\n" + helpStyle.Render("space: select • enter: confirm • q: quit")
+	s += "\n" + helpStyle.Render(i18n.T("prompt.select_many_help"))
 
 	return s
 }
@@ -114,8 +117,22 @@ func PromptForPackagesFunc(available []string, inputFunc func() ([]string, error
 		return selected, nil
 	}
 
+	if Accessible() {
+		indexes, err := accessibleSelectMany(i18n.T("prompt.packages"), available, nil)
+		if err != nil {
+			return nil, err
+		}
+		selected := make([]string, len(indexes))
+		for i, index := range indexes {
+			selected[i] = available[index]
+		}
+		return selected, nil
+	}
+
 	// Interactive prompt using Bubble Tea
 	m := packageModel{
+		title:    i18n.T("prompt.packages"),
+		item:     "package",
 		packages: available,
 		selected: make(map[int]bool),
 	}
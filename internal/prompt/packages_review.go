@@ -9,6 +9,7 @@ import (
 
 type packageReviewModel struct {
 	packages []config.Package
+	labels   []string
 	selected map[int]bool
 	cursor   int
 	done     bool
@@ -64,7 +65,7 @@ func (m packageReviewModel) View() string {
 
 	s := titleStyle.Render("Detected packages - select which to include:") + "\n\n"
 
-	for i, pkg := range m.packages {
+	for i := range m.packages {
 		cursor := "  "
 		if m.cursor == i {
 			cursor = cursorStyle.Render("> ")
@@ -75,9 +76,7 @@ func (m packageReviewModel) View() string {
 			checked = selectedStyle.Render("[✓]")
 		}
 
-		// Show package info: name, ecosystem, path
-		info := fmt.Sprintf("%s (%s) at %s", pkg.Name, pkg.Ecosystem, pkg.Path)
-		s += fmt.Sprintf("%s%s %s\n", cursor, checked, info)
+		s += fmt.Sprintf("%s%s %s\n", cursor, checked, m.labels[i])
 	}
 
 	s += "\n" + helpStyle.Render("space: toggle • enter: confirm • q: quit")
@@ -90,8 +89,29 @@ func PromptReviewPackages(packages []config.Package) ([]config.Package, error) {
 	return PromptReviewPackagesFunc(packages, nil)
 }
 
+// PromptReviewPackagesWithSources prompts the user to review and select
+// detected packages, listing the manifest each was detected from. sources
+// parallels packages; an empty source is not shown.
+func PromptReviewPackagesWithSources(packages []config.Package, sources []string) ([]config.Package, error) {
+	return reviewPackages(packages, sources, nil)
+}
+
 // PromptReviewPackagesFunc allows dependency injection for testing
 func PromptReviewPackagesFunc(packages []config.Package, inputFunc func() ([]config.Package, error)) ([]config.Package, error) {
+	return reviewPackages(packages, nil, inputFunc)
+}
+
+// packageReviewLabel describes a package as name, ecosystem and path, and the
+// manifest it was detected from when known
+func packageReviewLabel(pkg config.Package, source string) string {
+	label := fmt.Sprintf("%s (%s) at %s", pkg.Name, pkg.Ecosystem, pkg.Path)
+	if source != "" {
+		label += " from " + source
+	}
+	return label
+}
+
+func reviewPackages(packages []config.Package, sources []string, inputFunc func() ([]config.Package, error)) ([]config.Package, error) {
 	// Validate packages
 	if len(packages) == 0 {
 		return nil, fmt.Errorf("no packages to review")
@@ -102,6 +122,31 @@ func PromptReviewPackagesFunc(packages []config.Package, inputFunc func() ([]con
 		return inputFunc()
 	}
 
+	labels := make([]string, len(packages))
+	for i, pkg := range packages {
+		source := ""
+		if i < len(sources) {
+			source = sources[i]
+		}
+		labels[i] = packageReviewLabel(pkg, source)
+	}
+
+	if Accessible() {
+		all := make([]int, len(packages))
+		for i := range packages {
+			all[i] = i
+		}
+		indexes, err := accessibleSelectMany("Detected packages - select which to include:", labels, all)
+		if err != nil {
+			return nil, err
+		}
+		selectedPackages := make([]config.Package, len(indexes))
+		for i, index := range indexes {
+			selectedPackages[i] = packages[index]
+		}
+		return selectedPackages, nil
+	}
+
 	// Interactive prompt using Bubble Tea
 	// Start with all packages selected by default
 	selected := make(map[int]bool)
@@ -111,6 +156,7 @@ func PromptReviewPackagesFunc(packages []config.Package, inputFunc func() ([]con
 
 	m := packageReviewModel{
 		packages: packages,
+		labels:   labels,
 		selected: selected,
 	}
 
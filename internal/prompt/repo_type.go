@@ -97,22 +97,34 @@ func PromptRepoTypeFunc(inputFunc func() (RepoType, error)) (RepoType, error) {
 		return inputFunc()
 	}
 
-	// Interactive prompt using Bubble Tea
-	m := repoTypeModel{
-		options: []repoTypeOption{
-			{
-				value:       RepoTypeSingle,
-				label:       "Single repository",
-				description: "One package/project in this repository",
-			},
-			{
-				value:       RepoTypeMonorepo,
-				label:       "Monorepo",
-				description: "Multiple packages/projects in this repository",
-			},
+	options := []repoTypeOption{
+		{
+			value:       RepoTypeSingle,
+			label:       "Single repository",
+			description: "One package/project in this repository",
+		},
+		{
+			value:       RepoTypeMonorepo,
+			label:       "Monorepo",
+			description: "Multiple packages/projects in this repository",
 		},
 	}
 
+	if Accessible() {
+		labels := make([]string, len(options))
+		for i, opt := range options {
+			labels[i] = fmt.Sprintf("%s - %s", opt.label, opt.description)
+		}
+		index, err := accessibleSelectOne("What type of repository is this?", labels)
+		if err != nil {
+			return "", err
+		}
+		return options[index].value, nil
+	}
+
+	// Interactive prompt using Bubble Tea
+	m := repoTypeModel{options: options}
+
 	p := tea.NewProgram(m)
 	finalModel, err := p.Run()
 	if err != nil {
@@ -0,0 +1,102 @@
+package prompt
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type selectModel struct {
+	title    string
+	labels   []string
+	cursor   int
+	selected int
+	done     bool
+	err      error
+}
+
+func (m selectModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m selectModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "q":
+			m.err = fmt.Errorf("cancelled")
+			m.done = true
+			return m, tea.Quit
+
+		case "enter":
+			m.selected = m.cursor
+			m.done = true
+			return m, tea.Quit
+
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+
+		case "down", "j":
+			if m.cursor < len(m.labels)-1 {
+				m.cursor++
+			}
+		}
+	}
+
+	return m, nil
+}
+
+func (m selectModel) View() string {
+	if m.done {
+		return ""
+	}
+
+	s := titleStyle.Render(m.title) + "\n\n"
+	for i, label := range m.labels {
+		if m.cursor == i {
+			s += fmt.Sprintf("%s%s\n", cursorStyle.Render("> "), selectedStyle.Render(label))
+		} else {
+			s += fmt.Sprintf("  %s\n", label)
+		}
+	}
+	s += "\n" + helpStyle.Render("↑/↓: navigate • enter: confirm • q: quit")
+
+	return s
+}
+
+// PromptSelectOne prompts the user to pick one of labels and returns its index
+func PromptSelectOne(title string, labels []string) (int, error) {
+	return PromptSelectOneFunc(title, labels, nil)
+}
+
+// PromptSelectOneFunc allows dependency injection for testing
+func PromptSelectOneFunc(title string, labels []string, inputFunc func() (int, error)) (int, error) {
+	if len(labels) == 0 {
+		return 0, fmt.Errorf("no options to select from")
+	}
+
+	// If inputFunc provided (for testing), use it
+	if inputFunc != nil {
+		return inputFunc()
+	}
+
+	if Accessible() {
+		return accessibleSelectOne(title, labels)
+	}
+
+	// Interactive prompt using Bubble Tea
+	p := tea.NewProgram(selectModel{title: title, labels: labels})
+	finalModel, err := p.Run()
+	if err != nil {
+		return 0, fmt.Errorf("selection failed: %w", err)
+	}
+
+	result := finalModel.(selectModel)
+	if result.err != nil {
+		return 0, result.err
+	}
+
+	return result.selected, nil
+}
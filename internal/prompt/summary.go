@@ -54,6 +54,10 @@ func PromptSummaryWithFunc(projectPath string, fn PromptSummaryFunc) (string, er
 
 // runSummaryPrompt runs the actual Bubble Tea program
 func runSummaryPrompt(projectPath string) (string, error) {
+	if Accessible() {
+		return accessibleSummary()
+	}
+
 	ta := textarea.New()
 	ta.Placeholder = "Enter summary (first line) and optional description..."
 	ta.SetWidth(80)
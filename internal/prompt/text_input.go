@@ -91,6 +91,10 @@ func PromptTextInputFunc(message, defaultValue string, inputFunc func() (string,
 		return value, nil
 	}
 
+	if Accessible() {
+		return accessibleText(message, defaultValue)
+	}
+
 	// Interactive prompt using Bubble Tea
 	m := textInputModel{
 		message:      message,
@@ -0,0 +1,253 @@
+// Package publish pushes released packages to external registries.
+package publish
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/fileutil"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gopkg.in/yaml.v3"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/memory"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/retry"
+)
+
+// Media types used by Helm for charts stored in OCI registries
+const (
+	HelmConfigMediaType       = "application/vnd.cncf.helm.config.v1+json"
+	HelmChartContentMediaType = "application/vnd.cncf.helm.chart.content.v1.tar.gz"
+)
+
+// HelmChart is a packaged chart ready to push
+type HelmChart struct {
+	Name    string
+	Version string
+	Config  []byte // Chart.yaml metadata as JSON
+	Archive []byte // gzipped chart tarball
+}
+
+// HelmPublisher pushes packaged charts to an OCI registry
+type HelmPublisher struct {
+	Registry  string // e.g. oci://ghcr.io/acme/charts
+	Username  string
+	Password  string
+	PlainHTTP bool // Use HTTP instead of HTTPS (local registries)
+}
+
+// PublishResult describes a chart pushed to a registry
+type PublishResult struct {
+	Reference string // e.g. ghcr.io/acme/charts/mychart:1.2.0
+	Digest    string // manifest digest
+}
+
+// PackageChart builds a chart archive from a chart directory, equivalent to
+// `helm package`. Files matching .helmignore are excluded and file times are
+// fixed so the same chart always produces the same archive.
+func PackageChart(chartDir string) (*HelmChart, error) {
+	chartYAML, err := fileutil.ReadFile(filepath.Join(chartDir, "Chart.yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Chart.yaml: %w", err)
+	}
+
+	var metadata map[string]interface{}
+	if err := yaml.Unmarshal(chartYAML, &metadata); err != nil {
+		return nil, fmt.Errorf("failed to parse Chart.yaml: %w", err)
+	}
+	name, _ := metadata["name"].(string)
+	version, _ := metadata["version"].(string)
+	if name == "" || version == "" {
+		return nil, fmt.Errorf("Chart.yaml must define name and version")
+	}
+
+	config, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode chart metadata: %w", err)
+	}
+
+	ignore, err := readHelmIgnore(chartDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	err = filepath.WalkDir(chartDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(chartDir, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		if ignore.matches(rel, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || !d.Type().IsRegular() {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		header := &tar.Header{
+			Name:    name + "/" + rel,
+			Mode:    0644,
+			Size:    int64(len(data)),
+			ModTime: time.Unix(0, 0).UTC(),
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		_, err = tw.Write(data)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to package chart %s: %w", name, err)
+	}
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to package chart %s: %w", name, err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to package chart %s: %w", name, err)
+	}
+
+	return &HelmChart{
+		Name:    name,
+		Version: version,
+		Config:  config,
+		Archive: buf.Bytes(),
+	}, nil
+}
+
+// Push uploads a packaged chart to the registry, tagged with the chart version,
+// and returns the manifest digest
+func (p *HelmPublisher) Push(ctx context.Context, chart *HelmChart) (*PublishResult, error) {
+	registry := strings.TrimSuffix(strings.TrimPrefix(p.Registry, "oci://"), "/")
+	if registry == "" || registry == p.Registry {
+		return nil, fmt.Errorf("helm registry must be an oci:// reference: %q", p.Registry)
+	}
+
+	repo, err := remote.NewRepository(registry + "/" + chart.Name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid helm registry %s: %w", p.Registry, err)
+	}
+	repo.PlainHTTP = p.PlainHTTP
+	client := &auth.Client{
+		Client: retry.DefaultClient,
+		Cache:  auth.NewCache(),
+	}
+	if p.Username != "" || p.Password != "" {
+		client.Credential = auth.StaticCredential(repo.Reference.Registry, auth.Credential{
+			Username: p.Username,
+			Password: p.Password,
+		})
+	}
+	repo.Client = client
+
+	store := memory.New()
+	configDesc := content.NewDescriptorFromBytes(HelmConfigMediaType, chart.Config)
+	if err := store.Push(ctx, configDesc, bytes.NewReader(chart.Config)); err != nil {
+		return nil, fmt.Errorf("failed to stage chart config: %w", err)
+	}
+	layerDesc := content.NewDescriptorFromBytes(HelmChartContentMediaType, chart.Archive)
+	if err := store.Push(ctx, layerDesc, bytes.NewReader(chart.Archive)); err != nil {
+		return nil, fmt.Errorf("failed to stage chart archive: %w", err)
+	}
+
+	manifestDesc, err := oras.PackManifest(ctx, store, oras.PackManifestVersion1_1, "", oras.PackManifestOptions{
+		ConfigDescriptor: &configDesc,
+		Layers:           []ocispec.Descriptor{layerDesc},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build chart manifest: %w", err)
+	}
+
+	// OCI tags cannot contain '+', so Helm stores build metadata with '_'
+	tag := strings.ReplaceAll(chart.Version, "+", "_")
+	if err := store.Tag(ctx, manifestDesc, tag); err != nil {
+		return nil, fmt.Errorf("failed to tag chart manifest: %w", err)
+	}
+
+	pushed, err := oras.Copy(ctx, store, tag, repo, tag, oras.DefaultCopyOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to push %s:%s: %w", chart.Name, tag, err)
+	}
+
+	return &PublishResult{
+		Reference: fmt.Sprintf("%s/%s:%s", registry, chart.Name, tag),
+		Digest:    pushed.Digest.String(),
+	}, nil
+}
+
+// helmIgnore holds .helmignore patterns
+type helmIgnore []string
+
+// readHelmIgnore loads .helmignore from the chart directory, if present
+func readHelmIgnore(chartDir string) (helmIgnore, error) {
+	ignore := helmIgnore{".git/", ".helmignore"}
+
+	file, err := os.Open(filepath.Join(chartDir, ".helmignore"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ignore, nil
+		}
+		return nil, fmt.Errorf("failed to read .helmignore: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		ignore = append(ignore, line)
+	}
+	return ignore, scanner.Err()
+}
+
+// matches reports whether a slash-separated path relative to the chart root is ignored.
+// Patterns ending in "/" only match directories; patterns without "/" match any base name.
+func (h helmIgnore) matches(rel string, isDir bool) bool {
+	base := rel[strings.LastIndex(rel, "/")+1:]
+	for _, pattern := range h {
+		dirOnly := strings.HasSuffix(pattern, "/")
+		pattern = strings.TrimSuffix(pattern, "/")
+		if dirOnly && !isDir {
+			continue
+		}
+
+		target := rel
+		if !strings.Contains(pattern, "/") {
+			target = base
+		}
+		if ok, _ := filepath.Match(pattern, target); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+package publish
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/NatoNathan/shipyard/internal/publish/publishtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeChart(t *testing.T, dir, version string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "templates"), 0755))
+	chartYAML := fmt.Sprintf("apiVersion: v2\nname: mychart\nversion: %s\nappVersion: \"1.0\"\n", version)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Chart.yaml"), []byte(chartYAML), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "values.yaml"), []byte("replicas: 1\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "templates", "deployment.yaml"), []byte("kind: Deployment\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.tmp"), []byte("scratch"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".helmignore"), []byte("# scratch files\n*.tmp\n"), 0644))
+}
+
+func archiveEntries(t *testing.T, archive []byte) []string {
+	t.Helper()
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+	var names []string
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		names = append(names, header.Name)
+	}
+	return names
+}
+
+func TestPackageChart(t *testing.T) {
+	dir := t.TempDir()
+	writeChart(t, dir, "1.2.0")
+
+	chart, err := PackageChart(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "mychart", chart.Name)
+	assert.Equal(t, "1.2.0", chart.Version)
+
+	var config map[string]interface{}
+	require.NoError(t, json.Unmarshal(chart.Config, &config))
+	assert.Equal(t, "mychart", config["name"])
+
+	assert.ElementsMatch(t, []string{
+		"mychart/Chart.yaml",
+		"mychart/templates/deployment.yaml",
+		"mychart/values.yaml",
+	}, archiveEntries(t, chart.Archive))
+
+	t.Run("reproducible", func(t *testing.T) {
+		again, err := PackageChart(dir)
+		require.NoError(t, err)
+		assert.Equal(t, chart.Archive, again.Archive)
+	})
+
+	t.Run("missing version", func(t *testing.T) {
+		bad := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(bad, "Chart.yaml"), []byte("name: mychart\n"), 0644))
+		_, err := PackageChart(bad)
+		assert.Error(t, err)
+	})
+}
+
+func TestHelmPublisher_Push(t *testing.T) {
+	registry := publishtest.NewRegistry()
+	registry.Username = "robot"
+	registry.Password = "secret"
+	server := httptest.NewServer(registry)
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	dir := t.TempDir()
+	writeChart(t, dir, "1.2.0+build.1")
+	chart, err := PackageChart(dir)
+	require.NoError(t, err)
+
+	publisher := &HelmPublisher{
+		Registry:  "oci://" + host + "/acme/charts",
+		Username:  "robot",
+		Password:  "secret",
+		PlainHTTP: true,
+	}
+	result, err := publisher.Push(context.Background(), chart)
+	require.NoError(t, err)
+
+	assert.Equal(t, host+"/acme/charts/mychart:1.2.0_build.1", result.Reference)
+
+	manifest, ok := registry.Manifest("acme/charts/mychart", "1.2.0_build.1")
+	require.True(t, ok, "manifest should be tagged with the chart version")
+	sum := sha256.Sum256(manifest)
+	assert.Equal(t, "sha256:"+hex.EncodeToString(sum[:]), result.Digest)
+
+	var parsed struct {
+		Config struct {
+			MediaType string `json:"mediaType"`
+		} `json:"config"`
+		Layers []struct {
+			MediaType string `json:"mediaType"`
+		} `json:"layers"`
+	}
+	require.NoError(t, json.Unmarshal(manifest, &parsed))
+	assert.Equal(t, HelmConfigMediaType, parsed.Config.MediaType)
+	require.Len(t, parsed.Layers, 1)
+	assert.Equal(t, HelmChartContentMediaType, parsed.Layers[0].MediaType)
+
+	t.Run("bad credentials", func(t *testing.T) {
+		bad := *publisher
+		bad.Password = "wrong"
+		_, err := bad.Push(context.Background(), chart)
+		assert.Error(t, err)
+	})
+
+	t.Run("non-oci registry", func(t *testing.T) {
+		bad := *publisher
+		bad.Registry = "https://" + host
+		_, err := bad.Push(context.Background(), chart)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,105 @@
+// Package publishtest provides an in-memory OCI registry for publish tests.
+package publishtest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Registry is a minimal in-memory OCI distribution registry for tests.
+// It supports the blob upload and manifest endpoints used when pushing.
+type Registry struct {
+	mu        sync.Mutex
+	blobs     map[string][]byte
+	manifests map[string][]byte // "<repo>:<tag or digest>" -> manifest
+	Username  string
+	Password  string
+}
+
+// NewRegistry returns an empty registry. Set Username and Password to require basic auth.
+func NewRegistry() *Registry {
+	return &Registry{blobs: map[string][]byte{}, manifests: map[string][]byte{}}
+}
+
+// Manifest returns the manifest stored for a repository and tag or digest
+func (s *Registry) Manifest(repo, reference string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.manifests[repo+":"+reference]
+	return data, ok
+}
+
+// ServeHTTP implements http.Handler
+func (s *Registry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.Username != "" {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != s.Username || pass != s.Password {
+			w.Header().Set("WWW-Authenticate", `Basic realm="stub"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/v2/")
+	switch {
+	case strings.Contains(path, "/blobs/uploads/"):
+		repo := path[:strings.Index(path, "/blobs/uploads/")]
+		if r.Method == http.MethodPost {
+			w.Header().Set("Location", "/v2/"+repo+"/blobs/uploads/session")
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		digest := r.URL.Query().Get("digest")
+		s.blobs[digest] = data
+		w.Header().Set("Docker-Content-Digest", digest)
+		w.WriteHeader(http.StatusCreated)
+	case strings.Contains(path, "/blobs/"):
+		digest := path[strings.LastIndex(path, "/")+1:]
+		data, ok := s.blobs[digest]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Length", fmt.Sprint(len(data)))
+		w.Header().Set("Docker-Content-Digest", digest)
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(data)
+		}
+	case strings.Contains(path, "/manifests/"):
+		idx := strings.Index(path, "/manifests/")
+		key := path[:idx] + ":" + path[idx+len("/manifests/"):]
+		if r.Method == http.MethodPut {
+			data, _ := io.ReadAll(r.Body)
+			sum := sha256.Sum256(data)
+			digest := "sha256:" + hex.EncodeToString(sum[:])
+			s.manifests[key] = data
+			s.manifests[path[:idx]+":"+digest] = data
+			w.Header().Set("Docker-Content-Digest", digest)
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		data, ok := s.manifests[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		sum := sha256.Sum256(data)
+		w.Header().Set("Content-Type", "application/vnd.oci.image.manifest.v1+json")
+		w.Header().Set("Content-Length", fmt.Sprint(len(data)))
+		w.Header().Set("Docker-Content-Digest", "sha256:"+hex.EncodeToString(sum[:]))
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(data)
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
@@ -0,0 +1,127 @@
+package release
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/consignment"
+	"github.com/NatoNathan/shipyard/internal/history"
+	"github.com/NatoNathan/shipyard/internal/template"
+)
+
+// HistoryConsignments converts consignments to their history form for a
+// package, using each consignment's wording for that package
+func HistoryConsignments(packageName string, consignments []*consignment.Consignment) []history.Consignment {
+	converted := make([]history.Consignment, len(consignments))
+	for i, c := range consignments {
+		converted[i] = history.Consignment{
+			ID:         c.ID,
+			Summary:    c.SummaryFor(packageName),
+			ChangeType: string(c.ChangeType),
+			Metadata:   c.Metadata,
+		}
+	}
+	return converted
+}
+
+// UnreleasedEntry returns a history entry without a version holding the
+// pending consignments that name the package. Changelog templates list it as
+// .Unreleased rather than as a release. ok is false when none name it.
+func UnreleasedEntry(packageName string, pending []*consignment.Consignment) (history.Entry, bool) {
+	pkgConsignments := ConsignmentsFor(pending, packageName)
+	if len(pkgConsignments) == 0 {
+		return history.Entry{}, false
+	}
+	return history.Entry{
+		Package:      packageName,
+		Timestamp:    time.Now(),
+		Consignments: HistoryConsignments(packageName, pkgConsignments),
+	}, true
+}
+
+// RenderChangelog renders a package's changelog from its history entries
+// with the package's configured template, or templateOverride when set
+func RenderChangelog(cfg *config.Config, settings Settings, pkg config.Package, entries []history.Entry, templateOverride string) (string, error) {
+	templateSource := cfg.ChangelogTemplateFor(pkg.Name)
+	if templateOverride != "" {
+		templateSource = templateOverride
+	}
+	content, err := template.RenderChangelogWithOptions(ChangelogEntriesFor(cfg, entries), templateSource, settings.RenderOptions())
+	if err != nil {
+		return "", fmt.Errorf("failed to generate changelog for %s: %w", pkg.Name, err)
+	}
+	return content, nil
+}
+
+// ChangelogPreview is a package's changelog as a release would write it
+type ChangelogPreview struct {
+	Package string
+	Path    string
+	Content string
+}
+
+// PreviewChangelogs renders the changelog of each package the plan releases
+// with the plan's history entries appended, as the changelogs phase of a run
+// would, without writing anything. With includeUnreleased the consignments
+// the plan leaves pending are listed under Unreleased.
+func PreviewChangelogs(projectPath string, cfg *config.Config, plan *Plan, templateOverride string, includeUnreleased bool) ([]ChangelogPreview, error) {
+	allEntries, err := plan.settings.History(filepath.Join(projectPath, cfg.HistoryPathFor(plan.Channel))).ReadWithArchives()
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read history for changelog generation: %w", err)
+	}
+
+	generator := NewGenerator(projectPath, cfg, plan.settings)
+	generator.SetChannel(plan.Channel)
+	for _, pkg := range plan.Packages {
+		bump, hasBump := plan.Bumps[pkg.Name]
+		if !hasBump {
+			continue
+		}
+		tagName, _, err := PackageTag(generator, cfg, pkg, plan.Consignments, bump.NewVersion)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate tag for package %s: %w", pkg.Name, err)
+		}
+		if entry, ok := plan.historyEntry(pkg.Name, tagName, nil); ok {
+			allEntries = append(allEntries, entry)
+		}
+	}
+
+	var unreleasedConsignments []*consignment.Consignment
+	if includeUnreleased {
+		released := make(map[string]bool, len(plan.Consignments))
+		for _, c := range plan.Consignments {
+			released[c.ID] = true
+		}
+		if unreleasedConsignments, err = pendingExcept(projectPath, cfg, released, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	var previews []ChangelogPreview
+	for _, pkg := range plan.Packages {
+		if _, hasBump := plan.Bumps[pkg.Name]; !hasBump {
+			continue
+		}
+		pkgEntries := history.FilterByPackage(allEntries, pkg.Name, pkg.Aliases...)
+		if len(pkgEntries) == 0 {
+			continue
+		}
+		if unreleased, ok := UnreleasedEntry(pkg.Name, unreleasedConsignments); ok {
+			pkgEntries = append(pkgEntries, unreleased)
+		}
+
+		content, err := RenderChangelog(cfg, plan.settings, pkg, pkgEntries, templateOverride)
+		if err != nil {
+			return nil, err
+		}
+		path, err := ChangelogPath(projectPath, pkg)
+		if err != nil {
+			return nil, err
+		}
+		previews = append(previews, ChangelogPreview{Package: pkg.Name, Path: path, Content: content})
+	}
+	return previews, nil
+}
@@ -0,0 +1,47 @@
+package release
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/consignment"
+	"github.com/NatoNathan/shipyard/internal/history"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderChangelog_PackageSummaries(t *testing.T) {
+	dir := t.TempDir()
+	content := "---\nid: search\ntimestamp: 2026-01-30T14:30:22Z\npackages: [api, cli, web]\nchangeType: minor\n---\n\n" +
+		"Add project search\n\n## api\n\nAdd the search endpoint\n\n## cli\n\nAdd the search flag\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "search.md"), []byte(content), 0644))
+	mixed, err := consignment.ReadConsignment(filepath.Join(dir, "search.md"))
+	require.NoError(t, err)
+	single := &consignment.Consignment{ID: "fix", Timestamp: mixed.Timestamp, Packages: []string{"api", "cli"}, ChangeType: "patch", Summary: "Fix crash on empty query"}
+
+	cfg := &config.Config{Packages: []config.Package{{Name: "api"}, {Name: "cli"}, {Name: "web"}}}
+	render := func(name string) string {
+		t.Helper()
+		entry := history.Entry{Package: name, Version: "1.1.0", Timestamp: mixed.Timestamp, Consignments: HistoryConsignments(name, []*consignment.Consignment{mixed, single})}
+		pkg, _ := cfg.GetPackage(name)
+		changelog, err := RenderChangelog(cfg, Settings{}, pkg, []history.Entry{entry}, "")
+		require.NoError(t, err)
+		return changelog
+	}
+
+	api := render("api")
+	assert.Contains(t, api, "Add the search endpoint")
+	assert.NotContains(t, api, "Add the search flag")
+	assert.NotContains(t, api, "Add project search")
+	assert.Contains(t, api, "Fix crash on empty query", "single-body consignments render as before")
+
+	cli := render("cli")
+	assert.Contains(t, cli, "Add the search flag")
+	assert.NotContains(t, cli, "Add the search endpoint")
+
+	web := render("web")
+	assert.Contains(t, web, "Add project search", "packages without a section use the shared summary")
+	assert.NotContains(t, web, "search endpoint")
+}
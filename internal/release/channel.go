@@ -0,0 +1,56 @@
+package release
+
+import (
+	"strings"
+
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/errors"
+	"github.com/NatoNathan/shipyard/internal/git"
+	"github.com/NatoNathan/shipyard/internal/i18n"
+)
+
+// ResolveChannel returns the release channel for a run: the requested one,
+// otherwise the channel mapped to the checked-out branch, otherwise stable.
+// Without configured channels and no request it returns "", so releases
+// keep writing history.path as before.
+func ResolveChannel(projectPath string, cfg *config.Config, requested string) (string, error) {
+	if requested != "" {
+		if !cfg.HasChannel(requested) {
+			return "", errors.NewValidationError("channel", i18n.T("version.channel_unknown", requested, strings.Join(channelNames(cfg), ", ")))
+		}
+		return requested, nil
+	}
+	if len(cfg.Channels) == 0 {
+		return "", nil
+	}
+	if isRepo, _ := git.IsRepository(projectPath); isRepo {
+		branch, err := git.CurrentBranch(projectPath)
+		if err != nil {
+			return "", err
+		}
+		if channel := cfg.ChannelForBranch(branch); channel != "" {
+			return channel, nil
+		}
+	}
+	return config.StableChannel, nil
+}
+
+// ChannelPrereleaseID returns the pre-release identifier a channel releases
+// with, or "" for the stable channel
+func ChannelPrereleaseID(channel string) string {
+	if channel == "" || channel == config.StableChannel {
+		return ""
+	}
+	return channel
+}
+
+// channelNames returns stable followed by the configured channels
+func channelNames(cfg *config.Config) []string {
+	names := []string{config.StableChannel}
+	for _, channel := range cfg.Channels {
+		if channel.Name != config.StableChannel {
+			names = append(names, channel.Name)
+		}
+	}
+	return names
+}
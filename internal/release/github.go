@@ -0,0 +1,140 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/git"
+	"github.com/NatoNathan/shipyard/internal/github"
+	"github.com/NatoNathan/shipyard/internal/history"
+	"github.com/NatoNathan/shipyard/internal/httpclient"
+	"github.com/NatoNathan/shipyard/internal/runstate"
+	"github.com/NatoNathan/shipyard/internal/template"
+	"github.com/NatoNathan/shipyard/internal/upgrade"
+)
+
+// NewGitHubReleaseAPI creates the client GitHub releases are published with,
+// connecting with the project's certificate settings
+var NewGitHubReleaseAPI = func(token string, tls httpclient.TLSOptions) github.ReleaseAPI {
+	client := upgrade.NewGitHubClientFor("", token)
+	client.SetTransport(httpclient.TransportFor(tls))
+	return client
+}
+
+// gitHubTarget is the repository releases are published to and the token
+// that authorizes it
+type gitHubTarget struct {
+	Owner string
+	Repo  string
+	Token string
+}
+
+// resolveGitHubTarget reads github.owner and github.repo, inferring them from
+// the release remote's URL when unset, and the token named by github.token
+// (env:VAR) or GITHUB_TOKEN
+func resolveGitHubTarget(projectPath string, cfg *config.Config) (*gitHubTarget, error) {
+	target := &gitHubTarget{Owner: cfg.GitHub.Owner, Repo: cfg.GitHub.Repo}
+	if target.Owner == "" || target.Repo == "" {
+		url, err := git.RemoteURL(projectPath, Remote(cfg))
+		if err != nil {
+			return nil, fmt.Errorf("set github.owner and github.repo or add a GitHub remote: %w", err)
+		}
+		owner, repo, err := github.ParseRemoteURL(url)
+		if err != nil {
+			return nil, fmt.Errorf("set github.owner and github.repo: %w", err)
+		}
+		if target.Owner == "" {
+			target.Owner = owner
+		}
+		if target.Repo == "" {
+			target.Repo = repo
+		}
+	}
+
+	tokenEnv := git.GitHubTokenEnv
+	if name, ok := strings.CutPrefix(cfg.GitHub.Token, "env:"); ok && name != "" {
+		tokenEnv = name
+	}
+	target.Token = os.Getenv(tokenEnv)
+	if target.Token == "" {
+		return nil, fmt.Errorf("%s is not set; a token is required to publish GitHub releases", tokenEnv)
+	}
+	return target, nil
+}
+
+// GitHubReleaseResult records the outcome of publishing one tag's release
+type GitHubReleaseResult struct {
+	Tag     string
+	URL     string
+	Created bool
+	Err     error
+}
+
+// publishGitHubReleases creates or updates a GitHub release for every tag of
+// a run started with GitHubRelease, with notes rendered from the history
+// entries the run recorded. The release is complete and pushed at this
+// point, so failures are returned per tag, or as skipped when no release
+// could be published at all.
+func publishGitHubReleases(ctx context.Context, projectPath string, cfg *config.Config, settings Settings, run *runstate.Run) (results []GitHubReleaseResult, skipped error) {
+	if !run.Options.GitHubRelease || run.Options.NoTag {
+		return nil, nil
+	}
+
+	target, err := resolveGitHubTarget(projectPath, cfg)
+	if err != nil {
+		return nil, err
+	}
+	api := NewGitHubReleaseAPI(target.Token, settings.Remote.TLS)
+
+	for _, tag := range run.Tags {
+		result := GitHubReleaseResult{Tag: tag.Name}
+		result.URL, result.Created, result.Err = publishGitHubRelease(ctx, api, target, cfg, settings, run, tag)
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// publishGitHubRelease upserts the release of one tag, returning its URL
+func publishGitHubRelease(ctx context.Context, api github.ReleaseAPI, target *gitHubTarget, cfg *config.Config, settings Settings, run *runstate.Run, tag runstate.Tag) (string, bool, error) {
+	var entry *history.Entry
+	for i := range run.History {
+		if run.History[i].Package == tag.Package {
+			entry = &run.History[i]
+			break
+		}
+	}
+	if entry == nil {
+		return "", false, fmt.Errorf("no history entry recorded for %s", tag.Package)
+	}
+	pkg, ok := cfg.GetPackage(tag.Package)
+	if !ok {
+		return "", false, fmt.Errorf("package %s is not in the config", tag.Package)
+	}
+	version, err := pkg.ParseVersion(entry.Version)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to parse version %s: %w", entry.Version, err)
+	}
+
+	notesTemplate := "builtin:default"
+	if cfg.Templates.ReleaseNotes != nil && cfg.Templates.ReleaseNotes.Source != "" {
+		notesTemplate = cfg.Templates.ReleaseNotes.Source
+	}
+	notes, err := template.RenderReleaseNotesWithOptions(ChangelogEntriesFor(cfg, []history.Entry{*entry}), notesTemplate, settings.RenderOptions())
+	if err != nil {
+		return "", false, fmt.Errorf("failed to render release notes: %w", err)
+	}
+
+	request := github.NewReleaseRequest(tag.Package, version, tag.Name, notes, run.Options.Draft, version.PreRelease != "")
+	info, created, err := github.UpsertRelease(ctx, api, target.Owner, target.Repo, request)
+	if err != nil {
+		return "", false, err
+	}
+	url := info.URL
+	if url == "" {
+		url = fmt.Sprintf("https://github.com/%s/%s/releases/tag/%s", target.Owner, target.Repo, tag.Name)
+	}
+	return url, created, nil
+}
@@ -0,0 +1,99 @@
+package release
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/github"
+	"github.com/NatoNathan/shipyard/internal/history"
+	"github.com/NatoNathan/shipyard/internal/httpclient"
+	"github.com/NatoNathan/shipyard/internal/runstate"
+	"github.com/NatoNathan/shipyard/internal/upgrade"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeReleaseAPI keeps releases in memory, keyed by tag
+type fakeReleaseAPI struct {
+	releases map[string]*upgrade.ReleaseInfo
+	created  int
+	updated  int
+}
+
+func (f *fakeReleaseAPI) GetLatestRelease(ctx context.Context, owner, repo string) (*upgrade.ReleaseInfo, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *fakeReleaseAPI) GetReleaseByTag(ctx context.Context, owner, repo, tag string) (*upgrade.ReleaseInfo, error) {
+	return f.releases[tag], nil
+}
+
+func (f *fakeReleaseAPI) CreateRelease(ctx context.Context, owner, repo string, release *upgrade.CreateReleaseRequest) (*upgrade.ReleaseInfo, error) {
+	f.created++
+	info := &upgrade.ReleaseInfo{ID: int64(len(f.releases) + 1), TagName: release.TagName, Name: release.Name, Body: release.Body, Draft: release.Draft}
+	f.releases[release.TagName] = info
+	return info, nil
+}
+
+func (f *fakeReleaseAPI) UpdateRelease(ctx context.Context, owner, repo string, id int64, release *upgrade.CreateReleaseRequest) (*upgrade.ReleaseInfo, error) {
+	f.updated++
+	info := &upgrade.ReleaseInfo{ID: id, TagName: release.TagName, Name: release.Name, Body: release.Body, Draft: release.Draft}
+	f.releases[release.TagName] = info
+	return info, nil
+}
+
+func TestPublishGitHubReleases_UpdatesExistingRelease(t *testing.T) {
+	fake := &fakeReleaseAPI{releases: map[string]*upgrade.ReleaseInfo{
+		"v1.1.0": {ID: 7, TagName: "v1.1.0", Body: "stale"},
+	}}
+	original := NewGitHubReleaseAPI
+	NewGitHubReleaseAPI = func(string, httpclient.TLSOptions) github.ReleaseAPI { return fake }
+	t.Cleanup(func() { NewGitHubReleaseAPI = original })
+	t.Setenv("GITHUB_TOKEN", "token")
+
+	cfg := &config.Config{
+		Packages: []config.Package{{Name: "test-package", Path: "."}},
+		GitHub:   config.GitHubConfig{Owner: "octo", Repo: "shipyard"},
+	}
+	run := &runstate.Run{
+		Options: runstate.Options{Push: true, GitHubRelease: true},
+		Tags:    []runstate.Tag{{Package: "test-package", Name: "v1.1.0"}},
+		History: []history.Entry{{
+			Package:    "test-package",
+			Version:    "1.1.0",
+			ChangeType: "minor",
+			Tag:        "v1.1.0",
+			Timestamp:  time.Now(),
+			Consignments: []history.Consignment{
+				{ID: "c1", Summary: "Add feature", ChangeType: "minor"},
+			},
+		}},
+	}
+
+	results, skipped := publishGitHubReleases(context.Background(), t.TempDir(), cfg, Settings{}, run)
+	require.NoError(t, skipped)
+	require.Len(t, results, 1)
+	require.NoError(t, results[0].Err)
+	assert.False(t, results[0].Created)
+	assert.Equal(t, "https://github.com/octo/shipyard/releases/tag/v1.1.0", results[0].URL)
+	assert.Equal(t, 0, fake.created)
+	assert.Equal(t, 1, fake.updated)
+	assert.Contains(t, fake.releases["v1.1.0"].Body, "Add feature")
+}
+
+func TestPublishGitHubReleases_SkippedWithoutToken(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "")
+	cfg := &config.Config{GitHub: config.GitHubConfig{Owner: "octo", Repo: "shipyard"}}
+	run := &runstate.Run{
+		Options: runstate.Options{Push: true, GitHubRelease: true},
+		Tags:    []runstate.Tag{{Package: "test-package", Name: "v1.1.0"}},
+	}
+
+	results, skipped := publishGitHubReleases(context.Background(), t.TempDir(), cfg, Settings{}, run)
+	assert.Empty(t, results)
+	require.Error(t, skipped)
+	assert.Contains(t, skipped.Error(), "GITHUB_TOKEN")
+}
@@ -0,0 +1,530 @@
+package release
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/changelog"
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/consignment"
+	"github.com/NatoNathan/shipyard/internal/ecosystem"
+	"github.com/NatoNathan/shipyard/internal/fileutil"
+	"github.com/NatoNathan/shipyard/internal/git"
+	"github.com/NatoNathan/shipyard/internal/history"
+	"github.com/NatoNathan/shipyard/internal/template"
+	"github.com/NatoNathan/shipyard/internal/version"
+	"github.com/NatoNathan/shipyard/pkg/semver"
+	"golang.org/x/sync/errgroup"
+)
+
+// GetEcosystemHandler returns the appropriate ecosystem handler for a package
+func GetEcosystemHandler(pkg config.Package, pkgPath string) (ecosystem.Handler, error) {
+	return GetEcosystemHandlerWithContext(pkg, pkgPath, nil)
+}
+
+// GetEcosystemHandlerWithContext returns the appropriate ecosystem handler with optional context
+func GetEcosystemHandlerWithContext(pkg config.Package, pkgPath string, ctx *ecosystem.HandlerContext) (ecosystem.Handler, error) {
+	var handler ecosystem.Handler
+
+	switch pkg.Ecosystem {
+	case config.EcosystemGo:
+		if pkg.IsTagOnly() {
+			handler = ecosystem.NewGoEcosystemWithOptions(pkgPath, &ecosystem.GoEcosystemOptions{TagOnly: true})
+		} else {
+			handler = ecosystem.NewGoEcosystemWithOptions(pkgPath, &ecosystem.GoEcosystemOptions{VersionFiles: pkg.VersionFiles})
+		}
+	case config.EcosystemNPM:
+		handler = ecosystem.NewNPMEcosystem(pkgPath)
+	case config.EcosystemPython:
+		handler = ecosystem.NewPythonEcosystem(pkgPath)
+	case config.EcosystemHelm:
+		handler = ecosystem.NewHelmEcosystem(pkgPath)
+	case config.EcosystemCargo:
+		handler = ecosystem.NewCargoEcosystem(pkgPath)
+	case config.EcosystemDeno:
+		handler = ecosystem.NewDenoEcosystem(pkgPath)
+	case config.EcosystemDotnet:
+		handler = ecosystem.NewDotnetEcosystemWithOptions(pkgPath, &ecosystem.DotnetEcosystemOptions{
+			Manifest: pkg.GetDotnetOptions().Manifest,
+		})
+	case config.EcosystemMaven:
+		handler = ecosystem.NewMavenEcosystem(pkgPath)
+	default:
+		return nil, fmt.Errorf("unsupported ecosystem: %s", pkg.Ecosystem)
+	}
+
+	// Calendar-versioned packages read their versions with the CalVer format
+	if pkg.IsCalVer() {
+		cv, ok := handler.(ecosystem.CalVerAware)
+		if !ok {
+			return nil, fmt.Errorf("ecosystem %s does not support calver", pkg.Ecosystem)
+		}
+		cv.SetCalVerFormat(pkg.GetCalVerFormat())
+	}
+
+	// Set context if handler supports it and context is provided
+	if ctx != nil {
+		if hwc, ok := handler.(ecosystem.HandlerWithContext); ok {
+			hwc.SetContext(ctx)
+		}
+	}
+
+	// Tag-only packages of any ecosystem keep their manifests as they are
+	if pkg.IsTagOnly() {
+		handler = ecosystem.NewTagOnlyHandler(handler)
+	}
+
+	return handler, nil
+}
+
+// Settings are the config settings a project's history reads and writes,
+// template fetches and renders run with. They are resolved once per project
+// and passed along, so projects opened in one process never share them.
+type Settings struct {
+	LockTimeout time.Duration          // history.lockTimeout; zero waits history.DefaultLockTimeout
+	AllowHTML   bool                   // templates.allowHtml: render HTML in summaries as written
+	Remote      template.RemoteOptions // remote.cacheTTL, remote.auth and the certificate settings of remote fetches
+	Repository  *template.Repository   // Repository templates link into; nil links nowhere
+}
+
+// SettingsFor resolves the settings of the project at projectPath from cfg.
+// Durations were validated when the config loaded; unset ones keep the
+// defaults.
+func SettingsFor(projectPath string, cfg *config.Config) Settings {
+	timeout, _ := cfg.History.LockTimeoutDuration()
+	return Settings{
+		LockTimeout: timeout,
+		AllowHTML:   cfg.Templates.HTMLAllowed(),
+		Remote:      cfg.Remote.Options(projectPath, os.Getenv),
+		Repository:  RepositoryFor(projectPath, cfg),
+	}
+}
+
+// History returns the history file at path, locked with the configured timeout
+func (s Settings) History(path string) history.File {
+	return history.File{Path: path, LockTimeout: s.LockTimeout}
+}
+
+// RenderOptions returns the options templates are rendered with
+func (s Settings) RenderOptions() template.RenderOptions {
+	opts := template.DefaultRenderOptions()
+	opts.EscapeHTML = !s.AllowHTML
+	opts.Repository = s.Repository
+	opts.Remote = s.Remote
+	return opts
+}
+
+// RepositoryFor returns the repository templates link into: github.owner and
+// github.repo when set, otherwise the release remote, or nil when there is
+// neither or the remote is not a repository URL
+func RepositoryFor(projectPath string, cfg *config.Config) *template.Repository {
+	url := ""
+	if cfg.GitHub.Owner != "" && cfg.GitHub.Repo != "" {
+		url = "https://github.com/" + cfg.GitHub.Owner + "/" + cfg.GitHub.Repo
+	} else if remote, err := git.RemoteURL(projectPath, Remote(cfg)); err == nil {
+		url = remote
+	}
+	repo, err := template.ParseRepositoryURL(url)
+	if err != nil {
+		return nil
+	}
+	return &repo
+}
+
+// ReadAllCurrentVersions reads current versions for all configured packages.
+// Calendar-versioned packages use the later of their version file and their
+// latest archived release, so the next MICRO never reuses a released version.
+// Tag-only packages take their version from their latest matching git tag.
+func ReadAllCurrentVersions(projectPath string, cfg *config.Config, settings Settings) (map[string]semver.Version, error) {
+	return ReadChannelCurrentVersions(projectPath, cfg, settings, "")
+}
+
+// ReadChannelCurrentVersions reads current versions like
+// ReadAllCurrentVersions, consulting only the history of the given release
+// channel. History and tags are loaded once and shared; packages are read
+// concurrently, at most GOMAXPROCS at a time.
+func ReadChannelCurrentVersions(projectPath string, cfg *config.Config, settings Settings, channel string) (map[string]semver.Version, error) {
+	var entries []history.Entry
+	var tags []string
+	if slices.ContainsFunc(cfg.Packages, func(p config.Package) bool { return p.IsCalVer() }) {
+		var err error
+		entries, err = settings.History(filepath.Join(projectPath, cfg.HistoryPathFor(channel))).Read()
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read history: %w", err)
+		}
+	}
+	if slices.ContainsFunc(cfg.Packages, func(p config.Package) bool { return p.IsTagOnly() }) {
+		var err error
+		if tags, err = listRepositoryTags(projectPath); err != nil {
+			return nil, err
+		}
+	}
+
+	read := make([]semver.Version, len(cfg.Packages))
+	errs := make([]error, len(cfg.Packages))
+	var group errgroup.Group
+	group.SetLimit(runtime.GOMAXPROCS(0))
+	for i, pkg := range cfg.Packages {
+		group.Go(func() error {
+			read[i], errs[i] = readCurrentVersion(projectPath, cfg, settings, pkg, entries, tags)
+			return nil
+		})
+	}
+	_ = group.Wait()
+
+	// Errors are reported for the first failing package in config order, so
+	// the outcome never depends on scheduling
+	versions := make(map[string]semver.Version, len(cfg.Packages))
+	for i, pkg := range cfg.Packages {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		versions[pkg.Name] = read[i]
+	}
+	return versions, nil
+}
+
+// readCurrentVersion reads one package's current version from its version
+// file or tags, raised to its latest archived release for CalVer packages
+func readCurrentVersion(projectPath string, cfg *config.Config, settings Settings, pkg config.Package, entries []history.Entry, tags []string) (semver.Version, error) {
+	handler, err := GetEcosystemHandler(pkg, filepath.Join(projectPath, pkg.Path))
+	if err != nil {
+		return semver.Version{}, err
+	}
+	var ver semver.Version
+	if pkg.IsTagOnly() {
+		ver, err = tagOnlyVersion(projectPath, cfg, settings, pkg, handler, tags)
+	} else {
+		ver, err = handler.ReadVersion()
+	}
+	if err != nil {
+		return semver.Version{}, fmt.Errorf("failed to read version for %s: %w", pkg.Name, err)
+	}
+	if pkg.IsCalVer() {
+		ver = latestReleasedVersion(pkg, ver, entries)
+	}
+	return ver, nil
+}
+
+// tagVersionProbe is rendered through a package's tag template to find where
+// the version sits in its tag names
+var tagVersionProbe = semver.Version{Major: 987654321}
+
+// listRepositoryTags returns the project's tags, or none outside a git
+// repository
+func listRepositoryTags(projectPath string) ([]string, error) {
+	isRepo, err := git.IsRepository(projectPath)
+	if err != nil || !isRepo {
+		return nil, nil
+	}
+	return git.ListTags(projectPath)
+}
+
+// tagOnlyVersion returns the current version of a tag-only package: the
+// highest version among the tags its tag template produces, then a version
+// file if one exists, then the configured initialVersion. A package with
+// none of these has never been released and cannot be versioned.
+func tagOnlyVersion(projectPath string, cfg *config.Config, settings Settings, pkg config.Package, handler ecosystem.Handler, tags []string) (semver.Version, error) {
+	ver, found, err := latestTaggedVersion(projectPath, cfg, settings, pkg, tags)
+	if err != nil || found {
+		return ver, err
+	}
+	if ver, err := handler.ReadVersion(); err == nil {
+		return ver, nil
+	}
+	if pkg.InitialVersion != "" {
+		return pkg.ParseVersion(pkg.InitialVersion)
+	}
+	return semver.Version{}, fmt.Errorf("no release tag found; tag a release or set initialVersion")
+}
+
+// latestTaggedVersion returns the highest version among tags that match the
+// package's tag template. found is false when no tag matches.
+func latestTaggedVersion(projectPath string, cfg *config.Config, settings Settings, pkg config.Package, tags []string) (latest semver.Version, found bool, err error) {
+	if len(tags) == 0 {
+		return semver.Version{}, false, nil
+	}
+	generator := changelog.NewChangelogGenerator()
+	generator.SetBaseDir(projectPath)
+	generator.SetRenderOptions(settings.RenderOptions())
+	probeTag, _, err := PackageTag(generator, cfg, pkg, nil, tagVersionProbe)
+	if err != nil {
+		return semver.Version{}, false, fmt.Errorf("failed to render tag template: %w", err)
+	}
+	probe := tagVersionProbe.String()
+	i := strings.Index(probeTag, probe)
+	if i < 0 {
+		return semver.Version{}, false, fmt.Errorf("tag template does not include the version")
+	}
+	prefix, suffix := probeTag[:i], probeTag[i+len(probe):]
+
+	for _, tag := range tags {
+		if len(tag) <= len(prefix)+len(suffix) || !strings.HasPrefix(tag, prefix) || !strings.HasSuffix(tag, suffix) {
+			continue
+		}
+		v, err := pkg.ParseVersion(tag[len(prefix) : len(tag)-len(suffix)])
+		if err != nil {
+			continue
+		}
+		if !found || v.Compare(latest) > 0 {
+			latest, found = v, true
+		}
+	}
+	return latest, found, nil
+}
+
+// latestReleasedVersion returns the greater of current and the package's
+// archived versions. Entries in another scheme or format are ignored.
+func latestReleasedVersion(pkg config.Package, current semver.Version, entries []history.Entry) semver.Version {
+	latest := current
+	for _, entry := range history.FilterByPackage(entries, pkg.Name, pkg.Aliases...) {
+		v, err := pkg.ParseVersion(entry.Version)
+		if err != nil {
+			continue
+		}
+		if v.Compare(latest) > 0 {
+			latest = v
+		}
+	}
+	return latest
+}
+
+// PackageEntries returns the history entries of the named package, including
+// those recorded under its aliases when it is configured
+func PackageEntries(cfg *config.Config, entries []history.Entry, name string) []history.Entry {
+	if pkg, ok := cfg.GetPackage(name); ok {
+		return history.FilterByPackage(entries, pkg.Name, pkg.Aliases...)
+	}
+	return history.FilterByPackage(entries, name)
+}
+
+// NewVersionHandler creates the handler used to write new versions; tests replace it to observe writes
+var NewVersionHandler = GetEcosystemHandlerWithContext
+
+// OrderPackages returns the configured packages in the given apply order.
+// Packages missing from the order keep their config order at the end.
+func OrderPackages(cfg *config.Config, order [][]string) []config.Package {
+	byName := make(map[string]config.Package, len(cfg.Packages))
+	for _, pkg := range cfg.Packages {
+		byName[pkg.Name] = pkg
+	}
+
+	ordered := make([]config.Package, 0, len(cfg.Packages))
+	for _, group := range order {
+		for _, name := range group {
+			if pkg, ok := byName[name]; ok {
+				ordered = append(ordered, pkg)
+				delete(byName, name)
+			}
+		}
+	}
+	for _, pkg := range cfg.Packages {
+		if _, ok := byName[pkg.Name]; ok {
+			ordered = append(ordered, pkg)
+		}
+	}
+	return ordered
+}
+
+// FormatApplyOrder renders the apply order of the packages being released,
+// e.g. "core → api → [a, b]" where bracketed packages form a dependency cycle
+func FormatApplyOrder(order [][]string, versionBumps map[string]version.VersionBump) string {
+	var parts []string
+	for _, group := range order {
+		var released []string
+		for _, name := range group {
+			if _, ok := versionBumps[name]; ok {
+				released = append(released, name)
+			}
+		}
+		switch {
+		case len(released) == 0:
+			continue
+		case len(group) > 1:
+			parts = append(parts, "["+strings.Join(released, ", ")+"]")
+		default:
+			parts = append(parts, released[0])
+		}
+	}
+	return strings.Join(parts, " → ")
+}
+
+// CollectVersionFiles collects all version files that should be staged for the given packages
+func CollectVersionFiles(projectPath string, cfg *config.Config, packageNames map[string]bool) ([]string, error) {
+	return CollectPackageVersionFiles(projectPath, cfg.Packages, packageNames)
+}
+
+// CollectPackageVersionFiles collects version files for the selected packages, in the order given
+func CollectPackageVersionFiles(projectPath string, packages []config.Package, packageNames map[string]bool) ([]string, error) {
+	var files []string
+	for _, pkg := range packages {
+		if !packageNames[pkg.Name] {
+			continue
+		}
+		pkgPath := filepath.Join(projectPath, pkg.Path)
+		handler, err := GetEcosystemHandler(pkg, pkgPath)
+		if err != nil {
+			return nil, err
+		}
+		for _, vf := range handler.GetVersionFiles() {
+			files = append(files, filepath.Join(pkgPath, vf))
+		}
+		// Add changelog if it exists
+		changelogPath := filepath.Join(pkgPath, "CHANGELOG.md")
+		if _, err := os.Stat(changelogPath); err == nil {
+			files = append(files, changelogPath)
+		}
+	}
+	return files, nil
+}
+
+// PackageTag renders a package's tag name and message for version
+// from its tag template, falling back to the global template and then the
+// builtin default
+func PackageTag(generator *changelog.ChangelogGenerator, cfg *config.Config, pkg config.Package, consignments []*consignment.Consignment, version semver.Version) (string, string, error) {
+	tagTemplate := cfg.Templates.TagName
+	if pkg.Templates != nil && pkg.Templates.TagName != nil &&
+		(pkg.Templates.TagName.Inline != "" || pkg.Templates.TagName.Source != "") {
+		tagTemplate = pkg.Templates.TagName
+	}
+	switch {
+	case tagTemplate != nil && tagTemplate.Inline != "":
+		return generator.GeneratePackageTagWithContext(consignments, pkg.Name, version, tagTemplate.Inline)
+	case tagTemplate != nil && tagTemplate.Source != "":
+		return generator.GeneratePackageTag(consignments, pkg.Name, version, tagTemplate.Source)
+	default:
+		return generator.GeneratePackageTag(consignments, pkg.Name, version, "builtin:default")
+	}
+}
+
+// NewGenerator returns a changelog generator configured from cfg and the
+// project's settings for rendering a release's tags and commit message
+func NewGenerator(projectPath string, cfg *config.Config, settings Settings) *changelog.ChangelogGenerator {
+	generator := changelog.NewChangelogGenerator()
+	generator.SetBaseDir(projectPath)
+	generator.SetRenderOptions(settings.RenderOptions())
+	generator.SetMaxMessageBytes(cfg.Templates.MaxMessageBytes)
+	generator.SetPackageEcosystems(cfg.PackageEcosystems())
+	generator.SetChangeTypeAudiences(cfg.ChangeTypeAudiences())
+	generator.SetChangelogSections(cfg.ChangelogSections(""))
+	return generator
+}
+
+// CommitMessage renders the release commit message from the
+// configured commit template, or the builtin default
+func CommitMessage(generator *changelog.ChangelogGenerator, cfg *config.Config, consignments []*consignment.Consignment, versionBumps map[string]version.VersionBump) (string, error) {
+	source := "builtin:default"
+	if cfg.Templates.CommitMessage != nil && cfg.Templates.CommitMessage.Source != "" {
+		source = cfg.Templates.CommitMessage.Source
+	}
+
+	changelogBumps := make(map[string]changelog.VersionBump)
+	for name, bump := range versionBumps {
+		changelogBumps[name] = changelog.VersionBump{
+			Package:    bump.Package,
+			OldVersion: bump.OldVersion,
+			NewVersion: bump.NewVersion,
+			ChangeType: bump.ChangeType,
+		}
+	}
+	return generator.GenerateCommitMessage(consignments, changelogBumps, source)
+}
+
+// ManifestVersions maps a package's version files, relative to the
+// project root, to the version a release of it writes there
+func ManifestVersions(projectPath string, pkg config.Package, version string) (map[string]string, error) {
+	handler, err := GetEcosystemHandler(pkg, filepath.Join(projectPath, pkg.Path))
+	if err != nil {
+		return nil, err
+	}
+	versions := make(map[string]string)
+	for _, vf := range handler.GetVersionFiles() {
+		versions[filepath.ToSlash(filepath.Join(pkg.Path, vf))] = version
+	}
+	return versions, nil
+}
+
+// ChangelogPath returns the path of a package's changelog, rejecting
+// package paths (possibly from an extended remote config) that leave the project
+func ChangelogPath(projectPath string, pkg config.Package) (string, error) {
+	path, err := fileutil.WithinRoot(projectPath, filepath.Join(pkg.Path, "CHANGELOG.md"))
+	if err != nil {
+		return "", fmt.Errorf("invalid changelog path for %s: %w", pkg.Name, err)
+	}
+	return path, nil
+}
+
+// ChangelogEntriesFor applies each package's changelog exclusions to entries before
+// rendering, drops history notes unless the package renders them, and records the
+// package's ecosystem, repo shape and change-type audiences for templates. History itself is never
+// modified; excluded changes stay archived.
+func ChangelogEntriesFor(cfg *config.Config, entries []history.Entry) []history.Entry {
+	result := make([]history.Entry, len(entries))
+	for i, entry := range entries {
+		settings := cfg.ChangelogFor(entry.Package)
+		result[i] = template.ExcludeChangeTypes(entry, settings.ExcludeTypes, settings.Placeholder)
+		if settings.Notes {
+			result[i].NotesHeading = settings.NotesHeading
+		} else {
+			result[i].Notes = nil
+		}
+		if pkg, ok := cfg.GetPackage(entry.Package); ok {
+			result[i].Ecosystem = pkg.Ecosystem
+		}
+		result[i].IsMonorepo = cfg.IsMonorepo()
+		result[i].Audiences = cfg.ChangeTypeAudiences()
+		result[i].ChangelogSections = cfg.ChangelogSections(entry.Package)
+	}
+	return result
+}
+
+// RecordConfigSnapshot captures the effective configuration for a history entry.
+// The git blob hash of the local config file is included when it is committed at HEAD.
+func RecordConfigSnapshot(projectPath string, cfg *config.Config) (*history.ConfigSnapshot, error) {
+	resolved, err := cfg.Snapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &history.ConfigSnapshot{Hash: config.HashSnapshot(resolved)}
+	if cfg.History.EmbedConfig {
+		snapshot.Resolved = resolved
+	}
+
+	if configPath, err := config.FindConfigFile(projectPath); err == nil {
+		if rel, err := filepath.Rel(projectPath, configPath); err == nil {
+			snapshot.Path = filepath.ToSlash(rel)
+		}
+		if blobHash, err := git.BlobHashAtHead(projectPath, configPath); err == nil {
+			snapshot.BlobHash = blobHash
+		}
+	}
+
+	return snapshot, nil
+}
+
+// CheckMessageSize reports an error when a rendered commit message or tag
+// annotation is larger than maxBytes even after truncation. A non-positive
+// maxBytes disables the check.
+func CheckMessageSize(kind, message string, maxBytes int) error {
+	if maxBytes <= 0 || len(message) <= maxBytes {
+		return nil
+	}
+	return fmt.Errorf("%s is %d bytes, exceeding templates.maxMessageBytes (%d); raise the limit or shorten the template", kind, len(message), maxBytes)
+}
+
+// ConsignmentsFor returns the consignments that affect the given package
+func ConsignmentsFor(consignments []*consignment.Consignment, packageName string) []*consignment.Consignment {
+	var filtered []*consignment.Consignment
+	for _, c := range consignments {
+		if slices.Contains(c.Packages, packageName) {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered
+}
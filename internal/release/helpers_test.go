@@ -0,0 +1,141 @@
+package release
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/history"
+	"github.com/NatoNathan/shipyard/pkg/semver"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeMonorepoFixture writes a monorepo of n packages cycling through Go
+// version files, npm manifests, CalVer packages with newer archived releases
+// and tag-only packages, and returns its loaded configuration
+func writeMonorepoFixture(tb testing.TB, dir string, n int) *config.Config {
+	tb.Helper()
+	var configYAML strings.Builder
+	configYAML.WriteString("templates:\n  tagName:\n    inline: \"{{ .Package }}/v{{ .Version }}\"\npackages:\n")
+	var entries []history.Entry
+	var tags []string
+
+	for i := range n {
+		name := fmt.Sprintf("pkg-%03d", i)
+		pkgDir := filepath.Join(dir, "packages", name)
+		require.NoError(tb, os.MkdirAll(pkgDir, 0755))
+		fmt.Fprintf(&configYAML, "  - name: %s\n    path: ./packages/%s\n", name, name)
+
+		switch i % 4 {
+		case 0:
+			configYAML.WriteString("    ecosystem: go\n")
+			writeFixtureGoVersion(tb, pkgDir, fmt.Sprintf("1.%d.0", i))
+		case 1:
+			configYAML.WriteString("    ecosystem: npm\n")
+			manifest := fmt.Sprintf(`{"name": %q, "version": "0.%d.1"}`, name, i)
+			require.NoError(tb, os.WriteFile(filepath.Join(pkgDir, "package.json"), []byte(manifest), 0644))
+		case 2:
+			configYAML.WriteString("    ecosystem: go\n    versioningScheme: calver\n")
+			writeFixtureGoVersion(tb, pkgDir, "2024.06.0")
+			entries = append(entries, history.Entry{Package: name, Version: fmt.Sprintf("2024.06.%d", i), Timestamp: time.Now()})
+		case 3:
+			configYAML.WriteString("    ecosystem: go\n    versionFiles: [tag-only]\n")
+			require.NoError(tb, os.WriteFile(filepath.Join(pkgDir, "main.go"), []byte("package main\n"), 0644))
+			tags = append(tags, fmt.Sprintf("%s/v2.%d.0", name, i), fmt.Sprintf("%s/v2.0.%d", name, i))
+		}
+	}
+
+	shipyardDir := filepath.Join(dir, ".shipyard")
+	require.NoError(tb, os.MkdirAll(shipyardDir, 0755))
+	require.NoError(tb, os.WriteFile(filepath.Join(shipyardDir, "shipyard.yaml"), []byte(configYAML.String()), 0644))
+	data, err := json.Marshal(entries)
+	require.NoError(tb, err)
+	require.NoError(tb, os.WriteFile(filepath.Join(shipyardDir, "history.json"), data, 0644))
+
+	repo, err := gogit.PlainInit(dir, false)
+	require.NoError(tb, err)
+	wt, err := repo.Worktree()
+	require.NoError(tb, err)
+	_, err = wt.Add(".")
+	require.NoError(tb, err)
+	head, err := wt.Commit("initial commit", &gogit.CommitOptions{
+		Author: &object.Signature{Name: "Test", Email: "test@example.com"},
+	})
+	require.NoError(tb, err)
+	for _, tag := range tags {
+		_, err := repo.CreateTag(tag, head, nil)
+		require.NoError(tb, err)
+	}
+
+	cfg, err := config.LoadFromDir(dir)
+	require.NoError(tb, err)
+	return cfg
+}
+
+// writeFixtureGoVersion writes a version.go holding version
+func writeFixtureGoVersion(tb testing.TB, pkgDir, version string) {
+	tb.Helper()
+	content := "package main\n\nconst Version = \"" + version + "\"\n"
+	require.NoError(tb, os.WriteFile(filepath.Join(pkgDir, "version.go"), []byte(content), 0644))
+}
+
+func TestReadAllCurrentVersions_MatchesSerialReads(t *testing.T) {
+	dir := t.TempDir()
+	cfg := writeMonorepoFixture(t, dir, 100)
+
+	// Read every package one at a time, as the serial path did
+	entries, err := history.ReadHistory(filepath.Join(dir, cfg.History.Path))
+	require.NoError(t, err)
+	tags, err := listRepositoryTags(dir)
+	require.NoError(t, err)
+	expected := make(map[string]semver.Version, len(cfg.Packages))
+	for _, pkg := range cfg.Packages {
+		ver, err := readCurrentVersion(dir, cfg, Settings{}, pkg, entries, tags)
+		require.NoError(t, err, pkg.Name)
+		expected[pkg.Name] = ver
+	}
+
+	assert.Equal(t, "1.0.0", expected["pkg-000"].String())
+	assert.Equal(t, "0.1.1", expected["pkg-001"].String())
+	assert.Equal(t, "2024.06.2", expected["pkg-002"].String(), "CalVer packages use their latest archived release")
+	assert.Equal(t, "2.3.0", expected["pkg-003"].String(), "tag-only packages use their highest tag")
+
+	for range 5 {
+		versions, err := ReadAllCurrentVersions(dir, cfg, Settings{})
+		require.NoError(t, err)
+		assert.Equal(t, expected, versions)
+	}
+}
+
+func TestReadAllCurrentVersions_ReportsFirstFailingPackage(t *testing.T) {
+	dir := t.TempDir()
+	cfg := writeMonorepoFixture(t, dir, 40)
+	for _, name := range []string{"pkg-004", "pkg-020", "pkg-036"} {
+		require.NoError(t, os.Remove(filepath.Join(dir, "packages", name, "version.go")))
+	}
+
+	for range 5 {
+		_, err := ReadAllCurrentVersions(dir, cfg, Settings{})
+		assert.ErrorContains(t, err, "failed to read version for pkg-004")
+	}
+}
+
+func BenchmarkReadAllCurrentVersions(b *testing.B) {
+	dir := b.TempDir()
+	cfg := writeMonorepoFixture(b, dir, 100)
+
+	b.ResetTimer()
+	for b.Loop() {
+		if _, err := ReadAllCurrentVersions(dir, cfg, Settings{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
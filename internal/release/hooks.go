@@ -0,0 +1,125 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/git"
+	"github.com/NatoNathan/shipyard/internal/hooks"
+	"github.com/NatoNathan/shipyard/internal/i18n"
+	"github.com/NatoNathan/shipyard/internal/runstate"
+	"github.com/NatoNathan/shipyard/internal/version"
+)
+
+// runReleaseHooks runs the stage's hooks of each released package in apply
+// order, the global hooks before the package's own, streaming their output to
+// out. tags maps packages to the release tag they get, if any.
+func runReleaseHooks(projectPath string, cfg *config.Config, stage hooks.Stage, packages []config.Package, versionBumps map[string]version.VersionBump, tags map[string]string, out io.Writer) error {
+	for _, pkg := range packages {
+		bump, ok := versionBumps[pkg.Name]
+		if !ok {
+			continue
+		}
+		configured := cfg.HooksFor(pkg)
+		commands := configured.PreVersion
+		if stage == hooks.PostVersion {
+			commands = configured.PostVersion
+		}
+		if len(commands) == 0 {
+			continue
+		}
+
+		release := hooks.Release{
+			Package:    pkg.Name,
+			OldVersion: bump.OldVersion.String(),
+			NewVersion: bump.NewVersion.String(),
+			Tag:        tags[pkg.Name],
+			Dir:        filepath.Join(projectPath, pkg.Path),
+		}
+		if err := hooks.Run(context.Background(), stage, commands, release, out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasHooks reports whether any released package has hooks for the stage
+func hasHooks(cfg *config.Config, stage hooks.Stage, packages []config.Package) bool {
+	for _, pkg := range packages {
+		configured := cfg.HooksFor(pkg)
+		if (stage == hooks.PreVersion && len(configured.PreVersion) > 0) ||
+			(stage == hooks.PostVersion && len(configured.PostVersion) > 0) {
+			return true
+		}
+	}
+	return false
+}
+
+// runTags maps each package to the tag the run creates for it
+func runTags(run *runstate.Run) map[string]string {
+	tags := make(map[string]string, len(run.Tags))
+	if run.Options.NoTag || run.Options.NoCommit {
+		return tags
+	}
+	for _, tag := range run.Tags {
+		tags[tag.Package] = tag.Name
+	}
+	return tags
+}
+
+// runPostVersionHooks runs the postVersion hooks. Files the hooks change are
+// recorded, even when a hook fails, so the release commit includes them and
+// a rollback returns them to HEAD.
+func (r *runner) runPostVersionHooks() error {
+	if r.run.Options.NoHooks {
+		return nil
+	}
+	packages, err := r.releasePackages()
+	if err != nil {
+		return err
+	}
+	if !hasHooks(r.cfg, hooks.PostVersion, packages) {
+		return nil
+	}
+
+	isRepo, _ := git.IsRepository(r.projectPath)
+	var before []string
+	if isRepo {
+		if before, err = git.ChangedFiles(r.projectPath); err != nil {
+			return fmt.Errorf("failed to check files before hooks: %w", err)
+		}
+	}
+
+	hookErr := runReleaseHooks(r.projectPath, r.cfg, hooks.PostVersion, packages, r.versionBumps, runTags(r.run), r.hookOutput)
+	if !isRepo {
+		return hookErr
+	}
+
+	after, err := git.ChangedFiles(r.projectPath)
+	if err != nil {
+		if hookErr != nil {
+			return hookErr
+		}
+		return fmt.Errorf("failed to check files changed by hooks: %w", err)
+	}
+	for _, path := range after {
+		if !slices.Contains(before, path) && !slices.Contains(r.run.HookFiles, path) {
+			r.run.HookFiles = append(r.run.HookFiles, path)
+		}
+	}
+	if err := runstate.Write(runstate.Path(r.projectPath), r.run); err != nil && hookErr == nil {
+		return fmt.Errorf("failed to record run state: %w", err)
+	}
+	if hookErr != nil {
+		return hookErr
+	}
+
+	if len(r.run.HookFiles) > 0 {
+		r.report.detail(i18n.T("version.hook_files", len(r.run.HookFiles)))
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package release
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/NatoNathan/shipyard/internal/fileutil"
+	"github.com/NatoNathan/shipyard/internal/git"
+	"github.com/NatoNathan/shipyard/internal/history"
+	"github.com/NatoNathan/shipyard/internal/runstate"
+	"github.com/NatoNathan/shipyard/pkg/shipment"
+	"github.com/NatoNathan/shipyard/pkg/types"
+)
+
+// manifestPath resolves the manifest path against the project, so a resumed
+// run writes to the same file
+func manifestPath(projectPath, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(projectPath, path)
+}
+
+// newRunManifest describes the package versions a run released, with HEAD
+// as the release commit when the run committed
+func newRunManifest(projectPath string, run *runstate.Run) (*shipment.Manifest, error) {
+	commit := ""
+	if !run.Options.NoCommit {
+		head, err := git.HeadHash(projectPath)
+		if err != nil {
+			return nil, err
+		}
+		commit = head.String()
+	}
+
+	packages := make([]shipment.Package, 0, len(run.Bumps))
+	for _, bump := range run.Bumps {
+		pkg := shipment.Package{
+			Name:         bump.Package,
+			OldVersion:   bump.OldVersion,
+			NewVersion:   bump.NewVersion,
+			ChangeType:   types.ChangeType(bump.ChangeType),
+			Consignments: []shipment.Consignment{},
+		}
+		for _, entry := range run.History {
+			if entry.Package == bump.Package {
+				pkg = ManifestPackage(entry)
+			}
+		}
+		pkg.Tag = ""
+		if !run.Options.NoTag {
+			for _, tag := range run.Tags {
+				if tag.Package == bump.Package {
+					pkg.Tag = tag.Name
+				}
+			}
+		}
+		pkg.Commit = commit
+		packages = append(packages, pkg)
+	}
+	return shipment.New(packages), nil
+}
+
+// writeRunManifest writes the manifest of a finished run when it was
+// started with Manifest, returning the path written
+func writeRunManifest(projectPath string, run *runstate.Run) (string, error) {
+	if run.Options.Manifest == "" {
+		return "", nil
+	}
+	manifest, err := newRunManifest(projectPath, run)
+	if err != nil {
+		return "", fmt.Errorf("failed to write release manifest: %w", err)
+	}
+	data, err := manifest.Marshal()
+	if err != nil {
+		return "", fmt.Errorf("failed to write release manifest: %w", err)
+	}
+	if err := fileutil.WriteFile(run.Options.Manifest, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write release manifest: %w", err)
+	}
+	return run.Options.Manifest, nil
+}
+
+// ManifestPackage converts a history entry to its manifest package. Entries
+// written before the bump was recorded take the highest consignment change
+// type and leave the old version empty.
+func ManifestPackage(entry history.Entry) shipment.Package {
+	pkg := shipment.Package{
+		Name:         entry.Package,
+		OldVersion:   entry.PreviousVersion,
+		NewVersion:   entry.Version,
+		ChangeType:   types.ChangeType(entry.ChangeType),
+		Tag:          entry.Tag,
+		Yanked:       entry.Yanked,
+		Consignments: make([]shipment.Consignment, len(entry.Consignments)),
+	}
+	for i, c := range entry.Consignments {
+		changeType := types.ChangeType(c.ChangeType)
+		pkg.Consignments[i] = shipment.Consignment{
+			ID:         c.ID,
+			ChangeType: changeType,
+			Summary:    c.Summary,
+			Metadata:   c.Metadata,
+		}
+		if entry.ChangeType == "" && changeType.Priority() > pkg.ChangeType.Priority() {
+			pkg.ChangeType = changeType
+		}
+	}
+	return pkg
+}
@@ -0,0 +1,390 @@
+package release
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/consignment"
+	"github.com/NatoNathan/shipyard/internal/errors"
+	"github.com/NatoNathan/shipyard/internal/git"
+	"github.com/NatoNathan/shipyard/internal/graph"
+	"github.com/NatoNathan/shipyard/internal/history"
+	"github.com/NatoNathan/shipyard/internal/i18n"
+	"github.com/NatoNathan/shipyard/internal/version"
+	"github.com/NatoNathan/shipyard/pkg/semver"
+)
+
+// ScheduleNow is the clock used for release window checks; tests replace it
+var ScheduleNow = time.Now
+
+// ScheduleTimeFormat renders window times in the schedule's timezone
+const ScheduleTimeFormat = "Mon 2 Jan 2006 15:04 MST"
+
+// prereleaseIdentifierRe matches accepted pre-release identifiers
+var prereleaseIdentifierRe = regexp.MustCompile(`^[A-Za-z][0-9A-Za-z-]*$`)
+
+// PlanOptions selects what a release plan covers
+type PlanOptions struct {
+	Packages      []string // Only consignments naming these packages; all when empty
+	Consignments  []string // Only the consignments with these IDs; all when empty
+	Channel       string   // Release channel; the checked-out branch's when empty
+	Prerelease    string   // Cut the next pre-release with this identifier
+	IncludeFrozen bool     // Version packages marked frozen too
+
+	SetVersions map[string]string // Package -> version replacing the calculated one
+	Force       bool              // Allow SetVersions that do not move past the current and released versions
+}
+
+// Plan is the set of versions a release would write, calculated from the
+// pending consignments without changing anything
+type Plan struct {
+	Channel       string                         // Resolved release channel; "" without configured channels
+	Consignments  []*consignment.Consignment     // Consignments the release applies
+	Held          []*consignment.Consignment     // Consignments kept pending for frozen packages
+	Frozen        []string                       // Sorted frozen packages the release leaves unversioned
+	Current       map[string]semver.Version      // Current version of every package; nil when nothing is pending
+	Bumps         map[string]version.VersionBump // New version of each released package
+	Order         [][]string                     // Apply order; a group of several is a dependency cycle
+	Packages      []config.Package               // Configured packages in apply order
+	SetVersions   []string                       // Sorted packages whose version was set rather than calculated
+	BaseCommitSHA string                         // HEAD commit the release is cut from; "" outside a repository
+
+	graph    *graph.DependencyGraph
+	settings Settings // Project settings, kept for applying and previewing the plan
+}
+
+// CheckPrereleaseID reports an error for a pre-release identifier that does
+// not start with a letter or holds anything but letters, digits and hyphens
+func CheckPrereleaseID(id string) error {
+	if id != "" && !prereleaseIdentifierRe.MatchString(id) {
+		return errors.NewValidationError("prerelease", i18n.T("version.prerelease_invalid", id))
+	}
+	return nil
+}
+
+// NewPlan calculates the versions a release of the pending consignments
+// would write: consignments touching a frozen package are held back,
+// changes propagate through the dependency graph, and pending pre-releases
+// are promoted or the next pre-release is cut. When nothing is pending, no
+// versions are read and the plan releases nothing. Consignment files that
+// fail to parse are skipped and reported as warnings. The plan keeps
+// settings, so Apply and PreviewChangelogs use them too.
+func NewPlan(projectPath string, cfg *config.Config, settings Settings, opts PlanOptions, report Reporter) (*Plan, error) {
+	if err := CheckPrereleaseID(opts.Prerelease); err != nil {
+		return nil, err
+	}
+
+	// Each channel keeps its own history; channels other than stable
+	// release pre-releases named after the channel
+	channel, err := ResolveChannel(projectPath, cfg, opts.Channel)
+	if err != nil {
+		return nil, err
+	}
+	preReleaseID := opts.Prerelease
+	if id := ChannelPrereleaseID(channel); id != "" {
+		if opts.Prerelease != "" && opts.Prerelease != id {
+			return nil, errors.NewValidationError("prerelease", i18n.T("version.channel_prerelease", opts.Prerelease, channel))
+		}
+		preReleaseID = id
+	}
+
+	consignments, err := ReadConsignments(projectPath, cfg, nil, report)
+	if err != nil {
+		return nil, err
+	}
+	if len(opts.Consignments) > 0 {
+		consignments, err = selectConsignments(consignments, opts.Consignments, report)
+		if err != nil {
+			return nil, err
+		}
+	}
+	consignments = filterByPackages(consignments, opts.Packages)
+
+	// Consignments touching a frozen package stay pending until it is unfrozen
+	frozen := map[string]bool{}
+	if !opts.IncludeFrozen {
+		frozen = frozenPackages(cfg)
+	}
+	plan := &Plan{Channel: channel, settings: settings}
+	if head, err := git.HeadHash(projectPath); err == nil {
+		plan.BaseCommitSHA = head.String()
+	}
+	plan.Consignments, plan.Held = holdFrozenConsignments(consignments, frozen)
+	plan.Frozen = skippedFrozenPackages(plan.Held, frozen)
+
+	plan.graph, err = graph.BuildGraph(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build dependency graph: %w", err)
+	}
+	plan.Bumps = map[string]version.VersionBump{}
+	if len(plan.Consignments) == 0 {
+		if err := plan.setVersions(projectPath, cfg, opts); err != nil {
+			return nil, err
+		}
+		return plan, nil
+	}
+	plan.Current, err = ReadChannelCurrentVersions(projectPath, cfg, settings, channel)
+	if err != nil {
+		return nil, err
+	}
+
+	propagator, err := version.NewPropagator(plan.graph)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create propagator: %w", err)
+	}
+	plan.Bumps, err = propagator.Propagate(plan.Current, plan.Consignments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate version bumps: %w", err)
+	}
+
+	// A frozen package keeps its current version even when a dependency's
+	// release would propagate to it
+	for name := range plan.Bumps {
+		if frozen[name] {
+			delete(plan.Bumps, name)
+			plan.Frozen = appendSorted(plan.Frozen, name)
+		}
+	}
+
+	// Promote pending pre-releases, or cut the next pre-release when requested
+	if err := version.ResolveReleaseVersions(plan.Bumps, preReleaseID); err != nil {
+		return nil, fmt.Errorf("failed to calculate version bumps: %w", err)
+	}
+
+	// Versions set explicitly replace the calculated ones; dependents keep
+	// the bumps their dependencies' changes call for
+	if err := plan.setVersions(projectPath, cfg, opts); err != nil {
+		return nil, err
+	}
+
+	// Apply releases in dependency order so dependents always see their
+	// dependencies' final versions and file writes are deterministic
+	plan.Order, err = graph.ApplyOrder(plan.graph)
+	if err != nil {
+		return nil, fmt.Errorf("failed to order packages: %w", err)
+	}
+	plan.Packages = OrderPackages(cfg, plan.Order)
+	return plan, nil
+}
+
+// setVersions replaces the calculated new versions with the versions set in
+// opts. Only a package the plan releases can be set, and unless opts.Force
+// is set, the version must be above both its current version and the
+// highest version its history records.
+func (p *Plan) setVersions(projectPath string, cfg *config.Config, opts PlanOptions) error {
+	if len(opts.SetVersions) == 0 {
+		return nil
+	}
+	var entries []history.Entry
+	if !opts.Force {
+		var err error
+		entries, err = p.settings.History(filepath.Join(projectPath, cfg.HistoryPathFor(p.Channel))).Read()
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to read history: %w", err)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(opts.SetVersions)) {
+		pkg, ok := cfg.GetPackage(name)
+		if !ok {
+			return errors.NewValidationError("set-version", i18n.T("version.set_version_unknown", name))
+		}
+		v, err := pkg.ParseVersion(opts.SetVersions[name])
+		if err != nil {
+			return errors.NewValidationError("set-version", i18n.T("version.set_version_invalid", opts.SetVersions[name], name, err))
+		}
+		bump, ok := p.Bumps[name]
+		if !ok {
+			return errors.NewValidationError("set-version", i18n.T("version.set_version_not_released", name))
+		}
+		if !opts.Force {
+			floor := bump.OldVersion
+			for _, entry := range history.FilterByPackage(entries, name, pkg.Aliases...) {
+				if released, err := pkg.ParseVersion(entry.Version); err == nil && released.Compare(floor) > 0 {
+					floor = released
+				}
+			}
+			if v.Compare(floor) <= 0 {
+				return errors.NewValidationError("set-version", i18n.T("version.set_version_backwards", name, v, floor))
+			}
+		}
+		bump.NewVersion = v
+		p.Bumps[name] = bump
+		p.SetVersions = appendSorted(p.SetVersions, name)
+	}
+	return nil
+}
+
+// Explain reports why each package in the plan is released
+func (p *Plan) Explain() *version.Propagation {
+	return version.ExplainPropagation(p.graph, p.Current, p.Bumps, p.Consignments)
+}
+
+// ReadConsignments reads the pending consignments, only those naming one of
+// packages when any are given. Files that fail to parse are skipped and
+// reported as warnings.
+func ReadConsignments(projectPath string, cfg *config.Config, packages []string, report Reporter) ([]*consignment.Consignment, error) {
+	consignments, parseErrors, err := consignment.ReadAllConsignmentsWithErrors(filepath.Join(projectPath, cfg.Consignments.Path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read consignments: %w", err)
+	}
+	for _, pe := range parseErrors {
+		report.warn(fmt.Sprintf("skipping invalid consignment %s: %v", pe.File, pe.Err))
+	}
+	return filterByPackages(consignments, packages), nil
+}
+
+// filterByPackages keeps the consignments naming one of packages, or all of
+// them when packages is empty
+func filterByPackages(consignments []*consignment.Consignment, packages []string) []*consignment.Consignment {
+	if len(packages) == 0 {
+		return consignments
+	}
+	return slices.DeleteFunc(consignments, func(c *consignment.Consignment) bool {
+		return !slices.ContainsFunc(c.Packages, func(name string) bool { return slices.Contains(packages, name) })
+	})
+}
+
+// selectConsignments keeps the pending consignments with the given IDs and
+// leaves the rest on disk for a later release. Every ID must be pending. A
+// consignment left behind that changes a package the selection releases is
+// reported, since it ships in a later release and a higher change type than
+// the selection's is not reflected in this release's bump.
+func selectConsignments(pending []*consignment.Consignment, ids []string, report Reporter) ([]*consignment.Consignment, error) {
+	selected := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if !slices.ContainsFunc(pending, func(c *consignment.Consignment) bool { return c.ID == id }) {
+			return nil, errors.NewValidationError("consignment", i18n.T("version.consignment_not_pending", id))
+		}
+		selected[id] = true
+	}
+
+	var chosen, rest []*consignment.Consignment
+	for _, c := range pending {
+		if selected[c.ID] {
+			chosen = append(chosen, c)
+		} else {
+			rest = append(rest, c)
+		}
+	}
+
+	for _, c := range rest {
+		for _, name := range c.Packages {
+			shipped := consignment.FilterConsignmentsByPackage(chosen, name)
+			if len(shipped) == 0 {
+				continue
+			}
+			highest := consignment.GetHighestChangeType(shipped)
+			if c.ChangeType.Priority() > highest.Priority() {
+				report.warn(i18n.T("version.consignment_unselected_higher", c.ID, c.ChangeType, name, highest))
+			} else {
+				report.warn(i18n.T("version.consignment_unselected", c.ID, name))
+			}
+		}
+	}
+	return chosen, nil
+}
+
+// CheckReleaseWindow refuses to release outside the configured schedule.
+// The schedule is enforced when respect is set or releaseSchedule.enforce is
+// true, unless ignore is set.
+func CheckReleaseWindow(cfg *config.Config, respect, ignore bool) error {
+	if ignore || (!respect && (cfg.ReleaseSchedule == nil || !cfg.ReleaseSchedule.Enforce)) {
+		return nil
+	}
+	if cfg.ReleaseSchedule == nil {
+		return fmt.Errorf("--respect-schedule requires releaseSchedule in shipyard.yaml")
+	}
+	sched, err := cfg.ReleaseSchedule.Build()
+	if err != nil {
+		return err
+	}
+
+	status := sched.Status(ScheduleNow())
+	if status.Open {
+		return nil
+	}
+	if status.Next == nil {
+		return fmt.Errorf("outside the release window and the schedule %q never opens again (use --ignore-schedule to override)", cfg.ReleaseSchedule.Cron)
+	}
+	return fmt.Errorf("outside the release window; the next window opens %s (use --ignore-schedule to override)",
+		status.Next.Opens.Format(ScheduleTimeFormat))
+}
+
+// frozenPackages returns the names of the packages marked frozen
+func frozenPackages(cfg *config.Config) map[string]bool {
+	frozen := make(map[string]bool)
+	for _, pkg := range cfg.Packages {
+		if pkg.Frozen {
+			frozen[pkg.Name] = true
+		}
+	}
+	return frozen
+}
+
+// holdFrozenConsignments splits off the consignments that touch a frozen
+// package. A consignment is removed as a whole once applied, so one that
+// also names unfrozen packages is held back for all of them.
+func holdFrozenConsignments(consignments []*consignment.Consignment, frozen map[string]bool) (applied, held []*consignment.Consignment) {
+	for _, c := range consignments {
+		if slices.ContainsFunc(c.Packages, func(name string) bool { return frozen[name] }) {
+			held = append(held, c)
+		} else {
+			applied = append(applied, c)
+		}
+	}
+	return applied, held
+}
+
+// skippedFrozenPackages returns the sorted frozen packages named by the held
+// consignments
+func skippedFrozenPackages(held []*consignment.Consignment, frozen map[string]bool) []string {
+	var skipped []string
+	for _, c := range held {
+		for _, name := range c.Packages {
+			if frozen[name] {
+				skipped = appendSorted(skipped, name)
+			}
+		}
+	}
+	return skipped
+}
+
+// appendSorted inserts name into a sorted slice unless it is already there
+func appendSorted(names []string, name string) []string {
+	i, found := slices.BinarySearch(names, name)
+	if found {
+		return names
+	}
+	return slices.Insert(names, i, name)
+}
+
+// historyEntry returns the history entry the plan's release of a package
+// records, tagged with tag. ok is false when no consignment names the
+// package, as for a release propagated from a dependency.
+func (p *Plan) historyEntry(name, tag string, snapshot *history.ConfigSnapshot) (history.Entry, bool) {
+	pkgConsignments := ConsignmentsFor(p.Consignments, name)
+	if len(pkgConsignments) == 0 {
+		return history.Entry{}, false
+	}
+	bump := p.Bumps[name]
+	return history.Entry{
+		Version:         bump.NewVersion.String(),
+		Package:         name,
+		PreviousVersion: bump.OldVersion.String(),
+		ChangeType:      bump.ChangeType,
+		Tag:             tag,
+		BaseCommitSHA:   p.BaseCommitSHA,
+		Timestamp:       time.Now(),
+		Consignments:    HistoryConsignments(name, pkgConsignments),
+		Config:          snapshot,
+		Channel:         p.Channel,
+		SetVersion:      slices.Contains(p.SetVersions, name),
+	}, true
+}
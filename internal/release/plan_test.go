@@ -0,0 +1,50 @@
+package release
+
+import (
+	"testing"
+
+	"github.com/NatoNathan/shipyard/internal/consignment"
+	"github.com/NatoNathan/shipyard/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHoldFrozenConsignments(t *testing.T) {
+	frozen := map[string]bool{"api-v1": true}
+	shared := &consignment.Consignment{ID: "c3", Packages: []string{"core", "api-v1"}}
+	own := &consignment.Consignment{ID: "c4", Packages: []string{"core"}}
+
+	// A consignment naming a frozen package is held for all its packages
+	applied, held := holdFrozenConsignments([]*consignment.Consignment{shared, own}, frozen)
+	assert.Equal(t, []*consignment.Consignment{own}, applied)
+	assert.Equal(t, []*consignment.Consignment{shared}, held)
+	assert.Equal(t, []string{"api-v1"}, skippedFrozenPackages(held, frozen))
+}
+
+func TestCheckPrereleaseID(t *testing.T) {
+	assert.NoError(t, CheckPrereleaseID(""))
+	assert.NoError(t, CheckPrereleaseID("rc"))
+	assert.NoError(t, CheckPrereleaseID("beta-2"))
+	assert.Error(t, CheckPrereleaseID("1rc"))
+	assert.Error(t, CheckPrereleaseID("rc.1"))
+}
+
+func TestSelectConsignments(t *testing.T) {
+	fix := &consignment.Consignment{ID: "c1", Packages: []string{"core"}, ChangeType: types.ChangeTypeMinor}
+	feature := &consignment.Consignment{ID: "c2", Packages: []string{"core"}, ChangeType: types.ChangeTypeMajor}
+	docs := &consignment.Consignment{ID: "c3", Packages: []string{"core"}, ChangeType: types.ChangeTypePatch}
+	other := &consignment.Consignment{ID: "c4", Packages: []string{"web"}, ChangeType: types.ChangeTypeMajor}
+	pending := []*consignment.Consignment{fix, feature, docs, other}
+
+	var warnings []string
+	report := Reporter(func(e Event) { warnings = append(warnings, e.Message) })
+	selected, err := selectConsignments(pending, []string{"c1"}, report)
+	require.NoError(t, err)
+	assert.Equal(t, []*consignment.Consignment{fix}, selected)
+	require.Len(t, warnings, 2, "only consignments sharing a selected package are reported")
+	assert.Contains(t, warnings[0], "c2 makes a major change to core")
+	assert.Contains(t, warnings[1], "c3 also changes core")
+
+	_, err = selectConsignments(pending, []string{"c1", "missing"}, nil)
+	assert.ErrorContains(t, err, "consignment missing is not pending")
+}
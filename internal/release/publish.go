@@ -0,0 +1,106 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/git"
+	"github.com/NatoNathan/shipyard/internal/history"
+	"github.com/NatoNathan/shipyard/internal/publish"
+	"github.com/NatoNathan/shipyard/internal/version"
+)
+
+// ChartPublishResult records the outcome of publishing one Helm chart. Err
+// with a Result means the chart was pushed but its digest was not recorded.
+type ChartPublishResult struct {
+	Package string
+	Result  *publish.PublishResult
+	Err     error
+}
+
+// publishHelmCharts packages and pushes every released Helm package that has
+// publish.helm configured, recording pushed digests in history. The release has
+// already succeeded at this point, so failures are reported per chart rather
+// than returned.
+func publishHelmCharts(ctx context.Context, projectPath string, cfg *config.Config, versionBumps map[string]version.VersionBump, historyFile history.File) []ChartPublishResult {
+	var results []ChartPublishResult
+
+	for _, pkg := range cfg.Packages {
+		bump, released := versionBumps[pkg.Name]
+		if !released || pkg.Publish == nil || pkg.Publish.Helm == nil {
+			continue
+		}
+
+		result, err := publishHelmChart(ctx, projectPath, pkg, bump, historyFile)
+		results = append(results, ChartPublishResult{Package: pkg.Name, Result: result, Err: err})
+	}
+
+	return results
+}
+
+// publishHelmChart pushes a single released chart and records its digest
+func publishHelmChart(ctx context.Context, projectPath string, pkg config.Package, bump version.VersionBump, historyFile history.File) (*publish.PublishResult, error) {
+	helmCfg := pkg.Publish.Helm
+
+	chart, err := publish.PackageChart(filepath.Join(projectPath, pkg.Path))
+	if err != nil {
+		return nil, err
+	}
+
+	publisher := &publish.HelmPublisher{
+		Registry:  helmCfg.Registry,
+		PlainHTTP: helmCfg.PlainHTTP,
+	}
+	if helmCfg.UsernameEnv != "" {
+		publisher.Username = os.Getenv(helmCfg.UsernameEnv)
+	}
+	if helmCfg.PasswordEnv != "" {
+		publisher.Password = os.Getenv(helmCfg.PasswordEnv)
+	}
+
+	result, err := publisher.Push(ctx, chart)
+	if err != nil {
+		return nil, err
+	}
+
+	artifact := history.Artifact{
+		Type:      "helm",
+		Reference: result.Reference,
+		Digest:    result.Digest,
+	}
+	if err := historyFile.RecordArtifacts(pkg.Name, bump.NewVersion.String(), []history.Artifact{artifact}); err != nil {
+		return result, fmt.Errorf("pushed %s but failed to record digest: %w", result.Reference, err)
+	}
+
+	return result, nil
+}
+
+// artifactsCommitMessage is the message of the commit recording published
+// chart digests, made after the release commit because the digests only
+// exist once the tagged release is pushed to the registry
+const artifactsCommitMessage = "chore: record published artifacts"
+
+// digestsRecorded reports whether any chart digest was written to history
+func digestsRecorded(results []ChartPublishResult) bool {
+	for _, r := range results {
+		if r.Err == nil && r.Result != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// commitArtifacts commits the history file holding the recorded digests, so
+// the tree is left clean and a push carries them
+func commitArtifacts(projectPath, historyPath string) error {
+	if err := git.StageFiles(projectPath, []string{historyPath}); err != nil {
+		return fmt.Errorf("failed to stage history: %w", err)
+	}
+	if err := git.CreateCommit(projectPath, artifactsCommitMessage); err != nil {
+		return fmt.Errorf("failed to create commit: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package release
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/git"
+	"github.com/NatoNathan/shipyard/internal/runstate"
+)
+
+// Remote returns the remote releases are pushed to
+func Remote(cfg *config.Config) string {
+	if cfg.Git.Remote != "" {
+		return cfg.Git.Remote
+	}
+	return git.DefaultRemote
+}
+
+// PushOptions builds the push of the checked-out branch and tags from
+// the git config and the token environment variables
+func PushOptions(projectPath string, cfg *config.Config, tags []string, dryRun bool) git.PushOptions {
+	sshKey := cfg.Git.SSHKey
+	if sshKey != "" && !filepath.IsAbs(sshKey) {
+		sshKey = filepath.Join(projectPath, sshKey)
+	}
+	return git.PushOptions{
+		Remote:      Remote(cfg),
+		Tags:        tags,
+		Token:       os.Getenv(git.TokenEnv),
+		GitHubToken: os.Getenv(git.GitHubTokenEnv),
+		SSHKeyPath:  sshKey,
+		DryRun:      dryRun,
+	}
+}
+
+// PushResult records a pushed release
+type PushResult struct {
+	Remote string
+	Tags   []string // Tags pushed with the release commit
+}
+
+// pushRelease pushes the release commit and the tags the run created when the
+// run was started with Push, returning nil when it was not. The release is
+// complete at this point, so a failed push leaves it in place and says how
+// to push it by hand.
+func pushRelease(projectPath string, cfg *config.Config, run *runstate.Run) (*PushResult, error) {
+	if !run.Options.Push || run.Options.NoCommit {
+		return nil, nil
+	}
+
+	var tags []string
+	if !run.Options.NoTag {
+		for _, tag := range run.Tags {
+			if !tag.Existing {
+				tags = append(tags, tag.Name)
+			}
+		}
+	}
+
+	remote := Remote(cfg)
+	if err := git.Push(projectPath, PushOptions(projectPath, cfg, tags, false)); err != nil {
+		manual := append([]string{"git push --atomic", remote, "HEAD"}, tags...)
+		return nil, fmt.Errorf("the release was created but not pushed; fix the problem and run `%s`: %w", strings.Join(manual, " "), err)
+	}
+	return &PushResult{Remote: remote, Tags: tags}, nil
+}
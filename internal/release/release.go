@@ -0,0 +1,290 @@
+// Package release plans and applies releases: it calculates versions from
+// pending consignments, then writes version files, history and changelogs,
+// removes the released consignments and creates the release commit and tags
+// as one run that can be resumed or rolled back. It never prints; what a run
+// has to say is passed to a Reporter.
+package release
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/changelog"
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/errors"
+	"github.com/NatoNathan/shipyard/internal/git"
+	"github.com/NatoNathan/shipyard/internal/hooks"
+	"github.com/NatoNathan/shipyard/internal/i18n"
+	"github.com/NatoNathan/shipyard/internal/rules"
+	"github.com/NatoNathan/shipyard/internal/runstate"
+)
+
+// Level is the importance of an Event
+type Level int
+
+const (
+	LevelDetail  Level = iota // A step of the run, such as a version file written
+	LevelInfo                 // Worth showing, such as a tag that already marks the release
+	LevelWarning              // Went wrong without failing the release
+)
+
+// Event is a message reported while a release runs
+type Event struct {
+	Level   Level
+	Message string
+}
+
+// Reporter receives the events of a release as they happen; nil discards them
+type Reporter func(Event)
+
+func (r Reporter) emit(level Level, message string) {
+	if r != nil {
+		r(Event{Level: level, Message: message})
+	}
+}
+
+func (r Reporter) detail(message string) { r.emit(LevelDetail, message) }
+func (r Reporter) info(message string)   { r.emit(LevelInfo, message) }
+func (r Reporter) warn(message string)   { r.emit(LevelWarning, message) }
+
+// Output is where a run sends what it has to say
+type Output struct {
+	Report     Reporter  // Events of the run
+	HookOutput io.Writer // Streamed output of the hooks; nil discards it
+}
+
+func (o Output) hooks() io.Writer {
+	if o.HookOutput == nil {
+		return io.Discard
+	}
+	return o.HookOutput
+}
+
+// ApplyOptions controls how a planned release is applied
+type ApplyOptions struct {
+	NoCommit          bool   // Skip the release commit (and so the tags)
+	NoTag             bool   // Skip the release tags
+	NoPublish         bool   // Skip publishing Helm charts
+	NoHooks           bool   // Skip the preVersion and postVersion hooks
+	Template          string // Changelog template for every package, overriding the config
+	IncludeUnreleased bool   // List consignments left pending under Unreleased in changelogs
+	MaxSeverity       string // Override the level of every enabled rule
+
+	Push          bool   // Push the release commit and tags to the release remote
+	GitHubRelease bool   // Publish a GitHub release per pushed tag
+	Draft         bool   // Publish the GitHub releases as drafts
+	Manifest      string // Write the release manifest JSON to this path
+
+	Output
+}
+
+// Result reports what a release applied
+type Result struct {
+	Run                *runstate.Run         // The completed run: versions, tags, history entries
+	Committed          bool                  // A release commit was created
+	Charts             []ChartPublishResult  // Helm charts published after the release
+	ArtifactsCommitted bool                  // Chart digests were committed after the release commit
+	Pushed             *PushResult           // Set when the release was pushed
+	GitHubReleases     []GitHubReleaseResult // GitHub releases published after the push
+	GitHubSkipped      error                 // Why GitHub releases were requested but not published
+	Manifest           string                // Path of the written release manifest, if any
+}
+
+// Apply releases the plan. Tags, the commit message and pre-flight rules are
+// checked and the preVersion hooks run before anything changes; the release
+// plan is then checkpointed so an interrupted run can be finished with Resume
+// or rolled back with Abort. A failure while applying rolls back everything.
+// When the release succeeded but pushing or writing the manifest failed, the
+// Result is returned together with the error.
+func Apply(projectPath string, cfg *config.Config, plan *Plan, opts ApplyOptions) (*Result, error) {
+	if err := CheckInterrupted(projectPath); err != nil {
+		return nil, err
+	}
+	opts.Report.detail(i18n.T("version.apply_order", FormatApplyOrder(plan.Order, plan.Bumps)))
+
+	resolver, err := NewRuleResolver(cfg, opts.MaxSeverity)
+	if err != nil {
+		return nil, err
+	}
+
+	// Render tag names and messages (needed for history entries) and the
+	// release commit message up front, so pre-flight rules fail before any mutation
+	preflight := rules.NewReport(resolver)
+	generator := NewGenerator(projectPath, cfg, plan.settings)
+	generator.SetChannel(plan.Channel)
+
+	packageTags := make(map[string]changelog.PackageTag)
+	for _, pkg := range plan.Packages {
+		bump, hasBump := plan.Bumps[pkg.Name]
+		if !hasBump {
+			continue
+		}
+		tagName, tagMsg, err := PackageTag(generator, cfg, pkg, plan.Consignments, bump.NewVersion)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate tag for package %s: %w", pkg.Name, err)
+		}
+		if err := CheckMessageSize(fmt.Sprintf("tag annotation for %s", pkg.Name), tagMsg, cfg.Templates.MaxMessageBytes); err != nil {
+			preflight.Add(rules.MessageSize, err.Error())
+		}
+		packageTags[pkg.Name] = changelog.PackageTag{Name: tagName, Message: tagMsg}
+	}
+
+	var commitMessage string
+	if !opts.NoCommit {
+		commitMessage, err = CommitMessage(generator, cfg, plan.Consignments, plan.Bumps)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate commit message: %w", err)
+		}
+		if err := CheckMessageSize("commit message", commitMessage, cfg.Templates.MaxMessageBytes); err != nil {
+			preflight.Add(rules.MessageSize, err.Error())
+		}
+	}
+
+	// A tag that already marks this release, left by an interrupted run, is
+	// skipped. Other existing tags are reported under tag-collision; below
+	// error level they are skipped rather than recreated.
+	existingTags := make(map[string]bool)
+	if isRepo, _ := git.IsRepository(projectPath); isRepo && !opts.NoCommit && !opts.NoTag {
+		for _, pkg := range plan.Packages {
+			tag, ok := packageTags[pkg.Name]
+			if !ok {
+				continue
+			}
+			exists, err := git.VerifyTagExists(projectPath, tag.Name)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check tag %s: %w", tag.Name, err)
+			}
+			if !exists {
+				continue
+			}
+			existingTags[tag.Name] = true
+
+			newVersion := plan.Bumps[pkg.Name].NewVersion.String()
+			versions, err := ManifestVersions(projectPath, pkg, newVersion)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check tag %s: %w", tag.Name, err)
+			}
+			released, err := git.VerifyTagMatchesRelease(projectPath, tag.Name, versions)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check tag %s: %w", tag.Name, err)
+			}
+			if released {
+				opts.Report.info(i18n.T("version.tag_already_released", tag.Name, pkg.Name, newVersion))
+				continue
+			}
+			preflight.Addf(rules.TagCollision,
+				"tag %s for %s already exists and does not point at a release of %s %s; delete it with `git tag -d %s` (and from the remote if it was pushed) or release a different version",
+				tag.Name, pkg.Name, pkg.Name, newVersion, tag.Name)
+		}
+	}
+
+	for _, finding := range preflight.Warnings() {
+		opts.Report.warn(finding.String())
+	}
+	if err := preflight.Err(); err != nil {
+		return nil, fmt.Errorf("pre-flight checks failed: %w", err)
+	}
+
+	// A release that cannot be pushed or published fails before anything changes
+	if opts.Push {
+		if err := git.Push(projectPath, PushOptions(projectPath, cfg, nil, true)); err != nil {
+			return nil, fmt.Errorf("pre-flight checks failed: %w", err)
+		}
+	}
+	if opts.GitHubRelease {
+		if _, err := resolveGitHubTarget(projectPath, cfg); err != nil {
+			return nil, fmt.Errorf("pre-flight checks failed: %w", err)
+		}
+	}
+
+	// preVersion hooks run last, so a failing hook aborts before any file changes
+	if !opts.NoHooks {
+		tags := make(map[string]string, len(packageTags))
+		if !opts.NoCommit && !opts.NoTag {
+			for name, tag := range packageTags {
+				tags[name] = tag.Name
+			}
+		}
+		if err := runReleaseHooks(projectPath, cfg, hooks.PreVersion, plan.Packages, plan.Bumps, tags, opts.hooks()); err != nil {
+			return nil, err
+		}
+	}
+
+	// Record the release plan before anything changes, so an interrupted
+	// run can be finished with Resume or rolled back with Abort
+	run, err := newRun(projectPath, cfg, plan, opts, packageTags, existingTags, commitMessage)
+	if err != nil {
+		return nil, err
+	}
+	return finish(projectPath, cfg, plan.settings, run, opts.Output)
+}
+
+// finish applies the remaining phases of a recorded run, then publishes
+// released Helm charts, pushes, publishes GitHub releases and writes the
+// manifest as the run's options ask
+func finish(projectPath string, cfg *config.Config, settings Settings, run *runstate.Run, out Output) (*Result, error) {
+	result, err := executeRun(projectPath, cfg, settings, run, out)
+	if err != nil {
+		return nil, err
+	}
+	if result.Pushed, err = pushRelease(projectPath, cfg, run); err != nil {
+		return result, err
+	}
+	result.GitHubReleases, result.GitHubSkipped = publishGitHubReleases(context.Background(), projectPath, cfg, settings, run)
+	if result.Manifest, err = writeRunManifest(projectPath, run); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// Interrupted returns the checkpoint of an interrupted run, or nil when
+// there is none
+func Interrupted(projectPath string) (*runstate.Run, error) {
+	statePath := runstate.Path(projectPath)
+	if !runstate.Exists(statePath) {
+		return nil, nil
+	}
+	return runstate.Read(statePath)
+}
+
+// Resume finishes an interrupted run from its checkpoint with the project's
+// settings; nothing is recomputed
+func Resume(projectPath string, cfg *config.Config, settings Settings, out Output) (*Result, error) {
+	run, err := Interrupted(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	if run == nil {
+		return nil, errors.NewValidationError("resume", i18n.T("version.resume_none"))
+	}
+	return finish(projectPath, cfg, settings, run, out)
+}
+
+// Abort rolls back an interrupted run from its checkpoint and returns it,
+// reporting anything that needs manual attention as an error
+func Abort(projectPath string) (*runstate.Run, error) {
+	run, err := Interrupted(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	if run == nil {
+		return nil, errors.NewValidationError("abort-run", i18n.T("version.abort_none"))
+	}
+	if err := abort(projectPath, run); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// CheckInterrupted reports an error when an interrupted run left a
+// checkpoint, since a new release would overwrite it
+func CheckInterrupted(projectPath string) error {
+	run, err := Interrupted(projectPath)
+	if err != nil || run == nil {
+		return err
+	}
+	return errors.NewValidationError("version", i18n.T("version.interrupted",
+		run.StartedAt.Format(time.RFC3339), DescribeProgress(run.Completed)))
+}
@@ -0,0 +1,21 @@
+package release
+
+import (
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/errors"
+	"github.com/NatoNathan/shipyard/internal/rules"
+)
+
+// NewRuleResolver resolves rule levels from the config's rules map and the
+// --max-severity flag. cfg may be nil when the config failed to load.
+func NewRuleResolver(cfg *config.Config, maxSeverity string) (*rules.Resolver, error) {
+	var overrides map[string]string
+	if cfg != nil {
+		overrides = cfg.Rules
+	}
+	resolver, err := rules.NewResolver(overrides, maxSeverity)
+	if err != nil {
+		return nil, errors.NewValidationError("rules", err.Error())
+	}
+	return resolver, nil
+}
@@ -0,0 +1,772 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/changelog"
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/consignment"
+	"github.com/NatoNathan/shipyard/internal/ecosystem"
+	"github.com/NatoNathan/shipyard/internal/fileutil"
+	"github.com/NatoNathan/shipyard/internal/git"
+	"github.com/NatoNathan/shipyard/internal/history"
+	"github.com/NatoNathan/shipyard/internal/i18n"
+	"github.com/NatoNathan/shipyard/internal/prerelease"
+	"github.com/NatoNathan/shipyard/internal/runstate"
+	"github.com/NatoNathan/shipyard/internal/version"
+	"github.com/NatoNathan/shipyard/pkg/semver"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// AfterPhase runs after each phase of a run is checkpointed; tests replace
+// it to interrupt a run between phases
+var AfterPhase = func(phase runstate.Phase) {}
+
+// newRun records the release plan and backs up every file the run may
+// touch before any of them change. The checkpoint holds everything the
+// remaining phases need, so a resumed run never recomputes from inputs the
+// interrupted run already modified.
+func newRun(
+	projectPath string,
+	cfg *config.Config,
+	plan *Plan,
+	opts ApplyOptions,
+	packageTags map[string]changelog.PackageTag,
+	existingTags map[string]bool,
+	commitMessage string,
+) (*runstate.Run, error) {
+	run := &runstate.Run{
+		StartedAt: time.Now(),
+		Options: runstate.Options{
+			NoCommit:  opts.NoCommit,
+			NoTag:     opts.NoTag,
+			NoPublish: opts.NoPublish,
+			Template:  opts.Template,
+			Push:      opts.Push,
+			NoHooks:   opts.NoHooks,
+			Channel:   plan.Channel,
+
+			IncludeUnreleased: opts.IncludeUnreleased,
+
+			GitHubRelease: opts.GitHubRelease,
+			Draft:         opts.Draft,
+
+			Manifest: manifestPath(projectPath, opts.Manifest),
+		},
+		CommitMessage: commitMessage,
+	}
+	tx := NewFileTransaction()
+
+	configSnapshot, err := RecordConfigSnapshot(projectPath, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record config snapshot: %w", err)
+	}
+
+	for _, pkg := range plan.Packages {
+		bump, hasBump := plan.Bumps[pkg.Name]
+		if !hasBump {
+			continue
+		}
+		run.Bumps = append(run.Bumps, runstate.Bump{
+			Package:    bump.Package,
+			OldVersion: bump.OldVersion.String(),
+			NewVersion: bump.NewVersion.String(),
+			ChangeType: bump.ChangeType,
+			Source:     bump.Source,
+			CalVer:     bump.NewVersion.CalVer,
+		})
+
+		tagName := ""
+		if tag, exists := packageTags[pkg.Name]; exists {
+			tagName = tag.Name
+			run.Tags = append(run.Tags, runstate.Tag{
+				Package:  pkg.Name,
+				Name:     tag.Name,
+				Message:  tag.Message,
+				Existing: existingTags[tag.Name],
+			})
+		}
+
+		if entry, ok := plan.historyEntry(pkg.Name, tagName, configSnapshot); ok {
+			run.History = append(run.History, entry)
+		}
+
+		pkgPath := filepath.Join(projectPath, pkg.Path)
+		handler, err := GetEcosystemHandler(pkg, pkgPath)
+		if err != nil {
+			return nil, err
+		}
+		for _, versionFile := range handler.GetVersionFiles() {
+			if err := tx.Backup(filepath.Join(pkgPath, versionFile)); err != nil {
+				return nil, err
+			}
+		}
+		changelogPath, err := ChangelogPath(projectPath, pkg)
+		if err != nil {
+			return nil, err
+		}
+		if err := tx.Backup(changelogPath); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Backup(filepath.Join(projectPath, cfg.HistoryPathFor(plan.Channel))); err != nil {
+		return nil, err
+	}
+
+	consignmentsDir := filepath.Join(projectPath, cfg.Consignments.Path)
+	for _, c := range plan.Consignments {
+		consignmentPath := filepath.Join(consignmentsDir, c.FileName())
+		if err := tx.Backup(consignmentPath); err != nil {
+			return nil, err
+		}
+		rel, err := filepath.Rel(projectPath, consignmentPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to record consignment %s: %w", c.ID, err)
+		}
+		run.Consignments = append(run.Consignments, filepath.ToSlash(rel))
+	}
+
+	if prereleaseStatePath := filepath.Join(projectPath, ".shipyard", "prerelease.yml"); prerelease.Exists(prereleaseStatePath) {
+		if err := tx.Backup(prereleaseStatePath); err != nil {
+			return nil, err
+		}
+		run.Prerelease = true
+	}
+
+	if !opts.NoCommit {
+		head, err := git.HeadHash(projectPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to capture git HEAD before version changes: %w", err)
+		}
+		run.OriginalHead = head.String()
+	}
+
+	run.Backups, err = tx.Backups(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := runstate.Write(runstate.Path(projectPath), run); err != nil {
+		return nil, fmt.Errorf("failed to record run state: %w", err)
+	}
+	return run, nil
+}
+
+// runner applies the phases of a recorded run
+type runner struct {
+	projectPath  string
+	cfg          *config.Config
+	settings     Settings
+	run          *runstate.Run
+	tx           *FileTransaction
+	versionBumps map[string]version.VersionBump
+	report       Reporter
+	hookOutput   io.Writer
+	retry        bool // the current phase was interrupted and is being applied again
+}
+
+// executeRun applies every phase the run has not completed,
+// checkpointing before and after each one. Any failure rolls back everything
+// the run applied, including phases completed by an earlier interrupted
+// process, so a release is either fully applied or not applied at all.
+func executeRun(projectPath string, cfg *config.Config, settings Settings, run *runstate.Run, out Output) (result *Result, err error) {
+	r := &runner{
+		projectPath: projectPath,
+		cfg:         cfg,
+		settings:    settings,
+		run:         run,
+		tx:          RestoreFileTransaction(projectPath, run.Backups),
+		report:      out.Report,
+		hookOutput:  out.hooks(),
+	}
+	statePath := runstate.Path(projectPath)
+
+	defer func() {
+		if err != nil {
+			problems := rollback(projectPath, run, r.tx)
+			for _, problem := range problems {
+				err = fmt.Errorf("%w; additionally %v", err, problem)
+			}
+			if len(problems) == 0 {
+				err = fmt.Errorf("%w (all changes were rolled back; nothing was applied)", err)
+			} else {
+				err = fmt.Errorf("%w (the repository may be partially updated; run `shipyard version --abort-run` to retry the rollback)", err)
+			}
+		}
+	}()
+
+	r.versionBumps, err = bumpsFromRun(run)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, phase := range runstate.Phases {
+		if run.Done(phase) {
+			continue
+		}
+		r.retry = run.Phase == phase
+		run.Start(phase)
+		if err := runstate.Write(statePath, run); err != nil {
+			return nil, fmt.Errorf("failed to record run state: %w", err)
+		}
+		if err := r.apply(phase); err != nil {
+			return nil, err
+		}
+		run.Complete(phase)
+		if err := runstate.Write(statePath, run); err != nil {
+			return nil, fmt.Errorf("failed to record run state: %w", err)
+		}
+		AfterPhase(phase)
+	}
+
+	// The release is complete; a checkpoint left behind would only block the next run
+	if err := runstate.Delete(statePath); err != nil {
+		r.report.warn(i18n.T("version.state_not_removed", err, statePath))
+	}
+
+	result = &Result{Run: run, Committed: r.committed()}
+
+	// Publish released Helm charts; the release is complete, so failures are only reported
+	if !run.Options.NoPublish {
+		result.Charts = publishHelmCharts(context.Background(), projectPath, cfg, r.versionBumps, r.history())
+		if r.committed() && digestsRecorded(result.Charts) {
+			if err := commitArtifacts(projectPath, r.historyPath()); err != nil {
+				r.report.warn(i18n.T("version.chart_digests_not_committed", err))
+			} else {
+				result.ArtifactsCommitted = true
+				r.report.detail(i18n.T("version.chart_digests_committed"))
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// apply runs one phase
+func (r *runner) apply(phase runstate.Phase) error {
+	switch phase {
+	case runstate.PhaseVersions:
+		return r.applyVersions()
+	case runstate.PhaseHistory:
+		return r.archiveHistory()
+	case runstate.PhaseChangelogs:
+		return r.generateChangelogs()
+	case runstate.PhaseConsignments:
+		return r.removeConsignments()
+	case runstate.PhaseHooks:
+		return r.runPostVersionHooks()
+	case runstate.PhaseCommit:
+		return r.createCommit()
+	case runstate.PhaseTags:
+		return r.createTags()
+	}
+	return fmt.Errorf("unknown version run phase %q", phase)
+}
+
+// releasePackages returns the configured packages the run releases, in apply order
+func (r *runner) releasePackages() ([]config.Package, error) {
+	packages := make([]config.Package, 0, len(r.run.Bumps))
+	for _, bump := range r.run.Bumps {
+		pkg, ok := r.cfg.GetPackage(bump.Package)
+		if !ok {
+			return nil, fmt.Errorf("package %s is no longer configured", bump.Package)
+		}
+		packages = append(packages, pkg)
+	}
+	return packages, nil
+}
+
+func (r *runner) historyPath() string {
+	return filepath.Join(r.projectPath, r.cfg.HistoryPathFor(r.run.Options.Channel))
+}
+
+// history returns the run's history file, locked with the project's timeout
+func (r *runner) history() history.File {
+	return r.settings.History(r.historyPath())
+}
+
+// committed reports whether the run creates a release commit
+func (r *runner) committed() bool {
+	return !r.run.Options.NoCommit && len(r.run.Staged) > 0
+}
+
+// applyVersions writes the new versions to each package's version files.
+// Writing a version is idempotent, so an interrupted phase is simply repeated.
+func (r *runner) applyVersions() error {
+	packages, err := r.releasePackages()
+	if err != nil {
+		return err
+	}
+
+	allNewVersions := make(map[string]semver.Version)
+	for pkgName, pkgBump := range r.versionBumps {
+		allNewVersions[pkgName] = pkgBump.NewVersion
+	}
+
+	warned := make(map[string]bool)
+	for _, pkg := range packages {
+		bump := r.versionBumps[pkg.Name]
+		pkgPath := filepath.Join(r.projectPath, pkg.Path)
+
+		handlerCtx := &ecosystem.HandlerContext{
+			AllVersions:   allNewVersions,
+			PackageConfig: &pkg,
+			Packages:      r.cfg.Packages,
+			ProjectPath:   r.projectPath,
+		}
+
+		handler, err := NewVersionHandler(pkg, pkgPath, handlerCtx)
+		if err != nil {
+			return err
+		}
+
+		for _, versionFile := range handler.GetVersionFiles() {
+			if err := r.tx.Backup(filepath.Join(pkgPath, versionFile)); err != nil {
+				return err
+			}
+		}
+
+		if err := handler.UpdateVersion(bump.NewVersion); err != nil {
+			return fmt.Errorf("failed to update version for %s: %w", pkg.Name, err)
+		}
+		// Lockfiles shared by several packages are reported once
+		if reporter, ok := handler.(ecosystem.WarningReporter); ok {
+			for _, warning := range reporter.Warnings() {
+				if !warned[warning] {
+					warned[warning] = true
+					r.report.warn(i18n.T("version.handler_warning", pkg.Name, warning))
+				}
+			}
+		}
+
+		r.report.detail(i18n.T("version.updated", pkg.Name, bump.OldVersion, bump.NewVersion))
+	}
+	return nil
+}
+
+// archiveHistory appends the recorded entries to history. A repeated phase
+// skips entries the interrupted run already appended.
+func (r *runner) archiveHistory() error {
+	historyPath := r.historyPath()
+	entries := r.run.History
+
+	if r.retry {
+		existing, err := r.history().Read()
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to archive consignments: %w", err)
+		}
+		archived := make(map[string]bool, len(existing))
+		for _, entry := range existing {
+			archived[entry.Package+"@"+entry.Version] = true
+		}
+		var pending []history.Entry
+		for _, entry := range entries {
+			if !archived[entry.Package+"@"+entry.Version] {
+				pending = append(pending, entry)
+			}
+		}
+		entries = pending
+	}
+
+	if err := r.tx.Backup(historyPath); err != nil {
+		return err
+	}
+	if err := history.EnsureHistory(historyPath); err != nil {
+		return fmt.Errorf("failed to archive consignments: %w", err)
+	}
+	if err := r.history().Append(entries); err != nil {
+		return fmt.Errorf("failed to archive consignments: %w", err)
+	}
+
+	r.report.detail(i18n.T("version.history_archived", len(entries)))
+	return nil
+}
+
+// generateChangelogs renders each released package's changelog from history,
+// so it must run after archiveHistory for the new version to appear
+func (r *runner) generateChangelogs() error {
+	packages, err := r.releasePackages()
+	if err != nil {
+		return err
+	}
+
+	// Changelogs cover every release, including those compacted into archives
+	allEntries, err := r.history().ReadWithArchives()
+	if err != nil {
+		return fmt.Errorf("failed to read history for changelog generation: %w", err)
+	}
+
+	// With --include-unreleased, consignments this run leaves pending are
+	// listed under Unreleased
+	var unreleasedConsignments []*consignment.Consignment
+	if r.run.Options.IncludeUnreleased {
+		unreleasedConsignments, err = r.pendingAfterRun()
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, pkg := range packages {
+		pkgEntries := history.FilterByPackage(allEntries, pkg.Name, pkg.Aliases...)
+		if len(pkgEntries) == 0 {
+			continue
+		}
+
+		if unreleased, ok := UnreleasedEntry(pkg.Name, unreleasedConsignments); ok {
+			pkgEntries = append(pkgEntries, unreleased)
+		}
+
+		changelogContent, err := RenderChangelog(r.cfg, r.settings, pkg, pkgEntries, r.run.Options.Template)
+		if err != nil {
+			return err
+		}
+
+		changelogPath, err := ChangelogPath(r.projectPath, pkg)
+		if err != nil {
+			return err
+		}
+		if err := r.tx.Backup(changelogPath); err != nil {
+			return err
+		}
+		if err := fileutil.WriteFile(changelogPath, []byte(changelogContent), 0644); err != nil {
+			return fmt.Errorf("failed to write changelog for %s: %w", pkg.Name, err)
+		}
+
+		r.report.detail(i18n.T("version.changelog_generated", pkg.Name))
+	}
+	return nil
+}
+
+// pendingAfterRun returns the pending consignments the run does not release
+func (r *runner) pendingAfterRun() ([]*consignment.Consignment, error) {
+	released := make(map[string]bool, len(r.run.Consignments))
+	for _, rel := range r.run.Consignments {
+		released[strings.TrimSuffix(filepath.Base(rel), ".md")] = true
+	}
+	return pendingExcept(r.projectPath, r.cfg, released, r.report)
+}
+
+// pendingExcept returns the pending consignments other than the released ones
+func pendingExcept(projectPath string, cfg *config.Config, released map[string]bool, report Reporter) ([]*consignment.Consignment, error) {
+	pending, err := ReadConsignments(projectPath, cfg, nil, report)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(pending, func(c *consignment.Consignment) bool {
+		return released[c.ID]
+	}), nil
+}
+
+// removeConsignments deletes the released consignment files and any
+// pre-release state the release promotes
+func (r *runner) removeConsignments() error {
+	for _, rel := range r.run.Consignments {
+		consignmentPath := filepath.Join(r.projectPath, filepath.FromSlash(rel))
+		if err := r.tx.Backup(consignmentPath); err != nil {
+			return err
+		}
+		if err := os.Remove(consignmentPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to delete consignment %s: %w", strings.TrimSuffix(filepath.Base(rel), ".md"), err)
+		}
+	}
+
+	r.report.detail(i18n.T("version.consignments_deleted", len(r.run.Consignments)))
+
+	if r.run.Prerelease {
+		prereleaseStatePath := filepath.Join(r.projectPath, ".shipyard", "prerelease.yml")
+		if err := r.tx.Backup(prereleaseStatePath); err != nil {
+			return err
+		}
+		if err := prerelease.DeleteState(prereleaseStatePath); err != nil {
+			return fmt.Errorf("failed to delete prerelease state: %w", err)
+		}
+		r.report.detail(i18n.T("version.prerelease_deleted"))
+	}
+	return nil
+}
+
+// createCommit stages the release files and commits them. The staged file
+// list is checkpointed first; a repeated phase whose commit already moved
+// HEAD does not commit again.
+func (r *runner) createCommit() error {
+	if r.run.Options.NoCommit {
+		return nil
+	}
+
+	packages, err := r.releasePackages()
+	if err != nil {
+		return err
+	}
+	changedPackages := make(map[string]bool)
+	for pkgName := range r.versionBumps {
+		changedPackages[pkgName] = true
+	}
+	filesToStage, err := CollectPackageVersionFiles(r.projectPath, packages, changedPackages)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(r.historyPath()); err == nil {
+		filesToStage = append(filesToStage, r.historyPath())
+	}
+	for _, rel := range r.run.Consignments {
+		filesToStage = append(filesToStage, filepath.Join(r.projectPath, filepath.FromSlash(rel)))
+	}
+	if r.run.Prerelease {
+		filesToStage = append(filesToStage, filepath.Join(r.projectPath, ".shipyard", "prerelease.yml"))
+	}
+	for _, rel := range r.run.HookFiles {
+		filesToStage = append(filesToStage, filepath.Join(r.projectPath, filepath.FromSlash(rel)))
+	}
+
+	r.run.Staged = make([]string, 0, len(filesToStage))
+	for _, path := range filesToStage {
+		rel, err := filepath.Rel(r.projectPath, path)
+		if err != nil {
+			return fmt.Errorf("failed to record staged file %s: %w", path, err)
+		}
+		r.run.Staged = append(r.run.Staged, filepath.ToSlash(rel))
+	}
+	if err := runstate.Write(runstate.Path(r.projectPath), r.run); err != nil {
+		return fmt.Errorf("failed to record run state: %w", err)
+	}
+	if len(filesToStage) == 0 {
+		return nil
+	}
+
+	if r.retry {
+		head, err := git.HeadHash(r.projectPath)
+		if err != nil {
+			return fmt.Errorf("failed to read git HEAD: %w", err)
+		}
+		if head.String() != r.run.OriginalHead {
+			return nil
+		}
+	}
+
+	if err := git.StageFiles(r.projectPath, filesToStage); err != nil {
+		return fmt.Errorf("failed to stage files: %w", err)
+	}
+	if err := git.CreateCommit(r.projectPath, r.run.CommitMessage); err != nil {
+		return fmt.Errorf("failed to create commit: %w", err)
+	}
+
+	r.report.detail(i18n.T("version.commit_created", len(filesToStage)))
+	return nil
+}
+
+// createTags creates the release tags that did not exist before the run. A
+// repeated phase skips tags the interrupted run already created, once they
+// are confirmed to point at the release.
+func (r *runner) createTags() error {
+	if r.run.Options.NoTag || !r.committed() {
+		return nil
+	}
+
+	var pending []runstate.Tag
+	for _, tag := range r.run.Tags {
+		if tag.Existing {
+			continue
+		}
+		if r.retry {
+			exists, err := git.VerifyTagExists(r.projectPath, tag.Name)
+			if err != nil {
+				return fmt.Errorf("failed to check tag %s: %w", tag.Name, err)
+			}
+			if exists {
+				if err := r.verifyCreatedTag(tag); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		pending = append(pending, tag)
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	names := make([]string, len(pending))
+	for i, tag := range pending {
+		names[i] = tag.Name
+	}
+	if err := git.EnsureTagsAbsent(r.projectPath, names); err != nil {
+		return fmt.Errorf("failed to validate tags: %w", err)
+	}
+
+	// Annotated tags first, then lightweight ones
+	for _, annotated := range []bool{true, false} {
+		for _, tag := range pending {
+			if (tag.Message != "") != annotated {
+				continue
+			}
+			if annotated {
+				r.report.detail(i18n.T("version.tag_annotated", tag.Package, tag.Name))
+				if err := git.CreateAnnotatedTag(r.projectPath, tag.Name, tag.Message); err != nil {
+					return fmt.Errorf("failed to create annotated tag %s: %w", tag.Name, err)
+				}
+			} else {
+				r.report.detail(i18n.T("version.tag_lightweight", tag.Package, tag.Name))
+				if err := git.CreateLightweightTag(r.projectPath, tag.Name); err != nil {
+					return fmt.Errorf("failed to create lightweight tag %s: %w", tag.Name, err)
+				}
+			}
+		}
+	}
+
+	r.report.detail(i18n.T("version.tags_created", len(pending)))
+	return nil
+}
+
+// verifyCreatedTag checks that a tag found while repeating the tag phase
+// marks this run's release rather than something created since
+func (r *runner) verifyCreatedTag(tag runstate.Tag) error {
+	pkg, ok := r.cfg.GetPackage(tag.Package)
+	if !ok {
+		return fmt.Errorf("failed to check tag %s: package %s is not configured", tag.Name, tag.Package)
+	}
+	var newVersion string
+	for _, bump := range r.run.Bumps {
+		if bump.Package == tag.Package {
+			newVersion = bump.NewVersion
+		}
+	}
+	versions, err := ManifestVersions(r.projectPath, pkg, newVersion)
+	if err != nil {
+		return fmt.Errorf("failed to check tag %s: %w", tag.Name, err)
+	}
+	released, err := git.VerifyTagMatchesRelease(r.projectPath, tag.Name, versions)
+	if err != nil {
+		return fmt.Errorf("failed to check tag %s: %w", tag.Name, err)
+	}
+	if !released {
+		return fmt.Errorf("tag %s already exists and does not point at the release of %s %s; delete it with `git tag -d %s` and run `shipyard version --resume` again",
+			tag.Name, tag.Package, newVersion, tag.Name)
+	}
+	return nil
+}
+
+// rollback undoes what a version run applied: tags it created, its
+// release commit and every backed-up file. The checkpoint is removed once
+// everything is undone and kept otherwise, so the rollback can be retried.
+// Returns what could not be rolled back.
+func rollback(projectPath string, run *runstate.Run, tx *FileTransaction) []error {
+	var problems []error
+
+	if run.Started(runstate.PhaseTags) {
+		var created []string
+		for _, tag := range run.Tags {
+			if tag.Existing {
+				continue
+			}
+			exists, err := git.VerifyTagExists(projectPath, tag.Name)
+			if err != nil {
+				problems = append(problems, fmt.Errorf("failed to check tag %s: %w", tag.Name, err))
+				continue
+			}
+			if exists {
+				created = append(created, tag.Name)
+			}
+		}
+		if len(created) > 0 {
+			if err := git.DeleteTags(projectPath, created); err != nil {
+				problems = append(problems, fmt.Errorf("failed to delete created tags: %w", err))
+			}
+		}
+	}
+
+	if run.Started(runstate.PhaseCommit) && run.OriginalHead != "" {
+		originalHead := plumbing.NewHash(run.OriginalHead)
+		head, err := git.HeadHash(projectPath)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("failed to roll back git commit: %w", err))
+		} else if head != originalHead {
+			if err := git.ResetMixed(projectPath, originalHead); err != nil {
+				problems = append(problems, fmt.Errorf("failed to roll back git commit: %w", err))
+			}
+		}
+	}
+
+	if run.Started(runstate.PhaseHooks) && len(run.HookFiles) > 0 {
+		if err := git.RestoreFiles(projectPath, run.HookFiles); err != nil {
+			problems = append(problems, fmt.Errorf("failed to restore files changed by hooks: %w", err))
+		}
+	}
+
+	if err := tx.Rollback(); err != nil {
+		problems = append(problems, fmt.Errorf("failed to roll back filesystem changes: %w", err))
+	}
+
+	if len(problems) == 0 {
+		if err := runstate.Delete(runstate.Path(projectPath)); err != nil {
+			problems = append(problems, err)
+		}
+	}
+	return problems
+}
+
+// abort rolls back an interrupted run from its checkpoint, reporting
+// anything that needs manual attention
+func abort(projectPath string, run *runstate.Run) error {
+	problems := rollback(projectPath, run, RestoreFileTransaction(projectPath, run.Backups))
+	if len(problems) > 0 {
+		lines := make([]string, len(problems))
+		for i, problem := range problems {
+			lines[i] = "  - " + problem.Error()
+		}
+		return fmt.Errorf("could not fully roll back the interrupted version run; fix these by hand, then run `shipyard version --abort-run` again:\n%s", strings.Join(lines, "\n"))
+	}
+	return nil
+}
+
+// DescribeProgress summarizes the phases a run completed
+func DescribeProgress(phases []runstate.Phase) string {
+	if len(phases) == 0 {
+		return i18n.T("version.progress_none")
+	}
+	completed := make([]string, len(phases))
+	for i, phase := range phases {
+		completed[i] = string(phase)
+	}
+	return i18n.T("version.progress", strings.Join(completed, ", "))
+}
+
+// bumpsFromRun rebuilds the version bumps recorded in a run
+func bumpsFromRun(run *runstate.Run) (map[string]version.VersionBump, error) {
+	// The old version of a package that just switched to CalVer is still SemVer
+	parse := func(bump runstate.Bump, s string) (semver.Version, error) {
+		if bump.CalVer != "" {
+			if v, err := semver.ParseCalVer(bump.CalVer, s); err == nil {
+				return v, nil
+			}
+		}
+		return semver.Parse(s)
+	}
+
+	bumps := make(map[string]version.VersionBump, len(run.Bumps))
+	for _, bump := range run.Bumps {
+		oldVersion, err := parse(bump, bump.OldVersion)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recorded version for %s: %w", bump.Package, err)
+		}
+		newVersion, err := parse(bump, bump.NewVersion)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recorded version for %s: %w", bump.Package, err)
+		}
+		bumps[bump.Package] = version.VersionBump{
+			Package:    bump.Package,
+			OldVersion: oldVersion,
+			NewVersion: newVersion,
+			ChangeType: bump.ChangeType,
+			Source:     bump.Source,
+		}
+	}
+	return bumps, nil
+}
@@ -1,4 +1,4 @@
-package commands
+package release
 
 import (
 	"fmt"
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 
 	"github.com/NatoNathan/shipyard/internal/fileutil"
+	"github.com/NatoNathan/shipyard/internal/runstate"
 )
 
 type fileSnapshot struct {
@@ -15,16 +16,16 @@ type fileSnapshot struct {
 	exists bool
 }
 
-type fileTransaction struct {
+type FileTransaction struct {
 	snapshots map[string]fileSnapshot
 	order     []string
 }
 
-func newFileTransaction() *fileTransaction {
-	return &fileTransaction{snapshots: make(map[string]fileSnapshot)}
+func NewFileTransaction() *FileTransaction {
+	return &FileTransaction{snapshots: make(map[string]fileSnapshot)}
 }
 
-func (tx *fileTransaction) Backup(path string) error {
+func (tx *FileTransaction) Backup(path string) error {
 	if _, ok := tx.snapshots[path]; ok {
 		return nil
 	}
@@ -50,7 +51,7 @@ func (tx *fileTransaction) Backup(path string) error {
 	return nil
 }
 
-func (tx *fileTransaction) Rollback() error {
+func (tx *FileTransaction) Rollback() error {
 	var rollbackErr error
 	for i := len(tx.order) - 1; i >= 0; i-- {
 		snapshot := tx.snapshots[tx.order[i]]
@@ -82,3 +83,35 @@ func joinRollbackError(existing error, next error) error {
 	}
 	return fmt.Errorf("%v; %w", existing, next)
 }
+
+// Backups returns the snapshots with paths relative to root, in the order
+// they were taken, for recording in a run checkpoint
+func (tx *FileTransaction) Backups(root string) ([]runstate.Backup, error) {
+	backups := make([]runstate.Backup, 0, len(tx.order))
+	for _, path := range tx.order {
+		snapshot := tx.snapshots[path]
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to record backup of %s: %w", path, err)
+		}
+		backups = append(backups, runstate.Backup{
+			Path:   filepath.ToSlash(rel),
+			Exists: snapshot.exists,
+			Mode:   snapshot.mode,
+			Data:   snapshot.data,
+		})
+	}
+	return backups, nil
+}
+
+// RestoreFileTransaction rebuilds a transaction from backups recorded by
+// Backups, so a later process can roll back the files it protected
+func RestoreFileTransaction(root string, backups []runstate.Backup) *FileTransaction {
+	tx := NewFileTransaction()
+	for _, backup := range backups {
+		path := filepath.Join(root, filepath.FromSlash(backup.Path))
+		tx.snapshots[path] = fileSnapshot{path: path, data: backup.Data, mode: backup.Mode, exists: backup.Exists}
+		tx.order = append(tx.order, path)
+	}
+	return tx
+}
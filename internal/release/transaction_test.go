@@ -1,4 +1,4 @@
-package commands
+package release
 
 import (
 	"os"
@@ -13,7 +13,7 @@ func TestFileTransactionRollbackPreservesFileMode(t *testing.T) {
 	path := filepath.Join(t.TempDir(), "script.sh")
 	require.NoError(t, os.WriteFile(path, []byte("original"), 0750))
 
-	tx := newFileTransaction()
+	tx := NewFileTransaction()
 	require.NoError(t, tx.Backup(path))
 	require.NoError(t, os.WriteFile(path, []byte("changed"), 0644))
 	require.NoError(t, os.Chmod(path, 0644))
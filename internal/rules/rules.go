@@ -0,0 +1,246 @@
+// Package rules assigns severity levels to validation and pre-flight checks.
+//
+// Every check has a rule ID and a default level. Projects override levels per
+// rule in the config's rules map, and the --max-severity flag overrides every
+// enabled rule at once, so new checks can be rolled out as warnings first.
+package rules
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Level is the severity of a rule
+type Level string
+
+// Rule levels
+const (
+	LevelOff   Level = "off"   // The check does not run or its findings are dropped
+	LevelWarn  Level = "warn"  // Findings are reported but never block
+	LevelError Level = "error" // Findings fail the command
+)
+
+// Rule IDs
+const (
+	ConsignmentParse = "consignment-parse"
+	StaleConsignment = "stale-consignment"
+	DependencyConfig = "dependency-config"
+	DependencyCycle  = "dependency-cycle"
+	MessageSize      = "message-size"
+	TagCollision     = "tag-collision"
+	ReleaseBoundary  = "release-boundary"
+	PackageManifest  = "package-manifest"
+	TemplateSyntax   = "template-syntax"
+)
+
+// Rule describes a check and its default level
+type Rule struct {
+	ID          string
+	Default     Level
+	Description string
+}
+
+// registry lists every known rule
+var registry = []Rule{
+	{ID: ConsignmentParse, Default: LevelError, Description: "consignment files must parse"},
+	{ID: StaleConsignment, Default: LevelWarn, Description: "consignments must only reference configured packages"},
+	{ID: DependencyConfig, Default: LevelError, Description: "package dependencies must reference configured packages"},
+	{ID: DependencyCycle, Default: LevelWarn, Description: "the dependency graph should not contain cycles"},
+	{ID: MessageSize, Default: LevelError, Description: "commit messages and tag annotations must fit templates.maxMessageBytes"},
+	{ID: TagCollision, Default: LevelError, Description: "release tags must not already exist"},
+	{ID: ReleaseBoundary, Default: LevelError, Description: "adding to a pending major or yanked release must be acknowledged"},
+	{ID: PackageManifest, Default: LevelError, Description: "package paths must exist and their version files must parse"},
+	{ID: TemplateSyntax, Default: LevelError, Description: "configured templates must load and parse"},
+}
+
+// All returns every known rule sorted by ID
+func All() []Rule {
+	all := append([]Rule{}, registry...)
+	sort.Slice(all, func(i, j int) bool { return all[i].ID < all[j].ID })
+	return all
+}
+
+// Lookup returns the rule with the given ID
+func Lookup(id string) (Rule, bool) {
+	for _, rule := range registry {
+		if rule.ID == id {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+// ParseLevel parses a rule level name
+func ParseLevel(s string) (Level, error) {
+	switch level := Level(strings.ToLower(strings.TrimSpace(s))); level {
+	case LevelOff, LevelWarn, LevelError:
+		return level, nil
+	default:
+		return "", fmt.Errorf("invalid rule level %q (must be off, warn, or error)", s)
+	}
+}
+
+// ValidateOverrides checks that every configured override names a known rule
+// and a valid level
+func ValidateOverrides(overrides map[string]string) error {
+	ids := make([]string, 0, len(overrides))
+	for id := range overrides {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		if _, ok := Lookup(id); !ok {
+			return fmt.Errorf("unknown rule %q", id)
+		}
+		if _, err := ParseLevel(overrides[id]); err != nil {
+			return fmt.Errorf("rule %q: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// Resolver resolves the effective level of each rule.
+// Precedence: --max-severity flag, then config overrides, then rule defaults.
+type Resolver struct {
+	overrides   map[string]Level
+	maxSeverity Level
+}
+
+// NewResolver creates a resolver from config overrides and the --max-severity
+// flag value. An empty maxSeverity leaves levels as configured.
+func NewResolver(overrides map[string]string, maxSeverity string) (*Resolver, error) {
+	if err := ValidateOverrides(overrides); err != nil {
+		return nil, err
+	}
+
+	r := &Resolver{overrides: make(map[string]Level, len(overrides))}
+	for id, value := range overrides {
+		level, _ := ParseLevel(value)
+		r.overrides[id] = level
+	}
+
+	if maxSeverity != "" {
+		level, err := ParseLevel(maxSeverity)
+		if err != nil {
+			return nil, fmt.Errorf("--max-severity: %w", err)
+		}
+		if level == LevelOff {
+			return nil, fmt.Errorf("--max-severity: must be warn or error")
+		}
+		r.maxSeverity = level
+	}
+	return r, nil
+}
+
+// Level returns the effective level of a rule. Rules that are off after config
+// resolution stay off; --max-severity applies to every enabled rule.
+func (r *Resolver) Level(id string) Level {
+	level := LevelError
+	if rule, ok := Lookup(id); ok {
+		level = rule.Default
+	}
+	if override, ok := r.overrides[id]; ok {
+		level = override
+	}
+	if level != LevelOff && r.maxSeverity != "" {
+		level = r.maxSeverity
+	}
+	return level
+}
+
+// Enabled reports whether a rule's check should run
+func (r *Resolver) Enabled(id string) bool {
+	return r.Level(id) != LevelOff
+}
+
+// Finding is a single rule violation. File and Field locate it when known.
+type Finding struct {
+	Rule    string `json:"rule"`
+	Level   Level  `json:"level"`
+	File    string `json:"file,omitempty"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// String formats the finding with its location and rule ID so users know
+// where to look and what to configure
+func (f Finding) String() string {
+	location := ""
+	if f.File != "" {
+		location = f.File + ": "
+	}
+	if f.Field != "" {
+		location += f.Field + ": "
+	}
+	return fmt.Sprintf("%s%s [%s]", location, f.Message, f.Rule)
+}
+
+// Report collects findings at their resolved levels
+type Report struct {
+	resolver *Resolver
+	Findings []Finding
+}
+
+// NewReport creates an empty report that resolves levels with resolver
+func NewReport(resolver *Resolver) *Report {
+	return &Report{resolver: resolver}
+}
+
+// Add records a finding for a rule. Findings for disabled rules are dropped.
+func (r *Report) Add(id, message string) {
+	r.AddAt(id, "", "", message)
+}
+
+// AddAt records a finding located at a file and, optionally, a field within it
+func (r *Report) AddAt(id, file, field, message string) {
+	level := r.resolver.Level(id)
+	if level == LevelOff {
+		return
+	}
+	r.Findings = append(r.Findings, Finding{Rule: id, Level: level, File: file, Field: field, Message: message})
+}
+
+// Addf records a formatted finding for a rule
+func (r *Report) Addf(id, format string, args ...interface{}) {
+	r.Add(id, fmt.Sprintf(format, args...))
+}
+
+// Errors returns the error-level findings
+func (r *Report) Errors() []Finding {
+	return r.filter(LevelError)
+}
+
+// Warnings returns the warn-level findings
+func (r *Report) Warnings() []Finding {
+	return r.filter(LevelWarn)
+}
+
+// HasErrors reports whether any finding blocks the command
+func (r *Report) HasErrors() bool {
+	return len(r.Errors()) > 0
+}
+
+func (r *Report) filter(level Level) []Finding {
+	var result []Finding
+	for _, f := range r.Findings {
+		if f.Level == level {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+// Err returns an error describing the error-level findings, or nil
+func (r *Report) Err() error {
+	errs := r.Errors()
+	if len(errs) == 0 {
+		return nil
+	}
+	messages := make([]string, len(errs))
+	for i, f := range errs {
+		messages[i] = f.String()
+	}
+	return fmt.Errorf("%s", strings.Join(messages, "; "))
+}
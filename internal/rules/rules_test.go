@@ -0,0 +1,88 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolver_Level(t *testing.T) {
+	tests := []struct {
+		name        string
+		overrides   map[string]string
+		maxSeverity string
+		rule        string
+		want        Level
+	}{
+		{name: "default error", rule: TagCollision, want: LevelError},
+		{name: "default warn", rule: DependencyCycle, want: LevelWarn},
+		{name: "config overrides default", overrides: map[string]string{TagCollision: "warn"}, rule: TagCollision, want: LevelWarn},
+		{name: "config can disable", overrides: map[string]string{StaleConsignment: "off"}, rule: StaleConsignment, want: LevelOff},
+		{name: "config level is case-insensitive", overrides: map[string]string{DependencyCycle: "ERROR"}, rule: DependencyCycle, want: LevelError},
+		{name: "flag escalates default", maxSeverity: "error", rule: DependencyCycle, want: LevelError},
+		{name: "flag overrides config", overrides: map[string]string{MessageSize: "warn"}, maxSeverity: "error", rule: MessageSize, want: LevelError},
+		{name: "flag relaxes config", overrides: map[string]string{MessageSize: "error"}, maxSeverity: "warn", rule: MessageSize, want: LevelWarn},
+		{name: "flag leaves disabled rules off", overrides: map[string]string{StaleConsignment: "off"}, maxSeverity: "error", rule: StaleConsignment, want: LevelOff},
+		{name: "unknown rule defaults to error", rule: "not-a-rule", want: LevelError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver, err := NewResolver(tt.overrides, tt.maxSeverity)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, resolver.Level(tt.rule))
+		})
+	}
+}
+
+func TestNewResolver_Invalid(t *testing.T) {
+	_, err := NewResolver(map[string]string{"tag-colision": "warn"}, "")
+	assert.ErrorContains(t, err, `unknown rule "tag-colision"`)
+
+	_, err = NewResolver(map[string]string{TagCollision: "fatal"}, "")
+	assert.ErrorContains(t, err, "invalid rule level")
+
+	_, err = NewResolver(nil, "off")
+	assert.ErrorContains(t, err, "--max-severity")
+
+	_, err = NewResolver(nil, "loud")
+	assert.ErrorContains(t, err, "--max-severity")
+}
+
+func TestReport(t *testing.T) {
+	resolver, err := NewResolver(map[string]string{StaleConsignment: "off"}, "")
+	require.NoError(t, err)
+
+	report := NewReport(resolver)
+	report.Add(StaleConsignment, "dropped")
+	report.Addf(DependencyCycle, "cycle %s", "a -> b -> a")
+	assert.NoError(t, report.Err(), "warnings never block")
+
+	report.Add(TagCollision, "tag v1.0.0 exists")
+
+	require.Len(t, report.Findings, 2)
+	assert.Equal(t, []Finding{{Rule: DependencyCycle, Level: LevelWarn, Message: "cycle a -> b -> a"}}, report.Warnings())
+	assert.True(t, report.HasErrors())
+	assert.EqualError(t, report.Err(), "tag v1.0.0 exists [tag-collision]")
+}
+
+func TestFinding_String(t *testing.T) {
+	assert.Equal(t, "tag exists [tag-collision]", Finding{Rule: TagCollision, Message: "tag exists"}.String())
+	assert.Equal(t, "a.md: changeType: invalid [consignment-parse]",
+		Finding{Rule: ConsignmentParse, File: "a.md", Field: "changeType", Message: "invalid"}.String())
+	assert.Equal(t, "a.md: invalid [consignment-parse]",
+		Finding{Rule: ConsignmentParse, File: "a.md", Message: "invalid"}.String())
+}
+
+func TestAll(t *testing.T) {
+	all := All()
+	require.NotEmpty(t, all)
+	for i, rule := range all {
+		assert.NotEmpty(t, rule.Description, rule.ID)
+		assert.Contains(t, []Level{LevelWarn, LevelError}, rule.Default, rule.ID)
+		if i > 0 {
+			assert.Less(t, all[i-1].ID, rule.ID)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package runstate
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/fileutil"
+	"github.com/NatoNathan/shipyard/internal/history"
+)
+
+// Phase names a mutating step of a version run
+type Phase string
+
+const (
+	PhaseVersions     Phase = "versions"     // version files updated
+	PhaseHistory      Phase = "history"      // release entries appended to history
+	PhaseChangelogs   Phase = "changelogs"   // changelogs regenerated
+	PhaseConsignments Phase = "consignments" // released consignments and pre-release state removed
+	PhaseHooks        Phase = "hooks"        // postVersion hooks run
+	PhaseCommit       Phase = "commit"       // release commit created
+	PhaseTags         Phase = "tags"         // release tags created
+)
+
+// Phases lists the mutating phases in the order a version run applies them
+var Phases = []Phase{PhaseVersions, PhaseHistory, PhaseChangelogs, PhaseConsignments, PhaseHooks, PhaseCommit, PhaseTags}
+
+// Run is the checkpoint of a version run (.shipyard/state/run.json). It holds
+// the plan computed before any file changed, the phases completed so far and
+// backups of every file the run may touch, so an interrupted run can be
+// finished without recomputing from inputs it already modified, or rolled back.
+type Run struct {
+	StartedAt     time.Time       `json:"startedAt"`
+	Phase         Phase           `json:"phase,omitempty"` // phase in progress or last completed
+	Completed     []Phase         `json:"completed"`
+	Options       Options         `json:"options"`
+	Bumps         []Bump          `json:"bumps"` // in apply order
+	Tags          []Tag           `json:"tags,omitempty"`
+	CommitMessage string          `json:"commitMessage,omitempty"`
+	History       []history.Entry `json:"history"`
+	Consignments  []string        `json:"consignments"`           // released consignment files
+	Prerelease    bool            `json:"prerelease,omitempty"`   // pre-release state is cleared
+	HookFiles     []string        `json:"hookFiles,omitempty"`    // files postVersion hooks changed, staged with the release
+	Staged        []string        `json:"staged,omitempty"`       // files staged for the release commit
+	OriginalHead  string          `json:"originalHead,omitempty"` // HEAD before the run
+	Backups       []Backup        `json:"backups"`
+}
+
+// Options records the flags of the interrupted run that affect the remaining phases
+type Options struct {
+	NoCommit  bool   `json:"noCommit,omitempty"`
+	NoTag     bool   `json:"noTag,omitempty"`
+	NoPublish bool   `json:"noPublish,omitempty"`
+	Template  string `json:"template,omitempty"`
+	Push      bool   `json:"push,omitempty"`
+
+	GitHubRelease bool `json:"githubRelease,omitempty"`
+	Draft         bool `json:"draft,omitempty"`
+
+	Manifest string `json:"manifest,omitempty"` // absolute path the release manifest is written to
+
+	IncludeUnreleased bool `json:"includeUnreleased,omitempty"` // list pending consignments under Unreleased in changelogs
+
+	NoHooks bool `json:"noHooks,omitempty"` // skip configured hooks
+
+	Channel string `json:"channel,omitempty"` // release channel, whose history the run writes
+}
+
+// Bump is a planned version change. CalVer holds the calendar version format
+// of calendar-versioned packages.
+type Bump struct {
+	Package    string `json:"package"`
+	OldVersion string `json:"oldVersion"`
+	NewVersion string `json:"newVersion"`
+	ChangeType string `json:"changeType"`
+	Source     string `json:"source,omitempty"`
+	CalVer     string `json:"calver,omitempty"`
+}
+
+// Tag is a rendered release tag. Existing tags were present before the run
+// and are not created.
+type Tag struct {
+	Package  string `json:"package"`
+	Name     string `json:"name"`
+	Message  string `json:"message,omitempty"`
+	Existing bool   `json:"existing,omitempty"`
+}
+
+// Backup is a file's content before the run; a file that did not exist is
+// removed on rollback. Paths are relative to the project root.
+type Backup struct {
+	Path   string      `json:"path"`
+	Exists bool        `json:"exists"`
+	Mode   os.FileMode `json:"mode,omitempty"`
+	Data   []byte      `json:"data,omitempty"`
+}
+
+// Path returns the checkpoint path for a project
+func Path(projectPath string) string {
+	return filepath.Join(projectPath, ".shipyard", "state", "run.json")
+}
+
+// Start records phase as in progress
+func (r *Run) Start(phase Phase) {
+	r.Phase = phase
+}
+
+// Complete records phase as finished
+func (r *Run) Complete(phase Phase) {
+	if !r.Done(phase) {
+		r.Completed = append(r.Completed, phase)
+	}
+}
+
+// Done reports whether phase has finished
+func (r *Run) Done(phase Phase) bool {
+	return slices.Contains(r.Completed, phase)
+}
+
+// Started reports whether phase was started, whether or not it finished
+func (r *Run) Started(phase Phase) bool {
+	return r.Done(phase) || r.Phase == phase
+}
+
+// Read reads the checkpoint at path
+func Read(path string) (*Run, error) {
+	data, err := fileutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read run state: %w", err)
+	}
+
+	var run Run
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("failed to parse run state %s: %w", path, err)
+	}
+	return &run, nil
+}
+
+// Write writes the checkpoint to path atomically (write to temp file, then
+// rename), so a crash never leaves a torn checkpoint behind
+func Write(path string, run *Run) error {
+	data, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal run state: %w", err)
+	}
+
+	if err := fileutil.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create run state directory: %w", err)
+	}
+
+	tempPath := path + ".tmp"
+	if err := fileutil.WriteFile(tempPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+
+	if err := os.Rename(tempPath, path); err != nil {
+		_ = os.Remove(tempPath)
+		return fmt.Errorf("failed to rename temp file: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes the checkpoint. Returns nil if it does not exist.
+func Delete(path string) error {
+	err := os.Remove(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete run state: %w", err)
+	}
+	return nil
+}
+
+// Exists checks if a checkpoint exists at path
+func Exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
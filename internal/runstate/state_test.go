@@ -0,0 +1,68 @@
+package runstate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/history"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteRead(t *testing.T) {
+	path := Path(t.TempDir())
+	run := &Run{
+		StartedAt: time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC),
+		Options:   Options{NoPublish: true, Template: "builtin:keepachangelog"},
+		Bumps:     []Bump{{Package: "core", OldVersion: "1.0.0", NewVersion: "1.1.0", ChangeType: "minor"}},
+		Tags:      []Tag{{Package: "core", Name: "v1.1.0", Message: "Release 1.1.0"}},
+		History:   []history.Entry{{Package: "core", Version: "1.1.0", Tag: "v1.1.0"}},
+		Backups: []Backup{
+			{Path: "core/version.go", Exists: true, Mode: 0644, Data: []byte("package core\n")},
+			{Path: "core/CHANGELOG.md"},
+		},
+	}
+	run.Start(PhaseVersions)
+	run.Complete(PhaseVersions)
+
+	require.NoError(t, Write(path, run))
+	assert.True(t, Exists(path))
+	leftovers, err := filepath.Glob(filepath.Join(filepath.Dir(path), "*.tmp"))
+	require.NoError(t, err)
+	assert.Empty(t, leftovers)
+
+	read, err := Read(path)
+	require.NoError(t, err)
+	assert.Equal(t, run, read)
+
+	require.NoError(t, Delete(path))
+	assert.False(t, Exists(path))
+	require.NoError(t, Delete(path), "deleting a missing checkpoint is not an error")
+}
+
+func TestRead_Corrupt(t *testing.T) {
+	path := Path(t.TempDir())
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0644))
+
+	_, err := Read(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse run state")
+}
+
+func TestRunPhases(t *testing.T) {
+	run := &Run{}
+	assert.False(t, run.Started(PhaseHistory))
+
+	run.Start(PhaseHistory)
+	assert.True(t, run.Started(PhaseHistory))
+	assert.False(t, run.Done(PhaseHistory))
+
+	run.Complete(PhaseHistory)
+	run.Complete(PhaseHistory)
+	assert.True(t, run.Done(PhaseHistory))
+	assert.Equal(t, []Phase{PhaseHistory}, run.Completed)
+	assert.False(t, run.Started(PhaseCommit))
+}
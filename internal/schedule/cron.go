@@ -0,0 +1,199 @@
+// Package schedule computes release windows from cron expressions.
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// searchYears bounds how far ahead Next looks for a matching time, so
+// expressions that can never fire (e.g. 30 February) terminate
+const searchYears = 5
+
+// Cron is a parsed five-field cron expression: minute, hour, day of month,
+// month and day of week
+type Cron struct {
+	expr    string
+	minutes []int
+	hours   []int
+	days    map[int]bool
+	months  map[int]bool
+	weekday map[int]bool
+
+	// Standard cron semantics: when both day fields are restricted a day
+	// matches if either field matches
+	daysStar    bool
+	weekdayStar bool
+}
+
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField  = cronField{name: "minute", min: 0, max: 59}
+	hourField    = cronField{name: "hour", min: 0, max: 23}
+	dayField     = cronField{name: "day of month", min: 1, max: 31}
+	monthField   = cronField{name: "month", min: 1, max: 12, names: map[string]int{"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6, "jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12}}
+	weekdayField = cronField{name: "day of week", min: 0, max: 7, names: map[string]int{"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6}}
+)
+
+// ParseCron parses a standard five-field cron expression. Fields accept *,
+// single values, ranges (1-5), steps (*/15, 1-10/2) and comma lists; months
+// and weekdays also accept three-letter names, and 7 means Sunday.
+func ParseCron(expr string) (*Cron, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 fields, got %d", expr, len(fields))
+	}
+
+	c := &Cron{expr: expr}
+	parsed := make([]map[int]bool, 5)
+	for i, f := range []cronField{minuteField, hourField, dayField, monthField, weekdayField} {
+		values, err := f.parse(fields[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+		}
+		parsed[i] = values
+	}
+
+	c.minutes = sortedKeys(parsed[0], minuteField)
+	c.hours = sortedKeys(parsed[1], hourField)
+	c.days = parsed[2]
+	c.months = parsed[3]
+	c.weekday = parsed[4]
+	if c.weekday[7] {
+		c.weekday[0] = true
+	}
+	c.daysStar = strings.HasPrefix(fields[2], "*")
+	c.weekdayStar = strings.HasPrefix(fields[4], "*")
+
+	return c, nil
+}
+
+// String returns the expression the schedule was parsed from
+func (c *Cron) String() string {
+	return c.expr
+}
+
+// Next returns the first time strictly after t that matches the expression,
+// evaluated on the wall clock of t's location. Wall-clock times skipped by a
+// daylight saving transition never match. The zero time is returned when
+// nothing matches within a few years.
+func (c *Cron) Next(t time.Time) time.Time {
+	loc := t.Location()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	limit := day.AddDate(searchYears, 0, 0)
+
+	for ; day.Before(limit); day = day.AddDate(0, 0, 1) {
+		if !c.matchesDay(day) {
+			continue
+		}
+		for _, h := range c.hours {
+			for _, m := range c.minutes {
+				candidate := time.Date(day.Year(), day.Month(), day.Day(), h, m, 0, 0, loc)
+				if candidate.Hour() != h || candidate.Minute() != m || candidate.Day() != day.Day() {
+					continue
+				}
+				if candidate.After(t) {
+					return candidate
+				}
+			}
+		}
+	}
+	return time.Time{}
+}
+
+func (c *Cron) matchesDay(day time.Time) bool {
+	if !c.months[int(day.Month())] {
+		return false
+	}
+	dom := c.days[day.Day()]
+	dow := c.weekday[int(day.Weekday())]
+	switch {
+	case c.daysStar && c.weekdayStar:
+		return true
+	case c.daysStar:
+		return dow
+	case c.weekdayStar:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+func (f cronField) parse(field string) (map[int]bool, error) {
+	values := make(map[int]bool)
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			rangePart = part[:i]
+			s, err := strconv.Atoi(part[i+1:])
+			if err != nil || s <= 0 {
+				return nil, fmt.Errorf("invalid step in %s field %q", f.name, part)
+			}
+			step = s
+		}
+
+		var lo, hi int
+		switch {
+		case rangePart == "*":
+			lo, hi = f.min, f.max
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if lo, err = f.value(bounds[0]); err != nil {
+				return nil, err
+			}
+			if hi, err = f.value(bounds[1]); err != nil {
+				return nil, err
+			}
+			if lo > hi {
+				return nil, fmt.Errorf("invalid range in %s field %q", f.name, part)
+			}
+		default:
+			v, err := f.value(rangePart)
+			if err != nil {
+				return nil, err
+			}
+			lo, hi = v, v
+			if step > 1 {
+				// "5/15" means every 15 starting at 5
+				hi = f.max
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			values[v] = true
+		}
+	}
+	return values, nil
+}
+
+func (f cronField) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q", f.name, s)
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("%s %d out of range %d-%d", f.name, v, f.min, f.max)
+	}
+	return v, nil
+}
+
+func sortedKeys(values map[int]bool, f cronField) []int {
+	var keys []int
+	for v := f.min; v <= f.max; v++ {
+		if values[v] {
+			keys = append(keys, v)
+		}
+	}
+	return keys
+}
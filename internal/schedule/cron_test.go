@@ -0,0 +1,111 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCron_Errors(t *testing.T) {
+	tests := []struct {
+		expr   string
+		errMsg string
+	}{
+		{expr: "0 10 * *", errMsg: "expected 5 fields"},
+		{expr: "60 10 * * *", errMsg: "minute 60 out of range"},
+		{expr: "0 24 * * *", errMsg: "hour 24 out of range"},
+		{expr: "0 10 * * fri-mon", errMsg: "invalid range"},
+		{expr: "*/0 * * * *", errMsg: "invalid step"},
+		{expr: "0 10 * foo *", errMsg: `invalid month "foo"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := ParseCron(tt.expr)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestCron_Next(t *testing.T) {
+	// 2026-10-15 is a Thursday
+	base := time.Date(2026, 10, 15, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{
+			name: "later the same day",
+			expr: "0 10 * * 4",
+			from: base,
+			want: time.Date(2026, 10, 15, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "strictly after a matching time",
+			expr: "0 10 * * thu",
+			from: time.Date(2026, 10, 15, 10, 0, 0, 0, time.UTC),
+			want: time.Date(2026, 10, 22, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "steps",
+			expr: "*/15 * * * *",
+			from: base,
+			want: time.Date(2026, 10, 15, 9, 45, 0, 0, time.UTC),
+		},
+		{
+			name: "lists and ranges",
+			expr: "0 9-17/4 * * mon,wed",
+			from: base,
+			want: time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "7 is sunday",
+			expr: "0 0 * * 7",
+			from: base,
+			want: time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "day of month or day of week",
+			expr: "0 0 1 * 1",
+			from: time.Date(2026, 10, 27, 0, 0, 0, 0, time.UTC),
+			want: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "month names",
+			expr: "0 12 25 dec *",
+			from: base,
+			want: time.Date(2026, 12, 25, 12, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := ParseCron(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, c.Next(tt.from))
+		})
+	}
+}
+
+func TestCron_NextNeverMatches(t *testing.T) {
+	c, err := ParseCron("0 0 30 feb *")
+	require.NoError(t, err)
+	assert.True(t, c.Next(time.Now()).IsZero())
+}
+
+func TestCron_NextSkipsDSTGap(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	require.NoError(t, err)
+
+	// Clocks go from 01:00 to 02:00 on 2026-03-29, so 01:30 does not exist
+	c, err := ParseCron("30 1 * * *")
+	require.NoError(t, err)
+	next := c.Next(time.Date(2026, 3, 28, 12, 0, 0, 0, london))
+	assert.Equal(t, time.Date(2026, 3, 30, 1, 30, 0, 0, london), next)
+}
@@ -0,0 +1,80 @@
+package schedule
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultGrace is how long a release window stays open when no grace period
+// is configured
+const DefaultGrace = 24 * time.Hour
+
+// Window is a period during which releases are allowed
+type Window struct {
+	Opens  time.Time `json:"opens"`
+	Closes time.Time `json:"closes"`
+}
+
+// Schedule opens a release window each time its cron expression fires and
+// keeps it open for the grace period
+type Schedule struct {
+	cron     *Cron
+	location *time.Location
+	grace    time.Duration
+}
+
+// Status describes the release windows around a point in time
+type Status struct {
+	Open    bool    `json:"open"`
+	Current *Window `json:"current,omitempty"` // The open window, if any
+	Next    *Window `json:"next,omitempty"`    // The next window to open
+}
+
+// New creates a schedule. An empty timezone means UTC and a zero grace means
+// DefaultGrace.
+func New(expr, timezone string, grace time.Duration) (*Schedule, error) {
+	cron, err := ParseCron(expr)
+	if err != nil {
+		return nil, err
+	}
+	loc := time.UTC
+	if timezone != "" {
+		loc, err = time.LoadLocation(timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone %q: %w", timezone, err)
+		}
+	}
+	if grace < 0 {
+		return nil, fmt.Errorf("grace period must not be negative")
+	}
+	if grace == 0 {
+		grace = DefaultGrace
+	}
+	return &Schedule{cron: cron, location: loc, grace: grace}, nil
+}
+
+// Location returns the timezone the cron expression is evaluated in
+func (s *Schedule) Location() *time.Location {
+	return s.location
+}
+
+// Grace returns how long each window stays open
+func (s *Schedule) Grace() time.Duration {
+	return s.grace
+}
+
+// Status reports whether a window is open at now and when the next one opens
+func (s *Schedule) Status(now time.Time) Status {
+	now = now.In(s.location)
+	var status Status
+
+	// A window opened at f contains now when now-grace < f <= now
+	if opens := s.cron.Next(now.Add(-s.grace)); !opens.IsZero() && !opens.After(now) {
+		status.Open = true
+		status.Current = &Window{Opens: opens, Closes: opens.Add(s.grace)}
+	}
+	if opens := s.cron.Next(now); !opens.IsZero() {
+		status.Next = &Window{Opens: opens, Closes: opens.Add(s.grace)}
+	}
+	return status
+}
@@ -0,0 +1,84 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedule_Status(t *testing.T) {
+	// Thursdays at 10:00 London time with a 6 hour window. On 2026-10-15
+	// London is on BST (UTC+1), so the window is 09:00-15:00 UTC.
+	sched, err := New("0 10 * * 4", "Europe/London", 6*time.Hour)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		now       time.Time
+		wantOpen  bool
+		wantNext  time.Time
+		wantClose time.Time
+	}{
+		{
+			name:     "just before the window",
+			now:      time.Date(2026, 10, 15, 8, 59, 59, 0, time.UTC),
+			wantNext: time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "window opens",
+			now:       time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC),
+			wantOpen:  true,
+			wantClose: time.Date(2026, 10, 15, 15, 0, 0, 0, time.UTC),
+			wantNext:  time.Date(2026, 10, 22, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "last moment of the window",
+			now:       time.Date(2026, 10, 15, 14, 59, 59, 0, time.UTC),
+			wantOpen:  true,
+			wantClose: time.Date(2026, 10, 15, 15, 0, 0, 0, time.UTC),
+			wantNext:  time.Date(2026, 10, 22, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "window closed",
+			now:      time.Date(2026, 10, 15, 15, 0, 0, 0, time.UTC),
+			wantNext: time.Date(2026, 10, 22, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "after the clocks change",
+			now:  time.Date(2026, 10, 26, 12, 0, 0, 0, time.UTC),
+			// GMT from 25 October, so 10:00 local is 10:00 UTC
+			wantNext: time.Date(2026, 10, 29, 10, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := sched.Status(tt.now)
+			assert.Equal(t, tt.wantOpen, status.Open)
+			if tt.wantOpen {
+				require.NotNil(t, status.Current)
+				assert.True(t, tt.wantClose.Equal(status.Current.Closes), "closes %s", status.Current.Closes)
+			} else {
+				assert.Nil(t, status.Current)
+			}
+			require.NotNil(t, status.Next)
+			assert.True(t, tt.wantNext.Equal(status.Next.Opens), "next opens %s", status.Next.Opens)
+			assert.Equal(t, "Europe/London", status.Next.Opens.Location().String())
+		})
+	}
+}
+
+func TestNew_Defaults(t *testing.T) {
+	sched, err := New("0 10 * * 4", "", 0)
+	require.NoError(t, err)
+	assert.Equal(t, time.UTC, sched.Location())
+	assert.Equal(t, DefaultGrace, sched.Grace())
+
+	_, err = New("0 10 * * 4", "Mars/Olympus", 0)
+	assert.ErrorContains(t, err, `invalid timezone "Mars/Olympus"`)
+
+	_, err = New("0 10 * * 4", "", -time.Hour)
+	assert.Error(t, err)
+}
@@ -0,0 +1,73 @@
+package template
+
+import (
+	"net/http"
+	"os"
+	"strings"
+)
+
+// RemoteTokenEnv names the environment variable holding a bearer token sent
+// to remote hosts that have no credential of their own
+const RemoteTokenEnv = "SHIPYARD_REMOTE_TOKEN"
+
+// Credential authenticates requests to one remote host
+type Credential struct {
+	Basic    bool   // Send HTTP basic auth instead of a bearer token
+	Username string // Basic auth username; defaults to "token"
+	Token    string // Bearer token or basic auth password
+}
+
+// apply sets the Authorization header for the credential
+func (c *Credential) apply(req *http.Request) {
+	if c.Basic {
+		req.SetBasicAuth(c.username(), c.Token)
+		return
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+}
+
+func (c *Credential) username() string {
+	if c.Username == "" {
+		return "token"
+	}
+	return c.Username
+}
+
+// normalizeCredentials keys credentials by lowercased host, since hosts are
+// matched case-insensitively
+func normalizeCredentials(credentials map[string]*Credential) map[string]*Credential {
+	normalized := make(map[string]*Credential, len(credentials))
+	for host, cred := range credentials {
+		normalized[strings.ToLower(host)] = cred
+	}
+	return normalized
+}
+
+// credentialFor returns the credential for a host: a configured per-host
+// credential, then the loader's auth token, then SHIPYARD_REMOTE_TOKEN
+func (l *TemplateLoader) credentialFor(host string) *Credential {
+	if cred := l.hostCredential(host); cred != nil {
+		return cred
+	}
+	if l.authToken != "" {
+		return &Credential{Token: l.authToken}
+	}
+	if token := os.Getenv(RemoteTokenEnv); token != "" {
+		return &Credential{Token: token}
+	}
+	return nil
+}
+
+// hostCredential returns the credential configured for exactly this host.
+// Unlike credentialFor it never falls back to a catch-all token, so it is
+// safe to use for a redirect target. Hosts match with or without a port.
+func (l *TemplateLoader) hostCredential(host string) *Credential {
+	host = strings.ToLower(host)
+	if cred, ok := l.credentials[host]; ok {
+		return cred
+	}
+	if hostname, _, found := strings.Cut(host, ":"); found {
+		return l.credentials[hostname]
+	}
+	return nil
+}
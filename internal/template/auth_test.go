@@ -0,0 +1,175 @@
+package template
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loaderWithCredentials returns a loader that sends per-host credentials
+func loaderWithCredentials(credentials map[string]*Credential) *TemplateLoader {
+	loader := NewTemplateLoader()
+	loader.SetRemoteOptions(RemoteOptions{Credentials: credentials})
+	return loader
+}
+
+// authServer serves "private template" only to requests carrying wantAuth
+func authServer(t *testing.T, wantAuth string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Header.Get("Authorization") {
+		case wantAuth:
+			_, _ = w.Write([]byte("private template"))
+		case "":
+			w.WriteHeader(http.StatusUnauthorized)
+		default:
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func serverHost(server *httptest.Server) string {
+	return strings.TrimPrefix(server.URL, "http://")
+}
+
+func TestLoadTemplate_RemoteAuth(t *testing.T) {
+	t.Setenv(RemoteTokenEnv, "")
+
+	t.Run("sends configured bearer token", func(t *testing.T) {
+		server := authServer(t, "Bearer host-token")
+		loader := loaderWithCredentials(map[string]*Credential{serverHost(server): {Token: "host-token"}})
+		loader.SetCacheDir("")
+		content, err := loader.Load(server.URL + "/template.tmpl")
+
+		require.NoError(t, err)
+		assert.Equal(t, "private template", content)
+	})
+
+	t.Run("sends basic auth", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, pass, ok := r.BasicAuth()
+			if !ok || user != "deploy" || pass != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte("private template"))
+		}))
+		defer server.Close()
+		loader := loaderWithCredentials(map[string]*Credential{serverHost(server): {Basic: true, Username: "deploy", Token: "secret"}})
+		loader.SetCacheDir("")
+		content, err := loader.Load(server.URL + "/template.tmpl")
+
+		require.NoError(t, err)
+		assert.Equal(t, "private template", content)
+	})
+
+	t.Run("falls back to SHIPYARD_REMOTE_TOKEN", func(t *testing.T) {
+		t.Setenv(RemoteTokenEnv, "env-token")
+		server := authServer(t, "Bearer env-token")
+
+		loader := NewTemplateLoader()
+		loader.SetCacheDir("")
+		content, err := loader.Load(server.URL + "/template.tmpl")
+
+		require.NoError(t, err)
+		assert.Equal(t, "private template", content)
+	})
+
+	t.Run("does not leak token on cross-host redirect", func(t *testing.T) {
+		var leaked string
+		target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			leaked = r.Header.Get("Authorization")
+			_, _ = w.Write([]byte("redirected template"))
+		}))
+		defer target.Close()
+		origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "Bearer origin-token", r.Header.Get("Authorization"))
+			http.Redirect(w, r, target.URL+"/final", http.StatusFound)
+		}))
+		defer origin.Close()
+		t.Setenv(RemoteTokenEnv, "env-token")
+		loader := loaderWithCredentials(map[string]*Credential{serverHost(origin): {Token: "origin-token"}})
+		loader.SetCacheDir("")
+		content, err := loader.Load(origin.URL + "/start")
+
+		require.NoError(t, err)
+		assert.Equal(t, "redirected template", content)
+		assert.Empty(t, leaked, "no credential may reach the redirect target")
+	})
+
+	t.Run("distinguishes 401, 403 and 404", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/unauthorized":
+				w.WriteHeader(http.StatusUnauthorized)
+			case "/forbidden":
+				w.WriteHeader(http.StatusForbidden)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		loader := NewTemplateLoader()
+		loader.SetCacheDir("")
+
+		_, err := loader.Load(server.URL + "/unauthorized")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "HTTP 401")
+		assert.Contains(t, err.Error(), "configure remote.auth for "+serverHost(server))
+
+		_, err = loader.Load(server.URL + "/forbidden")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "HTTP 403")
+		assert.Contains(t, err.Error(), "denied")
+
+		_, err = loader.Load(server.URL + "/missing")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "HTTP 404")
+		assert.Contains(t, err.Error(), "not found")
+	})
+
+	t.Run("reports rejected credentials", func(t *testing.T) {
+		server := authServer(t, "Bearer right")
+		loader := loaderWithCredentials(map[string]*Credential{serverHost(server): {Token: "wrong"}})
+		loader.SetCacheDir("")
+		_, err := loader.Load(server.URL + "/template.tmpl")
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "HTTP 403")
+	})
+
+	t.Run("cache key does not include the token", func(t *testing.T) {
+		server := authServer(t, "Bearer first-token")
+		cacheDir := t.TempDir()
+
+		first := loaderWithCredentials(map[string]*Credential{serverHost(server): {Token: "first-token"}})
+		first.SetCacheDir(cacheDir)
+		_, err := first.Load(server.URL + "/template.tmpl")
+		require.NoError(t, err)
+
+		// A different token still hits the same cache entry
+		second := loaderWithCredentials(map[string]*Credential{serverHost(server): {Token: "second-token"}})
+		second.SetCacheDir(cacheDir)
+		content, err := second.Load(server.URL + "/template.tmpl")
+
+		require.NoError(t, err)
+		assert.Equal(t, "private template", content)
+		assert.Equal(t, first.diskCache.path(server.URL+"/template.tmpl"), second.diskCache.path(server.URL+"/template.tmpl"))
+	})
+}
+
+func TestTemplateLoader_GitAuth(t *testing.T) {
+	t.Setenv(RemoteTokenEnv, "")
+	loader := loaderWithCredentials(map[string]*Credential{"git.example.com": {Token: "git-token"}})
+
+	assert.NotNil(t, loader.gitAuth("https://git.example.com/org/repo.git"))
+	assert.Nil(t, loader.gitAuth("https://other.example.com/org/repo.git"))
+	assert.Nil(t, loader.gitAuth("git@git.example.com:org/repo.git"))
+}
@@ -54,6 +54,19 @@ func TestBuiltinTemplate_Changelog(t *testing.T) {
 	assert.Contains(t, result, "OAuth2")
 }
 
+func TestBuiltinTemplate_ChangelogTagLinks(t *testing.T) {
+	now := time.Date(2026, 1, 30, 14, 30, 0, 0, time.UTC)
+	entries := []history.Entry{
+		{Package: "core", Version: "1.2.0", Tag: "core/v1.2.0", BaseCommitSHA: "abc123", Timestamp: now, Consignments: []history.Consignment{{ChangeType: "minor", Summary: "Add OAuth2 support"}}},
+		{Package: "core", Version: "1.1.0", Timestamp: now.Add(-24 * time.Hour), Consignments: []history.Consignment{{ChangeType: "patch", Summary: "Fix validation"}}},
+	}
+
+	result, err := RenderChangelogWithOptions(entries, "builtin:default", linkOptions("git@github.com:acme/core.git"))
+	require.NoError(t, err)
+	assert.Contains(t, result, "## [1.2.0](https://github.com/acme/core/tree/core/v1.2.0) - 2026-01-30")
+	assert.Contains(t, result, "## [1.1.0] - 2026-01-29", "entries recorded without a tag are not linked")
+}
+
 func TestBuiltinTemplate_TagName(t *testing.T) {
 	context := map[string]interface{}{
 		"Package": "core",
@@ -236,3 +249,35 @@ func TestBuiltinTemplate_ConsistentFormatting(t *testing.T) {
 		assert.Contains(t, template, "## Changes")
 	})
 }
+
+func TestCustomChangelogTemplate_ConsignmentMetadata(t *testing.T) {
+	context := ChangelogContext{
+		Package: "core",
+		Entries: []history.Entry{
+			{
+				Version:   "1.2.0",
+				Timestamp: time.Date(2026, 1, 30, 0, 0, 0, 0, time.UTC),
+				Consignments: []history.Consignment{
+					{
+						ChangeType: "minor",
+						Summary:    "Added OAuth2 support",
+						Metadata:   map[string]interface{}{"issue": "FEAT-123", "pr": "42"},
+					},
+					{
+						// Consignments recorded before metadata existed
+						ChangeType: "patch",
+						Summary:    "Fixed validation",
+					},
+				},
+			},
+		},
+	}
+
+	tmpl := `{{ range .Entries }}{{ range .Consignments }}- {{ .Summary }}` +
+		`{{ with .Metadata.issue }} [{{ . }}]{{ end }}{{ with .Metadata.pr }} (#{{ . }}){{ end }}
+{{ end }}{{ end }}`
+
+	result, err := NewTemplateRenderer().Render(tmpl, context)
+	require.NoError(t, err)
+	assert.Equal(t, "- Added OAuth2 support [FEAT-123] (#42)\n- Fixed validation\n", result)
+}
@@ -0,0 +1,306 @@
+package template
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/fileutil"
+)
+
+const (
+	// DefaultTemplateCacheTTL is how long a downloaded template is reused
+	// before it is fetched again
+	DefaultTemplateCacheTTL = 24 * time.Hour
+
+	// CacheDirEnv overrides the directory shipyard caches downloads in
+	CacheDirEnv = "SHIPYARD_CACHE_DIR"
+
+	// FreshTemplatesEnv bypasses the template cache when set to a non-empty value
+	FreshTemplatesEnv = "SHIPYARD_FRESH_TEMPLATES"
+)
+
+// DefaultTemplateCacheDir returns the directory remote templates are cached in:
+// $SHIPYARD_CACHE_DIR/templates if set, otherwise shipyard/templates under the
+// user cache directory. It returns "" when no cache directory is available.
+func DefaultTemplateCacheDir() string {
+	if dir := os.Getenv(CacheDirEnv); dir != "" {
+		return filepath.Join(dir, "templates")
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "shipyard", "templates")
+}
+
+// templateCache stores downloaded templates on disk keyed by URL.
+// The file modification time records when the template was fetched or last
+// revalidated, and a .meta file beside it holds the source and HTTP validators.
+type templateCache struct {
+	dir string
+	ttl time.Duration
+}
+
+// cachedTemplate is a template read back from the disk cache
+type cachedTemplate struct {
+	content    string
+	fetchedAt  time.Time
+	validators cacheValidators
+}
+
+// cacheValidators are the response headers used to revalidate an expired
+// entry with a conditional GET
+type cacheValidators struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+}
+
+// cacheMeta is the .meta file stored beside a cached template
+type cacheMeta struct {
+	Source string `json:"source,omitempty"` // Absent for entries cached by older versions
+	cacheValidators
+}
+
+// hash returns the cache file name stem for url
+func (c templateCache) hash(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return hex.EncodeToString(sum[:])
+}
+
+// path returns the cache file for url
+func (c templateCache) path(url string) string {
+	return filepath.Join(c.dir, c.hash(url)+".tmpl")
+}
+
+// metaPath returns the validators file for url
+func (c templateCache) metaPath(url string) string {
+	return c.path(url) + ".meta"
+}
+
+// get returns the cached copy of url, if any, regardless of its age
+func (c templateCache) get(url string) (cachedTemplate, bool) {
+	if c.dir == "" {
+		return cachedTemplate{}, false
+	}
+	path := c.path(url)
+	info, err := os.Stat(path)
+	if err != nil {
+		return cachedTemplate{}, false
+	}
+	content, err := fileutil.ReadFile(path)
+	if err != nil {
+		return cachedTemplate{}, false
+	}
+	entry := cachedTemplate{content: string(content), fetchedAt: info.ModTime()}
+	if data, err := fileutil.ReadFile(c.metaPath(url)); err == nil {
+		var meta cacheMeta
+		if json.Unmarshal(data, &meta) == nil {
+			entry.validators = meta.cacheValidators
+		}
+	}
+	return entry, true
+}
+
+// fresh reports whether a cached template is still within the TTL
+func (c templateCache) fresh(entry cachedTemplate) bool {
+	return time.Since(entry.fetchedAt) < c.ttl
+}
+
+// put stores content for url. Caching is best effort, so errors are ignored.
+func (c templateCache) put(url, content string) {
+	if c.dir == "" {
+		return
+	}
+	if err := fileutil.MkdirAll(c.dir, 0755); err != nil {
+		return
+	}
+	_ = fileutil.AtomicWrite(c.path(url), []byte(content), 0644)
+}
+
+// setValidators records the source and validators for url
+func (c templateCache) setValidators(url string, validators cacheValidators) {
+	if c.dir == "" {
+		return
+	}
+	data, err := json.Marshal(cacheMeta{Source: url, cacheValidators: validators})
+	if err != nil {
+		return
+	}
+	_ = fileutil.AtomicWrite(c.metaPath(url), data, 0644)
+}
+
+// touch restarts the TTL of url's entry after the server confirmed it is current
+func (c templateCache) touch(url string) {
+	if c.dir == "" {
+		return
+	}
+	now := time.Now()
+	_ = os.Chtimes(c.path(url), now, now)
+}
+
+// remove deletes url's entry, reporting whether one existed
+func (c templateCache) remove(url string) (bool, error) {
+	if c.dir == "" {
+		return false, nil
+	}
+	err := os.Remove(c.path(url))
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+	if metaErr := os.Remove(c.metaPath(url)); metaErr != nil && !os.IsNotExist(metaErr) {
+		return false, metaErr
+	}
+	return err == nil, nil
+}
+
+// CacheEntry describes a template in the disk cache
+type CacheEntry struct {
+	Source       string    `json:"source"` // Empty for entries cached by older versions
+	Hash         string    `json:"hash"`   // sha256 of the source, the cache file name
+	Size         int64     `json:"size"`
+	FetchedAt    time.Time `json:"fetchedAt"` // When the entry was fetched or last revalidated
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"lastModified,omitempty"`
+}
+
+// list returns every cached template sorted by source, then hash
+func (c templateCache) list() ([]CacheEntry, error) {
+	if c.dir == "" {
+		return nil, nil
+	}
+	files, err := os.ReadDir(c.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var entries []CacheEntry
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || filepath.Ext(name) != ".tmpl" {
+			continue
+		}
+		info, err := file.Info()
+		if err != nil {
+			continue
+		}
+		entry := CacheEntry{
+			Hash:      strings.TrimSuffix(name, ".tmpl"),
+			Size:      info.Size(),
+			FetchedAt: info.ModTime(),
+		}
+		if data, err := fileutil.ReadFile(filepath.Join(c.dir, name+".meta")); err == nil {
+			var meta cacheMeta
+			if json.Unmarshal(data, &meta) == nil {
+				entry.Source = meta.Source
+				entry.ETag = meta.ETag
+				entry.LastModified = meta.LastModified
+			}
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Source != entries[j].Source {
+			return entries[i].Source < entries[j].Source
+		}
+		return entries[i].Hash < entries[j].Hash
+	})
+	return entries, nil
+}
+
+// clear removes every cached template, returning how many were removed
+func (c templateCache) clear() (int, error) {
+	entries, err := c.list()
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, entry := range entries {
+		stem := filepath.Join(c.dir, entry.Hash+".tmpl")
+		if err := os.Remove(stem); err != nil && !os.IsNotExist(err) {
+			return removed, err
+		}
+		if err := os.Remove(stem + ".meta"); err != nil && !os.IsNotExist(err) {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// CacheTTL returns how long the loader reuses a cached template before
+// revalidating it
+func (l *TemplateLoader) CacheTTL() time.Duration {
+	return l.diskCache.ttl
+}
+
+// CacheDir returns the directory the loader caches remote templates in
+func (l *TemplateLoader) CacheDir() string {
+	return l.diskCache.dir
+}
+
+// ListCached returns the templates in the disk cache sorted by source
+func (l *TemplateLoader) ListCached() ([]CacheEntry, error) {
+	return l.diskCache.list()
+}
+
+// ClearCache removes every template from the disk cache and returns how
+// many were removed
+func (l *TemplateLoader) ClearCache() (int, error) {
+	return l.diskCache.clear()
+}
+
+// RemoveCached removes the cache entry for a remote source, found by the
+// hash of its canonical form. It reports whether an entry existed.
+func (l *TemplateLoader) RemoveCached(source string) (bool, error) {
+	return l.diskCache.remove(cacheKey(source))
+}
+
+// RefreshCached fetches a remote source again, bypassing the cache, and
+// stores the result. It reports whether the content differs from the
+// previously cached copy; a source that was not cached counts as changed.
+func (l *TemplateLoader) RefreshCached(source string) (bool, error) {
+	switch sourceType, _ := DetectSourceType(source); sourceType {
+	case SourceTypeHTTPS, SourceTypeGit, SourceTypeGitHub, SourceTypeGitLab, SourceTypeBitbucket:
+	default:
+		return false, fmt.Errorf("%s is not a cached remote source", source)
+	}
+
+	before, hadCached := l.diskCache.get(cacheKey(source))
+	fresh := l.fresh
+	l.fresh = true
+	defer func() { l.fresh = fresh }()
+
+	delete(l.cache, source)
+	content, err := l.Load(source)
+	if err != nil {
+		return false, err
+	}
+	return !hadCached || content != before.content, nil
+}
+
+// cacheKey returns the disk cache key for a remote source: the URL for HTTP
+// sources, the reference for git sources, and the canonical reference for
+// forge sources
+func cacheKey(source string) string {
+	switch sourceType, target := DetectSourceType(source); sourceType {
+	case SourceTypeGitLab:
+		if fs, err := ParseForgeSource(ForgeGitLab + ":" + target); err == nil {
+			return fs.String()
+		}
+	case SourceTypeBitbucket:
+		if fs, err := ParseForgeSource(ForgeBitbucket + ":" + target); err == nil {
+			return fs.String()
+		}
+	}
+	return source
+}
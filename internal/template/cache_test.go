@@ -0,0 +1,429 @@
+package template
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMain keeps remote template downloads out of the user cache directory
+func TestMain(m *testing.M) {
+	cacheDir, err := os.MkdirTemp("", "shipyard-template-cache-*")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create template cache directory: %v\n", err)
+		os.Exit(1)
+	}
+	_ = os.Setenv(CacheDirEnv, cacheDir)
+	// Retried fetches of unreachable test servers should not slow the suite
+	defaultRetryBackoff = time.Millisecond
+
+	code := m.Run()
+	_ = os.RemoveAll(cacheDir)
+	os.Exit(code)
+}
+
+// countingServer serves body and counts requests; it fails with status when
+// status is non-zero
+func countingServer(t *testing.T, body string, status *atomic.Int32, hits *atomic.Int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if code := status.Load(); code != 0 {
+			w.WriteHeader(int(code))
+			return
+		}
+		_, _ = fmt.Fprint(w, body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestLoadTemplate_HTTPSCache(t *testing.T) {
+	t.Run("miss downloads and stores template", func(t *testing.T) {
+		var status, hits atomic.Int32
+		server := countingServer(t, "remote", &status, &hits)
+		cacheDir := t.TempDir()
+
+		loader := NewTemplateLoader()
+		loader.SetCacheDir(cacheDir)
+		content, err := loader.Load(server.URL + "/changelog.tmpl")
+
+		require.NoError(t, err)
+		assert.Equal(t, "remote", content)
+		assert.Equal(t, int32(1), hits.Load())
+
+		files, err := filepath.Glob(filepath.Join(cacheDir, "*.tmpl"))
+		require.NoError(t, err)
+		assert.Len(t, files, 1)
+	})
+
+	t.Run("hit within TTL skips the network", func(t *testing.T) {
+		var status, hits atomic.Int32
+		server := countingServer(t, "remote", &status, &hits)
+		cacheDir := t.TempDir()
+
+		first := NewTemplateLoader()
+		first.SetCacheDir(cacheDir)
+		_, err := first.Load(server.URL + "/changelog.tmpl")
+		require.NoError(t, err)
+
+		// A new loader has an empty in-memory cache, so only the disk cache can answer
+		second := NewTemplateLoader()
+		second.SetCacheDir(cacheDir)
+		content, err := second.Load(server.URL + "/changelog.tmpl")
+
+		require.NoError(t, err)
+		assert.Equal(t, "remote", content)
+		assert.Equal(t, int32(1), hits.Load(), "cached template should not be refetched")
+	})
+
+	t.Run("expired entry is refetched", func(t *testing.T) {
+		var status, hits atomic.Int32
+		server := countingServer(t, "remote", &status, &hits)
+		cacheDir := t.TempDir()
+		url := server.URL + "/changelog.tmpl"
+
+		cache := templateCache{dir: cacheDir, ttl: time.Hour}
+		cache.put(url, "old")
+		past := time.Now().Add(-2 * time.Hour)
+		require.NoError(t, os.Chtimes(cache.path(url), past, past))
+
+		loader := NewTemplateLoader()
+		loader.SetCacheDir(cacheDir)
+		loader.SetCacheTTL(time.Hour)
+		content, err := loader.Load(url)
+
+		require.NoError(t, err)
+		assert.Equal(t, "remote", content)
+		assert.Equal(t, int32(1), hits.Load())
+
+		refreshed, ok := cache.get(url)
+		require.True(t, ok)
+		assert.Equal(t, "remote", refreshed.content)
+		assert.True(t, cache.fresh(refreshed), "refetch should reset the entry age")
+	})
+
+	t.Run("fresh bypasses a valid entry", func(t *testing.T) {
+		var status, hits atomic.Int32
+		server := countingServer(t, "remote", &status, &hits)
+		cacheDir := t.TempDir()
+		url := server.URL + "/changelog.tmpl"
+		templateCache{dir: cacheDir, ttl: time.Hour}.put(url, "old")
+
+		loader := NewTemplateLoader()
+		loader.SetCacheDir(cacheDir)
+		loader.SetFresh(true)
+		content, err := loader.Load(url)
+
+		require.NoError(t, err)
+		assert.Equal(t, "remote", content)
+		assert.Equal(t, int32(1), hits.Load())
+	})
+
+	t.Run("fresh from environment", func(t *testing.T) {
+		t.Setenv(FreshTemplatesEnv, "1")
+		assert.True(t, NewTemplateLoader().fresh)
+	})
+
+	t.Run("offline falls back to stale copy with warning", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		url := server.URL + "/changelog.tmpl"
+		server.Close() // connections are now refused
+
+		cacheDir := t.TempDir()
+		cache := templateCache{dir: cacheDir, ttl: time.Hour}
+		cache.put(url, "stale")
+		past := time.Now().Add(-48 * time.Hour)
+		require.NoError(t, os.Chtimes(cache.path(url), past, past))
+
+		var warnings bytes.Buffer
+		loader := NewTemplateLoader()
+		loader.SetCacheDir(cacheDir)
+		loader.SetCacheTTL(time.Hour)
+		loader.SetWarningWriter(&warnings)
+		content, err := loader.Load(url)
+
+		require.NoError(t, err)
+		assert.Equal(t, "stale", content)
+		assert.Contains(t, warnings.String(), "could not fetch "+url)
+		assert.Contains(t, warnings.String(), "using STALE cached copy of "+url)
+	})
+
+	t.Run("server error falls back to stale copy", func(t *testing.T) {
+		var status, hits atomic.Int32
+		status.Store(http.StatusBadGateway)
+		server := countingServer(t, "remote", &status, &hits)
+		cacheDir := t.TempDir()
+		url := server.URL + "/changelog.tmpl"
+		cache := templateCache{dir: cacheDir, ttl: time.Hour}
+		cache.put(url, "stale")
+		past := time.Now().Add(-48 * time.Hour)
+		require.NoError(t, os.Chtimes(cache.path(url), past, past))
+
+		loader := NewTemplateLoader()
+		loader.SetCacheDir(cacheDir)
+		loader.SetCacheTTL(time.Hour)
+		loader.SetWarningWriter(&bytes.Buffer{})
+		content, err := loader.Load(url)
+
+		require.NoError(t, err)
+		assert.Equal(t, "stale", content)
+	})
+
+	t.Run("offline without cached copy fails", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		url := server.URL + "/changelog.tmpl"
+		server.Close()
+
+		loader := NewTemplateLoader()
+		loader.SetCacheDir(t.TempDir())
+		_, err := loader.Load(url)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to fetch template")
+	})
+
+	t.Run("client errors do not use stale copy", func(t *testing.T) {
+		var status, hits atomic.Int32
+		status.Store(http.StatusNotFound)
+		server := countingServer(t, "remote", &status, &hits)
+		cacheDir := t.TempDir()
+		url := server.URL + "/changelog.tmpl"
+		cache := templateCache{dir: cacheDir, ttl: time.Hour}
+		cache.put(url, "stale")
+		past := time.Now().Add(-48 * time.Hour)
+		require.NoError(t, os.Chtimes(cache.path(url), past, past))
+
+		loader := NewTemplateLoader()
+		loader.SetCacheDir(cacheDir)
+		loader.SetCacheTTL(time.Hour)
+		_, err := loader.Load(url)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "HTTP 404")
+	})
+}
+
+// validatingServer serves *body with validators and answers matching
+// conditional requests with 304. It counts requests and full body transfers.
+func validatingServer(t *testing.T, body *atomic.Value, useETag bool, hits, transfers *atomic.Int32) *httptest.Server {
+	t.Helper()
+	lastModified := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC).Format(http.TimeFormat)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		content := body.Load().(string)
+		if useETag {
+			etag := fmt.Sprintf("%q", content)
+			w.Header().Set("ETag", etag)
+			if r.Header.Get("If-None-Match") == etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		} else {
+			w.Header().Set("Last-Modified", lastModified)
+			if r.Header.Get("If-Modified-Since") == lastModified {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+		transfers.Add(1)
+		_, _ = fmt.Fprint(w, content)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestTemplateCache_Revalidation(t *testing.T) {
+	// Each load uses a new loader with a TTL that has always expired, so
+	// every load after the first revalidates
+	load := func(t *testing.T, cacheDir, url string) string {
+		t.Helper()
+		loader := NewTemplateLoader()
+		loader.SetCacheDir(cacheDir)
+		loader.SetCacheTTL(time.Nanosecond)
+		content, err := loader.Load(url)
+		require.NoError(t, err)
+		return content
+	}
+
+	for _, useETag := range []bool{true, false} {
+		name := "last-modified"
+		if useETag {
+			name = "etag"
+		}
+		t.Run(name+" revalidates without a second transfer", func(t *testing.T) {
+			var body atomic.Value
+			body.Store("remote v1")
+			var hits, transfers atomic.Int32
+			server := validatingServer(t, &body, useETag, &hits, &transfers)
+			cacheDir := t.TempDir()
+			url := server.URL + "/changelog.tmpl"
+
+			for i := 0; i < 3; i++ {
+				assert.Equal(t, "remote v1", load(t, cacheDir, url))
+			}
+
+			assert.Equal(t, int32(3), hits.Load())
+			assert.Equal(t, int32(1), transfers.Load(), "only the first fetch should transfer the body")
+		})
+	}
+
+	t.Run("304 restarts the TTL", func(t *testing.T) {
+		var body atomic.Value
+		body.Store("remote v1")
+		var hits, transfers atomic.Int32
+		server := validatingServer(t, &body, true, &hits, &transfers)
+		cacheDir := t.TempDir()
+		url := server.URL + "/changelog.tmpl"
+		load(t, cacheDir, url)
+
+		cache := templateCache{dir: cacheDir, ttl: time.Hour}
+		past := time.Now().Add(-2 * time.Hour)
+		require.NoError(t, os.Chtimes(cache.path(url), past, past))
+		load(t, cacheDir, url)
+
+		entry, ok := cache.get(url)
+		require.True(t, ok)
+		assert.True(t, cache.fresh(entry), "a 304 should reset the entry age")
+	})
+
+	t.Run("changed content is downloaded and cached", func(t *testing.T) {
+		var body atomic.Value
+		body.Store("remote v1")
+		var hits, transfers atomic.Int32
+		server := validatingServer(t, &body, true, &hits, &transfers)
+		cacheDir := t.TempDir()
+		url := server.URL + "/changelog.tmpl"
+		load(t, cacheDir, url)
+
+		body.Store("remote v2")
+		assert.Equal(t, "remote v2", load(t, cacheDir, url))
+		assert.Equal(t, "remote v2", load(t, cacheDir, url))
+
+		assert.Equal(t, int32(2), transfers.Load())
+		entry, ok := templateCache{dir: cacheDir}.get(url)
+		require.True(t, ok)
+		assert.Equal(t, `"remote v2"`, entry.validators.ETag)
+	})
+
+	t.Run("fresh skips revalidation", func(t *testing.T) {
+		var body atomic.Value
+		body.Store("remote v1")
+		var hits, transfers atomic.Int32
+		server := validatingServer(t, &body, true, &hits, &transfers)
+		cacheDir := t.TempDir()
+		url := server.URL + "/changelog.tmpl"
+		load(t, cacheDir, url)
+
+		loader := NewTemplateLoader()
+		loader.SetCacheDir(cacheDir)
+		loader.SetFresh(true)
+		_, err := loader.Load(url)
+		require.NoError(t, err)
+
+		assert.Equal(t, int32(2), transfers.Load())
+	})
+}
+
+func TestTemplateCache_Manage(t *testing.T) {
+	var body atomic.Value
+	body.Store("{{ .Version }}")
+	var hits, transfers atomic.Int32
+	server := validatingServer(t, &body, true, &hits, &transfers)
+	first, second := server.URL+"/a.tmpl", server.URL+"/b.tmpl"
+
+	cacheDir := t.TempDir()
+	loader := NewTemplateLoader()
+	loader.SetCacheDir(cacheDir)
+	for _, url := range []string{second, first} {
+		_, err := loader.Load(url)
+		require.NoError(t, err)
+	}
+
+	t.Run("list reports sources sorted", func(t *testing.T) {
+		entries, err := loader.ListCached()
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.Equal(t, first, entries[0].Source)
+		assert.Equal(t, second, entries[1].Source)
+		assert.Len(t, entries[0].Hash, 64)
+		assert.Equal(t, int64(len("{{ .Version }}")), entries[0].Size)
+		assert.NotEmpty(t, entries[0].ETag)
+		assert.WithinDuration(t, time.Now(), entries[0].FetchedAt, time.Minute)
+	})
+
+	t.Run("refresh reports whether content changed", func(t *testing.T) {
+		before := transfers.Load()
+		changed, err := loader.RefreshCached(first)
+		require.NoError(t, err)
+		assert.False(t, changed)
+		assert.Equal(t, before+1, transfers.Load(), "refresh must bypass the cache and its validators")
+
+		body.Store("{{ .Version }} changed")
+		changed, err = loader.RefreshCached(first)
+		require.NoError(t, err)
+		assert.True(t, changed)
+
+		content, err := loader.Load(first)
+		require.NoError(t, err)
+		assert.Equal(t, "{{ .Version }} changed", content, "refreshed content is used afterwards")
+
+		_, err = loader.RefreshCached("templates/local.tmpl")
+		assert.ErrorContains(t, err, "not a cached remote source")
+	})
+
+	t.Run("remove drops a single entry", func(t *testing.T) {
+		found, err := loader.RemoveCached(second)
+		require.NoError(t, err)
+		assert.True(t, found)
+
+		found, err = loader.RemoveCached(second)
+		require.NoError(t, err)
+		assert.False(t, found)
+
+		entries, err := loader.ListCached()
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, first, entries[0].Source)
+	})
+
+	t.Run("clear drops everything", func(t *testing.T) {
+		removed, err := loader.ClearCache()
+		require.NoError(t, err)
+		assert.Equal(t, 1, removed)
+
+		files, err := os.ReadDir(cacheDir)
+		require.NoError(t, err)
+		assert.Empty(t, files)
+	})
+}
+
+func TestCacheKey(t *testing.T) {
+	assert.Equal(t, "https://example.com/a.tmpl", cacheKey("https://example.com/a.tmpl"))
+	assert.Equal(t, "github:acme/templates/a.tmpl@v1", cacheKey("github:acme/templates/a.tmpl@v1"))
+	assert.Equal(t, cacheKey("gitlab:platform/release//notes.tmpl"), cacheKey("gitlab:platform/release.git/notes.tmpl"))
+}
+
+func TestSetRemoteOptions_CacheTTL(t *testing.T) {
+	loader := NewTemplateLoader()
+	loader.SetRemoteOptions(RemoteOptions{CacheTTL: 6 * time.Hour})
+	assert.Equal(t, 6*time.Hour, loader.diskCache.ttl)
+
+	loader.SetRemoteOptions(RemoteOptions{})
+	assert.Equal(t, DefaultTemplateCacheTTL, loader.diskCache.ttl)
+	assert.Equal(t, DefaultTemplateCacheTTL, NewTemplateLoader().diskCache.ttl, "loaders do not share settings")
+}
+
+func TestDefaultTemplateCacheDir(t *testing.T) {
+	t.Setenv(CacheDirEnv, "/tmp/shipyard-cache")
+	assert.Equal(t, filepath.Join("/tmp/shipyard-cache", "templates"), DefaultTemplateCacheDir())
+}
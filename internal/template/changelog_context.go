@@ -1,6 +1,10 @@
 package template
 
 import (
+	"slices"
+	"strconv"
+	"strings"
+
 	"github.com/NatoNathan/shipyard/internal/history"
 	"github.com/NatoNathan/shipyard/pkg/semver"
 )
@@ -13,24 +17,62 @@ type ChangelogContext struct {
 	LatestVersion    string          // most recent version string (stable or pre-release)
 	LatestStable     string          // most recent non-pre-release version; empty if none
 	LatestPreRelease string          // most recent pre-release version; empty if none
-	Entries          []history.Entry // all entries, sorted newest-first
+	Ecosystem        string          // package ecosystem, from the newest entry; empty if unknown
+	IsMonorepo       bool            // project configures more than one package
+	LatestTag        string          // git tag of the most recent version; empty if none
+	Channel          string          // release channel of the most recent version; empty without channels
+	RepoHost         string          // repository web host, e.g. github.com; empty if unknown
+	RepoHostType     string          // github, gitlab, bitbucket or other; empty if the repository is unknown
+	Unreleased       *history.Entry  // pending changes with no version yet; nil if none
+	Entries          []history.Entry // all released entries, sorted newest-first
 }
 
-// newChangelogContext builds a ChangelogContext from a slice already sorted newest-first.
-func newChangelogContext(sorted []history.Entry) ChangelogContext {
-	ctx := ChangelogContext{Entries: sorted}
+// newChangelogContext builds a ChangelogContext from a slice already sorted
+// newest-first. Entries without a version hold pending changes: they are
+// merged into Unreleased instead of being listed as releases. Releases link
+// into repo when it is known.
+func newChangelogContext(sorted []history.Entry, repo *Repository) ChangelogContext {
+	ctx := ChangelogContext{RepoHost: repo.host(), RepoHostType: repo.hostType()}
+	released := make([]history.Entry, 0, len(sorted))
+	for _, e := range sorted {
+		if e.Version != "" {
+			released = append(released, e)
+			continue
+		}
+		if len(e.Consignments) == 0 {
+			continue
+		}
+		if ctx.Unreleased == nil {
+			unreleased := e
+			unreleased.Consignments = slices.Clone(e.Consignments)
+			ctx.Unreleased = &unreleased
+		} else {
+			ctx.Unreleased.Consignments = append(ctx.Unreleased.Consignments, e.Consignments...)
+		}
+	}
+	sorted = released
+	linkReleases(sorted, repo)
+	ctx.Entries = sorted
 	if len(sorted) == 0 {
+		if ctx.Unreleased != nil {
+			ctx.Package = ctx.Unreleased.Package
+			ctx.Ecosystem = ctx.Unreleased.Ecosystem
+			ctx.IsMonorepo = ctx.Unreleased.IsMonorepo
+		}
 		return ctx
 	}
+	ctx.LatestTag = sorted[0].VersionTag()
 	ctx.Package = sorted[0].Package
 	ctx.LatestVersion = sorted[0].Version
+	ctx.Ecosystem = sorted[0].Ecosystem
+	ctx.IsMonorepo = sorted[0].IsMonorepo
+	ctx.Channel = sorted[0].Channel
 
 	for _, e := range sorted {
-		v, err := semver.Parse(e.Version)
-		if err != nil {
+		if !isVersionLike(e.Version) {
 			continue // skip malformed versions
 		}
-		if v.IsPreRelease() {
+		if isPreReleaseVersion(e.Version) {
 			if ctx.LatestPreRelease == "" {
 				ctx.LatestPreRelease = e.Version
 			}
@@ -45,3 +87,50 @@ func newChangelogContext(sorted []history.Entry) ChangelogContext {
 	}
 	return ctx
 }
+
+// linkReleases fills in each release's previous version and tag from the
+// next older release in sorted, and the links between them. The first
+// release has nothing to compare against, so it links to its tag's tree.
+func linkReleases(sorted []history.Entry, repo *Repository) {
+	for i := range sorted {
+		e := &sorted[i]
+		tag := e.VersionTag()
+		if i+1 < len(sorted) {
+			previous := sorted[i+1]
+			e.PreviousTag = previous.VersionTag()
+			if e.PreviousVersion == "" {
+				e.PreviousVersion = previous.Version
+			}
+			e.CompareURL = repo.compareURL(e.PreviousTag, tag)
+		} else {
+			e.CompareURL = repo.treeURL(tag)
+		}
+		e.ReleaseURL = repo.releaseURL(tag)
+	}
+}
+
+// isVersionLike reports whether s parses as a SemVer version or is a
+// dot-separated numeric version such as a zero-padded CalVer release
+func isVersionLike(s string) bool {
+	if _, err := semver.Parse(s); err == nil {
+		return true
+	}
+	base, _, _ := strings.Cut(strings.TrimPrefix(s, "v"), "+")
+	base, _, _ = strings.Cut(base, "-")
+	parts := strings.Split(base, ".")
+	if len(parts) < 2 {
+		return false
+	}
+	for _, part := range parts {
+		if _, err := strconv.Atoi(part); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// isPreReleaseVersion reports whether a version string has a pre-release suffix
+func isPreReleaseVersion(s string) bool {
+	base, _, _ := strings.Cut(s, "+")
+	return strings.Contains(base, "-")
+}
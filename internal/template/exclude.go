@@ -0,0 +1,29 @@
+package template
+
+import (
+	"slices"
+
+	"github.com/NatoNathan/shipyard/internal/history"
+)
+
+// ExcludeChangeTypes returns a copy of entry without consignments of the given
+// change types. When an entry had changes but all of them were excluded, its
+// Placeholder is set so templates can still render the release.
+func ExcludeChangeTypes(entry history.Entry, excluded []string, placeholder string) history.Entry {
+	if len(excluded) == 0 || len(entry.Consignments) == 0 {
+		return entry
+	}
+
+	kept := make([]history.Consignment, 0, len(entry.Consignments))
+	for _, c := range entry.Consignments {
+		if !slices.Contains(excluded, c.ChangeType) {
+			kept = append(kept, c)
+		}
+	}
+
+	entry.Consignments = kept
+	if len(kept) == 0 {
+		entry.Placeholder = placeholder
+	}
+	return entry
+}
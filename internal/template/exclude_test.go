@@ -0,0 +1,104 @@
+package template
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/history"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func excludeTestEntries() []history.Entry {
+	return []history.Entry{
+		{
+			Version:   "1.1.0",
+			Package:   "core",
+			Timestamp: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+			Consignments: []history.Consignment{
+				{ID: "c1", Summary: "Add export", ChangeType: "minor"},
+				{ID: "c2", Summary: "Bump internal tooling", ChangeType: "patch"},
+			},
+		},
+		{
+			Version:   "1.0.1",
+			Package:   "core",
+			Timestamp: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			Consignments: []history.Consignment{
+				{ID: "c0", Summary: "Reformat sources", ChangeType: "patch"},
+			},
+		},
+	}
+}
+
+func TestExcludeChangeTypes(t *testing.T) {
+	entries := excludeTestEntries()
+
+	t.Run("drops excluded types", func(t *testing.T) {
+		filtered := ExcludeChangeTypes(entries[0], []string{"patch"}, "Internal changes only")
+		require.Len(t, filtered.Consignments, 1)
+		assert.Equal(t, "c1", filtered.Consignments[0].ID)
+		assert.Empty(t, filtered.Placeholder)
+		assert.Len(t, entries[0].Consignments, 2, "original entry must not be modified")
+	})
+
+	t.Run("placeholder when everything excluded", func(t *testing.T) {
+		filtered := ExcludeChangeTypes(entries[1], []string{"patch"}, "Internal changes only")
+		assert.Empty(t, filtered.Consignments)
+		assert.Equal(t, "Internal changes only", filtered.Placeholder)
+	})
+
+	t.Run("no exclusions", func(t *testing.T) {
+		filtered := ExcludeChangeTypes(entries[1], nil, "Internal changes only")
+		assert.Equal(t, entries[1], filtered)
+	})
+}
+
+func TestRenderChangelog_ExcludedTypes(t *testing.T) {
+	var filtered []history.Entry
+	for _, e := range excludeTestEntries() {
+		filtered = append(filtered, ExcludeChangeTypes(e, []string{"patch"}, "Internal changes only"))
+	}
+
+	for _, source := range []string{"builtin:default", "builtin:keepachangelog"} {
+		t.Run(source, func(t *testing.T) {
+			output, err := RenderChangelogWithTemplate(filtered, source)
+			require.NoError(t, err)
+
+			assert.Contains(t, output, "Add export")
+			assert.NotContains(t, output, "Bump internal tooling")
+			assert.NotContains(t, output, "Reformat sources")
+			// The patch-only section is omitted rather than rendered empty
+			assert.NotContains(t, output, "### Bug Fixes")
+			assert.NotContains(t, output, "### Fixed")
+			// A fully excluded release still renders, with the placeholder
+			assert.Contains(t, output, "## [1.0.1]")
+			assert.Contains(t, output, "- Internal changes only")
+			assert.Equal(t, 1, countOccurrences(output, "Internal changes only"))
+		})
+	}
+}
+
+func TestRenderReleaseNotes_ExcludedTypes(t *testing.T) {
+	entry := ExcludeChangeTypes(excludeTestEntries()[1], []string{"patch"}, "Maintenance release")
+
+	for _, source := range []string{"builtin:default", "builtin:grouped"} {
+		t.Run(source, func(t *testing.T) {
+			output, err := RenderReleaseNotesWithTemplate([]history.Entry{entry}, source)
+			require.NoError(t, err)
+			assert.Contains(t, output, "Maintenance release")
+			assert.NotContains(t, output, "Reformat sources")
+			assert.NotContains(t, output, "No changes in this release")
+		})
+	}
+}
+
+func countOccurrences(s, substr string) int {
+	count := 0
+	for i := 0; i+len(substr) <= len(s); i++ {
+		if s[i:i+len(substr)] == substr {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,135 @@
+package template
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Forge prefixes for hosted git sources
+const (
+	ForgeGitHub    = "github"
+	ForgeGitLab    = "gitlab"
+	ForgeBitbucket = "bitbucket"
+)
+
+// defaultForgeHosts maps each forge prefix to its public host
+var defaultForgeHosts = map[string]string{
+	ForgeGitHub:    "github.com",
+	ForgeGitLab:    "gitlab.com",
+	ForgeBitbucket: "bitbucket.org",
+}
+
+// ForgeSource is a file in a repository on a git hosting service, written as
+//
+//	gitlab:[host=HOST:]group/subgroup/repo//path/to/file[@ref]
+//
+// The repository is the first two path segments unless a "//" separator or a
+// segment ending in ".git" marks where it ends, which GitLab subgroups need.
+// GitHub and Bitbucket repositories are always owner/repo.
+type ForgeSource struct {
+	Forge string // github, gitlab or bitbucket
+	Host  string // Defaults to the forge's public host
+	Repo  string // Repository path without .git, e.g. group/subgroup/repo
+	Path  string // File path within the repository
+	Ref   string // Branch or full ref; empty means the default branch
+}
+
+// IsForgeSource reports whether source starts with a forge prefix
+func IsForgeSource(source string) bool {
+	forge, _, ok := strings.Cut(source, ":")
+	_, known := defaultForgeHosts[forge]
+	return ok && known
+}
+
+// ParseForgeSource parses a github:, gitlab: or bitbucket: reference
+func ParseForgeSource(source string) (*ForgeSource, error) {
+	forge, rest, ok := strings.Cut(source, ":")
+	host, known := defaultForgeHosts[forge]
+	if !ok || !known {
+		return nil, fmt.Errorf("unknown forge source %q (expected github:, gitlab: or bitbucket:)", source)
+	}
+	invalid := func() error {
+		nested := ""
+		if forge == ForgeGitLab {
+			nested = "[subgroup/...]"
+		}
+		return fmt.Errorf("invalid %s source format: %s (expected %s:[host=HOST:]owner/%srepo[//]path[@ref])", forge, source, forge, nested)
+	}
+
+	fs := &ForgeSource{Forge: forge, Host: host}
+
+	if strings.HasPrefix(rest, "host=") {
+		var hostPart string
+		hostPart, rest, ok = strings.Cut(strings.TrimPrefix(rest, "host="), ":")
+		if !ok || hostPart == "" || strings.ContainsAny(hostPart, "/@") {
+			return nil, invalid()
+		}
+		fs.Host = hostPart
+	}
+
+	if idx := strings.LastIndex(rest, "@"); idx != -1 {
+		rest, fs.Ref = rest[:idx], rest[idx+1:]
+		if fs.Ref == "" {
+			return nil, invalid()
+		}
+	}
+
+	var repoSegments []string
+	if repo, path, found := strings.Cut(rest, "//"); found {
+		repoSegments = strings.Split(repo, "/")
+		fs.Path = path
+	} else {
+		segments := strings.Split(rest, "/")
+		end := 2
+		for i, segment := range segments {
+			if strings.HasSuffix(segment, ".git") {
+				end = i + 1
+				break
+			}
+		}
+		if len(segments) <= end {
+			return nil, invalid()
+		}
+		repoSegments = segments[:end]
+		fs.Path = strings.Join(segments[end:], "/")
+	}
+
+	if len(repoSegments) > 0 {
+		last := len(repoSegments) - 1
+		repoSegments[last] = strings.TrimSuffix(repoSegments[last], ".git")
+	}
+	for _, segment := range repoSegments {
+		if segment == "" {
+			return nil, invalid()
+		}
+	}
+	if len(repoSegments) < 2 || (forge != ForgeGitLab && len(repoSegments) != 2) || fs.Path == "" {
+		return nil, invalid()
+	}
+	fs.Repo = strings.Join(repoSegments, "/")
+
+	return fs, nil
+}
+
+// SSHURL returns the SSH clone URL
+func (f *ForgeSource) SSHURL() string {
+	return "git@" + f.Host + ":" + f.Repo + ".git"
+}
+
+// HTTPSURL returns the HTTPS clone URL
+func (f *ForgeSource) HTTPSURL() string {
+	return "https://" + f.Host + "/" + f.Repo + ".git"
+}
+
+// String returns the canonical reference, which is also its cache key
+func (f *ForgeSource) String() string {
+	s := f.Forge + ":"
+	if f.Host != defaultForgeHosts[f.Forge] {
+		s += "host=" + f.Host + ":"
+	}
+	s += f.Repo + "//" + f.Path
+	if f.Ref != "" {
+		s += "@" + f.Ref
+	}
+	return s
+}
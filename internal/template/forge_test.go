@@ -0,0 +1,104 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseForgeSource(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   ForgeSource
+		ssh    string
+		https  string
+	}{
+		{
+			name:   "gitlab group and repo",
+			source: "gitlab:acme/templates/changelog.tmpl",
+			want:   ForgeSource{Forge: "gitlab", Host: "gitlab.com", Repo: "acme/templates", Path: "changelog.tmpl"},
+			ssh:    "git@gitlab.com:acme/templates.git",
+			https:  "https://gitlab.com/acme/templates.git",
+		},
+		{
+			name:   "gitlab nested subgroups with separator",
+			source: "gitlab:acme/platform/release/templates//shipyard/changelog.tmpl@v1",
+			want:   ForgeSource{Forge: "gitlab", Host: "gitlab.com", Repo: "acme/platform/release/templates", Path: "shipyard/changelog.tmpl", Ref: "v1"},
+			https:  "https://gitlab.com/acme/platform/release/templates.git",
+		},
+		{
+			name:   "gitlab nested subgroups with .git marker",
+			source: "gitlab:acme/platform/templates.git/shipyard/changelog.tmpl",
+			want:   ForgeSource{Forge: "gitlab", Host: "gitlab.com", Repo: "acme/platform/templates", Path: "shipyard/changelog.tmpl"},
+		},
+		{
+			name:   "gitlab self-hosted",
+			source: "gitlab:host=git.corp.example.com:platform/shared/configs//base.yaml@refs/tags/v2.0.0",
+			want:   ForgeSource{Forge: "gitlab", Host: "git.corp.example.com", Repo: "platform/shared/configs", Path: "base.yaml", Ref: "refs/tags/v2.0.0"},
+			ssh:    "git@git.corp.example.com:platform/shared/configs.git",
+			https:  "https://git.corp.example.com/platform/shared/configs.git",
+		},
+		{
+			name:   "bitbucket workspace and repo",
+			source: "bitbucket:acme/templates/notes/release.tmpl@main",
+			want:   ForgeSource{Forge: "bitbucket", Host: "bitbucket.org", Repo: "acme/templates", Path: "notes/release.tmpl", Ref: "main"},
+			ssh:    "git@bitbucket.org:acme/templates.git",
+		},
+		{
+			name:   "github",
+			source: "github:acme/templates.git/changelog.tmpl",
+			want:   ForgeSource{Forge: "github", Host: "github.com", Repo: "acme/templates", Path: "changelog.tmpl"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs, err := ParseForgeSource(tt.source)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, *fs)
+			if tt.ssh != "" {
+				assert.Equal(t, tt.ssh, fs.SSHURL())
+			}
+			if tt.https != "" {
+				assert.Equal(t, tt.https, fs.HTTPSURL())
+			}
+
+			// The canonical form parses back to the same source
+			again, err := ParseForgeSource(fs.String())
+			require.NoError(t, err)
+			assert.Equal(t, fs, again)
+		})
+	}
+}
+
+func TestParseForgeSource_Invalid(t *testing.T) {
+	tests := []string{
+		"gitlab:acme/templates",                 // no path
+		"gitlab:acme//changelog.tmpl",           // no repo
+		"gitlab:acme/templates//",               // empty path
+		"gitlab:acme//sub/templates//x.tmpl",    // empty group segment
+		"gitlab:host=:acme/templates/x.tmpl",    // empty host
+		"gitlab:host=example.com",               // host without source
+		"gitlab:acme/templates/x.tmpl@",         // empty ref
+		"bitbucket:acme/team/templates//x.tmpl", // bitbucket has no subgroups
+		"github:acme/a/b.git/x.tmpl",            // nor does github
+		"sourcehut:acme/templates/x.tmpl",
+	}
+
+	for _, source := range tests {
+		t.Run(source, func(t *testing.T) {
+			_, err := ParseForgeSource(source)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestIsForgeSource(t *testing.T) {
+	assert.True(t, IsForgeSource("gitlab:acme/templates/x.tmpl"))
+	assert.True(t, IsForgeSource("bitbucket:acme/templates/x.tmpl"))
+	assert.True(t, IsForgeSource("github:acme/templates/x.tmpl"))
+	assert.False(t, IsForgeSource("https://gitlab.com/acme/templates"))
+	assert.False(t, IsForgeSource("templates/changelog.tmpl"))
+}
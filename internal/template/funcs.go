@@ -0,0 +1,197 @@
+package template
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/NatoNathan/shipyard/pkg/semver"
+)
+
+// eqEcosystem reports whether the last argument, a package's ecosystem,
+// matches any of the ecosystems before it, ignoring case. The ecosystem comes
+// last so it can be piped: {{ if .Ecosystem | eqEcosystem "npm" "deno" }}
+func eqEcosystem(values ...string) (bool, error) {
+	if len(values) < 2 {
+		return false, fmt.Errorf("eqEcosystem requires at least one ecosystem to compare against")
+	}
+	actual := values[len(values)-1]
+	for _, candidate := range values[:len(values)-1] {
+		if strings.EqualFold(candidate, actual) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// hasMetadata reports whether key is set in value's metadata. value may be a
+// metadata map, anything with a Metadata field or key, or anything holding
+// Consignments or Entries, such as a whole template context.
+func hasMetadata(key string, value interface{}) bool {
+	v := indirect(reflect.ValueOf(value))
+	if hasMapKey(v, key) {
+		return true
+	}
+	return anyRecord(v, func(record reflect.Value) bool {
+		metadata, ok := field(record, "Metadata")
+		return ok && hasMapKey(indirect(metadata), key)
+	})
+}
+
+// anyChangeOfType reports whether any change in value has the given change
+// type, ignoring case. value may be a consignment, a list of consignments, or
+// anything holding Consignments or Entries, such as a whole template context.
+func anyChangeOfType(changeType string, value interface{}) bool {
+	return anyRecord(reflect.ValueOf(value), func(record reflect.Value) bool {
+		ct, ok := field(record, "ChangeType")
+		if !ok {
+			return false
+		}
+		ct = indirect(ct)
+		return ct.Kind() == reflect.String && strings.EqualFold(ct.String(), changeType)
+	})
+}
+
+// anyRecord walks value's structs and string-keyed maps, descending through
+// lists and Consignments and Entries fields, and reports whether match
+// holds for any of them
+func anyRecord(value reflect.Value, match func(record reflect.Value) bool) bool {
+	v := indirect(value)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if anyRecord(v.Index(i), match) {
+				return true
+			}
+		}
+	case reflect.Struct, reflect.Map:
+		if match(v) {
+			return true
+		}
+		for _, name := range []string{"Consignments", "Entries"} {
+			if child, ok := field(v, name); ok && anyRecord(child, match) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// field returns the named exported struct field or string map entry of v
+func field(v reflect.Value, name string) (reflect.Value, bool) {
+	switch v.Kind() {
+	case reflect.Struct:
+		f := v.FieldByName(name)
+		if !f.IsValid() || !f.CanInterface() {
+			return reflect.Value{}, false
+		}
+		return f, true
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return reflect.Value{}, false
+		}
+		entry := v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+		return entry, entry.IsValid()
+	}
+	return reflect.Value{}, false
+}
+
+// hasMapKey reports whether v is a string-keyed map containing key
+func hasMapKey(v reflect.Value, key string) bool {
+	if v.Kind() != reflect.Map || v.Type().Key().Kind() != reflect.String {
+		return false
+	}
+	return v.MapIndex(reflect.ValueOf(key).Convert(v.Type().Key())).IsValid()
+}
+
+// indirect unwraps interfaces and pointers, returning the zero Value for nil
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// regexReplace replaces every match of pattern in text with repl, which may
+// refer to capture groups as $1. The text comes last so it can be piped:
+// {{ .Summary | regexReplace "JIRA-(\\d+)" "[JIRA-$1]" }}
+func regexReplace(pattern, repl, text string) (string, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", fmt.Errorf("regexReplace: invalid pattern %q: %w", pattern, err)
+	}
+	return re.ReplaceAllString(text, repl), nil
+}
+
+// semverPart returns a version's major, minor or patch number. version may be
+// a semver.Version or a string such as "v1.2.3".
+func semverPart(part string, version interface{}) (int, error) {
+	var v semver.Version
+	switch value := version.(type) {
+	case semver.Version:
+		v = value
+	case *semver.Version:
+		if value == nil {
+			return 0, fmt.Errorf("semver%s: no version", part)
+		}
+		v = *value
+	default:
+		parsed, err := semver.Parse(fmt.Sprint(version))
+		if err != nil {
+			return 0, fmt.Errorf("semver%s: %w", part, err)
+		}
+		v = parsed
+	}
+	switch part {
+	case "Major":
+		return v.Major, nil
+	case "Minor":
+		return v.Minor, nil
+	}
+	return v.Patch, nil
+}
+
+func semverMajor(version interface{}) (int, error) { return semverPart("Major", version) }
+func semverMinor(version interface{}) (int, error) { return semverPart("Minor", version) }
+func semverPatch(version interface{}) (int, error) { return semverPart("Patch", version) }
+
+// groupBy groups a list of structs or maps by a field, which may be a dotted
+// path such as "Metadata.scope". Items keep their order within a group, and
+// items without the field are grouped under "". The list comes last so it
+// can be piped: {{ range $type, $changes := .Consignments | groupBy "ChangeType" }}
+func groupBy(path string, list interface{}) (map[string][]interface{}, error) {
+	v := indirect(reflect.ValueOf(list))
+	if v.IsValid() && v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, fmt.Errorf("groupBy: expected a list, got %s", v.Kind())
+	}
+	groups := make(map[string][]interface{})
+	if !v.IsValid() {
+		return groups, nil
+	}
+	for i := 0; i < v.Len(); i++ {
+		item := v.Index(i)
+		key := ""
+		if value, ok := fieldPath(item, path); ok {
+			key = fmt.Sprint(value.Interface())
+		}
+		groups[key] = append(groups[key], item.Interface())
+	}
+	return groups, nil
+}
+
+// fieldPath follows a dotted path of fields and map keys from v
+func fieldPath(v reflect.Value, path string) (reflect.Value, bool) {
+	for _, name := range strings.Split(path, ".") {
+		next, ok := field(indirect(v), name)
+		if !ok {
+			return reflect.Value{}, false
+		}
+		v = next
+	}
+	v = indirect(v)
+	return v, v.IsValid()
+}
@@ -2,8 +2,10 @@ package template
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -11,6 +13,8 @@ import (
 	"time"
 
 	"github.com/NatoNathan/shipyard/internal/fileutil"
+	"github.com/NatoNathan/shipyard/internal/httpclient"
+	"github.com/NatoNathan/shipyard/internal/logger"
 	gogit "github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/transport"
@@ -26,6 +30,15 @@ const (
 	SourceTypeGit
 	SourceTypeHTTPS
 	SourceTypeInline
+	SourceTypeGitHub
+	SourceTypeGitLab
+	SourceTypeBitbucket
+)
+
+// Default GitHub clone URL bases for github: template sources
+const (
+	defaultGitHubSSHBase   = "git@github.com:"
+	defaultGitHubHTTPSBase = "https://github.com/"
 )
 
 // TemplateLoader handles loading templates from various sources
@@ -33,8 +46,19 @@ type TemplateLoader struct {
 	baseDir          string
 	cache            map[string]string
 	authToken        string
-	timeout          time.Duration
+	timeout          time.Duration // Per fetch attempt
+	retries          int           // Times a fetch of an unavailable source is retried
+	retryBackoff     time.Duration // Delay before the first retry, doubled for each later one
+	sleep            func(time.Duration)
 	maxResponseBytes int64
+	diskCache        templateCache
+	credentials      map[string]*Credential // Lowercased host -> credential
+	tls              httpclient.TLSOptions
+	fresh            bool
+	warnings         io.Writer
+	githubSSHBase    string
+	githubHTTPSBase  string
+	forgeBases       map[string][2]string // Host -> SSH and HTTPS clone URL bases
 }
 
 const (
@@ -43,12 +67,22 @@ const (
 	maxTemplateRedirects            = 3
 )
 
-// NewTemplateLoader creates a new template loader
+// NewTemplateLoader creates a new template loader. Retries and the fetch
+// timeout default to FetchRetriesEnv and FetchTimeoutEnv when set.
 func NewTemplateLoader() *TemplateLoader {
+	retries, timeout := fetchSettingsFromEnv(DefaultFetchRetries, defaultTemplateTimeout)
 	return &TemplateLoader{
 		cache:            make(map[string]string),
-		timeout:          defaultTemplateTimeout,
+		timeout:          timeout,
+		retries:          retries,
+		retryBackoff:     defaultRetryBackoff,
+		sleep:            time.Sleep,
 		maxResponseBytes: defaultTemplateMaxResponseBytes,
+		diskCache:        templateCache{dir: DefaultTemplateCacheDir(), ttl: DefaultTemplateCacheTTL},
+		fresh:            os.Getenv(FreshTemplatesEnv) != "",
+		warnings:         os.Stderr,
+		githubSSHBase:    defaultGitHubSSHBase,
+		githubHTTPSBase:  defaultGitHubHTTPSBase,
 	}
 }
 
@@ -67,16 +101,84 @@ func (l *TemplateLoader) GetAuthToken() string {
 	return l.authToken
 }
 
-// SetTimeout sets the timeout for remote operations
+// SetTimeout sets the timeout of each remote fetch attempt
 func (l *TemplateLoader) SetTimeout(timeout time.Duration) {
 	l.timeout = timeout
 }
 
+// SetRetries sets how many times a fetch is retried when the source is
+// unavailable, with exponential backoff between attempts. Zero disables retries.
+func (l *TemplateLoader) SetRetries(retries int) {
+	l.retries = retries
+}
+
+// SetRetryBackoff sets the delay before the first retry; each later retry
+// waits twice as long as the one before, with jitter
+func (l *TemplateLoader) SetRetryBackoff(backoff time.Duration) {
+	l.retryBackoff = backoff
+}
+
 // SetMaxResponseBytes sets the maximum remote template response size.
 func (l *TemplateLoader) SetMaxResponseBytes(maxBytes int64) {
 	l.maxResponseBytes = maxBytes
 }
 
+// SetCacheDir sets the directory remote templates are cached in.
+// An empty directory disables the disk cache.
+func (l *TemplateLoader) SetCacheDir(dir string) {
+	l.diskCache.dir = dir
+}
+
+// SetCacheTTL sets how long a cached remote template is reused before refetching
+func (l *TemplateLoader) SetCacheTTL(ttl time.Duration) {
+	l.diskCache.ttl = ttl
+}
+
+// RemoteOptions are a project's settings for fetching remote templates
+type RemoteOptions struct {
+	CacheTTL    time.Duration          // How long cached templates are reused; DefaultTemplateCacheTTL when zero
+	Credentials map[string]*Credential // Host -> credential; hosts match case-insensitively, with or without a port
+	TLS         httpclient.TLSOptions  // Certificate verification of HTTPS fetches
+}
+
+// SetRemoteOptions sets the cache TTL, per-host credentials and certificate
+// settings of remote fetches
+func (l *TemplateLoader) SetRemoteOptions(opts RemoteOptions) {
+	l.diskCache.ttl = opts.CacheTTL
+	if l.diskCache.ttl <= 0 {
+		l.diskCache.ttl = DefaultTemplateCacheTTL
+	}
+	l.credentials = normalizeCredentials(opts.Credentials)
+	l.tls = opts.TLS
+}
+
+// SetFresh makes the loader ignore cached remote templates and always fetch them
+func (l *TemplateLoader) SetFresh(fresh bool) {
+	l.fresh = fresh
+}
+
+// SetWarningWriter sets where warnings such as stale cache fallbacks are written
+func (l *TemplateLoader) SetWarningWriter(w io.Writer) {
+	l.warnings = w
+}
+
+// SetGitHubBaseURLs sets the clone URL bases github: sources are resolved
+// against. "owner/repo.git" is appended to each base; SSH is tried first.
+func (l *TemplateLoader) SetGitHubBaseURLs(sshBase, httpsBase string) {
+	l.githubSSHBase = sshBase
+	l.githubHTTPSBase = httpsBase
+}
+
+// SetForgeBaseURLs sets the clone URL bases gitlab: and bitbucket: sources
+// on host are resolved against, in place of git@host: and https://host/.
+// "repo.git" is appended to each base; SSH is tried first.
+func (l *TemplateLoader) SetForgeBaseURLs(host, sshBase, httpsBase string) {
+	if l.forgeBases == nil {
+		l.forgeBases = make(map[string][2]string)
+	}
+	l.forgeBases[host] = [2]string{sshBase, httpsBase}
+}
+
 // Load loads a template from the specified source
 // For builtin templates, expectedType specifies which type directory to search
 func (l *TemplateLoader) Load(source string, expectedType ...TemplateType) (string, error) {
@@ -114,6 +216,12 @@ func (l *TemplateLoader) Load(source string, expectedType ...TemplateType) (stri
 		content, err = l.loadHTTPS(target)
 	case SourceTypeGit:
 		content, err = l.loadGit(target)
+	case SourceTypeGitHub:
+		content, err = l.loadGitHub(target)
+	case SourceTypeGitLab:
+		content, err = l.loadForge(ForgeGitLab + ":" + target)
+	case SourceTypeBitbucket:
+		content, err = l.loadForge(ForgeBitbucket + ":" + target)
 	case SourceTypeInline:
 		content = target // Inline content is the target itself
 	default:
@@ -154,6 +262,18 @@ func DetectSourceType(source string) (SourceType, string) {
 		return SourceTypeGit, strings.TrimPrefix(source, "git:")
 	}
 
+	if strings.HasPrefix(source, "github:") {
+		return SourceTypeGitHub, strings.TrimPrefix(source, "github:")
+	}
+
+	if strings.HasPrefix(source, "gitlab:") {
+		return SourceTypeGitLab, strings.TrimPrefix(source, "gitlab:")
+	}
+
+	if strings.HasPrefix(source, "bitbucket:") {
+		return SourceTypeBitbucket, strings.TrimPrefix(source, "bitbucket:")
+	}
+
 	if strings.HasPrefix(source, "https://") || strings.HasPrefix(source, "http://") {
 		return SourceTypeHTTPS, source
 	}
@@ -191,42 +311,172 @@ func (l *TemplateLoader) loadFile(path string) (string, error) {
 	return string(content), nil
 }
 
-// loadHTTPS loads a template from an HTTPS URL
+// errTemplateUnavailable marks fetch failures caused by the network or the
+// server rather than the request, which may fall back to a stale cached copy
+type errTemplateUnavailable struct {
+	err error
+}
+
+func (e *errTemplateUnavailable) Error() string {
+	return e.err.Error()
+}
+
+func (e *errTemplateUnavailable) Unwrap() error {
+	return e.err
+}
+
+// loadHTTPS loads a template from an HTTP(S) URL, cached on disk
 func (l *TemplateLoader) loadHTTPS(url string) (string, error) {
+	return l.loadCached(url, url, func(validators cacheValidators) (fetchedTemplate, error) {
+		return l.fetchHTTPS(url, validators)
+	})
+}
+
+// fetchedTemplate is the result of fetching a remote template
+type fetchedTemplate struct {
+	content     string
+	validators  cacheValidators
+	notModified bool // The server confirmed the cached copy is current
+}
+
+// fetchUncached adapts a fetch without revalidation support for loadCached
+func fetchUncached(fetch func() (string, error)) func(cacheValidators) (fetchedTemplate, error) {
+	return func(cacheValidators) (fetchedTemplate, error) {
+		content, err := fetch()
+		return fetchedTemplate{content: content}, err
+	}
+}
+
+// loadCached returns the disk-cached template for key, fetching it when
+// missing or expired. A cached copy within the TTL is used without fetching.
+// An expired copy is revalidated with its stored validators. Fetches of an
+// unavailable source are retried, and when every attempt fails an expired
+// copy is used with a warning that it may be out of date.
+func (l *TemplateLoader) loadCached(key, display string, fetch func(cacheValidators) (fetchedTemplate, error)) (string, error) {
+	log := logger.Get()
+	cached, hasCached := l.diskCache.get(key)
+	if hasCached && !l.fresh && l.diskCache.fresh(cached) {
+		log.Debug("Using cached copy of %s from %s", display, cached.fetchedAt.Format(time.RFC3339))
+		return cached.content, nil
+	}
+
+	var validators cacheValidators
+	if hasCached && !l.fresh {
+		validators = cached.validators
+	}
+	log.Debug("Fetching %s", display)
+	fetched, err := l.fetchWithRetries(display, func() (fetchedTemplate, error) {
+		return fetch(validators)
+	})
+	if err != nil {
+		var unavailable *errTemplateUnavailable
+		if hasCached && !l.fresh && errors.As(err, &unavailable) {
+			if l.warnings != nil {
+				_, _ = fmt.Fprintf(l.warnings, "warning: could not fetch %s: %v\nwarning: using STALE cached copy of %s from %s; it may be out of date\n",
+					display, err, display, cached.fetchedAt.Format(time.RFC3339))
+			}
+			return cached.content, nil
+		}
+		return "", err
+	}
+	if fetched.notModified {
+		log.Debug("%s not modified; using cached copy", display)
+		l.diskCache.touch(key)
+		return cached.content, nil
+	}
+
+	l.diskCache.put(key, fetched.content)
+	l.diskCache.setValidators(key, fetched.validators)
+	return fetched.content, nil
+}
+
+// fetchHTTPS downloads a template from an HTTP(S) URL within the fetch
+// timeout. With validators from a cached copy it sends a conditional GET,
+// and a 304 reports notModified.
+func (l *TemplateLoader) fetchHTTPS(url string, validators cacheValidators) (fetchedTemplate, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), l.timeout)
+	defer cancel()
+
 	client := &http.Client{
-		Timeout: l.timeout,
+		Transport: httpclient.TransportFor(l.tls),
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			if len(via) >= maxTemplateRedirects {
 				return fmt.Errorf("stopped after %d redirects", maxTemplateRedirects)
 			}
-			if l.authToken != "" && len(via) > 0 {
-				original := via[0].URL
-				if req.URL.Scheme != "https" || req.URL.Host != original.Host {
-					return fmt.Errorf("refusing authenticated redirect to different origin")
+			// Credentials never follow a redirect to another host; that host
+			// only gets a credential configured for it, and only over HTTPS
+			req.Header.Del("Authorization")
+			previous := via[len(via)-1].URL
+			if req.URL.Host != previous.Host {
+				if cred := l.hostCredential(req.URL.Host); cred != nil && req.URL.Scheme == "https" {
+					cred.apply(req)
+				}
+				return nil
+			}
+			if cred := l.credentialFor(req.URL.Host); cred != nil {
+				if req.URL.Scheme != "https" {
+					return fmt.Errorf("refusing authenticated redirect to insecure target %s", req.URL.Redacted())
 				}
+				cred.apply(req)
 			}
 			return nil
 		},
 	}
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return fetchedTemplate{}, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	// Add authentication if token is set
-	if l.authToken != "" {
-		req.Header.Set("Authorization", "Bearer "+l.authToken)
+	if cred := l.credentialFor(req.URL.Host); cred != nil {
+		cred.apply(req)
+	}
+	if validators.ETag != "" {
+		req.Header.Set("If-None-Match", validators.ETag)
+	}
+	if validators.LastModified != "" {
+		req.Header.Set("If-Modified-Since", validators.LastModified)
 	}
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to fetch template: %w", err)
+		// client.Do wraps every failure in *url.Error; only the network
+		// errors beneath it (refused, DNS, timeout) mean the server is unreachable
+		var netErr net.Error
+		if errors.As(errors.Unwrap(err), &netErr) {
+			return fetchedTemplate{}, &errTemplateUnavailable{fmt.Errorf("failed to fetch template: %w", err)}
+		}
+		return fetchedTemplate{}, fmt.Errorf("failed to fetch template: %w", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("failed to fetch template: HTTP %d", resp.StatusCode)
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fetchedTemplate{}, &errTemplateUnavailable{fmt.Errorf("failed to fetch template: HTTP %d", resp.StatusCode)}
+	}
+	// Report against the host that answered, which differs after a redirect
+	host := resp.Request.URL.Host
+	authenticated := resp.Request.Header.Get("Authorization") != ""
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		if validators == (cacheValidators{}) {
+			return fetchedTemplate{}, fmt.Errorf("failed to fetch template: HTTP 304 without a conditional request")
+		}
+		return fetchedTemplate{notModified: true}, nil
+	case http.StatusUnauthorized:
+		if !authenticated {
+			return fetchedTemplate{}, fmt.Errorf("failed to fetch template: HTTP 401 (authentication required; configure remote.auth for %s or set %s)", host, RemoteTokenEnv)
+		}
+		return fetchedTemplate{}, fmt.Errorf("failed to fetch template: HTTP 401 (credentials for %s were rejected)", host)
+	case http.StatusForbidden:
+		return fetchedTemplate{}, fmt.Errorf("failed to fetch template: HTTP 403 (access to %s denied; check the token's permissions)", host)
+	case http.StatusNotFound:
+		if !authenticated {
+			return fetchedTemplate{}, fmt.Errorf("failed to fetch template: HTTP 404 (not found; private hosts may also answer 404 without credentials)")
+		}
+		return fetchedTemplate{}, fmt.Errorf("failed to fetch template: HTTP 404 (not found)")
+	default:
+		return fetchedTemplate{}, fmt.Errorf("failed to fetch template: HTTP %d", resp.StatusCode)
 	}
 
 	maxBytes := l.maxResponseBytes
@@ -234,15 +484,21 @@ func (l *TemplateLoader) loadHTTPS(url string) (string, error) {
 		maxBytes = defaultTemplateMaxResponseBytes
 	}
 	if resp.ContentLength > maxBytes {
-		return "", fmt.Errorf("template response exceeds maximum size of %d bytes", maxBytes)
+		return fetchedTemplate{}, fmt.Errorf("template response exceeds maximum size of %d bytes", maxBytes)
 	}
 
 	content, err := readLimited(resp.Body, maxBytes)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response: %w", err)
+		return fetchedTemplate{}, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	return string(content), nil
+	return fetchedTemplate{
+		content: string(content),
+		validators: cacheValidators{
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+		},
+	}, nil
 }
 
 func readLimited(reader io.Reader, maxBytes int64) ([]byte, error) {
@@ -257,17 +513,118 @@ func readLimited(reader io.Reader, maxBytes int64) ([]byte, error) {
 	return content, nil
 }
 
-// loadGit loads a template from a git repository.
+// loadGit loads a template from a git repository, cached on disk by the
+// full reference.
 // Format: git:https://github.com/user/repo.git#path/to/template@branch
 func (l *TemplateLoader) loadGit(source string) (string, error) {
 	gitURL, templatePath, ref := parseGitSource(source)
 	if gitURL == "" || templatePath == "" {
 		return "", fmt.Errorf("invalid git source format: %s", source)
 	}
+
+	if err := checkTemplatePath(templatePath); err != nil {
+		return "", err
+	}
+
+	reference := "git:" + source
+	return l.loadCached(reference, reference, fetchUncached(func() (string, error) {
+		return l.cloneAndReadFile(gitURL, templatePath, ref)
+	}))
+}
+
+// loadGitHub loads a template from a GitHub repository, cached on disk by the
+// full reference. The repository is cloned over SSH first, then HTTPS.
+// Format: github:owner/repo/path/to/template@ref (ref defaults to the default branch)
+func (l *TemplateLoader) loadGitHub(source string) (string, error) {
+	owner, repo, templatePath, ref, err := parseGitHubSource(source)
+	if err != nil {
+		return "", err
+	}
+	if err := checkTemplatePath(templatePath); err != nil {
+		return "", err
+	}
+
+	reference := "github:" + source
+	return l.loadCached(reference, reference, fetchUncached(func() (string, error) {
+		repoPath := owner + "/" + repo + ".git"
+		return l.cloneSSHThenHTTPS(l.githubSSHBase+repoPath, l.githubHTTPSBase+repoPath, templatePath, ref)
+	}))
+}
+
+// loadForge loads a template from a GitLab or Bitbucket repository, cached
+// on disk by the canonical reference. The repository is cloned over SSH
+// first, then HTTPS.
+// Format: gitlab:[host=HOST:]group/subgroup/repo//path/to/template[@ref]
+func (l *TemplateLoader) loadForge(source string) (string, error) {
+	fs, err := ParseForgeSource(source)
+	if err != nil {
+		return "", err
+	}
+	if err := checkTemplatePath(fs.Path); err != nil {
+		return "", err
+	}
+
+	sshURL, httpsURL := fs.SSHURL(), fs.HTTPSURL()
+	if bases, ok := l.forgeBases[fs.Host]; ok {
+		sshURL, httpsURL = bases[0]+fs.Repo+".git", bases[1]+fs.Repo+".git"
+	}
+
+	return l.loadCached(fs.String(), source, fetchUncached(func() (string, error) {
+		return l.cloneSSHThenHTTPS(sshURL, httpsURL, fs.Path, fs.Ref)
+	}))
+}
+
+// cloneSSHThenHTTPS reads a file from a repository over SSH, falling back
+// to HTTPS when the SSH clone fails
+func (l *TemplateLoader) cloneSSHThenHTTPS(sshURL, httpsURL, templatePath, ref string) (string, error) {
+	content, sshErr := l.cloneAndReadFile(sshURL, templatePath, ref)
+	if sshErr == nil {
+		return content, nil
+	}
+	var unavailable *errTemplateUnavailable
+	if !errors.As(sshErr, &unavailable) {
+		// The clone worked, so HTTPS would hit the same problem
+		return "", sshErr
+	}
+	logger.Get().Debug("SSH clone of %s failed, trying HTTPS: %v", sshURL, sshErr)
+
+	content, err := l.cloneAndReadFile(httpsURL, templatePath, ref)
+	if err != nil && errors.As(err, &unavailable) {
+		return "", &errTemplateUnavailable{fmt.Errorf("%w (ssh: %v)", err, sshErr)}
+	}
+	return content, err
+}
+
+// parseGitHubSource parses owner/repo/path@ref
+func parseGitHubSource(source string) (owner, repo, path, ref string, err error) {
+	if idx := strings.LastIndex(source, "@"); idx != -1 {
+		source, ref = source[:idx], source[idx+1:]
+	}
+
+	parts := strings.SplitN(source, "/", 3)
+	if len(parts) < 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", "", fmt.Errorf("invalid github source format: github:%s (expected github:owner/repo/path[@ref])", source)
+	}
+	return parts[0], strings.TrimSuffix(parts[1], ".git"), parts[2], ref, nil
+}
+
+// checkTemplatePath rejects template paths that escape the repository
+func checkTemplatePath(templatePath string) error {
 	cleanTemplatePath := filepath.Clean(templatePath)
 	if filepath.IsAbs(cleanTemplatePath) || cleanTemplatePath == ".." || strings.HasPrefix(cleanTemplatePath, ".."+string(filepath.Separator)) {
-		return "", fmt.Errorf("unsafe git template path: %s", templatePath)
+		return fmt.Errorf("unsafe git template path: %s", templatePath)
+	}
+	return nil
+}
+
+// cloneAndReadFile shallow-clones a repository into a temporary directory and
+// reads a file from it. Clone failures are reported as unavailable so callers
+// can fall back to another URL or a cached copy.
+func (l *TemplateLoader) cloneAndReadFile(gitURL, templatePath, ref string) (string, error) {
+	if err := checkTemplatePath(templatePath); err != nil {
+		return "", err
 	}
+	cleanTemplatePath := filepath.Clean(templatePath)
 
 	cloneDir, err := os.MkdirTemp("", "shipyard-template-*")
 	if err != nil {
@@ -278,6 +635,7 @@ func (l *TemplateLoader) loadGit(source string) (string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), l.timeout)
 	defer cancel()
 
+	logger.Get().Debug("Cloning %s", gitURL)
 	_, err = gogit.PlainCloneContext(ctx, cloneDir, false, &gogit.CloneOptions{
 		URL:           gitURL,
 		Depth:         1,
@@ -286,7 +644,7 @@ func (l *TemplateLoader) loadGit(source string) (string, error) {
 		Auth:          l.gitAuth(gitURL),
 	})
 	if err != nil {
-		return "", fmt.Errorf("failed to clone template repository: %w", err)
+		return "", &errTemplateUnavailable{fmt.Errorf("failed to clone template repository: %w", err)}
 	}
 
 	templateFile := filepath.Join(cloneDir, cleanTemplatePath)
@@ -322,13 +680,15 @@ func referenceNameFor(ref string) plumbing.ReferenceName {
 }
 
 func (l *TemplateLoader) gitAuth(gitURL string) transport.AuthMethod {
-	if l.authToken == "" {
+	if !strings.HasPrefix(gitURL, "https://") {
 		return nil
 	}
-	if strings.HasPrefix(gitURL, "https://") {
-		return &gitHttp.BasicAuth{Username: "token", Password: l.authToken}
+	host, _, _ := strings.Cut(strings.TrimPrefix(gitURL, "https://"), "/")
+	cred := l.credentialFor(host)
+	if cred == nil {
+		return nil
 	}
-	return nil
+	return &gitHttp.BasicAuth{Username: cred.username(), Password: cred.Token}
 }
 
 // parseGitSource parses a git source string into components
@@ -326,6 +326,182 @@ func TestLoadTemplate_Git(t *testing.T) {
 	})
 }
 
+// newBareGitHubFixture creates <root>/<owner>/<repo>.git as a bare repository
+// holding files, so github: sources resolve without network access
+func newBareGitHubFixture(t *testing.T, owner, repo string, files map[string]string) string {
+	t.Helper()
+
+	workDir := t.TempDir()
+	work, err := gogit.PlainInit(workDir, false)
+	require.NoError(t, err)
+	worktree, err := work.Worktree()
+	require.NoError(t, err)
+	for name, content := range files {
+		path := filepath.Join(workDir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		_, err = worktree.Add(name)
+		require.NoError(t, err)
+	}
+	_, err = worktree.Commit("add templates", &gogit.CommitOptions{
+		Author: &object.Signature{Name: "Test", Email: "test@example.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+
+	root := t.TempDir()
+	_, err = gogit.PlainClone(filepath.Join(root, owner, repo+".git"), true, &gogit.CloneOptions{URL: workDir})
+	require.NoError(t, err)
+	return root
+}
+
+func TestLoadTemplate_GitHub(t *testing.T) {
+	files := map[string]string{"templates/changelog.tmpl": "github template"}
+
+	newLoader := func(t *testing.T, root string) *TemplateLoader {
+		loader := NewTemplateLoader()
+		loader.SetCacheDir(t.TempDir())
+		// SSH points nowhere, so every load exercises the HTTPS fallback
+		loader.SetGitHubBaseURLs(filepath.Join(t.TempDir(), "missing")+"/", root+"/")
+		return loader
+	}
+
+	t.Run("falls back from ssh to https", func(t *testing.T) {
+		root := newBareGitHubFixture(t, "acme", "templates", files)
+
+		content, err := newLoader(t, root).Load("github:acme/templates/templates/changelog.tmpl")
+
+		require.NoError(t, err)
+		assert.Equal(t, "github template", content)
+	})
+
+	t.Run("uses ssh when available", func(t *testing.T) {
+		root := newBareGitHubFixture(t, "acme", "templates", files)
+		loader := newLoader(t, root)
+		loader.SetGitHubBaseURLs(root+"/", filepath.Join(t.TempDir(), "missing")+"/")
+
+		content, err := loader.Load("github:acme/templates/templates/changelog.tmpl")
+
+		require.NoError(t, err)
+		assert.Equal(t, "github template", content)
+	})
+
+	t.Run("resolves ref", func(t *testing.T) {
+		root := newBareGitHubFixture(t, "acme", "templates", files)
+
+		content, err := newLoader(t, root).Load("github:acme/templates/templates/changelog.tmpl@master")
+		require.NoError(t, err)
+		assert.Equal(t, "github template", content)
+
+		_, err = newLoader(t, root).Load("github:acme/templates/templates/changelog.tmpl@no-such-branch")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to clone template repository")
+	})
+
+	t.Run("caches by full reference", func(t *testing.T) {
+		root := newBareGitHubFixture(t, "acme", "templates", files)
+		cacheDir := t.TempDir()
+		source := "github:acme/templates/templates/changelog.tmpl@master"
+
+		first := newLoader(t, root)
+		first.SetCacheDir(cacheDir)
+		_, err := first.Load(source)
+		require.NoError(t, err)
+
+		// The repository is gone, so only the disk cache can answer
+		require.NoError(t, os.RemoveAll(filepath.Join(root, "acme")))
+		second := newLoader(t, root)
+		second.SetCacheDir(cacheDir)
+		content, err := second.Load(source)
+		require.NoError(t, err)
+		assert.Equal(t, "github template", content)
+
+		// A different ref is a different cache entry
+		third := newLoader(t, root)
+		third.SetCacheDir(cacheDir)
+		_, err = third.Load("github:acme/templates/templates/changelog.tmpl")
+		require.Error(t, err)
+	})
+
+	t.Run("missing file is an error", func(t *testing.T) {
+		root := newBareGitHubFixture(t, "acme", "templates", files)
+
+		_, err := newLoader(t, root).Load("github:acme/templates/templates/missing.tmpl")
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to resolve git template file")
+	})
+
+	t.Run("rejects invalid and unsafe references", func(t *testing.T) {
+		loader := NewTemplateLoader()
+
+		_, err := loader.Load("github:acme/templates")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid github source format")
+
+		_, err = loader.Load("github:acme/templates/../secret")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unsafe")
+	})
+}
+
+func TestLoadTemplate_Forges(t *testing.T) {
+	files := map[string]string{"shipyard/changelog.tmpl": "forge template"}
+
+	t.Run("gitlab nested subgroups on a self-hosted instance", func(t *testing.T) {
+		root := newBareGitHubFixture(t, "platform/shared", "templates", files)
+		loader := NewTemplateLoader()
+		loader.SetCacheDir(t.TempDir())
+		loader.SetForgeBaseURLs("git.corp.example.com", filepath.Join(t.TempDir(), "missing")+"/", root+"/")
+
+		content, err := loader.Load("gitlab:host=git.corp.example.com:platform/shared/templates//shipyard/changelog.tmpl@master")
+
+		require.NoError(t, err)
+		assert.Equal(t, "forge template", content)
+	})
+
+	t.Run("bitbucket over ssh", func(t *testing.T) {
+		root := newBareGitHubFixture(t, "acme", "templates", files)
+		loader := NewTemplateLoader()
+		loader.SetCacheDir(t.TempDir())
+		loader.SetForgeBaseURLs("bitbucket.org", root+"/", filepath.Join(t.TempDir(), "missing")+"/")
+
+		content, err := loader.Load("bitbucket:acme/templates/shipyard/changelog.tmpl")
+
+		require.NoError(t, err)
+		assert.Equal(t, "forge template", content)
+	})
+
+	t.Run("caches by canonical reference", func(t *testing.T) {
+		root := newBareGitHubFixture(t, "acme", "templates", files)
+		cacheDir := t.TempDir()
+		newLoader := func() *TemplateLoader {
+			loader := NewTemplateLoader()
+			loader.SetCacheDir(cacheDir)
+			loader.SetForgeBaseURLs("gitlab.com", filepath.Join(t.TempDir(), "missing")+"/", root+"/")
+			return loader
+		}
+
+		_, err := newLoader().Load("gitlab:acme/templates/shipyard/changelog.tmpl")
+		require.NoError(t, err)
+
+		// The same file written with an explicit separator hits the cache
+		require.NoError(t, os.RemoveAll(filepath.Join(root, "acme")))
+		content, err := newLoader().Load("gitlab:acme/templates//shipyard/changelog.tmpl")
+		require.NoError(t, err)
+		assert.Equal(t, "forge template", content)
+	})
+
+	t.Run("rejects invalid and unsafe references", func(t *testing.T) {
+		loader := NewTemplateLoader()
+
+		_, err := loader.Load("gitlab:acme/templates")
+		assert.ErrorContains(t, err, "invalid gitlab source format")
+
+		_, err = loader.Load("bitbucket:acme/templates/../secret")
+		assert.ErrorContains(t, err, "unsafe")
+	})
+}
+
 func TestDetectTemplateSource(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -362,6 +538,27 @@ func TestDetectTemplateSource(t *testing.T) {
 			expectedTarget: "https://github.com/example/repo.git#path@ref",
 			description:    "should detect git source",
 		},
+		{
+			name:           "github format",
+			source:         "github:acme/templates/changelog.tmpl@v1",
+			expectedType:   SourceTypeGitHub,
+			expectedTarget: "acme/templates/changelog.tmpl@v1",
+			description:    "should detect github source",
+		},
+		{
+			name:           "gitlab format",
+			source:         "gitlab:host=git.corp.example.com:group/sub/repo//changelog.tmpl",
+			expectedType:   SourceTypeGitLab,
+			expectedTarget: "host=git.corp.example.com:group/sub/repo//changelog.tmpl",
+			description:    "should detect gitlab source",
+		},
+		{
+			name:           "bitbucket format",
+			source:         "bitbucket:acme/templates/changelog.tmpl",
+			expectedType:   SourceTypeBitbucket,
+			expectedTarget: "acme/templates/changelog.tmpl",
+			description:    "should detect bitbucket source",
+		},
 		{
 			name:           "inline format (multiline)",
 			source:         "# Template\n{{ .Version }}",
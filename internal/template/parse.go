@@ -2,11 +2,21 @@ package template
 
 import (
 	"fmt"
+	"maps"
+	"regexp"
 	"text/template"
 
 	"github.com/Masterminds/sprig/v3"
+
+	"github.com/NatoNathan/shipyard/internal/fileutil"
+	"github.com/NatoNathan/shipyard/internal/git"
 )
 
+// undefinedFunctionRe matches text/template's error for a call to a function
+// that is not in the function map, capturing the template name, the line and
+// the function
+var undefinedFunctionRe = regexp.MustCompile(`^template: (.*):(\d+): function "([^"]+)" not defined$`)
+
 // TemplateParser handles parsing go templates with Sprig functions
 type TemplateParser struct {
 	funcMap            template.FuncMap
@@ -44,6 +54,9 @@ func (p *TemplateParser) Parse(name, content string) (*template.Template, error)
 	// Parse the template content
 	parsed, err := tmpl.Parse(content)
 	if err != nil {
+		if match := undefinedFunctionRe.FindStringSubmatch(err.Error()); match != nil {
+			return nil, fmt.Errorf("failed to parse template: template %s, line %s: unknown function %q", match[1], match[2], match[3])
+		}
 		return nil, fmt.Errorf("failed to parse template: %w", err)
 	}
 
@@ -68,6 +81,12 @@ func (p *TemplateParser) EnableEnvironmentAccess() {
 	}
 }
 
+// SetRepository sets the repository the link functions of templates parsed
+// afterwards point into. nil leaves them unlinked.
+func (p *TemplateParser) SetRepository(repo *Repository) {
+	maps.Copy(p.funcMap, repositoryFunctions(repo))
+}
+
 // SetOption sets a template option (e.g., "missingkey=error")
 func (p *TemplateParser) SetOption(key, value string) {
 	p.options[key] = value
@@ -146,6 +165,36 @@ func addCustomFunctions(funcMap template.FuncMap) {
 		}
 		return values
 	}
+
+	// eqEcosystem: Branch on a package's ecosystem
+	funcMap["eqEcosystem"] = eqEcosystem
+
+	// hasMetadata: Check whether consignment metadata sets a key
+	funcMap["hasMetadata"] = hasMetadata
+
+	// anyChangeOfType: Check whether any change has a change type
+	funcMap["anyChangeOfType"] = anyChangeOfType
+
+	// sanitizeRef: Clean a value for use in a git tag name
+	funcMap["sanitizeRef"] = git.SanitizeRefName
+
+	// sanitizePath: Clean a value into a relative path that stays in its directory
+	funcMap["sanitizePath"] = fileutil.SanitizePath
+
+	// regexReplace: Replace regular expression matches, with $1 for groups
+	funcMap["regexReplace"] = regexReplace
+
+	// semverMajor, semverMinor, semverPatch: Read a part of a version
+	funcMap["semverMajor"] = semverMajor
+	funcMap["semverMinor"] = semverMinor
+	funcMap["semverPatch"] = semverPatch
+
+	// groupBy: Group a list by a field
+	funcMap["groupBy"] = groupBy
+
+	// repoHost, repoHostType, repoURL, commitURL, compareURL, treeURL,
+	// releaseURL, linkIssues: Link into the repository set with SetRepository
+	maps.Copy(funcMap, repositoryFunctions(nil))
 }
 
 // ParseWithFunctions parses a template with custom functions
@@ -169,3 +218,41 @@ func MustParse(name, content string) *template.Template {
 	}
 	return tmpl
 }
+
+// ValidateTemplate checks a template source of the given type without
+// rendering it. Builtin names must exist and builtin and inline content must
+// parse; file and remote sources are only checked for a well-formed source,
+// since their content is read when the template is used.
+func ValidateTemplate(source string, templateType TemplateType) error {
+	switch source {
+	case "changelog", "release-notes":
+		return nil
+	}
+
+	sourceType, target := DetectSourceType(source)
+	var content string
+	switch sourceType {
+	case SourceTypeBuiltin:
+		builtin, err := GetBuiltinTemplate(templateType, target)
+		if err != nil {
+			return err
+		}
+		content = builtin
+	case SourceTypeInline:
+		content = target
+	case SourceTypeGitHub:
+		_, _, _, _, err := parseGitHubSource(target)
+		return err
+	case SourceTypeGitLab:
+		_, err := ParseForgeSource(ForgeGitLab + ":" + target)
+		return err
+	case SourceTypeBitbucket:
+		_, err := ParseForgeSource(ForgeBitbucket + ":" + target)
+		return err
+	default:
+		return nil
+	}
+
+	_, err := NewTemplateParser().Parse(string(templateType), content)
+	return err
+}
@@ -253,6 +253,13 @@ func TestParseTemplate_ErrorMessages(t *testing.T) {
 	}
 }
 
+func TestParseTemplate_UnknownFunction(t *testing.T) {
+	parser := NewTemplateParser()
+	_, err := parser.Parse("release-notes.tmpl", "# Notes\n\n{{ .Summary | linkify }}\n")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `template release-notes.tmpl, line 3: unknown function "linkify"`)
+}
+
 func TestParseTemplate_Caching(t *testing.T) {
 	parser := NewTemplateParser()
 
@@ -300,3 +307,29 @@ func TestTemplateParser_WithOptions(t *testing.T) {
 		assert.NotNil(t, tmpl)
 	})
 }
+
+func TestValidateTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		wantErr bool
+	}{
+		{name: "builtin", source: "builtin:keepachangelog"},
+		{name: "alias", source: "changelog"},
+		{name: "inline", source: "# Changelog\n{{ range .Entries }}{{ .Version }}{{ end }}"},
+		{name: "file", source: "templates/changelog.tmpl"},
+		{name: "unknown builtin", source: "builtin:missing", wantErr: true},
+		{name: "broken inline", source: "# Changelog\n{{ range .Entries }}", wantErr: true},
+		{name: "malformed github source", source: "github:owner", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTemplate(tt.source, TemplateTypeChangelog)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
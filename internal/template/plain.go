@@ -0,0 +1,58 @@
+package template
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	plainHeading    = regexp.MustCompile(`^#{1,6}\s+(.*?)(\s+#+)?\s*$`)
+	plainImage      = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	plainLink       = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	plainInlineCode = regexp.MustCompile("`([^`]+)`")
+	plainStrong     = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	plainEmphasis   = regexp.MustCompile(`(^|[^\w*])\*([^*\s][^*]*)\*|(^|[^\w])_([^_\s][^_]*)_`)
+	plainQuote      = regexp.MustCompile(`^>\s?`)
+	plainEntities   = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&amp;", "&")
+)
+
+// PlainText converts rendered markdown notes to plain text for places that
+// do not render markdown, such as commit messages and terminals. Heading
+// markers, emphasis, inline code and code fences are removed, links become
+// "text (url)", images keep their alt text, and escaped HTML is unescaped.
+// The contents of code blocks are kept verbatim.
+func PlainText(markdown string) string {
+	var out []string
+	inFence := false
+	for _, line := range strings.Split(NormalizeNewlines(markdown), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			out = append(out, line)
+			continue
+		}
+		out = append(out, plainLine(line))
+	}
+	return strings.Join(out, "\n")
+}
+
+// plainLine strips the markdown from a line outside a code block
+func plainLine(line string) string {
+	line = plainHeading.ReplaceAllString(line, "$1")
+	line = plainQuote.ReplaceAllString(line, "")
+	line = plainImage.ReplaceAllString(line, "$1")
+	line = plainLink.ReplaceAllStringFunc(line, func(link string) string {
+		parts := plainLink.FindStringSubmatch(link)
+		if parts[1] == parts[2] {
+			return parts[2]
+		}
+		return parts[1] + " (" + parts[2] + ")"
+	})
+	line = plainInlineCode.ReplaceAllString(line, "$1")
+	line = plainStrong.ReplaceAllString(line, "$1$2")
+	line = plainEmphasis.ReplaceAllString(line, "$1$2$3$4")
+	return plainEntities.Replace(line)
+}
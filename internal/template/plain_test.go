@@ -0,0 +1,35 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlainText(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{name: "heading", markdown: "# Release Notes: core v1.2.0", want: "Release Notes: core v1.2.0"},
+		{name: "closed heading", markdown: "## Changes ##", want: "Changes"},
+		{name: "strong and emphasis", markdown: "- **Minor**: Add _dark_ mode and *themes*", want: "- Minor: Add dark mode and themes"},
+		{name: "identifiers keep underscores", markdown: "Rename snake_case_name to foo_bar", want: "Rename snake_case_name to foo_bar"},
+		{name: "link", markdown: "See [the docs](https://example.com/docs).", want: "See the docs (https://example.com/docs)."},
+		{name: "bare link", markdown: "[https://example.com](https://example.com)", want: "https://example.com"},
+		{name: "image", markdown: "![logo](logo.png) Shipyard", want: "logo Shipyard"},
+		{name: "inline code", markdown: "Require `v1.2.0` of the module", want: "Require v1.2.0 of the module"},
+		{name: "blockquote", markdown: "> Breaking change", want: "Breaking change"},
+		{name: "escaped html", markdown: "Support &lt;div&gt; tags", want: "Support <div> tags"},
+		{name: "code fence", markdown: "## Install\n\n```sh\nnpm install core@1.2.0\n```", want: "Install\n\nnpm install core@1.2.0"},
+		{name: "markdown inside fence is kept", markdown: "```\n# not a heading\n```", want: "# not a heading"},
+		{name: "crlf", markdown: "# Title\r\n**bold**", want: "Title\nbold"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, PlainText(tt.markdown))
+		})
+	}
+}
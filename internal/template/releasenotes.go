@@ -22,21 +22,35 @@ func RenderReleaseNotes(entries []history.Entry) (string, error) {
 // - "changelog" - auto-maps to changelog template for multi-version
 // - "release-notes" - auto-maps to release-notes template for single-version
 func RenderReleaseNotesWithTemplate(entries []history.Entry, templateSource string) (string, error) {
-	return renderWithMode(entries, templateSource, TemplateTypeReleaseNotes)
+	return RenderReleaseNotesWithOptions(entries, templateSource, DefaultRenderOptions())
+}
+
+// RenderReleaseNotesWithOptions renders a single-version release note using the
+// given render options.
+func RenderReleaseNotesWithOptions(entries []history.Entry, templateSource string, opts RenderOptions) (string, error) {
+	return renderWithMode(entries, templateSource, TemplateTypeReleaseNotes, opts)
 }
 
 // RenderChangelogWithTemplate renders a multi-version changelog.
 // Custom templates always receive a ChangelogContext as context.
 func RenderChangelogWithTemplate(entries []history.Entry, templateSource string) (string, error) {
-	return renderWithMode(entries, templateSource, TemplateTypeChangelog)
+	return RenderChangelogWithOptions(entries, templateSource, DefaultRenderOptions())
+}
+
+// RenderChangelogWithOptions renders a multi-version changelog using the given
+// render options.
+func RenderChangelogWithOptions(entries []history.Entry, templateSource string, opts RenderOptions) (string, error) {
+	return renderWithMode(entries, templateSource, TemplateTypeChangelog, opts)
 }
 
 // renderWithMode is the shared implementation. mode controls the context type used
-// for custom (non-alias) template sources.
-func renderWithMode(entries []history.Entry, templateSource string, mode TemplateType) (string, error) {
+// for custom (non-alias) template sources. Summaries are normalized according to
+// opts; the original text remains available to templates as .RawSummary.
+func renderWithMode(entries []history.Entry, templateSource string, mode TemplateType, opts RenderOptions) (string, error) {
 	if len(entries) == 0 {
 		return "No releases found\n", nil
 	}
+	entries = normalizeEntries(entries, opts)
 
 	var templateType TemplateType
 	var source string
@@ -57,7 +71,9 @@ func renderWithMode(entries []history.Entry, templateSource string, mode Templat
 	}
 
 	loader := NewTemplateLoader()
+	loader.SetRemoteOptions(opts.Remote)
 	renderer := NewTemplateRenderer()
+	renderer.SetRepository(opts.Repository)
 
 	templateContent, err := loader.Load(source, templateType)
 	if err != nil {
@@ -71,12 +87,12 @@ func renderWithMode(entries []history.Entry, templateSource string, mode Templat
 		slices.SortFunc(sorted, func(a, b history.Entry) int {
 			return b.Timestamp.Compare(a.Timestamp)
 		})
-		context = newChangelogContext(sorted)
+		context = newChangelogContext(sorted, opts.Repository)
 	} else {
 		context = entries[0]
 	}
 
-	output, err := renderer.Render(templateContent, context)
+	output, err := renderer.RenderWithName(source, templateContent, context)
 	if err != nil {
 		return "", fmt.Errorf("failed to render template: %w", err)
 	}
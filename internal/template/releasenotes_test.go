@@ -1,6 +1,7 @@
 package template
 
 import (
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -312,7 +313,7 @@ func TestNewChangelogContext(t *testing.T) {
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			ctx := newChangelogContext(tc.entries)
+			ctx := newChangelogContext(tc.entries, nil)
 			assert.Equal(t, tc.wantPackage, ctx.Package)
 			assert.Equal(t, tc.wantLatestVer, ctx.LatestVersion)
 			assert.Equal(t, tc.wantStable, ctx.LatestStable)
@@ -325,3 +326,177 @@ func TestNewChangelogContext(t *testing.T) {
 		})
 	}
 }
+
+// TestRenderChangelog_Unreleased tests the Unreleased section of the
+// keepachangelog template, built from entries without a version
+func TestRenderChangelog_Unreleased(t *testing.T) {
+	opts := linkOptions("git@github.com:acme/core.git")
+	ts := time.Date(2026, 1, 30, 10, 0, 0, 0, time.UTC)
+	released := []history.Entry{
+		{Package: "core", Version: "1.1.0", Tag: "v1.1.0", Timestamp: ts, Consignments: []history.Consignment{
+			{ID: "c2", Summary: "Add search", ChangeType: "minor"},
+		}},
+		{Package: "core", Version: "1.0.0", Tag: "v1.0.0", Timestamp: ts.Add(-24 * time.Hour), Consignments: []history.Consignment{
+			{ID: "c1", Summary: "Initial release", ChangeType: "major"},
+		}},
+	}
+	unreleased := history.Entry{Package: "core", Consignments: []history.Consignment{
+		{ID: "c3", Summary: "Fix crash on empty query", ChangeType: "patch"},
+		{ID: "c4", Summary: "Add filters", ChangeType: "minor"},
+	}}
+
+	t.Run("pending changes come first", func(t *testing.T) {
+		output, err := RenderChangelogWithOptions(append(slices.Clone(released), unreleased), "builtin:keepachangelog", opts)
+		require.NoError(t, err)
+
+		unreleasedAt := strings.Index(output, "## [Unreleased]")
+		newestAt := strings.Index(output, "## [1.1.0]")
+		oldestAt := strings.Index(output, "## [1.0.0]")
+		require.NotEqual(t, -1, unreleasedAt)
+		assert.Less(t, unreleasedAt, newestAt)
+		assert.Less(t, newestAt, oldestAt)
+
+		section := output[unreleasedAt:newestAt]
+		assert.Contains(t, section, "### Added\n- Add filters")
+		assert.Contains(t, section, "### Fixed\n- Fix crash on empty query")
+		assert.NotContains(t, section, "Add search")
+		assert.True(t, strings.HasSuffix(output, "\n\n[Unreleased]: https://github.com/acme/core/compare/v1.1.0...HEAD\n"+
+			"[1.1.0]: https://github.com/acme/core/compare/v1.0.0...v1.1.0\n"+
+			"[1.0.0]: https://github.com/acme/core/tree/v1.0.0\n"), output)
+	})
+
+	t.Run("section disappears once shipped", func(t *testing.T) {
+		output, err := RenderChangelogWithOptions(released, "builtin:keepachangelog", opts)
+		require.NoError(t, err)
+		assert.NotContains(t, output, "Unreleased")
+	})
+
+	t.Run("no releases yet", func(t *testing.T) {
+		output, err := RenderChangelogWithOptions([]history.Entry{unreleased}, "builtin:keepachangelog", opts)
+		require.NoError(t, err)
+		assert.Contains(t, output, "## [Unreleased]\n\n### Added\n- Add filters")
+		assert.NotContains(t, output, "[Unreleased]:", "there is no tag to compare against")
+	})
+}
+
+// TestRenderChangelog_LinkReferences tests the compare links the
+// keepachangelog template ends with, one per release
+func TestRenderChangelog_LinkReferences(t *testing.T) {
+	ts := time.Date(2026, 1, 30, 10, 0, 0, 0, time.UTC)
+	release := func(version, tag string, age int) history.Entry {
+		return history.Entry{Package: "core", Version: version, Tag: tag, Timestamp: ts.Add(-time.Duration(age) * time.Hour),
+			Consignments: []history.Consignment{{ID: "c" + version, Summary: "Change " + version, ChangeType: "minor"}}}
+	}
+
+	tests := []struct {
+		name     string
+		remote   string
+		entries  []history.Entry
+		expected string
+	}{
+		{
+			name:    "single package",
+			remote:  "git@github.com:acme/core.git",
+			entries: []history.Entry{release("1.0.0", "v1.0.0", 2), release("1.2.0", "v1.2.0", 0), release("1.1.0", "v1.1.0", 1)},
+			expected: "\n\n[1.2.0]: https://github.com/acme/core/compare/v1.1.0...v1.2.0\n" +
+				"[1.1.0]: https://github.com/acme/core/compare/v1.0.0...v1.1.0\n" +
+				"[1.0.0]: https://github.com/acme/core/tree/v1.0.0\n",
+		},
+		{
+			name:    "monorepo package tags",
+			remote:  "https://gitlab.com/acme/platform.git",
+			entries: []history.Entry{release("2.1.0", "core/v2.1.0", 0), release("2.0.0", "core/v2.0.0", 1)},
+			expected: "\n\n[2.1.0]: https://gitlab.com/acme/platform/-/compare/core/v2.0.0...core/v2.1.0\n" +
+				"[2.0.0]: https://gitlab.com/acme/platform/-/tree/core/v2.0.0\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := RenderChangelogWithOptions(tt.entries, "builtin:keepachangelog", linkOptions(tt.remote))
+			require.NoError(t, err)
+			assert.True(t, strings.HasSuffix(output, tt.expected), output)
+		})
+	}
+
+	t.Run("entries know the previous release", func(t *testing.T) {
+		output, err := RenderChangelogWithOptions([]history.Entry{release("1.1.0", "v1.1.0", 0), release("1.0.0", "v1.0.0", 1)},
+			`{{ range .Entries }}{{ .Version }} {{ .PreviousVersion }} {{ .PreviousTag }} {{ .ReleaseURL }}
+{{ end }}`, linkOptions("git@github.com:acme/core.git"))
+		require.NoError(t, err)
+		assert.Equal(t, "1.1.0 1.0.0 v1.0.0 https://github.com/acme/core/releases/tag/v1.1.0\n"+
+			"1.0.0   https://github.com/acme/core/releases/tag/v1.0.0\n", output)
+	})
+
+	t.Run("no links without a known repository", func(t *testing.T) {
+		output, err := RenderChangelogWithTemplate([]history.Entry{release("1.1.0", "v1.1.0", 0), release("1.0.0", "v1.0.0", 1)}, "builtin:keepachangelog")
+		require.NoError(t, err)
+		assert.NotContains(t, output, "]: ")
+	})
+}
+
+// TestRenderReleaseNotes_InstallInstructions tests the ecosystem-conditional
+// install section of the builtin release notes template
+func TestRenderReleaseNotes_InstallInstructions(t *testing.T) {
+	timestamp := time.Date(2026, 1, 30, 10, 0, 0, 0, time.UTC)
+	render := func(ecosystem string) string {
+		t.Helper()
+		output, err := RenderReleaseNotes([]history.Entry{{
+			Version:      "1.2.0",
+			Package:      "widgets",
+			Timestamp:    timestamp,
+			Ecosystem:    ecosystem,
+			Consignments: []history.Consignment{{ID: "c1", Summary: "Add widget", ChangeType: "minor"}},
+		}})
+		require.NoError(t, err)
+		return output
+	}
+
+	assert.Contains(t, render("npm"), "npm install widgets@1.2.0")
+	assert.Contains(t, render("python"), "pip install widgets==1.2.0")
+	assert.Contains(t, render("cargo"), "cargo add widgets@1.2.0")
+	assert.Contains(t, render("dotnet"), "dotnet add package widgets --version 1.2.0")
+	assert.Contains(t, render("go"), "Require `v1.2.0` of the module with `go get`.")
+
+	for _, ecosystem := range []string{"", "helm"} {
+		assert.NotContains(t, render(ecosystem), "## Install", ecosystem)
+	}
+}
+
+// TestRenderReleaseNotes_Audience tests the builtin audience template
+func TestRenderReleaseNotes_Audience(t *testing.T) {
+	entry := history.Entry{
+		Version:   "2.0.0",
+		Package:   "api",
+		Timestamp: time.Date(2026, 1, 30, 10, 0, 0, 0, time.UTC),
+		Consignments: []history.Consignment{
+			{ID: "c1", Summary: "Add dark mode", ChangeType: "minor"},
+			{ID: "c2", Summary: "Bump CI runners", ChangeType: "patch"},
+			{ID: "c3", Summary: "Fix login redirect", ChangeType: "patch", Metadata: map[string]interface{}{"audience": "user"}},
+			{ID: "c4", Summary: "Refactor queue workers", ChangeType: "minor", Metadata: map[string]interface{}{"audience": "Internal"}},
+		},
+		Audiences: map[string]string{"patch": history.AudienceInternal},
+	}
+
+	output, err := RenderReleaseNotesWithTemplate([]history.Entry{entry}, "builtin:audience")
+	require.NoError(t, err)
+
+	user, internal, found := strings.Cut(output, "## Internal Changes")
+	require.True(t, found, output)
+	assert.Contains(t, user, "## What's New")
+	assert.Contains(t, user, "Add dark mode", "minor defaults to user")
+	assert.Contains(t, user, "Fix login redirect", "metadata overrides the change type audience")
+	assert.Contains(t, internal, "Bump CI runners", "patch is configured as internal")
+	assert.Contains(t, internal, "Refactor queue workers", "metadata overrides the default audience")
+	assert.NotContains(t, internal, "Add dark mode")
+
+	t.Run("only user changes", func(t *testing.T) {
+		entry := entry
+		entry.Audiences = nil
+		entry.Consignments = entry.Consignments[:1]
+		output, err := RenderReleaseNotesWithTemplate([]history.Entry{entry}, "builtin:audience")
+		require.NoError(t, err)
+		assert.Contains(t, output, "## What's New")
+		assert.NotContains(t, output, "## Internal Changes")
+	})
+}
@@ -8,6 +8,20 @@ import (
 	"time"
 )
 
+// RenderOptions are a project's settings for rendering templates: how
+// consignment summaries are normalized before templates see them, the
+// repository templates link into and how remote templates are fetched
+type RenderOptions struct {
+	EscapeHTML bool          // Escape HTML angle brackets so raw markup cannot break the output
+	Repository *Repository   // Repository of the links templates render; nil when not known
+	Remote     RemoteOptions // How remote templates are fetched
+}
+
+// DefaultRenderOptions returns the render options used when none are configured
+func DefaultRenderOptions() RenderOptions {
+	return RenderOptions{EscapeHTML: true}
+}
+
 // TemplateRenderer handles rendering templates with context and timeout
 type TemplateRenderer struct {
 	parser  *TemplateParser
@@ -27,6 +41,12 @@ func (r *TemplateRenderer) SetTimeout(timeout time.Duration) {
 	r.timeout = timeout
 }
 
+// SetRepository sets the repository rendered templates link into. nil
+// leaves them unlinked.
+func (r *TemplateRenderer) SetRepository(repo *Repository) {
+	r.parser.SetRepository(repo)
+}
+
 // Render renders a template string with the given context
 func (r *TemplateRenderer) Render(templateContent string, ctx interface{}) (string, error) {
 	// Parse the template
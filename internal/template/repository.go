@@ -0,0 +1,246 @@
+package template
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// Repository host types, as returned by Repository.HostType
+const (
+	HostTypeGitHub    = "github"
+	HostTypeGitLab    = "gitlab"
+	HostTypeBitbucket = "bitbucket"
+	HostTypeOther     = "other"
+)
+
+// Repository is the hosted repository that repoURL, linkIssues, commitURL,
+// compareURL, treeURL and releaseURL build links into
+type Repository struct {
+	Host   string // web host, e.g. github.com, or git.example.com:8443 with a port
+	Path   string // owner/repo, or group/subgroup/repo on GitLab
+	Scheme string // scheme of the web URL: https unless the remote is plain http
+}
+
+// scpRemoteRe matches an scp-like git remote: git@github.com:owner/repo.git
+var scpRemoteRe = regexp.MustCompile(`^(?:[^@/]+@)?([^:/]+):(.+)$`)
+
+// sshOnlyHosts maps hosts that only serve git over SSH to their web host
+var sshOnlyHosts = map[string]string{
+	"ssh.github.com":       "github.com",
+	"altssh.gitlab.com":    "gitlab.com",
+	"altssh.bitbucket.org": "bitbucket.org",
+}
+
+// issueRefRe matches an issue reference such as #123 that is not already part
+// of a link, a heading anchor or an HTML entity
+var issueRefRe = regexp.MustCompile(`(^|[^\w&\[/#])#(\d+)\b`)
+
+// ParseRepositoryURL parses a web or git remote URL, such as
+// https://github.com/owner/repo, git@gitlab.com:group/repo.git or
+// ssh://git@github.com:22/owner/repo.git. The port of a web URL is kept
+// as part of the host; the port of an SSH remote is not, since the web
+// interface is not served there. Web URLs of a repository page, such as
+// https://github.com/owner/repo/tree/main, are trimmed to the repository.
+func ParseRepositoryURL(remoteURL string) (Repository, error) {
+	remoteURL = strings.TrimSpace(remoteURL)
+	repo := Repository{Scheme: "https"}
+	if parsed, err := url.Parse(remoteURL); err == nil && parsed.Scheme != "" && parsed.Host != "" {
+		repo.Path = parsed.Path
+		switch scheme := strings.ToLower(parsed.Scheme); scheme {
+		case "http", "https":
+			repo.Host = parsed.Host
+			if port := parsed.Port(); (scheme == "https" && port == "443") || (scheme == "http" && port == "80") {
+				repo.Host = parsed.Hostname()
+			}
+			repo.Scheme = scheme
+		default:
+			repo.Host = parsed.Hostname()
+		}
+	} else if match := scpRemoteRe.FindStringSubmatch(remoteURL); match != nil {
+		repo.Host, repo.Path = match[1], match[2]
+	} else {
+		return Repository{}, fmt.Errorf("%q is not a repository URL", remoteURL)
+	}
+
+	repo.Host = strings.ToLower(repo.Host)
+	if web, ok := sshOnlyHosts[repo.Host]; ok {
+		repo.Host = web
+	}
+	repo.Path = strings.TrimSuffix(strings.Trim(repo.Path, "/"), ".git")
+	switch repo.HostType() {
+	case HostTypeGitLab:
+		repo.Path, _, _ = strings.Cut(repo.Path, "/-/")
+	case HostTypeGitHub, HostTypeBitbucket:
+		if segments := strings.Split(repo.Path, "/"); len(segments) > 2 {
+			repo.Path = strings.Join(segments[:2], "/")
+		}
+	}
+	if repo.Host == "" || !strings.Contains(repo.Path, "/") {
+		return Repository{}, fmt.Errorf("%q is not a repository URL", remoteURL)
+	}
+	return repo, nil
+}
+
+// HostType returns the kind of service hosting the repository. GitHub and
+// GitLab hosts are recognized by name, so self-hosted instances such as
+// gitlab.example.com count too; bitbucket is only bitbucket.org, since
+// self-hosted Bitbucket lays its pages out differently.
+func (r Repository) HostType() string {
+	hostname := r.Host
+	if host, _, err := net.SplitHostPort(hostname); err == nil {
+		hostname = host
+	}
+	if hostname == "bitbucket.org" {
+		return HostTypeBitbucket
+	}
+	for _, label := range strings.Split(hostname, ".") {
+		switch label {
+		case HostTypeGitHub:
+			return HostTypeGitHub
+		case HostTypeGitLab:
+			return HostTypeGitLab
+		}
+	}
+	return HostTypeOther
+}
+
+// URL returns the repository's web URL
+func (r Repository) URL() string {
+	scheme := r.Scheme
+	if scheme == "" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + "/" + r.Path
+}
+
+// pagePrefix returns the URL prefix of the repository's pages: GitLab puts
+// them under /-/. Hosts of other types are not linked.
+func (r Repository) pagePrefix() (string, bool) {
+	switch r.HostType() {
+	case HostTypeGitHub, HostTypeBitbucket:
+		return r.URL() + "/", true
+	case HostTypeGitLab:
+		return r.URL() + "/-/", true
+	}
+	return "", false
+}
+
+// repositoryFunctions returns the template functions that link into repo.
+// A nil repo is not known: the functions return "" and leave text unlinked.
+func repositoryFunctions(repo *Repository) template.FuncMap {
+	return template.FuncMap{
+		"repoHost":     repo.host,
+		"repoHostType": repo.hostType,
+		"repoURL":      repo.webURL,
+		"commitURL":    repo.commitURL,
+		"compareURL":   repo.compareURL,
+		"treeURL":      repo.treeURL,
+		"releaseURL":   repo.releaseURL,
+		"linkIssues":   repo.linkIssues,
+	}
+}
+
+// host returns the repository's web host, or "" when it is not known
+func (r *Repository) host() string {
+	if r == nil {
+		return ""
+	}
+	return r.Host
+}
+
+// hostType returns the repository's host type (github, gitlab, bitbucket
+// or other), or "" when it is not known
+func (r *Repository) hostType() string {
+	if r == nil {
+		return ""
+	}
+	return r.HostType()
+}
+
+// webURL returns the repository's web URL, or "" when it is not known
+func (r *Repository) webURL() string {
+	if r == nil {
+		return ""
+	}
+	return r.URL()
+}
+
+// links returns the URL prefix of the repository's pages, and false when
+// the repository is not known or its host is not linked
+func (r *Repository) links() (string, bool) {
+	if r == nil {
+		return "", false
+	}
+	return r.pagePrefix()
+}
+
+// commitURL returns the web URL of a commit, or "" when the repository is
+// not known
+func (r *Repository) commitURL(sha string) string {
+	prefix, ok := r.links()
+	if !ok || sha == "" {
+		return ""
+	}
+	if r.HostType() == HostTypeBitbucket {
+		return prefix + "commits/" + sha
+	}
+	return prefix + "commit/" + sha
+}
+
+// compareURL returns the web URL comparing two refs, or "" when the
+// repository is not known
+func (r *Repository) compareURL(from, to string) string {
+	prefix, ok := r.links()
+	if !ok || from == "" || to == "" {
+		return ""
+	}
+	if r.HostType() == HostTypeBitbucket {
+		// Bitbucket compares the source ref against the destination ref
+		return prefix + "branches/compare/" + to + "%0D" + from
+	}
+	return prefix + "compare/" + from + "..." + to
+}
+
+// treeURL returns the web URL browsing the repository at a ref, or "" when
+// the repository is not known
+func (r *Repository) treeURL(ref string) string {
+	prefix, ok := r.links()
+	if !ok || ref == "" {
+		return ""
+	}
+	if r.HostType() == HostTypeBitbucket {
+		return prefix + "src/" + ref
+	}
+	return prefix + "tree/" + ref
+}
+
+// releaseURL returns the web URL of the release for a tag, or "" when the
+// repository is not known
+func (r *Repository) releaseURL(tag string) string {
+	prefix, ok := r.links()
+	if !ok || tag == "" {
+		return ""
+	}
+	switch r.HostType() {
+	case HostTypeGitHub:
+		return prefix + "releases/tag/" + tag
+	case HostTypeGitLab:
+		return prefix + "releases/" + tag
+	}
+	return "" // Bitbucket has no releases
+}
+
+// linkIssues turns issue references such as #123 into markdown links to the
+// repository's issues. Text is returned unchanged when the repository is not
+// known.
+func (r *Repository) linkIssues(text string) string {
+	prefix, ok := r.links()
+	if !ok {
+		return text
+	}
+	return issueRefRe.ReplaceAllString(text, "${1}[#${2}]("+prefix+"issues/${2})")
+}
@@ -0,0 +1,114 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/NatoNathan/shipyard/internal/history"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRepositoryURL(t *testing.T) {
+	tests := []struct {
+		remote   string
+		url      string
+		hostType string
+	}{
+		{"git@github.com:acme/web.git", "https://github.com/acme/web", HostTypeGitHub},
+		{"git@github.com:/acme/web.git/", "https://github.com/acme/web", HostTypeGitHub},
+		{"ssh://git@github.com/acme/web.git", "https://github.com/acme/web", HostTypeGitHub},
+		{"ssh://git@ssh.github.com:443/acme/web.git", "https://github.com/acme/web", HostTypeGitHub},
+		{"git+ssh://git@github.com/acme/web", "https://github.com/acme/web", HostTypeGitHub},
+		{"https://github.com/acme/web/tree/main", "https://github.com/acme/web", HostTypeGitHub},
+		{"https://token@GitHub.com:443/acme/web.git", "https://github.com/acme/web", HostTypeGitHub},
+		{"https://github.example.com:8443/acme/web.git", "https://github.example.com:8443/acme/web", HostTypeGitHub},
+		{"git@gitlab.com:group/sub/web.git", "https://gitlab.com/group/sub/web", HostTypeGitLab},
+		{"https://gitlab.com/group/sub/deeper/web/-/tree/main", "https://gitlab.com/group/sub/deeper/web", HostTypeGitLab},
+		{"ssh://git@gitlab.example.com:2222/platform/tools/web.git", "https://gitlab.example.com/platform/tools/web", HostTypeGitLab},
+		{"http://gitlab.internal:8080/group/web", "http://gitlab.internal:8080/group/web", HostTypeGitLab},
+		{"git@bitbucket.org:acme/web.git", "https://bitbucket.org/acme/web", HostTypeBitbucket},
+		{"https://bitbucket.org/acme/web/src/main/", "https://bitbucket.org/acme/web", HostTypeBitbucket},
+		{"ssh://git@git.example.com:7999/scm/proj/web.git", "https://git.example.com/scm/proj/web", HostTypeOther},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.remote, func(t *testing.T) {
+			repo, err := ParseRepositoryURL(tt.remote)
+			require.NoError(t, err)
+			assert.Equal(t, tt.url, repo.URL())
+			assert.Equal(t, tt.hostType, repo.HostType())
+		})
+	}
+
+	for _, remote := range []string{"", "web", "/srv/git/web.git", "https://github.com/acme"} {
+		_, err := ParseRepositoryURL(remote)
+		assert.Error(t, err, remote)
+	}
+}
+
+// testRepository parses remote, returning nil when it is not a repository URL
+func testRepository(remote string) *Repository {
+	repo, err := ParseRepositoryURL(remote)
+	if err != nil {
+		return nil
+	}
+	return &repo
+}
+
+// linkOptions returns the default summary options linking into remote
+func linkOptions(remote string) RenderOptions {
+	opts := DefaultRenderOptions()
+	opts.Repository = testRepository(remote)
+	return opts
+}
+
+func TestRepositoryHostTemplateFunctions(t *testing.T) {
+	tmpl := `{{ repoHost }}|{{ repoHostType }}|{{ commitURL "abc123" }}|{{ compareURL "v1.0.0" "v1.1.0" }}|{{ treeURL "v1.1.0" }}|{{ releaseURL "v1.1.0" }}`
+
+	tests := map[string]string{
+		"git@bitbucket.org:acme/web.git":                    "bitbucket.org|bitbucket|https://bitbucket.org/acme/web/commits/abc123|https://bitbucket.org/acme/web/branches/compare/v1.1.0%0Dv1.0.0|https://bitbucket.org/acme/web/src/v1.1.0|",
+		"https://gitlab.example.com:8443/group/sub/web.git": "gitlab.example.com:8443|gitlab|https://gitlab.example.com:8443/group/sub/web/-/commit/abc123|https://gitlab.example.com:8443/group/sub/web/-/compare/v1.0.0...v1.1.0|https://gitlab.example.com:8443/group/sub/web/-/tree/v1.1.0|https://gitlab.example.com:8443/group/sub/web/-/releases/v1.1.0",
+		"ssh://git@git.example.com/acme/web.git":            "git.example.com|other||||",
+		"":                                                  "|||||",
+	}
+	for remote, expected := range tests {
+		t.Run(remote, func(t *testing.T) {
+			renderer := NewTemplateRenderer()
+			renderer.SetRepository(testRepository(remote))
+			output, err := renderer.Render(tmpl, nil)
+			require.NoError(t, err)
+			assert.Equal(t, expected, output)
+		})
+	}
+}
+
+func TestChangelogContext_RepoHost(t *testing.T) {
+	entries := []history.Entry{{Package: "core", Version: "1.0.0"}}
+	tmpl := "{{ .RepoHost }}|{{ .RepoHostType }}\n"
+
+	output, err := RenderChangelogWithOptions(entries, tmpl, linkOptions("ssh://git@gitlab.example.com:2222/platform/core.git"))
+	require.NoError(t, err)
+	assert.Equal(t, "gitlab.example.com|gitlab\n", output)
+
+	output, err = RenderChangelogWithTemplate(entries, tmpl)
+	require.NoError(t, err)
+	assert.Equal(t, "|\n", output)
+}
+
+func TestRenderersLinkIntoTheirOwnRepository(t *testing.T) {
+	github := NewTemplateRenderer()
+	github.SetRepository(testRepository("git@github.com:acme/web.git"))
+	gitlab := NewTemplateRenderer()
+	gitlab.SetRepository(testRepository("https://gitlab.com/acme/api.git"))
+	unlinked := NewTemplateRenderer()
+
+	for renderer, expected := range map[*TemplateRenderer]string{
+		github:   "https://github.com/acme/web/commit/abc123",
+		gitlab:   "https://gitlab.com/acme/api/-/commit/abc123",
+		unlinked: "",
+	} {
+		output, err := renderer.Render(`{{ commitURL "abc123" }}`, nil)
+		require.NoError(t, err)
+		assert.Equal(t, expected, output)
+	}
+}
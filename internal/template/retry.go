@@ -0,0 +1,90 @@
+package template
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/logger"
+)
+
+const (
+	// FetchRetriesEnv sets how many times a failed remote fetch is retried
+	FetchRetriesEnv = "SHIPYARD_FETCH_RETRIES"
+
+	// FetchTimeoutEnv sets the timeout of each remote fetch attempt, as a
+	// Go duration such as "45s"
+	FetchTimeoutEnv = "SHIPYARD_FETCH_TIMEOUT"
+
+	// DefaultFetchRetries is how many times a failed remote fetch is retried
+	// unless configured otherwise
+	DefaultFetchRetries = 3
+)
+
+// defaultRetryBackoff is the delay before the first retry; tests shorten it
+var defaultRetryBackoff = 500 * time.Millisecond
+
+const maxRetryBackoff = 10 * time.Second
+
+// fetchSettingsFromEnv returns the retry count and per-attempt timeout set
+// by FetchRetriesEnv and FetchTimeoutEnv, or the given defaults. Invalid
+// values are ignored with a warning rather than failing every fetch.
+func fetchSettingsFromEnv(retries int, timeout time.Duration) (int, time.Duration) {
+	log := logger.Get()
+	if value := os.Getenv(FetchRetriesEnv); value != "" {
+		if n, err := strconv.Atoi(value); err == nil && n >= 0 {
+			retries = n
+		} else {
+			log.Warn("Ignoring %s=%q: expected a non-negative number", FetchRetriesEnv, value)
+		}
+	}
+	if value := os.Getenv(FetchTimeoutEnv); value != "" {
+		if d, err := time.ParseDuration(value); err == nil && d > 0 {
+			timeout = d
+		} else {
+			log.Warn("Ignoring %s=%q: expected a positive duration such as 30s", FetchTimeoutEnv, value)
+		}
+	}
+	return retries, timeout
+}
+
+// retryDelay returns the backoff before the given retry, counting from 1:
+// the base delay doubled for each earlier retry, capped at maxRetryBackoff,
+// with random jitter over its upper half so concurrent runs spread out
+func retryDelay(base time.Duration, retry int) time.Duration {
+	delay := base
+	for i := 1; i < retry && delay < maxRetryBackoff; i++ {
+		delay *= 2
+	}
+	delay = min(delay, maxRetryBackoff)
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+// fetchWithRetries calls fetch until it succeeds, fails for a reason other
+// than the source being unavailable, or runs out of retries. Only
+// unavailable sources are retried: a 404 or a bad path fails the same way
+// every time.
+func (l *TemplateLoader) fetchWithRetries(display string, fetch func() (fetchedTemplate, error)) (fetchedTemplate, error) {
+	for attempt := 1; ; attempt++ {
+		fetched, err := fetch()
+		var unavailable *errTemplateUnavailable
+		if err == nil || !errors.As(err, &unavailable) {
+			return fetched, err
+		}
+		if attempt > l.retries {
+			if attempt > 1 {
+				err = &errTemplateUnavailable{fmt.Errorf("%w (after %d attempts)", err, attempt)}
+			}
+			return fetched, err
+		}
+
+		delay := retryDelay(l.retryBackoff, attempt)
+		logger.Get().Debug("Fetching %s failed (attempt %d of %d), retrying in %s: %v",
+			display, attempt, l.retries+1, delay.Round(time.Millisecond), err)
+		l.sleep(delay)
+	}
+}
@@ -0,0 +1,173 @@
+package template
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyServer fails the first failures requests with 503, then serves body.
+// hits counts every request.
+func flakyServer(t *testing.T, body string, failures int32, hits *atomic.Int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = fmt.Fprint(w, body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newRetryLoader returns a loader with its own cache that records the
+// backoff delays instead of sleeping
+func newRetryLoader(t *testing.T, retries int) (*TemplateLoader, *[]time.Duration) {
+	t.Helper()
+	var delays []time.Duration
+	loader := NewTemplateLoader()
+	loader.SetCacheDir(t.TempDir())
+	loader.SetRetries(retries)
+	loader.SetRetryBackoff(100 * time.Millisecond)
+	loader.sleep = func(d time.Duration) { delays = append(delays, d) }
+	return loader, &delays
+}
+
+func TestLoadTemplate_Retries(t *testing.T) {
+	t.Run("transient failures are retried with backoff", func(t *testing.T) {
+		var hits atomic.Int32
+		server := flakyServer(t, "remote", 2, &hits)
+		loader, delays := newRetryLoader(t, 3)
+
+		content, err := loader.Load(server.URL + "/changelog.tmpl")
+
+		require.NoError(t, err)
+		assert.Equal(t, "remote", content)
+		assert.Equal(t, int32(3), hits.Load())
+		require.Len(t, *delays, 2)
+		assert.GreaterOrEqual(t, (*delays)[1], 100*time.Millisecond, "second delay is doubled")
+	})
+
+	t.Run("gives up after the configured retries", func(t *testing.T) {
+		var hits atomic.Int32
+		server := flakyServer(t, "remote", 100, &hits)
+		loader, _ := newRetryLoader(t, 2)
+
+		_, err := loader.Load(server.URL + "/changelog.tmpl")
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "HTTP 503 (after 3 attempts)")
+		assert.Equal(t, int32(3), hits.Load())
+	})
+
+	t.Run("zero retries fetches once", func(t *testing.T) {
+		var hits atomic.Int32
+		server := flakyServer(t, "remote", 1, &hits)
+		loader, _ := newRetryLoader(t, 0)
+
+		_, err := loader.Load(server.URL + "/changelog.tmpl")
+
+		require.Error(t, err)
+		assert.NotContains(t, err.Error(), "attempts")
+		assert.Equal(t, int32(1), hits.Load())
+	})
+
+	t.Run("client errors are not retried", func(t *testing.T) {
+		var status, hits atomic.Int32
+		status.Store(http.StatusNotFound)
+		server := countingServer(t, "remote", &status, &hits)
+		loader, delays := newRetryLoader(t, 3)
+
+		_, err := loader.Load(server.URL + "/changelog.tmpl")
+
+		require.Error(t, err)
+		assert.Equal(t, int32(1), hits.Load())
+		assert.Empty(t, *delays)
+	})
+
+	t.Run("each attempt has its own timeout", func(t *testing.T) {
+		var hits atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if hits.Add(1) == 1 {
+				<-r.Context().Done() // the first attempt hangs until it times out
+				return
+			}
+			_, _ = fmt.Fprint(w, "remote")
+		}))
+		t.Cleanup(server.Close)
+		loader, _ := newRetryLoader(t, 1)
+		loader.SetTimeout(200 * time.Millisecond)
+
+		content, err := loader.Load(server.URL + "/changelog.tmpl")
+
+		require.NoError(t, err)
+		assert.Equal(t, "remote", content)
+		assert.Equal(t, int32(2), hits.Load())
+	})
+
+	t.Run("git clones are retried and fall back to a stale copy", func(t *testing.T) {
+		source := "git:" + filepath.Join(t.TempDir(), "missing") + "#templates/changelog.tmpl@main"
+		loader, delays := newRetryLoader(t, 2)
+		cache := templateCache{dir: loader.diskCache.dir, ttl: time.Hour}
+		cache.put(source, "stale")
+		past := time.Now().Add(-48 * time.Hour)
+		require.NoError(t, os.Chtimes(cache.path(source), past, past))
+		var warnings bytes.Buffer
+		loader.SetCacheTTL(time.Hour)
+		loader.SetWarningWriter(&warnings)
+
+		content, err := loader.Load(source)
+
+		require.NoError(t, err)
+		assert.Equal(t, "stale", content)
+		assert.Len(t, *delays, 2)
+		assert.Contains(t, warnings.String(), "after 3 attempts")
+		assert.Contains(t, warnings.String(), "using STALE cached copy of "+source)
+	})
+}
+
+func TestRetryDelay(t *testing.T) {
+	for retry, ceiling := range map[int]time.Duration{1: 500 * time.Millisecond, 2: time.Second, 3: 2 * time.Second, 40: maxRetryBackoff} {
+		for i := 0; i < 20; i++ {
+			delay := retryDelay(500*time.Millisecond, retry)
+			assert.GreaterOrEqual(t, delay, ceiling/2, "retry %d", retry)
+			assert.LessOrEqual(t, delay, ceiling, "retry %d", retry)
+		}
+	}
+}
+
+func TestNewTemplateLoader_FetchSettingsFromEnv(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		t.Setenv(FetchRetriesEnv, "")
+		t.Setenv(FetchTimeoutEnv, "")
+		loader := NewTemplateLoader()
+		assert.Equal(t, DefaultFetchRetries, loader.retries)
+		assert.Equal(t, defaultTemplateTimeout, loader.timeout)
+	})
+
+	t.Run("from environment", func(t *testing.T) {
+		t.Setenv(FetchRetriesEnv, "5")
+		t.Setenv(FetchTimeoutEnv, "45s")
+		loader := NewTemplateLoader()
+		assert.Equal(t, 5, loader.retries)
+		assert.Equal(t, 45*time.Second, loader.timeout)
+	})
+
+	t.Run("invalid values are ignored", func(t *testing.T) {
+		t.Setenv(FetchRetriesEnv, "-1")
+		t.Setenv(FetchTimeoutEnv, "soon")
+		loader := NewTemplateLoader()
+		assert.Equal(t, DefaultFetchRetries, loader.retries)
+		assert.Equal(t, defaultTemplateTimeout, loader.timeout)
+	})
+}
@@ -0,0 +1,64 @@
+package template
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/NatoNathan/shipyard/internal/history"
+)
+
+var (
+	headingMarker   = regexp.MustCompile(`^#{1,6}(\s+|$)`)
+	htmlEscaper     = strings.NewReplacer("<", "&lt;", ">", "&gt;")
+	newlineReplacer = strings.NewReplacer("\r\n", "\n", "\r", "\n")
+)
+
+// NormalizeSummary converts a raw consignment summary into a single-line form
+// that is safe to embed in bullets, commit messages and tag annotations.
+// CRLF line endings are normalized, leading markdown heading markers are
+// stripped from every line, internal newlines are collapsed into single
+// spaces, and HTML is escaped unless disabled via opts.
+func NormalizeSummary(raw string, opts RenderOptions) string {
+	var parts []string
+	for _, line := range strings.Split(NormalizeNewlines(raw), "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimSpace(headingMarker.ReplaceAllString(line, ""))
+		if line != "" {
+			parts = append(parts, line)
+		}
+	}
+
+	summary := strings.Join(parts, " ")
+	if opts.EscapeHTML {
+		summary = htmlEscaper.Replace(summary)
+	}
+	return summary
+}
+
+// NormalizeNewlines converts CRLF and lone CR line endings to LF
+func NormalizeNewlines(s string) string {
+	return newlineReplacer.Replace(s)
+}
+
+// normalizeEntries returns a copy of entries with every consignment summary
+// normalized. The original value is preserved in RawSummary.
+func normalizeEntries(entries []history.Entry, opts RenderOptions) []history.Entry {
+	normalized := make([]history.Entry, len(entries))
+	for i, entry := range entries {
+		normalized[i] = entry
+		if entry.Consignments == nil {
+			continue
+		}
+		normalized[i].Consignments = make([]history.Consignment, len(entry.Consignments))
+		for j, c := range entry.Consignments {
+			raw := c.Summary
+			if c.RawSummary != "" {
+				raw = c.RawSummary
+			}
+			c.RawSummary = raw
+			c.Summary = NormalizeSummary(raw, opts)
+			normalized[i].Consignments[j] = c
+		}
+	}
+	return normalized
+}
@@ -0,0 +1,96 @@
+package template
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/history"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeSummary(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		opts     RenderOptions
+		expected string
+	}{
+		{"trims whitespace", "  Fix bug \t\n", DefaultRenderOptions(), "Fix bug"},
+		{"strips heading marker", "# Add feature", DefaultRenderOptions(), "Add feature"},
+		{"strips deep heading marker", "###### Add feature", DefaultRenderOptions(), "Add feature"},
+		{"keeps issue references", "#123 fixed", DefaultRenderOptions(), "#123 fixed"},
+		{"collapses CRLF lines", "Title\r\n\r\nBody line\r\nmore", DefaultRenderOptions(), "Title Body line more"},
+		{"collapses lone CR", "one\rtwo", DefaultRenderOptions(), "one two"},
+		{"strips headings on every line", "# Title\n\n## Details\nText", DefaultRenderOptions(), "Title Details Text"},
+		{"escapes html by default", "Fix <script>alert(1)</script>", DefaultRenderOptions(), "Fix &lt;script&gt;alert(1)&lt;/script&gt;"},
+		{"html allowed when disabled", "Use <kbd>Ctrl</kbd>", RenderOptions{EscapeHTML: false}, "Use <kbd>Ctrl</kbd>"},
+		{"empty input", "", DefaultRenderOptions(), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NormalizeSummary(tt.raw, tt.opts))
+		})
+	}
+}
+
+func TestRenderChangelog_AdversarialSummaries(t *testing.T) {
+	raw := "# Injected heading\r\n\r\n<div>html</div>\r\n## Another"
+	entries := []history.Entry{
+		{
+			Version:   "1.1.0",
+			Package:   "core",
+			Timestamp: time.Date(2026, 1, 30, 10, 0, 0, 0, time.UTC),
+			Consignments: []history.Consignment{
+				{ID: "c1", Summary: raw, ChangeType: "minor"},
+				{ID: "c2", Summary: "  Trailing whitespace  \r\n", ChangeType: "patch"},
+			},
+		},
+	}
+
+	output, err := RenderChangelogWithTemplate(entries, "builtin:default")
+	require.NoError(t, err)
+
+	assert.NotContains(t, output, "\r")
+	assert.NotContains(t, output, "<div>")
+	assert.Contains(t, output, "- Injected heading &lt;div&gt;html&lt;/div&gt; Another\n")
+	assert.Contains(t, output, "- Trailing whitespace")
+
+	// Only the template's own headings may appear
+	allowed := []string{"# Changelog", "## [1.1.0]", "### Features", "### Bug Fixes"}
+	for _, line := range strings.Split(output, "\n") {
+		if !strings.HasPrefix(line, "#") {
+			continue
+		}
+		matched := false
+		for _, prefix := range allowed {
+			if strings.HasPrefix(line, prefix) {
+				matched = true
+				break
+			}
+		}
+		assert.True(t, matched, "unexpected heading in output: %q", line)
+	}
+
+	// Input entries must not be mutated
+	assert.Equal(t, raw, entries[0].Consignments[0].Summary)
+}
+
+func TestRenderReleaseNotes_RawSummaryAvailable(t *testing.T) {
+	entries := []history.Entry{
+		{
+			Version:   "1.0.0",
+			Package:   "core",
+			Timestamp: time.Date(2026, 1, 30, 10, 0, 0, 0, time.UTC),
+			Consignments: []history.Consignment{
+				{ID: "c1", Summary: "# Title\n\nBody", ChangeType: "patch"},
+			},
+		},
+	}
+
+	output, err := RenderReleaseNotesWithTemplate(entries, "{{ range .Consignments }}{{ .Summary }}|{{ .RawSummary }}{{ end }}\n")
+	require.NoError(t, err)
+	assert.Equal(t, "Title Body|# Title\n\nBody\n", output)
+}
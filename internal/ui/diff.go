@@ -0,0 +1,58 @@
+package ui
+
+import "strings"
+
+// Diff returns a line-based diff of two texts. Unchanged lines are prefixed
+// with two spaces, removed lines with "- " and added lines with "+ ".
+func Diff(oldText, newText string) string {
+	oldLines := splitLines(oldText)
+	newLines := splitLines(newText)
+
+	// Longest common subsequence table
+	lcs := make([][]int, len(oldLines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(newLines)+1)
+	}
+	for i := len(oldLines) - 1; i >= 0; i-- {
+		for j := len(newLines) - 1; j >= 0; j-- {
+			if oldLines[i] == newLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out []string
+	i, j := 0, 0
+	for i < len(oldLines) && j < len(newLines) {
+		switch {
+		case oldLines[i] == newLines[j]:
+			out = append(out, "  "+oldLines[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, oldVersionStyle.Render("- "+oldLines[i]))
+			i++
+		default:
+			out = append(out, newVersionStyle.Render("+ "+newLines[j]))
+			j++
+		}
+	}
+	for ; i < len(oldLines); i++ {
+		out = append(out, oldVersionStyle.Render("- "+oldLines[i]))
+	}
+	for ; j < len(newLines); j++ {
+		out = append(out, newVersionStyle.Render("+ "+newLines[j]))
+	}
+
+	return strings.Join(out, "\n")
+}
+
+func splitLines(text string) []string {
+	text = strings.TrimSuffix(text, "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
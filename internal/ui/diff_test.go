@@ -0,0 +1,25 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	t.Run("identical texts have no changes", func(t *testing.T) {
+		assert.Equal(t, "  a\n  b", Diff("a\nb\n", "a\nb\n"))
+	})
+
+	t.Run("changed line", func(t *testing.T) {
+		assert.Equal(t, "  a\n- b\n+ c\n  d", Diff("a\nb\nd", "a\nc\nd"))
+	})
+
+	t.Run("added and removed lines", func(t *testing.T) {
+		assert.Equal(t, "- a\n  b\n+ c", Diff("a\nb", "b\nc"))
+	})
+
+	t.Run("empty old text", func(t *testing.T) {
+		assert.Equal(t, "+ a", Diff("", "a"))
+	})
+}
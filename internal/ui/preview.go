@@ -15,6 +15,7 @@ type PackageChange struct {
 	NewVersion semver.Version
 	ChangeType string
 	Changes    []string
+	Because    []string // Packages whose changes propagated to this one
 }
 
 var (
@@ -57,6 +58,37 @@ func RenderPreview(changes []PackageChange) string {
 	return strings.Join(sections, "\n\n")
 }
 
+// RenderPlainPreview renders a preview of version changes as plain text with
+// no colors or symbols, for screen readers and accessible mode
+func RenderPlainPreview(changes []PackageChange) string {
+	if len(changes) == 0 {
+		return "No changes to preview"
+	}
+
+	lines := []string{"Version preview:"}
+	for _, change := range changes {
+		line := fmt.Sprintf("%s: %s to %s (%s)",
+			change.Name, change.OldVersion.String(), change.NewVersion.String(), change.ChangeType)
+		if len(change.Because) > 0 {
+			line += " because of " + strings.Join(change.Because, ", ")
+		}
+		lines = append(lines, line)
+		for _, item := range change.Changes {
+			// Multi-line summaries continue on indented lines
+			prefix := "  - "
+			for _, line := range strings.Split(item, "\n") {
+				if line = strings.TrimSpace(line); line == "" {
+					continue
+				}
+				lines = append(lines, prefix+line)
+				prefix = "    "
+			}
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
 // renderPackageChange renders a single package change
 func renderPackageChange(change PackageChange) string {
 	var lines []string
@@ -66,7 +98,11 @@ func renderPackageChange(change PackageChange) string {
 	versionDiff := RenderVersionDiff(change.OldVersion, change.NewVersion)
 	changeType := changeTypeStyle.Render(fmt.Sprintf("(%s)", change.ChangeType))
 
-	lines = append(lines, fmt.Sprintf("%s: %s %s", pkgName, versionDiff, changeType))
+	header := fmt.Sprintf("%s: %s %s", pkgName, versionDiff, changeType)
+	if len(change.Because) > 0 {
+		header += " " + Dimmed("because of "+strings.Join(change.Because, ", "))
+	}
+	lines = append(lines, header)
 
 	// Changes list
 	if len(change.Changes) > 0 {
@@ -87,3 +123,71 @@ func RenderVersionDiff(oldVer, newVer semver.Version) string {
 
 	return fmt.Sprintf("%s %s %s", old, arrow, new)
 }
+
+// TagPreview is a tag a release would create, or the error rendering its
+// tag template
+type TagPreview struct {
+	Package string
+	Name    string
+	Err     error
+}
+
+// GitPreview lists the git operations a release would run
+type GitPreview struct {
+	Tags          []TagPreview
+	CommitMessage string
+	CommitErr     error
+	NoCommit      bool // The release commit is skipped
+	NoTag         bool // Tags are skipped
+}
+
+// RenderGitPreview renders the git operations a release would run, marked as
+// not executed
+func RenderGitPreview(preview GitPreview) string {
+	sections := []string{Section("Git operations that would run (not executed)")}
+	for _, line := range gitPreviewLines(preview) {
+		sections = append(sections, strings.TrimRight("  "+line, " "))
+	}
+	return strings.Join(sections, "\n")
+}
+
+// RenderPlainGitPreview renders the git operations a release would run as
+// plain text, for screen readers and accessible mode
+func RenderPlainGitPreview(preview GitPreview) string {
+	lines := []string{"Git operations that would run (not executed):"}
+	for _, line := range gitPreviewLines(preview) {
+		lines = append(lines, strings.TrimRight("  "+line, " "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// gitPreviewLines lists the commit and then the tags, one operation per line
+// with the commit body indented beneath it
+func gitPreviewLines(preview GitPreview) []string {
+	var lines []string
+	switch {
+	case preview.NoCommit:
+		lines = append(lines, "commit: skipped (--no-commit)")
+	case preview.CommitErr != nil:
+		lines = append(lines, fmt.Sprintf("commit: error: %v", preview.CommitErr))
+	default:
+		message := strings.Split(strings.TrimRight(preview.CommitMessage, "\n"), "\n")
+		lines = append(lines, "commit: "+message[0])
+		for _, line := range message[1:] {
+			lines = append(lines, "  "+line)
+		}
+	}
+
+	// Tags are only created on the release commit
+	if preview.NoTag || preview.NoCommit {
+		return append(lines, "tags: skipped")
+	}
+	for _, tag := range preview.Tags {
+		if tag.Err != nil {
+			lines = append(lines, fmt.Sprintf("tag %s: error: %v", tag.Package, tag.Err))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("tag %s: %s", tag.Package, tag.Name))
+	}
+	return lines
+}
@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/NatoNathan/shipyard/pkg/semver"
@@ -58,6 +59,60 @@ func TestRenderPreviewEmpty(t *testing.T) {
 	assert.Contains(t, output, "No changes", "Should indicate no changes")
 }
 
+// TestRenderPlainPreview tests the accessible plain-text preview
+func TestRenderPlainPreview(t *testing.T) {
+	changes := []PackageChange{
+		{
+			Name:       "core",
+			OldVersion: semver.MustParse("1.0.0"),
+			NewVersion: semver.MustParse("1.1.0"),
+			ChangeType: "minor",
+			Changes:    []string{"Add new feature\n\nWith details", "Fix bug"},
+		},
+		{
+			Name:       "api",
+			OldVersion: semver.MustParse("2.0.0"),
+			NewVersion: semver.MustParse("2.0.1"),
+			ChangeType: "patch",
+			Because:    []string{"core"},
+		},
+	}
+
+	assert.Equal(t, `Version preview:
+core: 1.0.0 to 1.1.0 (minor)
+  - Add new feature
+    With details
+  - Fix bug
+api: 2.0.0 to 2.0.1 (patch) because of core`, RenderPlainPreview(changes))
+	assert.Equal(t, "No changes to preview", RenderPlainPreview(nil))
+}
+
+// TestRenderPlainGitPreview tests the git operations section of the preview
+func TestRenderPlainGitPreview(t *testing.T) {
+	preview := GitPreview{
+		CommitMessage: "chore: release\n\n- core 1.1.0\n",
+		Tags: []TagPreview{
+			{Package: "core", Name: "core/v1.1.0"},
+			{Package: "api", Err: errors.New("template: unexpected EOF")},
+		},
+	}
+
+	assert.Equal(t, `Git operations that would run (not executed):
+  commit: chore: release
+
+    - core 1.1.0
+  tag core: core/v1.1.0
+  tag api: error: template: unexpected EOF`, RenderPlainGitPreview(preview))
+
+	assert.Equal(t, `Git operations that would run (not executed):
+  commit: skipped (--no-commit)
+  tags: skipped`, RenderPlainGitPreview(GitPreview{NoCommit: true, Tags: preview.Tags}))
+
+	output := RenderGitPreview(preview)
+	assert.Contains(t, output, "not executed")
+	assert.Contains(t, output, "tag core: core/v1.1.0")
+}
+
 // TestRenderVersionDiff tests rendering version diff
 func TestRenderVersionDiff(t *testing.T) {
 	oldVer := semver.MustParse("1.2.3")
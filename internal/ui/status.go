@@ -0,0 +1,32 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+)
+
+// Status prints user-facing progress lines such as "Detected 3 package(s)".
+// They belong to a command's human output rather than its log, so they are
+// printed once here instead of also being logged.
+type Status struct {
+	w io.Writer
+}
+
+// NewStatus returns a Status printing to w. A nil w discards every line, for
+// --json and --quiet runs whose stdout must hold nothing else.
+func NewStatus(w io.Writer) *Status {
+	if w == nil {
+		w = io.Discard
+	}
+	return &Status{w: w}
+}
+
+// Info prints an informational status line
+func (s *Status) Info(format string, args ...interface{}) {
+	_, _ = fmt.Fprintln(s.w, InfoMessage(fmt.Sprintf(format, args...)))
+}
+
+// Warn prints a warning status line
+func (s *Status) Warn(format string, args ...interface{}) {
+	_, _ = fmt.Fprintln(s.w, WarningMessage(fmt.Sprintf(format, args...)))
+}
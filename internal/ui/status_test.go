@@ -0,0 +1,25 @@
+package ui
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatus(t *testing.T) {
+	var buf bytes.Buffer
+	status := NewStatus(&buf)
+
+	status.Info("Detected %d package(s)", 3)
+	status.Warn("No packages detected")
+
+	assert.Contains(t, buf.String(), "ℹ Detected 3 package(s)\n")
+	assert.Contains(t, buf.String(), "⚠ No packages detected\n")
+}
+
+func TestStatus_NilWriterDiscards(t *testing.T) {
+	assert.NotPanics(t, func() {
+		NewStatus(nil).Info("discarded")
+	})
+}
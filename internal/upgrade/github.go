@@ -1,14 +1,19 @@
 package upgrade
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	neturl "net/url"
 	"os"
 	"strings"
 	"time"
 
+	"github.com/NatoNathan/shipyard/internal/httpclient"
 	"github.com/NatoNathan/shipyard/pkg/semver"
 )
 
@@ -29,17 +34,37 @@ func NewGitHubClient() *GitHubClient {
 	return &GitHubClient{
 		baseURL: defaultBaseURL,
 		httpClient: &http.Client{
-			Timeout: defaultTimeout,
+			Timeout:   defaultTimeout,
+			Transport: httpclient.Transport(),
 		},
 		authToken: os.Getenv("GITHUB_TOKEN"),
 	}
 }
 
+// NewGitHubClientFor creates a GitHub API client that authenticates with
+// token. An empty baseURL uses api.github.com; tests pass a fake server.
+func NewGitHubClientFor(baseURL, token string) *GitHubClient {
+	client := NewGitHubClient()
+	if baseURL != "" {
+		client.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+	client.authToken = token
+	return client
+}
+
+// SetTransport sets the transport the client's requests go through
+func (c *GitHubClient) SetTransport(transport http.RoundTripper) {
+	c.httpClient.Transport = transport
+}
+
 // githubRelease represents the GitHub API release response
 type githubRelease struct {
+	ID          int64     `json:"id"`
+	HTMLURL     string    `json:"html_url"`
 	TagName     string    `json:"tag_name"`
 	Name        string    `json:"name"`
 	Body        string    `json:"body"`
+	Draft       bool      `json:"draft"`
 	PublishedAt time.Time `json:"published_at"`
 	Prerelease  bool      `json:"prerelease"`
 	Assets      []struct {
@@ -88,25 +113,7 @@ func (c *GitHubClient) GetLatestRelease(ctx context.Context, owner, repo string)
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	// Convert to ReleaseInfo
-	release := &ReleaseInfo{
-		TagName:     ghRelease.TagName,
-		Name:        ghRelease.Name,
-		Body:        ghRelease.Body,
-		PublishedAt: ghRelease.PublishedAt,
-		Prerelease:  ghRelease.Prerelease,
-		Assets:      make([]ReleaseAsset, len(ghRelease.Assets)),
-	}
-
-	for i, asset := range ghRelease.Assets {
-		release.Assets[i] = ReleaseAsset{
-			Name:        asset.Name,
-			DownloadURL: asset.BrowserDownloadURL,
-			Size:        asset.Size,
-		}
-	}
-
-	return release, nil
+	return ghRelease.info(), nil
 }
 
 // IsNewer compares the release version with the current version
@@ -132,66 +139,150 @@ func IsNewer(currentVersion, releaseVersion string) (bool, error) {
 // CreateRelease creates a new GitHub release
 func (c *GitHubClient) CreateRelease(ctx context.Context, owner, repo string, release *CreateReleaseRequest) (*ReleaseInfo, error) {
 	url := fmt.Sprintf("%s/repos/%s/%s/releases", c.baseURL, owner, repo)
+	info, err := c.sendRelease(ctx, http.MethodPost, url, release, http.StatusCreated)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create release: %w", err)
+	}
+	return info, nil
+}
+
+// UpdateRelease replaces the tag, name, notes and flags of an existing release
+func (c *GitHubClient) UpdateRelease(ctx context.Context, owner, repo string, id int64, release *CreateReleaseRequest) (*ReleaseInfo, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/releases/%d", c.baseURL, owner, repo, id)
+	info, err := c.sendRelease(ctx, http.MethodPatch, url, release, http.StatusOK)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update release: %w", err)
+	}
+	return info, nil
+}
+
+// GetReleaseByTag fetches the release for a tag, or nil when the tag has no
+// published release. Draft releases are not attached to their tag until they
+// are published, so they are found by listing the repository's releases.
+func (c *GitHubClient) GetReleaseByTag(ctx context.Context, owner, repo, tag string) (*ReleaseInfo, error) {
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/releases/tags/%s", c.baseURL, owner, repo, neturl.PathEscape(tag))
+	info, err := c.sendRelease(ctx, http.MethodGet, endpoint, nil, http.StatusOK)
+	if err == nil {
+		return info, nil
+	}
+	if !errors.Is(err, errReleaseNotFound) {
+		return nil, fmt.Errorf("failed to fetch release for %s: %w", tag, err)
+	}
 
-	// Marshal request body
-	bodyBytes, err := json.Marshal(release)
+	req, err := c.newRequest(ctx, http.MethodGet, fmt.Sprintf("%s/repos/%s/%s/releases?per_page=100", c.baseURL, owner, repo), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+		return nil, fmt.Errorf("failed to list releases: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if err := checkResponse(resp, http.StatusOK); err != nil {
+		return nil, fmt.Errorf("failed to list releases: %w", err)
+	}
+	var releases []githubRelease
+	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
+	for _, release := range releases {
+		if release.Draft && release.TagName == tag {
+			return release.info(), nil
+		}
+	}
+	return nil, nil
+}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", url, strings.NewReader(string(bodyBytes)))
+// errReleaseNotFound is returned for a 404 response
+var errReleaseNotFound = errors.New("release not found")
+
+// newRequest builds a GitHub API request with a JSON body when body is not nil
+func (c *GitHubClient) newRequest(ctx context.Context, method, url string, body any) (*http.Request, error) {
+	var reader io.Reader
+	if body != nil {
+		bodyBytes, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(bodyBytes)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Add headers
 	req.Header.Set("Accept", "application/vnd.github+json")
-	req.Header.Set("Content-Type", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
 	if c.authToken != "" {
 		req.Header.Set("Authorization", "Bearer "+c.authToken)
 	}
+	return req, nil
+}
 
+// sendRelease sends a request whose response is a single release
+func (c *GitHubClient) sendRelease(ctx context.Context, method, url string, body any, wantStatus int) (*ReleaseInfo, error) {
+	req, err := c.newRequest(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create release: %w", err)
+		return nil, err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
-	// Check for rate limiting
+	if err := checkResponse(resp, wantStatus); err != nil {
+		return nil, err
+	}
+
+	var ghRelease githubRelease
+	if err := json.NewDecoder(resp.Body).Decode(&ghRelease); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return ghRelease.info(), nil
+}
+
+// checkResponse reports rate limiting, a missing resource or any status other than want
+func checkResponse(resp *http.Response, want int) error {
 	if resp.StatusCode == http.StatusForbidden {
 		remaining := resp.Header.Get("X-RateLimit-Remaining")
 		if remaining == "0" {
 			resetTime := resp.Header.Get("X-RateLimit-Reset")
-			return nil, fmt.Errorf("GitHub API rate limit exceeded. Reset at %s. Set GITHUB_TOKEN environment variable to increase rate limit", resetTime)
+			return fmt.Errorf("GitHub API rate limit exceeded. Reset at %s. Set GITHUB_TOKEN environment variable to increase rate limit", resetTime)
 		}
 	}
-
-	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+	if resp.StatusCode == http.StatusNotFound && want != http.StatusNotFound {
+		return errReleaseNotFound
 	}
-
-	var ghRelease githubRelease
-	if err := json.NewDecoder(resp.Body).Decode(&ghRelease); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if resp.StatusCode != want {
+		return fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
 	}
+	return nil
+}
 
-	// Convert to ReleaseInfo
-	releaseInfo := &ReleaseInfo{
-		TagName:     ghRelease.TagName,
-		Name:        ghRelease.Name,
-		Body:        ghRelease.Body,
-		PublishedAt: ghRelease.PublishedAt,
-		Prerelease:  ghRelease.Prerelease,
-		Assets:      make([]ReleaseAsset, len(ghRelease.Assets)),
+// info converts an API release to ReleaseInfo
+func (r *githubRelease) info() *ReleaseInfo {
+	release := &ReleaseInfo{
+		ID:          r.ID,
+		URL:         r.HTMLURL,
+		TagName:     r.TagName,
+		Name:        r.Name,
+		Body:        r.Body,
+		Draft:       r.Draft,
+		PublishedAt: r.PublishedAt,
+		Prerelease:  r.Prerelease,
+		Assets:      make([]ReleaseAsset, len(r.Assets)),
 	}
-
-	for i, asset := range ghRelease.Assets {
-		releaseInfo.Assets[i] = ReleaseAsset{
+	for i, asset := range r.Assets {
+		release.Assets[i] = ReleaseAsset{
 			Name:        asset.Name,
 			DownloadURL: asset.BrowserDownloadURL,
 			Size:        asset.Size,
 		}
 	}
-
-	return releaseInfo, nil
+	return release
 }
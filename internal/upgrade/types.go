@@ -45,12 +45,15 @@ type InstallInfo struct {
 
 // ReleaseInfo contains information about a GitHub release
 type ReleaseInfo struct {
+	ID          int64
+	URL         string // Web page of the release
 	TagName     string
 	Name        string
 	Body        string
 	PublishedAt time.Time
 	Assets      []ReleaseAsset
 	Prerelease  bool
+	Draft       bool
 }
 
 // ReleaseAsset represents a downloadable asset from a GitHub release
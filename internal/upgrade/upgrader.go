@@ -17,6 +17,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/NatoNathan/shipyard/internal/httpclient"
 	"github.com/NatoNathan/shipyard/internal/logger"
 )
 
@@ -127,9 +128,18 @@ const (
 	maxUpgradeRedirects            = 3
 )
 
+// releasePlatform returns the os_arch part of release archive names for a
+// Go platform. 32-bit ARM is released for ARMv7 only, as "armv7".
+func releasePlatform(goos, goarch string) string {
+	if goarch == "arm" {
+		goarch = "armv7"
+	}
+	return fmt.Sprintf("%s_%s", goos, goarch)
+}
+
 func (u *ScriptUpgrader) Upgrade(ctx context.Context, release *ReleaseInfo) error {
 	// Determine platform string
-	platform := fmt.Sprintf("%s_%s", runtime.GOOS, runtime.GOARCH)
+	platform := releasePlatform(runtime.GOOS, runtime.GOARCH)
 
 	// Find the appropriate asset
 	var tarballAsset *ReleaseAsset
@@ -137,7 +147,7 @@ func (u *ScriptUpgrader) Upgrade(ctx context.Context, release *ReleaseInfo) erro
 
 	for i := range release.Assets {
 		asset := &release.Assets[i]
-		if strings.Contains(asset.Name, platform) && strings.HasSuffix(asset.Name, ".tar.gz") {
+		if strings.HasSuffix(asset.Name, "_"+platform+".tar.gz") {
 			tarballAsset = asset
 		}
 		if asset.Name == "checksums.txt" {
@@ -160,7 +170,7 @@ func (u *ScriptUpgrader) Upgrade(ctx context.Context, release *ReleaseInfo) erro
 	}
 
 	// Download tarball
-	u.log.Info("Downloading %s...", tarballAsset.Name)
+	u.log.Debug("Downloading %s", tarballAsset.Name)
 	tarballData, err := u.downloadFile(ctx, tarballAsset.DownloadURL)
 	if err != nil {
 		return fmt.Errorf("failed to download release: %w", err)
@@ -172,14 +182,14 @@ func (u *ScriptUpgrader) Upgrade(ctx context.Context, release *ReleaseInfo) erro
 	}
 
 	// Extract binary
-	u.log.Info("Extracting binary...")
+	u.log.Debug("Extracting binary")
 	binaryData, err := u.extractBinary(tarballData)
 	if err != nil {
 		return fmt.Errorf("failed to extract binary: %w", err)
 	}
 
 	// Atomic replacement
-	u.log.Info("Installing binary...")
+	u.log.Debug("Installing binary to %s", u.binaryPath)
 	if err := u.atomicReplace(binaryData); err != nil {
 		return fmt.Errorf("failed to replace binary: %w", err)
 	}
@@ -199,7 +209,8 @@ func (u *ScriptUpgrader) downloadFile(ctx context.Context, url string) ([]byte,
 	}
 
 	client := &http.Client{
-		Timeout: defaultUpgradeDownloadTimeout,
+		Timeout:   defaultUpgradeDownloadTimeout,
+		Transport: httpclient.Transport(),
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			if len(via) >= maxUpgradeRedirects {
 				return fmt.Errorf("stopped after %d redirects", maxUpgradeRedirects)
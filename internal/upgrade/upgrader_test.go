@@ -223,9 +223,15 @@ func TestScriptUpgrader_AtomicReplace(t *testing.T) {
 	})
 }
 
+func TestReleasePlatform(t *testing.T) {
+	assert.Equal(t, "linux_amd64", releasePlatform("linux", "amd64"))
+	assert.Equal(t, "linux_armv7", releasePlatform("linux", "arm"))
+	assert.Equal(t, "windows_arm64", releasePlatform("windows", "arm64"))
+}
+
 func TestScriptUpgrader_Upgrade(t *testing.T) {
 	// Create a mock HTTP server
-	platform := fmt.Sprintf("%s_%s", runtime.GOOS, runtime.GOARCH)
+	platform := releasePlatform(runtime.GOOS, runtime.GOARCH)
 	tarballName := fmt.Sprintf("shipyard_v1.0.0_%s.tar.gz", platform)
 
 	// Create a minimal tar.gz for testing
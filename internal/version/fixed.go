@@ -19,12 +19,13 @@ package version
 //
 // Fixed dependencies are handled in PropagateLinked() by checking the edge strategy:
 //
-//	if edge.Strategy != "linked" {
-//	    continue // Skip propagation for non-linked dependencies
+//	if edge.Strategy != "linked" && edge.Strategy != "patch" {
+//	    continue // Skip propagation for fixed dependencies
 //	}
 //
-// Only "linked" dependencies participate in version propagation. All other strategies
-// (including "fixed" and any unrecognized strategies) block propagation.
+// Only "linked" and "patch" dependencies participate in version propagation. "linked"
+// passes the dependency's change type through (subject to bump mapping); "patch" always
+// gives the dependent a patch bump. "fixed" blocks propagation.
 //
 // ## Default Strategy
 //
@@ -15,7 +15,8 @@ import (
 //  1. Start with direct bumps and apply them to result
 //  2. Process changed packages in sorted order for deterministic results
 //  3. For each changed package, find packages that depend on it
-//  4. If dependency has "linked" strategy, propagate the bump (respecting bump mapping)
+//  4. If dependency has "linked" strategy, propagate the bump (respecting bump mapping);
+//     with "patch" strategy, propagate a patch bump whatever the dependency's change type
 //  5. Skip packages that already have direct bumps (direct takes precedence)
 //  6. When a package receives bumps from multiple paths, keep the higher-priority bump
 //  7. Continue until no more changes propagate
@@ -72,8 +73,8 @@ func PropagateLinked(
 					if edge.To == changedPkg {
 						dependent := node.Package.Name
 
-						// Only propagate for linked strategy
-						if edge.Strategy != "linked" {
+						// Only linked and patch strategies propagate
+						if edge.Strategy != "linked" && edge.Strategy != "patch" {
 							continue
 						}
 
@@ -85,8 +86,10 @@ func PropagateLinked(
 						// Get the change type from the dependency
 						changeType := result[changedPkg].ChangeType
 
-						// Apply bump mapping if present
-						if edge.BumpMap != nil {
+						// Patch strategy always yields a patch bump; otherwise apply bump mapping if present
+						if edge.Strategy == "patch" {
+							changeType = "patch"
+						} else if edge.BumpMap != nil {
 							if mapped, ok := edge.BumpMap[changeType]; ok {
 								changeType = mapped
 							}
@@ -270,6 +270,44 @@ func TestPropagateLinked(t *testing.T) {
 		assert.NotContains(t, result, "web")
 	})
 
+	t.Run("patch strategy always propagates a patch bump", func(t *testing.T) {
+		// cli -> core (patch), app -> cli (linked)
+		cfg := &config.Config{
+			Packages: []config.Package{
+				{Name: "core", Path: "./core", Ecosystem: config.EcosystemGo},
+				{Name: "cli", Path: "./cli", Ecosystem: config.EcosystemGo,
+					Dependencies: []config.Dependency{
+						{Package: "core", Strategy: "patch", BumpMapping: map[string]string{"major": "major"}},
+					},
+				},
+				{Name: "app", Path: "./app", Ecosystem: config.EcosystemGo,
+					Dependencies: []config.Dependency{
+						{Package: "cli", Strategy: "linked"},
+					},
+				},
+			},
+		}
+
+		g, err := graph.BuildGraph(cfg)
+		require.NoError(t, err)
+
+		currentVersions := map[string]semver.Version{
+			"core": {Major: 1, Minor: 0, Patch: 0},
+			"cli":  {Major: 3, Minor: 2, Patch: 1},
+			"app":  {Major: 0, Minor: 4, Patch: 0},
+		}
+
+		result, err := PropagateLinked(g, currentVersions, map[string]string{"core": "major"})
+		require.NoError(t, err)
+
+		assert.Equal(t, "2.0.0", result["core"].NewVersion.String())
+		assert.Equal(t, "patch", result["cli"].ChangeType)
+		assert.Equal(t, "3.2.2", result["cli"].NewVersion.String())
+		assert.Equal(t, "propagated", result["cli"].Source)
+		// The patch bump continues down linked edges
+		assert.Equal(t, "0.4.1", result["app"].NewVersion.String())
+	})
+
 	t.Run("no propagation for empty direct bumps", func(t *testing.T) {
 		cfg := &config.Config{
 			Packages: []config.Package{
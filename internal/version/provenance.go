@@ -0,0 +1,248 @@
+package version
+
+import (
+	"sort"
+
+	"github.com/NatoNathan/shipyard/internal/consignment"
+	"github.com/NatoNathan/shipyard/internal/graph"
+	"github.com/NatoNathan/shipyard/pkg/semver"
+)
+
+// Propagation describes the dependency graph behind a set of version bumps
+// and why each package bumps. It is the JSON schema shared by every command
+// that explains a release plan.
+type Propagation struct {
+	Nodes      []PropagationNode `json:"nodes"`
+	Edges      []PropagationEdge `json:"edges"`
+	Provenance []BumpProvenance  `json:"provenance"`
+}
+
+// PropagationNode is a package with its current and, when bumped, next version
+type PropagationNode struct {
+	Package    string `json:"package"`
+	Current    string `json:"current"`
+	Next       string `json:"next,omitempty"`
+	ChangeType string `json:"changeType,omitempty"`
+}
+
+// PropagationEdge is a dependency from one package on another
+type PropagationEdge struct {
+	From       string            `json:"from"`
+	To         string            `json:"to"`
+	Strategy   string            `json:"strategy"`
+	Propagates bool              `json:"propagates"` // False for fixed dependencies
+	BumpMap    map[string]string `json:"bumpMap,omitempty"`
+}
+
+// BumpProvenance explains why a package bumps: its own consignments, bumps
+// propagated over dependency edges, or unification with a dependency cycle
+type BumpProvenance struct {
+	Package        string           `json:"package"`
+	ChangeType     string           `json:"changeType"`
+	Source         string           `json:"source"` // "direct", "propagated" or "cycle"
+	Consignments   []string         `json:"consignments,omitempty"`
+	PropagatedFrom []PropagatedEdge `json:"propagatedFrom,omitempty"`
+	Cycle          []string         `json:"cycle,omitempty"`   // Other members of the package's cycle
+	Origins        []string         `json:"origins,omitempty"` // Packages with consignments that caused the bump
+}
+
+// PropagatedEdge is a dependency edge that carried a bump to its dependent
+type PropagatedEdge struct {
+	Dependency string   `json:"dependency"`
+	Strategy   string   `json:"strategy"`
+	ChangeType string   `json:"changeType"` // The bump this edge contributes
+	Origins    []string `json:"origins"`
+}
+
+// ExplainPropagation builds the propagation report for bumps calculated by
+// Propagate over g. Nodes, edges and provenance are sorted by package name,
+// and each package's consignments by ID.
+func ExplainPropagation(
+	g *graph.DependencyGraph,
+	currentVersions map[string]semver.Version,
+	bumps map[string]VersionBump,
+	consignments []*consignment.Consignment,
+) *Propagation {
+	report := &Propagation{
+		Nodes:      []PropagationNode{},
+		Edges:      []PropagationEdge{},
+		Provenance: []BumpProvenance{},
+	}
+
+	names := make([]string, 0, g.GetNodeCount())
+	for _, node := range g.GetAllNodes() {
+		names = append(names, node.Package.Name)
+	}
+	sort.Strings(names)
+
+	direct := make(map[string][]string)
+	for _, c := range consignments {
+		for _, pkg := range c.Packages {
+			direct[pkg] = append(direct[pkg], c.ID)
+		}
+	}
+	for _, ids := range direct {
+		sort.Strings(ids)
+	}
+
+	cycles := make(map[string][]string)
+	for _, scc := range graph.FindStronglyConnectedComponents(g) {
+		if len(scc) > 1 || isSelfCycle(g, scc[0]) {
+			members := append([]string{}, scc...)
+			sort.Strings(members)
+			for _, member := range members {
+				cycles[member] = members
+			}
+		}
+	}
+
+	e := &explainer{graph: g, bumps: bumps, direct: direct, cycles: cycles, origins: make(map[string][]string)}
+
+	for _, name := range names {
+		node := PropagationNode{Package: name, Current: currentVersions[name].String()}
+		if bump, ok := bumps[name]; ok {
+			node.Next = bump.NewVersion.String()
+			node.ChangeType = bump.ChangeType
+		}
+		report.Nodes = append(report.Nodes, node)
+
+		for _, edge := range sortedEdgesFrom(g, name) {
+			report.Edges = append(report.Edges, PropagationEdge{
+				From:       edge.From,
+				To:         edge.To,
+				Strategy:   edge.Strategy,
+				Propagates: propagates(edge),
+				BumpMap:    edge.BumpMap,
+			})
+		}
+	}
+
+	for _, name := range names {
+		bump, ok := bumps[name]
+		if !ok {
+			continue
+		}
+		provenance := BumpProvenance{
+			Package:      name,
+			ChangeType:   bump.ChangeType,
+			Source:       bump.Source,
+			Consignments: direct[name],
+		}
+		if bump.Source == "propagated" {
+			provenance.PropagatedFrom = e.propagatedFrom(name)
+		}
+		if members, inCycle := cycles[name]; inCycle {
+			provenance.Cycle = without(members, name)
+		}
+		provenance.Origins = without(e.originsOf(name), name)
+		report.Provenance = append(report.Provenance, provenance)
+	}
+
+	return report
+}
+
+// Because returns the packages whose consignments caused pkg to bump through
+// propagation or a cycle, or nil when the bump is entirely its own
+func (p *Propagation) Because(pkg string) []string {
+	for _, provenance := range p.Provenance {
+		if provenance.Package == pkg {
+			return provenance.Origins
+		}
+	}
+	return nil
+}
+
+// explainer traces bumps back to the packages whose consignments caused them
+type explainer struct {
+	graph   *graph.DependencyGraph
+	bumps   map[string]VersionBump
+	direct  map[string][]string
+	cycles  map[string][]string
+	origins map[string][]string
+}
+
+// propagatedFrom lists the edges that carried a bump to pkg
+func (e *explainer) propagatedFrom(pkg string) []PropagatedEdge {
+	var result []PropagatedEdge
+	for _, edge := range sortedEdgesFrom(e.graph, pkg) {
+		dep, bumped := e.bumps[edge.To]
+		if !bumped || edge.To == pkg || !propagates(edge) {
+			continue
+		}
+		changeType := dep.ChangeType
+		if edge.Strategy == "patch" {
+			changeType = "patch"
+		} else if mapped, ok := edge.BumpMap[changeType]; ok {
+			changeType = mapped
+		}
+		result = append(result, PropagatedEdge{
+			Dependency: edge.To,
+			Strategy:   edge.Strategy,
+			ChangeType: changeType,
+			Origins:    e.originsOf(edge.To),
+		})
+	}
+	return result
+}
+
+// originsOf returns the sorted packages with consignments behind pkg's bump,
+// including pkg itself when it has consignments
+func (e *explainer) originsOf(pkg string) []string {
+	if origins, done := e.origins[pkg]; done {
+		return origins
+	}
+	// Guard against revisiting pkg while its origins are being resolved
+	e.origins[pkg] = nil
+
+	set := make(map[string]bool)
+	if len(e.direct[pkg]) > 0 {
+		set[pkg] = true
+	}
+	switch e.bumps[pkg].Source {
+	case "cycle":
+		for _, member := range e.cycles[pkg] {
+			if len(e.direct[member]) > 0 {
+				set[member] = true
+			}
+		}
+	case "propagated":
+		for _, edge := range e.graph.GetEdgesFrom(pkg) {
+			if _, bumped := e.bumps[edge.To]; bumped && edge.To != pkg && propagates(edge) {
+				for _, origin := range e.originsOf(edge.To) {
+					set[origin] = true
+				}
+			}
+		}
+	}
+
+	origins := make([]string, 0, len(set))
+	for origin := range set {
+		origins = append(origins, origin)
+	}
+	sort.Strings(origins)
+	e.origins[pkg] = origins
+	return origins
+}
+
+// sortedEdgesFrom returns a copy of pkg's edges sorted by dependency name
+func sortedEdgesFrom(g *graph.DependencyGraph, pkg string) []graph.GraphEdge {
+	edges := append([]graph.GraphEdge{}, g.GetEdgesFrom(pkg)...)
+	sort.SliceStable(edges, func(i, j int) bool { return edges[i].To < edges[j].To })
+	return edges
+}
+
+// propagates reports whether bumps travel over the edge; fixed edges block them
+func propagates(edge graph.GraphEdge) bool {
+	return edge.Strategy == "linked" || edge.Strategy == "patch"
+}
+
+// without returns names minus name, or nil when nothing is left
+func without(names []string, name string) []string {
+	var result []string
+	for _, n := range names {
+		if n != name {
+			result = append(result, n)
+		}
+	}
+	return result
+}
@@ -0,0 +1,137 @@
+package version
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/consignment"
+	"github.com/NatoNathan/shipyard/internal/graph"
+	"github.com/NatoNathan/shipyard/pkg/semver"
+	"github.com/NatoNathan/shipyard/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// provenanceFixture builds a graph with a lib/util cycle, a linked app on
+// lib, a patch-strategy cli on app and a fixed docs on lib, with one minor
+// consignment for util
+func provenanceFixture(t *testing.T) (*graph.DependencyGraph, map[string]semver.Version, map[string]VersionBump, []*consignment.Consignment) {
+	t.Helper()
+	cfg := &config.Config{
+		Packages: []config.Package{
+			{Name: "app", Path: "./app", Dependencies: []config.Dependency{{Package: "lib", Strategy: "linked"}}},
+			{Name: "cli", Path: "./cli", Dependencies: []config.Dependency{{Package: "app", Strategy: "patch"}}},
+			{Name: "docs", Path: "./docs", Dependencies: []config.Dependency{{Package: "lib", Strategy: "fixed"}}},
+			{Name: "lib", Path: "./lib", Dependencies: []config.Dependency{{Package: "util", Strategy: "linked"}}},
+			{Name: "util", Path: "./util", Dependencies: []config.Dependency{{Package: "lib", Strategy: "linked"}}},
+		},
+	}
+	g, err := graph.BuildGraph(cfg)
+	require.NoError(t, err)
+
+	current := map[string]semver.Version{
+		"app":  {Major: 1},
+		"cli":  {Major: 2},
+		"docs": {Major: 3},
+		"lib":  {Major: 4},
+		"util": {Major: 5},
+	}
+	consignments := []*consignment.Consignment{
+		{ID: "c-util", Packages: []string{"util"}, ChangeType: types.ChangeTypeMinor, Summary: "util feature"},
+	}
+
+	prop, err := NewPropagator(g)
+	require.NoError(t, err)
+	bumps, err := prop.Propagate(current, consignments)
+	require.NoError(t, err)
+	return g, current, bumps, consignments
+}
+
+func TestExplainPropagation(t *testing.T) {
+	g, current, bumps, consignments := provenanceFixture(t)
+
+	report := ExplainPropagation(g, current, bumps, consignments)
+
+	assert.Equal(t, []PropagationNode{
+		{Package: "app", Current: "1.0.0", Next: "1.1.0", ChangeType: "minor"},
+		{Package: "cli", Current: "2.0.0", Next: "2.0.1", ChangeType: "patch"},
+		{Package: "docs", Current: "3.0.0"},
+		{Package: "lib", Current: "4.0.0", Next: "4.1.0", ChangeType: "minor"},
+		{Package: "util", Current: "5.0.0", Next: "5.1.0", ChangeType: "minor"},
+	}, report.Nodes)
+
+	assert.Equal(t, []PropagationEdge{
+		{From: "app", To: "lib", Strategy: "linked", Propagates: true},
+		{From: "cli", To: "app", Strategy: "patch", Propagates: true},
+		{From: "docs", To: "lib", Strategy: "fixed", Propagates: false},
+		{From: "lib", To: "util", Strategy: "linked", Propagates: true},
+		{From: "util", To: "lib", Strategy: "linked", Propagates: true},
+	}, report.Edges)
+
+	assert.Equal(t, []BumpProvenance{
+		{
+			Package: "app", ChangeType: "minor", Source: "propagated",
+			PropagatedFrom: []PropagatedEdge{{Dependency: "lib", Strategy: "linked", ChangeType: "minor", Origins: []string{"util"}}},
+			Origins:        []string{"util"},
+		},
+		{
+			Package: "cli", ChangeType: "patch", Source: "propagated",
+			PropagatedFrom: []PropagatedEdge{{Dependency: "app", Strategy: "patch", ChangeType: "patch", Origins: []string{"util"}}},
+			Origins:        []string{"util"},
+		},
+		{Package: "lib", ChangeType: "minor", Source: "cycle", Cycle: []string{"util"}, Origins: []string{"util"}},
+		{Package: "util", ChangeType: "minor", Source: "cycle", Consignments: []string{"c-util"}, Cycle: []string{"lib"}},
+	}, report.Provenance)
+
+	assert.Equal(t, []string{"util"}, report.Because("cli"))
+	assert.Nil(t, report.Because("util"), "a bump caused only by its own consignments has no because")
+	assert.Nil(t, report.Because("docs"), "fixed dependencies do not propagate")
+}
+
+func TestExplainPropagation_JSON(t *testing.T) {
+	g, current, bumps, consignments := provenanceFixture(t)
+
+	data, err := json.Marshal(ExplainPropagation(g, current, bumps, consignments))
+	require.NoError(t, err)
+
+	var decoded map[string][]map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Len(t, decoded["nodes"], 5)
+	assert.Len(t, decoded["edges"], 5)
+	assert.Len(t, decoded["provenance"], 4)
+
+	docs := decoded["nodes"][2]
+	assert.Equal(t, "docs", docs["package"])
+	assert.NotContains(t, docs, "next", "unbumped packages omit next")
+
+	fixed := decoded["edges"][2]
+	assert.Equal(t, "fixed", fixed["strategy"])
+	assert.Equal(t, false, fixed["propagates"])
+
+	util := decoded["provenance"][3]
+	assert.Equal(t, []interface{}{"c-util"}, util["consignments"])
+	assert.Equal(t, "cycle", util["source"])
+}
+
+func TestExplainPropagation_ConsignmentOrderIsStable(t *testing.T) {
+	g, current, _, _ := provenanceFixture(t)
+	consignments := []*consignment.Consignment{
+		{ID: "c-util-b", Packages: []string{"util"}, ChangeType: types.ChangeTypePatch, Summary: "second"},
+		{ID: "c-util-a", Packages: []string{"util"}, ChangeType: types.ChangeTypeMinor, Summary: "first"},
+	}
+	reversed := []*consignment.Consignment{consignments[1], consignments[0]}
+
+	prop, err := NewPropagator(g)
+	require.NoError(t, err)
+	bumps, err := prop.Propagate(current, consignments)
+	require.NoError(t, err)
+
+	first, err := json.Marshal(ExplainPropagation(g, current, bumps, consignments))
+	require.NoError(t, err)
+	second, err := json.Marshal(ExplainPropagation(g, current, bumps, reversed))
+	require.NoError(t, err)
+
+	assert.Equal(t, string(first), string(second))
+	assert.Equal(t, []string{"c-util-a", "c-util-b"}, ExplainPropagation(g, current, bumps, reversed).Provenance[3].Consignments)
+}
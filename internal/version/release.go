@@ -0,0 +1,35 @@
+package version
+
+import (
+	"fmt"
+)
+
+// ResolveReleaseVersions recalculates the new version of each bump from its change
+// type, promoting pre-releases whose base already includes the change. When
+// preReleaseID is set, each new version becomes the next pre-release of that
+// release instead (1.1.0 -> 1.2.0-rc.1, then 1.2.0-rc.1 -> 1.2.0-rc.2). An
+// identifier that sorts before the current pre-release's, such as beta after
+// 1.2.0-rc.2, is an error: the release would go backwards.
+func ResolveReleaseVersions(bumps map[string]VersionBump, preReleaseID string) error {
+	for name, bump := range bumps {
+		target, err := bump.OldVersion.NextRelease(bump.ChangeType)
+		if err != nil {
+			return fmt.Errorf("failed to bump %s: %w", name, err)
+		}
+
+		if preReleaseID != "" {
+			if bump.OldVersion.IsPreRelease() && bump.OldVersion.BaseVersion().Compare(target) == 0 {
+				target = bump.OldVersion.BumpPrerelease(preReleaseID)
+			} else {
+				target = target.BumpPrerelease(preReleaseID)
+			}
+			if target.Compare(bump.OldVersion) <= 0 {
+				return fmt.Errorf("cannot release %s as %s: it is not newer than %s", name, target, bump.OldVersion)
+			}
+		}
+
+		bump.NewVersion = target
+		bumps[name] = bump
+	}
+	return nil
+}
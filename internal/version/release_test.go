@@ -0,0 +1,49 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/NatoNathan/shipyard/pkg/semver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveReleaseVersions(t *testing.T) {
+	tests := []struct {
+		name         string
+		oldVersion   string
+		changeType   string
+		preReleaseID string
+		want         string
+	}{
+		{name: "stable bump", oldVersion: "1.1.0", changeType: "minor", want: "1.2.0"},
+		{name: "first release candidate", oldVersion: "1.1.0", changeType: "minor", preReleaseID: "rc", want: "1.2.0-rc.1"},
+		{name: "next release candidate", oldVersion: "1.2.0-rc.1", changeType: "patch", preReleaseID: "rc", want: "1.2.0-rc.2"},
+		{name: "larger change restarts candidates", oldVersion: "1.2.0-rc.3", changeType: "major", preReleaseID: "rc", want: "2.0.0-rc.1"},
+		{name: "switching identifier", oldVersion: "1.2.0-beta.2", changeType: "minor", preReleaseID: "rc", want: "1.2.0-rc.1"},
+		{name: "promote release candidate", oldVersion: "1.2.0-rc.2", changeType: "minor", want: "1.2.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old := semver.MustParse(tt.oldVersion)
+			bumps := map[string]VersionBump{
+				"core": {Package: "core", OldVersion: old, NewVersion: old, ChangeType: tt.changeType, Source: "direct"},
+			}
+
+			require.NoError(t, ResolveReleaseVersions(bumps, tt.preReleaseID))
+			assert.Equal(t, tt.want, bumps["core"].NewVersion.String())
+			assert.Equal(t, tt.changeType, bumps["core"].ChangeType)
+		})
+	}
+}
+
+func TestResolveReleaseVersions_EarlierPrereleaseID(t *testing.T) {
+	old := semver.MustParse("1.2.0-rc.2")
+	bumps := map[string]VersionBump{
+		"core": {Package: "core", OldVersion: old, NewVersion: old, ChangeType: "patch", Source: "direct"},
+	}
+
+	err := ResolveReleaseVersions(bumps, "beta")
+	assert.EqualError(t, err, "cannot release core as 1.2.0-beta.1: it is not newer than 1.2.0-rc.2")
+}
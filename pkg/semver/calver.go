@@ -0,0 +1,212 @@
+package semver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultCalVerFormat is the CalVer format used when a package does not configure one
+const DefaultCalVerFormat = "YYYY.0M.MICRO"
+
+// now is the clock used to compute calendar versions; tests replace it
+var now = time.Now
+
+// calverToken describes one dot-separated segment of a CalVer format
+type calverToken struct {
+	name   string
+	padded bool // Zero-padded to two digits (0Y, 0M, 0W)
+}
+
+// calverTokens lists the supported CalVer format segments
+var calverTokens = map[string]calverToken{
+	"YYYY":  {name: "YYYY"},
+	"YY":    {name: "YY"},
+	"0Y":    {name: "YY", padded: true},
+	"MM":    {name: "MM"},
+	"0M":    {name: "MM", padded: true},
+	"WW":    {name: "WW"},
+	"0W":    {name: "WW", padded: true},
+	"MICRO": {name: "MICRO"},
+}
+
+// parseCalVerFormat splits a CalVer format into its tokens. Formats are a year,
+// an optional month or ISO week, and MICRO: "YYYY.0M.MICRO", "YY.MM.MICRO",
+// "YYYY.0W.MICRO", "YYYY.MICRO".
+func parseCalVerFormat(format string) ([]calverToken, error) {
+	parts := strings.Split(format, ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, fmt.Errorf("invalid calver format %q: expected 2 or 3 dot-separated segments", format)
+	}
+
+	tokens := make([]calverToken, len(parts))
+	for i, part := range parts {
+		token, ok := calverTokens[part]
+		if !ok {
+			return nil, fmt.Errorf("invalid calver format %q: unknown segment %q", format, part)
+		}
+		tokens[i] = token
+	}
+
+	if tokens[0].name != "YYYY" && tokens[0].name != "YY" {
+		return nil, fmt.Errorf("invalid calver format %q: first segment must be a year", format)
+	}
+	for _, token := range tokens[1 : len(tokens)-1] {
+		if token.name == "YYYY" || token.name == "YY" || token.name == "MICRO" {
+			return nil, fmt.Errorf("invalid calver format %q: middle segment must be a month or week", format)
+		}
+	}
+	if tokens[len(tokens)-1].name != "MICRO" {
+		return nil, fmt.Errorf("invalid calver format %q: last segment must be MICRO", format)
+	}
+	return tokens, nil
+}
+
+// ValidateCalVerFormat reports whether format is a supported CalVer format
+func ValidateCalVerFormat(format string) error {
+	_, err := parseCalVerFormat(format)
+	return err
+}
+
+// ParseCalVer parses a calendar version written in the given format.
+// The year is stored in Major, the optional second date segment in Minor
+// (zero for two-segment formats) and MICRO in Patch, so versions compare
+// chronologically. Pre-release and build suffixes follow SemVer syntax.
+func ParseCalVer(format, s string) (Version, error) {
+	tokens, err := parseCalVerFormat(format)
+	if err != nil {
+		return Version{}, err
+	}
+
+	s = strings.TrimPrefix(s, "v")
+	if s == "" {
+		return Version{}, fmt.Errorf("empty version string")
+	}
+
+	var buildMetadata, preRelease string
+	if idx := strings.Index(s, "+"); idx != -1 {
+		buildMetadata = s[idx+1:]
+		s = s[:idx]
+	}
+	if idx := strings.Index(s, "-"); idx != -1 {
+		preRelease = s[idx+1:]
+		s = s[:idx]
+	}
+
+	parts := strings.Split(s, ".")
+	if len(parts) != len(tokens) {
+		return Version{}, fmt.Errorf("invalid calendar version %s (expected %s)", s, format)
+	}
+
+	values := make([]int, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return Version{}, fmt.Errorf("invalid calendar version %s: segment %q is not a number", s, part)
+		}
+		token := tokens[i]
+		switch {
+		case token.padded && len(part) != 2:
+			return Version{}, fmt.Errorf("invalid calendar version %s: segment %q must be two digits", s, part)
+		case !token.padded && len(part) > 1 && part[0] == '0':
+			return Version{}, fmt.Errorf("invalid calendar version %s: leading zeros not allowed in %q", s, part)
+		case token.name == "YYYY" && len(part) != 4:
+			return Version{}, fmt.Errorf("invalid calendar version %s: year %q must be four digits", s, part)
+		}
+		if err := checkCalVerRange(token.name, n); err != nil {
+			return Version{}, fmt.Errorf("invalid calendar version %s: %w", s, err)
+		}
+		values[i] = n
+	}
+
+	v := Version{
+		Major:         values[0],
+		Patch:         values[len(values)-1],
+		PreRelease:    preRelease,
+		BuildMetadata: buildMetadata,
+		CalVer:        format,
+	}
+	if len(values) == 3 {
+		v.Minor = values[1]
+	}
+	return v, nil
+}
+
+// checkCalVerRange validates a date segment value
+func checkCalVerRange(name string, n int) error {
+	var upper int
+	switch name {
+	case "MM":
+		upper = 12
+	case "WW":
+		upper = 53
+	default:
+		return nil
+	}
+	if n < 1 || n > upper {
+		return fmt.Errorf("%s value %d out of range", name, n)
+	}
+	return nil
+}
+
+// formatCalVer renders the numeric part of a calendar version
+func formatCalVer(v Version) string {
+	tokens, err := parseCalVerFormat(v.CalVer)
+	if err != nil {
+		return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	}
+
+	values := []int{v.Major, v.Patch}
+	if len(tokens) == 3 {
+		values = []int{v.Major, v.Minor, v.Patch}
+	}
+
+	parts := make([]string, len(tokens))
+	for i, token := range tokens {
+		if token.padded {
+			parts[i] = fmt.Sprintf("%02d", values[i])
+		} else {
+			parts[i] = strconv.Itoa(values[i])
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// calverPeriod returns the year and optional month or week segment for t
+func calverPeriod(tokens []calverToken, t time.Time) (year, period int) {
+	year = t.Year()
+	if len(tokens) == 3 {
+		switch tokens[1].name {
+		case "MM":
+			period = int(t.Month())
+		case "WW":
+			year, period = t.ISOWeek()
+		}
+	}
+	if tokens[0].name == "YY" {
+		year -= 2000
+	}
+	return year, period
+}
+
+// IsCalVer returns true if this is a calendar version
+func (v Version) IsCalVer() bool {
+	return v.CalVer != ""
+}
+
+// NextCalVer returns the next calendar version at time t. A release in the
+// same period as v bumps MICRO; a release in a new period starts at MICRO 0.
+// If v is ahead of t (clock skew), MICRO is bumped within v's period.
+func (v Version) NextCalVer(t time.Time) (Version, error) {
+	tokens, err := parseCalVerFormat(v.CalVer)
+	if err != nil {
+		return Version{}, err
+	}
+
+	year, period := calverPeriod(tokens, t)
+	if year > v.Major || (year == v.Major && period > v.Minor) {
+		return Version{Major: year, Minor: period, CalVer: v.CalVer}, nil
+	}
+	return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1, CalVer: v.CalVer}, nil
+}
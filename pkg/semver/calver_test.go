@@ -0,0 +1,145 @@
+package semver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateCalVerFormat(t *testing.T) {
+	valid := []string{"YYYY.0M.MICRO", "YYYY.MM.MICRO", "YY.0M.MICRO", "0Y.MM.MICRO", "YYYY.0W.MICRO", "YYYY.MICRO"}
+	for _, format := range valid {
+		assert.NoError(t, ValidateCalVerFormat(format), format)
+	}
+
+	invalid := []string{"", "YYYY", "MICRO.YYYY", "0M.YYYY.MICRO", "YYYY.0M.0D.MICRO", "YYYY.0M.PATCH", "YYYY.MICRO.MICRO"}
+	for _, format := range invalid {
+		assert.Error(t, ValidateCalVerFormat(format), format)
+	}
+}
+
+func TestParseCalVer(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		input   string
+		want    Version
+		wantErr bool
+	}{
+		{
+			name:   "zero-padded month",
+			format: "YYYY.0M.MICRO",
+			input:  "2024.06.2",
+			want:   Version{Major: 2024, Minor: 6, Patch: 2, CalVer: "YYYY.0M.MICRO"},
+		},
+		{
+			name:   "v prefix and pre-release",
+			format: "YYYY.0M.MICRO",
+			input:  "v2024.12.0-rc.1",
+			want:   Version{Major: 2024, Minor: 12, PreRelease: "rc.1", CalVer: "YYYY.0M.MICRO"},
+		},
+		{
+			name:   "short year unpadded month",
+			format: "YY.MM.MICRO",
+			input:  "24.6.10",
+			want:   Version{Major: 24, Minor: 6, Patch: 10, CalVer: "YY.MM.MICRO"},
+		},
+		{
+			name:   "year and micro only",
+			format: "YYYY.MICRO",
+			input:  "2025.3",
+			want:   Version{Major: 2025, Patch: 3, CalVer: "YYYY.MICRO"},
+		},
+		{name: "missing padding", format: "YYYY.0M.MICRO", input: "2024.6.2", wantErr: true},
+		{name: "unexpected padding", format: "YYYY.MM.MICRO", input: "2024.06.2", wantErr: true},
+		{name: "month out of range", format: "YYYY.0M.MICRO", input: "2024.13.0", wantErr: true},
+		{name: "short year in long format", format: "YYYY.0M.MICRO", input: "24.06.0", wantErr: true},
+		{name: "wrong segment count", format: "YYYY.0M.MICRO", input: "2024.06", wantErr: true},
+		{name: "semver", format: "YYYY.0M.MICRO", input: "1.2.x", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCalVer(tt.format, tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.input[len(tt.input)-len(got.String()):], got.String(), "round trip")
+		})
+	}
+}
+
+func TestNextCalVer(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 12, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name    string
+		format  string
+		current string
+		at      time.Time
+		want    string
+	}{
+		{name: "same month bumps micro", format: "YYYY.0M.MICRO", current: "2024.06.2", at: date(2024, 6, 30), want: "2024.06.3"},
+		{name: "month rollover resets micro", format: "YYYY.0M.MICRO", current: "2024.06.2", at: date(2024, 7, 1), want: "2024.07.0"},
+		{name: "year rollover resets micro", format: "YYYY.0M.MICRO", current: "2024.12.5", at: date(2025, 1, 1), want: "2025.01.0"},
+		{name: "short year rollover", format: "YY.MM.MICRO", current: "24.12.5", at: date(2025, 1, 1), want: "25.1.0"},
+		{name: "year format same year", format: "YYYY.MICRO", current: "2024.4", at: date(2024, 12, 31), want: "2024.5"},
+		{name: "year format rollover", format: "YYYY.MICRO", current: "2024.4", at: date(2025, 1, 1), want: "2025.0"},
+		{name: "ISO week year boundary", format: "YYYY.0W.MICRO", current: "2024.52.1", at: date(2024, 12, 30), want: "2025.01.0"},
+		{name: "clock behind current version", format: "YYYY.0M.MICRO", current: "2024.08.0", at: date(2024, 7, 15), want: "2024.08.1"},
+		{name: "pre-release base", format: "YYYY.0M.MICRO", current: "2024.06.2-rc.1", at: date(2024, 6, 3), want: "2024.06.3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, err := ParseCalVer(tt.format, tt.current)
+			require.NoError(t, err)
+			next, err := current.BaseVersion().NextCalVer(tt.at)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, next.String())
+			assert.Equal(t, 1, next.Compare(current.BaseVersion()), "next version must sort after current")
+		})
+	}
+}
+
+func TestCalVer_BumpAndNextRelease(t *testing.T) {
+	original := now
+	t.Cleanup(func() { now = original })
+	now = func() time.Time { return time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC) }
+
+	v, err := ParseCalVer("YYYY.0M.MICRO", "2024.06.2")
+	require.NoError(t, err)
+
+	// Every change type moves to the next release for the current date
+	for _, changeType := range []string{"patch", "minor", "major"} {
+		next, err := v.Bump(changeType)
+		require.NoError(t, err)
+		assert.Equal(t, "2024.06.3", next.String(), changeType)
+	}
+	_, err = v.Bump("chore")
+	assert.Error(t, err)
+
+	// A pre-release in the current period is promoted to its base
+	rc, err := ParseCalVer("YYYY.0M.MICRO", "2024.06.3-rc.2")
+	require.NoError(t, err)
+	promoted, err := rc.NextRelease("minor")
+	require.NoError(t, err)
+	assert.Equal(t, "2024.06.3", promoted.String())
+
+	// A pre-release from a past period moves to the new period
+	stale, err := ParseCalVer("YYYY.0M.MICRO", "2024.05.0-rc.1")
+	require.NoError(t, err)
+	next, err := stale.NextRelease("patch")
+	require.NoError(t, err)
+	assert.Equal(t, "2024.06.0", next.String())
+
+	// Pre-release numbering keeps the CalVer format
+	assert.Equal(t, "2024.06.3-rc.3", rc.BumpPrerelease("rc").String())
+}
@@ -9,12 +9,16 @@ import (
 // Version represents a semantic version with major, minor, and patch numbers,
 // an optional pre-release identifier (e.g., "alpha.1", "beta.2", "snapshot.20260204-153045"),
 // and optional build metadata (e.g., "build.123").
+//
+// Calendar versions reuse the same fields (see ParseCalVer) and record their
+// format in CalVer, which controls how they are printed and bumped.
 type Version struct {
 	Major         int
 	Minor         int
 	Patch         int
 	PreRelease    string
 	BuildMetadata string
+	CalVer        string // CalVer format (e.g. "YYYY.0M.MICRO"); empty for SemVer
 }
 
 // Parse parses a semantic version string into a Version struct
@@ -98,6 +102,9 @@ func Parse(s string) (Version, error) {
 // String returns the string representation of the version (e.g., "1.2.3", "1.2.3-alpha.1", "1.2.3+build.123")
 func (v Version) String() string {
 	base := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.CalVer != "" {
+		base = formatCalVer(v)
+	}
 	if v.PreRelease != "" {
 		base += "-" + v.PreRelease
 	}
@@ -235,9 +242,20 @@ func MustParse(s string) Version {
 // Bump returns a new version with the specified change type applied.
 // The returned version always has an empty PreRelease (clean bump).
 // changeType should be one of: "patch", "minor", "major"
+//
+// Calendar versions ignore the change type beyond validating it: every bump
+// moves to the next release for the current date (see NextCalVer).
 func (v Version) Bump(changeType string) (Version, error) {
 	// Bump is always based on the base version (without pre-release)
 	base := v.BaseVersion()
+	if base.CalVer != "" {
+		switch changeType {
+		case "patch", "minor", "major":
+			return base.NextCalVer(now())
+		default:
+			return Version{}, fmt.Errorf("invalid change type: %s (must be patch, minor, or major)", changeType)
+		}
+	}
 	switch changeType {
 	case "patch":
 		return Version{
@@ -262,12 +280,55 @@ func (v Version) Bump(changeType string) (Version, error) {
 	}
 }
 
+// NextRelease returns the stable release that a change of the given type leads to.
+// A pre-release whose base version already includes the change is promoted to that
+// base (1.2.0-rc.2 + minor -> 1.2.0); otherwise this is the same as Bump.
+func (v Version) NextRelease(changeType string) (Version, error) {
+	if v.IsPreRelease() {
+		base := v.BaseVersion()
+		var includes bool
+		switch {
+		case v.CalVer != "":
+			// A calendar pre-release is promoted unless its period has passed
+			next, err := base.NextCalVer(now())
+			if err != nil {
+				return Version{}, err
+			}
+			includes = next.Patch != 0
+		case changeType == "patch":
+			includes = true
+		case changeType == "minor":
+			includes = base.Patch == 0
+		case changeType == "major":
+			includes = base.Minor == 0 && base.Patch == 0
+		}
+		if includes {
+			return base, nil
+		}
+	}
+	return v.Bump(changeType)
+}
+
+// BumpPrerelease returns the next pre-release of this version for the given identifier.
+// A matching numbered pre-release is incremented (1.2.0-rc.1 -> 1.2.0-rc.2); any other
+// version starts a new series (1.2.0 -> 1.2.0-rc.1, 1.2.0-beta.2 -> 1.2.0-rc.1).
+// Build metadata is dropped.
+func (v Version) BumpPrerelease(identifier string) Version {
+	if rest, ok := strings.CutPrefix(v.PreRelease, identifier+"."); ok {
+		if n, numeric := isNumeric(rest); numeric {
+			return v.WithPreRelease(fmt.Sprintf("%s.%d", identifier, n+1))
+		}
+	}
+	return v.WithPreRelease(identifier + ".1")
+}
+
 // BaseVersion returns a copy of this version without the PreRelease identifier
 func (v Version) BaseVersion() Version {
 	return Version{
-		Major: v.Major,
-		Minor: v.Minor,
-		Patch: v.Patch,
+		Major:  v.Major,
+		Minor:  v.Minor,
+		Patch:  v.Patch,
+		CalVer: v.CalVer,
 	}
 }
 
@@ -278,6 +339,7 @@ func (v Version) WithPreRelease(preRelease string) Version {
 		Minor:      v.Minor,
 		Patch:      v.Patch,
 		PreRelease: preRelease,
+		CalVer:     v.CalVer,
 	}
 }
 
@@ -289,6 +351,7 @@ func (v Version) WithBuildMetadata(metadata string) Version {
 		Patch:         v.Patch,
 		PreRelease:    v.PreRelease,
 		BuildMetadata: metadata,
+		CalVer:        v.CalVer,
 	}
 }
 
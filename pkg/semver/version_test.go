@@ -550,3 +550,52 @@ func TestParseRejectsLeadingZeros(t *testing.T) {
 		})
 	}
 }
+
+func TestNextRelease(t *testing.T) {
+	tests := []struct {
+		name       string
+		version    string
+		changeType string
+		want       string
+	}{
+		{name: "stable minor", version: "1.1.0", changeType: "minor", want: "1.2.0"},
+		{name: "rc promoted on minor", version: "1.2.0-rc.2", changeType: "minor", want: "1.2.0"},
+		{name: "rc promoted on patch", version: "1.2.0-rc.2", changeType: "patch", want: "1.2.0"},
+		{name: "rc of patch release needs minor", version: "1.2.1-rc.1", changeType: "minor", want: "1.3.0"},
+		{name: "rc of minor release needs major", version: "1.2.0-rc.1", changeType: "major", want: "2.0.0"},
+		{name: "rc of major release promoted", version: "2.0.0-rc.1", changeType: "major", want: "2.0.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MustParse(tt.version).NextRelease(tt.changeType)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.String())
+		})
+	}
+
+	_, err := MustParse("1.0.0").NextRelease("invalid")
+	assert.Error(t, err)
+}
+
+func TestBumpPrerelease(t *testing.T) {
+	tests := []struct {
+		version string
+		want    string
+	}{
+		{version: "1.2.0", want: "1.2.0-rc.1"},
+		{version: "1.2.0-rc.1", want: "1.2.0-rc.2"},
+		{version: "1.2.0-rc.9", want: "1.2.0-rc.10"},
+		{version: "1.2.0-beta.2", want: "1.2.0-rc.1"},
+		{version: "1.2.0-rc", want: "1.2.0-rc.1"},
+		{version: "1.2.0-rcx.3", want: "1.2.0-rc.1"},
+		{version: "1.2.0-rc.1+build.5", want: "1.2.0-rc.2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			got := MustParse(tt.version).BumpPrerelease("rc")
+			assert.Equal(t, tt.want, got.String())
+		})
+	}
+}
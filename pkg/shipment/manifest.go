@@ -0,0 +1,75 @@
+// Package shipment defines the release manifest written by
+// `shipyard version --manifest` and `shipyard manifest`, so deploy tooling
+// can read what a release shipped instead of parsing changelogs.
+package shipment
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/NatoNathan/shipyard/pkg/types"
+)
+
+// SchemaVersion is the manifest format this package reads and writes. It is
+// bumped whenever a field changes meaning or is removed; new fields are added
+// without a bump.
+const SchemaVersion = 1
+
+// Manifest describes the package versions shipped by a release
+type Manifest struct {
+	SchemaVersion int       `json:"schemaVersion"`
+	Packages      []Package `json:"packages"`
+}
+
+// Package is one package version in a release
+type Package struct {
+	Name         string           `json:"name"`
+	OldVersion   string           `json:"oldVersion,omitempty"` // Empty when the release did not record it
+	NewVersion   string           `json:"newVersion"`
+	ChangeType   types.ChangeType `json:"changeType"`
+	Tag          string           `json:"tag,omitempty"`    // Git tag of the release, when tagged
+	Commit       string           `json:"commit,omitempty"` // SHA of the release commit, when known
+	Yanked       bool             `json:"yanked,omitempty"` // Release was withdrawn after publishing
+	Consignments []Consignment    `json:"consignments"`
+}
+
+// Consignment is a change shipped in a package version
+type Consignment struct {
+	ID         string                 `json:"id"`
+	ChangeType types.ChangeType       `json:"changeType"`
+	Summary    string                 `json:"summary"`
+	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// New returns a manifest of the current schema version
+func New(packages []Package) *Manifest {
+	if packages == nil {
+		packages = []Package{}
+	}
+	return &Manifest{SchemaVersion: SchemaVersion, Packages: packages}
+}
+
+// Marshal encodes the manifest as indented JSON with a trailing newline
+func (m *Manifest) Marshal() ([]byte, error) {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// Parse decodes a manifest, rejecting schema versions newer than this
+// package understands
+func Parse(data []byte) (*Manifest, error) {
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if m.SchemaVersion < 1 {
+		return nil, fmt.Errorf("manifest has no schemaVersion")
+	}
+	if m.SchemaVersion > SchemaVersion {
+		return nil, fmt.Errorf("manifest schema version %d is newer than the supported version %d", m.SchemaVersion, SchemaVersion)
+	}
+	return &m, nil
+}
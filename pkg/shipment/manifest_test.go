@@ -0,0 +1,52 @@
+package shipment
+
+import (
+	"testing"
+
+	"github.com/NatoNathan/shipyard/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManifest_RoundTrip(t *testing.T) {
+	manifest := New([]Package{
+		{
+			Name:       "core",
+			OldVersion: "1.2.0",
+			NewVersion: "1.3.0",
+			ChangeType: types.ChangeTypeMinor,
+			Tag:        "core/v1.3.0",
+			Commit:     "0123456789abcdef0123456789abcdef01234567",
+			Consignments: []Consignment{
+				{ID: "20260301-120000-abc123", ChangeType: types.ChangeTypeMinor, Summary: "Add CSV export", Metadata: map[string]interface{}{"issue": "JIRA-12"}},
+			},
+		},
+		{Name: "api", NewVersion: "0.3.1", ChangeType: types.ChangeTypePatch, Yanked: true, Consignments: []Consignment{}},
+	})
+
+	data, err := manifest.Marshal()
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"schemaVersion": 1`)
+	assert.NotContains(t, string(data), `"oldVersion": ""`, "unknown fields are omitted")
+
+	parsed, err := Parse(data)
+	require.NoError(t, err)
+	assert.Equal(t, manifest, parsed)
+}
+
+func TestNew_EmptyPackages(t *testing.T) {
+	data, err := New(nil).Marshal()
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"packages": []`)
+}
+
+func TestParse_SchemaVersion(t *testing.T) {
+	_, err := Parse([]byte(`{"packages": []}`))
+	assert.ErrorContains(t, err, "no schemaVersion")
+
+	_, err = Parse([]byte(`{"schemaVersion": 2, "packages": []}`))
+	assert.ErrorContains(t, err, "newer than the supported version 1")
+
+	_, err = Parse([]byte(`not json`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,149 @@
+package shipyard_test
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/NatoNathan/shipyard/pkg/shipyard"
+)
+
+// newExampleProject writes a project with one Go package at 1.0.0 and a
+// pending minor change, and returns its directory
+func newExampleProject() string {
+	dir, err := os.MkdirTemp("", "shipyard-example")
+	if err != nil {
+		log.Fatal(err)
+	}
+	files := map[string]string{
+		".shipyard/shipyard.yaml": "packages:\n  - name: core\n    path: ./core\n    ecosystem: go\n",
+		".shipyard/history.json":  "[]\n",
+		".shipyard/consignments/20260101-120000-add-search.md": `---
+id: 20260101-120000-add-search
+timestamp: 2026-01-01T12:00:00Z
+packages:
+  - core
+changeType: minor
+---
+
+Add search
+`,
+		"core/version.go": "package core\n\nconst Version = \"1.0.0\"\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			log.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func ExampleOpen() {
+	dir := newExampleProject()
+	defer os.RemoveAll(dir)
+
+	project, err := shipyard.Open(dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	pending, err := project.PendingConsignments()
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(project.Packages())
+	for _, c := range pending {
+		fmt.Printf("%s: %s %s\n", c.ID, c.ChangeType, c.Summary)
+	}
+	// Output:
+	// [core]
+	// 20260101-120000-add-search: minor Add search
+}
+
+func ExampleProject_ProjectedVersions() {
+	dir := newExampleProject()
+	defer os.RemoveAll(dir)
+
+	project, err := shipyard.Open(dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	changes, err := project.ProjectedVersions(shipyard.PlanOptions{})
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, change := range changes {
+		fmt.Printf("%s %s -> %s (%s)\n", change.Package, change.OldVersion, change.NewVersion, change.Source)
+	}
+
+	// Pre-releases are projected the same way
+	changes, err = project.ProjectedVersions(shipyard.PlanOptions{Prerelease: "rc"})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(changes[0].NewVersion)
+	// Output:
+	// core 1.0.0 -> 1.1.0 (direct)
+	// 1.1.0-rc.1
+}
+
+func ExampleProject_PreviewChangelog() {
+	dir := newExampleProject()
+	defer os.RemoveAll(dir)
+
+	project, err := shipyard.Open(dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	changelogs, err := project.PreviewChangelog(shipyard.ChangelogOptions{})
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, changelog := range changelogs {
+		rel, _ := filepath.Rel(dir, changelog.Path)
+		fmt.Println(changelog.Package, rel)
+	}
+	// Output:
+	// core core/CHANGELOG.md
+}
+
+func ExampleProject_Apply() {
+	dir := newExampleProject()
+	defer os.RemoveAll(dir)
+
+	project, err := shipyard.Open(dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	result, err := project.Apply(shipyard.ApplyOptions{
+		// The example project is not a git repository
+		NoCommit: true,
+		Output: shipyard.Output{
+			OnEvent: func(e shipyard.Event) {
+				if e.Level == shipyard.LevelWarning {
+					log.Println(e.Message)
+				}
+			},
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, pkg := range result.Packages {
+		fmt.Printf("%s %s -> %s\n", pkg.Package, pkg.OldVersion, pkg.NewVersion)
+	}
+
+	// The released consignment is gone
+	pending, err := project.PendingConsignments()
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(len(pending), "pending")
+	// Output:
+	// core 1.0.0 -> 1.1.0
+	// 0 pending
+}
@@ -0,0 +1,441 @@
+// Package shipyard is the Go API of shipyard, for tools that plan and apply
+// releases of a shipyard project without running the CLI. The shipyard
+// commands are built on it, so a release applied here is the release
+// `shipyard version` would apply.
+//
+// Nothing in this package prints to stdout or stderr, or exits the process:
+// results are returned, and what a release reports while it runs is passed
+// to the callbacks in ApplyOptions.
+package shipyard
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/NatoNathan/shipyard/internal/config"
+	"github.com/NatoNathan/shipyard/internal/consignment"
+	_ "github.com/NatoNathan/shipyard/internal/detect" // Expands package globs such as "packages/*"
+	"github.com/NatoNathan/shipyard/internal/release"
+	"github.com/NatoNathan/shipyard/internal/runstate"
+	"github.com/NatoNathan/shipyard/internal/template"
+	"github.com/NatoNathan/shipyard/pkg/types"
+)
+
+// Project is a shipyard project: a directory with a .shipyard configuration
+type Project struct {
+	dir      string
+	cfg      *config.Config
+	settings release.Settings
+}
+
+// Open loads the project whose configuration is in dir. Settings from the
+// configuration, such as the history lock timeout and remote template
+// credentials, are kept with the Project, so projects opened side by side
+// in one process each use their own.
+func Open(dir string) (*Project, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve project directory: %w", err)
+	}
+	cfg, err := config.LoadFromDir(abs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	return &Project{dir: abs, cfg: cfg, settings: release.SettingsFor(abs, cfg)}, nil
+}
+
+// Dir returns the absolute path of the project
+func (p *Project) Dir() string {
+	return p.dir
+}
+
+// Packages returns the names of the configured packages in config order
+func (p *Project) Packages() []string {
+	names := make([]string, len(p.cfg.Packages))
+	for i, pkg := range p.cfg.Packages {
+		names[i] = pkg.Name
+	}
+	return names
+}
+
+// Consignment is a pending change waiting to be released
+type Consignment struct {
+	ID         string
+	Created    time.Time
+	Packages   []string
+	ChangeType types.ChangeType
+	Summary    string
+	Metadata   map[string]interface{}
+}
+
+// PendingConsignments returns the pending consignments, oldest first.
+// Consignment files that fail to parse are skipped; `shipyard validate`
+// reports them.
+func (p *Project) PendingConsignments() ([]Consignment, error) {
+	pending, err := release.ReadConsignments(p.dir, p.cfg, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return newConsignments(pending), nil
+}
+
+// PlanOptions selects what a release covers
+type PlanOptions struct {
+	Packages      []string // Only consignments naming these packages; all when empty
+	Consignments  []string // Only the consignments with these IDs; the rest stay pending
+	Channel       string   // Release channel; the one mapped to the checked-out branch when empty
+	Prerelease    string   // Cut the next pre-release with this identifier, e.g. "rc"
+	IncludeFrozen bool     // Version packages marked frozen too
+
+	SetVersions map[string]string // Package -> version replacing the calculated one, e.g. "core": "2.0.0"
+	Force       bool              // Allow SetVersions that do not move past the current and released versions
+}
+
+func (o PlanOptions) internal() release.PlanOptions {
+	return release.PlanOptions{
+		Packages:      o.Packages,
+		Consignments:  o.Consignments,
+		Channel:       o.Channel,
+		Prerelease:    o.Prerelease,
+		IncludeFrozen: o.IncludeFrozen,
+		SetVersions:   o.SetVersions,
+		Force:         o.Force,
+	}
+}
+
+// VersionChange is the version a release gives a package
+type VersionChange struct {
+	Package    string
+	OldVersion string
+	NewVersion string
+	ChangeType types.ChangeType
+	Source     string   // "direct", "propagated" or "cycle"
+	Because    []string // For propagated changes, the released packages that caused them
+}
+
+// ProjectedVersions returns the versions a release would write, sorted by
+// package, without changing anything. Changes propagate through the
+// dependency graph exactly as they do when the release is applied.
+func (p *Project) ProjectedVersions(opts PlanOptions) ([]VersionChange, error) {
+	plan, err := release.NewPlan(p.dir, p.cfg, p.settings, opts.internal(), nil)
+	if err != nil {
+		return nil, err
+	}
+	propagation := plan.Explain()
+
+	changes := make([]VersionChange, 0, len(plan.Bumps))
+	for name, bump := range plan.Bumps {
+		changes = append(changes, VersionChange{
+			Package:    name,
+			OldVersion: bump.OldVersion.String(),
+			NewVersion: bump.NewVersion.String(),
+			ChangeType: types.ChangeType(bump.ChangeType),
+			Source:     bump.Source,
+			Because:    propagation.Because(name),
+		})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Package < changes[j].Package })
+	return changes, nil
+}
+
+// ChangelogOptions controls a changelog preview
+type ChangelogOptions struct {
+	PlanOptions
+	Template          string // Changelog template for every package, overriding the config
+	IncludeUnreleased bool   // List consignments the release leaves pending under Unreleased
+}
+
+// Changelog is a package's changelog as a release would write it
+type Changelog struct {
+	Package string
+	Path    string // Absolute path of the changelog file
+	Content string
+}
+
+// PreviewChangelog renders the changelog of each package a release would
+// version, including the new release, in apply order. Nothing is written.
+func (p *Project) PreviewChangelog(opts ChangelogOptions) ([]Changelog, error) {
+	if opts.Template != "" {
+		if err := template.ValidateTemplate(opts.Template, template.TemplateTypeChangelog); err != nil {
+			return nil, err
+		}
+	}
+	plan, err := release.NewPlan(p.dir, p.cfg, p.settings, opts.internal(), nil)
+	if err != nil {
+		return nil, err
+	}
+	previews, err := release.PreviewChangelogs(p.dir, p.cfg, plan, opts.Template, opts.IncludeUnreleased)
+	if err != nil {
+		return nil, err
+	}
+	changelogs := make([]Changelog, len(previews))
+	for i, preview := range previews {
+		changelogs[i] = Changelog(preview)
+	}
+	return changelogs, nil
+}
+
+// Level is the importance of an Event
+type Level int
+
+const (
+	LevelDetail  Level = iota // A step of the release, such as a version file written
+	LevelInfo                 // Worth showing, such as a tag that already marks the release
+	LevelWarning              // Went wrong without failing the release
+)
+
+// Event is a message reported while a release runs
+type Event struct {
+	Level   Level
+	Message string
+}
+
+// Output is where a release sends what it has to say while it runs
+type Output struct {
+	OnEvent    func(Event) // Receives each event; nil discards them
+	HookOutput io.Writer   // Receives the output of preVersion and postVersion hooks; nil discards it
+}
+
+func (o Output) internal() release.Output {
+	out := release.Output{HookOutput: o.HookOutput}
+	if o.OnEvent != nil {
+		out.Report = func(e release.Event) {
+			o.OnEvent(Event{Level: Level(e.Level), Message: e.Message})
+		}
+	}
+	return out
+}
+
+// ApplyOptions controls a release
+type ApplyOptions struct {
+	PlanOptions
+	Output
+
+	NoCommit          bool   // Skip the release commit, and so the tags
+	NoTag             bool   // Skip the release tags
+	NoPublish         bool   // Skip publishing Helm charts
+	NoHooks           bool   // Skip the preVersion and postVersion hooks
+	Template          string // Changelog template for every package, overriding the config
+	IncludeUnreleased bool   // List consignments left pending under Unreleased in changelogs
+	MaxSeverity       string // Override the level of every enabled rule: off, warn or error
+
+	RespectSchedule bool // Refuse to release outside the release window
+	IgnoreSchedule  bool // Release even when releaseSchedule.enforce is set
+
+	Push          bool   // Push the release commit and tags to the release remote
+	GitHubRelease bool   // Publish a GitHub release per pushed tag; requires Push
+	Draft         bool   // Publish the GitHub releases as drafts
+	Manifest      string // Write the release manifest JSON to this path, relative to the project
+}
+
+// Result reports what a release applied
+type Result struct {
+	Channel            string            // Release channel; "" without configured channels
+	Packages           []ReleasedPackage // In apply order; empty when nothing was pending
+	Committed          bool              // A release commit was created
+	Frozen             []string          // Frozen packages left unversioned
+	Held               []string          // IDs of the consignments kept pending for frozen packages
+	Charts             []Chart           // Helm charts published after the release
+	ArtifactsCommitted bool              // Chart digests were committed after the release commit
+	Pushed             *Push             // Set when the release was pushed
+	GitHubReleases     []GitHubRelease   // GitHub releases published after the push
+	GitHubSkipped      error             // Why requested GitHub releases were not published
+	Manifest           string            // Path of the written release manifest, if any
+}
+
+// ReleasedPackage is a package version a release wrote
+type ReleasedPackage struct {
+	Package    string
+	OldVersion string
+	NewVersion string
+	ChangeType types.ChangeType
+	Tag        string // Release tag; "" when the release was not tagged
+}
+
+// Chart is the outcome of publishing a released Helm chart. Err with a
+// Reference means the chart was pushed but its digest was not recorded.
+type Chart struct {
+	Package   string
+	Reference string
+	Digest    string
+	Err       error
+}
+
+// Push describes a pushed release
+type Push struct {
+	Remote string
+	Tags   []string
+}
+
+// GitHubRelease is the outcome of publishing one tag's GitHub release
+type GitHubRelease struct {
+	Tag     string
+	URL     string
+	Created bool // false when an existing release was updated
+	Err     error
+}
+
+// Apply releases the pending consignments: version files, history and
+// changelogs are written, released consignments removed, and the release
+// commit and tags created, then Helm charts, the push, GitHub releases and
+// the manifest follow as requested. Pre-flight checks and preVersion hooks
+// run before anything changes, and a failure while applying rolls
+// everything back. A run interrupted part way (the process was killed) is
+// finished with Resume or rolled back with Abort.
+//
+// When nothing is pending the Result lists no packages. When the release
+// succeeded but pushing it or writing the manifest failed, the Result is
+// returned together with the error.
+func (p *Project) Apply(opts ApplyOptions) (*Result, error) {
+	if opts.Template != "" {
+		if err := template.ValidateTemplate(opts.Template, template.TemplateTypeChangelog); err != nil {
+			return nil, err
+		}
+	}
+	if err := release.CheckReleaseWindow(p.cfg, opts.RespectSchedule, opts.IgnoreSchedule); err != nil {
+		return nil, err
+	}
+	out := opts.Output.internal()
+	plan, err := release.NewPlan(p.dir, p.cfg, p.settings, opts.PlanOptions.internal(), out.Report)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Channel: plan.Channel, Packages: []ReleasedPackage{}, Frozen: plan.Frozen}
+	for _, c := range plan.Held {
+		result.Held = append(result.Held, c.ID)
+	}
+	if len(plan.Consignments) == 0 {
+		return result, nil
+	}
+
+	applied, err := release.Apply(p.dir, p.cfg, plan, release.ApplyOptions{
+		NoCommit:          opts.NoCommit,
+		NoTag:             opts.NoTag,
+		NoPublish:         opts.NoPublish,
+		NoHooks:           opts.NoHooks,
+		Template:          opts.Template,
+		IncludeUnreleased: opts.IncludeUnreleased,
+		MaxSeverity:       opts.MaxSeverity,
+		Push:              opts.Push,
+		GitHubRelease:     opts.GitHubRelease,
+		Draft:             opts.Draft,
+		Manifest:          opts.Manifest,
+		Output:            out,
+	})
+	if applied == nil {
+		return nil, err
+	}
+	result.fill(applied)
+	return result, err
+}
+
+// ResumeOptions controls the finishing of an interrupted release
+type ResumeOptions struct {
+	Output
+}
+
+// Resume finishes a release that was interrupted part way, from the plan it
+// recorded before changing anything; nothing is recalculated. Like Apply, it
+// returns the Result together with the error when only the push or the
+// manifest failed.
+func (p *Project) Resume(opts ResumeOptions) (*Result, error) {
+	applied, err := release.Resume(p.dir, p.cfg, p.settings, opts.Output.internal())
+	if applied == nil {
+		return nil, err
+	}
+	result := &Result{Channel: applied.Run.Options.Channel, Packages: []ReleasedPackage{}}
+	result.fill(applied)
+	return result, err
+}
+
+// InterruptedRun describes a release that stopped part way
+type InterruptedRun struct {
+	StartedAt time.Time
+	Completed []string // Phases it completed, in order
+}
+
+// Interrupted returns the release that was interrupted part way, or nil
+// when there is none. Apply refuses to start while one is pending.
+func (p *Project) Interrupted() (*InterruptedRun, error) {
+	run, err := release.Interrupted(p.dir)
+	if err != nil || run == nil {
+		return nil, err
+	}
+	return newInterruptedRun(run), nil
+}
+
+// Abort rolls back a release that was interrupted part way and returns it.
+// The error lists anything that could not be rolled back.
+func (p *Project) Abort() (*InterruptedRun, error) {
+	run, err := release.Abort(p.dir)
+	if err != nil {
+		return nil, err
+	}
+	return newInterruptedRun(run), nil
+}
+
+// fill records what an applied run released and published
+func (r *Result) fill(applied *release.Result) {
+	run := applied.Run
+	tags := make(map[string]string, len(run.Tags))
+	if !run.Options.NoCommit && !run.Options.NoTag {
+		for _, tag := range run.Tags {
+			tags[tag.Package] = tag.Name
+		}
+	}
+	for _, bump := range run.Bumps {
+		r.Packages = append(r.Packages, ReleasedPackage{
+			Package:    bump.Package,
+			OldVersion: bump.OldVersion,
+			NewVersion: bump.NewVersion,
+			ChangeType: types.ChangeType(bump.ChangeType),
+			Tag:        tags[bump.Package],
+		})
+	}
+	r.Committed = applied.Committed
+	r.ArtifactsCommitted = applied.ArtifactsCommitted
+
+	for _, chart := range applied.Charts {
+		c := Chart{Package: chart.Package, Err: chart.Err}
+		if chart.Result != nil {
+			c.Reference = chart.Result.Reference
+			c.Digest = chart.Result.Digest
+		}
+		r.Charts = append(r.Charts, c)
+	}
+	if applied.Pushed != nil {
+		r.Pushed = &Push{Remote: applied.Pushed.Remote, Tags: applied.Pushed.Tags}
+	}
+	for _, gh := range applied.GitHubReleases {
+		r.GitHubReleases = append(r.GitHubReleases, GitHubRelease(gh))
+	}
+	r.GitHubSkipped = applied.GitHubSkipped
+	r.Manifest = applied.Manifest
+}
+
+func newInterruptedRun(run *runstate.Run) *InterruptedRun {
+	completed := make([]string, len(run.Completed))
+	for i, phase := range run.Completed {
+		completed[i] = string(phase)
+	}
+	return &InterruptedRun{StartedAt: run.StartedAt, Completed: completed}
+}
+
+func newConsignments(pending []*consignment.Consignment) []Consignment {
+	consignments := make([]Consignment, len(pending))
+	for i, c := range pending {
+		consignments[i] = Consignment{
+			ID:         c.ID,
+			Created:    c.Timestamp,
+			Packages:   c.Packages,
+			ChangeType: c.ChangeType,
+			Summary:    c.Summary,
+			Metadata:   c.Metadata,
+		}
+	}
+	return consignments
+}
@@ -0,0 +1,193 @@
+package shipyard
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeProject writes a project whose core package is at 1.0.0 and whose
+// api package depends on it, with the given consignment files pending
+func writeProject(t *testing.T, consignments map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		".shipyard/shipyard.yaml": `packages:
+  - name: core
+    path: ./core
+    ecosystem: go
+  - name: api
+    path: ./api
+    ecosystem: go
+    dependencies:
+      - package: core
+        strategy: linked
+`,
+		".shipyard/history.json": "[]\n",
+		"core/version.go":        "package core\n\nconst Version = \"1.0.0\"\n",
+		"api/version.go":         "package api\n\nconst Version = \"2.0.0\"\n",
+	}
+	for name, content := range consignments {
+		files[filepath.Join(".shipyard", "consignments", name)] = content
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	return dir
+}
+
+const coreMinor = `---
+id: c1
+timestamp: 2026-01-01T12:00:00Z
+packages:
+  - core
+changeType: minor
+---
+
+Add search
+`
+
+// captureStdout returns what fn writes to stdout
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	original := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = original }()
+
+	fn()
+	require.NoError(t, w.Close())
+	out, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return string(out)
+}
+
+func TestOpen_MissingConfig(t *testing.T) {
+	_, err := Open(t.TempDir())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to load configuration")
+}
+
+func TestOpen_KeepsSettingsPerProject(t *testing.T) {
+	dir := writeProject(t, nil)
+	configPath := filepath.Join(dir, ".shipyard", "shipyard.yaml")
+	config, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	t.Setenv("TEMPLATES_TOKEN", "secret")
+	settings := "history:\n  lockTimeout: 100ms\nremote:\n  cacheTTL: 2h\n  auth:\n    - host: templates.example.com\n      tokenEnv: TEMPLATES_TOKEN\n"
+	require.NoError(t, os.WriteFile(configPath, append(config, settings...), 0644))
+
+	configured, err := Open(dir)
+	require.NoError(t, err)
+	defaults, err := Open(writeProject(t, nil))
+	require.NoError(t, err)
+
+	// Opening a second project leaves the first one's settings alone
+	assert.Equal(t, 100*time.Millisecond, configured.settings.LockTimeout)
+	assert.Equal(t, 2*time.Hour, configured.settings.Remote.CacheTTL)
+	assert.Contains(t, configured.settings.Remote.Credentials, "templates.example.com")
+	assert.Zero(t, defaults.settings.LockTimeout)
+	assert.Zero(t, defaults.settings.Remote.CacheTTL)
+	assert.Empty(t, defaults.settings.Remote.Credentials)
+}
+
+func TestOpen_ExpandsPackageGlobs(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		".shipyard/shipyard.yaml":   "packages:\n  - path: packages/*\n    ecosystem: auto\n",
+		".shipyard/history.json":    "[]\n",
+		"packages/api/go.mod":       "module github.com/example/api\n\ngo 1.21\n",
+		"packages/web/package.json": `{"name": "web", "version": "1.0.0"}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	project, err := Open(dir)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"api", "web"}, project.Packages())
+}
+
+func TestProjectedVersions_Propagates(t *testing.T) {
+	project, err := Open(writeProject(t, map[string]string{"c1.md": coreMinor}))
+	require.NoError(t, err)
+
+	changes, err := project.ProjectedVersions(PlanOptions{})
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	assert.Equal(t, VersionChange{Package: "api", OldVersion: "2.0.0", NewVersion: "2.1.0", ChangeType: "minor", Source: "propagated", Because: []string{"core"}}, changes[0])
+	assert.Equal(t, "core", changes[1].Package)
+	assert.Equal(t, "1.1.0", changes[1].NewVersion)
+	assert.Equal(t, "direct", changes[1].Source)
+}
+
+func TestApply_NothingPending(t *testing.T) {
+	project, err := Open(writeProject(t, nil))
+	require.NoError(t, err)
+
+	result, err := project.Apply(ApplyOptions{NoCommit: true})
+	require.NoError(t, err)
+	assert.Empty(t, result.Packages)
+	assert.False(t, result.Committed)
+}
+
+func TestApply_ReportsThroughCallbacksOnly(t *testing.T) {
+	dir := writeProject(t, map[string]string{"c1.md": coreMinor})
+	project, err := Open(dir)
+	require.NoError(t, err)
+
+	var events []Event
+	var result *Result
+	stdout := captureStdout(t, func() {
+		result, err = project.Apply(ApplyOptions{
+			NoCommit: true,
+			Output:   Output{OnEvent: func(e Event) { events = append(events, e) }},
+		})
+	})
+	require.NoError(t, err)
+	assert.Empty(t, stdout)
+	assert.NotEmpty(t, events)
+
+	require.Len(t, result.Packages, 2)
+	assert.Equal(t, ReleasedPackage{Package: "core", OldVersion: "1.0.0", NewVersion: "1.1.0", ChangeType: "minor"}, result.Packages[0])
+	assert.Equal(t, "api", result.Packages[1].Package)
+
+	content, err := os.ReadFile(filepath.Join(dir, "core", "version.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"1.1.0"`)
+	assert.FileExists(t, filepath.Join(dir, "core", "CHANGELOG.md"))
+	assert.NoFileExists(t, filepath.Join(dir, ".shipyard", "consignments", "c1.md"))
+}
+
+func TestApply_InvalidTemplate(t *testing.T) {
+	project, err := Open(writeProject(t, map[string]string{"c1.md": coreMinor}))
+	require.NoError(t, err)
+
+	_, err = project.Apply(ApplyOptions{NoCommit: true, Template: "builtin:no-such-template"})
+	require.Error(t, err)
+	assert.FileExists(t, filepath.Join(project.Dir(), ".shipyard", "consignments", "c1.md"))
+}
+
+func TestInterruptedRun_None(t *testing.T) {
+	project, err := Open(writeProject(t, nil))
+	require.NoError(t, err)
+
+	run, err := project.Interrupted()
+	require.NoError(t, err)
+	assert.Nil(t, run)
+
+	_, err = project.Resume(ResumeOptions{})
+	assert.Error(t, err)
+	_, err = project.Abort()
+	assert.Error(t, err)
+}
@@ -165,7 +165,7 @@ func TestDocumentedCommandInventoryMatchesCLI(t *testing.T) {
 
 	shipyardBin := buildShipyard(t)
 	actual := helpCommandNames(t, shipyardBin)
-	for _, parent := range []string{"version", "config"} {
+	for _, parent := range []string{"version", "config", "consignment", "history", "import", "cache", "channel"} {
 		for _, child := range helpCommandNames(t, shipyardBin, parent) {
 			actual = append(actual, parent+" "+child)
 		}
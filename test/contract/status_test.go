@@ -17,7 +17,7 @@ func TestStatusCommand_ExitCodes(t *testing.T) {
 	// Build the shipyard binary for testing
 	shipyardBin := buildShipyard(t)
 
-	t.Run("exit 0 with no consignments", func(t *testing.T) {
+	t.Run("exit 2 with no consignments", func(t *testing.T) {
 		// Setup: Create initialized repo
 		tempDir := t.TempDir()
 		initializeTestRepo(t, shipyardBin, tempDir)
@@ -27,9 +27,12 @@ func TestStatusCommand_ExitCodes(t *testing.T) {
 		cmd.Dir = tempDir
 		output, err := cmd.CombinedOutput()
 
-		// Verify: Exit code 0
-		require.NoError(t, err, "status should exit 0 with no consignments")
+		// Verify: Exit code 2 so CI can gate on pending changes
+		var exitErr *exec.ExitError
+		require.ErrorAs(t, err, &exitErr, "status should exit non-zero with no consignments")
+		assert.Equal(t, 2, exitErr.ExitCode())
 		assert.Contains(t, string(output), "No pending consignments")
+		assert.NotContains(t, string(output), "Error:")
 	})
 
 	t.Run("exit 0 with pending consignments", func(t *testing.T) {
@@ -59,7 +62,9 @@ func TestStatusCommand_ExitCodes(t *testing.T) {
 		cmd.Dir = tempDir
 		output, err := cmd.CombinedOutput()
 
-		require.NoError(t, err, "status should exit 0 with a missing consignments directory: %s", output)
+		var exitErr *exec.ExitError
+		require.ErrorAs(t, err, &exitErr, "status should exit 2 with a missing consignments directory: %s", output)
+		assert.Equal(t, 2, exitErr.ExitCode())
 		assert.True(t, json.Valid(output), "status should return valid JSON: %s", output)
 		assert.JSONEq(t, `{}`, string(output))
 	})
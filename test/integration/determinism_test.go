@@ -0,0 +1,145 @@
+package integration
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	shipyardBuild     sync.Once
+	shipyardBinary    string
+	shipyardBuildErr  error
+	shipyardBuildLogs []byte
+)
+
+// shipyardBin builds the shipyard binary once per test run and returns its path
+func shipyardBin(t *testing.T) string {
+	t.Helper()
+	shipyardBuild.Do(func() {
+		dir, err := os.MkdirTemp("", "shipyard-integration-*")
+		if err != nil {
+			shipyardBuildErr = err
+			return
+		}
+		shipyardBinary = filepath.Join(dir, "shipyard")
+		cmd := exec.Command("go", "build", "-o", shipyardBinary, "../../cmd/shipyard")
+		shipyardBuildLogs, shipyardBuildErr = cmd.CombinedOutput()
+	})
+	require.NoError(t, shipyardBuildErr, "failed to build shipyard: %s", shipyardBuildLogs)
+	return shipyardBinary
+}
+
+// assertDeterministicOutput runs shipyard with args twice in dir and asserts
+// both runs succeed with byte-identical stdout
+func assertDeterministicOutput(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	bin := shipyardBin(t)
+
+	run := func() []byte {
+		var stdout, stderr bytes.Buffer
+		cmd := exec.Command(bin, args...)
+		cmd.Dir = dir
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		require.NoError(t, cmd.Run(), "shipyard %s: %s", strings.Join(args, " "), stderr.String())
+		return stdout.Bytes()
+	}
+
+	first := run()
+	second := run()
+	require.NotEmpty(t, first, "shipyard %s produced no output", strings.Join(args, " "))
+	assert.Equal(t, string(first), string(second), "shipyard %s output differs between runs", strings.Join(args, " "))
+}
+
+// setupDeterminismFixture creates a repository with a dependency cycle, a
+// fixed edge and consignments that share a timestamp, so any map-ordered or
+// filesystem-ordered output shows up as a diff between runs
+func setupDeterminismFixture(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	_, err := gogit.PlainInit(dir, false)
+	require.NoError(t, err)
+
+	shipyardDir := filepath.Join(dir, ".shipyard")
+	consignmentsDir := filepath.Join(shipyardDir, "consignments")
+	require.NoError(t, os.MkdirAll(consignmentsDir, 0755))
+
+	configContent := `packages:
+  - name: web
+    path: ./web
+    ecosystem: go
+    dependencies:
+      - package: core
+        strategy: fixed
+  - name: api
+    path: ./api
+    ecosystem: go
+    dependencies:
+      - package: core
+  - name: core
+    path: ./core
+    ecosystem: go
+    dependencies:
+      - package: api
+  - name: utils
+    path: ./utils
+    ecosystem: go
+  - name: cli
+    path: ./cli
+    ecosystem: go
+    dependencies:
+      - package: utils
+`
+	require.NoError(t, os.WriteFile(filepath.Join(shipyardDir, "shipyard.yaml"), []byte(configContent), 0644))
+
+	for _, pkg := range []string{"web", "api", "core", "utils", "cli"} {
+		pkgDir := filepath.Join(dir, pkg)
+		require.NoError(t, os.MkdirAll(pkgDir, 0755))
+		version := "package " + pkg + "\n\nconst Version = \"1.0.0\"\n"
+		require.NoError(t, os.WriteFile(filepath.Join(pkgDir, "version.go"), []byte(version), 0644))
+	}
+
+	consignments := []struct {
+		id, packages, changeType, summary string
+	}{
+		{"20260130-000000-ccc333", "[core, utils]", "minor", "Shared feature"},
+		{"20260130-000000-aaa111", "[utils]", "patch", "Fix utils"},
+		{"20260130-000000-bbb222", "[core]", "patch", "Fix core"},
+		{"20260130-000000-ddd444", "[web]", "major", "Redesign web"},
+	}
+	for _, c := range consignments {
+		content := "---\nid: " + c.id + "\ntimestamp: 2026-01-30T00:00:00Z\npackages: " + c.packages +
+			"\nchangeType: " + c.changeType + "\nmetadata:\n  team: platform\n  ticket: T-1\n---\n\n" + c.summary + "\n"
+		require.NoError(t, os.WriteFile(filepath.Join(consignmentsDir, c.id+".md"), []byte(content), 0644))
+	}
+
+	return dir
+}
+
+// TestMachineReadableOutputIsDeterministic runs every JSON-producing read-only
+// command twice against the same fixture and requires identical output
+func TestMachineReadableOutputIsDeterministic(t *testing.T) {
+	dir := setupDeterminismFixture(t)
+
+	commands := [][]string{
+		{"status", "--json"},
+		{"status", "--json", "--verbose"},
+		{"version", "--preview", "--json"},
+		{"validate", "--json"},
+		{"config", "show", "--json"},
+	}
+	for _, args := range commands {
+		t.Run(strings.Join(args, " "), func(t *testing.T) {
+			assertDeterministicOutput(t, dir, args...)
+		})
+	}
+}